@@ -18,6 +18,8 @@ package v1beta1
 
 import (
 	"fmt"
+	"math/big"
+	"net/netip"
 
 	"k8s.io/utils/ptr"
 )
@@ -31,12 +33,26 @@ const (
 	DefaultNodeSubnetCIDR = "10.1.0.0/16"
 	// DefaultNodeSubnetCIDRPattern is the pattern that will be used to generate the default subnets CIDRs.
 	DefaultNodeSubnetCIDRPattern = "10.%d.0.0/16"
+	// DefaultNodeSubnetPoolPrefixLength is the default prefix length of subnets carved from the node subnet pool.
+	DefaultNodeSubnetPoolPrefixLength = 24
 	// DefaultAzureBastionSubnetCIDR is the default Subnet CIDR for AzureBastion.
 	DefaultAzureBastionSubnetCIDR = "10.255.255.224/27"
 	// DefaultAzureBastionSubnetName is the default Subnet Name for AzureBastion.
 	DefaultAzureBastionSubnetName = "AzureBastionSubnet"
 	// DefaultAzureBastionSubnetRole is the default Subnet role for AzureBastion.
 	DefaultAzureBastionSubnetRole = SubnetBastion
+	// DefaultNetAppSubnetCIDR is the default Subnet CIDR for Azure NetApp Files.
+	DefaultNetAppSubnetCIDR = "10.255.254.0/24"
+	// DefaultNetAppSubnetRole is the default Subnet role for Azure NetApp Files.
+	DefaultNetAppSubnetRole = SubnetNetApp
+	// DefaultAzureFirewallSubnetCIDR is the default Subnet CIDR for Azure Firewall.
+	DefaultAzureFirewallSubnetCIDR = "10.255.255.0/26"
+	// DefaultAzureFirewallSubnetName is the Subnet Name Azure requires for Azure Firewall.
+	DefaultAzureFirewallSubnetName = "AzureFirewallSubnet"
+	// DefaultAzureFirewallSubnetRole is the default Subnet role for Azure Firewall.
+	DefaultAzureFirewallSubnetRole = SubnetFirewall
+	// DefaultApplicationGatewaySubnetCIDR is the default Subnet CIDR for Application Gateway.
+	DefaultApplicationGatewaySubnetCIDR = "10.255.253.0/24"
 	// DefaultInternalLBIPAddress is the default internal load balancer ip address.
 	DefaultInternalLBIPAddress = "10.0.0.100"
 	// DefaultOutboundRuleIdleTimeoutInMinutes is the default for IdleTimeoutInMinutes for the load balancer.
@@ -49,12 +65,18 @@ func (c *AzureCluster) setDefaults() {
 	c.Spec.AzureClusterClassSpec.setDefaults()
 	c.setResourceGroupDefault()
 	c.setNetworkSpecDefaults()
+	c.setTrafficManagerDefaults()
 }
 
 func (c *AzureCluster) setNetworkSpecDefaults() {
 	c.setVnetDefaults()
 	c.setBastionDefaults()
+	c.setNetAppDefaults()
+	c.setAzureFirewallDefaults()
+	c.setNodeSubnetPoolDefaults()
 	c.setSubnetDefaults()
+	c.setServiceSubnetDefaults()
+	c.setApplicationGatewayDefaults()
 	c.setVnetPeeringDefaults()
 	c.setAPIServerLBDefaults()
 	c.SetNodeOutboundLBDefaults()
@@ -67,6 +89,19 @@ func (c *AzureCluster) setResourceGroupDefault() {
 	}
 }
 
+func (c *AzureCluster) setTrafficManagerDefaults() {
+	tm := c.Spec.TrafficManager
+	if tm == nil {
+		return
+	}
+	if tm.RoutingMethod == "" {
+		tm.RoutingMethod = TrafficRoutingMethodPriority
+	}
+	if tm.EndpointName == "" {
+		tm.EndpointName = c.ObjectMeta.Name
+	}
+}
+
 func (c *AzureCluster) setAzureEnvironmentDefault() {
 	if c.Spec.AzureEnvironment == "" {
 		c.Spec.AzureEnvironment = DefaultAzureCloud
@@ -114,7 +149,14 @@ func (c *AzureCluster) setSubnetDefaults() {
 		if subnet.Name == "" {
 			subnet.Name = withIndex(generateNodeSubnetName(c.ObjectMeta.Name), nodeSubnetCounter)
 		}
-		subnet.SubnetClassSpec.setDefaults(fmt.Sprintf(DefaultNodeSubnetCIDRPattern, nodeSubnetCounter))
+		if c.Spec.NetworkSpec.NodeSubnetPool == nil {
+			subnet.SubnetClassSpec.setDefaults(fmt.Sprintf(DefaultNodeSubnetCIDRPattern, nodeSubnetCounter))
+		} else if len(subnet.CIDRBlocks) == 0 {
+			// The subnet is left without CIDR blocks when the pool is exhausted, so that the webhook rejects it.
+			if cidr, ok := c.nextNodeSubnetPoolCIDR(); ok {
+				subnet.CIDRBlocks = []string{cidr}
+			}
+		}
 
 		if subnet.SecurityGroup.Name == "" {
 			subnet.SecurityGroup.Name = generateNodeSecurityGroupName(c.ObjectMeta.Name)
@@ -128,7 +170,8 @@ func (c *AzureCluster) setSubnetDefaults() {
 		// NAT gateway only supports the use of IPv4 public IP addresses for outbound connectivity.
 		// So default use the NAT gateway for outbound traffic in IPv4 cluster instead of loadbalancer.
 		// We assume that if the ID is set, the subnet already exists so we shouldn't add a NAT gateway.
-		if !subnet.IsIPv6Enabled() && subnet.ID == "" {
+		// Node subnets egressing through an Azure Firewall don't need one either.
+		if !subnet.IsIPv6Enabled() && subnet.ID == "" && c.Spec.NetworkSpec.OutboundType != AzureFirewallNodeOutboundType {
 			if subnet.NatGateway.Name == "" {
 				subnet.NatGateway.Name = withIndex(generateNatGatewayName(c.ObjectMeta.Name), nodeSubnetCounter)
 			}
@@ -141,10 +184,17 @@ func (c *AzureCluster) setSubnetDefaults() {
 	}
 
 	if !nodeSubnetFound {
+		nodeSubnetCIDRs := []string{DefaultNodeSubnetCIDR}
+		if c.Spec.NetworkSpec.NodeSubnetPool != nil {
+			nodeSubnetCIDRs = nil
+			if cidr, ok := c.nextNodeSubnetPoolCIDR(); ok {
+				nodeSubnetCIDRs = []string{cidr}
+			}
+		}
 		nodeSubnet := SubnetSpec{
 			SubnetClassSpec: SubnetClassSpec{
 				Role:       SubnetNode,
-				CIDRBlocks: []string{DefaultNodeSubnetCIDR},
+				CIDRBlocks: nodeSubnetCIDRs,
 				Name:       generateNodeSubnetName(c.ObjectMeta.Name),
 			},
 			SecurityGroup: SecurityGroup{
@@ -153,16 +203,178 @@ func (c *AzureCluster) setSubnetDefaults() {
 			RouteTable: RouteTable{
 				Name: generateNodeRouteTableName(c.ObjectMeta.Name),
 			},
-			NatGateway: NatGateway{
+		}
+		if c.Spec.NetworkSpec.OutboundType != AzureFirewallNodeOutboundType {
+			nodeSubnet.NatGateway = NatGateway{
 				NatGatewayClassSpec: NatGatewayClassSpec{
 					Name: generateNatGatewayName(c.ObjectMeta.Name),
 				},
-			},
+			}
 		}
 		c.Spec.NetworkSpec.Subnets = append(c.Spec.NetworkSpec.Subnets, nodeSubnet)
 	}
 }
 
+// setServiceSubnetDefaults sets the default names and security groups of the ingress, egress and bastion-only subnets.
+// Subnets of the same role share a security group, whose rules default to the ones the role needs.
+func (c *AzureCluster) setServiceSubnetDefaults() {
+	subnetCounters := make(map[SubnetRole]int)
+	for i, subnet := range c.Spec.NetworkSpec.Subnets {
+		if !subnet.Role.IsServiceRole() {
+			continue
+		}
+		subnetCounters[subnet.Role]++
+		if subnet.Name == "" {
+			subnet.Name = withIndex(generateServiceSubnetName(c.ObjectMeta.Name, subnet.Role), subnetCounters[subnet.Role])
+		}
+		if subnet.SecurityGroup.Name == "" {
+			subnet.SecurityGroup.Name = generateServiceSecurityGroupName(c.ObjectMeta.Name, subnet.Role)
+		}
+		if subnet.SecurityGroup.SecurityRules == nil {
+			subnet.SecurityGroup.SecurityRules = defaultServiceSecurityRules(subnet.Role)
+		}
+		subnet.SecurityGroup.SecurityGroupClass.setDefaults()
+
+		c.Spec.NetworkSpec.Subnets[i] = subnet
+	}
+}
+
+// defaultServiceSecurityRules returns the default security rules of the subnets with the given service role.
+// Egress subnets only rely on the default rules of Azure network security groups, which deny inbound traffic
+// from the internet.
+func defaultServiceSecurityRules(role SubnetRole) SecurityRules {
+	switch role {
+	case SubnetIngress:
+		return SecurityRules{
+			{
+				Name:             "allow_gateway_manager",
+				Description:      "Allow the Application Gateway infrastructure communication",
+				Priority:         2200,
+				Protocol:         SecurityGroupProtocolTCP,
+				Direction:        SecurityRuleDirectionInbound,
+				Source:           ptr.To("GatewayManager"),
+				SourcePorts:      ptr.To("*"),
+				Destination:      ptr.To("*"),
+				DestinationPorts: ptr.To("65200-65535"),
+			},
+			{
+				Name:             "allow_http",
+				Description:      "Allow HTTP",
+				Priority:         2201,
+				Protocol:         SecurityGroupProtocolTCP,
+				Direction:        SecurityRuleDirectionInbound,
+				Source:           ptr.To("*"),
+				SourcePorts:      ptr.To("*"),
+				Destination:      ptr.To("*"),
+				DestinationPorts: ptr.To("80"),
+			},
+			{
+				Name:             "allow_https",
+				Description:      "Allow HTTPS",
+				Priority:         2202,
+				Protocol:         SecurityGroupProtocolTCP,
+				Direction:        SecurityRuleDirectionInbound,
+				Source:           ptr.To("*"),
+				SourcePorts:      ptr.To("*"),
+				Destination:      ptr.To("*"),
+				DestinationPorts: ptr.To("443"),
+			},
+		}
+	case SubnetBastionOnly:
+		return SecurityRules{
+			{
+				Name:             "allow_ssh",
+				Description:      "Allow SSH",
+				Priority:         2200,
+				Protocol:         SecurityGroupProtocolTCP,
+				Direction:        SecurityRuleDirectionInbound,
+				Source:           ptr.To("*"),
+				SourcePorts:      ptr.To("*"),
+				Destination:      ptr.To("*"),
+				DestinationPorts: ptr.To("22"),
+			},
+		}
+	default:
+		return SecurityRules{}
+	}
+}
+
+func (c *AzureCluster) setNodeSubnetPoolDefaults() {
+	pool := c.Spec.NetworkSpec.NodeSubnetPool
+	if pool != nil && pool.PrefixLength == nil {
+		pool.PrefixLength = ptr.To[int32](DefaultNodeSubnetPoolPrefixLength)
+	}
+}
+
+// nextNodeSubnetPoolCIDR returns the first CIDR block of the node subnet pool that does not overlap
+// the CIDR blocks of any subnet in the network spec. It returns false if no pool is set, the pool is
+// invalid, or the pool has no free CIDR block left. Candidates skip past each overlapping CIDR block
+// at once, so the search takes at most one step per subnet CIDR block whatever the size of the pool.
+func (c *AzureCluster) nextNodeSubnetPoolCIDR() (string, bool) {
+	pool := c.Spec.NetworkSpec.NodeSubnetPool
+	if pool == nil {
+		return "", false
+	}
+	poolPrefix, err := netip.ParsePrefix(pool.CIDRBlock)
+	if err != nil {
+		return "", false
+	}
+	poolPrefix = poolPrefix.Masked()
+	bits := int(ptr.Deref(pool.PrefixLength, DefaultNodeSubnetPoolPrefixLength))
+	if bits < poolPrefix.Bits() || bits > poolPrefix.Addr().BitLen() {
+		return "", false
+	}
+
+	var used []netip.Prefix
+	for _, subnet := range c.Spec.NetworkSpec.Subnets {
+		for _, cidr := range subnet.CIDRBlocks {
+			if prefix, err := netip.ParsePrefix(cidr); err == nil {
+				used = append(used, prefix.Masked())
+			}
+		}
+	}
+
+	candidate := netip.PrefixFrom(poolPrefix.Addr(), bits)
+	for poolPrefix.Contains(candidate.Addr()) {
+		overlapping, overlaps := netip.Prefix{}, false
+		for _, prefix := range used {
+			if prefix.Overlaps(candidate) {
+				overlapping, overlaps = prefix, true
+				break
+			}
+		}
+		if !overlaps {
+			return candidate.String(), true
+		}
+		// Skip the whole overlapping CIDR block when it is larger than the candidate.
+		skipped := candidate
+		if overlapping.Bits() < bits {
+			skipped = overlapping
+		}
+		next, ok := nextPrefix(skipped)
+		if !ok {
+			break
+		}
+		candidate = netip.PrefixFrom(next.Addr(), bits)
+	}
+	return "", false
+}
+
+// nextPrefix returns the prefix of the same length immediately following the given prefix.
+func nextPrefix(prefix netip.Prefix) (netip.Prefix, bool) {
+	bitLen := prefix.Addr().BitLen()
+	n := new(big.Int).SetBytes(prefix.Addr().AsSlice())
+	n.Add(n, new(big.Int).Lsh(big.NewInt(1), uint(bitLen-prefix.Bits())))
+	if n.BitLen() > bitLen {
+		return netip.Prefix{}, false
+	}
+	addr, ok := netip.AddrFromSlice(n.FillBytes(make([]byte, bitLen/8)))
+	if !ok {
+		return netip.Prefix{}, false
+	}
+	return netip.PrefixFrom(addr, prefix.Bits()), true
+}
+
 func (c *AzureCluster) setVnetPeeringDefaults() {
 	for i, peering := range c.Spec.NetworkSpec.Vnet.Peerings {
 		if peering.ResourceGroup == "" {
@@ -246,6 +458,29 @@ func (c *AzureCluster) SetNodeOutboundLBDefaults() {
 
 	c.setOutboundLBFrontendIPs(lb, generateNodeOutboundIPName)
 	c.SetNodeOutboundLBBackendPoolNameDefault()
+	c.setAdditionalOutboundRulesDefaults(lb)
+}
+
+// setAdditionalOutboundRulesDefaults generates the frontend IPs of the additional outbound rules of a load balancer
+// that don't define their own.
+func (c *AzureCluster) setAdditionalOutboundRulesDefaults(lb *LoadBalancerSpec) {
+	for i := range lb.AdditionalOutboundRules {
+		rule := &lb.AdditionalOutboundRules[i]
+		if len(rule.FrontendIPs) > 0 {
+			continue
+		}
+		if rule.FrontendIPsCount == nil {
+			rule.FrontendIPsCount = ptr.To[int32](1)
+		}
+		for j := int32(1); j <= *rule.FrontendIPsCount; j++ {
+			rule.FrontendIPs = append(rule.FrontendIPs, FrontendIP{
+				Name: generateOutboundRuleFrontendIPConfigName(lb.Name, rule.Name, j),
+				PublicIP: &PublicIPSpec{
+					Name: generateOutboundRuleIPName(c.ObjectMeta.Name, rule.Name, j),
+				},
+			})
+		}
+	}
 }
 
 // SetControlPlaneOutboundLBDefaults sets the default values for the control plane's outbound LB.
@@ -300,29 +535,30 @@ func (c *AzureCluster) SetControlPlaneOutboundLBBackendPoolNameDefault() {
 
 // setOutboundLBFrontendIPs sets the frontend ips for the given load balancer.
 // The name of the frontend ip is generated using generatePublicIPName function.
+// Existing frontend ips keep their names when the frontend ips count changes, so that their public IPs aren't
+// replaced: frontend ips are only added to or removed from the end of the list.
 func (c *AzureCluster) setOutboundLBFrontendIPs(lb *LoadBalancerSpec, generatePublicIPName func(string) string) {
-	switch *lb.FrontendIPsCount {
-	case 0:
+	count := int(*lb.FrontendIPsCount)
+	if count == 0 {
 		lb.FrontendIPs = []FrontendIP{}
-	case 1:
-		lb.FrontendIPs = []FrontendIP{
-			{
-				Name: generateFrontendIPConfigName(lb.Name),
-				PublicIP: &PublicIPSpec{
-					Name: generatePublicIPName(c.ObjectMeta.Name),
-				},
+		return
+	}
+	if len(lb.FrontendIPs) >= count {
+		lb.FrontendIPs = lb.FrontendIPs[:count]
+		return
+	}
+	for i := len(lb.FrontendIPs); i < count; i++ {
+		frontendIP := FrontendIP{
+			Name: generateFrontendIPConfigName(lb.Name),
+			PublicIP: &PublicIPSpec{
+				Name: generatePublicIPName(c.ObjectMeta.Name),
 			},
 		}
-	default:
-		lb.FrontendIPs = make([]FrontendIP, *lb.FrontendIPsCount)
-		for i := 0; i < int(*lb.FrontendIPsCount); i++ {
-			lb.FrontendIPs[i] = FrontendIP{
-				Name: withIndex(generateFrontendIPConfigName(lb.Name), i+1),
-				PublicIP: &PublicIPSpec{
-					Name: withIndex(generatePublicIPName(c.ObjectMeta.Name), i+1),
-				},
-			}
+		if count > 1 {
+			frontendIP.Name = withIndex(frontendIP.Name, i+1)
+			frontendIP.PublicIP.Name = withIndex(frontendIP.PublicIP.Name, i+1)
 		}
+		lb.FrontendIPs = append(lb.FrontendIPs, frontendIP)
 	}
 }
 
@@ -348,6 +584,118 @@ func (c *AzureCluster) setBastionDefaults() {
 	}
 }
 
+func (c *AzureCluster) setNetAppDefaults() {
+	netApp := c.Spec.NetworkSpec.NetApp
+	if netApp == nil {
+		return
+	}
+	if netApp.Subnet.Name == "" {
+		netApp.Subnet.Name = generateNetAppSubnetName(c.ObjectMeta.Name)
+	}
+	if len(netApp.Subnet.CIDRBlocks) == 0 {
+		netApp.Subnet.CIDRBlocks = []string{DefaultNetAppSubnetCIDR}
+	}
+	if netApp.Subnet.Role == "" {
+		netApp.Subnet.Role = DefaultNetAppSubnetRole
+	}
+	if netApp.Account != nil && netApp.Account.Name == "" {
+		netApp.Account.Name = generateNetAppAccountName(c.ObjectMeta.Name)
+	}
+}
+
+func (c *AzureCluster) setAzureFirewallDefaults() {
+	if c.Spec.NetworkSpec.OutboundType != AzureFirewallNodeOutboundType {
+		return
+	}
+	if c.Spec.NetworkSpec.AzureFirewall == nil {
+		c.Spec.NetworkSpec.AzureFirewall = &AzureFirewallSpec{}
+	}
+	firewall := c.Spec.NetworkSpec.AzureFirewall
+	// CAPZ only routes the node subnets through an existing firewall.
+	if firewall.ID != "" {
+		return
+	}
+	if firewall.Name == "" {
+		firewall.Name = generateAzureFirewallName(c.ObjectMeta.Name)
+	}
+	if firewall.Subnet.Name == "" {
+		firewall.Subnet.Name = DefaultAzureFirewallSubnetName
+	}
+	if len(firewall.Subnet.CIDRBlocks) == 0 {
+		firewall.Subnet.CIDRBlocks = []string{DefaultAzureFirewallSubnetCIDR}
+	}
+	if firewall.Subnet.Role == "" {
+		firewall.Subnet.Role = DefaultAzureFirewallSubnetRole
+	}
+	if firewall.PublicIP.Name == "" {
+		firewall.PublicIP.Name = generateAzureFirewallPublicIPName(c.ObjectMeta.Name)
+	}
+	if firewall.PrivateIPAddress == "" {
+		firewall.PrivateIPAddress = azureFirewallPrivateIPAddress(firewall.Subnet.CIDRBlocks[0])
+	}
+}
+
+func (c *AzureCluster) setApplicationGatewayDefaults() {
+	appGateway := c.Spec.NetworkSpec.ApplicationGateway
+	if appGateway == nil {
+		return
+	}
+	if appGateway.Controller == "" {
+		appGateway.Controller = ApplicationGatewayIngressController
+	}
+	// CAPZ only assigns roles on an existing Application Gateway.
+	if appGateway.ID != "" {
+		return
+	}
+	if appGateway.Controller == ApplicationGatewayIngressController {
+		if appGateway.Name == "" {
+			appGateway.Name = generateApplicationGatewayName(c.ObjectMeta.Name)
+		}
+		if appGateway.PublicIP.Name == "" {
+			appGateway.PublicIP.Name = generateApplicationGatewayPublicIPName(c.ObjectMeta.Name)
+		}
+	}
+	if appGateway.SubnetName != "" {
+		return
+	}
+	for _, subnet := range c.Spec.NetworkSpec.Subnets {
+		if subnet.Role == SubnetIngress {
+			appGateway.SubnetName = subnet.Name
+			return
+		}
+	}
+	subnet := SubnetSpec{
+		SubnetClassSpec: SubnetClassSpec{
+			Role:       SubnetIngress,
+			Name:       generateApplicationGatewaySubnetName(c.ObjectMeta.Name),
+			CIDRBlocks: []string{DefaultApplicationGatewaySubnetCIDR},
+		},
+		SecurityGroup: SecurityGroup{
+			Name: generateServiceSecurityGroupName(c.ObjectMeta.Name, SubnetIngress),
+			SecurityGroupClass: SecurityGroupClass{
+				SecurityRules: defaultServiceSecurityRules(SubnetIngress),
+			},
+		},
+	}
+	c.Spec.NetworkSpec.Subnets = append(c.Spec.NetworkSpec.Subnets, subnet)
+	appGateway.SubnetName = subnet.Name
+}
+
+// azureFirewallPrivateIPAddress returns the private IP address Azure assigns to a firewall in the given subnet, which
+// is the first one that isn't reserved by Azure, or an empty string if the CIDR block is invalid.
+func azureFirewallPrivateIPAddress(cidr string) string {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil || !prefix.Addr().Is4() {
+		return ""
+	}
+	// Azure reserves the network address and the next three addresses of every subnet.
+	addr := prefix.Masked().Addr()
+	for i := 0; i < 4; i++ {
+		addr = addr.Next()
+	}
+	return addr.String()
+}
+
 func (lb *LoadBalancerClassSpec) setAPIServerLBDefaults() {
 	if lb.Type == "" {
 		lb.Type = Public
@@ -410,6 +758,11 @@ func generateNodeSubnetName(clusterName string) string {
 	return fmt.Sprintf("%s-%s", clusterName, "node-subnet")
 }
 
+// generateServiceSubnetName generates the name of a subnet with a service role, based on the cluster name.
+func generateServiceSubnetName(clusterName string, role SubnetRole) string {
+	return fmt.Sprintf("%s-%s-subnet", clusterName, role)
+}
+
 // generateAzureBastionName generates an azure bastion name.
 func generateAzureBastionName(clusterName string) string {
 	return fmt.Sprintf("%s-azure-bastion", clusterName)
@@ -420,6 +773,41 @@ func generateAzureBastionPublicIPName(clusterName string) string {
 	return fmt.Sprintf("%s-azure-bastion-pip", clusterName)
 }
 
+// generateNetAppSubnetName generates an Azure NetApp Files subnet name, based on the cluster name.
+func generateNetAppSubnetName(clusterName string) string {
+	return fmt.Sprintf("%s-%s", clusterName, "netapp-subnet")
+}
+
+// generateAzureFirewallName generates an Azure Firewall name, based on the cluster name.
+func generateAzureFirewallName(clusterName string) string {
+	return fmt.Sprintf("%s-%s", clusterName, "firewall")
+}
+
+// generateAzureFirewallPublicIPName generates an Azure Firewall public IP name, based on the cluster name.
+func generateAzureFirewallPublicIPName(clusterName string) string {
+	return fmt.Sprintf("%s-%s", clusterName, "firewall-pip")
+}
+
+// generateApplicationGatewayName generates an Application Gateway name, based on the cluster name.
+func generateApplicationGatewayName(clusterName string) string {
+	return fmt.Sprintf("%s-%s", clusterName, "appgw")
+}
+
+// generateApplicationGatewaySubnetName generates an Application Gateway subnet name, based on the cluster name.
+func generateApplicationGatewaySubnetName(clusterName string) string {
+	return fmt.Sprintf("%s-%s", clusterName, "appgw-subnet")
+}
+
+// generateApplicationGatewayPublicIPName generates an Application Gateway public IP name, based on the cluster name.
+func generateApplicationGatewayPublicIPName(clusterName string) string {
+	return fmt.Sprintf("%s-%s", clusterName, "appgw-pip")
+}
+
+// generateNetAppAccountName generates a NetApp account name, based on the cluster name.
+func generateNetAppAccountName(clusterName string) string {
+	return fmt.Sprintf("%s-%s", clusterName, "netapp")
+}
+
 // generateControlPlaneSecurityGroupName generates a control plane security group name, based on the cluster name.
 func generateControlPlaneSecurityGroupName(clusterName string) string {
 	return fmt.Sprintf("%s-%s", clusterName, "controlplane-nsg")
@@ -430,6 +818,12 @@ func generateNodeSecurityGroupName(clusterName string) string {
 	return fmt.Sprintf("%s-%s", clusterName, "node-nsg")
 }
 
+// generateServiceSecurityGroupName generates the name of the security group of the subnets with a service role,
+// based on the cluster name.
+func generateServiceSecurityGroupName(clusterName string, role SubnetRole) string {
+	return fmt.Sprintf("%s-%s-nsg", clusterName, role)
+}
+
 // generateNodeRouteTableName generates a node route table name, based on the cluster name.
 func generateNodeRouteTableName(clusterName string) string {
 	return fmt.Sprintf("%s-%s", clusterName, "node-routetable")
@@ -460,6 +854,16 @@ func generateFrontendIPConfigName(lbName string) string {
 	return fmt.Sprintf("%s-%s", lbName, "frontEnd")
 }
 
+// generateOutboundRuleFrontendIPConfigName generates the name of a frontend IP of an additional outbound rule.
+func generateOutboundRuleFrontendIPConfigName(lbName, ruleName string, index int32) string {
+	return fmt.Sprintf("%s-%s-frontEnd-%d", lbName, ruleName, index)
+}
+
+// generateOutboundRuleIPName generates the name of a public IP of an additional outbound rule.
+func generateOutboundRuleIPName(clusterName, ruleName string, index int32) string {
+	return fmt.Sprintf("pip-%s-%s-outbound-%d", clusterName, ruleName, index)
+}
+
 // generateNodeOutboundIPName generates a public IP name, based on the cluster name.
 func generateNodeOutboundIPName(clusterName string) string {
 	return fmt.Sprintf("pip-%s-node-outbound", clusterName)
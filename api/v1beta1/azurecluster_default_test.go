@@ -345,6 +345,57 @@ func TestSubnetDefaults(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "no NAT gateway for node subnets egressing through an azure firewall",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						OutboundType: AzureFirewallNodeOutboundType,
+						Subnets: Subnets{
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role: SubnetNode,
+									Name: "my-node-subnet",
+								},
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						OutboundType: AzureFirewallNodeOutboundType,
+						Subnets: Subnets{
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role:       SubnetNode,
+									CIDRBlocks: []string{DefaultNodeSubnetCIDR},
+									Name:       "my-node-subnet",
+								},
+								SecurityGroup: SecurityGroup{Name: "cluster-test-node-nsg"},
+								RouteTable:    RouteTable{Name: "cluster-test-node-routetable"},
+							},
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role:       SubnetControlPlane,
+									CIDRBlocks: []string{DefaultControlPlaneSubnetCIDR},
+									Name:       "cluster-test-controlplane-subnet",
+								},
+								SecurityGroup: SecurityGroup{Name: "cluster-test-controlplane-nsg"},
+								RouteTable:    RouteTable{},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "subnets with custom attributes",
 			cluster: &AzureCluster{
@@ -670,6 +721,95 @@ func TestSubnetDefaults(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "node subnets without CIDR blocks carved from node subnet pool",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NodeSubnetPool: &SubnetPoolSpec{
+							CIDRBlock:    "10.2.0.0/16",
+							PrefixLength: ptr.To[int32](24),
+						},
+						Subnets: Subnets{
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role:       SubnetNode,
+									CIDRBlocks: []string{"10.2.0.0/24"},
+									Name:       "pool-a",
+								},
+							},
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role: SubnetNode,
+									Name: "pool-b",
+								},
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NodeSubnetPool: &SubnetPoolSpec{
+							CIDRBlock:    "10.2.0.0/16",
+							PrefixLength: ptr.To[int32](24),
+						},
+						Subnets: Subnets{
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role:       SubnetNode,
+									CIDRBlocks: []string{"10.2.0.0/24"},
+									Name:       "pool-a",
+								},
+								SecurityGroup: SecurityGroup{Name: "cluster-test-node-nsg"},
+								RouteTable:    RouteTable{Name: "cluster-test-node-routetable"},
+								NatGateway: NatGateway{
+									NatGatewayClassSpec: NatGatewayClassSpec{
+										Name: "cluster-test-node-natgw-1",
+									},
+									NatGatewayIP: PublicIPSpec{
+										Name: "pip-cluster-test-node-natgw-1",
+									},
+								},
+							},
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role:       SubnetNode,
+									CIDRBlocks: []string{"10.2.1.0/24"},
+									Name:       "pool-b",
+								},
+								SecurityGroup: SecurityGroup{Name: "cluster-test-node-nsg"},
+								RouteTable:    RouteTable{Name: "cluster-test-node-routetable"},
+								NatGateway: NatGateway{
+									NatGatewayClassSpec: NatGatewayClassSpec{
+										Name: "cluster-test-node-natgw-2",
+									},
+									NatGatewayIP: PublicIPSpec{
+										Name: "pip-cluster-test-node-natgw-2",
+									},
+								},
+							},
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role:       SubnetControlPlane,
+									CIDRBlocks: []string{DefaultControlPlaneSubnetCIDR},
+									Name:       "cluster-test-controlplane-subnet",
+								},
+								SecurityGroup: SecurityGroup{Name: "cluster-test-controlplane-nsg"},
+								RouteTable:    RouteTable{},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "subnets with custom security group",
 			cluster: &AzureCluster{
@@ -843,6 +983,139 @@ func TestSubnetDefaults(t *testing.T) {
 	}
 }
 
+func TestServiceSubnetDefaults(t *testing.T) {
+	cases := map[string]struct {
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		"no service subnets": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{SubnetClassSpec: SubnetClassSpec{Role: SubnetNode, Name: "node"}},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{SubnetClassSpec: SubnetClassSpec{Role: SubnetNode, Name: "node"}},
+						},
+					},
+				},
+			},
+		},
+		"service subnets with no settings": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{SubnetClassSpec: SubnetClassSpec{Role: SubnetIngress, CIDRBlocks: []string{"10.2.0.0/24"}}},
+							{SubnetClassSpec: SubnetClassSpec{Role: SubnetEgress, CIDRBlocks: []string{"10.3.0.0/24"}}},
+							{SubnetClassSpec: SubnetClassSpec{Role: SubnetEgress, CIDRBlocks: []string{"10.4.0.0/24"}}},
+							{SubnetClassSpec: SubnetClassSpec{Role: SubnetBastionOnly, CIDRBlocks: []string{"10.5.0.0/24"}}},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{
+								SubnetClassSpec: SubnetClassSpec{Role: SubnetIngress, Name: "foo-ingress-subnet-1", CIDRBlocks: []string{"10.2.0.0/24"}},
+								SecurityGroup: SecurityGroup{
+									Name:               "foo-ingress-nsg",
+									SecurityGroupClass: SecurityGroupClass{SecurityRules: defaultServiceSecurityRules(SubnetIngress)},
+								},
+							},
+							{
+								SubnetClassSpec: SubnetClassSpec{Role: SubnetEgress, Name: "foo-egress-subnet-1", CIDRBlocks: []string{"10.3.0.0/24"}},
+								SecurityGroup: SecurityGroup{
+									Name:               "foo-egress-nsg",
+									SecurityGroupClass: SecurityGroupClass{SecurityRules: SecurityRules{}},
+								},
+							},
+							{
+								SubnetClassSpec: SubnetClassSpec{Role: SubnetEgress, Name: "foo-egress-subnet-2", CIDRBlocks: []string{"10.4.0.0/24"}},
+								SecurityGroup: SecurityGroup{
+									Name:               "foo-egress-nsg",
+									SecurityGroupClass: SecurityGroupClass{SecurityRules: SecurityRules{}},
+								},
+							},
+							{
+								SubnetClassSpec: SubnetClassSpec{Role: SubnetBastionOnly, Name: "foo-bastion-only-subnet-1", CIDRBlocks: []string{"10.5.0.0/24"}},
+								SecurityGroup: SecurityGroup{
+									Name:               "foo-bastion-only-nsg",
+									SecurityGroupClass: SecurityGroupClass{SecurityRules: defaultServiceSecurityRules(SubnetBastionOnly)},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"service subnet with custom settings": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{
+								SubnetClassSpec: SubnetClassSpec{Role: SubnetIngress, Name: "appgw", CIDRBlocks: []string{"10.2.0.0/24"}},
+								SecurityGroup: SecurityGroup{
+									Name: "appgw-nsg",
+									SecurityGroupClass: SecurityGroupClass{SecurityRules: SecurityRules{
+										{Name: "allow_https", Priority: 100, Protocol: SecurityGroupProtocolTCP, DestinationPorts: ptr.To("443")},
+									}},
+								},
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{
+								SubnetClassSpec: SubnetClassSpec{Role: SubnetIngress, Name: "appgw", CIDRBlocks: []string{"10.2.0.0/24"}},
+								SecurityGroup: SecurityGroup{
+									Name: "appgw-nsg",
+									SecurityGroupClass: SecurityGroupClass{SecurityRules: SecurityRules{
+										{Name: "allow_https", Priority: 100, Protocol: SecurityGroupProtocolTCP, Direction: SecurityRuleDirectionInbound, DestinationPorts: ptr.To("443")},
+									}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c.cluster.setServiceSubnetDefaults()
+			if !reflect.DeepEqual(c.cluster, c.output) {
+				expected, _ := json.MarshalIndent(c.output, "", "\t")
+				actual, _ := json.MarshalIndent(c.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
 func TestVnetPeeringDefaults(t *testing.T) {
 	cases := []struct {
 		name    string
@@ -2282,3 +2555,573 @@ func TestBastionDefault(t *testing.T) {
 		})
 	}
 }
+
+func TestNetAppDefault(t *testing.T) {
+	cases := map[string]struct {
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		"no netapp set": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+			},
+		},
+		"netapp enabled with no settings": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NetApp: &NetAppSpec{},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NetApp: &NetAppSpec{
+							Subnet: SubnetSpec{
+								SubnetClassSpec: SubnetClassSpec{
+									Name:       "foo-netapp-subnet",
+									CIDRBlocks: []string{DefaultNetAppSubnetCIDR},
+									Role:       DefaultNetAppSubnetRole,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"netapp with account and custom subnet": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NetApp: &NetAppSpec{
+							Subnet: SubnetSpec{
+								SubnetClassSpec: SubnetClassSpec{
+									Name:       "my-anf-subnet",
+									CIDRBlocks: []string{"10.1.0.0/24"},
+								},
+							},
+							Account: &NetAppAccount{
+								CapacityPools: []NetAppCapacityPool{{Name: "pool1", ServiceLevel: NetAppServiceLevelUltra, SizeTiB: 4}},
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NetApp: &NetAppSpec{
+							Subnet: SubnetSpec{
+								SubnetClassSpec: SubnetClassSpec{
+									Name:       "my-anf-subnet",
+									CIDRBlocks: []string{"10.1.0.0/24"},
+									Role:       DefaultNetAppSubnetRole,
+								},
+							},
+							Account: &NetAppAccount{
+								Name:          "foo-netapp",
+								CapacityPools: []NetAppCapacityPool{{Name: "pool1", ServiceLevel: NetAppServiceLevelUltra, SizeTiB: 4}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c.cluster.setNetAppDefaults()
+			if !reflect.DeepEqual(c.cluster, c.output) {
+				expected, _ := json.MarshalIndent(c.output, "", "\t")
+				actual, _ := json.MarshalIndent(c.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestAzureFirewallDefault(t *testing.T) {
+	cases := map[string]struct {
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		"no outbound type set": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+			},
+		},
+		"azure firewall outbound type with no settings": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						OutboundType: AzureFirewallNodeOutboundType,
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						OutboundType: AzureFirewallNodeOutboundType,
+						AzureFirewall: &AzureFirewallSpec{
+							Name: "foo-firewall",
+							Subnet: SubnetSpec{
+								SubnetClassSpec: SubnetClassSpec{
+									Name:       DefaultAzureFirewallSubnetName,
+									CIDRBlocks: []string{DefaultAzureFirewallSubnetCIDR},
+									Role:       DefaultAzureFirewallSubnetRole,
+								},
+							},
+							PublicIP:         PublicIPSpec{Name: "foo-firewall-pip"},
+							PrivateIPAddress: "10.255.255.4",
+						},
+					},
+				},
+			},
+		},
+		"azure firewall with a custom subnet": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						OutboundType: AzureFirewallNodeOutboundType,
+						AzureFirewall: &AzureFirewallSpec{
+							Subnet: SubnetSpec{
+								SubnetClassSpec: SubnetClassSpec{
+									CIDRBlocks: []string{"10.2.0.64/26"},
+								},
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						OutboundType: AzureFirewallNodeOutboundType,
+						AzureFirewall: &AzureFirewallSpec{
+							Name: "foo-firewall",
+							Subnet: SubnetSpec{
+								SubnetClassSpec: SubnetClassSpec{
+									Name:       DefaultAzureFirewallSubnetName,
+									CIDRBlocks: []string{"10.2.0.64/26"},
+									Role:       DefaultAzureFirewallSubnetRole,
+								},
+							},
+							PublicIP:         PublicIPSpec{Name: "foo-firewall-pip"},
+							PrivateIPAddress: "10.2.0.68",
+						},
+					},
+				},
+			},
+		},
+		"existing azure firewall": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						OutboundType: AzureFirewallNodeOutboundType,
+						AzureFirewall: &AzureFirewallSpec{
+							ID:               "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/azureFirewalls/hub-firewall",
+							PrivateIPAddress: "10.100.0.4",
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						OutboundType: AzureFirewallNodeOutboundType,
+						AzureFirewall: &AzureFirewallSpec{
+							ID:               "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/azureFirewalls/hub-firewall",
+							PrivateIPAddress: "10.100.0.4",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c.cluster.setAzureFirewallDefaults()
+			if !reflect.DeepEqual(c.cluster, c.output) {
+				expected, _ := json.MarshalIndent(c.output, "", "\t")
+				actual, _ := json.MarshalIndent(c.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestApplicationGatewayDefault(t *testing.T) {
+	ingressSubnet := SubnetSpec{
+		SubnetClassSpec: SubnetClassSpec{
+			Role:       SubnetIngress,
+			Name:       "foo-ingress-subnet",
+			CIDRBlocks: []string{"10.3.0.0/24"},
+		},
+	}
+
+	cases := map[string]struct {
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		"no application gateway": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+			},
+		},
+		"application gateway with no settings": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						ApplicationGateway: &ApplicationGatewaySpec{},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role:       SubnetIngress,
+									Name:       "foo-appgw-subnet",
+									CIDRBlocks: []string{DefaultApplicationGatewaySubnetCIDR},
+								},
+								SecurityGroup: SecurityGroup{
+									Name: "foo-ingress-nsg",
+									SecurityGroupClass: SecurityGroupClass{
+										SecurityRules: defaultServiceSecurityRules(SubnetIngress),
+									},
+								},
+							},
+						},
+						ApplicationGateway: &ApplicationGatewaySpec{
+							Controller: ApplicationGatewayIngressController,
+							Name:       "foo-appgw",
+							SubnetName: "foo-appgw-subnet",
+							PublicIP:   PublicIPSpec{Name: "foo-appgw-pip"},
+						},
+					},
+				},
+			},
+		},
+		"application gateway for containers uses the first ingress subnet": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{ingressSubnet},
+						ApplicationGateway: &ApplicationGatewaySpec{
+							Controller: ApplicationGatewayForContainers,
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{ingressSubnet},
+						ApplicationGateway: &ApplicationGatewaySpec{
+							Controller: ApplicationGatewayForContainers,
+							SubnetName: "foo-ingress-subnet",
+						},
+					},
+				},
+			},
+		},
+		"existing application gateway": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						ApplicationGateway: &ApplicationGatewaySpec{
+							ID:          "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/applicationGateways/hub-appgw",
+							PrincipalID: "00000000-0000-0000-0000-000000000000",
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						ApplicationGateway: &ApplicationGatewaySpec{
+							Controller:  ApplicationGatewayIngressController,
+							ID:          "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/applicationGateways/hub-appgw",
+							PrincipalID: "00000000-0000-0000-0000-000000000000",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c.cluster.setApplicationGatewayDefaults()
+			if !reflect.DeepEqual(c.cluster, c.output) {
+				expected, _ := json.MarshalIndent(c.output, "", "\t")
+				actual, _ := json.MarshalIndent(c.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestAdditionalOutboundRulesDefaults(t *testing.T) {
+	cases := map[string]struct {
+		rules  []OutboundRule
+		output []OutboundRule
+	}{
+		"no additional outbound rules": {},
+		"rule without frontend IPs": {
+			rules: []OutboundRule{{Name: "egress"}},
+			output: []OutboundRule{
+				{
+					Name:             "egress",
+					FrontendIPsCount: ptr.To[int32](1),
+					FrontendIPs: []FrontendIP{
+						{
+							Name:     "cluster-test-egress-frontEnd-1",
+							PublicIP: &PublicIPSpec{Name: "pip-cluster-test-egress-outbound-1"},
+						},
+					},
+				},
+			},
+		},
+		"rule with frontend IPs count": {
+			rules: []OutboundRule{{Name: "egress", FrontendIPsCount: ptr.To[int32](2)}},
+			output: []OutboundRule{
+				{
+					Name:             "egress",
+					FrontendIPsCount: ptr.To[int32](2),
+					FrontendIPs: []FrontendIP{
+						{
+							Name:     "cluster-test-egress-frontEnd-1",
+							PublicIP: &PublicIPSpec{Name: "pip-cluster-test-egress-outbound-1"},
+						},
+						{
+							Name:     "cluster-test-egress-frontEnd-2",
+							PublicIP: &PublicIPSpec{Name: "pip-cluster-test-egress-outbound-2"},
+						},
+					},
+				},
+			},
+		},
+		"rule with custom frontend IPs": {
+			rules: []OutboundRule{
+				{
+					Name:        "egress",
+					FrontendIPs: []FrontendIP{{Name: "my-frontend", PublicIP: &PublicIPSpec{Name: "my-ip"}}},
+				},
+			},
+			output: []OutboundRule{
+				{
+					Name:        "egress",
+					FrontendIPs: []FrontendIP{{Name: "my-frontend", PublicIP: &PublicIPSpec{Name: "my-ip"}}},
+				},
+			},
+		},
+	}
+
+	for name := range cases {
+		tc := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			cluster := &AzureCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-test"}}
+			lb := &LoadBalancerSpec{Name: "cluster-test", AdditionalOutboundRules: tc.rules}
+			cluster.setAdditionalOutboundRulesDefaults(lb)
+			if !reflect.DeepEqual(lb.AdditionalOutboundRules, tc.output) {
+				expected, _ := json.MarshalIndent(tc.output, "", "\t")
+				actual, _ := json.MarshalIndent(lb.AdditionalOutboundRules, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestOutboundLBFrontendIPsDefaults(t *testing.T) {
+	cases := map[string]struct {
+		count       int32
+		frontendIPs []FrontendIP
+		output      []FrontendIP
+	}{
+		"no frontend IPs": {
+			count:  0,
+			output: []FrontendIP{},
+		},
+		"single frontend IP": {
+			count: 1,
+			output: []FrontendIP{
+				{Name: "cluster-test-frontEnd", PublicIP: &PublicIPSpec{Name: "pip-cluster-test-node-outbound"}},
+			},
+		},
+		"multiple frontend IPs": {
+			count: 2,
+			output: []FrontendIP{
+				{Name: "cluster-test-frontEnd-1", PublicIP: &PublicIPSpec{Name: "pip-cluster-test-node-outbound-1"}},
+				{Name: "cluster-test-frontEnd-2", PublicIP: &PublicIPSpec{Name: "pip-cluster-test-node-outbound-2"}},
+			},
+		},
+		"frontend IPs count increased": {
+			count: 3,
+			frontendIPs: []FrontendIP{
+				{Name: "cluster-test-frontEnd", PublicIP: &PublicIPSpec{Name: "pip-cluster-test-node-outbound"}},
+			},
+			output: []FrontendIP{
+				{Name: "cluster-test-frontEnd", PublicIP: &PublicIPSpec{Name: "pip-cluster-test-node-outbound"}},
+				{Name: "cluster-test-frontEnd-2", PublicIP: &PublicIPSpec{Name: "pip-cluster-test-node-outbound-2"}},
+				{Name: "cluster-test-frontEnd-3", PublicIP: &PublicIPSpec{Name: "pip-cluster-test-node-outbound-3"}},
+			},
+		},
+		"frontend IPs count decreased": {
+			count: 1,
+			frontendIPs: []FrontendIP{
+				{Name: "cluster-test-frontEnd-1", PublicIP: &PublicIPSpec{Name: "pip-cluster-test-node-outbound-1"}},
+				{Name: "cluster-test-frontEnd-2", PublicIP: &PublicIPSpec{Name: "pip-cluster-test-node-outbound-2"}},
+			},
+			output: []FrontendIP{
+				{Name: "cluster-test-frontEnd-1", PublicIP: &PublicIPSpec{Name: "pip-cluster-test-node-outbound-1"}},
+			},
+		},
+	}
+
+	for name := range cases {
+		tc := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			cluster := &AzureCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-test"}}
+			lb := &LoadBalancerSpec{Name: "cluster-test", FrontendIPsCount: ptr.To(tc.count), FrontendIPs: tc.frontendIPs}
+			cluster.setOutboundLBFrontendIPs(lb, generateNodeOutboundIPName)
+			if !reflect.DeepEqual(lb.FrontendIPs, tc.output) {
+				expected, _ := json.MarshalIndent(tc.output, "", "\t")
+				actual, _ := json.MarshalIndent(lb.FrontendIPs, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestNextNodeSubnetPoolCIDR(t *testing.T) {
+	cases := map[string]struct {
+		pool       *SubnetPoolSpec
+		cidrBlocks []string
+		want       string
+		wantOK     bool
+	}{
+		"no pool": {
+			wantOK: false,
+		},
+		"first block of the pool": {
+			pool:   &SubnetPoolSpec{CIDRBlock: "10.2.0.0/16", PrefixLength: ptr.To[int32](24)},
+			want:   "10.2.0.0/24",
+			wantOK: true,
+		},
+		"skip the blocks used by other subnets": {
+			pool:       &SubnetPoolSpec{CIDRBlock: "10.2.0.0/16", PrefixLength: ptr.To[int32](24)},
+			cidrBlocks: []string{"10.2.0.0/24", "10.2.1.128/25"},
+			want:       "10.2.2.0/24",
+			wantOK:     true,
+		},
+		"pool exhausted": {
+			pool:       &SubnetPoolSpec{CIDRBlock: "10.2.0.0/23", PrefixLength: ptr.To[int32](24)},
+			cidrBlocks: []string{"10.2.0.0/24", "10.2.1.0/24"},
+			wantOK:     false,
+		},
+		"large IPv4 pool with small blocks mostly used": {
+			pool:       &SubnetPoolSpec{CIDRBlock: "10.0.0.0/8", PrefixLength: ptr.To[int32](29)},
+			cidrBlocks: []string{"10.0.0.0/9", "10.128.0.0/10", "10.192.0.0/29"},
+			want:       "10.192.0.8/29",
+			wantOK:     true,
+		},
+		"large IPv4 pool with small blocks exhausted": {
+			pool:       &SubnetPoolSpec{CIDRBlock: "10.0.0.0/8", PrefixLength: ptr.To[int32](29)},
+			cidrBlocks: []string{"10.0.0.0/9", "10.128.0.0/9"},
+			wantOK:     false,
+		},
+		"large IPv6 pool with small blocks mostly used": {
+			pool:       &SubnetPoolSpec{CIDRBlock: "fd00::/8", PrefixLength: ptr.To[int32](64)},
+			cidrBlocks: []string{"fd00::/9"},
+			want:       "fd80::/64",
+			wantOK:     true,
+		},
+		"large IPv6 pool with small blocks exhausted": {
+			pool:       &SubnetPoolSpec{CIDRBlock: "fd00::/8", PrefixLength: ptr.To[int32](64)},
+			cidrBlocks: []string{"fd00::/8"},
+			wantOK:     false,
+		},
+	}
+
+	for name := range cases {
+		tc := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			cluster := &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-test"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NodeSubnetPool: tc.pool,
+						Subnets: Subnets{
+							{SubnetClassSpec: SubnetClassSpec{Role: SubnetNode, Name: "used", CIDRBlocks: tc.cidrBlocks}},
+						},
+					},
+				},
+			}
+			got, ok := cluster.nextNodeSubnetPoolCIDR()
+			if got != tc.want || ok != tc.wantOK {
+				t.Errorf("Expected (%q, %t), got (%q, %t)", tc.want, tc.wantOK, got, ok)
+			}
+		})
+	}
+}
+
+func TestNodeSubnetPoolExhaustedDefaults(t *testing.T) {
+	cluster := &AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-test"},
+		Spec: AzureClusterSpec{
+			NetworkSpec: NetworkSpec{
+				NodeSubnetPool: &SubnetPoolSpec{CIDRBlock: "10.2.0.0/24", PrefixLength: ptr.To[int32](24)},
+				Subnets: Subnets{
+					{SubnetClassSpec: SubnetClassSpec{Role: SubnetNode, Name: "pool-a", CIDRBlocks: []string{"10.2.0.0/24"}}},
+					{SubnetClassSpec: SubnetClassSpec{Role: SubnetNode, Name: "pool-b"}},
+				},
+			},
+		},
+	}
+	cluster.setSubnetDefaults()
+	for _, subnet := range cluster.Spec.NetworkSpec.Subnets {
+		if subnet.Name == "pool-b" && len(subnet.CIDRBlocks) != 0 {
+			t.Errorf("Expected no CIDR blocks for subnet %s, got %v", subnet.Name, subnet.CIDRBlocks)
+		}
+	}
+}
@@ -28,6 +28,10 @@ const (
 
 	// ClusterLabelNamespace indicates the namespace of the cluster.
 	ClusterLabelNamespace = "azurecluster.infrastructure.cluster.x-k8s.io/cluster-namespace"
+
+	// SharedVnetSubnetsClaimedAnnotation records the subnets an AzureCluster claimed in its VNet, once they were
+	// checked against the subnets claimed by the other AzureClusters sharing the VNet.
+	SharedVnetSubnetsClaimedAnnotation = "azurecluster.infrastructure.cluster.x-k8s.io/shared-vnet-subnets"
 )
 
 // AzureClusterSpec defines the desired state of AzureCluster.
@@ -45,10 +49,21 @@ type AzureClusterSpec struct {
 	// +optional
 	BastionSpec BastionSpec `json:"bastionSpec,omitempty"`
 
+	// TrafficManager configures the registration of the cluster's API server endpoint behind an Azure Traffic Manager
+	// profile. Several clusters can register with the same profile to support active-passive regional failover.
+	// +optional
+	TrafficManager *TrafficManagerSpec `json:"trafficManager,omitempty"`
+
 	// ControlPlaneEndpoint represents the endpoint used to communicate with the control plane. It is not recommended to set
 	// this when creating an AzureCluster as CAPZ will set this for you. However, if it is set, CAPZ will not change it.
+	// Its port must match the API server port of the Cluster, which is set with spec.clusterNetwork.apiServerPort.
 	// +optional
 	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint,omitempty"`
+
+	// DeletionProtection prevents the AzureCluster from being deleted, and the Azure resources of the cluster from being
+	// torn down when the Cluster is deleted, while it is true. It must be set to false before deleting the cluster.
+	// +optional
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
 }
 
 // AzureClusterStatus defines the observed state of AzureCluster.
@@ -74,6 +89,29 @@ type AzureClusterStatus struct {
 	// next reconciliation loop.
 	// +optional
 	LongRunningOperationStates Futures `json:"longRunningOperationStates,omitempty"`
+
+	// RetainedResources are the IDs of the Azure resources left in place on deletion of the cluster
+	// because their delete policy is Retain, including the disks of the AzureMachines of the cluster.
+	// +optional
+	RetainedResources []string `json:"retainedResources,omitempty"`
+
+	// VMSizeZones are the availability zones of the location in which each VM size of VMSizes is available.
+	// VM sizes that are not offered in the location are left out.
+	// +listType=map
+	// +listMapKey=vmSize
+	// +optional
+	VMSizeZones []VMSizeZones `json:"vmSizeZones,omitempty"`
+}
+
+// VMSizeZones defines the availability zones of a location in which a VM size is available.
+type VMSizeZones struct {
+	// VMSize is the name of the VM size.
+	VMSize string `json:"vmSize"`
+
+	// Zones are the availability zones in which the VM size is available.
+	// It is empty if the location doesn't support availability zones or the VM size is restricted in all of them.
+	// +optional
+	Zones []string `json:"zones,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -21,6 +21,7 @@ import (
 	"net"
 	"reflect"
 	"regexp"
+	"strings"
 
 	valid "github.com/asaskevich/govalidator"
 	corev1 "k8s.io/api/core/v1"
@@ -29,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
@@ -42,8 +44,16 @@ const (
 	// obtained from https://learn.microsoft.com/rest/api/resources/resourcegroups/createorupdate#uri-parameters.
 	resourceGroupRegex = `^[-\w\._\(\)]+$`
 	// described in https://learn.microsoft.com/azure/azure-resource-manager/management/resource-name-rules.
-	subnetRegex       = `^[-\w\._]+$`
-	loadBalancerRegex = `^[-\w\._]+$`
+	subnetRegex = `^[-\w\._]+$`
+	// Traffic Manager profile names are used as relative DNS names.
+	trafficManagerProfileRegex = `^[a-zA-Z0-9][a-zA-Z0-9-]{0,61}[a-zA-Z0-9]$`
+	loadBalancerRegex          = `^[-\w\._]+$`
+	// Outbound rule names are part of the generated frontend, backend pool and public IP names.
+	outboundRuleRegex = `^[a-z0-9]([-a-z0-9]{0,30}[a-z0-9])?$`
+	// Availability zones are identified by their number.
+	availabilityZoneRegex = `^[1-9][0-9]*$`
+	// defaultOutboundRuleName is the name of the outbound rule every outbound load balancer is created with.
+	defaultOutboundRuleName = "OutboundNATAllProtocols"
 	// MaxLoadBalancerOutboundIPs is the maximum number of outbound IPs in a Standard LoadBalancer frontend configuration.
 	MaxLoadBalancerOutboundIPs = 16
 	// MinLBIdleTimeoutInMinutes is the minimum number of minutes for the LB idle timeout.
@@ -62,11 +72,16 @@ const (
 	privateEndpointRegex = `^[-\w\._]+$`
 	// resource ID Pattern.
 	resourceIDPattern = `(?i)subscriptions/(.+)/resourceGroups/(.+)/providers/(.+?)/(.+?)/(.+)`
+	// Service Endpoint Policy resource ID Pattern.
+	serviceEndpointPolicyIDPattern = `(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Network/serviceEndpointPolicies/[^/]+$`
+	// storageServiceEndpoint is the only service endpoint Service Endpoint Policies apply to.
+	storageServiceEndpoint = "Microsoft.Storage"
 )
 
 var (
 	serviceEndpointServiceRegex  = regexp.MustCompile(serviceEndpointServiceRegexPattern)
 	serviceEndpointLocationRegex = regexp.MustCompile(serviceEndpointLocationRegexPattern)
+	serviceEndpointPolicyIDRegex = regexp.MustCompile(serviceEndpointPolicyIDPattern)
 )
 
 // validateCluster validates a cluster.
@@ -92,11 +107,7 @@ func (c *AzureCluster) validateClusterSpec(old *AzureCluster) field.ErrorList {
 	}
 	allErrs = append(allErrs, validateNetworkSpec(c.Spec.NetworkSpec, oldNetworkSpec, field.NewPath("spec").Child("networkSpec"))...)
 
-	var oldCloudProviderConfigOverrides *CloudProviderConfigOverrides
-	if old != nil {
-		oldCloudProviderConfigOverrides = old.Spec.CloudProviderConfigOverrides
-	}
-	allErrs = append(allErrs, validateCloudProviderConfigOverrides(c.Spec.CloudProviderConfigOverrides, oldCloudProviderConfigOverrides,
+	allErrs = append(allErrs, validateCloudProviderConfigOverrides(c.Spec.CloudProviderConfigOverrides,
 		field.NewPath("spec").Child("cloudProviderConfigOverrides"))...)
 
 	// If ClusterSpec has non-nil ExtendedLocation field but not enable EdgeZone feature gate flag, ClusterSpec validation failed.
@@ -108,10 +119,129 @@ func (c *AzureCluster) validateClusterSpec(old *AzureCluster) field.ErrorList {
 		allErrs = append(allErrs, err)
 	}
 
+	allErrs = append(allErrs, c.validatePublicIPZones(old)...)
+
 	if err := validateIdentityRef(c.Spec.IdentityRef, field.NewPath("spec").Child("identityRef")); err != nil {
 		allErrs = append(allErrs, err)
 	}
 
+	if c.Spec.TrafficManager != nil {
+		allErrs = append(allErrs, validateTrafficManager(*c.Spec.TrafficManager, c.Spec.NetworkSpec.APIServerLB.Type, field.NewPath("spec").Child("trafficManager"))...)
+		// The resource group is immutable, so existing clusters whose profile was defaulted to the cluster resource
+		// group are left as is.
+		if (old == nil || old.Spec.TrafficManager == nil) && strings.EqualFold(c.Spec.TrafficManager.ResourceGroup, c.Spec.ResourceGroup) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "trafficManager", "resourceGroup"), c.Spec.TrafficManager.ResourceGroup,
+				"the Traffic Manager profile can't be in the resource group of the cluster, which is deleted with the cluster"))
+		}
+	}
+
+	return allErrs
+}
+
+// publicIPField is a managed public IP of an AzureCluster along with its field path.
+type publicIPField struct {
+	ip      *PublicIPSpec
+	fldPath *field.Path
+}
+
+// publicIPFields returns the managed public IPs of the AzureCluster along with their field paths.
+func (c *AzureCluster) publicIPFields() []publicIPField {
+	var fields []publicIPField
+	networkPath := field.NewPath("spec").Child("networkSpec")
+	addFrontendIPs := func(ips []FrontendIP, fldPath *field.Path) {
+		for i := range ips {
+			if ips[i].PublicIP != nil {
+				fields = append(fields, publicIPField{ip: ips[i].PublicIP, fldPath: fldPath.Index(i).Child("publicIP")})
+			}
+		}
+	}
+
+	addFrontendIPs(c.Spec.NetworkSpec.APIServerLB.FrontendIPs, networkPath.Child("apiServerLB", "frontendIPs"))
+	if lb := c.Spec.NetworkSpec.ControlPlaneOutboundLB; lb != nil {
+		addFrontendIPs(lb.FrontendIPs, networkPath.Child("controlPlaneOutboundLB", "frontendIPs"))
+	}
+	if lb := c.Spec.NetworkSpec.NodeOutboundLB; lb != nil {
+		addFrontendIPs(lb.FrontendIPs, networkPath.Child("nodeOutboundLB", "frontendIPs"))
+		for i := range lb.AdditionalOutboundRules {
+			addFrontendIPs(lb.AdditionalOutboundRules[i].FrontendIPs,
+				networkPath.Child("nodeOutboundLB", "additionalOutboundRules").Index(i).Child("frontendIPs"))
+		}
+	}
+	for i := range c.Spec.NetworkSpec.Subnets {
+		if c.Spec.NetworkSpec.Subnets[i].IsNatGatewayEnabled() {
+			fields = append(fields, publicIPField{
+				ip:      &c.Spec.NetworkSpec.Subnets[i].NatGateway.NatGatewayIP,
+				fldPath: networkPath.Child("subnets").Index(i).Child("natGateway", "ip"),
+			})
+		}
+	}
+	if c.Spec.BastionSpec.AzureBastion != nil {
+		fields = append(fields, publicIPField{
+			ip:      &c.Spec.BastionSpec.AzureBastion.PublicIP,
+			fldPath: field.NewPath("spec", "bastionSpec", "azureBastion", "publicIP"),
+		})
+	}
+	if fw := c.Spec.NetworkSpec.AzureFirewall; fw != nil && fw.ID == "" {
+		fields = append(fields, publicIPField{ip: &fw.PublicIP, fldPath: networkPath.Child("azureFirewall", "publicIP")})
+	}
+	if appGateway := c.Spec.NetworkSpec.ApplicationGateway; appGateway != nil && appGateway.ID == "" && appGateway.Controller == ApplicationGatewayIngressController {
+		fields = append(fields, publicIPField{ip: &appGateway.PublicIP, fldPath: networkPath.Child("applicationGateway", "publicIP")})
+	}
+	return fields
+}
+
+// validatePublicIPZones validates the availability zones of the managed public IPs, which can't be changed
+// once the public IPs exist.
+func (c *AzureCluster) validatePublicIPZones(old *AzureCluster) field.ErrorList {
+	var allErrs field.ErrorList
+
+	oldZones := make(map[string][]string)
+	if old != nil {
+		for _, f := range old.publicIPFields() {
+			oldZones[f.ip.Name] = f.ip.Zones
+		}
+	}
+
+	for _, f := range c.publicIPFields() {
+		seen := make(map[string]struct{}, len(f.ip.Zones))
+		for i, zone := range f.ip.Zones {
+			if success, _ := regexp.MatchString(availabilityZoneRegex, zone); !success {
+				allErrs = append(allErrs, field.Invalid(f.fldPath.Child("zones").Index(i), zone,
+					fmt.Sprintf("availability zone doesn't match regex %s", availabilityZoneRegex)))
+			}
+			if _, ok := seen[zone]; ok {
+				allErrs = append(allErrs, field.Duplicate(f.fldPath.Child("zones").Index(i), zone))
+			}
+			seen[zone] = struct{}{}
+		}
+		if zones, ok := oldZones[f.ip.Name]; ok && !reflect.DeepEqual(zones, f.ip.Zones) {
+			allErrs = append(allErrs, field.Forbidden(f.fldPath.Child("zones"),
+				"zones of a public IP cannot be modified after it is created"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateTrafficManager validates a TrafficManagerSpec.
+func validateTrafficManager(tm TrafficManagerSpec, apiServerLBType LBType, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if success, _ := regexp.MatchString(trafficManagerProfileRegex, tm.ProfileName); !success {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("profileName"), tm.ProfileName,
+			fmt.Sprintf("profileName doesn't match regex %s", trafficManagerProfileRegex)))
+	}
+	if err := validateResourceGroup(tm.ResourceGroup, fldPath.Child("resourceGroup")); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if apiServerLBType == Internal {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "Traffic Manager requires a public API server load balancer"))
+	}
+	if tm.Weight != nil && tm.RoutingMethod != TrafficRoutingMethodWeighted {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("weight"), "weight can only be set when routingMethod is Weighted"))
+	}
+	if tm.Priority != nil && tm.RoutingMethod != TrafficRoutingMethodPriority {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("priority"), "priority can only be set when routingMethod is Priority"))
+	}
 	return allErrs
 }
 
@@ -180,7 +310,7 @@ func validateNetworkSpec(networkSpec NetworkSpec, old NetworkSpec, fldPath *fiel
 
 	cidrBlocks = controlPlaneSubnet.CIDRBlocks
 
-	allErrs = append(allErrs, validateAPIServerLB(networkSpec.APIServerLB, old.APIServerLB, cidrBlocks, fldPath.Child("apiServerLB"))...)
+	allErrs = append(allErrs, validateAPIServerLB(networkSpec.APIServerLB, old.APIServerLB, cidrBlocks, networkSpec.Vnet, fldPath.Child("apiServerLB"))...)
 
 	var needOutboundLB bool
 	for _, subnet := range networkSpec.Subnets {
@@ -195,8 +325,40 @@ func validateNetworkSpec(networkSpec NetworkSpec, old NetworkSpec, fldPath *fiel
 
 	allErrs = append(allErrs, validateControlPlaneOutboundLB(networkSpec.ControlPlaneOutboundLB, networkSpec.APIServerLB, fldPath.Child("controlPlaneOutboundLB"))...)
 
+	if len(networkSpec.APIServerLB.AdditionalOutboundRules) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("apiServerLB", "additionalOutboundRules"),
+			"additional outbound rules are only supported on the node outbound load balancer"))
+	}
+	if networkSpec.ControlPlaneOutboundLB != nil && len(networkSpec.ControlPlaneOutboundLB.AdditionalOutboundRules) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("controlPlaneOutboundLB", "additionalOutboundRules"),
+			"additional outbound rules are only supported on the node outbound load balancer"))
+	}
+	if networkSpec.NodeOutboundLB != nil {
+		var oldRules []OutboundRule
+		if old.NodeOutboundLB != nil {
+			oldRules = old.NodeOutboundLB.AdditionalOutboundRules
+		}
+		allErrs = append(allErrs, validateAdditionalOutboundRules(networkSpec.NodeOutboundLB.AdditionalOutboundRules, oldRules,
+			fldPath.Child("nodeOutboundLB", "additionalOutboundRules"))...)
+	}
+
 	allErrs = append(allErrs, validatePrivateDNSZoneName(networkSpec.PrivateDNSZoneName, networkSpec.APIServerLB.Type, fldPath.Child("privateDNSZoneName"))...)
 
+	if networkSpec.NodeSubnetPool != nil {
+		allErrs = append(allErrs, validateNodeSubnetPool(*networkSpec.NodeSubnetPool, networkSpec.Vnet.CIDRBlocks, fldPath.Child("nodeSubnetPool"))...)
+		allErrs = append(allErrs, validateNodeSubnetPoolExhaustion(*networkSpec.NodeSubnetPool, networkSpec.Subnets, fldPath.Child("subnets"))...)
+	}
+
+	if networkSpec.NetApp != nil {
+		allErrs = append(allErrs, validateNetApp(*networkSpec.NetApp, networkSpec.Vnet.CIDRBlocks, fldPath.Child("netApp"))...)
+	}
+
+	allErrs = append(allErrs, validateAzureFirewall(networkSpec, fldPath)...)
+
+	if networkSpec.ApplicationGateway != nil {
+		allErrs = append(allErrs, validateApplicationGateway(*networkSpec.ApplicationGateway, networkSpec.Subnets, fldPath.Child("applicationGateway"))...)
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -243,11 +405,20 @@ func validateSubnets(subnets Subnets, vnet VnetSpec, fldPath *field.Path) field.
 			}
 		}
 		allErrs = append(allErrs, validateSubnetCIDR(subnet.CIDRBlocks, vnet.CIDRBlocks, fldPath.Index(i).Child("cidrBlocks"))...)
+		// Unlike node subnets, subnets with a service role have no default address space to fall back to.
+		if subnet.Role.IsServiceRole() && len(subnet.CIDRBlocks) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("cidrBlocks"),
+				fmt.Sprintf("cidrBlocks are required for subnets with the %s role", subnet.Role)))
+		}
 
 		if len(subnet.ServiceEndpoints) > 0 {
 			allErrs = append(allErrs, validateServiceEndpoints(subnet.ServiceEndpoints, fldPath.Index(i).Child("serviceEndpoints"))...)
 		}
 
+		if len(subnet.ServiceEndpointPolicies) > 0 {
+			allErrs = append(allErrs, validateServiceEndpointPolicies(subnet.ServiceEndpointPolicies, subnet.ServiceEndpoints, fldPath.Index(i).Child("serviceEndpointPolicies"))...)
+		}
+
 		if len(subnet.PrivateEndpoints) > 0 {
 			allErrs = append(allErrs, validatePrivateEndpoints(subnet.PrivateEndpoints, subnet.CIDRBlocks, fldPath.Index(i).Child("privateEndpoints"))...)
 		}
@@ -303,6 +474,213 @@ func validateSubnetCIDR(subnetCidrBlocks []string, vnetCidrBlocks []string, fldP
 	return allErrs
 }
 
+// validateNodeSubnetPool validates the node subnet pool.
+func validateNodeSubnetPool(pool SubnetPoolSpec, vnetCidrBlocks []string, fldPath *field.Path) field.ErrorList {
+	_, poolNw, err := net.ParseCIDR(pool.CIDRBlock)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath.Child("cidrBlock"), pool.CIDRBlock, "invalid CIDR format")}
+	}
+
+	allErrs := validateSubnetCIDR([]string{pool.CIDRBlock}, vnetCidrBlocks, fldPath.Child("cidrBlock"))
+
+	if pool.PrefixLength != nil {
+		poolBits, addrBits := poolNw.Mask.Size()
+		if int(*pool.PrefixLength) < poolBits || int(*pool.PrefixLength) > addrBits {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("prefixLength"), *pool.PrefixLength,
+				fmt.Sprintf("prefixLength must be between %d and %d", poolBits, addrBits)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateNodeSubnetPoolExhaustion validates that every node subnet got CIDR blocks, as the node subnets that
+// have none after defaulting could not be carved from the exhausted node subnet pool.
+func validateNodeSubnetPoolExhaustion(pool SubnetPoolSpec, subnets Subnets, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, subnet := range subnets {
+		if subnet.Role == SubnetNode && len(subnet.CIDRBlocks) == 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("cidrBlocks"), subnet.CIDRBlocks,
+				fmt.Sprintf("node subnet pool %s has no free CIDR block left for subnet %s", pool.CIDRBlock, subnet.Name)))
+		}
+	}
+	return allErrs
+}
+
+// validateNetApp validates a NetAppSpec.
+func validateNetApp(netApp NetAppSpec, vnetCidrBlocks []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	subnetPath := fldPath.Child("subnet")
+
+	if err := validateSubnetName(netApp.Subnet.Name, subnetPath.Child("name")); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	allErrs = append(allErrs, validateSubnetCIDR(netApp.Subnet.CIDRBlocks, vnetCidrBlocks, subnetPath.Child("cidrBlocks"))...)
+
+	if netApp.Subnet.Role != SubnetNetApp {
+		allErrs = append(allErrs, field.Invalid(subnetPath.Child("role"), netApp.Subnet.Role,
+			fmt.Sprintf("role must be %s", SubnetNetApp)))
+	}
+
+	// Subnets delegated to Azure NetApp Files are not managed like node subnets,
+	// so no security group, route table or NAT gateway is attached to them.
+	if netApp.Subnet.SecurityGroup.Name != "" {
+		allErrs = append(allErrs, field.Forbidden(subnetPath.Child("securityGroup"), "a security group cannot be attached to the netapp subnet"))
+	}
+	if netApp.Subnet.RouteTable.Name != "" {
+		allErrs = append(allErrs, field.Forbidden(subnetPath.Child("routeTable"), "a route table cannot be attached to the netapp subnet"))
+	}
+	if netApp.Subnet.NatGateway.Name != "" {
+		allErrs = append(allErrs, field.Forbidden(subnetPath.Child("natGateway"), "a NAT gateway cannot be attached to the netapp subnet"))
+	}
+
+	if netApp.Account != nil {
+		poolNames := make(map[string]bool, len(netApp.Account.CapacityPools))
+		for i, pool := range netApp.Account.CapacityPools {
+			if pool.Name == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Child("account", "capacityPools").Index(i).Child("name"), "name is required"))
+			}
+			if poolNames[pool.Name] {
+				allErrs = append(allErrs, field.Duplicate(fldPath.Child("account", "capacityPools").Index(i).Child("name"), pool.Name))
+			}
+			poolNames[pool.Name] = true
+		}
+	}
+
+	return allErrs
+}
+
+// validateApplicationGateway validates an ApplicationGatewaySpec and the subnet it references.
+func validateApplicationGateway(appGateway ApplicationGatewaySpec, subnets Subnets, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if appGateway.Controller == ApplicationGatewayForContainers {
+		// The ALB controller creates and names its own gateway.
+		if appGateway.ID != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("id"), fmt.Sprintf("cannot be set when controller is %s", ApplicationGatewayForContainers)))
+		}
+		if appGateway.Name != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("name"), fmt.Sprintf("cannot be set when controller is %s", ApplicationGatewayForContainers)))
+		}
+		if appGateway.PublicIP.Name != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("publicIP"), fmt.Sprintf("cannot be set when controller is %s", ApplicationGatewayForContainers)))
+		}
+	}
+
+	if appGateway.ID != "" {
+		if _, err := azureutil.ParseResourceID(appGateway.ID); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("id"), appGateway.ID, "id must be a valid Azure resource ID"))
+		}
+		return allErrs
+	}
+
+	subnetPath := fldPath.Child("subnetName")
+	var subnet *SubnetSpec
+	for i := range subnets {
+		if subnets[i].Name == appGateway.SubnetName {
+			subnet = &subnets[i]
+			break
+		}
+	}
+	if subnet == nil {
+		return append(allErrs, field.Invalid(subnetPath, appGateway.SubnetName, "subnetName must be the name of a subnet in networkSpec.subnets"))
+	}
+	if subnet.Role != SubnetIngress {
+		allErrs = append(allErrs, field.Invalid(subnetPath, appGateway.SubnetName,
+			fmt.Sprintf("the subnet must have the %s role", SubnetIngress)))
+	}
+	if appGateway.Controller == ApplicationGatewayForContainers {
+		for _, cidr := range subnet.CIDRBlocks {
+			if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+				if ones, _ := ipNet.Mask.Size(); ones > 24 {
+					allErrs = append(allErrs, field.Invalid(subnetPath, appGateway.SubnetName,
+						"the Application Gateway for Containers subnet must be at least a /24"))
+				}
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateAzureFirewall validates the Azure Firewall of a NetworkSpec and the node subnets egressing through it.
+func validateAzureFirewall(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	firewallPath := fldPath.Child("azureFirewall")
+	firewall := networkSpec.AzureFirewall
+
+	if networkSpec.OutboundType != AzureFirewallNodeOutboundType {
+		if firewall != nil {
+			allErrs = append(allErrs, field.Forbidden(firewallPath,
+				fmt.Sprintf("can only be set when outboundType is %s", AzureFirewallNodeOutboundType)))
+		}
+		return allErrs
+	}
+	if firewall == nil {
+		return append(allErrs, field.Required(firewallPath, fmt.Sprintf("required when outboundType is %s", AzureFirewallNodeOutboundType)))
+	}
+
+	if firewall.PrivateIPAddress == "" {
+		allErrs = append(allErrs, field.Required(firewallPath.Child("privateIPAddress"), "privateIPAddress is required"))
+	} else if ip := net.ParseIP(firewall.PrivateIPAddress); ip == nil || ip.To4() == nil {
+		allErrs = append(allErrs, field.Invalid(firewallPath.Child("privateIPAddress"), firewall.PrivateIPAddress,
+			"privateIPAddress must be a valid IPv4 address"))
+	}
+
+	if firewall.ID != "" {
+		if _, err := azureutil.ParseResourceID(firewall.ID); err != nil {
+			allErrs = append(allErrs, field.Invalid(firewallPath.Child("id"), firewall.ID, "id must be a valid Azure resource ID"))
+		}
+	} else {
+		subnetPath := firewallPath.Child("subnet")
+		// Azure only deploys a firewall into a subnet with this exact name.
+		if firewall.Subnet.Name != DefaultAzureFirewallSubnetName {
+			allErrs = append(allErrs, field.Invalid(subnetPath.Child("name"), firewall.Subnet.Name,
+				fmt.Sprintf("name must be %s", DefaultAzureFirewallSubnetName)))
+		}
+		allErrs = append(allErrs, validateSubnetCIDR(firewall.Subnet.CIDRBlocks, networkSpec.Vnet.CIDRBlocks, subnetPath.Child("cidrBlocks"))...)
+		for i, cidr := range firewall.Subnet.CIDRBlocks {
+			if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+				if ones, _ := ipNet.Mask.Size(); ones > 26 {
+					allErrs = append(allErrs, field.Invalid(subnetPath.Child("cidrBlocks").Index(i), cidr,
+						"the Azure Firewall subnet must be at least a /26"))
+				}
+			}
+		}
+		if firewall.Subnet.Role != SubnetFirewall {
+			allErrs = append(allErrs, field.Invalid(subnetPath.Child("role"), firewall.Subnet.Role,
+				fmt.Sprintf("role must be %s", SubnetFirewall)))
+		}
+		if firewall.Subnet.SecurityGroup.Name != "" {
+			allErrs = append(allErrs, field.Forbidden(subnetPath.Child("securityGroup"), "a security group cannot be attached to the Azure Firewall subnet"))
+		}
+		if firewall.Subnet.RouteTable.Name != "" {
+			allErrs = append(allErrs, field.Forbidden(subnetPath.Child("routeTable"), "a route table cannot be attached to the Azure Firewall subnet"))
+		}
+		if firewall.Subnet.NatGateway.Name != "" {
+			allErrs = append(allErrs, field.Forbidden(subnetPath.Child("natGateway"), "a NAT gateway cannot be attached to the Azure Firewall subnet"))
+		}
+	}
+
+	// Node subnets send their egress traffic to the firewall through their route table. A NAT gateway would take
+	// precedence over it, and IPv6 traffic isn't routed to the firewall.
+	for i, subnet := range networkSpec.Subnets {
+		if subnet.Role != SubnetNode {
+			continue
+		}
+		if subnet.IsNatGatewayEnabled() {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("subnets").Index(i).Child("natGateway"),
+				fmt.Sprintf("node subnets cannot have a NAT gateway when outboundType is %s", AzureFirewallNodeOutboundType)))
+		}
+		if subnet.IsIPv6Enabled() {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("subnets").Index(i).Child("cidrBlocks"),
+				fmt.Sprintf("IPv6 node subnets are not supported when outboundType is %s", AzureFirewallNodeOutboundType)))
+		}
+	}
+
+	return allErrs
+}
+
 // validateVnetCIDR validates the CIDR blocks of a Vnet.
 func validateVnetCIDR(vnetCIDRBlocks []string, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
@@ -364,7 +742,7 @@ func validateSecurityRule(rule SecurityRule, fldPath *field.Path) *field.Error {
 	return nil
 }
 
-func validateAPIServerLB(lb LoadBalancerSpec, old LoadBalancerSpec, cidrs []string, fldPath *field.Path) field.ErrorList {
+func validateAPIServerLB(lb LoadBalancerSpec, old LoadBalancerSpec, cidrs []string, vnet VnetSpec, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
 	lbClassSpec := lb.LoadBalancerClassSpec
@@ -380,8 +758,8 @@ func validateAPIServerLB(lb LoadBalancerSpec, old LoadBalancerSpec, cidrs []stri
 		allErrs = append(allErrs, field.Forbidden(fldPath.Child("name"), "API Server load balancer name should not be modified after AzureCluster creation."))
 	}
 
-	// There should only be one IP config.
-	if len(lb.FrontendIPs) != 1 || ptr.Deref[int32](lb.FrontendIPsCount, 1) != 1 {
+	// There should only be one IP config, except for secondary frontends of an internal load balancer.
+	if len(lb.FrontendIPs) == 0 || (lb.Type != Internal && len(lb.FrontendIPs) != 1) || ptr.Deref[int32](lb.FrontendIPsCount, 1) != 1 {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("frontendIPConfigs"), lb.FrontendIPs,
 			"API Server Load balancer should have 1 Frontend IP"))
 	} else {
@@ -391,6 +769,10 @@ func validateAPIServerLB(lb LoadBalancerSpec, old LoadBalancerSpec, cidrs []stri
 				allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPConfigs").Index(0).Child("publicIP"),
 					"Internal Load Balancers cannot have a Public IP"))
 			}
+			if lb.FrontendIPs[0].Subnet != nil {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPConfigs").Index(0).Child("subnet"),
+					"the primary frontend IP of the API Server load balancer is always allocated in the control plane subnet"))
+			}
 			if lb.FrontendIPs[0].PrivateIPAddress != "" {
 				if err := validateInternalLBIPAddress(lb.FrontendIPs[0].PrivateIPAddress, cidrs,
 					fldPath.Child("frontendIPConfigs").Index(0).Child("privateIP")); err != nil {
@@ -400,6 +782,9 @@ func validateAPIServerLB(lb LoadBalancerSpec, old LoadBalancerSpec, cidrs []stri
 					allErrs = append(allErrs, field.Forbidden(fldPath.Child("name"), "API Server load balancer private IP should not be modified after AzureCluster creation."))
 				}
 			}
+			for i := 1; i < len(lb.FrontendIPs); i++ {
+				allErrs = append(allErrs, validateSecondaryFrontendIP(lb.FrontendIPs[i], vnet, fldPath.Child("frontendIPConfigs").Index(i))...)
+			}
 		}
 
 		// if Public, IP config should not have a private IP.
@@ -408,12 +793,44 @@ func validateAPIServerLB(lb LoadBalancerSpec, old LoadBalancerSpec, cidrs []stri
 				allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPConfigs").Index(0).Child("privateIP"),
 					"Public Load Balancers cannot have a Private IP"))
 			}
+			if lb.FrontendIPs[0].Subnet != nil {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPConfigs").Index(0).Child("subnet"),
+					"Public Load Balancers cannot have a Subnet"))
+			}
 		}
 	}
 
 	return allErrs
 }
 
+// validateSecondaryFrontendIP validates an additional frontend IP of an internal API server load balancer.
+// Azure requires the frontends of an internal load balancer to be in the virtual network of its backend pool,
+// so the subnet must be in the cluster virtual network.
+func validateSecondaryFrontendIP(ip FrontendIP, vnet VnetSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if ip.PublicIP != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("publicIP"), "Internal Load Balancers cannot have a Public IP"))
+	}
+	if ip.Subnet == nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("subnet"), "secondary frontend IPs must reference a subnet"))
+	} else {
+		if !strings.EqualFold(ip.Subnet.VNetName, vnet.Name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("subnet", "vnetName"), ip.Subnet.VNetName,
+				fmt.Sprintf("secondary frontend IPs must be in the cluster virtual network %s", vnet.Name)))
+		}
+		if ip.Subnet.VNetResourceGroup != "" && vnet.ResourceGroup != "" && !strings.EqualFold(ip.Subnet.VNetResourceGroup, vnet.ResourceGroup) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("subnet", "vnetResourceGroup"), ip.Subnet.VNetResourceGroup,
+				fmt.Sprintf("secondary frontend IPs must be in the cluster virtual network, in resource group %s", vnet.ResourceGroup)))
+		}
+	}
+	if ip.PrivateIPAddress == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("privateIP"), "secondary frontend IPs must have a static private IP"))
+	} else if net.ParseIP(ip.PrivateIPAddress) == nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("privateIP"), ip.PrivateIPAddress, "Internal LB IP address isn't a valid IPv4 or IPv6 address"))
+	}
+	return allErrs
+}
+
 func validateNodeOutboundLB(lb *LoadBalancerSpec, old *LoadBalancerSpec, apiserverLB LoadBalancerSpec, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -440,18 +857,20 @@ func validateNodeOutboundLB(lb *LoadBalancerSpec, old *LoadBalancerSpec, apiserv
 		allErrs = append(allErrs, field.Forbidden(fldPath.Child("name"), "Node outbound load balancer Name should not be modified after AzureCluster creation."))
 	}
 
-	if old != nil && old.FrontendIPsCount == lb.FrontendIPsCount {
-		if len(old.FrontendIPs) != len(lb.FrontendIPs) {
+	if old != nil {
+		if ptr.Equal(old.FrontendIPsCount, lb.FrontendIPsCount) && len(old.FrontendIPs) != len(lb.FrontendIPs) {
 			allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPs"), "Node outbound load balancer FrontendIPs cannot be modified after AzureCluster creation."))
 		}
 
-		if len(old.FrontendIPs) == len(lb.FrontendIPs) {
-			for i, frontEndIP := range lb.FrontendIPs {
-				oldFrontendIP := old.FrontendIPs[i]
-				if oldFrontendIP.Name != frontEndIP.Name || !reflect.DeepEqual(*oldFrontendIP.PublicIP, *frontEndIP.PublicIP) {
-					allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPs").Index(i),
-						"Node outbound load balancer FrontendIPs cannot be modified after AzureCluster creation."))
-				}
+		// When the frontend IPs count changes, frontend IPs are only added to or removed from the end of the list, so
+		// that the public IPs of the remaining ones are kept.
+		for i := 0; i < len(lb.FrontendIPs) && i < len(old.FrontendIPs); i++ {
+			frontEndIP, oldFrontendIP := lb.FrontendIPs[i], old.FrontendIPs[i]
+			// The delete policy of the public IPs can be changed after creation.
+			if oldFrontendIP.Name != frontEndIP.Name ||
+				!reflect.DeepEqual(publicIPWithoutDeletePolicy(oldFrontendIP.PublicIP), publicIPWithoutDeletePolicy(frontEndIP.PublicIP)) {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPs").Index(i),
+					"Node outbound load balancer FrontendIPs cannot be modified after AzureCluster creation."))
 			}
 		}
 	}
@@ -461,6 +880,77 @@ func validateNodeOutboundLB(lb *LoadBalancerSpec, old *LoadBalancerSpec, apiserv
 			fmt.Sprintf("Max front end ips allowed is %d", MaxLoadBalancerOutboundIPs)))
 	}
 
+	allErrs = append(allErrs, validateOutboundFrontendIPs(lb.FrontendIPs, fldPath.Child("frontendIPs"))...)
+
+	return allErrs
+}
+
+// validateOutboundFrontendIPs validates the frontend IPs of an outbound load balancer, which are always public.
+func validateOutboundFrontendIPs(frontendIPs []FrontendIP, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, ip := range frontendIPs {
+		if ip.Subnet != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Index(i).Child("subnet"), "Outbound Load Balancers cannot have a Subnet"))
+		}
+	}
+	return allErrs
+}
+
+// validateAdditionalOutboundRules validates the additional outbound rules of the node outbound load balancer.
+func validateAdditionalOutboundRules(rules []OutboundRule, oldRules []OutboundRule, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := make(map[string]struct{}, len(rules))
+	for i, rule := range rules {
+		ruleFldPath := fldPath.Index(i)
+		if success, _ := regexp.MatchString(outboundRuleRegex, rule.Name); !success {
+			allErrs = append(allErrs, field.Invalid(ruleFldPath.Child("name"), rule.Name,
+				fmt.Sprintf("name of outbound rule doesn't match regex %s", outboundRuleRegex)))
+		}
+		if strings.EqualFold(rule.Name, defaultOutboundRuleName) {
+			allErrs = append(allErrs, field.Invalid(ruleFldPath.Child("name"), rule.Name,
+				"name of outbound rule conflicts with the default outbound rule"))
+		}
+		if _, ok := names[rule.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(ruleFldPath.Child("name"), rule.Name))
+		}
+		names[rule.Name] = struct{}{}
+
+		if rule.FrontendIPsCount != nil && (*rule.FrontendIPsCount < 1 || *rule.FrontendIPsCount > MaxLoadBalancerOutboundIPs) {
+			allErrs = append(allErrs, field.Invalid(ruleFldPath.Child("frontendIPsCount"), *rule.FrontendIPsCount,
+				fmt.Sprintf("frontendIPsCount must be between 1 and %d", MaxLoadBalancerOutboundIPs)))
+		}
+		if len(rule.FrontendIPs) > MaxLoadBalancerOutboundIPs {
+			allErrs = append(allErrs, field.TooMany(ruleFldPath.Child("frontendIPs"), len(rule.FrontendIPs), MaxLoadBalancerOutboundIPs))
+		}
+		for j, ip := range rule.FrontendIPs {
+			if ip.PublicIP == nil {
+				allErrs = append(allErrs, field.Required(ruleFldPath.Child("frontendIPs").Index(j).Child("publicIP"),
+					"frontend IPs of outbound rules must have a public IP"))
+			}
+		}
+		allErrs = append(allErrs, validateOutboundFrontendIPs(rule.FrontendIPs, ruleFldPath.Child("frontendIPs"))...)
+	}
+
+	// Machine pools may be bound to existing rules, so they can neither be changed nor removed.
+	for _, oldRule := range oldRules {
+		var found bool
+		for i, rule := range rules {
+			if rule.Name != oldRule.Name {
+				continue
+			}
+			found = true
+			if !reflect.DeepEqual(outboundRuleWithoutDeletePolicy(rule), outboundRuleWithoutDeletePolicy(oldRule)) {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Index(i),
+					"additional outbound rules cannot be modified after AzureCluster creation"))
+			}
+		}
+		if !found {
+			allErrs = append(allErrs, field.Forbidden(fldPath,
+				fmt.Sprintf("additional outbound rule %q cannot be removed", oldRule.Name)))
+		}
+	}
+
 	return allErrs
 }
 
@@ -482,6 +972,10 @@ func validateControlPlaneOutboundLB(lb *LoadBalancerSpec, apiserverLB LoadBalanc
 		}
 	}
 
+	if lb != nil {
+		allErrs = append(allErrs, validateOutboundFrontendIPs(lb.FrontendIPs, fldPath.Child("frontendIPs"))...)
+	}
+
 	return allErrs
 }
 
@@ -505,10 +999,18 @@ func validatePrivateDNSZoneName(privateDNSZoneName string, apiserverLBType LBTyp
 }
 
 // validateCloudProviderConfigOverrides validates CloudProviderConfigOverrides.
-func validateCloudProviderConfigOverrides(oldConfig, newConfig *CloudProviderConfigOverrides, fldPath *field.Path) field.ErrorList {
+func validateCloudProviderConfigOverrides(config *CloudProviderConfigOverrides, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
-	if !reflect.DeepEqual(oldConfig, newConfig) {
-		allErrs = append(allErrs, field.Invalid(fldPath, newConfig, "cannot change cloudProviderConfigOverrides cluster creation"))
+	if config == nil {
+		return allErrs
+	}
+
+	names := make(map[string]bool, len(config.RateLimits))
+	for i, rateLimit := range config.RateLimits {
+		if names[rateLimit.Name] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("rateLimits").Index(i).Child("name"), rateLimit.Name))
+		}
+		names[rateLimit.Name] = true
 	}
 	return allErrs
 }
@@ -642,6 +1144,38 @@ func validateServiceEndpoints(serviceEndpoints []ServiceEndpointSpec, fldPath *f
 	return allErrs
 }
 
+// validateServiceEndpointPolicies validates the Service Endpoint Policies of a subnet.
+func validateServiceEndpointPolicies(policyIDs []string, serviceEndpoints ServiceEndpoints, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	policies := make(map[string]bool, len(policyIDs))
+	for i, policyID := range policyIDs {
+		if !serviceEndpointPolicyIDRegex.MatchString(policyID) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), policyID,
+				fmt.Sprintf("service endpoint policy ID doesn't match regex %s", serviceEndpointPolicyIDPattern)))
+			continue
+		}
+		if policies[strings.ToLower(policyID)] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), policyID))
+		}
+		policies[strings.ToLower(policyID)] = true
+	}
+
+	var hasStorageEndpoint bool
+	for _, se := range serviceEndpoints {
+		if se.Service == storageServiceEndpoint {
+			hasStorageEndpoint = true
+			break
+		}
+	}
+	if !hasStorageEndpoint {
+		allErrs = append(allErrs, field.Invalid(fldPath, policyIDs,
+			fmt.Sprintf("service endpoint policies require the %s service endpoint to be enabled on the subnet", storageServiceEndpoint)))
+	}
+
+	return allErrs
+}
+
 func validateServiceEndpointServiceName(serviceName string, fldPath *field.Path) *field.Error {
 	if success := serviceEndpointServiceRegex.MatchString(serviceName); !success {
 		return field.Invalid(fldPath, serviceName, fmt.Sprintf("service name of endpoint service doesn't match regex %s", serviceEndpointServiceRegexPattern))
@@ -734,3 +1268,23 @@ func validatePrivateEndpointIPAddress(address string, cidrs []string, fldPath *f
 	return field.Invalid(fldPath, address,
 		fmt.Sprintf("Private Endpoint IP address needs to be in subnet range (%s)", cidrs))
 }
+
+// publicIPWithoutDeletePolicy returns a copy of the public IP with its delete policy cleared.
+func publicIPWithoutDeletePolicy(ip *PublicIPSpec) *PublicIPSpec {
+	if ip == nil {
+		return nil
+	}
+	ipCopy := ip.DeepCopy()
+	ipCopy.DeletePolicy = ""
+	return ipCopy
+}
+
+// outboundRuleWithoutDeletePolicy returns a copy of an outbound rule without the delete policies of its public IPs,
+// which can be changed after creation.
+func outboundRuleWithoutDeletePolicy(rule OutboundRule) OutboundRule {
+	ruleCopy := *rule.DeepCopy()
+	for i := range ruleCopy.FrontendIPs {
+		ruleCopy.FrontendIPs[i].PublicIP = publicIPWithoutDeletePolicy(ruleCopy.FrontendIPs[i].PublicIP)
+	}
+	return ruleCopy
+}
@@ -549,6 +549,50 @@ func TestSubnetNamesNotUnique(t *testing.T) {
 	})
 }
 
+func TestSubnetsServiceRoles(t *testing.T) {
+	tests := []struct {
+		name       string
+		subnet     SubnetSpec
+		wantErrMsg string
+	}{
+		{
+			name: "ingress subnet with cidr blocks",
+			subnet: SubnetSpec{
+				SubnetClassSpec: SubnetClassSpec{Role: SubnetIngress, Name: "appgw-subnet", CIDRBlocks: []string{"10.2.0.0/24"}},
+			},
+		},
+		{
+			name: "egress subnet without cidr blocks",
+			subnet: SubnetSpec{
+				SubnetClassSpec: SubnetClassSpec{Role: SubnetEgress, Name: "pls-subnet"},
+			},
+			wantErrMsg: "spec.networkSpec.subnets[2].cidrBlocks: Required value: cidrBlocks are required for subnets with the egress role",
+		},
+		{
+			name: "bastion-only subnet without cidr blocks",
+			subnet: SubnetSpec{
+				SubnetClassSpec: SubnetClassSpec{Role: SubnetBastionOnly, Name: "jumpbox-subnet"},
+			},
+			wantErrMsg: "spec.networkSpec.subnets[2].cidrBlocks: Required value: cidrBlocks are required for subnets with the bastion-only role",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			subnets := append(createValidSubnets(), tc.subnet)
+			errs := validateSubnets(subnets, createValidVnet(),
+				field.NewPath("spec").Child("networkSpec").Child("subnets"))
+			if tc.wantErrMsg == "" {
+				g.Expect(errs).To(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(HaveLen(1))
+			g.Expect(errs[0].Error()).To(Equal(tc.wantErrMsg))
+		})
+	}
+}
+
 func TestSubnetNameValid(t *testing.T) {
 	g := NewWithT(t)
 
@@ -652,6 +696,671 @@ func TestValidateSubnetCIDR(t *testing.T) {
 	}
 }
 
+func TestValidateNodeSubnetPool(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name           string
+		vnetCidrBlocks []string
+		pool           SubnetPoolSpec
+		wantErr        bool
+		expectedErr    field.Error
+	}{
+		{
+			name:           "valid node subnet pool",
+			vnetCidrBlocks: []string{"10.0.0.0/8"},
+			pool:           SubnetPoolSpec{CIDRBlock: "10.2.0.0/16", PrefixLength: ptr.To[int32](24)},
+			wantErr:        false,
+		},
+		{
+			name:           "invalid node subnet pool cidr not in the right format",
+			vnetCidrBlocks: []string{"10.0.0.0/8"},
+			pool:           SubnetPoolSpec{CIDRBlock: "foo/bar"},
+			wantErr:        true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "nodeSubnetPool.cidrBlock",
+				BadValue: "foo/bar",
+				Detail:   "invalid CIDR format",
+			},
+		},
+		{
+			name:           "node subnet pool cidr not in vnet range",
+			vnetCidrBlocks: []string{"10.0.0.0/8"},
+			pool:           SubnetPoolSpec{CIDRBlock: "11.2.0.0/16"},
+			wantErr:        true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "nodeSubnetPool.cidrBlock",
+				BadValue: "11.2.0.0/16",
+				Detail:   "subnet CIDR not in vnet address space: [10.0.0.0/8]",
+			},
+		},
+		{
+			name:           "node subnet pool prefix length shorter than pool",
+			vnetCidrBlocks: []string{"10.0.0.0/8"},
+			pool:           SubnetPoolSpec{CIDRBlock: "10.2.0.0/16", PrefixLength: ptr.To[int32](12)},
+			wantErr:        true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "nodeSubnetPool.prefixLength",
+				BadValue: int32(12),
+				Detail:   "prefixLength must be between 16 and 32",
+			},
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateNodeSubnetPool(testCase.pool, testCase.vnetCidrBlocks, field.NewPath("nodeSubnetPool"))
+			if testCase.wantErr {
+				g.Expect(err).To(ContainElement(MatchError(testCase.expectedErr.Error())))
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateNodeSubnetPoolExhaustion(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := SubnetPoolSpec{CIDRBlock: "10.2.0.0/24", PrefixLength: ptr.To[int32](24)}
+	tests := []struct {
+		name        string
+		subnets     Subnets
+		wantErr     bool
+		expectedErr field.Error
+	}{
+		{
+			name: "every node subnet has CIDR blocks",
+			subnets: Subnets{
+				{SubnetClassSpec: SubnetClassSpec{Role: SubnetControlPlane, Name: "control-plane-subnet", CIDRBlocks: []string{"10.0.0.0/16"}}},
+				{SubnetClassSpec: SubnetClassSpec{Role: SubnetNode, Name: "pool-a", CIDRBlocks: []string{"10.2.0.0/24"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "node subnet without CIDR blocks after the pool is exhausted",
+			subnets: Subnets{
+				{SubnetClassSpec: SubnetClassSpec{Role: SubnetNode, Name: "pool-a", CIDRBlocks: []string{"10.2.0.0/24"}}},
+				{SubnetClassSpec: SubnetClassSpec{Role: SubnetNode, Name: "pool-b"}},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "subnets[1].cidrBlocks",
+				BadValue: []string(nil),
+				Detail:   "node subnet pool 10.2.0.0/24 has no free CIDR block left for subnet pool-b",
+			},
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateNodeSubnetPoolExhaustion(pool, testCase.subnets, field.NewPath("subnets"))
+			if testCase.wantErr {
+				g.Expect(err).To(ContainElement(MatchError(testCase.expectedErr.Error())))
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateNetApp(t *testing.T) {
+	g := NewWithT(t)
+
+	validSubnet := SubnetSpec{
+		SubnetClassSpec: SubnetClassSpec{
+			Name:       "netapp-subnet",
+			CIDRBlocks: []string{"10.255.254.0/24"},
+			Role:       SubnetNetApp,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		vnetCidrBlocks []string
+		netApp         NetAppSpec
+		wantErr        bool
+		expectedErr    field.Error
+	}{
+		{
+			name:           "valid netapp subnet with account",
+			vnetCidrBlocks: []string{"10.0.0.0/8"},
+			netApp: NetAppSpec{
+				Subnet: validSubnet,
+				Account: &NetAppAccount{
+					Name:          "my-account",
+					CapacityPools: []NetAppCapacityPool{{Name: "pool1", SizeTiB: 4}, {Name: "pool2", SizeTiB: 2}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:           "netapp subnet cidr not in vnet range",
+			vnetCidrBlocks: []string{"10.0.0.0/16"},
+			netApp:         NetAppSpec{Subnet: validSubnet},
+			wantErr:        true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "netApp.subnet.cidrBlocks",
+				BadValue: "10.255.254.0/24",
+				Detail:   "subnet CIDR not in vnet address space: [10.0.0.0/16]",
+			},
+		},
+		{
+			name:           "netapp subnet with wrong role",
+			vnetCidrBlocks: []string{"10.0.0.0/8"},
+			netApp: NetAppSpec{
+				Subnet: SubnetSpec{
+					SubnetClassSpec: SubnetClassSpec{
+						Name:       "netapp-subnet",
+						CIDRBlocks: []string{"10.255.254.0/24"},
+						Role:       SubnetNode,
+					},
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "netApp.subnet.role",
+				BadValue: SubnetNode,
+				Detail:   "role must be netapp",
+			},
+		},
+		{
+			name:           "netapp subnet with security group",
+			vnetCidrBlocks: []string{"10.0.0.0/8"},
+			netApp: NetAppSpec{
+				Subnet: SubnetSpec{
+					SecurityGroup:   SecurityGroup{Name: "my-nsg"},
+					SubnetClassSpec: validSubnet.SubnetClassSpec,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "netApp.subnet.securityGroup",
+				Detail: "a security group cannot be attached to the netapp subnet",
+			},
+		},
+		{
+			name:           "duplicate capacity pool names",
+			vnetCidrBlocks: []string{"10.0.0.0/8"},
+			netApp: NetAppSpec{
+				Subnet: validSubnet,
+				Account: &NetAppAccount{
+					Name:          "my-account",
+					CapacityPools: []NetAppCapacityPool{{Name: "pool1", SizeTiB: 4}, {Name: "pool1", SizeTiB: 2}},
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueDuplicate",
+				Field:    "netApp.account.capacityPools[1].name",
+				BadValue: "pool1",
+			},
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateNetApp(testCase.netApp, testCase.vnetCidrBlocks, field.NewPath("netApp"))
+			if testCase.wantErr {
+				g.Expect(err).To(ContainElement(MatchError(testCase.expectedErr.Error())))
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateAzureFirewall(t *testing.T) {
+	g := NewWithT(t)
+
+	validFirewall := func() *AzureFirewallSpec {
+		return &AzureFirewallSpec{
+			Name: "my-cluster-firewall",
+			Subnet: SubnetSpec{
+				SubnetClassSpec: SubnetClassSpec{
+					Name:       "AzureFirewallSubnet",
+					CIDRBlocks: []string{"10.255.255.0/26"},
+					Role:       SubnetFirewall,
+				},
+			},
+			PublicIP:         PublicIPSpec{Name: "my-cluster-firewall-pip"},
+			PrivateIPAddress: "10.255.255.4",
+		}
+	}
+	nodeSubnet := SubnetSpec{
+		SubnetClassSpec: SubnetClassSpec{
+			Name:       "node-subnet",
+			CIDRBlocks: []string{"10.1.0.0/16"},
+			Role:       SubnetNode,
+		},
+	}
+
+	tests := []struct {
+		name        string
+		networkSpec NetworkSpec
+		wantErr     bool
+		expectedErr field.Error
+	}{
+		{
+			name: "valid managed firewall",
+			networkSpec: NetworkSpec{
+				Vnet:          VnetSpec{VnetClassSpec: VnetClassSpec{CIDRBlocks: []string{"10.0.0.0/8"}}},
+				Subnets:       Subnets{nodeSubnet},
+				OutboundType:  AzureFirewallNodeOutboundType,
+				AzureFirewall: validFirewall(),
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid existing firewall",
+			networkSpec: NetworkSpec{
+				Vnet:         VnetSpec{VnetClassSpec: VnetClassSpec{CIDRBlocks: []string{"10.0.0.0/8"}}},
+				Subnets:      Subnets{nodeSubnet},
+				OutboundType: AzureFirewallNodeOutboundType,
+				AzureFirewall: &AzureFirewallSpec{
+					ID:               "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/azureFirewalls/hub-firewall",
+					PrivateIPAddress: "192.168.0.4",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "firewall without outbound type",
+			networkSpec: NetworkSpec{
+				Vnet:          VnetSpec{VnetClassSpec: VnetClassSpec{CIDRBlocks: []string{"10.0.0.0/8"}}},
+				AzureFirewall: validFirewall(),
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "networkSpec.azureFirewall",
+				Detail: "can only be set when outboundType is AzureFirewall",
+			},
+		},
+		{
+			name: "existing firewall without private IP address",
+			networkSpec: NetworkSpec{
+				OutboundType: AzureFirewallNodeOutboundType,
+				AzureFirewall: &AzureFirewallSpec{
+					ID: "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/azureFirewalls/hub-firewall",
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueRequired",
+				Field:  "networkSpec.azureFirewall.privateIPAddress",
+				Detail: "privateIPAddress is required",
+			},
+		},
+		{
+			name: "existing firewall with invalid ID",
+			networkSpec: NetworkSpec{
+				OutboundType: AzureFirewallNodeOutboundType,
+				AzureFirewall: &AzureFirewallSpec{
+					ID:               "hub-firewall",
+					PrivateIPAddress: "192.168.0.4",
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "networkSpec.azureFirewall.id",
+				BadValue: "hub-firewall",
+				Detail:   "id must be a valid Azure resource ID",
+			},
+		},
+		{
+			name: "firewall subnet with wrong name",
+			networkSpec: NetworkSpec{
+				Vnet:         VnetSpec{VnetClassSpec: VnetClassSpec{CIDRBlocks: []string{"10.0.0.0/8"}}},
+				OutboundType: AzureFirewallNodeOutboundType,
+				AzureFirewall: func() *AzureFirewallSpec {
+					fw := validFirewall()
+					fw.Subnet.Name = "firewall-subnet"
+					return fw
+				}(),
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "networkSpec.azureFirewall.subnet.name",
+				BadValue: "firewall-subnet",
+				Detail:   "name must be AzureFirewallSubnet",
+			},
+		},
+		{
+			name: "firewall subnet smaller than a /26",
+			networkSpec: NetworkSpec{
+				Vnet:         VnetSpec{VnetClassSpec: VnetClassSpec{CIDRBlocks: []string{"10.0.0.0/8"}}},
+				OutboundType: AzureFirewallNodeOutboundType,
+				AzureFirewall: func() *AzureFirewallSpec {
+					fw := validFirewall()
+					fw.Subnet.CIDRBlocks = []string{"10.255.255.0/27"}
+					return fw
+				}(),
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "networkSpec.azureFirewall.subnet.cidrBlocks[0]",
+				BadValue: "10.255.255.0/27",
+				Detail:   "the Azure Firewall subnet must be at least a /26",
+			},
+		},
+		{
+			name: "node subnet with NAT gateway",
+			networkSpec: NetworkSpec{
+				Vnet: VnetSpec{VnetClassSpec: VnetClassSpec{CIDRBlocks: []string{"10.0.0.0/8"}}},
+				Subnets: Subnets{
+					{
+						SubnetClassSpec: nodeSubnet.SubnetClassSpec,
+						NatGateway:      NatGateway{NatGatewayClassSpec: NatGatewayClassSpec{Name: "node-natgw"}},
+					},
+				},
+				OutboundType:  AzureFirewallNodeOutboundType,
+				AzureFirewall: validFirewall(),
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "networkSpec.subnets[0].natGateway",
+				Detail: "node subnets cannot have a NAT gateway when outboundType is AzureFirewall",
+			},
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateAzureFirewall(testCase.networkSpec, field.NewPath("networkSpec"))
+			if testCase.wantErr {
+				g.Expect(err).To(ContainElement(MatchError(testCase.expectedErr.Error())))
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateApplicationGateway(t *testing.T) {
+	g := NewWithT(t)
+
+	ingressSubnet := func(cidr string) SubnetSpec {
+		return SubnetSpec{
+			SubnetClassSpec: SubnetClassSpec{
+				Name:       "ingress-subnet",
+				CIDRBlocks: []string{cidr},
+				Role:       SubnetIngress,
+			},
+		}
+	}
+	nodeSubnet := SubnetSpec{
+		SubnetClassSpec: SubnetClassSpec{
+			Name:       "node-subnet",
+			CIDRBlocks: []string{"10.1.0.0/16"},
+			Role:       SubnetNode,
+		},
+	}
+
+	tests := []struct {
+		name        string
+		appGateway  ApplicationGatewaySpec
+		subnets     Subnets
+		wantErr     bool
+		expectedErr field.Error
+	}{
+		{
+			name: "valid application gateway for AGIC",
+			appGateway: ApplicationGatewaySpec{
+				Controller: ApplicationGatewayIngressController,
+				Name:       "my-cluster-appgw",
+				SubnetName: "ingress-subnet",
+				PublicIP:   PublicIPSpec{Name: "my-cluster-appgw-pip"},
+			},
+			subnets: Subnets{nodeSubnet, ingressSubnet("10.2.0.0/26")},
+			wantErr: false,
+		},
+		{
+			name: "valid existing application gateway",
+			appGateway: ApplicationGatewaySpec{
+				Controller:  ApplicationGatewayIngressController,
+				ID:          "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/applicationGateways/hub-appgw",
+				PrincipalID: "00000000-0000-0000-0000-000000000000",
+			},
+			subnets: Subnets{nodeSubnet},
+			wantErr: false,
+		},
+		{
+			name: "valid application gateway for containers",
+			appGateway: ApplicationGatewaySpec{
+				Controller: ApplicationGatewayForContainers,
+				SubnetName: "ingress-subnet",
+			},
+			subnets: Subnets{nodeSubnet, ingressSubnet("10.2.0.0/24")},
+			wantErr: false,
+		},
+		{
+			name: "invalid existing application gateway ID",
+			appGateway: ApplicationGatewaySpec{
+				Controller: ApplicationGatewayIngressController,
+				ID:         "hub-appgw",
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "networkSpec.applicationGateway.id",
+				BadValue: "hub-appgw",
+				Detail:   "id must be a valid Azure resource ID",
+			},
+		},
+		{
+			name: "subnet not found",
+			appGateway: ApplicationGatewaySpec{
+				Controller: ApplicationGatewayIngressController,
+				Name:       "my-cluster-appgw",
+				SubnetName: "missing-subnet",
+			},
+			subnets: Subnets{nodeSubnet},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "networkSpec.applicationGateway.subnetName",
+				BadValue: "missing-subnet",
+				Detail:   "subnetName must be the name of a subnet in networkSpec.subnets",
+			},
+		},
+		{
+			name: "subnet without the ingress role",
+			appGateway: ApplicationGatewaySpec{
+				Controller: ApplicationGatewayIngressController,
+				Name:       "my-cluster-appgw",
+				SubnetName: "node-subnet",
+			},
+			subnets: Subnets{nodeSubnet},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "networkSpec.applicationGateway.subnetName",
+				BadValue: "node-subnet",
+				Detail:   "the subnet must have the ingress role",
+			},
+		},
+		{
+			name: "application gateway for containers subnet is too small",
+			appGateway: ApplicationGatewaySpec{
+				Controller: ApplicationGatewayForContainers,
+				SubnetName: "ingress-subnet",
+			},
+			subnets: Subnets{ingressSubnet("10.2.0.0/26")},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "networkSpec.applicationGateway.subnetName",
+				BadValue: "ingress-subnet",
+				Detail:   "the Application Gateway for Containers subnet must be at least a /24",
+			},
+		},
+		{
+			name: "application gateway for containers with a name",
+			appGateway: ApplicationGatewaySpec{
+				Controller: ApplicationGatewayForContainers,
+				Name:       "my-cluster-appgw",
+				SubnetName: "ingress-subnet",
+			},
+			subnets: Subnets{ingressSubnet("10.2.0.0/24")},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "networkSpec.applicationGateway.name",
+				Detail: "cannot be set when controller is AGC",
+			},
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateApplicationGateway(testCase.appGateway, testCase.subnets, field.NewPath("networkSpec", "applicationGateway"))
+			if testCase.wantErr {
+				g.Expect(err).To(ContainElement(MatchError(testCase.expectedErr.Error())))
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateTrafficManager(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name            string
+		tm              TrafficManagerSpec
+		apiServerLBType LBType
+		wantErr         bool
+		expectedErr     field.Error
+	}{
+		{
+			name: "valid traffic manager",
+			tm: TrafficManagerSpec{
+				ProfileName:   "my-profile",
+				ResourceGroup: "shared-rg",
+				RoutingMethod: TrafficRoutingMethodPriority,
+				Priority:      ptr.To[int64](1),
+			},
+			apiServerLBType: Public,
+			wantErr:         false,
+		},
+		{
+			name: "invalid profile name",
+			tm: TrafficManagerSpec{
+				ProfileName:   "-my-profile",
+				ResourceGroup: "shared-rg",
+				RoutingMethod: TrafficRoutingMethodPriority,
+			},
+			apiServerLBType: Public,
+			wantErr:         true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "trafficManager.profileName",
+				BadValue: "-my-profile",
+				Detail:   "profileName doesn't match regex " + trafficManagerProfileRegex,
+			},
+		},
+		{
+			name: "internal api server load balancer",
+			tm: TrafficManagerSpec{
+				ProfileName:   "my-profile",
+				ResourceGroup: "shared-rg",
+				RoutingMethod: TrafficRoutingMethodPriority,
+			},
+			apiServerLBType: Internal,
+			wantErr:         true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "trafficManager",
+				Detail: "Traffic Manager requires a public API server load balancer",
+			},
+		},
+		{
+			name: "weight with priority routing",
+			tm: TrafficManagerSpec{
+				ProfileName:   "my-profile",
+				ResourceGroup: "shared-rg",
+				RoutingMethod: TrafficRoutingMethodPriority,
+				Weight:        ptr.To[int64](10),
+			},
+			apiServerLBType: Public,
+			wantErr:         true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "trafficManager.weight",
+				Detail: "weight can only be set when routingMethod is Weighted",
+			},
+		},
+		{
+			name: "priority with weighted routing",
+			tm: TrafficManagerSpec{
+				ProfileName:   "my-profile",
+				ResourceGroup: "shared-rg",
+				RoutingMethod: TrafficRoutingMethodWeighted,
+				Priority:      ptr.To[int64](1),
+			},
+			apiServerLBType: Public,
+			wantErr:         true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "trafficManager.priority",
+				Detail: "priority can only be set when routingMethod is Priority",
+			},
+		},
+		{
+			name: "missing resource group",
+			tm: TrafficManagerSpec{
+				ProfileName:   "my-profile",
+				RoutingMethod: TrafficRoutingMethodPriority,
+			},
+			apiServerLBType: Public,
+			wantErr:         true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "trafficManager.resourceGroup",
+				BadValue: "",
+				Detail:   "resourceGroup doesn't match regex " + resourceGroupRegex,
+			},
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateTrafficManager(testCase.tm, testCase.apiServerLBType, field.NewPath("trafficManager"))
+			if testCase.wantErr {
+				g.Expect(err).To(ContainElement(MatchError(testCase.expectedErr.Error())))
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestClusterSpecTrafficManagerResourceGroup(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := createValidCluster()
+	cluster.Spec.ResourceGroup = "my-rg"
+	cluster.Spec.TrafficManager = &TrafficManagerSpec{
+		ProfileName:   "my-profile",
+		ResourceGroup: "MY-RG",
+		RoutingMethod: TrafficRoutingMethodPriority,
+	}
+	g.Expect(cluster.validateClusterSpec(nil)).To(ContainElement(HaveField("Field", "spec.trafficManager.resourceGroup")))
+
+	// existing clusters keep the resource group their profile was defaulted to
+	g.Expect(cluster.validateClusterSpec(cluster.DeepCopy())).To(BeEmpty())
+
+	cluster.Spec.TrafficManager.ResourceGroup = "shared-rg"
+	g.Expect(cluster.validateClusterSpec(nil)).To(BeEmpty())
+}
+
 func TestValidateSecurityRule(t *testing.T) {
 	g := NewWithT(t)
 
@@ -875,34 +1584,216 @@ func TestValidateAPIServerLB(t *testing.T) {
 					Type: Internal,
 				},
 			},
-			cpCIDRS: []string{"10.0.0.0/24", "10.1.0.0/24"},
+			cpCIDRS: []string{"10.0.0.0/24", "10.1.0.0/24"},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "apiServerLB.frontendIPConfigs[0].privateIP",
+				BadValue: "20.1.2.3",
+				Detail:   "Internal LB IP address needs to be in control plane subnet range ([10.0.0.0/24 10.1.0.0/24])",
+			},
+		},
+		{
+			name: "internal LB with in range private IP",
+			lb: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{
+						Name: "ip-1",
+						FrontendIPClass: FrontendIPClass{
+							PrivateIPAddress: "10.1.0.3",
+						},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+					SKU:  SKUStandard,
+				},
+				Name: "my-private-lb",
+			},
+			cpCIDRS: []string{"10.0.0.0/24", "10.1.0.0/24"},
+			wantErr: false,
+		},
+		{
+			name: "internal LB with secondary frontend IP in another subnet of the cluster vnet",
+			lb: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{
+						Name: "ip-1",
+						FrontendIPClass: FrontendIPClass{
+							PrivateIPAddress: "10.1.0.3",
+						},
+					},
+					{
+						Name: "ip-tooling",
+						Subnet: &FrontendIPSubnet{
+							Name:              "tooling-subnet",
+							VNetName:          "my-vnet",
+							VNetResourceGroup: "my-rg",
+						},
+						FrontendIPClass: FrontendIPClass{
+							PrivateIPAddress: "10.2.0.10",
+						},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+					SKU:  SKUStandard,
+				},
+				Name: "my-private-lb",
+			},
+			cpCIDRS: []string{"10.0.0.0/24", "10.1.0.0/24"},
+			wantErr: false,
+		},
+		{
+			name: "internal LB with secondary frontend IP in another vnet",
+			lb: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{
+						Name: "ip-1",
+						FrontendIPClass: FrontendIPClass{
+							PrivateIPAddress: "10.1.0.3",
+						},
+					},
+					{
+						Name: "ip-hub",
+						Subnet: &FrontendIPSubnet{
+							Name:     "hub-subnet",
+							VNetName: "hub-vnet",
+						},
+						FrontendIPClass: FrontendIPClass{
+							PrivateIPAddress: "10.100.0.10",
+						},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+					SKU:  SKUStandard,
+				},
+				Name: "my-private-lb",
+			},
+			cpCIDRS: []string{"10.0.0.0/24", "10.1.0.0/24"},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "apiServerLB.frontendIPConfigs[1].subnet.vnetName",
+				BadValue: "hub-vnet",
+				Detail:   "secondary frontend IPs must be in the cluster virtual network my-vnet",
+			},
+		},
+		{
+			name: "internal LB with secondary frontend IP in a vnet of another resource group",
+			lb: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{
+						Name: "ip-1",
+						FrontendIPClass: FrontendIPClass{
+							PrivateIPAddress: "10.1.0.3",
+						},
+					},
+					{
+						Name: "ip-hub",
+						Subnet: &FrontendIPSubnet{
+							Name:              "hub-subnet",
+							VNetName:          "my-vnet",
+							VNetResourceGroup: "hub-rg",
+						},
+						FrontendIPClass: FrontendIPClass{
+							PrivateIPAddress: "10.100.0.10",
+						},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+					SKU:  SKUStandard,
+				},
+				Name: "my-private-lb",
+			},
+			cpCIDRS: []string{"10.0.0.0/24", "10.1.0.0/24"},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "apiServerLB.frontendIPConfigs[1].subnet.vnetResourceGroup",
+				BadValue: "hub-rg",
+				Detail:   "secondary frontend IPs must be in the cluster virtual network, in resource group my-rg",
+			},
+		},
+		{
+			name: "internal LB with secondary frontend IP without subnet",
+			lb: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{
+						Name: "ip-1",
+						FrontendIPClass: FrontendIPClass{
+							PrivateIPAddress: "10.1.0.3",
+						},
+					},
+					{
+						Name: "ip-hub",
+						FrontendIPClass: FrontendIPClass{
+							PrivateIPAddress: "10.100.0.10",
+						},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+				},
+			},
+			cpCIDRS: []string{"10.0.0.0/24", "10.1.0.0/24"},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueRequired",
+				Field:  "apiServerLB.frontendIPConfigs[1].subnet",
+				Detail: "secondary frontend IPs must reference a subnet",
+			},
+		},
+		{
+			name: "internal LB with secondary frontend IP without private IP",
+			lb: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{
+						Name: "ip-1",
+					},
+					{
+						Name: "ip-tooling",
+						Subnet: &FrontendIPSubnet{
+							Name:     "tooling-subnet",
+							VNetName: "my-vnet",
+						},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+				},
+			},
 			wantErr: true,
 			expectedErr: field.Error{
-				Type:     "FieldValueInvalid",
-				Field:    "apiServerLB.frontendIPConfigs[0].privateIP",
-				BadValue: "20.1.2.3",
-				Detail:   "Internal LB IP address needs to be in control plane subnet range ([10.0.0.0/24 10.1.0.0/24])",
+				Type:   "FieldValueRequired",
+				Field:  "apiServerLB.frontendIPConfigs[1].privateIP",
+				Detail: "secondary frontend IPs must have a static private IP",
 			},
 		},
 		{
-			name: "internal LB with in range private IP",
+			name: "internal LB with subnet on primary frontend IP",
 			lb: LoadBalancerSpec{
 				FrontendIPs: []FrontendIP{
 					{
 						Name: "ip-1",
-						FrontendIPClass: FrontendIPClass{
-							PrivateIPAddress: "10.1.0.3",
+						Subnet: &FrontendIPSubnet{
+							Name:     "hub-subnet",
+							VNetName: "hub-vnet",
 						},
 					},
 				},
 				LoadBalancerClassSpec: LoadBalancerClassSpec{
 					Type: Internal,
-					SKU:  SKUStandard,
 				},
-				Name: "my-private-lb",
 			},
-			cpCIDRS: []string{"10.0.0.0/24", "10.1.0.0/24"},
-			wantErr: false,
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "apiServerLB.frontendIPConfigs[0].subnet",
+				Detail: "the primary frontend IP of the API Server load balancer is always allocated in the control plane subnet",
+			},
 		},
 	}
 
@@ -910,7 +1801,7 @@ func TestValidateAPIServerLB(t *testing.T) {
 		test := test
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
-			err := validateAPIServerLB(test.lb, test.old, test.cpCIDRS, field.NewPath("apiServerLB"))
+			err := validateAPIServerLB(test.lb, test.old, test.cpCIDRS, VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"}, field.NewPath("apiServerLB"))
 			if test.wantErr {
 				g.Expect(err).To(ContainElement(MatchError(test.expectedErr.Error())))
 			} else {
@@ -1116,7 +2007,7 @@ func TestValidateNodeOutboundLB(t *testing.T) {
 			name: "FrontendIps can update when frontendIpsCount changes",
 			lb: &LoadBalancerSpec{
 				FrontendIPs: []FrontendIP{{
-					Name: "some-frontend-ip-1",
+					Name: "old-frontend-ip",
 				}, {
 					Name: "some-frontend-ip-2",
 				}},
@@ -1130,6 +2021,76 @@ func TestValidateNodeOutboundLB(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "existing FrontendIps can't be renamed when frontendIpsCount changes",
+			lb: &LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{{
+					Name: "some-frontend-ip-1",
+				}, {
+					Name: "some-frontend-ip-2",
+				}},
+				FrontendIPsCount: ptr.To[int32](2),
+			},
+			old: &LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{{
+					Name: "old-frontend-ip",
+				}},
+				FrontendIPsCount: ptr.To[int32](1),
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:  "FieldValueForbidden",
+				Field: "nodeOutboundLB.frontendIPs[0]",
+				BadValue: FrontendIP{
+					Name: "some-frontend-ip-1",
+				},
+				Detail: "Node outbound load balancer FrontendIPs cannot be modified after AzureCluster creation.",
+			},
+		},
+		{
+			name: "invalid FrontendIps update with the same frontendIpsCount",
+			lb: &LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{{
+					Name: "some-frontend-ip",
+				}},
+				FrontendIPsCount: ptr.To[int32](1),
+			},
+			old: &LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{{
+					Name: "old-frontend-ip",
+				}},
+				FrontendIPsCount: ptr.To[int32](1),
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:  "FieldValueForbidden",
+				Field: "nodeOutboundLB.frontendIPs[0]",
+				BadValue: FrontendIP{
+					Name: "some-frontend-ip",
+				},
+				Detail: "Node outbound load balancer FrontendIPs cannot be modified after AzureCluster creation.",
+			},
+		},
+		{
+			name: "invalid IdleTimeoutInMinutes update",
+			lb: &LoadBalancerSpec{
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					IdleTimeoutInMinutes: ptr.To[int32](30),
+				},
+			},
+			old: &LoadBalancerSpec{
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					IdleTimeoutInMinutes: ptr.To[int32](4),
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueForbidden",
+				Field:    "nodeOutboundLB.idleTimeoutInMinutes",
+				BadValue: nil,
+				Detail:   "Node outbound load balancer idle timeout cannot be modified after AzureCluster creation.",
+			},
+		},
 		{
 			name: "frontend ips count exceeds max value",
 			lb: &LoadBalancerSpec{
@@ -1143,6 +2104,22 @@ func TestValidateNodeOutboundLB(t *testing.T) {
 				Detail:   "Max front end ips allowed is 16",
 			},
 		},
+		{
+			name: "frontend ip with a subnet",
+			lb: &LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{{
+					Name:   "some-frontend-ip",
+					Subnet: &FrontendIPSubnet{Name: "hub-subnet", VNetName: "hub-vnet"},
+				}},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueForbidden",
+				Field:    "nodeOutboundLB.frontendIPs[0].subnet",
+				BadValue: "",
+				Detail:   "Outbound Load Balancers cannot have a Subnet",
+			},
+		},
 	}
 
 	for _, test := range testcases {
@@ -1224,6 +2201,27 @@ func TestValidateControlPlaneNodeOutboundLB(t *testing.T) {
 				Detail:   "Max front end ips allowed is 16",
 			},
 		},
+		{
+			name: "frontend ip with a subnet",
+			lb: &LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{{
+					Name:   "some-frontend-ip",
+					Subnet: &FrontendIPSubnet{Name: "hub-subnet", VNetName: "hub-vnet"},
+				}},
+			},
+			apiServerLB: LoadBalancerSpec{
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueForbidden",
+				Field:    "controlPlaneOutboundLB.frontendIPs[0].subnet",
+				BadValue: "",
+				Detail:   "Outbound Load Balancers cannot have a Subnet",
+			},
+		},
 	}
 
 	for _, test := range testcases {
@@ -1245,83 +2243,56 @@ func TestValidateCloudProviderConfigOverrides(t *testing.T) {
 
 	tests := []struct {
 		name        string
-		oldConfig   *CloudProviderConfigOverrides
-		newConfig   *CloudProviderConfigOverrides
+		config      *CloudProviderConfigOverrides
 		wantErr     bool
 		expectedErr field.Error
 	}{
 		{
-			name:    "both old and new config nil",
+			name:    "config nil",
 			wantErr: false,
 		},
 		{
-			name: "both old and new config are same",
-			oldConfig: &CloudProviderConfigOverrides{RateLimits: []RateLimitSpec{{
-				Name:   "foo",
-				Config: RateLimitConfig{CloudProviderRateLimitBucket: 10, CloudProviderRateLimit: true},
-			}}},
-			newConfig: &CloudProviderConfigOverrides{RateLimits: []RateLimitSpec{{
-				Name:   "foo",
-				Config: RateLimitConfig{CloudProviderRateLimitBucket: 10, CloudProviderRateLimit: true},
-			}}},
+			name: "valid config",
+			config: &CloudProviderConfigOverrides{
+				RateLimits: []RateLimitSpec{
+					{
+						Name:   "foo",
+						Config: RateLimitConfig{CloudProviderRateLimitBucket: 10, CloudProviderRateLimit: true},
+					},
+					{
+						Name:   "bar",
+						Config: RateLimitConfig{CloudProviderRateLimitBucket: 5},
+					},
+				},
+				LoadBalancerSKU:             "Standard",
+				UseInstanceMetadata:         ptr.To(false),
+				ExcludeMasterFromStandardLB: ptr.To(true),
+			},
 			wantErr: false,
 		},
 		{
-			name: "old and new config are not same",
-			oldConfig: &CloudProviderConfigOverrides{RateLimits: []RateLimitSpec{{
-				Name:   "foo",
-				Config: RateLimitConfig{CloudProviderRateLimitBucket: 10, CloudProviderRateLimit: true},
-			}}},
-			newConfig: &CloudProviderConfigOverrides{RateLimits: []RateLimitSpec{{
-				Name:   "foo",
-				Config: RateLimitConfig{CloudProviderRateLimitBucket: 11, CloudProviderRateLimit: true},
-			}}},
-			wantErr: true,
-			expectedErr: field.Error{
-				Type:  "FieldValueInvalid",
-				Field: "spec.cloudProviderConfigOverrides",
-				BadValue: CloudProviderConfigOverrides{RateLimits: []RateLimitSpec{{
+			name: "duplicate rate limit names",
+			config: &CloudProviderConfigOverrides{RateLimits: []RateLimitSpec{
+				{
 					Name:   "foo",
-					Config: RateLimitConfig{CloudProviderRateLimitBucket: 11, CloudProviderRateLimit: true},
-				}}},
-				Detail: "cannot change cloudProviderConfigOverrides cluster creation",
-			},
-		},
-		{
-			name: "new config is nil",
-			oldConfig: &CloudProviderConfigOverrides{RateLimits: []RateLimitSpec{{
-				Name:   "foo",
-				Config: RateLimitConfig{CloudProviderRateLimitBucket: 10, CloudProviderRateLimit: true},
-			}}},
-			wantErr: true,
-			expectedErr: field.Error{
-				Type:     "FieldValueInvalid",
-				Field:    "spec.cloudProviderConfigOverrides",
-				BadValue: nil,
-				Detail:   "cannot change cloudProviderConfigOverrides cluster creation",
-			},
-		},
-		{
-			name: "old config is nil",
-			newConfig: &CloudProviderConfigOverrides{RateLimits: []RateLimitSpec{{
-				Name:   "foo",
-				Config: RateLimitConfig{CloudProviderRateLimitBucket: 10, CloudProviderRateLimit: true},
-			}}},
+					Config: RateLimitConfig{CloudProviderRateLimitBucket: 10, CloudProviderRateLimit: true},
+				},
+				{
+					Name:   "foo",
+					Config: RateLimitConfig{CloudProviderRateLimitBucket: 11},
+				},
+			}},
 			wantErr: true,
 			expectedErr: field.Error{
-				Type:  "FieldValueInvalid",
-				Field: "spec.cloudProviderConfigOverrides",
-				BadValue: &CloudProviderConfigOverrides{RateLimits: []RateLimitSpec{{
-					Name:   "foo",
-					Config: RateLimitConfig{CloudProviderRateLimitBucket: 10, CloudProviderRateLimit: true},
-				}}},
-				Detail: "cannot change cloudProviderConfigOverrides cluster creation",
+				Type:     "FieldValueDuplicate",
+				Field:    "spec.cloudProviderConfigOverrides.rateLimits[1].name",
+				BadValue: "foo",
 			},
 		},
 	}
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
-			err := validateCloudProviderConfigOverrides(testCase.oldConfig, testCase.newConfig, field.NewPath("spec.cloudProviderConfigOverrides"))
+			err := validateCloudProviderConfigOverrides(testCase.config, field.NewPath("spec.cloudProviderConfigOverrides"))
 			if testCase.wantErr {
 				g.Expect(err).To(ContainElement(MatchError(testCase.expectedErr.Error())))
 			} else {
@@ -1386,6 +2357,50 @@ func createValidVnet() VnetSpec {
 	}
 }
 
+func createValidClusterWithNetApp() *AzureCluster {
+	cluster := createValidCluster()
+	cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{DefaultVnetCIDR}
+	cluster.Spec.NetworkSpec.NetApp = createValidNetApp()
+	return cluster
+}
+
+func createValidNetApp() *NetAppSpec {
+	return &NetAppSpec{
+		Subnet: SubnetSpec{
+			SubnetClassSpec: SubnetClassSpec{
+				Name:       "netapp-subnet",
+				CIDRBlocks: []string{DefaultNetAppSubnetCIDR},
+				Role:       SubnetNetApp,
+			},
+		},
+		Account: &NetAppAccount{
+			Name: "test-cluster-netapp",
+			CapacityPools: []NetAppCapacityPool{
+				{Name: "pool1", ServiceLevel: NetAppServiceLevelPremium, SizeTiB: 4},
+			},
+		},
+	}
+}
+
+func createValidClusterWithAzureFirewall() *AzureCluster {
+	cluster := createValidCluster()
+	cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{DefaultVnetCIDR}
+	cluster.Spec.NetworkSpec.OutboundType = AzureFirewallNodeOutboundType
+	cluster.Spec.NetworkSpec.AzureFirewall = &AzureFirewallSpec{
+		Name: "test-cluster-firewall",
+		Subnet: SubnetSpec{
+			SubnetClassSpec: SubnetClassSpec{
+				Name:       DefaultAzureFirewallSubnetName,
+				CIDRBlocks: []string{DefaultAzureFirewallSubnetCIDR},
+				Role:       SubnetFirewall,
+			},
+		},
+		PublicIP:         PublicIPSpec{Name: "test-cluster-firewall-pip"},
+		PrivateIPAddress: "10.255.255.4",
+	}
+	return cluster
+}
+
 func createValidAPIServerLB() LoadBalancerSpec {
 	return LoadBalancerSpec{
 		Name: "my-lb",
@@ -1556,6 +2571,73 @@ func TestServiceEndpointsLackRequiredFieldLocations(t *testing.T) {
 	})
 }
 
+func TestValidateServiceEndpointPolicies(t *testing.T) {
+	g := NewWithT(t)
+
+	policyID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/serviceEndpointPolicies/my-policy"
+	storageEndpoints := ServiceEndpoints{{Service: "Microsoft.Storage", Locations: []string{"*"}}}
+
+	tests := []struct {
+		name             string
+		policies         []string
+		serviceEndpoints ServiceEndpoints
+		wantErr          bool
+		expectedErr      field.Error
+	}{
+		{
+			name:             "valid service endpoint policy",
+			policies:         []string{policyID},
+			serviceEndpoints: storageEndpoints,
+			wantErr:          false,
+		},
+		{
+			name:             "invalid service endpoint policy ID",
+			policies:         []string{"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Storage/storageAccounts/foo"},
+			serviceEndpoints: storageEndpoints,
+			wantErr:          true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "subnets[0].serviceEndpointPolicies[0]",
+				BadValue: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Storage/storageAccounts/foo",
+				Detail:   "service endpoint policy ID doesn't match regex " + serviceEndpointPolicyIDPattern,
+			},
+		},
+		{
+			name:             "duplicate service endpoint policy",
+			policies:         []string{policyID, policyID},
+			serviceEndpoints: storageEndpoints,
+			wantErr:          true,
+			expectedErr: field.Error{
+				Type:     "FieldValueDuplicate",
+				Field:    "subnets[0].serviceEndpointPolicies[1]",
+				BadValue: policyID,
+			},
+		},
+		{
+			name:             "service endpoint policy without storage service endpoint",
+			policies:         []string{policyID},
+			serviceEndpoints: ServiceEndpoints{{Service: "Microsoft.KeyVault", Locations: []string{"*"}}},
+			wantErr:          true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "subnets[0].serviceEndpointPolicies",
+				BadValue: []string{policyID},
+				Detail:   "service endpoint policies require the Microsoft.Storage service endpoint to be enabled on the subnet",
+			},
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateServiceEndpointPolicies(testCase.policies, testCase.serviceEndpoints, field.NewPath("subnets[0].serviceEndpointPolicies"))
+			if testCase.wantErr {
+				g.Expect(err).To(ContainElement(MatchError(testCase.expectedErr.Error())))
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
 func TestClusterWithExtendedLocationInvalid(t *testing.T) {
 	g := NewWithT(t)
 
@@ -1579,3 +2661,163 @@ func TestClusterWithExtendedLocationInvalid(t *testing.T) {
 		g.Expect(err).NotTo(BeNil())
 	})
 }
+
+func TestValidateAdditionalOutboundRules(t *testing.T) {
+	validRule := OutboundRule{
+		Name:        "egress",
+		FrontendIPs: []FrontendIP{{Name: "cluster-test-egress-frontEnd-1", PublicIP: &PublicIPSpec{Name: "pip-cluster-test-egress-outbound-1"}}},
+	}
+
+	tests := []struct {
+		name        string
+		rules       []OutboundRule
+		old         []OutboundRule
+		expectedErr *field.Error
+	}{
+		{
+			name:  "valid rule",
+			rules: []OutboundRule{validRule},
+		},
+		{
+			name: "invalid rule name",
+			rules: []OutboundRule{
+				{Name: "Egress_1", FrontendIPs: validRule.FrontendIPs},
+			},
+			expectedErr: field.Invalid(field.NewPath("additionalOutboundRules").Index(0).Child("name"), "Egress_1",
+				"name of outbound rule doesn't match regex "+outboundRuleRegex),
+		},
+		{
+			name: "rule name conflicts with the default rule",
+			rules: []OutboundRule{
+				{Name: "outboundnatallprotocols", FrontendIPs: validRule.FrontendIPs},
+			},
+			expectedErr: field.Invalid(field.NewPath("additionalOutboundRules").Index(0).Child("name"), "outboundnatallprotocols",
+				"name of outbound rule conflicts with the default outbound rule"),
+		},
+		{
+			name:        "duplicate rule name",
+			rules:       []OutboundRule{validRule, validRule},
+			expectedErr: field.Duplicate(field.NewPath("additionalOutboundRules").Index(1).Child("name"), "egress"),
+		},
+		{
+			name: "too many frontend IPs",
+			rules: []OutboundRule{
+				{Name: "egress", FrontendIPsCount: ptr.To[int32](17), FrontendIPs: validRule.FrontendIPs},
+			},
+			expectedErr: field.Invalid(field.NewPath("additionalOutboundRules").Index(0).Child("frontendIPsCount"), int32(17),
+				"frontendIPsCount must be between 1 and 16"),
+		},
+		{
+			name: "frontend IP without public IP",
+			rules: []OutboundRule{
+				{Name: "egress", FrontendIPs: []FrontendIP{{Name: "my-frontend"}}},
+			},
+			expectedErr: field.Required(field.NewPath("additionalOutboundRules").Index(0).Child("frontendIPs").Index(0).Child("publicIP"),
+				"frontend IPs of outbound rules must have a public IP"),
+		},
+		{
+			name:  "new rule added",
+			rules: []OutboundRule{validRule, {Name: "other", FrontendIPs: []FrontendIP{{Name: "other", PublicIP: &PublicIPSpec{Name: "other"}}}}},
+			old:   []OutboundRule{validRule},
+		},
+		{
+			name: "delete policy of a public IP changed",
+			rules: []OutboundRule{
+				{
+					Name:        "egress",
+					FrontendIPs: []FrontendIP{{Name: "cluster-test-egress-frontEnd-1", PublicIP: &PublicIPSpec{Name: "pip-cluster-test-egress-outbound-1", DeletePolicy: DeletePolicyRetain}}},
+				},
+			},
+			old: []OutboundRule{validRule},
+		},
+		{
+			name: "frontend IPs of an existing rule changed",
+			rules: []OutboundRule{
+				{Name: "egress", FrontendIPs: []FrontendIP{{Name: "my-frontend", PublicIP: &PublicIPSpec{Name: "my-ip"}}}},
+			},
+			old: []OutboundRule{validRule},
+			expectedErr: field.Forbidden(field.NewPath("additionalOutboundRules").Index(0),
+				"additional outbound rules cannot be modified after AzureCluster creation"),
+		},
+		{
+			name:  "existing rule removed",
+			rules: nil,
+			old:   []OutboundRule{validRule},
+			expectedErr: field.Forbidden(field.NewPath("additionalOutboundRules"),
+				`additional outbound rule "egress" cannot be removed`),
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateAdditionalOutboundRules(test.rules, test.old, field.NewPath("additionalOutboundRules"))
+			if test.expectedErr != nil {
+				g.Expect(errs).To(ContainElement(MatchError(test.expectedErr.Error())))
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidatePublicIPZones(t *testing.T) {
+	withAPIServerIPZones := func(zones ...string) *AzureCluster {
+		cluster := createValidCluster()
+		cluster.Spec.NetworkSpec.APIServerLB.FrontendIPs = []FrontendIP{
+			{Name: "ip-config", PublicIP: &PublicIPSpec{Name: "public-ip", Zones: zones}},
+		}
+		return cluster
+	}
+
+	tests := []struct {
+		name        string
+		cluster     *AzureCluster
+		old         *AzureCluster
+		expectedErr *field.Error
+	}{
+		{
+			name:    "zone-redundant public IP",
+			cluster: withAPIServerIPZones(),
+		},
+		{
+			name:    "zonal public IP",
+			cluster: withAPIServerIPZones("1"),
+		},
+		{
+			name:    "invalid zone",
+			cluster: withAPIServerIPZones("eastus-1"),
+			expectedErr: field.Invalid(field.NewPath("spec", "networkSpec", "apiServerLB", "frontendIPs").Index(0).Child("publicIP", "zones").Index(0),
+				"eastus-1", "availability zone doesn't match regex "+availabilityZoneRegex),
+		},
+		{
+			name:        "duplicate zone",
+			cluster:     withAPIServerIPZones("1", "1"),
+			expectedErr: field.Duplicate(field.NewPath("spec", "networkSpec", "apiServerLB", "frontendIPs").Index(0).Child("publicIP", "zones").Index(1), "1"),
+		},
+		{
+			name:    "unchanged zones",
+			cluster: withAPIServerIPZones("1"),
+			old:     withAPIServerIPZones("1"),
+		},
+		{
+			name:    "changed zones",
+			cluster: withAPIServerIPZones("1"),
+			old:     withAPIServerIPZones(),
+			expectedErr: field.Forbidden(field.NewPath("spec", "networkSpec", "apiServerLB", "frontendIPs").Index(0).Child("publicIP", "zones"),
+				"zones of a public IP cannot be modified after it is created"),
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := test.cluster.validatePublicIPZones(test.old)
+			if test.expectedErr != nil {
+				g.Expect(errs).To(ContainElement(MatchError(test.expectedErr.Error())))
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
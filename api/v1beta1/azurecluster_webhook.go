@@ -17,13 +17,19 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
+	"fmt"
 	"reflect"
 
+	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	webhookutils "sigs.k8s.io/cluster-api-provider-azure/util/webhook"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
@@ -32,10 +38,102 @@ import (
 func (c *AzureCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(c).
+		WithValidator(&azureClusterWebhook{Client: mgr.GetClient()}).
 		Complete()
 }
 
-// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azurecluster,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azureclusters,versions=v1beta1,name=validation.azurecluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+// azureClusterWebhook implements the validating webhook for AzureClusters. It runs the validation of the AzureCluster
+// itself, and then checks the AzureCluster against its Cluster.
+type azureClusterWebhook struct {
+	Client client.Client
+}
+
+// ValidateCreate implements admission.CustomValidator so a webhook will be registered for the type.
+func (cw *azureClusterWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	c, ok := obj.(*AzureCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureCluster resource")
+	}
+	warnings, err := c.ValidateCreate()
+	if err != nil {
+		return warnings, err
+	}
+	return warnings, cw.validateAPIServerPort(ctx, c)
+}
+
+// ValidateUpdate implements admission.CustomValidator so a webhook will be registered for the type.
+func (cw *azureClusterWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	c, ok := newObj.(*AzureCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureCluster resource")
+	}
+	warnings, err := c.ValidateUpdate(oldObj)
+	if err != nil {
+		return warnings, err
+	}
+	return warnings, cw.validateAPIServerPort(ctx, c)
+}
+
+// ValidateDelete implements admission.CustomValidator so a webhook will be registered for the type.
+func (cw *azureClusterWebhook) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	c, ok := obj.(*AzureCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureCluster resource")
+	}
+	return c.ValidateDelete()
+}
+
+// validateAPIServerPort checks that the port of the control plane endpoint set on an AzureCluster matches the API
+// server port of its Cluster. The Cluster isn't known yet when the AzureCluster is created before being owned by it or
+// labeled with its name, and may not exist yet when both are created together; the AzureCluster controller checks the
+// port again in that case.
+func (cw *azureClusterWebhook) validateAPIServerPort(ctx context.Context, c *AzureCluster) error {
+	port := c.Spec.ControlPlaneEndpoint.Port
+	if port == 0 || cw.Client == nil {
+		return nil
+	}
+	clusterName := azureClusterOwnerName(c)
+	if clusterName == "" {
+		return nil
+	}
+
+	cluster := &clusterv1.Cluster{}
+	if err := cw.Client.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: clusterName}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	apiServerPort := int32(6443)
+	if cluster.Spec.ClusterNetwork != nil && cluster.Spec.ClusterNetwork.APIServerPort != nil {
+		apiServerPort = *cluster.Spec.ClusterNetwork.APIServerPort
+	}
+	if port == apiServerPort {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("AzureCluster").GroupKind(), c.Name, field.ErrorList{
+		field.Invalid(field.NewPath("spec", "controlPlaneEndpoint", "port"), port,
+			fmt.Sprintf("must match the API server port %d of Cluster %s, set spec.clusterNetwork.apiServerPort on the Cluster to change the API server port", apiServerPort, clusterName)),
+	})
+}
+
+// azureClusterOwnerName returns the name of the Cluster of an AzureCluster, from its cluster name label or its owner
+// reference, or an empty string when it isn't known yet.
+func azureClusterOwnerName(c *AzureCluster) string {
+	if name := c.Labels[clusterv1.ClusterNameLabel]; name != "" {
+		return name
+	}
+	for _, ref := range c.OwnerReferences {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err == nil && gv.Group == clusterv1.GroupVersion.Group && ref.Kind == "Cluster" {
+			return ref.Name
+		}
+	}
+	return ""
+}
+
+// +kubebuilder:webhook:verbs=create;update;delete,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azurecluster,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azureclusters,versions=v1beta1,name=validation.azurecluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1beta1-azurecluster,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azureclusters,versions=v1beta1,name=default.azurecluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
 var _ webhook.Validator = &AzureCluster{}
@@ -123,14 +221,33 @@ func (c *AzureCluster) ValidateUpdate(oldRaw runtime.Object) (admission.Warnings
 		)
 	}
 
-	if err := webhookutils.ValidateImmutable(
-		field.NewPath("Spec", "NetworkSpec", "ControlPlaneOutboundLB"),
-		old.Spec.NetworkSpec.ControlPlaneOutboundLB,
-		c.Spec.NetworkSpec.ControlPlaneOutboundLB); err != nil {
-		allErrs = append(allErrs, err)
+	allErrs = append(allErrs, c.validateControlPlaneOutboundLBUpdate(old)...)
+
+	if old.Spec.TrafficManager != nil && c.Spec.TrafficManager != nil {
+		if err := webhookutils.ValidateImmutable(
+			field.NewPath("Spec", "TrafficManager", "ProfileName"),
+			old.Spec.TrafficManager.ProfileName,
+			c.Spec.TrafficManager.ProfileName); err != nil {
+			allErrs = append(allErrs, err)
+		}
+		if err := webhookutils.ValidateImmutable(
+			field.NewPath("Spec", "TrafficManager", "ResourceGroup"),
+			old.Spec.TrafficManager.ResourceGroup,
+			c.Spec.TrafficManager.ResourceGroup); err != nil {
+			allErrs = append(allErrs, err)
+		}
+		if err := webhookutils.ValidateImmutable(
+			field.NewPath("Spec", "TrafficManager", "EndpointName"),
+			old.Spec.TrafficManager.EndpointName,
+			c.Spec.TrafficManager.EndpointName); err != nil {
+			allErrs = append(allErrs, err)
+		}
 	}
 
 	allErrs = append(allErrs, c.validateSubnetUpdate(old)...)
+	allErrs = append(allErrs, c.validateNetAppUpdate(old)...)
+	allErrs = append(allErrs, c.validateAzureFirewallUpdate(old)...)
+	allErrs = append(allErrs, c.validateApplicationGatewayUpdate(old)...)
 
 	if len(allErrs) == 0 {
 		return c.validateCluster(old)
@@ -139,6 +256,175 @@ func (c *AzureCluster) ValidateUpdate(oldRaw runtime.Object) (admission.Warnings
 	return nil, apierrors.NewInvalid(GroupVersion.WithKind("AzureCluster").GroupKind(), c.Name, allErrs)
 }
 
+// validateControlPlaneOutboundLBUpdate validates a ClusterSpec.NetworkSpec.ControlPlaneOutboundLB update.
+// The load balancer can't be added or removed, and only its frontend IPs count and idle timeout can be modified.
+func (c *AzureCluster) validateControlPlaneOutboundLBUpdate(old *AzureCluster) field.ErrorList {
+	fldPath := field.NewPath("Spec", "NetworkSpec", "ControlPlaneOutboundLB")
+	oldLB, newLB := old.Spec.NetworkSpec.ControlPlaneOutboundLB, c.Spec.NetworkSpec.ControlPlaneOutboundLB
+
+	if oldLB == nil || newLB == nil {
+		if err := webhookutils.ValidateImmutable(fldPath, oldLB, newLB); err != nil {
+			return field.ErrorList{err}
+		}
+		return nil
+	}
+
+	oldLB, newLB = oldLB.DeepCopy(), newLB.DeepCopy()
+	oldLB.IdleTimeoutInMinutes, newLB.IdleTimeoutInMinutes = nil, nil
+	// The frontend IPs are generated from the frontend IPs count, so frontend IPs are added to or removed from the end
+	// of the list along with it, but the remaining ones can't be modified.
+	if !reflect.DeepEqual(oldLB.FrontendIPsCount, newLB.FrontendIPsCount) {
+		oldLB.FrontendIPsCount, newLB.FrontendIPsCount = nil, nil
+		n := len(oldLB.FrontendIPs)
+		if len(newLB.FrontendIPs) < n {
+			n = len(newLB.FrontendIPs)
+		}
+		oldLB.FrontendIPs = append([]FrontendIP{}, oldLB.FrontendIPs[:n]...)
+		newLB.FrontendIPs = append([]FrontendIP{}, newLB.FrontendIPs[:n]...)
+	}
+
+	if !reflect.DeepEqual(oldLB, newLB) {
+		return field.ErrorList{field.Forbidden(fldPath, "only the frontendIPsCount and idleTimeoutInMinutes of the control plane outbound load balancer can be modified after AzureCluster creation")}
+	}
+	return nil
+}
+
+// validateNetAppUpdate validates a ClusterSpec.NetworkSpec.NetApp for immutability.
+func (c *AzureCluster) validateNetAppUpdate(old *AzureCluster) field.ErrorList {
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("spec", "networkSpec", "netApp")
+
+	oldNetApp, newNetApp := old.Spec.NetworkSpec.NetApp, c.Spec.NetworkSpec.NetApp
+	if oldNetApp == nil {
+		return nil
+	}
+	if newNetApp == nil {
+		return field.ErrorList{field.Forbidden(fldPath, "azure netapp files cannot be removed from a cluster")}
+	}
+
+	if err := webhookutils.ValidateImmutable(
+		fldPath.Child("subnet", "name"),
+		oldNetApp.Subnet.Name,
+		newNetApp.Subnet.Name); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if !reflect.DeepEqual(oldNetApp.Subnet.CIDRBlocks, newNetApp.Subnet.CIDRBlocks) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("subnet", "cidrBlocks"), newNetApp.Subnet.CIDRBlocks, "field is immutable"))
+	}
+
+	if oldNetApp.Account == nil {
+		return allErrs
+	}
+	if newNetApp.Account == nil {
+		return append(allErrs, field.Forbidden(fldPath.Child("account"), "netapp account cannot be removed from a cluster"))
+	}
+	if err := webhookutils.ValidateImmutable(
+		fldPath.Child("account", "name"),
+		oldNetApp.Account.Name,
+		newNetApp.Account.Name); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	oldServiceLevels := make(map[string]NetAppServiceLevel, len(oldNetApp.Account.CapacityPools))
+	for _, pool := range oldNetApp.Account.CapacityPools {
+		oldServiceLevels[pool.Name] = pool.ServiceLevel
+	}
+	for i, pool := range newNetApp.Account.CapacityPools {
+		if oldServiceLevel, ok := oldServiceLevels[pool.Name]; ok && oldServiceLevel != pool.ServiceLevel {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("account", "capacityPools").Index(i).Child("serviceLevel"),
+				pool.ServiceLevel, "field is immutable"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateAzureFirewallUpdate validates a ClusterSpec.NetworkSpec.OutboundType and AzureFirewall for immutability.
+func (c *AzureCluster) validateAzureFirewallUpdate(old *AzureCluster) field.ErrorList {
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("spec", "networkSpec", "azureFirewall")
+
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("spec", "networkSpec", "outboundType"),
+		old.Spec.NetworkSpec.OutboundType,
+		c.Spec.NetworkSpec.OutboundType); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	oldFirewall, newFirewall := old.Spec.NetworkSpec.AzureFirewall, c.Spec.NetworkSpec.AzureFirewall
+	if oldFirewall == nil {
+		return allErrs
+	}
+	if newFirewall == nil {
+		return append(allErrs, field.Forbidden(fldPath, "azure firewall cannot be removed from a cluster"))
+	}
+
+	if err := webhookutils.ValidateImmutable(
+		fldPath.Child("id"),
+		oldFirewall.ID,
+		newFirewall.ID); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := webhookutils.ValidateImmutable(
+		fldPath.Child("name"),
+		oldFirewall.Name,
+		newFirewall.Name); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if !reflect.DeepEqual(oldFirewall.Subnet.CIDRBlocks, newFirewall.Subnet.CIDRBlocks) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("subnet", "cidrBlocks"), newFirewall.Subnet.CIDRBlocks, "field is immutable"))
+	}
+	if err := webhookutils.ValidateImmutable(
+		fldPath.Child("privateIPAddress"),
+		oldFirewall.PrivateIPAddress,
+		newFirewall.PrivateIPAddress); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	return allErrs
+}
+
+// validateApplicationGatewayUpdate validates a ClusterSpec.NetworkSpec.ApplicationGateway for immutability.
+func (c *AzureCluster) validateApplicationGatewayUpdate(old *AzureCluster) field.ErrorList {
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("spec", "networkSpec", "applicationGateway")
+
+	oldAppGateway, newAppGateway := old.Spec.NetworkSpec.ApplicationGateway, c.Spec.NetworkSpec.ApplicationGateway
+	if oldAppGateway == nil {
+		return allErrs
+	}
+	if newAppGateway == nil {
+		return append(allErrs, field.Forbidden(fldPath, "application gateway cannot be removed from a cluster"))
+	}
+
+	if err := webhookutils.ValidateImmutable(
+		fldPath.Child("controller"),
+		oldAppGateway.Controller,
+		newAppGateway.Controller); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := webhookutils.ValidateImmutable(
+		fldPath.Child("id"),
+		oldAppGateway.ID,
+		newAppGateway.ID); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := webhookutils.ValidateImmutable(
+		fldPath.Child("name"),
+		oldAppGateway.Name,
+		newAppGateway.Name); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := webhookutils.ValidateImmutable(
+		fldPath.Child("subnetName"),
+		oldAppGateway.SubnetName,
+		newAppGateway.SubnetName); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	return allErrs
+}
+
 // validateSubnetUpdate validates a ClusterSpec.NetworkSpec.Subnets for immutability.
 func (c *AzureCluster) validateSubnetUpdate(old *AzureCluster) field.ErrorList {
 	var allErrs field.ErrorList
@@ -189,5 +475,9 @@ func (c *AzureCluster) validateSubnetUpdate(old *AzureCluster) field.ErrorList {
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
 func (c *AzureCluster) ValidateDelete() (admission.Warnings, error) {
+	if c.Spec.DeletionProtection {
+		return nil, apierrors.NewForbidden(GroupVersion.WithResource("azureclusters").GroupResource(), c.Name,
+			errors.New("deletion protection is enabled, set spec.deletionProtection to false before deleting the AzureCluster"))
+	}
 	return nil, nil
 }
@@ -17,10 +17,15 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestAzureCluster_ValidateCreate(t *testing.T) {
@@ -167,6 +172,74 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 			cluster:    createValidCluster(),
 			wantErr:    false,
 		},
+		{
+			name:       "azurecluster adding netapp - valid spec",
+			oldCluster: createValidCluster(),
+			cluster:    createValidClusterWithNetApp(),
+			wantErr:    false,
+		},
+		{
+			name:       "azurecluster resizing a netapp capacity pool - valid spec",
+			oldCluster: createValidClusterWithNetApp(),
+			cluster: func() *AzureCluster {
+				cluster := createValidClusterWithNetApp()
+				cluster.Spec.NetworkSpec.NetApp.Account.CapacityPools[0].SizeTiB = 8
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name:       "azurecluster removing netapp - invalid spec",
+			oldCluster: createValidClusterWithNetApp(),
+			cluster:    createValidCluster(),
+			wantErr:    true,
+		},
+		{
+			name:       "azurecluster changing netapp account name - invalid spec",
+			oldCluster: createValidClusterWithNetApp(),
+			cluster: func() *AzureCluster {
+				cluster := createValidClusterWithNetApp()
+				cluster.Spec.NetworkSpec.NetApp.Account.Name = "other-account"
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name:       "azurecluster changing netapp capacity pool service level - invalid spec",
+			oldCluster: createValidClusterWithNetApp(),
+			cluster: func() *AzureCluster {
+				cluster := createValidClusterWithNetApp()
+				cluster.Spec.NetworkSpec.NetApp.Account.CapacityPools[0].ServiceLevel = NetAppServiceLevelUltra
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name:       "azurecluster adding allowed FQDNs to the azure firewall - valid spec",
+			oldCluster: createValidClusterWithAzureFirewall(),
+			cluster: func() *AzureCluster {
+				cluster := createValidClusterWithAzureFirewall()
+				cluster.Spec.NetworkSpec.AzureFirewall.AllowedFQDNs = []string{"example.com"}
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name:       "azurecluster switching to an azure firewall - invalid spec",
+			oldCluster: createValidCluster(),
+			cluster:    createValidClusterWithAzureFirewall(),
+			wantErr:    true,
+		},
+		{
+			name:       "azurecluster changing azure firewall private IP address - invalid spec",
+			oldCluster: createValidClusterWithAzureFirewall(),
+			cluster: func() *AzureCluster {
+				cluster := createValidClusterWithAzureFirewall()
+				cluster.Spec.NetworkSpec.AzureFirewall.PrivateIPAddress = "10.255.255.5"
+				return cluster
+			}(),
+			wantErr: true,
+		},
 		{
 			name: "azurecluster without pre-existing vnet - valid spec",
 			oldCluster: func() *AzureCluster {
@@ -356,3 +429,305 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureCluster_ValidateControlPlaneOutboundLBUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldLB   *LoadBalancerSpec
+		lb      *LoadBalancerSpec
+		wantErr bool
+	}{
+		{
+			name:    "control plane outbound lb can't be added",
+			oldLB:   nil,
+			lb:      &LoadBalancerSpec{Name: "cp-lb"},
+			wantErr: true,
+		},
+		{
+			name:    "control plane outbound lb can't be removed",
+			oldLB:   &LoadBalancerSpec{Name: "cp-lb"},
+			lb:      nil,
+			wantErr: true,
+		},
+		{
+			name:    "control plane outbound lb name is immutable",
+			oldLB:   &LoadBalancerSpec{Name: "cp-lb"},
+			lb:      &LoadBalancerSpec{Name: "cp-lb-new"},
+			wantErr: true,
+		},
+		{
+			name: "control plane outbound lb SKU is immutable",
+			oldLB: &LoadBalancerSpec{
+				Name:                  "cp-lb",
+				LoadBalancerClassSpec: LoadBalancerClassSpec{SKU: SKUStandard},
+			},
+			lb: &LoadBalancerSpec{
+				Name:                  "cp-lb",
+				LoadBalancerClassSpec: LoadBalancerClassSpec{SKU: "Basic"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "control plane outbound lb frontend IPs can't change without the frontend IPs count",
+			oldLB: &LoadBalancerSpec{
+				Name:             "cp-lb",
+				FrontendIPsCount: ptr.To[int32](1),
+				FrontendIPs:      []FrontendIP{{Name: "cp-lb-frontEnd"}},
+			},
+			lb: &LoadBalancerSpec{
+				Name:             "cp-lb",
+				FrontendIPsCount: ptr.To[int32](1),
+				FrontendIPs:      []FrontendIP{{Name: "cp-lb-frontEnd-new"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "control plane outbound lb frontend IPs count can be modified",
+			oldLB: &LoadBalancerSpec{
+				Name:             "cp-lb",
+				FrontendIPsCount: ptr.To[int32](1),
+				FrontendIPs:      []FrontendIP{{Name: "cp-lb-frontEnd"}},
+			},
+			lb: &LoadBalancerSpec{
+				Name:             "cp-lb",
+				FrontendIPsCount: ptr.To[int32](2),
+				FrontendIPs:      []FrontendIP{{Name: "cp-lb-frontEnd"}, {Name: "cp-lb-frontEnd-2"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "control plane outbound lb frontend IPs can't be renamed when the frontend IPs count changes",
+			oldLB: &LoadBalancerSpec{
+				Name:             "cp-lb",
+				FrontendIPsCount: ptr.To[int32](1),
+				FrontendIPs:      []FrontendIP{{Name: "cp-lb-frontEnd"}},
+			},
+			lb: &LoadBalancerSpec{
+				Name:             "cp-lb",
+				FrontendIPsCount: ptr.To[int32](2),
+				FrontendIPs:      []FrontendIP{{Name: "cp-lb-frontEnd-1"}, {Name: "cp-lb-frontEnd-2"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "control plane outbound lb idle timeout can be modified",
+			oldLB: &LoadBalancerSpec{
+				Name:                  "cp-lb",
+				LoadBalancerClassSpec: LoadBalancerClassSpec{IdleTimeoutInMinutes: ptr.To[int32](4)},
+			},
+			lb: &LoadBalancerSpec{
+				Name:                  "cp-lb",
+				LoadBalancerClassSpec: LoadBalancerClassSpec{IdleTimeoutInMinutes: ptr.To[int32](30)},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			oldCluster := &AzureCluster{Spec: AzureClusterSpec{NetworkSpec: NetworkSpec{ControlPlaneOutboundLB: tc.oldLB}}}
+			cluster := &AzureCluster{Spec: AzureClusterSpec{NetworkSpec: NetworkSpec{ControlPlaneOutboundLB: tc.lb}}}
+			errs := cluster.validateControlPlaneOutboundLBUpdate(oldCluster)
+			if tc.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureCluster_ValidateApplicationGatewayUpdate(t *testing.T) {
+	tests := []struct {
+		name          string
+		oldAppGateway *ApplicationGatewaySpec
+		appGateway    *ApplicationGatewaySpec
+		wantErr       bool
+	}{
+		{
+			name:       "application gateway can be added",
+			appGateway: &ApplicationGatewaySpec{Controller: ApplicationGatewayIngressController, Name: "appgw"},
+			wantErr:    false,
+		},
+		{
+			name:          "application gateway can't be removed",
+			oldAppGateway: &ApplicationGatewaySpec{Controller: ApplicationGatewayIngressController, Name: "appgw"},
+			wantErr:       true,
+		},
+		{
+			name:          "application gateway controller is immutable",
+			oldAppGateway: &ApplicationGatewaySpec{Controller: ApplicationGatewayIngressController},
+			appGateway:    &ApplicationGatewaySpec{Controller: ApplicationGatewayForContainers},
+			wantErr:       true,
+		},
+		{
+			name:          "application gateway name is immutable",
+			oldAppGateway: &ApplicationGatewaySpec{Controller: ApplicationGatewayIngressController, Name: "appgw"},
+			appGateway:    &ApplicationGatewaySpec{Controller: ApplicationGatewayIngressController, Name: "appgw-new"},
+			wantErr:       true,
+		},
+		{
+			name:          "application gateway subnet name is immutable",
+			oldAppGateway: &ApplicationGatewaySpec{Controller: ApplicationGatewayIngressController, SubnetName: "ingress-subnet"},
+			appGateway:    &ApplicationGatewaySpec{Controller: ApplicationGatewayIngressController, SubnetName: "other-subnet"},
+			wantErr:       true,
+		},
+		{
+			name:          "application gateway principal ID can be modified",
+			oldAppGateway: &ApplicationGatewaySpec{Controller: ApplicationGatewayIngressController, Name: "appgw"},
+			appGateway:    &ApplicationGatewaySpec{Controller: ApplicationGatewayIngressController, Name: "appgw", PrincipalID: "00000000-0000-0000-0000-000000000000"},
+			wantErr:       false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			oldCluster := &AzureCluster{Spec: AzureClusterSpec{NetworkSpec: NetworkSpec{ApplicationGateway: tc.oldAppGateway}}}
+			cluster := &AzureCluster{Spec: AzureClusterSpec{NetworkSpec: NetworkSpec{ApplicationGateway: tc.appGateway}}}
+			errs := cluster.validateApplicationGatewayUpdate(oldCluster)
+			if tc.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureCluster_ValidateDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster *AzureCluster
+		wantErr bool
+	}{
+		{
+			name:    "azurecluster without deletion protection",
+			cluster: createValidCluster(),
+			wantErr: false,
+		},
+		{
+			name: "azurecluster with deletion protection",
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.DeletionProtection = true
+				return cluster
+			}(),
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := tc.cluster.ValidateDelete()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureClusterWebhook_ValidateAPIServerPort(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster *AzureCluster
+		owner   *clusterv1.Cluster
+		wantErr bool
+	}{
+		{
+			name:    "control plane endpoint without a port",
+			cluster: createAzureClusterWithControlPlanePort(0),
+			owner:   createClusterWithAPIServerPort(ptr.To[int32](8443)),
+			wantErr: false,
+		},
+		{
+			name:    "control plane endpoint port matching the default API server port",
+			cluster: createAzureClusterWithControlPlanePort(6443),
+			owner:   createClusterWithAPIServerPort(nil),
+			wantErr: false,
+		},
+		{
+			name:    "control plane endpoint port matching the API server port",
+			cluster: createAzureClusterWithControlPlanePort(8443),
+			owner:   createClusterWithAPIServerPort(ptr.To[int32](8443)),
+			wantErr: false,
+		},
+		{
+			name:    "control plane endpoint port not matching the API server port",
+			cluster: createAzureClusterWithControlPlanePort(6443),
+			owner:   createClusterWithAPIServerPort(ptr.To[int32](8443)),
+			wantErr: true,
+		},
+		{
+			name: "control plane endpoint port not matching the API server port of the owner Cluster",
+			cluster: func() *AzureCluster {
+				cluster := createAzureClusterWithControlPlanePort(6443)
+				cluster.Labels = nil
+				cluster.OwnerReferences = []metav1.OwnerReference{
+					{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: "my-cluster"},
+				}
+				return cluster
+			}(),
+			owner:   createClusterWithAPIServerPort(ptr.To[int32](8443)),
+			wantErr: true,
+		},
+		{
+			name: "Cluster not known yet",
+			cluster: func() *AzureCluster {
+				cluster := createAzureClusterWithControlPlanePort(6443)
+				cluster.Labels = nil
+				return cluster
+			}(),
+			owner:   createClusterWithAPIServerPort(ptr.To[int32](8443)),
+			wantErr: false,
+		},
+		{
+			name:    "Cluster not created yet",
+			cluster: createAzureClusterWithControlPlanePort(6443),
+			wantErr: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = AddToScheme(scheme)
+			_ = clusterv1.AddToScheme(scheme)
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if tc.owner != nil {
+				builder = builder.WithObjects(tc.owner)
+			}
+			cw := &azureClusterWebhook{Client: builder.Build()}
+			err := cw.validateAPIServerPort(context.Background(), tc.cluster)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func createAzureClusterWithControlPlanePort(port int32) *AzureCluster {
+	cluster := createValidCluster()
+	cluster.Namespace = "default"
+	cluster.Labels = map[string]string{clusterv1.ClusterNameLabel: "my-cluster"}
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "apiserver.example.com", Port: port}
+	return cluster
+}
+
+func createClusterWithAPIServerPort(port *int32) *clusterv1.Cluster {
+	return &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster",
+			Namespace: "default",
+		},
+		Spec: clusterv1.ClusterSpec{
+			ClusterNetwork: &clusterv1.ClusterNetwork{APIServerPort: port},
+		},
+	}
+}
@@ -58,6 +58,11 @@ type AzureClusterIdentitySpec struct {
 	ClientSecret corev1.SecretReference `json:"clientSecret,omitempty"`
 	// TenantID is the service principal primary tenant id.
 	TenantID string `json:"tenantID"`
+	// SendCertificateChain enables subject name and issuer (SNI) based authentication by sending the
+	// certificate chain with each token request.
+	// Only applicable when type is ServicePrincipalCertificate.
+	// +optional
+	SendCertificateChain bool `json:"sendCertificateChain,omitempty"`
 	// AllowedNamespaces is used to identify the namespaces the clusters are allowed to use the identity from.
 	// Namespaces can be selected either using an array of namespaces or with label selector.
 	// An empty allowedNamespaces object indicates that AzureClusters can use this identity from any namespace.
@@ -29,6 +29,9 @@ func (c *AzureClusterIdentity) validateClusterIdentity() (admission.Warnings, er
 	} else if c.Spec.Type != UserAssignedMSI && c.Spec.ResourceID != "" {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "resourceID"), c.Spec.ResourceID))
 	}
+	if c.Spec.Type != ServicePrincipalCertificate && c.Spec.SendCertificateChain {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "sendCertificateChain"), "sendCertificateChain can only be set for ServicePrincipalCertificate identities"))
+	}
 	if len(allErrs) == 0 {
 		return nil, nil
 	}
@@ -80,6 +80,30 @@ func TestAzureClusterIdentity_ValidateCreate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "azureclusteridentity with service principal certificate and certificate chain",
+			clusterIdentity: &AzureClusterIdentity{
+				Spec: AzureClusterIdentitySpec{
+					Type:                 ServicePrincipalCertificate,
+					ClientID:             fakeClientID,
+					TenantID:             fakeTenantID,
+					SendCertificateChain: true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "azureclusteridentity with service principal and certificate chain",
+			clusterIdentity: &AzureClusterIdentity{
+				Spec: AzureClusterIdentitySpec{
+					Type:                 ServicePrincipal,
+					ClientID:             fakeClientID,
+					TenantID:             fakeTenantID,
+					SendCertificateChain: true,
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"strings"
+
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -29,6 +31,21 @@ func ValidateImage(image *Image, fldPath *field.Path) field.ErrorList {
 		return allErrs
 	}
 
+	hasDetails := image.ID != nil || image.SharedGallery != nil || image.Marketplace != nil || image.ComputeGallery != nil
+
+	if len(image.LocationOverrides) > 0 {
+		allErrs = append(allErrs, validateLocationOverrides(image.LocationOverrides, fldPath.Child("locationOverrides"))...)
+	}
+
+	if image.Variant != "" && hasDetails {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("variant"), "a variant can only be selected for the default reference image"))
+	}
+
+	// an image with location overrides or a variant may leave the image details empty to use the default image
+	if !hasDetails && (len(image.LocationOverrides) > 0 || image.Variant != "") {
+		return allErrs
+	}
+
 	allErrs = append(allErrs, validateSingleDetailsOnly(image, fldPath)...)
 
 	if image.Marketplace != nil {
@@ -47,6 +64,14 @@ func ValidateImage(image *Image, fldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+// ValidateImageVariant validates that the variant of an image is supported by the OS of the machine.
+func ValidateImageVariant(image *Image, osType string, fldPath *field.Path) field.ErrorList {
+	if image == nil || image.Variant == "" || osType != WindowsOS {
+		return nil
+	}
+	return field.ErrorList{field.Forbidden(fldPath, "reference image variants are only published for Linux")}
+}
+
 func validateSingleDetailsOnly(image *Image, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	imageDetailsFound := false
@@ -86,6 +111,26 @@ func validateSingleDetailsOnly(image *Image, fldPath *field.Path) field.ErrorLis
 	return allErrs
 }
 
+func validateLocationOverrides(overrides []ImageLocationOverride, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	locations := make(map[string]struct{}, len(overrides))
+
+	for i, override := range overrides {
+		if override.Location == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("location"), "location cannot be empty"))
+		}
+		location := strings.ToLower(override.Location)
+		if _, ok := locations[location]; ok {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("location"), override.Location))
+		}
+		locations[location] = struct{}{}
+
+		allErrs = append(allErrs, ValidateImage(override.Image(), fldPath.Index(i))...)
+	}
+
+	return allErrs
+}
+
 func validateComputeGalleryImage(image *Image, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
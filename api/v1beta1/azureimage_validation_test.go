@@ -185,6 +185,159 @@ func TestImageByIDValid(t *testing.T) {
 	}
 }
 
+func TestImageLocationOverridesValid(t *testing.T) {
+	g := NewWithT(t)
+
+	testCases := map[string]struct {
+		image          *Image
+		expectedErrors int
+	}{
+		"location overrides only": {
+			expectedErrors: 0,
+			image: &Image{
+				LocationOverrides: []ImageLocationOverride{
+					{Location: "westeurope", ID: ptr.To("ID1234")},
+					{Location: "eastus", ID: ptr.To("ID5678")},
+				},
+			},
+		},
+		"location overrides with image details": {
+			expectedErrors: 0,
+			image: &Image{
+				ID: ptr.To("ID1234"),
+				LocationOverrides: []ImageLocationOverride{
+					{Location: "eastus", ID: ptr.To("ID5678")},
+				},
+			},
+		},
+		"location override without location": {
+			expectedErrors: 1,
+			image: &Image{
+				LocationOverrides: []ImageLocationOverride{
+					{ID: ptr.To("ID1234")},
+				},
+			},
+		},
+		"duplicate location override": {
+			expectedErrors: 1,
+			image: &Image{
+				LocationOverrides: []ImageLocationOverride{
+					{Location: "eastus", ID: ptr.To("ID1234")},
+					{Location: "EastUS", ID: ptr.To("ID5678")},
+				},
+			},
+		},
+		"location override without image details": {
+			expectedErrors: 1,
+			image: &Image{
+				LocationOverrides: []ImageLocationOverride{
+					{Location: "eastus"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		g.Expect(ValidateImage(tc.image, field.NewPath("image"))).To(HaveLen(tc.expectedErrors))
+	}
+}
+
+func TestImageVariantValid(t *testing.T) {
+	g := NewWithT(t)
+
+	testCases := map[string]struct {
+		image          *Image
+		osType         string
+		expectedErrors int
+	}{
+		"variant only": {
+			expectedErrors: 0,
+			osType:         LinuxOS,
+			image:          &Image{Variant: ImageVariantFIPS},
+		},
+		"variant with location overrides": {
+			expectedErrors: 0,
+			osType:         LinuxOS,
+			image: &Image{
+				Variant: ImageVariantCIS,
+				LocationOverrides: []ImageLocationOverride{
+					{Location: "eastus", ID: ptr.To("ID5678")},
+				},
+			},
+		},
+		"variant with image details": {
+			expectedErrors: 1,
+			osType:         LinuxOS,
+			image: &Image{
+				Variant: ImageVariantFIPS,
+				ID:      ptr.To("ID1234"),
+			},
+		},
+		"variant for Windows": {
+			expectedErrors: 1,
+			osType:         WindowsOS,
+			image:          &Image{Variant: ImageVariantFIPS},
+		},
+	}
+
+	for _, tc := range testCases {
+		errs := ValidateImage(tc.image, field.NewPath("image"))
+		errs = append(errs, ValidateImageVariant(tc.image, tc.osType, field.NewPath("image", "variant"))...)
+		g.Expect(errs).To(HaveLen(tc.expectedErrors))
+	}
+}
+
+func TestImageForLocation(t *testing.T) {
+	testCases := map[string]struct {
+		image    *Image
+		location string
+		expected *Image
+	}{
+		"nil image": {
+			image:    nil,
+			location: "eastus",
+			expected: nil,
+		},
+		"matching location override": {
+			image: &Image{
+				ID: ptr.To("ID1234"),
+				LocationOverrides: []ImageLocationOverride{
+					{Location: "eastus", ID: ptr.To("ID5678")},
+				},
+			},
+			location: "eastus",
+			expected: &Image{ID: ptr.To("ID5678")},
+		},
+		"no matching location override": {
+			image: &Image{
+				ID: ptr.To("ID1234"),
+				LocationOverrides: []ImageLocationOverride{
+					{Location: "eastus", ID: ptr.To("ID5678")},
+				},
+			},
+			location: "westeurope",
+			expected: &Image{ID: ptr.To("ID1234")},
+		},
+		"no matching location override and no image details": {
+			image: &Image{
+				LocationOverrides: []ImageLocationOverride{
+					{Location: "eastus", ID: ptr.To("ID5678")},
+				},
+			},
+			location: "westeurope",
+			expected: nil,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(tc.image.ForLocation(tc.location)).To(Equal(tc.expected))
+		})
+	}
+}
+
 func createTestComputeImage(subscriptionID, resourceGroup *string) *Image {
 	return &Image{
 		ComputeGallery: &AzureComputeGalleryImage{
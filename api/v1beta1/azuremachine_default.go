@@ -77,8 +77,9 @@ func (s *AzureMachineSpec) SetDataDisksDefaults() {
 			}
 		}
 		if disk.CachingType == "" {
-			if s.DataDisks[i].ManagedDisk != nil &&
-				s.DataDisks[i].ManagedDisk.StorageAccountType == string(compute.StorageAccountTypesUltraSSDLRS) {
+			// Host caching isn't supported for ultra disks and shared disks.
+			if s.DataDisks[i].IsShared() || (s.DataDisks[i].ManagedDisk != nil &&
+				s.DataDisks[i].ManagedDisk.StorageAccountType == string(compute.StorageAccountTypesUltraSSDLRS)) {
 				s.DataDisks[i].CachingType = string(compute.CachingTypesNone)
 			} else {
 				s.DataDisks[i].CachingType = string(compute.CachingTypesReadWrite)
@@ -166,9 +167,9 @@ func (s *AzureMachineSpec) SetNetworkInterfacesDefaults() {
 		s.AcceleratedNetworking = nil
 	}
 
-	// Ensure that PrivateIPConfigs defaults to 1 if not specified.
+	// Ensure that PrivateIPConfigs defaults to 1 if not specified, unless the network interface already exists.
 	for i := 0; i < len(s.NetworkInterfaces); i++ {
-		if s.NetworkInterfaces[i].PrivateIPConfigs == 0 {
+		if s.NetworkInterfaces[i].ID == "" && s.NetworkInterfaces[i].PrivateIPConfigs == 0 {
 			s.NetworkInterfaces[i].PrivateIPConfigs = 1
 		}
 	}
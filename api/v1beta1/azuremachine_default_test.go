@@ -283,6 +283,32 @@ func TestAzureMachineSpec_SetDataDisksDefaults(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "shared disk caching type",
+			disks: []DataDisk{
+				{
+					NameSuffix: "testdisk1",
+					DiskSizeGB: 256,
+					Lun:        ptr.To[int32](0),
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+						MaxShares:          ptr.To[int32](2),
+					},
+				},
+			},
+			output: []DataDisk{
+				{
+					NameSuffix: "testdisk1",
+					DiskSizeGB: 256,
+					Lun:        ptr.To[int32](0),
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+						MaxShares:          ptr.To[int32](2),
+					},
+					CachingType: "None",
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -560,6 +586,7 @@ func hardcodedAzureMachineWithSSHKey(sshPublicKey string) *AzureMachine {
 			},
 		},
 		Spec: AzureMachineSpec{
+			VMSize:       "Standard_D2s_v3",
 			SSHPublicKey: sshPublicKey,
 			OSDisk:       generateValidOSDisk(),
 			Image: &Image{
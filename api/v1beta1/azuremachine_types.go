@@ -28,6 +28,22 @@ const (
 	// MachineFinalizer allows ReconcileAzureMachine to clean up Azure resources associated with AzureMachine before
 	// removing it from the apiserver.
 	MachineFinalizer = "azuremachine.infrastructure.cluster.x-k8s.io"
+
+	// ResizeOSDiskAnnotation is set on an AzureMachine to allow growing its OS disk in place by increasing
+	// osDisk.diskSizeGB. The virtual machine is deallocated while its OS disk is resized, and started again afterwards.
+	ResizeOSDiskAnnotation = "azuremachine.infrastructure.cluster.x-k8s.io/resize-os-disk"
+
+	// ResizeVMAnnotation is set on an AzureMachine to allow changing vmSize, in which case the existing virtual
+	// machine is resized in place instead of ignoring the change.
+	ResizeVMAnnotation = "azuremachine.infrastructure.cluster.x-k8s.io/resize-vm"
+
+	// UpdateDataDisksAnnotation is set on an AzureMachine to allow adding and removing dataDisks, in which case the
+	// disks are attached to and detached from the existing virtual machine in place.
+	UpdateDataDisksAnnotation = "azuremachine.infrastructure.cluster.x-k8s.io/update-data-disks"
+
+	// EstimatedHourlyCostAnnotation is set on AzureMachines and AzureMachinePools to the estimated hourly cost in USD of
+	// a single VM, including its managed OS disk, when the CostEstimation feature is enabled.
+	EstimatedHourlyCostAnnotation = "sigs.k8s.io/cluster-api-provider-azure-estimated-hourly-cost-usd"
 )
 
 // AzureMachineSpec defines the desired state of AzureMachine.
@@ -36,7 +52,22 @@ type AzureMachineSpec struct {
 	// +optional
 	ProviderID *string `json:"providerID,omitempty"`
 
-	VMSize string `json:"vmSize"`
+	// VMSize is the size of the virtual machine.
+	// It can be left empty when VMSizeClassRef is set, in which case it is resolved from the referenced size class
+	// when the virtual machine is first reconciled.
+	// It can only be changed on an AzureMachine with the resize-vm annotation.
+	// +optional
+	VMSize string `json:"vmSize,omitempty"`
+
+	// VMSizeClassRef references a size class of an AzureVMSizeCatalog to resolve VMSize from.
+	// It allows the same machine template to be used in locations that offer different VM sizes.
+	// +optional
+	VMSizeClassRef *VMSizeClassReference `json:"vmSizeClassRef,omitempty"`
+
+	// MinimumVMResources overrides the minimum vCPUs and memory the VM size must have, which default to 2 vCPUs
+	// and 2 GiB of memory. Lower them to use small burstable VM sizes, for instance in development clusters.
+	// +optional
+	MinimumVMResources *MinimumVMResources `json:"minimumVMResources,omitempty"`
 
 	// FailureDomain is the failure domain unique identifier this Machine should be attached to,
 	// as defined in Cluster API. This relates to an Azure Availability Zone
@@ -81,11 +112,30 @@ type AzureMachineSpec struct {
 	// +optional
 	DataDisks []DataDisk `json:"dataDisks,omitempty"`
 
+	// DiskSnapshot enables taking snapshots of the OS and data disks of the machine before they are deleted,
+	// so they can be inspected after the machine is gone.
+	// +optional
+	DiskSnapshot *DiskSnapshot `json:"diskSnapshot,omitempty"`
+
 	// SSHPublicKey is the SSH public key string, base64-encoded to add to a Virtual Machine. Linux only.
 	// Refer to documentation on how to set up SSH access on Windows instances.
 	// +optional
 	SSHPublicKey string `json:"sshPublicKey"`
 
+	// AdditionalSSHPublicKeys is a list of SSH public key strings, base64-encoded, authorized in addition to the
+	// SSHPublicKey, such as break-glass or team keys. Linux only.
+	// Immutable.
+	// +optional
+	AdditionalSSHPublicKeys []string `json:"additionalSSHPublicKeys,omitempty"`
+
+	// AdminUsername is the name of the administrator account of the VM, to which the SSHPublicKey is authorized.
+	// Linux only. It must not be a name reserved by Azure, such as root or admin. Defaults to capi.
+	// Immutable.
+	// +kubebuilder:validation:Pattern=`^[a-z_][-a-z0-9_]*$`
+	// +kubebuilder:validation:MaxLength=32
+	// +optional
+	AdminUsername string `json:"adminUsername,omitempty"`
+
 	// AdditionalTags is an optional set of tags to add to an instance, in addition to the ones added by default by the
 	// Azure provider. If both the AzureCluster and the AzureMachine specify the same tag name with different values, the
 	// AzureMachine's value takes precedence.
@@ -120,10 +170,38 @@ type AzureMachineSpec struct {
 	// +optional
 	SpotVMOptions *SpotVMOptions `json:"spotVMOptions,omitempty"`
 
+	// TerminateNotificationTimeout enables the Scheduled Events termination notification of the virtual machine with the
+	// specified timeout in minutes, giving the node time to drain before the virtual machine is deleted.
+	// Allowed values are between 5 and 15. Immutable.
+	// +optional
+	TerminateNotificationTimeout *int `json:"terminateNotificationTimeout,omitempty"`
+
+	// DedicatedHost places the virtual machine on an Azure Dedicated Host, for workloads that require physical isolation.
+	// The availability zone of the machine must match the zone of the host group, and the machine isn't added to an availability set.
+	// Immutable.
+	// +optional
+	DedicatedHost *DedicatedHost `json:"dedicatedHost,omitempty"`
+
 	// SecurityProfile specifies the Security profile settings for a virtual machine.
 	// +optional
 	SecurityProfile *SecurityProfile `json:"securityProfile,omitempty"`
 
+	// VaultSecrets are the certificates from Key Vault to install on the virtual machine when it is provisioned,
+	// for instance to serve kubelet with a certificate or to trust a private certificate authority.
+	// +optional
+	VaultSecrets []VaultSecretGroup `json:"vaultSecrets,omitempty"`
+
+	// VMGalleryApplications are the Azure Compute Gallery applications to install on the virtual machine when it is
+	// provisioned, such as monitoring agents, without having to use a custom script extension.
+	// Immutable.
+	// +optional
+	VMGalleryApplications []VMGalleryApplication `json:"vmGalleryApplications,omitempty"`
+
+	// BootstrapEncryption enables envelope encryption of the bootstrap data of the virtual machine with a Key Vault key.
+	// It is only supported for Linux machines bootstrapped with cloud-init.
+	// +optional
+	BootstrapEncryption *BootstrapEncryption `json:"bootstrapEncryption,omitempty"`
+
 	// Deprecated: SubnetName should be set in the networkInterfaces field.
 	// +optional
 	SubnetName string `json:"subnetName,omitempty"`
@@ -136,6 +214,27 @@ type AzureMachineSpec struct {
 	// +optional
 	VMExtensions []VMExtension `json:"vmExtensions,omitempty"`
 
+	// EnableGPUDriverExtension installs the GPU driver VM extension on VM sizes with GPUs. It isn't needed when the
+	// image already contains the GPU driver, or when the driver is installed by other means, such as the NVIDIA GPU
+	// Operator.
+	// +optional
+	EnableGPUDriverExtension bool `json:"enableGPUDriverExtension,omitempty"`
+
+	// EnableAutomaticExtensionUpgrade enables the automatic upgrade of the VM extensions installed by CAPZ, such as the
+	// bootstrap extension and the GPU driver extension, so that new versions of them, including security fixes, are
+	// rolled out by Azure without replacing the machine. Custom VM extensions use their own enableAutomaticUpgrade.
+	// +optional
+	EnableAutomaticExtensionUpgrade bool `json:"enableAutomaticExtensionUpgrade,omitempty"`
+
+	// RunCommands are scripts to run on the virtual machine with Azure Run Command once it is bootstrapped, for
+	// day-2 operations such as rotating certificates or collecting logs without SSH access to the machine.
+	// A run command runs again when it is changed. The result of the run commands is reported in the
+	// RunCommandsSucceeded condition.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	RunCommands []RunCommand `json:"runCommands,omitempty"`
+
 	// NetworkInterfaces specifies a list of network interface configurations.
 	// If left unspecified, the VM will get a single network interface with a
 	// single IPConfig in the subnet specified in the cluster's node subnet field.
@@ -153,6 +252,34 @@ type SpotVMOptions struct {
 	// EvictionPolicy defines the behavior of the virtual machine when it is evicted. It can be either Delete or Deallocate.
 	// +optional
 	EvictionPolicy *SpotEvictionPolicy `json:"evictionPolicy,omitempty"`
+
+	// RestorePolicy configures Azure to try to restore Spot instances evicted from a scale set. It is only
+	// supported by AzureMachinePools.
+	// +optional
+	RestorePolicy *SpotRestorePolicy `json:"restorePolicy,omitempty"`
+}
+
+// SpotRestorePolicy defines how Azure tries to restore evicted Spot instances of a scale set.
+type SpotRestorePolicy struct {
+	// Enabled enables Azure to restore evicted Spot instances opportunistically, as capacity and pricing allow.
+	Enabled bool `json:"enabled"`
+
+	// RestoreTimeout is how long Azure tries to restore an evicted instance before giving up. Azure defaults it to 1h.
+	// +optional
+	RestoreTimeout *metav1.Duration `json:"restoreTimeout,omitempty"`
+}
+
+// DedicatedHost defines the Azure Dedicated Host placement of a virtual machine.
+// Exactly one of HostID and HostGroupID must be set.
+type DedicatedHost struct {
+	// HostID is the resource ID of the dedicated host to place the virtual machine on.
+	// +optional
+	HostID string `json:"hostID,omitempty"`
+
+	// HostGroupID is the resource ID of a dedicated host group with automatic placement enabled.
+	// Azure places the virtual machine on a host of the group that has capacity.
+	// +optional
+	HostGroupID string `json:"hostGroupID,omitempty"`
 }
 
 // SystemAssignedIdentityRole defines the role and scope to assign to the system assigned identity.
@@ -233,6 +360,17 @@ type AzureMachineStatus struct {
 	// next reconciliation loop.
 	// +optional
 	LongRunningOperationStates Futures `json:"longRunningOperationStates,omitempty"`
+
+	// RetainedResources are the IDs of the Azure resources left in place on deletion of the machine
+	// because their delete policy is Retain.
+	// +optional
+	RetainedResources []string `json:"retainedResources,omitempty"`
+
+	// FailureDomain is the availability zone selected for the virtual machine when neither the Machine nor the
+	// AzureMachine specify a failure domain. It is chosen among the zones of the cluster in which the VM size is
+	// available and not restricted for the subscription.
+	// +optional
+	FailureDomain string `json:"failureDomain,omitempty"`
 }
 
 // AdditionalCapabilities enables or disables a capability on the virtual machine.
@@ -242,6 +380,16 @@ type AdditionalCapabilities struct {
 	// otherwise it doesn't set the capability on the VM.
 	// +optional
 	UltraSSDEnabled *bool `json:"ultraSSDEnabled,omitempty"`
+
+	// HibernationEnabled enables or disables the hibernation capability of the virtual machine.
+	// +optional
+	HibernationEnabled *bool `json:"hibernationEnabled,omitempty"`
+
+	// DeallocateOnChange allows deallocating a running virtual machine to apply changes to its additional capabilities,
+	// which Azure only accepts on deallocated virtual machines. The virtual machine is started again once the changes are applied.
+	// If false, changes are applied the next time the virtual machine is found deallocated.
+	// +optional
+	DeallocateOnChange bool `json:"deallocateOnChange,omitempty"`
 }
 
 // +kubebuilder:object:root=true
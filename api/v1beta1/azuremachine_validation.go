@@ -19,14 +19,49 @@ package v1beta1
 import (
 	"encoding/base64"
 	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/ssh"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 )
 
+// validRunCommandName matches the names Azure allows for the run command resources of a virtual machine.
+var validRunCommandName = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_.-]{0,78}[a-zA-Z0-9_])?$`)
+
+// validVMExtensionVersion matches the major.minor type handler versions of VM extensions.
+var validVMExtensionVersion = regexp.MustCompile(`^[0-9]+\.[0-9]+$`)
+
+// validComputerNamePrefix matches the computer name prefixes that result in valid hostnames and node names.
+var validComputerNamePrefix = regexp.MustCompile(`^[a-z][-a-z0-9]*$`)
+
+// validAdminUsername matches the Linux user names accepted for the administrator account of a VM.
+var validAdminUsername = regexp.MustCompile(`^[a-z_][-a-z0-9_]*$`)
+
+// reservedAdminUsernames are the administrator user names Azure refuses for Linux VMs.
+var reservedAdminUsernames = []string{
+	"1", "123", "a", "actuser", "adm", "admin", "admin1", "admin2", "administrator", "aspnet", "backup", "console",
+	"david", "guest", "john", "owner", "root", "server", "sql", "support", "support_388945a0", "sys", "test", "test1",
+	"test2", "test3", "user", "user1", "user2", "user3", "user4", "user5",
+}
+
+const (
+	// maxWindowsComputerNameLength is the maximum length of the computer name of a Windows VM.
+	maxWindowsComputerNameLength = 15
+	// maxLinuxComputerNameLength is the maximum length of the computer name of a Linux VM.
+	maxLinuxComputerNameLength = 64
+	// maxAdminUsernameLength is the maximum length of the administrator user name of a Linux VM.
+	maxAdminUsernameLength = 32
+	// ScaleSetComputerNameSuffixLength is the number of characters Azure appends to the computer name prefix of scale
+	// set instances.
+	ScaleSetComputerNameSuffixLength = 6
+)
+
 // ValidateAzureMachineSpec checks an AzureMachineSpec and returns any validation errors.
 func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 	var allErrs field.ErrorList
@@ -35,11 +70,15 @@ func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateImageVariant(spec.Image, spec.OSDisk.OSType, field.NewPath("image", "variant")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	if errs := ValidateOSDisk(spec.OSDisk, field.NewPath("osDisk")); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
 
-	if errs := ValidateConfidentialCompute(spec.OSDisk.ManagedDisk, spec.SecurityProfile, field.NewPath("securityProfile")); len(errs) > 0 {
+	if errs := ValidateSecurityProfile(spec.OSDisk.ManagedDisk, spec.SecurityProfile, field.NewPath("securityProfile")); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
 
@@ -47,6 +86,10 @@ func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateAdditionalSSHKeys(spec.SSHPublicKey, spec.AdditionalSSHPublicKeys, field.NewPath("additionalSSHPublicKeys")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	if errs := ValidateUserAssignedIdentity(spec.Identity, spec.UserAssignedIdentities, field.NewPath("userAssignedIdentities")); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
@@ -59,17 +102,198 @@ func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateDiskSnapshot(spec.DiskSnapshot, field.NewPath("diskSnapshot")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	if errs := ValidateNetwork(spec.SubnetName, spec.AcceleratedNetworking, spec.NetworkInterfaces, field.NewPath("networkInterfaces")); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if spec.AllocatePublicIP && len(spec.NetworkInterfaces) > 0 && spec.NetworkInterfaces[0].ID != "" {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("allocatePublicIP"), "cannot allocate a public IP when the primary network interface already exists"))
+	}
+
 	if errs := ValidateSystemAssignedIdentityRole(spec.Identity, spec.RoleAssignmentName, spec.SystemAssignedIdentityRole, field.NewPath("systemAssignedIdentityRole")); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateVMSizeClassRef(spec.VMSize, spec.VMSizeClassRef, field.NewPath("vmSizeClassRef")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateDedicatedHost(spec.DedicatedHost, spec.SpotVMOptions, field.NewPath("dedicatedHost")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if spec.SpotVMOptions != nil && spec.SpotVMOptions.RestorePolicy != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spotVMOptions", "restorePolicy"), "restorePolicy is only supported by AzureMachinePools"))
+	}
+
+	if errs := ValidateTerminateNotificationTimeout(spec.TerminateNotificationTimeout, field.NewPath("terminateNotificationTimeout")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateVaultSecrets(spec.OSDisk.OSType, spec.VaultSecrets, field.NewPath("vaultSecrets")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateVMGalleryApplications(spec.VMGalleryApplications, field.NewPath("vmGalleryApplications")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateVMExtensions(spec.VMExtensions, field.NewPath("vmExtensions")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateRunCommands(spec.RunCommands, field.NewPath("runCommands")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateBootstrapEncryption(spec.OSDisk.OSType, spec.Identity, spec.BootstrapEncryption, field.NewPath("bootstrapEncryption")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateAdminUsername(spec.AdminUsername, spec.OSDisk.OSType, field.NewPath("adminUsername")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	return allErrs
+}
+
+// ValidateComputerNamePrefix validates the computer name prefix of a machine, to which suffixLength characters are
+// appended to form its computer name.
+func ValidateComputerNamePrefix(prefix, osType string, suffixLength int, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if prefix == "" {
+		return allErrs
+	}
+
+	if !validComputerNamePrefix.MatchString(prefix) {
+		allErrs = append(allErrs, field.Invalid(fldPath, prefix, "computerNamePrefix must start with a lowercase letter and only contain lowercase alphanumeric characters and hyphens"))
+	}
+
+	maxLength := maxLinuxComputerNameLength - suffixLength
+	if osType == WindowsOS {
+		maxLength = maxWindowsComputerNameLength - suffixLength
+	}
+	if len(prefix) > maxLength {
+		allErrs = append(allErrs, field.TooLong(fldPath, prefix, maxLength))
+	}
+
 	return allErrs
 }
 
+// ValidateAdminUsername validates the administrator user name of a machine.
+func ValidateAdminUsername(adminUsername, osType string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if adminUsername == "" {
+		return allErrs
+	}
+
+	if osType == WindowsOS {
+		return append(allErrs, field.Forbidden(fldPath, "adminUsername is only supported for Linux machines"))
+	}
+
+	if !validAdminUsername.MatchString(adminUsername) {
+		allErrs = append(allErrs, field.Invalid(fldPath, adminUsername, "adminUsername must start with a lowercase letter or an underscore and only contain lowercase alphanumeric characters, hyphens and underscores"))
+	}
+
+	if len(adminUsername) > maxAdminUsernameLength {
+		allErrs = append(allErrs, field.TooLong(fldPath, adminUsername, maxAdminUsernameLength))
+	}
+
+	for _, reserved := range reservedAdminUsernames {
+		if adminUsername == reserved {
+			allErrs = append(allErrs, field.Invalid(fldPath, adminUsername, "adminUsername is reserved by Azure"))
+			break
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateVMSizeClassRef validates the reference to an AzureVMSizeCatalog size class, which must be set if and only
+// if the VM size is not.
+func ValidateVMSizeClassRef(vmSize string, ref *VMSizeClassReference, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if ref == nil {
+		if vmSize == "" {
+			allErrs = append(allErrs, field.Required(fldPath, "one of vmSize and vmSizeClassRef is required"))
+		}
+		return allErrs
+	}
+
+	if vmSize != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "cannot set both vmSize and vmSizeClassRef"))
+	}
+	if ref.CatalogName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("catalogName"), "catalogName is required"))
+	}
+	if ref.SizeClass == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("sizeClass"), "sizeClass is required"))
+	}
+
+	return allErrs
+}
+
+// ValidateDedicatedHost validates the dedicated host placement of a machine.
+func ValidateDedicatedHost(dedicatedHost *DedicatedHost, spotVMOptions *SpotVMOptions, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if dedicatedHost == nil {
+		return allErrs
+	}
+
+	switch {
+	case dedicatedHost.HostID != "" && dedicatedHost.HostGroupID != "":
+		allErrs = append(allErrs, field.Forbidden(fldPath, "cannot set both hostID and hostGroupID"))
+	case dedicatedHost.HostID != "":
+		if !isComputeResourceID(dedicatedHost.HostID, "hostGroups/hosts") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("hostID"), dedicatedHost.HostID, "hostID must be the resource ID of a dedicated host"))
+		}
+	case dedicatedHost.HostGroupID != "":
+		if !isComputeResourceID(dedicatedHost.HostGroupID, "hostGroups") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("hostGroupID"), dedicatedHost.HostGroupID, "hostGroupID must be the resource ID of a dedicated host group"))
+		}
+	default:
+		allErrs = append(allErrs, field.Required(fldPath, "one of hostID or hostGroupID is required"))
+	}
+
+	if spotVMOptions != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "spot VMs cannot be placed on a dedicated host"))
+	}
+
+	return allErrs
+}
+
+// ValidateTerminateNotificationTimeout validates that the termination notification timeout is between 5 and 15 minutes.
+func ValidateTerminateNotificationTimeout(timeout *int, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if timeout == nil {
+		return allErrs
+	}
+
+	if *timeout < 5 || *timeout > 15 {
+		allErrs = append(allErrs, field.Invalid(fldPath, *timeout, "allowed values are between 5 and 15 minutes"))
+	}
+
+	return allErrs
+}
+
+// isComputeResourceID returns true if id is the resource ID of a Microsoft.Compute resource of the given type.
+func isComputeResourceID(id, resourceType string) bool {
+	parsed, err := azureutil.ParseResourceID(id)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(parsed.ResourceType.Namespace, "Microsoft.Compute") && strings.EqualFold(parsed.ResourceType.Type, resourceType)
+}
+
 // ValidateNetwork validates the network configuration.
 func ValidateNetwork(subnetName string, acceleratedNetworking *bool, networkInterfaces []NetworkInterface, fldPath *field.Path) field.ErrorList {
 	if (networkInterfaces != nil) && len(networkInterfaces) > 0 && subnetName != "" {
@@ -80,7 +304,16 @@ func ValidateNetwork(subnetName string, acceleratedNetworking *bool, networkInte
 		return field.ErrorList{field.Invalid(fldPath, networkInterfaces, "cannot set both networkInterfaces and machine acceleratedNetworking")}
 	}
 
-	for _, nic := range networkInterfaces {
+	for i, nic := range networkInterfaces {
+		if nic.PublicIPPerInstance || nic.PublicIPPrefixID != "" {
+			return field.ErrorList{field.Forbidden(fldPath.Index(i).Child("publicIPPerInstance"), "public IPs per instance are only supported for machine pools")}
+		}
+		if nic.ID != "" {
+			if errs := validateExistingNetworkInterface(nic, fldPath.Index(i)); len(errs) > 0 {
+				return errs
+			}
+			continue
+		}
 		if nic.PrivateIPConfigs < 1 {
 			return field.ErrorList{field.Invalid(fldPath, networkInterfaces, "number of privateIPConfigs per interface must be at least 1")}
 		}
@@ -89,6 +322,26 @@ func ValidateNetwork(subnetName string, acceleratedNetworking *bool, networkInte
 	return field.ErrorList{}
 }
 
+// validateExistingNetworkInterface validates a network interface that references an existing network interface by ID.
+func validateExistingNetworkInterface(nic NetworkInterface, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if _, err := azureutil.ParseResourceID(nic.ID); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("id"), nic.ID, "id must be the resource ID of a network interface"))
+	}
+	if nic.SubnetName != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("subnetName"), "cannot set subnetName on an existing network interface"))
+	}
+	if nic.PrivateIPConfigs != 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("privateIPConfigs"), "cannot set privateIPConfigs on an existing network interface"))
+	}
+	if nic.AcceleratedNetworking != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("acceleratedNetworking"), "cannot set acceleratedNetworking on an existing network interface"))
+	}
+
+	return allErrs
+}
+
 // ValidateSSHKey validates an SSHKey.
 func ValidateSSHKey(sshKey string, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -107,6 +360,25 @@ func ValidateSSHKey(sshKey string, fldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+// ValidateAdditionalSSHKeys validates the SSH keys authorized in addition to sshKey.
+func ValidateAdditionalSSHKeys(sshKey string, additionalSSHKeys []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	seen := map[string]struct{}{sshKey: {}}
+	for i, key := range additionalSSHKeys {
+		if errs := ValidateSSHKey(key, fldPath.Index(i)); len(errs) > 0 {
+			allErrs = append(allErrs, errs...)
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), key))
+		}
+		seen[key] = struct{}{}
+	}
+
+	return allErrs
+}
+
 // ValidateSystemAssignedIdentity validates the system-assigned identities list.
 func ValidateSystemAssignedIdentity(identityType VMIdentity, oldIdentity, newIdentity string, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -171,8 +443,11 @@ func ValidateDataDisks(dataDisks []DataDisk, fieldPath *field.Path) field.ErrorL
 	lunSet := make(map[int32]struct{})
 	nameSet := make(map[string]struct{})
 	for _, disk := range dataDisks {
-		// validate that the disk size is between 4 and 32767.
-		if disk.DiskSizeGB < 4 || disk.DiskSizeGB > 32767 {
+		if disk.IsExisting() {
+			// validate that existing disks are attached as they are.
+			allErrs = append(allErrs, validateExistingDataDisk(disk, fieldPath)...)
+		} else if disk.DiskSizeGB < 4 || disk.DiskSizeGB > 32767 {
+			// validate that the disk size is between 4 and 32767.
 			allErrs = append(allErrs, field.Invalid(fieldPath.Child("DiskSizeGB"), "", "the disk size should be a value between 4 and 32767"))
 		}
 
@@ -206,7 +481,64 @@ func ValidateDataDisks(dataDisks []DataDisk, fieldPath *field.Path) field.ErrorL
 
 		// validate cachingType
 		allErrs = append(allErrs, validateCachingType(disk.CachingType, fieldPath, disk.ManagedDisk)...)
+
+		// validate that shared disks use a storage account type and caching type that support sharing
+		allErrs = append(allErrs, validateSharedDisk(disk, fieldPath)...)
+	}
+	return allErrs
+}
+
+// validateExistingDataDisk validates a data disk that attaches an existing managed disk.
+func validateExistingDataDisk(disk DataDisk, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !isComputeResourceID(disk.ManagedDiskID, "disks") {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("managedDiskID"), disk.ManagedDiskID, "managedDiskID must be the resource ID of a managed disk"))
+	}
+	if disk.DiskSizeGB != 0 {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("diskSizeGB"), "diskSizeGB cannot be set when managedDiskID is set, the size of the existing disk is used"))
 	}
+	if disk.ManagedDisk != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("managedDisk"), "managedDisk cannot be set when managedDiskID is set, the parameters of the existing disk are used"))
+	}
+
+	return allErrs
+}
+
+// sharedDiskStorageAccountTypes are the storage account types of the managed disks that can be shared between machines.
+var sharedDiskStorageAccountTypes = []string{
+	string(compute.StorageAccountTypesPremiumLRS),
+	string(compute.StorageAccountTypesPremiumZRS),
+	string(compute.StorageAccountTypesStandardSSDLRS),
+	string(compute.StorageAccountTypesStandardSSDZRS),
+	string(compute.StorageAccountTypesUltraSSDLRS),
+}
+
+// validateSharedDisk validates that a data disk whose maxShares is greater than 1 can be shared between machines.
+// See https://learn.microsoft.com/azure/virtual-machines/disks-shared#limitations
+func validateSharedDisk(disk DataDisk, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !disk.IsShared() {
+		return allErrs
+	}
+
+	supported := false
+	for _, storageAccountType := range sharedDiskStorageAccountTypes {
+		if disk.ManagedDisk.StorageAccountType == storageAccountType {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("managedDisk").Child("storageAccountType"), disk.ManagedDisk.StorageAccountType,
+			fmt.Sprintf("storageAccountType must be one of %v when maxShares is greater than 1", sharedDiskStorageAccountTypes)))
+	}
+
+	if disk.CachingType != string(compute.CachingTypesNone) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("cachingType"), disk.CachingType,
+			fmt.Sprintf("cachingType must be '%s' when maxShares is greater than 1", compute.CachingTypesNone)))
+	}
+
 	return allErrs
 }
 
@@ -250,6 +582,12 @@ func validateManagedDisk(m *ManagedDiskParameters, fieldPath *field.Path, isOSDi
 	if m != nil {
 		allErrs = append(allErrs, validateStorageAccountType(m.StorageAccountType, fieldPath.Child("StorageAccountType"), isOSDisk)...)
 
+		if isOSDisk && m.MaxShares != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxShares"), *m.MaxShares, "maxShares can only be set on data disks"))
+		}
+
+		allErrs = append(allErrs, validateProvisionedPerformance(m, fieldPath, isOSDisk)...)
+
 		// DiskEncryptionSet can only be set when SecurityEncryptionType is set to DiskWithVMGuestState
 		// https://learn.microsoft.com/en-us/rest/api/compute/virtual-machines/create-or-update?tabs=HTTP#securityencryptiontypes
 		if isOSDisk && m.SecurityProfile != nil && m.SecurityProfile.DiskEncryptionSet != nil {
@@ -266,12 +604,83 @@ func validateManagedDisk(m *ManagedDiskParameters, fieldPath *field.Path, isOSDi
 	return allErrs
 }
 
+// validateProvisionedPerformance validates that the IOPS and the throughput of a managed disk are only set on
+// UltraSSD_LRS data disks, whose performance can be provisioned independently of their size.
+func validateProvisionedPerformance(m *ManagedDiskParameters, fieldPath *field.Path, isOSDisk bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	fields := []struct {
+		name  string
+		value *int64
+	}{
+		{name: "diskIOPSReadWrite", value: m.DiskIOPSReadWrite},
+		{name: "diskMBpsReadWrite", value: m.DiskMBpsReadWrite},
+	}
+	for _, f := range fields {
+		if f.value == nil {
+			continue
+		}
+		if isOSDisk {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child(f.name), fmt.Sprintf("%s can only be set on data disks", f.name)))
+		} else if m.StorageAccountType != string(compute.StorageAccountTypesUltraSSDLRS) {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child(f.name), fmt.Sprintf("%s can only be set when storageAccountType is %s", f.name, compute.StorageAccountTypesUltraSSDLRS)))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateOSDiskSizeUpdate validates a change to the size of the OS disk of an existing machine.
+// Managed OS disks can only be grown, up to the maximum OS disk size.
+func ValidateOSDiskSizeUpdate(oldOSDisk, newOSDisk OSDisk, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if newOSDisk.DiffDiskSettings != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "ephemeral OS disks cannot be resized"))
+		return allErrs
+	}
+
+	if newOSDisk.DiskSizeGB == nil {
+		allErrs = append(allErrs, field.Required(fieldPath, "the OS disk size cannot be unset after machine creation"))
+		return allErrs
+	}
+
+	if oldOSDisk.DiskSizeGB != nil && *newOSDisk.DiskSizeGB < *oldOSDisk.DiskSizeGB {
+		allErrs = append(allErrs, field.Invalid(fieldPath, *newOSDisk.DiskSizeGB, fmt.Sprintf("the OS disk can only be grown, its current size is %d GB", *oldOSDisk.DiskSizeGB)))
+	}
+
+	if *newOSDisk.DiskSizeGB > 2048 {
+		allErrs = append(allErrs, field.Invalid(fieldPath, *newOSDisk.DiskSizeGB, "the Disk size should be a value between 1 and 2048"))
+	}
+
+	return allErrs
+}
+
+// osDiskWithoutDeletePolicy returns a copy of osDisk without its delete policy, which can be changed after machine creation.
+func osDiskWithoutDeletePolicy(osDisk OSDisk) OSDisk {
+	osDisk.DeletePolicy = ""
+	return osDisk
+}
+
+// dataDisksWithoutDeletePolicy returns a copy of dataDisks without their delete policies, which can be changed after
+// machine creation.
+func dataDisksWithoutDeletePolicy(dataDisks []DataDisk) []DataDisk {
+	if dataDisks == nil {
+		return nil
+	}
+	disks := make([]DataDisk, len(dataDisks))
+	for i, disk := range dataDisks {
+		disk.DeletePolicy = ""
+		disks[i] = disk
+	}
+	return disks
+}
+
 // ValidateDataDisksUpdate validates updates to Data disks.
 func ValidateDataDisksUpdate(oldDataDisks, newDataDisks []DataDisk, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	diskErrMsg := "adding/removing data disks after machine creation is not allowed"
-	fieldErrMsg := "modifying data disk's fields after machine creation is not allowed"
 
 	if len(oldDataDisks) != len(newDataDisks) {
 		allErrs = append(allErrs, field.Invalid(fieldPath, newDataDisks, diskErrMsg))
@@ -286,29 +695,77 @@ func ValidateDataDisksUpdate(oldDataDisks, newDataDisks []DataDisk, fieldPath *f
 
 	for i, newDisk := range newDataDisks {
 		if oldDisk, ok := oldDisks[newDisk.NameSuffix]; ok {
-			if newDisk.DiskSizeGB != oldDisk.DiskSizeGB {
-				allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("diskSizeGB"), newDataDisks, fieldErrMsg))
-			}
+			allErrs = append(allErrs, validateDataDiskUpdate(oldDisk, newDisk, newDataDisks, fieldPath.Index(i))...)
+		} else {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("nameSuffix"), newDataDisks, diskErrMsg))
+		}
+	}
 
-			allErrs = append(allErrs, validateManagedDisksUpdate(oldDisk.ManagedDisk, newDisk.ManagedDisk, fieldPath.Index(i).Child("managedDisk"))...)
+	return allErrs
+}
 
-			if (newDisk.Lun != nil && oldDisk.Lun != nil) && (*newDisk.Lun != *oldDisk.Lun) {
-				allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("lun"), newDataDisks, fieldErrMsg))
-			} else if (newDisk.Lun != nil && oldDisk.Lun == nil) || (newDisk.Lun == nil && oldDisk.Lun != nil) {
-				allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("lun"), newDataDisks, fieldErrMsg))
-			}
+// ValidateDataDisksAttachUpdate validates updates to the data disks of a machine that opted in to attaching and
+// detaching data disks in place. Data disks can be added and removed, but the existing ones can't be modified.
+// A removed disk is deleted if it was created for the machine, and only detached otherwise, so its delete policy
+// must match.
+func ValidateDataDisksAttachUpdate(oldDataDisks, newDataDisks []DataDisk, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
 
-			if newDisk.CachingType != oldDisk.CachingType {
-				allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("cachingType"), newDataDisks, fieldErrMsg))
-			}
-		} else {
-			allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("nameSuffix"), newDataDisks, diskErrMsg))
+	oldDisks := make(map[string]DataDisk)
+	for _, disk := range oldDataDisks {
+		oldDisks[disk.NameSuffix] = disk
+	}
+	newDisks := make(map[string]struct{})
+	for i, newDisk := range newDataDisks {
+		newDisks[newDisk.NameSuffix] = struct{}{}
+		if oldDisk, ok := oldDisks[newDisk.NameSuffix]; ok {
+			allErrs = append(allErrs, validateDataDiskUpdate(oldDisk, newDisk, newDataDisks, fieldPath.Index(i))...)
+		}
+	}
+
+	for _, oldDisk := range oldDataDisks {
+		if _, ok := newDisks[oldDisk.NameSuffix]; ok || oldDisk.IsShared() {
+			continue
+		}
+		if !oldDisk.IsExisting() && oldDisk.IsRetained() {
+			allErrs = append(allErrs, field.Forbidden(fieldPath, fmt.Sprintf("data disk %s can't be removed because its delete policy is %s, removed disks created for the machine are deleted", oldDisk.NameSuffix, DeletePolicyRetain)))
+		}
+		if oldDisk.IsExisting() && !oldDisk.IsRetained() {
+			allErrs = append(allErrs, field.Forbidden(fieldPath, fmt.Sprintf("data disk %s can't be removed because its delete policy is %s, removed existing disks are only detached", oldDisk.NameSuffix, DeletePolicyDelete)))
 		}
 	}
 
 	return allErrs
 }
 
+// validateDataDiskUpdate validates that a data disk of a machine wasn't modified.
+func validateDataDiskUpdate(oldDisk, newDisk DataDisk, newDataDisks []DataDisk, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	fieldErrMsg := "modifying data disk's fields after machine creation is not allowed"
+
+	if newDisk.DiskSizeGB != oldDisk.DiskSizeGB {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("diskSizeGB"), newDataDisks, fieldErrMsg))
+	}
+
+	if newDisk.ManagedDiskID != oldDisk.ManagedDiskID {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("managedDiskID"), newDataDisks, fieldErrMsg))
+	}
+
+	allErrs = append(allErrs, validateManagedDisksUpdate(oldDisk.ManagedDisk, newDisk.ManagedDisk, fieldPath.Child("managedDisk"))...)
+
+	if (newDisk.Lun != nil && oldDisk.Lun != nil) && (*newDisk.Lun != *oldDisk.Lun) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("lun"), newDataDisks, fieldErrMsg))
+	} else if (newDisk.Lun != nil && oldDisk.Lun == nil) || (newDisk.Lun == nil && oldDisk.Lun != nil) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("lun"), newDataDisks, fieldErrMsg))
+	}
+
+	if newDisk.CachingType != oldDisk.CachingType {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("cachingType"), newDataDisks, fieldErrMsg))
+	}
+
+	return allErrs
+}
+
 func validateManagedDisksUpdate(oldDiskParams, newDiskParams *ManagedDiskParameters, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	fieldErrMsg := "changing managed disk options after machine creation is not allowed"
@@ -317,6 +774,15 @@ func validateManagedDisksUpdate(oldDiskParams, newDiskParams *ManagedDiskParamet
 		if newDiskParams.StorageAccountType != oldDiskParams.StorageAccountType {
 			allErrs = append(allErrs, field.Invalid(fieldPath.Child("storageAccountType"), newDiskParams, fieldErrMsg))
 		}
+		if !ptr.Equal(newDiskParams.MaxShares, oldDiskParams.MaxShares) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxShares"), newDiskParams, fieldErrMsg))
+		}
+		if !ptr.Equal(newDiskParams.DiskIOPSReadWrite, oldDiskParams.DiskIOPSReadWrite) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("diskIOPSReadWrite"), newDiskParams, fieldErrMsg))
+		}
+		if !ptr.Equal(newDiskParams.DiskMBpsReadWrite, oldDiskParams.DiskMBpsReadWrite) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("diskMBpsReadWrite"), newDiskParams, fieldErrMsg))
+		}
 		if newDiskParams.DiskEncryptionSet != nil && oldDiskParams.DiskEncryptionSet != nil {
 			if newDiskParams.DiskEncryptionSet.ID != oldDiskParams.DiskEncryptionSet.ID {
 				allErrs = append(allErrs, field.Invalid(fieldPath.Child("diskEncryptionSet").Child("ID"), newDiskParams, fieldErrMsg))
@@ -406,6 +872,230 @@ func ValidateDiagnostics(diagnostics *Diagnostics, fieldPath *field.Path) field.
 	return allErrs
 }
 
+// ValidateDiskSnapshot validates the DiskSnapshot spec.
+func ValidateDiskSnapshot(diskSnapshot *DiskSnapshot, fieldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if diskSnapshot == nil {
+		return allErrs
+	}
+
+	if diskSnapshot.ResourceGroup == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("resourceGroup"), "resourceGroup is required"))
+	} else if err := validateResourceGroup(diskSnapshot.ResourceGroup, fieldPath.Child("resourceGroup")); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if diskSnapshot.RetentionPeriod != nil && diskSnapshot.RetentionPeriod.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("retentionPeriod"), diskSnapshot.RetentionPeriod.Duration.String(),
+			"retentionPeriod must be greater than 0"))
+	}
+
+	return allErrs
+}
+
+// ValidateVaultSecrets validates the Key Vault certificates to install on a virtual machine.
+func ValidateVaultSecrets(osType string, vaultSecrets []VaultSecretGroup, fieldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, group := range vaultSecrets {
+		groupPath := fieldPath.Index(i)
+		if group.SourceVault == "" {
+			allErrs = append(allErrs, field.Required(groupPath.Child("sourceVault"), "sourceVault is required"))
+		} else if _, err := azureutil.ParseResourceID(group.SourceVault); err != nil {
+			allErrs = append(allErrs, field.Invalid(groupPath.Child("sourceVault"), group.SourceVault, "sourceVault must be the resource ID of a Key Vault"))
+		}
+
+		if len(group.VaultCertificates) == 0 {
+			allErrs = append(allErrs, field.Required(groupPath.Child("vaultCertificates"), "at least one certificate is required"))
+		}
+
+		for j, cert := range group.VaultCertificates {
+			certPath := groupPath.Child("vaultCertificates").Index(j)
+			if cert.CertificateURL == "" {
+				allErrs = append(allErrs, field.Required(certPath.Child("certificateURL"), "certificateURL is required"))
+			} else if u, err := url.Parse(cert.CertificateURL); err != nil || u.Scheme != "https" || u.Host == "" {
+				allErrs = append(allErrs, field.Invalid(certPath.Child("certificateURL"), cert.CertificateURL, "certificateURL must be an https URL"))
+			}
+
+			switch {
+			case osType == WindowsOS && cert.CertificateStore == "":
+				allErrs = append(allErrs, field.Required(certPath.Child("certificateStore"), "certificateStore is required for Windows machines"))
+			case osType != WindowsOS && cert.CertificateStore != "":
+				allErrs = append(allErrs, field.Forbidden(certPath.Child("certificateStore"), "certificateStore can only be set for Windows machines"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateBootstrapEncryption validates the envelope encryption of the bootstrap data of a virtual machine.
+func ValidateBootstrapEncryption(osType string, identity VMIdentity, encryption *BootstrapEncryption, fieldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if encryption == nil {
+		return allErrs
+	}
+
+	if osType == WindowsOS {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "bootstrap encryption is not supported for Windows machines"))
+	}
+
+	if identity == "" || identity == VMIdentityNone {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "bootstrap encryption requires the machine to have a managed identity to unwrap the data encryption key"))
+	}
+
+	if encryption.KeyID == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("keyID"), "keyID is required"))
+	} else if u, err := url.Parse(encryption.KeyID); err != nil || u.Scheme != "https" || u.Host == "" || len(strings.Split(strings.Trim(u.Path, "/"), "/")) != 3 || !strings.HasPrefix(u.Path, "/keys/") {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("keyID"), encryption.KeyID, "keyID must be the versioned URL of a Key Vault key, such as https://myvault.vault.azure.net/keys/mykey/0123456789abcdef0123456789abcdef"))
+	}
+
+	return allErrs
+}
+
+// ValidateVMGalleryApplications validates the gallery applications to install on a virtual machine.
+func ValidateVMGalleryApplications(apps []VMGalleryApplication, fieldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	versions := make(map[string]bool, len(apps))
+	for i, app := range apps {
+		appPath := fieldPath.Index(i)
+		switch {
+		case app.Version == "":
+			allErrs = append(allErrs, field.Required(appPath.Child("version"), "version is required"))
+		case !isComputeResourceID(app.Version, "galleries/applications/versions"):
+			allErrs = append(allErrs, field.Invalid(appPath.Child("version"), app.Version, "version must be the resource ID of a gallery application version"))
+		case versions[strings.ToLower(app.Version)]:
+			allErrs = append(allErrs, field.Duplicate(appPath.Child("version"), app.Version))
+		default:
+			versions[strings.ToLower(app.Version)] = true
+		}
+
+		if app.Order != nil && *app.Order < 0 {
+			allErrs = append(allErrs, field.Invalid(appPath.Child("order"), *app.Order, "order must be greater than or equal to 0"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateVMExtensions validates the custom VM extensions of a virtual machine.
+// Whether the publisher offers the extension in the version is checked by ValidateVMExtensionVersions.
+func ValidateVMExtensions(extensions []VMExtension, fieldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := make(map[string]bool, len(extensions))
+	for i, extension := range extensions {
+		extensionPath := fieldPath.Index(i)
+		switch {
+		case extension.Name == "":
+			allErrs = append(allErrs, field.Required(extensionPath.Child("name"), "name is required"))
+		case names[strings.ToLower(extension.Name)]:
+			allErrs = append(allErrs, field.Duplicate(extensionPath.Child("name"), extension.Name))
+		default:
+			names[strings.ToLower(extension.Name)] = true
+		}
+
+		if extension.Publisher == "" {
+			allErrs = append(allErrs, field.Required(extensionPath.Child("publisher"), "publisher is required"))
+		}
+
+		if !validVMExtensionVersion.MatchString(extension.Version) {
+			allErrs = append(allErrs, field.Invalid(extensionPath.Child("version"), extension.Version, "version must be a major.minor type handler version, such as 2.1"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateRunCommands validates the run commands of a virtual machine.
+func ValidateRunCommands(runCommands []RunCommand, fieldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := make(map[string]bool, len(runCommands))
+	for i, runCommand := range runCommands {
+		runCommandPath := fieldPath.Index(i)
+		switch {
+		case runCommand.Name == "":
+			allErrs = append(allErrs, field.Required(runCommandPath.Child("name"), "name is required"))
+		case !validRunCommandName.MatchString(runCommand.Name):
+			allErrs = append(allErrs, field.Invalid(runCommandPath.Child("name"), runCommand.Name, fmt.Sprintf("name must match %q", validRunCommandName.String())))
+		case names[strings.ToLower(runCommand.Name)]:
+			allErrs = append(allErrs, field.Duplicate(runCommandPath.Child("name"), runCommand.Name))
+		default:
+			names[strings.ToLower(runCommand.Name)] = true
+		}
+
+		sources := 0
+		for _, source := range []string{runCommand.Script, runCommand.ScriptURI, runCommand.CommandID} {
+			if source != "" {
+				sources++
+			}
+		}
+		if sources != 1 {
+			allErrs = append(allErrs, field.Invalid(runCommandPath, runCommand.Name, "exactly one of script, scriptURI and commandID must be set"))
+		}
+
+		if runCommand.ScriptURI != "" {
+			if u, err := url.Parse(runCommand.ScriptURI); err != nil || u.Scheme != "https" || u.Host == "" {
+				allErrs = append(allErrs, field.Invalid(runCommandPath.Child("scriptURI"), runCommand.ScriptURI, "scriptURI must be an https URL"))
+			}
+		}
+
+		for j, parameter := range runCommand.Parameters {
+			if parameter.Name == "" {
+				allErrs = append(allErrs, field.Required(runCommandPath.Child("parameters").Index(j).Child("name"), "name is required"))
+			}
+		}
+
+		if runCommand.TimeoutSeconds != nil && *runCommand.TimeoutSeconds < 1 {
+			allErrs = append(allErrs, field.Invalid(runCommandPath.Child("timeoutSeconds"), *runCommand.TimeoutSeconds, "timeoutSeconds must be greater than 0"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateSecurityProfile validates the security profile of a machine against the combinations of security type, OS
+// disk encryption, host encryption, secure boot and vTPM supported by Azure.
+func ValidateSecurityProfile(managedDisk *ManagedDiskParameters, profile *SecurityProfile, fieldPath *field.Path) field.ErrorList {
+	var securityEncryptionType SecurityEncryptionType
+	if managedDisk != nil && managedDisk.SecurityProfile != nil {
+		securityEncryptionType = managedDisk.SecurityProfile.SecurityEncryptionType
+	}
+
+	if profile == nil {
+		if securityEncryptionType != "" {
+			return field.ErrorList{field.Required(fieldPath,
+				fmt.Sprintf("securityProfile should be set with SecurityType '%s' when securityEncryptionType is defined", SecurityTypesConfidentialVM))}
+		}
+		return nil
+	}
+
+	allErrs := ValidateConfidentialCompute(managedDisk, profile, fieldPath)
+
+	switch {
+	case profile.SecurityType == SecurityTypesConfidentialVM && securityEncryptionType == "":
+		// Confidential VMs always encrypt at least the VM guest state of the OS disk.
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("SecurityType"), profile.SecurityType,
+			fmt.Sprintf("securityEncryptionType of the OS disk should be set when SecurityType is '%s'", SecurityTypesConfidentialVM)))
+	case profile.SecurityType == "" && securityEncryptionType == "" && profile.UefiSettings != nil:
+		// Secure boot and vTPM are only available to Trusted Launch and Confidential VMs.
+		if ptr.Deref(profile.UefiSettings.SecureBootEnabled, false) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("SecureBootEnabled"), profile.UefiSettings.SecureBootEnabled,
+				fmt.Sprintf("SecureBootEnabled can only be set to true when SecurityType is '%s' or '%s'", SecurityTypesTrustedLaunch, SecurityTypesConfidentialVM)))
+		}
+		if ptr.Deref(profile.UefiSettings.VTpmEnabled, false) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("VTpmEnabled"), profile.UefiSettings.VTpmEnabled,
+				fmt.Sprintf("VTpmEnabled can only be set to true when SecurityType is '%s' or '%s'", SecurityTypesTrustedLaunch, SecurityTypesConfidentialVM)))
+		}
+	}
+
+	return allErrs
+}
+
 // ValidateConfidentialCompute validates the configuration options when the machine is a Confidential VM.
 // https://learn.microsoft.com/en-us/rest/api/compute/virtual-machines/create-or-update?tabs=HTTP#vmdisksecurityprofile
 // https://learn.microsoft.com/en-us/rest/api/compute/virtual-machines/create-or-update?tabs=HTTP#securityencryptiontypes
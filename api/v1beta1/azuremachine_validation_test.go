@@ -21,12 +21,15 @@ import (
 	"crypto/rsa"
 	"encoding/base64"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
 	"github.com/google/uuid"
 	. "github.com/onsi/gomega"
 	"golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 )
@@ -68,6 +71,61 @@ func TestAzureMachine_ValidateSSHKey(t *testing.T) {
 	}
 }
 
+func TestAzureMachine_ValidateAdditionalSSHKeys(t *testing.T) {
+	g := NewWithT(t)
+
+	sshKey := generateSSHPublicKey(true)
+	teamKey := generateSSHPublicKey(true)
+
+	tests := []struct {
+		name              string
+		additionalSSHKeys []string
+		wantErr           bool
+	}{
+		{
+			name:              "no additional ssh keys",
+			additionalSSHKeys: nil,
+			wantErr:           false,
+		},
+		{
+			name:              "valid additional ssh keys",
+			additionalSSHKeys: []string{teamKey, generateSSHPublicKey(true)},
+			wantErr:           false,
+		},
+		{
+			name:              "invalid additional ssh key",
+			additionalSSHKeys: []string{teamKey, "invalid ssh key"},
+			wantErr:           true,
+		},
+		{
+			name:              "additional ssh key not base64 encoded",
+			additionalSSHKeys: []string{generateSSHPublicKey(false)},
+			wantErr:           true,
+		},
+		{
+			name:              "duplicate additional ssh keys",
+			additionalSSHKeys: []string{teamKey, teamKey},
+			wantErr:           true,
+		},
+		{
+			name:              "additional ssh key duplicating the ssh public key",
+			additionalSSHKeys: []string{sshKey},
+			wantErr:           true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateAdditionalSSHKeys(sshKey, tc.additionalSSHKeys, field.NewPath("additionalSSHPublicKeys"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
 func generateSSHPublicKey(b64Enconded bool) string {
 	privateKey, _ := rsa.GenerateKey(rand.Reader, 2048)
 	publicRsaKey, _ := ssh.NewPublicKey(&privateKey.PublicKey)
@@ -97,6 +155,32 @@ func TestAzureMachine_ValidateOSDisk(t *testing.T) {
 			wantErr: true,
 			osDisk:  createOSDiskWithCacheType("invalid_cache_type"),
 		},
+		{
+			name:    "invalid os disk with maxShares",
+			wantErr: true,
+			osDisk: OSDisk{
+				DiskSizeGB:  ptr.To[int32](30),
+				CachingType: "None",
+				OSType:      LinuxOS,
+				ManagedDisk: &ManagedDiskParameters{
+					StorageAccountType: "Premium_LRS",
+					MaxShares:          ptr.To[int32](2),
+				},
+			},
+		},
+		{
+			name:    "invalid os disk with diskIOPSReadWrite",
+			wantErr: true,
+			osDisk: OSDisk{
+				DiskSizeGB:  ptr.To[int32](30),
+				CachingType: "None",
+				OSType:      LinuxOS,
+				ManagedDisk: &ManagedDiskParameters{
+					StorageAccountType: "Premium_LRS",
+					DiskIOPSReadWrite:  ptr.To[int64](5000),
+				},
+			},
+		},
 		{
 			name:    "valid ephemeral os disk spec",
 			wantErr: false,
@@ -430,12 +514,917 @@ func TestAzureMachine_ValidateDataDisks(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid shared disk",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 256,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: string(compute.StorageAccountTypesPremiumZRS),
+						MaxShares:          ptr.To[int32](2),
+					},
+					Lun:         ptr.To[int32](0),
+					CachingType: string(compute.CachingTypesNone),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid disk with maxShares 1 and storage account type Standard_LRS",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: string(compute.StorageAccountTypesStandardLRS),
+						MaxShares:          ptr.To[int32](1),
+					},
+					Lun:         ptr.To[int32](0),
+					CachingType: string(compute.CachingTypesReadWrite),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid shared disk with storage account type Standard_LRS",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 256,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: string(compute.StorageAccountTypesStandardLRS),
+						MaxShares:          ptr.To[int32](2),
+					},
+					Lun:         ptr.To[int32](0),
+					CachingType: string(compute.CachingTypesNone),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid shared disk with cachingType ReadOnly",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 256,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: string(compute.StorageAccountTypesPremiumLRS),
+						MaxShares:          ptr.To[int32](2),
+					},
+					Lun:         ptr.To[int32](0),
+					CachingType: string(compute.CachingTypesReadOnly),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid ultra disk with provisioned IOPS and throughput",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 256,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: string(compute.StorageAccountTypesUltraSSDLRS),
+						DiskIOPSReadWrite:  ptr.To[int64](20000),
+						DiskMBpsReadWrite:  ptr.To[int64](500),
+					},
+					Lun:         ptr.To[int32](0),
+					CachingType: string(compute.CachingTypesNone),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid disk with provisioned IOPS and storage account type Premium_LRS",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 256,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: string(compute.StorageAccountTypesPremiumLRS),
+						DiskIOPSReadWrite:  ptr.To[int64](20000),
+					},
+					Lun:         ptr.To[int32](0),
+					CachingType: string(compute.CachingTypesNone),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid disk with provisioned throughput and storage account type StandardSSD_LRS",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 256,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: string(compute.StorageAccountTypesStandardSSDLRS),
+						DiskMBpsReadWrite:  ptr.To[int64](500),
+					},
+					Lun:         ptr.To[int32](0),
+					CachingType: string(compute.CachingTypesNone),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid existing disk",
+			disks: []DataDisk{
+				{
+					NameSuffix:    "my_disk_1",
+					ManagedDiskID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/restored-disk",
+					Lun:           ptr.To[int32](0),
+					CachingType:   string(compute.CachingTypesReadWrite),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid existing disk with a disk size",
+			disks: []DataDisk{
+				{
+					NameSuffix:    "my_disk_1",
+					DiskSizeGB:    256,
+					ManagedDiskID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/restored-disk",
+					Lun:           ptr.To[int32](0),
+					CachingType:   string(compute.CachingTypesReadWrite),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid existing disk with managed disk parameters",
+			disks: []DataDisk{
+				{
+					NameSuffix:    "my_disk_1",
+					ManagedDiskID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/restored-disk",
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: string(compute.StorageAccountTypesPremiumLRS),
+					},
+					Lun:         ptr.To[int32](0),
+					CachingType: string(compute.CachingTypesReadWrite),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid existing disk with the ID of a snapshot",
+			disks: []DataDisk{
+				{
+					NameSuffix:    "my_disk_1",
+					ManagedDiskID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/snapshots/my-snapshot",
+					Lun:           ptr.To[int32](0),
+					CachingType:   string(compute.CachingTypesReadWrite),
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateDataDisks(test.disks, field.NewPath("dataDisks"))
+			if test.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateDiskSnapshot(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name         string
+		diskSnapshot *DiskSnapshot
+		wantErr      bool
+	}{
+		{
+			name:         "nil",
+			diskSnapshot: nil,
+			wantErr:      false,
+		},
+		{
+			name:         "missing resource group",
+			diskSnapshot: &DiskSnapshot{},
+			wantErr:      true,
+		},
+		{
+			name: "resource group only",
+			diskSnapshot: &DiskSnapshot{
+				ResourceGroup: "my-snapshots",
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid resource group and retention period",
+			diskSnapshot: &DiskSnapshot{
+				ResourceGroup:   "my-snapshots",
+				RetentionPeriod: &metav1.Duration{Duration: 72 * time.Hour},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid resource group",
+			diskSnapshot: &DiskSnapshot{
+				ResourceGroup: "my snapshots?",
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero retention period",
+			diskSnapshot: &DiskSnapshot{
+				ResourceGroup:   "my-snapshots",
+				RetentionPeriod: &metav1.Duration{},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateDiskSnapshot(tc.diskSnapshot, field.NewPath("diskSnapshot"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateVMSizeClassRef(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		vmSize  string
+		ref     *VMSizeClassReference
+		wantErr bool
+	}{
+		{
+			name:    "only vmSize",
+			vmSize:  "Standard_D4s_v3",
+			wantErr: false,
+		},
+		{
+			name:    "only size class reference",
+			ref:     &VMSizeClassReference{CatalogName: "default", SizeClass: "general-4cpu-16gb"},
+			wantErr: false,
+		},
+		{
+			name:    "neither vmSize nor size class reference",
+			wantErr: true,
+		},
+		{
+			name:    "vmSize and size class reference",
+			vmSize:  "Standard_D4s_v3",
+			ref:     &VMSizeClassReference{CatalogName: "default", SizeClass: "general-4cpu-16gb"},
+			wantErr: true,
+		},
+		{
+			name:    "missing catalog name",
+			ref:     &VMSizeClassReference{SizeClass: "general-4cpu-16gb"},
+			wantErr: true,
+		},
+		{
+			name:    "missing size class",
+			ref:     &VMSizeClassReference{CatalogName: "default"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateVMSizeClassRef(tc.vmSize, tc.ref, field.NewPath("vmSizeClassRef"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateDedicatedHost(t *testing.T) {
+	g := NewWithT(t)
+
+	hostGroupID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/hostGroups/my-host-group"
+	hostID := hostGroupID + "/hosts/my-host"
+
+	tests := []struct {
+		name          string
+		dedicatedHost *DedicatedHost
+		spotVMOptions *SpotVMOptions
+		wantErr       bool
+	}{
+		{
+			name:    "no dedicated host",
+			wantErr: false,
+		},
+		{
+			name:          "valid host ID",
+			dedicatedHost: &DedicatedHost{HostID: hostID},
+			wantErr:       false,
+		},
+		{
+			name:          "valid host group ID",
+			dedicatedHost: &DedicatedHost{HostGroupID: hostGroupID},
+			wantErr:       false,
+		},
+		{
+			name:          "host ID and host group ID",
+			dedicatedHost: &DedicatedHost{HostID: hostID, HostGroupID: hostGroupID},
+			wantErr:       true,
+		},
+		{
+			name:          "neither host ID nor host group ID",
+			dedicatedHost: &DedicatedHost{},
+			wantErr:       true,
+		},
+		{
+			name:          "host ID of a host group",
+			dedicatedHost: &DedicatedHost{HostID: hostGroupID},
+			wantErr:       true,
+		},
+		{
+			name:          "host group ID of another resource type",
+			dedicatedHost: &DedicatedHost{HostGroupID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/availabilitySets/my-as"},
+			wantErr:       true,
+		},
+		{
+			name:          "invalid host ID",
+			dedicatedHost: &DedicatedHost{HostID: "my-host"},
+			wantErr:       true,
+		},
+		{
+			name:          "spot VM on a dedicated host",
+			dedicatedHost: &DedicatedHost{HostID: hostID},
+			spotVMOptions: &SpotVMOptions{},
+			wantErr:       true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateDedicatedHost(tc.dedicatedHost, tc.spotVMOptions, field.NewPath("dedicatedHost"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateTerminateNotificationTimeout(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		timeout *int
+		wantErr bool
+	}{
+		{
+			name:    "no timeout",
+			wantErr: false,
+		},
+		{
+			name:    "minimum timeout",
+			timeout: ptr.To(5),
+			wantErr: false,
+		},
+		{
+			name:    "maximum timeout",
+			timeout: ptr.To(15),
+			wantErr: false,
+		},
+		{
+			name:    "timeout too short",
+			timeout: ptr.To(3),
+			wantErr: true,
+		},
+		{
+			name:    "timeout too long",
+			timeout: ptr.To(20),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTerminateNotificationTimeout(tc.timeout, field.NewPath("terminateNotificationTimeout"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateVaultSecrets(t *testing.T) {
+	g := NewWithT(t)
+
+	vaultID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/my-vault"
+	certURL := "https://my-vault.vault.azure.net/secrets/my-cert/0123456789abcdef"
+
+	tests := []struct {
+		name         string
+		osType       string
+		vaultSecrets []VaultSecretGroup
+		wantErr      bool
+	}{
+		{
+			name:    "no vault secrets",
+			osType:  LinuxOS,
+			wantErr: false,
+		},
+		{
+			name:   "valid Linux certificate",
+			osType: LinuxOS,
+			vaultSecrets: []VaultSecretGroup{
+				{SourceVault: vaultID, VaultCertificates: []VaultCertificate{{CertificateURL: certURL}}},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "valid Windows certificate",
+			osType: WindowsOS,
+			vaultSecrets: []VaultSecretGroup{
+				{SourceVault: vaultID, VaultCertificates: []VaultCertificate{{CertificateURL: certURL, CertificateStore: "My"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "missing source vault",
+			osType: LinuxOS,
+			vaultSecrets: []VaultSecretGroup{
+				{VaultCertificates: []VaultCertificate{{CertificateURL: certURL}}},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "invalid source vault",
+			osType: LinuxOS,
+			vaultSecrets: []VaultSecretGroup{
+				{SourceVault: "my-vault", VaultCertificates: []VaultCertificate{{CertificateURL: certURL}}},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "no certificates",
+			osType: LinuxOS,
+			vaultSecrets: []VaultSecretGroup{
+				{SourceVault: vaultID},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "certificate URL is not https",
+			osType: LinuxOS,
+			vaultSecrets: []VaultSecretGroup{
+				{SourceVault: vaultID, VaultCertificates: []VaultCertificate{{CertificateURL: "http://my-vault.vault.azure.net/secrets/my-cert"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "Windows certificate without a store",
+			osType: WindowsOS,
+			vaultSecrets: []VaultSecretGroup{
+				{SourceVault: vaultID, VaultCertificates: []VaultCertificate{{CertificateURL: certURL}}},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "Linux certificate with a store",
+			osType: LinuxOS,
+			vaultSecrets: []VaultSecretGroup{
+				{SourceVault: vaultID, VaultCertificates: []VaultCertificate{{CertificateURL: certURL, CertificateStore: "My"}}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateVaultSecrets(tc.osType, tc.vaultSecrets, field.NewPath("vaultSecrets"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateVMGalleryApplications(t *testing.T) {
+	g := NewWithT(t)
+
+	versionID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/galleries/my-gallery/applications/my-agent/versions/1.0.0"
+
+	tests := []struct {
+		name    string
+		apps    []VMGalleryApplication
+		wantErr bool
+	}{
+		{
+			name:    "no gallery applications",
+			wantErr: false,
+		},
+		{
+			name:    "valid gallery application",
+			apps:    []VMGalleryApplication{{Version: versionID, Order: ptr.To[int32](0)}},
+			wantErr: false,
+		},
+		{
+			name:    "missing version",
+			apps:    []VMGalleryApplication{{Tags: "env=prod"}},
+			wantErr: true,
+		},
+		{
+			name:    "version is not a gallery application version",
+			apps:    []VMGalleryApplication{{Version: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/galleries/my-gallery/applications/my-agent"}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate version",
+			apps:    []VMGalleryApplication{{Version: versionID}, {Version: strings.ToUpper(versionID)}},
+			wantErr: true,
+		},
+		{
+			name:    "negative order",
+			apps:    []VMGalleryApplication{{Version: versionID, Order: ptr.To[int32](-1)}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateVMGalleryApplications(tc.apps, field.NewPath("vmGalleryApplications"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateVMExtensions(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name       string
+		extensions []VMExtension
+		wantErr    bool
+	}{
+		{
+			name:    "no extensions",
+			wantErr: false,
+		},
+		{
+			name: "valid extensions",
+			extensions: []VMExtension{
+				{Name: "CustomScript", Publisher: "Microsoft.Azure.Extensions", Version: "2.1", AutoUpgradeMinorVersion: ptr.To(true)},
+				{Name: "AzureMonitorLinuxAgent", Publisher: "Microsoft.Azure.Monitor", Version: "1.0", EnableAutomaticUpgrade: ptr.To(true)},
+			},
+			wantErr: false,
+		},
+		{
+			name:       "missing name",
+			extensions: []VMExtension{{Publisher: "Microsoft.Azure.Extensions", Version: "2.1"}},
+			wantErr:    true,
+		},
+		{
+			name:       "missing publisher",
+			extensions: []VMExtension{{Name: "CustomScript", Version: "2.1"}},
+			wantErr:    true,
+		},
+		{
+			name: "duplicate name",
+			extensions: []VMExtension{
+				{Name: "CustomScript", Publisher: "Microsoft.Azure.Extensions", Version: "2.1"},
+				{Name: "customscript", Publisher: "Microsoft.Azure.Extensions", Version: "2.0"},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "version with a patch number",
+			extensions: []VMExtension{{Name: "CustomScript", Publisher: "Microsoft.Azure.Extensions", Version: "2.1.3"}},
+			wantErr:    true,
+		},
+		{
+			name:       "missing version",
+			extensions: []VMExtension{{Name: "CustomScript", Publisher: "Microsoft.Azure.Extensions"}},
+			wantErr:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateVMExtensions(tc.extensions, field.NewPath("vmExtensions"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateComputerNamePrefix(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name         string
+		prefix       string
+		osType       string
+		suffixLength int
+		wantErr      bool
+	}{
+		{
+			name:         "no prefix",
+			osType:       WindowsOS,
+			suffixLength: ScaleSetComputerNameSuffixLength,
+			wantErr:      false,
+		},
+		{
+			name:         "valid Windows prefix",
+			prefix:       "prdweb-",
+			osType:       WindowsOS,
+			suffixLength: ScaleSetComputerNameSuffixLength,
+			wantErr:      false,
+		},
+		{
+			name:         "Windows prefix fitting a scale set",
+			prefix:       "prdweb-eu",
+			osType:       WindowsOS,
+			suffixLength: ScaleSetComputerNameSuffixLength,
+			wantErr:      false,
+		},
+		{
+			name:         "Windows prefix too long for a scale set",
+			prefix:       "prdweb-eus",
+			osType:       WindowsOS,
+			suffixLength: ScaleSetComputerNameSuffixLength,
+			wantErr:      true,
+		},
+		{
+			name:         "long Linux prefix",
+			prefix:       "prdweb-eastus2-",
+			osType:       LinuxOS,
+			suffixLength: ScaleSetComputerNameSuffixLength,
+			wantErr:      false,
+		},
+		{
+			name:         "uppercase characters",
+			prefix:       "PrdWeb",
+			osType:       LinuxOS,
+			suffixLength: ScaleSetComputerNameSuffixLength,
+			wantErr:      true,
+		},
+		{
+			name:         "starting with a digit",
+			prefix:       "1web",
+			osType:       LinuxOS,
+			suffixLength: ScaleSetComputerNameSuffixLength,
+			wantErr:      true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateComputerNamePrefix(tc.prefix, tc.osType, tc.suffixLength, field.NewPath("computerNamePrefix"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateAdminUsername(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name          string
+		adminUsername string
+		osType        string
+		wantErr       bool
+	}{
+		{
+			name:    "no admin username",
+			osType:  WindowsOS,
+			wantErr: false,
+		},
+		{
+			name:          "valid Linux admin username",
+			adminUsername: "ops_admin",
+			osType:        LinuxOS,
+			wantErr:       false,
+		},
+		{
+			name:          "admin username starting with an underscore",
+			adminUsername: "_svc-deploy",
+			osType:        LinuxOS,
+			wantErr:       false,
+		},
+		{
+			name:          "Windows admin username",
+			adminUsername: "opsadmin",
+			osType:        WindowsOS,
+			wantErr:       true,
+		},
+		{
+			name:          "reserved admin username",
+			adminUsername: "root",
+			osType:        LinuxOS,
+			wantErr:       true,
+		},
+		{
+			name:          "uppercase characters",
+			adminUsername: "OpsAdmin",
+			osType:        LinuxOS,
+			wantErr:       true,
+		},
+		{
+			name:          "starting with a digit",
+			adminUsername: "1ops",
+			osType:        LinuxOS,
+			wantErr:       true,
+		},
+		{
+			name:          "too long",
+			adminUsername: "operations-administrator-account1",
+			osType:        LinuxOS,
+			wantErr:       true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateAdminUsername(tc.adminUsername, tc.osType, field.NewPath("adminUsername"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateRunCommands(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		runCommands []RunCommand
+		wantErr     bool
+	}{
+		{
+			name:    "no run commands",
+			wantErr: false,
+		},
+		{
+			name: "valid run commands",
+			runCommands: []RunCommand{
+				{Name: "rotate-certs", Script: "kubeadm certs renew all", TimeoutSeconds: ptr.To[int32](600)},
+				{Name: "collect-logs", ScriptURI: "https://mystorage.blob.core.windows.net/scripts/collect-logs.sh", Parameters: []RunCommandParameter{{Name: "since", Value: "1h"}}},
+				{Name: "ip-config", CommandID: "RunShellScript"},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "missing name",
+			runCommands: []RunCommand{{Script: "echo hello"}},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid name",
+			runCommands: []RunCommand{{Name: "-rotate certs", Script: "echo hello"}},
+			wantErr:     true,
+		},
+		{
+			name:        "duplicate name",
+			runCommands: []RunCommand{{Name: "rotate-certs", Script: "echo hello"}, {Name: "Rotate-Certs", Script: "echo world"}},
+			wantErr:     true,
+		},
+		{
+			name:        "no source",
+			runCommands: []RunCommand{{Name: "rotate-certs"}},
+			wantErr:     true,
+		},
+		{
+			name:        "more than one source",
+			runCommands: []RunCommand{{Name: "rotate-certs", Script: "echo hello", CommandID: "RunShellScript"}},
+			wantErr:     true,
+		},
+		{
+			name:        "script URI is not https",
+			runCommands: []RunCommand{{Name: "collect-logs", ScriptURI: "http://example.com/collect-logs.sh"}},
+			wantErr:     true,
+		},
+		{
+			name:        "parameter without a name",
+			runCommands: []RunCommand{{Name: "collect-logs", Script: "echo $1", Parameters: []RunCommandParameter{{Value: "1h"}}}},
+			wantErr:     true,
+		},
+		{
+			name:        "zero timeout",
+			runCommands: []RunCommand{{Name: "rotate-certs", Script: "echo hello", TimeoutSeconds: ptr.To[int32](0)}},
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateRunCommands(tc.runCommands, field.NewPath("runCommands"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateBootstrapEncryption(t *testing.T) {
+	g := NewWithT(t)
+
+	keyID := "https://my-vault.vault.azure.net/keys/my-key/0123456789abcdef"
+
+	tests := []struct {
+		name       string
+		osType     string
+		identity   VMIdentity
+		encryption *BootstrapEncryption
+		wantErr    bool
+	}{
+		{
+			name:     "no bootstrap encryption",
+			osType:   LinuxOS,
+			identity: VMIdentityNone,
+			wantErr:  false,
+		},
+		{
+			name:       "Linux machine with a system-assigned identity",
+			osType:     LinuxOS,
+			identity:   VMIdentitySystemAssigned,
+			encryption: &BootstrapEncryption{KeyID: keyID},
+			wantErr:    false,
+		},
+		{
+			name:       "Linux machine with a user-assigned identity",
+			osType:     LinuxOS,
+			identity:   VMIdentityUserAssigned,
+			encryption: &BootstrapEncryption{KeyID: keyID, IdentityClientID: "my-client-id"},
+			wantErr:    false,
+		},
+		{
+			name:       "Windows machine",
+			osType:     WindowsOS,
+			identity:   VMIdentitySystemAssigned,
+			encryption: &BootstrapEncryption{KeyID: keyID},
+			wantErr:    true,
+		},
+		{
+			name:       "machine without an identity",
+			osType:     LinuxOS,
+			identity:   VMIdentityNone,
+			encryption: &BootstrapEncryption{KeyID: keyID},
+			wantErr:    true,
+		},
+		{
+			name:       "missing key ID",
+			osType:     LinuxOS,
+			identity:   VMIdentitySystemAssigned,
+			encryption: &BootstrapEncryption{},
+			wantErr:    true,
+		},
+		{
+			name:       "key ID without a version",
+			osType:     LinuxOS,
+			identity:   VMIdentitySystemAssigned,
+			encryption: &BootstrapEncryption{KeyID: "https://my-vault.vault.azure.net/keys/my-key"},
+			wantErr:    true,
+		},
+		{
+			name:       "secret ID instead of a key ID",
+			osType:     LinuxOS,
+			identity:   VMIdentitySystemAssigned,
+			encryption: &BootstrapEncryption{KeyID: "https://my-vault.vault.azure.net/secrets/my-secret/0123456789abcdef"},
+			wantErr:    true,
+		},
 	}
 
-	for _, test := range testcases {
-		t.Run(test.name, func(t *testing.T) {
-			err := ValidateDataDisks(test.disks, field.NewPath("dataDisks"))
-			if test.wantErr {
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateBootstrapEncryption(tc.osType, tc.identity, tc.encryption, field.NewPath("bootstrapEncryption"))
+			if tc.wantErr {
 				g.Expect(err).NotTo(BeEmpty())
 			} else {
 				g.Expect(err).To(BeEmpty())
@@ -649,6 +1638,65 @@ func TestAzureMachine_ValidateUserAssignedIdentity(t *testing.T) {
 	}
 }
 
+func TestAzureMachine_ValidateOSDiskSizeUpdate(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldOSDisk OSDisk
+		newOSDisk OSDisk
+		wantErr   bool
+	}{
+		{
+			name:      "grown OS disk",
+			oldOSDisk: OSDisk{DiskSizeGB: ptr.To[int32](30)},
+			newOSDisk: OSDisk{DiskSizeGB: ptr.To[int32](128)},
+			wantErr:   false,
+		},
+		{
+			name:      "OS disk with the default size of the image",
+			oldOSDisk: OSDisk{},
+			newOSDisk: OSDisk{DiskSizeGB: ptr.To[int32](128)},
+			wantErr:   false,
+		},
+		{
+			name:      "shrunk OS disk",
+			oldOSDisk: OSDisk{DiskSizeGB: ptr.To[int32](128)},
+			newOSDisk: OSDisk{DiskSizeGB: ptr.To[int32](30)},
+			wantErr:   true,
+		},
+		{
+			name:      "unset OS disk size",
+			oldOSDisk: OSDisk{DiskSizeGB: ptr.To[int32](128)},
+			newOSDisk: OSDisk{},
+			wantErr:   true,
+		},
+		{
+			name:      "OS disk larger than the maximum size",
+			oldOSDisk: OSDisk{DiskSizeGB: ptr.To[int32](128)},
+			newOSDisk: OSDisk{DiskSizeGB: ptr.To[int32](4096)},
+			wantErr:   true,
+		},
+		{
+			name:      "ephemeral OS disk",
+			oldOSDisk: OSDisk{DiskSizeGB: ptr.To[int32](30), DiffDiskSettings: &DiffDiskSettings{Option: "Local"}},
+			newOSDisk: OSDisk{DiskSizeGB: ptr.To[int32](64), DiffDiskSettings: &DiffDiskSettings{Option: "Local"}},
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := ValidateOSDiskSizeUpdate(test.oldOSDisk, test.newOSDisk, field.NewPath("osDisk", "diskSizeGB"))
+			if test.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
 func TestAzureMachine_ValidateDataDisksUpdate(t *testing.T) {
 	g := NewWithT(t)
 
@@ -831,6 +1879,82 @@ func TestAzureMachine_ValidateDataDisksUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid modification of maxShares",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 256,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+						MaxShares:          ptr.To[int32](3),
+					},
+					Lun:         ptr.To[int32](0),
+					CachingType: "None",
+				},
+			},
+			oldDisks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 256,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+						MaxShares:          ptr.To[int32](2),
+					},
+					Lun:         ptr.To[int32](0),
+					CachingType: "None",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid modification of diskIOPSReadWrite",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 256,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+						DiskIOPSReadWrite:  ptr.To[int64](40000),
+					},
+					Lun:         ptr.To[int32](0),
+					CachingType: "None",
+				},
+			},
+			oldDisks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 256,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+						DiskIOPSReadWrite:  ptr.To[int64](20000),
+					},
+					Lun:         ptr.To[int32](0),
+					CachingType: "None",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid existing disk change",
+			disks: []DataDisk{
+				{
+					NameSuffix:    "my_disk_1",
+					ManagedDiskID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/restored-disk-2",
+					Lun:           ptr.To[int32](0),
+					CachingType:   "None",
+				},
+			},
+			oldDisks: []DataDisk{
+				{
+					NameSuffix:    "my_disk_1",
+					ManagedDiskID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/restored-disk-1",
+					Lun:           ptr.To[int32](0),
+					CachingType:   "None",
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -845,6 +1969,95 @@ func TestAzureMachine_ValidateDataDisksUpdate(t *testing.T) {
 	}
 }
 
+func TestAzureMachine_ValidateDataDisksAttachUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	disk := DataDisk{
+		NameSuffix:  "my_disk",
+		DiskSizeGB:  64,
+		Lun:         ptr.To[int32](0),
+		CachingType: "ReadWrite",
+	}
+	otherDisk := DataDisk{
+		NameSuffix:  "my_other_disk",
+		DiskSizeGB:  64,
+		Lun:         ptr.To[int32](1),
+		CachingType: "ReadWrite",
+	}
+	existingDisk := DataDisk{
+		NameSuffix:    "my_existing_disk",
+		ManagedDiskID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/restored-disk",
+		Lun:           ptr.To[int32](2),
+		CachingType:   "None",
+	}
+	withDeletePolicy := func(disk DataDisk, policy DeletePolicy) DataDisk {
+		disk.DeletePolicy = policy
+		return disk
+	}
+
+	tests := []struct {
+		name     string
+		disks    []DataDisk
+		oldDisks []DataDisk
+		wantErr  bool
+	}{
+		{
+			name:     "data disk added",
+			disks:    []DataDisk{disk, otherDisk},
+			oldDisks: []DataDisk{disk},
+			wantErr:  false,
+		},
+		{
+			name:     "data disk removed",
+			disks:    []DataDisk{disk},
+			oldDisks: []DataDisk{disk, otherDisk},
+			wantErr:  false,
+		},
+		{
+			name:     "existing data disk removed",
+			disks:    []DataDisk{disk},
+			oldDisks: []DataDisk{disk, existingDisk},
+			wantErr:  false,
+		},
+		{
+			name:     "data disk whose delete policy is Retain removed",
+			disks:    []DataDisk{disk},
+			oldDisks: []DataDisk{disk, withDeletePolicy(otherDisk, DeletePolicyRetain)},
+			wantErr:  true,
+		},
+		{
+			name:     "existing data disk whose delete policy is Delete removed",
+			disks:    []DataDisk{disk},
+			oldDisks: []DataDisk{disk, withDeletePolicy(existingDisk, DeletePolicyDelete)},
+			wantErr:  true,
+		},
+		{
+			name: "data disk modified",
+			disks: []DataDisk{
+				{
+					NameSuffix:  "my_disk",
+					DiskSizeGB:  128,
+					Lun:         ptr.To[int32](0),
+					CachingType: "ReadWrite",
+				},
+			},
+			oldDisks: []DataDisk{disk},
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateDataDisksAttachUpdate(test.oldDisks, test.disks, field.NewPath("dataDisks"))
+			if test.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
 func TestAzureMachine_ValidateNetwork(t *testing.T) {
 	g := NewWithT(t)
 
@@ -924,6 +2137,61 @@ func TestAzureMachine_ValidateNetwork(t *testing.T) {
 			}},
 			wantErr: true,
 		},
+		{
+			name:                  "valid config with an existing network interface",
+			subnetName:            "",
+			acceleratedNetworking: nil,
+			networkInterfaces: []NetworkInterface{
+				{
+					ID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/my-nic",
+				},
+				{
+					SubnetName:       "subnet2",
+					PrivateIPConfigs: 1,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:                  "invalid existing network interface ID",
+			subnetName:            "",
+			acceleratedNetworking: nil,
+			networkInterfaces: []NetworkInterface{{
+				ID: "my-nic",
+			}},
+			wantErr: true,
+		},
+		{
+			name:                  "invalid config setting subnetName on an existing network interface",
+			subnetName:            "",
+			acceleratedNetworking: nil,
+			networkInterfaces: []NetworkInterface{{
+				ID:         "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/my-nic",
+				SubnetName: "subnet1",
+			}},
+			wantErr: true,
+		},
+		{
+			name:                  "invalid config setting privateIPConfigs on an existing network interface",
+			subnetName:            "",
+			acceleratedNetworking: nil,
+			networkInterfaces: []NetworkInterface{{
+				ID:               "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/my-nic",
+				PrivateIPConfigs: 1,
+			}},
+			wantErr: true,
+		},
+		{
+			name:                  "invalid config setting publicIPPerInstance on a machine",
+			subnetName:            "",
+			acceleratedNetworking: nil,
+			networkInterfaces: []NetworkInterface{{
+				SubnetName:          "subnet1",
+				PrivateIPConfigs:    1,
+				PublicIPPerInstance: true,
+			}},
+			wantErr: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -938,6 +2206,110 @@ func TestAzureMachine_ValidateNetwork(t *testing.T) {
 	}
 }
 
+func TestAzureMachine_ValidateSecurityProfile(t *testing.T) {
+	g := NewWithT(t)
+
+	confidentialDisk := &ManagedDiskParameters{
+		SecurityProfile: &VMDiskSecurityProfile{
+			SecurityEncryptionType: SecurityEncryptionTypeVMGuestStateOnly,
+		},
+	}
+
+	tests := []struct {
+		name            string
+		managedDisk     *ManagedDiskParameters
+		securityProfile *SecurityProfile
+		wantErr         bool
+	}{
+		{
+			name:            "no security profile",
+			managedDisk:     &ManagedDiskParameters{},
+			securityProfile: nil,
+			wantErr:         false,
+		},
+		{
+			name:            "confidential OS disk without security profile",
+			managedDisk:     confidentialDisk,
+			securityProfile: nil,
+			wantErr:         true,
+		},
+		{
+			name:        "valid confidential VM",
+			managedDisk: confidentialDisk,
+			securityProfile: &SecurityProfile{
+				EncryptionAtHost: ptr.To(true),
+				SecurityType:     SecurityTypesConfidentialVM,
+				UefiSettings:     &UefiSettings{VTpmEnabled: ptr.To(true)},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "confidential VM without OS disk encryption",
+			managedDisk: &ManagedDiskParameters{},
+			securityProfile: &SecurityProfile{
+				SecurityType: SecurityTypesConfidentialVM,
+				UefiSettings: &UefiSettings{VTpmEnabled: ptr.To(true)},
+			},
+			wantErr: true,
+		},
+		{
+			name:        "valid trusted launch VM",
+			managedDisk: &ManagedDiskParameters{},
+			securityProfile: &SecurityProfile{
+				EncryptionAtHost: ptr.To(true),
+				SecurityType:     SecurityTypesTrustedLaunch,
+				UefiSettings:     &UefiSettings{SecureBootEnabled: ptr.To(true), VTpmEnabled: ptr.To(true)},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "trusted launch VM with OS disk encryption",
+			managedDisk: confidentialDisk,
+			securityProfile: &SecurityProfile{
+				SecurityType: SecurityTypesTrustedLaunch,
+				UefiSettings: &UefiSettings{VTpmEnabled: ptr.To(true)},
+			},
+			wantErr: true,
+		},
+		{
+			name:        "secure boot without security type",
+			managedDisk: &ManagedDiskParameters{},
+			securityProfile: &SecurityProfile{
+				UefiSettings: &UefiSettings{SecureBootEnabled: ptr.To(true)},
+			},
+			wantErr: true,
+		},
+		{
+			name:        "vTPM without security type",
+			managedDisk: &ManagedDiskParameters{},
+			securityProfile: &SecurityProfile{
+				UefiSettings: &UefiSettings{VTpmEnabled: ptr.To(true)},
+			},
+			wantErr: true,
+		},
+		{
+			name:        "secure boot and vTPM disabled without security type",
+			managedDisk: &ManagedDiskParameters{},
+			securityProfile: &SecurityProfile{
+				EncryptionAtHost: ptr.To(true),
+				UefiSettings:     &UefiSettings{SecureBootEnabled: ptr.To(false), VTpmEnabled: ptr.To(false)},
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateSecurityProfile(tc.managedDisk, tc.securityProfile, field.NewPath("securityProfile"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
 func TestAzureMachine_ValidateConfidentialCompute(t *testing.T) {
 	g := NewWithT(t)
 
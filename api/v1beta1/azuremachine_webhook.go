@@ -18,20 +18,80 @@ package v1beta1
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	webhookutils "sigs.k8s.io/cluster-api-provider-azure/util/webhook"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
+// ultraSSDZonesLookupTimeout bounds the Ultra disk zone lookup, so that a slow Azure API doesn't make the admission
+// request time out.
+const ultraSSDZonesLookupTimeout = 5 * time.Second
+
+// UltraSSDZonesGetter looks up the availability zones in which the VM size of an AzureMachine supports Ultra disks.
+type UltraSSDZonesGetter interface {
+	// UltraSSDZones returns the location of the AzureMachine and the zones of that location in which its VM size supports Ultra disks.
+	UltraSSDZones(ctx context.Context, machine *AzureMachine) (location string, zones []string, err error)
+}
+
+// dedicatedHostGroupLookupTimeout bounds the dedicated host group lookup, so that a slow Azure API doesn't make the
+// admission request time out.
+const dedicatedHostGroupLookupTimeout = 5 * time.Second
+
+// DedicatedHostGroupZonesGetter looks up the availability zones of the dedicated host group of an AzureMachine.
+type DedicatedHostGroupZonesGetter interface {
+	// DedicatedHostGroupZones returns the resource ID of the dedicated host group of the AzureMachine and its zones.
+	// The zones are empty for a host group that isn't zonal.
+	DedicatedHostGroupZones(ctx context.Context, machine *AzureMachine) (hostGroupID string, zones []string, err error)
+}
+
+// vmExtensionVersionsLookupTimeout bounds the lookup of the versions of custom VM extensions, so that a slow Azure API
+// doesn't make the admission request time out.
+const vmExtensionVersionsLookupTimeout = 5 * time.Second
+
+// VMExtensionVersionsGetter looks up the versions of the VM extension images offered in the location of a cluster.
+type VMExtensionVersionsGetter interface {
+	// VMExtensionVersions returns the location of the cluster of the object and the versions of the VM extension type
+	// that the publisher offers in that location.
+	VMExtensionVersions(ctx context.Context, obj metav1.ObjectMeta, publisher, extensionType string) (location string, versions []string, err error)
+}
+
+// hourlyCostEstimationTimeout bounds the estimation of the hourly cost of a VM, so that a slow Retail Prices API
+// doesn't make the admission request time out.
+const hourlyCostEstimationTimeout = 5 * time.Second
+
+// HourlyCostEstimator estimates the hourly cost of the VMs of an AzureMachine or AzureMachinePool.
+type HourlyCostEstimator interface {
+	// EstimateHourlyCost returns the estimated hourly cost in USD of a VM of the size in the location of the cluster of
+	// the object, including its managed OS disk.
+	EstimateHourlyCost(ctx context.Context, obj metav1.ObjectMeta, vmSize string, osDisk OSDisk, spot bool) (float64, error)
+}
+
 // SetupAzureMachineWebhookWithManager sets up and registers the webhook with the manager.
-func SetupAzureMachineWebhookWithManager(mgr ctrl.Manager) error {
-	mw := &azureMachineWebhook{Client: mgr.GetClient()}
+// ultraSSDZones is optional; when it is nil, Ultra disk zone support is only checked when reconciling the VM.
+// hostGroupZones is optional; when it is nil, the zone of a dedicated host group is not checked.
+// extensionVersions is optional; when it is nil, custom VM extensions are only checked before they are deployed.
+// costEstimator is optional; when it is nil, the estimated hourly cost is only annotated by the controller.
+func SetupAzureMachineWebhookWithManager(mgr ctrl.Manager, ultraSSDZones UltraSSDZonesGetter, hostGroupZones DedicatedHostGroupZonesGetter, extensionVersions VMExtensionVersionsGetter, costEstimator HourlyCostEstimator) error {
+	mw := &azureMachineWebhook{
+		Client:            mgr.GetClient(),
+		UltraSSDZones:     ultraSSDZones,
+		HostGroupZones:    hostGroupZones,
+		ExtensionVersions: extensionVersions,
+		CostEstimator:     costEstimator,
+	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&AzureMachine{}).
 		WithDefaulter(mw).
@@ -44,7 +104,11 @@ func SetupAzureMachineWebhookWithManager(mgr ctrl.Manager) error {
 
 // azureMachineWebhook implements a validating and defaulting webhook for AzureMachines.
 type azureMachineWebhook struct {
-	Client client.Client
+	Client            client.Client
+	UltraSSDZones     UltraSSDZonesGetter
+	HostGroupZones    DedicatedHostGroupZonesGetter
+	ExtensionVersions VMExtensionVersionsGetter
+	CostEstimator     HourlyCostEstimator
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
@@ -66,11 +130,176 @@ func (mw *azureMachineWebhook) ValidateCreate(ctx context.Context, obj runtime.O
 		allErrs = append(allErrs, errs...)
 	}
 
+	warnings, errs := mw.validateUltraSSDZones(ctx, m)
+	allErrs = append(allErrs, errs...)
+
+	hostGroupWarnings, errs := mw.validateDedicatedHostZone(ctx, m)
+	warnings = append(warnings, hostGroupWarnings...)
+	allErrs = append(allErrs, errs...)
+
+	extensionWarnings, errs := ValidateVMExtensionVersions(ctx, mw.ExtensionVersions, m.ObjectMeta, spec.VMExtensions, field.NewPath("spec", "vmExtensions"))
+	warnings = append(warnings, extensionWarnings...)
+	allErrs = append(allErrs, errs...)
+
 	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+
+	return warnings, apierrors.NewInvalid(GroupVersion.WithKind("AzureMachine").GroupKind(), m.Name, allErrs)
+}
+
+// validateUltraSSDZones checks that the VM size of an AzureMachine with Ultra data disks supports them in its zone.
+// The check is best effort: if the supported zones can't be looked up in time, the machine is admitted with a
+// warning and the VM reconciler reports the problem instead.
+func (mw *azureMachineWebhook) validateUltraSSDZones(ctx context.Context, m *AzureMachine) (admission.Warnings, field.ErrorList) {
+	if mw.UltraSSDZones == nil || !hasUltraSSDDataDisks(m.Spec.DataDisks) {
 		return nil, nil
 	}
+	if m.Spec.VMSize == "" {
+		return admission.Warnings{"Ultra disk support can't be verified before the VM size is resolved from vmSizeClassRef"}, nil
+	}
 
-	return nil, apierrors.NewInvalid(GroupVersion.WithKind("AzureMachine").GroupKind(), m.Name, allErrs)
+	ctx, cancel := context.WithTimeout(ctx, ultraSSDZonesLookupTimeout)
+	defer cancel()
+	location, zones, err := mw.UltraSSDZones.UltraSSDZones(ctx, m)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("unable to verify that VM size %s supports Ultra disks: %v", m.Spec.VMSize, err)}, nil
+	}
+
+	// Without a failure domain, the zone of the VM is only known once it is placed, and regional VMs can support
+	// Ultra disks in locations where no zone does.
+	if m.Spec.FailureDomain == nil {
+		if len(zones) == 0 {
+			return admission.Warnings{fmt.Sprintf("VM size %s does not support Ultra disks in any zone of location %s; machines placed in a zone will fail to provision",
+				m.Spec.VMSize, location)}, nil
+		}
+		return admission.Warnings{fmt.Sprintf("VM size %s only supports Ultra disks in zones %s of location %s; machines placed in other zones will fail to provision",
+			m.Spec.VMSize, strings.Join(zones, ", "), location)}, nil
+	}
+
+	if len(zones) == 0 {
+		return nil, field.ErrorList{field.Invalid(field.NewPath("spec", "vmSize"), m.Spec.VMSize,
+			fmt.Sprintf("VM size %s does not support Ultra disks in any zone of location %s. Select a different VM size or disable Ultra disks", m.Spec.VMSize, location))}
+	}
+
+	for _, zone := range zones {
+		if zone == *m.Spec.FailureDomain {
+			return nil, nil
+		}
+	}
+	return nil, field.ErrorList{field.Invalid(field.NewPath("spec", "failureDomain"), *m.Spec.FailureDomain,
+		fmt.Sprintf("VM size %s only supports Ultra disks in zones %s of location %s", m.Spec.VMSize, strings.Join(zones, ", "), location))}
+}
+
+// validateDedicatedHostZone checks that the failure domain of an AzureMachine on a dedicated host matches the zones of
+// its host group, since Azure can only place a VM on a host in the same zone. The check is best effort: if the host
+// group can't be looked up in time, the machine is admitted with a warning.
+func (mw *azureMachineWebhook) validateDedicatedHostZone(ctx context.Context, m *AzureMachine) (admission.Warnings, field.ErrorList) {
+	if mw.HostGroupZones == nil || m.Spec.DedicatedHost == nil {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dedicatedHostGroupLookupTimeout)
+	defer cancel()
+	hostGroupID, zones, err := mw.HostGroupZones.DedicatedHostGroupZones(ctx, m)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("unable to verify the zone of the dedicated host group: %v", err)}, nil
+	}
+
+	if len(zones) == 0 {
+		if m.Spec.FailureDomain != nil {
+			return nil, field.ErrorList{field.Invalid(field.NewPath("spec", "failureDomain"), *m.Spec.FailureDomain,
+				fmt.Sprintf("dedicated host group %s is not zonal. Use a host group in zone %s or a machine without a failure domain", hostGroupID, *m.Spec.FailureDomain))}
+		}
+		return nil, nil
+	}
+
+	// Without a failure domain, the zone of the VM is only known once the owner Machine is placed.
+	if m.Spec.FailureDomain == nil {
+		return admission.Warnings{fmt.Sprintf("dedicated host group %s is in zones %s; machines placed in other zones will fail to provision",
+			hostGroupID, strings.Join(zones, ", "))}, nil
+	}
+	for _, zone := range zones {
+		if zone == *m.Spec.FailureDomain {
+			return nil, nil
+		}
+	}
+	return nil, field.ErrorList{field.Invalid(field.NewPath("spec", "failureDomain"), *m.Spec.FailureDomain,
+		fmt.Sprintf("dedicated host group %s is in zones %s", hostGroupID, strings.Join(zones, ", ")))}
+}
+
+// ValidateVMExtensionVersions checks that the publishers of custom VM extensions offer them in their major.minor
+// versions in the location of the cluster of the object. The check is best effort: if the versions can't be looked up
+// in time, the object is admitted with a warning and the extension is checked again before it is deployed.
+func ValidateVMExtensionVersions(ctx context.Context, getter VMExtensionVersionsGetter, obj metav1.ObjectMeta, extensions []VMExtension, fldPath *field.Path) (admission.Warnings, field.ErrorList) {
+	if getter == nil || len(extensions) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, vmExtensionVersionsLookupTimeout)
+	defer cancel()
+
+	var warnings admission.Warnings
+	var allErrs field.ErrorList
+	for i, extension := range extensions {
+		if extension.Name == "" || extension.Publisher == "" || !validVMExtensionVersion.MatchString(extension.Version) {
+			// Already reported by ValidateVMExtensions.
+			continue
+		}
+		location, versions, err := getter.VMExtensionVersions(ctx, obj, extension.Publisher, extension.Name)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("unable to verify that publisher %s offers VM extension %s in version %s: %v",
+				extension.Publisher, extension.Name, extension.Version, err))
+			continue
+		}
+		if !vmExtensionVersionOffered(versions, extension.Version) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("version"), extension.Version,
+				fmt.Sprintf("publisher %s doesn't offer VM extension %s in version %s in location %s", extension.Publisher, extension.Name, extension.Version, location)))
+		}
+	}
+	return warnings, allErrs
+}
+
+// SetEstimatedHourlyCost annotates the object with the estimated hourly cost of its VMs when the CostEstimation feature
+// is enabled, so that admission policies evaluated after the mutating webhooks can check it on creation. The estimate is
+// best effort: if it can't be made in time, the annotation is left unchanged and is set by the controller instead.
+func SetEstimatedHourlyCost(ctx context.Context, estimator HourlyCostEstimator, obj *metav1.ObjectMeta, vmSize string, osDisk OSDisk, spot bool) {
+	if estimator == nil || !feature.Gates.Enabled(feature.CostEstimation) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hourlyCostEstimationTimeout)
+	defer cancel()
+
+	cost, err := estimator.EstimateHourlyCost(ctx, *obj, vmSize, osDisk, spot)
+	if err != nil {
+		ctrl.LoggerFrom(ctx).V(2).Info("failed to estimate the hourly cost", "vmSize", vmSize, "err", err.Error())
+		return
+	}
+	if obj.Annotations == nil {
+		obj.Annotations = map[string]string{}
+	}
+	obj.Annotations[EstimatedHourlyCostAnnotation] = fmt.Sprintf("%.4f", cost)
+}
+
+// vmExtensionVersionOffered returns true if one of the VM extension image versions has the major.minor type handler version.
+func vmExtensionVersionOffered(versions []string, version string) bool {
+	for _, v := range versions {
+		if v == version || strings.HasPrefix(v, version+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasUltraSSDDataDisks returns true if any of the data disks uses the UltraSSD_LRS storage account type.
+func hasUltraSSDDataDisks(dataDisks []DataDisk) bool {
+	for _, disk := range dataDisks {
+		if disk.ManagedDisk != nil && disk.ManagedDisk.StorageAccountType == string(compute.StorageAccountTypesUltraSSDLRS) {
+			return true
+		}
+	}
+	return false
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -92,6 +321,24 @@ func (mw *azureMachineWebhook) ValidateUpdate(ctx context.Context, oldObj, newOb
 		allErrs = append(allErrs, err)
 	}
 
+	// The VM size is set once when it is resolved from a size class, and can only be changed afterwards
+	// on machines that opted in to resizing their VM in place.
+	if old.Spec.VMSize != "" && m.Spec.VMSize != old.Spec.VMSize {
+		if _, ok := m.Annotations[ResizeVMAnnotation]; !ok {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "VMSize"), m.Spec.VMSize,
+				fmt.Sprintf("field is immutable, set the %s annotation to resize the VM in place", ResizeVMAnnotation)))
+		} else if m.Spec.VMSize == "" {
+			allErrs = append(allErrs, field.Required(field.NewPath("Spec", "VMSize"), "the VM size can't be unset"))
+		}
+	}
+
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "VMSizeClassRef"),
+		old.Spec.VMSizeClassRef,
+		m.Spec.VMSizeClassRef); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
 	if err := webhookutils.ValidateImmutable(
 		field.NewPath("Spec", "Identity"),
 		old.Spec.Identity,
@@ -120,17 +367,31 @@ func (mw *azureMachineWebhook) ValidateUpdate(ctx context.Context, oldObj, newOb
 		allErrs = append(allErrs, err)
 	}
 
+	// The OS disk size can be changed on machines that opted in to resizing their OS disk in place.
+	oldOSDisk := osDiskWithoutDeletePolicy(old.Spec.OSDisk)
+	newOSDisk := osDiskWithoutDeletePolicy(m.Spec.OSDisk)
+	if _, ok := m.Annotations[ResizeOSDiskAnnotation]; ok && !ptr.Equal(oldOSDisk.DiskSizeGB, newOSDisk.DiskSizeGB) {
+		allErrs = append(allErrs, ValidateOSDiskSizeUpdate(oldOSDisk, newOSDisk, field.NewPath("Spec", "OSDisk", "DiskSizeGB"))...)
+		newOSDisk.DiskSizeGB = oldOSDisk.DiskSizeGB
+	}
+
 	if err := webhookutils.ValidateImmutable(
 		field.NewPath("Spec", "OSDisk"),
-		old.Spec.OSDisk,
-		m.Spec.OSDisk); err != nil {
+		oldOSDisk,
+		newOSDisk); err != nil {
 		allErrs = append(allErrs, err)
 	}
 
-	if err := webhookutils.ValidateImmutable(
+	// Data disks can be added and removed on machines that opted in to attaching and detaching them in place.
+	if _, ok := m.Annotations[UpdateDataDisksAnnotation]; ok {
+		if !reflect.DeepEqual(old.Spec.DataDisks, m.Spec.DataDisks) {
+			allErrs = append(allErrs, ValidateDataDisks(m.Spec.DataDisks, field.NewPath("Spec", "DataDisks"))...)
+			allErrs = append(allErrs, ValidateDataDisksAttachUpdate(old.Spec.DataDisks, m.Spec.DataDisks, field.NewPath("Spec", "DataDisks"))...)
+		}
+	} else if err := webhookutils.ValidateImmutable(
 		field.NewPath("Spec", "DataDisks"),
-		old.Spec.DataDisks,
-		m.Spec.DataDisks); err != nil {
+		dataDisksWithoutDeletePolicy(old.Spec.DataDisks),
+		dataDisksWithoutDeletePolicy(m.Spec.DataDisks)); err != nil {
 		allErrs = append(allErrs, err)
 	}
 
@@ -141,6 +402,20 @@ func (mw *azureMachineWebhook) ValidateUpdate(ctx context.Context, oldObj, newOb
 		allErrs = append(allErrs, err)
 	}
 
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "AdditionalSSHPublicKeys"),
+		old.Spec.AdditionalSSHPublicKeys,
+		m.Spec.AdditionalSSHPublicKeys); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "AdminUsername"),
+		old.Spec.AdminUsername,
+		m.Spec.AdminUsername); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
 	if err := webhookutils.ValidateImmutable(
 		field.NewPath("Spec", "AllocatePublicIP"),
 		old.Spec.AllocatePublicIP,
@@ -172,6 +447,20 @@ func (mw *azureMachineWebhook) ValidateUpdate(ctx context.Context, oldObj, newOb
 		allErrs = append(allErrs, err)
 	}
 
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "TerminateNotificationTimeout"),
+		old.Spec.TerminateNotificationTimeout,
+		m.Spec.TerminateNotificationTimeout); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "DedicatedHost"),
+		old.Spec.DedicatedHost,
+		m.Spec.DedicatedHost); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
 	if err := webhookutils.ValidateImmutable(
 		field.NewPath("Spec", "SecurityProfile"),
 		old.Spec.SecurityProfile,
@@ -179,6 +468,27 @@ func (mw *azureMachineWebhook) ValidateUpdate(ctx context.Context, oldObj, newOb
 		allErrs = append(allErrs, err)
 	}
 
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "VaultSecrets"),
+		old.Spec.VaultSecrets,
+		m.Spec.VaultSecrets); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "VMGalleryApplications"),
+		old.Spec.VMGalleryApplications,
+		m.Spec.VMGalleryApplications); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "BootstrapEncryption"),
+		old.Spec.BootstrapEncryption,
+		m.Spec.BootstrapEncryption); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
 	if old.Spec.Diagnostics != nil {
 		if err := webhookutils.ValidateImmutable(
 			field.NewPath("Spec", "Diagnostics"),
@@ -188,6 +498,14 @@ func (mw *azureMachineWebhook) ValidateUpdate(ctx context.Context, oldObj, newOb
 		}
 	}
 
+	if errs := ValidateDiskSnapshot(m.Spec.DiskSnapshot, field.NewPath("spec", "diskSnapshot")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateRunCommands(m.Spec.RunCommands, field.NewPath("spec", "runCommands")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	if !reflect.DeepEqual(m.Spec.NetworkInterfaces, old.Spec.NetworkInterfaces) {
 		// The defaulting webhook may have migrated values from the old SubnetName field to the new NetworkInterfaces format.
 		old.Spec.SetNetworkInterfacesDefaults()
@@ -223,5 +541,9 @@ func (mw *azureMachineWebhook) Default(ctx context.Context, obj runtime.Object)
 	if !ok {
 		return apierrors.NewBadRequest("expected an AzureMachine resource")
 	}
-	return m.SetDefaults(mw.Client)
+	if err := m.SetDefaults(mw.Client); err != nil {
+		return err
+	}
+	SetEstimatedHourlyCost(ctx, mw.CostEstimator, &m.ObjectMeta, m.Spec.VMSize, m.Spec.OSDisk, m.Spec.SpotVMOptions != nil)
+	return nil
 }
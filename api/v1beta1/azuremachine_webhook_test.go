@@ -26,7 +26,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -217,6 +219,11 @@ func TestAzureMachine_ValidateCreate(t *testing.T) {
 			machine: createMachineWithConfidentialCompute(SecurityEncryptionTypeDiskWithVMGuestState, "", false, true, false),
 			wantErr: true,
 		},
+		{
+			name:    "azuremachine with spot restore policy",
+			machine: createMachineWithSpotRestorePolicy(),
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -231,6 +238,284 @@ func TestAzureMachine_ValidateCreate(t *testing.T) {
 	}
 }
 
+type fakeUltraSSDZonesGetter struct {
+	zones []string
+	err   error
+}
+
+func (f fakeUltraSSDZonesGetter) UltraSSDZones(_ context.Context, _ *AzureMachine) (string, []string, error) {
+	return "eastus", f.zones, f.err
+}
+
+func TestAzureMachine_ValidateCreateUltraSSDZones(t *testing.T) {
+	tests := []struct {
+		name          string
+		ultraSSDZones UltraSSDZonesGetter
+		failureDomain *string
+		vmSizeClass   bool
+		ultraSSD      bool
+		wantErr       string
+		wantWarnings  bool
+	}{
+		{
+			name:          "no Ultra disks",
+			ultraSSDZones: fakeUltraSSDZonesGetter{},
+			failureDomain: ptr.To("1"),
+		},
+		{
+			name:          "no zone lookup",
+			failureDomain: ptr.To("1"),
+			ultraSSD:      true,
+		},
+		{
+			name:          "zone lookup fails",
+			ultraSSDZones: fakeUltraSSDZonesGetter{err: errors.New("no credentials")},
+			failureDomain: ptr.To("1"),
+			ultraSSD:      true,
+			wantWarnings:  true,
+		},
+		{
+			name:          "VM size not resolved yet",
+			ultraSSDZones: fakeUltraSSDZonesGetter{err: errors.New("unexpected lookup")},
+			vmSizeClass:   true,
+			failureDomain: ptr.To("1"),
+			ultraSSD:      true,
+			wantWarnings:  true,
+		},
+		{
+			name:          "zone supports Ultra disks",
+			ultraSSDZones: fakeUltraSSDZonesGetter{zones: []string{"1", "2"}},
+			failureDomain: ptr.To("2"),
+			ultraSSD:      true,
+		},
+		{
+			name:          "zone does not support Ultra disks",
+			ultraSSDZones: fakeUltraSSDZonesGetter{zones: []string{"1", "2"}},
+			failureDomain: ptr.To("3"),
+			ultraSSD:      true,
+			wantErr:       "VM size Standard_D2s_v3 only supports Ultra disks in zones 1, 2 of location eastus",
+		},
+		{
+			name:          "no zone supports Ultra disks",
+			ultraSSDZones: fakeUltraSSDZonesGetter{},
+			failureDomain: ptr.To("1"),
+			ultraSSD:      true,
+			wantErr:       "VM size Standard_D2s_v3 does not support Ultra disks in any zone of location eastus",
+		},
+		{
+			name:          "no zone supports Ultra disks and zone not known",
+			ultraSSDZones: fakeUltraSSDZonesGetter{},
+			ultraSSD:      true,
+			wantWarnings:  true,
+		},
+		{
+			name:          "zone not known",
+			ultraSSDZones: fakeUltraSSDZonesGetter{zones: []string{"1"}},
+			ultraSSD:      true,
+			wantWarnings:  true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machine := createMachineWithMarketPlaceImage("PUB1234", "OFFER1234", "SKU1234", "1.0.0")
+			machine.Spec.VMSize = "Standard_D2s_v3"
+			if tc.vmSizeClass {
+				machine.Spec.VMSize = ""
+				machine.Spec.VMSizeClassRef = &VMSizeClassReference{CatalogName: "catalog", SizeClass: "general"}
+			}
+			machine.Spec.FailureDomain = tc.failureDomain
+			if tc.ultraSSD {
+				machine.Spec.DataDisks = []DataDisk{
+					{
+						NameSuffix:  "ultra",
+						DiskSizeGB:  64,
+						Lun:         ptr.To[int32](0),
+						CachingType: string(compute.CachingTypesNone),
+						ManagedDisk: &ManagedDiskParameters{StorageAccountType: "UltraSSD_LRS"},
+					},
+				}
+			}
+			mw := &azureMachineWebhook{UltraSSDZones: tc.ultraSSDZones}
+			warnings, err := mw.ValidateCreate(context.Background(), machine)
+			if tc.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tc.wantWarnings {
+				g.Expect(warnings).NotTo(BeEmpty())
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
+type fakeDedicatedHostGroupZonesGetter struct {
+	zones []string
+	err   error
+}
+
+func (f fakeDedicatedHostGroupZonesGetter) DedicatedHostGroupZones(_ context.Context, _ *AzureMachine) (string, []string, error) {
+	return "my-host-group", f.zones, f.err
+}
+
+func TestAzureMachine_ValidateCreateDedicatedHostZone(t *testing.T) {
+	tests := []struct {
+		name           string
+		hostGroupZones DedicatedHostGroupZonesGetter
+		failureDomain  *string
+		dedicatedHost  bool
+		wantErr        string
+		wantWarnings   bool
+	}{
+		{
+			name:           "no dedicated host",
+			hostGroupZones: fakeDedicatedHostGroupZonesGetter{err: errors.New("unexpected lookup")},
+			failureDomain:  ptr.To("1"),
+		},
+		{
+			name:          "no host group lookup",
+			failureDomain: ptr.To("1"),
+			dedicatedHost: true,
+		},
+		{
+			name:           "host group lookup fails",
+			hostGroupZones: fakeDedicatedHostGroupZonesGetter{err: errors.New("no credentials")},
+			failureDomain:  ptr.To("1"),
+			dedicatedHost:  true,
+			wantWarnings:   true,
+		},
+		{
+			name:           "host group in the zone of the machine",
+			hostGroupZones: fakeDedicatedHostGroupZonesGetter{zones: []string{"2"}},
+			failureDomain:  ptr.To("2"),
+			dedicatedHost:  true,
+		},
+		{
+			name:           "host group in another zone",
+			hostGroupZones: fakeDedicatedHostGroupZonesGetter{zones: []string{"2"}},
+			failureDomain:  ptr.To("1"),
+			dedicatedHost:  true,
+			wantErr:        "dedicated host group my-host-group is in zones 2",
+		},
+		{
+			name:           "zonal host group and zone not known",
+			hostGroupZones: fakeDedicatedHostGroupZonesGetter{zones: []string{"2"}},
+			dedicatedHost:  true,
+			wantWarnings:   true,
+		},
+		{
+			name:           "regional host group and a machine without zone",
+			hostGroupZones: fakeDedicatedHostGroupZonesGetter{},
+			dedicatedHost:  true,
+		},
+		{
+			name:           "regional host group and a zonal machine",
+			hostGroupZones: fakeDedicatedHostGroupZonesGetter{},
+			failureDomain:  ptr.To("1"),
+			dedicatedHost:  true,
+			wantErr:        "dedicated host group my-host-group is not zonal",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machine := createMachineWithMarketPlaceImage("PUB1234", "OFFER1234", "SKU1234", "1.0.0")
+			machine.Spec.FailureDomain = tc.failureDomain
+			if tc.dedicatedHost {
+				machine.Spec.DedicatedHost = &DedicatedHost{
+					HostGroupID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/hostGroups/my-host-group",
+				}
+			}
+			mw := &azureMachineWebhook{HostGroupZones: tc.hostGroupZones}
+			warnings, err := mw.ValidateCreate(context.Background(), machine)
+			if tc.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tc.wantWarnings {
+				g.Expect(warnings).NotTo(BeEmpty())
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
+type fakeVMExtensionVersionsGetter struct {
+	versions []string
+	err      error
+}
+
+func (f fakeVMExtensionVersionsGetter) VMExtensionVersions(_ context.Context, _ metav1.ObjectMeta, _, _ string) (string, []string, error) {
+	return "eastus", f.versions, f.err
+}
+
+func TestAzureMachine_ValidateCreateVMExtensionVersions(t *testing.T) {
+	tests := []struct {
+		name              string
+		extensionVersions VMExtensionVersionsGetter
+		version           string
+		wantErr           string
+		wantWarnings      bool
+	}{
+		{
+			name:    "no extension versions lookup",
+			version: "2.1",
+		},
+		{
+			name:              "version is offered",
+			extensionVersions: fakeVMExtensionVersionsGetter{versions: []string{"2.0.7", "2.1.3"}},
+			version:           "2.1",
+		},
+		{
+			name:              "version is not offered",
+			extensionVersions: fakeVMExtensionVersionsGetter{versions: []string{"2.0.7", "2.1.3"}},
+			version:           "3.0",
+			wantErr:           "publisher Microsoft.Azure.Extensions doesn't offer VM extension CustomScript in version 3.0 in location eastus",
+		},
+		{
+			name:              "extension is not offered",
+			extensionVersions: fakeVMExtensionVersionsGetter{},
+			version:           "2.1",
+			wantErr:           "doesn't offer VM extension CustomScript",
+		},
+		{
+			name:              "extension versions lookup fails",
+			extensionVersions: fakeVMExtensionVersionsGetter{err: errors.New("no credentials")},
+			version:           "2.1",
+			wantWarnings:      true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machine := createMachineWithMarketPlaceImage("PUB1234", "OFFER1234", "SKU1234", "1.0.0")
+			machine.Spec.VMExtensions = []VMExtension{
+				{Name: "CustomScript", Publisher: "Microsoft.Azure.Extensions", Version: tc.version},
+			}
+			mw := &azureMachineWebhook{ExtensionVersions: tc.extensionVersions}
+			warnings, err := mw.ValidateCreate(context.Background(), machine)
+			if tc.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tc.wantWarnings {
+				g.Expect(warnings).NotTo(BeEmpty())
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
 func TestAzureMachine_ValidateUpdate(t *testing.T) {
 	g := NewWithT(t)
 
@@ -448,6 +733,156 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalidTest: azuremachine.spec.OSDisk.DiskSizeGB is immutable without the resize annotation",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					OSDisk: OSDisk{
+						OSType:     "osType-1",
+						DiskSizeGB: ptr.To[int32](30),
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					OSDisk: OSDisk{
+						OSType:     "osType-1",
+						DiskSizeGB: ptr.To[int32](64),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "validTest: azuremachine.spec.OSDisk.DiskSizeGB can be grown with the resize annotation",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					OSDisk: OSDisk{
+						OSType:     "osType-1",
+						DiskSizeGB: ptr.To[int32](30),
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{ResizeOSDiskAnnotation: "true"},
+				},
+				Spec: AzureMachineSpec{
+					OSDisk: OSDisk{
+						OSType:     "osType-1",
+						DiskSizeGB: ptr.To[int32](64),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.OSDisk.DiskSizeGB cannot be shrunk with the resize annotation",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					OSDisk: OSDisk{
+						OSType:     "osType-1",
+						DiskSizeGB: ptr.To[int32](64),
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{ResizeOSDiskAnnotation: "true"},
+				},
+				Spec: AzureMachineSpec{
+					OSDisk: OSDisk{
+						OSType:     "osType-1",
+						DiskSizeGB: ptr.To[int32](30),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.OSDisk is immutable except for its size with the resize annotation",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					OSDisk: OSDisk{
+						OSType:     "osType-0",
+						DiskSizeGB: ptr.To[int32](30),
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{ResizeOSDiskAnnotation: "true"},
+				},
+				Spec: AzureMachineSpec{
+					OSDisk: OSDisk{
+						OSType:     "osType-1",
+						DiskSizeGB: ptr.To[int32](64),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.VMSize cannot be changed without the resize annotation",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					VMSize: "Standard_D2s_v3",
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					VMSize: "Standard_D4s_v3",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "validTest: azuremachine.spec.VMSize can be changed with the resize annotation",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					VMSize: "Standard_D2s_v3",
+				},
+			},
+			newMachine: &AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{ResizeVMAnnotation: "true"},
+				},
+				Spec: AzureMachineSpec{
+					VMSize: "Standard_D4s_v3",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.VMSize cannot be unset with the resize annotation",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					VMSize: "Standard_D2s_v3",
+				},
+			},
+			newMachine: &AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{ResizeVMAnnotation: "true"},
+				},
+				Spec: AzureMachineSpec{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "validTest: azuremachine.spec.VMSize can be resolved from a size class",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					VMSizeClassRef: &VMSizeClassReference{CatalogName: "catalog", SizeClass: "general"},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					VMSize:         "Standard_D4s_v3",
+					VMSizeClassRef: &VMSizeClassReference{CatalogName: "catalog", SizeClass: "general"},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalidTest: azuremachine.spec.DataDisks is immutable",
 			oldMachine: &AzureMachine{
@@ -492,6 +927,92 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "validTest: azuremachine.spec.DataDisks can be added with the update-data-disks annotation",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DataDisks: []DataDisk{
+						{NameSuffix: "disk-1", DiskSizeGB: 128, Lun: ptr.To[int32](0)},
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{UpdateDataDisksAnnotation: "true"},
+				},
+				Spec: AzureMachineSpec{
+					DataDisks: []DataDisk{
+						{NameSuffix: "disk-1", DiskSizeGB: 128, Lun: ptr.To[int32](0)},
+						{NameSuffix: "disk-2", DiskSizeGB: 64, Lun: ptr.To[int32](1)},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.DataDisks can't be modified with the update-data-disks annotation",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DataDisks: []DataDisk{
+						{NameSuffix: "disk-1", DiskSizeGB: 128, Lun: ptr.To[int32](0)},
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{UpdateDataDisksAnnotation: "true"},
+				},
+				Spec: AzureMachineSpec{
+					DataDisks: []DataDisk{
+						{NameSuffix: "disk-1", DiskSizeGB: 256, Lun: ptr.To[int32](0)},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "validTest: azuremachine.spec.OSDisk.DeletePolicy is mutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					OSDisk: OSDisk{
+						OSType: "osType-1",
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					OSDisk: OSDisk{
+						OSType:       "osType-1",
+						DeletePolicy: DeletePolicyRetain,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "validTest: azuremachine.spec.DataDisks.DeletePolicy is mutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DataDisks: []DataDisk{
+						{
+							DiskSizeGB:   128,
+							DeletePolicy: DeletePolicyRetain,
+						},
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DataDisks: []DataDisk{
+						{
+							DiskSizeGB:   128,
+							DeletePolicy: DeletePolicyDelete,
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalidTest: azuremachine.spec.SSHPublicKey is immutable",
 			oldMachine: &AzureMachine{
@@ -520,6 +1041,20 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalidTest: azuremachine.spec.AdditionalSSHPublicKeys is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					AdditionalSSHPublicKeys: []string{"teamKey"},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					AdditionalSSHPublicKeys: []string{"teamKey", "breakGlassKey"},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalidTest: azuremachine.spec.AllocatePublicIP is immutable",
 			oldMachine: &AzureMachine{
@@ -668,6 +1203,42 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalidTest: azuremachine.spec.DedicatedHost is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DedicatedHost: &DedicatedHost{
+						HostID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/hostGroups/my-host-group/hosts/host-0",
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DedicatedHost: &DedicatedHost{
+						HostID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/hostGroups/my-host-group/hosts/host-1",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "validTest: azuremachine.spec.DedicatedHost is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DedicatedHost: &DedicatedHost{
+						HostGroupID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/hostGroups/my-host-group",
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DedicatedHost: &DedicatedHost{
+						HostGroupID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/hostGroups/my-host-group",
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalidTest: azuremachine.spec.SecurityProfile is immutable",
 			oldMachine: &AzureMachine{
@@ -871,9 +1442,67 @@ func TestAzureMachine_Default(t *testing.T) {
 	}
 }
 
+type fakeHourlyCostEstimator struct {
+	cost float64
+	err  error
+}
+
+func (f fakeHourlyCostEstimator) EstimateHourlyCost(_ context.Context, _ metav1.ObjectMeta, _ string, _ OSDisk, _ bool) (float64, error) {
+	return f.cost, f.err
+}
+
+func TestAzureMachine_DefaultEstimatedHourlyCost(t *testing.T) {
+	tests := []struct {
+		name           string
+		featureEnabled bool
+		costEstimator  HourlyCostEstimator
+		want           string
+	}{
+		{
+			name:           "cost estimation is disabled",
+			featureEnabled: false,
+			costEstimator:  fakeHourlyCostEstimator{cost: 0.192},
+		},
+		{
+			name:           "no cost estimator",
+			featureEnabled: true,
+		},
+		{
+			name:           "cost is estimated",
+			featureEnabled: true,
+			costEstimator:  fakeHourlyCostEstimator{cost: 0.192},
+			want:           "0.1920",
+		},
+		{
+			name:           "cost estimation fails",
+			featureEnabled: true,
+			costEstimator:  fakeHourlyCostEstimator{err: errors.New("timeout")},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.CostEstimation, tc.featureEnabled)()
+
+			machine := createMachineWithSSHPublicKey(validSSHPublicKey)
+			mw := &azureMachineWebhook{
+				Client:        mockDefaultClient{SubscriptionID: "test-subscription-id"},
+				CostEstimator: tc.costEstimator,
+			}
+			g.Expect(mw.Default(context.Background(), machine)).To(Succeed())
+			if tc.want != "" {
+				g.Expect(machine.Annotations).To(HaveKeyWithValue(EstimatedHourlyCostAnnotation, tc.want))
+			} else {
+				g.Expect(machine.Annotations).NotTo(HaveKey(EstimatedHourlyCostAnnotation))
+			}
+		})
+	}
+}
+
 func createMachineWithNetworkConfig(subnetName string, acceleratedNetworking *bool, interfaces []NetworkInterface) *AzureMachine {
 	return &AzureMachine{
 		Spec: AzureMachineSpec{
+			VMSize:                "Standard_D2s_v3",
 			SubnetName:            subnetName,
 			NetworkInterfaces:     interfaces,
 			AcceleratedNetworking: acceleratedNetworking,
@@ -896,6 +1525,7 @@ func createMachineWithSharedImage(subscriptionID, resourceGroup, name, gallery,
 
 	return &AzureMachine{
 		Spec: AzureMachineSpec{
+			VMSize:       "Standard_D2s_v3",
 			Image:        image,
 			SSHPublicKey: validSSHPublicKey,
 			OSDisk:       validOSDisk,
@@ -917,6 +1547,7 @@ func createMachineWithMarketPlaceImage(publisher, offer, sku, version string) *A
 
 	return &AzureMachine{
 		Spec: AzureMachineSpec{
+			VMSize:       "Standard_D2s_v3",
 			Image:        image,
 			SSHPublicKey: validSSHPublicKey,
 			OSDisk:       validOSDisk,
@@ -931,6 +1562,7 @@ func createMachineWithImageByID(imageID string) *AzureMachine {
 
 	return &AzureMachine{
 		Spec: AzureMachineSpec{
+			VMSize:       "Standard_D2s_v3",
 			Image:        image,
 			SSHPublicKey: validSSHPublicKey,
 			OSDisk:       validOSDisk,
@@ -938,9 +1570,18 @@ func createMachineWithImageByID(imageID string) *AzureMachine {
 	}
 }
 
+func createMachineWithSpotRestorePolicy() *AzureMachine {
+	machine := createMachineWithImageByID("ID123")
+	machine.Spec.SpotVMOptions = &SpotVMOptions{
+		RestorePolicy: &SpotRestorePolicy{Enabled: true},
+	}
+	return machine
+}
+
 func createMachineWithOsDiskCacheType(cacheType string) *AzureMachine {
 	machine := &AzureMachine{
 		Spec: AzureMachineSpec{
+			VMSize:       "Standard_D2s_v3",
 			SSHPublicKey: validSSHPublicKey,
 			OSDisk:       validOSDisk,
 		},
@@ -952,6 +1593,7 @@ func createMachineWithOsDiskCacheType(cacheType string) *AzureMachine {
 func createMachineWithSystemAssignedIdentityRoleName() *AzureMachine {
 	machine := &AzureMachine{
 		Spec: AzureMachineSpec{
+			VMSize:       "Standard_D2s_v3",
 			SSHPublicKey: validSSHPublicKey,
 			OSDisk:       validOSDisk,
 			Identity:     VMIdentitySystemAssigned,
@@ -968,6 +1610,7 @@ func createMachineWithSystemAssignedIdentityRoleName() *AzureMachine {
 func createMachineWithoutSystemAssignedIdentityRoleName() *AzureMachine {
 	machine := &AzureMachine{
 		Spec: AzureMachineSpec{
+			VMSize:       "Standard_D2s_v3",
 			SSHPublicKey: validSSHPublicKey,
 			OSDisk:       validOSDisk,
 			Identity:     VMIdentitySystemAssigned,
@@ -983,6 +1626,7 @@ func createMachineWithoutSystemAssignedIdentityRoleName() *AzureMachine {
 func createMachineWithoutRoleAssignmentName() *AzureMachine {
 	machine := &AzureMachine{
 		Spec: AzureMachineSpec{
+			VMSize:       "Standard_D2s_v3",
 			SSHPublicKey: validSSHPublicKey,
 			OSDisk:       validOSDisk,
 		},
@@ -993,6 +1637,7 @@ func createMachineWithoutRoleAssignmentName() *AzureMachine {
 func createMachineWithRoleAssignmentName() *AzureMachine {
 	machine := &AzureMachine{
 		Spec: AzureMachineSpec{
+			VMSize:             "Standard_D2s_v3",
 			SSHPublicKey:       validSSHPublicKey,
 			OSDisk:             validOSDisk,
 			RoleAssignmentName: "test-role-assignment",
@@ -1018,6 +1663,7 @@ func createMachineWithDiagnostics(diagnosticsType BootDiagnosticsStorageAccountT
 
 	return &AzureMachine{
 		Spec: AzureMachineSpec{
+			VMSize:       "Standard_D2s_v3",
 			SSHPublicKey: validSSHPublicKey,
 			OSDisk:       validOSDisk,
 			Diagnostics:  diagnostics,
@@ -1049,6 +1695,7 @@ func createMachineWithConfidentialCompute(securityEncryptionType SecurityEncrypt
 
 	return &AzureMachine{
 		Spec: AzureMachineSpec{
+			VMSize:          "Standard_D2s_v3",
 			SSHPublicKey:    validSSHPublicKey,
 			OSDisk:          osDisk,
 			SecurityProfile: securityProfile,
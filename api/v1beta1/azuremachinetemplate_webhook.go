@@ -80,7 +80,7 @@ func (r *AzureMachineTemplate) ValidateCreate(ctx context.Context, obj runtime.O
 	}
 
 	for i, networkInterface := range r.Spec.Template.Spec.NetworkInterfaces {
-		if networkInterface.PrivateIPConfigs < 1 {
+		if networkInterface.ID == "" && networkInterface.PrivateIPConfigs < 1 {
 			allErrs = append(allErrs, field.Invalid(field.NewPath("AzureMachineTemplate", "spec", "template", "spec", "networkInterfaces", "privateIPConfigs"), r.Spec.Template.Spec.NetworkInterfaces[i].PrivateIPConfigs, "networkInterface privateIPConfigs must be set to a minimum value of 1"))
 		}
 	}
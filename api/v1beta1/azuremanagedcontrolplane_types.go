@@ -191,6 +191,93 @@ type AzureManagedControlPlaneSpec struct {
 	// Immutable.
 	// +optional
 	HTTPProxyConfig *HTTPProxyConfig `json:"httpProxyConfig,omitempty"`
+
+	// WindowsProfile configures the Windows nodes of the cluster. It is required to add Windows node pools.
+	// It can't be added or removed after the cluster is created.
+	// +optional
+	WindowsProfile *ManagedControlPlaneWindowsProfile `json:"windowsProfile,omitempty"`
+
+	// OIDCIssuerProfile configures the OIDC issuer of the cluster, for example to use workload identity.
+	// The OIDC issuer can't be disabled once enabled.
+	// +optional
+	OIDCIssuerProfile *OIDCIssuerProfile `json:"oidcIssuerProfile,omitempty"`
+}
+
+// OIDCIssuerProfile is the OIDC issuer profile of an AKS cluster.
+// See also [AKS doc].
+//
+// [AKS doc]: https://learn.microsoft.com/azure/aks/use-oidc-issuer
+type OIDCIssuerProfile struct {
+	// Enabled enables the OIDC issuer of the cluster.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// OIDCIssuerProfileStatus is the OIDC issuer of an AKS cluster as reported by AKS.
+type OIDCIssuerProfileStatus struct {
+	// IssuerURL is the URL of the OIDC issuer of the cluster.
+	// +optional
+	IssuerURL *string `json:"issuerURL,omitempty"`
+}
+
+// ManagedControlPlaneLicenseType enumerates the license types of the Windows nodes of an AKS cluster.
+type ManagedControlPlaneLicenseType string
+
+const (
+	// ManagedControlPlaneLicenseTypeNone applies no additional licensing.
+	ManagedControlPlaneLicenseTypeNone ManagedControlPlaneLicenseType = "None"
+
+	// ManagedControlPlaneLicenseTypeWindowsServer enables Azure Hybrid User Benefits for the Windows nodes.
+	ManagedControlPlaneLicenseTypeWindowsServer ManagedControlPlaneLicenseType = "Windows_Server"
+)
+
+// WindowsAdminPasswordSecretKey is the key of the Windows administrator password in the Secret referenced by
+// ManagedControlPlaneWindowsProfile.AdminPasswordSecretRef.
+const WindowsAdminPasswordSecretKey = "password"
+
+// ManagedControlPlaneWindowsProfile is the profile of the Windows nodes of an AKS cluster.
+// See also [AKS doc].
+//
+// [AKS doc]: https://learn.microsoft.com/azure/aks/windows-faq
+type ManagedControlPlaneWindowsProfile struct {
+	// AdminUsername is the name of the administrator account of the Windows nodes.
+	// Immutable.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=20
+	AdminUsername string `json:"adminUsername"`
+
+	// AdminPasswordSecretRef references a Secret in the namespace of the AzureManagedControlPlane that holds the
+	// password of the administrator account under the "password" key.
+	// Changing the password in the Secret rotates the password of the Windows nodes.
+	AdminPasswordSecretRef corev1.LocalObjectReference `json:"adminPasswordSecretRef"`
+
+	// LicenseType is the license type of the Windows nodes. Windows_Server enables Azure Hybrid User Benefits.
+	// +kubebuilder:validation:Enum=None;Windows_Server
+	// +optional
+	LicenseType *ManagedControlPlaneLicenseType `json:"licenseType,omitempty"`
+
+	// GmsaProfile configures Group Managed Service Accounts for the Windows nodes.
+	// +optional
+	GmsaProfile *WindowsGmsaProfile `json:"gmsaProfile,omitempty"`
+}
+
+// WindowsGmsaProfile is the Group Managed Service Account profile of the Windows nodes of an AKS cluster.
+// See also [AKS doc].
+//
+// [AKS doc]: https://learn.microsoft.com/azure/aks/use-group-managed-service-accounts
+type WindowsGmsaProfile struct {
+	// Enabled enables Group Managed Service Accounts on the Windows nodes.
+	Enabled bool `json:"enabled"`
+
+	// DNSServer is the DNS server of the Active Directory domain.
+	// Leave it empty if the DNS server is configured in the virtual network of the cluster.
+	// +optional
+	DNSServer string `json:"dnsServer,omitempty"`
+
+	// RootDomainName is the root domain name of the Active Directory domain.
+	// Leave it empty if the DNS server is configured in the virtual network of the cluster.
+	// +optional
+	RootDomainName string `json:"rootDomainName,omitempty"`
 }
 
 // HTTPProxyConfig is the HTTP proxy configuration for the cluster.
@@ -349,6 +436,18 @@ type AzureManagedControlPlaneStatus struct {
 	// next reconciliation loop.
 	// +optional
 	LongRunningOperationStates Futures `json:"longRunningOperationStates,omitempty"`
+
+	// ControlPlaneIdentity is the identity used by the AKS control plane to manage cluster resources.
+	// +optional
+	ControlPlaneIdentity *ManagedClusterIdentityStatus `json:"controlPlaneIdentity,omitempty"`
+
+	// KubeletIdentity is the identity used by kubelet to authenticate to Azure, for example to pull images from ACR.
+	// +optional
+	KubeletIdentity *ManagedClusterIdentityStatus `json:"kubeletIdentity,omitempty"`
+
+	// OIDCIssuerProfile is the OIDC issuer of the cluster. It is set once the OIDC issuer is enabled.
+	// +optional
+	OIDCIssuerProfile *OIDCIssuerProfileStatus `json:"oidcIssuerProfile,omitempty"`
 }
 
 // AutoScalerProfile parameters to be applied to the cluster-autoscaler.
@@ -485,6 +584,21 @@ type Identity struct {
 	UserAssignedIdentityResourceID string `json:"userAssignedIdentityResourceID,omitempty"`
 }
 
+// ManagedClusterIdentityStatus describes a managed identity of an AKS cluster as reported by AKS.
+type ManagedClusterIdentityStatus struct {
+	// ResourceID is the ARM resource ID of the identity. It is empty for a system-assigned identity.
+	// +optional
+	ResourceID string `json:"resourceID,omitempty"`
+
+	// ClientID is the client ID of the identity. It is empty for a system-assigned control plane identity.
+	// +optional
+	ClientID string `json:"clientID,omitempty"`
+
+	// ObjectID is the object ID of the service principal of the identity, to be used as the principal of role assignments.
+	// +optional
+	ObjectID string `json:"objectID,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=azuremanagedcontrolplanes,scope=Namespaced,categories=cluster-api,shortName=amcp
 // +kubebuilder:storageversion
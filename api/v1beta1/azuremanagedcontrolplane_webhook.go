@@ -27,6 +27,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/blang/semver"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -49,9 +50,22 @@ var (
 	rScanInterval              = regexp.MustCompile(`^(\d+)s$`)
 )
 
+// aksUpgradesLookupTimeout bounds the lookup of the upgrade profile of an AKS cluster, so that a slow Azure API
+// doesn't make the admission request time out.
+const aksUpgradesLookupTimeout = 5 * time.Second
+
+// AKSUpgradesGetter looks up the Kubernetes versions the AKS cluster of an AzureManagedControlPlane can be upgraded to.
+type AKSUpgradesGetter interface {
+	// AKSUpgrades returns the Kubernetes version of the AKS cluster of the AzureManagedControlPlane and the versions
+	// it can be upgraded to.
+	AKSUpgrades(ctx context.Context, controlPlane *AzureManagedControlPlane) (current string, upgrades []string, err error)
+}
+
 // SetupAzureManagedControlPlaneWebhookWithManager sets up and registers the webhook with the manager.
-func SetupAzureManagedControlPlaneWebhookWithManager(mgr ctrl.Manager) error {
-	mw := &azureManagedControlPlaneWebhook{Client: mgr.GetClient()}
+// aksUpgrades is optional; when it is nil, version changes are only checked against the AKS upgrade rules and
+// unavailable versions are reported when reconciling the cluster.
+func SetupAzureManagedControlPlaneWebhookWithManager(mgr ctrl.Manager, aksUpgrades AKSUpgradesGetter) error {
+	mw := &azureManagedControlPlaneWebhook{Client: mgr.GetClient(), AKSUpgrades: aksUpgrades}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&AzureManagedControlPlane{}).
 		WithDefaulter(mw).
@@ -63,7 +77,8 @@ func SetupAzureManagedControlPlaneWebhookWithManager(mgr ctrl.Manager) error {
 
 // azureManagedControlPlaneWebhook implements a validating and defaulting webhook for AzureManagedControlPlane.
 type azureManagedControlPlaneWebhook struct {
-	Client client.Client
+	Client      client.Client
+	AKSUpgrades AKSUpgradesGetter
 }
 
 // Default implements webhook.Defaulter so a webhook will be registered for the type.
@@ -257,11 +272,85 @@ func (mw *azureManagedControlPlaneWebhook) ValidateUpdate(ctx context.Context, o
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := m.validateWindowsProfileUpdate(old); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := m.validateOIDCIssuerProfileUpdate(old); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	warnings, errs := mw.validateVersionUpdate(ctx, old, m)
+	allErrs = append(allErrs, errs...)
+
 	if len(allErrs) == 0 {
-		return nil, m.Validate(mw.Client)
+		return warnings, m.Validate(mw.Client)
+	}
+
+	return warnings, apierrors.NewInvalid(GroupVersion.WithKind("AzureManagedControlPlane").GroupKind(), m.Name, allErrs)
+}
+
+// validateVersionUpdate checks that a change of the Kubernetes version is an upgrade AKS supports: the version can't be
+// downgraded and minor versions can't be skipped. When the upgrade profile of the AKS cluster can be looked up, the
+// version must also be the version the cluster runs or one of its available upgrades. The upgrade profile check is
+// best effort: if the profile can't be looked up in time, e.g. because the cluster doesn't exist yet, the change is
+// admitted with a warning and AKS reports unavailable versions when the cluster is reconciled.
+func (mw *azureManagedControlPlaneWebhook) validateVersionUpdate(ctx context.Context, old, m *AzureManagedControlPlane) (admission.Warnings, field.ErrorList) {
+	if m.Spec.Version == old.Spec.Version {
+		return nil, nil
+	}
+
+	// Invalid versions are reported by validateVersion.
+	oldVersion, err := semver.ParseTolerant(old.Spec.Version)
+	if err != nil {
+		return nil, nil
+	}
+	newVersion, err := semver.ParseTolerant(m.Spec.Version)
+	if err != nil {
+		return nil, nil
+	}
+
+	var warnings admission.Warnings
+	var current string
+	var upgrades []string
+	lookedUp := false
+	if mw.AKSUpgrades != nil {
+		lookupCtx, cancel := context.WithTimeout(ctx, aksUpgradesLookupTimeout)
+		current, upgrades, err = mw.AKSUpgrades.AKSUpgrades(lookupCtx, old)
+		cancel()
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("unable to verify that Kubernetes %s is an available upgrade of AKS cluster %s: %v", m.Spec.Version, m.Name, err))
+		} else {
+			lookedUp = true
+			// The cluster may still run the new version, e.g. when an upgrade that hasn't been applied yet is reverted.
+			for _, version := range append([]string{current}, upgrades...) {
+				if v, err := semver.ParseTolerant(version); err == nil && v.Equals(newVersion) {
+					return nil, nil
+				}
+			}
+		}
 	}
 
-	return nil, apierrors.NewInvalid(GroupVersion.WithKind("AzureManagedControlPlane").GroupKind(), m.Name, allErrs)
+	fldPath := field.NewPath("Spec", "Version")
+	if newVersion.LT(oldVersion) {
+		return warnings, field.ErrorList{field.Invalid(fldPath, m.Spec.Version,
+			fmt.Sprintf("AKS does not support downgrading Kubernetes from %s to %s", old.Spec.Version, m.Spec.Version))}
+	}
+	if newVersion.Major == oldVersion.Major && newVersion.Minor > oldVersion.Minor+1 {
+		return warnings, field.ErrorList{field.Invalid(fldPath, m.Spec.Version,
+			fmt.Sprintf("AKS does not support skipping Kubernetes minor versions. Upgrade from %s to a v%d.%d version first", old.Spec.Version, oldVersion.Major, oldVersion.Minor+1))}
+	}
+
+	if !lookedUp {
+		return warnings, nil
+	}
+	if len(upgrades) == 0 {
+		return nil, field.ErrorList{field.Invalid(fldPath, m.Spec.Version,
+			fmt.Sprintf("AKS cluster %s running Kubernetes %s has no available upgrades", m.Name, current))}
+	}
+	return nil, field.ErrorList{field.Invalid(fldPath, m.Spec.Version,
+		fmt.Sprintf("Kubernetes %s is not an available upgrade of AKS cluster %s running Kubernetes %s. Available upgrades are %s",
+			strings.TrimPrefix(m.Spec.Version, "v"), m.Name, current, strings.Join(upgrades, ", ")))}
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
@@ -280,6 +369,7 @@ func (m *AzureManagedControlPlane) Validate(cli client.Client) error {
 		m.validateManagedClusterNetwork,
 		m.validateAutoScalerProfile,
 		m.validateIdentity,
+		m.validateWindowsProfile,
 	}
 
 	var errs []error
@@ -489,6 +579,46 @@ func (m *AzureManagedControlPlane) validateAPIServerAccessProfileUpdate(old *Azu
 	return allErrs
 }
 
+// validateWindowsProfileUpdate validates update to WindowsProfile.
+func (m *AzureManagedControlPlane) validateWindowsProfileUpdate(old *AzureManagedControlPlane) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if (old.Spec.WindowsProfile == nil) != (m.Spec.WindowsProfile == nil) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("Spec", "WindowsProfile"),
+				m.Spec.WindowsProfile, "field cannot be added or removed"),
+		)
+		return allErrs
+	}
+
+	if old.Spec.WindowsProfile != nil {
+		if err := webhookutils.ValidateImmutable(
+			field.NewPath("Spec", "WindowsProfile", "AdminUsername"),
+			old.Spec.WindowsProfile.AdminUsername,
+			m.Spec.WindowsProfile.AdminUsername); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+
+	return allErrs
+}
+
+// validateOIDCIssuerProfileUpdate validates update to OIDCIssuerProfile. AKS can't disable the OIDC issuer once it is
+// enabled.
+func (m *AzureManagedControlPlane) validateOIDCIssuerProfileUpdate(old *AzureManagedControlPlane) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if old.Spec.OIDCIssuerProfile != nil && ptr.Deref(old.Spec.OIDCIssuerProfile.Enabled, false) &&
+		(m.Spec.OIDCIssuerProfile == nil || !ptr.Deref(m.Spec.OIDCIssuerProfile.Enabled, false)) {
+		allErrs = append(allErrs,
+			field.Forbidden(field.NewPath("Spec", "OIDCIssuerProfile", "Enabled"),
+				"cannot be disabled once enabled"),
+		)
+	}
+
+	return allErrs
+}
+
 // validateVirtualNetworkUpdate validates update to VirtualNetwork.
 func (m *AzureManagedControlPlane) validateVirtualNetworkUpdate(old *AzureManagedControlPlane) field.ErrorList {
 	var allErrs field.ErrorList
@@ -713,3 +843,50 @@ func (m *AzureManagedControlPlane) validateIdentity(_ client.Client) error {
 
 	return nil
 }
+
+// disallowedWindowsAdminUsernames are the administrator names that AKS rejects for Windows nodes.
+var disallowedWindowsAdminUsernames = []string{
+	"administrator", "admin", "user", "user1", "test", "user2", "test1", "user3", "admin1", "1", "123", "a",
+	"actuser", "adm", "admin2", "aspnet", "backup", "console", "david", "guest", "john", "owner", "root",
+	"server", "sql", "support", "support_388945a0", "sys", "test2", "test3", "user4", "user5",
+}
+
+// validateWindowsProfile validates a WindowsProfile.
+func (m *AzureManagedControlPlane) validateWindowsProfile(_ client.Client) error {
+	if m.Spec.WindowsProfile == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("Spec", "WindowsProfile")
+
+	username := m.Spec.WindowsProfile.AdminUsername
+	if strings.HasSuffix(username, ".") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("AdminUsername"), username, "cannot end with a period"))
+	}
+	for _, disallowed := range disallowedWindowsAdminUsernames {
+		if strings.EqualFold(username, disallowed) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("AdminUsername"), username, "is not allowed by AKS"))
+			break
+		}
+	}
+
+	if m.Spec.WindowsProfile.AdminPasswordSecretRef.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("AdminPasswordSecretRef", "Name"), "must reference the Secret holding the administrator password"))
+	}
+
+	if gmsa := m.Spec.WindowsProfile.GmsaProfile; gmsa != nil {
+		if (gmsa.DNSServer == "") != (gmsa.RootDomainName == "") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("GmsaProfile"), gmsa, "DNSServer and RootDomainName must be set together"))
+		}
+		if !gmsa.Enabled && gmsa.DNSServer != "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("GmsaProfile", "DNSServer"), gmsa.DNSServer, "cannot be set if Enabled is false"))
+		}
+	}
+
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+
+	return nil
+}
@@ -18,9 +18,11 @@ package v1beta1
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/ptr"
@@ -662,6 +664,85 @@ func TestValidatingWebhook(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "Testing valid WindowsProfile",
+			amcp: AzureManagedControlPlane{
+				ObjectMeta: getAMCPMetaData(),
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.24.1",
+					WindowsProfile: &ManagedControlPlaneWindowsProfile{
+						AdminUsername:          "azureuser",
+						AdminPasswordSecretRef: corev1.LocalObjectReference{Name: "windows-password"},
+						LicenseType:            ptr.To(ManagedControlPlaneLicenseTypeWindowsServer),
+						GmsaProfile: &WindowsGmsaProfile{
+							Enabled:        true,
+							DNSServer:      "10.0.0.4",
+							RootDomainName: "contoso.com",
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Testing invalid WindowsProfile: disallowed AdminUsername",
+			amcp: AzureManagedControlPlane{
+				ObjectMeta: getAMCPMetaData(),
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.24.1",
+					WindowsProfile: &ManagedControlPlaneWindowsProfile{
+						AdminUsername:          "Administrator",
+						AdminPasswordSecretRef: corev1.LocalObjectReference{Name: "windows-password"},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Testing invalid WindowsProfile: AdminUsername ending with a period",
+			amcp: AzureManagedControlPlane{
+				ObjectMeta: getAMCPMetaData(),
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.24.1",
+					WindowsProfile: &ManagedControlPlaneWindowsProfile{
+						AdminUsername:          "azureuser.",
+						AdminPasswordSecretRef: corev1.LocalObjectReference{Name: "windows-password"},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Testing invalid WindowsProfile: missing AdminPasswordSecretRef",
+			amcp: AzureManagedControlPlane{
+				ObjectMeta: getAMCPMetaData(),
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.24.1",
+					WindowsProfile: &ManagedControlPlaneWindowsProfile{
+						AdminUsername: "azureuser",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Testing invalid WindowsProfile: GmsaProfile with DNSServer and no RootDomainName",
+			amcp: AzureManagedControlPlane{
+				ObjectMeta: getAMCPMetaData(),
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.24.1",
+					WindowsProfile: &ManagedControlPlaneWindowsProfile{
+						AdminUsername:          "azureuser",
+						AdminPasswordSecretRef: corev1.LocalObjectReference{Name: "windows-password"},
+						GmsaProfile: &WindowsGmsaProfile{
+							Enabled:   true,
+							DNSServer: "10.0.0.4",
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1417,6 +1498,67 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:    "AzureManagedControlPlane WindowsProfile can't be added",
+			oldAMCP: windowsAzureManagedControlPlane(nil),
+			amcp: windowsAzureManagedControlPlane(&ManagedControlPlaneWindowsProfile{
+				AdminUsername:          "azureuser",
+				AdminPasswordSecretRef: corev1.LocalObjectReference{Name: "windows-password"},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane WindowsProfile can't be removed",
+			oldAMCP: windowsAzureManagedControlPlane(&ManagedControlPlaneWindowsProfile{
+				AdminUsername:          "azureuser",
+				AdminPasswordSecretRef: corev1.LocalObjectReference{Name: "windows-password"},
+			}),
+			amcp:    windowsAzureManagedControlPlane(nil),
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane WindowsProfile AdminUsername is immutable",
+			oldAMCP: windowsAzureManagedControlPlane(&ManagedControlPlaneWindowsProfile{
+				AdminUsername:          "azureuser",
+				AdminPasswordSecretRef: corev1.LocalObjectReference{Name: "windows-password"},
+			}),
+			amcp: windowsAzureManagedControlPlane(&ManagedControlPlaneWindowsProfile{
+				AdminUsername:          "otheruser",
+				AdminPasswordSecretRef: corev1.LocalObjectReference{Name: "windows-password"},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane WindowsProfile password Secret and license type can be changed",
+			oldAMCP: windowsAzureManagedControlPlane(&ManagedControlPlaneWindowsProfile{
+				AdminUsername:          "azureuser",
+				AdminPasswordSecretRef: corev1.LocalObjectReference{Name: "windows-password"},
+			}),
+			amcp: windowsAzureManagedControlPlane(&ManagedControlPlaneWindowsProfile{
+				AdminUsername:          "azureuser",
+				AdminPasswordSecretRef: corev1.LocalObjectReference{Name: "windows-password-2"},
+				LicenseType:            ptr.To(ManagedControlPlaneLicenseTypeWindowsServer),
+			}),
+			wantErr: false,
+		},
+		{
+			name:    "AzureManagedControlPlane OIDCIssuerProfile can be enabled",
+			oldAMCP: oidcIssuerAzureManagedControlPlane(nil),
+			amcp:    oidcIssuerAzureManagedControlPlane(&OIDCIssuerProfile{Enabled: ptr.To(true)}),
+			wantErr: false,
+		},
+		{
+			name:    "AzureManagedControlPlane OIDCIssuerProfile can't be disabled",
+			oldAMCP: oidcIssuerAzureManagedControlPlane(&OIDCIssuerProfile{Enabled: ptr.To(true)}),
+			amcp:    oidcIssuerAzureManagedControlPlane(&OIDCIssuerProfile{Enabled: ptr.To(false)}),
+			wantErr: true,
+		},
+		{
+			name:    "AzureManagedControlPlane OIDCIssuerProfile can't be removed once enabled",
+			oldAMCP: oidcIssuerAzureManagedControlPlane(&OIDCIssuerProfile{Enabled: ptr.To(true)}),
+			amcp:    oidcIssuerAzureManagedControlPlane(nil),
+			wantErr: true,
+		},
 	}
 	client := mockClient{ReturnError: false}
 	for _, tc := range tests {
@@ -1434,6 +1576,136 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 	}
 }
 
+type fakeAKSUpgradesGetter struct {
+	current  string
+	upgrades []string
+	err      error
+}
+
+func (f fakeAKSUpgradesGetter) AKSUpgrades(_ context.Context, _ *AzureManagedControlPlane) (string, []string, error) {
+	return f.current, f.upgrades, f.err
+}
+
+func TestAzureManagedControlPlane_ValidateVersionUpdate(t *testing.T) {
+	tests := []struct {
+		name         string
+		oldVersion   string
+		newVersion   string
+		aksUpgrades  AKSUpgradesGetter
+		wantErr      string
+		wantWarnings bool
+	}{
+		{
+			name:        "version unchanged",
+			oldVersion:  "v1.26.6",
+			newVersion:  "v1.26.6",
+			aksUpgrades: fakeAKSUpgradesGetter{err: errors.New("should not be called")},
+		},
+		{
+			name:       "patch upgrade without upgrade profile lookup",
+			oldVersion: "v1.26.6",
+			newVersion: "v1.26.10",
+		},
+		{
+			name:       "minor upgrade without upgrade profile lookup",
+			oldVersion: "v1.26.6",
+			newVersion: "v1.27.3",
+		},
+		{
+			name:       "downgrade",
+			oldVersion: "v1.27.3",
+			newVersion: "v1.26.6",
+			wantErr:    "AKS does not support downgrading Kubernetes from v1.27.3 to v1.26.6",
+		},
+		{
+			name:       "skipped minor version",
+			oldVersion: "v1.26.6",
+			newVersion: "v1.28.0",
+			wantErr:    "AKS does not support skipping Kubernetes minor versions. Upgrade from v1.26.6 to a v1.27 version first",
+		},
+		{
+			name:         "upgrade profile lookup fails",
+			oldVersion:   "v1.26.6",
+			newVersion:   "v1.27.3",
+			aksUpgrades:  fakeAKSUpgradesGetter{err: errors.New("no credentials")},
+			wantWarnings: true,
+		},
+		{
+			name:         "upgrade profile lookup fails on a downgrade",
+			oldVersion:   "v1.27.3",
+			newVersion:   "v1.26.6",
+			aksUpgrades:  fakeAKSUpgradesGetter{err: errors.New("no credentials")},
+			wantErr:      "AKS does not support downgrading Kubernetes from v1.27.3 to v1.26.6",
+			wantWarnings: true,
+		},
+		{
+			name:        "available upgrade",
+			oldVersion:  "v1.26.6",
+			newVersion:  "v1.27.3",
+			aksUpgrades: fakeAKSUpgradesGetter{current: "1.26.6", upgrades: []string{"1.26.10", "1.27.3"}},
+		},
+		{
+			name:        "unavailable upgrade",
+			oldVersion:  "v1.26.6",
+			newVersion:  "v1.27.1",
+			aksUpgrades: fakeAKSUpgradesGetter{current: "1.26.6", upgrades: []string{"1.26.10", "1.27.3"}},
+			wantErr:     "Kubernetes 1.27.1 is not an available upgrade of AKS cluster test-AMCP running Kubernetes 1.26.6. Available upgrades are 1.26.10, 1.27.3",
+		},
+		{
+			name:        "no available upgrades",
+			oldVersion:  "v1.26.6",
+			newVersion:  "v1.27.3",
+			aksUpgrades: fakeAKSUpgradesGetter{current: "1.26.6"},
+			wantErr:     "AKS cluster test-AMCP running Kubernetes 1.26.6 has no available upgrades",
+		},
+		{
+			name:        "revert an upgrade that hasn't been applied yet",
+			oldVersion:  "v1.27.3",
+			newVersion:  "v1.26.6",
+			aksUpgrades: fakeAKSUpgradesGetter{current: "1.26.6", upgrades: []string{"1.27.3"}},
+		},
+		{
+			name:        "downgrade with upgrade profile lookup",
+			oldVersion:  "v1.27.3",
+			newVersion:  "v1.26.6",
+			aksUpgrades: fakeAKSUpgradesGetter{current: "1.27.3"},
+			wantErr:     "AKS does not support downgrading Kubernetes from v1.27.3 to v1.26.6",
+		},
+		{
+			name:        "cluster already runs the new version",
+			oldVersion:  "v1.26.6",
+			newVersion:  "v1.27.3",
+			aksUpgrades: fakeAKSUpgradesGetter{current: "1.27.3"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			oldAMCP := getKnownValidAzureManagedControlPlane()
+			oldAMCP.Spec.Version = tc.oldVersion
+			amcp := getKnownValidAzureManagedControlPlane()
+			amcp.Spec.Version = tc.newVersion
+
+			mcpw := &azureManagedControlPlaneWebhook{
+				Client:      mockClient{ReturnError: false},
+				AKSUpgrades: tc.aksUpgrades,
+			}
+			warnings, err := mcpw.ValidateUpdate(context.Background(), oldAMCP, amcp)
+			if tc.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tc.wantWarnings {
+				g.Expect(warnings).NotTo(BeEmpty())
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
 func createAzureManagedControlPlane(serviceIP, version, sshKey string) *AzureManagedControlPlane {
 	return &AzureManagedControlPlane{
 		ObjectMeta: getAMCPMetaData(),
@@ -1445,6 +1717,18 @@ func createAzureManagedControlPlane(serviceIP, version, sshKey string) *AzureMan
 	}
 }
 
+func windowsAzureManagedControlPlane(windowsProfile *ManagedControlPlaneWindowsProfile) *AzureManagedControlPlane {
+	amcp := createAzureManagedControlPlane("192.168.0.10", "v1.18.0", "")
+	amcp.Spec.WindowsProfile = windowsProfile
+	return amcp
+}
+
+func oidcIssuerAzureManagedControlPlane(oidcIssuerProfile *OIDCIssuerProfile) *AzureManagedControlPlane {
+	amcp := createAzureManagedControlPlane("192.168.0.10", "v1.18.0", "")
+	amcp.Spec.OIDCIssuerProfile = oidcIssuerProfile
+	return amcp
+}
+
 func getKnownValidAzureManagedControlPlane() *AzureManagedControlPlane {
 	return &AzureManagedControlPlane{
 		ObjectMeta: getAMCPMetaData(),
@@ -40,6 +40,17 @@ const (
 // NodePoolMode enumerates the values for agent pool mode.
 type NodePoolMode string
 
+// AgentPoolPowerState enumerates the values for the power state of an agent pool.
+type AgentPoolPowerState string
+
+const (
+	// AgentPoolPowerStateRunning means the nodes of the agent pool are running.
+	AgentPoolPowerStateRunning AgentPoolPowerState = "Running"
+
+	// AgentPoolPowerStateStopped means the nodes of the agent pool are stopped and don't accrue compute charges.
+	AgentPoolPowerStateStopped AgentPoolPowerState = "Stopped"
+)
+
 // CPUManagerPolicy enumerates the values for KubeletConfig.CPUManagerPolicy.
 type CPUManagerPolicy string
 
@@ -63,6 +74,16 @@ const (
 	KubeletDiskTypeTemporary KubeletDiskType = "Temporary"
 )
 
+// OsDiskType enumerates the values for the agent pool's OsDiskType.
+type OsDiskType string
+
+const (
+	// OsDiskTypeEphemeral places the OS disk on the VM's cache disk.
+	OsDiskTypeEphemeral OsDiskType = "Ephemeral"
+	// OsDiskTypeManaged uses a managed disk for the OS disk.
+	OsDiskTypeManaged OsDiskType = "Managed"
+)
+
 const (
 	// TopologyManagerPolicyNone ...
 	TopologyManagerPolicyNone TopologyManagerPolicy = "none"
@@ -481,6 +502,7 @@ type AzureManagedMachinePoolSpec struct {
 	MaxPods *int32 `json:"maxPods,omitempty"`
 
 	// OsDiskType specifies the OS disk type for each node in the pool. Allowed values are 'Ephemeral' and 'Managed' (default).
+	// An Ephemeral OS disk is placed on the cache disk or the temp disk of the VM, so the VM size must support ephemeral OS disks and, if osDiskSizeGB is set, have a cache disk or a temp disk at least that large.
 	// Immutable.
 	// See also [AKS doc].
 	//
@@ -515,6 +537,34 @@ type AzureManagedMachinePoolSpec struct {
 	// +optional
 	NodePublicIPPrefixID *string `json:"nodePublicIPPrefixID,omitempty"`
 
+	// ProximityPlacementGroupID specifies the resource ID of the proximity placement group the nodes of the pool are placed in,
+	// to reduce the network latency between them. The proximity placement group must be in the same location as the cluster.
+	// Immutable.
+	// See also [AKS doc].
+	//
+	// [AKS doc]: https://learn.microsoft.com/azure/aks/reduce-latency-ppg
+	// +optional
+	ProximityPlacementGroupID *string `json:"proximityPlacementGroupID,omitempty"`
+
+	// CapacityReservationGroupID specifies the resource ID of the capacity reservation group the nodes of the pool consume
+	// reserved capacity from. The capacity reservation group must be in the same location as the cluster, and the identity
+	// of the cluster must be allowed to use it.
+	// Immutable.
+	// See also [AKS doc].
+	//
+	// [AKS doc]: https://learn.microsoft.com/azure/aks/manage-node-pools#associate-capacity-reservation-groups-to-node-pools
+	// +optional
+	CapacityReservationGroupID *string `json:"capacityReservationGroupID,omitempty"`
+
+	// HostGroupID specifies the resource ID of the dedicated host group the nodes of the pool are placed on. The host group
+	// must have automatic placement enabled, and the identity of the cluster must be allowed to use it.
+	// Immutable.
+	// See also [AKS doc].
+	//
+	// [AKS doc]: https://learn.microsoft.com/azure/aks/use-azure-dedicated-hosts
+	// +optional
+	HostGroupID *string `json:"hostGroupID,omitempty"`
+
 	// ScaleSetPriority specifies the ScaleSetPriority value. Default to Regular. Possible values include: 'Regular', 'Spot'
 	// Immutable.
 	// +kubebuilder:validation:Enum=Regular;Spot
@@ -527,6 +577,16 @@ type AzureManagedMachinePoolSpec struct {
 	// +optional
 	ScaleDownMode *string `json:"scaleDownMode,omitempty"`
 
+	// PowerState specifies whether the nodes of the pool are running or stopped. Possible values include: 'Running', 'Stopped'.
+	// A stopped pool keeps its nodes and configuration but doesn't accrue compute charges. System pools can't be stopped.
+	// When unset, the power state of the pool isn't changed.
+	// See also [AKS doc].
+	//
+	// [AKS doc]: https://learn.microsoft.com/azure/aks/start-stop-nodepools
+	// +kubebuilder:validation:Enum=Running;Stopped
+	// +optional
+	PowerState *AgentPoolPowerState `json:"powerState,omitempty"`
+
 	// SpotMaxPrice defines max price to pay for spot instance. Possible values are any decimal value greater than zero or -1.
 	// If you set the max price to be -1, the VM won't be evicted based on price. The price for the VM will be the current price
 	// for spot or the price for a standard VM, which ever is less, as long as there's capacity and quota available.
@@ -23,6 +23,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -43,9 +44,27 @@ import (
 
 var validNodePublicPrefixID = regexp.MustCompile(`(?i)^/?subscriptions/[0-9a-f]{8}-([0-9a-f]{4}-){3}[0-9a-f]{12}/resourcegroups/[^/]+/providers/microsoft\.network/publicipprefixes/[^/]+$`)
 
+var validProximityPlacementGroupID = regexp.MustCompile(`(?i)^/?subscriptions/[0-9a-f]{8}-([0-9a-f]{4}-){3}[0-9a-f]{12}/resourcegroups/[^/]+/providers/microsoft\.compute/proximityplacementgroups/[^/]+$`)
+
+var validHostGroupID = regexp.MustCompile(`(?i)^/?subscriptions/[0-9a-f]{8}-([0-9a-f]{4}-){3}[0-9a-f]{12}/resourcegroups/[^/]+/providers/microsoft\.compute/hostgroups/[^/]+$`)
+
+var validCapacityReservationGroupID = regexp.MustCompile(`(?i)^/?subscriptions/[0-9a-f]{8}-([0-9a-f]{4}-){3}[0-9a-f]{12}/resourcegroups/[^/]+/providers/microsoft\.compute/capacityreservationgroups/[^/]+$`)
+
+// ephemeralOSDiskLookupTimeout bounds the lookup of the ephemeral OS disk support of a VM size, so that a slow Azure
+// API doesn't make the admission request time out.
+const ephemeralOSDiskLookupTimeout = 5 * time.Second
+
+// EphemeralOSDiskGetter looks up the ephemeral OS disk support of the VM size of an AzureManagedMachinePool.
+type EphemeralOSDiskGetter interface {
+	// EphemeralOSDisk returns whether the VM size of the AzureManagedMachinePool supports ephemeral OS disks, and the
+	// sizes in GB of its cache and temp disks that can hold one, which are 0 when the disk can't hold one.
+	EphemeralOSDisk(ctx context.Context, pool *AzureManagedMachinePool) (supported bool, cacheDiskGB, tempDiskGB int64, err error)
+}
+
 // SetupAzureManagedMachinePoolWebhookWithManager sets up and registers the webhook with the manager.
-func SetupAzureManagedMachinePoolWebhookWithManager(mgr ctrl.Manager) error {
-	mw := &azureManagedMachinePoolWebhook{Client: mgr.GetClient()}
+// ephemeralOSDisk is optional; when it is nil, ephemeral OS disk support is only checked by AKS.
+func SetupAzureManagedMachinePoolWebhookWithManager(mgr ctrl.Manager, ephemeralOSDisk EphemeralOSDiskGetter) error {
+	mw := &azureManagedMachinePoolWebhook{Client: mgr.GetClient(), EphemeralOSDisk: ephemeralOSDisk}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&AzureManagedMachinePool{}).
 		WithDefaulter(mw).
@@ -57,7 +76,8 @@ func SetupAzureManagedMachinePoolWebhookWithManager(mgr ctrl.Manager) error {
 
 // azureManagedMachinePoolWebhook implements a validating and defaulting webhook for AzureManagedMachinePool.
 type azureManagedMachinePoolWebhook struct {
-	Client client.Client
+	Client          client.Client
+	EphemeralOSDisk EphemeralOSDiskGetter
 }
 
 // Default implements webhook.Defaulter so a webhook will be registered for the type.
@@ -105,6 +125,10 @@ func (mw *azureManagedMachinePoolWebhook) ValidateCreate(ctx context.Context, ob
 		m.validateNodeLabels,
 		m.validateNodePublicIPPrefixID,
 		m.validateEnableNodePublicIP,
+		m.validateProximityPlacementGroupID,
+		m.validateCapacityReservationGroupID,
+		m.validateHostGroupID,
+		m.validatePowerState,
 		m.validateKubeletConfig,
 		m.validateLinuxOSConfig,
 		m.validateSubnetName,
@@ -117,7 +141,43 @@ func (mw *azureManagedMachinePoolWebhook) ValidateCreate(ctx context.Context, ob
 		}
 	}
 
-	return nil, kerrors.NewAggregate(errs)
+	warnings, err := mw.validateEphemeralOSDisk(ctx, m)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return warnings, kerrors.NewAggregate(errs)
+}
+
+// validateEphemeralOSDisk checks that the VM size of an AzureManagedMachinePool with an ephemeral OS disk supports it,
+// and that the OS disk fits on the cache disk or on the temp disk of the VM size, where AKS can place it.
+// The check is best effort: if the VM size can't be looked up in time, the pool is admitted with a warning and AKS
+// reports the problem when the node pool is created.
+func (mw *azureManagedMachinePoolWebhook) validateEphemeralOSDisk(ctx context.Context, m *AzureManagedMachinePool) (admission.Warnings, error) {
+	if mw.EphemeralOSDisk == nil || ptr.Deref(m.Spec.OsDiskType, "") != string(OsDiskTypeEphemeral) {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ephemeralOSDiskLookupTimeout)
+	defer cancel()
+	supported, cacheDiskGB, tempDiskGB, err := mw.EphemeralOSDisk.EphemeralOSDisk(ctx, m)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("unable to verify that VM size %s supports ephemeral OS disks: %v", m.Spec.SKU, err)}, nil
+	}
+
+	if !supported {
+		return nil, field.Invalid(field.NewPath("Spec", "OsDiskType"), *m.Spec.OsDiskType,
+			fmt.Sprintf("VM size %s does not support ephemeral OS disks. Select a different VM size or set osDiskType to %s", m.Spec.SKU, OsDiskTypeManaged))
+	}
+
+	// Without osDiskSizeGB, AKS sizes the OS disk to fit.
+	osDiskSizeGB := int64(ptr.Deref(m.Spec.OSDiskSizeGB, 0))
+	if osDiskSizeGB > 0 && osDiskSizeGB > cacheDiskGB && osDiskSizeGB > tempDiskGB {
+		return nil, field.Invalid(field.NewPath("Spec", "OSDiskSizeGB"), osDiskSizeGB,
+			fmt.Sprintf("the ephemeral OS disk fits neither the %d GB cache disk nor the %d GB temp disk of VM size %s. Reduce osDiskSizeGB, select a VM size with a larger cache or temp disk, or set osDiskType to %s",
+				cacheDiskGB, tempDiskGB, m.Spec.SKU, OsDiskTypeManaged))
+	}
+	return nil, nil
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -175,10 +235,11 @@ func (mw *azureManagedMachinePoolWebhook) ValidateUpdate(ctx context.Context, ol
 		allErrs = append(allErrs, err)
 	}
 
+	// AKS creates node pools without FIPS when enableFIPS is unset, so it can't be enabled afterwards either.
 	if err := webhookutils.ValidateImmutable(
 		field.NewPath("Spec", "EnableFIPS"),
-		old.Spec.EnableFIPS,
-		m.Spec.EnableFIPS); err != nil && old.Spec.EnableFIPS != nil {
+		ptr.Deref(old.Spec.EnableFIPS, false),
+		ptr.Deref(m.Spec.EnableFIPS, false)); err != nil {
 		allErrs = append(allErrs, err)
 	}
 
@@ -201,6 +262,14 @@ func (mw *azureManagedMachinePoolWebhook) ValidateUpdate(ctx context.Context, ol
 				"field is immutable"))
 	}
 
+	if err := m.validatePowerState(); err != nil {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "PowerState"),
+				m.Spec.PowerState,
+				err.Error()))
+	}
+
 	if m.Spec.Mode != string(NodePoolModeSystem) && old.Spec.Mode == string(NodePoolModeSystem) {
 		// validate for last system node pool
 		if err := m.validateLastSystemNodePool(mw.Client); err != nil {
@@ -249,6 +318,24 @@ func (mw *azureManagedMachinePoolWebhook) ValidateUpdate(ctx context.Context, ol
 		m.Spec.NodePublicIPPrefixID); err != nil {
 		allErrs = append(allErrs, err)
 	}
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "ProximityPlacementGroupID"),
+		old.Spec.ProximityPlacementGroupID,
+		m.Spec.ProximityPlacementGroupID); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "CapacityReservationGroupID"),
+		old.Spec.CapacityReservationGroupID,
+		m.Spec.CapacityReservationGroupID); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "HostGroupID"),
+		old.Spec.HostGroupID,
+		m.Spec.HostGroupID); err != nil {
+		allErrs = append(allErrs, err)
+	}
 
 	if err := webhookutils.ValidateImmutable(
 		field.NewPath("Spec", "KubeletConfig"),
@@ -408,6 +495,46 @@ func (m *AzureManagedMachinePool) validateEnableNodePublicIP() error {
 	return nil
 }
 
+func (m *AzureManagedMachinePool) validateProximityPlacementGroupID() error {
+	if m.Spec.ProximityPlacementGroupID != nil && !validProximityPlacementGroupID.MatchString(*m.Spec.ProximityPlacementGroupID) {
+		return field.Invalid(
+			field.NewPath("Spec", "ProximityPlacementGroupID"),
+			m.Spec.ProximityPlacementGroupID,
+			fmt.Sprintf("resource ID must match %q", validProximityPlacementGroupID.String()))
+	}
+	return nil
+}
+
+func (m *AzureManagedMachinePool) validateCapacityReservationGroupID() error {
+	if m.Spec.CapacityReservationGroupID != nil && !validCapacityReservationGroupID.MatchString(*m.Spec.CapacityReservationGroupID) {
+		return field.Invalid(
+			field.NewPath("Spec", "CapacityReservationGroupID"),
+			m.Spec.CapacityReservationGroupID,
+			fmt.Sprintf("resource ID must match %q", validCapacityReservationGroupID.String()))
+	}
+	return nil
+}
+
+func (m *AzureManagedMachinePool) validateHostGroupID() error {
+	if m.Spec.HostGroupID != nil && !validHostGroupID.MatchString(*m.Spec.HostGroupID) {
+		return field.Invalid(
+			field.NewPath("Spec", "HostGroupID"),
+			m.Spec.HostGroupID,
+			fmt.Sprintf("resource ID must match %q", validHostGroupID.String()))
+	}
+	return nil
+}
+
+func (m *AzureManagedMachinePool) validatePowerState() error {
+	if ptr.Deref(m.Spec.PowerState, "") == AgentPoolPowerStateStopped && m.Spec.Mode == string(NodePoolModeSystem) {
+		return field.Invalid(
+			field.NewPath("Spec", "PowerState"),
+			m.Spec.PowerState,
+			"System node pools can't be stopped, change the mode of the node pool to User first")
+	}
+	return nil
+}
+
 func (m *AzureManagedMachinePool) validateSubnetName() error {
 	if m.Spec.SubnetName != nil {
 		subnetRegex := "^[a-zA-Z0-9][a-zA-Z0-9-]{0,78}[a-zA-Z0-9]$"
@@ -20,8 +20,9 @@ import (
 	"context"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2022-03-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/preview/containerservice/mgmt/2022-03-02-preview/containerservice"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilfeature "k8s.io/component-base/featuregate/testing"
@@ -462,6 +463,79 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "ProximityPlacementGroupID is immutable",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					ProximityPlacementGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/ppg-test/providers/Microsoft.Compute/proximityPlacementGroups/my-ppg-2"),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					ProximityPlacementGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/ppg-test/providers/Microsoft.Compute/proximityPlacementGroups/my-ppg"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "CapacityReservationGroupID is immutable",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					CapacityReservationGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/crg-test/providers/Microsoft.Compute/capacityReservationGroups/my-crg-2"),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					CapacityReservationGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/crg-test/providers/Microsoft.Compute/capacityReservationGroups/my-crg"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "HostGroupID is immutable",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					HostGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/hg-test/providers/Microsoft.Compute/hostGroups/my-host-group-2"),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					HostGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/hg-test/providers/Microsoft.Compute/hostGroups/my-host-group"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "User pool can be stopped",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:       "User",
+					PowerState: ptr.To(AgentPoolPowerStateStopped),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "User",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "System pool can't be stopped",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:       "System",
+					PowerState: ptr.To(AgentPoolPowerStateStopped),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:       "System",
+					PowerState: ptr.To(AgentPoolPowerStateRunning),
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "NodeTaints are mutable",
 			new: &AzureManagedMachinePool{
@@ -599,6 +673,30 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Cannot enable FIPS on an existing node pool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					EnableFIPS: ptr.To(true),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Can set enableFIPS to false if it was unset",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					EnableFIPS: ptr.To(false),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{},
+			},
+			wantErr: false,
+		},
 	}
 	var client client.Client
 	for _, tc := range tests {
@@ -929,6 +1027,84 @@ func TestAzureManagedMachinePool_ValidateCreate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "pool with invalid proximity placement group",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					ProximityPlacementGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/ppg-test/providers/Microsoft.Compute/hostGroups/my-host-group"),
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "stopped System pool",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:       "System",
+					PowerState: ptr.To(AgentPoolPowerStateStopped),
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "stopped User pool",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:       "User",
+					PowerState: ptr.To(AgentPoolPowerStateStopped),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "pool with proximity placement group ok",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					ProximityPlacementGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/ppg-test/providers/Microsoft.Compute/proximityPlacementGroups/my-ppg"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "pool with invalid capacity reservation group",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					CapacityReservationGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/crg-test/providers/Microsoft.Compute/hostGroups/my-host-group"),
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "pool with capacity reservation group ok",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					CapacityReservationGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/crg-test/providers/Microsoft.Compute/capacityReservationGroups/my-crg"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "pool with invalid host group",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					HostGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/hg-test/providers/Microsoft.Compute/proximityPlacementGroups/my-ppg"),
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "pool with host group ok",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					HostGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/hg-test/providers/Microsoft.Compute/hostGroups/my-host-group"),
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "KubeletConfig CPUCfsQuotaPeriod needs 'ms' suffix",
 			ammp: &AzureManagedMachinePool{
@@ -1203,6 +1379,98 @@ func TestAzureManagedMachinePool_ValidateCreateFailure(t *testing.T) {
 	}
 }
 
+type fakeEphemeralOSDiskGetter struct {
+	supported   bool
+	cacheDiskGB int64
+	tempDiskGB  int64
+	err         error
+}
+
+func (f fakeEphemeralOSDiskGetter) EphemeralOSDisk(_ context.Context, _ *AzureManagedMachinePool) (bool, int64, int64, error) {
+	return f.supported, f.cacheDiskGB, f.tempDiskGB, f.err
+}
+
+func TestAzureManagedMachinePool_ValidateCreateEphemeralOSDisk(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, true)()
+	tests := []struct {
+		name            string
+		ephemeralOSDisk EphemeralOSDiskGetter
+		osDiskType      OsDiskType
+		osDiskSizeGB    *int32
+		wantErr         string
+		wantWarnings    bool
+	}{
+		{
+			name:            "managed OS disk",
+			ephemeralOSDisk: fakeEphemeralOSDiskGetter{err: errors.New("should not be called")},
+			osDiskType:      OsDiskTypeManaged,
+		},
+		{
+			name:       "no VM size lookup",
+			osDiskType: OsDiskTypeEphemeral,
+		},
+		{
+			name:            "VM size lookup fails",
+			ephemeralOSDisk: fakeEphemeralOSDiskGetter{err: errors.New("no credentials")},
+			osDiskType:      OsDiskTypeEphemeral,
+			osDiskSizeGB:    ptr.To[int32](128),
+			wantWarnings:    true,
+		},
+		{
+			name:            "VM size does not support ephemeral OS disks",
+			ephemeralOSDisk: fakeEphemeralOSDiskGetter{},
+			osDiskType:      OsDiskTypeEphemeral,
+			wantErr:         "VM size Standard_D2s_v3 does not support ephemeral OS disks",
+		},
+		{
+			name:            "OS disk size not set",
+			ephemeralOSDisk: fakeEphemeralOSDiskGetter{supported: true, cacheDiskGB: 50},
+			osDiskType:      OsDiskTypeEphemeral,
+		},
+		{
+			name:            "OS disk fits on the cache disk",
+			ephemeralOSDisk: fakeEphemeralOSDiskGetter{supported: true, cacheDiskGB: 100, tempDiskGB: 16},
+			osDiskType:      OsDiskTypeEphemeral,
+			osDiskSizeGB:    ptr.To[int32](100),
+		},
+		{
+			name:            "OS disk fits on the temp disk",
+			ephemeralOSDisk: fakeEphemeralOSDiskGetter{supported: true, tempDiskGB: 150},
+			osDiskType:      OsDiskTypeEphemeral,
+			osDiskSizeGB:    ptr.To[int32](128),
+		},
+		{
+			name:            "OS disk fits on neither disk",
+			ephemeralOSDisk: fakeEphemeralOSDiskGetter{supported: true, cacheDiskGB: 100, tempDiskGB: 16},
+			osDiskType:      OsDiskTypeEphemeral,
+			osDiskSizeGB:    ptr.To[int32](128),
+			wantErr:         "the ephemeral OS disk fits neither the 100 GB cache disk nor the 16 GB temp disk of VM size Standard_D2s_v3",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ammp := getKnownValidAzureManagedMachinePool()
+			ammp.Spec.SKU = "Standard_D2s_v3"
+			ammp.Spec.OsDiskType = ptr.To(string(tc.osDiskType))
+			ammp.Spec.OSDiskSizeGB = tc.osDiskSizeGB
+			mw := &azureManagedMachinePoolWebhook{EphemeralOSDisk: tc.ephemeralOSDisk}
+			warnings, err := mw.ValidateCreate(context.Background(), ammp)
+			if tc.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tc.wantWarnings {
+				g.Expect(warnings).NotTo(BeEmpty())
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
 func TestAzureManagedMachinePool_validateLastSystemNodePool(t *testing.T) {
 	deletionTime := metav1.Now()
 	finalizers := []string{"test"}
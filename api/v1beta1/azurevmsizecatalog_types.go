@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VMSizeClass is a logical VM size, such as "general-4cpu-16gb", backed by a list of interchangeable VM sizes.
+type VMSizeClass struct {
+	// Name is the name used to reference the size class from an AzureMachine.
+	Name string `json:"name"`
+
+	// VMSizes are the VM sizes that can back the size class, in order of preference.
+	// The first VM size that is available in the location, and in the zone of the machine if it has one, is used.
+	// +kubebuilder:validation:MinItems=1
+	VMSizes []string `json:"vmSizes"`
+}
+
+// VMSizeClassReference references a size class of an AzureVMSizeCatalog.
+type VMSizeClassReference struct {
+	// CatalogName is the name of the AzureVMSizeCatalog.
+	CatalogName string `json:"catalogName"`
+
+	// SizeClass is the name of the size class in the catalog.
+	SizeClass string `json:"sizeClass"`
+}
+
+// AzureVMSizeCatalogSpec defines the size classes of an AzureVMSizeCatalog.
+type AzureVMSizeCatalogSpec struct {
+	// SizeClasses are the size classes of the catalog.
+	// +listType=map
+	// +listMapKey=name
+	SizeClasses []VMSizeClass `json:"sizeClasses"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of this AzureVMSizeCatalog"
+// +kubebuilder:resource:path=azurevmsizecatalogs,scope=Cluster,categories=cluster-api
+// +kubebuilder:storageversion
+
+// AzureVMSizeCatalog is the Schema for the azurevmsizecatalogs API.
+// It maps logical size classes to VM sizes so that machine templates can be used across regions.
+type AzureVMSizeCatalog struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AzureVMSizeCatalogSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AzureVMSizeCatalogList contains a list of AzureVMSizeCatalog.
+type AzureVMSizeCatalogList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AzureVMSizeCatalog `json:"items"`
+}
+
+// GetSizeClass returns the size class with the given name, or nil if the catalog doesn't have one.
+func (c *AzureVMSizeCatalog) GetSizeClass(name string) *VMSizeClass {
+	for i := range c.Spec.SizeClasses {
+		if c.Spec.SizeClasses[i].Name == name {
+			return &c.Spec.SizeClasses[i]
+		}
+	}
+	return nil
+}
+
+func init() {
+	SchemeBuilder.Register(&AzureVMSizeCatalog{}, &AzureVMSizeCatalogList{})
+}
@@ -24,6 +24,9 @@ const (
 	NetworkInfrastructureReadyCondition clusterv1.ConditionType = "NetworkInfrastructureReady"
 	// NamespaceNotAllowedByIdentity used to indicate cluster in a namespace not allowed by identity.
 	NamespaceNotAllowedByIdentity = "NamespaceNotAllowedByIdentity"
+	// SubnetCIDRConflictReason used when the subnets of a cluster conflict with the subnets of another cluster sharing
+	// its VNet.
+	SubnetCIDRConflictReason = "SubnetCIDRConflict"
 )
 
 // AzureMachine Conditions and Reasons.
@@ -52,6 +55,23 @@ const (
 	BootstrapInProgressReason = "BootstrapInProgress"
 	// BootstrapFailedReason is used to indicate the bootstrap process ran into an error.
 	BootstrapFailedReason = "BootstrapFailed"
+	// NodeJoinedCondition reports the last stage reached by the bootstrap of a Linux machine, as reported by the bootstrap
+	// VM extension.
+	NodeJoinedCondition clusterv1.ConditionType = "NodeJoined"
+	// BootstrapDownloadingReason is used when the bootstrap data is downloaded and processed, before kubeadm runs.
+	BootstrapDownloadingReason = "BootstrapDownloading"
+	// BootstrapRunningKubeadmReason is used when kubeadm is initializing or joining the node.
+	BootstrapRunningKubeadmReason = "BootstrapRunningKubeadm"
+	// BootLogCondition reports the tail of the serial console log of a VM whose bootstrap failed.
+	BootLogCondition clusterv1.ConditionType = "BootLog"
+	// BootLogUnavailableReason is used when the serial console log of a VM can't be retrieved, e.g. because boot diagnostics are disabled.
+	BootLogUnavailableReason = "BootLogUnavailable"
+	// RunCommandsSucceededCondition reports the result of the run commands of the machine.
+	RunCommandsSucceededCondition clusterv1.ConditionType = "RunCommandsSucceeded"
+	// RunCommandInProgressReason is used to indicate a run command has not finished running.
+	RunCommandInProgressReason = "RunCommandInProgress"
+	// RunCommandFailedReason is used to indicate the script of a run command failed.
+	RunCommandFailedReason = "RunCommandFailed"
 )
 
 // AzureMachinePool Conditions and Reasons.
@@ -78,6 +98,35 @@ const (
 	ScaleSetModelUpdatedCondition clusterv1.ConditionType = "ScaleSetModelUpdated"
 	// ScaleSetModelOutOfDateReason describes the machine pool model being out of date.
 	ScaleSetModelOutOfDateReason = "ScaleSetModelOutOfDate"
+
+	// StandbyPoolReadyCondition means the standby pool of the scale set exists and is ready to be used.
+	StandbyPoolReadyCondition clusterv1.ConditionType = "StandbyPoolReady"
+
+	// DiagnosticsStorageAccountReadyCondition means the boot diagnostics storage account of the machine pool exists
+	// and is ready to be used.
+	DiagnosticsStorageAccountReadyCondition clusterv1.ConditionType = "DiagnosticsStorageAccountReady"
+)
+
+// AzureMachinePoolMachine Conditions and Reasons.
+const (
+	// InstanceProvisionedCondition reports on the provisioning state of the scale set instance.
+	InstanceProvisionedCondition clusterv1.ConditionType = "InstanceProvisioned"
+	// InstanceProvisioningReason is used while the scale set instance is being created or updated.
+	InstanceProvisioningReason = "InstanceProvisioning"
+	// InstanceDeletingReason is used while the scale set instance is being deleted.
+	InstanceDeletingReason = "InstanceDeleting"
+	// InstanceProvisionFailedReason is used when the provisioning of the scale set instance failed.
+	InstanceProvisionFailedReason = "InstanceProvisionFailed"
+
+	// InstanceRunningCondition reports on the power state of the scale set instance.
+	InstanceRunningCondition clusterv1.ConditionType = "InstanceRunning"
+	// InstanceNotRunningReason is used when the scale set instance is starting, stopped or deallocated.
+	InstanceNotRunningReason = "InstanceNotRunning"
+
+	// InstanceLatestModelCondition reports whether the scale set instance runs the latest model of the scale set.
+	InstanceLatestModelCondition clusterv1.ConditionType = "InstanceLatestModel"
+	// InstanceModelOutOfDateReason is used when the scale set instance doesn't run the latest model of the scale set.
+	InstanceModelOutOfDateReason = "InstanceModelOutOfDate"
 )
 
 // AzureManagedCluster Conditions and Reasons.
@@ -86,8 +135,18 @@ const (
 	ManagedClusterRunningCondition clusterv1.ConditionType = "ManagedClusterRunning"
 	// AgentPoolsReadyCondition means the AKS agent pools exist and are ready to be used.
 	AgentPoolsReadyCondition clusterv1.ConditionType = "AgentPoolsReady"
-	// AzureResourceAvailableCondition means the AKS cluster is healthy according to Azure's Resource Health API.
+	// AzureResourceAvailableCondition means the Azure resource backing the object, such as the AKS cluster, the VM of
+	// an AzureMachine or the API server load balancer of an AzureCluster, is healthy according to Azure's Resource
+	// Health API.
 	AzureResourceAvailableCondition clusterv1.ConditionType = "AzureResourceAvailable"
+	// ResourceProvidersRegisteredCondition means the subscription of the cluster is registered with the resource
+	// providers required by the cluster.
+	ResourceProvidersRegisteredCondition clusterv1.ConditionType = "ResourceProvidersRegistered"
+	// ResourceProvidersRegisteringReason means the subscription is being registered with resource providers.
+	ResourceProvidersRegisteringReason = "ResourceProvidersRegistering"
+	// ResourceProvidersNotRegisteredReason means the subscription isn't registered with resource providers and
+	// couldn't be registered with them.
+	ResourceProvidersNotRegisteredReason = "ResourceProvidersNotRegistered"
 )
 
 // Azure Services Conditions and Reasons.
@@ -130,6 +189,15 @@ const (
 	NetworkInterfaceReadyCondition clusterv1.ConditionType = "NetworkInterfacesReady"
 	// PrivateEndpointsReadyCondition means the private endpoints exist and are ready to be used.
 	PrivateEndpointsReadyCondition clusterv1.ConditionType = "PrivateEndpointsReady"
+	// TrafficManagerReadyCondition means the Traffic Manager profile and endpoint exist and are ready to be used.
+	TrafficManagerReadyCondition clusterv1.ConditionType = "TrafficManagerReady"
+	// NetAppReadyCondition means the NetApp account and capacity pools exist and are ready to be used.
+	NetAppReadyCondition clusterv1.ConditionType = "NetAppReady"
+	// AzureFirewallReadyCondition means the Azure Firewall exists and is ready to be used.
+	AzureFirewallReadyCondition clusterv1.ConditionType = "AzureFirewallReady"
+	// ApplicationGatewayReadyCondition means the prerequisites of the Application Gateway based ingress controller exist
+	// and are ready to be used.
+	ApplicationGatewayReadyCondition clusterv1.ConditionType = "ApplicationGatewayReady"
 
 	// CreatingReason means the resource is being created.
 	CreatingReason = "Creating"
@@ -143,6 +211,11 @@ const (
 	DeletionFailedReason = "DeletionFailed"
 	// UpdatingReason means the resource is being updated.
 	UpdatingReason = "Updating"
+	// DeletionProtectedReason means the resource isn't deleted because the AzureCluster has deletion protection enabled.
+	DeletionProtectedReason = "DeletionProtected"
+	// NodeOutboundRuleNotFoundReason means the additional outbound rule of the node outbound load balancer referenced by
+	// a machine pool doesn't exist.
+	NodeOutboundRuleNotFoundReason = "NodeOutboundRuleNotFound"
 )
 
 const (
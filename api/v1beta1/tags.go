@@ -116,6 +116,10 @@ const (
 	// dedicated to this cluster api provider implementation.
 	NameAzureClusterAPIRole = NameAzureProviderPrefix + "role"
 
+	// SnapshotExpiryTag is the tag name recording the time after which a disk snapshot taken by the Azure provider
+	// can be deleted.
+	SnapshotExpiryTag = NameAzureProviderPrefix + "snapshot-expiry"
+
 	// APIServerRole describes the value for the apiserver role.
 	APIServerRole = "apiserver"
 
@@ -17,8 +17,11 @@ limitations under the License.
 package v1beta1
 
 import (
+	"strings"
+
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/net"
 )
 
@@ -29,6 +32,16 @@ const (
 	Node string = "node"
 	// Bastion subnet label.
 	Bastion string = "bastion"
+	// NetApp subnet label.
+	NetApp string = "netapp"
+	// Firewall subnet label.
+	Firewall string = "firewall"
+	// Ingress subnet label.
+	Ingress string = "ingress"
+	// Egress subnet label.
+	Egress string = "egress"
+	// BastionOnly subnet label.
+	BastionOnly string = "bastion-only"
 )
 
 // SecurityEncryptionType represents the Encryption Type when the virtual machine is a
@@ -109,9 +122,192 @@ type NetworkSpec struct {
 	// +optional
 	ControlPlaneOutboundLB *LoadBalancerSpec `json:"controlPlaneOutboundLB,omitempty"`
 
+	// NodeSubnetPool is an optional address range from which CIDR blocks are carved for node subnets
+	// that do not specify CIDRBlocks. This allows additional node subnets to be declared by name and role only,
+	// so that MachineDeployments can reference them without pre-computing a CIDR for each subnet.
+	// +optional
+	NodeSubnetPool *SubnetPoolSpec `json:"nodeSubnetPool,omitempty"`
+
+	// NetApp configures the prerequisites of Azure NetApp Files volumes: a subnet delegated to Azure NetApp Files and,
+	// optionally, a NetApp account and capacity pools managed with the cluster.
+	// +optional
+	NetApp *NetAppSpec `json:"netApp,omitempty"`
+
+	// OutboundType is the egress path of the node subnets. AzureFirewall routes the egress traffic of the node subnets
+	// through the Azure Firewall configured in azureFirewall instead of NAT gateways.
+	// When not set, node subnets egress through their NAT gateway or the node outbound load balancer. Immutable.
+	// +kubebuilder:validation:Enum=AzureFirewall
+	// +optional
+	OutboundType NodeOutboundType `json:"outboundType,omitempty"`
+
+	// AzureFirewall is the Azure Firewall the node subnets egress through when outboundType is AzureFirewall.
+	// It defaults to a firewall created by CAPZ.
+	// +optional
+	AzureFirewall *AzureFirewallSpec `json:"azureFirewall,omitempty"`
+
+	// ApplicationGateway provisions the prerequisites of an Application Gateway based ingress controller running on
+	// the cluster. They are the Application Gateway of the Application Gateway Ingress Controller (AGIC), or the subnet
+	// of Application Gateway for Containers (AGC), and the role assignments of the controller's identity.
+	// +optional
+	ApplicationGateway *ApplicationGatewaySpec `json:"applicationGateway,omitempty"`
+
 	NetworkClassSpec `json:",inline"`
 }
 
+// NodeOutboundType is the egress path of the node subnets of a cluster.
+type NodeOutboundType string
+
+const (
+	// AzureFirewallNodeOutboundType routes the egress traffic of the node subnets through an Azure Firewall.
+	AzureFirewallNodeOutboundType NodeOutboundType = "AzureFirewall"
+)
+
+// AzureFirewallSpec defines the Azure Firewall the node subnets of a cluster egress through.
+type AzureFirewallSpec struct {
+	// ID is the resource ID of an existing Azure Firewall. When set, CAPZ routes the egress traffic of the node
+	// subnets through it, but doesn't create it or manage its rules.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Name is the name of the Azure Firewall created by CAPZ. Defaults to <cluster name>-firewall.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Subnet is the subnet of the Azure Firewall created by CAPZ. Azure requires it to be named AzureFirewallSubnet
+	// and to be at least a /26. Defaults to the CIDR block 10.255.255.0/26.
+	// +optional
+	Subnet SubnetSpec `json:"subnet,omitempty"`
+
+	// PublicIP is the public IP the Azure Firewall created by CAPZ egresses through.
+	// Defaults to <cluster name>-firewall-pip.
+	// +optional
+	PublicIP PublicIPSpec `json:"publicIP,omitempty"`
+
+	// PrivateIPAddress is the private IP address of the Azure Firewall the node subnets route their egress traffic to.
+	// It is required for an existing firewall. For a firewall created by CAPZ, it defaults to the first address of
+	// its subnet that Azure doesn't reserve, such as 10.255.255.4, which Azure always assigns to the firewall.
+	// +optional
+	PrivateIPAddress string `json:"privateIPAddress,omitempty"`
+
+	// AllowedFQDNs are the FQDNs the nodes can reach over HTTPS in addition to the ones Kubernetes and CAPZ require.
+	// Only used for a firewall created by CAPZ.
+	// +optional
+	AllowedFQDNs []string `json:"allowedFQDNs,omitempty"`
+}
+
+// ApplicationGatewayController is the Application Gateway based ingress controller the prerequisites are provisioned for.
+type ApplicationGatewayController string
+
+const (
+	// ApplicationGatewayIngressController is the Application Gateway Ingress Controller (AGIC), which configures an
+	// existing Application Gateway.
+	ApplicationGatewayIngressController ApplicationGatewayController = "AGIC"
+
+	// ApplicationGatewayForContainers is the ALB controller of Application Gateway for Containers (AGC), which creates
+	// its gateway in a subnet delegated to it.
+	ApplicationGatewayForContainers ApplicationGatewayController = "AGC"
+)
+
+// ApplicationGatewaySpec defines the prerequisites of an Application Gateway based ingress controller.
+type ApplicationGatewaySpec struct {
+	// Controller is the ingress controller the prerequisites are provisioned for. Defaults to AGIC. Immutable.
+	// +kubebuilder:validation:Enum=AGIC;AGC
+	// +optional
+	Controller ApplicationGatewayController `json:"controller,omitempty"`
+
+	// ID is the resource ID of an existing Application Gateway for AGIC. When set, CAPZ only assigns roles on it,
+	// and doesn't create an Application Gateway, a subnet or a public IP.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Name is the name of the Application Gateway created by CAPZ for AGIC. Defaults to <cluster name>-appgw.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// SubnetName is the name of the subnet with the ingress role in networkSpec.subnets that the Application Gateway
+	// is deployed into, or that is delegated to Application Gateway for Containers. Defaults to the first subnet with the
+	// ingress role, or to a subnet named <cluster name>-appgw-subnet with the CIDR block 10.255.253.0/24 that is added
+	// to networkSpec.subnets.
+	// +optional
+	SubnetName string `json:"subnetName,omitempty"`
+
+	// PublicIP is the public IP of the frontend of the Application Gateway created by CAPZ for AGIC.
+	// Defaults to <cluster name>-appgw-pip.
+	// +optional
+	PublicIP PublicIPSpec `json:"publicIP,omitempty"`
+
+	// PrincipalID is the object ID of the managed identity the ingress controller runs with.
+	// When set, CAPZ assigns it the roles the controller needs on the Application Gateway, its subnet and its resource group.
+	// +optional
+	PrincipalID string `json:"principalID,omitempty"`
+}
+
+// NetAppSpec defines the Azure NetApp Files resources of a cluster.
+type NetAppSpec struct {
+	// Subnet is the subnet delegated to Azure NetApp Files.
+	// It defaults to a subnet named <cluster name>-netapp-subnet with the CIDR block 10.255.254.0/24.
+	// +optional
+	Subnet SubnetSpec `json:"subnet,omitempty"`
+
+	// Account is the NetApp account created in the cluster resource group.
+	// If not set, no NetApp account is created.
+	// +optional
+	Account *NetAppAccount `json:"account,omitempty"`
+}
+
+// NetAppAccount defines a NetApp account and its capacity pools.
+type NetAppAccount struct {
+	// Name is the name of the NetApp account. Defaults to <cluster name>-netapp.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// CapacityPools are the capacity pools of the NetApp account.
+	// +optional
+	CapacityPools []NetAppCapacityPool `json:"capacityPools,omitempty"`
+}
+
+// NetAppServiceLevel is the service level of a NetApp capacity pool.
+type NetAppServiceLevel string
+
+const (
+	// NetAppServiceLevelStandard is the Standard service level.
+	NetAppServiceLevelStandard NetAppServiceLevel = "Standard"
+	// NetAppServiceLevelPremium is the Premium service level.
+	NetAppServiceLevelPremium NetAppServiceLevel = "Premium"
+	// NetAppServiceLevelUltra is the Ultra service level.
+	NetAppServiceLevelUltra NetAppServiceLevel = "Ultra"
+)
+
+// NetAppCapacityPool defines a capacity pool of a NetApp account.
+type NetAppCapacityPool struct {
+	// Name is the name of the capacity pool.
+	Name string `json:"name"`
+
+	// ServiceLevel is the service level of the capacity pool. It can't be changed after creation. Defaults to Premium.
+	// +kubebuilder:validation:Enum=Standard;Premium;Ultra
+	// +kubebuilder:default=Premium
+	// +optional
+	ServiceLevel NetAppServiceLevel `json:"serviceLevel,omitempty"`
+
+	// SizeTiB is the provisioned size of the capacity pool in TiB. It can be increased or decreased after creation.
+	// +kubebuilder:validation:Minimum=2
+	SizeTiB int64 `json:"sizeTiB"`
+}
+
+// SubnetPoolSpec defines an address range from which subnet CIDR blocks are allocated.
+type SubnetPoolSpec struct {
+	// CIDRBlock is the address range, in CIDR notation, from which subnet CIDR blocks are carved.
+	// It must be contained within the virtual network's address space.
+	CIDRBlock string `json:"cidrBlock"`
+
+	// PrefixLength is the prefix length of each subnet CIDR block carved from the pool.
+	// Defaults to 24.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=128
+	// +optional
+	PrefixLength *int32 `json:"prefixLength,omitempty"`
+}
+
 // VnetSpec configures an Azure virtual network.
 type VnetSpec struct {
 	// ResourceGroup is the name of the resource group of the existing virtual network
@@ -131,6 +327,12 @@ type VnetSpec struct {
 	// +optional
 	Peerings VnetPeerings `json:"peerings,omitempty"`
 
+	// DeletePolicy specifies whether a managed virtual network is deleted or retained when the cluster is deleted.
+	// Subnets of a retained virtual network are still deleted. Defaults to Delete.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +optional
+	DeletePolicy DeletePolicy `json:"deletePolicy,omitempty"`
+
 	VnetClassSpec `json:",inline"`
 }
 
@@ -320,10 +522,29 @@ type LoadBalancerSpec struct {
 	// BackendPool describes the backend pool of the load balancer.
 	// +optional
 	BackendPool BackendPool `json:"backendPool,omitempty"`
+	// AdditionalOutboundRules are outbound rules in addition to the default one. Each rule SNATs the outbound traffic
+	// of its own backend pool through dedicated frontend IPs, so that selected machine pools egress through known public IPs.
+	// Only supported on the node outbound load balancer.
+	// +optional
+	AdditionalOutboundRules []OutboundRule `json:"additionalOutboundRules,omitempty"`
 
 	LoadBalancerClassSpec `json:",inline"`
 }
 
+// OutboundRule defines an outbound rule of a load balancer bound to a dedicated backend pool.
+type OutboundRule struct {
+	// Name is the name of the outbound rule. Machine pools reference the rule by this name.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// FrontendIPsCount specifies the number of frontend IP addresses of the outbound rule. Defaults to 1.
+	// +optional
+	FrontendIPsCount *int32 `json:"frontendIPsCount,omitempty"`
+	// FrontendIPs are the frontend IP configurations of the outbound rule.
+	// If not set, they are generated from FrontendIPsCount.
+	// +optional
+	FrontendIPs []FrontendIP `json:"frontendIPs,omitempty"`
+}
+
 // SKU defines an Azure load balancer SKU.
 type SKU string
 
@@ -348,10 +569,31 @@ type FrontendIP struct {
 	Name string `json:"name"`
 	// +optional
 	PublicIP *PublicIPSpec `json:"publicIP,omitempty"`
+	// Subnet is the subnet in which the private IP of an internal load balancer frontend is allocated.
+	// It is only valid for secondary frontends of an internal API server load balancer and must reference a subnet
+	// of the cluster virtual network, as Azure requires the frontends of an internal load balancer to be in the
+	// virtual network of its backend pool.
+	// If not set, the control plane subnet is used.
+	// +optional
+	Subnet *FrontendIPSubnet `json:"subnet,omitempty"`
 
 	FrontendIPClass `json:",inline"`
 }
 
+// FrontendIPSubnet references an existing subnet for an internal load balancer frontend IP.
+type FrontendIPSubnet struct {
+	// Name is the name of the subnet.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// VNetName is the name of the virtual network containing the subnet. It must be the cluster virtual network.
+	// +kubebuilder:validation:MinLength=1
+	VNetName string `json:"vnetName"`
+	// VNetResourceGroup is the resource group of the virtual network containing the subnet.
+	// Defaults to the resource group of the cluster virtual network.
+	// +optional
+	VNetResourceGroup string `json:"vnetResourceGroup,omitempty"`
+}
+
 // PublicIPSpec defines the inputs to create an Azure public IP address.
 type PublicIPSpec struct {
 	Name string `json:"name"`
@@ -359,6 +601,16 @@ type PublicIPSpec struct {
 	DNSName string `json:"dnsName,omitempty"`
 	// +optional
 	IPTags []IPTag `json:"ipTags,omitempty"`
+	// DeletePolicy specifies whether a managed public IP is deleted or retained when the cluster is deleted.
+	// Defaults to Delete.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +optional
+	DeletePolicy DeletePolicy `json:"deletePolicy,omitempty"`
+	// Zones are the availability zones of the public IP, e.g. ["1"] for a zonal public IP.
+	// If not set, the public IP is zone-redundant across the failure domains of the cluster.
+	// Immutable.
+	// +optional
+	Zones []string `json:"zones,omitempty"`
 }
 
 // IPTag contains the IpTag associated with the object.
@@ -369,6 +621,16 @@ type IPTag struct {
 	Tag string `json:"tag"`
 }
 
+// DeletePolicy defines what happens to an Azure resource when the object it belongs to is deleted.
+type DeletePolicy string
+
+const (
+	// DeletePolicyDelete deletes the Azure resource along with the object it belongs to.
+	DeletePolicyDelete DeletePolicy = "Delete"
+	// DeletePolicyRetain leaves the Azure resource in place when the object it belongs to is deleted.
+	DeletePolicyRetain DeletePolicy = "Retain"
+)
+
 // VMState describes the state of an Azure virtual machine.
 // Deprecated: use ProvisioningState.
 type VMState string
@@ -416,6 +678,91 @@ type Image struct {
 	// ComputeGallery specifies an image to use from the Azure Compute Gallery
 	// +optional
 	ComputeGallery *AzureComputeGalleryImage `json:"computeGallery,omitempty"`
+
+	// Variant selects a hardened variant of the default reference image for the Kubernetes version of the machine.
+	// Variants aren't guaranteed to be published. If no reference image of the variant is found in the location of the
+	// machine, the machine fails to reconcile and the image must be set explicitly instead.
+	// It can only be set when no other image details are set, and is only supported for Linux machines.
+	// +optional
+	Variant ImageVariant `json:"variant,omitempty"`
+
+	// LocationOverrides specifies images to use instead of this image in specific locations.
+	// This allows a template to be used across locations when some of its images, such as
+	// images referenced by ID, are scoped to a location.
+	// When none of the overrides matches the location, this image is used, or the default image if no image details are set.
+	// +optional
+	// +listType=map
+	// +listMapKey=location
+	LocationOverrides []ImageLocationOverride `json:"locationOverrides,omitempty"`
+}
+
+// ImageVariant is a hardened variant of the default reference images.
+// +kubebuilder:validation:Enum=FIPS;CIS
+type ImageVariant string
+
+const (
+	// ImageVariantFIPS is the variant of the reference images that uses FIPS 140 validated cryptographic modules.
+	// Azure VMs have no FIPS setting of their own, so this is how self-managed machines opt into FIPS.
+	ImageVariantFIPS ImageVariant = "FIPS"
+	// ImageVariantCIS is the variant of the reference images hardened according to the CIS benchmarks.
+	ImageVariantCIS ImageVariant = "CIS"
+)
+
+// ImageLocationOverride defines the image to use for VM creation in a location.
+// One of ID, SharedGallery, Marketplace or ComputeGallery should be set.
+type ImageLocationOverride struct {
+	// Location is the Azure location the image is used in.
+	Location string `json:"location"`
+
+	// ID specifies an image to use by ID
+	// +optional
+	ID *string `json:"id,omitempty"`
+
+	// SharedGallery specifies an image to use from an Azure Shared Image Gallery
+	// Deprecated: use ComputeGallery instead.
+	// +optional
+	SharedGallery *AzureSharedGalleryImage `json:"sharedGallery,omitempty"`
+
+	// Marketplace specifies an image to use from the Azure Marketplace
+	// +optional
+	Marketplace *AzureMarketplaceImage `json:"marketplace,omitempty"`
+
+	// ComputeGallery specifies an image to use from the Azure Compute Gallery
+	// +optional
+	ComputeGallery *AzureComputeGalleryImage `json:"computeGallery,omitempty"`
+}
+
+// Image returns the image defined by the override.
+func (o ImageLocationOverride) Image() *Image {
+	return &Image{
+		ID:             o.ID,
+		SharedGallery:  o.SharedGallery,
+		Marketplace:    o.Marketplace,
+		ComputeGallery: o.ComputeGallery,
+	}
+}
+
+// ForLocation returns the image to use in the given location: the image of the matching location override if
+// there is one, otherwise the image without its location overrides. It returns nil when no override matches the
+// location and the image doesn't set any image details itself, in which case the default image should be used.
+func (i *Image) ForLocation(location string) *Image {
+	if i == nil {
+		return nil
+	}
+
+	for _, override := range i.LocationOverrides {
+		if strings.EqualFold(override.Location, location) {
+			return override.Image()
+		}
+	}
+
+	if i.ID == nil && i.SharedGallery == nil && i.Marketplace == nil && i.ComputeGallery == nil {
+		return nil
+	}
+
+	image := i.DeepCopy()
+	image.LocationOverrides = nil
+	return image
 }
 
 // AzureComputeGalleryImage defines an image in the Azure Compute Gallery to use for VM creation.
@@ -597,6 +944,11 @@ type OSDisk struct {
 	// +optional
 	// +kubebuilder:validation:Enum=None;ReadOnly;ReadWrite
 	CachingType string `json:"cachingType,omitempty"`
+	// DeletePolicy specifies whether the OS disk is deleted or retained when the machine is deleted.
+	// Not supported for machine pools. Defaults to Delete.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +optional
+	DeletePolicy DeletePolicy `json:"deletePolicy,omitempty"`
 }
 
 // DataDisk specifies the parameters that are used to add one or more data disks to the machine.
@@ -605,7 +957,16 @@ type DataDisk struct {
 	// Each disk name will be in format <machineName>_<nameSuffix>.
 	NameSuffix string `json:"nameSuffix"`
 	// DiskSizeGB is the size in GB to assign to the data disk.
-	DiskSizeGB int32 `json:"diskSizeGB"`
+	// Required unless ManagedDiskID is set, in which case the size of the existing disk is used.
+	// +optional
+	DiskSizeGB int32 `json:"diskSizeGB,omitempty"`
+	// ManagedDiskID is the resource ID of an existing managed disk, e.g. one restored from a snapshot, to attach to the
+	// machine instead of creating an empty disk. The disk must be in the subscription and location, and the zone if any,
+	// of the machine, and can only be attached to one machine at a time. It is retained when the machine is deleted,
+	// unless DeletePolicy is set to Delete. Cannot be set together with ManagedDisk.
+	// Not supported for machine pools.
+	// +optional
+	ManagedDiskID string `json:"managedDiskID,omitempty"`
 	// ManagedDisk specifies the Managed Disk parameters for the data disk.
 	// +optional
 	ManagedDisk *ManagedDiskParameters `json:"managedDisk,omitempty"`
@@ -617,6 +978,35 @@ type DataDisk struct {
 	// +optional
 	// +kubebuilder:validation:Enum=None;ReadOnly;ReadWrite
 	CachingType string `json:"cachingType,omitempty"`
+	// DeletePolicy specifies whether the data disk is deleted or retained when the machine is deleted.
+	// Not supported for machine pools. Defaults to Delete, or to Retain if ManagedDiskID is set.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +optional
+	DeletePolicy DeletePolicy `json:"deletePolicy,omitempty"`
+}
+
+// IsShared returns whether the data disk is a shared disk, which can be attached to multiple machines at the same time.
+func (d DataDisk) IsShared() bool {
+	return d.ManagedDisk != nil && d.ManagedDisk.MaxShares != nil && *d.ManagedDisk.MaxShares > 1
+}
+
+// HasProvisionedPerformance returns whether the IOPS or the throughput of the data disk is set.
+func (d DataDisk) HasProvisionedPerformance() bool {
+	return d.ManagedDisk != nil && (d.ManagedDisk.DiskIOPSReadWrite != nil || d.ManagedDisk.DiskMBpsReadWrite != nil)
+}
+
+// IsExisting returns whether the data disk is an existing managed disk attached to the machine.
+func (d DataDisk) IsExisting() bool {
+	return d.ManagedDiskID != ""
+}
+
+// IsRetained returns whether the data disk is retained when the machine is deleted.
+// Existing managed disks are only deleted along with the machine if their delete policy is explicitly Delete.
+func (d DataDisk) IsRetained() bool {
+	if d.IsExisting() {
+		return d.DeletePolicy != DeletePolicyDelete
+	}
+	return d.DeletePolicy == DeletePolicyRetain
 }
 
 // VMExtension specifies the parameters for a custom VM extension.
@@ -633,6 +1023,31 @@ type VMExtension struct {
 	// ProtectedSettings is a JSON formatted protected settings for the extension.
 	// +optional
 	ProtectedSettings Tags `json:"protectedSettings,omitempty"`
+	// AutoUpgradeMinorVersion indicates whether Azure uses the latest minor version of the extension when it is
+	// deployed. Once deployed, the extension only moves to a newer minor version when it is redeployed.
+	// If unset, the Azure default is used.
+	// +optional
+	AutoUpgradeMinorVersion *bool `json:"autoUpgradeMinorVersion,omitempty"`
+	// EnableAutomaticUpgrade indicates whether Azure automatically upgrades the extension when a newer version of it
+	// is published. It is only supported by some extensions.
+	// If unset, the Azure default is used.
+	// +optional
+	EnableAutomaticUpgrade *bool `json:"enableAutomaticUpgrade,omitempty"`
+}
+
+// DiskSnapshot defines the snapshots taken of the disks of a machine before the disks are deleted.
+type DiskSnapshot struct {
+	// ResourceGroup is the name of the resource group the snapshots are created in. The resource group must exist and
+	// be in the same subscription as the machine. It must not be a resource group managed by the Azure provider, such as
+	// the resource group of the cluster, since the snapshots would be deleted along with it.
+	// +kubebuilder:validation:MinLength=1
+	ResourceGroup string `json:"resourceGroup"`
+
+	// RetentionPeriod is how long the snapshots are meant to be kept. The time after which a snapshot can be deleted is
+	// recorded in its sigs.k8s.io_cluster-api-provider-azure_snapshot-expiry tag, in RFC 3339 format.
+	// The Azure provider does not delete snapshots.
+	// +optional
+	RetentionPeriod *metav1.Duration `json:"retentionPeriod,omitempty"`
 }
 
 // ManagedDiskParameters defines the parameters of a managed disk.
@@ -645,6 +1060,23 @@ type ManagedDiskParameters struct {
 	// SecurityProfile specifies the security profile for the managed disk.
 	// +optional
 	SecurityProfile *VMDiskSecurityProfile `json:"securityProfile,omitempty"`
+	// MaxShares is the maximum number of machines that can attach the data disk at the same time.
+	// A value greater than 1 makes the disk a shared disk, which is created once per cluster and name suffix and
+	// attached to every machine of the cluster with a data disk of the same name suffix.
+	// Only supported for data disks of AzureMachines.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxShares *int32 `json:"maxShares,omitempty"`
+	// DiskIOPSReadWrite is the number of IOPS the data disk is provisioned with.
+	// Only supported for UltraSSD_LRS data disks. Defaults to a value based on the size of the disk.
+	// +kubebuilder:validation:Minimum=100
+	// +optional
+	DiskIOPSReadWrite *int64 `json:"diskIOPSReadWrite,omitempty"`
+	// DiskMBpsReadWrite is the throughput the data disk is provisioned with, in MB per second.
+	// Only supported for UltraSSD_LRS data disks. Defaults to a value based on the size of the disk.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	DiskMBpsReadWrite *int64 `json:"diskMBpsReadWrite,omitempty"`
 }
 
 // VMDiskSecurityProfile specifies the security profile settings for the managed disk.
@@ -680,8 +1112,25 @@ type DiffDiskSettings struct {
 	// See https://learn.microsoft.com/azure/virtual-machines/ephemeral-os-disks for full details
 	// +kubebuilder:validation:Enum=Local
 	Option string `json:"option"`
+
+	// Placement is the location of the ephemeral OS disk on the VM, either the cache disk or the resource (temp) disk
+	// of the VM size. Setting it to ResourceDisk allows using an ephemeral OS disk with VM sizes that have no cache disk.
+	// Defaults to CacheDisk if the VM size has one, otherwise to ResourceDisk.
+	// +kubebuilder:validation:Enum=CacheDisk;ResourceDisk
+	// +optional
+	Placement DiffDiskPlacement `json:"placement,omitempty"`
 }
 
+// DiffDiskPlacement is the location of an ephemeral OS disk on a VM.
+type DiffDiskPlacement string
+
+const (
+	// DiffDiskPlacementCacheDisk places the ephemeral OS disk on the cache disk of the VM.
+	DiffDiskPlacementCacheDisk DiffDiskPlacement = "CacheDisk"
+	// DiffDiskPlacementResourceDisk places the ephemeral OS disk on the resource (temp) disk of the VM.
+	DiffDiskPlacementResourceDisk DiffDiskPlacement = "ResourceDisk"
+)
+
 // SubnetRole defines the unique role of a subnet.
 type SubnetRole string
 
@@ -694,8 +1143,31 @@ const (
 
 	// SubnetBastion defines a Bastion subnet role.
 	SubnetBastion = SubnetRole(Bastion)
+
+	// SubnetNetApp defines a subnet delegated to Azure NetApp Files.
+	SubnetNetApp = SubnetRole(NetApp)
+
+	// SubnetFirewall defines the subnet of an Azure Firewall.
+	SubnetFirewall = SubnetRole(Firewall)
+
+	// SubnetIngress defines a subnet for services receiving traffic into the virtual network,
+	// such as an Application Gateway.
+	SubnetIngress = SubnetRole(Ingress)
+
+	// SubnetEgress defines a subnet for services handling traffic out of the virtual network,
+	// such as a network virtual appliance or a private link service.
+	SubnetEgress = SubnetRole(Egress)
+
+	// SubnetBastionOnly defines a subnet that only hosts jump box virtual machines.
+	SubnetBastionOnly = SubnetRole(BastionOnly)
 )
 
+// IsServiceRole returns true if the role is one of the roles of subnets carved out of the virtual network for services
+// other than the cluster machines, which are ingress, egress and bastion-only.
+func (r SubnetRole) IsServiceRole() bool {
+	return r == SubnetIngress || r == SubnetEgress || r == SubnetBastionOnly
+}
+
 // SubnetSpec configures an Azure subnet.
 type SubnetSpec struct {
 	// ID is the Azure resource ID of the subnet.
@@ -769,6 +1241,13 @@ type PrivateEndpointSpec struct {
 
 // NetworkInterface defines a network interface.
 type NetworkInterface struct {
+	// ID is the resource ID of an existing network interface to attach to the virtual machine instead of creating one,
+	// for instance one carrying externally managed IP or DNS configuration. The network interface is neither updated
+	// nor deleted, and SubnetName, PrivateIPConfigs and AcceleratedNetworking must not be set along with it.
+	// It is only supported on AzureMachines.
+	// +optional
+	ID string `json:"id,omitempty"`
+
 	// SubnetName specifies the subnet in which the new network interface will be placed.
 	SubnetName string `json:"subnetName,omitempty"`
 
@@ -783,6 +1262,18 @@ type NetworkInterface struct {
 	// +kubebuilder:validation:nullable
 	// +optional
 	AcceleratedNetworking *bool `json:"acceleratedNetworking,omitempty"`
+
+	// PublicIPPerInstance assigns a public IP address to the primary IP configuration of the network interface of each
+	// instance, so the instances can be reached directly from outside of the virtual network.
+	// It is only supported on AzureMachinePools.
+	// +optional
+	PublicIPPerInstance bool `json:"publicIPPerInstance,omitempty"`
+
+	// PublicIPPrefixID is the resource ID of the public IP prefix the public IP addresses of the instances are allocated
+	// from. It requires PublicIPPerInstance.
+	// It is only supported on AzureMachinePools.
+	// +optional
+	PublicIPPrefixID string `json:"publicIPPrefixID,omitempty"`
 }
 
 // GetControlPlaneSubnet returns the cluster control plane subnet.
@@ -828,6 +1319,109 @@ func (s SubnetSpec) IsIPv6Enabled() bool {
 	return false
 }
 
+// VaultSecretGroup is a set of certificates from the same Key Vault to install on a virtual machine or the instances of
+// a virtual machine scale set when they are provisioned.
+type VaultSecretGroup struct {
+	// SourceVault is the resource ID of the Key Vault containing the certificates.
+	// The Key Vault must be enabled for deployment.
+	SourceVault string `json:"sourceVault"`
+
+	// VaultCertificates are the certificates of the Key Vault to install.
+	// +kubebuilder:validation:MinItems=1
+	VaultCertificates []VaultCertificate `json:"vaultCertificates"`
+}
+
+// VaultCertificate is a Key Vault certificate to install on a virtual machine.
+type VaultCertificate struct {
+	// CertificateURL is the URL of the certificate in Key Vault, including its version, such as
+	// https://myvault.vault.azure.net/secrets/mycert/0123456789abcdef0123456789abcdef.
+	CertificateURL string `json:"certificateURL"`
+
+	// CertificateStore is the certificate store of the LocalMachine account the certificate is added to on Windows,
+	// such as "My" or "Root". It is required for Windows and must not be set for Linux, where certificates are placed
+	// in the /var/lib/waagent directory as <thumbprint>.crt and <thumbprint>.prv files.
+	// +optional
+	CertificateStore string `json:"certificateStore,omitempty"`
+}
+
+// VMGalleryApplication is an Azure Compute Gallery application to install on a virtual machine or the instances of a
+// virtual machine scale set when they are provisioned.
+type VMGalleryApplication struct {
+	// Version is the resource ID of the gallery application version to install, such as
+	// /subscriptions/{subscriptionID}/resourceGroups/{resourceGroup}/providers/Microsoft.Compute/galleries/{gallery}/applications/{application}/versions/{version}.
+	Version string `json:"version"`
+
+	// Order is the order in which the application is installed relative to the other applications.
+	// Applications without an order are installed after the ordered ones.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Order *int32 `json:"order,omitempty"`
+
+	// ConfigurationReference is the URI of an Azure blob that replaces the default configuration of the application.
+	// +optional
+	ConfigurationReference string `json:"configurationReference,omitempty"`
+
+	// Tags is a passthrough value passed to the application for more generic context.
+	// +optional
+	Tags string `json:"tags,omitempty"`
+}
+
+// RunCommand is a script to run on a virtual machine with Azure Run Command, without needing SSH access to the machine.
+// Exactly one of Script, ScriptURI and CommandID must be set.
+type RunCommand struct {
+	// Name is the name of the run command, which is also the name of the run command resource of the virtual machine.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=80
+	Name string `json:"name"`
+
+	// Script is the content of the script to run.
+	// +optional
+	Script string `json:"script,omitempty"`
+
+	// ScriptURI is the https URI the script to run is downloaded from, such as the SAS URI of an Azure blob.
+	// +optional
+	ScriptURI string `json:"scriptURI,omitempty"`
+
+	// CommandID is the ID of a built-in run command, such as "RunShellScript" or "IPConfig".
+	// +optional
+	CommandID string `json:"commandID,omitempty"`
+
+	// Parameters are the parameters passed to the script. They are stored in plain text in the run command resource
+	// and can be read by anyone who can read the AzureMachine or the virtual machine, so they must not contain secrets.
+	// +optional
+	Parameters []RunCommandParameter `json:"parameters,omitempty"`
+
+	// TimeoutSeconds is the time after which the script is stopped if it hasn't finished.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// RunCommandParameter is a parameter passed to the script of a run command.
+type RunCommandParameter struct {
+	// Name is the name of the parameter.
+	Name string `json:"name"`
+
+	// Value is the value of the parameter.
+	Value string `json:"value"`
+}
+
+// BootstrapEncryption configures envelope encryption of the bootstrap data of a virtual machine. The bootstrap data is
+// encrypted with a random data encryption key, which is itself wrapped with a Key Vault key. The virtual machine is given
+// a small script as custom data that unwraps the data encryption key with its managed identity, then decrypts and
+// applies the bootstrap data, so that it can't be read by anyone who can only read the virtual machine.
+type BootstrapEncryption struct {
+	// KeyID is the versioned identifier of the Key Vault RSA key used to wrap the data encryption key, such as
+	// https://myvault.vault.azure.net/keys/mykey/0123456789abcdef0123456789abcdef.
+	// The identity of the virtual machine must be allowed to unwrap keys with it.
+	KeyID string `json:"keyID"`
+
+	// IdentityClientID is the client ID of the user-assigned identity the virtual machine uses to unwrap the data
+	// encryption key. It is required when the virtual machine has more than one user-assigned identity.
+	// +optional
+	IdentityClientID string `json:"identityClientID,omitempty"`
+}
+
 // SecurityProfile specifies the Security profile settings for a
 // virtual machine or virtual machine scale set.
 type SecurityProfile struct {
@@ -877,6 +1471,23 @@ type CloudProviderConfigOverrides struct {
 	RateLimits []RateLimitSpec `json:"rateLimits,omitempty"`
 	// +optional
 	BackOffs BackOffConfig `json:"backOffs,omitempty"`
+	// LoadBalancerSKU is the SKU of the load balancers created by the cloud provider. Defaults to Standard.
+	// +kubebuilder:validation:Enum=Standard;Basic
+	// +optional
+	LoadBalancerSKU string `json:"loadBalancerSku,omitempty"`
+	// UseInstanceMetadata specifies whether the cloud provider uses the instance metadata service to retrieve
+	// instance information. Defaults to true.
+	// +optional
+	UseInstanceMetadata *bool `json:"useInstanceMetadata,omitempty"`
+	// ExcludeMasterFromStandardLB specifies whether control plane nodes are excluded from the backend pools of
+	// Standard load balancers created by the cloud provider. Defaults to the cloud provider default.
+	// +optional
+	ExcludeMasterFromStandardLB *bool `json:"excludeMasterFromStandardLB,omitempty"`
+	// MaximumLoadBalancerRuleCount is the maximum number of load balancing rules the cloud provider creates
+	// on a single load balancer. Defaults to 250.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaximumLoadBalancerRuleCount *int `json:"maximumLoadBalancerRuleCount,omitempty"`
 }
 
 // BackOffConfig indicates the back-off config options.
@@ -989,6 +1600,54 @@ type AzureBastion struct {
 	EnableTunneling bool `json:"enableTunneling,omitempty"`
 }
 
+// TrafficRoutingMethod is the method used by a Traffic Manager profile to route traffic between its endpoints.
+// +kubebuilder:validation:Enum=Priority;Weighted
+type TrafficRoutingMethod string
+
+const (
+	// TrafficRoutingMethodPriority routes all traffic to the healthy endpoint with the lowest priority value.
+	TrafficRoutingMethodPriority TrafficRoutingMethod = "Priority"
+	// TrafficRoutingMethodWeighted distributes traffic across healthy endpoints according to their weights.
+	TrafficRoutingMethodWeighted TrafficRoutingMethod = "Weighted"
+)
+
+// TrafficManagerSpec specifies how the cluster's API server endpoint is registered with an Azure Traffic Manager profile.
+type TrafficManagerSpec struct {
+	// ProfileName is the name of the Traffic Manager profile. It is also used as the relative DNS name of the profile.
+	// The profile is created if it does not exist.
+	// +kubebuilder:validation:MinLength=1
+	ProfileName string `json:"profileName"`
+
+	// ResourceGroup is the existing resource group of the Traffic Manager profile. It can't be the resource group of
+	// the cluster, which is deleted with the cluster, since the profile is shared with the endpoints of other clusters.
+	// +kubebuilder:validation:MinLength=1
+	ResourceGroup string `json:"resourceGroup"`
+
+	// RoutingMethod is the traffic routing method used when the profile is created. Defaults to Priority.
+	// It is ignored when the profile already exists.
+	// +kubebuilder:default=Priority
+	// +optional
+	RoutingMethod TrafficRoutingMethod `json:"routingMethod,omitempty"`
+
+	// EndpointName is the name of the Traffic Manager endpoint for this cluster's API server.
+	// Defaults to the name of the cluster.
+	// +optional
+	EndpointName string `json:"endpointName,omitempty"`
+
+	// Priority is the priority of this cluster's endpoint when the Priority routing method is used.
+	// Lower values represent higher priority. No two endpoints in a profile can share the same priority.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	Priority *int64 `json:"priority,omitempty"`
+
+	// Weight is the weight of this cluster's endpoint when the Weighted routing method is used.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	Weight *int64 `json:"weight,omitempty"`
+}
+
 // BackendPool describes the backend pool of the load balancer.
 type BackendPool struct {
 	// Name specifies the name of backend pool for the load balancer. If not specified, the default name will
@@ -1072,3 +1731,48 @@ const (
 	// UniformOrchestrationMode treats VMs as identical instances accessible by the VMSS VM API.
 	UniformOrchestrationMode OrchestrationModeType = "Uniform"
 )
+
+// VMPatchStatus describes the OS patch status of a virtual machine as reported by Azure.
+type VMPatchStatus struct {
+	// AssessmentStatus is the status of the latest patch assessment, e.g. Succeeded, Failed or InProgress.
+	// +optional
+	AssessmentStatus string `json:"assessmentStatus,omitempty"`
+
+	// LastAssessmentTime is the time the latest patch assessment was last modified.
+	// +optional
+	LastAssessmentTime *metav1.Time `json:"lastAssessmentTime,omitempty"`
+
+	// CriticalAndSecurityPatchCount is the number of critical or security patches available and not yet installed.
+	// +optional
+	CriticalAndSecurityPatchCount int32 `json:"criticalAndSecurityPatchCount,omitempty"`
+
+	// OtherPatchCount is the number of other patches available and not yet installed.
+	// +optional
+	OtherPatchCount int32 `json:"otherPatchCount,omitempty"`
+
+	// RebootPending indicates the virtual machine needs to be rebooted to complete the installation of patches.
+	// +optional
+	RebootPending bool `json:"rebootPending,omitempty"`
+
+	// InstallationStatus is the status of the latest patch installation, e.g. Succeeded, Failed or InProgress.
+	// +optional
+	InstallationStatus string `json:"installationStatus,omitempty"`
+
+	// FailedPatchCount is the number of patches that failed to install during the latest patch installation.
+	// +optional
+	FailedPatchCount int32 `json:"failedPatchCount,omitempty"`
+}
+
+// MinimumVMResources defines the minimum resources the VM size of a machine must have.
+type MinimumVMResources struct {
+	// VCPUs is the minimum number of vCPUs of the VM size. Defaults to 2.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	VCPUs *int64 `json:"vCPUs,omitempty"`
+
+	// MemoryGiB is the minimum memory of the VM size, in GiB. Defaults to 2.
+	// Set it to 0 to allow VM sizes with less than 1 GiB of memory.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MemoryGiB *int64 `json:"memoryGiB,omitempty"`
+}
@@ -54,8 +54,15 @@ type AzureClusterClassSpec struct {
 	// Some values for the cloud provider config are inferred from other parts of cluster api provider azure spec, and may not be available for overrides.
 	// See: https://cloud-provider-azure.sigs.k8s.io/install/configs
 	// Note: All cloud provider config values can be customized by creating the secret beforehand. CloudProviderConfigOverrides is only used when the secret is managed by the Azure Provider.
+	// Changes are written to the managed secrets and take effect on nodes whose cloud provider configuration is read after the change.
 	// +optional
 	CloudProviderConfigOverrides *CloudProviderConfigOverrides `json:"cloudProviderConfigOverrides,omitempty"`
+
+	// VMSizes are the VM sizes used by the machines of the cluster.
+	// The availability zones of the location in which each of them is available are reported in the VMSizeZones
+	// status field, so that failure domains of MachineDeployments can be chosen accordingly.
+	// +optional
+	VMSizes []string `json:"vmSizes,omitempty"`
 }
 
 // ExtendedLocationSpec defines the ExtendedLocation properties to enable CAPZ for Azure public MEC.
@@ -91,8 +98,10 @@ type SubnetClassSpec struct {
 	// Name defines a name for the subnet resource.
 	Name string `json:"name"`
 
-	// Role defines the subnet role (eg. Node, ControlPlane)
-	// +kubebuilder:validation:Enum=node;control-plane;bastion
+	// Role defines the subnet role (eg. Node, ControlPlane).
+	// The ingress, egress and bastion-only roles carve subnets for other services out of the virtual network,
+	// with a network security group generated for the role.
+	// +kubebuilder:validation:Enum=node;control-plane;bastion;netapp;firewall;ingress;egress;bastion-only
 	Role SubnetRole `json:"role"`
 
 	// CIDRBlocks defines the subnet's address space, specified as one or more address prefixes in CIDR notation.
@@ -103,6 +112,12 @@ type SubnetClassSpec struct {
 	// +optional
 	ServiceEndpoints ServiceEndpoints `json:"serviceEndpoints,omitempty"`
 
+	// ServiceEndpointPolicies is a list of resource IDs of existing Service Endpoint Policies to associate with the subnet.
+	// They restrict the storage accounts reachable through the subnet's Microsoft.Storage service endpoint,
+	// which must be enabled in ServiceEndpoints.
+	// +optional
+	ServiceEndpointPolicies []string `json:"serviceEndpointPolicies,omitempty"`
+
 	// PrivateEndpoints defines a list of private endpoints that should be attached to this subnet.
 	// +optional
 	PrivateEndpoints PrivateEndpoints `json:"privateEndpoints,omitempty"`
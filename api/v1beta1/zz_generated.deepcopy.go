@@ -107,6 +107,11 @@ func (in *AdditionalCapabilities) DeepCopyInto(out *AdditionalCapabilities) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.HibernationEnabled != nil {
+		in, out := &in.HibernationEnabled, &out.HibernationEnabled
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalCapabilities.
@@ -181,6 +186,22 @@ func (in *AllowedNamespaces) DeepCopy() *AllowedNamespaces {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationGatewaySpec) DeepCopyInto(out *ApplicationGatewaySpec) {
+	*out = *in
+	in.PublicIP.DeepCopyInto(&out.PublicIP)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationGatewaySpec.
+func (in *ApplicationGatewaySpec) DeepCopy() *ApplicationGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutoScalerProfile) DeepCopyInto(out *AutoScalerProfile) {
 	*out = *in
@@ -366,6 +387,11 @@ func (in *AzureClusterClassSpec) DeepCopyInto(out *AzureClusterClassSpec) {
 		*out = new(CloudProviderConfigOverrides)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.VMSizes != nil {
+		in, out := &in.VMSizes, &out.VMSizes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterClassSpec.
@@ -518,6 +544,11 @@ func (in *AzureClusterSpec) DeepCopyInto(out *AzureClusterSpec) {
 	in.AzureClusterClassSpec.DeepCopyInto(&out.AzureClusterClassSpec)
 	in.NetworkSpec.DeepCopyInto(&out.NetworkSpec)
 	in.BastionSpec.DeepCopyInto(&out.BastionSpec)
+	if in.TrafficManager != nil {
+		in, out := &in.TrafficManager, &out.TrafficManager
+		*out = new(TrafficManagerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
 }
 
@@ -553,6 +584,18 @@ func (in *AzureClusterStatus) DeepCopyInto(out *AzureClusterStatus) {
 		*out = make(Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.RetainedResources != nil {
+		in, out := &in.RetainedResources, &out.RetainedResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VMSizeZones != nil {
+		in, out := &in.VMSizeZones, &out.VMSizeZones
+		*out = make([]VMSizeZones, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterStatus.
@@ -703,6 +746,28 @@ func (in *AzureComputeGalleryImage) DeepCopy() *AzureComputeGalleryImage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureFirewallSpec) DeepCopyInto(out *AzureFirewallSpec) {
+	*out = *in
+	in.Subnet.DeepCopyInto(&out.Subnet)
+	in.PublicIP.DeepCopyInto(&out.PublicIP)
+	if in.AllowedFQDNs != nil {
+		in, out := &in.AllowedFQDNs, &out.AllowedFQDNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureFirewallSpec.
+func (in *AzureFirewallSpec) DeepCopy() *AzureFirewallSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureFirewallSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachine) DeepCopyInto(out *AzureMachine) {
 	*out = *in
@@ -770,6 +835,16 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.VMSizeClassRef != nil {
+		in, out := &in.VMSizeClassRef, &out.VMSizeClassRef
+		*out = new(VMSizeClassReference)
+		**out = **in
+	}
+	if in.MinimumVMResources != nil {
+		in, out := &in.MinimumVMResources, &out.MinimumVMResources
+		*out = new(MinimumVMResources)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.FailureDomain != nil {
 		in, out := &in.FailureDomain, &out.FailureDomain
 		*out = new(string)
@@ -798,6 +873,16 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DiskSnapshot != nil {
+		in, out := &in.DiskSnapshot, &out.DiskSnapshot
+		*out = new(DiskSnapshot)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalSSHPublicKeys != nil {
+		in, out := &in.AdditionalSSHPublicKeys, &out.AdditionalSSHPublicKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.AdditionalTags != nil {
 		in, out := &in.AdditionalTags, &out.AdditionalTags
 		*out = make(Tags, len(*in))
@@ -825,11 +910,40 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 		*out = new(SpotVMOptions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TerminateNotificationTimeout != nil {
+		in, out := &in.TerminateNotificationTimeout, &out.TerminateNotificationTimeout
+		*out = new(int)
+		**out = **in
+	}
+	if in.DedicatedHost != nil {
+		in, out := &in.DedicatedHost, &out.DedicatedHost
+		*out = new(DedicatedHost)
+		**out = **in
+	}
 	if in.SecurityProfile != nil {
 		in, out := &in.SecurityProfile, &out.SecurityProfile
 		*out = new(SecurityProfile)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.VaultSecrets != nil {
+		in, out := &in.VaultSecrets, &out.VaultSecrets
+		*out = make([]VaultSecretGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VMGalleryApplications != nil {
+		in, out := &in.VMGalleryApplications, &out.VMGalleryApplications
+		*out = make([]VMGalleryApplication, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BootstrapEncryption != nil {
+		in, out := &in.BootstrapEncryption, &out.BootstrapEncryption
+		*out = new(BootstrapEncryption)
+		**out = **in
+	}
 	if in.DNSServers != nil {
 		in, out := &in.DNSServers, &out.DNSServers
 		*out = make([]string, len(*in))
@@ -842,6 +956,13 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RunCommands != nil {
+		in, out := &in.RunCommands, &out.RunCommands
+		*out = make([]RunCommand, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.NetworkInterfaces != nil {
 		in, out := &in.NetworkInterfaces, &out.NetworkInterfaces
 		*out = make([]NetworkInterface, len(*in))
@@ -896,6 +1017,11 @@ func (in *AzureMachineStatus) DeepCopyInto(out *AzureMachineStatus) {
 		*out = make(Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.RetainedResources != nil {
+		in, out := &in.RetainedResources, &out.RetainedResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineStatus.
@@ -1237,6 +1363,16 @@ func (in *AzureManagedControlPlaneSpec) DeepCopyInto(out *AzureManagedControlPla
 		*out = new(HTTPProxyConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.WindowsProfile != nil {
+		in, out := &in.WindowsProfile, &out.WindowsProfile
+		*out = new(ManagedControlPlaneWindowsProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OIDCIssuerProfile != nil {
+		in, out := &in.OIDCIssuerProfile, &out.OIDCIssuerProfile
+		*out = new(OIDCIssuerProfile)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureManagedControlPlaneSpec.
@@ -1264,6 +1400,21 @@ func (in *AzureManagedControlPlaneStatus) DeepCopyInto(out *AzureManagedControlP
 		*out = make(Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.ControlPlaneIdentity != nil {
+		in, out := &in.ControlPlaneIdentity, &out.ControlPlaneIdentity
+		*out = new(ManagedClusterIdentityStatus)
+		**out = **in
+	}
+	if in.KubeletIdentity != nil {
+		in, out := &in.KubeletIdentity, &out.KubeletIdentity
+		*out = new(ManagedClusterIdentityStatus)
+		**out = **in
+	}
+	if in.OIDCIssuerProfile != nil {
+		in, out := &in.OIDCIssuerProfile, &out.OIDCIssuerProfile
+		*out = new(OIDCIssuerProfileStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureManagedControlPlaneStatus.
@@ -1412,6 +1563,21 @@ func (in *AzureManagedMachinePoolSpec) DeepCopyInto(out *AzureManagedMachinePool
 		*out = new(string)
 		**out = **in
 	}
+	if in.ProximityPlacementGroupID != nil {
+		in, out := &in.ProximityPlacementGroupID, &out.ProximityPlacementGroupID
+		*out = new(string)
+		**out = **in
+	}
+	if in.CapacityReservationGroupID != nil {
+		in, out := &in.CapacityReservationGroupID, &out.CapacityReservationGroupID
+		*out = new(string)
+		**out = **in
+	}
+	if in.HostGroupID != nil {
+		in, out := &in.HostGroupID, &out.HostGroupID
+		*out = new(string)
+		**out = **in
+	}
 	if in.ScaleSetPriority != nil {
 		in, out := &in.ScaleSetPriority, &out.ScaleSetPriority
 		*out = new(string)
@@ -1422,6 +1588,11 @@ func (in *AzureManagedMachinePoolSpec) DeepCopyInto(out *AzureManagedMachinePool
 		*out = new(string)
 		**out = **in
 	}
+	if in.PowerState != nil {
+		in, out := &in.PowerState, &out.PowerState
+		*out = new(AgentPoolPowerState)
+		**out = **in
+	}
 	if in.SpotMaxPrice != nil {
 		in, out := &in.SpotMaxPrice, &out.SpotMaxPrice
 		x := (*in).DeepCopy()
@@ -1547,6 +1718,86 @@ func (in *AzureSharedGalleryImage) DeepCopy() *AzureSharedGalleryImage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureVMSizeCatalog) DeepCopyInto(out *AzureVMSizeCatalog) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureVMSizeCatalog.
+func (in *AzureVMSizeCatalog) DeepCopy() *AzureVMSizeCatalog {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureVMSizeCatalog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureVMSizeCatalog) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureVMSizeCatalogList) DeepCopyInto(out *AzureVMSizeCatalogList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AzureVMSizeCatalog, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureVMSizeCatalogList.
+func (in *AzureVMSizeCatalogList) DeepCopy() *AzureVMSizeCatalogList {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureVMSizeCatalogList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureVMSizeCatalogList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureVMSizeCatalogSpec) DeepCopyInto(out *AzureVMSizeCatalogSpec) {
+	*out = *in
+	if in.SizeClasses != nil {
+		in, out := &in.SizeClasses, &out.SizeClasses
+		*out = make([]VMSizeClass, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureVMSizeCatalogSpec.
+func (in *AzureVMSizeCatalogSpec) DeepCopy() *AzureVMSizeCatalogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureVMSizeCatalogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackOffConfig) DeepCopyInto(out *BackOffConfig) {
 	*out = *in
@@ -1647,6 +1898,21 @@ func (in *BootDiagnostics) DeepCopy() *BootDiagnostics {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapEncryption) DeepCopyInto(out *BootstrapEncryption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapEncryption.
+func (in *BootstrapEncryption) DeepCopy() *BootstrapEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildParams) DeepCopyInto(out *BuildParams) {
 	*out = *in
@@ -1690,6 +1956,21 @@ func (in *CloudProviderConfigOverrides) DeepCopyInto(out *CloudProviderConfigOve
 		}
 	}
 	in.BackOffs.DeepCopyInto(&out.BackOffs)
+	if in.UseInstanceMetadata != nil {
+		in, out := &in.UseInstanceMetadata, &out.UseInstanceMetadata
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExcludeMasterFromStandardLB != nil {
+		in, out := &in.ExcludeMasterFromStandardLB, &out.ExcludeMasterFromStandardLB
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaximumLoadBalancerRuleCount != nil {
+		in, out := &in.MaximumLoadBalancerRuleCount, &out.MaximumLoadBalancerRuleCount
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudProviderConfigOverrides.
@@ -1727,6 +2008,21 @@ func (in *DataDisk) DeepCopy() *DataDisk {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DedicatedHost) DeepCopyInto(out *DedicatedHost) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DedicatedHost.
+func (in *DedicatedHost) DeepCopy() *DedicatedHost {
+	if in == nil {
+		return nil
+	}
+	out := new(DedicatedHost)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Diagnostics) DeepCopyInto(out *Diagnostics) {
 	*out = *in
@@ -1777,6 +2073,26 @@ func (in *DiskEncryptionSetParameters) DeepCopy() *DiskEncryptionSetParameters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskSnapshot) DeepCopyInto(out *DiskSnapshot) {
+	*out = *in
+	if in.RetentionPeriod != nil {
+		in, out := &in.RetentionPeriod, &out.RetentionPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskSnapshot.
+func (in *DiskSnapshot) DeepCopy() *DiskSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExtendedLocationSpec) DeepCopyInto(out *ExtendedLocationSpec) {
 	*out = *in
@@ -1800,6 +2116,11 @@ func (in *FrontendIP) DeepCopyInto(out *FrontendIP) {
 		*out = new(PublicIPSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Subnet != nil {
+		in, out := &in.Subnet, &out.Subnet
+		*out = new(FrontendIPSubnet)
+		**out = **in
+	}
 	out.FrontendIPClass = in.FrontendIPClass
 }
 
@@ -1828,6 +2149,21 @@ func (in *FrontendIPClass) DeepCopy() *FrontendIPClass {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FrontendIPSubnet) DeepCopyInto(out *FrontendIPSubnet) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrontendIPSubnet.
+func (in *FrontendIPSubnet) DeepCopy() *FrontendIPSubnet {
+	if in == nil {
+		return nil
+	}
+	out := new(FrontendIPSubnet)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Future) DeepCopyInto(out *Future) {
 	*out = *in
@@ -1950,6 +2286,13 @@ func (in *Image) DeepCopyInto(out *Image) {
 		*out = new(AzureComputeGalleryImage)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LocationOverrides != nil {
+		in, out := &in.LocationOverrides, &out.LocationOverrides
+		*out = make([]ImageLocationOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Image.
@@ -1962,6 +2305,41 @@ func (in *Image) DeepCopy() *Image {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageLocationOverride) DeepCopyInto(out *ImageLocationOverride) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.SharedGallery != nil {
+		in, out := &in.SharedGallery, &out.SharedGallery
+		*out = new(AzureSharedGalleryImage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Marketplace != nil {
+		in, out := &in.Marketplace, &out.Marketplace
+		*out = new(AzureMarketplaceImage)
+		**out = **in
+	}
+	if in.ComputeGallery != nil {
+		in, out := &in.ComputeGallery, &out.ComputeGallery
+		*out = new(AzureComputeGalleryImage)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageLocationOverride.
+func (in *ImageLocationOverride) DeepCopy() *ImageLocationOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageLocationOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImagePlan) DeepCopyInto(out *ImagePlan) {
 	*out = *in
@@ -2158,6 +2536,13 @@ func (in *LoadBalancerSpec) DeepCopyInto(out *LoadBalancerSpec) {
 		**out = **in
 	}
 	out.BackendPool = in.BackendPool
+	if in.AdditionalOutboundRules != nil {
+		in, out := &in.AdditionalOutboundRules, &out.AdditionalOutboundRules
+		*out = make([]OutboundRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.LoadBalancerClassSpec.DeepCopyInto(&out.LoadBalancerClassSpec)
 }
 
@@ -2172,12 +2557,27 @@ func (in *LoadBalancerSpec) DeepCopy() *LoadBalancerSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagedControlPlaneSubnet) DeepCopyInto(out *ManagedControlPlaneSubnet) {
+func (in *ManagedClusterIdentityStatus) DeepCopyInto(out *ManagedClusterIdentityStatus) {
 	*out = *in
-	if in.ServiceEndpoints != nil {
-		in, out := &in.ServiceEndpoints, &out.ServiceEndpoints
-		*out = make(ServiceEndpoints, len(*in))
-		for i := range *in {
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedClusterIdentityStatus.
+func (in *ManagedClusterIdentityStatus) DeepCopy() *ManagedClusterIdentityStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedClusterIdentityStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedControlPlaneSubnet) DeepCopyInto(out *ManagedControlPlaneSubnet) {
+	*out = *in
+	if in.ServiceEndpoints != nil {
+		in, out := &in.ServiceEndpoints, &out.ServiceEndpoints
+		*out = make(ServiceEndpoints, len(*in))
+		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
@@ -2216,6 +2616,32 @@ func (in *ManagedControlPlaneVirtualNetwork) DeepCopy() *ManagedControlPlaneVirt
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedControlPlaneWindowsProfile) DeepCopyInto(out *ManagedControlPlaneWindowsProfile) {
+	*out = *in
+	out.AdminPasswordSecretRef = in.AdminPasswordSecretRef
+	if in.LicenseType != nil {
+		in, out := &in.LicenseType, &out.LicenseType
+		*out = new(ManagedControlPlaneLicenseType)
+		**out = **in
+	}
+	if in.GmsaProfile != nil {
+		in, out := &in.GmsaProfile, &out.GmsaProfile
+		*out = new(WindowsGmsaProfile)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedControlPlaneWindowsProfile.
+func (in *ManagedControlPlaneWindowsProfile) DeepCopy() *ManagedControlPlaneWindowsProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedControlPlaneWindowsProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedDiskParameters) DeepCopyInto(out *ManagedDiskParameters) {
 	*out = *in
@@ -2229,6 +2655,21 @@ func (in *ManagedDiskParameters) DeepCopyInto(out *ManagedDiskParameters) {
 		*out = new(VMDiskSecurityProfile)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MaxShares != nil {
+		in, out := &in.MaxShares, &out.MaxShares
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DiskIOPSReadWrite != nil {
+		in, out := &in.DiskIOPSReadWrite, &out.DiskIOPSReadWrite
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DiskMBpsReadWrite != nil {
+		in, out := &in.DiskMBpsReadWrite, &out.DiskMBpsReadWrite
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedDiskParameters.
@@ -2266,6 +2707,31 @@ func (in *ManagedMachinePoolScaling) DeepCopy() *ManagedMachinePoolScaling {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MinimumVMResources) DeepCopyInto(out *MinimumVMResources) {
+	*out = *in
+	if in.VCPUs != nil {
+		in, out := &in.VCPUs, &out.VCPUs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MemoryGiB != nil {
+		in, out := &in.MemoryGiB, &out.MemoryGiB
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinimumVMResources.
+func (in *MinimumVMResources) DeepCopy() *MinimumVMResources {
+	if in == nil {
+		return nil
+	}
+	out := new(MinimumVMResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NatGateway) DeepCopyInto(out *NatGateway) {
 	*out = *in
@@ -2298,6 +2764,62 @@ func (in *NatGatewayClassSpec) DeepCopy() *NatGatewayClassSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetAppAccount) DeepCopyInto(out *NetAppAccount) {
+	*out = *in
+	if in.CapacityPools != nil {
+		in, out := &in.CapacityPools, &out.CapacityPools
+		*out = make([]NetAppCapacityPool, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetAppAccount.
+func (in *NetAppAccount) DeepCopy() *NetAppAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(NetAppAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetAppCapacityPool) DeepCopyInto(out *NetAppCapacityPool) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetAppCapacityPool.
+func (in *NetAppCapacityPool) DeepCopy() *NetAppCapacityPool {
+	if in == nil {
+		return nil
+	}
+	out := new(NetAppCapacityPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetAppSpec) DeepCopyInto(out *NetAppSpec) {
+	*out = *in
+	in.Subnet.DeepCopyInto(&out.Subnet)
+	if in.Account != nil {
+		in, out := &in.Account, &out.Account
+		*out = new(NetAppAccount)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetAppSpec.
+func (in *NetAppSpec) DeepCopy() *NetAppSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetAppSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkClassSpec) DeepCopyInto(out *NetworkClassSpec) {
 	*out = *in
@@ -2355,6 +2877,26 @@ func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
 		*out = new(LoadBalancerSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodeSubnetPool != nil {
+		in, out := &in.NodeSubnetPool, &out.NodeSubnetPool
+		*out = new(SubnetPoolSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetApp != nil {
+		in, out := &in.NetApp, &out.NetApp
+		*out = new(NetAppSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AzureFirewall != nil {
+		in, out := &in.AzureFirewall, &out.AzureFirewall
+		*out = new(AzureFirewallSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ApplicationGateway != nil {
+		in, out := &in.ApplicationGateway, &out.ApplicationGateway
+		*out = new(ApplicationGatewaySpec)
+		(*in).DeepCopyInto(*out)
+	}
 	out.NetworkClassSpec = in.NetworkClassSpec
 }
 
@@ -2403,6 +2945,46 @@ func (in *NetworkTemplateSpec) DeepCopy() *NetworkTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCIssuerProfile) DeepCopyInto(out *OIDCIssuerProfile) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCIssuerProfile.
+func (in *OIDCIssuerProfile) DeepCopy() *OIDCIssuerProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCIssuerProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCIssuerProfileStatus) DeepCopyInto(out *OIDCIssuerProfileStatus) {
+	*out = *in
+	if in.IssuerURL != nil {
+		in, out := &in.IssuerURL, &out.IssuerURL
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCIssuerProfileStatus.
+func (in *OIDCIssuerProfileStatus) DeepCopy() *OIDCIssuerProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCIssuerProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OSDisk) DeepCopyInto(out *OSDisk) {
 	*out = *in
@@ -2433,6 +3015,33 @@ func (in *OSDisk) DeepCopy() *OSDisk {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutboundRule) DeepCopyInto(out *OutboundRule) {
+	*out = *in
+	if in.FrontendIPsCount != nil {
+		in, out := &in.FrontendIPsCount, &out.FrontendIPsCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FrontendIPs != nil {
+		in, out := &in.FrontendIPs, &out.FrontendIPs
+		*out = make([]FrontendIP, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutboundRule.
+func (in *OutboundRule) DeepCopy() *OutboundRule {
+	if in == nil {
+		return nil
+	}
+	out := new(OutboundRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PrivateEndpointSpec) DeepCopyInto(out *PrivateEndpointSpec) {
 	*out = *in
@@ -2514,6 +3123,11 @@ func (in *PublicIPSpec) DeepCopyInto(out *PublicIPSpec) {
 		*out = make([]IPTag, len(*in))
 		copy(*out, *in)
 	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicIPSpec.
@@ -2582,6 +3196,46 @@ func (in *RouteTable) DeepCopy() *RouteTable {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunCommand) DeepCopyInto(out *RunCommand) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]RunCommandParameter, len(*in))
+		copy(*out, *in)
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunCommand.
+func (in *RunCommand) DeepCopy() *RunCommand {
+	if in == nil {
+		return nil
+	}
+	out := new(RunCommand)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunCommandParameter) DeepCopyInto(out *RunCommandParameter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunCommandParameter.
+func (in *RunCommandParameter) DeepCopy() *RunCommandParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(RunCommandParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecurityGroup) DeepCopyInto(out *SecurityGroup) {
 	*out = *in
@@ -2749,6 +3403,26 @@ func (in ServiceEndpoints) DeepCopy() ServiceEndpoints {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpotRestorePolicy) DeepCopyInto(out *SpotRestorePolicy) {
+	*out = *in
+	if in.RestoreTimeout != nil {
+		in, out := &in.RestoreTimeout, &out.RestoreTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpotRestorePolicy.
+func (in *SpotRestorePolicy) DeepCopy() *SpotRestorePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SpotRestorePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpotVMOptions) DeepCopyInto(out *SpotVMOptions) {
 	*out = *in
@@ -2762,6 +3436,11 @@ func (in *SpotVMOptions) DeepCopyInto(out *SpotVMOptions) {
 		*out = new(SpotEvictionPolicy)
 		**out = **in
 	}
+	if in.RestorePolicy != nil {
+		in, out := &in.RestorePolicy, &out.RestorePolicy
+		*out = new(SpotRestorePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpotVMOptions.
@@ -2789,6 +3468,11 @@ func (in *SubnetClassSpec) DeepCopyInto(out *SubnetClassSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ServiceEndpointPolicies != nil {
+		in, out := &in.ServiceEndpointPolicies, &out.ServiceEndpointPolicies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.PrivateEndpoints != nil {
 		in, out := &in.PrivateEndpoints, &out.PrivateEndpoints
 		*out = make(PrivateEndpoints, len(*in))
@@ -2808,6 +3492,26 @@ func (in *SubnetClassSpec) DeepCopy() *SubnetClassSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubnetPoolSpec) DeepCopyInto(out *SubnetPoolSpec) {
+	*out = *in
+	if in.PrefixLength != nil {
+		in, out := &in.PrefixLength, &out.PrefixLength
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetPoolSpec.
+func (in *SubnetPoolSpec) DeepCopy() *SubnetPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SubnetPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubnetSpec) DeepCopyInto(out *SubnetSpec) {
 	*out = *in
@@ -3112,6 +3816,31 @@ func (in Taints) DeepCopy() Taints {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficManagerSpec) DeepCopyInto(out *TrafficManagerSpec) {
+	*out = *in
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficManagerSpec.
+func (in *TrafficManagerSpec) DeepCopy() *TrafficManagerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficManagerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UefiSettings) DeepCopyInto(out *UefiSettings) {
 	*out = *in
@@ -3204,6 +3933,16 @@ func (in *VMExtension) DeepCopyInto(out *VMExtension) {
 			(*out)[key] = val
 		}
 	}
+	if in.AutoUpgradeMinorVersion != nil {
+		in, out := &in.AutoUpgradeMinorVersion, &out.AutoUpgradeMinorVersion
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableAutomaticUpgrade != nil {
+		in, out := &in.EnableAutomaticUpgrade, &out.EnableAutomaticUpgrade
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMExtension.
@@ -3216,6 +3955,135 @@ func (in *VMExtension) DeepCopy() *VMExtension {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMGalleryApplication) DeepCopyInto(out *VMGalleryApplication) {
+	*out = *in
+	if in.Order != nil {
+		in, out := &in.Order, &out.Order
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMGalleryApplication.
+func (in *VMGalleryApplication) DeepCopy() *VMGalleryApplication {
+	if in == nil {
+		return nil
+	}
+	out := new(VMGalleryApplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMPatchStatus) DeepCopyInto(out *VMPatchStatus) {
+	*out = *in
+	if in.LastAssessmentTime != nil {
+		in, out := &in.LastAssessmentTime, &out.LastAssessmentTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMPatchStatus.
+func (in *VMPatchStatus) DeepCopy() *VMPatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VMPatchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMSizeClass) DeepCopyInto(out *VMSizeClass) {
+	*out = *in
+	if in.VMSizes != nil {
+		in, out := &in.VMSizes, &out.VMSizes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMSizeClass.
+func (in *VMSizeClass) DeepCopy() *VMSizeClass {
+	if in == nil {
+		return nil
+	}
+	out := new(VMSizeClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMSizeClassReference) DeepCopyInto(out *VMSizeClassReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMSizeClassReference.
+func (in *VMSizeClassReference) DeepCopy() *VMSizeClassReference {
+	if in == nil {
+		return nil
+	}
+	out := new(VMSizeClassReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMSizeZones) DeepCopyInto(out *VMSizeZones) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMSizeZones.
+func (in *VMSizeZones) DeepCopy() *VMSizeZones {
+	if in == nil {
+		return nil
+	}
+	out := new(VMSizeZones)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultCertificate) DeepCopyInto(out *VaultCertificate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultCertificate.
+func (in *VaultCertificate) DeepCopy() *VaultCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretGroup) DeepCopyInto(out *VaultSecretGroup) {
+	*out = *in
+	if in.VaultCertificates != nil {
+		in, out := &in.VaultCertificates, &out.VaultCertificates
+		*out = make([]VaultCertificate, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretGroup.
+func (in *VaultSecretGroup) DeepCopy() *VaultSecretGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VnetClassSpec) DeepCopyInto(out *VnetClassSpec) {
 	*out = *in
@@ -3398,3 +4266,18 @@ func (in *VnetTemplateSpec) DeepCopy() *VnetTemplateSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WindowsGmsaProfile) DeepCopyInto(out *WindowsGmsaProfile) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WindowsGmsaProfile.
+func (in *WindowsGmsaProfile) DeepCopy() *WindowsGmsaProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(WindowsGmsaProfile)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -23,6 +23,12 @@ const (
 	// for annotation formatting rules.
 	VMTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-vm"
 
+	// VMSSTagsLastAppliedAnnotation is the key for the AzureMachinePool object annotation
+	// which tracks the AdditionalTags of the scale set.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	VMSSTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-vmss"
+
 	// RGTagsLastAppliedAnnotation is the key for the Azure Cluster object annotation
 	// which tracks the AdditionalTags for Resource Group which is part in the Azure Cluster.
 	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
@@ -46,4 +52,47 @@ const (
 	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
 	// for annotation formatting rules.
 	CustomDataHashAnnotation = "sigs.k8s.io/cluster-api-provider-azure-vmss-custom-data-hash"
+
+	// WindowsAdminPasswordSecretVersionAnnotation is the key for the managed control plane object annotation
+	// which tracks the UID and resource version of the Windows admin password Secret last applied to the managed cluster.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	WindowsAdminPasswordSecretVersionAnnotation = "sigs.k8s.io/cluster-api-provider-azure-windows-admin-password-secret-version"
+
+	// BootstrapDataSecretAnnotation is the key for the machine pool object annotation
+	// which tracks the name of the bootstrap data secret last applied to the scale set model.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	BootstrapDataSecretAnnotation = "sigs.k8s.io/cluster-api-provider-azure-vmss-bootstrap-data-secret"
+
+	// UserAgentSuffixAnnotation is the key for the Cluster object annotation
+	// whose value is appended to the User-Agent of all Azure API requests made for the cluster.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	UserAgentSuffixAnnotation = "sigs.k8s.io/cluster-api-provider-azure-user-agent-suffix"
+
+	// BasicSKUMigrationAnnotation is the key for the AzureCluster object annotation
+	// which, when set to "true", migrates the Basic load balancers and public IPs of the cluster to the Standard SKU.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	BasicSKUMigrationAnnotation = "sigs.k8s.io/cluster-api-provider-azure-migrate-basic-sku"
+
+	// VMDeallocatedForUpdateAnnotation is the key for the machine object annotation
+	// which tracks that the VM was deallocated by CAPZ to update its additional capabilities or resize its OS disk, and
+	// must be started again.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	VMDeallocatedForUpdateAnnotation = "sigs.k8s.io/cluster-api-provider-azure-vm-deallocated-for-update"
+
+	// EstimatedHourlyCostAnnotation is the key for the machine and machine pool object annotation
+	// which tracks the estimated hourly cost in USD of a single VM, including its managed OS disk.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	EstimatedHourlyCostAnnotation = "sigs.k8s.io/cluster-api-provider-azure-estimated-hourly-cost-usd"
+
+	// ResourceGroupWhatIfAnnotation is the key for the cluster object annotation which records the changes reported by
+	// the last ARM what-if operation run before CAPZ created or updated the cluster resource group.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	ResourceGroupWhatIfAnnotation = "sigs.k8s.io/cluster-api-provider-azure-resource-group-what-if"
 )
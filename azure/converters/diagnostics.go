@@ -50,3 +50,24 @@ func GetDiagnosticsProfile(diagnostics *infrav1.Diagnostics) *compute.Diagnostic
 
 	return nil
 }
+
+// SDKToDiagnostics converts an Azure SDK DiagnosticsProfile to the CAPZ Diagnostics type.
+func SDKToDiagnostics(profile *compute.DiagnosticsProfile) *infrav1.Diagnostics {
+	if profile == nil || profile.BootDiagnostics == nil {
+		return nil
+	}
+
+	boot := &infrav1.BootDiagnostics{}
+	switch {
+	case !ptr.Deref(profile.BootDiagnostics.Enabled, false):
+		boot.StorageAccountType = infrav1.DisabledDiagnosticsStorage
+	case ptr.Deref(profile.BootDiagnostics.StorageURI, "") == "":
+		boot.StorageAccountType = infrav1.ManagedDiagnosticsStorage
+	default:
+		boot.StorageAccountType = infrav1.UserManagedDiagnosticsStorage
+		boot.UserManaged = &infrav1.UserManagedBootDiagnostics{
+			StorageAccountURI: *profile.BootDiagnostics.StorageURI,
+		}
+	}
+	return &infrav1.Diagnostics{Boot: boot}
+}
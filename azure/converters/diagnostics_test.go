@@ -93,3 +93,31 @@ func TestGetDiagnosticsProfile(t *testing.T) {
 		})
 	}
 }
+
+func FuzzDiagnosticsRoundTrip(f *testing.F) {
+	f.Add(uint8(0), "")
+	f.Add(uint8(2), "https://fake")
+	f.Fuzz(func(t *testing.T, storageAccountType uint8, storageAccountURI string) {
+		types := []infrav1.BootDiagnosticsStorageAccountType{
+			infrav1.DisabledDiagnosticsStorage,
+			infrav1.ManagedDiagnosticsStorage,
+			infrav1.UserManagedDiagnosticsStorage,
+		}
+		diagnostics := &infrav1.Diagnostics{
+			Boot: &infrav1.BootDiagnostics{
+				StorageAccountType: types[int(storageAccountType)%len(types)],
+			},
+		}
+		if diagnostics.Boot.StorageAccountType == infrav1.UserManagedDiagnosticsStorage {
+			if storageAccountURI == "" {
+				t.Skip("user managed diagnostics require a storage account URI")
+			}
+			diagnostics.Boot.UserManaged = &infrav1.UserManagedBootDiagnostics{
+				StorageAccountURI: storageAccountURI,
+			}
+		}
+		if got := SDKToDiagnostics(GetDiagnosticsProfile(diagnostics)); !cmp.Equal(got, diagnostics) {
+			t.Errorf("round trip mismatch (-want +got):\n%s", cmp.Diff(diagnostics, got))
+		}
+	})
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// SDKToOSDisk converts an Azure SDK OSDisk to the CAPZ OSDisk type.
+func SDKToOSDisk(disk *compute.OSDisk) infrav1.OSDisk {
+	if disk == nil {
+		return infrav1.OSDisk{}
+	}
+
+	osDisk := infrav1.OSDisk{
+		OSType:      string(disk.OsType),
+		DiskSizeGB:  disk.DiskSizeGB,
+		CachingType: string(disk.Caching),
+	}
+	if disk.DiffDiskSettings != nil {
+		osDisk.DiffDiskSettings = &infrav1.DiffDiskSettings{
+			Option:    string(disk.DiffDiskSettings.Option),
+			Placement: infrav1.DiffDiskPlacement(disk.DiffDiskSettings.Placement),
+		}
+	}
+	if disk.ManagedDisk != nil {
+		osDisk.ManagedDisk = sdkToManagedDisk(disk.ManagedDisk.StorageAccountType, disk.ManagedDisk.DiskEncryptionSet, disk.ManagedDisk.SecurityProfile)
+	}
+	return osDisk
+}
+
+// SDKToVMSSOSDisk converts an Azure SDK VirtualMachineScaleSetOSDisk to the CAPZ OSDisk type.
+func SDKToVMSSOSDisk(disk *compute.VirtualMachineScaleSetOSDisk) infrav1.OSDisk {
+	if disk == nil {
+		return infrav1.OSDisk{}
+	}
+
+	osDisk := infrav1.OSDisk{
+		OSType:      string(disk.OsType),
+		DiskSizeGB:  disk.DiskSizeGB,
+		CachingType: string(disk.Caching),
+	}
+	if disk.DiffDiskSettings != nil {
+		osDisk.DiffDiskSettings = &infrav1.DiffDiskSettings{
+			Option:    string(disk.DiffDiskSettings.Option),
+			Placement: infrav1.DiffDiskPlacement(disk.DiffDiskSettings.Placement),
+		}
+	}
+	if disk.ManagedDisk != nil {
+		osDisk.ManagedDisk = sdkToManagedDisk(disk.ManagedDisk.StorageAccountType, disk.ManagedDisk.DiskEncryptionSet, disk.ManagedDisk.SecurityProfile)
+	}
+	return osDisk
+}
+
+// SDKToDataDisks converts the Azure SDK DataDisks of the VM with the given name to the CAPZ DataDisk type.
+// Disks named after the VM were created along with it and get their name suffix back. Any other
+// disk was attached to the VM, so it is converted to an existing managed disk referenced by its ID.
+func SDKToDataDisks(vmName string, disks *[]compute.DataDisk) []infrav1.DataDisk {
+	if disks == nil || len(*disks) == 0 {
+		return nil
+	}
+
+	dataDisks := make([]infrav1.DataDisk, 0, len(*disks))
+	for _, disk := range *disks {
+		name := ptr.Deref(disk.Name, "")
+		dataDisk := infrav1.DataDisk{
+			Lun:         disk.Lun,
+			CachingType: string(disk.Caching),
+		}
+		if prefix := vmName + "_"; strings.HasPrefix(name, prefix) {
+			dataDisk.NameSuffix = strings.TrimPrefix(name, prefix)
+			dataDisk.DiskSizeGB = ptr.Deref(disk.DiskSizeGB, 0)
+			if disk.ManagedDisk != nil {
+				dataDisk.ManagedDisk = sdkToManagedDisk(disk.ManagedDisk.StorageAccountType, disk.ManagedDisk.DiskEncryptionSet, nil)
+			}
+		} else {
+			dataDisk.NameSuffix = name
+			if disk.ManagedDisk != nil {
+				dataDisk.ManagedDiskID = ptr.Deref(disk.ManagedDisk.ID, "")
+			}
+		}
+		dataDisks = append(dataDisks, dataDisk)
+	}
+	return dataDisks
+}
+
+// SDKToVMSSDataDisks converts the Azure SDK VirtualMachineScaleSetDataDisks of the scale set with the
+// given name to the CAPZ DataDisk type.
+func SDKToVMSSDataDisks(vmssName string, disks *[]compute.VirtualMachineScaleSetDataDisk) []infrav1.DataDisk {
+	if disks == nil || len(*disks) == 0 {
+		return nil
+	}
+
+	dataDisks := make([]infrav1.DataDisk, 0, len(*disks))
+	for _, disk := range *disks {
+		dataDisk := infrav1.DataDisk{
+			NameSuffix:  strings.TrimPrefix(ptr.Deref(disk.Name, ""), vmssName+"_"),
+			DiskSizeGB:  ptr.Deref(disk.DiskSizeGB, 0),
+			Lun:         disk.Lun,
+			CachingType: string(disk.Caching),
+		}
+		if disk.ManagedDisk != nil {
+			dataDisk.ManagedDisk = sdkToManagedDisk(disk.ManagedDisk.StorageAccountType, disk.ManagedDisk.DiskEncryptionSet, nil)
+		}
+		dataDisks = append(dataDisks, dataDisk)
+	}
+	return dataDisks
+}
+
+func sdkToManagedDisk(storageAccountType compute.StorageAccountTypes, diskEncryptionSet *compute.DiskEncryptionSetParameters, securityProfile *compute.VMDiskSecurityProfile) *infrav1.ManagedDiskParameters {
+	managedDisk := &infrav1.ManagedDiskParameters{
+		StorageAccountType: string(storageAccountType),
+	}
+	if diskEncryptionSet != nil {
+		managedDisk.DiskEncryptionSet = &infrav1.DiskEncryptionSetParameters{ID: ptr.Deref(diskEncryptionSet.ID, "")}
+	}
+	if securityProfile != nil {
+		managedDisk.SecurityProfile = &infrav1.VMDiskSecurityProfile{
+			SecurityEncryptionType: infrav1.SecurityEncryptionType(securityProfile.SecurityEncryptionType),
+		}
+		if securityProfile.DiskEncryptionSet != nil {
+			managedDisk.SecurityProfile.DiskEncryptionSet = &infrav1.DiskEncryptionSetParameters{ID: ptr.Deref(securityProfile.DiskEncryptionSet.ID, "")}
+		}
+	}
+	return managedDisk
+}
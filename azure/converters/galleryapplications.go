@@ -0,0 +1,66 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// VMGalleryApplicationsToSDK converts CAPZ gallery applications to a compute application profile.
+// It returns nil when there are no applications so that the application profile is left unset.
+func VMGalleryApplicationsToSDK(apps []infrav1.VMGalleryApplication) *compute.ApplicationProfile {
+	if len(apps) == 0 {
+		return nil
+	}
+
+	galleryApplications := make([]compute.VMGalleryApplication, 0, len(apps))
+	for _, app := range apps {
+		galleryApplication := compute.VMGalleryApplication{
+			PackageReferenceID: ptr.To(app.Version),
+			Order:              app.Order,
+		}
+		if app.ConfigurationReference != "" {
+			galleryApplication.ConfigurationReference = ptr.To(app.ConfigurationReference)
+		}
+		if app.Tags != "" {
+			galleryApplication.Tags = ptr.To(app.Tags)
+		}
+		galleryApplications = append(galleryApplications, galleryApplication)
+	}
+
+	return &compute.ApplicationProfile{GalleryApplications: &galleryApplications}
+}
+
+// SDKToVMGalleryApplications converts an Azure SDK ApplicationProfile to the CAPZ VMGalleryApplication type.
+func SDKToVMGalleryApplications(profile *compute.ApplicationProfile) []infrav1.VMGalleryApplication {
+	if profile == nil || profile.GalleryApplications == nil || len(*profile.GalleryApplications) == 0 {
+		return nil
+	}
+
+	apps := make([]infrav1.VMGalleryApplication, 0, len(*profile.GalleryApplications))
+	for _, app := range *profile.GalleryApplications {
+		apps = append(apps, infrav1.VMGalleryApplication{
+			Version:                ptr.Deref(app.PackageReferenceID, ""),
+			Order:                  app.Order,
+			ConfigurationReference: ptr.Deref(app.ConfigurationReference, ""),
+			Tags:                   ptr.Deref(app.Tags, ""),
+		})
+	}
+	return apps
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestVMGalleryApplicationsToSDK(t *testing.T) {
+	versionID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/galleries/my-gallery/applications/my-agent/versions/1.0.0"
+
+	tests := []struct {
+		name  string
+		input []infrav1.VMGalleryApplication
+		want  *compute.ApplicationProfile
+	}{
+		{
+			name:  "nil applications",
+			input: nil,
+			want:  nil,
+		},
+		{
+			name:  "application with only a version",
+			input: []infrav1.VMGalleryApplication{{Version: versionID}},
+			want: &compute.ApplicationProfile{
+				GalleryApplications: &[]compute.VMGalleryApplication{
+					{PackageReferenceID: ptr.To(versionID)},
+				},
+			},
+		},
+		{
+			name: "application with all options",
+			input: []infrav1.VMGalleryApplication{
+				{
+					Version:                versionID,
+					Order:                  ptr.To[int32](1),
+					ConfigurationReference: "https://mystorage.blob.core.windows.net/config/agent.json",
+					Tags:                   "env=prod",
+				},
+			},
+			want: &compute.ApplicationProfile{
+				GalleryApplications: &[]compute.VMGalleryApplication{
+					{
+						PackageReferenceID:     ptr.To(versionID),
+						Order:                  ptr.To[int32](1),
+						ConfigurationReference: ptr.To("https://mystorage.blob.core.windows.net/config/agent.json"),
+						Tags:                   ptr.To("env=prod"),
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(VMGalleryApplicationsToSDK(tt.input)).To(Equal(tt.want))
+		})
+	}
+}
+
+func FuzzVMGalleryApplicationsRoundTrip(f *testing.F) {
+	f.Add("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/galleries/my-gallery/applications/my-app/versions/1.0.0", true, int32(1), "https://config", "tag")
+	f.Add("version", false, int32(0), "", "")
+	f.Fuzz(func(t *testing.T, version string, hasOrder bool, order int32, configurationReference, tags string) {
+		g := NewWithT(t)
+		app := infrav1.VMGalleryApplication{
+			Version:                version,
+			ConfigurationReference: configurationReference,
+			Tags:                   tags,
+		}
+		if hasOrder {
+			app.Order = ptr.To(order)
+		}
+		apps := []infrav1.VMGalleryApplication{app}
+		g.Expect(SDKToVMGalleryApplications(VMGalleryApplicationsToSDK(apps))).To(Equal(apps))
+	})
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package converters
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
@@ -86,3 +87,47 @@ func UserAssignedIdentitiesToVMSSSDK(identities []infrav1.UserAssignedIdentity)
 func sanitized(id string) string {
 	return strings.TrimPrefix(id, azureutil.ProviderIDPrefix)
 }
+
+// SDKToVMIdentity converts an Azure SDK VirtualMachineIdentity to the CAPZ VMIdentity and UserAssignedIdentity types.
+func SDKToVMIdentity(identity *compute.VirtualMachineIdentity) (infrav1.VMIdentity, []infrav1.UserAssignedIdentity) {
+	if identity == nil {
+		return infrav1.VMIdentityNone, nil
+	}
+	ids := make([]string, 0, len(identity.UserAssignedIdentities))
+	for id := range identity.UserAssignedIdentities {
+		ids = append(ids, id)
+	}
+	return sdkToIdentity(identity.Type, ids)
+}
+
+// SDKToVMSSIdentity converts an Azure SDK VirtualMachineScaleSetIdentity to the CAPZ VMIdentity and UserAssignedIdentity types.
+func SDKToVMSSIdentity(identity *compute.VirtualMachineScaleSetIdentity) (infrav1.VMIdentity, []infrav1.UserAssignedIdentity) {
+	if identity == nil {
+		return infrav1.VMIdentityNone, nil
+	}
+	ids := make([]string, 0, len(identity.UserAssignedIdentities))
+	for id := range identity.UserAssignedIdentities {
+		ids = append(ids, id)
+	}
+	return sdkToIdentity(identity.Type, ids)
+}
+
+// sdkToIdentity restores the provider ID prefix stripped by sanitized. The identities are
+// sorted by ID since the SDK returns them as a map.
+func sdkToIdentity(identityType compute.ResourceIdentityType, ids []string) (infrav1.VMIdentity, []infrav1.UserAssignedIdentity) {
+	switch identityType {
+	case compute.ResourceIdentityTypeSystemAssigned:
+		return infrav1.VMIdentitySystemAssigned, nil
+	case compute.ResourceIdentityTypeUserAssigned:
+		sort.Strings(ids)
+		identities := make([]infrav1.UserAssignedIdentity, 0, len(ids))
+		for _, id := range ids {
+			identities = append(identities, infrav1.UserAssignedIdentity{
+				ProviderID: azureutil.ProviderIDPrefix + id,
+			})
+		}
+		return infrav1.VMIdentityUserAssigned, identities
+	default:
+		return infrav1.VMIdentityNone, nil
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
 	. "github.com/onsi/gomega"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 )
 
 var sampleSubjectFactory = []infrav1.UserAssignedIdentity{
@@ -178,3 +179,36 @@ func Test_UserAssignedIdentitiesToVMSSSDK(t *testing.T) {
 		})
 	}
 }
+
+func FuzzVMIdentityRoundTrip(f *testing.F) {
+	f.Add("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/a", "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/b")
+	f.Add("a", "b")
+	f.Fuzz(func(t *testing.T, first, second string) {
+		if first == second {
+			t.Skip("identities with the same ID are merged")
+		}
+		if first > second {
+			first, second = second, first
+		}
+		g := NewWithT(t)
+		identities := []infrav1.UserAssignedIdentity{
+			{ProviderID: azureutil.ProviderIDPrefix + first},
+			{ProviderID: azureutil.ProviderIDPrefix + second},
+		}
+
+		vmIdentity, err := VMIdentityToVMSDK(infrav1.VMIdentityUserAssigned, identities)
+		g.Expect(err).NotTo(HaveOccurred())
+		identity, userAssignedIdentities := SDKToVMIdentity(vmIdentity)
+		g.Expect(identity).To(Equal(infrav1.VMIdentityUserAssigned))
+		g.Expect(userAssignedIdentities).To(Equal(identities))
+
+		vmssIdentities, err := UserAssignedIdentitiesToVMSSSDK(identities)
+		g.Expect(err).NotTo(HaveOccurred())
+		identity, userAssignedIdentities = SDKToVMSSIdentity(&compute.VirtualMachineScaleSetIdentity{
+			Type:                   compute.ResourceIdentityTypeUserAssigned,
+			UserAssignedIdentities: vmssIdentities,
+		})
+		g.Expect(identity).To(Equal(infrav1.VMIdentityUserAssigned))
+		g.Expect(userAssignedIdentities).To(Equal(identities))
+	})
+}
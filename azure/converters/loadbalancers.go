@@ -17,7 +17,10 @@ limitations under the License.
 package converters
 
 import (
+	"path"
+
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
@@ -28,3 +31,73 @@ func SKUtoSDK(src infrav1.SKU) network.LoadBalancerSkuName {
 	}
 	return ""
 }
+
+// SDKToSKU converts an Azure SDK load balancer SKU name to the CAPZ SKU type.
+func SDKToSKU(src *network.LoadBalancerSku) infrav1.SKU {
+	if src != nil && src.Name == network.LoadBalancerSkuNameStandard {
+		return infrav1.SKUStandard
+	}
+	return ""
+}
+
+// SDKToLoadBalancer converts an Azure SDK LoadBalancer to the CAPZ LoadBalancerSpec type.
+// The load balancer is internal when any of its frontends has a private IP address.
+// Frontend subnets and additional outbound rules are derived from the cluster network
+// spec when the load balancer is created, so they are not reconstructed.
+func SDKToLoadBalancer(lb network.LoadBalancer) infrav1.LoadBalancerSpec {
+	spec := infrav1.LoadBalancerSpec{
+		ID:   ptr.Deref(lb.ID, ""),
+		Name: ptr.Deref(lb.Name, ""),
+		LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+			SKU:  SDKToSKU(lb.Sku),
+			Type: infrav1.Public,
+		},
+	}
+	if lb.LoadBalancerPropertiesFormat == nil {
+		return spec
+	}
+
+	if lb.FrontendIPConfigurations != nil {
+		for _, config := range *lb.FrontendIPConfigurations {
+			frontendIP := infrav1.FrontendIP{
+				Name: ptr.Deref(config.Name, ""),
+			}
+			if props := config.FrontendIPConfigurationPropertiesFormat; props != nil {
+				if props.PrivateIPAddress != nil {
+					frontendIP.PrivateIPAddress = *props.PrivateIPAddress
+					spec.Type = infrav1.Internal
+				}
+				if props.PublicIPAddress != nil && props.PublicIPAddress.ID != nil {
+					frontendIP.PublicIP = &infrav1.PublicIPSpec{
+						Name: path.Base(*props.PublicIPAddress.ID),
+					}
+				}
+			}
+			spec.FrontendIPs = append(spec.FrontendIPs, frontendIP)
+		}
+	}
+
+	if lb.BackendAddressPools != nil && len(*lb.BackendAddressPools) > 0 {
+		spec.BackendPool.Name = ptr.Deref((*lb.BackendAddressPools)[0].Name, "")
+	}
+
+	// The idle timeout is set on every rule, so the first rule carrying one is enough.
+	if lb.OutboundRules != nil {
+		for _, rule := range *lb.OutboundRules {
+			if rule.OutboundRulePropertiesFormat != nil && rule.IdleTimeoutInMinutes != nil {
+				spec.IdleTimeoutInMinutes = rule.IdleTimeoutInMinutes
+				break
+			}
+		}
+	}
+	if spec.IdleTimeoutInMinutes == nil && lb.LoadBalancingRules != nil {
+		for _, rule := range *lb.LoadBalancingRules {
+			if rule.LoadBalancingRulePropertiesFormat != nil && rule.IdleTimeoutInMinutes != nil {
+				spec.IdleTimeoutInMinutes = rule.IdleTimeoutInMinutes
+				break
+			}
+		}
+	}
+
+	return spec
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
 	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
@@ -52,3 +53,129 @@ func TestSKUtoSDK(t *testing.T) {
 		})
 	}
 }
+
+func TestSDKToLoadBalancer(t *testing.T) {
+	tests := []struct {
+		name string
+		lb   network.LoadBalancer
+		want infrav1.LoadBalancerSpec
+	}{
+		{
+			name: "without properties",
+			lb: network.LoadBalancer{
+				ID:   ptr.To("lb-id"),
+				Name: ptr.To("my-lb"),
+			},
+			want: infrav1.LoadBalancerSpec{
+				ID:   "lb-id",
+				Name: "my-lb",
+				LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+					Type: infrav1.Public,
+				},
+			},
+		},
+		{
+			name: "public load balancer",
+			lb: network.LoadBalancer{
+				ID:   ptr.To("lb-id"),
+				Name: ptr.To("my-lb"),
+				Sku:  &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
+				LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+					FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+						{
+							Name: ptr.To("my-lb-frontEnd"),
+							FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+								PublicIPAddress: &network.PublicIPAddress{
+									ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-publicip"),
+								},
+							},
+						},
+					},
+					BackendAddressPools: &[]network.BackendAddressPool{
+						{Name: ptr.To("my-lb-backendPool")},
+						{Name: ptr.To("my-lb-outboundBackendPool")},
+					},
+					OutboundRules: &[]network.OutboundRule{
+						{
+							Name: ptr.To("OutboundNATAllProtocols"),
+							OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
+								IdleTimeoutInMinutes: ptr.To[int32](4),
+							},
+						},
+					},
+				},
+			},
+			want: infrav1.LoadBalancerSpec{
+				ID:   "lb-id",
+				Name: "my-lb",
+				FrontendIPs: []infrav1.FrontendIP{
+					{
+						Name:     "my-lb-frontEnd",
+						PublicIP: &infrav1.PublicIPSpec{Name: "my-publicip"},
+					},
+				},
+				BackendPool: infrav1.BackendPool{Name: "my-lb-backendPool"},
+				LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+					SKU:                  infrav1.SKUStandard,
+					Type:                 infrav1.Public,
+					IdleTimeoutInMinutes: ptr.To[int32](4),
+				},
+			},
+		},
+		{
+			name: "internal load balancer",
+			lb: network.LoadBalancer{
+				Name: ptr.To("my-lb"),
+				Sku:  &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
+				LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+					FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+						{
+							Name: ptr.To("my-lb-frontEnd"),
+							FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+								PrivateIPAddress: ptr.To("10.0.0.100"),
+							},
+						},
+					},
+					BackendAddressPools: &[]network.BackendAddressPool{
+						{Name: ptr.To("my-lb-backendPool")},
+					},
+					LoadBalancingRules: &[]network.LoadBalancingRule{
+						{
+							Name: ptr.To("LBRuleHTTPS"),
+							LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
+								IdleTimeoutInMinutes: ptr.To[int32](30),
+							},
+						},
+					},
+				},
+			},
+			want: infrav1.LoadBalancerSpec{
+				Name: "my-lb",
+				FrontendIPs: []infrav1.FrontendIP{
+					{
+						Name: "my-lb-frontEnd",
+						FrontendIPClass: infrav1.FrontendIPClass{
+							PrivateIPAddress: "10.0.0.100",
+						},
+					},
+				},
+				BackendPool: infrav1.BackendPool{Name: "my-lb-backendPool"},
+				LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+					SKU:                  infrav1.SKUStandard,
+					Type:                 infrav1.Internal,
+					IdleTimeoutInMinutes: ptr.To[int32](30),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := SDKToLoadBalancer(tt.lb)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("SDKToLoadBalancer(%s) mismatch (-want +got):\n%s", tt.name, diff)
+			}
+		})
+	}
+}
@@ -37,11 +37,18 @@ func SDKAvailabilityStatusToCondition(availStatus resourcehealth.AvailabilitySta
 		return conditions.TrueCondition(infrav1.AzureResourceAvailableCondition)
 	}
 
+	// The reason type tells whether the event was planned, unplanned, user initiated, etc. When it isn't set, the
+	// cause of the health event (e.g. "PlatformInitiated") still distinguishes Azure issues from user actions.
+	reasonType := availStatus.Properties.ReasonType
+	if reasonType == nil {
+		reasonType = availStatus.Properties.HealthEventCause
+	}
+
 	var reason strings.Builder
-	if availStatus.Properties.ReasonType != nil {
+	if reasonType != nil {
 		// CAPI specifies Reason should be CamelCase, though the Azure API
 		// response may include spaces (e.g. "Customer Initiated")
-		words := strings.Split(*availStatus.Properties.ReasonType, " ")
+		words := strings.Split(*reasonType, " ")
 		for _, word := range words {
 			if len(word) > 0 {
 				reason.WriteString(strings.ToTitle(word[:1]))
@@ -65,5 +72,18 @@ func SDKAvailabilityStatusToCondition(availStatus resourcehealth.AvailabilitySta
 		message = *availStatus.Properties.Summary
 	}
 
+	// Ongoing service incidents, such as regional outages, that may be impacting the resource.
+	if availStatus.Properties.ServiceImpactingEvents != nil {
+		var incidents []string
+		for _, event := range *availStatus.Properties.ServiceImpactingEvents {
+			if event.IncidentProperties != nil && event.IncidentProperties.Title != nil {
+				incidents = append(incidents, *event.IncidentProperties.Title)
+			}
+		}
+		if len(incidents) > 0 {
+			message = strings.TrimSpace(message + " Service impacting events: " + strings.Join(incidents, "; "))
+		}
+	}
+
 	return conditions.FalseCondition(infrav1.AzureResourceAvailableCondition, reason.String(), severity, message)
 }
@@ -82,6 +82,34 @@ func TestAzureAvailabilityStatusToCondition(t *testing.T) {
 				Message:  "The Summary",
 			},
 		},
+		{
+			name: "unavailable due to a platform incident",
+			avail: resourcehealth.AvailabilityStatus{
+				Properties: &resourcehealth.AvailabilityStatusProperties{
+					AvailabilityState: resourcehealth.AvailabilityStateValuesUnavailable,
+					HealthEventCause:  ptr.To("PlatformInitiated"),
+					Summary:           ptr.To("The Summary."),
+					ServiceImpactingEvents: &[]resourcehealth.ServiceImpactingEvent{
+						{
+							IncidentProperties: &resourcehealth.ServiceImpactingEventIncidentProperties{
+								Title: ptr.To("Virtual Machines - West Europe"),
+							},
+						},
+						{
+							IncidentProperties: &resourcehealth.ServiceImpactingEventIncidentProperties{
+								Title: ptr.To("Storage - West Europe"),
+							},
+						},
+					},
+				},
+			},
+			expected: &clusterv1.Condition{
+				Status:   corev1.ConditionFalse,
+				Severity: clusterv1.ConditionSeverityError,
+				Reason:   "PlatformInitiated",
+				Message:  "The Summary. Service impacting events: Virtual Machines - West Europe; Storage - West Europe",
+			},
+		},
 	}
 
 	for _, test := range tests {
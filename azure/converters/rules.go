@@ -57,3 +57,63 @@ func SecurityRuleToSDK(rule infrav1.SecurityRule) network.SecurityRule {
 
 	return secRule
 }
+
+// SDKToSecurityRule converts an Azure SDK SecurityRule to the CAPZ SecurityRule type.
+func SDKToSecurityRule(rule network.SecurityRule) infrav1.SecurityRule {
+	secRule := infrav1.SecurityRule{
+		Name: ptr.Deref(rule.Name, ""),
+	}
+	if rule.SecurityRulePropertiesFormat == nil {
+		return secRule
+	}
+
+	props := rule.SecurityRulePropertiesFormat
+	secRule.Description = ptr.Deref(props.Description, "")
+	secRule.Priority = ptr.Deref(props.Priority, 0)
+	secRule.Source = props.SourceAddressPrefix
+	secRule.SourcePorts = props.SourcePortRange
+	secRule.Destination = props.DestinationAddressPrefix
+	secRule.DestinationPorts = props.DestinationPortRange
+
+	switch props.Protocol {
+	case network.SecurityRuleProtocolAsterisk:
+		secRule.Protocol = infrav1.SecurityGroupProtocolAll
+	case network.SecurityRuleProtocolTCP:
+		secRule.Protocol = infrav1.SecurityGroupProtocolTCP
+	case network.SecurityRuleProtocolUDP:
+		secRule.Protocol = infrav1.SecurityGroupProtocolUDP
+	case network.SecurityRuleProtocolIcmp:
+		secRule.Protocol = infrav1.SecurityGroupProtocolICMP
+	}
+
+	switch props.Direction {
+	case network.SecurityRuleDirectionOutbound:
+		secRule.Direction = infrav1.SecurityRuleDirectionOutbound
+	case network.SecurityRuleDirectionInbound:
+		secRule.Direction = infrav1.SecurityRuleDirectionInbound
+	}
+
+	return secRule
+}
+
+// SDKToSecurityGroup converts an Azure SDK SecurityGroup to the CAPZ SecurityGroup type.
+// Only the rules CAPZ manages are converted: rules denying traffic are skipped since
+// SecurityRuleToSDK always creates rules allowing it.
+func SDKToSecurityGroup(sg network.SecurityGroup) infrav1.SecurityGroup {
+	securityGroup := infrav1.SecurityGroup{
+		ID:   ptr.Deref(sg.ID, ""),
+		Name: ptr.Deref(sg.Name, ""),
+	}
+	if len(sg.Tags) > 0 {
+		securityGroup.Tags = MapToTags(sg.Tags)
+	}
+	if sg.SecurityGroupPropertiesFormat != nil && sg.SecurityRules != nil {
+		for _, rule := range *sg.SecurityRules {
+			if rule.SecurityRulePropertiesFormat != nil && rule.Access == network.SecurityRuleAccessDeny {
+				continue
+			}
+			securityGroup.SecurityRules = append(securityGroup.SecurityRules, SDKToSecurityRule(rule))
+		}
+	}
+	return securityGroup
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+var (
+	securityGroupProtocols = []infrav1.SecurityGroupProtocol{
+		infrav1.SecurityGroupProtocolAll,
+		infrav1.SecurityGroupProtocolTCP,
+		infrav1.SecurityGroupProtocolUDP,
+		infrav1.SecurityGroupProtocolICMP,
+	}
+	securityRuleDirections = []infrav1.SecurityRuleDirection{
+		infrav1.SecurityRuleDirectionInbound,
+		infrav1.SecurityRuleDirectionOutbound,
+	}
+)
+
+func TestSDKToSecurityGroup(t *testing.T) {
+	g := NewWithT(t)
+
+	sg := network.SecurityGroup{
+		ID:   ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-nsg"),
+		Name: ptr.To("my-nsg"),
+		Tags: map[string]*string{"foo": ptr.To("bar")},
+		SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
+			SecurityRules: &[]network.SecurityRule{
+				SecurityRuleToSDK(infrav1.SecurityRule{
+					Name:             "allow_ssh",
+					Description:      "Allow SSH",
+					Protocol:         infrav1.SecurityGroupProtocolTCP,
+					Direction:        infrav1.SecurityRuleDirectionInbound,
+					Priority:         2200,
+					SourcePorts:      ptr.To("*"),
+					DestinationPorts: ptr.To("22"),
+					Source:           ptr.To("*"),
+					Destination:      ptr.To("*"),
+				}),
+				{
+					Name: ptr.To("deny_all"),
+					SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+						Access:   network.SecurityRuleAccessDeny,
+						Priority: ptr.To[int32](4096),
+					},
+				},
+			},
+		},
+	}
+
+	g.Expect(SDKToSecurityGroup(sg)).To(Equal(infrav1.SecurityGroup{
+		ID:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-nsg",
+		Name: "my-nsg",
+		SecurityGroupClass: infrav1.SecurityGroupClass{
+			SecurityRules: infrav1.SecurityRules{
+				{
+					Name:             "allow_ssh",
+					Description:      "Allow SSH",
+					Protocol:         infrav1.SecurityGroupProtocolTCP,
+					Direction:        infrav1.SecurityRuleDirectionInbound,
+					Priority:         2200,
+					SourcePorts:      ptr.To("*"),
+					DestinationPorts: ptr.To("22"),
+					Source:           ptr.To("*"),
+					Destination:      ptr.To("*"),
+				},
+			},
+			Tags: infrav1.Tags{"foo": "bar"},
+		},
+	}))
+}
+
+func FuzzSecurityRuleRoundTrip(f *testing.F) {
+	f.Add("allow_ssh", "Allow SSH", uint8(1), uint8(0), int32(2200), "*", "22", "*", "*")
+	f.Add("allow_all_out", "", uint8(0), uint8(1), int32(100), "", "", "10.0.0.0/16", "Internet")
+	f.Fuzz(func(t *testing.T, name, description string, protocol, direction uint8, priority int32, sourcePorts, destinationPorts, source, destination string) {
+		g := NewWithT(t)
+		rule := infrav1.SecurityRule{
+			Name:             name,
+			Description:      description,
+			Protocol:         securityGroupProtocols[int(protocol)%len(securityGroupProtocols)],
+			Direction:        securityRuleDirections[int(direction)%len(securityRuleDirections)],
+			Priority:         priority,
+			SourcePorts:      ptr.To(sourcePorts),
+			DestinationPorts: ptr.To(destinationPorts),
+			Source:           ptr.To(source),
+			Destination:      ptr.To(destination),
+		}
+		g.Expect(SDKToSecurityRule(SecurityRuleToSDK(rule))).To(Equal(rule))
+	})
+}
@@ -20,6 +20,8 @@ import (
 	"strconv"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
@@ -49,3 +51,24 @@ func GetSpotVMOptions(spotVMOptions *infrav1.SpotVMOptions, diffDiskSettings *in
 
 	return compute.VirtualMachinePriorityTypesSpot, evictionPolicy, billingProfile, nil
 }
+
+// SDKToSpotVMOptions converts the Azure SDK priority, eviction policy and billing profile of a VM
+// to the CAPZ SpotVMOptions type. It returns nil for VMs that are not Spot VMs.
+func SDKToSpotVMOptions(priority compute.VirtualMachinePriorityTypes, evictionPolicy compute.VirtualMachineEvictionPolicyTypes, billingProfile *compute.BillingProfile) (*infrav1.SpotVMOptions, error) {
+	if priority != compute.VirtualMachinePriorityTypesSpot {
+		return nil, nil
+	}
+
+	spotVMOptions := &infrav1.SpotVMOptions{}
+	if billingProfile != nil && billingProfile.MaxPrice != nil {
+		maxPrice, err := resource.ParseQuantity(strconv.FormatFloat(*billingProfile.MaxPrice, 'f', -1, 64))
+		if err != nil {
+			return nil, err
+		}
+		spotVMOptions.MaxPrice = &maxPrice
+	}
+	if evictionPolicy != "" {
+		spotVMOptions.EvictionPolicy = ptr.To(infrav1.SpotEvictionPolicy(evictionPolicy))
+	}
+	return spotVMOptions, nil
+}
@@ -122,3 +122,39 @@ func TestGetSpotVMOptions(t *testing.T) {
 		})
 	}
 }
+
+func FuzzSpotVMOptionsRoundTrip(f *testing.F) {
+	f.Add(true, int64(1234), uint8(0))
+	f.Add(true, int64(-1000), uint8(1))
+	f.Add(false, int64(0), uint8(2))
+	f.Fuzz(func(t *testing.T, hasMaxPrice bool, maxPriceMillis int64, evictionPolicy uint8) {
+		// Prices are float64 in the SDK, which only represents up to 15 significant digits exactly.
+		if maxPriceMillis > 1e12 || maxPriceMillis < -1e12 {
+			t.Skip("max price out of range")
+		}
+		g := NewWithT(t)
+		policies := []*infrav1.SpotEvictionPolicy{
+			ptr.To(infrav1.SpotEvictionPolicyDeallocate),
+			ptr.To(infrav1.SpotEvictionPolicyDelete),
+			nil,
+		}
+		spot := &infrav1.SpotVMOptions{
+			EvictionPolicy: policies[int(evictionPolicy)%len(policies)],
+		}
+		if hasMaxPrice {
+			spot.MaxPrice = resource.NewMilliQuantity(maxPriceMillis, resource.DecimalSI)
+		}
+
+		priority, policy, billingProfile, err := GetSpotVMOptions(spot, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		got, err := SDKToSpotVMOptions(priority, policy, billingProfile)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(got.EvictionPolicy).To(Equal(spot.EvictionPolicy))
+		if spot.MaxPrice == nil {
+			g.Expect(got.MaxPrice).To(BeNil())
+		} else {
+			g.Expect(got.MaxPrice).NotTo(BeNil())
+			g.Expect(got.MaxPrice.Cmp(*spot.MaxPrice)).To(BeZero(), fmt.Sprintf("want %s, got %s", spot.MaxPrice, got.MaxPrice))
+		}
+	})
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// VaultSecretsToSDK converts CAPZ Key Vault secret groups to the secrets of a compute OS profile.
+// It returns nil when there are no secrets so that the OS profile is left unchanged.
+func VaultSecretsToSDK(vaultSecrets []infrav1.VaultSecretGroup) *[]compute.VaultSecretGroup {
+	if len(vaultSecrets) == 0 {
+		return nil
+	}
+
+	secrets := make([]compute.VaultSecretGroup, 0, len(vaultSecrets))
+	for _, group := range vaultSecrets {
+		certificates := make([]compute.VaultCertificate, 0, len(group.VaultCertificates))
+		for _, cert := range group.VaultCertificates {
+			certificate := compute.VaultCertificate{
+				CertificateURL: ptr.To(cert.CertificateURL),
+			}
+			if cert.CertificateStore != "" {
+				certificate.CertificateStore = ptr.To(cert.CertificateStore)
+			}
+			certificates = append(certificates, certificate)
+		}
+		secrets = append(secrets, compute.VaultSecretGroup{
+			SourceVault:       &compute.SubResource{ID: ptr.To(group.SourceVault)},
+			VaultCertificates: &certificates,
+		})
+	}
+
+	return &secrets
+}
+
+// SDKToVaultSecrets converts Azure SDK VaultSecretGroups to the CAPZ VaultSecretGroup type.
+func SDKToVaultSecrets(vaultSecrets *[]compute.VaultSecretGroup) []infrav1.VaultSecretGroup {
+	if vaultSecrets == nil || len(*vaultSecrets) == 0 {
+		return nil
+	}
+
+	secrets := make([]infrav1.VaultSecretGroup, 0, len(*vaultSecrets))
+	for _, group := range *vaultSecrets {
+		secretGroup := infrav1.VaultSecretGroup{}
+		if group.SourceVault != nil {
+			secretGroup.SourceVault = ptr.Deref(group.SourceVault.ID, "")
+		}
+		if group.VaultCertificates != nil {
+			secretGroup.VaultCertificates = make([]infrav1.VaultCertificate, 0, len(*group.VaultCertificates))
+			for _, cert := range *group.VaultCertificates {
+				secretGroup.VaultCertificates = append(secretGroup.VaultCertificates, infrav1.VaultCertificate{
+					CertificateURL:   ptr.Deref(cert.CertificateURL, ""),
+					CertificateStore: ptr.Deref(cert.CertificateStore, ""),
+				})
+			}
+		}
+		secrets = append(secrets, secretGroup)
+	}
+	return secrets
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestVaultSecretsToSDK(t *testing.T) {
+	vaultID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/my-vault"
+
+	tests := []struct {
+		name  string
+		input []infrav1.VaultSecretGroup
+		want  *[]compute.VaultSecretGroup
+	}{
+		{
+			name:  "nil secrets",
+			input: nil,
+			want:  nil,
+		},
+		{
+			name: "Linux certificate without a store",
+			input: []infrav1.VaultSecretGroup{
+				{
+					SourceVault: vaultID,
+					VaultCertificates: []infrav1.VaultCertificate{
+						{CertificateURL: "https://my-vault.vault.azure.net/secrets/my-cert/1"},
+					},
+				},
+			},
+			want: &[]compute.VaultSecretGroup{
+				{
+					SourceVault: &compute.SubResource{ID: ptr.To(vaultID)},
+					VaultCertificates: &[]compute.VaultCertificate{
+						{CertificateURL: ptr.To("https://my-vault.vault.azure.net/secrets/my-cert/1")},
+					},
+				},
+			},
+		},
+		{
+			name: "Windows certificates with stores",
+			input: []infrav1.VaultSecretGroup{
+				{
+					SourceVault: vaultID,
+					VaultCertificates: []infrav1.VaultCertificate{
+						{CertificateURL: "https://my-vault.vault.azure.net/secrets/my-cert/1", CertificateStore: "My"},
+						{CertificateURL: "https://my-vault.vault.azure.net/secrets/my-ca/1", CertificateStore: "Root"},
+					},
+				},
+			},
+			want: &[]compute.VaultSecretGroup{
+				{
+					SourceVault: &compute.SubResource{ID: ptr.To(vaultID)},
+					VaultCertificates: &[]compute.VaultCertificate{
+						{CertificateURL: ptr.To("https://my-vault.vault.azure.net/secrets/my-cert/1"), CertificateStore: ptr.To("My")},
+						{CertificateURL: ptr.To("https://my-vault.vault.azure.net/secrets/my-ca/1"), CertificateStore: ptr.To("Root")},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(VaultSecretsToSDK(tt.input)).To(Equal(tt.want))
+		})
+	}
+}
+
+func FuzzVaultSecretsRoundTrip(f *testing.F) {
+	f.Add("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/my-vault", "https://my-vault.vault.azure.net/secrets/cert/1", "My")
+	f.Add("vault", "url", "")
+	f.Fuzz(func(t *testing.T, sourceVault, certificateURL, certificateStore string) {
+		g := NewWithT(t)
+		secrets := []infrav1.VaultSecretGroup{
+			{
+				SourceVault: sourceVault,
+				VaultCertificates: []infrav1.VaultCertificate{
+					{
+						CertificateURL:   certificateURL,
+						CertificateStore: certificateStore,
+					},
+				},
+			},
+		}
+		g.Expect(SDKToVaultSecrets(VaultSecretsToSDK(secrets))).To(Equal(secrets))
+	})
+}
@@ -17,7 +17,10 @@ limitations under the License.
 package converters
 
 import (
+	"encoding/base64"
+
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -78,3 +81,91 @@ func SDKToVM(v compute.VirtualMachine) *VM {
 
 	return vm
 }
+
+// SDKToAzureMachineSpec converts an Azure SDK VirtualMachine to the CAPZ AzureMachineSpec type.
+// Fields CAPZ derives from other resources, such as the network interfaces or the provider ID,
+// are not part of the VM and are left empty.
+func SDKToAzureMachineSpec(v compute.VirtualMachine) (infrav1.AzureMachineSpec, error) {
+	spec := infrav1.AzureMachineSpec{}
+	spec.Identity, spec.UserAssignedIdentities = SDKToVMIdentity(v.Identity)
+
+	props := v.VirtualMachineProperties
+	if props == nil {
+		return spec, nil
+	}
+
+	if props.HardwareProfile != nil {
+		spec.VMSize = string(props.HardwareProfile.VMSize)
+	}
+
+	if props.StorageProfile != nil {
+		if props.StorageProfile.ImageReference != nil {
+			image := SDKImageToImage(props.StorageProfile.ImageReference, v.Plan != nil)
+			spec.Image = &image
+		}
+		spec.OSDisk = SDKToOSDisk(props.StorageProfile.OsDisk)
+		spec.DataDisks = SDKToDataDisks(ptr.Deref(v.Name, ""), props.StorageProfile.DataDisks)
+	}
+
+	if props.OsProfile != nil {
+		spec.SSHPublicKey, spec.AdditionalSSHPublicKeys = sdkToSSHPublicKeys(props.OsProfile.LinuxConfiguration)
+		spec.VaultSecrets = SDKToVaultSecrets(props.OsProfile.Secrets)
+	}
+
+	spotVMOptions, err := SDKToSpotVMOptions(props.Priority, props.EvictionPolicy, props.BillingProfile)
+	if err != nil {
+		return spec, errors.Wrap(err, "failed to convert the Spot VM options")
+	}
+	spec.SpotVMOptions = spotVMOptions
+
+	spec.SecurityProfile = SDKToSecurityProfile(props.SecurityProfile)
+	spec.AdditionalCapabilities = SDKToAdditionalCapabilities(props.AdditionalCapabilities)
+	spec.Diagnostics = SDKToDiagnostics(props.DiagnosticsProfile)
+	spec.VMGalleryApplications = SDKToVMGalleryApplications(props.ApplicationProfile)
+
+	return spec, nil
+}
+
+// SDKToSecurityProfile converts an Azure SDK SecurityProfile to the CAPZ SecurityProfile type.
+func SDKToSecurityProfile(profile *compute.SecurityProfile) *infrav1.SecurityProfile {
+	if profile == nil {
+		return nil
+	}
+
+	securityProfile := &infrav1.SecurityProfile{
+		EncryptionAtHost: profile.EncryptionAtHost,
+		SecurityType:     infrav1.SecurityTypes(profile.SecurityType),
+	}
+	if profile.UefiSettings != nil {
+		securityProfile.UefiSettings = &infrav1.UefiSettings{
+			SecureBootEnabled: profile.UefiSettings.SecureBootEnabled,
+			VTpmEnabled:       profile.UefiSettings.VTpmEnabled,
+		}
+	}
+	return securityProfile
+}
+
+// SDKToAdditionalCapabilities converts Azure SDK AdditionalCapabilities to the CAPZ AdditionalCapabilities type.
+func SDKToAdditionalCapabilities(capabilities *compute.AdditionalCapabilities) *infrav1.AdditionalCapabilities {
+	if capabilities == nil {
+		return nil
+	}
+	return &infrav1.AdditionalCapabilities{
+		UltraSSDEnabled:    capabilities.UltraSSDEnabled,
+		HibernationEnabled: capabilities.HibernationEnabled,
+	}
+}
+
+// sdkToSSHPublicKeys returns the base64 encoded first SSH public key of a Linux configuration, and the base64 encoded
+// keys following it.
+func sdkToSSHPublicKeys(config *compute.LinuxConfiguration) (string, []string) {
+	if config == nil || config.SSH == nil || config.SSH.PublicKeys == nil || len(*config.SSH.PublicKeys) == 0 {
+		return "", nil
+	}
+	publicKeys := *config.SSH.PublicKeys
+	var additionalKeys []string
+	for _, key := range publicKeys[1:] {
+		additionalKeys = append(additionalKeys, base64.StdEncoding.EncodeToString([]byte(ptr.Deref(key.KeyData, ""))))
+	}
+	return base64.StdEncoding.EncodeToString([]byte(ptr.Deref(publicKeys[0].KeyData, ""))), additionalKeys
+}
@@ -135,3 +135,148 @@ func TestSDKToVM(t *testing.T) {
 		})
 	}
 }
+
+func TestSDKToAzureMachineSpec(t *testing.T) {
+	tests := []struct {
+		name string
+		sdk  compute.VirtualMachine
+		want infrav1.AzureMachineSpec
+	}{
+		{
+			name: "without properties",
+			sdk: compute.VirtualMachine{
+				Name: ptr.To("test-vm-name"),
+			},
+			want: infrav1.AzureMachineSpec{
+				Identity: infrav1.VMIdentityNone,
+			},
+		},
+		{
+			name: "Should convert the VM model",
+			sdk: compute.VirtualMachine{
+				Name: ptr.To("test-vm-name"),
+				Identity: &compute.VirtualMachineIdentity{
+					Type: compute.ResourceIdentityTypeSystemAssigned,
+				},
+				VirtualMachineProperties: &compute.VirtualMachineProperties{
+					HardwareProfile: &compute.HardwareProfile{
+						VMSize: compute.VirtualMachineSizeTypesStandardD2sV3,
+					},
+					StorageProfile: &compute.StorageProfile{
+						ImageReference: &compute.ImageReference{
+							ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/images/my-image"),
+						},
+						OsDisk: &compute.OSDisk{
+							Name:       ptr.To("test-vm-name_OSDisk"),
+							OsType:     compute.OperatingSystemTypesLinux,
+							DiskSizeGB: ptr.To[int32](128),
+							Caching:    compute.CachingTypesReadWrite,
+							ManagedDisk: &compute.ManagedDiskParameters{
+								StorageAccountType: compute.StorageAccountTypesPremiumLRS,
+							},
+						},
+						DataDisks: &[]compute.DataDisk{
+							{
+								Name:       ptr.To("test-vm-name_etcddisk"),
+								Lun:        ptr.To[int32](0),
+								DiskSizeGB: ptr.To[int32](256),
+								ManagedDisk: &compute.ManagedDiskParameters{
+									StorageAccountType: compute.StorageAccountTypesStandardLRS,
+								},
+							},
+							{
+								Name: ptr.To("my-existing-disk"),
+								Lun:  ptr.To[int32](1),
+								ManagedDisk: &compute.ManagedDiskParameters{
+									ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-existing-disk"),
+								},
+							},
+						},
+					},
+					OsProfile: &compute.OSProfile{
+						LinuxConfiguration: &compute.LinuxConfiguration{
+							SSH: &compute.SSHConfiguration{
+								PublicKeys: &[]compute.SSHPublicKey{
+									{KeyData: ptr.To("ssh-rsa AAAA")},
+									{KeyData: ptr.To("ssh-rsa BBBB")},
+								},
+							},
+						},
+					},
+					Priority:       compute.VirtualMachinePriorityTypesSpot,
+					EvictionPolicy: compute.VirtualMachineEvictionPolicyTypesDelete,
+					SecurityProfile: &compute.SecurityProfile{
+						EncryptionAtHost: ptr.To(true),
+					},
+					DiagnosticsProfile: &compute.DiagnosticsProfile{
+						BootDiagnostics: &compute.BootDiagnostics{
+							Enabled: ptr.To(true),
+						},
+					},
+					AdditionalCapabilities: &compute.AdditionalCapabilities{
+						UltraSSDEnabled: ptr.To(true),
+					},
+				},
+			},
+			want: infrav1.AzureMachineSpec{
+				VMSize: "Standard_D2s_v3",
+				Image: &infrav1.Image{
+					ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/images/my-image"),
+				},
+				Identity: infrav1.VMIdentitySystemAssigned,
+				OSDisk: infrav1.OSDisk{
+					OSType:      "Linux",
+					DiskSizeGB:  ptr.To[int32](128),
+					CachingType: "ReadWrite",
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+					},
+				},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "etcddisk",
+						DiskSizeGB: 256,
+						Lun:        ptr.To[int32](0),
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Standard_LRS",
+						},
+					},
+					{
+						NameSuffix:    "my-existing-disk",
+						Lun:           ptr.To[int32](1),
+						ManagedDiskID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-existing-disk",
+					},
+				},
+				SSHPublicKey:            "c3NoLXJzYSBBQUFB",
+				AdditionalSSHPublicKeys: []string{"c3NoLXJzYSBCQkJC"},
+				SpotVMOptions: &infrav1.SpotVMOptions{
+					EvictionPolicy: ptr.To(infrav1.SpotEvictionPolicyDelete),
+				},
+				SecurityProfile: &infrav1.SecurityProfile{
+					EncryptionAtHost: ptr.To(true),
+				},
+				Diagnostics: &infrav1.Diagnostics{
+					Boot: &infrav1.BootDiagnostics{
+						StorageAccountType: infrav1.ManagedDiagnosticsStorage,
+					},
+				},
+				AdditionalCapabilities: &infrav1.AdditionalCapabilities{
+					UltraSSDEnabled: ptr.To(true),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := SDKToAzureMachineSpec(tt.sdk)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff between expected result and actual result:\n%s", cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
@@ -18,12 +18,16 @@ package converters
 
 import (
 	"regexp"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	azprovider "sigs.k8s.io/cloud-provider-azure/pkg/provider"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 )
 
 const (
@@ -31,6 +35,9 @@ const (
 	RegExpStrCommunityGalleryID = `/CommunityGalleries/(?P<gallery>.*)/Images/(?P<name>.*)/Versions/(?P<version>.*)`
 	// RegExpStrComputeGalleryID is a regexp string used for matching compute gallery IDs and capturing specific values.
 	RegExpStrComputeGalleryID = `/subscriptions/(?P<subID>.*)/resourceGroups/(?P<rg>.*)/providers/Microsoft.Compute/galleries/(?P<gallery>.*)/images/(?P<name>.*)/versions/(?P<version>.*)`
+
+	// powerStatePrefix is the prefix of the instance view status code that reports the power state of an instance.
+	powerStatePrefix = "PowerState/"
 )
 
 // SDKToVMSS converts an Azure SDK VirtualMachineScaleSet to the AzureMachinePool type.
@@ -103,9 +110,52 @@ func SDKVMToVMSSVM(sdkInstance compute.VirtualMachine, mode infrav1.Orchestratio
 
 	instance.OrchestrationMode = mode
 
+	if sdkInstance.InstanceView != nil {
+		instance.PatchStatus = SDKToVMPatchStatus(sdkInstance.InstanceView.PatchStatus)
+		instance.PlatformFaultDomain = sdkInstance.InstanceView.PlatformFaultDomain
+		instance.PowerState = sdkToPowerState(sdkInstance.InstanceView.Statuses)
+	}
+
 	return &instance
 }
 
+// sdkToPowerState returns the power state of an instance from the statuses of its instance view, e.g. running or
+// deallocated, or an empty string if it isn't reported.
+func sdkToPowerState(statuses *[]compute.InstanceViewStatus) string {
+	if statuses == nil {
+		return ""
+	}
+	for _, status := range *statuses {
+		if code := ptr.Deref(status.Code, ""); strings.HasPrefix(code, powerStatePrefix) {
+			return strings.TrimPrefix(code, powerStatePrefix)
+		}
+	}
+	return ""
+}
+
+// SDKToVMPatchStatus converts an Azure SDK VirtualMachinePatchStatus into an infrav1.VMPatchStatus.
+func SDKToVMPatchStatus(sdkStatus *compute.VirtualMachinePatchStatus) *infrav1.VMPatchStatus {
+	if sdkStatus == nil || (sdkStatus.AvailablePatchSummary == nil && sdkStatus.LastPatchInstallationSummary == nil) {
+		return nil
+	}
+
+	status := &infrav1.VMPatchStatus{}
+	if summary := sdkStatus.AvailablePatchSummary; summary != nil {
+		status.AssessmentStatus = string(summary.Status)
+		if summary.LastModifiedTime != nil {
+			status.LastAssessmentTime = &metav1.Time{Time: summary.LastModifiedTime.Time}
+		}
+		status.CriticalAndSecurityPatchCount = ptr.Deref(summary.CriticalAndSecurityPatchCount, 0)
+		status.OtherPatchCount = ptr.Deref(summary.OtherPatchCount, 0)
+		status.RebootPending = ptr.Deref(summary.RebootPending, false)
+	}
+	if summary := sdkStatus.LastPatchInstallationSummary; summary != nil {
+		status.InstallationStatus = string(summary.Status)
+		status.FailedPatchCount = ptr.Deref(summary.FailedPatchCount, 0)
+	}
+	return status
+}
+
 // SDKToVMSSVM converts an Azure SDK VirtualMachineScaleSetVM into an infrav1exp.VMSSVM.
 func SDKToVMSSVM(sdkInstance compute.VirtualMachineScaleSetVM) *azure.VMSSVM {
 	// Convert resourceGroup Name ID ( ProviderID in capz objects )
@@ -153,6 +203,10 @@ func SDKToVMSSVM(sdkInstance compute.VirtualMachineScaleSetVM) *azure.VMSSVM {
 		instance.AvailabilityZone = azure.StringSlice(sdkInstance.Zones)[0]
 	}
 
+	if sdkInstance.InstanceView != nil {
+		instance.PowerState = sdkToPowerState(sdkInstance.InstanceView.Statuses)
+	}
+
 	return &instance
 }
 
@@ -263,3 +317,44 @@ func getParams(regStr, str string) (matched bool, params map[string]string) {
 
 	return matched, params
 }
+
+// SDKToAzureMachinePoolMachineTemplate converts an Azure SDK VirtualMachineScaleSet to the
+// AzureMachinePoolMachineTemplate type. The identity of the scale set is part of the
+// AzureMachinePoolSpec and is converted separately by SDKToVMSSIdentity.
+func SDKToAzureMachinePoolMachineTemplate(vmss compute.VirtualMachineScaleSet) (infrav1exp.AzureMachinePoolMachineTemplate, error) {
+	template := infrav1exp.AzureMachinePoolMachineTemplate{}
+	if vmss.Sku != nil {
+		template.VMSize = ptr.Deref(vmss.Sku.Name, "")
+	}
+
+	if vmss.VirtualMachineScaleSetProperties == nil || vmss.VirtualMachineProfile == nil {
+		return template, nil
+	}
+	profile := vmss.VirtualMachineProfile
+
+	if profile.StorageProfile != nil {
+		if profile.StorageProfile.ImageReference != nil {
+			image := SDKImageToImage(profile.StorageProfile.ImageReference, vmss.Plan != nil)
+			template.Image = &image
+		}
+		template.OSDisk = SDKToVMSSOSDisk(profile.StorageProfile.OsDisk)
+		template.DataDisks = SDKToVMSSDataDisks(ptr.Deref(vmss.Name, ""), profile.StorageProfile.DataDisks)
+	}
+
+	if profile.OsProfile != nil {
+		template.SSHPublicKey, template.AdditionalSSHPublicKeys = sdkToSSHPublicKeys(profile.OsProfile.LinuxConfiguration)
+		template.VaultSecrets = SDKToVaultSecrets(profile.OsProfile.Secrets)
+	}
+
+	spotVMOptions, err := SDKToSpotVMOptions(profile.Priority, profile.EvictionPolicy, profile.BillingProfile)
+	if err != nil {
+		return template, errors.Wrap(err, "failed to convert the Spot VM options")
+	}
+	template.SpotVMOptions = spotVMOptions
+
+	template.SecurityProfile = SDKToSecurityProfile(profile.SecurityProfile)
+	template.Diagnostics = SDKToDiagnostics(profile.DiagnosticsProfile)
+	template.VMGalleryApplications = SDKToVMGalleryApplications(profile.ApplicationProfile)
+
+	return template, nil
+}
@@ -26,6 +26,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 )
 
 func Test_SDKToVMSS(t *testing.T) {
@@ -202,6 +203,28 @@ func Test_SDKToVMSSVM(t *testing.T) {
 				State:            "Creating",
 			},
 		},
+		{
+			Name: "VM with instance view",
+			SDKInstance: compute.VirtualMachineScaleSetVM{
+				ID: ptr.To("/subscriptions/foo/resourceGroups/MY_RESOURCE_GROUP/providers/bar"),
+				VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+					ProvisioningState: ptr.To(string(compute.ProvisioningState1Succeeded)),
+					OsProfile:         &compute.OSProfile{ComputerName: ptr.To("instance-000003")},
+					InstanceView: &compute.VirtualMachineScaleSetVMInstanceView{
+						Statuses: &[]compute.InstanceViewStatus{
+							{Code: ptr.To("ProvisioningState/succeeded")},
+							{Code: ptr.To("PowerState/deallocated")},
+						},
+					},
+				},
+			},
+			VMSSVM: &azure.VMSSVM{
+				ID:         "/subscriptions/foo/resourceGroups/my_resource_group/providers/bar",
+				Name:       "instance-000003",
+				State:      "Succeeded",
+				PowerState: "deallocated",
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -397,6 +420,68 @@ func Test_SDKVMToVMSSVM(t *testing.T) {
 				State: "Succeeded",
 			},
 		},
+		{
+			Name: "VM with patch status",
+			Subject: compute.VirtualMachine{
+				ID: ptr.To("vmID5"),
+				VirtualMachineProperties: &compute.VirtualMachineProperties{
+					OsProfile: &compute.OSProfile{
+						ComputerName: ptr.To("vmwithpatchstatus"),
+					},
+					ProvisioningState: ptr.To("Succeeded"),
+					InstanceView: &compute.VirtualMachineInstanceView{
+						PatchStatus: &compute.VirtualMachinePatchStatus{
+							AvailablePatchSummary: &compute.AvailablePatchSummary{
+								Status:                        compute.PatchOperationStatusSucceeded,
+								CriticalAndSecurityPatchCount: ptr.To[int32](1),
+								OtherPatchCount:               ptr.To[int32](3),
+								RebootPending:                 ptr.To(true),
+							},
+							LastPatchInstallationSummary: &compute.LastPatchInstallationSummary{
+								Status:           compute.PatchOperationStatusFailed,
+								FailedPatchCount: ptr.To[int32](2),
+							},
+						},
+					},
+				},
+			},
+			Expected: &azure.VMSSVM{
+				ID:    "vmID5",
+				Name:  "vmwithpatchstatus",
+				State: "Succeeded",
+				PatchStatus: &infrav1.VMPatchStatus{
+					AssessmentStatus:              "Succeeded",
+					CriticalAndSecurityPatchCount: 1,
+					OtherPatchCount:               3,
+					RebootPending:                 true,
+					InstallationStatus:            "Failed",
+					FailedPatchCount:              2,
+				},
+			},
+		},
+		{
+			Name: "VM with zone and fault domain",
+			Subject: compute.VirtualMachine{
+				ID:    ptr.To("vmID6"),
+				Zones: &[]string{"2"},
+				VirtualMachineProperties: &compute.VirtualMachineProperties{
+					OsProfile: &compute.OSProfile{
+						ComputerName: ptr.To("vmwithplacement"),
+					},
+					ProvisioningState: ptr.To("Succeeded"),
+					InstanceView: &compute.VirtualMachineInstanceView{
+						PlatformFaultDomain: ptr.To[int32](1),
+					},
+				},
+			},
+			Expected: &azure.VMSSVM{
+				ID:                  "vmID6",
+				Name:                "vmwithplacement",
+				State:               "Succeeded",
+				AvailabilityZone:    "2",
+				PlatformFaultDomain: ptr.To[int32](1),
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -420,3 +505,96 @@ func Test_GetOrchestrationMode(t *testing.T) {
 	g.Expect(converters.GetOrchestrationMode("invalid")).
 		To(gomega.Equal(compute.OrchestrationModeUniform))
 }
+
+func Test_SDKToAzureMachinePoolMachineTemplate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	vmss := compute.VirtualMachineScaleSet{
+		Name: ptr.To("my-vmss"),
+		Sku:  &compute.Sku{Name: ptr.To("Standard_D2s_v3")},
+		Plan: &compute.Plan{Name: ptr.To("my-sku")},
+		VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
+			VirtualMachineProfile: &compute.VirtualMachineScaleSetVMProfile{
+				StorageProfile: &compute.VirtualMachineScaleSetStorageProfile{
+					ImageReference: &compute.ImageReference{
+						Publisher: ptr.To("my-publisher"),
+						Offer:     ptr.To("my-offer"),
+						Sku:       ptr.To("my-sku"),
+						Version:   ptr.To("1.0.0"),
+					},
+					OsDisk: &compute.VirtualMachineScaleSetOSDisk{
+						OsType:     compute.OperatingSystemTypesLinux,
+						DiskSizeGB: ptr.To[int32](30),
+						DiffDiskSettings: &compute.DiffDiskSettings{
+							Option: compute.DiffDiskOptionsLocal,
+						},
+						ManagedDisk: &compute.VirtualMachineScaleSetManagedDiskParameters{
+							StorageAccountType: compute.StorageAccountTypesStandardLRS,
+						},
+					},
+					DataDisks: &[]compute.VirtualMachineScaleSetDataDisk{
+						{
+							Name:       ptr.To("my-vmss_etcddisk"),
+							Lun:        ptr.To[int32](0),
+							DiskSizeGB: ptr.To[int32](64),
+						},
+					},
+				},
+				OsProfile: &compute.VirtualMachineScaleSetOSProfile{
+					LinuxConfiguration: &compute.LinuxConfiguration{
+						SSH: &compute.SSHConfiguration{
+							PublicKeys: &[]compute.SSHPublicKey{
+								{KeyData: ptr.To("ssh-rsa AAAA")},
+							},
+						},
+					},
+				},
+				DiagnosticsProfile: &compute.DiagnosticsProfile{
+					BootDiagnostics: &compute.BootDiagnostics{
+						Enabled: ptr.To(false),
+					},
+				},
+			},
+		},
+	}
+
+	template, err := converters.SDKToAzureMachinePoolMachineTemplate(vmss)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(template).To(gomega.Equal(infrav1exp.AzureMachinePoolMachineTemplate{
+		VMSize: "Standard_D2s_v3",
+		Image: &infrav1.Image{
+			Marketplace: &infrav1.AzureMarketplaceImage{
+				ImagePlan: infrav1.ImagePlan{
+					Publisher: "my-publisher",
+					Offer:     "my-offer",
+					SKU:       "my-sku",
+				},
+				Version:         "1.0.0",
+				ThirdPartyImage: true,
+			},
+		},
+		OSDisk: infrav1.OSDisk{
+			OSType:     "Linux",
+			DiskSizeGB: ptr.To[int32](30),
+			DiffDiskSettings: &infrav1.DiffDiskSettings{
+				Option: "Local",
+			},
+			ManagedDisk: &infrav1.ManagedDiskParameters{
+				StorageAccountType: "Standard_LRS",
+			},
+		},
+		DataDisks: []infrav1.DataDisk{
+			{
+				NameSuffix: "etcddisk",
+				DiskSizeGB: 64,
+				Lun:        ptr.To[int32](0),
+			},
+		},
+		SSHPublicKey: "c3NoLXJzYSBBQUFB",
+		Diagnostics: &infrav1.Diagnostics{
+			Boot: &infrav1.BootDiagnostics{
+				StorageAccountType: infrav1.DisabledDiagnosticsStorage,
+			},
+		},
+	}))
+}
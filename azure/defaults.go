@@ -17,15 +17,26 @@ limitations under the License.
 package azure
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/go-autorest/autorest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	"sigs.k8s.io/cluster-api-provider-azure/version"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+const (
+	// ClientRequestIDHeader is the header used to identify the client request that originated an Azure API call.
+	ClientRequestIDHeader = "x-ms-client-request-id"
 )
 
 const (
@@ -64,6 +75,16 @@ const (
 	BootstrappingExtensionLinux = "CAPZ.Linux.Bootstrapping"
 	// BootstrappingExtensionWindows is the name of the Windows CAPZ bootstrapping VM extension.
 	BootstrappingExtensionWindows = "CAPZ.Windows.Bootstrapping"
+	// NvidiaGPUDriverExtensionLinux is the name of the NVIDIA GPU driver VM extension for Linux.
+	NvidiaGPUDriverExtensionLinux = "NvidiaGpuDriverLinux"
+	// NvidiaGPUDriverExtensionWindows is the name of the NVIDIA GPU driver VM extension for Windows.
+	NvidiaGPUDriverExtensionWindows = "NvidiaGpuDriverWindows"
+	// AMDGPUDriverExtensionWindows is the name of the AMD GPU driver VM extension for Windows.
+	AMDGPUDriverExtensionWindows = "AmdGpuDriverWindows"
+	// ApplicationHealthExtensionLinux is the name of the Application Health VM extension for Linux.
+	ApplicationHealthExtensionLinux = "ApplicationHealthLinux"
+	// ApplicationHealthExtensionWindows is the name of the Application Health VM extension for Windows.
+	ApplicationHealthExtensionWindows = "ApplicationHealthWindows"
 )
 
 const (
@@ -96,6 +117,26 @@ const (
 	// bootstrapSentinelFile is the file written by bootstrap provider on machines to indicate successful bootstrapping,
 	// as defined by the Cluster API Bootstrap Provider contract (https://cluster-api.sigs.k8s.io/developer/providers/bootstrap.html).
 	bootstrapSentinelFile = "/run/cluster-api/bootstrap-success.complete"
+	// kubeadmCACertFile is the file written by kubeadm on machines once it starts initializing or joining the node.
+	kubeadmCACertFile = "/etc/kubernetes/pki/ca.crt"
+
+	// DefaultBootstrapExtensionTimeout is how long the bootstrap extension waits for bootstrapping to complete by default.
+	DefaultBootstrapExtensionTimeout = bootstrapExtensionRetries * bootstrapExtensionSleep * time.Second
+	// DefaultBootstrapExtensionRetryInterval is how often the bootstrap extension checks whether bootstrapping completed
+	// by default.
+	DefaultBootstrapExtensionRetryInterval = bootstrapExtensionSleep * time.Second
+)
+
+const (
+	// BootstrapStageMarker prefixes the lines written by the Linux bootstrap extension command when bootstrapping
+	// reaches a new stage.
+	BootstrapStageMarker = "bootstrap stage: "
+	// BootstrapStageDownloading is the stage before kubeadm runs, while the bootstrap data is downloaded and processed.
+	BootstrapStageDownloading = "Downloading"
+	// BootstrapStageRunningKubeadm is the stage while kubeadm initializes or joins the node.
+	BootstrapStageRunningKubeadm = "RunningKubeadm"
+	// BootstrapStageJoined is the stage once the bootstrap provider reported a successful bootstrap.
+	BootstrapStageJoined = "Joined"
 )
 
 const (
@@ -108,12 +149,27 @@ const (
 
 var (
 	// LinuxBootstrapExtensionCommand is the command the VM bootstrap extension will execute to verify Linux nodes bootstrap completes successfully.
-	LinuxBootstrapExtensionCommand = fmt.Sprintf("for i in $(seq 1 %d); do test -f %s && break; if [ $i -eq %d ]; then exit 1; else sleep %d; fi; done", bootstrapExtensionRetries, bootstrapSentinelFile, bootstrapExtensionRetries, bootstrapExtensionSleep)
+	// Every time bootstrapping reaches a new stage, the command writes a line starting with BootstrapStageMarker to its output.
+	LinuxBootstrapExtensionCommand = linuxBootstrapExtensionCommand(bootstrapExtensionRetries, bootstrapExtensionSleep)
 	// WindowsBootstrapExtensionCommand is the command the VM bootstrap extension will execute to verify Windows nodes bootstrap completes successfully.
-	WindowsBootstrapExtensionCommand = fmt.Sprintf("powershell.exe -Command \"for ($i = 0; $i -lt %d; $i++) {if (Test-Path '%s') {exit 0} else {Start-Sleep -Seconds %d}} exit -2\"",
-		bootstrapExtensionRetries, bootstrapSentinelFile, bootstrapExtensionSleep)
+	WindowsBootstrapExtensionCommand = windowsBootstrapExtensionCommand(bootstrapExtensionRetries, bootstrapExtensionSleep)
 )
 
+// linuxBootstrapExtensionCommand returns the Linux bootstrap extension command checking whether bootstrapping completed
+// up to retries times, sleep seconds apart.
+func linuxBootstrapExtensionCommand(retries, sleep int) string {
+	return fmt.Sprintf(`stage=; for i in $(seq 1 %d); do next=%s; test -f %s && next=%s; test -f %s && next=%s; if [ "$next" != "$stage" ]; then stage=$next; echo "%s$stage"; fi; test "$stage" = %s && break; if [ $i -eq %d ]; then exit 1; else sleep %d; fi; done`,
+		retries, BootstrapStageDownloading, kubeadmCACertFile, BootstrapStageRunningKubeadm, bootstrapSentinelFile, BootstrapStageJoined,
+		BootstrapStageMarker, BootstrapStageJoined, retries, sleep)
+}
+
+// windowsBootstrapExtensionCommand returns the Windows bootstrap extension command checking whether bootstrapping
+// completed up to retries times, sleep seconds apart.
+func windowsBootstrapExtensionCommand(retries, sleep int) string {
+	return fmt.Sprintf("powershell.exe -Command \"for ($i = 0; $i -lt %d; $i++) {if (Test-Path '%s') {exit 0} else {Start-Sleep -Seconds %d}} exit -2\"",
+		retries, bootstrapSentinelFile, sleep)
+}
+
 // GenerateBackendAddressPoolName generates a load balancer backend address pool name.
 func GenerateBackendAddressPoolName(lbName string) string {
 	return fmt.Sprintf("%s-%s", lbName, "backendPool")
@@ -124,6 +180,11 @@ func GenerateOutboundBackendAddressPoolName(lbName string) string {
 	return fmt.Sprintf("%s-%s", lbName, "outboundBackendPool")
 }
 
+// GenerateOutboundRuleBackendPoolName generates the name of the backend address pool of an additional outbound rule.
+func GenerateOutboundRuleBackendPoolName(lbName, ruleName string) string {
+	return fmt.Sprintf("%s-%s-%s", lbName, ruleName, "outboundBackendPool")
+}
+
 // GenerateFrontendIPConfigName generates a load balancer frontend IP config name.
 func GenerateFrontendIPConfigName(lbName string) string {
 	return fmt.Sprintf("%s-%s", lbName, "frontEnd")
@@ -187,6 +248,17 @@ func GenerateDataDiskName(machineName, nameSuffix string) string {
 	return fmt.Sprintf("%s_%s", machineName, nameSuffix)
 }
 
+// GenerateSharedDataDiskName generates the name of a shared data disk based on the name of the cluster and of the
+// group of machines that share it.
+func GenerateSharedDataDiskName(clusterName, ownerName, nameSuffix string) string {
+	return fmt.Sprintf("%s_%s_%s", clusterName, ownerName, nameSuffix)
+}
+
+// GenerateSnapshotName generates the name of a snapshot based on the name of the disk it is taken of.
+func GenerateSnapshotName(diskName string) string {
+	return fmt.Sprintf("%s_snapshot", diskName)
+}
+
 // GenerateVnetPeeringName generates the name for a peering between two vnets.
 func GenerateVnetPeeringName(sourceVnetName string, remoteVnetName string) string {
 	return fmt.Sprintf("%s-To-%s", sourceVnetName, remoteVnetName)
@@ -215,6 +287,21 @@ func VMID(subscriptionID, resourceGroup, vmName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachines/%s", subscriptionID, resourceGroup, vmName)
 }
 
+// VMSSID returns the azure resource ID for a given virtual machine scale set.
+func VMSSID(subscriptionID, resourceGroup, vmssName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachineScaleSets/%s", subscriptionID, resourceGroup, vmssName)
+}
+
+// StandbyPoolID returns the azure resource ID for a given standby virtual machine pool.
+func StandbyPoolID(subscriptionID, resourceGroup, standbyPoolName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.StandbyPool/standbyVirtualMachinePools/%s", subscriptionID, resourceGroup, standbyPoolName)
+}
+
+// LoadBalancerID returns the azure resource ID for a given load balancer.
+func LoadBalancerID(subscriptionID, resourceGroup, loadBalancerName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/loadBalancers/%s", subscriptionID, resourceGroup, loadBalancerName)
+}
+
 // VNetID returns the azure resource ID for a given VNet.
 func VNetID(subscriptionID, resourceGroup, vnetName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/virtualNetworks/%s", subscriptionID, resourceGroup, vnetName)
@@ -230,6 +317,11 @@ func PublicIPID(subscriptionID, resourceGroup, ipName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/publicIPAddresses/%s", subscriptionID, resourceGroup, ipName)
 }
 
+// DiskID returns the azure resource ID for a given managed disk.
+func DiskID(subscriptionID, resourceGroup, diskName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/disks/%s", subscriptionID, resourceGroup, diskName)
+}
+
 // RouteTableID returns the azure resource ID for a given route table.
 func RouteTableID(subscriptionID, resourceGroup, routeTableName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/routeTables/%s", subscriptionID, resourceGroup, routeTableName)
@@ -285,6 +377,11 @@ func VirtualNetworkLinkID(subscriptionID, resourceGroup, privateDNSZoneName, vir
 	return fmt.Sprintf("subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/privateDnsZones/%s/virtualNetworkLinks/%s", subscriptionID, resourceGroup, privateDNSZoneName, virtualNetworkLinkName)
 }
 
+// ApplicationGatewayID returns the azure resource ID for a given application gateway.
+func ApplicationGatewayID(subscriptionID, resourceGroup, appGatewayName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/applicationGateways/%s", subscriptionID, resourceGroup, appGatewayName)
+}
+
 // ManagedClusterID returns the azure resource ID for a given managed cluster.
 func ManagedClusterID(subscriptionID, resourceGroup, managedClusterName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ContainerService/managedClusters/%s", subscriptionID, resourceGroup, managedClusterName)
@@ -296,6 +393,22 @@ func ManagedClusterID(subscriptionID, resourceGroup, managedClusterName string)
 // This extension allows running arbitrary scripts on the VM.
 // Its role is to detect and report Kubernetes bootstrap failure or success.
 func GetBootstrappingVMExtension(osType string, cloud string, vmName string, cpuArchitectureType string) *ExtensionSpec {
+	return GetBootstrappingVMExtensionWithTimeout(osType, cloud, vmName, cpuArchitectureType, DefaultBootstrapExtensionTimeout, DefaultBootstrapExtensionRetryInterval)
+}
+
+// GetBootstrappingVMExtensionWithTimeout returns the CAPZ Bootstrapping VM extension, which waits up to timeout for
+// bootstrapping to complete, checking every retryInterval.
+func GetBootstrappingVMExtensionWithTimeout(osType string, cloud string, vmName string, cpuArchitectureType string, timeout time.Duration, retryInterval time.Duration) *ExtensionSpec {
+	sleep := int(retryInterval / time.Second)
+	if sleep < 1 {
+		sleep = 1
+	}
+	interval := time.Duration(sleep) * time.Second
+	retries := int((timeout + interval - 1) / interval)
+	if retries < 1 {
+		retries = 1
+	}
+
 	// currently, the bootstrap extension is only available in AzurePublicCloud.
 	if osType == LinuxOS && cloud == PublicCloudName {
 		// The command checks for the existence of the bootstrapSentinelFile on the machine, with retries and sleep between retries.
@@ -312,7 +425,7 @@ func GetBootstrappingVMExtension(osType string, cloud string, vmName string, cpu
 			Publisher: "Microsoft.Azure.ContainerUpstream",
 			Version:   extensionVersion,
 			ProtectedSettings: map[string]string{
-				"commandToExecute": LinuxBootstrapExtensionCommand,
+				"commandToExecute": linuxBootstrapExtensionCommand(retries, sleep),
 			},
 		}
 	} else if osType == WindowsOS && cloud == PublicCloudName {
@@ -324,7 +437,7 @@ func GetBootstrappingVMExtension(osType string, cloud string, vmName string, cpu
 			Publisher: "Microsoft.Azure.ContainerUpstream",
 			Version:   "1.0",
 			ProtectedSettings: map[string]string{
-				"commandToExecute": WindowsBootstrapExtensionCommand,
+				"commandToExecute": windowsBootstrapExtensionCommand(retries, sleep),
 			},
 		}
 	}
@@ -332,14 +445,106 @@ func GetBootstrappingVMExtension(osType string, cloud string, vmName string, cpu
 	return nil
 }
 
+// amdGPUVMSizeRegex matches the VM sizes with AMD GPUs, i.e. the NVv4 and NGads V620 series.
+var amdGPUVMSizeRegex = regexp.MustCompile(`(?i)^Standard_(NV\d+as_v4|NG\d+ads_V620)$`)
+
+// GetGPUDriverVMExtension returns the VM extension that installs the GPU driver on VMs of the given GPU size.
+// VM sizes with AMD GPUs get the AMD driver, which is only available for Windows, and all other sizes the NVIDIA driver.
+// See https://learn.microsoft.com/azure/virtual-machines/sizes-gpu.
+func GetGPUDriverVMExtension(osType string, vmSize string, vmName string) *ExtensionSpec {
+	if amdGPUVMSizeRegex.MatchString(vmSize) {
+		if osType != WindowsOS {
+			return nil
+		}
+		return &ExtensionSpec{
+			Name:      AMDGPUDriverExtensionWindows,
+			VMName:    vmName,
+			Publisher: "Microsoft.HpcCompute",
+			Version:   "1.1",
+		}
+	}
+
+	switch osType {
+	case LinuxOS:
+		return &ExtensionSpec{
+			Name:      NvidiaGPUDriverExtensionLinux,
+			VMName:    vmName,
+			Publisher: "Microsoft.HpcCompute",
+			Version:   "1.6",
+		}
+	case WindowsOS:
+		return &ExtensionSpec{
+			Name:      NvidiaGPUDriverExtensionWindows,
+			VMName:    vmName,
+			Publisher: "Microsoft.HpcCompute",
+			Version:   "1.4",
+		}
+	}
+	return nil
+}
+
 // UserAgent specifies a string to append to the agent identifier.
 func UserAgent() string {
 	return fmt.Sprintf("cluster-api-provider-azure/%s", version.Get().String())
 }
 
-// ARMClientOptions returns default ARM client options for CAPZ SDK v2 requests.
-func ARMClientOptions(azureEnvironment string) (*arm.ClientOptions, error) {
+// reconcileIDFromContext returns the ID of the controller-runtime reconcile request in ctx, if any.
+// It is used as the client request ID of Azure API requests so they can be related to the reconcile that made them.
+var reconcileIDFromContext = controller.ReconcileIDFromContext
+
+// userAgentSuffixKey is the context key of the User-Agent suffix of Azure API requests.
+type userAgentSuffixKey struct{}
+
+// WithUserAgentSuffix returns a copy of ctx carrying the value of the UserAgentSuffixAnnotation of the given Cluster.
+// Azure API requests made with the returned context append it to their User-Agent.
+func WithUserAgentSuffix(ctx context.Context, cluster metav1.Object) context.Context {
+	suffix := strings.TrimSpace(cluster.GetAnnotations()[UserAgentSuffixAnnotation])
+	if suffix == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, userAgentSuffixKey{}, suffix)
+}
+
+// userAgentSuffixFromCtx returns the User-Agent suffix in ctx, if any.
+func userAgentSuffixFromCtx(ctx context.Context) (string, bool) {
+	suffix, ok := ctx.Value(userAgentSuffixKey{}).(string)
+	return suffix, ok
+}
+
+// apiVersionPattern matches ARM API versions, e.g. 2023-05-01 or 2023-07-02-preview.
+var apiVersionPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(-preview)?$`)
+
+// apiVersionOverrideServices are the names of the services whose SDK v2 clients support API version overrides.
+var apiVersionOverrideServices = map[string]bool{
+	"natgateways":          true,
+	"virtualmachineimages": true,
+}
+
+// apiVersionOverrides are the ARM API versions used by the SDK v2 clients of services instead of the API version of
+// their SDK, keyed by service name. It is only set on startup, before any client is created.
+var apiVersionOverrides map[string]string
+
+// SetAPIVersionOverrides sets the ARM API versions used by the SDK v2 clients of the given services instead of the
+// API version of their SDK. Only the api-version query parameter of the requests changes: the request and response
+// bodies are still those of the SDK models, so properties that only exist in the overridden API version are neither
+// sent nor read. The clients aren't tested with other API versions than the one of their SDK.
+func SetAPIVersionOverrides(overrides map[string]string) error {
+	for serviceName, apiVersion := range overrides {
+		if !apiVersionOverrideServices[serviceName] {
+			return fmt.Errorf("API version overrides aren't supported for service %s", serviceName)
+		}
+		if !apiVersionPattern.MatchString(apiVersion) {
+			return fmt.Errorf("invalid API version %q for service %s", apiVersion, serviceName)
+		}
+	}
+	apiVersionOverrides = overrides
+	return nil
+}
+
+// ARMClientOptions returns default ARM client options for CAPZ SDK v2 requests of a service.
+func ARMClientOptions(azureEnvironment, serviceName string) (*arm.ClientOptions, error) {
 	opts := &arm.ClientOptions{}
+	opts.APIVersion = apiVersionOverrides[serviceName]
 
 	switch azureEnvironment {
 	case PublicCloudName:
@@ -355,6 +560,7 @@ func ARMClientOptions(azureEnvironment string) (*arm.ClientOptions, error) {
 	}
 	opts.PerCallPolicies = []policy.Policy{
 		correlationIDPolicy{},
+		clientRequestIDPolicy{},
 		userAgentPolicy{},
 	}
 	opts.Retry.MaxRetries = -1 // Less than zero means one try and no retries.
@@ -374,13 +580,30 @@ func (p correlationIDPolicy) Do(req *policy.Request) (*http.Response, error) {
 	return req.Next()
 }
 
+// clientRequestIDPolicy adds the "x-ms-client-request-id" header to requests.
+// It implements the policy.Policy interface.
+type clientRequestIDPolicy struct{}
+
+// Do adds the "x-ms-client-request-id" header if a request has a reconcile ID in its context.
+func (p clientRequestIDPolicy) Do(req *policy.Request) (*http.Response, error) {
+	if reconcileID := reconcileIDFromContext(req.Raw().Context()); reconcileID != "" {
+		req.Raw().Header.Set(ClientRequestIDHeader, string(reconcileID))
+	}
+	return req.Next()
+}
+
 // userAgentPolicy extends the "User-Agent" header on requests.
 // It implements the policy.Policy interface.
 type userAgentPolicy struct{}
 
-// Do extends the "User-Agent" header of a request by appending CAPZ's user agent.
+// Do extends the "User-Agent" header of a request by appending CAPZ's user agent and the User-Agent suffix
+// in the request context, if any.
 func (p userAgentPolicy) Do(req *policy.Request) (*http.Response, error) {
-	req.Raw().Header.Set("User-Agent", req.Raw().UserAgent()+" "+UserAgent())
+	userAgent := req.Raw().UserAgent() + " " + UserAgent()
+	if suffix, ok := userAgentSuffixFromCtx(req.Raw().Context()); ok {
+		userAgent += " " + suffix
+	}
+	req.Raw().Header.Set("User-Agent", userAgent)
 	return req.Next()
 }
 
@@ -388,9 +611,9 @@ func (p userAgentPolicy) Do(req *policy.Request) (*http.Response, error) {
 func SetAutoRestClientDefaults(c *autorest.Client, auth autorest.Authorizer) {
 	c.Authorizer = auth
 	// Wrap the original Sender on the autorest.Client c.
-	// The wrapped Sender should set the x-ms-correlation-request-id on the given
-	// request, then pass the new request to the underlying Sender.
-	c.Sender = autorest.DecorateSender(c.Sender, msCorrelationIDSendDecorator)
+	// The wrapped Sender should set the x-ms-correlation-request-id and x-ms-client-request-id on the given
+	// request, extend its User-Agent, then pass the new request to the underlying Sender.
+	c.Sender = autorest.DecorateSender(c.Sender, msCorrelationIDSendDecorator, msClientRequestIDSendDecorator, userAgentSuffixSendDecorator)
 	// The default number of retries is 3. This means the client will attempt to retry operation results like resource
 	// conflicts (HTTP 409). For a reconciling controller, this is undesirable behavior since if the controller runs
 	// into an error reconciling, the controller would be better off to end with an error and try again later.
@@ -416,3 +639,24 @@ func msCorrelationIDSendDecorator(snd autorest.Sender) autorest.Sender {
 		return snd.Do(r)
 	})
 }
+
+func msClientRequestIDSendDecorator(snd autorest.Sender) autorest.Sender {
+	return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+		// if the reconcile ID was found in the request context, set
+		// it in the header
+		if reconcileID := reconcileIDFromContext(r.Context()); reconcileID != "" {
+			r.Header.Set(ClientRequestIDHeader, string(reconcileID))
+		}
+		return snd.Do(r)
+	})
+}
+
+func userAgentSuffixSendDecorator(snd autorest.Sender) autorest.Sender {
+	return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+		// the same request may be sent more than once, so only extend the User-Agent once
+		if suffix, ok := userAgentSuffixFromCtx(r.Context()); ok && !strings.HasSuffix(r.UserAgent(), " "+suffix) {
+			r.Header.Set("User-Agent", r.UserAgent()+" "+suffix)
+		}
+		return snd.Do(r)
+	})
+}
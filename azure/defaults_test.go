@@ -23,12 +23,15 @@ import (
 	"net/http/httptest"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/go-autorest/autorest"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -72,7 +75,7 @@ func TestARMClientOptions(t *testing.T) {
 			t.Parallel()
 			g := NewWithT(t)
 
-			opts, err := ARMClientOptions(tc.cloudName)
+			opts, err := ARMClientOptions(tc.cloudName, "")
 			if tc.expectError {
 				g.Expect(err).To(HaveOccurred())
 				return
@@ -80,33 +83,63 @@ func TestARMClientOptions(t *testing.T) {
 			g.Expect(err).NotTo(HaveOccurred())
 			g.Expect(opts.Cloud).To(Equal(tc.expectedCloud))
 			g.Expect(opts.Retry.MaxRetries).To(BeNumerically("==", -1))
-			g.Expect(opts.PerCallPolicies).To(HaveLen(2))
+			g.Expect(opts.PerCallPolicies).To(HaveLen(3))
 		})
 	}
 }
 
+// TestAPIVersionOverrides tests that `ARMClientOptions()` uses the API version overrides of a service.
+func TestAPIVersionOverrides(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(SetAPIVersionOverrides(map[string]string{"natgateways": "not-a-version"})).NotTo(Succeed())
+	g.Expect(SetAPIVersionOverrides(map[string]string{"standbypools": "2024-03-01"})).NotTo(Succeed())
+
+	g.Expect(SetAPIVersionOverrides(map[string]string{"natgateways": "2023-06-01-preview"})).To(Succeed())
+	defer func() {
+		g.Expect(SetAPIVersionOverrides(nil)).To(Succeed())
+	}()
+
+	opts, err := ARMClientOptions("", "natgateways")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(opts.APIVersion).To(Equal("2023-06-01-preview"))
+
+	opts, err = ARMClientOptions("", "virtualmachineimages")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(opts.APIVersion).To(BeEmpty())
+}
+
 // TestPerCallPolicies tests the per-call policies returned by `ARMClientOptions()`.
 func TestPerCallPolicies(t *testing.T) {
 	g := NewWithT(t)
 
 	corrID := "test-1234abcd-5678efgh"
-	// This server will check that the correlation ID and user-agent are set correctly.
+	reconcileID := "test-reconcile-id"
+	defer setReconcileIDFromContext(reconcileID)()
+
+	// This server will check that the correlation ID, client request ID and user-agent are set correctly.
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		g.Expect(r.Header.Get("User-Agent")).To(ContainSubstring("cluster-api-provider-azure/"))
+		g.Expect(r.Header.Get("User-Agent")).To(HaveSuffix(" my-team/cluster-1"))
 		g.Expect(r.Header.Get(string(tele.CorrIDKeyVal))).To(Equal(corrID))
+		g.Expect(r.Header.Get(ClientRequestIDHeader)).To(Equal(reconcileID))
 		fmt.Fprintf(w, "Hello, %s", r.Proto)
 	}))
 	defer server.Close()
 
-	// Call the factory function and ensure it has both PerCallPolicies.
-	opts, err := ARMClientOptions("")
+	// Call the factory function and ensure it has all PerCallPolicies.
+	opts, err := ARMClientOptions("", "")
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(opts.PerCallPolicies).To(HaveLen(2))
+	g.Expect(opts.PerCallPolicies).To(HaveLen(3))
 	g.Expect(opts.PerCallPolicies).To(ContainElement(BeAssignableToTypeOf(correlationIDPolicy{})))
+	g.Expect(opts.PerCallPolicies).To(ContainElement(BeAssignableToTypeOf(clientRequestIDPolicy{})))
 	g.Expect(opts.PerCallPolicies).To(ContainElement(BeAssignableToTypeOf(userAgentPolicy{})))
 
-	// Create a request with a correlation ID.
+	// Create a request with a correlation ID and a user agent suffix.
 	ctx := context.WithValue(context.Background(), tele.CorrIDKeyVal, tele.CorrID(corrID))
+	ctx = WithUserAgentSuffix(ctx, &metav1.ObjectMeta{
+		Annotations: map[string]string{UserAgentSuffixAnnotation: "my-team/cluster-1"},
+	})
 	req, err := runtime.NewRequest(ctx, http.MethodGet, server.URL)
 	g.Expect(err).NotTo(HaveOccurred())
 
@@ -118,6 +151,17 @@ func TestPerCallPolicies(t *testing.T) {
 	g.Expect(resp.StatusCode).To(Equal(http.StatusOK))
 }
 
+// setReconcileIDFromContext makes reconcileIDFromContext return the given reconcile ID, and returns a function restoring it.
+func setReconcileIDFromContext(reconcileID string) func() {
+	orig := reconcileIDFromContext
+	reconcileIDFromContext = func(context.Context) types.UID {
+		return types.UID(reconcileID)
+	}
+	return func() {
+		reconcileIDFromContext = orig
+	}
+}
+
 func defaultTestPipeline(policies []policy.Policy) runtime.Pipeline {
 	return runtime.NewPipeline(
 		"testmodule",
@@ -216,6 +260,57 @@ func TestMSCorrelationIDSendDecorator(t *testing.T) {
 	).To(Equal(string(corrID)))
 }
 
+func TestMSClientRequestIDAndUserAgentSuffixSendDecorators(t *testing.T) {
+	g := NewWithT(t)
+	const reconcileID = "TestMSClientRequestIDSendDecoratorReconcileID"
+	defer setReconcileIDFromContext(reconcileID)()
+
+	ctx := WithUserAgentSuffix(context.Background(), &metav1.ObjectMeta{
+		Annotations: map[string]string{UserAgentSuffixAnnotation: " my-team "},
+	})
+
+	var receivedReq *http.Request
+	origSender := autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+		receivedReq = r
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	newSender := autorest.DecorateSender(origSender, msClientRequestIDSendDecorator, userAgentSuffixSendDecorator)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/abc", http.NoBody)
+	g.Expect(err).NotTo(HaveOccurred())
+	req.Header.Set("User-Agent", UserAgent())
+
+	// send the request twice to make sure the suffix is only appended once
+	for i := 0; i < 2; i++ {
+		rsp, err := newSender.Do(req)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(rsp.Body.Close()).To(Succeed())
+	}
+	g.Expect(receivedReq.Header.Get(ClientRequestIDHeader)).To(Equal(reconcileID))
+	g.Expect(receivedReq.Header.Get("User-Agent")).To(Equal(UserAgent() + " my-team"))
+}
+
+func TestWithUserAgentSuffix(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx := WithUserAgentSuffix(context.Background(), &metav1.ObjectMeta{})
+	_, ok := userAgentSuffixFromCtx(ctx)
+	g.Expect(ok).To(BeFalse())
+
+	ctx = WithUserAgentSuffix(context.Background(), &metav1.ObjectMeta{
+		Annotations: map[string]string{UserAgentSuffixAnnotation: "  "},
+	})
+	_, ok = userAgentSuffixFromCtx(ctx)
+	g.Expect(ok).To(BeFalse())
+
+	ctx = WithUserAgentSuffix(context.Background(), &metav1.ObjectMeta{
+		Annotations: map[string]string{UserAgentSuffixAnnotation: "my-team/cluster-1"},
+	})
+	suffix, ok := userAgentSuffixFromCtx(ctx)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(suffix).To(Equal("my-team/cluster-1"))
+}
+
 func TestGetBootstrappingVMExtension(t *testing.T) {
 	testCases := []struct {
 		name            string
@@ -282,3 +377,105 @@ func TestGetBootstrappingVMExtension(t *testing.T) {
 		})
 	}
 }
+
+func TestGetBootstrappingVMExtensionWithTimeout(t *testing.T) {
+	testCases := []struct {
+		name            string
+		osType          string
+		timeout         time.Duration
+		retryInterval   time.Duration
+		expectedCommand string
+	}{
+		{
+			name:            "Linux OS with the default timeout",
+			osType:          LinuxOS,
+			timeout:         DefaultBootstrapExtensionTimeout,
+			retryInterval:   DefaultBootstrapExtensionRetryInterval,
+			expectedCommand: LinuxBootstrapExtensionCommand,
+		},
+		{
+			name:            "Linux OS with a custom timeout",
+			osType:          LinuxOS,
+			timeout:         20 * time.Minute,
+			retryInterval:   10 * time.Second,
+			expectedCommand: linuxBootstrapExtensionCommand(120, 10),
+		},
+		{
+			name:            "Windows OS with a timeout that isn't a multiple of the retry interval",
+			osType:          WindowsOS,
+			timeout:         time.Minute,
+			retryInterval:   7 * time.Second,
+			expectedCommand: windowsBootstrapExtensionCommand(9, 7),
+		},
+		{
+			name:            "Linux OS with a retry interval below a second",
+			osType:          LinuxOS,
+			timeout:         10 * time.Second,
+			retryInterval:   100 * time.Millisecond,
+			expectedCommand: linuxBootstrapExtensionCommand(10, 1),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			actualExtension := GetBootstrappingVMExtensionWithTimeout(tc.osType, PublicCloudName, "test-vm", "x64", tc.timeout, tc.retryInterval)
+			g.Expect(actualExtension.ProtectedSettings).To(HaveKeyWithValue("commandToExecute", tc.expectedCommand))
+		})
+	}
+}
+
+func TestGetGPUDriverVMExtension(t *testing.T) {
+	testCases := []struct {
+		name         string
+		osType       string
+		vmSize       string
+		expectedName string
+		expectNil    bool
+	}{
+		{
+			name:         "Linux NVIDIA GPU VM size",
+			osType:       LinuxOS,
+			vmSize:       "Standard_NC6s_v3",
+			expectedName: NvidiaGPUDriverExtensionLinux,
+		},
+		{
+			name:         "Windows NVIDIA GPU VM size",
+			osType:       WindowsOS,
+			vmSize:       "Standard_NV12s_v3",
+			expectedName: NvidiaGPUDriverExtensionWindows,
+		},
+		{
+			name:         "Windows AMD GPU VM size",
+			osType:       WindowsOS,
+			vmSize:       "Standard_NV8as_v4",
+			expectedName: AMDGPUDriverExtensionWindows,
+		},
+		{
+			name:      "Linux AMD GPU VM size",
+			osType:    LinuxOS,
+			vmSize:    "Standard_NG8ads_V620",
+			expectNil: true,
+		},
+		{
+			name:      "Invalid OS Type",
+			osType:    "invalid",
+			vmSize:    "Standard_NC6s_v3",
+			expectNil: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			actualExtension := GetGPUDriverVMExtension(tc.osType, tc.vmSize, "test-vm")
+			if tc.expectNil {
+				g.Expect(actualExtension).To(BeNil())
+			} else {
+				g.Expect(actualExtension.Name).To(Equal(tc.expectedName))
+				g.Expect(actualExtension.Publisher).To(Equal("Microsoft.HpcCompute"))
+				g.Expect(actualExtension.VMName).To(Equal("test-vm"))
+			}
+		})
+	}
+}
@@ -137,6 +137,15 @@ type ResourceSpecGetterWithHeaders interface {
 	CustomHeaders() map[string]string
 }
 
+// ResourceSpecGetterWithSpecHash is a ResourceSpecGetter whose resource is tagged with the hash of the spec it was last
+// created or updated from. The tag is informational: the resource is still compared with the spec on every
+// reconciliation so that changes made out of band are reverted.
+type ResourceSpecGetterWithSpecHash interface {
+	ResourceSpecGetter
+	// WithSpecHash returns the parameters of the resource tagged with the given spec hash.
+	WithSpecHash(parameters interface{}, hash string) (interface{}, error)
+}
+
 // ASOResourceSpecGetter is an interface for getting all the required information to create/update/delete an Azure resource.
 type ASOResourceSpecGetter interface {
 	// ResourceRef returns a concrete, named (and namespaced if applicable) ASO
@@ -1968,6 +1968,101 @@ func (mr *MockResourceSpecGetterWithHeadersMockRecorder) ResourceName() *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceName", reflect.TypeOf((*MockResourceSpecGetterWithHeaders)(nil).ResourceName))
 }
 
+// MockResourceSpecGetterWithSpecHash is a mock of ResourceSpecGetterWithSpecHash interface.
+type MockResourceSpecGetterWithSpecHash struct {
+	ctrl     *gomock.Controller
+	recorder *MockResourceSpecGetterWithSpecHashMockRecorder
+}
+
+// MockResourceSpecGetterWithSpecHashMockRecorder is the mock recorder for MockResourceSpecGetterWithSpecHash.
+type MockResourceSpecGetterWithSpecHashMockRecorder struct {
+	mock *MockResourceSpecGetterWithSpecHash
+}
+
+// NewMockResourceSpecGetterWithSpecHash creates a new mock instance.
+func NewMockResourceSpecGetterWithSpecHash(ctrl *gomock.Controller) *MockResourceSpecGetterWithSpecHash {
+	mock := &MockResourceSpecGetterWithSpecHash{ctrl: ctrl}
+	mock.recorder = &MockResourceSpecGetterWithSpecHashMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResourceSpecGetterWithSpecHash) EXPECT() *MockResourceSpecGetterWithSpecHashMockRecorder {
+	return m.recorder
+}
+
+// OwnerResourceName mocks base method.
+func (m *MockResourceSpecGetterWithSpecHash) OwnerResourceName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OwnerResourceName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// OwnerResourceName indicates an expected call of OwnerResourceName.
+func (mr *MockResourceSpecGetterWithSpecHashMockRecorder) OwnerResourceName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OwnerResourceName", reflect.TypeOf((*MockResourceSpecGetterWithSpecHash)(nil).OwnerResourceName))
+}
+
+// Parameters mocks base method.
+func (m *MockResourceSpecGetterWithSpecHash) Parameters(ctx context.Context, existing interface{}) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Parameters", ctx, existing)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Parameters indicates an expected call of Parameters.
+func (mr *MockResourceSpecGetterWithSpecHashMockRecorder) Parameters(ctx, existing interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Parameters", reflect.TypeOf((*MockResourceSpecGetterWithSpecHash)(nil).Parameters), ctx, existing)
+}
+
+// ResourceGroupName mocks base method.
+func (m *MockResourceSpecGetterWithSpecHash) ResourceGroupName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceGroupName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ResourceGroupName indicates an expected call of ResourceGroupName.
+func (mr *MockResourceSpecGetterWithSpecHashMockRecorder) ResourceGroupName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroupName", reflect.TypeOf((*MockResourceSpecGetterWithSpecHash)(nil).ResourceGroupName))
+}
+
+// ResourceName mocks base method.
+func (m *MockResourceSpecGetterWithSpecHash) ResourceName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ResourceName indicates an expected call of ResourceName.
+func (mr *MockResourceSpecGetterWithSpecHashMockRecorder) ResourceName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceName", reflect.TypeOf((*MockResourceSpecGetterWithSpecHash)(nil).ResourceName))
+}
+
+// WithSpecHash mocks base method.
+func (m *MockResourceSpecGetterWithSpecHash) WithSpecHash(parameters interface{}, hash string) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithSpecHash", parameters, hash)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WithSpecHash indicates an expected call of WithSpecHash.
+func (mr *MockResourceSpecGetterWithSpecHashMockRecorder) WithSpecHash(parameters, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithSpecHash", reflect.TypeOf((*MockResourceSpecGetterWithSpecHash)(nil).WithSpecHash), parameters, hash)
+}
+
 // MockASOResourceSpecGetter is a mock of ASOResourceSpecGetter interface.
 type MockASOResourceSpecGetter struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// aksUpgradesGetter looks up the available upgrades of AKS clusters in their cached upgrade profiles.
+type aksUpgradesGetter struct {
+	client client.Client
+}
+
+// NewAKSUpgradesGetter returns an infrav1.AKSUpgradesGetter backed by the managed cluster upgrades cache.
+func NewAKSUpgradesGetter(c client.Client) infrav1.AKSUpgradesGetter {
+	return &aksUpgradesGetter{client: c}
+}
+
+// AKSUpgrades returns the Kubernetes version of the AKS cluster of the AzureManagedControlPlane and the versions it
+// can be upgraded to.
+func (g *aksUpgradesGetter) AKSUpgrades(ctx context.Context, controlPlane *infrav1.AzureManagedControlPlane) (string, []string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.aksUpgradesGetter.AKSUpgrades")
+	defer done()
+
+	cluster, err := util.GetClusterFromMetadata(ctx, g.client, controlPlane.ObjectMeta)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to get owner cluster")
+	}
+
+	managedControlPlaneScope, err := NewManagedControlPlaneScope(ctx, ManagedControlPlaneScopeParams{
+		Client:       g.client,
+		Cluster:      cluster,
+		ControlPlane: controlPlane,
+	})
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to create managed control plane scope")
+	}
+
+	return managedclusters.GetUpgrades(ctx, managedControlPlaneScope, controlPlane.Spec.ResourceGroupName, controlPlane.Name, controlPlane.Spec.Version)
+}
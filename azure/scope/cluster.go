@@ -25,24 +25,31 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/net"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/applicationgateways"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/asogroups"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/azurefirewalls"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bastionhosts"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/natgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/netapp"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privatedns"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privateendpoints"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/routetables"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/securitygroups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/trafficmanager"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vnetpeerings"
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
@@ -128,6 +135,10 @@ type ClusterScope struct {
 	Cluster         *clusterv1.Cluster
 	AzureCluster    *infrav1.AzureCluster
 	UseLegacyGroups bool
+
+	// retainedDisks are the IDs of the disks of the machines of the cluster whose delete policy is Retain,
+	// loaded by LoadRetainedDisks.
+	retainedDisks []string
 }
 
 // ClusterCache stores ClusterCache data locally so we don't have to hit the API multiple times within the same reconcile loop.
@@ -150,6 +161,19 @@ func (s *ClusterScope) GetClient() client.Client {
 	return s.Client
 }
 
+// OutboundPublicIPNamePrefixes returns the name prefixes of the public IPs generated for the frontend IPs of the
+// outbound load balancers.
+func (s *ClusterScope) OutboundPublicIPNamePrefixes() []string {
+	var prefixes []string
+	if s.IsAPIServerPrivate() && s.ControlPlaneOutboundLB() != nil {
+		prefixes = append(prefixes, azure.GenerateControlPlaneOutboundIPName(s.AzureCluster.Name))
+	}
+	if s.NodeOutboundLB() != nil {
+		prefixes = append(prefixes, azure.GenerateNodeOutboundIPName(s.AzureCluster.Name))
+	}
+	return prefixes
+}
+
 // PublicIPSpecs returns the public IP specs.
 func (s *ClusterScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 	var publicIPSpecs []azure.ResourceSpecGetter
@@ -168,8 +192,9 @@ func (s *ClusterScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 					IsIPv6:           false, // Set to default value
 					Location:         s.Location(),
 					ExtendedLocation: s.ExtendedLocation(),
-					FailureDomains:   s.FailureDomains(),
+					FailureDomains:   s.publicIPZones(*ip.PublicIP),
 					AdditionalTags:   s.AdditionalTags(),
+					DeletePolicy:     ip.PublicIP.DeletePolicy,
 				})
 			}
 		}
@@ -183,9 +208,10 @@ func (s *ClusterScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 				ClusterName:      s.ClusterName(),
 				Location:         s.Location(),
 				ExtendedLocation: s.ExtendedLocation(),
-				FailureDomains:   s.FailureDomains(),
+				FailureDomains:   s.publicIPZones(*s.APIServerPublicIP()),
 				AdditionalTags:   s.AdditionalTags(),
 				IPTags:           s.APIServerPublicIP().IPTags,
+				DeletePolicy:     s.APIServerPublicIP().DeletePolicy,
 			},
 		}
 	}
@@ -193,7 +219,7 @@ func (s *ClusterScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 
 	// Public IP specs for node outbound lb
 	if s.NodeOutboundLB() != nil {
-		for _, ip := range s.NodeOutboundLB().FrontendIPs {
+		for _, ip := range s.nodeOutboundFrontendIPs() {
 			publicIPSpecs = append(publicIPSpecs, &publicips.PublicIPSpec{
 				Name:             ip.PublicIP.Name,
 				ResourceGroup:    s.ResourceGroup(),
@@ -202,8 +228,9 @@ func (s *ClusterScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 				IsIPv6:           false, // Set to default value
 				Location:         s.Location(),
 				ExtendedLocation: s.ExtendedLocation(),
-				FailureDomains:   s.FailureDomains(),
+				FailureDomains:   s.publicIPZones(*ip.PublicIP),
 				AdditionalTags:   s.AdditionalTags(),
+				DeletePolicy:     ip.PublicIP.DeletePolicy,
 			})
 		}
 	}
@@ -219,9 +246,10 @@ func (s *ClusterScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 				IsIPv6:         false, // Public IP is IPv4 by default
 				ClusterName:    s.ClusterName(),
 				Location:       s.Location(),
-				FailureDomains: s.FailureDomains(),
+				FailureDomains: s.publicIPZones(subnet.NatGateway.NatGatewayIP),
 				AdditionalTags: s.AdditionalTags(),
 				IPTags:         subnet.NatGateway.NatGatewayIP.IPTags,
+				DeletePolicy:   subnet.NatGateway.NatGatewayIP.DeletePolicy,
 			})
 		}
 		publicIPSpecs = append(publicIPSpecs, nodeNatGatewayIPSpecs...)
@@ -236,16 +264,176 @@ func (s *ClusterScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 			IsIPv6:         false, // Public IP is IPv4 by default
 			ClusterName:    s.ClusterName(),
 			Location:       s.Location(),
-			FailureDomains: s.FailureDomains(),
+			FailureDomains: s.publicIPZones(azureBastion.PublicIP),
 			AdditionalTags: s.AdditionalTags(),
 			IPTags:         azureBastion.PublicIP.IPTags,
+			DeletePolicy:   azureBastion.PublicIP.DeletePolicy,
 		}
 		publicIPSpecs = append(publicIPSpecs, azureBastionPublicIP)
 	}
 
+	if firewall := s.managedAzureFirewall(); firewall != nil {
+		// public IP for Azure Firewall.
+		publicIPSpecs = append(publicIPSpecs, &publicips.PublicIPSpec{
+			Name:           firewall.PublicIP.Name,
+			ResourceGroup:  s.ResourceGroup(),
+			DNSName:        firewall.PublicIP.DNSName,
+			IsIPv6:         false, // Azure Firewall only supports IPv4 public IPs
+			ClusterName:    s.ClusterName(),
+			Location:       s.Location(),
+			FailureDomains: s.publicIPZones(firewall.PublicIP),
+			AdditionalTags: s.AdditionalTags(),
+			IPTags:         firewall.PublicIP.IPTags,
+			DeletePolicy:   firewall.PublicIP.DeletePolicy,
+		})
+	}
+
+	if appGateway := s.managedApplicationGateway(); appGateway != nil {
+		// public IP for the frontend of the Application Gateway.
+		publicIPSpecs = append(publicIPSpecs, &publicips.PublicIPSpec{
+			Name:           appGateway.PublicIP.Name,
+			ResourceGroup:  s.ResourceGroup(),
+			DNSName:        appGateway.PublicIP.DNSName,
+			IsIPv6:         false, // Application Gateway v2 frontends require an IPv4 public IP
+			ClusterName:    s.ClusterName(),
+			Location:       s.Location(),
+			FailureDomains: s.publicIPZones(appGateway.PublicIP),
+			AdditionalTags: s.AdditionalTags(),
+			IPTags:         appGateway.PublicIP.IPTags,
+			DeletePolicy:   appGateway.PublicIP.DeletePolicy,
+		})
+	}
+
 	return publicIPSpecs
 }
 
+// publicIPZones returns the availability zones of a public IP, which default to the failure domains of the cluster.
+func (s *ClusterScope) publicIPZones(ip infrav1.PublicIPSpec) []string {
+	if len(ip.Zones) > 0 {
+		return ip.Zones
+	}
+	return s.FailureDomains()
+}
+
+// RetainedResources returns the IDs of the Azure resources of the cluster whose delete policy is Retain, including
+// the disks of its machines loaded by LoadRetainedDisks.
+func (s *ClusterScope) RetainedResources() []string {
+	var ids []string
+	if s.Vnet().DeletePolicy == infrav1.DeletePolicyRetain {
+		ids = append(ids, azure.VNetID(s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name))
+	}
+	seen := make(map[string]bool)
+	for _, ip := range s.publicIPs() {
+		if ip.Name == "" || ip.DeletePolicy != infrav1.DeletePolicyRetain || seen[ip.Name] {
+			continue
+		}
+		seen[ip.Name] = true
+		ids = append(ids, azure.PublicIPID(s.SubscriptionID(), s.ResourceGroup(), ip.Name))
+	}
+	return append(ids, s.retainedDisks...)
+}
+
+// disksResourceType is the resource type of managed disks.
+const disksResourceType = "Microsoft.Compute/disks"
+
+// LoadRetainedDisks loads the disks of the AzureMachines of the cluster whose delete policy is Retain, so that they
+// are part of the retained resources of the cluster. The retained disks already recorded in the AzureCluster status
+// are kept, as the AzureMachines of the disks may have been deleted since.
+func (s *ClusterScope) LoadRetainedDisks(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.ClusterScope.LoadRetainedDisks")
+	defer done()
+
+	machines := &infrav1.AzureMachineList{}
+	if err := s.Client.List(ctx, machines, client.InNamespace(s.Namespace()), client.MatchingLabels{clusterv1.ClusterNameLabel: s.ClusterName()}); err != nil {
+		return errors.Wrap(err, "failed to list AzureMachines")
+	}
+
+	var disks []string
+	seen := make(map[string]bool)
+	addDisk := func(id string) {
+		if !seen[strings.ToLower(id)] {
+			seen[strings.ToLower(id)] = true
+			disks = append(disks, id)
+		}
+	}
+	for _, id := range s.AzureCluster.Status.RetainedResources {
+		if resourceID, err := arm.ParseResourceID(id); err == nil && strings.EqualFold(resourceID.ResourceType.String(), disksResourceType) {
+			addDisk(id)
+		}
+	}
+	for i := range machines.Items {
+		for _, id := range retainedDiskIDs(s.SubscriptionID(), s.ResourceGroup(), &machines.Items[i]) {
+			addDisk(id)
+		}
+	}
+	s.retainedDisks = disks
+	return nil
+}
+
+// nodeOutboundFrontendIPs returns the frontend IPs of the node outbound load balancer,
+// including the ones of its additional outbound rules.
+func (s *ClusterScope) nodeOutboundFrontendIPs() []infrav1.FrontendIP {
+	ips := append([]infrav1.FrontendIP{}, s.NodeOutboundLB().FrontendIPs...)
+	for _, rule := range s.NodeOutboundLB().AdditionalOutboundRules {
+		ips = append(ips, rule.FrontendIPs...)
+	}
+	return ips
+}
+
+// publicIPs returns the public IPs defined in the AzureCluster spec.
+func (s *ClusterScope) publicIPs() []infrav1.PublicIPSpec {
+	var ips []infrav1.PublicIPSpec
+	for _, lb := range []*infrav1.LoadBalancerSpec{s.APIServerLB(), s.ControlPlaneOutboundLB(), s.NodeOutboundLB()} {
+		if lb == nil {
+			continue
+		}
+		for _, frontendIP := range lb.FrontendIPs {
+			if frontendIP.PublicIP != nil {
+				ips = append(ips, *frontendIP.PublicIP)
+			}
+		}
+		for _, rule := range lb.AdditionalOutboundRules {
+			for _, frontendIP := range rule.FrontendIPs {
+				if frontendIP.PublicIP != nil {
+					ips = append(ips, *frontendIP.PublicIP)
+				}
+			}
+		}
+	}
+	for _, subnet := range s.Subnets() {
+		if subnet.IsNatGatewayEnabled() {
+			ips = append(ips, subnet.NatGateway.NatGatewayIP)
+		}
+	}
+	if s.AzureBastion() != nil {
+		ips = append(ips, s.AzureBastion().PublicIP)
+	}
+	if firewall := s.managedAzureFirewall(); firewall != nil {
+		ips = append(ips, firewall.PublicIP)
+	}
+	if appGateway := s.managedApplicationGateway(); appGateway != nil {
+		ips = append(ips, appGateway.PublicIP)
+	}
+	return ips
+}
+
+// HasRetainedResourcesInResourceGroup returns true if any Azure resource whose delete policy is Retain is in the
+// cluster resource group, which means the resource group must not be deleted with the cluster.
+func (s *ClusterScope) HasRetainedResourcesInResourceGroup() bool {
+	for _, id := range s.RetainedResources() {
+		resourceID, err := arm.ParseResourceID(id)
+		if err != nil || strings.EqualFold(resourceID.ResourceGroupName, s.ResourceGroup()) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetRetainedResources records the Azure resources of the cluster whose delete policy is Retain in the AzureCluster status.
+func (s *ClusterScope) SetRetainedResources() {
+	s.AzureCluster.Status.RetainedResources = s.RetainedResources()
+}
+
 // LBSpecs returns the load balancer specs.
 func (s *ClusterScope) LBSpecs() []azure.ResourceSpecGetter {
 	specs := []azure.ResourceSpecGetter{
@@ -274,21 +462,22 @@ func (s *ClusterScope) LBSpecs() []azure.ResourceSpecGetter {
 	// Node outbound LB
 	if s.NodeOutboundLB() != nil {
 		specs = append(specs, &loadbalancers.LBSpec{
-			Name:                 s.NodeOutboundLB().Name,
-			ResourceGroup:        s.ResourceGroup(),
-			SubscriptionID:       s.SubscriptionID(),
-			ClusterName:          s.ClusterName(),
-			Location:             s.Location(),
-			ExtendedLocation:     s.ExtendedLocation(),
-			VNetName:             s.Vnet().Name,
-			VNetResourceGroup:    s.Vnet().ResourceGroup,
-			FrontendIPConfigs:    s.NodeOutboundLB().FrontendIPs,
-			Type:                 s.NodeOutboundLB().Type,
-			SKU:                  s.NodeOutboundLB().SKU,
-			BackendPoolName:      s.NodeOutboundLB().BackendPool.Name,
-			IdleTimeoutInMinutes: s.NodeOutboundLB().IdleTimeoutInMinutes,
-			Role:                 infrav1.NodeOutboundRole,
-			AdditionalTags:       s.AdditionalTags(),
+			Name:                    s.NodeOutboundLB().Name,
+			ResourceGroup:           s.ResourceGroup(),
+			SubscriptionID:          s.SubscriptionID(),
+			ClusterName:             s.ClusterName(),
+			Location:                s.Location(),
+			ExtendedLocation:        s.ExtendedLocation(),
+			VNetName:                s.Vnet().Name,
+			VNetResourceGroup:       s.Vnet().ResourceGroup,
+			FrontendIPConfigs:       s.NodeOutboundLB().FrontendIPs,
+			Type:                    s.NodeOutboundLB().Type,
+			SKU:                     s.NodeOutboundLB().SKU,
+			BackendPoolName:         s.NodeOutboundLB().BackendPool.Name,
+			IdleTimeoutInMinutes:    s.NodeOutboundLB().IdleTimeoutInMinutes,
+			Role:                    infrav1.NodeOutboundRole,
+			AdditionalTags:          s.AdditionalTags(),
+			AdditionalOutboundRules: s.NodeOutboundLB().AdditionalOutboundRules,
 		})
 	}
 
@@ -316,18 +505,31 @@ func (s *ClusterScope) LBSpecs() []azure.ResourceSpecGetter {
 	return specs
 }
 
+// azureFirewallRouteName is the name of the route sending the egress traffic of the node subnets to the Azure Firewall.
+const azureFirewallRouteName = "default-via-firewall"
+
 // RouteTableSpecs returns the subnet route tables.
 func (s *ClusterScope) RouteTableSpecs() []azure.ResourceSpecGetter {
 	var specs []azure.ResourceSpecGetter
 	for _, subnet := range s.AzureCluster.Spec.NetworkSpec.Subnets {
 		if subnet.RouteTable.Name != "" {
-			specs = append(specs, &routetables.RouteTableSpec{
+			spec := &routetables.RouteTableSpec{
 				Name:           subnet.RouteTable.Name,
 				Location:       s.Location(),
 				ResourceGroup:  s.ResourceGroup(),
 				ClusterName:    s.ClusterName(),
 				AdditionalTags: s.AdditionalTags(),
-			})
+			}
+			firewall := s.AzureCluster.Spec.NetworkSpec.AzureFirewall
+			if s.AzureCluster.Spec.NetworkSpec.OutboundType == infrav1.AzureFirewallNodeOutboundType && firewall != nil && subnet.Role == infrav1.SubnetNode {
+				// Node subnets send all their egress traffic to the Azure Firewall.
+				spec.Routes = []routetables.RouteSpec{{
+					Name:             azureFirewallRouteName,
+					AddressPrefix:    "0.0.0.0/0",
+					NextHopIPAddress: firewall.PrivateIPAddress,
+				}}
+			}
+			specs = append(specs, spec)
 		}
 	}
 
@@ -386,23 +588,34 @@ func (s *ClusterScope) SubnetSpecs() []azure.ResourceSpecGetter {
 	if s.IsAzureBastionEnabled() {
 		numberOfSubnets++
 	}
+	if s.AzureCluster.Spec.NetworkSpec.NetApp != nil {
+		numberOfSubnets++
+	}
+	if s.managedAzureFirewall() != nil {
+		numberOfSubnets++
+	}
 
 	subnetSpecs := make([]azure.ResourceSpecGetter, 0, numberOfSubnets)
 
 	for _, subnet := range s.AzureCluster.Spec.NetworkSpec.Subnets {
 		subnetSpec := &subnets.SubnetSpec{
-			Name:              subnet.Name,
-			ResourceGroup:     s.ResourceGroup(),
-			SubscriptionID:    s.SubscriptionID(),
-			CIDRs:             subnet.CIDRBlocks,
-			VNetName:          s.Vnet().Name,
-			VNetResourceGroup: s.Vnet().ResourceGroup,
-			IsVNetManaged:     s.IsVnetManaged(),
-			RouteTableName:    subnet.RouteTable.Name,
-			SecurityGroupName: subnet.SecurityGroup.Name,
-			Role:              subnet.Role,
-			NatGatewayName:    subnet.NatGateway.Name,
-			ServiceEndpoints:  subnet.ServiceEndpoints,
+			Name:                    subnet.Name,
+			ResourceGroup:           s.ResourceGroup(),
+			SubscriptionID:          s.SubscriptionID(),
+			CIDRs:                   subnet.CIDRBlocks,
+			VNetName:                s.Vnet().Name,
+			VNetResourceGroup:       s.Vnet().ResourceGroup,
+			IsVNetManaged:           s.IsVnetManaged(),
+			RouteTableName:          subnet.RouteTable.Name,
+			SecurityGroupName:       subnet.SecurityGroup.Name,
+			Role:                    subnet.Role,
+			NatGatewayName:          subnet.NatGateway.Name,
+			ServiceEndpoints:        subnet.ServiceEndpoints,
+			ServiceEndpointPolicies: subnet.ServiceEndpointPolicies,
+		}
+		if appGateway := s.AzureCluster.Spec.NetworkSpec.ApplicationGateway; appGateway != nil &&
+			appGateway.Controller == infrav1.ApplicationGatewayForContainers && appGateway.SubnetName == subnet.Name {
+			subnetSpec.Delegations = []string{applicationgateways.SubnetDelegation}
 		}
 		subnetSpecs = append(subnetSpecs, subnetSpec)
 	}
@@ -410,17 +623,49 @@ func (s *ClusterScope) SubnetSpecs() []azure.ResourceSpecGetter {
 	if s.IsAzureBastionEnabled() {
 		azureBastionSubnet := s.AzureCluster.Spec.BastionSpec.AzureBastion.Subnet
 		subnetSpecs = append(subnetSpecs, &subnets.SubnetSpec{
-			Name:              azureBastionSubnet.Name,
-			ResourceGroup:     s.ResourceGroup(),
-			SubscriptionID:    s.SubscriptionID(),
-			CIDRs:             azureBastionSubnet.CIDRBlocks,
-			VNetName:          s.Vnet().Name,
-			VNetResourceGroup: s.Vnet().ResourceGroup,
-			IsVNetManaged:     s.IsVnetManaged(),
-			SecurityGroupName: azureBastionSubnet.SecurityGroup.Name,
-			RouteTableName:    azureBastionSubnet.RouteTable.Name,
-			Role:              azureBastionSubnet.Role,
-			ServiceEndpoints:  azureBastionSubnet.ServiceEndpoints,
+			Name:                    azureBastionSubnet.Name,
+			ResourceGroup:           s.ResourceGroup(),
+			SubscriptionID:          s.SubscriptionID(),
+			CIDRs:                   azureBastionSubnet.CIDRBlocks,
+			VNetName:                s.Vnet().Name,
+			VNetResourceGroup:       s.Vnet().ResourceGroup,
+			IsVNetManaged:           s.IsVnetManaged(),
+			SecurityGroupName:       azureBastionSubnet.SecurityGroup.Name,
+			RouteTableName:          azureBastionSubnet.RouteTable.Name,
+			Role:                    azureBastionSubnet.Role,
+			ServiceEndpoints:        azureBastionSubnet.ServiceEndpoints,
+			ServiceEndpointPolicies: azureBastionSubnet.ServiceEndpointPolicies,
+		})
+	}
+
+	if netApp := s.AzureCluster.Spec.NetworkSpec.NetApp; netApp != nil {
+		subnetSpecs = append(subnetSpecs, &subnets.SubnetSpec{
+			Name:                    netApp.Subnet.Name,
+			ResourceGroup:           s.ResourceGroup(),
+			SubscriptionID:          s.SubscriptionID(),
+			CIDRs:                   netApp.Subnet.CIDRBlocks,
+			VNetName:                s.Vnet().Name,
+			VNetResourceGroup:       s.Vnet().ResourceGroup,
+			IsVNetManaged:           s.IsVnetManaged(),
+			Role:                    netApp.Subnet.Role,
+			ServiceEndpoints:        netApp.Subnet.ServiceEndpoints,
+			ServiceEndpointPolicies: netApp.Subnet.ServiceEndpointPolicies,
+			Delegations:             []string{netapp.SubnetDelegation},
+		})
+	}
+
+	if firewall := s.managedAzureFirewall(); firewall != nil {
+		subnetSpecs = append(subnetSpecs, &subnets.SubnetSpec{
+			Name:                    firewall.Subnet.Name,
+			ResourceGroup:           s.ResourceGroup(),
+			SubscriptionID:          s.SubscriptionID(),
+			CIDRs:                   firewall.Subnet.CIDRBlocks,
+			VNetName:                s.Vnet().Name,
+			VNetResourceGroup:       s.Vnet().ResourceGroup,
+			IsVNetManaged:           s.IsVnetManaged(),
+			Role:                    firewall.Subnet.Role,
+			ServiceEndpoints:        firewall.Subnet.ServiceEndpoints,
+			ServiceEndpointPolicies: firewall.Subnet.ServiceEndpointPolicies,
 		})
 	}
 
@@ -494,6 +739,7 @@ func (s *ClusterScope) VNetSpec() azure.ResourceSpecGetter {
 		Location:         s.Location(),
 		ClusterName:      s.ClusterName(),
 		AdditionalTags:   s.AdditionalTags(),
+		DeletePolicy:     s.Vnet().DeletePolicy,
 	}
 }
 
@@ -547,6 +793,195 @@ func (s *ClusterScope) PrivateDNSSpec() (zoneSpec azure.ResourceSpecGetter, link
 	return nil, nil, nil
 }
 
+// TrafficManagerSpecs returns the Traffic Manager profile and endpoint specs.
+func (s *ClusterScope) TrafficManagerSpecs() (profileSpec azure.ResourceSpecGetter, endpointSpec azure.ResourceSpecGetter) {
+	tm := s.AzureCluster.Spec.TrafficManager
+	if tm == nil || s.IsAPIServerPrivate() {
+		return nil, nil
+	}
+
+	profile := &trafficmanager.ProfileSpec{
+		Name:           tm.ProfileName,
+		ResourceGroup:  tm.ResourceGroup,
+		RoutingMethod:  tm.RoutingMethod,
+		MonitorPort:    s.APIServerPort(),
+		ClusterName:    s.ClusterName(),
+		AdditionalTags: s.AdditionalTags(),
+	}
+	endpoint := &trafficmanager.EndpointSpec{
+		Name:          tm.EndpointName,
+		ProfileName:   tm.ProfileName,
+		ResourceGroup: tm.ResourceGroup,
+		Target:        s.APIServerHost(),
+		Priority:      tm.Priority,
+		Weight:        tm.Weight,
+	}
+
+	return profile, endpoint
+}
+
+// NetAppAccountSpec returns the NetApp account spec, or nil if no NetApp account is configured.
+func (s *ClusterScope) NetAppAccountSpec() azure.ResourceSpecGetter {
+	netApp := s.AzureCluster.Spec.NetworkSpec.NetApp
+	if netApp == nil || netApp.Account == nil {
+		return nil
+	}
+
+	return &netapp.AccountSpec{
+		Name:           netApp.Account.Name,
+		ResourceGroup:  s.ResourceGroup(),
+		Location:       s.Location(),
+		ClusterName:    s.ClusterName(),
+		AdditionalTags: s.AdditionalTags(),
+	}
+}
+
+// NetAppCapacityPoolSpecs returns the capacity pool specs of the NetApp account.
+func (s *ClusterScope) NetAppCapacityPoolSpecs() []azure.ResourceSpecGetter {
+	netApp := s.AzureCluster.Spec.NetworkSpec.NetApp
+	if netApp == nil || netApp.Account == nil {
+		return nil
+	}
+
+	poolSpecs := make([]azure.ResourceSpecGetter, 0, len(netApp.Account.CapacityPools))
+	for _, pool := range netApp.Account.CapacityPools {
+		poolSpecs = append(poolSpecs, &netapp.CapacityPoolSpec{
+			Name:           pool.Name,
+			AccountName:    netApp.Account.Name,
+			ResourceGroup:  s.ResourceGroup(),
+			Location:       s.Location(),
+			ServiceLevel:   pool.ServiceLevel,
+			SizeTiB:        pool.SizeTiB,
+			ClusterName:    s.ClusterName(),
+			AdditionalTags: s.AdditionalTags(),
+		})
+	}
+
+	return poolSpecs
+}
+
+// managedAzureFirewall returns the Azure Firewall the node subnets egress through if CAPZ creates it, or nil.
+func (s *ClusterScope) managedAzureFirewall() *infrav1.AzureFirewallSpec {
+	firewall := s.AzureCluster.Spec.NetworkSpec.AzureFirewall
+	if s.AzureCluster.Spec.NetworkSpec.OutboundType != infrav1.AzureFirewallNodeOutboundType || firewall == nil || firewall.ID != "" {
+		return nil
+	}
+	return firewall
+}
+
+// AzureFirewallSpec returns the Azure Firewall spec, or nil if the Azure Firewall isn't created by CAPZ.
+func (s *ClusterScope) AzureFirewallSpec() azure.ResourceSpecGetter {
+	firewall := s.managedAzureFirewall()
+	if firewall == nil {
+		return nil
+	}
+
+	var sourceCIDRs []string
+	for _, subnet := range s.NodeSubnets() {
+		sourceCIDRs = append(sourceCIDRs, subnet.CIDRBlocks...)
+	}
+
+	return &azurefirewalls.AzureFirewallSpec{
+		Name:           firewall.Name,
+		ResourceGroup:  s.ResourceGroup(),
+		Location:       s.Location(),
+		ClusterName:    s.ClusterName(),
+		SubnetID:       azure.SubnetID(s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name, firewall.Subnet.Name),
+		PublicIPID:     azure.PublicIPID(s.SubscriptionID(), s.ResourceGroup(), firewall.PublicIP.Name),
+		SourceCIDRs:    sourceCIDRs,
+		APIServerPort:  s.APIServerPort(),
+		AllowedFQDNs:   firewall.AllowedFQDNs,
+		AdditionalTags: s.AdditionalTags(),
+	}
+}
+
+// Built-in roles assigned to the identity of an Application Gateway based ingress controller.
+const (
+	readerRoleID                  = "acdd72a7-3385-48ef-bd42-f606fba81ae7"
+	networkContributorRoleID      = "4d97b98b-1d4f-4787-a291-c67834d212e7"
+	appGwForContainersConfigMgrID = "fbc52c3f-28ad-4303-a892-8a056630b8f1"
+)
+
+// managedApplicationGateway returns the Application Gateway for AGIC if CAPZ creates it, or nil.
+func (s *ClusterScope) managedApplicationGateway() *infrav1.ApplicationGatewaySpec {
+	appGateway := s.AzureCluster.Spec.NetworkSpec.ApplicationGateway
+	if appGateway == nil || appGateway.Controller != infrav1.ApplicationGatewayIngressController || appGateway.ID != "" {
+		return nil
+	}
+	return appGateway
+}
+
+// ApplicationGatewaySpec returns the Application Gateway spec, or nil if the Application Gateway isn't created by CAPZ.
+func (s *ClusterScope) ApplicationGatewaySpec() azure.ResourceSpecGetter {
+	appGateway := s.managedApplicationGateway()
+	if appGateway == nil {
+		return nil
+	}
+
+	return &applicationgateways.ApplicationGatewaySpec{
+		Name:           appGateway.Name,
+		ResourceGroup:  s.ResourceGroup(),
+		SubscriptionID: s.SubscriptionID(),
+		Location:       s.Location(),
+		ClusterName:    s.ClusterName(),
+		SubnetID:       azure.SubnetID(s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name, appGateway.SubnetName),
+		PublicIPID:     azure.PublicIPID(s.SubscriptionID(), s.ResourceGroup(), appGateway.PublicIP.Name),
+		AdditionalTags: s.AdditionalTags(),
+	}
+}
+
+// ApplicationGatewayRoleAssignmentSpecs returns the specs of the role assignments of the identity of the
+// Application Gateway based ingress controller, or nil if no identity is set.
+func (s *ClusterScope) ApplicationGatewayRoleAssignmentSpecs() []azure.ResourceSpecGetter {
+	appGateway := s.AzureCluster.Spec.NetworkSpec.ApplicationGateway
+	if appGateway == nil || appGateway.PrincipalID == "" {
+		return nil
+	}
+
+	subnetScope := azure.SubnetID(s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name, appGateway.SubnetName)
+	var specs []azure.ResourceSpecGetter
+	switch appGateway.Controller {
+	case infrav1.ApplicationGatewayForContainers:
+		specs = append(specs,
+			s.applicationGatewayRoleAssignmentSpec(s.SubscriptionID(), appGwForContainersConfigMgrID, azure.ResourceGroupID(s.SubscriptionID(), s.ResourceGroup())),
+			s.applicationGatewayRoleAssignmentSpec(s.SubscriptionID(), networkContributorRoleID, subnetScope),
+		)
+	default:
+		gatewayID := appGateway.ID
+		if gatewayID == "" {
+			gatewayID = azure.ApplicationGatewayID(s.SubscriptionID(), s.ResourceGroup(), appGateway.Name)
+		}
+		resourceID, err := arm.ParseResourceID(gatewayID)
+		if err != nil {
+			// The webhook validates the ID, so this should never happen.
+			return nil
+		}
+		specs = append(specs,
+			s.applicationGatewayRoleAssignmentSpec(resourceID.SubscriptionID, infrav1.ContributorRoleID, gatewayID),
+			s.applicationGatewayRoleAssignmentSpec(resourceID.SubscriptionID, readerRoleID, azure.ResourceGroupID(resourceID.SubscriptionID, resourceID.ResourceGroupName)),
+		)
+		// AGIC needs to join the subnet of an Application Gateway created by CAPZ to configure it.
+		if appGateway.ID == "" {
+			specs = append(specs, s.applicationGatewayRoleAssignmentSpec(s.SubscriptionID(), networkContributorRoleID, subnetScope))
+		}
+	}
+	return specs
+}
+
+// applicationGatewayRoleAssignmentSpec returns the spec of a role assignment of the identity of the Application
+// Gateway based ingress controller on a scope in the given subscription. Its name is derived from its scope, role
+// and principal so that it's stable across reconciliations.
+func (s *ClusterScope) applicationGatewayRoleAssignmentSpec(subscriptionID, roleID, scope string) *roleassignments.RoleAssignmentSpec {
+	principalID := s.AzureCluster.Spec.NetworkSpec.ApplicationGateway.PrincipalID
+	return &roleassignments.RoleAssignmentSpec{
+		Name:             uuid.NewSHA1(uuid.NameSpaceURL, []byte(scope+roleID+principalID)).String(),
+		ResourceGroup:    s.ResourceGroup(),
+		PrincipalID:      ptr.To(principalID),
+		RoleDefinitionID: fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", subscriptionID, roleID),
+		Scope:            scope,
+	}
+}
+
 // IsAzureBastionEnabled returns true if the azure bastion is enabled.
 func (s *ClusterScope) IsAzureBastionEnabled() bool {
 	return s.AzureCluster.Spec.BastionSpec.AzureBastion != nil
@@ -861,9 +1296,46 @@ func (s *ClusterScope) PatchObject(ctx context.Context) error {
 			infrav1.PrivateDNSLinkReadyCondition,
 			infrav1.PrivateDNSRecordReadyCondition,
 			infrav1.PrivateEndpointsReadyCondition,
+			infrav1.ApplicationGatewayReadyCondition,
+			infrav1.AzureResourceAvailableCondition,
+			infrav1.ResourceProvidersRegisteredCondition,
 		}})
 }
 
+// RequiredResourceProviders returns the namespaces of the resource providers the subscription of the cluster must be
+// registered with.
+func (s *ClusterScope) RequiredResourceProviders() []string {
+	providers := []string{"Microsoft.Compute", "Microsoft.Network"}
+	if appGateway := s.AzureCluster.Spec.NetworkSpec.ApplicationGateway; appGateway != nil && appGateway.Controller == infrav1.ApplicationGatewayForContainers {
+		providers = append(providers, "Microsoft.ServiceNetworking")
+	}
+	return providers
+}
+
+// ResourceProvidersObject refers to the AzureCluster.
+func (s *ClusterScope) ResourceProvidersObject() conditions.Setter {
+	return s.AzureCluster
+}
+
+// AvailabilityStatusResource refers to the AzureCluster.
+func (s *ClusterScope) AvailabilityStatusResource() conditions.Setter {
+	return s.AzureCluster
+}
+
+// AvailabilityStatusResourceURI constructs the ID of the API server load balancer.
+func (s *ClusterScope) AvailabilityStatusResourceURI() string {
+	return azure.LoadBalancerID(s.SubscriptionID(), s.ResourceGroup(), s.APIServerLBName())
+}
+
+// AvailabilityStatusFilter ignores the availability status of the API server load balancer until the control plane
+// is initialized, since its health probes can't succeed before then.
+func (s *ClusterScope) AvailabilityStatusFilter(cond *clusterv1.Condition) *clusterv1.Condition {
+	if !conditions.IsTrue(s.Cluster, clusterv1.ControlPlaneInitializedCondition) {
+		return nil
+	}
+	return cond
+}
+
 // Close closes the current scope persisting the cluster configuration and status.
 func (s *ClusterScope) Close(ctx context.Context) error {
 	return s.PatchObject(ctx)
@@ -886,6 +1358,16 @@ func (s *ClusterScope) APIServerPort() int32 {
 	return 6443
 }
 
+// ValidateAPIServerPort checks that the port of the control plane endpoint, when it is set on the AzureCluster,
+// matches the API server port that the load balancer rules, health probe and security rules are created for.
+func (s *ClusterScope) ValidateAPIServerPort() error {
+	port := s.AzureCluster.Spec.ControlPlaneEndpoint.Port
+	if port != 0 && port != s.APIServerPort() {
+		return azure.WithTerminalError(errors.Errorf("control plane endpoint port %d does not match the API server port %d of the cluster, set spec.clusterNetwork.apiServerPort on the Cluster to change the API server port", port, s.APIServerPort()))
+	}
+	return nil
+}
+
 // APIServerHost returns the hostname used to reach the API server.
 func (s *ClusterScope) APIServerHost() string {
 	if s.IsAPIServerPrivate() {
@@ -902,6 +1384,16 @@ func (s *ClusterScope) SetFailureDomain(id string, spec clusterv1.FailureDomainS
 	s.AzureCluster.Status.FailureDomains[id] = spec
 }
 
+// VMSizes returns the VM sizes whose availability zones are reported in the cluster status.
+func (s *ClusterScope) VMSizes() []string {
+	return s.AzureCluster.Spec.VMSizes
+}
+
+// SetVMSizeZones sets the availability zones of the VM sizes of the cluster.
+func (s *ClusterScope) SetVMSizeZones(vmSizeZones []infrav1.VMSizeZones) {
+	s.AzureCluster.Status.VMSizeZones = vmSizeZones
+}
+
 // FailureDomains returns the failure domains for the cluster.
 func (s *ClusterScope) FailureDomains() []string {
 	fds := make([]string, len(s.AzureCluster.Status.FailureDomains))
@@ -945,10 +1437,70 @@ func (s *ClusterScope) SetControlPlaneSecurityRules() {
 				DestinationPorts: ptr.To(strconv.Itoa(int(s.APIServerPort()))),
 			},
 		}
+		if s.IsIPv6Enabled() {
+			subnet.SecurityGroup.SecurityRules = ipFamilySecurityRules(subnet.SecurityGroup.SecurityRules)
+		}
 		s.AzureCluster.Spec.NetworkSpec.UpdateControlPlaneSubnet(subnet)
 	}
 }
 
+// SetNodeSecurityRules sets the default security rules of the node subnets when IPv6 is enabled, so that NodePort
+// services are reachable over both IP families.
+func (s *ClusterScope) SetNodeSecurityRules() {
+	if !s.IsIPv6Enabled() {
+		return
+	}
+	for _, subnet := range s.NodeSubnets() {
+		if subnet.SecurityGroup.SecurityRules != nil {
+			continue
+		}
+		subnet.SecurityGroup.SecurityRules = ipFamilySecurityRules(infrav1.SecurityRules{
+			infrav1.SecurityRule{
+				Name:             "allow_node_ports",
+				Description:      "Allow K8s NodePort services",
+				Priority:         2200,
+				Protocol:         infrav1.SecurityGroupProtocolTCP,
+				Direction:        infrav1.SecurityRuleDirectionInbound,
+				Source:           ptr.To("*"),
+				SourcePorts:      ptr.To("*"),
+				Destination:      ptr.To("*"),
+				DestinationPorts: ptr.To(nodePortRange),
+			},
+		})
+		s.SetSubnet(subnet)
+	}
+}
+
+const (
+	// nodePortRange is the default port range of Kubernetes NodePort services.
+	nodePortRange = "30000-32767"
+
+	// ipv6SecurityRulePriorityOffset separates the priorities of the IPv6 default security rules from their IPv4 counterparts.
+	ipv6SecurityRulePriorityOffset = 100
+)
+
+// ipFamilySecurityRules splits default security rules into one rule per IP family. A "*" address prefix already
+// matches both families, so the rules are restricted to "0.0.0.0/0" and "::/0" to keep each family explicit.
+func ipFamilySecurityRules(rules infrav1.SecurityRules) infrav1.SecurityRules {
+	ipv4Rules := make(infrav1.SecurityRules, 0, len(rules))
+	ipv6Rules := make(infrav1.SecurityRules, 0, len(rules))
+	for _, rule := range rules {
+		ipv4Rule := rule
+		ipv4Rule.Source = ptr.To("0.0.0.0/0")
+		ipv4Rule.Destination = ptr.To("0.0.0.0/0")
+		ipv4Rules = append(ipv4Rules, ipv4Rule)
+
+		ipv6Rule := rule
+		ipv6Rule.Name += "_ipv6"
+		ipv6Rule.Description += " over IPv6"
+		ipv6Rule.Priority += ipv6SecurityRulePriorityOffset
+		ipv6Rule.Source = ptr.To("::/0")
+		ipv6Rule.Destination = ptr.To("::/0")
+		ipv6Rules = append(ipv6Rules, ipv6Rule)
+	}
+	return append(ipv4Rules, ipv6Rules...)
+}
+
 // SetDNSName sets the API Server public IP DNS name.
 // Note: this logic exists only for purposes of ensuring backwards compatibility for old clusters created without an APIServerLB, and should be removed in the future.
 func (s *ClusterScope) SetDNSName() {
@@ -1069,6 +1621,12 @@ func (s *ClusterScope) SetAnnotation(key, value string) {
 	s.AzureCluster.Annotations[key] = value
 }
 
+// BasicSKUMigrationRequested returns true if the AzureCluster is annotated to migrate its Basic load balancers
+// and public IPs to the Standard SKU.
+func (s *ClusterScope) BasicSKUMigrationRequested() bool {
+	return s.AzureCluster.GetAnnotations()[azure.BasicSKUMigrationAnnotation] == "true"
+}
+
 // TagsSpecs returns the tag specs for the AzureCluster.
 func (s *ClusterScope) TagsSpecs() []azure.TagsSpec {
 	if s.UseLegacyGroups {
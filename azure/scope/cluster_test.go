@@ -27,20 +27,26 @@ import (
 	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/applicationgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/azurefirewalls"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bastionhosts"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/natgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/netapp"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/routetables"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/securitygroups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vnetpeerings"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -246,6 +252,124 @@ func TestGettingSecurityRules(t *testing.T) {
 	subnet, err := clusterScope.AzureCluster.Spec.NetworkSpec.GetControlPlaneSubnet()
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(len(subnet.SecurityGroup.SecurityRules)).To(Equal(2))
+
+	clusterScope.SetNodeSecurityRules()
+
+	for _, nodeSubnet := range clusterScope.NodeSubnets() {
+		g.Expect(nodeSubnet.SecurityGroup.SecurityRules).To(BeNil())
+	}
+}
+
+func TestGettingSecurityRulesIPv6(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster",
+			Namespace: "default",
+		},
+	}
+
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-azure-cluster",
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "cluster.x-k8s.io/v1beta1",
+					Kind:       "Cluster",
+					Name:       "my-cluster",
+				},
+			},
+		},
+		Spec: infrav1.AzureClusterSpec{
+			AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+				SubscriptionID: "123",
+			},
+			NetworkSpec: infrav1.NetworkSpec{
+				Vnet: infrav1.VnetSpec{
+					VnetClassSpec: infrav1.VnetClassSpec{
+						CIDRBlocks: []string{"10.0.0.0/8", "2001:1234:5678:9a00::/56"},
+					},
+				},
+			},
+		},
+	}
+	azureCluster.Default()
+
+	initObjects := []runtime.Object{cluster, azureCluster}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+	clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+		Client:       fakeClient,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	clusterScope.SetControlPlaneSecurityRules()
+
+	subnet, err := clusterScope.AzureCluster.Spec.NetworkSpec.GetControlPlaneSubnet()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(subnet.SecurityGroup.SecurityRules).To(HaveLen(4))
+	g.Expect(subnet.SecurityGroup.SecurityRules[0]).To(Equal(infrav1.SecurityRule{
+		Name:             "allow_ssh",
+		Description:      "Allow SSH",
+		Priority:         2200,
+		Protocol:         infrav1.SecurityGroupProtocolTCP,
+		Direction:        infrav1.SecurityRuleDirectionInbound,
+		Source:           ptr.To("0.0.0.0/0"),
+		SourcePorts:      ptr.To("*"),
+		Destination:      ptr.To("0.0.0.0/0"),
+		DestinationPorts: ptr.To("22"),
+	}))
+	g.Expect(subnet.SecurityGroup.SecurityRules[2]).To(Equal(infrav1.SecurityRule{
+		Name:             "allow_ssh_ipv6",
+		Description:      "Allow SSH over IPv6",
+		Priority:         2300,
+		Protocol:         infrav1.SecurityGroupProtocolTCP,
+		Direction:        infrav1.SecurityRuleDirectionInbound,
+		Source:           ptr.To("::/0"),
+		SourcePorts:      ptr.To("*"),
+		Destination:      ptr.To("::/0"),
+		DestinationPorts: ptr.To("22"),
+	}))
+	g.Expect(subnet.SecurityGroup.SecurityRules[3].Name).To(Equal("allow_apiserver_ipv6"))
+	g.Expect(subnet.SecurityGroup.SecurityRules[3].DestinationPorts).To(Equal(ptr.To("6443")))
+
+	clusterScope.SetNodeSecurityRules()
+
+	nodeSubnets := clusterScope.NodeSubnets()
+	g.Expect(nodeSubnets).To(HaveLen(1))
+	g.Expect(nodeSubnets[0].SecurityGroup.SecurityRules).To(Equal(infrav1.SecurityRules{
+		{
+			Name:             "allow_node_ports",
+			Description:      "Allow K8s NodePort services",
+			Priority:         2200,
+			Protocol:         infrav1.SecurityGroupProtocolTCP,
+			Direction:        infrav1.SecurityRuleDirectionInbound,
+			Source:           ptr.To("0.0.0.0/0"),
+			SourcePorts:      ptr.To("*"),
+			Destination:      ptr.To("0.0.0.0/0"),
+			DestinationPorts: ptr.To("30000-32767"),
+		},
+		{
+			Name:             "allow_node_ports_ipv6",
+			Description:      "Allow K8s NodePort services over IPv6",
+			Priority:         2300,
+			Protocol:         infrav1.SecurityGroupProtocolTCP,
+			Direction:        infrav1.SecurityRuleDirectionInbound,
+			Source:           ptr.To("::/0"),
+			SourcePorts:      ptr.To("*"),
+			Destination:      ptr.To("::/0"),
+			DestinationPorts: ptr.To("30000-32767"),
+		},
+	}))
 }
 
 func TestPublicIPSpecs(t *testing.T) {
@@ -575,6 +699,71 @@ func TestPublicIPSpecs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Azure cluster with zonal public type apiserver LB",
+			azureCluster: &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-cluster",
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: "cluster.x-k8s.io/v1beta1",
+							Kind:       "Cluster",
+							Name:       "my-cluster",
+						},
+					},
+				},
+				Status: infrav1.AzureClusterStatus{
+					FailureDomains: map[string]clusterv1.FailureDomainSpec{
+						"failure-domain-id-1": {},
+						"failure-domain-id-2": {},
+						"failure-domain-id-3": {},
+					},
+				},
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						SubscriptionID: "123",
+						Location:       "centralIndia",
+						AdditionalTags: infrav1.Tags{
+							"Name": "my-publicip-ipv6",
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+						},
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						ControlPlaneOutboundLB: &infrav1.LoadBalancerSpec{
+							LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{},
+						},
+						APIServerLB: infrav1.LoadBalancerSpec{
+							LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{},
+							FrontendIPs: []infrav1.FrontendIP{
+								{
+									PublicIP: &infrav1.PublicIPSpec{
+										Name:    "40.60.89.22",
+										DNSName: "fake-dns",
+										Zones:   []string{"2"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedPublicIPSpec: []azure.ResourceSpecGetter{
+				&publicips.PublicIPSpec{
+					Name:           "40.60.89.22",
+					ResourceGroup:  "my-rg",
+					DNSName:        "fake-dns",
+					IsIPv6:         false,
+					ClusterName:    "my-cluster",
+					Location:       "centralIndia",
+					FailureDomains: []string{"2"},
+					AdditionalTags: infrav1.Tags{
+						"Name": "my-publicip-ipv6",
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+					},
+				},
+			},
+		},
 		{
 			name: "Azure cluster with public type apiserver LB and public node outbound lb",
 			azureCluster: &infrav1.AzureCluster{
@@ -850,6 +1039,58 @@ func TestRouteTableSpecs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "routes the node subnets through the azure firewall",
+			clusterScope: ClusterScope{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-cluster",
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+							Location: "centralIndia",
+						},
+						NetworkSpec: infrav1.NetworkSpec{
+							Subnets: infrav1.Subnets{
+								{
+									SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetControlPlane},
+									RouteTable:      infrav1.RouteTable{Name: "fake-cp-route-table"},
+								},
+								{
+									SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetNode},
+									RouteTable:      infrav1.RouteTable{Name: "fake-node-route-table"},
+								},
+							},
+							OutboundType:  infrav1.AzureFirewallNodeOutboundType,
+							AzureFirewall: &infrav1.AzureFirewallSpec{PrivateIPAddress: "10.255.255.4"},
+						},
+					},
+				},
+				cache: &ClusterCache{},
+			},
+			want: []azure.ResourceSpecGetter{
+				&routetables.RouteTableSpec{
+					Name:           "fake-cp-route-table",
+					ResourceGroup:  "my-rg",
+					Location:       "centralIndia",
+					ClusterName:    "my-cluster",
+					AdditionalTags: make(infrav1.Tags),
+				},
+				&routetables.RouteTableSpec{
+					Name:           "fake-node-route-table",
+					ResourceGroup:  "my-rg",
+					Location:       "centralIndia",
+					ClusterName:    "my-cluster",
+					AdditionalTags: make(infrav1.Tags),
+					Routes: []routetables.RouteSpec{
+						{Name: "default-via-firewall", AddressPrefix: "0.0.0.0/0", NextHopIPAddress: "10.255.255.4"},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1335,84 +1576,542 @@ func TestSubnetSpecs(t *testing.T) {
 						},
 						ResourceGroup: "my-rg",
 						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
-							Location: "centralIndia",
+							Location: "centralIndia",
+						},
+						NetworkSpec: infrav1.NetworkSpec{
+							Vnet: infrav1.VnetSpec{
+								ID:            "fake-vnet-id-1",
+								Name:          "fake-vnet-1",
+								ResourceGroup: "my-rg-vnet",
+							},
+							Subnets: infrav1.Subnets{
+								{
+									SubnetClassSpec: infrav1.SubnetClassSpec{
+										Role:       infrav1.SubnetNode,
+										CIDRBlocks: []string{"192.168.1.1/16"},
+										Name:       "fake-subnet-1",
+									},
+									NatGateway: infrav1.NatGateway{
+										NatGatewayClassSpec: infrav1.NatGatewayClassSpec{
+											Name: "fake-natgateway-1",
+										},
+									},
+									RouteTable: infrav1.RouteTable{
+										ID:   "fake-route-table-id-1",
+										Name: "fake-route-table-1",
+									},
+									SecurityGroup: infrav1.SecurityGroup{
+										Name: "fake-security-group-1",
+										SecurityGroupClass: infrav1.SecurityGroupClass{
+											SecurityRules: infrav1.SecurityRules{
+												{
+													Name: "fake-rule-1",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				cache: &ClusterCache{},
+			},
+			want: []azure.ResourceSpecGetter{
+				&subnets.SubnetSpec{
+					Name:              "fake-subnet-1",
+					ResourceGroup:     "my-rg",
+					SubscriptionID:    "123",
+					CIDRs:             []string{"192.168.1.1/16"},
+					VNetName:          "fake-vnet-1",
+					VNetResourceGroup: "my-rg-vnet",
+					IsVNetManaged:     false,
+					RouteTableName:    "fake-route-table-1",
+					SecurityGroupName: "fake-security-group-1",
+					Role:              infrav1.SubnetNode,
+					NatGatewayName:    "fake-natgateway-1",
+				},
+				&subnets.SubnetSpec{
+					Name:              "fake-bastion-subnet-1",
+					ResourceGroup:     "my-rg",
+					SubscriptionID:    "123",
+					CIDRs:             []string{"172.122.1.1./16"},
+					VNetName:          "fake-vnet-1",
+					VNetResourceGroup: "my-rg-vnet",
+					IsVNetManaged:     false,
+					SecurityGroupName: "fake-bastion-security-group-1",
+					RouteTableName:    "fake-bastion-route-table-1",
+					Role:              infrav1.SubnetBastion,
+				},
+			},
+		},
+		{
+			name: "returns specified subnet spec and delegated netapp subnet spec if configured",
+			clusterScope: ClusterScope{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-cluster",
+					},
+				},
+				AzureClients: AzureClients{
+					EnvironmentSettings: auth.EnvironmentSettings{
+						Values: map[string]string{
+							auth.SubscriptionID: "123",
+						},
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+						NetworkSpec: infrav1.NetworkSpec{
+							Vnet: infrav1.VnetSpec{
+								ID:            "fake-vnet-id-1",
+								Name:          "fake-vnet-1",
+								ResourceGroup: "my-rg-vnet",
+							},
+							Subnets: infrav1.Subnets{
+								{
+									SubnetClassSpec: infrav1.SubnetClassSpec{
+										Role:       infrav1.SubnetNode,
+										CIDRBlocks: []string{"192.168.1.1/16"},
+										Name:       "fake-subnet-1",
+									},
+								},
+							},
+							NetApp: &infrav1.NetAppSpec{
+								Subnet: infrav1.SubnetSpec{
+									SubnetClassSpec: infrav1.SubnetClassSpec{
+										Role:       infrav1.SubnetNetApp,
+										CIDRBlocks: []string{"10.255.254.0/24"},
+										Name:       "fake-netapp-subnet",
+									},
+								},
+							},
+						},
+					},
+				},
+				cache: &ClusterCache{},
+			},
+			want: []azure.ResourceSpecGetter{
+				&subnets.SubnetSpec{
+					Name:              "fake-subnet-1",
+					ResourceGroup:     "my-rg",
+					SubscriptionID:    "123",
+					CIDRs:             []string{"192.168.1.1/16"},
+					VNetName:          "fake-vnet-1",
+					VNetResourceGroup: "my-rg-vnet",
+					IsVNetManaged:     false,
+					Role:              infrav1.SubnetNode,
+				},
+				&subnets.SubnetSpec{
+					Name:              "fake-netapp-subnet",
+					ResourceGroup:     "my-rg",
+					SubscriptionID:    "123",
+					CIDRs:             []string{"10.255.254.0/24"},
+					VNetName:          "fake-vnet-1",
+					VNetResourceGroup: "my-rg-vnet",
+					IsVNetManaged:     false,
+					Role:              infrav1.SubnetNetApp,
+					Delegations:       []string{"Microsoft.NetApp/volumes"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.clusterScope.SubnetSpecs(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SubnetSpecs() = \n%s, want \n%s", specArrayToString(got), specArrayToString(tt.want))
+			}
+		})
+	}
+}
+
+func TestNetAppSpecs(t *testing.T) {
+	tests := []struct {
+		name        string
+		netApp      *infrav1.NetAppSpec
+		wantAccount azure.ResourceSpecGetter
+		wantPools   []azure.ResourceSpecGetter
+	}{
+		{
+			name:   "returns nothing if netapp is not configured",
+			netApp: nil,
+		},
+		{
+			name: "returns nothing if no netapp account is configured",
+			netApp: &infrav1.NetAppSpec{
+				Subnet: infrav1.SubnetSpec{SubnetClassSpec: infrav1.SubnetClassSpec{Name: "netapp-subnet"}},
+			},
+		},
+		{
+			name: "returns account and capacity pool specs",
+			netApp: &infrav1.NetAppSpec{
+				Account: &infrav1.NetAppAccount{
+					Name: "my-cluster-netapp",
+					CapacityPools: []infrav1.NetAppCapacityPool{
+						{Name: "pool1", ServiceLevel: infrav1.NetAppServiceLevelPremium, SizeTiB: 4},
+					},
+				},
+			},
+			wantAccount: &netapp.AccountSpec{
+				Name:           "my-cluster-netapp",
+				ResourceGroup:  "my-rg",
+				Location:       "westus",
+				ClusterName:    "my-cluster",
+				AdditionalTags: infrav1.Tags{},
+			},
+			wantPools: []azure.ResourceSpecGetter{
+				&netapp.CapacityPoolSpec{
+					Name:           "pool1",
+					AccountName:    "my-cluster-netapp",
+					ResourceGroup:  "my-rg",
+					Location:       "westus",
+					ServiceLevel:   infrav1.NetAppServiceLevelPremium,
+					SizeTiB:        4,
+					ClusterName:    "my-cluster",
+					AdditionalTags: infrav1.Tags{},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			clusterScope := ClusterScope{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-cluster",
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+							Location: "westus",
+						},
+						NetworkSpec: infrav1.NetworkSpec{
+							NetApp: tt.netApp,
+						},
+					},
+				},
+			}
+			if tt.wantAccount == nil {
+				g.Expect(clusterScope.NetAppAccountSpec()).To(BeNil())
+				g.Expect(clusterScope.NetAppCapacityPoolSpecs()).To(BeEmpty())
+				return
+			}
+			g.Expect(clusterScope.NetAppAccountSpec()).To(Equal(tt.wantAccount))
+			g.Expect(clusterScope.NetAppCapacityPoolSpecs()).To(Equal(tt.wantPools))
+		})
+	}
+}
+
+func TestAzureFirewallSpec(t *testing.T) {
+	tests := []struct {
+		name          string
+		outboundType  infrav1.NodeOutboundType
+		azureFirewall *infrav1.AzureFirewallSpec
+		want          azure.ResourceSpecGetter
+	}{
+		{
+			name: "returns nil if the node subnets don't egress through an azure firewall",
+		},
+		{
+			name:         "returns nil for an existing azure firewall",
+			outboundType: infrav1.AzureFirewallNodeOutboundType,
+			azureFirewall: &infrav1.AzureFirewallSpec{
+				ID:               "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/azureFirewalls/hub-firewall",
+				PrivateIPAddress: "192.168.0.4",
+			},
+		},
+		{
+			name:         "returns the spec of an azure firewall created by CAPZ",
+			outboundType: infrav1.AzureFirewallNodeOutboundType,
+			azureFirewall: &infrav1.AzureFirewallSpec{
+				Name: "my-cluster-firewall",
+				Subnet: infrav1.SubnetSpec{
+					SubnetClassSpec: infrav1.SubnetClassSpec{
+						Name:       "AzureFirewallSubnet",
+						CIDRBlocks: []string{"10.255.255.0/26"},
+						Role:       infrav1.SubnetFirewall,
+					},
+				},
+				PublicIP:         infrav1.PublicIPSpec{Name: "my-cluster-firewall-pip"},
+				PrivateIPAddress: "10.255.255.4",
+				AllowedFQDNs:     []string{"example.com"},
+			},
+			want: &azurefirewalls.AzureFirewallSpec{
+				Name:           "my-cluster-firewall",
+				ResourceGroup:  "my-rg",
+				Location:       "westus",
+				ClusterName:    "my-cluster",
+				SubnetID:       "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/AzureFirewallSubnet",
+				PublicIPID:     "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-cluster-firewall-pip",
+				SourceCIDRs:    []string{"10.1.0.0/16"},
+				APIServerPort:  6443,
+				AllowedFQDNs:   []string{"example.com"},
+				AdditionalTags: infrav1.Tags{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			clusterScope := ClusterScope{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-cluster",
+					},
+				},
+				AzureClients: AzureClients{
+					EnvironmentSettings: auth.EnvironmentSettings{
+						Values: map[string]string{
+							auth.SubscriptionID: "123",
+						},
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+							Location: "westus",
 						},
 						NetworkSpec: infrav1.NetworkSpec{
 							Vnet: infrav1.VnetSpec{
-								ID:            "fake-vnet-id-1",
-								Name:          "fake-vnet-1",
-								ResourceGroup: "my-rg-vnet",
+								Name:          "my-vnet",
+								ResourceGroup: "my-rg",
 							},
 							Subnets: infrav1.Subnets{
 								{
 									SubnetClassSpec: infrav1.SubnetClassSpec{
-										Role:       infrav1.SubnetNode,
-										CIDRBlocks: []string{"192.168.1.1/16"},
-										Name:       "fake-subnet-1",
-									},
-									NatGateway: infrav1.NatGateway{
-										NatGatewayClassSpec: infrav1.NatGatewayClassSpec{
-											Name: "fake-natgateway-1",
-										},
-									},
-									RouteTable: infrav1.RouteTable{
-										ID:   "fake-route-table-id-1",
-										Name: "fake-route-table-1",
+										Name:       "control-plane-subnet",
+										CIDRBlocks: []string{"10.0.0.0/16"},
+										Role:       infrav1.SubnetControlPlane,
 									},
-									SecurityGroup: infrav1.SecurityGroup{
-										Name: "fake-security-group-1",
-										SecurityGroupClass: infrav1.SecurityGroupClass{
-											SecurityRules: infrav1.SecurityRules{
-												{
-													Name: "fake-rule-1",
-												},
-											},
-										},
+								},
+								{
+									SubnetClassSpec: infrav1.SubnetClassSpec{
+										Name:       "node-subnet",
+										CIDRBlocks: []string{"10.1.0.0/16"},
+										Role:       infrav1.SubnetNode,
 									},
 								},
 							},
+							OutboundType:  tt.outboundType,
+							AzureFirewall: tt.azureFirewall,
 						},
 					},
 				},
-				cache: &ClusterCache{},
+			}
+			if tt.want == nil {
+				g.Expect(clusterScope.AzureFirewallSpec()).To(BeNil())
+				return
+			}
+			g.Expect(clusterScope.AzureFirewallSpec()).To(Equal(tt.want))
+		})
+	}
+}
+
+func newApplicationGatewayTestClusterScope(appGateway *infrav1.ApplicationGatewaySpec) ClusterScope {
+	return ClusterScope{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-cluster",
 			},
-			want: []azure.ResourceSpecGetter{
-				&subnets.SubnetSpec{
-					Name:              "fake-subnet-1",
-					ResourceGroup:     "my-rg",
-					SubscriptionID:    "123",
-					CIDRs:             []string{"192.168.1.1/16"},
-					VNetName:          "fake-vnet-1",
-					VNetResourceGroup: "my-rg-vnet",
-					IsVNetManaged:     false,
-					RouteTableName:    "fake-route-table-1",
-					SecurityGroupName: "fake-security-group-1",
-					Role:              infrav1.SubnetNode,
-					NatGatewayName:    "fake-natgateway-1",
+		},
+		AzureClients: AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Values: map[string]string{
+					auth.SubscriptionID: "123",
 				},
-				&subnets.SubnetSpec{
-					Name:              "fake-bastion-subnet-1",
-					ResourceGroup:     "my-rg",
-					SubscriptionID:    "123",
-					CIDRs:             []string{"172.122.1.1./16"},
-					VNetName:          "fake-vnet-1",
-					VNetResourceGroup: "my-rg-vnet",
-					IsVNetManaged:     false,
-					SecurityGroupName: "fake-bastion-security-group-1",
-					RouteTableName:    "fake-bastion-route-table-1",
-					Role:              infrav1.SubnetBastion,
+			},
+		},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+				AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+					Location: "westus",
+				},
+				NetworkSpec: infrav1.NetworkSpec{
+					Vnet: infrav1.VnetSpec{
+						Name:          "my-vnet",
+						ResourceGroup: "my-rg",
+					},
+					Subnets: infrav1.Subnets{
+						{
+							SubnetClassSpec: infrav1.SubnetClassSpec{
+								Name:       "node-subnet",
+								CIDRBlocks: []string{"10.1.0.0/16"},
+								Role:       infrav1.SubnetNode,
+							},
+						},
+						{
+							SubnetClassSpec: infrav1.SubnetClassSpec{
+								Name:       "ingress-subnet",
+								CIDRBlocks: []string{"10.2.0.0/24"},
+								Role:       infrav1.SubnetIngress,
+							},
+						},
+					},
+					ApplicationGateway: appGateway,
 				},
 			},
 		},
 	}
+}
+
+func TestApplicationGatewaySpec(t *testing.T) {
+	tests := []struct {
+		name       string
+		appGateway *infrav1.ApplicationGatewaySpec
+		want       azure.ResourceSpecGetter
+	}{
+		{
+			name: "returns nil if there is no application gateway",
+		},
+		{
+			name: "returns nil for an existing application gateway",
+			appGateway: &infrav1.ApplicationGatewaySpec{
+				Controller: infrav1.ApplicationGatewayIngressController,
+				ID:         "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/applicationGateways/hub-appgw",
+			},
+		},
+		{
+			name: "returns nil for application gateway for containers",
+			appGateway: &infrav1.ApplicationGatewaySpec{
+				Controller: infrav1.ApplicationGatewayForContainers,
+				SubnetName: "ingress-subnet",
+			},
+		},
+		{
+			name: "returns the spec of an application gateway created by CAPZ",
+			appGateway: &infrav1.ApplicationGatewaySpec{
+				Controller: infrav1.ApplicationGatewayIngressController,
+				Name:       "my-cluster-appgw",
+				SubnetName: "ingress-subnet",
+				PublicIP:   infrav1.PublicIPSpec{Name: "my-cluster-appgw-pip"},
+			},
+			want: &applicationgateways.ApplicationGatewaySpec{
+				Name:           "my-cluster-appgw",
+				ResourceGroup:  "my-rg",
+				SubscriptionID: "123",
+				Location:       "westus",
+				ClusterName:    "my-cluster",
+				SubnetID:       "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/ingress-subnet",
+				PublicIPID:     "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-cluster-appgw-pip",
+				AdditionalTags: infrav1.Tags{},
+			},
+		},
+	}
 
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
 			t.Parallel()
-			if got := tt.clusterScope.SubnetSpecs(); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("SubnetSpecs() = \n%s, want \n%s", specArrayToString(got), specArrayToString(tt.want))
+			clusterScope := newApplicationGatewayTestClusterScope(tt.appGateway)
+			if tt.want == nil {
+				g.Expect(clusterScope.ApplicationGatewaySpec()).To(BeNil())
+				return
+			}
+			g.Expect(clusterScope.ApplicationGatewaySpec()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestApplicationGatewayRoleAssignmentSpecs(t *testing.T) {
+	const (
+		roleDefinitionPrefix = "/subscriptions/123/providers/Microsoft.Authorization/roleDefinitions/"
+		subnetID             = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/ingress-subnet"
+	)
+
+	tests := []struct {
+		name       string
+		appGateway *infrav1.ApplicationGatewaySpec
+		// want maps the scopes of the role assignments to the role definition IDs assigned on them.
+		want map[string]string
+	}{
+		{
+			name: "returns nil if there is no application gateway",
+		},
+		{
+			name: "returns nil if no principal ID is set",
+			appGateway: &infrav1.ApplicationGatewaySpec{
+				Controller: infrav1.ApplicationGatewayIngressController,
+				Name:       "my-cluster-appgw",
+				SubnetName: "ingress-subnet",
+			},
+		},
+		{
+			name: "assigns roles on an application gateway created by CAPZ",
+			appGateway: &infrav1.ApplicationGatewaySpec{
+				Controller:  infrav1.ApplicationGatewayIngressController,
+				Name:        "my-cluster-appgw",
+				SubnetName:  "ingress-subnet",
+				PrincipalID: "my-principal",
+			},
+			want: map[string]string{
+				"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/applicationGateways/my-cluster-appgw": roleDefinitionPrefix + infrav1.ContributorRoleID,
+				"/subscriptions/123/resourceGroups/my-rg": roleDefinitionPrefix + readerRoleID,
+				subnetID: roleDefinitionPrefix + networkContributorRoleID,
+			},
+		},
+		{
+			name: "assigns roles on an existing application gateway",
+			appGateway: &infrav1.ApplicationGatewaySpec{
+				Controller:  infrav1.ApplicationGatewayIngressController,
+				ID:          "/subscriptions/456/resourceGroups/hub-rg/providers/Microsoft.Network/applicationGateways/hub-appgw",
+				PrincipalID: "my-principal",
+			},
+			want: map[string]string{
+				"/subscriptions/456/resourceGroups/hub-rg/providers/Microsoft.Network/applicationGateways/hub-appgw": "/subscriptions/456/providers/Microsoft.Authorization/roleDefinitions/" + infrav1.ContributorRoleID,
+				"/subscriptions/456/resourceGroups/hub-rg":                                                           "/subscriptions/456/providers/Microsoft.Authorization/roleDefinitions/" + readerRoleID,
+			},
+		},
+		{
+			name: "assigns roles for application gateway for containers",
+			appGateway: &infrav1.ApplicationGatewaySpec{
+				Controller:  infrav1.ApplicationGatewayForContainers,
+				SubnetName:  "ingress-subnet",
+				PrincipalID: "my-principal",
+			},
+			want: map[string]string{
+				"/subscriptions/123/resourceGroups/my-rg": roleDefinitionPrefix + appGwForContainersConfigMgrID,
+				subnetID: roleDefinitionPrefix + networkContributorRoleID,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			clusterScope := newApplicationGatewayTestClusterScope(tt.appGateway)
+			specs := clusterScope.ApplicationGatewayRoleAssignmentSpecs()
+			if tt.want == nil {
+				g.Expect(specs).To(BeNil())
+				return
+			}
+			got := map[string]string{}
+			for _, spec := range specs {
+				roleAssignment, ok := spec.(*roleassignments.RoleAssignmentSpec)
+				g.Expect(ok).To(BeTrue())
+				g.Expect(roleAssignment.PrincipalID).To(Equal(ptr.To("my-principal")))
+				got[roleAssignment.Scope] = roleAssignment.RoleDefinitionID
 			}
+			g.Expect(got).To(Equal(tt.want))
+			// The names of the role assignments must be stable across reconciliations.
+			g.Expect(clusterScope.ApplicationGatewayRoleAssignmentSpecs()).To(Equal(specs))
 		})
 	}
 }
@@ -2609,6 +3308,214 @@ func TestAPIServerPort(t *testing.T) {
 	}
 }
 
+func TestValidateAPIServerPort(t *testing.T) {
+	tests := []struct {
+		name           string
+		clusterNetwork *clusterv1.ClusterNetwork
+		endpointPort   int32
+		wantErr        bool
+	}{
+		{
+			name:    "endpoint port not set yet",
+			wantErr: false,
+		},
+		{
+			name:         "endpoint port matches default API server port",
+			endpointPort: 6443,
+			wantErr:      false,
+		},
+		{
+			name: "endpoint port matches custom API server port",
+			clusterNetwork: &clusterv1.ClusterNetwork{
+				APIServerPort: ptr.To[int32](8443),
+			},
+			endpointPort: 8443,
+			wantErr:      false,
+		},
+		{
+			name:         "endpoint port does not match API server port",
+			endpointPort: 8443,
+			wantErr:      true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterScope := &ClusterScope{
+				Cluster: &clusterv1.Cluster{
+					Spec: clusterv1.ClusterSpec{
+						ClusterNetwork: tc.clusterNetwork,
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ControlPlaneEndpoint: clusterv1.APIEndpoint{
+							Host: "my-cluster.westeurope.cloudapp.azure.com",
+							Port: tc.endpointPort,
+						},
+					},
+				},
+			}
+			err := clusterScope.ValidateAPIServerPort()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				var reconcileErr azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileErr)).To(BeTrue())
+				g.Expect(reconcileErr.IsTerminal()).To(BeTrue())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestRetainedResources(t *testing.T) {
+	tests := []struct {
+		name                    string
+		networkSpec             infrav1.NetworkSpec
+		wantRetainedResources   []string
+		wantRetainedInClusterRG bool
+	}{
+		{
+			name: "no retained resources",
+			networkSpec: infrav1.NetworkSpec{
+				Vnet: infrav1.VnetSpec{ResourceGroup: "my-rg", Name: "my-vnet"},
+			},
+			wantRetainedResources:   nil,
+			wantRetainedInClusterRG: false,
+		},
+		{
+			name: "retained vnet in another resource group",
+			networkSpec: infrav1.NetworkSpec{
+				Vnet: infrav1.VnetSpec{ResourceGroup: "vnet-rg", Name: "my-vnet", DeletePolicy: infrav1.DeletePolicyRetain},
+			},
+			wantRetainedResources:   []string{"/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"},
+			wantRetainedInClusterRG: false,
+		},
+		{
+			name: "retained public IPs are only listed once",
+			networkSpec: infrav1.NetworkSpec{
+				Vnet: infrav1.VnetSpec{ResourceGroup: "my-rg", Name: "my-vnet"},
+				Subnets: infrav1.Subnets{
+					{
+						SubnetClassSpec: infrav1.SubnetClassSpec{Name: "node-subnet-1", Role: infrav1.SubnetNode},
+						NatGateway: infrav1.NatGateway{
+							NatGatewayClassSpec: infrav1.NatGatewayClassSpec{Name: "my-natgw"},
+							NatGatewayIP:        infrav1.PublicIPSpec{Name: "natgw-ip", DeletePolicy: infrav1.DeletePolicyRetain},
+						},
+					},
+					{
+						SubnetClassSpec: infrav1.SubnetClassSpec{Name: "node-subnet-2", Role: infrav1.SubnetNode},
+						NatGateway: infrav1.NatGateway{
+							NatGatewayClassSpec: infrav1.NatGatewayClassSpec{Name: "my-natgw"},
+							NatGatewayIP:        infrav1.PublicIPSpec{Name: "natgw-ip", DeletePolicy: infrav1.DeletePolicyRetain},
+						},
+					},
+				},
+				APIServerLB: infrav1.LoadBalancerSpec{
+					FrontendIPs: []infrav1.FrontendIP{
+						{Name: "api-frontend", PublicIP: &infrav1.PublicIPSpec{Name: "api-ip"}},
+					},
+				},
+			},
+			wantRetainedResources:   []string{"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/natgw-ip"},
+			wantRetainedInClusterRG: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterScope := &ClusterScope{
+				AzureClients: AzureClients{
+					EnvironmentSettings: auth.EnvironmentSettings{
+						Values: map[string]string{
+							auth.SubscriptionID: "123",
+						},
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+						NetworkSpec:   tc.networkSpec,
+					},
+				},
+			}
+			g.Expect(clusterScope.RetainedResources()).To(Equal(tc.wantRetainedResources))
+			g.Expect(clusterScope.HasRetainedResourcesInResourceGroup()).To(Equal(tc.wantRetainedInClusterRG))
+		})
+	}
+}
+
+func TestLoadRetainedDisks(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+
+	machine := func(name, clusterName string, osDiskPolicy infrav1.DeletePolicy, dataDisks ...infrav1.DataDisk) *infrav1.AzureMachine {
+		return &infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+			},
+			Spec: infrav1.AzureMachineSpec{
+				OSDisk:    infrav1.OSDisk{DeletePolicy: osDiskPolicy},
+				DataDisks: dataDisks,
+			},
+		}
+	}
+	initObjects := []runtime.Object{
+		machine("machine-1", "my-cluster", infrav1.DeletePolicyRetain,
+			infrav1.DataDisk{NameSuffix: "etcddisk", DeletePolicy: infrav1.DeletePolicyRetain},
+			infrav1.DataDisk{NameSuffix: "scratch"},
+		),
+		machine("machine-2", "my-cluster", ""),
+		machine("machine-3", "other-cluster", infrav1.DeletePolicyRetain),
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+	clusterScope := &ClusterScope{
+		Client: fakeClient,
+		AzureClients: AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Values: map[string]string{
+					auth.SubscriptionID: "123",
+				},
+			},
+		},
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+		},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+				NetworkSpec: infrav1.NetworkSpec{
+					Vnet: infrav1.VnetSpec{ResourceGroup: "vnet-rg", Name: "my-vnet", DeletePolicy: infrav1.DeletePolicyRetain},
+				},
+			},
+			Status: infrav1.AzureClusterStatus{
+				RetainedResources: []string{
+					"/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+					"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/deleted-machine_OSDisk",
+					"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/MACHINE-1_OSDisk",
+				},
+			},
+		},
+	}
+	g.Expect(clusterScope.HasRetainedResourcesInResourceGroup()).To(BeFalse())
+
+	g.Expect(clusterScope.LoadRetainedDisks(context.Background())).To(Succeed())
+	g.Expect(clusterScope.RetainedResources()).To(Equal([]string{
+		"/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+		"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/deleted-machine_OSDisk",
+		"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/MACHINE-1_OSDisk",
+		"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/machine-1_etcddisk",
+	}))
+	g.Expect(clusterScope.HasRetainedResourcesInResourceGroup()).To(BeTrue())
+}
+
 func TestFailureDomains(t *testing.T) {
 	tests := []struct {
 		name                 string
@@ -3362,3 +4269,37 @@ func TestVNetPeerings(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterScope_AvailabilityStatusFilter(t *testing.T) {
+	tests := []struct {
+		name                    string
+		controlPlaneInitialized bool
+		want                    *clusterv1.Condition
+	}{
+		{
+			name:                    "control plane not initialized",
+			controlPlaneInitialized: false,
+			want:                    nil,
+		},
+		{
+			name:                    "control plane initialized",
+			controlPlaneInitialized: true,
+			want:                    conditions.FalseCondition(infrav1.AzureResourceAvailableCondition, "Unplanned", clusterv1.ConditionSeverityError, "summary"),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cluster := &clusterv1.Cluster{}
+			if tc.controlPlaneInitialized {
+				conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+			}
+			clusterScope := &ClusterScope{
+				Cluster:      cluster,
+				AzureCluster: &infrav1.AzureCluster{},
+			}
+			cond := conditions.FalseCondition(infrav1.AzureResourceAvailableCondition, "Unplanned", clusterv1.ConditionSeverityError, "summary")
+			g.Expect(clusterScope.AvailabilityStatusFilter(cond)).To(Equal(tc.want))
+		})
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
+	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// dedicatedHostGroupZonesGetter looks up the zones of the dedicated host group of an AzureMachine with the credentials
+// of the AzureMachine's cluster.
+type dedicatedHostGroupZonesGetter struct {
+	client client.Client
+}
+
+// NewDedicatedHostGroupZonesGetter returns an infrav1.DedicatedHostGroupZonesGetter backed by the Azure API.
+func NewDedicatedHostGroupZonesGetter(c client.Client) infrav1.DedicatedHostGroupZonesGetter {
+	return &dedicatedHostGroupZonesGetter{client: c}
+}
+
+// DedicatedHostGroupZones returns the resource ID of the dedicated host group of the AzureMachine and its zones.
+func (g *dedicatedHostGroupZonesGetter) DedicatedHostGroupZones(ctx context.Context, machine *infrav1.AzureMachine) (string, []string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.dedicatedHostGroupZonesGetter.DedicatedHostGroupZones")
+	defer done()
+
+	hostGroupID := machine.Spec.DedicatedHost.HostGroupID
+	if machine.Spec.DedicatedHost.HostID != "" {
+		parsed, err := azureutil.ParseResourceID(machine.Spec.DedicatedHost.HostID)
+		if err != nil || parsed.Parent == nil {
+			return "", nil, errors.Errorf("failed to parse dedicated host ID %s", machine.Spec.DedicatedHost.HostID)
+		}
+		hostGroupID = parsed.Parent.String()
+	}
+
+	cluster, err := util.GetClusterFromMetadata(ctx, g.client, machine.ObjectMeta)
+	if err != nil {
+		return hostGroupID, nil, errors.Wrap(err, "failed to get owner cluster")
+	}
+	if cluster.Spec.InfrastructureRef == nil || cluster.Spec.InfrastructureRef.Kind != "AzureCluster" {
+		return hostGroupID, nil, errors.New("owner cluster is not backed by an AzureCluster")
+	}
+
+	azureCluster := &infrav1.AzureCluster{}
+	key := client.ObjectKey{Namespace: machine.Namespace, Name: cluster.Spec.InfrastructureRef.Name}
+	if err := g.client.Get(ctx, key, azureCluster); err != nil {
+		return hostGroupID, nil, errors.Wrap(err, "failed to get AzureCluster")
+	}
+
+	clusterScope, err := NewClusterScope(ctx, ClusterScopeParams{
+		Client:       g.client,
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+	})
+	if err != nil {
+		return hostGroupID, nil, errors.Wrap(err, "failed to create cluster scope")
+	}
+
+	hostGroup, err := virtualmachines.NewClient(clusterScope).GetDedicatedHostGroup(ctx, hostGroupID)
+	if err != nil {
+		return hostGroupID, nil, errors.Wrapf(err, "failed to get dedicated host group %s", hostGroupID)
+	}
+	if hostGroup.Zones == nil {
+		return hostGroupID, nil, nil
+	}
+	return hostGroupID, *hostGroup.Zones, nil
+}
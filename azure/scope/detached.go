@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
+
+// NewDetachedClusterScope returns a cluster scope which is backed neither by a Kubernetes client nor by Azure
+// credentials. It can only be used to build the specs of the cluster resources, e.g. to render them outside of the
+// controllers.
+func NewDetachedClusterScope(cluster *clusterv1.Cluster, azureCluster *infrav1.AzureCluster) *ClusterScope {
+	return &ClusterScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Values: map[string]string{auth.SubscriptionID: azureCluster.Spec.SubscriptionID},
+			},
+		},
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+		cache:        &ClusterCache{},
+	}
+}
+
+// NewDetachedMachineScope returns a machine scope which is backed neither by a Kubernetes client nor by Azure
+// credentials. The cache provides the data the scope otherwise looks up while reconciling.
+func NewDetachedMachineScope(clusterScope azure.ClusterScoper, machine *clusterv1.Machine, azureMachine *infrav1.AzureMachine, cache *MachineCache) *MachineScope {
+	return &MachineScope{
+		ClusterScoper: clusterScope,
+		Machine:       machine,
+		AzureMachine:  azureMachine,
+		cache:         cache,
+	}
+}
+
+// NewDetachedMachinePoolScope returns a machine pool scope which is backed neither by a Kubernetes client nor by Azure
+// credentials. The cache provides the data the scope otherwise looks up while reconciling.
+func NewDetachedMachinePoolScope(clusterScope azure.ClusterScoper, machinePool *expv1.MachinePool, azureMachinePool *infrav1exp.AzureMachinePool, cache *MachinePoolCache) *MachinePoolScope {
+	return &MachinePoolScope{
+		ClusterScoper:    clusterScope,
+		MachinePool:      machinePool,
+		AzureMachinePool: azureMachinePool,
+		cache:            cache,
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ephemeralOSDiskGetter looks up ephemeral OS disk support in the cached resource SKUs of the location of the AKS
+// cluster of an AzureManagedMachinePool.
+type ephemeralOSDiskGetter struct {
+	client client.Client
+}
+
+// NewEphemeralOSDiskGetter returns an infrav1.EphemeralOSDiskGetter backed by the resource SKU cache.
+func NewEphemeralOSDiskGetter(c client.Client) infrav1.EphemeralOSDiskGetter {
+	return &ephemeralOSDiskGetter{client: c}
+}
+
+// EphemeralOSDisk returns whether the VM size of the AzureManagedMachinePool supports ephemeral OS disks, and the
+// sizes of its cache and temp disks that can hold one.
+func (g *ephemeralOSDiskGetter) EphemeralOSDisk(ctx context.Context, pool *infrav1.AzureManagedMachinePool) (bool, int64, int64, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.ephemeralOSDiskGetter.EphemeralOSDisk")
+	defer done()
+
+	cluster, err := util.GetClusterFromMetadata(ctx, g.client, pool.ObjectMeta)
+	if err != nil {
+		return false, 0, 0, errors.Wrap(err, "failed to get owner cluster")
+	}
+	if cluster.Spec.ControlPlaneRef == nil || cluster.Spec.ControlPlaneRef.Kind != "AzureManagedControlPlane" {
+		return false, 0, 0, errors.New("owner cluster is not backed by an AzureManagedControlPlane")
+	}
+
+	controlPlane := &infrav1.AzureManagedControlPlane{}
+	key := client.ObjectKey{Namespace: pool.Namespace, Name: cluster.Spec.ControlPlaneRef.Name}
+	if err := g.client.Get(ctx, key, controlPlane); err != nil {
+		return false, 0, 0, errors.Wrap(err, "failed to get AzureManagedControlPlane")
+	}
+
+	managedControlPlaneScope, err := NewManagedControlPlaneScope(ctx, ManagedControlPlaneScopeParams{
+		Client:       g.client,
+		Cluster:      cluster,
+		ControlPlane: controlPlane,
+	})
+	if err != nil {
+		return false, 0, 0, errors.Wrap(err, "failed to create managed control plane scope")
+	}
+
+	skuCache, err := resourceskus.GetCache(managedControlPlaneScope, controlPlane.Spec.Location)
+	if err != nil {
+		return false, 0, 0, errors.Wrap(err, "failed to init resourceskus cache")
+	}
+
+	sku, err := skuCache.Get(ctx, pool.Spec.SKU, resourceskus.VirtualMachines)
+	if err != nil {
+		return false, 0, 0, errors.Wrapf(err, "failed to get SKU %s", pool.Spec.SKU)
+	}
+	if !sku.HasCapability(resourceskus.EphemeralOSDisk) {
+		return false, 0, 0, nil
+	}
+
+	var cacheDiskGB, tempDiskGB int64
+	if sku.SupportsEphemeralOSDiskPlacement(string(compute.DiffDiskPlacementCacheDisk)) {
+		if value, ok := sku.GetCapability(resourceskus.CachedDiskBytes); ok {
+			if bytes, err := strconv.ParseInt(value, 10, 64); err == nil {
+				cacheDiskGB = bytes / (1024 * 1024 * 1024)
+			}
+		}
+	}
+	if sku.SupportsEphemeralOSDiskPlacement(string(compute.DiffDiskPlacementResourceDisk)) {
+		if value, ok := sku.GetCapability(resourceskus.MaxResourceVolumeMB); ok {
+			if mb, err := strconv.ParseInt(value, 10, 64); err == nil {
+				tempDiskGB = mb / 1024
+			}
+		}
+	}
+	return true, cacheDiskGB, tempDiskGB, nil
+}
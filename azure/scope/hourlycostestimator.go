@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/retailprices"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// hourlyCostEstimator estimates the hourly cost of a VM in the location of the cluster of an AzureMachine or
+// AzureMachinePool with the Azure Retail Prices API.
+type hourlyCostEstimator struct {
+	client client.Client
+}
+
+// NewHourlyCostEstimator returns an infrav1.HourlyCostEstimator backed by the retail prices cache.
+func NewHourlyCostEstimator(c client.Client) infrav1.HourlyCostEstimator {
+	return &hourlyCostEstimator{client: c}
+}
+
+// EstimateHourlyCost returns the estimated hourly cost in USD of a VM of the size in the location of the cluster of the
+// object, including its managed OS disk.
+func (e *hourlyCostEstimator) EstimateHourlyCost(ctx context.Context, obj metav1.ObjectMeta, vmSize string, osDisk infrav1.OSDisk, spot bool) (float64, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.hourlyCostEstimator.EstimateHourlyCost")
+	defer done()
+
+	cluster, err := util.GetClusterFromMetadata(ctx, e.client, obj)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get owner cluster")
+	}
+	if cluster.Spec.InfrastructureRef == nil || cluster.Spec.InfrastructureRef.Kind != "AzureCluster" {
+		return 0, errors.New("owner cluster is not backed by an AzureCluster")
+	}
+
+	azureCluster := &infrav1.AzureCluster{}
+	key := client.ObjectKey{Namespace: obj.Namespace, Name: cluster.Spec.InfrastructureRef.Name}
+	if err := e.client.Get(ctx, key, azureCluster); err != nil {
+		return 0, errors.Wrap(err, "failed to get AzureCluster")
+	}
+
+	spec := retailprices.CostSpec{
+		Location: azureCluster.Spec.Location,
+		VMSize:   vmSize,
+		OSType:   osDisk.OSType,
+		Spot:     spot,
+	}
+	if osDisk.DiffDiskSettings == nil && osDisk.ManagedDisk != nil {
+		spec.OSDiskStorageAccountType = osDisk.ManagedDisk.StorageAccountType
+		spec.OSDiskSizeGB = ptr.Deref(osDisk.DiskSizeGB, 0)
+	}
+	return retailprices.EstimateHourlyCost(ctx, spec)
+}
@@ -18,6 +18,8 @@ package scope
 
 import (
 	"context"
+	"crypto"
+	"crypto/x509"
 	"fmt"
 	"reflect"
 	"strings"
@@ -45,6 +47,14 @@ import (
 // AzureSecretKey is the value for they client secret key.
 const AzureSecretKey = "clientSecret"
 
+const (
+	// AzureCertificateKey is the key of the client certificate in the secret of a ServicePrincipalCertificate identity.
+	// The certificate and its private key are either PEM encoded or PKCS#12 encoded.
+	AzureCertificateKey = "certificate"
+	// AzureCertificatePasswordKey is the key of the optional password of the client certificate's private key.
+	AzureCertificatePasswordKey = "password"
+)
+
 // CredentialsProvider defines the behavior for azure identity based credential providers.
 type CredentialsProvider interface {
 	GetAuthorizer(ctx context.Context, tokenCredential azcore.TokenCredential, tokenAudience string) (autorest.Authorizer, error)
@@ -165,7 +175,7 @@ func (p *AzureCredentialsProvider) GetTokenCredential(ctx context.Context, resou
 		}
 		cred, authErr = NewWorkloadIdentityCredential(azwiCredOptions)
 
-	case infrav1.ServicePrincipal, infrav1.ServicePrincipalCertificate, infrav1.UserAssignedMSI:
+	case infrav1.ServicePrincipal, infrav1.UserAssignedMSI:
 		if err := createAzureIdentityWithBindings(ctx, p.Identity, resourceManagerEndpoint, activeDirectoryEndpoint, clusterMeta, p.Client); err != nil {
 			return nil, err
 		}
@@ -175,6 +185,18 @@ func (p *AzureCredentialsProvider) GetTokenCredential(ctx context.Context, resou
 		}
 		cred, authErr = azidentity.NewManagedIdentityCredential(&options)
 
+	case infrav1.ServicePrincipalCertificate:
+		certs, key, err := p.GetClientCertificate(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get client certificate")
+		}
+
+		options := azidentity.ClientCertificateCredentialOptions{
+			ClientOptions:        newClientOptions(resourceManagerEndpoint, activeDirectoryEndpoint, tokenAudience),
+			SendCertificateChain: p.Identity.Spec.SendCertificateChain,
+		}
+		cred, authErr = azidentity.NewClientCertificateCredential(p.GetTenantID(), p.Identity.Spec.ClientID, certs, key, &options)
+
 	case infrav1.ManualServicePrincipal:
 		clientSecret, err := p.GetClientSecret(ctx)
 		if err != nil {
@@ -182,17 +204,7 @@ func (p *AzureCredentialsProvider) GetTokenCredential(ctx context.Context, resou
 		}
 
 		options := azidentity.ClientSecretCredentialOptions{
-			ClientOptions: azcore.ClientOptions{
-				Cloud: cloud.Configuration{
-					ActiveDirectoryAuthorityHost: activeDirectoryEndpoint,
-					Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
-						cloud.ResourceManager: {
-							Audience: tokenAudience,
-							Endpoint: resourceManagerEndpoint,
-						},
-					},
-				},
-			},
+			ClientOptions: newClientOptions(resourceManagerEndpoint, activeDirectoryEndpoint, tokenAudience),
 		}
 		cred, authErr = azidentity.NewClientSecretCredential(p.GetTenantID(), p.Identity.Spec.ClientID, clientSecret, &options)
 
@@ -244,6 +256,32 @@ func (p *AzureCredentialsProvider) GetClientSecret(ctx context.Context) (string,
 	return "", nil
 }
 
+// GetClientCertificate returns the client certificate chain and private key associated with the AzureCredentialsProvider's Identity.
+// The certificate may be PEM or PKCS#12 encoded, and its private key may be unencrypted, in which case the secret doesn't need a password.
+// NOTE: this only works if the Identity references a Service Principal Certificate.
+func (p *AzureCredentialsProvider) GetClientCertificate(ctx context.Context) ([]*x509.Certificate, crypto.PrivateKey, error) {
+	secretRef := p.Identity.Spec.ClientSecret
+	key := types.NamespacedName{
+		Namespace: secretRef.Namespace,
+		Name:      secretRef.Name,
+	}
+	secret := &corev1.Secret{}
+	if err := p.Client.Get(ctx, key, secret); err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to fetch ClientSecret")
+	}
+
+	var password []byte
+	if data, ok := secret.Data[AzureCertificatePasswordKey]; ok && len(data) > 0 {
+		password = data
+	}
+
+	certs, privateKey, err := azidentity.ParseCertificates(secret.Data[AzureCertificateKey], password)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to parse certificate from secret %s", key)
+	}
+	return certs, privateKey, nil
+}
+
 // GetTenantID returns the Tenant ID associated with the AzureCredentialsProvider's Identity.
 func (p *AzureCredentialsProvider) GetTenantID() string {
 	return p.Identity.Spec.TenantID
@@ -255,6 +293,21 @@ func (p *AzureCredentialsProvider) hasClientSecret() bool {
 	return p.Identity.Spec.Type == infrav1.ServicePrincipal || p.Identity.Spec.Type == infrav1.ManualServicePrincipal
 }
 
+// newClientOptions returns the azcore client options to authenticate against the given cloud endpoints.
+func newClientOptions(resourceManagerEndpoint, activeDirectoryEndpoint, tokenAudience string) azcore.ClientOptions {
+	return azcore.ClientOptions{
+		Cloud: cloud.Configuration{
+			ActiveDirectoryAuthorityHost: activeDirectoryEndpoint,
+			Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+				cloud.ResourceManager: {
+					Audience: tokenAudience,
+					Endpoint: resourceManagerEndpoint,
+				},
+			},
+		},
+	}
+}
+
 func createAzureIdentityWithBindings(ctx context.Context, azureIdentity *infrav1.AzureClusterIdentity, resourceManagerEndpoint, activeDirectoryEndpoint string, clusterMeta metav1.ObjectMeta,
 	kubeClient client.Client) error {
 	azureIdentityType, err := getAzureIdentityType(azureIdentity)
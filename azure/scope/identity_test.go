@@ -18,10 +18,18 @@ package scope
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"testing"
+	"time"
 
 	aadpodid "github.com/Azure/aad-pod-identity/pkg/apis/aadpodidentity"
 	aadpodv1 "github.com/Azure/aad-pod-identity/pkg/apis/aadpodidentity/v1"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -353,3 +361,121 @@ func TestHasClientSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestGetClientCertificate(t *testing.T) {
+	g := NewWithT(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	g.Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "capz-test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	g.Expect(err).NotTo(HaveOccurred())
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	g.Expect(err).NotTo(HaveOccurred())
+	certPEM := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})...)
+	pkcs1PEM := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})...)
+
+	tests := []struct {
+		name        string
+		secret      *corev1.Secret
+		expectedErr bool
+	}{
+		{
+			name: "password-less PEM certificate with PKCS8 key",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "sp-cert", Namespace: "default"},
+				Data: map[string][]byte{
+					AzureCertificateKey: certPEM,
+				},
+			},
+		},
+		{
+			name: "password-less PEM certificate with PKCS1 key",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "sp-cert", Namespace: "default"},
+				Data: map[string][]byte{
+					AzureCertificateKey: pkcs1PEM,
+				},
+			},
+		},
+		{
+			name: "password-less PEM certificate with empty password",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "sp-cert", Namespace: "default"},
+				Data: map[string][]byte{
+					AzureCertificateKey:         certPEM,
+					AzureCertificatePasswordKey: {},
+				},
+			},
+		},
+		{
+			name: "invalid certificate",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "sp-cert", Namespace: "default"},
+				Data: map[string][]byte{
+					AzureCertificateKey: []byte("not a certificate"),
+				},
+			},
+			expectedErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = corev1.AddToScheme(scheme)
+			p := &AzureCredentialsProvider{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tc.secret).Build(),
+				Identity: &infrav1.AzureClusterIdentity{
+					Spec: infrav1.AzureClusterIdentitySpec{
+						Type:         infrav1.ServicePrincipalCertificate,
+						ClientSecret: corev1.SecretReference{Name: "sp-cert", Namespace: "default"},
+					},
+				},
+			}
+
+			certs, privateKey, err := p.GetClientCertificate(context.TODO())
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(certs).To(HaveLen(1))
+			g.Expect(certs[0].Subject.CommonName).To(Equal("capz-test"))
+			g.Expect(privateKey).NotTo(BeNil())
+		})
+	}
+
+	t.Run("token credential with certificate chain", func(t *testing.T) {
+		g := NewWithT(t)
+		scheme := runtime.NewScheme()
+		_ = corev1.AddToScheme(scheme)
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "sp-cert", Namespace: "default"},
+			Data: map[string][]byte{
+				AzureCertificateKey: certPEM,
+			},
+		}
+		p := &AzureCredentialsProvider{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(secret).Build(),
+			Identity: &infrav1.AzureClusterIdentity{
+				Spec: infrav1.AzureClusterIdentitySpec{
+					Type:                 infrav1.ServicePrincipalCertificate,
+					TenantID:             "fake-tenant-id",
+					ClientID:             "fake-client-id",
+					ClientSecret:         corev1.SecretReference{Name: "sp-cert", Namespace: "default"},
+					SendCertificateChain: true,
+				},
+			},
+		}
+
+		cred, err := p.GetTokenCredential(context.TODO(), "https://management.azure.com/", "https://login.microsoftonline.com/", "https://management.azure.com/", metav1.ObjectMeta{})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(cred).To(BeAssignableToTypeOf(&azidentity.ClientCertificateCredential{}))
+	})
+}
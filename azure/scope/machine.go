@@ -20,27 +20,33 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"hash/fnv"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/availabilitysets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bootstrapencryption"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/disks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inboundnatrules"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/retailprices"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/runcommands"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachineimages"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensions"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
+	"sigs.k8s.io/cluster-api-provider-azure/util/slice"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
@@ -120,6 +126,14 @@ func (m *MachineScope) InitMachineCache(ctx context.Context) error {
 			return err
 		}
 
+		// The bootstrap data is only used to create the VM, so there is nothing to encrypt once it exists.
+		if m.AzureMachine.Spec.BootstrapEncryption != nil && m.ProviderID() == "" {
+			m.cache.BootstrapData, err = encryptBootstrapData(ctx, m, *m.AzureMachine.Spec.BootstrapEncryption, m.cache.BootstrapData)
+			if err != nil {
+				return err
+			}
+		}
+
 		m.cache.VMImage, err = m.GetVMImage(ctx)
 		if err != nil {
 			return err
@@ -130,11 +144,21 @@ func (m *MachineScope) InitMachineCache(ctx context.Context) error {
 			return err
 		}
 
+		if m.AzureMachine.Spec.VMSize == "" && m.AzureMachine.Spec.VMSizeClassRef != nil {
+			if err := m.resolveVMSizeClass(ctx, skuCache); err != nil {
+				return err
+			}
+		}
+
 		m.cache.VMSKU, err = skuCache.Get(ctx, m.AzureMachine.Spec.VMSize, resourceskus.VirtualMachines)
 		if err != nil {
 			return errors.Wrapf(err, "failed to get VM SKU %s in compute api", m.AzureMachine.Spec.VMSize)
 		}
 
+		if err := m.selectAvailabilityZone(ctx, skuCache); err != nil {
+			return err
+		}
+
 		m.cache.availabilitySetSKU, err = skuCache.Get(ctx, string(compute.AvailabilitySetSkuTypesAligned), resourceskus.AvailabilitySets)
 		if err != nil {
 			return errors.Wrapf(err, "failed to get availability set SKU %s in compute api", string(compute.AvailabilitySetSkuTypesAligned))
@@ -144,30 +168,132 @@ func (m *MachineScope) InitMachineCache(ctx context.Context) error {
 	return nil
 }
 
+// resolveVMSizeClass sets the VM size of the AzureMachine to the first VM size of its referenced size class
+// that is available in the machine's location and zone.
+func (m *MachineScope) resolveVMSizeClass(ctx context.Context, skuCache *resourceskus.Cache) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.resolveVMSizeClass")
+	defer done()
+
+	ref := m.AzureMachine.Spec.VMSizeClassRef
+	catalog := &infrav1.AzureVMSizeCatalog{}
+	if err := m.client.Get(ctx, client.ObjectKey{Name: ref.CatalogName}, catalog); err != nil {
+		return errors.Wrapf(err, "failed to get AzureVMSizeCatalog %s", ref.CatalogName)
+	}
+
+	sizeClass := catalog.GetSizeClass(ref.SizeClass)
+	if sizeClass == nil {
+		return azure.WithTerminalError(errors.Errorf("size class %s not found in AzureVMSizeCatalog %s", ref.SizeClass, ref.CatalogName))
+	}
+
+	zone := m.AvailabilityZone()
+	for _, size := range sizeClass.VMSizes {
+		if _, err := skuCache.Get(ctx, size, resourceskus.VirtualMachines); err != nil {
+			log.V(4).Info("VM size of size class is not available in location", "vmSize", size, "location", m.Location())
+			continue
+		}
+
+		if zone != "" {
+			zones, err := skuCache.GetZonesWithVMSize(ctx, size, m.Location())
+			if err != nil {
+				return errors.Wrapf(err, "failed to get zones for VM size %s", size)
+			}
+			if !slice.Contains(zones, zone) {
+				log.V(4).Info("VM size of size class is not available in zone", "vmSize", size, "zone", zone)
+				continue
+			}
+		}
+
+		log.V(2).Info("resolved VM size from size class", "sizeClass", ref.SizeClass, "vmSize", size)
+		m.AzureMachine.Spec.VMSize = size
+		return nil
+	}
+
+	return azure.WithTerminalError(errors.Errorf("none of the VM sizes of size class %s in AzureVMSizeCatalog %s are available in location %s", ref.SizeClass, ref.CatalogName, m.Location()))
+}
+
+// selectAvailabilityZone picks a zone for a VM that is yet to be created without a failure domain, among the
+// zones of the cluster in which the VM size is available and not restricted, and records it in the AzureMachine status.
+// The VM is left regional if no such zone exists.
+func (m *MachineScope) selectAvailabilityZone(ctx context.Context, skuCache *resourceskus.Cache) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.selectAvailabilityZone")
+	defer done()
+
+	// Zones can't be combined with availability sets, dedicated hosts or edge zones, and the zone of an existing VM can't change.
+	if m.AvailabilityZone() != "" || m.ProviderID() != "" || m.AvailabilitySetEnabled() ||
+		m.AzureMachine.Spec.DedicatedHost != nil || m.ExtendedLocation() != nil {
+		return nil
+	}
+
+	zones, err := skuCache.GetZonesWithVMSize(ctx, m.AzureMachine.Spec.VMSize, m.Location())
+	if err != nil {
+		return errors.Wrapf(err, "failed to get zones for VM size %s", m.AzureMachine.Spec.VMSize)
+	}
+
+	clusterZones := m.FailureDomains()
+	var allowed []string
+	for _, zone := range zones {
+		if slice.Contains(clusterZones, zone) {
+			allowed = append(allowed, zone)
+		}
+	}
+	if len(allowed) == 0 {
+		log.V(4).Info("no zone available for VM size, creating a regional VM", "vmSize", m.AzureMachine.Spec.VMSize, "location", m.Location())
+		return nil
+	}
+
+	// Hash the machine name so that machines are spread across zones and a retry picks the same zone.
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(m.AzureMachine.Name))
+	zone := allowed[h.Sum32()%uint32(len(allowed))]
+
+	log.V(2).Info("selected availability zone for VM", "zone", zone, "vmSize", m.AzureMachine.Spec.VMSize)
+	m.AzureMachine.Status.FailureDomain = zone
+	return nil
+}
+
 // VMSpec returns the VM spec.
 func (m *MachineScope) VMSpec() azure.ResourceSpecGetter {
+	_, resizeOSDisk := m.AzureMachine.Annotations[infrav1.ResizeOSDiskAnnotation]
+	_, resizeVM := m.AzureMachine.Annotations[infrav1.ResizeVMAnnotation]
+	_, updateDataDisks := m.AzureMachine.Annotations[infrav1.UpdateDataDisksAnnotation]
+	// The serial console log of a VM whose bootstrap failed is only retrieved once.
+	retrieveBootLog := conditions.GetReason(m.AzureMachine, infrav1.BootstrapSucceededCondition) == infrav1.FailedReason &&
+		!conditions.Has(m.AzureMachine, infrav1.BootLogCondition)
 	spec := &virtualmachines.VMSpec{
-		Name:                   m.Name(),
-		Location:               m.Location(),
-		ExtendedLocation:       m.ExtendedLocation(),
-		ResourceGroup:          m.ResourceGroup(),
-		ClusterName:            m.ClusterName(),
-		Role:                   m.Role(),
-		NICIDs:                 m.NICIDs(),
-		SSHKeyData:             m.AzureMachine.Spec.SSHPublicKey,
-		Size:                   m.AzureMachine.Spec.VMSize,
-		OSDisk:                 m.AzureMachine.Spec.OSDisk,
-		DataDisks:              m.AzureMachine.Spec.DataDisks,
-		AvailabilitySetID:      m.AvailabilitySetID(),
-		Zone:                   m.AvailabilityZone(),
-		Identity:               m.AzureMachine.Spec.Identity,
-		UserAssignedIdentities: m.AzureMachine.Spec.UserAssignedIdentities,
-		SpotVMOptions:          m.AzureMachine.Spec.SpotVMOptions,
-		SecurityProfile:        m.AzureMachine.Spec.SecurityProfile,
-		DiagnosticsProfile:     m.AzureMachine.Spec.Diagnostics,
-		AdditionalTags:         m.AdditionalTags(),
-		AdditionalCapabilities: m.AzureMachine.Spec.AdditionalCapabilities,
-		ProviderID:             m.ProviderID(),
+		Name:                         m.Name(),
+		Location:                     m.Location(),
+		ExtendedLocation:             m.ExtendedLocation(),
+		ResourceGroup:                m.ResourceGroup(),
+		SubscriptionID:               m.SubscriptionID(),
+		ClusterName:                  m.ClusterName(),
+		Role:                         m.Role(),
+		NICIDs:                       m.NICIDs(),
+		SSHKeyData:                   m.AzureMachine.Spec.SSHPublicKey,
+		AdditionalSSHKeyData:         m.AzureMachine.Spec.AdditionalSSHPublicKeys,
+		AdminUsername:                m.AzureMachine.Spec.AdminUsername,
+		Size:                         m.AzureMachine.Spec.VMSize,
+		ResizeVM:                     resizeVM,
+		OSDisk:                       m.AzureMachine.Spec.OSDisk,
+		ResizeOSDisk:                 resizeOSDisk,
+		DataDisks:                    m.AzureMachine.Spec.DataDisks,
+		SharedDiskOwnerName:          m.sharedDiskOwnerName(),
+		UpdateDataDisks:              updateDataDisks,
+		AvailabilitySetID:            m.AvailabilitySetID(),
+		Zone:                         m.AvailabilityZone(),
+		Identity:                     m.AzureMachine.Spec.Identity,
+		UserAssignedIdentities:       m.AzureMachine.Spec.UserAssignedIdentities,
+		SpotVMOptions:                m.AzureMachine.Spec.SpotVMOptions,
+		TerminateNotificationTimeout: m.AzureMachine.Spec.TerminateNotificationTimeout,
+		DedicatedHost:                m.AzureMachine.Spec.DedicatedHost,
+		SecurityProfile:              m.AzureMachine.Spec.SecurityProfile,
+		VaultSecrets:                 m.AzureMachine.Spec.VaultSecrets,
+		VMGalleryApplications:        m.AzureMachine.Spec.VMGalleryApplications,
+		DiagnosticsProfile:           m.AzureMachine.Spec.Diagnostics,
+		AdditionalTags:               m.AdditionalTags(),
+		AdditionalCapabilities:       m.AzureMachine.Spec.AdditionalCapabilities,
+		ProviderID:                   m.ProviderID(),
+		RetrieveBootLog:              retrieveBootLog,
+		MinimumVMResources:           m.AzureMachine.Spec.MinimumVMResources,
 	}
 	if m.cache != nil {
 		spec.SKU = m.cache.VMSKU
@@ -207,6 +333,11 @@ func (m *MachineScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 	return specs
 }
 
+// OutboundPublicIPNamePrefixes returns nil, machines don't have outbound load balancers.
+func (m *MachineScope) OutboundPublicIPNamePrefixes() []string {
+	return nil
+}
+
 // InboundNatSpecs returns the inbound NAT specs.
 func (m *MachineScope) InboundNatSpecs() []azure.ResourceSpecGetter {
 	// The existing inbound NAT rules are needed in order to find an available SSH port for each new inbound NAT rule.
@@ -237,6 +368,10 @@ func (m *MachineScope) NICSpecs() []azure.ResourceSpecGetter {
 	isMultiNIC := len(m.AzureMachine.Spec.NetworkInterfaces) > 1
 
 	for i := 0; i < len(m.AzureMachine.Spec.NetworkInterfaces); i++ {
+		// Existing network interfaces are attached to the VM as is, and are never created or deleted.
+		if m.AzureMachine.Spec.NetworkInterfaces[i].ID != "" {
+			continue
+		}
 		isPrimary := i == 0
 		nicName := azure.GenerateNICName(m.Name(), isMultiNIC, i)
 		nicSpecs = append(nicSpecs, m.BuildNICSpec(nicName, m.AzureMachine.Spec.NetworkInterfaces[i], isPrimary))
@@ -302,32 +437,188 @@ func (m *MachineScope) BuildNICSpec(nicName string, infrav1NetworkInterface infr
 
 // NICIDs returns the NIC resource IDs.
 func (m *MachineScope) NICIDs() []string {
-	nicspecs := m.NICSpecs()
-	nicIDs := make([]string, len(nicspecs))
-	for i, nic := range nicspecs {
-		nicIDs[i] = azure.NetworkInterfaceID(m.SubscriptionID(), nic.ResourceGroupName(), nic.ResourceName())
+	isMultiNIC := len(m.AzureMachine.Spec.NetworkInterfaces) > 1
+	nicIDs := make([]string, len(m.AzureMachine.Spec.NetworkInterfaces))
+	for i, nic := range m.AzureMachine.Spec.NetworkInterfaces {
+		if nic.ID != "" {
+			nicIDs[i] = nic.ID
+			continue
+		}
+		nicIDs[i] = azure.NetworkInterfaceID(m.SubscriptionID(), m.ResourceGroup(), azure.GenerateNICName(m.Name(), isMultiNIC, i))
 	}
 
 	return nicIDs
 }
 
-// DiskSpecs returns the disk specs.
+// DiskSpecs returns the disk specs. Disks whose delete policy is Retain and shared disks are excluded.
+// Shared disks can still be attached to other machines, so the disks service only deletes them once they aren't.
 func (m *MachineScope) DiskSpecs() []azure.ResourceSpecGetter {
-	diskSpecs := make([]azure.ResourceSpecGetter, 1+len(m.AzureMachine.Spec.DataDisks))
-	diskSpecs[0] = &disks.DiskSpec{
-		Name:          azure.GenerateOSDiskName(m.Name()),
-		ResourceGroup: m.ResourceGroup(),
+	diskSpecs := make([]azure.ResourceSpecGetter, 0, 1+len(m.AzureMachine.Spec.DataDisks))
+	if m.AzureMachine.Spec.OSDisk.DeletePolicy != infrav1.DeletePolicyRetain {
+		diskSpecs = append(diskSpecs, &disks.DiskSpec{
+			Name:          azure.GenerateOSDiskName(m.Name()),
+			ResourceGroup: m.ResourceGroup(),
+		})
 	}
 
-	for i, dd := range m.AzureMachine.Spec.DataDisks {
-		diskSpecs[i+1] = &disks.DiskSpec{
+	for _, dd := range m.AzureMachine.Spec.DataDisks {
+		if dd.IsRetained() || dd.IsShared() {
+			continue
+		}
+		if dd.IsExisting() {
+			// The managed disk ID is validated by the webhook.
+			if diskID, err := azureutil.ParseResourceID(dd.ManagedDiskID); err == nil {
+				diskSpecs = append(diskSpecs, &disks.DiskSpec{
+					Name:          diskID.Name,
+					ResourceGroup: diskID.ResourceGroupName,
+				})
+			}
+			continue
+		}
+		diskSpecs = append(diskSpecs, &disks.DiskSpec{
 			Name:          azure.GenerateDataDiskName(m.Name(), dd.NameSuffix),
 			ResourceGroup: m.ResourceGroup(),
-		}
+		})
 	}
 	return diskSpecs
 }
 
+// SharedDiskSpecs returns the specs of the shared data disks of the machine.
+// A shared disk is created by the first machine of its group that needs it and attached by the others.
+func (m *MachineScope) SharedDiskSpecs() []azure.ResourceSpecGetter {
+	var sharedDiskSpecs []azure.ResourceSpecGetter
+	for _, dd := range m.AzureMachine.Spec.DataDisks {
+		if !dd.IsShared() {
+			continue
+		}
+		spec := &disks.SharedDiskSpec{
+			Name:               azure.GenerateSharedDataDiskName(m.ClusterName(), m.sharedDiskOwnerName(), dd.NameSuffix),
+			ResourceGroup:      m.ResourceGroup(),
+			Location:           m.Location(),
+			DiskSizeGB:         dd.DiskSizeGB,
+			StorageAccountType: dd.ManagedDisk.StorageAccountType,
+			MaxShares:          *dd.ManagedDisk.MaxShares,
+			DiskIOPSReadWrite:  dd.ManagedDisk.DiskIOPSReadWrite,
+			DiskMBpsReadWrite:  dd.ManagedDisk.DiskMBpsReadWrite,
+			ClusterName:        m.ClusterName(),
+			AdditionalTags:     m.AdditionalTags(),
+			Retain:             dd.IsRetained(),
+		}
+		// Zone-redundant disks can be attached to machines in any zone, locally redundant disks only in their own zone.
+		if !strings.HasSuffix(dd.ManagedDisk.StorageAccountType, "_ZRS") {
+			spec.Zone = m.AvailabilityZone()
+		}
+		if dd.ManagedDisk.DiskEncryptionSet != nil {
+			spec.DiskEncryptionSetID = dd.ManagedDisk.DiskEncryptionSet.ID
+		}
+		sharedDiskSpecs = append(sharedDiskSpecs, spec)
+	}
+	return sharedDiskSpecs
+}
+
+// sharedDiskOwnerName returns the name of the group of machines that share the data disks of the machine: its
+// MachineDeployment, its control plane or its MachineSet. A machine that belongs to none of them doesn't share its
+// disks with other machines.
+func (m *MachineScope) sharedDiskOwnerName() string {
+	if m.Machine != nil {
+		for _, label := range []string{clusterv1.MachineDeploymentNameLabel, clusterv1.MachineControlPlaneNameLabel, clusterv1.MachineSetNameLabel} {
+			if name := m.Machine.Labels[label]; name != "" {
+				return name
+			}
+		}
+	}
+	return m.Name()
+}
+
+// DataDiskSpecs returns the specs of the data disks of the machine with provisioned IOPS or throughput.
+// The VM API can't set the performance of the disks it creates, so these disks are created first and attached to the VM.
+func (m *MachineScope) DataDiskSpecs() []azure.ResourceSpecGetter {
+	var dataDiskSpecs []azure.ResourceSpecGetter
+	for _, dd := range m.AzureMachine.Spec.DataDisks {
+		if !dd.HasProvisionedPerformance() || dd.IsShared() || dd.IsExisting() {
+			continue
+		}
+		spec := &disks.DataDiskSpec{
+			Name:               azure.GenerateDataDiskName(m.Name(), dd.NameSuffix),
+			ResourceGroup:      m.ResourceGroup(),
+			Location:           m.Location(),
+			Zone:               m.AvailabilityZone(),
+			DiskSizeGB:         dd.DiskSizeGB,
+			StorageAccountType: dd.ManagedDisk.StorageAccountType,
+			DiskIOPSReadWrite:  dd.ManagedDisk.DiskIOPSReadWrite,
+			DiskMBpsReadWrite:  dd.ManagedDisk.DiskMBpsReadWrite,
+			ClusterName:        m.ClusterName(),
+			AdditionalTags:     m.AdditionalTags(),
+		}
+		if dd.ManagedDisk.DiskEncryptionSet != nil {
+			spec.DiskEncryptionSetID = dd.ManagedDisk.DiskEncryptionSet.ID
+		}
+		dataDiskSpecs = append(dataDiskSpecs, spec)
+	}
+	return dataDiskSpecs
+}
+
+// DiskSnapshotSpecs returns the specs of the snapshots taken of the disks of the machine before they are deleted.
+func (m *MachineScope) DiskSnapshotSpecs() []azure.ResourceSpecGetter {
+	diskSnapshot := m.AzureMachine.Spec.DiskSnapshot
+	if diskSnapshot == nil {
+		return nil
+	}
+
+	// Snapshots are kept after the cluster is deleted, so they are tagged as shared with the cluster, including for the
+	// cloud provider.
+	tags := m.AdditionalTags()
+	tags[infrav1.ClusterAzureCloudProviderTagKey(m.ClusterName())] = string(infrav1.ResourceLifecycleShared)
+
+	var snapshotSpecs []azure.ResourceSpecGetter
+	for _, diskSpec := range m.DiskSpecs() {
+		// Ephemeral OS disks are not managed disks and can't be snapshotted.
+		if diskSpec.ResourceName() == azure.GenerateOSDiskName(m.Name()) && m.AzureMachine.Spec.OSDisk.DiffDiskSettings != nil {
+			continue
+		}
+		snapshotSpecs = append(snapshotSpecs, &disks.SnapshotSpec{
+			Name:            azure.GenerateSnapshotName(diskSpec.ResourceName()),
+			ResourceGroup:   diskSnapshot.ResourceGroup,
+			Location:        m.Location(),
+			SourceDiskID:    azure.DiskID(m.SubscriptionID(), diskSpec.ResourceGroupName(), diskSpec.ResourceName()),
+			ClusterName:     m.ClusterName(),
+			RetentionPeriod: diskSnapshot.RetentionPeriod,
+			AdditionalTags:  tags,
+		})
+	}
+	return snapshotSpecs
+}
+
+// RetainedResources returns the IDs of the disks of the machine that are retained when it is deleted.
+func (m *MachineScope) RetainedResources() []string {
+	return retainedDiskIDs(m.SubscriptionID(), m.ResourceGroup(), m.AzureMachine)
+}
+
+// retainedDiskIDs returns the IDs of the disks of an AzureMachine whose delete policy is Retain.
+func retainedDiskIDs(subscriptionID, resourceGroup string, azureMachine *infrav1.AzureMachine) []string {
+	var ids []string
+	name := vmName(azureMachine)
+	if azureMachine.Spec.OSDisk.DeletePolicy == infrav1.DeletePolicyRetain {
+		ids = append(ids, azure.DiskID(subscriptionID, resourceGroup, azure.GenerateOSDiskName(name)))
+	}
+	for _, dd := range azureMachine.Spec.DataDisks {
+		if !dd.IsRetained() || dd.IsShared() {
+			continue
+		}
+		if dd.IsExisting() {
+			ids = append(ids, dd.ManagedDiskID)
+			continue
+		}
+		ids = append(ids, azure.DiskID(subscriptionID, resourceGroup, azure.GenerateDataDiskName(name, dd.NameSuffix)))
+	}
+	return ids
+}
+
+// SetRetainedResources records the disks of the machine whose delete policy is Retain in the AzureMachine status.
+func (m *MachineScope) SetRetainedResources() {
+	m.AzureMachine.Status.RetainedResources = m.RetainedResources()
+}
+
 // RoleAssignmentSpecs returns the role assignment specs.
 func (m *MachineScope) RoleAssignmentSpecs(principalID *string) []azure.ResourceSpecGetter {
 	roles := make([]azure.ResourceSpecGetter, 1)
@@ -363,24 +654,38 @@ func (m *MachineScope) VMExtensionSpecs() []azure.ResourceSpecGetter {
 	for _, extension := range m.AzureMachine.Spec.VMExtensions {
 		extensionSpecs = append(extensionSpecs, &vmextensions.VMExtensionSpec{
 			ExtensionSpec: azure.ExtensionSpec{
-				Name:              extension.Name,
-				VMName:            m.Name(),
-				Publisher:         extension.Publisher,
-				Version:           extension.Version,
-				Settings:          extension.Settings,
-				ProtectedSettings: extension.ProtectedSettings,
+				Name:                    extension.Name,
+				VMName:                  m.Name(),
+				Publisher:               extension.Publisher,
+				Version:                 extension.Version,
+				Settings:                extension.Settings,
+				ProtectedSettings:       extension.ProtectedSettings,
+				AutoUpgradeMinorVersion: extension.AutoUpgradeMinorVersion,
+				EnableAutomaticUpgrade:  extension.EnableAutomaticUpgrade,
+				Custom:                  true,
 			},
 			ResourceGroup: m.ResourceGroup(),
 			Location:      m.Location(),
 		})
 	}
 
+	if m.AzureMachine.Spec.EnableGPUDriverExtension {
+		gpuDriverExtensionSpec := getGPUDriverVMExtension(m.cache.VMSKU, m.AzureMachine.Spec.OSDisk.OSType, m.Name(), m.AzureMachine.Spec.VMExtensions)
+		if gpuDriverExtensionSpec != nil {
+			extensionSpecs = append(extensionSpecs, &vmextensions.VMExtensionSpec{
+				ExtensionSpec: withAutomaticUpgrade(gpuDriverExtensionSpec, m.AzureMachine.Spec.EnableAutomaticExtensionUpgrade),
+				ResourceGroup: m.ResourceGroup(),
+				Location:      m.Location(),
+			})
+		}
+	}
+
 	cpuArchitectureType, _ := m.cache.VMSKU.GetCapability(resourceskus.CPUArchitectureType)
 	bootstrapExtensionSpec := azure.GetBootstrappingVMExtension(m.AzureMachine.Spec.OSDisk.OSType, m.CloudEnvironment(), m.Name(), cpuArchitectureType)
 
 	if bootstrapExtensionSpec != nil {
 		extensionSpecs = append(extensionSpecs, &vmextensions.VMExtensionSpec{
-			ExtensionSpec: *bootstrapExtensionSpec,
+			ExtensionSpec: withAutomaticUpgrade(bootstrapExtensionSpec, m.AzureMachine.Spec.EnableAutomaticExtensionUpgrade),
 			ResourceGroup: m.ResourceGroup(),
 			Location:      m.Location(),
 		})
@@ -389,6 +694,61 @@ func (m *MachineScope) VMExtensionSpecs() []azure.ResourceSpecGetter {
 	return extensionSpecs
 }
 
+// getGPUDriverVMExtension returns the GPU driver VM extension for the given VM SKU, or nil if the SKU has no GPUs,
+// there is no driver extension for it, or the driver extension is already part of the given extensions.
+func getGPUDriverVMExtension(sku resourceskus.SKU, osType string, vmName string, extensions []infrav1.VMExtension) *azure.ExtensionSpec {
+	if hasGPUs, _ := sku.HasCapabilityWithCapacity(resourceskus.GPUs, 1); !hasGPUs {
+		return nil
+	}
+	extensionSpec := azure.GetGPUDriverVMExtension(osType, ptr.Deref(sku.Name, ""), vmName)
+	if extensionSpec == nil {
+		return nil
+	}
+	for _, extension := range extensions {
+		if extension.Name == extensionSpec.Name {
+			return nil
+		}
+	}
+	return extensionSpec
+}
+
+// withAutomaticUpgrade returns a copy of the spec of a VM extension installed by CAPZ with its automatic upgrade
+// explicitly set, so that disabling it again is also applied to existing VMs.
+func withAutomaticUpgrade(extensionSpec *azure.ExtensionSpec, enabled bool) azure.ExtensionSpec {
+	spec := *extensionSpec
+	spec.EnableAutomaticUpgrade = ptr.To(enabled)
+	return spec
+}
+
+// RunCommandSpecs returns the run command specs.
+func (m *MachineScope) RunCommandSpecs() []azure.ResourceSpecGetter {
+	runCommandSpecs := make([]azure.ResourceSpecGetter, 0, len(m.AzureMachine.Spec.RunCommands))
+	for _, runCommand := range m.AzureMachine.Spec.RunCommands {
+		runCommandSpecs = append(runCommandSpecs, &runcommands.RunCommandSpec{
+			Name:           runCommand.Name,
+			VMName:         m.Name(),
+			ResourceGroup:  m.ResourceGroup(),
+			Location:       m.Location(),
+			Script:         runCommand.Script,
+			ScriptURI:      runCommand.ScriptURI,
+			CommandID:      runCommand.CommandID,
+			Parameters:     runCommand.Parameters,
+			TimeoutSeconds: runCommand.TimeoutSeconds,
+		})
+	}
+	return runCommandSpecs
+}
+
+// RunCommandsStatusResource returns the AzureMachine the result of the run commands is reported on.
+func (m *MachineScope) RunCommandsStatusResource() conditions.Setter {
+	return m.AzureMachine
+}
+
+// VMExtensionsStatusResource returns the AzureMachine the bootstrap stage of the machine is reported on.
+func (m *MachineScope) VMExtensionsStatusResource() conditions.Setter {
+	return m.AzureMachine
+}
+
 // Subnet returns the machine's subnet.
 func (m *MachineScope) Subnet() infrav1.SubnetSpec {
 	for _, subnet := range m.Subnets() {
@@ -404,7 +764,8 @@ func (m *MachineScope) Subnet() infrav1.SubnetSpec {
 // Priority for selecting the AZ is
 //  1. Machine.Spec.FailureDomain
 //  2. AzureMachine.Spec.FailureDomain (This is to support deprecated AZ)
-//  3. No AZ
+//  3. AzureMachine.Status.FailureDomain (The zone selected automatically for the VM)
+//  4. No AZ
 func (m *MachineScope) AvailabilityZone() string {
 	if m.Machine.Spec.FailureDomain != nil {
 		return *m.Machine.Spec.FailureDomain
@@ -413,20 +774,28 @@ func (m *MachineScope) AvailabilityZone() string {
 	if m.AzureMachine.Spec.FailureDomain != nil {
 		return *m.AzureMachine.Spec.FailureDomain
 	}
+	if m.AzureMachine.Status.FailureDomain != "" {
+		return m.AzureMachine.Status.FailureDomain
+	}
 
 	return ""
 }
 
 // Name returns the AzureMachine name.
 func (m *MachineScope) Name() string {
-	if id := m.GetVMID(); id != "" {
-		return id
+	return vmName(m.AzureMachine)
+}
+
+// vmName returns the name of the VM of an AzureMachine.
+func vmName(azureMachine *infrav1.AzureMachine) string {
+	if resourceID, err := azureutil.ParseResourceID(ptr.Deref(azureMachine.Spec.ProviderID, "")); err == nil && resourceID.Name != "" {
+		return resourceID.Name
 	}
 	// Windows Machine names cannot be longer than 15 chars
-	if m.AzureMachine.Spec.OSDisk.OSType == azure.WindowsOS && len(m.AzureMachine.Name) > 15 {
-		return strings.TrimSuffix(m.AzureMachine.Name[0:9], "-") + "-" + m.AzureMachine.Name[len(m.AzureMachine.Name)-5:]
+	if azureMachine.Spec.OSDisk.OSType == azure.WindowsOS && len(azureMachine.Name) > 15 {
+		return strings.TrimSuffix(azureMachine.Name[0:9], "-") + "-" + azureMachine.Name[len(azureMachine.Name)-5:]
 	}
-	return m.AzureMachine.Name
+	return azureMachine.Name
 }
 
 // Namespace returns the namespace name.
@@ -491,6 +860,11 @@ func (m *MachineScope) AvailabilitySet() (string, bool) {
 		return "", false
 	}
 
+	// VMs on a dedicated host get their fault domains from the host group and can't be in an availability set.
+	if m.AzureMachine != nil && m.AzureMachine.Spec.DedicatedHost != nil {
+		return "", false
+	}
+
 	if m.IsControlPlane() {
 		return azure.GenerateAvailabilitySetName(m.ClusterName(), azure.ControlPlaneNodeGroup), true
 	}
@@ -592,6 +966,16 @@ func (m *MachineScope) SetAnnotation(key, value string) {
 	m.AzureMachine.Annotations[key] = value
 }
 
+// Annotation returns the value of an AzureMachine annotation.
+func (m *MachineScope) Annotation(key string) string {
+	return m.AzureMachine.GetAnnotations()[key]
+}
+
+// RemoveAnnotation removes an annotation from the AzureMachine.
+func (m *MachineScope) RemoveAnnotation(key string) {
+	delete(m.AzureMachine.Annotations, key)
+}
+
 // AnnotationJSON returns a map[string]interface from a JSON annotation.
 func (m *MachineScope) AnnotationJSON(annotation string) (map[string]interface{}, error) {
 	out := map[string]interface{}{}
@@ -636,9 +1020,50 @@ func (m *MachineScope) PatchObject(ctx context.Context) error {
 			infrav1.VMRunningCondition,
 			infrav1.AvailabilitySetReadyCondition,
 			infrav1.NetworkInterfaceReadyCondition,
+			infrav1.AzureResourceAvailableCondition,
 		}})
 }
 
+// AvailabilityStatusResource refers to the AzureMachine.
+func (m *MachineScope) AvailabilityStatusResource() conditions.Setter {
+	return m.AzureMachine
+}
+
+// AvailabilityStatusResourceURI constructs the ID of the VM.
+func (m *MachineScope) AvailabilityStatusResourceURI() string {
+	return azure.VMID(m.SubscriptionID(), m.ResourceGroup(), m.Name())
+}
+
+// AvailabilityStatusFilter ignores the availability status of the VM until it is provisioned, since Azure doesn't
+// report a meaningful status for VMs being created.
+func (m *MachineScope) AvailabilityStatusFilter(cond *clusterv1.Condition) *clusterv1.Condition {
+	if m.VMState() != infrav1.Succeeded {
+		return nil
+	}
+	return cond
+}
+
+// EstimatedCostSpec returns the resources of the AzureMachine whose hourly cost is estimated.
+func (m *MachineScope) EstimatedCostSpec() retailprices.CostSpec {
+	spec := retailprices.CostSpec{
+		Location: m.Location(),
+		VMSize:   m.AzureMachine.Spec.VMSize,
+		OSType:   m.AzureMachine.Spec.OSDisk.OSType,
+		Spot:     m.AzureMachine.Spec.SpotVMOptions != nil,
+	}
+	osDisk := m.AzureMachine.Spec.OSDisk
+	if osDisk.DiffDiskSettings == nil && osDisk.ManagedDisk != nil {
+		spec.OSDiskStorageAccountType = osDisk.ManagedDisk.StorageAccountType
+		spec.OSDiskSizeGB = ptr.Deref(osDisk.DiskSizeGB, 0)
+	}
+	return spec
+}
+
+// EstimatedCostObject returns the AzureMachine.
+func (m *MachineScope) EstimatedCostObject() metav1.Object {
+	return m.AzureMachine
+}
+
 // Close the MachineScope by updating the machine spec, machine status.
 func (m *MachineScope) Close(ctx context.Context) error {
 	return m.PatchObject(ctx)
@@ -679,14 +1104,30 @@ func (m *MachineScope) GetBootstrapData(ctx context.Context) (string, error) {
 	return base64.StdEncoding.EncodeToString(value), nil
 }
 
+// encryptBootstrapData envelope-encrypts base64 encoded bootstrap data with a Key Vault key, and returns the custom data
+// that decrypts it on the virtual machine.
+func encryptBootstrapData(ctx context.Context, auth azure.Authorizer, encryption infrav1.BootstrapEncryption, bootstrapData string) (string, error) {
+	encrypter, err := bootstrapencryption.New(auth)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create bootstrap data encrypter")
+	}
+
+	customData, err := encrypter.Encrypt(ctx, encryption, bootstrapData)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encrypt bootstrap data")
+	}
+
+	return customData, nil
+}
+
 // GetVMImage returns the image from the machine configuration, or a default one.
 func (m *MachineScope) GetVMImage(ctx context.Context) (*infrav1.Image, error) {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.GetVMImage")
 	defer done()
 
 	// Use custom Marketplace image, Image ID or a Shared Image Gallery image if provided
-	if m.AzureMachine.Spec.Image != nil {
-		return m.AzureMachine.Spec.Image, nil
+	if image := m.AzureMachine.Spec.Image.ForLocation(m.Location()); image != nil {
+		return image, nil
 	}
 
 	svc, err := virtualmachineimages.New(m)
@@ -702,14 +1143,18 @@ func (m *MachineScope) GetVMImage(ctx context.Context) (*infrav1.Image, error) {
 	}
 
 	log.Info("No image specified for machine, using default Linux Image", "machine", m.AzureMachine.GetName())
-	return svc.GetDefaultUbuntuImage(ctx, m.Location(), ptr.Deref(m.Machine.Spec.Version, ""))
+	var variant infrav1.ImageVariant
+	if m.AzureMachine.Spec.Image != nil {
+		variant = m.AzureMachine.Spec.Image.Variant
+	}
+	return svc.GetDefaultUbuntuImage(ctx, m.Location(), ptr.Deref(m.Machine.Spec.Version, ""), variant)
 }
 
 // SetSubnetName defaults the AzureMachine subnet name to the name of one the subnets with the machine role when there is only one of them.
 // Note: this logic exists only for purposes of ensuring backwards compatibility for old clusters created without the `subnetName` field being
 // set, and should be removed in the future when this field is no longer optional.
 func (m *MachineScope) SetSubnetName() error {
-	if m.AzureMachine.Spec.NetworkInterfaces[0].SubnetName == "" {
+	if m.AzureMachine.Spec.NetworkInterfaces[0].ID == "" && m.AzureMachine.Spec.NetworkInterfaces[0].SubnetName == "" {
 		subnetName := ""
 		subnets := m.Subnets()
 		var subnetCount int
@@ -20,14 +20,17 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
 	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -37,11 +40,14 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/retailprices"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachineimages"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachineimages/mock_virtualmachineimages"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensions"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestMachineScope_Name(t *testing.T) {
@@ -588,12 +594,163 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 						ProtectedSettings: map[string]string{
 							"commandToExecute": azure.LinuxBootstrapExtensionCommand,
 						},
+						EnableAutomaticUpgrade: ptr.To(false),
 					},
 					ResourceGroup: "my-rg",
 					Location:      "westus",
 				},
 			},
 		},
+		{
+			name: "If automatic extension upgrade is enabled, it returns the bootstrap ExtensionSpec with automatic upgrade",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							OSType: "Linux",
+						},
+						EnableAutomaticExtensionUpgrade: true,
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Environment: azureautorest.Environment{
+								Name: azureautorest.PublicCloud.Name,
+							},
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+							},
+						},
+					},
+				},
+				cache: &MachineCache{
+					VMSKU: resourceskus.SKU{},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&vmextensions.VMExtensionSpec{
+					ExtensionSpec: azure.ExtensionSpec{
+						Name:      "CAPZ.Linux.Bootstrapping",
+						VMName:    "machine-name",
+						Publisher: "Microsoft.Azure.ContainerUpstream",
+						Version:   "1.0",
+						ProtectedSettings: map[string]string{
+							"commandToExecute": azure.LinuxBootstrapExtensionCommand,
+						},
+						EnableAutomaticUpgrade: ptr.To(true),
+					},
+					ResourceGroup: "my-rg",
+					Location:      "westus",
+				},
+			},
+		},
+		{
+			name: "If VM size has GPUs, it returns the GPU driver ExtensionSpec",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							OSType: "Linux",
+						},
+						EnableGPUDriverExtension: true,
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Environment: azureautorest.Environment{
+								Name: azureautorest.USGovernmentCloud.Name,
+							},
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+							},
+						},
+					},
+				},
+				cache: &MachineCache{
+					VMSKU: resourceskus.SKU{
+						Name: ptr.To("Standard_NC6s_v3"),
+						Capabilities: &[]compute.ResourceSkuCapabilities{
+							{Name: ptr.To(resourceskus.GPUs), Value: ptr.To("1")},
+						},
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&vmextensions.VMExtensionSpec{
+					ExtensionSpec: azure.ExtensionSpec{
+						Name:                   "NvidiaGpuDriverLinux",
+						VMName:                 "machine-name",
+						Publisher:              "Microsoft.HpcCompute",
+						Version:                "1.6",
+						EnableAutomaticUpgrade: ptr.To(false),
+					},
+					ResourceGroup: "my-rg",
+					Location:      "westus",
+				},
+			},
+		},
+		{
+			name: "If GPU driver extension isn't enabled, it doesn't return the GPU driver ExtensionSpec",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							OSType: "Linux",
+						},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Environment: azureautorest.Environment{
+								Name: azureautorest.USGovernmentCloud.Name,
+							},
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+							},
+						},
+					},
+				},
+				cache: &MachineCache{
+					VMSKU: resourceskus.SKU{
+						Name: ptr.To("Standard_NC6s_v3"),
+						Capabilities: &[]compute.ResourceSkuCapabilities{
+							{Name: ptr.To(resourceskus.GPUs), Value: ptr.To("1")},
+						},
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{},
+		},
 		{
 			name: "If OS type is Linux and cloud is not AzurePublicCloud, it returns empty",
 			machineScope: MachineScope{
@@ -676,6 +833,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 						ProtectedSettings: map[string]string{
 							"commandToExecute": azure.WindowsBootstrapExtensionCommand,
 						},
+						EnableAutomaticUpgrade: ptr.To(false),
 					},
 					ResourceGroup: "my-rg",
 					Location:      "westus",
@@ -816,6 +974,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 								ProtectedSettings: map[string]string{
 									"commandToExecute": "echo hello world",
 								},
+								AutoUpgradeMinorVersion: ptr.To(true),
 							},
 						},
 					},
@@ -854,6 +1013,8 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 						ProtectedSettings: map[string]string{
 							"commandToExecute": "echo hello world",
 						},
+						AutoUpgradeMinorVersion: ptr.To(true),
+						Custom:                  true,
 					},
 					ResourceGroup: "my-rg",
 					Location:      "westus",
@@ -867,6 +1028,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 						ProtectedSettings: map[string]string{
 							"commandToExecute": azure.LinuxBootstrapExtensionCommand,
 						},
+						EnableAutomaticUpgrade: ptr.To(false),
 					},
 					ResourceGroup: "my-rg",
 					Location:      "westus",
@@ -1040,6 +1202,24 @@ func TestMachineScope_AvailabilityZone(t *testing.T) {
 			},
 			want: "dummy-failure-domain-from-azuremachine-spec",
 		},
+		{
+			name: "returns failure domain from the azuremachine status",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{
+					Spec: clusterv1.MachineSpec{},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{},
+					Status: infrav1.AzureMachineStatus{
+						FailureDomain: "dummy-failure-domain-from-azuremachine-status",
+					},
+				},
+			},
+			want: "dummy-failure-domain-from-azuremachine-status",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1050,6 +1230,230 @@ func TestMachineScope_AvailabilityZone(t *testing.T) {
 	}
 }
 
+func TestMachineScope_ResolveVMSizeClass(t *testing.T) {
+	vmSKU := func(name string, zones ...string) compute.ResourceSku {
+		return compute.ResourceSku{
+			Name:         ptr.To(name),
+			ResourceType: ptr.To(string(resourceskus.VirtualMachines)),
+			LocationInfo: &[]compute.ResourceSkuLocationInfo{
+				{
+					Location: ptr.To("westeurope"),
+					Zones:    &zones,
+				},
+			},
+		}
+	}
+	skus := []compute.ResourceSku{
+		vmSKU("Standard_D4s_v5", "1"),
+		vmSKU("Standard_D4s_v4", "1", "2", "3"),
+	}
+	catalog := &infrav1.AzureVMSizeCatalog{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: infrav1.AzureVMSizeCatalogSpec{
+			SizeClasses: []infrav1.VMSizeClass{
+				{Name: "general-4cpu-16gb", VMSizes: []string{"Standard_D4s_v6", "Standard_D4s_v5", "Standard_D4s_v4"}},
+				{Name: "memory-4cpu-32gb", VMSizes: []string{"Standard_E4s_v5"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		sizeClass     string
+		failureDomain *string
+		want          string
+		wantErr       string
+	}{
+		{
+			name:      "first VM size available in location",
+			sizeClass: "general-4cpu-16gb",
+			want:      "Standard_D4s_v5",
+		},
+		{
+			name:          "first VM size available in zone",
+			sizeClass:     "general-4cpu-16gb",
+			failureDomain: ptr.To("2"),
+			want:          "Standard_D4s_v4",
+		},
+		{
+			name:      "no VM size available",
+			sizeClass: "memory-4cpu-32gb",
+			wantErr:   "none of the VM sizes of size class memory-4cpu-32gb in AzureVMSizeCatalog default are available in location westeurope",
+		},
+		{
+			name:      "unknown size class",
+			sizeClass: "gpu",
+			wantErr:   "size class gpu not found in AzureVMSizeCatalog default",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			machineScope := MachineScope{
+				client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(catalog).Build(),
+				Machine: &clusterv1.Machine{
+					Spec: clusterv1.MachineSpec{FailureDomain: tt.failureDomain},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					Spec: infrav1.AzureMachineSpec{
+						VMSizeClassRef: &infrav1.VMSizeClassReference{CatalogName: "default", SizeClass: tt.sizeClass},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westeurope",
+							},
+						},
+					},
+				},
+			}
+
+			err := machineScope.resolveVMSizeClass(context.Background(), resourceskus.NewStaticCache(skus, "westeurope"))
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(machineScope.AzureMachine.Spec.VMSize).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachineScope_SelectAvailabilityZone(t *testing.T) {
+	skus := []compute.ResourceSku{
+		{
+			Name:         ptr.To("Standard_D4s_v5"),
+			ResourceType: ptr.To(string(resourceskus.VirtualMachines)),
+			LocationInfo: &[]compute.ResourceSkuLocationInfo{
+				{
+					Location: ptr.To("westeurope"),
+					Zones:    &[]string{"1", "2", "3"},
+				},
+			},
+			Restrictions: &[]compute.ResourceSkuRestrictions{
+				{
+					Type: compute.ResourceSkuRestrictionsTypeZone,
+					RestrictionInfo: &compute.ResourceSkuRestrictionInfo{
+						Zones: &[]string{"1", "3"},
+					},
+				},
+			},
+		},
+		{
+			Name:         ptr.To("Standard_D4s_v4"),
+			ResourceType: ptr.To(string(resourceskus.VirtualMachines)),
+			LocationInfo: &[]compute.ResourceSkuLocationInfo{
+				{
+					Location: ptr.To("westeurope"),
+					Zones:    &[]string{"1", "2", "3"},
+				},
+			},
+		},
+		{
+			Name:         ptr.To("Standard_B2s"),
+			ResourceType: ptr.To(string(resourceskus.VirtualMachines)),
+			LocationInfo: &[]compute.ResourceSkuLocationInfo{
+				{
+					Location: ptr.To("westeurope"),
+					Zones:    &[]string{},
+				},
+			},
+		},
+	}
+	allZones := clusterv1.FailureDomains{"1": {}, "2": {}, "3": {}}
+
+	tests := []struct {
+		name           string
+		vmSize         string
+		failureDomains clusterv1.FailureDomains
+		failureDomain  *string
+		providerID     *string
+		want           []string
+	}{
+		{
+			name:           "selects the only zone that is not restricted",
+			vmSize:         "Standard_D4s_v5",
+			failureDomains: allZones,
+			want:           []string{"2"},
+		},
+		{
+			name:           "selects one of the zones of the cluster",
+			vmSize:         "Standard_D4s_v4",
+			failureDomains: clusterv1.FailureDomains{"1": {}, "3": {}},
+			want:           []string{"1", "3"},
+		},
+		{
+			name:           "creates a regional VM if the VM size is not available in any zone",
+			vmSize:         "Standard_B2s",
+			failureDomains: allZones,
+		},
+		{
+			name:           "creates a regional VM if the only available zone is not a zone of the cluster",
+			vmSize:         "Standard_D4s_v5",
+			failureDomains: clusterv1.FailureDomains{"1": {}, "3": {}},
+		},
+		{
+			name:   "does not select a zone if the cluster uses availability sets",
+			vmSize: "Standard_D4s_v4",
+		},
+		{
+			name:           "does not override the failure domain of the machine",
+			vmSize:         "Standard_D4s_v4",
+			failureDomains: allZones,
+			failureDomain:  ptr.To("1"),
+		},
+		{
+			name:           "does not select a zone for an existing VM",
+			vmSize:         "Standard_D4s_v4",
+			failureDomains: allZones,
+			providerID:     ptr.To("azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/machine-name"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				Machine: &clusterv1.Machine{
+					Spec: clusterv1.MachineSpec{FailureDomain: tt.failureDomain},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						VMSize:     tt.vmSize,
+						ProviderID: tt.providerID,
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westeurope",
+							},
+						},
+						Status: infrav1.AzureClusterStatus{
+							FailureDomains: tt.failureDomains,
+						},
+					},
+				},
+			}
+
+			err := machineScope.selectAvailabilityZone(context.Background(), resourceskus.NewStaticCache(skus, "westeurope"))
+			g.Expect(err).NotTo(HaveOccurred())
+			if tt.want == nil {
+				g.Expect(machineScope.AzureMachine.Status.FailureDomain).To(BeEmpty())
+				return
+			}
+			g.Expect(machineScope.AzureMachine.Status.FailureDomain).To(BeElementOf(tt.want))
+		})
+	}
+}
+
 func TestMachineScope_Namespace(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -1236,7 +1640,7 @@ func TestMachineScope_AvailabilitySet(t *testing.T) {
 			wantAvailabilitySetExistence: true,
 		},
 		{
-			name: "returns AvailabilitySet name and true if AvailabilitySet is enabled for worker machine which is part of machine deployment",
+			name: "returns empty and false if the machine is placed on a dedicated host",
 			machineScope: MachineScope{
 
 				ClusterScoper: &ClusterScope{
@@ -1252,7 +1656,39 @@ func TestMachineScope_AvailabilitySet(t *testing.T) {
 				Machine: &clusterv1.Machine{
 					ObjectMeta: metav1.ObjectMeta{
 						Labels: map[string]string{
-							clusterv1.MachineDeploymentNameLabel: "foo-machine-deployment",
+							clusterv1.MachineControlPlaneLabel: "",
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					Spec: infrav1.AzureMachineSpec{
+						DedicatedHost: &infrav1.DedicatedHost{
+							HostGroupID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/hostGroups/my-host-group",
+						},
+					},
+				},
+			},
+			wantAvailabilitySetName:      "",
+			wantAvailabilitySetExistence: false,
+		},
+		{
+			name: "returns AvailabilitySet name and true if AvailabilitySet is enabled for worker machine which is part of machine deployment",
+			machineScope: MachineScope{
+
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cluster",
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Status: infrav1.AzureClusterStatus{},
+					},
+				},
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							clusterv1.MachineDeploymentNameLabel: "foo-machine-deployment",
 						},
 					},
 				},
@@ -1424,12 +1860,45 @@ func TestMachineScope_GetVMImage(t *testing.T) {
 						},
 					},
 				},
+				ClusterScoper: clusterMock,
 			},
 			want: &infrav1.Image{
 				ID: ptr.To("1"),
 			},
 			expectedErr: "",
 		},
+		{
+			name: "returns the location override of the AzureMachine image matching the location",
+			machineScope: MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						Image: &infrav1.Image{
+							ID: ptr.To("1"),
+							LocationOverrides: []infrav1.ImageLocationOverride{
+								{Location: "eastus", ID: ptr.To("2")},
+								{Location: "westeurope", ID: ptr.To("3")},
+							},
+						},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westeurope",
+							},
+						},
+					},
+				},
+			},
+			want: &infrav1.Image{
+				ID: ptr.To("3"),
+			},
+			expectedErr: "",
+		},
 		{
 			name: "if no image is specified and os specified is windows with version below 1.22, returns windows dockershim image",
 			machineScope: MachineScope{
@@ -1664,7 +2133,7 @@ func TestMachineScope_GetVMImage(t *testing.T) {
 				ClusterScoper: clusterMock,
 			},
 			want: func() *infrav1.Image {
-				image, _ := svc.GetDefaultUbuntuImage(context.TODO(), "", "1.20.1")
+				image, _ := svc.GetDefaultUbuntuImage(context.TODO(), "", "1.20.1", "")
 				return image
 			}(),
 			expectedErr: "",
@@ -2859,6 +3328,73 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 	}
 }
 
+func TestMachineScope_NICIDsWithExistingNetworkInterface(t *testing.T) {
+	g := NewWithT(t)
+
+	existingNICID := "/subscriptions/123/resourceGroups/other-rg/providers/Microsoft.Network/networkInterfaces/existing-nic"
+	machineScope := MachineScope{
+		ClusterScoper: &ClusterScope{
+			AzureClients: AzureClients{
+				EnvironmentSettings: auth.EnvironmentSettings{
+					Values: map[string]string{
+						auth.SubscriptionID: "123",
+					},
+				},
+			},
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cluster",
+					Namespace: "default",
+				},
+			},
+			AzureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						Location: "westus",
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						Vnet: infrav1.VnetSpec{
+							Name:          "vnet1",
+							ResourceGroup: "rg1",
+						},
+					},
+				},
+			},
+		},
+		AzureMachine: &infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "machine",
+			},
+			Spec: infrav1.AzureMachineSpec{
+				NetworkInterfaces: []infrav1.NetworkInterface{
+					{
+						ID: existingNICID,
+					},
+					{
+						SubnetName:       "subnet2",
+						PrivateIPConfigs: 1,
+					},
+				},
+			},
+		},
+		Machine: &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "machine",
+			},
+		},
+	}
+
+	nicSpecs := machineScope.NICSpecs()
+	g.Expect(nicSpecs).To(HaveLen(1))
+	g.Expect(nicSpecs[0].ResourceName()).To(Equal("machine-nic-1"))
+
+	g.Expect(machineScope.NICIDs()).To(Equal([]string{
+		existingNICID,
+		"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/machine-nic-1",
+	}))
+}
+
 func TestDiskSpecs(t *testing.T) {
 	testcases := []struct {
 		name         string
@@ -3014,6 +3550,110 @@ func TestDiskSpecs(t *testing.T) {
 					ResourceGroup: "my-rg",
 				},
 			},
+		}, {
+			name: "disks with Retain delete policy are excluded",
+			machineScope: MachineScope{
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cluster",
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cluster",
+						},
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-azure-machine",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							DiskSizeGB:   ptr.To[int32](30),
+							OSType:       "Linux",
+							DeletePolicy: infrav1.DeletePolicyRetain,
+						},
+						DataDisks: []infrav1.DataDisk{
+							{
+								NameSuffix:   "etcddisk",
+								DeletePolicy: infrav1.DeletePolicyRetain,
+							},
+							{
+								NameSuffix: "otherdisk",
+							},
+						},
+					},
+				},
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine",
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&disks.DiskSpec{
+					Name:          "my-azure-machine_otherdisk",
+					ResourceGroup: "my-rg",
+				},
+			},
+		}, {
+			name: "existing disks are only included with Delete delete policy",
+			machineScope: MachineScope{
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cluster",
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cluster",
+						},
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-azure-machine",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							DiskSizeGB:   ptr.To[int32](30),
+							OSType:       "Linux",
+							DeletePolicy: infrav1.DeletePolicyRetain,
+						},
+						DataDisks: []infrav1.DataDisk{
+							{
+								NameSuffix:    "etcddisk",
+								ManagedDiskID: "/subscriptions/123/resourceGroups/restore-rg/providers/Microsoft.Compute/disks/restored-etcddisk",
+							},
+							{
+								NameSuffix:    "otherdisk",
+								ManagedDiskID: "/subscriptions/123/resourceGroups/restore-rg/providers/Microsoft.Compute/disks/restored-otherdisk",
+								DeletePolicy:  infrav1.DeletePolicyDelete,
+							},
+						},
+					},
+				},
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine",
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&disks.DiskSpec{
+					Name:          "restored-otherdisk",
+					ResourceGroup: "restore-rg",
+				},
+			},
 		},
 	}
 
@@ -3028,3 +3668,441 @@ func TestDiskSpecs(t *testing.T) {
 		})
 	}
 }
+
+func TestDiskSnapshotSpecs(t *testing.T) {
+	newMachineScope := func(diskSnapshot *infrav1.DiskSnapshot, osDisk infrav1.OSDisk) MachineScope {
+		return MachineScope{
+			ClusterScoper: &ClusterScope{
+				AzureClients: AzureClients{
+					EnvironmentSettings: auth.EnvironmentSettings{
+						Values: map[string]string{
+							auth.SubscriptionID: "123",
+						},
+					},
+				},
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cluster",
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cluster",
+					},
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+							Location: "westus",
+						},
+					},
+				},
+			},
+			AzureMachine: &infrav1.AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-azure-machine",
+				},
+				Spec: infrav1.AzureMachineSpec{
+					OSDisk: osDisk,
+					DataDisks: []infrav1.DataDisk{
+						{
+							NameSuffix: "etcddisk",
+						},
+					},
+					DiskSnapshot: diskSnapshot,
+				},
+			},
+		}
+	}
+
+	testcases := []struct {
+		name         string
+		machineScope MachineScope
+		want         []azure.ResourceSpecGetter
+	}{
+		{
+			name:         "disk snapshots disabled",
+			machineScope: newMachineScope(nil, infrav1.OSDisk{OSType: "Linux"}),
+			want:         nil,
+		},
+		{
+			name: "os and data disks snapshotted",
+			machineScope: newMachineScope(&infrav1.DiskSnapshot{
+				ResourceGroup:   "my-snapshots",
+				RetentionPeriod: &metav1.Duration{Duration: time.Hour},
+			}, infrav1.OSDisk{OSType: "Linux"}),
+			want: []azure.ResourceSpecGetter{
+				&disks.SnapshotSpec{
+					Name:            "my-azure-machine_OSDisk_snapshot",
+					ResourceGroup:   "my-snapshots",
+					Location:        "westus",
+					SourceDiskID:    "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-azure-machine_OSDisk",
+					ClusterName:     "cluster",
+					RetentionPeriod: &metav1.Duration{Duration: time.Hour},
+					AdditionalTags:  infrav1.Tags{"kubernetes.io_cluster_cluster": "shared"},
+				},
+				&disks.SnapshotSpec{
+					Name:            "my-azure-machine_etcddisk_snapshot",
+					ResourceGroup:   "my-snapshots",
+					Location:        "westus",
+					SourceDiskID:    "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-azure-machine_etcddisk",
+					ClusterName:     "cluster",
+					RetentionPeriod: &metav1.Duration{Duration: time.Hour},
+					AdditionalTags:  infrav1.Tags{"kubernetes.io_cluster_cluster": "shared"},
+				},
+			},
+		},
+		{
+			name: "ephemeral os disk is not snapshotted",
+			machineScope: newMachineScope(&infrav1.DiskSnapshot{ResourceGroup: "my-snapshots"}, infrav1.OSDisk{
+				OSType:           "Linux",
+				DiffDiskSettings: &infrav1.DiffDiskSettings{Option: "Local"},
+			}),
+			want: []azure.ResourceSpecGetter{
+				&disks.SnapshotSpec{
+					Name:           "my-azure-machine_etcddisk_snapshot",
+					ResourceGroup:  "my-snapshots",
+					Location:       "westus",
+					SourceDiskID:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-azure-machine_etcddisk",
+					ClusterName:    "cluster",
+					AdditionalTags: infrav1.Tags{"kubernetes.io_cluster_cluster": "shared"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range testcases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			result := tt.machineScope.DiskSnapshotSpecs()
+			g.Expect(result).To(BeEquivalentTo(tt.want))
+		})
+	}
+}
+
+func TestSharedDiskSpecs(t *testing.T) {
+	g := NewWithT(t)
+
+	machineScope := MachineScope{
+		ClusterScoper: &ClusterScope{
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster",
+				},
+			},
+			AzureCluster: &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster",
+				},
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						Location: "westus",
+					},
+				},
+			},
+		},
+		AzureMachine: &infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-azure-machine",
+			},
+			Spec: infrav1.AzureMachineSpec{
+				OSDisk: infrav1.OSDisk{
+					OSType: "Linux",
+				},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "etcddisk",
+					},
+					{
+						NameSuffix: "quorum",
+						DiskSizeGB: 256,
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Premium_LRS",
+							MaxShares:          ptr.To[int32](2),
+							DiskEncryptionSet: &infrav1.DiskEncryptionSetParameters{
+								ID: "my-des-id",
+							},
+						},
+					},
+					{
+						NameSuffix:   "data",
+						DiskSizeGB:   512,
+						DeletePolicy: infrav1.DeletePolicyRetain,
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Premium_ZRS",
+							MaxShares:          ptr.To[int32](3),
+						},
+					},
+				},
+			},
+		},
+		Machine: &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "machine",
+				Labels: map[string]string{
+					clusterv1.MachineSetNameLabel:        "md-0-abcde",
+					clusterv1.MachineDeploymentNameLabel: "md-0",
+				},
+			},
+			Spec: clusterv1.MachineSpec{
+				FailureDomain: ptr.To("2"),
+			},
+		},
+	}
+
+	g.Expect(machineScope.SharedDiskSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&disks.SharedDiskSpec{
+			Name:                "cluster_md-0_quorum",
+			ResourceGroup:       "my-rg",
+			Location:            "westus",
+			Zone:                "2",
+			DiskSizeGB:          256,
+			StorageAccountType:  "Premium_LRS",
+			MaxShares:           2,
+			DiskEncryptionSetID: "my-des-id",
+			ClusterName:         "cluster",
+			AdditionalTags:      infrav1.Tags{"kubernetes.io_cluster_cluster": "owned"},
+		},
+		&disks.SharedDiskSpec{
+			Name:               "cluster_md-0_data",
+			ResourceGroup:      "my-rg",
+			Location:           "westus",
+			DiskSizeGB:         512,
+			StorageAccountType: "Premium_ZRS",
+			MaxShares:          3,
+			ClusterName:        "cluster",
+			AdditionalTags:     infrav1.Tags{"kubernetes.io_cluster_cluster": "owned"},
+			Retain:             true,
+		},
+	}))
+
+	// Shared disks are deleted by the disks service once no machine uses them.
+	g.Expect(machineScope.DiskSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&disks.DiskSpec{
+			Name:          "my-azure-machine_OSDisk",
+			ResourceGroup: "my-rg",
+		},
+		&disks.DiskSpec{
+			Name:          "my-azure-machine_etcddisk",
+			ResourceGroup: "my-rg",
+		},
+	}))
+}
+
+func TestDataDiskSpecs(t *testing.T) {
+	g := NewWithT(t)
+
+	machineScope := MachineScope{
+		ClusterScoper: &ClusterScope{
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster",
+				},
+			},
+			AzureCluster: &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster",
+				},
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						Location: "westus",
+					},
+				},
+			},
+		},
+		AzureMachine: &infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-azure-machine",
+			},
+			Spec: infrav1.AzureMachineSpec{
+				OSDisk: infrav1.OSDisk{
+					OSType: "Linux",
+				},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "logs",
+						DiskSizeGB: 128,
+					},
+					{
+						NameSuffix: "etcddisk",
+						DiskSizeGB: 256,
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "UltraSSD_LRS",
+							DiskIOPSReadWrite:  ptr.To[int64](20000),
+							DiskMBpsReadWrite:  ptr.To[int64](500),
+							DiskEncryptionSet: &infrav1.DiskEncryptionSetParameters{
+								ID: "my-des-id",
+							},
+						},
+					},
+					{
+						NameSuffix: "quorum",
+						DiskSizeGB: 256,
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "UltraSSD_LRS",
+							MaxShares:          ptr.To[int32](2),
+							DiskIOPSReadWrite:  ptr.To[int64](10000),
+						},
+					},
+				},
+			},
+		},
+		Machine: &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "machine",
+			},
+			Spec: clusterv1.MachineSpec{
+				FailureDomain: ptr.To("2"),
+			},
+		},
+	}
+
+	// Shared disks with provisioned performance are created as shared disks.
+	g.Expect(machineScope.DataDiskSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&disks.DataDiskSpec{
+			Name:                "my-azure-machine_etcddisk",
+			ResourceGroup:       "my-rg",
+			Location:            "westus",
+			Zone:                "2",
+			DiskSizeGB:          256,
+			StorageAccountType:  "UltraSSD_LRS",
+			DiskIOPSReadWrite:   ptr.To[int64](20000),
+			DiskMBpsReadWrite:   ptr.To[int64](500),
+			DiskEncryptionSetID: "my-des-id",
+			ClusterName:         "cluster",
+			AdditionalTags:      infrav1.Tags{"kubernetes.io_cluster_cluster": "owned"},
+		},
+	}))
+
+	// Data disks with provisioned performance are deleted along with the machine.
+	g.Expect(machineScope.DiskSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&disks.DiskSpec{
+			Name:          "my-azure-machine_OSDisk",
+			ResourceGroup: "my-rg",
+		},
+		&disks.DiskSpec{
+			Name:          "my-azure-machine_logs",
+			ResourceGroup: "my-rg",
+		},
+		&disks.DiskSpec{
+			Name:          "my-azure-machine_etcddisk",
+			ResourceGroup: "my-rg",
+		},
+	}))
+}
+
+func TestMachineScope_AvailabilityStatusFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		vmState *infrav1.ProvisioningState
+		want    *clusterv1.Condition
+	}{
+		{
+			name:    "VM not created yet",
+			vmState: nil,
+			want:    nil,
+		},
+		{
+			name:    "VM being created",
+			vmState: ptr.To(infrav1.Creating),
+			want:    nil,
+		},
+		{
+			name:    "VM provisioned",
+			vmState: ptr.To(infrav1.Succeeded),
+			want:    conditions.FalseCondition(infrav1.AzureResourceAvailableCondition, "PlatformInitiated", clusterv1.ConditionSeverityError, "summary"),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := &MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					Status: infrav1.AzureMachineStatus{
+						VMState: tc.vmState,
+					},
+				},
+			}
+			cond := conditions.FalseCondition(infrav1.AzureResourceAvailableCondition, "PlatformInitiated", clusterv1.ConditionSeverityError, "summary")
+			g.Expect(machineScope.AvailabilityStatusFilter(cond)).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestMachineScope_EstimatedCostSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		machine *infrav1.AzureMachine
+		want    retailprices.CostSpec
+	}{
+		{
+			name: "managed OS disk",
+			machine: &infrav1.AzureMachine{
+				Spec: infrav1.AzureMachineSpec{
+					VMSize: "Standard_D2s_v3",
+					OSDisk: infrav1.OSDisk{
+						OSType:     azure.LinuxOS,
+						DiskSizeGB: ptr.To[int32](128),
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Premium_LRS",
+						},
+					},
+				},
+			},
+			want: retailprices.CostSpec{
+				Location:                 "westus",
+				VMSize:                   "Standard_D2s_v3",
+				OSType:                   azure.LinuxOS,
+				OSDiskStorageAccountType: "Premium_LRS",
+				OSDiskSizeGB:             128,
+			},
+		},
+		{
+			name: "spot VM with an ephemeral OS disk",
+			machine: &infrav1.AzureMachine{
+				Spec: infrav1.AzureMachineSpec{
+					VMSize:        "Standard_D2s_v3",
+					SpotVMOptions: &infrav1.SpotVMOptions{},
+					OSDisk: infrav1.OSDisk{
+						OSType:     azure.WindowsOS,
+						DiskSizeGB: ptr.To[int32](128),
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Standard_LRS",
+						},
+						DiffDiskSettings: &infrav1.DiffDiskSettings{
+							Option: "Local",
+						},
+					},
+				},
+			},
+			want: retailprices.CostSpec{
+				Location: "westus",
+				VMSize:   "Standard_D2s_v3",
+				OSType:   azure.WindowsOS,
+				Spot:     true,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := &MachineScope{
+				ClusterScoper: &ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+							},
+						},
+					},
+				},
+				AzureMachine: tc.machine,
+			}
+			g.Expect(machineScope.EstimatedCostSpec()).To(Equal(tc.want))
+		})
+	}
+}
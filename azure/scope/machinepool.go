@@ -20,10 +20,13 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,9 +35,13 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	machinepool "sigs.k8s.io/cluster-api-provider-azure/azure/scope/strategies/machinepool_deployments"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/reservations"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/retailprices"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/standbypools"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/storageaccounts"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachineimages"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
@@ -85,11 +92,13 @@ type (
 
 	// MachinePoolCache stores common machine pool information so we don't have to hit the API multiple times within the same reconcile loop.
 	MachinePoolCache struct {
-		BootstrapData           string
-		HasBootstrapDataChanges bool
-		VMImage                 *infrav1.Image
-		VMSKU                   resourceskus.SKU
-		MaxSurge                int
+		BootstrapData                 string
+		CustomData                    string
+		HasBootstrapDataChanges       bool
+		HasBootstrapDataSecretChanges bool
+		VMImage                       *infrav1.Image
+		VMSKU                         resourceskus.SKU
+		MaxSurge                      int
 	}
 )
 
@@ -147,6 +156,18 @@ func (m *MachinePoolScope) InitMachinePoolCache(ctx context.Context) error {
 			return err
 		}
 
+		m.cache.HasBootstrapDataSecretChanges = m.HasBootstrapDataSecretChanges()
+
+		// The custom data is encrypted on every reconcile since the scale set model may be updated at any time, but
+		// changes are detected on the bootstrap data itself as every encryption uses a new key.
+		m.cache.CustomData = m.cache.BootstrapData
+		if m.AzureMachinePool.Spec.Template.BootstrapEncryption != nil {
+			m.cache.CustomData, err = encryptBootstrapData(ctx, m, *m.AzureMachinePool.Spec.Template.BootstrapEncryption, m.cache.BootstrapData)
+			if err != nil {
+				return err
+			}
+		}
+
 		m.cache.VMImage, err = m.GetVMImage(ctx)
 		if err != nil {
 			return err
@@ -177,32 +198,46 @@ func (m *MachinePoolScope) ScaleSetSpec(ctx context.Context) azure.ResourceSpecG
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.ScaleSetSpec")
 	defer done()
 
-	shouldPatchCustomData := false
+	// A new bootstrap data secret means the bootstrap config (e.g. kubelet node labels, taints or containerd
+	// config, for both Linux and Windows pools) was replaced, so the scale set model has to pick it up.
+	shouldPatchCustomData := m.cache.HasBootstrapDataSecretChanges
 	if m.HasReplicasExternallyManaged(ctx) {
-		shouldPatchCustomData = m.cache.HasBootstrapDataChanges
-		log.V(4).Info("has bootstrap data changed?", "shouldPatchCustomData", shouldPatchCustomData)
+		// Instances added by an external autoscaler are created from the scale set model directly,
+		// so the model also has to follow in-place changes of the bootstrap data such as token rotation.
+		shouldPatchCustomData = shouldPatchCustomData || m.cache.HasBootstrapDataChanges
 	}
+	log.V(4).Info("has bootstrap data changed?", "shouldPatchCustomData", shouldPatchCustomData)
 
-	return &scalesets.ScaleSetSpec{
+	spec := &scalesets.ScaleSetSpec{
 		Name:                         m.Name(),
+		ComputerNamePrefix:           m.AzureMachinePool.Spec.Template.ComputerNamePrefix,
 		ResourceGroup:                m.ResourceGroup(),
 		Size:                         m.AzureMachinePool.Spec.Template.VMSize,
 		Capacity:                     int64(ptr.Deref[int32](m.MachinePool.Spec.Replicas, 0)),
 		SSHKeyData:                   m.AzureMachinePool.Spec.Template.SSHPublicKey,
+		AdditionalSSHKeyData:         m.AzureMachinePool.Spec.Template.AdditionalSSHPublicKeys,
+		AdminUsername:                m.AzureMachinePool.Spec.Template.AdminUsername,
 		OSDisk:                       m.AzureMachinePool.Spec.Template.OSDisk,
 		DataDisks:                    m.AzureMachinePool.Spec.Template.DataDisks,
 		SubnetName:                   m.AzureMachinePool.Spec.Template.NetworkInterfaces[0].SubnetName,
 		VNetName:                     m.Vnet().Name,
 		VNetResourceGroup:            m.Vnet().ResourceGroup,
-		PublicLBName:                 m.OutboundLBName(infrav1.Node),
-		PublicLBAddressPoolName:      m.OutboundPoolName(infrav1.Node),
+		PublicLBName:                 m.outboundLBName(),
+		PublicLBAddressPoolName:      m.outboundPoolName(),
 		AcceleratedNetworking:        m.AzureMachinePool.Spec.Template.NetworkInterfaces[0].AcceleratedNetworking,
 		Identity:                     m.AzureMachinePool.Spec.Identity,
 		UserAssignedIdentities:       m.AzureMachinePool.Spec.UserAssignedIdentities,
-		DiagnosticsProfile:           m.AzureMachinePool.Spec.Template.Diagnostics,
+		DiagnosticsProfile:           m.diagnosticsProfile(),
 		SecurityProfile:              m.AzureMachinePool.Spec.Template.SecurityProfile,
 		SpotVMOptions:                m.AzureMachinePool.Spec.Template.SpotVMOptions,
+		VaultSecrets:                 m.AzureMachinePool.Spec.Template.VaultSecrets,
+		VMGalleryApplications:        m.AzureMachinePool.Spec.Template.VMGalleryApplications,
 		FailureDomains:               m.MachinePool.Spec.FailureDomains,
+		ZoneBalance:                  m.zoneBalance(),
+		PlatformFaultDomainCount:     m.platformFaultDomainCount(),
+		SinglePlacementGroup:         m.singlePlacementGroup(),
+		CapacityReservationGroupID:   m.capacityReservationGroupID(),
+		PinnedInstances:              m.pinnedInstances(),
 		TerminateNotificationTimeout: m.AzureMachinePool.Spec.Template.TerminateNotificationTimeout,
 		NetworkInterfaces:            m.AzureMachinePool.Spec.Template.NetworkInterfaces,
 		IPv6Enabled:                  m.IsIPv6Enabled(),
@@ -214,17 +249,141 @@ func (m *MachinePoolScope) ScaleSetSpec(ctx context.Context) azure.ResourceSpecG
 		ClusterName:                  m.ClusterName(),
 		AdditionalTags:               m.AzureMachinePool.Spec.AdditionalTags,
 		SKU:                          m.cache.VMSKU,
+		MinimumVMResources:           m.AzureMachinePool.Spec.Template.MinimumVMResources,
 		VMImage:                      m.cache.VMImage,
-		BootstrapData:                m.cache.BootstrapData,
+		BootstrapData:                m.cache.CustomData,
 		ShouldPatchCustomData:        shouldPatchCustomData,
 		MaxSurge:                     m.cache.MaxSurge,
 	}
+
+	if upgradePolicy := m.AzureMachinePool.Spec.UpgradePolicy; upgradePolicy != nil {
+		spec.UpgradeMode = string(upgradePolicy.Mode)
+		spec.EnableAutomaticOSUpgrade = upgradePolicy.EnableAutomaticOSUpgrade
+		if upgradePolicy.RollingUpgrade != nil {
+			spec.MaxBatchInstancePercent = upgradePolicy.RollingUpgrade.MaxBatchInstancePercent
+			if upgradePolicy.RollingUpgrade.PauseTime != nil {
+				spec.PauseTimeBetweenBatches = &upgradePolicy.RollingUpgrade.PauseTime.Duration
+			}
+		}
+	}
+
+	if automaticRepairs := m.AzureMachinePool.Spec.AutomaticRepairs; automaticRepairs != nil && automaticRepairs.Enabled {
+		spec.AutomaticRepairsEnabled = true
+		if automaticRepairs.GracePeriod != nil {
+			spec.AutomaticRepairsGracePeriod = &automaticRepairs.GracePeriod.Duration
+		}
+	}
+
+	if spotVMOptions := m.AzureMachinePool.Spec.Template.SpotVMOptions; spotVMOptions != nil && spotVMOptions.RestorePolicy != nil && spotVMOptions.RestorePolicy.Enabled {
+		spec.SpotRestoreEnabled = true
+		if spotVMOptions.RestorePolicy.RestoreTimeout != nil {
+			spec.SpotRestoreTimeout = &spotVMOptions.RestorePolicy.RestoreTimeout.Duration
+		}
+	}
+
+	if priorityMixPolicy := m.AzureMachinePool.Spec.PriorityMixPolicy; priorityMixPolicy != nil {
+		spec.PriorityMixPolicy = &scalesets.PriorityMixPolicy{
+			BaseRegularPriorityCount:           priorityMixPolicy.BaseRegularPriorityCount,
+			RegularPriorityPercentageAboveBase: priorityMixPolicy.RegularPriorityPercentageAboveBase,
+		}
+	}
+
+	if scaleInPolicy := m.AzureMachinePool.Spec.ScaleInPolicy; scaleInPolicy != nil {
+		spec.ScaleInPolicy = &scalesets.ScaleInPolicy{
+			Rule:          string(scaleInPolicy.Rule),
+			ForceDeletion: scaleInPolicy.ForceDeletion,
+		}
+	}
+
+	return spec
+}
+
+// isUpgradedByAzure returns whether Azure updates the instances of the scale set to the latest model, because the
+// upgrade policy of the scale set isn't Manual.
+func (m *MachinePoolScope) isUpgradedByAzure() bool {
+	upgradePolicy := m.AzureMachinePool.Spec.UpgradePolicy
+	return upgradePolicy != nil && upgradePolicy.Mode != "" && upgradePolicy.Mode != infrav1exp.ManualUpgradeMode
+}
+
+// zoneBalance returns whether the instances of the scale set have to be strictly balanced across zones.
+func (m *MachinePoolScope) zoneBalance() *bool {
+	if m.AzureMachinePool.Spec.Placement == nil {
+		return nil
+	}
+	return m.AzureMachinePool.Spec.Placement.ZoneBalance
+}
+
+// platformFaultDomainCount returns the number of fault domains set in the placement of the scale set, if any.
+func (m *MachinePoolScope) platformFaultDomainCount() *int32 {
+	if m.AzureMachinePool.Spec.Placement == nil {
+		return nil
+	}
+	return m.AzureMachinePool.Spec.Placement.PlatformFaultDomainCount
+}
+
+// singlePlacementGroup returns whether the scale set is limited to a single placement group, if set.
+func (m *MachinePoolScope) singlePlacementGroup() *bool {
+	if m.AzureMachinePool.Spec.Placement == nil {
+		return nil
+	}
+	return m.AzureMachinePool.Spec.Placement.SinglePlacementGroup
+}
+
+// capacityReservationGroupID returns the ID of the capacity reservation group of the scale set, if any.
+func (m *MachinePoolScope) capacityReservationGroupID() string {
+	if m.AzureMachinePool.Spec.Placement == nil {
+		return ""
+	}
+	return m.AzureMachinePool.Spec.Placement.CapacityReservationGroupID
+}
+
+// pinnedInstances returns the instances of the scale set pinned to a zone and a fault domain. Pinned instances count
+// toward the replicas, so there are never more of them than replicas.
+func (m *MachinePoolScope) pinnedInstances() []scalesets.PinnedInstance {
+	if m.AzureMachinePool.Spec.Placement == nil {
+		return nil
+	}
+	var instances []scalesets.PinnedInstance
+	for _, instance := range m.AzureMachinePool.Spec.Placement.Instances {
+		if len(instances) >= int(ptr.Deref[int32](m.MachinePool.Spec.Replicas, 0)) {
+			break
+		}
+		instances = append(instances, scalesets.PinnedInstance{
+			Name:                instance.Name,
+			Zone:                instance.Zone,
+			PlatformFaultDomain: instance.PlatformFaultDomain,
+		})
+	}
+	return instances
+}
+
+// outboundLBName returns the name of the node outbound load balancer the scale set joins, if any.
+// Scale sets egressing through a NAT gateway or without outbound connectivity don't join it.
+func (m *MachinePoolScope) outboundLBName() string {
+	if m.AzureMachinePool.Spec.OutboundType == infrav1exp.NATGatewayOutboundType ||
+		m.AzureMachinePool.Spec.OutboundType == infrav1exp.NoneOutboundType {
+		return ""
+	}
+	return m.OutboundLBName(infrav1.Node)
+}
+
+// outboundPoolName returns the name of the node outbound load balancer backend pool the scale set joins,
+// which is the one of its additional outbound rule if it has one.
+func (m *MachinePoolScope) outboundPoolName() string {
+	lbName := m.outboundLBName()
+	if lbName == "" {
+		return ""
+	}
+	if m.AzureMachinePool.Spec.NodeOutboundRule != "" {
+		return azure.GenerateOutboundRuleBackendPoolName(lbName, m.AzureMachinePool.Spec.NodeOutboundRule)
+	}
+	return m.OutboundPoolName(infrav1.Node)
 }
 
 // Name returns the Azure Machine Pool Name.
 func (m *MachinePoolScope) Name() string {
-	// Windows Machine pools names cannot be longer than 9 chars
-	if m.AzureMachinePool.Spec.Template.OSDisk.OSType == azure.WindowsOS && len(m.AzureMachinePool.Name) > 9 {
+	// Windows Machine pools names cannot be longer than 9 chars, unless the computer name prefix is set separately
+	if m.AzureMachinePool.Spec.Template.OSDisk.OSType == azure.WindowsOS && len(m.AzureMachinePool.Name) > 9 && m.AzureMachinePool.Spec.Template.ComputerNamePrefix == "" {
 		return "win-" + m.AzureMachinePool.Name[len(m.AzureMachinePool.Name)-5:]
 	}
 	return m.AzureMachinePool.Name
@@ -338,9 +497,76 @@ func (m *MachinePoolScope) updateReplicasAndProviderIDs(ctx context.Context) err
 
 	m.AzureMachinePool.Status.Replicas = readyReplicas
 	m.AzureMachinePool.Spec.ProviderIDList = providerIDs
+	m.AzureMachinePool.Status.PatchStatus = aggregatePatchStatus(machines)
+	m.AzureMachinePool.Status.Rollout = aggregateRolloutStatus(machines, m.observedModelGeneration())
 	return nil
 }
 
+// observedModelGeneration returns the generation of the AzureMachinePool the VMSS model was last applied for. It is
+// only bumped to the current generation once the VMSS model was applied.
+func (m *MachinePoolScope) observedModelGeneration() int64 {
+	if m.vmssModelApplied() {
+		return m.AzureMachinePool.Generation
+	}
+	if rollout := m.AzureMachinePool.Status.Rollout; rollout != nil {
+		return rollout.ObservedModelGeneration
+	}
+	return 0
+}
+
+// vmssModelApplied returns true if the VMSS model of the current generation of the AzureMachinePool was applied during
+// this reconciliation, i.e. the scale set service set the VMSS state after a successful create or update, and the
+// resulting VMSS didn't fail to provision.
+func (m *MachinePoolScope) vmssModelApplied() bool {
+	return m.vmssState != nil && m.AzureMachinePool.DeletionTimestamp.IsZero() && m.vmssState.State != infrav1.Failed
+}
+
+// aggregateRolloutStatus counts the AzureMachinePoolMachines running the latest VMSS model of the given generation of
+// the AzureMachinePool.
+func aggregateRolloutStatus(machines []infrav1exp.AzureMachinePoolMachine, generation int64) *infrav1exp.AzureMachinePoolRolloutStatus {
+	rollout := &infrav1exp.AzureMachinePoolRolloutStatus{
+		ObservedModelGeneration: generation,
+	}
+	for _, machine := range machines {
+		if !machine.Status.LatestModelApplied {
+			rollout.RemainingReplicas++
+			continue
+		}
+		rollout.UpdatedReplicas++
+		if machine.Status.Ready {
+			rollout.ReadyUpdatedReplicas++
+		}
+	}
+	return rollout
+}
+
+// aggregatePatchStatus counts the AzureMachinePoolMachines by OS patch status. It returns nil if none of the machines
+// report a patch status.
+func aggregatePatchStatus(machines []infrav1exp.AzureMachinePoolMachine) *infrav1exp.AzureMachinePoolPatchStatus {
+	var summary *infrav1exp.AzureMachinePoolPatchStatus
+	for _, machine := range machines {
+		status := machine.Status.PatchStatus
+		if status == nil {
+			continue
+		}
+		if summary == nil {
+			summary = &infrav1exp.AzureMachinePoolPatchStatus{}
+		}
+
+		switch {
+		case status.AssessmentStatus == string(compute.PatchOperationStatusFailed),
+			status.InstallationStatus == string(compute.PatchOperationStatusFailed),
+			status.FailedPatchCount > 0:
+			summary.Failed++
+		case status.RebootPending:
+			summary.RebootPending++
+		case status.CriticalAndSecurityPatchCount == 0:
+			summary.Patched++
+		}
+	}
+	return summary
+}
+
 func (m *MachinePoolScope) getMachinePoolMachines(ctx context.Context) ([]infrav1exp.AzureMachinePoolMachine, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.getMachinePoolMachines")
 	defer done()
@@ -446,6 +672,36 @@ func (m *MachinePoolScope) applyAzureMachinePoolMachines(ctx context.Context) er
 		}
 	}
 
+	if len(toDelete) > 0 {
+		log.V(4).Info("exiting early due to AzureMachinePoolMachine(s) selected for deletion")
+		return nil
+	}
+
+	reimageSelector, ok := deleteSelector.(machinepool.ReimageSelector)
+	if !ok {
+		log.V(4).Info("done reconciling AzureMachinePoolMachine(s)")
+		return nil
+	}
+
+	// select machines to reimage to the latest model
+	toReimage, err := reimageSelector.SelectMachinesToReimage(ctx, m.DesiredReplicas(), existingMachinesByProviderID)
+	if err != nil {
+		return errors.Wrap(err, "failed selecting AzureMachinePoolMachine(s) to reimage")
+	}
+
+	for _, machine := range toReimage {
+		machine := machine
+		log.Info("marking selected AzureMachinePoolMachine to be reimaged", "providerID", machine.Spec.ProviderID)
+		before := machine.DeepCopy()
+		if machine.Annotations == nil {
+			machine.Annotations = map[string]string{}
+		}
+		machine.Annotations[infrav1exp.ReimageAnnotation] = "true"
+		if err := m.client.Patch(ctx, &machine, client.MergeFrom(before)); err != nil {
+			return errors.Wrap(err, "failed marking AzureMachinePoolMachine to be reimaged")
+		}
+	}
+
 	log.V(4).Info("done reconciling AzureMachinePoolMachine(s)")
 	return nil
 }
@@ -579,6 +835,52 @@ func (m *MachinePoolScope) AdditionalTags() infrav1.Tags {
 	return tags
 }
 
+// EstimatedCostSpec returns the resources of a single instance of the AzureMachinePool whose hourly cost is estimated.
+func (m *MachinePoolScope) EstimatedCostSpec() retailprices.CostSpec {
+	template := m.AzureMachinePool.Spec.Template
+	spec := retailprices.CostSpec{
+		Location: m.Location(),
+		VMSize:   template.VMSize,
+		OSType:   template.OSDisk.OSType,
+		Spot:     template.SpotVMOptions != nil,
+	}
+	if template.OSDisk.DiffDiskSettings == nil && template.OSDisk.ManagedDisk != nil {
+		spec.OSDiskStorageAccountType = template.OSDisk.ManagedDisk.StorageAccountType
+		spec.OSDiskSizeGB = ptr.Deref(template.OSDisk.DiskSizeGB, 0)
+	}
+	return spec
+}
+
+// EstimatedCostObject returns the AzureMachinePool.
+func (m *MachinePoolScope) EstimatedCostObject() metav1.Object {
+	return m.AzureMachinePool
+}
+
+// ReservationCoverageSpec returns the instances of the AzureMachinePool whose reservation coverage is reported.
+func (m *MachinePoolScope) ReservationCoverageSpec() reservations.CoverageSpec {
+	return reservations.CoverageSpec{
+		Location:  m.Location(),
+		VMSize:    m.AzureMachinePool.Spec.Template.VMSize,
+		Instances: m.AzureMachinePool.Status.Replicas,
+		Spot:      m.AzureMachinePool.Spec.Template.SpotVMOptions != nil,
+	}
+}
+
+// SetReservationCoverage sets the reservation coverage in the AzureMachinePool status, or clears it when coverage is
+// nil.
+func (m *MachinePoolScope) SetReservationCoverage(coverage *reservations.Coverage) {
+	if coverage == nil {
+		m.AzureMachinePool.Status.ReservationCoverage = nil
+		return
+	}
+	m.AzureMachinePool.Status.ReservationCoverage = &infrav1exp.AzureMachinePoolReservationCoverage{
+		VMSize:            coverage.VMSize,
+		Instances:         coverage.Instances,
+		ReservedInstances: coverage.ReservedInstances,
+		Covered:           coverage.ReservedInstances >= coverage.Instances,
+	}
+}
+
 // SetAnnotation sets a key value annotation on the AzureMachinePool.
 func (m *MachinePoolScope) SetAnnotation(key, value string) {
 	if m.AzureMachinePool.Annotations == nil {
@@ -587,6 +889,45 @@ func (m *MachinePoolScope) SetAnnotation(key, value string) {
 	m.AzureMachinePool.Annotations[key] = value
 }
 
+// AnnotationJSON returns a map[string]interface from a JSON annotation.
+func (m *MachinePoolScope) AnnotationJSON(annotation string) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	jsonAnnotation := m.AzureMachinePool.GetAnnotations()[annotation]
+	if jsonAnnotation == "" {
+		return out, nil
+	}
+	err := json.Unmarshal([]byte(jsonAnnotation), &out)
+	if err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// UpdateAnnotationJSON updates the `annotation` with
+// `content`. `content` in this case should be a `map[string]interface{}`
+// suitable for turning into JSON. This `content` map will be marshalled into a
+// JSON string before being set as the given `annotation`.
+func (m *MachinePoolScope) UpdateAnnotationJSON(annotation string, content map[string]interface{}) error {
+	b, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	m.SetAnnotation(annotation, string(b))
+	return nil
+}
+
+// TagsSpecs returns the tags for the scale set of the AzureMachinePool. Tag changes are applied through the Tags API,
+// so they don't roll out a new model to the instances.
+func (m *MachinePoolScope) TagsSpecs() []azure.TagsSpec {
+	return []azure.TagsSpec{
+		{
+			Scope:      azure.VMSSID(m.SubscriptionID(), m.ResourceGroup(), m.Name()),
+			Tags:       m.AzureMachinePool.Spec.AdditionalTags,
+			Annotation: azure.VMSSTagsLastAppliedAnnotation,
+		},
+	}
+}
+
 // PatchObject persists the AzureMachinePool spec and status.
 func (m *MachinePoolScope) PatchObject(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.PatchObject")
@@ -626,6 +967,9 @@ func (m *MachinePoolScope) Close(ctx context.Context) error {
 				log.V(4).Error(err, "unable to update custom data hash, ignoring.")
 			}
 		}
+		if m.vmssModelApplied() {
+			m.updateBootstrapDataSecret()
+		}
 	}
 
 	if err := m.PatchObject(ctx); err != nil {
@@ -689,14 +1033,33 @@ func (m *MachinePoolScope) updateCustomDataHash(ctx context.Context) error {
 	return nil
 }
 
+// HasBootstrapDataSecretChanges returns true if the MachinePool references a different bootstrap data secret than the one
+// last applied to the scale set model. Pools that have not recorded a secret yet are not considered changed to avoid
+// updating the model of every existing scale set.
+func (m *MachinePoolScope) HasBootstrapDataSecretChanges() bool {
+	dataSecretName := m.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName
+	lastApplied, ok := m.AzureMachinePool.GetAnnotations()[azure.BootstrapDataSecretAnnotation]
+	if dataSecretName == nil || !ok {
+		return false
+	}
+	return lastApplied != *dataSecretName
+}
+
+// updateBootstrapDataSecret saves the name of the bootstrap data secret applied to the scale set model in AzureMachinePool annotations.
+func (m *MachinePoolScope) updateBootstrapDataSecret() {
+	if dataSecretName := m.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName; dataSecretName != nil {
+		m.SetAnnotation(azure.BootstrapDataSecretAnnotation, *dataSecretName)
+	}
+}
+
 // GetVMImage picks an image from the AzureMachinePool configuration, or uses a default one.
 func (m *MachinePoolScope) GetVMImage(ctx context.Context) (*infrav1.Image, error) {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.GetVMImage")
 	defer done()
 
 	// Use custom Marketplace image, Image ID or a Shared Image Gallery image if provided
-	if m.AzureMachinePool.Spec.Template.Image != nil {
-		return m.AzureMachinePool.Spec.Template.Image, nil
+	if image := m.AzureMachinePool.Spec.Template.Image.ForLocation(m.Location()); image != nil {
+		return image, nil
 	}
 
 	var (
@@ -715,7 +1078,11 @@ func (m *MachinePoolScope) GetVMImage(ctx context.Context) (*infrav1.Image, erro
 		log.V(4).Info("No image specified for machine, using default Windows Image", "machine", m.MachinePool.GetName(), "runtime", runtime, "windowsServerVersion", windowsServerVersion)
 		defaultImage, err = svc.GetDefaultWindowsImage(ctx, m.Location(), ptr.Deref(m.MachinePool.Spec.Template.Spec.Version, ""), runtime, windowsServerVersion)
 	} else {
-		defaultImage, err = svc.GetDefaultUbuntuImage(ctx, m.Location(), ptr.Deref(m.MachinePool.Spec.Template.Spec.Version, ""))
+		var variant infrav1.ImageVariant
+		if m.AzureMachinePool.Spec.Template.Image != nil {
+			variant = m.AzureMachinePool.Spec.Template.Image.Variant
+		}
+		defaultImage, err = svc.GetDefaultUbuntuImage(ctx, m.Location(), ptr.Deref(m.MachinePool.Spec.Template.Spec.Version, ""), variant)
 	}
 
 	if err != nil {
@@ -766,36 +1133,194 @@ func (m *MachinePoolScope) VMSSExtensionSpecs() []azure.ResourceSpecGetter {
 	for _, extension := range m.AzureMachinePool.Spec.Template.VMExtensions {
 		extensionSpecs = append(extensionSpecs, &scalesets.VMSSExtensionSpec{
 			ExtensionSpec: azure.ExtensionSpec{
-				Name:              extension.Name,
-				VMName:            m.Name(),
-				Publisher:         extension.Publisher,
-				Version:           extension.Version,
-				Settings:          extension.Settings,
-				ProtectedSettings: extension.ProtectedSettings,
+				Name:                    extension.Name,
+				VMName:                  m.Name(),
+				Publisher:               extension.Publisher,
+				Version:                 extension.Version,
+				Settings:                extension.Settings,
+				ProtectedSettings:       extension.ProtectedSettings,
+				AutoUpgradeMinorVersion: extension.AutoUpgradeMinorVersion,
+				EnableAutomaticUpgrade:  extension.EnableAutomaticUpgrade,
+				Custom:                  true,
 			},
 			ResourceGroup: m.ResourceGroup(),
 		})
 	}
 
-	cpuArchitectureType, _ := m.cache.VMSKU.GetCapability(resourceskus.CPUArchitectureType)
-	bootstrapExtensionSpec := azure.GetBootstrappingVMExtension(m.AzureMachinePool.Spec.Template.OSDisk.OSType, m.CloudEnvironment(), m.Name(), cpuArchitectureType)
+	if m.AzureMachinePool.Spec.Template.EnableGPUDriverExtension {
+		gpuDriverExtensionSpec := getGPUDriverVMExtension(m.cache.VMSKU, m.AzureMachinePool.Spec.Template.OSDisk.OSType, m.Name(), m.AzureMachinePool.Spec.Template.VMExtensions)
+		if gpuDriverExtensionSpec != nil {
+			extensionSpecs = append(extensionSpecs, &scalesets.VMSSExtensionSpec{
+				ExtensionSpec: withAutomaticUpgrade(gpuDriverExtensionSpec, m.AzureMachinePool.Spec.Template.EnableAutomaticExtensionUpgrade),
+				ResourceGroup: m.ResourceGroup(),
+			})
+		}
+	}
 
-	if bootstrapExtensionSpec != nil {
+	if bootstrapExtensionSpec := m.bootstrapExtensionSpec(); bootstrapExtensionSpec != nil {
 		extensionSpecs = append(extensionSpecs, &scalesets.VMSSExtensionSpec{
-			ExtensionSpec: *bootstrapExtensionSpec,
+			ExtensionSpec: withAutomaticUpgrade(bootstrapExtensionSpec, m.AzureMachinePool.Spec.Template.EnableAutomaticExtensionUpgrade),
 			ResourceGroup: m.ResourceGroup(),
 		})
 	}
 
+	if applicationHealthExtensionSpec := m.applicationHealthExtensionSpec(); applicationHealthExtensionSpec != nil {
+		extensionSpecs = append(extensionSpecs, applicationHealthExtensionSpec)
+	}
+
 	return extensionSpecs
 }
 
+// bootstrapExtensionSpec returns the spec of the CAPZ bootstrap extension with the timeout and retry interval of the
+// AzureMachinePool, or nil if the extension is disabled or not available for the OS and cloud.
+func (m *MachinePoolScope) bootstrapExtensionSpec() *azure.ExtensionSpec {
+	timeout := azure.DefaultBootstrapExtensionTimeout
+	retryInterval := azure.DefaultBootstrapExtensionRetryInterval
+	if bootstrapExtension := m.AzureMachinePool.Spec.Template.BootstrapExtension; bootstrapExtension != nil {
+		if bootstrapExtension.Disabled {
+			return nil
+		}
+		if bootstrapExtension.Timeout != nil {
+			timeout = bootstrapExtension.Timeout.Duration
+		}
+		if bootstrapExtension.RetryInterval != nil {
+			retryInterval = bootstrapExtension.RetryInterval.Duration
+		}
+	}
+
+	cpuArchitectureType, _ := m.cache.VMSKU.GetCapability(resourceskus.CPUArchitectureType)
+	return azure.GetBootstrappingVMExtensionWithTimeout(m.AzureMachinePool.Spec.Template.OSDisk.OSType, m.CloudEnvironment(), m.Name(), cpuArchitectureType, timeout, retryInterval)
+}
+
+// applicationHealthExtensionSpec returns the spec of the Application Health extension reporting the health of the
+// instances, if automatic repairs are enabled or their health probe is set. The instances are probed on the health
+// endpoint of the kubelet unless another health probe is set.
+func (m *MachinePoolScope) applicationHealthExtensionSpec() *scalesets.ApplicationHealthExtensionSpec {
+	automaticRepairs := m.AzureMachinePool.Spec.AutomaticRepairs
+	if automaticRepairs == nil || !automaticRepairs.Enabled && automaticRepairs.HealthProbe == nil {
+		return nil
+	}
+
+	spec := &scalesets.ApplicationHealthExtensionSpec{
+		VMSSName:      m.Name(),
+		ResourceGroup: m.ResourceGroup(),
+		OSType:        m.AzureMachinePool.Spec.Template.OSDisk.OSType,
+		Protocol:      string(infrav1exp.HTTPHealthProbeProtocol),
+		Port:          10248,
+		RequestPath:   "/healthz",
+	}
+	if healthProbe := automaticRepairs.HealthProbe; healthProbe != nil {
+		spec.Port = healthProbe.Port
+		spec.RequestPath = healthProbe.RequestPath
+		if healthProbe.Protocol != "" {
+			spec.Protocol = string(healthProbe.Protocol)
+		}
+		if healthProbe.GracePeriod != nil {
+			spec.GracePeriod = &healthProbe.GracePeriod.Duration
+		}
+	}
+	return spec
+}
+
+// HasStandbyPool returns true if the AzureMachinePool attaches a standby pool to its scale set.
+func (m *MachinePoolScope) HasStandbyPool() bool {
+	return m.AzureMachinePool.Spec.StandbyPool != nil
+}
+
+// StandbyPoolSpec returns the standby pool spec. It describes the standby pool to delete when the AzureMachinePool
+// doesn't have one.
+func (m *MachinePoolScope) StandbyPoolSpec() azure.ResourceSpecGetter {
+	spec := &standbypools.StandbyPoolSpec{
+		Name:           m.Name(),
+		ResourceGroup:  m.ResourceGroup(),
+		SubscriptionID: m.SubscriptionID(),
+		Location:       m.AzureMachinePool.Spec.Location,
+		ScaleSetName:   m.Name(),
+		ClusterName:    m.ClusterName(),
+		AdditionalTags: m.AzureMachinePool.Spec.AdditionalTags,
+	}
+	if standbyPool := m.AzureMachinePool.Spec.StandbyPool; standbyPool != nil {
+		spec.MaxReadyCapacity = standbyPool.MaxReadyCapacity
+		spec.MinReadyCapacity = standbyPool.MinReadyCapacity
+		spec.VirtualMachineState = string(standbyPool.VirtualMachineState)
+		if spec.VirtualMachineState == "" {
+			spec.VirtualMachineState = string(infrav1exp.DeallocatedStandbyPoolVMState)
+		}
+	}
+	return spec
+}
+
+// StandbyPoolStatusResource returns the object reporting the status of the standby pool.
+func (m *MachinePoolScope) StandbyPoolStatusResource() conditions.Setter {
+	return m.AzureMachinePool
+}
+
+// DiagnosticsStorageAccountSpec returns the spec of the storage account dedicated to the boot diagnostics of the
+// scale set, or nil when the AzureMachinePool doesn't have one.
+func (m *MachinePoolScope) DiagnosticsStorageAccountSpec() azure.ResourceSpecGetter {
+	account := m.AzureMachinePool.Spec.DiagnosticsStorageAccount
+	if account == nil {
+		return nil
+	}
+	return &storageaccounts.StorageAccountSpec{
+		Name:           m.diagnosticsStorageAccountName(),
+		ResourceGroup:  m.ResourceGroup(),
+		Location:       m.AzureMachinePool.Spec.Location,
+		SKU:            account.SKU,
+		ClusterName:    m.ClusterName(),
+		AdditionalTags: m.AzureMachinePool.Spec.AdditionalTags,
+	}
+}
+
+// diagnosticsProfile returns the diagnostics of the scale set, which store boot diagnostics in the dedicated storage
+// account of the AzureMachinePool when it has one.
+func (m *MachinePoolScope) diagnosticsProfile() *infrav1.Diagnostics {
+	if m.AzureMachinePool.Spec.DiagnosticsStorageAccount == nil {
+		return m.AzureMachinePool.Spec.Template.Diagnostics
+	}
+
+	env := azureautorest.PublicCloud
+	if name := m.CloudEnvironment(); name != "" {
+		if e, err := azureautorest.EnvironmentFromName(name); err == nil {
+			env = e
+		}
+	}
+	return &infrav1.Diagnostics{
+		Boot: &infrav1.BootDiagnostics{
+			StorageAccountType: infrav1.UserManagedDiagnosticsStorage,
+			UserManaged: &infrav1.UserManagedBootDiagnostics{
+				StorageAccountURI: fmt.Sprintf("https://%s.blob.%s/", m.diagnosticsStorageAccountName(), env.StorageEndpointSuffix),
+			},
+		},
+	}
+}
+
+// diagnosticsStorageAccountName returns the name of the dedicated boot diagnostics storage account. Storage account
+// names are globally unique, so the default name is derived from the resource group and the name of the
+// AzureMachinePool.
+func (m *MachinePoolScope) diagnosticsStorageAccountName() string {
+	if name := m.AzureMachinePool.Spec.DiagnosticsStorageAccount.Name; name != "" {
+		return name
+	}
+	hash := sha256.Sum256([]byte(m.ResourceGroup() + "/" + m.AzureMachinePool.Name))
+	return fmt.Sprintf("diag%x", hash[:10])
+}
+
 func (m *MachinePoolScope) getDeploymentStrategy() machinepool.TypedDeleteSelector {
 	if m.AzureMachinePool == nil {
 		return nil
 	}
 
-	return machinepool.NewMachinePoolDeploymentStrategy(m.AzureMachinePool.Spec.Strategy)
+	strategy := machinepool.NewMachinePoolDeploymentStrategy(m.AzureMachinePool.Spec.Strategy)
+	if m.isUpgradedByAzure() {
+		strategy = machinepool.NewAzureUpgradedMachinePoolDeploymentStrategy(m.AzureMachinePool.Spec.Strategy)
+	}
+
+	if placement := m.AzureMachinePool.Spec.Placement; placement != nil && placement.ZoneRebalanceStrategy == infrav1exp.ScaleInZoneRebalanceStrategy {
+		strategy = machinepool.WithScaleInZoneRebalancing(strategy)
+	}
+
+	return strategy
 }
 
 // SetSubnetName defaults the AzureMachinePool subnet name to the name of the subnet with role 'node' when there is only one of them.
@@ -817,6 +1342,23 @@ func (m *MachinePoolScope) SetSubnetName() error {
 	return nil
 }
 
+// ValidateOutboundType checks that the cluster provides the egress path chosen by the outbound type of the
+// machine pool. It must be called after the subnet name has been defaulted.
+func (m *MachinePoolScope) ValidateOutboundType() error {
+	switch m.AzureMachinePool.Spec.OutboundType {
+	case infrav1exp.LoadBalancerOutboundType:
+		if m.OutboundLBName(infrav1.Node) == "" {
+			return errors.Errorf("outboundType %s requires the cluster to have a node outbound load balancer", infrav1exp.LoadBalancerOutboundType)
+		}
+	case infrav1exp.NATGatewayOutboundType:
+		subnetName := m.AzureMachinePool.Spec.Template.NetworkInterfaces[0].SubnetName
+		if !m.Subnet(subnetName).IsNatGatewayEnabled() {
+			return errors.Errorf("outboundType %s requires subnet %s to have a NAT gateway", infrav1exp.NATGatewayOutboundType, subnetName)
+		}
+	}
+	return nil
+}
+
 // UpdateDeleteStatus updates a condition on the AzureMachinePool status after a DELETE operation.
 func (m *MachinePoolScope) UpdateDeleteStatus(condition clusterv1.ConditionType, service string, err error) {
 	switch {
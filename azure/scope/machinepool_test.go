@@ -21,8 +21,10 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
 	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
 	. "github.com/onsi/gomega"
@@ -35,9 +37,12 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/reservations"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/standbypools"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/storageaccounts"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
@@ -85,6 +90,27 @@ func TestMachinePoolScope_Name(t *testing.T) {
 			},
 			want: "win-23456",
 		},
+		{
+			name: "windows is not shortened when a computer name prefix is set",
+			machinePoolScope: MachinePoolScope{
+				MachinePool: nil,
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-90123456",
+					},
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						Template: infrav1exp.AzureMachinePoolMachineTemplate{
+							OSDisk: infrav1.OSDisk{
+								OSType: "Windows",
+							},
+							ComputerNamePrefix: "prdweb",
+						},
+					},
+				},
+				ClusterScoper: nil,
+			},
+			want: "machine-90123456",
+		},
 	}
 
 	for _, tt := range tests {
@@ -101,6 +127,306 @@ func TestMachinePoolScope_Name(t *testing.T) {
 	}
 }
 
+func TestMachinePoolScope_TagsSpecs(t *testing.T) {
+	g := NewWithT(t)
+	scope := MachinePoolScope{
+		AzureMachinePool: &infrav1exp.AzureMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "machine-name",
+			},
+			Spec: infrav1exp.AzureMachinePoolSpec{
+				AdditionalTags: infrav1.Tags{"cost-center": "1234"},
+			},
+		},
+		ClusterScoper: &ClusterScope{
+			AzureClients: AzureClients{
+				EnvironmentSettings: auth.EnvironmentSettings{
+					Values: map[string]string{
+						auth.SubscriptionID: "123",
+					},
+				},
+			},
+			AzureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+				},
+			},
+		},
+	}
+
+	g.Expect(scope.TagsSpecs()).To(Equal([]azure.TagsSpec{
+		{
+			Scope:      "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/machine-name",
+			Tags:       infrav1.Tags{"cost-center": "1234"},
+			Annotation: azure.VMSSTagsLastAppliedAnnotation,
+		},
+	}))
+
+	g.Expect(scope.UpdateAnnotationJSON(azure.VMSSTagsLastAppliedAnnotation, map[string]interface{}{"cost-center": "1234"})).To(Succeed())
+	g.Expect(scope.AnnotationJSON(azure.VMSSTagsLastAppliedAnnotation)).To(Equal(map[string]interface{}{"cost-center": "1234"}))
+}
+
+func TestMachinePoolScope_outboundPoolName(t *testing.T) {
+	tests := []struct {
+		name             string
+		nodeOutboundLB   *infrav1.LoadBalancerSpec
+		nodeOutboundRule string
+		outboundType     infrav1exp.AzureMachinePoolOutboundType
+		want             string
+	}{
+		{
+			name: "no node outbound load balancer",
+			want: "",
+		},
+		{
+			name: "default outbound backend pool",
+			nodeOutboundLB: &infrav1.LoadBalancerSpec{
+				Name:        "my-cluster",
+				BackendPool: infrav1.BackendPool{Name: "my-cluster-outboundBackendPool"},
+			},
+			want: "my-cluster-outboundBackendPool",
+		},
+		{
+			name: "backend pool of an additional outbound rule",
+			nodeOutboundLB: &infrav1.LoadBalancerSpec{
+				Name:                    "my-cluster",
+				BackendPool:             infrav1.BackendPool{Name: "my-cluster-outboundBackendPool"},
+				AdditionalOutboundRules: []infrav1.OutboundRule{{Name: "egress"}},
+			},
+			nodeOutboundRule: "egress",
+			want:             "my-cluster-egress-outboundBackendPool",
+		},
+		{
+			name: "NAT gateway outbound type",
+			nodeOutboundLB: &infrav1.LoadBalancerSpec{
+				Name:        "my-cluster",
+				BackendPool: infrav1.BackendPool{Name: "my-cluster-outboundBackendPool"},
+			},
+			outboundType: infrav1exp.NATGatewayOutboundType,
+			want:         "",
+		},
+		{
+			name: "no outbound connectivity",
+			nodeOutboundLB: &infrav1.LoadBalancerSpec{
+				Name:        "my-cluster",
+				BackendPool: infrav1.BackendPool{Name: "my-cluster-outboundBackendPool"},
+			},
+			outboundType: infrav1exp.NoneOutboundType,
+			want:         "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machinePoolScope := MachinePoolScope{
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						NodeOutboundRule: tt.nodeOutboundRule,
+						OutboundType:     tt.outboundType,
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							NetworkSpec: infrav1.NetworkSpec{
+								NodeOutboundLB: tt.nodeOutboundLB,
+							},
+						},
+					},
+				},
+			}
+			g.Expect(machinePoolScope.outboundPoolName()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachinePoolScope_ValidateOutboundType(t *testing.T) {
+	tests := []struct {
+		name           string
+		outboundType   infrav1exp.AzureMachinePoolOutboundType
+		nodeOutboundLB *infrav1.LoadBalancerSpec
+		natGateway     infrav1.NatGateway
+		wantErr        bool
+	}{
+		{
+			name:    "default outbound type",
+			wantErr: false,
+		},
+		{
+			name:           "load balancer outbound type",
+			outboundType:   infrav1exp.LoadBalancerOutboundType,
+			nodeOutboundLB: &infrav1.LoadBalancerSpec{Name: "my-cluster"},
+			wantErr:        false,
+		},
+		{
+			name:         "load balancer outbound type without a node outbound load balancer",
+			outboundType: infrav1exp.LoadBalancerOutboundType,
+			wantErr:      true,
+		},
+		{
+			name:         "NAT gateway outbound type",
+			outboundType: infrav1exp.NATGatewayOutboundType,
+			natGateway:   infrav1.NatGateway{NatGatewayClassSpec: infrav1.NatGatewayClassSpec{Name: "node-natgw"}},
+			wantErr:      false,
+		},
+		{
+			name:         "NAT gateway outbound type on a subnet without a NAT gateway",
+			outboundType: infrav1exp.NATGatewayOutboundType,
+			wantErr:      true,
+		},
+		{
+			name:         "no outbound connectivity",
+			outboundType: infrav1exp.NoneOutboundType,
+			wantErr:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machinePoolScope := MachinePoolScope{
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						OutboundType: tt.outboundType,
+						Template: infrav1exp.AzureMachinePoolMachineTemplate{
+							NetworkInterfaces: []infrav1.NetworkInterface{{SubnetName: "node-subnet"}},
+						},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							NetworkSpec: infrav1.NetworkSpec{
+								NodeOutboundLB: tt.nodeOutboundLB,
+								Subnets: infrav1.Subnets{
+									{
+										SubnetClassSpec: infrav1.SubnetClassSpec{Name: "node-subnet", Role: infrav1.SubnetNode},
+										NatGateway:      tt.natGateway,
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			err := machinePoolScope.ValidateOutboundType()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestMachinePoolScope_HasBootstrapDataSecretChanges(t *testing.T) {
+	tests := []struct {
+		name           string
+		dataSecretName *string
+		annotations    map[string]string
+		want           bool
+	}{
+		{
+			name:           "no bootstrap data secret yet",
+			dataSecretName: nil,
+			annotations:    map[string]string{azure.BootstrapDataSecretAnnotation: "bootstrap-data"},
+			want:           false,
+		},
+		{
+			name:           "no secret recorded yet",
+			dataSecretName: ptr.To("bootstrap-data"),
+			want:           false,
+		},
+		{
+			name:           "same secret",
+			dataSecretName: ptr.To("bootstrap-data"),
+			annotations:    map[string]string{azure.BootstrapDataSecretAnnotation: "bootstrap-data"},
+			want:           false,
+		},
+		{
+			name:           "secret replaced",
+			dataSecretName: ptr.To("bootstrap-data-new"),
+			annotations:    map[string]string{azure.BootstrapDataSecretAnnotation: "bootstrap-data"},
+			want:           true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machinePoolScope := MachinePoolScope{
+				MachinePool: &expv1.MachinePool{
+					Spec: expv1.MachinePoolSpec{
+						Template: clusterv1.MachineTemplateSpec{
+							Spec: clusterv1.MachineSpec{
+								Bootstrap: clusterv1.Bootstrap{
+									DataSecretName: tt.dataSecretName,
+								},
+							},
+						},
+					},
+				},
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: tt.annotations,
+					},
+				},
+			}
+			g.Expect(machinePoolScope.HasBootstrapDataSecretChanges()).To(Equal(tt.want))
+
+			machinePoolScope.updateBootstrapDataSecret()
+			g.Expect(machinePoolScope.HasBootstrapDataSecretChanges()).To(BeFalse())
+		})
+	}
+}
+
+func TestMachinePoolScope_vmssModelApplied(t *testing.T) {
+	tests := []struct {
+		name      string
+		vmssState *azure.VMSS
+		deleting  bool
+		want      bool
+	}{
+		{
+			name:      "scale set not created or updated",
+			vmssState: nil,
+			want:      false,
+		},
+		{
+			name:      "scale set updated",
+			vmssState: &azure.VMSS{State: infrav1.Succeeded},
+			want:      true,
+		},
+		{
+			name:      "scale set failed to provision",
+			vmssState: &azure.VMSS{State: infrav1.Failed},
+			want:      false,
+		},
+		{
+			name:      "machine pool being deleted",
+			vmssState: &azure.VMSS{State: infrav1.Deleting},
+			deleting:  true,
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := &infrav1exp.AzureMachinePool{}
+			if tt.deleting {
+				amp.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			}
+			machinePoolScope := MachinePoolScope{
+				AzureMachinePool: amp,
+				vmssState:        tt.vmssState,
+			}
+			g.Expect(machinePoolScope.vmssModelApplied()).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestMachinePoolScope_ProviderID(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -315,6 +641,23 @@ func TestMachinePoolScope_MaxSurge(t *testing.T) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
 		},
+		{
+			Name: "percentage surge should be rounded up for small pools",
+			Setup: func(mp *expv1.MachinePool, amp *infrav1exp.AzureMachinePool) {
+				mp.Spec.Replicas = ptr.To[int32](2)
+				twentyFivePercent := intstr.FromString("25%")
+				amp.Spec.Strategy = infrav1exp.AzureMachinePoolDeploymentStrategy{
+					Type: infrav1exp.RollingUpdateAzureMachinePoolDeploymentStrategyType,
+					RollingUpdate: &infrav1exp.MachineRollingUpdateDeployment{
+						MaxSurge: &twentyFivePercent,
+					},
+				}
+			},
+			Verify: func(g *WithT, surge int, err error) {
+				g.Expect(surge).To(Equal(1))
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -396,6 +739,42 @@ func TestMachinePoolScope_SaveVMImageToStatus(t *testing.T) {
 	g.Expect(s.AzureMachinePool.Status.Image).To(Equal(image))
 }
 
+func TestMachinePoolScope_SetReservationCoverage(t *testing.T) {
+	tests := []struct {
+		name     string
+		coverage *reservations.Coverage
+		want     *infrav1exp.AzureMachinePoolReservationCoverage
+	}{
+		{
+			name:     "covered",
+			coverage: &reservations.Coverage{VMSize: "Standard_D2s_v3", Instances: 3, ReservedInstances: 3},
+			want:     &infrav1exp.AzureMachinePoolReservationCoverage{VMSize: "Standard_D2s_v3", Instances: 3, ReservedInstances: 3, Covered: true},
+		},
+		{
+			name:     "not covered",
+			coverage: &reservations.Coverage{VMSize: "Standard_D2s_v3", Instances: 3, ReservedInstances: 2},
+			want:     &infrav1exp.AzureMachinePoolReservationCoverage{VMSize: "Standard_D2s_v3", Instances: 3, ReservedInstances: 2, Covered: false},
+		},
+		{
+			name: "cleared",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &MachinePoolScope{
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					Status: infrav1exp.AzureMachinePoolStatus{
+						ReservationCoverage: &infrav1exp.AzureMachinePoolReservationCoverage{VMSize: "Standard_D4s_v3"},
+					},
+				},
+			}
+			s.SetReservationCoverage(tt.coverage)
+			g.Expect(s.AzureMachinePool.Status.ReservationCoverage).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestMachinePoolScope_GetVMImage(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -636,9 +1015,10 @@ func TestMachinePoolScope_updateReplicasAndProviderIDs(t *testing.T) {
 	_ = infrav1exp.AddToScheme(scheme)
 
 	cases := []struct {
-		Name   string
-		Setup  func(cb *fake.ClientBuilder)
-		Verify func(g *WithT, amp *infrav1exp.AzureMachinePool, err error)
+		Name      string
+		Setup     func(cb *fake.ClientBuilder)
+		VMSSState infrav1.ProvisioningState
+		Verify    func(g *WithT, amp *infrav1exp.AzureMachinePool, err error)
 	}{
 		{
 			Name: "if there are three ready machines with matching labels, then should count them",
@@ -652,6 +1032,68 @@ func TestMachinePoolScope_updateReplicasAndProviderIDs(t *testing.T) {
 				g.Expect(err).NotTo(HaveOccurred())
 				g.Expect(amp.Status.Replicas).To(BeEquivalentTo(3))
 				g.Expect(amp.Spec.ProviderIDList).To(ConsistOf("azure://foo/ampm0", "azure://foo/ampm1", "azure://foo/ampm2"))
+				g.Expect(amp.Status.PatchStatus).To(BeNil())
+			},
+		},
+		{
+			Name: "should aggregate the patch status of the machines",
+			Setup: func(cb *fake.ClientBuilder) {
+				machines := getReadyAzureMachinePoolMachines(4)
+				machines[0].Status.PatchStatus = &infrav1.VMPatchStatus{AssessmentStatus: "Succeeded"}
+				machines[1].Status.PatchStatus = &infrav1.VMPatchStatus{AssessmentStatus: "Succeeded", RebootPending: true}
+				machines[2].Status.PatchStatus = &infrav1.VMPatchStatus{AssessmentStatus: "Succeeded", InstallationStatus: "Failed", FailedPatchCount: 2}
+				for _, machine := range machines {
+					obj := machine
+					cb.WithObjects(&obj)
+				}
+			},
+			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(amp.Status.PatchStatus).To(Equal(&infrav1exp.AzureMachinePoolPatchStatus{
+					Patched:       1,
+					RebootPending: 1,
+					Failed:        1,
+				}))
+			},
+		},
+		{
+			Name: "should report the rollout progress of the latest model",
+			Setup: func(cb *fake.ClientBuilder) {
+				machines := getReadyAzureMachinePoolMachines(4)
+				machines[0].Status.LatestModelApplied = true
+				machines[1].Status.LatestModelApplied = true
+				machines[1].Status.Ready = false
+				machines[2].Status.LatestModelApplied = true
+				for _, machine := range machines {
+					obj := machine
+					cb.WithObjects(&obj)
+				}
+			},
+			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(amp.Status.Rollout).To(Equal(&infrav1exp.AzureMachinePoolRolloutStatus{
+					ObservedModelGeneration: 2,
+					UpdatedReplicas:         3,
+					ReadyUpdatedReplicas:    2,
+					RemainingReplicas:       1,
+				}))
+			},
+		},
+		{
+			Name: "should keep the observed model generation if the VMSS failed to update",
+			Setup: func(cb *fake.ClientBuilder) {
+				for _, machine := range getReadyAzureMachinePoolMachines(2) {
+					obj := machine
+					cb.WithObjects(&obj)
+				}
+			},
+			VMSSState: infrav1.Failed,
+			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(amp.Status.Rollout).To(Equal(&infrav1exp.AzureMachinePoolRolloutStatus{
+					ObservedModelGeneration: 1,
+					RemainingReplicas:       2,
+				}))
 			},
 		},
 		{
@@ -708,8 +1150,9 @@ func TestMachinePoolScope_updateReplicasAndProviderIDs(t *testing.T) {
 				}
 				amp = &infrav1exp.AzureMachinePool{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "amp1",
-						Namespace: "default",
+						Name:       "amp1",
+						Namespace:  "default",
+						Generation: 2,
 						OwnerReferences: []metav1.OwnerReference{
 							{
 								Name:       "mp1",
@@ -718,10 +1161,20 @@ func TestMachinePoolScope_updateReplicasAndProviderIDs(t *testing.T) {
 							},
 						},
 					},
+					Status: infrav1exp.AzureMachinePoolStatus{
+						Rollout: &infrav1exp.AzureMachinePoolRolloutStatus{
+							ObservedModelGeneration: 1,
+						},
+					},
 				}
+				vmssState = infrav1.Succeeded
 			)
 			defer mockCtrl.Finish()
 
+			if c.VMSSState != "" {
+				vmssState = c.VMSSState
+			}
+
 			c.Setup(cb.WithObjects(amp, cluster))
 			s := &MachinePoolScope{
 				client: cb.Build(),
@@ -729,6 +1182,7 @@ func TestMachinePoolScope_updateReplicasAndProviderIDs(t *testing.T) {
 					Cluster: cluster,
 				},
 				AzureMachinePool: amp,
+				vmssState:        &azure.VMSS{State: vmssState},
 			}
 			err := s.updateReplicasAndProviderIDs(context.TODO())
 			c.Verify(g, s.AzureMachinePool, err)
@@ -853,18 +1307,405 @@ func TestMachinePoolScope_RoleAssignmentSpecs(t *testing.T) {
 	}
 }
 
-func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
+func TestMachinePoolScope_StandbyPoolSpec(t *testing.T) {
+	clusterScope := &ClusterScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Values: map[string]string{
+					auth.SubscriptionID: "123",
+				},
+			},
+		},
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-cluster",
+			},
+		},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+			},
+		},
+	}
 	tests := []struct {
-		name             string
-		machinePoolScope MachinePoolScope
-		want             []azure.ResourceSpecGetter
+		name        string
+		standbyPool *infrav1exp.AzureMachinePoolStandbyPool
+		want        azure.ResourceSpecGetter
 	}{
 		{
-			name: "If OS type is Linux and cloud is AzurePublicCloud, it returns ExtensionSpec",
-			machinePoolScope: MachinePoolScope{
-				MachinePool: &expv1.MachinePool{},
-				AzureMachinePool: &infrav1exp.AzureMachinePool{
-					ObjectMeta: metav1.ObjectMeta{
+			name:        "describes the standby pool to delete without a standby pool",
+			standbyPool: nil,
+			want: &standbypools.StandbyPoolSpec{
+				Name:           "machine-name",
+				ResourceGroup:  "my-rg",
+				SubscriptionID: "123",
+				Location:       "westus",
+				ScaleSetName:   "machine-name",
+				ClusterName:    "my-cluster",
+			},
+		},
+		{
+			name: "defaults the virtual machine state to Deallocated",
+			standbyPool: &infrav1exp.AzureMachinePoolStandbyPool{
+				MaxReadyCapacity: 10,
+				MinReadyCapacity: ptr.To[int64](2),
+			},
+			want: &standbypools.StandbyPoolSpec{
+				Name:                "machine-name",
+				ResourceGroup:       "my-rg",
+				SubscriptionID:      "123",
+				Location:            "westus",
+				ScaleSetName:        "machine-name",
+				MaxReadyCapacity:    10,
+				MinReadyCapacity:    ptr.To[int64](2),
+				VirtualMachineState: "Deallocated",
+				ClusterName:         "my-cluster",
+			},
+		},
+		{
+			name: "keeps the instances of the standby pool running",
+			standbyPool: &infrav1exp.AzureMachinePoolStandbyPool{
+				MaxReadyCapacity:    5,
+				VirtualMachineState: infrav1exp.RunningStandbyPoolVMState,
+			},
+			want: &standbypools.StandbyPoolSpec{
+				Name:                "machine-name",
+				ResourceGroup:       "my-rg",
+				SubscriptionID:      "123",
+				Location:            "westus",
+				ScaleSetName:        "machine-name",
+				MaxReadyCapacity:    5,
+				VirtualMachineState: "Running",
+				ClusterName:         "my-cluster",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machinePoolScope := MachinePoolScope{
+				ClusterScoper: clusterScope,
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						Location:    "westus",
+						StandbyPool: tt.standbyPool,
+					},
+				},
+			}
+			g.Expect(machinePoolScope.HasStandbyPool()).To(Equal(tt.standbyPool != nil))
+			g.Expect(machinePoolScope.StandbyPoolSpec()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachinePoolScope_DiagnosticsStorageAccountSpec(t *testing.T) {
+	clusterScope := &ClusterScope{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-cluster",
+			},
+		},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+			},
+		},
+	}
+	templateDiagnostics := &infrav1.Diagnostics{
+		Boot: &infrav1.BootDiagnostics{
+			StorageAccountType: infrav1.ManagedDiagnosticsStorage,
+		},
+	}
+	tests := []struct {
+		name            string
+		storageAccount  *infrav1exp.AzureMachinePoolDiagnosticsStorageAccount
+		want            azure.ResourceSpecGetter
+		wantDiagnostics *infrav1.Diagnostics
+	}{
+		{
+			name:            "without a diagnostics storage account",
+			storageAccount:  nil,
+			want:            nil,
+			wantDiagnostics: templateDiagnostics,
+		},
+		{
+			name: "defaults the name of the diagnostics storage account",
+			storageAccount: &infrav1exp.AzureMachinePoolDiagnosticsStorageAccount{
+				SKU: "Standard_LRS",
+			},
+			want: &storageaccounts.StorageAccountSpec{
+				Name:          "diag8d6456b24910c619bb40",
+				ResourceGroup: "my-rg",
+				Location:      "westus",
+				SKU:           "Standard_LRS",
+				ClusterName:   "my-cluster",
+			},
+			wantDiagnostics: &infrav1.Diagnostics{
+				Boot: &infrav1.BootDiagnostics{
+					StorageAccountType: infrav1.UserManagedDiagnosticsStorage,
+					UserManaged: &infrav1.UserManagedBootDiagnostics{
+						StorageAccountURI: "https://diag8d6456b24910c619bb40.blob.core.windows.net/",
+					},
+				},
+			},
+		},
+		{
+			name: "uses the name of the diagnostics storage account",
+			storageAccount: &infrav1exp.AzureMachinePoolDiagnosticsStorageAccount{
+				Name: "mypooldiag",
+				SKU:  "Standard_ZRS",
+			},
+			want: &storageaccounts.StorageAccountSpec{
+				Name:          "mypooldiag",
+				ResourceGroup: "my-rg",
+				Location:      "westus",
+				SKU:           "Standard_ZRS",
+				ClusterName:   "my-cluster",
+			},
+			wantDiagnostics: &infrav1.Diagnostics{
+				Boot: &infrav1.BootDiagnostics{
+					StorageAccountType: infrav1.UserManagedDiagnosticsStorage,
+					UserManaged: &infrav1.UserManagedBootDiagnostics{
+						StorageAccountURI: "https://mypooldiag.blob.core.windows.net/",
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machinePoolScope := MachinePoolScope{
+				ClusterScoper: clusterScope,
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						Location: "westus",
+						Template: infrav1exp.AzureMachinePoolMachineTemplate{
+							Diagnostics: templateDiagnostics,
+						},
+						DiagnosticsStorageAccount: tt.storageAccount,
+					},
+				},
+			}
+			if tt.want == nil {
+				g.Expect(machinePoolScope.DiagnosticsStorageAccountSpec()).To(BeNil())
+			} else {
+				g.Expect(machinePoolScope.DiagnosticsStorageAccountSpec()).To(Equal(tt.want))
+			}
+			g.Expect(machinePoolScope.diagnosticsProfile()).To(Equal(tt.wantDiagnostics))
+		})
+	}
+}
+
+func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
+	tests := []struct {
+		name             string
+		machinePoolScope MachinePoolScope
+		want             []azure.ResourceSpecGetter
+	}{
+		{
+			name: "If OS type is Linux and cloud is AzurePublicCloud, it returns ExtensionSpec",
+			machinePoolScope: MachinePoolScope{
+				MachinePool: &expv1.MachinePool{},
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machinepool-name",
+					},
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						Template: infrav1exp.AzureMachinePoolMachineTemplate{
+							OSDisk: infrav1.OSDisk{
+								OSType: "Linux",
+							},
+						},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Environment: azureautorest.Environment{
+								Name: azureautorest.PublicCloud.Name,
+							},
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+						},
+					},
+				},
+				cache: &MachinePoolCache{
+					VMSKU: resourceskus.SKU{},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&scalesets.VMSSExtensionSpec{
+					ExtensionSpec: azure.ExtensionSpec{
+						Name:      "CAPZ.Linux.Bootstrapping",
+						VMName:    "machinepool-name",
+						Publisher: "Microsoft.Azure.ContainerUpstream",
+						Version:   "1.0",
+						ProtectedSettings: map[string]string{
+							"commandToExecute": azure.LinuxBootstrapExtensionCommand,
+						},
+						EnableAutomaticUpgrade: ptr.To(false),
+					},
+					ResourceGroup: "my-rg",
+				},
+			},
+		},
+		{
+			name: "If the bootstrap extension is disabled, it doesn't return its ExtensionSpec",
+			machinePoolScope: MachinePoolScope{
+				MachinePool: &expv1.MachinePool{},
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machinepool-name",
+					},
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						Template: infrav1exp.AzureMachinePoolMachineTemplate{
+							OSDisk: infrav1.OSDisk{
+								OSType: "Linux",
+							},
+							BootstrapExtension: &infrav1exp.AzureMachinePoolBootstrapExtension{
+								Disabled: true,
+							},
+						},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Environment: azureautorest.Environment{
+								Name: azureautorest.PublicCloud.Name,
+							},
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+						},
+					},
+				},
+				cache: &MachinePoolCache{
+					VMSKU: resourceskus.SKU{},
+				},
+			},
+			want: []azure.ResourceSpecGetter{},
+		},
+		{
+			name: "If automatic repairs are enabled, it returns the Application Health ExtensionSpec",
+			machinePoolScope: MachinePoolScope{
+				MachinePool: &expv1.MachinePool{},
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machinepool-name",
+					},
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						Template: infrav1exp.AzureMachinePoolMachineTemplate{
+							OSDisk: infrav1.OSDisk{
+								OSType: "Windows",
+							},
+						},
+						AutomaticRepairs: &infrav1exp.AzureMachinePoolAutomaticRepairs{
+							Enabled:     true,
+							HealthProbe: &infrav1exp.AzureMachinePoolHealthProbe{Protocol: infrav1exp.TCPHealthProbeProtocol, Port: 10250},
+						},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Environment: azureautorest.Environment{
+								Name: azureautorest.USGovernmentCloud.Name,
+							},
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+						},
+					},
+				},
+				cache: &MachinePoolCache{
+					VMSKU: resourceskus.SKU{},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&scalesets.ApplicationHealthExtensionSpec{
+					VMSSName:      "machinepool-name",
+					ResourceGroup: "my-rg",
+					OSType:        "Windows",
+					Protocol:      "tcp",
+					Port:          10250,
+				},
+			},
+		},
+		{
+			name: "If a health probe is set without automatic repairs, it returns the Application Health ExtensionSpec with its grace period",
+			machinePoolScope: MachinePoolScope{
+				MachinePool: &expv1.MachinePool{},
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machinepool-name",
+					},
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						Template: infrav1exp.AzureMachinePoolMachineTemplate{
+							OSDisk: infrav1.OSDisk{
+								OSType: "Windows",
+							},
+						},
+						AutomaticRepairs: &infrav1exp.AzureMachinePoolAutomaticRepairs{
+							HealthProbe: &infrav1exp.AzureMachinePoolHealthProbe{
+								Protocol:    infrav1exp.HTTPSHealthProbeProtocol,
+								Port:        443,
+								RequestPath: "/healthz",
+								GracePeriod: &metav1.Duration{Duration: 20 * time.Minute},
+							},
+						},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Environment: azureautorest.Environment{
+								Name: azureautorest.USGovernmentCloud.Name,
+							},
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+						},
+					},
+				},
+				cache: &MachinePoolCache{
+					VMSKU: resourceskus.SKU{},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&scalesets.ApplicationHealthExtensionSpec{
+					VMSSName:      "machinepool-name",
+					ResourceGroup: "my-rg",
+					OSType:        "Windows",
+					Protocol:      "https",
+					Port:          443,
+					RequestPath:   "/healthz",
+					GracePeriod:   ptr.To(20 * time.Minute),
+				},
+			},
+		},
+		{
+			name: "If automatic extension upgrade is enabled, it returns the bootstrap ExtensionSpec with automatic upgrade",
+			machinePoolScope: MachinePoolScope{
+				MachinePool: &expv1.MachinePool{},
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
 						Name: "machinepool-name",
 					},
 					Spec: infrav1exp.AzureMachinePoolSpec{
@@ -872,6 +1713,7 @@ func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 							OSDisk: infrav1.OSDisk{
 								OSType: "Linux",
 							},
+							EnableAutomaticExtensionUpgrade: true,
 						},
 					},
 				},
@@ -903,6 +1745,60 @@ func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 						ProtectedSettings: map[string]string{
 							"commandToExecute": azure.LinuxBootstrapExtensionCommand,
 						},
+						EnableAutomaticUpgrade: ptr.To(true),
+					},
+					ResourceGroup: "my-rg",
+				},
+			},
+		},
+		{
+			name: "If VM size has GPUs, it returns the GPU driver ExtensionSpec",
+			machinePoolScope: MachinePoolScope{
+				MachinePool: &expv1.MachinePool{},
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machinepool-name",
+					},
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						Template: infrav1exp.AzureMachinePoolMachineTemplate{
+							OSDisk: infrav1.OSDisk{
+								OSType: "Windows",
+							},
+							EnableGPUDriverExtension: true,
+						},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Environment: azureautorest.Environment{
+								Name: azureautorest.USGovernmentCloud.Name,
+							},
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+						},
+					},
+				},
+				cache: &MachinePoolCache{
+					VMSKU: resourceskus.SKU{
+						Name: ptr.To("Standard_NV8as_v4"),
+						Capabilities: &[]compute.ResourceSkuCapabilities{
+							{Name: ptr.To(resourceskus.GPUs), Value: ptr.To("1")},
+						},
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&scalesets.VMSSExtensionSpec{
+					ExtensionSpec: azure.ExtensionSpec{
+						Name:                   "AmdGpuDriverWindows",
+						VMName:                 "machinepool-name",
+						Publisher:              "Microsoft.HpcCompute",
+						Version:                "1.1",
+						EnableAutomaticUpgrade: ptr.To(false),
 					},
 					ResourceGroup: "my-rg",
 				},
@@ -990,6 +1886,7 @@ func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 						ProtectedSettings: map[string]string{
 							"commandToExecute": azure.WindowsBootstrapExtensionCommand,
 						},
+						EnableAutomaticUpgrade: ptr.To(false),
 					},
 					ResourceGroup: "my-rg",
 				},
@@ -1166,6 +2063,7 @@ func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 						ProtectedSettings: map[string]string{
 							"commandToExecute": "echo hello world",
 						},
+						Custom: true,
 					},
 					ResourceGroup: "my-rg",
 				},
@@ -1178,6 +2076,7 @@ func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 						ProtectedSettings: map[string]string{
 							"commandToExecute": azure.LinuxBootstrapExtensionCommand,
 						},
+						EnableAutomaticUpgrade: ptr.To(false),
 					},
 					ResourceGroup: "my-rg",
 				},
@@ -1293,6 +2192,48 @@ func TestMachinePoolScope_applyAzureMachinePoolMachines(t *testing.T) {
 				g.Expect(len(list.Items)).Should(Equal(1))
 			},
 		},
+		{
+			Name: "if ReplacePolicy is Reimage, mark machines without the latest model to be reimaged instead of deleting them",
+			Setup: func(mp *expv1.MachinePool, amp *infrav1exp.AzureMachinePool, vmssState *azure.VMSS, cb *fake.ClientBuilder) {
+				mp.Spec.Replicas = ptr.To[int32](2)
+				zero := intstr.FromInt(0)
+				amp.Spec.Strategy = infrav1exp.AzureMachinePoolDeploymentStrategy{
+					Type: infrav1exp.RollingUpdateAzureMachinePoolDeploymentStrategyType,
+					RollingUpdate: &infrav1exp.MachineRollingUpdateDeployment{
+						MaxUnavailable: &zero,
+						ReplacePolicy:  infrav1exp.ReimageReplacePolicyType,
+					},
+				}
+
+				for _, machine := range getReadyAzureMachinePoolMachines(2) {
+					obj := machine
+					cb.WithObjects(&obj)
+				}
+				vmssState.Instances = []azure.VMSSVM{
+					{
+						ID:   "foo/ampm0",
+						Name: "ampm0",
+					},
+					{
+						ID:   "foo/ampm1",
+						Name: "ampm1",
+					},
+				}
+			},
+			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, c client.Client, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				list := infrav1exp.AzureMachinePoolMachineList{}
+				g.Expect(c.List(ctx, &list)).NotTo(HaveOccurred())
+				g.Expect(list.Items).To(HaveLen(2))
+				reimaged := 0
+				for _, machine := range list.Items {
+					if _, ok := machine.Annotations[infrav1exp.ReimageAnnotation]; ok {
+						reimaged++
+					}
+				}
+				g.Expect(reimaged).To(Equal(1))
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.Name, func(t *testing.T) {
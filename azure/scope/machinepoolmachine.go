@@ -169,6 +169,18 @@ func (s *MachinePoolMachineScope) OrchestrationMode() infrav1.OrchestrationModeT
 	return s.AzureMachinePool.Spec.OrchestrationMode
 }
 
+// ProtectFromScaleIn returns true if the instance should be protected from scale-in.
+func (s *MachinePoolMachineScope) ProtectFromScaleIn() bool {
+	policy := s.AzureMachinePoolMachine.Spec.ProtectionPolicy
+	return policy != nil && policy.ProtectFromScaleIn
+}
+
+// ProtectFromScaleSetActions returns true if the instance should be protected from actions on the scale set.
+func (s *MachinePoolMachineScope) ProtectFromScaleSetActions() bool {
+	policy := s.AzureMachinePoolMachine.Spec.ProtectionPolicy
+	return policy != nil && policy.ProtectFromScaleSetActions
+}
+
 // SetLongRunningOperationState will set the future on the AzureMachinePoolMachine status to allow the resource to continue
 // in the next reconciliation.
 func (s *MachinePoolMachineScope) SetLongRunningOperationState(future *infrav1.Future) {
@@ -257,7 +269,14 @@ func (s *MachinePoolMachineScope) ProviderID() string {
 
 // PatchObject persists the MachinePoolMachine spec and status.
 func (s *MachinePoolMachineScope) PatchObject(ctx context.Context) error {
-	conditions.SetSummary(s.AzureMachinePoolMachine)
+	// An instance that doesn't run the latest model is still ready, so its model isn't part of the summary.
+	summaryConditions := []clusterv1.ConditionType{}
+	for _, c := range s.AzureMachinePoolMachine.GetConditions() {
+		if c.Type != clusterv1.ReadyCondition && c.Type != infrav1.InstanceLatestModelCondition {
+			summaryConditions = append(summaryConditions, c.Type)
+		}
+	}
+	conditions.SetSummary(s.AzureMachinePoolMachine, conditions.WithConditions(summaryConditions...))
 
 	return s.patchHelper.Patch(
 		ctx,
@@ -340,11 +359,81 @@ func (s *MachinePoolMachineScope) UpdateNodeStatus(ctx context.Context) error {
 		}
 
 		s.AzureMachinePoolMachine.Status.Version = node.Status.NodeInfo.KubeletVersion
+
+		if err := s.applyNodeLabelsAndTaints(ctx, node); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyNodeLabelsAndTaints adds the node labels and taints of the AzureMachinePool to the Kubernetes node associated with
+// this AzureMachinePoolMachine when they are missing or have a different value.
+func (s *MachinePoolMachineScope) applyNodeLabelsAndTaints(ctx context.Context, node *corev1.Node) error {
+	ctx, log, done := tele.StartSpanWithLogger(
+		ctx,
+		"scope.MachinePoolMachineScope.applyNodeLabelsAndTaints",
+	)
+	defer done()
+
+	before := node.DeepCopy()
+	template := s.AzureMachinePool.Spec.Template
+	if !addNodeLabelsAndTaints(node, template.NodeLabels, template.NodeTaints) {
+		return nil
+	}
+
+	workloadClient, err := getWorkloadClient(ctx, s.client, client.ObjectKey{
+		Name:      s.ClusterName(),
+		Namespace: s.AzureMachinePoolMachine.Namespace,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create the workload cluster client")
 	}
 
+	log.V(4).Info("Adding labels and taints to node", "node", node.Name)
+	if err := workloadClient.Patch(ctx, node, client.MergeFrom(before)); err != nil {
+		return azure.WithTransientError(errors.Errorf("unable to add labels and taints to node %s: %v", node.Name, err), 20*time.Second)
+	}
 	return nil
 }
 
+// addNodeLabelsAndTaints sets the given labels and taints on the node and returns whether the node changed. Taints are
+// matched by key and effect. Labels and taints of the node that are not given are left untouched.
+func addNodeLabelsAndTaints(node *corev1.Node, labels map[string]string, taints infrav1.Taints) bool {
+	changed := false
+	for key, value := range labels {
+		if current, ok := node.Labels[key]; ok && current == value {
+			continue
+		}
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		node.Labels[key] = value
+		changed = true
+	}
+
+	for _, taint := range taints {
+		nodeTaint := corev1.Taint{Key: taint.Key, Value: taint.Value, Effect: corev1.TaintEffect(taint.Effect)}
+		found := false
+		for i := range node.Spec.Taints {
+			if !node.Spec.Taints[i].MatchTaint(&nodeTaint) {
+				continue
+			}
+			found = true
+			if node.Spec.Taints[i].Value != nodeTaint.Value {
+				node.Spec.Taints[i].Value = nodeTaint.Value
+				changed = true
+			}
+		}
+		if !found {
+			node.Spec.Taints = append(node.Spec.Taints, nodeTaint)
+			changed = true
+		}
+	}
+	return changed
+}
+
 // UpdateInstanceStatus updates the provisioning state of the AzureMachinePoolMachine and if it has the latest model applied
 // using the VMSS VM instance.
 // Note: This func should be called at the end of a reconcile request and after updating the scope with the most recent Azure data.
@@ -363,11 +452,55 @@ func (s *MachinePoolMachineScope) UpdateInstanceStatus(ctx context.Context) erro
 		}
 
 		s.AzureMachinePoolMachine.Status.LatestModelApplied = hasLatestModel
+		s.AzureMachinePoolMachine.Status.PatchStatus = s.instance.PatchStatus
+		s.AzureMachinePoolMachine.Status.Zone = s.instance.AvailabilityZone
+		s.AzureMachinePoolMachine.Status.PlatformFaultDomain = s.instance.PlatformFaultDomain
+		// The network interfaces are only fetched when the instance is reconciled, not while it is deleted or reimaged.
+		if s.instance.NetworkInterfaceIDs != nil {
+			s.AzureMachinePoolMachine.Status.NetworkInterfaceIDs = s.instance.NetworkInterfaceIDs
+			// The addresses are nil when they couldn't be looked up, in which case the last known ones are kept.
+			if s.instance.Addresses != nil {
+				s.AzureMachinePoolMachine.Status.Addresses = s.instance.Addresses
+			}
+		}
+		s.updateInstanceConditions()
 	}
 
 	return nil
 }
 
+// updateInstanceConditions reports the provisioning state, the power state and the model of the VMSS VM instance in
+// the conditions of the AzureMachinePoolMachine.
+func (s *MachinePoolMachineScope) updateInstanceConditions() {
+	switch state := s.instance.State; state {
+	case infrav1.Succeeded:
+		conditions.MarkTrue(s.AzureMachinePoolMachine, infrav1.InstanceProvisionedCondition)
+	case infrav1.Failed:
+		conditions.MarkFalse(s.AzureMachinePoolMachine, infrav1.InstanceProvisionedCondition, infrav1.InstanceProvisionFailedReason, clusterv1.ConditionSeverityError, "instance provisioning state is %s", state)
+	case infrav1.Deleting:
+		conditions.MarkFalse(s.AzureMachinePoolMachine, infrav1.InstanceProvisionedCondition, infrav1.InstanceDeletingReason, clusterv1.ConditionSeverityInfo, "instance provisioning state is %s", state)
+	default:
+		conditions.MarkFalse(s.AzureMachinePoolMachine, infrav1.InstanceProvisionedCondition, infrav1.InstanceProvisioningReason, clusterv1.ConditionSeverityInfo, "instance provisioning state is %s", state)
+	}
+
+	// The power state is only reported with the instance view of the instance.
+	switch powerState := s.instance.PowerState; powerState {
+	case "":
+	case "running":
+		conditions.MarkTrue(s.AzureMachinePoolMachine, infrav1.InstanceRunningCondition)
+	case "starting":
+		conditions.MarkFalse(s.AzureMachinePoolMachine, infrav1.InstanceRunningCondition, infrav1.InstanceNotRunningReason, clusterv1.ConditionSeverityInfo, "instance power state is %s", powerState)
+	default:
+		conditions.MarkFalse(s.AzureMachinePoolMachine, infrav1.InstanceRunningCondition, infrav1.InstanceNotRunningReason, clusterv1.ConditionSeverityWarning, "instance power state is %s", powerState)
+	}
+
+	if s.AzureMachinePoolMachine.Status.LatestModelApplied {
+		conditions.MarkTrue(s.AzureMachinePoolMachine, infrav1.InstanceLatestModelCondition)
+	} else {
+		conditions.MarkFalse(s.AzureMachinePoolMachine, infrav1.InstanceLatestModelCondition, infrav1.InstanceModelOutOfDateReason, clusterv1.ConditionSeverityInfo, "instance doesn't run the latest model of the scale set")
+	}
+}
+
 // CordonAndDrain will cordon and drain the Kubernetes node associated with this AzureMachinePoolMachine.
 func (s *MachinePoolMachineScope) CordonAndDrain(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(
@@ -425,6 +558,84 @@ func (s *MachinePoolMachineScope) CordonAndDrain(ctx context.Context) error {
 	return nil
 }
 
+// IsReimageRequested indicates the AzureMachinePoolMachine was selected to be reimaged to the latest model.
+func (s *MachinePoolMachineScope) IsReimageRequested() bool {
+	_, ok := s.AzureMachinePoolMachine.Annotations[infrav1exp.ReimageAnnotation]
+	return ok
+}
+
+// IsReimaged indicates the instance of the AzureMachinePoolMachine finished being updated to the latest model.
+func (s *MachinePoolMachineScope) IsReimaged() bool {
+	_, ok := s.AzureMachinePoolMachine.Annotations[infrav1exp.ReimagedAtAnnotation]
+	return ok
+}
+
+// MarkReimaged records that the instance of the AzureMachinePoolMachine finished being updated to the latest model.
+func (s *MachinePoolMachineScope) MarkReimaged() {
+	if s.AzureMachinePoolMachine.Annotations == nil {
+		s.AzureMachinePoolMachine.Annotations = map[string]string{}
+	}
+	s.AzureMachinePoolMachine.Annotations[infrav1exp.ReimagedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+}
+
+// FinishReimage uncordons the Kubernetes node associated with this AzureMachinePoolMachine and clears the reimage
+// request once the node reported it is ready after the instance was reimaged.
+func (s *MachinePoolMachineScope) FinishReimage(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(
+		ctx,
+		"scope.MachinePoolMachineScope.FinishReimage",
+	)
+	defer done()
+
+	reimagedAt, err := time.Parse(time.RFC3339, s.AzureMachinePoolMachine.Annotations[infrav1exp.ReimagedAtAnnotation])
+	if err != nil {
+		return errors.Wrap(err, "failed to parse the time the instance was reimaged")
+	}
+
+	node, found, err := s.GetNode(ctx)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to get node")
+	}
+
+	// Until the kubelet of the reimaged instance reports its status, the node may still be ready from before the
+	// reimage.
+	if !found || !nodeReadySince(node, reimagedAt) {
+		return azure.WithTransientError(errors.New("waiting for the node of the reimaged instance to be ready"), 30*time.Second)
+	}
+
+	if node.Spec.Unschedulable {
+		workloadClient, err := getWorkloadClient(ctx, s.client, client.ObjectKey{
+			Name:      s.ClusterName(),
+			Namespace: s.AzureMachinePoolMachine.Namespace,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to create the workload cluster client")
+		}
+
+		log.V(4).Info("Uncordoning node", "node", node.Name)
+		before := node.DeepCopy()
+		node.Spec.Unschedulable = false
+		if err := workloadClient.Patch(ctx, node, client.MergeFrom(before)); err != nil {
+			return azure.WithTransientError(errors.Errorf("unable to uncordon node %s: %v", node.Name, err), 20*time.Second)
+		}
+	}
+
+	delete(s.AzureMachinePoolMachine.Annotations, infrav1exp.ReimageAnnotation)
+	delete(s.AzureMachinePoolMachine.Annotations, infrav1exp.ReimagedAtAnnotation)
+	conditions.Delete(s.AzureMachinePoolMachine, clusterv1.DrainingSucceededCondition)
+	return nil
+}
+
+// nodeReadySince returns true if the node reported it is ready at or after the given time.
+func nodeReadySince(node *corev1.Node, since time.Time) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue && !condition.LastHeartbeatTime.Time.Before(since)
+		}
+	}
+	return false
+}
+
 func (s *MachinePoolMachineScope) drainNode(ctx context.Context, node *corev1.Node) error {
 	ctx, log, done := tele.StartSpanWithLogger(
 		ctx,
@@ -19,6 +19,7 @@ package scope
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
@@ -27,6 +28,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
 	mock_scope "sigs.k8s.io/cluster-api-provider-azure/azure/scope/mocks"
@@ -274,6 +276,140 @@ func TestMachineScope_UpdateNodeStatus(t *testing.T) {
 	}
 }
 
+func TestAddNodeLabelsAndTaints(t *testing.T) {
+	tests := []struct {
+		name        string
+		node        *corev1.Node
+		labels      map[string]string
+		taints      infrav1.Taints
+		wantChanged bool
+		wantLabels  map[string]string
+		wantTaints  []corev1.Taint
+	}{
+		{
+			name:        "no labels and taints",
+			node:        &corev1.Node{},
+			wantChanged: false,
+		},
+		{
+			name:        "adds missing labels and taints",
+			node:        &corev1.Node{},
+			labels:      map[string]string{"pool": "win"},
+			taints:      infrav1.Taints{{Key: "os", Value: "windows", Effect: "NoSchedule"}},
+			wantChanged: true,
+			wantLabels:  map[string]string{"pool": "win"},
+			wantTaints:  []corev1.Taint{{Key: "os", Value: "windows", Effect: corev1.TaintEffectNoSchedule}},
+		},
+		{
+			name: "labels and taints already present",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"pool": "win", "other": "label"}},
+				Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: "os", Value: "windows", Effect: corev1.TaintEffectNoSchedule}}},
+			},
+			labels:      map[string]string{"pool": "win"},
+			taints:      infrav1.Taints{{Key: "os", Value: "windows", Effect: "NoSchedule"}},
+			wantChanged: false,
+			wantLabels:  map[string]string{"pool": "win", "other": "label"},
+			wantTaints:  []corev1.Taint{{Key: "os", Value: "windows", Effect: corev1.TaintEffectNoSchedule}},
+		},
+		{
+			name: "updates values and keeps other taints",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"pool": "linux"}},
+				Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+					{Key: "node.kubernetes.io/not-ready", Effect: corev1.TaintEffectNoExecute},
+					{Key: "os", Value: "linux", Effect: corev1.TaintEffectNoSchedule},
+				}},
+			},
+			labels:      map[string]string{"pool": "win"},
+			taints:      infrav1.Taints{{Key: "os", Value: "windows", Effect: "NoSchedule"}},
+			wantChanged: true,
+			wantLabels:  map[string]string{"pool": "win"},
+			wantTaints: []corev1.Taint{
+				{Key: "node.kubernetes.io/not-ready", Effect: corev1.TaintEffectNoExecute},
+				{Key: "os", Value: "windows", Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(addNodeLabelsAndTaints(tt.node, tt.labels, tt.taints)).To(Equal(tt.wantChanged))
+			g.Expect(tt.node.Labels).To(Equal(tt.wantLabels))
+			g.Expect(tt.node.Spec.Taints).To(Equal(tt.wantTaints))
+		})
+	}
+}
+
+func TestMachinePoolMachineScope_updateInstanceConditions(t *testing.T) {
+	cases := []struct {
+		Name               string
+		Instance           *azure.VMSSVM
+		LatestModelApplied bool
+		Verify             func(t *testing.T, g *WithT, ampm *infrav1exp.AzureMachinePoolMachine)
+	}{
+		{
+			Name:               "running instance with the latest model",
+			Instance:           &azure.VMSSVM{State: infrav1.Succeeded, PowerState: "running"},
+			LatestModelApplied: true,
+			Verify: func(t *testing.T, g *WithT, ampm *infrav1exp.AzureMachinePoolMachine) {
+				t.Helper()
+				g.Expect(conditions.IsTrue(ampm, infrav1.InstanceProvisionedCondition)).To(BeTrue())
+				g.Expect(conditions.IsTrue(ampm, infrav1.InstanceRunningCondition)).To(BeTrue())
+				g.Expect(conditions.IsTrue(ampm, infrav1.InstanceLatestModelCondition)).To(BeTrue())
+			},
+		},
+		{
+			Name:     "deallocated instance with an out of date model",
+			Instance: &azure.VMSSVM{State: infrav1.Succeeded, PowerState: "deallocated"},
+			Verify: func(t *testing.T, g *WithT, ampm *infrav1exp.AzureMachinePoolMachine) {
+				t.Helper()
+				g.Expect(conditions.IsTrue(ampm, infrav1.InstanceProvisionedCondition)).To(BeTrue())
+				assertCondition(t, ampm, conditions.FalseCondition(infrav1.InstanceRunningCondition, infrav1.InstanceNotRunningReason, clusterv1.ConditionSeverityWarning, "instance power state is deallocated"))
+				assertCondition(t, ampm, conditions.FalseCondition(infrav1.InstanceLatestModelCondition, infrav1.InstanceModelOutOfDateReason, clusterv1.ConditionSeverityInfo, "instance doesn't run the latest model of the scale set"))
+			},
+		},
+		{
+			Name:               "starting instance being updated",
+			Instance:           &azure.VMSSVM{State: infrav1.Updating, PowerState: "starting"},
+			LatestModelApplied: true,
+			Verify: func(t *testing.T, g *WithT, ampm *infrav1exp.AzureMachinePoolMachine) {
+				t.Helper()
+				assertCondition(t, ampm, conditions.FalseCondition(infrav1.InstanceProvisionedCondition, infrav1.InstanceProvisioningReason, clusterv1.ConditionSeverityInfo, "instance provisioning state is Updating"))
+				assertCondition(t, ampm, conditions.FalseCondition(infrav1.InstanceRunningCondition, infrav1.InstanceNotRunningReason, clusterv1.ConditionSeverityInfo, "instance power state is starting"))
+			},
+		},
+		{
+			Name:               "failed instance without an instance view",
+			Instance:           &azure.VMSSVM{State: infrav1.Failed},
+			LatestModelApplied: true,
+			Verify: func(t *testing.T, g *WithT, ampm *infrav1exp.AzureMachinePoolMachine) {
+				t.Helper()
+				assertCondition(t, ampm, conditions.FalseCondition(infrav1.InstanceProvisionedCondition, infrav1.InstanceProvisionFailedReason, clusterv1.ConditionSeverityError, "instance provisioning state is Failed"))
+				g.Expect(conditions.Has(ampm, infrav1.InstanceRunningCondition)).To(BeFalse())
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &MachinePoolMachineScope{
+				AzureMachinePoolMachine: &infrav1exp.AzureMachinePoolMachine{
+					Status: infrav1exp.AzureMachinePoolMachineStatus{
+						LatestModelApplied: c.LatestModelApplied,
+					},
+				},
+				instance: c.Instance,
+			}
+
+			s.updateInstanceConditions()
+			c.Verify(t, g, s.AzureMachinePoolMachine)
+		})
+	}
+}
+
 func TestMachinePoolMachineScope_CordonAndDrain(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = expv1.AddToScheme(scheme)
@@ -367,6 +503,90 @@ func TestMachinePoolMachineScope_CordonAndDrain(t *testing.T) {
 	}
 }
 
+func TestMachinePoolMachineScope_FinishReimage(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = expv1.AddToScheme(scheme)
+	_ = infrav1exp.AddToScheme(scheme)
+
+	reimagedAt := time.Date(2023, time.June, 1, 12, 0, 0, 0, time.UTC)
+	readyNode := func(heartbeat time.Time) *corev1.Node {
+		node := getReadyNode()
+		node.Status.Conditions[0].LastHeartbeatTime = metav1.NewTime(heartbeat)
+		return node
+	}
+
+	cases := []struct {
+		Name     string
+		Node     *corev1.Node
+		Finished bool
+	}{
+		{
+			Name: "should wait for the node if it was not found",
+		},
+		{
+			Name: "should wait for the node if it only reported it is ready before the instance was reimaged",
+			Node: readyNode(reimagedAt.Add(-time.Minute)),
+		},
+		{
+			Name:     "should clear the reimage request once the node reported it is ready after the instance was reimaged",
+			Node:     readyNode(reimagedAt.Add(time.Minute)),
+			Finished: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var (
+				controller = gomock.NewController(t)
+				mockClient = mock_scope.NewMocknodeGetter(controller)
+				g          = NewWithT(t)
+				ampm       = &infrav1exp.AzureMachinePoolMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{
+							infrav1exp.ReimageAnnotation:    "true",
+							infrav1exp.ReimagedAtAnnotation: reimagedAt.Format(time.RFC3339),
+						},
+					},
+					Spec: infrav1exp.AzureMachinePoolMachineSpec{
+						ProviderID: FakeProviderID,
+					},
+				}
+			)
+
+			defer controller.Finish()
+
+			mockClient.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(c.Node, nil)
+			s, err := NewMachinePoolMachineScope(MachinePoolMachineScopeParams{
+				Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+				ClusterScope: &ClusterScope{
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cluster-foo",
+						},
+					},
+				},
+				MachinePool:             new(expv1.MachinePool),
+				AzureMachinePool:        new(infrav1exp.AzureMachinePool),
+				AzureMachinePoolMachine: ampm,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			s.workloadNodeGetter = mockClient
+
+			err = s.FinishReimage(context.TODO())
+			if c.Finished {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(ampm.Annotations).NotTo(HaveKey(infrav1exp.ReimageAnnotation))
+				g.Expect(ampm.Annotations).NotTo(HaveKey(infrav1exp.ReimagedAtAnnotation))
+			} else {
+				var recerr azure.ReconcileError
+				g.Expect(errors.As(err, &recerr)).To(BeTrue())
+				g.Expect(recerr.IsTransient()).To(BeTrue())
+				g.Expect(ampm.Annotations).To(HaveKey(infrav1exp.ReimageAnnotation))
+			}
+		})
+	}
+}
+
 func getReadyNode() *corev1.Node {
 	return &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
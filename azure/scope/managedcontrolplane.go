@@ -19,6 +19,7 @@ package scope
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"time"
 
@@ -123,6 +124,9 @@ type ManagedControlPlaneScope struct {
 	kubeConfigData []byte
 	cache          *ManagedControlPlaneCache
 
+	windowsAdminPassword              string
+	windowsAdminPasswordSecretVersion string
+
 	AzureClients
 	Cluster             *clusterv1.Cluster
 	ControlPlane        *infrav1.AzureManagedControlPlane
@@ -231,9 +235,21 @@ func (s *ManagedControlPlaneScope) PatchObject(ctx context.Context) error {
 			infrav1.ManagedClusterRunningCondition,
 			infrav1.AgentPoolsReadyCondition,
 			infrav1.AzureResourceAvailableCondition,
+			infrav1.ResourceProvidersRegisteredCondition,
 		}})
 }
 
+// RequiredResourceProviders returns the namespaces of the resource providers the subscription of the cluster must be
+// registered with.
+func (s *ManagedControlPlaneScope) RequiredResourceProviders() []string {
+	return []string{"Microsoft.Compute", "Microsoft.Network", "Microsoft.ContainerService", "Microsoft.ManagedIdentity"}
+}
+
+// ResourceProvidersObject refers to the AzureManagedControlPlane.
+func (s *ManagedControlPlaneScope) ResourceProvidersObject() conditions.Setter {
+	return s.ControlPlane
+}
+
 // Close closes the current scope persisting the cluster configuration and status.
 func (s *ManagedControlPlaneScope) Close(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.ManagedControlPlaneScope.Close")
@@ -583,9 +599,63 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec() azure.ResourceSpecGetter
 		}
 	}
 
+	if windowsProfile := s.ControlPlane.Spec.WindowsProfile; windowsProfile != nil {
+		managedClusterSpec.WindowsProfile = &managedclusters.WindowsProfile{
+			AdminUsername:       windowsProfile.AdminUsername,
+			AdminPassword:       s.windowsAdminPassword,
+			RotateAdminPassword: s.ControlPlane.Annotations[azure.WindowsAdminPasswordSecretVersionAnnotation] != s.windowsAdminPasswordSecretVersion,
+			LicenseType:         (*string)(windowsProfile.LicenseType),
+		}
+		if windowsProfile.GmsaProfile != nil {
+			managedClusterSpec.WindowsProfile.GmsaProfile = &managedclusters.GmsaProfile{
+				Enabled:        windowsProfile.GmsaProfile.Enabled,
+				DNSServer:      windowsProfile.GmsaProfile.DNSServer,
+				RootDomainName: windowsProfile.GmsaProfile.RootDomainName,
+			}
+		}
+	}
+
+	if s.ControlPlane.Spec.OIDCIssuerProfile != nil {
+		managedClusterSpec.OIDCIssuerProfile = &managedclusters.OIDCIssuerProfile{
+			Enabled: s.ControlPlane.Spec.OIDCIssuerProfile.Enabled,
+		}
+	}
+
 	return &managedClusterSpec
 }
 
+// InitWindowsAdminPassword reads the Windows admin password from the Secret referenced by the WindowsProfile.
+func (s *ManagedControlPlaneScope) InitWindowsAdminPassword(ctx context.Context) error {
+	windowsProfile := s.ControlPlane.Spec.WindowsProfile
+	if windowsProfile == nil {
+		return nil
+	}
+
+	passwordSecret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: s.ControlPlane.Namespace, Name: windowsProfile.AdminPasswordSecretRef.Name}
+	if err := s.Client.Get(ctx, key, passwordSecret); err != nil {
+		return errors.Wrapf(err, "failed to get Windows admin password secret %s", key)
+	}
+
+	password := passwordSecret.Data[infrav1.WindowsAdminPasswordSecretKey]
+	if len(password) == 0 {
+		return errors.Errorf("Windows admin password secret %s has no %q key", key, infrav1.WindowsAdminPasswordSecretKey)
+	}
+	s.windowsAdminPassword = string(password)
+	// The UID and resource version identify the version of the Secret the password was read from, so that rotations
+	// can be detected without storing anything derived from the password.
+	s.windowsAdminPasswordSecretVersion = fmt.Sprintf("%s/%s", passwordSecret.UID, passwordSecret.ResourceVersion)
+	return nil
+}
+
+// SetWindowsAdminPasswordApplied records the version of the Windows admin password Secret applied to the managed cluster.
+func (s *ManagedControlPlaneScope) SetWindowsAdminPasswordApplied() {
+	if s.ControlPlane.Spec.WindowsProfile == nil {
+		return
+	}
+	s.SetAnnotation(azure.WindowsAdminPasswordSecretVersionAnnotation, s.windowsAdminPasswordSecretVersion)
+}
+
 // GetAllAgentPoolSpecs gets a slice of azure.AgentPoolSpec for the list of agent pools.
 func (s *ManagedControlPlaneScope) GetAllAgentPoolSpecs() ([]azure.ResourceSpecGetter, error) {
 	var (
@@ -651,6 +721,21 @@ func (s *ManagedControlPlaneScope) SetKubeletIdentity(id string) {
 	s.ControlPlane.Spec.KubeletUserAssignedIdentity = id
 }
 
+// SetControlPlaneIdentityStatus sets the identity of the AKS control plane in the AzureManagedControlPlane status.
+func (s *ManagedControlPlaneScope) SetControlPlaneIdentityStatus(identity *infrav1.ManagedClusterIdentityStatus) {
+	s.ControlPlane.Status.ControlPlaneIdentity = identity
+}
+
+// SetKubeletIdentityStatus sets the kubelet identity of the AKS cluster in the AzureManagedControlPlane status.
+func (s *ManagedControlPlaneScope) SetKubeletIdentityStatus(identity *infrav1.ManagedClusterIdentityStatus) {
+	s.ControlPlane.Status.KubeletIdentity = identity
+}
+
+// SetOIDCIssuerProfileStatus sets the OIDC issuer of the AKS cluster in the AzureManagedControlPlane status.
+func (s *ManagedControlPlaneScope) SetOIDCIssuerProfileStatus(oidc *infrav1.OIDCIssuerProfileStatus) {
+	s.ControlPlane.Status.OIDCIssuerProfile = oidc
+}
+
 // SetLongRunningOperationState will set the future on the AzureManagedControlPlane status to allow the resource to continue
 // in the next reconciliation.
 func (s *ManagedControlPlaneScope) SetLongRunningOperationState(future *infrav1.Future) {
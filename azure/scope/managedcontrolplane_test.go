@@ -22,6 +22,7 @@ import (
 
 	"github.com/Azure/go-autorest/autorest"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/ptr"
@@ -31,6 +32,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -603,3 +605,100 @@ func TestManagedControlPlaneScope_IsVnetManagedCache(t *testing.T) {
 		})
 	}
 }
+
+func TestManagedControlPlaneScope_WindowsProfile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = expv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	newControlPlane := func() *infrav1.AzureManagedControlPlane {
+		return &infrav1.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+				UID:       "00000000-0000-0000-0000-000000000001",
+			},
+			Spec: infrav1.AzureManagedControlPlaneSpec{
+				Version:        "v1.20.1",
+				SubscriptionID: "00000000-0000-0000-0000-000000000000",
+				WindowsProfile: &infrav1.ManagedControlPlaneWindowsProfile{
+					AdminUsername:          "azureuser",
+					AdminPasswordSecretRef: corev1.LocalObjectReference{Name: "windows-password"},
+					LicenseType:            ptr.To(infrav1.ManagedControlPlaneLicenseTypeWindowsServer),
+				},
+			},
+		}
+	}
+	newPasswordSecret := func(password string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "windows-password",
+				Namespace: "default",
+			},
+			Data: map[string][]byte{
+				infrav1.WindowsAdminPasswordSecretKey: []byte(password),
+			},
+		}
+	}
+	newScope := func(g *WithT, objs ...client.Object) *ManagedControlPlaneScope {
+		controlPlane := newControlPlane()
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objs, controlPlane)...).Build()
+		s, err := NewManagedControlPlaneScope(context.TODO(), ManagedControlPlaneScopeParams{
+			AzureClients: AzureClients{
+				Authorizer: autorest.NullAuthorizer{},
+			},
+			Client: fakeClient,
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cluster1",
+					Namespace: "default",
+				},
+			},
+			ControlPlane: controlPlane,
+		})
+		g.Expect(err).NotTo(HaveOccurred())
+		return s
+	}
+	windowsProfile := func(s *ManagedControlPlaneScope) *managedclusters.WindowsProfile {
+		return s.ManagedClusterSpec().(*managedclusters.ManagedClusterSpec).WindowsProfile
+	}
+
+	t.Run("password is read from the secret and rotated once", func(t *testing.T) {
+		g := NewWithT(t)
+		s := newScope(g, newPasswordSecret("P@ssw0rd1"))
+		g.Expect(s.InitWindowsAdminPassword(context.TODO())).To(Succeed())
+
+		g.Expect(windowsProfile(s)).To(Equal(&managedclusters.WindowsProfile{
+			AdminUsername:       "azureuser",
+			AdminPassword:       "P@ssw0rd1",
+			RotateAdminPassword: true,
+			LicenseType:         ptr.To("Windows_Server"),
+		}))
+
+		s.SetWindowsAdminPasswordApplied()
+		g.Expect(s.ControlPlane.Annotations).To(HaveKey(azure.WindowsAdminPasswordSecretVersionAnnotation))
+		g.Expect(s.ControlPlane.Annotations[azure.WindowsAdminPasswordSecretVersionAnnotation]).NotTo(ContainSubstring("P@ssw0rd1"))
+		g.Expect(windowsProfile(s).RotateAdminPassword).To(BeFalse())
+
+		passwordSecret := &corev1.Secret{}
+		g.Expect(s.Client.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "windows-password"}, passwordSecret)).To(Succeed())
+		passwordSecret.Data[infrav1.WindowsAdminPasswordSecretKey] = []byte("P@ssw0rd2")
+		g.Expect(s.Client.Update(context.TODO(), passwordSecret)).To(Succeed())
+		g.Expect(s.InitWindowsAdminPassword(context.TODO())).To(Succeed())
+		g.Expect(windowsProfile(s).AdminPassword).To(Equal("P@ssw0rd2"))
+		g.Expect(windowsProfile(s).RotateAdminPassword).To(BeTrue())
+	})
+
+	t.Run("missing secret returns an error", func(t *testing.T) {
+		g := NewWithT(t)
+		s := newScope(g)
+		g.Expect(s.InitWindowsAdminPassword(context.TODO())).To(HaveOccurred())
+	})
+
+	t.Run("secret without a password returns an error", func(t *testing.T) {
+		g := NewWithT(t)
+		s := newScope(g, newPasswordSecret(""))
+		g.Expect(s.InitWindowsAdminPassword(context.TODO())).To(MatchError(ContainSubstring(`has no "password" key`)))
+	})
+}
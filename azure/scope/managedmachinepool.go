@@ -180,21 +180,25 @@ func buildAgentPoolSpec(managedControlPlane *infrav1.AzureManagedControlPlane,
 			managedControlPlane.Spec.VirtualNetwork.Name,
 			ptr.Deref(getAgentPoolSubnet(managedControlPlane, managedMachinePool), ""),
 		),
-		Mode:                 managedMachinePool.Spec.Mode,
-		MaxPods:              managedMachinePool.Spec.MaxPods,
-		AvailabilityZones:    managedMachinePool.Spec.AvailabilityZones,
-		OsDiskType:           managedMachinePool.Spec.OsDiskType,
-		EnableUltraSSD:       managedMachinePool.Spec.EnableUltraSSD,
-		Headers:              maps.FilterByKeyPrefix(agentPoolAnnotations, infrav1.CustomHeaderPrefix),
-		EnableNodePublicIP:   managedMachinePool.Spec.EnableNodePublicIP,
-		NodePublicIPPrefixID: managedMachinePool.Spec.NodePublicIPPrefixID,
-		ScaleSetPriority:     managedMachinePool.Spec.ScaleSetPriority,
-		ScaleDownMode:        managedMachinePool.Spec.ScaleDownMode,
-		SpotMaxPrice:         managedMachinePool.Spec.SpotMaxPrice,
-		AdditionalTags:       managedMachinePool.Spec.AdditionalTags,
-		KubeletDiskType:      managedMachinePool.Spec.KubeletDiskType,
-		LinuxOSConfig:        managedMachinePool.Spec.LinuxOSConfig,
-		EnableFIPS:           managedMachinePool.Spec.EnableFIPS,
+		Mode:                       managedMachinePool.Spec.Mode,
+		MaxPods:                    managedMachinePool.Spec.MaxPods,
+		AvailabilityZones:          managedMachinePool.Spec.AvailabilityZones,
+		OsDiskType:                 managedMachinePool.Spec.OsDiskType,
+		EnableUltraSSD:             managedMachinePool.Spec.EnableUltraSSD,
+		Headers:                    maps.FilterByKeyPrefix(agentPoolAnnotations, infrav1.CustomHeaderPrefix),
+		EnableNodePublicIP:         managedMachinePool.Spec.EnableNodePublicIP,
+		NodePublicIPPrefixID:       managedMachinePool.Spec.NodePublicIPPrefixID,
+		ProximityPlacementGroupID:  managedMachinePool.Spec.ProximityPlacementGroupID,
+		CapacityReservationGroupID: managedMachinePool.Spec.CapacityReservationGroupID,
+		HostGroupID:                managedMachinePool.Spec.HostGroupID,
+		ScaleSetPriority:           managedMachinePool.Spec.ScaleSetPriority,
+		ScaleDownMode:              managedMachinePool.Spec.ScaleDownMode,
+		PowerState:                 managedMachinePool.Spec.PowerState,
+		SpotMaxPrice:               managedMachinePool.Spec.SpotMaxPrice,
+		AdditionalTags:             managedMachinePool.Spec.AdditionalTags,
+		KubeletDiskType:            managedMachinePool.Spec.KubeletDiskType,
+		LinuxOSConfig:              managedMachinePool.Spec.LinuxOSConfig,
+		EnableFIPS:                 managedMachinePool.Spec.EnableFIPS,
 	}
 
 	if managedMachinePool.Spec.OSDiskSizeGB != nil {
@@ -0,0 +1,187 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// sharedVnetSubnetsRequeue is how long to wait before checking conflicting subnets again, as they may be released
+// by the deletion of the cluster that claimed them.
+const sharedVnetSubnetsRequeue = time.Minute
+
+// ValidateSharedVnetSubnets checks the subnets of the cluster against the subnets claimed by the other AzureClusters
+// sharing its VNet, before the subnets are created. A subnet shared by several clusters must have the same CIDR blocks
+// in all of them, and subnets with different names must not overlap. The subnets are claimed by recording them in the
+// SharedVnetSubnetsClaimedAnnotation once they were checked, and are not checked again until they change. Conflicts
+// are only reported against the clusters that claimed their subnets first and are not being deleted, so that the
+// cluster that claimed a CIDR block first keeps it.
+func (s *ClusterScope) ValidateSharedVnetSubnets(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.ClusterScope.ValidateSharedVnetSubnets")
+	defer done()
+
+	claim := subnetsClaim(s.AzureCluster.Spec.NetworkSpec.Subnets)
+	if s.AzureCluster.Annotations[infrav1.SharedVnetSubnetsClaimedAnnotation] == claim {
+		return nil
+	}
+
+	clusters := &infrav1.AzureClusterList{}
+	if err := s.Client.List(ctx, clusters); err != nil {
+		return errors.Wrap(err, "failed to list AzureClusters")
+	}
+
+	var conflicts []string
+	for i := range clusters.Items {
+		other := &clusters.Items[i]
+		if other.UID == s.AzureCluster.UID || !other.DeletionTimestamp.IsZero() || !s.sharesVnetWith(other) || !s.claimedAfter(other) {
+			continue
+		}
+		log.V(4).Info("checking the subnets against an AzureCluster sharing the VNet", "namespace", other.Namespace, "name", other.Name)
+		conflicts = append(conflicts, subnetCIDRConflicts(s.AzureCluster.Spec.NetworkSpec.Subnets, other)...)
+	}
+
+	if len(conflicts) == 0 {
+		if s.AzureCluster.Annotations == nil {
+			s.AzureCluster.Annotations = map[string]string{}
+		}
+		s.AzureCluster.Annotations[infrav1.SharedVnetSubnetsClaimedAnnotation] = claim
+		return nil
+	}
+
+	message := strings.Join(conflicts, "; ")
+	conditions.MarkFalse(s.AzureCluster, infrav1.SubnetsReadyCondition, infrav1.SubnetCIDRConflictReason, clusterv1.ConditionSeverityError, message)
+	return azure.WithTransientError(errors.Errorf("subnets conflict with the subnets of other clusters sharing VNet %s: %s", s.Vnet().Name, message), sharedVnetSubnetsRequeue)
+}
+
+// subnetsClaim returns the value of the SharedVnetSubnetsClaimedAnnotation for the subnets, which lists the CIDR
+// blocks of each subnet.
+func subnetsClaim(subnets infrav1.Subnets) string {
+	claims := make([]string, 0, len(subnets))
+	for _, subnet := range subnets {
+		cidrBlocks := append([]string{}, subnet.CIDRBlocks...)
+		sort.Strings(cidrBlocks)
+		claims = append(claims, subnet.Name+"="+strings.Join(cidrBlocks, ","))
+	}
+	sort.Strings(claims)
+	return strings.Join(claims, ";")
+}
+
+// claimedAfter returns whether the cluster claimed its subnets after the other AzureCluster, i.e. whether the other
+// cluster has claimed its subnets and the cluster has not. When both clusters have claimed their subnets, e.g.
+// because they were checked at the same time, the cluster created first is considered to have claimed them first.
+func (s *ClusterScope) claimedAfter(other *infrav1.AzureCluster) bool {
+	if _, ok := other.Annotations[infrav1.SharedVnetSubnetsClaimedAnnotation]; !ok {
+		return false
+	}
+	if _, ok := s.AzureCluster.Annotations[infrav1.SharedVnetSubnetsClaimedAnnotation]; !ok {
+		return true
+	}
+	return createdBefore(other, s.AzureCluster)
+}
+
+// sharesVnetWith returns whether the other AzureCluster uses the same VNet as the cluster.
+func (s *ClusterScope) sharesVnetWith(other *infrav1.AzureCluster) bool {
+	otherVnet := other.Spec.NetworkSpec.Vnet
+	return strings.EqualFold(otherVnet.Name, s.Vnet().Name) &&
+		strings.EqualFold(otherVnet.ResourceGroup, s.Vnet().ResourceGroup) &&
+		strings.EqualFold(other.Spec.SubscriptionID, s.AzureCluster.Spec.SubscriptionID)
+}
+
+// createdBefore returns whether a was created before b. Clusters created at the same time are ordered by namespace
+// and name.
+func createdBefore(a, b *infrav1.AzureCluster) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+// subnetCIDRConflicts returns the conflicts between the subnets and the subnets of the other AzureCluster.
+func subnetCIDRConflicts(subnets infrav1.Subnets, other *infrav1.AzureCluster) []string {
+	var conflicts []string
+	for _, subnet := range subnets {
+		for _, otherSubnet := range other.Spec.NetworkSpec.Subnets {
+			if len(subnet.CIDRBlocks) == 0 || len(otherSubnet.CIDRBlocks) == 0 {
+				continue
+			}
+			if subnet.Name == otherSubnet.Name {
+				if !sameCIDRBlocks(subnet.CIDRBlocks, otherSubnet.CIDRBlocks) {
+					conflicts = append(conflicts, fmt.Sprintf("subnet %s has CIDR blocks %v, but AzureCluster %s/%s claims it with CIDR blocks %v",
+						subnet.Name, subnet.CIDRBlocks, other.Namespace, other.Name, otherSubnet.CIDRBlocks))
+				}
+				continue
+			}
+			if cidr, otherCIDR, ok := overlappingCIDRBlocks(subnet.CIDRBlocks, otherSubnet.CIDRBlocks); ok {
+				conflicts = append(conflicts, fmt.Sprintf("CIDR block %s of subnet %s overlaps CIDR block %s of subnet %s of AzureCluster %s/%s",
+					cidr, subnet.Name, otherCIDR, otherSubnet.Name, other.Namespace, other.Name))
+			}
+		}
+	}
+	return conflicts
+}
+
+// sameCIDRBlocks returns whether both lists contain the same CIDR blocks, in any order.
+func sameCIDRBlocks(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]string{}, a...), append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// overlappingCIDRBlocks returns the first pair of overlapping CIDR blocks of the two lists. Invalid CIDR blocks are
+// ignored as they are rejected by the AzureCluster webhook.
+func overlappingCIDRBlocks(a, b []string) (string, string, bool) {
+	for _, cidr := range a {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			continue
+		}
+		for _, otherCIDR := range b {
+			otherPrefix, err := netip.ParsePrefix(otherCIDR)
+			if err != nil {
+				continue
+			}
+			if prefix.Masked().Overlaps(otherPrefix.Masked()) {
+				return cidr, otherCIDR, true
+			}
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateSharedVnetSubnets(t *testing.T) {
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newAzureCluster := func(name string, age time.Duration, vnetName string, subnets ...infrav1.SubnetSpec) *infrav1.AzureCluster {
+		return &infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				UID:               types.UID(name),
+				CreationTimestamp: metav1.NewTime(created.Add(-age)),
+			},
+			Spec: infrav1.AzureClusterSpec{
+				AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+					SubscriptionID: "123",
+				},
+				NetworkSpec: infrav1.NetworkSpec{
+					Vnet: infrav1.VnetSpec{
+						Name:          vnetName,
+						ResourceGroup: "shared-rg",
+					},
+					Subnets: subnets,
+				},
+			},
+		}
+	}
+	newSubnet := func(name string, cidrBlocks ...string) infrav1.SubnetSpec {
+		return infrav1.SubnetSpec{SubnetClassSpec: infrav1.SubnetClassSpec{Name: name, CIDRBlocks: cidrBlocks}}
+	}
+	claimedAs := func(azureCluster *infrav1.AzureCluster, claim string) *infrav1.AzureCluster {
+		azureCluster.Annotations = map[string]string{infrav1.SharedVnetSubnetsClaimedAnnotation: claim}
+		return azureCluster
+	}
+	claimed := func(azureCluster *infrav1.AzureCluster) *infrav1.AzureCluster {
+		return claimedAs(azureCluster, subnetsClaim(azureCluster.Spec.NetworkSpec.Subnets))
+	}
+	deleting := func(azureCluster *infrav1.AzureCluster) *infrav1.AzureCluster {
+		azureCluster.Finalizers = []string{infrav1.ClusterFinalizer}
+		azureCluster.DeletionTimestamp = &metav1.Time{Time: created}
+		return azureCluster
+	}
+
+	tests := []struct {
+		name          string
+		azureCluster  *infrav1.AzureCluster
+		others        []*infrav1.AzureCluster
+		expectedError string
+	}{
+		{
+			name:         "no other cluster",
+			azureCluster: newAzureCluster("my-cluster", 0, "shared-vnet", newSubnet("node-subnet", "10.0.0.0/24")),
+		},
+		{
+			name:         "same subnet with the same CIDR blocks",
+			azureCluster: newAzureCluster("my-cluster", 0, "shared-vnet", newSubnet("node-subnet", "10.0.0.0/24")),
+			others: []*infrav1.AzureCluster{
+				claimed(newAzureCluster("other-cluster", time.Hour, "shared-vnet", newSubnet("node-subnet", "10.0.0.0/24"))),
+			},
+		},
+		{
+			name:         "overlapping subnets in another VNet",
+			azureCluster: newAzureCluster("my-cluster", 0, "shared-vnet", newSubnet("node-subnet", "10.0.0.0/24")),
+			others: []*infrav1.AzureCluster{
+				claimed(newAzureCluster("other-cluster", time.Hour, "other-vnet", newSubnet("other-subnet", "10.0.0.0/16"))),
+			},
+		},
+		{
+			name:         "overlapping subnets of a cluster that has not claimed them",
+			azureCluster: newAzureCluster("my-cluster", 0, "shared-vnet", newSubnet("node-subnet", "10.0.0.0/24")),
+			others: []*infrav1.AzureCluster{
+				newAzureCluster("other-cluster", time.Hour, "shared-vnet", newSubnet("other-subnet", "10.0.0.0/16")),
+			},
+		},
+		{
+			name:         "overlapping subnets of a cluster being deleted",
+			azureCluster: newAzureCluster("my-cluster", 0, "shared-vnet", newSubnet("node-subnet", "10.0.0.0/24")),
+			others: []*infrav1.AzureCluster{
+				deleting(claimed(newAzureCluster("other-cluster", time.Hour, "shared-vnet", newSubnet("other-subnet", "10.0.0.0/16")))),
+			},
+		},
+		{
+			name:         "overlapping subnets claimed by both clusters, the other cluster being created later",
+			azureCluster: claimedAs(newAzureCluster("my-cluster", 0, "shared-vnet", newSubnet("node-subnet", "10.0.0.0/25")), "node-subnet=10.0.0.0/24"),
+			others: []*infrav1.AzureCluster{
+				claimed(newAzureCluster("other-cluster", -time.Hour, "shared-vnet", newSubnet("other-subnet", "10.0.0.0/16"))),
+			},
+		},
+		{
+			name:         "subnets already claimed",
+			azureCluster: claimed(newAzureCluster("my-cluster", 0, "shared-vnet", newSubnet("node-subnet", "10.0.0.0/24"))),
+			others: []*infrav1.AzureCluster{
+				claimed(newAzureCluster("other-cluster", time.Hour, "shared-vnet", newSubnet("other-subnet", "10.0.0.0/16"))),
+			},
+		},
+		{
+			name:         "overlapping subnets",
+			azureCluster: newAzureCluster("my-cluster", 0, "shared-vnet", newSubnet("node-subnet", "10.0.0.0/24")),
+			others: []*infrav1.AzureCluster{
+				claimed(newAzureCluster("other-cluster", time.Hour, "shared-vnet", newSubnet("other-subnet", "10.0.0.0/16"))),
+			},
+			expectedError: "subnets conflict with the subnets of other clusters sharing VNet shared-vnet: CIDR block 10.0.0.0/24 of subnet node-subnet overlaps CIDR block 10.0.0.0/16 of subnet other-subnet of AzureCluster default/other-cluster",
+		},
+		{
+			name:         "same subnet with different CIDR blocks",
+			azureCluster: newAzureCluster("my-cluster", 0, "shared-vnet", newSubnet("node-subnet", "10.0.0.0/24")),
+			others: []*infrav1.AzureCluster{
+				claimed(newAzureCluster("other-cluster", time.Hour, "shared-vnet", newSubnet("node-subnet", "10.0.1.0/24"))),
+			},
+			expectedError: "subnets conflict with the subnets of other clusters sharing VNet shared-vnet: subnet node-subnet has CIDR blocks [10.0.0.0/24], but AzureCluster default/other-cluster claims it with CIDR blocks [10.0.1.0/24]",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+			initObjects := []runtime.Object{tc.azureCluster}
+			for _, other := range tc.others {
+				initObjects = append(initObjects, other)
+			}
+
+			s := &ClusterScope{
+				Client:       fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build(),
+				AzureCluster: tc.azureCluster,
+			}
+
+			err := s.ValidateSharedVnetSubnets(context.Background())
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedError)))
+				var reconcileErr azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileErr)).To(BeTrue())
+				g.Expect(reconcileErr.IsTransient()).To(BeTrue())
+				g.Expect(conditions.GetReason(tc.azureCluster, infrav1.SubnetsReadyCondition)).To(Equal(infrav1.SubnetCIDRConflictReason))
+				g.Expect(tc.azureCluster.Annotations).NotTo(HaveKey(infrav1.SharedVnetSubnetsClaimedAnnotation))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(tc.azureCluster.Annotations).To(HaveKeyWithValue(infrav1.SharedVnetSubnetsClaimedAnnotation, subnetsClaim(tc.azureCluster.Spec.NetworkSpec.Subnets)))
+			}
+		})
+	}
+}
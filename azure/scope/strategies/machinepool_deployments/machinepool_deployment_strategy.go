@@ -41,6 +41,12 @@ type (
 		SelectMachinesToDelete(ctx context.Context, desiredReplicas int32, machinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine) ([]infrav1exp.AzureMachinePoolMachine, error)
 	}
 
+	// ReimageSelector is the ability to select nodes to be reimaged to the latest model with respect to a desired
+	// number of replicas.
+	ReimageSelector interface {
+		SelectMachinesToReimage(ctx context.Context, desiredReplicas int32, machinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine) ([]infrav1exp.AzureMachinePoolMachine, error)
+	}
+
 	// TypedDeleteSelector is the ability to select nodes to be deleted with respect to a desired number of nodes, and
 	// the ability to describe the underlying type of the deployment strategy.
 	TypedDeleteSelector interface {
@@ -50,6 +56,14 @@ type (
 
 	rollingUpdateStrategy struct {
 		infrav1exp.MachineRollingUpdateDeployment
+
+		// upgradedByAzure is true when Azure updates the machines to the latest model according to the upgrade
+		// policy of the scale set.
+		upgradedByAzure bool
+
+		// rebalanceZones is true when the machines deleted when scaling in are taken from the zones with the most
+		// machines first.
+		rebalanceZones bool
 	}
 )
 
@@ -74,6 +88,28 @@ func NewMachinePoolDeploymentStrategy(strategy infrav1exp.AzureMachinePoolDeploy
 	}
 }
 
+// NewAzureUpgradedMachinePoolDeploymentStrategy constructs a strategy implementation described in the
+// AzureMachinePoolDeploymentStrategy specification for a scale set whose machines are updated to the latest model by
+// Azure. The strategy only scales the machine pool down and leaves the machines without the latest model to Azure.
+func NewAzureUpgradedMachinePoolDeploymentStrategy(strategy infrav1exp.AzureMachinePoolDeploymentStrategy) TypedDeleteSelector {
+	s := NewMachinePoolDeploymentStrategy(strategy)
+	if rollingUpdate, ok := s.(*rollingUpdateStrategy); ok {
+		rollingUpdate.upgradedByAzure = true
+	}
+
+	return s
+}
+
+// WithScaleInZoneRebalancing makes a strategy delete the machines from the zones with the most machines first when
+// the machine pool is over-provisioned, so the machines stay evenly distributed across zones.
+func WithScaleInZoneRebalancing(s TypedDeleteSelector) TypedDeleteSelector {
+	if rollingUpdate, ok := s.(*rollingUpdateStrategy); ok {
+		rollingUpdate.rebalanceZones = true
+	}
+
+	return s
+}
+
 // Type is the AzureMachinePoolDeploymentStrategyType for the strategy.
 func (rollingUpdateStrategy *rollingUpdateStrategy) Type() infrav1exp.AzureMachinePoolDeploymentStrategyType {
 	return infrav1exp.RollingUpdateAzureMachinePoolDeploymentStrategyType
@@ -81,6 +117,11 @@ func (rollingUpdateStrategy *rollingUpdateStrategy) Type() infrav1exp.AzureMachi
 
 // Surge calculates the number of replicas that can be added during an upgrade operation.
 func (rollingUpdateStrategy *rollingUpdateStrategy) Surge(desiredReplicaCount int) (int, error) {
+	// Reimaged machines and machines upgraded by Azure are updated in place, so no machines are added.
+	if rollingUpdateStrategy.ReplacePolicy == infrav1exp.ReimageReplacePolicyType || rollingUpdateStrategy.upgradedByAzure {
+		return 0, nil
+	}
+
 	if rollingUpdateStrategy.MaxSurge == nil {
 		return 1, nil
 	}
@@ -117,16 +158,7 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 	}
 
 	var (
-		order = func() func(machines []infrav1exp.AzureMachinePoolMachine) []infrav1exp.AzureMachinePoolMachine {
-			switch rollingUpdateStrategy.DeletePolicy {
-			case infrav1exp.OldestDeletePolicyType:
-				return orderByOldest
-			case infrav1exp.NewestDeletePolicyType:
-				return orderByNewest
-			default:
-				return orderRandom
-			}
-		}()
+		order                      = rollingUpdateStrategy.order()
 		log                        = ctrl.LoggerFrom(ctx).V(4)
 		failedMachines             = order(getFailedMachines(machinesByProviderID))
 		deletingMachines           = order(getDeletingMachines(machinesByProviderID))
@@ -165,15 +197,31 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 	}
 
 	// we have too many machines, let's choose the oldest to remove
+	if overProvisionCount > 0 && rollingUpdateStrategy.rebalanceZones {
+		log.Info("over-provisioned, rebalancing zones", "desiredReplicaCount", desiredReplicaCount, "overProvisionCount", overProvisionCount, "machinesWithoutLatestModel", getProviderIDs(machinesWithoutLatestModel), "readyMachines", getProviderIDs(readyMachines))
+		// old models are still removed first within each zone
+		candidates := append([]infrav1exp.AzureMachinePoolMachine{}, machinesWithoutLatestModel...)
+		for _, v := range readyMachines {
+			if v.Status.LatestModelApplied {
+				candidates = append(candidates, v)
+			}
+		}
+		return selectZoneBalanced(candidates, getNotReadyMachinesWithLatestModel(machinesByProviderID), overProvisionCount), nil
+	}
+
 	if overProvisionCount > 0 {
 		var toDelete []infrav1exp.AzureMachinePoolMachine
 		log.Info("over-provisioned", "desiredReplicaCount", desiredReplicaCount, "overProvisionCount", overProvisionCount, "machinesWithoutLatestModel", getProviderIDs(machinesWithoutLatestModel))
-		// we are over-provisioned try to remove old models
+		// we are over-provisioned try to remove old models, sparing the machines protected from scale-in
 		for _, v := range machinesWithoutLatestModel {
 			if len(toDelete) >= overProvisionCount {
 				return toDelete, nil
 			}
 
+			if v.IsProtectedFromScaleIn() {
+				continue
+			}
+
 			toDelete = append(toDelete, v)
 		}
 
@@ -184,6 +232,10 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 				return toDelete, nil
 			}
 
+			if v.IsProtectedFromScaleIn() {
+				continue
+			}
+
 			toDelete = append(toDelete, v)
 		}
 
@@ -195,6 +247,16 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 		return []infrav1exp.AzureMachinePoolMachine{}, nil
 	}
 
+	if rollingUpdateStrategy.upgradedByAzure {
+		log.Info("nothing more to do since the AzureMachinePoolMachine(s) without the latest model are upgraded by Azure")
+		return []infrav1exp.AzureMachinePoolMachine{}, nil
+	}
+
+	if rollingUpdateStrategy.ReplacePolicy == infrav1exp.ReimageReplacePolicyType {
+		log.Info("nothing more to do since the AzureMachinePoolMachine(s) without the latest model are reimaged instead of deleted")
+		return []infrav1exp.AzureMachinePoolMachine{}, nil
+	}
+
 	if disruptionBudget <= 0 {
 		log.Info("exit early since disruption budget is less than or equal to zero", "disruptionBudget", disruptionBudget, "desiredReplicaCount", desiredReplicaCount, "maxUnavailable", maxUnavailable, "readyMachines", getProviderIDs(readyMachines), "readyMachinesCount", len(readyMachines))
 		return []infrav1exp.AzureMachinePoolMachine{}, nil
@@ -207,7 +269,7 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 			return toDelete, nil
 		}
 
-		if !v.Status.LatestModelApplied {
+		if !v.Status.LatestModelApplied && !v.IsProtectedFromScaleSetActions() {
 			toDelete = append(toDelete, v)
 		}
 	}
@@ -216,6 +278,73 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 	return toDelete, nil
 }
 
+// SelectMachinesToReimage selects the machines without the latest model to reimage when the ReplacePolicy is Reimage.
+// Machines that are being reimaged count against the disruption budget, and at least one machine is reimaged at a time.
+func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToReimage(ctx context.Context, desiredReplicaCount int32, machinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine) ([]infrav1exp.AzureMachinePoolMachine, error) {
+	ctx, _, done := tele.StartSpanWithLogger(
+		ctx,
+		"strategies.rollingUpdateStrategy.SelectMachinesToReimage",
+	)
+	defer done()
+
+	if rollingUpdateStrategy.ReplacePolicy != infrav1exp.ReimageReplacePolicyType || rollingUpdateStrategy.upgradedByAzure {
+		return []infrav1exp.AzureMachinePoolMachine{}, nil
+	}
+
+	maxUnavailable, err := rollingUpdateStrategy.maxUnavailable(int(desiredReplicaCount))
+	if err != nil {
+		return nil, err
+	}
+	// Reimaging doesn't surge, so at least one machine has to be allowed to be unavailable to make progress.
+	if maxUnavailable < 1 {
+		maxUnavailable = 1
+	}
+
+	var (
+		log              = ctrl.LoggerFrom(ctx).V(4)
+		failedMachines   = getFailedMachines(machinesByProviderID)
+		deletingMachines = getDeletingMachines(machinesByProviderID)
+		readyMachines    = rollingUpdateStrategy.order()(getReadyMachinesNotReimaging(machinesByProviderID))
+		disruptionBudget = maxUnavailable - (int(desiredReplicaCount) - len(readyMachines))
+	)
+
+	if len(failedMachines) > 0 || len(deletingMachines) > 0 {
+		log.Info("exit early since there are failed or deleting machines", "failedMachines", getProviderIDs(failedMachines), "deletingMachines", getProviderIDs(deletingMachines))
+		return []infrav1exp.AzureMachinePoolMachine{}, nil
+	}
+
+	if disruptionBudget <= 0 {
+		log.Info("exit early since disruption budget is less than or equal to zero", "disruptionBudget", disruptionBudget, "desiredReplicaCount", desiredReplicaCount, "maxUnavailable", maxUnavailable, "readyMachinesCount", len(readyMachines))
+		return []infrav1exp.AzureMachinePoolMachine{}, nil
+	}
+
+	var toReimage []infrav1exp.AzureMachinePoolMachine
+	for _, v := range readyMachines {
+		if len(toReimage) >= disruptionBudget {
+			break
+		}
+
+		if !v.Status.LatestModelApplied && !v.IsProtectedFromScaleSetActions() {
+			toReimage = append(toReimage, v)
+		}
+	}
+
+	log.Info("selected machines to reimage", "toReimage", getProviderIDs(toReimage), "disruptionBudget", disruptionBudget)
+	return toReimage, nil
+}
+
+// order returns the function ordering machines according to the DeletePolicy.
+func (rollingUpdateStrategy rollingUpdateStrategy) order() func(machines []infrav1exp.AzureMachinePoolMachine) []infrav1exp.AzureMachinePoolMachine {
+	switch rollingUpdateStrategy.DeletePolicy {
+	case infrav1exp.OldestDeletePolicyType:
+		return orderByOldest
+	case infrav1exp.NewestDeletePolicyType:
+		return orderByNewest
+	default:
+		return orderRandom
+	}
+}
+
 func getFailedMachines(machinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine) []infrav1exp.AzureMachinePoolMachine {
 	var machines []infrav1exp.AzureMachinePoolMachine
 	for _, v := range machinesByProviderID {
@@ -248,13 +377,7 @@ func getDeletingMachines(machinesByProviderID map[string]infrav1exp.AzureMachine
 func getReadyMachines(machinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine) []infrav1exp.AzureMachinePoolMachine {
 	var readyMachines []infrav1exp.AzureMachinePoolMachine
 	for _, v := range machinesByProviderID {
-		// ready status, with provisioning state Succeeded, and not marked for delete
-		if v.Status.Ready &&
-			(v.Status.ProvisioningState != nil && *v.Status.ProvisioningState == infrav1.Succeeded) &&
-			// Don't include machines that have already been marked for delete
-			v.DeletionTimestamp.IsZero() &&
-			// Don't include machines whose VMs are in an active state of deleting
-			*v.Status.ProvisioningState != infrav1.Deleting {
+		if isReadyMachine(v) {
 			readyMachines = append(readyMachines, v)
 		}
 	}
@@ -262,6 +385,41 @@ func getReadyMachines(machinesByProviderID map[string]infrav1exp.AzureMachinePoo
 	return readyMachines
 }
 
+func isReadyMachine(v infrav1exp.AzureMachinePoolMachine) bool {
+	// ready status, with provisioning state Succeeded, and not marked for delete
+	return v.Status.Ready &&
+		(v.Status.ProvisioningState != nil && *v.Status.ProvisioningState == infrav1.Succeeded) &&
+		// Don't include machines that have already been marked for delete
+		v.DeletionTimestamp.IsZero() &&
+		// Don't include machines whose VMs are in an active state of deleting
+		*v.Status.ProvisioningState != infrav1.Deleting
+}
+
+// getNotReadyMachinesWithLatestModel returns the machines with the latest model that are not ready yet, e.g. because
+// their instances are still being created.
+func getNotReadyMachinesWithLatestModel(machinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine) []infrav1exp.AzureMachinePoolMachine {
+	var machines []infrav1exp.AzureMachinePoolMachine
+	for _, v := range machinesByProviderID {
+		if v.Status.LatestModelApplied && !isReadyMachine(v) {
+			machines = append(machines, v)
+		}
+	}
+
+	return machines
+}
+
+// getReadyMachinesNotReimaging returns the ready machines that are not marked to be reimaged.
+func getReadyMachinesNotReimaging(machinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine) []infrav1exp.AzureMachinePoolMachine {
+	var machines []infrav1exp.AzureMachinePoolMachine
+	for _, v := range getReadyMachines(machinesByProviderID) {
+		if _, ok := v.Annotations[infrav1exp.ReimageAnnotation]; !ok {
+			machines = append(machines, v)
+		}
+	}
+
+	return machines
+}
+
 func getMachinesWithoutLatestModel(machinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine) []infrav1exp.AzureMachinePoolMachine {
 	var machinesWithLatestModel []infrav1exp.AzureMachinePoolMachine
 	for _, v := range machinesByProviderID {
@@ -296,6 +454,56 @@ func orderRandom(machines []infrav1exp.AzureMachinePoolMachine) []infrav1exp.Azu
 	return machines
 }
 
+// selectZoneBalanced selects count machines to delete, one at a time from the zone with the most machines left, so
+// that the remaining machines are evenly distributed across zones. Within a zone, machines are selected in the order
+// of the given list. Machines protected from scale-in are never selected, but count towards the size of their zone,
+// as do the pending machines, which will join their zone once ready.
+func selectZoneBalanced(machines, pending []infrav1exp.AzureMachinePoolMachine, count int) []infrav1exp.AzureMachinePoolMachine {
+	var (
+		zones      []string
+		zoneSizes  = map[string]int{}
+		candidates = map[string][]infrav1exp.AzureMachinePoolMachine{}
+	)
+	countMachine := func(machine infrav1exp.AzureMachinePoolMachine) string {
+		zone := machine.Status.Zone
+		if _, ok := zoneSizes[zone]; !ok {
+			zones = append(zones, zone)
+		}
+		zoneSizes[zone]++
+		return zone
+	}
+	for _, machine := range machines {
+		zone := countMachine(machine)
+		if !machine.IsProtectedFromScaleIn() {
+			candidates[zone] = append(candidates[zone], machine)
+		}
+	}
+	for _, machine := range pending {
+		countMachine(machine)
+	}
+
+	var toDelete []infrav1exp.AzureMachinePoolMachine
+	for len(toDelete) < count {
+		// machines of instances without a zone are grouped under the empty zone
+		largest := -1
+		for i, zone := range zones {
+			if len(candidates[zone]) > 0 && (largest < 0 || zoneSizes[zone] > zoneSizes[zones[largest]]) {
+				largest = i
+			}
+		}
+		if largest < 0 {
+			break
+		}
+
+		zone := zones[largest]
+		toDelete = append(toDelete, candidates[zone][0])
+		candidates[zone] = candidates[zone][1:]
+		zoneSizes[zone]--
+	}
+
+	return toDelete
+}
+
 func getProviderIDs(machines []infrav1exp.AzureMachinePoolMachine) []string {
 	ids := make([]string, len(machines))
 	for i, machine := range machines {
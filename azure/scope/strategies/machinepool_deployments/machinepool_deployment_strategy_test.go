@@ -85,6 +85,26 @@ func TestMachinePoolRollingUpdateStrategy_Surge(t *testing.T) {
 			desiredReplicas: 21,
 			want:            5,
 		},
+		{
+			name: "ReplacePolicy is Reimage; does not surge",
+			strategy: &rollingUpdateStrategy{
+				MachineRollingUpdateDeployment: infrav1exp.MachineRollingUpdateDeployment{
+					MaxSurge:      &two,
+					ReplacePolicy: infrav1exp.ReimageReplacePolicyType,
+				},
+			},
+			want: 0,
+		},
+		{
+			name: "Machines are upgraded by Azure; does not surge",
+			strategy: NewAzureUpgradedMachinePoolDeploymentStrategy(infrav1exp.AzureMachinePoolDeploymentStrategy{
+				Type: infrav1exp.RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &infrav1exp.MachineRollingUpdateDeployment{
+					MaxSurge: &two,
+				},
+			}).(Surger),
+			want: 0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -380,6 +400,99 @@ func TestMachinePoolRollingUpdateStrategy_SelectMachinesToDelete(t *testing.T) {
 			},
 			want: BeEmpty(),
 		},
+		{
+			name:            "if over-provisioned, do not select machines protected from scale-in",
+			strategy:        makeRollingUpdateStrategy(infrav1exp.MachineRollingUpdateDeployment{}),
+			desiredReplicas: 2,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, ProtectionPolicy: &infrav1exp.InstanceProtectionPolicy{ProtectFromScaleIn: true}}),
+				"bin": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded}),
+				"baz": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, ProtectionPolicy: &infrav1exp.InstanceProtectionPolicy{ProtectFromScaleSetActions: true}}),
+			},
+			want: Equal([]infrav1exp.AzureMachinePoolMachine{
+				makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded}),
+			}),
+		},
+		{
+			name:            "do not replace machines with an out-of-date model that are protected from scale set actions",
+			strategy:        makeRollingUpdateStrategy(infrav1exp.MachineRollingUpdateDeployment{MaxUnavailable: &two}),
+			desiredReplicas: 2,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, ProtectionPolicy: &infrav1exp.InstanceProtectionPolicy{ProtectFromScaleSetActions: true}}),
+				"bin": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+			},
+			want: Equal([]infrav1exp.AzureMachinePoolMachine{
+				makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+			}),
+		},
+		{
+			name:            "if over-provisioned with zone rebalancing, select machines from the zones with the most machines",
+			strategy:        WithScaleInZoneRebalancing(NewMachinePoolDeploymentStrategy(infrav1exp.AzureMachinePoolDeploymentStrategy{RollingUpdate: &infrav1exp.MachineRollingUpdateDeployment{DeletePolicy: infrav1exp.OldestDeletePolicyType}})),
+			desiredReplicas: 3,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, Zone: "1", CreationTime: metav1.NewTime(baseTime.Add(1 * time.Hour))}),
+				"bin": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, Zone: "2", CreationTime: metav1.NewTime(baseTime.Add(2 * time.Hour))}),
+				"baz": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, Zone: "2", CreationTime: metav1.NewTime(baseTime.Add(3 * time.Hour))}),
+				"bar": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, Zone: "2", CreationTime: metav1.NewTime(baseTime.Add(4 * time.Hour))}),
+				"qux": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, Zone: "3", CreationTime: metav1.NewTime(baseTime.Add(5 * time.Hour))}),
+			},
+			want: gomega.DiffEq([]infrav1exp.AzureMachinePoolMachine{
+				makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, Zone: "2", CreationTime: metav1.NewTime(baseTime.Add(2 * time.Hour))}),
+				makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, Zone: "2", CreationTime: metav1.NewTime(baseTime.Add(3 * time.Hour))}),
+			}),
+		},
+		{
+			name:            "if over-provisioned with zone rebalancing, count the machines with the latest model that are not ready yet",
+			strategy:        WithScaleInZoneRebalancing(NewMachinePoolDeploymentStrategy(infrav1exp.AzureMachinePoolDeploymentStrategy{RollingUpdate: &infrav1exp.MachineRollingUpdateDeployment{DeletePolicy: infrav1exp.OldestDeletePolicyType}})),
+			desiredReplicas: 2,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, Zone: "1", CreationTime: metav1.NewTime(baseTime.Add(1 * time.Hour))}),
+				"bin": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, Zone: "2", CreationTime: metav1.NewTime(baseTime.Add(2 * time.Hour))}),
+				"baz": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, Zone: "2", CreationTime: metav1.NewTime(baseTime.Add(3 * time.Hour))}),
+				"bar": makeAMPM(ampmOptions{Ready: false, LatestModel: true, ProvisioningState: infrav1.Creating, Zone: "1", CreationTime: metav1.NewTime(baseTime.Add(4 * time.Hour))}),
+				"qux": makeAMPM(ampmOptions{Ready: false, LatestModel: true, ProvisioningState: infrav1.Creating, Zone: "1", CreationTime: metav1.NewTime(baseTime.Add(5 * time.Hour))}),
+			},
+			want: gomega.DiffEq([]infrav1exp.AzureMachinePoolMachine{
+				makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, Zone: "1", CreationTime: metav1.NewTime(baseTime.Add(1 * time.Hour))}),
+			}),
+		},
+		{
+			name:            "if over-provisioned with zone rebalancing, spare the machines protected from scale-in",
+			strategy:        WithScaleInZoneRebalancing(NewMachinePoolDeploymentStrategy(infrav1exp.AzureMachinePoolDeploymentStrategy{RollingUpdate: &infrav1exp.MachineRollingUpdateDeployment{DeletePolicy: infrav1exp.OldestDeletePolicyType}})),
+			desiredReplicas: 2,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, Zone: "1", CreationTime: metav1.NewTime(baseTime.Add(1 * time.Hour))}),
+				"bin": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, Zone: "2", CreationTime: metav1.NewTime(baseTime.Add(2 * time.Hour)), ProtectionPolicy: &infrav1exp.InstanceProtectionPolicy{ProtectFromScaleIn: true}}),
+				"baz": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, Zone: "2", CreationTime: metav1.NewTime(baseTime.Add(3 * time.Hour)), ProtectionPolicy: &infrav1exp.InstanceProtectionPolicy{ProtectFromScaleIn: true}}),
+			},
+			want: gomega.DiffEq([]infrav1exp.AzureMachinePoolMachine{
+				makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, Zone: "1", CreationTime: metav1.NewTime(baseTime.Add(1 * time.Hour))}),
+			}),
+		},
+		{
+			name:            "if machines are upgraded by Azure, do not delete machines with the latest model == false",
+			strategy:        NewAzureUpgradedMachinePoolDeploymentStrategy(infrav1exp.AzureMachinePoolDeploymentStrategy{RollingUpdate: &infrav1exp.MachineRollingUpdateDeployment{MaxUnavailable: &two}}),
+			desiredReplicas: 3,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded}),
+				"bin": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+				"baz": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+			},
+			want: BeEmpty(),
+		},
+		{
+			name:            "if machines are upgraded by Azure and over-provisioned, select a machine with an out-of-date model",
+			strategy:        NewAzureUpgradedMachinePoolDeploymentStrategy(infrav1exp.AzureMachinePoolDeploymentStrategy{}),
+			desiredReplicas: 2,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded}),
+				"bin": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded}),
+				"baz": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+			},
+			want: Equal([]infrav1exp.AzureMachinePoolMachine{
+				makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+			}),
+		},
 	}
 
 	for _, tt := range tests {
@@ -396,6 +509,101 @@ func TestMachinePoolRollingUpdateStrategy_SelectMachinesToDelete(t *testing.T) {
 	}
 }
 
+func TestMachinePoolRollingUpdateStrategy_SelectMachinesToReimage(t *testing.T) {
+	var (
+		zero      = intstr.FromInt(0)
+		two       = intstr.FromInt(2)
+		succeeded = infrav1.Succeeded
+		baseTime  = time.Now().Add(-24 * time.Hour).Truncate(time.Microsecond)
+	)
+
+	tests := []struct {
+		name            string
+		strategy        ReimageSelector
+		input           map[string]infrav1exp.AzureMachinePoolMachine
+		desiredReplicas int32
+		want            types.GomegaMatcher
+	}{
+		{
+			name:            "should not select machines to reimage if the ReplacePolicy is Recreate",
+			strategy:        makeRollingUpdateStrategy(infrav1exp.MachineRollingUpdateDeployment{MaxUnavailable: &two}),
+			desiredReplicas: 2,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+				"bin": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+			},
+			want: BeEmpty(),
+		},
+		{
+			name:            "if maxUnavailable is 0, reimage 1 machine at a time",
+			strategy:        makeRollingUpdateStrategy(infrav1exp.MachineRollingUpdateDeployment{MaxUnavailable: &zero, ReplacePolicy: infrav1exp.ReimageReplacePolicyType, DeletePolicy: infrav1exp.OldestDeletePolicyType}),
+			desiredReplicas: 3,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(1 * time.Hour))}),
+				"bin": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(2 * time.Hour))}),
+				"baz": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(3 * time.Hour))}),
+			},
+			want: gomega.DiffEq([]infrav1exp.AzureMachinePoolMachine{
+				makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(1 * time.Hour))}),
+			}),
+		},
+		{
+			name:            "if maxUnavailable is 2, and there are 2 with the latest model == false, reimage 2",
+			strategy:        makeRollingUpdateStrategy(infrav1exp.MachineRollingUpdateDeployment{MaxUnavailable: &two, ReplacePolicy: infrav1exp.ReimageReplacePolicyType}),
+			desiredReplicas: 3,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded}),
+				"bin": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+				"baz": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+			},
+			want: HaveLen(2),
+		},
+		{
+			name:            "should not select machines protected from scale set actions to reimage",
+			strategy:        makeRollingUpdateStrategy(infrav1exp.MachineRollingUpdateDeployment{MaxUnavailable: &two, ReplacePolicy: infrav1exp.ReimageReplacePolicyType}),
+			desiredReplicas: 2,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, ProtectionPolicy: &infrav1exp.InstanceProtectionPolicy{ProtectFromScaleSetActions: true}}),
+				"bin": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+			},
+			want: Equal([]infrav1exp.AzureMachinePoolMachine{
+				makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+			}),
+		},
+		{
+			name:            "machines being reimaged count against maxUnavailable",
+			strategy:        makeRollingUpdateStrategy(infrav1exp.MachineRollingUpdateDeployment{MaxUnavailable: &zero, ReplacePolicy: infrav1exp.ReimageReplacePolicyType}),
+			desiredReplicas: 3,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, Reimage: true}),
+				"bin": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+				"baz": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+			},
+			want: BeEmpty(),
+		},
+		{
+			name:            "should not select machines to reimage if a machine is failed",
+			strategy:        makeRollingUpdateStrategy(infrav1exp.MachineRollingUpdateDeployment{MaxUnavailable: &two, ReplacePolicy: infrav1exp.ReimageReplacePolicyType}),
+			desiredReplicas: 3,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: false, LatestModel: false, ProvisioningState: infrav1.Failed}),
+				"bin": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+				"baz": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+			},
+			want: BeEmpty(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := tt.strategy.SelectMachinesToReimage(context.Background(), tt.desiredReplicas, tt.input)
+			g.Expect(err).To(Succeed())
+			g.Expect(got).To(tt.want)
+		})
+	}
+}
+
 func makeRollingUpdateStrategy(rolling infrav1exp.MachineRollingUpdateDeployment) *rollingUpdateStrategy {
 	return &rollingUpdateStrategy{
 		MachineRollingUpdateDeployment: rolling,
@@ -408,18 +616,29 @@ type ampmOptions struct {
 	ProvisioningState infrav1.ProvisioningState
 	CreationTime      metav1.Time
 	DeletionTime      *metav1.Time
+	Reimage           bool
+	ProtectionPolicy  *infrav1exp.InstanceProtectionPolicy
+	Zone              string
 }
 
 func makeAMPM(opts ampmOptions) infrav1exp.AzureMachinePoolMachine {
-	return infrav1exp.AzureMachinePoolMachine{
+	ampm := infrav1exp.AzureMachinePoolMachine{
 		ObjectMeta: metav1.ObjectMeta{
 			CreationTimestamp: opts.CreationTime,
 			DeletionTimestamp: opts.DeletionTime,
 		},
+		Spec: infrav1exp.AzureMachinePoolMachineSpec{
+			ProtectionPolicy: opts.ProtectionPolicy,
+		},
 		Status: infrav1exp.AzureMachinePoolMachineStatus{
 			Ready:              opts.Ready,
 			LatestModelApplied: opts.LatestModel,
 			ProvisioningState:  &opts.ProvisioningState,
+			Zone:               opts.Zone,
 		},
 	}
+	if opts.Reimage {
+		ampm.Annotations = map[string]string{infrav1exp.ReimageAnnotation: "true"}
+	}
+	return ampm
 }
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ultraSSDZonesGetter looks up Ultra disk zone support in the cached resource SKUs of the AzureMachine's cluster location.
+type ultraSSDZonesGetter struct {
+	client client.Client
+}
+
+// NewUltraSSDZonesGetter returns an infrav1.UltraSSDZonesGetter backed by the resource SKU cache.
+func NewUltraSSDZonesGetter(c client.Client) infrav1.UltraSSDZonesGetter {
+	return &ultraSSDZonesGetter{client: c}
+}
+
+// UltraSSDZones returns the location of the AzureMachine's cluster and the zones of that location in which
+// the AzureMachine's VM size supports Ultra disks.
+func (g *ultraSSDZonesGetter) UltraSSDZones(ctx context.Context, machine *infrav1.AzureMachine) (string, []string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.ultraSSDZonesGetter.UltraSSDZones")
+	defer done()
+
+	cluster, err := util.GetClusterFromMetadata(ctx, g.client, machine.ObjectMeta)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to get owner cluster")
+	}
+	if cluster.Spec.InfrastructureRef == nil || cluster.Spec.InfrastructureRef.Kind != "AzureCluster" {
+		return "", nil, errors.New("owner cluster is not backed by an AzureCluster")
+	}
+
+	azureCluster := &infrav1.AzureCluster{}
+	key := client.ObjectKey{Namespace: machine.Namespace, Name: cluster.Spec.InfrastructureRef.Name}
+	if err := g.client.Get(ctx, key, azureCluster); err != nil {
+		return "", nil, errors.Wrap(err, "failed to get AzureCluster")
+	}
+
+	clusterScope, err := NewClusterScope(ctx, ClusterScopeParams{
+		Client:       g.client,
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+	})
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to create cluster scope")
+	}
+
+	location := clusterScope.Location()
+	skuCache, err := resourceskus.GetCache(clusterScope, location)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to init resourceskus cache")
+	}
+
+	sku, err := skuCache.Get(ctx, machine.Spec.VMSize, resourceskus.VirtualMachines)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to get SKU %s", machine.Spec.VMSize)
+	}
+
+	return location, sku.GetZonesWithLocationCapability(resourceskus.UltraSSDAvailable, location), nil
+}
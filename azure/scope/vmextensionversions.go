@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensionimages"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// vmExtensionVersionsGetter looks up the versions of VM extension images in the cache of the location of the cluster
+// of an AzureMachine or AzureMachinePool.
+type vmExtensionVersionsGetter struct {
+	client client.Client
+}
+
+// NewVMExtensionVersionsGetter returns an infrav1.VMExtensionVersionsGetter backed by the VM extension images cache.
+func NewVMExtensionVersionsGetter(c client.Client) infrav1.VMExtensionVersionsGetter {
+	return &vmExtensionVersionsGetter{client: c}
+}
+
+// VMExtensionVersions returns the location of the cluster of the object and the versions of the VM extension type
+// that the publisher offers in that location.
+func (g *vmExtensionVersionsGetter) VMExtensionVersions(ctx context.Context, obj metav1.ObjectMeta, publisher, extensionType string) (string, []string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.vmExtensionVersionsGetter.VMExtensionVersions")
+	defer done()
+
+	cluster, err := util.GetClusterFromMetadata(ctx, g.client, obj)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to get owner cluster")
+	}
+	if cluster.Spec.InfrastructureRef == nil || cluster.Spec.InfrastructureRef.Kind != "AzureCluster" {
+		return "", nil, errors.New("owner cluster is not backed by an AzureCluster")
+	}
+
+	azureCluster := &infrav1.AzureCluster{}
+	key := client.ObjectKey{Namespace: obj.Namespace, Name: cluster.Spec.InfrastructureRef.Name}
+	if err := g.client.Get(ctx, key, azureCluster); err != nil {
+		return "", nil, errors.Wrap(err, "failed to get AzureCluster")
+	}
+
+	clusterScope, err := NewClusterScope(ctx, ClusterScopeParams{
+		Client:       g.client,
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+	})
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to create cluster scope")
+	}
+
+	location := clusterScope.Location()
+	extensionImages, err := vmextensionimages.GetCache(clusterScope, location)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to init VM extension images cache")
+	}
+
+	versions, err := extensionImages.Versions(ctx, publisher, extensionType)
+	if err != nil {
+		return "", nil, err
+	}
+	return location, versions, nil
+}
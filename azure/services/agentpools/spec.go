@@ -19,9 +19,11 @@ package agentpools
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2022-03-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/preview/containerservice/mgmt/2022-03-02-preview/containerservice"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -30,6 +32,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/slice"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -127,12 +130,24 @@ type AgentPoolSpec struct {
 	// NodePublicIPPrefixID specifies the public IP prefix resource ID which VM nodes should use IPs from.
 	NodePublicIPPrefixID *string `json:"nodePublicIPPrefixID,omitempty"`
 
+	// ProximityPlacementGroupID specifies the resource ID of the proximity placement group the nodes are placed in.
+	ProximityPlacementGroupID *string `json:"proximityPlacementGroupID,omitempty"`
+
+	// CapacityReservationGroupID specifies the resource ID of the capacity reservation group the nodes consume reserved capacity from.
+	CapacityReservationGroupID *string `json:"capacityReservationGroupID,omitempty"`
+
+	// HostGroupID specifies the resource ID of the dedicated host group the nodes are placed on.
+	HostGroupID *string `json:"hostGroupID,omitempty"`
+
 	// ScaleSetPriority specifies the ScaleSetPriority for the node pool. Allowed values are 'Spot' and 'Regular'
 	ScaleSetPriority *string `json:"scaleSetPriority,omitempty"`
 
 	// ScaleDownMode affects the cluster autoscaler behavior. Allowed values are 'Deallocate' and 'Delete'
 	ScaleDownMode *string `json:"scaleDownMode,omitempty"`
 
+	// PowerState specifies whether the nodes of the agent pool are running or stopped. Allowed values are 'Running' and 'Stopped'
+	PowerState *infrav1.AgentPoolPowerState `json:"powerState,omitempty"`
+
 	// SpotMaxPrice defines max price to pay for spot instance. Allowed values are any decimal value greater than zero or -1 which indicates the willingness to pay any on-demand price.
 	SpotMaxPrice *resource.Quantity `json:"spotMaxPrice,omitempty"`
 
@@ -178,6 +193,7 @@ func (s *AgentPoolSpec) Parameters(ctx context.Context, existing interface{}) (p
 	defer done()
 
 	nodeLabels := s.NodeLabels
+	nodeTaints := s.NodeTaints
 	if existing != nil {
 		existingPool, ok := existing.(containerservice.AgentPool)
 		if !ok {
@@ -201,7 +217,7 @@ func (s *AgentPoolSpec) Parameters(ctx context.Context, existing interface{}) (p
 				MinCount:            existingPool.MinCount,
 				MaxCount:            existingPool.MaxCount,
 				NodeLabels:          existingPool.NodeLabels,
-				NodeTaints:          existingPool.NodeTaints,
+				NodeTaints:          sortedNodeTaints(ptr.Deref(existingPool.NodeTaints, nil)),
 				Tags:                existingPool.Tags,
 				ScaleDownMode:       existingPool.ScaleDownMode,
 				SpotMaxPrice:        existingPool.SpotMaxPrice,
@@ -217,15 +233,10 @@ func (s *AgentPoolSpec) Parameters(ctx context.Context, existing interface{}) (p
 				EnableAutoScaling:   ptr.To(s.EnableAutoScaling),
 				MinCount:            s.MinCount,
 				MaxCount:            s.MaxCount,
-				NodeLabels:          s.NodeLabels,
-				NodeTaints:          &s.NodeTaints,
 				ScaleDownMode:       containerservice.ScaleDownMode(ptr.Deref(s.ScaleDownMode, "")),
 				Tags:                converters.TagsToMap(s.AdditionalTags),
 			},
 		}
-		if len(*normalizedProfile.NodeTaints) == 0 {
-			normalizedProfile.NodeTaints = nil
-		}
 
 		if s.SpotMaxPrice != nil {
 			normalizedProfile.SpotMaxPrice = ptr.To[float64](s.SpotMaxPrice.AsApproximateFloat64())
@@ -254,13 +265,24 @@ func (s *AgentPoolSpec) Parameters(ctx context.Context, existing interface{}) (p
 			normalizedProfile.Count = existingProfile.Count
 		}
 
-		// We do a just-in-time merge of existent kubernetes.azure.com-prefixed labels
-		// So that we don't unintentionally delete them
+		// The power state is only diffed when it is set, so that pools started or stopped outside of CAPZ are left as is.
+		if s.PowerState != nil {
+			normalizedProfile.PowerState = &containerservice.PowerState{Code: containerservice.Code(*s.PowerState)}
+			existingProfile.PowerState = existingPool.PowerState
+		}
+
+		// We do a just-in-time merge of existent kubernetes.azure.com-prefixed labels and taints, which AKS adds
+		// itself, so that we don't unintentionally delete them and don't see a diff for them on every reconcile.
 		// See https://github.com/Azure/AKS/issues/3152
-		if normalizedProfile.NodeLabels != nil {
-			nodeLabels = mergeSystemNodeLabels(normalizedProfile.NodeLabels, existingPool.NodeLabels)
+		nodeLabels = mergeSystemNodeLabels(s.NodeLabels, existingPool.NodeLabels)
+		if len(nodeLabels) > 0 {
 			normalizedProfile.NodeLabels = nodeLabels
 		}
+		if len(existingProfile.NodeLabels) == 0 {
+			existingProfile.NodeLabels = nil
+		}
+		nodeTaints = mergeSystemNodeTaints(s.NodeTaints, ptr.Deref(existingPool.NodeTaints, nil))
+		normalizedProfile.NodeTaints = sortedNodeTaints(nodeTaints)
 
 		// Compute a diff to check if we require an update
 		diff := cmp.Diff(normalizedProfile, existingProfile)
@@ -276,9 +298,13 @@ func (s *AgentPoolSpec) Parameters(ctx context.Context, existing interface{}) (p
 	if len(s.AvailabilityZones) > 0 {
 		availabilityZones = &s.AvailabilityZones
 	}
-	var nodeTaints *[]string
-	if len(s.NodeTaints) > 0 {
-		nodeTaints = &s.NodeTaints
+	var agentPoolNodeTaints *[]string
+	switch {
+	case len(nodeTaints) > 0:
+		agentPoolNodeTaints = &nodeTaints
+	case existing != nil:
+		// Send an empty list, as AKS leaves the taints of an existing agent pool unchanged when they are omitted.
+		agentPoolNodeTaints = &[]string{}
 	}
 	var sku *string
 	if s.SKU != "" {
@@ -297,6 +323,11 @@ func (s *AgentPoolSpec) Parameters(ctx context.Context, existing interface{}) (p
 	if s.VnetSubnetID != "" {
 		vnetSubnetID = &s.VnetSubnetID
 	}
+	var powerState *containerservice.PowerState
+	if s.PowerState != nil && existing != nil {
+		// New agent pools are always created running, as AKS can only stop agent pools that were provisioned successfully.
+		powerState = &containerservice.PowerState{Code: containerservice.Code(*s.PowerState)}
+	}
 
 	var kubeletConfig *containerservice.KubeletConfig
 	if s.KubeletConfig != nil {
@@ -358,43 +389,50 @@ func (s *AgentPoolSpec) Parameters(ctx context.Context, existing interface{}) (p
 
 	agentPool := containerservice.AgentPool{
 		ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
-			AvailabilityZones:    availabilityZones,
-			Count:                &s.Replicas,
-			EnableAutoScaling:    ptr.To(s.EnableAutoScaling),
-			EnableUltraSSD:       s.EnableUltraSSD,
-			KubeletConfig:        kubeletConfig,
-			KubeletDiskType:      containerservice.KubeletDiskType(ptr.Deref((*string)(s.KubeletDiskType), "")),
-			MaxCount:             s.MaxCount,
-			MaxPods:              s.MaxPods,
-			MinCount:             s.MinCount,
-			Mode:                 containerservice.AgentPoolMode(s.Mode),
-			NodeLabels:           nodeLabels,
-			NodeTaints:           nodeTaints,
-			OrchestratorVersion:  s.Version,
-			OsDiskSizeGB:         &s.OSDiskSizeGB,
-			OsDiskType:           containerservice.OSDiskType(ptr.Deref(s.OsDiskType, "")),
-			OsType:               containerservice.OSType(ptr.Deref(s.OSType, "")),
-			ScaleSetPriority:     containerservice.ScaleSetPriority(ptr.Deref(s.ScaleSetPriority, "")),
-			ScaleDownMode:        containerservice.ScaleDownMode(ptr.Deref(s.ScaleDownMode, "")),
-			SpotMaxPrice:         spotMaxPrice,
-			Type:                 containerservice.AgentPoolTypeVirtualMachineScaleSets,
-			VMSize:               sku,
-			VnetSubnetID:         vnetSubnetID,
-			EnableNodePublicIP:   s.EnableNodePublicIP,
-			NodePublicIPPrefixID: s.NodePublicIPPrefixID,
-			Tags:                 tags,
-			EnableFIPS:           s.EnableFIPS,
-			LinuxOSConfig:        linuxOSConfig,
+			AvailabilityZones:          availabilityZones,
+			Count:                      &s.Replicas,
+			EnableAutoScaling:          ptr.To(s.EnableAutoScaling),
+			EnableUltraSSD:             s.EnableUltraSSD,
+			KubeletConfig:              kubeletConfig,
+			KubeletDiskType:            containerservice.KubeletDiskType(ptr.Deref((*string)(s.KubeletDiskType), "")),
+			MaxCount:                   s.MaxCount,
+			MaxPods:                    s.MaxPods,
+			MinCount:                   s.MinCount,
+			Mode:                       containerservice.AgentPoolMode(s.Mode),
+			NodeLabels:                 nodeLabels,
+			NodeTaints:                 agentPoolNodeTaints,
+			OrchestratorVersion:        s.Version,
+			OsDiskSizeGB:               &s.OSDiskSizeGB,
+			OsDiskType:                 containerservice.OSDiskType(ptr.Deref(s.OsDiskType, "")),
+			OsType:                     containerservice.OSType(ptr.Deref(s.OSType, "")),
+			ScaleSetPriority:           containerservice.ScaleSetPriority(ptr.Deref(s.ScaleSetPriority, "")),
+			ScaleDownMode:              containerservice.ScaleDownMode(ptr.Deref(s.ScaleDownMode, "")),
+			SpotMaxPrice:               spotMaxPrice,
+			Type:                       containerservice.AgentPoolTypeVirtualMachineScaleSets,
+			VMSize:                     sku,
+			VnetSubnetID:               vnetSubnetID,
+			EnableNodePublicIP:         s.EnableNodePublicIP,
+			NodePublicIPPrefixID:       s.NodePublicIPPrefixID,
+			ProximityPlacementGroupID:  s.ProximityPlacementGroupID,
+			CapacityReservationGroupID: s.CapacityReservationGroupID,
+			HostGroupID:                s.HostGroupID,
+			PowerState:                 powerState,
+			Tags:                       tags,
+			EnableFIPS:                 s.EnableFIPS,
+			LinuxOSConfig:              linuxOSConfig,
 		},
 	}
 
 	return agentPool, nil
 }
 
-// mergeSystemNodeLabels appends any kubernetes.azure.com-prefixed labels from the AKS label set
-// into the local capz label set.
+// mergeSystemNodeLabels returns a copy of the local capz label set with any kubernetes.azure.com-prefixed labels
+// from the AKS label set appended.
 func mergeSystemNodeLabels(capz, aks map[string]*string) map[string]*string {
-	ret := capz
+	ret := make(map[string]*string, len(capz))
+	for k, v := range capz {
+		ret[k] = v
+	}
 	// Look for labels returned from the AKS node pool API that begin with kubernetes.azure.com
 	for aksNodeLabelKey := range aks {
 		if azureutil.IsAzureSystemNodeLabelKey(aksNodeLabelKey) {
@@ -403,3 +441,36 @@ func mergeSystemNodeLabels(capz, aks map[string]*string) map[string]*string {
 	}
 	return ret
 }
+
+// mergeSystemNodeTaints returns a copy of the local capz taint list with any kubernetes.azure.com-prefixed taints
+// from the AKS taint list appended, such as the one AKS adds to Spot node pools.
+func mergeSystemNodeTaints(capz, aks []string) []string {
+	ret := make([]string, 0, len(capz))
+	ret = append(ret, capz...)
+	for _, aksNodeTaint := range aks {
+		if azureutil.IsAzureSystemNodeLabelKey(nodeTaintKey(aksNodeTaint)) && !slice.Contains(ret, aksNodeTaint) {
+			ret = append(ret, aksNodeTaint)
+		}
+	}
+	return ret
+}
+
+// nodeTaintKey returns the key of a taint in the "key=value:effect" format used by AKS.
+func nodeTaintKey(taint string) string {
+	if i := strings.IndexAny(taint, "=:"); i >= 0 {
+		return taint[:i]
+	}
+	return taint
+}
+
+// sortedNodeTaints returns a sorted copy of the taints to compare them regardless of their order,
+// or nil if there are none.
+func sortedNodeTaints(taints []string) *[]string {
+	if len(taints) == 0 {
+		return nil
+	}
+	ret := make([]string, len(taints))
+	copy(ret, taints)
+	sort.Strings(ret)
+	return &ret
+}
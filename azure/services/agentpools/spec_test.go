@@ -22,7 +22,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2022-03-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/preview/containerservice/mgmt/2022-03-02-preview/containerservice"
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
@@ -140,6 +140,12 @@ func sdkWithCount(count int32) func(*containerservice.AgentPool) {
 	}
 }
 
+func sdkWithPowerState(code containerservice.Code) func(*containerservice.AgentPool) {
+	return func(pool *containerservice.AgentPool) {
+		pool.ManagedClusterAgentPoolProfileProperties.PowerState = &containerservice.PowerState{Code: code}
+	}
+}
+
 func sdkWithProvisioningState(state string) func(*containerservice.AgentPool) {
 	return func(pool *containerservice.AgentPool) {
 		pool.ManagedClusterAgentPoolProfileProperties.ProvisioningState = ptr.To(state)
@@ -373,6 +379,191 @@ func TestParameters(t *testing.T) {
 			expected:      nil,
 			expectedError: nil,
 		},
+		{
+			name: "system node taints added by AKS shouldn't trigger an update",
+			spec: fakeAgentPool(),
+			existing: sdkFakeAgentPool(
+				func(pool *containerservice.AgentPool) {
+					pool.NodeTaints = &[]string{"kubernetes.azure.com/scalesetpriority=spot:NoSchedule", "fake-taint"}
+				},
+				sdkWithProvisioningState("Succeeded"),
+			),
+			expected:      nil,
+			expectedError: nil,
+		},
+		{
+			name: "node taints in a different order shouldn't trigger an update",
+			spec: fakeAgentPool(
+				func(pool *AgentPoolSpec) { pool.NodeTaints = []string{"fake-taint", "other-taint"} },
+			),
+			existing: sdkFakeAgentPool(
+				func(pool *containerservice.AgentPool) { pool.NodeTaints = &[]string{"other-taint", "fake-taint"} },
+				sdkWithProvisioningState("Succeeded"),
+			),
+			expected:      nil,
+			expectedError: nil,
+		},
+		{
+			name: "system node labels shouldn't trigger an update when no node labels are set",
+			spec: fakeAgentPool(
+				func(pool *AgentPoolSpec) { pool.NodeLabels = nil },
+			),
+			existing: sdkFakeAgentPool(
+				func(pool *containerservice.AgentPool) {
+					pool.NodeLabels = map[string]*string{
+						"kubernetes.azure.com/scalesetpriority": ptr.To("spot"),
+					}
+				},
+				sdkWithProvisioningState("Succeeded"),
+			),
+			expected:      nil,
+			expectedError: nil,
+		},
+		{
+			name: "removing all node labels and taints sends empty values to AKS",
+			spec: fakeAgentPool(
+				func(pool *AgentPoolSpec) {
+					pool.NodeLabels = nil
+					pool.NodeTaints = nil
+				},
+			),
+			existing: sdkFakeAgentPool(
+				sdkWithProvisioningState("Succeeded"),
+			),
+			expected: sdkFakeAgentPool(
+				func(pool *containerservice.AgentPool) {
+					pool.NodeLabels = map[string]*string{}
+					pool.NodeTaints = &[]string{}
+				},
+			),
+			expectedError: nil,
+		},
+		{
+			name: "removing node taints keeps the system node taints added by AKS",
+			spec: fakeAgentPool(
+				func(pool *AgentPoolSpec) { pool.NodeTaints = nil },
+			),
+			existing: sdkFakeAgentPool(
+				func(pool *containerservice.AgentPool) {
+					pool.NodeTaints = &[]string{"fake-taint", "kubernetes.azure.com/scalesetpriority=spot:NoSchedule"}
+				},
+				sdkWithProvisioningState("Succeeded"),
+			),
+			expected: sdkFakeAgentPool(
+				func(pool *containerservice.AgentPool) {
+					pool.NodeTaints = &[]string{"kubernetes.azure.com/scalesetpriority=spot:NoSchedule"}
+				},
+			),
+			expectedError: nil,
+		},
+		{
+			name: "parameters with a proximity placement group",
+			spec: fakeAgentPool(
+				func(pool *AgentPoolSpec) {
+					pool.ProximityPlacementGroupID = ptr.To("/subscriptions/fake/resourceGroups/fake/providers/Microsoft.Compute/proximityPlacementGroups/fake-ppg")
+				},
+			),
+			existing: nil,
+			expected: sdkFakeAgentPool(
+				func(pool *containerservice.AgentPool) {
+					pool.ProximityPlacementGroupID = ptr.To("/subscriptions/fake/resourceGroups/fake/providers/Microsoft.Compute/proximityPlacementGroups/fake-ppg")
+				},
+			),
+			expectedError: nil,
+		},
+		{
+			name: "parameters with a capacity reservation group",
+			spec: fakeAgentPool(
+				func(pool *AgentPoolSpec) {
+					pool.CapacityReservationGroupID = ptr.To("/subscriptions/fake/resourceGroups/fake/providers/Microsoft.Compute/capacityReservationGroups/fake-crg")
+				},
+			),
+			existing: nil,
+			expected: sdkFakeAgentPool(
+				func(pool *containerservice.AgentPool) {
+					pool.CapacityReservationGroupID = ptr.To("/subscriptions/fake/resourceGroups/fake/providers/Microsoft.Compute/capacityReservationGroups/fake-crg")
+				},
+			),
+			expectedError: nil,
+		},
+		{
+			name: "parameters with a dedicated host group",
+			spec: fakeAgentPool(
+				func(pool *AgentPoolSpec) {
+					pool.HostGroupID = ptr.To("/subscriptions/fake/resourceGroups/fake/providers/Microsoft.Compute/hostGroups/fake-host-group")
+				},
+			),
+			existing: nil,
+			expected: sdkFakeAgentPool(
+				func(pool *containerservice.AgentPool) {
+					pool.HostGroupID = ptr.To("/subscriptions/fake/resourceGroups/fake/providers/Microsoft.Compute/hostGroups/fake-host-group")
+				},
+			),
+			expectedError: nil,
+		},
+		{
+			name: "stop a running agent pool",
+			spec: fakeAgentPool(
+				func(pool *AgentPoolSpec) {
+					pool.PowerState = ptr.To(infrav1.AgentPoolPowerStateStopped)
+				},
+			),
+			existing: sdkFakeAgentPool(
+				sdkWithPowerState(containerservice.CodeRunning),
+				sdkWithProvisioningState("Succeeded"),
+			),
+			expected:      sdkFakeAgentPool(sdkWithPowerState(containerservice.CodeStopped)),
+			expectedError: nil,
+		},
+		{
+			name: "start a stopped agent pool",
+			spec: fakeAgentPool(
+				func(pool *AgentPoolSpec) {
+					pool.PowerState = ptr.To(infrav1.AgentPoolPowerStateRunning)
+				},
+			),
+			existing: sdkFakeAgentPool(
+				sdkWithPowerState(containerservice.CodeStopped),
+				sdkWithProvisioningState("Succeeded"),
+			),
+			expected:      sdkFakeAgentPool(sdkWithPowerState(containerservice.CodeRunning)),
+			expectedError: nil,
+		},
+		{
+			name: "stopped agent pool up to date",
+			spec: fakeAgentPool(
+				func(pool *AgentPoolSpec) {
+					pool.PowerState = ptr.To(infrav1.AgentPoolPowerStateStopped)
+				},
+			),
+			existing: sdkFakeAgentPool(
+				sdkWithPowerState(containerservice.CodeStopped),
+				sdkWithProvisioningState("Succeeded"),
+			),
+			expected:      nil,
+			expectedError: nil,
+		},
+		{
+			name: "unset power state doesn't start a stopped agent pool",
+			spec: fakeAgentPool(),
+			existing: sdkFakeAgentPool(
+				sdkWithPowerState(containerservice.CodeStopped),
+				sdkWithProvisioningState("Succeeded"),
+			),
+			expected:      nil,
+			expectedError: nil,
+		},
+		{
+			name: "new agent pool is created running",
+			spec: fakeAgentPool(
+				func(pool *AgentPoolSpec) {
+					pool.PowerState = ptr.To(infrav1.AgentPoolPowerStateStopped)
+				},
+			),
+			existing:      nil,
+			expected:      sdkFakeAgentPool(),
+			expectedError: nil,
+		},
 	}
 	for _, tc := range testcases {
 		tc := tc
@@ -472,3 +663,48 @@ func TestMergeSystemNodeLabels(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeSystemNodeTaints(t *testing.T) {
+	testcases := []struct {
+		name       string
+		capzTaints []string
+		aksTaints  []string
+		expected   []string
+	}{
+		{
+			name:       "update taints",
+			capzTaints: []string{"foo=bar:NoSchedule"},
+			aksTaints:  []string{"foo=baz:NoSchedule", "hello=world:NoExecute"},
+			expected:   []string{"foo=bar:NoSchedule"},
+		},
+		{
+			name:       "delete taints",
+			capzTaints: nil,
+			aksTaints:  []string{"foo=bar:NoSchedule"},
+			expected:   []string{},
+		},
+		{
+			name:       "retain system taint during update",
+			capzTaints: []string{"foo=bar:NoSchedule"},
+			aksTaints:  []string{"kubernetes.azure.com/scalesetpriority=spot:NoSchedule"},
+			expected:   []string{"foo=bar:NoSchedule", "kubernetes.azure.com/scalesetpriority=spot:NoSchedule"},
+		},
+		{
+			name:       "don't duplicate a system taint set by the user",
+			capzTaints: []string{"kubernetes.azure.com/scalesetpriority=spot:NoSchedule"},
+			aksTaints:  []string{"kubernetes.azure.com/scalesetpriority=spot:NoSchedule"},
+			expected:   []string{"kubernetes.azure.com/scalesetpriority=spot:NoSchedule"},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			ret := mergeSystemNodeTaints(tc.capzTaints, tc.aksTaints)
+			g.Expect(ret).To(Equal(tc.expected))
+		})
+	}
+}
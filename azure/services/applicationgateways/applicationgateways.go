@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationgateways
+
+import (
+	"context"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	serviceName = "applicationgateways"
+	// SubnetDelegation is the service a subnet must be delegated to in order to host the gateways of
+	// Application Gateway for Containers.
+	SubnetDelegation = "Microsoft.ServiceNetworking/trafficControllers"
+)
+
+// ApplicationGatewayScope defines the scope interface for an Application Gateway service.
+type ApplicationGatewayScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	ApplicationGatewaySpec() azure.ResourceSpecGetter
+	ApplicationGatewayRoleAssignmentSpecs() []azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope                    ApplicationGatewayScope
+	gatewayReconciler        async.Reconciler
+	roleAssignmentReconciler async.Reconciler
+}
+
+// New creates a new service.
+func New(scope ApplicationGatewayScope) *Service {
+	client := newClient(scope)
+	roleAssignmentsClient := roleassignments.NewClient(scope)
+	return &Service{
+		Scope:                    scope,
+		gatewayReconciler:        async.New(scope, client, client),
+		roleAssignmentReconciler: async.New(scope, roleAssignmentsClient, roleAssignmentsClient),
+	}
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile idempotently creates or updates the Application Gateway for AGIC, and then assigns the roles the ingress
+// controller needs.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "applicationgateways.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	gatewaySpec := s.Scope.ApplicationGatewaySpec()
+	roleAssignmentSpecs := s.Scope.ApplicationGatewayRoleAssignmentSpecs()
+	if gatewaySpec == nil && len(roleAssignmentSpecs) == 0 {
+		return nil
+	}
+
+	var result error
+	if gatewaySpec != nil {
+		_, result = s.gatewayReconciler.CreateOrUpdateResource(ctx, gatewaySpec, serviceName)
+	}
+	// Roles can only be assigned on the Application Gateway once it exists.
+	if result == nil {
+		for _, roleAssignmentSpec := range roleAssignmentSpecs {
+			if _, err := s.roleAssignmentReconciler.CreateOrUpdateResource(ctx, roleAssignmentSpec, serviceName); err != nil {
+				result = err
+				break
+			}
+		}
+	}
+
+	s.Scope.UpdatePutStatus(infrav1.ApplicationGatewayReadyCondition, serviceName, result)
+	return result
+}
+
+// Delete deletes the role assignments of the ingress controller, which may be on resources brought by the user that
+// outlive the cluster, and then the Application Gateway created for AGIC.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "applicationgateways.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	gatewaySpec := s.Scope.ApplicationGatewaySpec()
+	roleAssignmentSpecs := s.Scope.ApplicationGatewayRoleAssignmentSpecs()
+	if gatewaySpec == nil && len(roleAssignmentSpecs) == 0 {
+		return nil
+	}
+
+	var result error
+	for _, roleAssignmentSpec := range roleAssignmentSpecs {
+		if err := s.roleAssignmentReconciler.DeleteResource(ctx, roleAssignmentSpec, serviceName); err != nil {
+			result = err
+			break
+		}
+	}
+	if result == nil && gatewaySpec != nil {
+		result = s.gatewayReconciler.DeleteResource(ctx, gatewaySpec, serviceName)
+	}
+
+	s.Scope.UpdateDeleteStatus(infrav1.ApplicationGatewayReadyCondition, serviceName, result)
+	return result
+}
+
+// IsManaged returns always returns true as the scope only returns a spec for an Application Gateway created by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
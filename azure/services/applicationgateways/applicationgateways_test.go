@@ -0,0 +1,248 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationgateways
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/applicationgateways/mock_applicationgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeApplicationGateway = ApplicationGatewaySpec{
+		Name:           "my-cluster-appgw",
+		ResourceGroup:  "my-rg",
+		SubscriptionID: "123",
+		Location:       "westus",
+		ClusterName:    "my-cluster",
+		SubnetID:       "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-cluster-ingress-subnet",
+		PublicIPID:     "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-cluster-appgw-pip",
+	}
+	fakeContributorAssignment = roleassignments.RoleAssignmentSpec{
+		Name:             "00000000-0000-0000-0000-000000000001",
+		ResourceGroup:    "my-rg",
+		PrincipalID:      ptr.To("fake-principal-id"),
+		RoleDefinitionID: "/subscriptions/123/providers/Microsoft.Authorization/roleDefinitions/b24988ac-6180-42a0-ab88-20f7382dd24c",
+		Scope:            "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/applicationGateways/my-cluster-appgw",
+	}
+	fakeReaderAssignment = roleassignments.RoleAssignmentSpec{
+		Name:             "00000000-0000-0000-0000-000000000002",
+		ResourceGroup:    "my-rg",
+		PrincipalID:      ptr.To("fake-principal-id"),
+		RoleDefinitionID: "/subscriptions/123/providers/Microsoft.Authorization/roleDefinitions/acdd72a7-3385-48ef-bd42-f606fba81ae7",
+		Scope:            "/subscriptions/123/resourceGroups/my-rg",
+	}
+	errFake      = errors.New("this is an error")
+	notDoneError = azure.NewOperationNotDoneError(&infrav1.Future{})
+)
+
+func TestReconcileApplicationGateway(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, gw, ra *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if no application gateway spec or role assignment specs are found",
+			expectedError: "",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, gw, ra *mock_async.MockReconcilerMockRecorder) {
+				s.ApplicationGatewaySpec().Return(nil)
+				s.ApplicationGatewayRoleAssignmentSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "create application gateway and role assignments succeeds",
+			expectedError: "",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, gw, ra *mock_async.MockReconcilerMockRecorder) {
+				s.ApplicationGatewaySpec().Return(&fakeApplicationGateway)
+				s.ApplicationGatewayRoleAssignmentSpecs().Return([]azure.ResourceSpecGetter{&fakeContributorAssignment, &fakeReaderAssignment})
+				gw.CreateOrUpdateResource(gomockinternal.AContext(), &fakeApplicationGateway, serviceName).Return(nil, nil)
+				ra.CreateOrUpdateResource(gomockinternal.AContext(), &fakeContributorAssignment, serviceName).Return(nil, nil)
+				ra.CreateOrUpdateResource(gomockinternal.AContext(), &fakeReaderAssignment, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.ApplicationGatewayReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "role assignments for an existing application gateway succeed",
+			expectedError: "",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, gw, ra *mock_async.MockReconcilerMockRecorder) {
+				s.ApplicationGatewaySpec().Return(nil)
+				s.ApplicationGatewayRoleAssignmentSpecs().Return([]azure.ResourceSpecGetter{&fakeContributorAssignment})
+				ra.CreateOrUpdateResource(gomockinternal.AContext(), &fakeContributorAssignment, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.ApplicationGatewayReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "role assignments are skipped while the application gateway is not done",
+			expectedError: notDoneError.Error(),
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, gw, ra *mock_async.MockReconcilerMockRecorder) {
+				s.ApplicationGatewaySpec().Return(&fakeApplicationGateway)
+				s.ApplicationGatewayRoleAssignmentSpecs().Return([]azure.ResourceSpecGetter{&fakeContributorAssignment})
+				gw.CreateOrUpdateResource(gomockinternal.AContext(), &fakeApplicationGateway, serviceName).Return(nil, notDoneError)
+				s.UpdatePutStatus(infrav1.ApplicationGatewayReadyCondition, serviceName, notDoneError)
+			},
+		},
+		{
+			name:          "create application gateway fails",
+			expectedError: errFake.Error(),
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, gw, ra *mock_async.MockReconcilerMockRecorder) {
+				s.ApplicationGatewaySpec().Return(&fakeApplicationGateway)
+				s.ApplicationGatewayRoleAssignmentSpecs().Return([]azure.ResourceSpecGetter{&fakeContributorAssignment})
+				gw.CreateOrUpdateResource(gomockinternal.AContext(), &fakeApplicationGateway, serviceName).Return(nil, errFake)
+				s.UpdatePutStatus(infrav1.ApplicationGatewayReadyCondition, serviceName, errFake)
+			},
+		},
+		{
+			name:          "role assignment fails",
+			expectedError: errFake.Error(),
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, gw, ra *mock_async.MockReconcilerMockRecorder) {
+				s.ApplicationGatewaySpec().Return(&fakeApplicationGateway)
+				s.ApplicationGatewayRoleAssignmentSpecs().Return([]azure.ResourceSpecGetter{&fakeContributorAssignment, &fakeReaderAssignment})
+				gw.CreateOrUpdateResource(gomockinternal.AContext(), &fakeApplicationGateway, serviceName).Return(nil, nil)
+				ra.CreateOrUpdateResource(gomockinternal.AContext(), &fakeContributorAssignment, serviceName).Return(nil, errFake)
+				s.UpdatePutStatus(infrav1.ApplicationGatewayReadyCondition, serviceName, errFake)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_applicationgateways.NewMockApplicationGatewayScope(mockCtrl)
+			gatewayReconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+			roleAssignmentReconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), gatewayReconcilerMock.EXPECT(), roleAssignmentReconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:                    scopeMock,
+				gatewayReconciler:        gatewayReconcilerMock,
+				roleAssignmentReconciler: roleAssignmentReconcilerMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteApplicationGateway(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, gw, ra *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if no application gateway spec or role assignment specs are found",
+			expectedError: "",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, gw, ra *mock_async.MockReconcilerMockRecorder) {
+				s.ApplicationGatewaySpec().Return(nil)
+				s.ApplicationGatewayRoleAssignmentSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "delete role assignments and application gateway succeeds",
+			expectedError: "",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, gw, ra *mock_async.MockReconcilerMockRecorder) {
+				s.ApplicationGatewaySpec().Return(&fakeApplicationGateway)
+				s.ApplicationGatewayRoleAssignmentSpecs().Return([]azure.ResourceSpecGetter{&fakeContributorAssignment, &fakeReaderAssignment})
+				ra.DeleteResource(gomockinternal.AContext(), &fakeContributorAssignment, serviceName).Return(nil)
+				ra.DeleteResource(gomockinternal.AContext(), &fakeReaderAssignment, serviceName).Return(nil)
+				gw.DeleteResource(gomockinternal.AContext(), &fakeApplicationGateway, serviceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.ApplicationGatewayReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "delete role assignments on an existing application gateway succeeds",
+			expectedError: "",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, gw, ra *mock_async.MockReconcilerMockRecorder) {
+				s.ApplicationGatewaySpec().Return(nil)
+				s.ApplicationGatewayRoleAssignmentSpecs().Return([]azure.ResourceSpecGetter{&fakeContributorAssignment})
+				ra.DeleteResource(gomockinternal.AContext(), &fakeContributorAssignment, serviceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.ApplicationGatewayReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "delete role assignment fails",
+			expectedError: errFake.Error(),
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, gw, ra *mock_async.MockReconcilerMockRecorder) {
+				s.ApplicationGatewaySpec().Return(&fakeApplicationGateway)
+				s.ApplicationGatewayRoleAssignmentSpecs().Return([]azure.ResourceSpecGetter{&fakeContributorAssignment, &fakeReaderAssignment})
+				ra.DeleteResource(gomockinternal.AContext(), &fakeContributorAssignment, serviceName).Return(errFake)
+				s.UpdateDeleteStatus(infrav1.ApplicationGatewayReadyCondition, serviceName, errFake)
+			},
+		},
+		{
+			name:          "delete application gateway fails",
+			expectedError: errFake.Error(),
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, gw, ra *mock_async.MockReconcilerMockRecorder) {
+				s.ApplicationGatewaySpec().Return(&fakeApplicationGateway)
+				s.ApplicationGatewayRoleAssignmentSpecs().Return(nil)
+				gw.DeleteResource(gomockinternal.AContext(), &fakeApplicationGateway, serviceName).Return(errFake)
+				s.UpdateDeleteStatus(infrav1.ApplicationGatewayReadyCondition, serviceName, errFake)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_applicationgateways.NewMockApplicationGatewayScope(mockCtrl)
+			gatewayReconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+			roleAssignmentReconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), gatewayReconcilerMock.EXPECT(), roleAssignmentReconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:                    scopeMock,
+				gatewayReconciler:        gatewayReconcilerMock,
+				roleAssignmentReconciler: roleAssignmentReconcilerMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
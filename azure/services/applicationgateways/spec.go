@@ -0,0 +1,169 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationgateways
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+const (
+	// The names of the placeholder configuration an Application Gateway needs to be created with.
+	// AGIC replaces it with the configuration of the ingresses of the cluster.
+	gatewayIPConfigName  = "appGatewayIpConfig"
+	frontendIPConfigName = "appGatewayFrontendIP"
+	frontendPortName     = "appGatewayFrontendPort"
+	backendPoolName      = "appGatewayBackendPool"
+	backendHTTPSettings  = "appGatewayBackendHttpSettings"
+	httpListenerName     = "appGatewayHttpListener"
+	requestRoutingRule   = "appGatewayRoutingRule"
+	lowestRulePriority   = 20000
+	// defaultCapacity is the number of instances of the Application Gateway.
+	defaultCapacity = 2
+)
+
+// ApplicationGatewaySpec defines the specification for an Application Gateway configured by AGIC.
+type ApplicationGatewaySpec struct {
+	Name           string
+	ResourceGroup  string
+	SubscriptionID string
+	Location       string
+	ClusterName    string
+	SubnetID       string
+	PublicIPID     string
+	AdditionalTags infrav1.Tags
+}
+
+// ResourceName returns the name of the Application Gateway.
+func (s *ApplicationGatewaySpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *ApplicationGatewaySpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for Application Gateways.
+func (s *ApplicationGatewaySpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the Application Gateway.
+func (s *ApplicationGatewaySpec) Parameters(ctx context.Context, existing interface{}) (parameters interface{}, err error) {
+	if existing != nil {
+		if _, ok := existing.(network.ApplicationGateway); !ok {
+			return nil, errors.Errorf("%T is not a network.ApplicationGateway", existing)
+		}
+		// Application Gateway already exists.
+		// AGIC owns its configuration, so it's never updated.
+		return nil, nil
+	}
+
+	return network.ApplicationGateway{
+		Location: ptr.To(s.Location),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        ptr.To(s.Name),
+			Role:        ptr.To(infrav1.Ingress),
+			Additional:  s.AdditionalTags,
+		})),
+		ApplicationGatewayPropertiesFormat: &network.ApplicationGatewayPropertiesFormat{
+			Sku: &network.ApplicationGatewaySku{
+				Name:     network.ApplicationGatewaySkuNameStandardV2,
+				Tier:     network.ApplicationGatewayTierStandardV2,
+				Capacity: ptr.To[int32](defaultCapacity),
+			},
+			GatewayIPConfigurations: &[]network.ApplicationGatewayIPConfiguration{
+				{
+					Name: ptr.To(gatewayIPConfigName),
+					ApplicationGatewayIPConfigurationPropertiesFormat: &network.ApplicationGatewayIPConfigurationPropertiesFormat{
+						Subnet: &network.SubResource{ID: ptr.To(s.SubnetID)},
+					},
+				},
+			},
+			FrontendIPConfigurations: &[]network.ApplicationGatewayFrontendIPConfiguration{
+				{
+					Name: ptr.To(frontendIPConfigName),
+					ApplicationGatewayFrontendIPConfigurationPropertiesFormat: &network.ApplicationGatewayFrontendIPConfigurationPropertiesFormat{
+						PublicIPAddress: &network.SubResource{ID: ptr.To(s.PublicIPID)},
+					},
+				},
+			},
+			FrontendPorts: &[]network.ApplicationGatewayFrontendPort{
+				{
+					Name: ptr.To(frontendPortName),
+					ApplicationGatewayFrontendPortPropertiesFormat: &network.ApplicationGatewayFrontendPortPropertiesFormat{
+						Port: ptr.To[int32](80),
+					},
+				},
+			},
+			BackendAddressPools: &[]network.ApplicationGatewayBackendAddressPool{
+				{Name: ptr.To(backendPoolName)},
+			},
+			BackendHTTPSettingsCollection: &[]network.ApplicationGatewayBackendHTTPSettings{
+				{
+					Name: ptr.To(backendHTTPSettings),
+					ApplicationGatewayBackendHTTPSettingsPropertiesFormat: &network.ApplicationGatewayBackendHTTPSettingsPropertiesFormat{
+						Port:                ptr.To[int32](80),
+						Protocol:            network.ApplicationGatewayProtocolHTTP,
+						CookieBasedAffinity: network.ApplicationGatewayCookieBasedAffinityDisabled,
+						RequestTimeout:      ptr.To[int32](30),
+					},
+				},
+			},
+			HTTPListeners: &[]network.ApplicationGatewayHTTPListener{
+				{
+					Name: ptr.To(httpListenerName),
+					ApplicationGatewayHTTPListenerPropertiesFormat: &network.ApplicationGatewayHTTPListenerPropertiesFormat{
+						FrontendIPConfiguration: s.subResource("frontendIPConfigurations", frontendIPConfigName),
+						FrontendPort:            s.subResource("frontendPorts", frontendPortName),
+						Protocol:                network.ApplicationGatewayProtocolHTTP,
+					},
+				},
+			},
+			RequestRoutingRules: &[]network.ApplicationGatewayRequestRoutingRule{
+				{
+					Name: ptr.To(requestRoutingRule),
+					ApplicationGatewayRequestRoutingRulePropertiesFormat: &network.ApplicationGatewayRequestRoutingRulePropertiesFormat{
+						RuleType: network.ApplicationGatewayRequestRoutingRuleTypeBasic,
+						// The lowest priority, so that the rules AGIC adds take precedence until it replaces it.
+						Priority:            ptr.To[int32](lowestRulePriority),
+						HTTPListener:        s.subResource("httpListeners", httpListenerName),
+						BackendAddressPool:  s.subResource("backendAddressPools", backendPoolName),
+						BackendHTTPSettings: s.subResource("backendHttpSettingsCollection", backendHTTPSettings),
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// subResource returns a reference to a child resource of the Application Gateway.
+func (s *ApplicationGatewaySpec) subResource(collection, name string) *network.SubResource {
+	return &network.SubResource{
+		ID: ptr.To(fmt.Sprintf("%s/%s/%s", azure.ApplicationGatewayID(s.SubscriptionID, s.ResourceGroup, s.Name), collection, name)),
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationgateways
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestParameters(t *testing.T) {
+	testcases := []struct {
+		name          string
+		spec          ApplicationGatewaySpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name:     "new application gateway",
+			spec:     fakeApplicationGateway,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(network.ApplicationGateway{}))
+				gw := result.(network.ApplicationGateway)
+				g.Expect(gw.Sku.Name).To(Equal(network.ApplicationGatewaySkuNameStandardV2))
+				g.Expect(*gw.Sku.Capacity).To(Equal(int32(defaultCapacity)))
+				g.Expect(*gw.Location).To(Equal("westus"))
+				g.Expect(*(*gw.GatewayIPConfigurations)[0].Subnet.ID).To(Equal(fakeApplicationGateway.SubnetID))
+				g.Expect(*(*gw.FrontendIPConfigurations)[0].PublicIPAddress.ID).To(Equal(fakeApplicationGateway.PublicIPID))
+				g.Expect(gw.Tags).To(HaveKeyWithValue(infrav1.NameAzureClusterAPIRole, ptr.To(infrav1.Ingress)))
+				rule := (*gw.RequestRoutingRules)[0]
+				g.Expect(*rule.Priority).To(Equal(int32(lowestRulePriority)))
+				g.Expect(*rule.HTTPListener.ID).To(Equal("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/applicationGateways/my-cluster-appgw/httpListeners/appGatewayHttpListener"))
+				g.Expect(*rule.BackendAddressPool.ID).To(Equal("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/applicationGateways/my-cluster-appgw/backendAddressPools/appGatewayBackendPool"))
+			},
+		},
+		{
+			name:     "existing application gateway is left to AGIC",
+			spec:     fakeApplicationGateway,
+			existing: network.ApplicationGateway{},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:          "existing resource is not an application gateway",
+			spec:          fakeApplicationGateway,
+			existing:      network.AzureFirewall{},
+			expectedError: "network.AzureFirewall is not a network.ApplicationGateway",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.expect(g, result)
+		})
+	}
+}
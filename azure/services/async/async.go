@@ -18,6 +18,8 @@ package async
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -42,6 +44,7 @@ type Service struct {
 
 // New creates a new async service.
 func New(scope FutureScope, createClient Creator, deleteClient Deleter) *Service {
+	createClient, deleteClient = withFaultInjection(createClient, deleteClient)
 	return &Service{
 		Scope:   scope,
 		Creator: createClient,
@@ -132,6 +135,17 @@ func (s *Service) CreateOrUpdateResource(ctx context.Context, spec azure.Resourc
 		return existingResource, nil
 	}
 
+	// Tag the resource with the hash of the spec it is created or updated from, for observability only.
+	if hashSpec, ok := spec.(azure.ResourceSpecGetterWithSpecHash); ok {
+		specHash, err := getSpecHash(spec)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get spec hash for resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+		}
+		if parameters, err = hashSpec.WithSpecHash(parameters, specHash); err != nil {
+			return nil, errors.Wrapf(err, "failed to tag resource %s/%s with spec hash (service: %s)", rgName, resourceName, serviceName)
+		}
+	}
+
 	// Create or update the resource with the desired parameters.
 	logMessageVerbPrefix := "creat"
 	if existingResource != nil {
@@ -161,6 +175,15 @@ func (s *Service) CreateOrUpdateResource(ctx context.Context, spec azure.Resourc
 	return result, nil
 }
 
+// getSpecHash returns the hash of the resource spec.
+func getSpecHash(spec azure.ResourceSpecGetter) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
 // DeleteResource implements the logic for deleting a resource Asynchronously.
 func (s *Service) DeleteResource(ctx context.Context, spec azure.ResourceSpecGetter, serviceName string) (err error) {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "async.Service.DeleteResource")
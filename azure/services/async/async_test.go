@@ -28,6 +28,7 @@ import (
 	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
@@ -345,6 +346,83 @@ func TestCreateOrUpdateResource(t *testing.T) {
 	}
 }
 
+// TestCreateOrUpdateResourceWithSpecHash tests the CreateOrUpdateResource function with a spec tagging its resource
+// with its hash.
+func TestCreateOrUpdateResourceWithSpecHash(t *testing.T) {
+	fakeTaggedResourceParameters := resources.GenericResource{Tags: map[string]*string{infrav1.SpecVersionHashTagKey(): ptr.To("hash")}}
+
+	testcases := []struct {
+		name           string
+		expectedError  string
+		expectedResult interface{}
+		expect         func(s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockCreatorMockRecorder, r *mock_azure.MockResourceSpecGetterWithSpecHashMockRecorder)
+	}{
+		{
+			name:           "existing resource is updated and tagged with the spec hash",
+			expectedResult: "test-resource",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockCreatorMockRecorder, r *mock_azure.MockResourceSpecGetterWithSpecHashMockRecorder) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service", infrav1.PutFuture).Return(nil)
+				c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetterWithSpecHash{})).Return(&fakeExistingResource, nil)
+				r.Parameters(gomockinternal.AContext(), &fakeExistingResource).Return(&fakeResourceParameters, nil)
+				r.WithSpecHash(&fakeResourceParameters, gomock.Any()).Return(&fakeTaggedResourceParameters, nil)
+				c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetterWithSpecHash{}), &fakeTaggedResourceParameters).Return("test-resource", nil, nil)
+			},
+		},
+		{
+			name:           "existing resource is up to date",
+			expectedResult: &fakeExistingResource,
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockCreatorMockRecorder, r *mock_azure.MockResourceSpecGetterWithSpecHashMockRecorder) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service", infrav1.PutFuture).Return(nil)
+				c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetterWithSpecHash{})).Return(&fakeExistingResource, nil)
+				r.Parameters(gomockinternal.AContext(), &fakeExistingResource).Return(nil, nil)
+			},
+		},
+		{
+			name:           "new resource is tagged with the spec hash",
+			expectedResult: "test-resource",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockCreatorMockRecorder, r *mock_azure.MockResourceSpecGetterWithSpecHashMockRecorder) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service", infrav1.PutFuture).Return(nil)
+				c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetterWithSpecHash{})).Return(nil, fakeNotFoundError)
+				r.Parameters(gomockinternal.AContext(), nil).Return(&fakeResourceParameters, nil)
+				r.WithSpecHash(&fakeResourceParameters, gomock.Any()).Return(&fakeTaggedResourceParameters, nil)
+				c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetterWithSpecHash{}), &fakeTaggedResourceParameters).Return("test-resource", nil, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_async.NewMockFutureScope(mockCtrl)
+			creatorMock := mock_async.NewMockCreator(mockCtrl)
+			specMock := mock_azure.NewMockResourceSpecGetterWithSpecHash(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), creatorMock.EXPECT(), specMock.EXPECT())
+
+			s := New(scopeMock, creatorMock, nil)
+			result, err := s.CreateOrUpdateResource(context.TODO(), specMock, "test-service")
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(result).To(Equal(tc.expectedResult))
+			}
+		})
+	}
+}
+
 // TestDeleteResource tests the DeleteResource function.
 func TestDeleteResource(t *testing.T) {
 	testcases := []struct {
@@ -0,0 +1,279 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+)
+
+// baseURL is the host used for the resource and operation URLs of the futures returned by the fake client.
+const baseURL = "https://fake.management.azure.com"
+
+// Method is an operation of the fake client an error can be injected into.
+type Method string
+
+const (
+	// GetMethod is the Get operation.
+	GetMethod Method = "Get"
+	// CreateOrUpdateMethod is the CreateOrUpdateAsync operation.
+	CreateOrUpdateMethod Method = "CreateOrUpdate"
+	// DeleteMethod is the DeleteAsync operation.
+	DeleteMethod Method = "Delete"
+)
+
+// CreateOrUpdateFunc returns the resource stored by a create or update, given its spec and parameters.
+type CreateOrUpdateFunc func(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (interface{}, error)
+
+// Client is an in-memory implementation of async.Creator and async.Deleter. It keeps the resources it creates by
+// resource group and name, which are case-insensitive as in Azure Resource Manager, and can simulate long-running
+// operations and failures.
+type Client struct {
+	// Polls is the number of times IsDone reports an operation as still in progress before it completes.
+	// Operations complete synchronously, without returning a future, when it is 0.
+	Polls int
+
+	// CreateOrUpdateFunc returns the resource stored by CreateOrUpdateAsync. Defaults to storing the parameters.
+	CreateOrUpdateFunc CreateOrUpdateFunc
+
+	mu         sync.Mutex
+	resources  map[string]interface{}
+	errors     map[string]error
+	operations map[string]*operation
+	lastID     int
+}
+
+// operation is a long-running operation started by the fake client.
+type operation struct {
+	futureType string
+	key        string
+	resource   interface{}
+	polls      int
+	done       bool
+}
+
+var (
+	_ async.Creator = &Client{}
+	_ async.Deleter = &Client{}
+)
+
+// NewClient returns a fake client with no resources.
+func NewClient() *Client {
+	return &Client{
+		resources:  map[string]interface{}{},
+		errors:     map[string]error{},
+		operations: map[string]*operation{},
+	}
+}
+
+// SetResource stores a resource as if it already existed in Azure.
+func (c *Client) SetResource(resourceGroup, name string, resource interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resources[resourceKey(resourceGroup, name)] = resource
+}
+
+// GetResource returns a stored resource and whether it exists.
+func (c *Client) GetResource(resourceGroup, name string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resource, ok := c.resources[resourceKey(resourceGroup, name)]
+	return resource, ok
+}
+
+// InjectError makes every call of method on a resource fail with err, until it is reset with a nil error.
+func (c *Client) InjectError(method Method, resourceGroup, name string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := errorKey(method, resourceGroup, name)
+	if err == nil {
+		delete(c.errors, key)
+		return
+	}
+	c.errors[key] = err
+}
+
+// Get returns the resource of the spec, or a not found error if it doesn't exist.
+func (c *Client) Get(_ context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.errors[errorKey(GetMethod, spec.ResourceGroupName(), spec.ResourceName())]; err != nil {
+		return nil, err
+	}
+	resource, ok := c.resources[resourceKey(spec.ResourceGroupName(), spec.ResourceName())]
+	if !ok {
+		return nil, NotFoundError(spec.ResourceGroupName(), spec.ResourceName())
+	}
+	return resource, nil
+}
+
+// CreateOrUpdateAsync stores the resource of the spec. It returns a future instead of the resource if Polls is set.
+func (c *Client) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (result interface{}, future azureautorest.FutureAPI, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.errors[errorKey(CreateOrUpdateMethod, spec.ResourceGroupName(), spec.ResourceName())]; err != nil {
+		return nil, nil, err
+	}
+	resource := parameters
+	if c.CreateOrUpdateFunc != nil {
+		if resource, err = c.CreateOrUpdateFunc(ctx, spec, parameters); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	key := resourceKey(spec.ResourceGroupName(), spec.ResourceName())
+	if c.Polls == 0 {
+		c.resources[key] = resource
+		return resource, nil, nil
+	}
+	future, err = c.startOperation(http.MethodPut, infrav1.PutFuture, spec, resource)
+	return nil, future, err
+}
+
+// DeleteAsync removes the resource of the spec. It returns a future if Polls is set and the resource exists.
+func (c *Client) DeleteAsync(_ context.Context, spec azure.ResourceSpecGetter) (future azureautorest.FutureAPI, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.errors[errorKey(DeleteMethod, spec.ResourceGroupName(), spec.ResourceName())]; err != nil {
+		return nil, err
+	}
+	key := resourceKey(spec.ResourceGroupName(), spec.ResourceName())
+	if _, ok := c.resources[key]; !ok || c.Polls == 0 {
+		// Like Azure Resource Manager, deleting a resource which doesn't exist succeeds.
+		delete(c.resources, key)
+		return nil, nil
+	}
+	return c.startOperation(http.MethodDelete, infrav1.DeleteFuture, spec, nil)
+}
+
+// IsDone returns whether the operation of the future is complete. The resources are only changed once it is.
+func (c *Client) IsDone(_ context.Context, future azureautorest.FutureAPI) (isDone bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	op, err := c.getOperation(future)
+	if err != nil {
+		return false, err
+	}
+	if !op.done {
+		if op.polls > 0 {
+			op.polls--
+			return false, nil
+		}
+		op.done = true
+		if op.futureType == infrav1.DeleteFuture {
+			delete(c.resources, op.key)
+		} else {
+			c.resources[op.key] = op.resource
+		}
+	}
+	return true, nil
+}
+
+// Result returns the resource created or updated by the operation of the future, or nil for a delete.
+func (c *Client) Result(_ context.Context, future azureautorest.FutureAPI, futureType string) (result interface{}, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	op, err := c.getOperation(future)
+	if err != nil {
+		return nil, err
+	}
+	if !op.done {
+		return nil, errors.Errorf("operation %s is not done", future.PollingURL())
+	}
+	if op.futureType != futureType {
+		return nil, errors.Errorf("operation %s is a %s, not a %s", future.PollingURL(), op.futureType, futureType)
+	}
+	if futureType == infrav1.DeleteFuture {
+		return nil, nil
+	}
+	return op.resource, nil
+}
+
+// startOperation records a long-running operation and returns a future polling it, which survives being stored in
+// and restored from the status of a resource.
+func (c *Client) startOperation(method, futureType string, spec azure.ResourceSpecGetter, resource interface{}) (azureautorest.FutureAPI, error) {
+	c.lastID++
+	id := strconv.Itoa(c.lastID)
+
+	requestURL, err := url.Parse(fmt.Sprintf("%s/resourceGroups/%s/resources/%s", baseURL, url.PathEscape(spec.ResourceGroupName()), url.PathEscape(spec.ResourceName())))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build resource URL")
+	}
+	future, err := azureautorest.NewFutureFromResponse(&http.Response{
+		Status:     "202 Accepted",
+		StatusCode: http.StatusAccepted,
+		Header:     http.Header{"Location": []string{baseURL + "/operations/" + id}},
+		Request:    &http.Request{Method: method, URL: requestURL},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create future")
+	}
+
+	c.operations[id] = &operation{
+		futureType: futureType,
+		key:        resourceKey(spec.ResourceGroupName(), spec.ResourceName()),
+		resource:   resource,
+		polls:      c.Polls,
+	}
+	return &future, nil
+}
+
+// getOperation returns the operation polled by a future.
+func (c *Client) getOperation(future azureautorest.FutureAPI) (*operation, error) {
+	if future == nil {
+		return nil, errors.New("future is nil")
+	}
+	op, ok := c.operations[path.Base(future.PollingURL())]
+	if !ok {
+		return nil, errors.Errorf("unknown operation %s", future.PollingURL())
+	}
+	return op, nil
+}
+
+// NotFoundError returns the error Azure Resource Manager returns for a resource which doesn't exist.
+func NotFoundError(resourceGroup, name string) error {
+	return autorest.DetailedError{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("resource %s/%s not found", resourceGroup, name),
+	}
+}
+
+func resourceKey(resourceGroup, name string) string {
+	return strings.ToLower(resourceGroup + "/" + name)
+}
+
+func errorKey(method Method, resourceGroup, name string) string {
+	return string(method) + ":" + resourceKey(resourceGroup, name)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+type fakeSpec struct {
+	name          string
+	resourceGroup string
+	parameters    interface{}
+}
+
+func (s *fakeSpec) ResourceName() string      { return s.name }
+func (s *fakeSpec) OwnerResourceName() string { return "" }
+func (s *fakeSpec) ResourceGroupName() string { return s.resourceGroup }
+func (s *fakeSpec) Parameters(_ context.Context, _ interface{}) (interface{}, error) {
+	return s.parameters, nil
+}
+
+// futureStore keeps the futures of the async service in memory, as the status of a resource would.
+type futureStore struct {
+	futures []infrav1.Future
+}
+
+func (f *futureStore) SetLongRunningOperationState(future *infrav1.Future) {
+	f.DeleteLongRunningOperationState(future.Name, future.ServiceName, future.Type)
+	f.futures = append(f.futures, *future)
+}
+
+func (f *futureStore) GetLongRunningOperationState(name, service, futureType string) *infrav1.Future {
+	for i := range f.futures {
+		if f.futures[i].Name == name && f.futures[i].ServiceName == service && f.futures[i].Type == futureType {
+			return &f.futures[i]
+		}
+	}
+	return nil
+}
+
+func (f *futureStore) DeleteLongRunningOperationState(name, service, futureType string) {
+	for i := range f.futures {
+		if f.futures[i].Name == name && f.futures[i].ServiceName == service && f.futures[i].Type == futureType {
+			f.futures = append(f.futures[:i], f.futures[i+1:]...)
+			return
+		}
+	}
+}
+
+func (f *futureStore) UpdatePutStatus(clusterv1.ConditionType, string, error)    {}
+func (f *futureStore) UpdateDeleteStatus(clusterv1.ConditionType, string, error) {}
+func (f *futureStore) UpdatePatchStatus(clusterv1.ConditionType, string, error)  {}
+
+func TestClientSynchronousOperations(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.TODO()
+	client := NewClient()
+	spec := &fakeSpec{name: "my-resource", resourceGroup: "my-rg", parameters: "desired"}
+
+	_, err := client.Get(ctx, spec)
+	g.Expect(azure.ResourceNotFound(err)).To(BeTrue())
+
+	result, future, err := client.CreateOrUpdateAsync(ctx, spec, "desired")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(future).To(BeNil())
+	g.Expect(result).To(Equal("desired"))
+
+	result, err = client.Get(ctx, &fakeSpec{name: "MY-RESOURCE", resourceGroup: "My-RG"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal("desired"))
+
+	future, err = client.DeleteAsync(ctx, spec)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(future).To(BeNil())
+	_, ok := client.GetResource("my-rg", "my-resource")
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestClientInjectError(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.TODO()
+	client := NewClient()
+	client.SetResource("my-rg", "my-resource", "existing")
+	spec := &fakeSpec{name: "my-resource", resourceGroup: "my-rg"}
+	injected := errors.New("internal server error")
+
+	client.InjectError(DeleteMethod, "my-rg", "my-resource", injected)
+	_, err := client.DeleteAsync(ctx, spec)
+	g.Expect(err).To(MatchError(injected))
+	_, ok := client.GetResource("my-rg", "my-resource")
+	g.Expect(ok).To(BeTrue())
+
+	client.InjectError(DeleteMethod, "my-rg", "my-resource", nil)
+	_, err = client.DeleteAsync(ctx, spec)
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestClientWithAsyncService(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.TODO()
+	client := NewClient()
+	client.Polls = 1
+	store := &futureStore{}
+	svc := async.New(store, client, client)
+	spec := &fakeSpec{name: "my-resource", resourceGroup: "my-rg", parameters: "desired"}
+
+	// The operation starts and is stored as a future.
+	_, err := svc.CreateOrUpdateResource(ctx, spec, "test-service")
+	g.Expect(azure.IsOperationNotDoneError(err)).To(BeTrue())
+	g.Expect(store.GetLongRunningOperationState("my-resource", "test-service", infrav1.PutFuture)).NotTo(BeNil())
+	_, ok := client.GetResource("my-rg", "my-resource")
+	g.Expect(ok).To(BeFalse())
+
+	// The first poll reports the operation as still in progress.
+	_, err = svc.CreateOrUpdateResource(ctx, spec, "test-service")
+	g.Expect(azure.IsOperationNotDoneError(err)).To(BeTrue())
+
+	// The next poll completes the operation.
+	result, err := svc.CreateOrUpdateResource(ctx, spec, "test-service")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal("desired"))
+	g.Expect(store.futures).To(BeEmpty())
+
+	g.Expect(svc.DeleteResource(ctx, spec, "test-service")).NotTo(Succeed())
+	g.Expect(svc.DeleteResource(ctx, spec, "test-service")).NotTo(Succeed())
+	g.Expect(svc.DeleteResource(ctx, spec, "test-service")).To(Succeed())
+	_, ok = client.GetResource("my-rg", "my-resource")
+	g.Expect(ok).To(BeFalse())
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package faultinjection injects latency and errors into the operations of the async clients, so that the resilience
+// of the reconcilers can be tested without Azure misbehaving. It is only wired into the async service when CAPZ is
+// built with the faultinjection build tag.
+package faultinjection
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// ConfigPathEnvVar is the environment variable holding the path of the fault injection config, usually a file
+// mounted from a ConfigMap.
+const ConfigPathEnvVar = "CAPZ_FAULT_INJECTION_CONFIG"
+
+// Operation is an operation of the async clients that faults can be injected into.
+type Operation string
+
+const (
+	// GetOperation gets a resource.
+	GetOperation Operation = "Get"
+	// CreateOrUpdateOperation creates or updates a resource.
+	CreateOrUpdateOperation Operation = "CreateOrUpdate"
+	// DeleteOperation deletes a resource.
+	DeleteOperation Operation = "Delete"
+)
+
+// Config is the fault injection config.
+type Config struct {
+	// Rules are the faults to inject. Only the first rule matching an operation is applied.
+	Rules []Rule `json:"rules"`
+}
+
+// Rule injects a fault into the operations matching it.
+type Rule struct {
+	// Operations are the operations the rule applies to. Defaults to all operations.
+	Operations []Operation `json:"operations,omitempty"`
+
+	// ResourceGroup is the resource group of the resources the rule applies to. Defaults to all resource groups.
+	ResourceGroup string `json:"resourceGroup,omitempty"`
+
+	// ResourceName is a regular expression matching the names of the resources the rule applies to. Defaults to all
+	// resources.
+	ResourceName string `json:"resourceName,omitempty"`
+
+	// Probability is the probability, between 0 and 1, that the rule is applied to a matching operation. Defaults to 1.
+	Probability *float64 `json:"probability,omitempty"`
+
+	// Latency delays the operation.
+	Latency *metav1.Duration `json:"latency,omitempty"`
+
+	// StatusCode fails the operation with an Azure error with this HTTP status code, e.g. 429 to throttle it or 500
+	// to fail it.
+	StatusCode int `json:"statusCode,omitempty"`
+
+	// RetryAfter is the Retry-After header of the error.
+	RetryAfter *metav1.Duration `json:"retryAfter,omitempty"`
+
+	// Message is the message of the error.
+	Message string `json:"message,omitempty"`
+
+	resourceNameRegexp *regexp.Regexp
+}
+
+// Injector injects the faults of the config file at its path into operations. The config file is reloaded when it
+// changes, and no faults are injected while it doesn't exist.
+type Injector struct {
+	path    string
+	random  func() float64
+	mu      sync.Mutex
+	modTime time.Time
+	rules   []Rule
+}
+
+// NewInjector creates an injector reading its config from the file at the given path.
+func NewInjector(path string) *Injector {
+	return &Injector{
+		path:   path,
+		random: rand.Float64, //nolint:gosec // Fault injection doesn't need a cryptographically secure random number.
+	}
+}
+
+// Inject delays the operation on the resource and returns the error it should fail with, according to the first rule
+// matching it. It returns nil if the operation should proceed.
+func (i *Injector) Inject(ctx context.Context, operation Operation, resourceGroup, resourceName string) error {
+	rules, err := i.getRules()
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if !rule.matches(operation, resourceGroup, resourceName) {
+			continue
+		}
+		if rule.Probability != nil && i.random() >= *rule.Probability {
+			return nil
+		}
+		if rule.Latency != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(rule.Latency.Duration):
+			}
+		}
+		if rule.StatusCode != 0 {
+			return rule.error(operation, resourceGroup, resourceName)
+		}
+		return nil
+	}
+	return nil
+}
+
+// getRules returns the rules of the config file, reloading it if it changed since it was last read.
+func (i *Injector) getRules() ([]Rule, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.path == "" {
+		return nil, nil
+	}
+	info, err := os.Stat(i.path)
+	if os.IsNotExist(err) {
+		i.rules, i.modTime = nil, time.Time{}
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to stat fault injection config %s", i.path)
+	}
+	if info.ModTime().Equal(i.modTime) {
+		return i.rules, nil
+	}
+
+	data, err := os.ReadFile(i.path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read fault injection config %s", i.path)
+	}
+	rules, err := ParseConfig(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse fault injection config %s", i.path)
+	}
+	i.rules, i.modTime = rules, info.ModTime()
+	return i.rules, nil
+}
+
+// ParseConfig parses and validates a YAML or JSON fault injection config, and returns its rules.
+func ParseConfig(data []byte) ([]Rule, error) {
+	var config Config
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, err
+	}
+
+	for i := range config.Rules {
+		rule := &config.Rules[i]
+		if rule.Probability != nil && (*rule.Probability < 0 || *rule.Probability > 1) {
+			return nil, errors.Errorf("rules[%d].probability must be between 0 and 1, got %v", i, *rule.Probability)
+		}
+		if rule.StatusCode != 0 && (rule.StatusCode < 400 || rule.StatusCode > 599) {
+			return nil, errors.Errorf("rules[%d].statusCode must be an HTTP error status code, got %d", i, rule.StatusCode)
+		}
+		for _, operation := range rule.Operations {
+			if operation != GetOperation && operation != CreateOrUpdateOperation && operation != DeleteOperation {
+				return nil, errors.Errorf("rules[%d].operations has unknown operation %s", i, operation)
+			}
+		}
+		if rule.ResourceName != "" {
+			resourceNameRegexp, err := regexp.Compile(rule.ResourceName)
+			if err != nil {
+				return nil, errors.Wrapf(err, "rules[%d].resourceName is not a valid regular expression", i)
+			}
+			rule.resourceNameRegexp = resourceNameRegexp
+		}
+	}
+	return config.Rules, nil
+}
+
+// matches returns true if the rule applies to the operation on the resource.
+func (r *Rule) matches(operation Operation, resourceGroup, resourceName string) bool {
+	if len(r.Operations) > 0 {
+		found := false
+		for _, o := range r.Operations {
+			if o == operation {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.ResourceGroup != "" && r.ResourceGroup != resourceGroup {
+		return false
+	}
+	return r.resourceNameRegexp == nil || r.resourceNameRegexp.MatchString(resourceName)
+}
+
+// error returns the Azure error the operation fails with.
+func (r *Rule) error(operation Operation, resourceGroup, resourceName string) error {
+	message := r.Message
+	if message == "" {
+		message = "injected fault"
+	}
+	response := &http.Response{
+		StatusCode: r.StatusCode,
+		Header:     http.Header{},
+	}
+	if r.RetryAfter != nil {
+		response.Header.Set("Retry-After", strconv.Itoa(int(r.RetryAfter.Seconds())))
+	}
+	return autorest.DetailedError{
+		Original:    errors.Errorf("%s on %s/%s", message, resourceGroup, resourceName),
+		PackageType: "faultinjection",
+		Method:      string(operation),
+		StatusCode:  r.StatusCode,
+		Message:     message,
+		Response:    response,
+	}
+}
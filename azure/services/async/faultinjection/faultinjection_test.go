@@ -0,0 +1,214 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faultinjection
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+func TestParseConfig(t *testing.T) {
+	testcases := []struct {
+		name          string
+		config        string
+		expectedRules int
+		expectedError string
+	}{
+		{
+			name: "valid config",
+			config: `
+rules:
+- operations: [Get, CreateOrUpdate]
+  resourceGroup: my-rg
+  resourceName: "^my-cluster-.*"
+  probability: 0.5
+  latency: 2s
+- operations: [Delete]
+  statusCode: 429
+  retryAfter: 30s
+`,
+			expectedRules: 2,
+		},
+		{
+			name:          "empty config",
+			config:        "",
+			expectedRules: 0,
+		},
+		{
+			name:          "unknown field",
+			config:        "rules:\n- latncy: 2s\n",
+			expectedError: "unknown field",
+		},
+		{
+			name:          "probability greater than 1",
+			config:        "rules:\n- probability: 2\n",
+			expectedError: "rules[0].probability must be between 0 and 1, got 2",
+		},
+		{
+			name:          "status code that isn't an error",
+			config:        "rules:\n- statusCode: 200\n",
+			expectedError: "rules[0].statusCode must be an HTTP error status code, got 200",
+		},
+		{
+			name:          "unknown operation",
+			config:        "rules:\n- operations: [List]\n",
+			expectedError: "rules[0].operations has unknown operation List",
+		},
+		{
+			name:          "invalid resource name regular expression",
+			config:        "rules:\n- resourceName: \"(\"\n",
+			expectedError: "rules[0].resourceName is not a valid regular expression",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			rules, err := ParseConfig([]byte(tc.config))
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(rules).To(HaveLen(tc.expectedRules))
+			}
+		})
+	}
+}
+
+func TestInject(t *testing.T) {
+	testcases := []struct {
+		name           string
+		config         string
+		random         float64
+		operation      Operation
+		resourceGroup  string
+		resourceName   string
+		expectedStatus int
+		expectedDelay  time.Duration
+		expectedRetry  string
+	}{
+		{
+			name:           "throttles a matching operation",
+			config:         "rules:\n- operations: [Get]\n  statusCode: 429\n  retryAfter: 30s\n",
+			operation:      GetOperation,
+			resourceGroup:  "my-rg",
+			resourceName:   "my-vm",
+			expectedStatus: http.StatusTooManyRequests,
+			expectedRetry:  "30",
+		},
+		{
+			name:          "does not inject faults into other operations",
+			config:        "rules:\n- operations: [Delete]\n  statusCode: 500\n",
+			operation:     CreateOrUpdateOperation,
+			resourceGroup: "my-rg",
+			resourceName:  "my-vm",
+		},
+		{
+			name:          "does not inject faults into other resource groups",
+			config:        "rules:\n- resourceGroup: other-rg\n  statusCode: 500\n",
+			operation:     GetOperation,
+			resourceGroup: "my-rg",
+			resourceName:  "my-vm",
+		},
+		{
+			name:          "does not inject faults into resources with other names",
+			config:        "rules:\n- resourceName: \"^my-lb$\"\n  statusCode: 500\n",
+			operation:     GetOperation,
+			resourceGroup: "my-rg",
+			resourceName:  "my-vm",
+		},
+		{
+			name:          "does not inject faults when the probability isn't met",
+			config:        "rules:\n- probability: 0.25\n  statusCode: 500\n",
+			random:        0.5,
+			operation:     DeleteOperation,
+			resourceGroup: "my-rg",
+			resourceName:  "my-vm",
+		},
+		{
+			name:           "applies only the first matching rule",
+			config:         "rules:\n- resourceName: \"^my-\"\n  statusCode: 500\n- statusCode: 429\n",
+			random:         0.1,
+			operation:      DeleteOperation,
+			resourceGroup:  "my-rg",
+			resourceName:   "my-vm",
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:          "delays a matching operation",
+			config:        "rules:\n- latency: 10ms\n",
+			operation:     CreateOrUpdateOperation,
+			resourceGroup: "my-rg",
+			resourceName:  "my-vm",
+			expectedDelay: 10 * time.Millisecond,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			g.Expect(os.WriteFile(path, []byte(tc.config), 0600)).To(Succeed())
+			injector := NewInjector(path)
+			injector.random = func() float64 { return tc.random }
+
+			start := time.Now()
+			err := injector.Inject(context.TODO(), tc.operation, tc.resourceGroup, tc.resourceName)
+			g.Expect(time.Since(start)).To(BeNumerically(">=", tc.expectedDelay))
+			if tc.expectedStatus == 0 {
+				g.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			var detailedError autorest.DetailedError
+			g.Expect(err).To(BeAssignableToTypeOf(detailedError))
+			detailedError = err.(autorest.DetailedError)
+			g.Expect(detailedError.StatusCode).To(Equal(tc.expectedStatus))
+			g.Expect(detailedError.Response.Header.Get("Retry-After")).To(Equal(tc.expectedRetry))
+		})
+	}
+}
+
+func TestInjectReloadsConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	injector := NewInjector(path)
+	g.Expect(injector.Inject(context.TODO(), GetOperation, "my-rg", "my-vm")).To(Succeed())
+
+	g.Expect(os.WriteFile(path, []byte("rules:\n- statusCode: 404\n"), 0600)).To(Succeed())
+	g.Expect(azure.ResourceNotFound(injector.Inject(context.TODO(), GetOperation, "my-rg", "my-vm"))).To(BeTrue())
+
+	g.Expect(os.WriteFile(path, []byte("rules: []\n"), 0600)).To(Succeed())
+	g.Expect(os.Chtimes(path, time.Now(), time.Now().Add(time.Minute))).To(Succeed())
+	g.Expect(injector.Inject(context.TODO(), GetOperation, "my-rg", "my-vm")).To(Succeed())
+
+	g.Expect(os.Remove(path)).To(Succeed())
+	g.Expect(injector.Inject(context.TODO(), GetOperation, "my-rg", "my-vm")).To(Succeed())
+}
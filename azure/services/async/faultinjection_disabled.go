@@ -0,0 +1,26 @@
+//go:build !faultinjection
+// +build !faultinjection
+
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package async
+
+// withFaultInjection returns the clients unchanged, as faults are only injected when CAPZ is built with the
+// faultinjection build tag.
+func withFaultInjection(creator Creator, deleter Deleter) (Creator, Deleter) {
+	return creator, deleter
+}
@@ -0,0 +1,77 @@
+//go:build faultinjection
+// +build faultinjection
+
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package async
+
+import (
+	"context"
+	"os"
+
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/faultinjection"
+)
+
+// faultInjector injects the faults of the config file set by the CAPZ_FAULT_INJECTION_CONFIG environment variable.
+var faultInjector = faultinjection.NewInjector(os.Getenv(faultinjection.ConfigPathEnvVar))
+
+// withFaultInjection wraps the clients so that faults are injected into their operations.
+func withFaultInjection(creator Creator, deleter Deleter) (Creator, Deleter) {
+	if creator != nil {
+		creator = &faultInjectingCreator{Creator: creator}
+	}
+	if deleter != nil {
+		deleter = &faultInjectingDeleter{Deleter: deleter}
+	}
+	return creator, deleter
+}
+
+// faultInjectingCreator is a Creator injecting faults into the gets and creates or updates of resources.
+type faultInjectingCreator struct {
+	Creator
+}
+
+// Get gets the resource unless a fault is injected.
+func (c *faultInjectingCreator) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	if err := faultInjector.Inject(ctx, faultinjection.GetOperation, spec.ResourceGroupName(), spec.ResourceName()); err != nil {
+		return nil, err
+	}
+	return c.Creator.Get(ctx, spec)
+}
+
+// CreateOrUpdateAsync creates or updates the resource unless a fault is injected.
+func (c *faultInjectingCreator) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (result interface{}, future azureautorest.FutureAPI, err error) {
+	if err := faultInjector.Inject(ctx, faultinjection.CreateOrUpdateOperation, spec.ResourceGroupName(), spec.ResourceName()); err != nil {
+		return nil, nil, err
+	}
+	return c.Creator.CreateOrUpdateAsync(ctx, spec, parameters)
+}
+
+// faultInjectingDeleter is a Deleter injecting faults into the deletes of resources.
+type faultInjectingDeleter struct {
+	Deleter
+}
+
+// DeleteAsync deletes the resource unless a fault is injected.
+func (d *faultInjectingDeleter) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (future azureautorest.FutureAPI, err error) {
+	if err := faultInjector.Inject(ctx, faultinjection.DeleteOperation, spec.ResourceGroupName(), spec.ResourceName()); err != nil {
+		return nil, err
+	}
+	return d.Deleter.DeleteAsync(ctx, spec)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azurefirewalls
+
+import (
+	"context"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "azurefirewalls"
+
+// AzureFirewallScope defines the scope interface for an Azure Firewall service.
+type AzureFirewallScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	AzureFirewallSpec() azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope AzureFirewallScope
+	async.Reconciler
+}
+
+// New creates a new service.
+func New(scope AzureFirewallScope) *Service {
+	client := newClient(scope)
+	return &Service{
+		Scope:      scope,
+		Reconciler: async.New(scope, client, client),
+	}
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile idempotently creates or updates the Azure Firewall the node subnets egress through.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "azurefirewalls.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.AzureFirewallSpec()
+	if spec == nil {
+		return nil
+	}
+
+	_, err := s.CreateOrUpdateResource(ctx, spec, serviceName)
+	s.Scope.UpdatePutStatus(infrav1.AzureFirewallReadyCondition, serviceName, err)
+	return err
+}
+
+// Delete deletes the Azure Firewall.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "azurefirewalls.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.AzureFirewallSpec()
+	if spec == nil {
+		return nil
+	}
+
+	err := s.DeleteResource(ctx, spec, serviceName)
+	s.Scope.UpdateDeleteStatus(infrav1.AzureFirewallReadyCondition, serviceName, err)
+	return err
+}
+
+// IsManaged returns always returns true as the scope only returns a spec for an Azure Firewall created by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azurefirewalls
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/azurefirewalls/mock_azurefirewalls"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeFirewall = AzureFirewallSpec{
+		Name:          "my-cluster-firewall",
+		ResourceGroup: "my-rg",
+		Location:      "westus",
+		ClusterName:   "my-cluster",
+		SubnetID:      "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/AzureFirewallSubnet",
+		PublicIPID:    "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-cluster-firewall-pip",
+		SourceCIDRs:   []string{"10.1.0.0/16"},
+		APIServerPort: 6443,
+	}
+	errFake      = errors.New("this is an error")
+	notDoneError = azure.NewOperationNotDoneError(&infrav1.Future{})
+)
+
+func TestReconcileAzureFirewall(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if no azure firewall spec is found",
+			expectedError: "",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.AzureFirewallSpec().Return(nil)
+			},
+		},
+		{
+			name:          "create azure firewall succeeds",
+			expectedError: "",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeFirewall)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeFirewall, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.AzureFirewallReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "create azure firewall not done",
+			expectedError: notDoneError.Error(),
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeFirewall)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeFirewall, serviceName).Return(nil, notDoneError)
+				s.UpdatePutStatus(infrav1.AzureFirewallReadyCondition, serviceName, notDoneError)
+			},
+		},
+		{
+			name:          "create azure firewall fails",
+			expectedError: errFake.Error(),
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeFirewall)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeFirewall, serviceName).Return(nil, errFake)
+				s.UpdatePutStatus(infrav1.AzureFirewallReadyCondition, serviceName, errFake)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_azurefirewalls.NewMockAzureFirewallScope(mockCtrl)
+			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), reconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: reconcilerMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteAzureFirewall(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if no azure firewall spec is found",
+			expectedError: "",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.AzureFirewallSpec().Return(nil)
+			},
+		},
+		{
+			name:          "delete azure firewall succeeds",
+			expectedError: "",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeFirewall)
+				r.DeleteResource(gomockinternal.AContext(), &fakeFirewall, serviceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.AzureFirewallReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "delete azure firewall fails",
+			expectedError: errFake.Error(),
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeFirewall)
+				r.DeleteResource(gomockinternal.AContext(), &fakeFirewall, serviceName).Return(errFake)
+				s.UpdateDeleteStatus(infrav1.AzureFirewallReadyCondition, serviceName, errFake)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_azurefirewalls.NewMockAzureFirewallScope(mockCtrl)
+			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), reconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: reconcilerMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azurefirewalls
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+const (
+	// applicationRuleCollectionName is the name of the application rule collection managed by CAPZ.
+	applicationRuleCollectionName = "capz-egress"
+	// networkRuleCollectionName is the name of the network rule collection managed by CAPZ.
+	networkRuleCollectionName = "capz-egress"
+	// ruleCollectionPriority is the priority of the rule collections managed by CAPZ.
+	ruleCollectionPriority = 100
+)
+
+// RequiredFQDNs are the FQDNs the nodes of a cluster need to reach over HTTPS to bootstrap and run Kubernetes.
+var RequiredFQDNs = []string{
+	"management.azure.com",
+	"login.microsoftonline.com",
+	"mcr.microsoft.com",
+	"*.data.mcr.microsoft.com",
+	"registry.k8s.io",
+	"*.pkg.dev",
+	"packages.microsoft.com",
+	"acs-mirror.azureedge.net",
+	"*.blob.core.windows.net",
+}
+
+// AzureFirewallSpec defines the specification for an Azure Firewall.
+type AzureFirewallSpec struct {
+	Name           string
+	ResourceGroup  string
+	Location       string
+	ClusterName    string
+	SubnetID       string
+	PublicIPID     string
+	SourceCIDRs    []string
+	APIServerPort  int32
+	AllowedFQDNs   []string
+	AdditionalTags infrav1.Tags
+}
+
+// ResourceName returns the name of the Azure Firewall.
+func (s *AzureFirewallSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *AzureFirewallSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for Azure Firewalls.
+func (s *AzureFirewallSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the Azure Firewall.
+func (s *AzureFirewallSpec) Parameters(ctx context.Context, existing interface{}) (parameters interface{}, err error) {
+	if existing != nil {
+		existingFirewall, ok := existing.(network.AzureFirewall)
+		if !ok {
+			return nil, errors.Errorf("%T is not a network.AzureFirewall", existing)
+		}
+		// Azure Firewall already exists
+		// only update it if the FQDNs the nodes are allowed to reach changed.
+		if !s.shouldUpdate(existingFirewall) {
+			return nil, nil
+		}
+	}
+
+	return network.AzureFirewall{
+		Location: ptr.To(s.Location),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        ptr.To(s.Name),
+			Role:        ptr.To(infrav1.Firewall),
+			Additional:  s.AdditionalTags,
+		})),
+		AzureFirewallPropertiesFormat: &network.AzureFirewallPropertiesFormat{
+			Sku: &network.AzureFirewallSku{
+				Name: network.AzureFirewallSkuNameAZFWVNet,
+				Tier: network.AzureFirewallSkuTierStandard,
+			},
+			ThreatIntelMode: network.AzureFirewallThreatIntelModeAlert,
+			IPConfigurations: &[]network.AzureFirewallIPConfiguration{
+				{
+					Name: ptr.To(fmt.Sprintf("%s-%s", s.Name, "ipconfig")),
+					AzureFirewallIPConfigurationPropertiesFormat: &network.AzureFirewallIPConfigurationPropertiesFormat{
+						Subnet:          &network.SubResource{ID: ptr.To(s.SubnetID)},
+						PublicIPAddress: &network.SubResource{ID: ptr.To(s.PublicIPID)},
+					},
+				},
+			},
+			ApplicationRuleCollections: &[]network.AzureFirewallApplicationRuleCollection{
+				{
+					Name: ptr.To(applicationRuleCollectionName),
+					AzureFirewallApplicationRuleCollectionPropertiesFormat: &network.AzureFirewallApplicationRuleCollectionPropertiesFormat{
+						Priority: ptr.To[int32](ruleCollectionPriority),
+						Action:   &network.AzureFirewallRCAction{Type: network.AzureFirewallRCActionTypeAllow},
+						Rules: &[]network.AzureFirewallApplicationRule{
+							{
+								Name:            ptr.To("allowed-fqdns"),
+								SourceAddresses: ptr.To(s.SourceCIDRs),
+								Protocols: &[]network.AzureFirewallApplicationRuleProtocol{
+									{ProtocolType: network.AzureFirewallApplicationRuleProtocolTypeHTTPS, Port: ptr.To[int32](443)},
+								},
+								TargetFqdns: ptr.To(s.targetFQDNs()),
+							},
+						},
+					},
+				},
+			},
+			NetworkRuleCollections: &[]network.AzureFirewallNetworkRuleCollection{
+				{
+					Name: ptr.To(networkRuleCollectionName),
+					AzureFirewallNetworkRuleCollectionPropertiesFormat: &network.AzureFirewallNetworkRuleCollectionPropertiesFormat{
+						Priority: ptr.To[int32](ruleCollectionPriority),
+						Action:   &network.AzureFirewallRCAction{Type: network.AzureFirewallRCActionTypeAllow},
+						Rules: &[]network.AzureFirewallNetworkRule{
+							{
+								Name:                 ptr.To("ntp"),
+								Protocols:            &[]network.AzureFirewallNetworkRuleProtocol{network.AzureFirewallNetworkRuleProtocolUDP},
+								SourceAddresses:      ptr.To(s.SourceCIDRs),
+								DestinationAddresses: &[]string{"*"},
+								DestinationPorts:     &[]string{"123"},
+							},
+							{
+								// The nodes reach a public API server through its public IP, which the firewall can't
+								// filter by FQDN.
+								Name:                 ptr.To("apiserver"),
+								Protocols:            &[]network.AzureFirewallNetworkRuleProtocol{network.AzureFirewallNetworkRuleProtocolTCP},
+								SourceAddresses:      ptr.To(s.SourceCIDRs),
+								DestinationAddresses: &[]string{"*"},
+								DestinationPorts:     &[]string{strconv.Itoa(int(s.APIServerPort))},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// targetFQDNs returns the FQDNs the nodes are allowed to reach, sorted and without duplicates.
+func (s *AzureFirewallSpec) targetFQDNs() []string {
+	seen := make(map[string]bool, len(RequiredFQDNs)+len(s.AllowedFQDNs))
+	fqdns := make([]string, 0, len(RequiredFQDNs)+len(s.AllowedFQDNs))
+	for _, fqdn := range append(append([]string{}, RequiredFQDNs...), s.AllowedFQDNs...) {
+		if !seen[fqdn] {
+			seen[fqdn] = true
+			fqdns = append(fqdns, fqdn)
+		}
+	}
+	sort.Strings(fqdns)
+	return fqdns
+}
+
+// shouldUpdate returns true if the application rule collection of an existing Azure Firewall doesn't allow the nodes
+// to reach exactly the FQDNs of the spec.
+func (s *AzureFirewallSpec) shouldUpdate(existing network.AzureFirewall) bool {
+	if existing.AzureFirewallPropertiesFormat == nil || existing.ApplicationRuleCollections == nil {
+		return true
+	}
+	for _, collection := range *existing.ApplicationRuleCollections {
+		if ptr.Deref(collection.Name, "") != applicationRuleCollectionName {
+			continue
+		}
+		if collection.AzureFirewallApplicationRuleCollectionPropertiesFormat == nil || collection.Rules == nil || len(*collection.Rules) != 1 {
+			return true
+		}
+		existingFQDNs := append([]string{}, ptr.Deref((*collection.Rules)[0].TargetFqdns, []string{})...)
+		sort.Strings(existingFQDNs)
+		return cmp.Diff(s.targetFQDNs(), existingFQDNs) != ""
+	}
+	return true
+}
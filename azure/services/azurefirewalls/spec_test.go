@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azurefirewalls
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+func existingFirewallWithFQDNs(fqdns []string) network.AzureFirewall {
+	return network.AzureFirewall{
+		AzureFirewallPropertiesFormat: &network.AzureFirewallPropertiesFormat{
+			ApplicationRuleCollections: &[]network.AzureFirewallApplicationRuleCollection{
+				{
+					Name: ptr.To(applicationRuleCollectionName),
+					AzureFirewallApplicationRuleCollectionPropertiesFormat: &network.AzureFirewallApplicationRuleCollectionPropertiesFormat{
+						Rules: &[]network.AzureFirewallApplicationRule{{TargetFqdns: ptr.To(fqdns)}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestParameters(t *testing.T) {
+	withAllowedFQDNs := fakeFirewall
+	withAllowedFQDNs.AllowedFQDNs = []string{"example.com", "mcr.microsoft.com"}
+
+	testcases := []struct {
+		name     string
+		spec     AzureFirewallSpec
+		existing interface{}
+		expect   func(g *WithT, result interface{})
+	}{
+		{
+			name:     "new azure firewall",
+			spec:     fakeFirewall,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(network.AzureFirewall{}))
+				fw := result.(network.AzureFirewall)
+				g.Expect(fw.Sku.Tier).To(Equal(network.AzureFirewallSkuTierStandard))
+				g.Expect(*fw.IPConfigurations).To(HaveLen(1))
+				g.Expect((*fw.IPConfigurations)[0].Subnet.ID).To(Equal(ptr.To(fakeFirewall.SubnetID)))
+				g.Expect((*fw.IPConfigurations)[0].PublicIPAddress.ID).To(Equal(ptr.To(fakeFirewall.PublicIPID)))
+				rule := (*(*fw.ApplicationRuleCollections)[0].Rules)[0]
+				g.Expect(*rule.SourceAddresses).To(Equal([]string{"10.1.0.0/16"}))
+				g.Expect(*rule.TargetFqdns).To(ConsistOf(RequiredFQDNs))
+				networkRules := *(*fw.NetworkRuleCollections)[0].Rules
+				g.Expect(networkRules).To(HaveLen(2))
+				g.Expect(*networkRules[1].DestinationPorts).To(Equal([]string{"6443"}))
+			},
+		},
+		{
+			name:     "new azure firewall with allowed FQDNs",
+			spec:     withAllowedFQDNs,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(network.AzureFirewall{}))
+				fw := result.(network.AzureFirewall)
+				rule := (*(*fw.ApplicationRuleCollections)[0].Rules)[0]
+				g.Expect(*rule.TargetFqdns).To(HaveLen(len(RequiredFQDNs) + 1))
+				g.Expect(*rule.TargetFqdns).To(ContainElement("example.com"))
+			},
+		},
+		{
+			name:     "existing azure firewall with the same FQDNs",
+			spec:     withAllowedFQDNs,
+			existing: existingFirewallWithFQDNs(append([]string{"example.com"}, RequiredFQDNs...)),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:     "existing azure firewall missing an allowed FQDN",
+			spec:     withAllowedFQDNs,
+			existing: existingFirewallWithFQDNs(RequiredFQDNs),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(network.AzureFirewall{}))
+			},
+		},
+		{
+			name:     "existing azure firewall without rule collections",
+			spec:     fakeFirewall,
+			existing: network.AzureFirewall{AzureFirewallPropertiesFormat: &network.AzureFirewallPropertiesFormat{}},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(network.AzureFirewall{}))
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.expect(g, result)
+		})
+	}
+}
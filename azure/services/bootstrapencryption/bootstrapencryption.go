@@ -0,0 +1,169 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapencryption
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// stubScript is the custom data given to virtual machines whose bootstrap data is encrypted. It gets a Key Vault token
+// for the managed identity of the virtual machine from the instance metadata service, unwraps the data encryption key,
+// decrypts the bootstrap cloud-config and runs the cloud-init modules used to bootstrap Kubernetes nodes with it.
+// The runcmd script is run directly rather than with the scripts_user module, which would run this script again.
+const stubScript = `#!/bin/bash
+# Decrypts the bootstrap data of this machine with the Key Vault key %[1]s and applies it with cloud-init.
+set -euo pipefail
+
+key_id='%[1]s'
+resource='%[2]s'
+client_id='%[3]s'
+wrapped_key='%[4]s'
+iv='%[5]s'
+ciphertext='%[6]s'
+config=/etc/cloud/cloud.cfg.d/99-capz-bootstrap.cfg
+
+token_url="http://169.254.169.254/metadata/identity/oauth2/token?api-version=2018-02-01&resource=${resource}"
+if [ -n "${client_id}" ]; then
+  token_url="${token_url}&client_id=${client_id}"
+fi
+token=$(curl -sSf --retry 10 --retry-delay 5 -H Metadata:true "${token_url}" | grep -o '"access_token":"[^"]*"' | cut -d'"' -f4)
+
+key=$(curl -sSf --retry 10 --retry-delay 5 -X POST -H "Authorization: Bearer ${token}" -H "Content-Type: application/json" \
+  -d "{\"alg\":\"RSA-OAEP-256\",\"value\":\"${wrapped_key}\"}" "${key_id}/unwrapkey?api-version=7.0" | grep -o '"value":"[^"]*"' | cut -d'"' -f4 | tr '_-' '/+')
+while [ $(( ${#key} %% 4 )) -ne 0 ]; do key="${key}="; done
+key=$(echo -n "${key}" | base64 -d | od -An -v -tx1 | tr -d ' \n')
+
+umask 077
+trap 'rm -f "${config}"' EXIT
+echo "${ciphertext}" | base64 -d | openssl enc -d -aes-256-cbc -K "${key}" -iv "${iv}" | gunzip > "${config}"
+
+for module in disk_setup mounts write_files ntp users_groups runcmd; do
+  cloud-init single --name "${module}" --frequency always
+done
+if [ -f /var/lib/cloud/instance/scripts/runcmd ]; then
+  sh /var/lib/cloud/instance/scripts/runcmd
+fi
+`
+
+// Encrypter envelope-encrypts the bootstrap data of virtual machines.
+type Encrypter struct {
+	client
+	resource string
+}
+
+// New creates a new Encrypter using the credentials and cloud environment of the given scope.
+func New(auth azure.Authorizer) (*Encrypter, error) {
+	env, err := azureautorest.EnvironmentFromName(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the cloud environment")
+	}
+
+	c, err := newClient(auth, env.ResourceIdentifiers.KeyVault)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Encrypter{
+		client:   c,
+		resource: env.ResourceIdentifiers.KeyVault,
+	}, nil
+}
+
+// Encrypt encrypts the base64 encoded bootstrap data with a random data encryption key, wraps the data encryption key
+// with the Key Vault key of the given encryption settings, and returns the base64 encoded script that decrypts and
+// applies the bootstrap data on the virtual machine.
+func (e *Encrypter) Encrypt(ctx context.Context, encryption infrav1.BootstrapEncryption, bootstrapData string) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "bootstrapencryption.Encrypter.Encrypt")
+	defer done()
+
+	plaintext, err := base64.StdEncoding.DecodeString(bootstrapData)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode bootstrap data")
+	}
+
+	key, iv, ciphertext, err := encrypt(plaintext)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encrypt bootstrap data")
+	}
+
+	wrappedKey, err := e.WrapKey(ctx, encryption.KeyID, key)
+	if err != nil {
+		return "", err
+	}
+
+	script := fmt.Sprintf(stubScript,
+		encryption.KeyID,
+		url.QueryEscape(e.resource),
+		url.QueryEscape(encryption.IdentityClientID),
+		wrappedKey,
+		hex.EncodeToString(iv),
+		base64.StdEncoding.EncodeToString(ciphertext),
+	)
+
+	return base64.StdEncoding.EncodeToString([]byte(script)), nil
+}
+
+// encrypt compresses the plaintext and encrypts it with AES-256-CBC and PKCS#7 padding, as expected by `openssl enc`,
+// using a random key and initialization vector.
+func encrypt(plaintext []byte) (key, iv, ciphertext []byte, err error) {
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write(plaintext); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, nil, nil, err
+	}
+	iv = make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, nil, nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	padded := compressed.Bytes()
+	padding := aes.BlockSize - len(padded)%aes.BlockSize
+	padded = append(padded, bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	ciphertext = make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+
+	return key, iv, ciphertext, nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapencryption
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"regexp"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// fakeClient "wraps" keys by encoding them, so that tests can recover the data encryption key.
+type fakeClient struct {
+	keyID string
+}
+
+func (f *fakeClient) WrapKey(_ context.Context, keyID string, key []byte) (string, error) {
+	f.keyID = keyID
+	return base64.RawURLEncoding.EncodeToString(key), nil
+}
+
+func scriptVariable(g *WithT, script, name string) string {
+	matches := regexp.MustCompile(`(?m)^` + name + `='([^']*)'$`).FindStringSubmatch(script)
+	g.Expect(matches).To(HaveLen(2))
+	return matches[1]
+}
+
+func TestEncrypt(t *testing.T) {
+	g := NewWithT(t)
+
+	bootstrapData := []byte("#cloud-config\nruncmd:\n- kubeadm join --token abcdef.0123456789abcdef\n")
+	keyID := "https://my-vault.vault.azure.net/keys/my-key/0123456789abcdef"
+
+	fake := &fakeClient{}
+	e := &Encrypter{client: fake, resource: "https://vault.azure.net"}
+
+	customData, err := e.Encrypt(context.TODO(), infrav1.BootstrapEncryption{KeyID: keyID, IdentityClientID: "my-client-id"}, base64.StdEncoding.EncodeToString(bootstrapData))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(fake.keyID).To(Equal(keyID))
+
+	decoded, err := base64.StdEncoding.DecodeString(customData)
+	g.Expect(err).NotTo(HaveOccurred())
+	script := string(decoded)
+	g.Expect(script).NotTo(ContainSubstring("kubeadm join"))
+	g.Expect(scriptVariable(g, script, "key_id")).To(Equal(keyID))
+	g.Expect(scriptVariable(g, script, "resource")).To(Equal("https%3A%2F%2Fvault.azure.net"))
+	g.Expect(scriptVariable(g, script, "client_id")).To(Equal("my-client-id"))
+	g.Expect(script).To(ContainSubstring("$(( ${#key} % 4 ))"))
+
+	key, err := base64.RawURLEncoding.DecodeString(scriptVariable(g, script, "wrapped_key"))
+	g.Expect(err).NotTo(HaveOccurred())
+	iv, err := hex.DecodeString(scriptVariable(g, script, "iv"))
+	g.Expect(err).NotTo(HaveOccurred())
+	ciphertext, err := base64.StdEncoding.DecodeString(scriptVariable(g, script, "ciphertext"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	block, err := aes.NewCipher(key)
+	g.Expect(err).NotTo(HaveOccurred())
+	padded := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(padded, ciphertext)
+	padding := int(padded[len(padded)-1])
+	g.Expect(padding).To(BeNumerically(">", 0))
+	g.Expect(padding).To(BeNumerically("<=", aes.BlockSize))
+
+	zr, err := gzip.NewReader(bytes.NewReader(padded[:len(padded)-padding]))
+	g.Expect(err).NotTo(HaveOccurred())
+	plaintext, err := io.ReadAll(zr)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(plaintext).To(Equal(bootstrapData))
+}
+
+func TestEncryptUsesRandomKeys(t *testing.T) {
+	g := NewWithT(t)
+
+	key1, iv1, _, err := encrypt([]byte("data"))
+	g.Expect(err).NotTo(HaveOccurred())
+	key2, iv2, _, err := encrypt([]byte("data"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(key1).To(HaveLen(32))
+	g.Expect(iv1).To(HaveLen(aes.BlockSize))
+	g.Expect(key1).NotTo(Equal(key2))
+	g.Expect(iv1).NotTo(Equal(iv2))
+}
+
+func TestParseKeyID(t *testing.T) {
+	tests := []struct {
+		name            string
+		keyID           string
+		expectedBaseURL string
+		expectedName    string
+		expectedVersion string
+		expectErr       bool
+	}{
+		{
+			name:            "versioned key ID",
+			keyID:           "https://my-vault.vault.azure.net/keys/my-key/0123456789abcdef",
+			expectedBaseURL: "https://my-vault.vault.azure.net",
+			expectedName:    "my-key",
+			expectedVersion: "0123456789abcdef",
+		},
+		{
+			name:      "key ID without a version",
+			keyID:     "https://my-vault.vault.azure.net/keys/my-key",
+			expectErr: true,
+		},
+		{
+			name:      "secret ID",
+			keyID:     "https://my-vault.vault.azure.net/secrets/my-secret/0123456789abcdef",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			baseURL, name, version, err := parseKeyID(tc.keyID)
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(baseURL).To(Equal(tc.expectedBaseURL))
+			g.Expect(name).To(Equal(tc.expectedName))
+			g.Expect(version).To(Equal(tc.expectedVersion))
+		})
+	}
+}
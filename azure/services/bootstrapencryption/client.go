@@ -0,0 +1,103 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapencryption
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/jongio/azidext/go/azidext"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	WrapKey(context.Context, string, []byte) (string, error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	keys keyvault.BaseClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new Key Vault keys client using the token credential of the cluster for the given Key Vault
+// resource.
+func newClient(auth azure.Authorizer, resource string) (*azureClient, error) {
+	if auth.Token() == nil {
+		return nil, errors.New("bootstrap encryption requires the cluster to use an AzureClusterIdentity")
+	}
+
+	scope := strings.TrimSuffix(resource, "/") + "/.default"
+	c := newKeysClient(azidext.NewTokenCredentialAdapter(auth.Token(), []string{scope}))
+	return &azureClient{c}, nil
+}
+
+// newKeysClient creates a new Key Vault keys client.
+func newKeysClient(authorizer autorest.Authorizer) keyvault.BaseClient {
+	keysClient := keyvault.New()
+	azure.SetAutoRestClientDefaults(&keysClient.Client, authorizer)
+	return keysClient
+}
+
+// WrapKey wraps the given key with the Key Vault key identified by keyID, and returns the wrapped key as a URL-encoded
+// base64 string.
+func (ac *azureClient) WrapKey(ctx context.Context, keyID string, key []byte) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "bootstrapencryption.AzureClient.WrapKey")
+	defer done()
+
+	vaultBaseURL, keyName, keyVersion, err := parseKeyID(keyID)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := ac.keys.WrapKey(ctx, vaultBaseURL, keyName, keyVersion, keyvault.KeyOperationsParameters{
+		Algorithm: keyvault.RSAOAEP256,
+		Value:     ptr.To(base64.RawURLEncoding.EncodeToString(key)),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to wrap key with %s", keyID)
+	}
+	if result.Result == nil {
+		return "", errors.Errorf("Key Vault returned no wrapped key for %s", keyID)
+	}
+
+	return *result.Result, nil
+}
+
+// parseKeyID splits a versioned Key Vault key identifier into the vault URL, key name and key version.
+func parseKeyID(keyID string) (vaultBaseURL, keyName, keyVersion string, err error) {
+	u, err := url.Parse(keyID)
+	if err != nil {
+		return "", "", "", errors.Wrapf(err, "failed to parse Key Vault key ID %s", keyID)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 3 || parts[0] != "keys" {
+		return "", "", "", errors.Errorf("%s is not a versioned Key Vault key ID", keyID)
+	}
+
+	return u.Scheme + "://" + u.Host, parts[1], parts[2], nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package disks
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// DataDiskSpec defines the specification for a data disk of a single machine that is created before its VM, because
+// the VM API can't set its provisioned IOPS and throughput.
+type DataDiskSpec struct {
+	Name                string
+	ResourceGroup       string
+	Location            string
+	Zone                string
+	DiskSizeGB          int32
+	StorageAccountType  string
+	DiskIOPSReadWrite   *int64
+	DiskMBpsReadWrite   *int64
+	DiskEncryptionSetID string
+	ClusterName         string
+	AdditionalTags      infrav1.Tags
+}
+
+// ResourceName returns the name of the data disk.
+func (s *DataDiskSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the data disk.
+func (s *DataDiskSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for data disks.
+func (s *DataDiskSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the data disk.
+func (s *DataDiskSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		if _, ok := existing.(compute.Disk); !ok {
+			return nil, errors.Errorf("%T is not a compute.Disk", existing)
+		}
+		// The data disk options can't be changed after the machine is created.
+		return nil, nil
+	}
+
+	disk := compute.Disk{
+		Location: ptr.To(s.Location),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        ptr.To(s.Name),
+			Additional:  s.AdditionalTags,
+		})),
+		Sku: &compute.DiskSku{
+			Name: compute.DiskStorageAccountTypes(s.StorageAccountType),
+		},
+		DiskProperties: &compute.DiskProperties{
+			CreationData: &compute.CreationData{
+				CreateOption: compute.DiskCreateOptionEmpty,
+			},
+			DiskSizeGB:        ptr.To(s.DiskSizeGB),
+			DiskIOPSReadWrite: s.DiskIOPSReadWrite,
+			DiskMBpsReadWrite: s.DiskMBpsReadWrite,
+		},
+	}
+
+	if s.Zone != "" {
+		disk.Zones = &[]string{s.Zone}
+	}
+
+	if s.DiskEncryptionSetID != "" {
+		disk.Encryption = &compute.Encryption{
+			DiskEncryptionSetID: ptr.To(s.DiskEncryptionSetID),
+			Type:                compute.EncryptionTypeEncryptionAtRestWithCustomerKey,
+		}
+	}
+
+	return disk, nil
+}
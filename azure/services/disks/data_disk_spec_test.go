@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package disks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+func TestDataDiskSpec_Parameters(t *testing.T) {
+	testcases := []struct {
+		name          string
+		spec          DataDiskSpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name: "new data disk with provisioned IOPS and throughput",
+			spec: dataDiskSpec,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(compute.Disk{
+					Location: ptr.To("westus"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": ptr.To("owned"),
+						"Name": ptr.To("my-vm_etcddisk"),
+					},
+					Zones: &[]string{"1"},
+					Sku: &compute.DiskSku{
+						Name: compute.DiskStorageAccountTypesUltraSSDLRS,
+					},
+					DiskProperties: &compute.DiskProperties{
+						CreationData: &compute.CreationData{
+							CreateOption: compute.DiskCreateOptionEmpty,
+						},
+						DiskSizeGB:        ptr.To[int32](256),
+						DiskIOPSReadWrite: ptr.To[int64](20000),
+						DiskMBpsReadWrite: ptr.To[int64](500),
+					},
+				}))
+			},
+		},
+		{
+			name: "new data disk with a disk encryption set",
+			spec: DataDiskSpec{
+				Name:                "my-vm_etcddisk",
+				ResourceGroup:       "my-group",
+				Location:            "westus",
+				DiskSizeGB:          256,
+				StorageAccountType:  "UltraSSD_LRS",
+				DiskIOPSReadWrite:   ptr.To[int64](20000),
+				DiskEncryptionSetID: "my-des-id",
+				ClusterName:         "my-cluster",
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.Disk{}))
+				disk := result.(compute.Disk)
+				g.Expect(disk.Zones).To(BeNil())
+				g.Expect(disk.Encryption).To(Equal(&compute.Encryption{
+					DiskEncryptionSetID: ptr.To("my-des-id"),
+					Type:                compute.EncryptionTypeEncryptionAtRestWithCustomerKey,
+				}))
+				g.Expect(disk.DiskIOPSReadWrite).To(Equal(ptr.To[int64](20000)))
+				g.Expect(disk.DiskMBpsReadWrite).To(BeNil())
+			},
+		},
+		{
+			name:     "existing data disk",
+			spec:     dataDiskSpec,
+			existing: compute.Disk{},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:          "type cast error",
+			spec:          dataDiskSpec,
+			existing:      "I'm not compute.Disk",
+			expectedError: "string is not a compute.Disk",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				tc.expect(g, result)
+			}
+		})
+	}
+}
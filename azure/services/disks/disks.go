@@ -19,6 +19,9 @@ package disks
 import (
 	"context"
 
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
@@ -33,20 +36,28 @@ type DiskScope interface {
 	azure.ClusterDescriber
 	azure.AsyncStatusUpdater
 	DiskSpecs() []azure.ResourceSpecGetter
+	DiskSnapshotSpecs() []azure.ResourceSpecGetter
+	SharedDiskSpecs() []azure.ResourceSpecGetter
+	DataDiskSpecs() []azure.ResourceSpecGetter
 }
 
 // Service provides operations on Azure resources.
 type Service struct {
 	Scope DiskScope
 	async.Reconciler
+	snapshotReconciler async.Reconciler
+	sharedDiskGetter   async.Getter
 }
 
 // New creates a new disks service.
 func New(scope DiskScope) *Service {
 	client := newClient(scope)
+	snapshotsClient := newSnapshotsClient(scope)
 	return &Service{
-		Scope:      scope,
-		Reconciler: async.New(scope, nil, client),
+		Scope:              scope,
+		Reconciler:         async.New(scope, client, client),
+		snapshotReconciler: async.New(scope, snapshotsClient, nil),
+		sharedDiskGetter:   client,
 	}
 }
 
@@ -55,16 +66,38 @@ func (s *Service) Name() string {
 	return serviceName
 }
 
-// Reconcile on disk is currently no-op. OS disks should only be deleted and will create with the VM automatically.
+// Reconcile creates the shared data disks of a VM and its data disks with provisioned IOPS or throughput, which are
+// attached to the VM rather than created with it. Other disks are created with the VM automatically, and are only
+// deleted by this service.
 func (s *Service) Reconcile(ctx context.Context) error {
-	_, _, done := tele.StartSpanWithLogger(ctx, "disks.Service.Reconcile")
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.Service.Reconcile")
 	defer done()
 
-	// DisksReadyCondition is set in the VM service.
-	return nil
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	// We go through the list of SharedDiskSpecs and DataDiskSpecs to create each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one.
+	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
+	var result error
+	for _, diskSpec := range append(s.Scope.SharedDiskSpecs(), s.Scope.DataDiskSpecs()...) {
+		if _, err := s.CreateOrUpdateResource(ctx, diskSpec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	// DisksReadyCondition is set in the VM service once the VM is created, so it is only updated here when the disks
+	// created ahead of the VM aren't ready.
+	if result != nil {
+		s.Scope.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, result)
+	}
+	return result
 }
 
-// Delete deletes the disk associated with a VM.
+// Delete deletes the disk associated with a VM. When disk snapshots are enabled, the disks are snapshotted first.
+// Shared disks are only deleted once no other VM is attached to them.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.Service.Delete")
 	defer done()
@@ -72,11 +105,24 @@ func (s *Service) Delete(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
 	defer cancel()
 
+	unusedSharedDiskSpecs, err := s.unusedSharedDiskSpecs(ctx)
+	if err != nil {
+		s.Scope.UpdateDeleteStatus(infrav1.DisksReadyCondition, serviceName, err)
+		return err
+	}
+
 	specs := s.Scope.DiskSpecs()
+	specs = append(specs, unusedSharedDiskSpecs...)
 	if len(specs) == 0 {
 		return nil
 	}
 
+	// All the snapshots are taken before any disk is deleted, so that a disk is never deleted without its snapshot.
+	if err := s.snapshotDisks(ctx); err != nil {
+		s.Scope.UpdateDeleteStatus(infrav1.DisksReadyCondition, serviceName, err)
+		return err
+	}
+
 	// We go through the list of DiskSpecs to delete each one, independently of the result of the previous one.
 	// If multiple errors occur, we return the most pressing one.
 	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
@@ -92,6 +138,55 @@ func (s *Service) Delete(ctx context.Context) error {
 	return result
 }
 
+// unusedSharedDiskSpecs returns the specs of the shared disks of a VM that aren't attached to any VM, unless they are
+// retained. The VM is deleted before its disks, so a shared disk that isn't attached anymore has lost its last owner.
+func (s *Service) unusedSharedDiskSpecs(ctx context.Context) ([]azure.ResourceSpecGetter, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.Service.unusedSharedDiskSpecs")
+	defer done()
+
+	var specs []azure.ResourceSpecGetter
+	for _, spec := range s.Scope.SharedDiskSpecs() {
+		if sharedDiskSpec, ok := spec.(*SharedDiskSpec); ok && sharedDiskSpec.Retain {
+			continue
+		}
+		existing, err := s.sharedDiskGetter.Get(ctx, spec)
+		if azure.ResourceNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get shared disk %s", spec.ResourceName())
+		}
+		disk, ok := existing.(compute.Disk)
+		if !ok {
+			return nil, errors.Errorf("%T is not a compute.Disk", existing)
+		}
+		if ptr.Deref(disk.ManagedBy, "") != "" || (disk.ManagedByExtended != nil && len(*disk.ManagedByExtended) > 0) {
+			continue
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// snapshotDisks takes the snapshots of the disks associated with a VM.
+func (s *Service) snapshotDisks(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.Service.snapshotDisks")
+	defer done()
+
+	// We go through the list of snapshot specs to create each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one.
+	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
+	var result error
+	for _, snapshotSpec := range s.Scope.DiskSnapshotSpecs() {
+		if _, err := s.snapshotReconciler.CreateOrUpdateResource(ctx, snapshotSpec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+	return result
+}
+
 // IsManaged returns always returns true as CAPZ does not support BYO disk.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	return true, nil
@@ -21,9 +21,11 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
 	"github.com/Azure/go-autorest/autorest"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
@@ -47,27 +49,148 @@ var (
 		&diskSpec2,
 	}
 
+	snapshotSpec1 = SnapshotSpec{
+		Name:          "my-disk-1_snapshot",
+		ResourceGroup: "my-group",
+		Location:      "westus",
+		SourceDiskID:  "/subscriptions/123/resourceGroups/my-group/providers/Microsoft.Compute/disks/my-disk-1",
+		ClusterName:   "my-cluster",
+	}
+
+	snapshotSpec2 = SnapshotSpec{
+		Name:          "my-disk-2_snapshot",
+		ResourceGroup: "my-group",
+		Location:      "westus",
+		SourceDiskID:  "/subscriptions/123/resourceGroups/my-group/providers/Microsoft.Compute/disks/my-disk-2",
+		ClusterName:   "my-cluster",
+	}
+
+	fakeSnapshotSpecs = []azure.ResourceSpecGetter{
+		&snapshotSpec1,
+		&snapshotSpec2,
+	}
+
+	sharedDiskSpec = SharedDiskSpec{
+		Name:               "my-cluster_md-0_shared",
+		ResourceGroup:      "my-group",
+		Location:           "westus",
+		DiskSizeGB:         256,
+		StorageAccountType: "Premium_ZRS",
+		MaxShares:          2,
+		ClusterName:        "my-cluster",
+	}
+
+	dataDiskSpec = DataDiskSpec{
+		Name:               "my-vm_etcddisk",
+		ResourceGroup:      "my-group",
+		Location:           "westus",
+		Zone:               "1",
+		DiskSizeGB:         256,
+		StorageAccountType: "UltraSSD_LRS",
+		DiskIOPSReadWrite:  ptr.To[int64](20000),
+		DiskMBpsReadWrite:  ptr.To[int64](500),
+		ClusterName:        "my-cluster",
+	}
+
 	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+	notFoundError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusNotFound}, "Not Found")
 )
 
-func TestDeleteDisk(t *testing.T) {
+func TestReconcileDisk(t *testing.T) {
 	testcases := []struct {
 		name          string
 		expectedError string
 		expect        func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
 	}{
 		{
-			name:          "noop if no disk specs are found",
+			name:          "noop if no shared disk specs are found",
+			expectedError: "",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.SharedDiskSpecs().Return(nil)
+				s.DataDiskSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "create the shared disk",
 			expectedError: "",
 			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.SharedDiskSpecs().Return([]azure.ResourceSpecGetter{&sharedDiskSpec})
+				s.DataDiskSpecs().Return(nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &sharedDiskSpec, serviceName).Return(nil, nil)
+			},
+		},
+		{
+			name:          "create the shared disk and the data disk with provisioned performance",
+			expectedError: "",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.SharedDiskSpecs().Return([]azure.ResourceSpecGetter{&sharedDiskSpec})
+				s.DataDiskSpecs().Return([]azure.ResourceSpecGetter{&dataDiskSpec})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &sharedDiskSpec, serviceName).Return(nil, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &dataDiskSpec, serviceName).Return(nil, nil)
+			},
+		},
+		{
+			name:          "error while trying to create the shared disk",
+			expectedError: "#: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.SharedDiskSpecs().Return([]azure.ResourceSpecGetter{&sharedDiskSpec})
+				s.DataDiskSpecs().Return(nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &sharedDiskSpec, serviceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_disks.NewMockDiskScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteDisk(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, sr *mock_async.MockReconcilerMockRecorder, g *mock_async.MockGetterMockRecorder)
+	}{
+		{
+			name:          "noop if no disk specs are found",
+			expectedError: "",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, sr *mock_async.MockReconcilerMockRecorder, g *mock_async.MockGetterMockRecorder) {
+				s.SharedDiskSpecs().Return(nil)
 				s.DiskSpecs().Return([]azure.ResourceSpecGetter{})
 			},
 		},
 		{
 			name:          "delete the disk",
 			expectedError: "",
-			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, sr *mock_async.MockReconcilerMockRecorder, g *mock_async.MockGetterMockRecorder) {
+				s.SharedDiskSpecs().Return(nil)
 				s.DiskSpecs().Return(fakeDiskSpecs)
+				s.DiskSnapshotSpecs().Return(nil)
 				gomock.InOrder(
 					r.DeleteResource(gomockinternal.AContext(), &diskSpec1, serviceName).Return(nil),
 					r.DeleteResource(gomockinternal.AContext(), &diskSpec2, serviceName).Return(nil),
@@ -78,8 +201,10 @@ func TestDeleteDisk(t *testing.T) {
 		{
 			name:          "disk already deleted",
 			expectedError: "",
-			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, sr *mock_async.MockReconcilerMockRecorder, g *mock_async.MockGetterMockRecorder) {
+				s.SharedDiskSpecs().Return(nil)
 				s.DiskSpecs().Return(fakeDiskSpecs)
+				s.DiskSnapshotSpecs().Return(nil)
 				gomock.InOrder(
 					r.DeleteResource(gomockinternal.AContext(), &diskSpec1, serviceName).Return(nil),
 					r.DeleteResource(gomockinternal.AContext(), &diskSpec2, serviceName).Return(nil),
@@ -90,8 +215,10 @@ func TestDeleteDisk(t *testing.T) {
 		{
 			name:          "error while trying to delete the disk",
 			expectedError: "#: Internal Server Error: StatusCode=500",
-			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, sr *mock_async.MockReconcilerMockRecorder, g *mock_async.MockGetterMockRecorder) {
+				s.SharedDiskSpecs().Return(nil)
 				s.DiskSpecs().Return(fakeDiskSpecs)
+				s.DiskSnapshotSpecs().Return(nil)
 				gomock.InOrder(
 					r.DeleteResource(gomockinternal.AContext(), &diskSpec1, serviceName).Return(internalError),
 					r.DeleteResource(gomockinternal.AContext(), &diskSpec2, serviceName).Return(nil),
@@ -99,6 +226,88 @@ func TestDeleteDisk(t *testing.T) {
 				)
 			},
 		},
+		{
+			name:          "snapshot the disks before deleting them",
+			expectedError: "",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, sr *mock_async.MockReconcilerMockRecorder, g *mock_async.MockGetterMockRecorder) {
+				s.SharedDiskSpecs().Return(nil)
+				s.DiskSpecs().Return(fakeDiskSpecs)
+				s.DiskSnapshotSpecs().Return(fakeSnapshotSpecs)
+				gomock.InOrder(
+					sr.CreateOrUpdateResource(gomockinternal.AContext(), &snapshotSpec1, serviceName).Return(nil, nil),
+					sr.CreateOrUpdateResource(gomockinternal.AContext(), &snapshotSpec2, serviceName).Return(nil, nil),
+					r.DeleteResource(gomockinternal.AContext(), &diskSpec1, serviceName).Return(nil),
+					r.DeleteResource(gomockinternal.AContext(), &diskSpec2, serviceName).Return(nil),
+					s.UpdateDeleteStatus(infrav1.DisksReadyCondition, serviceName, nil),
+				)
+			},
+		},
+		{
+			name:          "error while trying to snapshot the disks, the disks are not deleted",
+			expectedError: "#: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, sr *mock_async.MockReconcilerMockRecorder, g *mock_async.MockGetterMockRecorder) {
+				s.SharedDiskSpecs().Return(nil)
+				s.DiskSpecs().Return(fakeDiskSpecs)
+				s.DiskSnapshotSpecs().Return(fakeSnapshotSpecs)
+				gomock.InOrder(
+					sr.CreateOrUpdateResource(gomockinternal.AContext(), &snapshotSpec1, serviceName).Return(nil, internalError),
+					sr.CreateOrUpdateResource(gomockinternal.AContext(), &snapshotSpec2, serviceName).Return(nil, nil),
+					s.UpdateDeleteStatus(infrav1.DisksReadyCondition, serviceName, internalError),
+				)
+			},
+		},
+		{
+			name:          "delete a shared disk that isn't attached to any VM",
+			expectedError: "",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, sr *mock_async.MockReconcilerMockRecorder, g *mock_async.MockGetterMockRecorder) {
+				s.SharedDiskSpecs().Return([]azure.ResourceSpecGetter{&sharedDiskSpec})
+				g.Get(gomockinternal.AContext(), &sharedDiskSpec).Return(compute.Disk{}, nil)
+				s.DiskSpecs().Return([]azure.ResourceSpecGetter{&diskSpec1})
+				s.DiskSnapshotSpecs().Return(nil)
+				gomock.InOrder(
+					r.DeleteResource(gomockinternal.AContext(), &diskSpec1, serviceName).Return(nil),
+					r.DeleteResource(gomockinternal.AContext(), &sharedDiskSpec, serviceName).Return(nil),
+					s.UpdateDeleteStatus(infrav1.DisksReadyCondition, serviceName, nil),
+				)
+			},
+		},
+		{
+			name:          "keep a shared disk that is attached to another VM",
+			expectedError: "",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, sr *mock_async.MockReconcilerMockRecorder, g *mock_async.MockGetterMockRecorder) {
+				s.SharedDiskSpecs().Return([]azure.ResourceSpecGetter{&sharedDiskSpec})
+				g.Get(gomockinternal.AContext(), &sharedDiskSpec).Return(compute.Disk{
+					ManagedByExtended: &[]string{"/subscriptions/123/resourceGroups/my-group/providers/Microsoft.Compute/virtualMachines/other-vm"},
+				}, nil)
+				s.DiskSpecs().Return([]azure.ResourceSpecGetter{})
+			},
+		},
+		{
+			name:          "keep a retained shared disk",
+			expectedError: "",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, sr *mock_async.MockReconcilerMockRecorder, g *mock_async.MockGetterMockRecorder) {
+				s.SharedDiskSpecs().Return([]azure.ResourceSpecGetter{&SharedDiskSpec{Name: "my-cluster_md-0_shared", ResourceGroup: "my-group", Retain: true}})
+				s.DiskSpecs().Return([]azure.ResourceSpecGetter{})
+			},
+		},
+		{
+			name:          "shared disk already deleted",
+			expectedError: "",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, sr *mock_async.MockReconcilerMockRecorder, g *mock_async.MockGetterMockRecorder) {
+				s.SharedDiskSpecs().Return([]azure.ResourceSpecGetter{&sharedDiskSpec})
+				g.Get(gomockinternal.AContext(), &sharedDiskSpec).Return(nil, notFoundError)
+				s.DiskSpecs().Return([]azure.ResourceSpecGetter{})
+			},
+		},
+		{
+			name:          "error while trying to get a shared disk, no disk is deleted",
+			expectedError: "failed to get shared disk my-cluster_md-0_shared: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, sr *mock_async.MockReconcilerMockRecorder, g *mock_async.MockGetterMockRecorder) {
+				s.SharedDiskSpecs().Return([]azure.ResourceSpecGetter{&sharedDiskSpec})
+				g.Get(gomockinternal.AContext(), &sharedDiskSpec).Return(nil, internalError)
+				s.UpdateDeleteStatus(infrav1.DisksReadyCondition, serviceName, gomock.Any())
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -111,12 +320,16 @@ func TestDeleteDisk(t *testing.T) {
 			defer mockCtrl.Finish()
 			scopeMock := mock_disks.NewMockDiskScope(mockCtrl)
 			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+			snapshotAsyncMock := mock_async.NewMockReconciler(mockCtrl)
+			getterMock := mock_async.NewMockGetter(mockCtrl)
 
-			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT(), snapshotAsyncMock.EXPECT(), getterMock.EXPECT())
 
 			s := &Service{
-				Scope:      scopeMock,
-				Reconciler: asyncMock,
+				Scope:              scopeMock,
+				Reconciler:         asyncMock,
+				snapshotReconciler: snapshotAsyncMock,
+				sharedDiskGetter:   getterMock,
 			}
 
 			err := s.Delete(context.TODO())
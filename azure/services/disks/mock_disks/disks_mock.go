@@ -180,6 +180,20 @@ func (mr *MockDiskScopeMockRecorder) ClusterName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterName", reflect.TypeOf((*MockDiskScope)(nil).ClusterName))
 }
 
+// DataDiskSpecs mocks base method.
+func (m *MockDiskScope) DataDiskSpecs() []azure.ResourceSpecGetter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DataDiskSpecs")
+	ret0, _ := ret[0].([]azure.ResourceSpecGetter)
+	return ret0
+}
+
+// DataDiskSpecs indicates an expected call of DataDiskSpecs.
+func (mr *MockDiskScopeMockRecorder) DataDiskSpecs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DataDiskSpecs", reflect.TypeOf((*MockDiskScope)(nil).DataDiskSpecs))
+}
+
 // DeleteLongRunningOperationState mocks base method.
 func (m *MockDiskScope) DeleteLongRunningOperationState(arg0, arg1, arg2 string) {
 	m.ctrl.T.Helper()
@@ -192,6 +206,20 @@ func (mr *MockDiskScopeMockRecorder) DeleteLongRunningOperationState(arg0, arg1,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockDiskScope)(nil).DeleteLongRunningOperationState), arg0, arg1, arg2)
 }
 
+// DiskSnapshotSpecs mocks base method.
+func (m *MockDiskScope) DiskSnapshotSpecs() []azure.ResourceSpecGetter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiskSnapshotSpecs")
+	ret0, _ := ret[0].([]azure.ResourceSpecGetter)
+	return ret0
+}
+
+// DiskSnapshotSpecs indicates an expected call of DiskSnapshotSpecs.
+func (mr *MockDiskScopeMockRecorder) DiskSnapshotSpecs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiskSnapshotSpecs", reflect.TypeOf((*MockDiskScope)(nil).DiskSnapshotSpecs))
+}
+
 // DiskSpecs mocks base method.
 func (m *MockDiskScope) DiskSpecs() []azure.ResourceSpecGetter {
 	m.ctrl.T.Helper()
@@ -330,6 +358,20 @@ func (mr *MockDiskScopeMockRecorder) SetLongRunningOperationState(arg0 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLongRunningOperationState", reflect.TypeOf((*MockDiskScope)(nil).SetLongRunningOperationState), arg0)
 }
 
+// SharedDiskSpecs mocks base method.
+func (m *MockDiskScope) SharedDiskSpecs() []azure.ResourceSpecGetter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SharedDiskSpecs")
+	ret0, _ := ret[0].([]azure.ResourceSpecGetter)
+	return ret0
+}
+
+// SharedDiskSpecs indicates an expected call of SharedDiskSpecs.
+func (mr *MockDiskScopeMockRecorder) SharedDiskSpecs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SharedDiskSpecs", reflect.TypeOf((*MockDiskScope)(nil).SharedDiskSpecs))
+}
+
 // SubscriptionID mocks base method.
 func (m *MockDiskScope) SubscriptionID() string {
 	m.ctrl.T.Helper()
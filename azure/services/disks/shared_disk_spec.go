@@ -0,0 +1,106 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package disks
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// SharedDiskSpec defines the specification for a data disk that is shared between machines.
+type SharedDiskSpec struct {
+	Name                string
+	ResourceGroup       string
+	Location            string
+	Zone                string
+	DiskSizeGB          int32
+	StorageAccountType  string
+	MaxShares           int32
+	DiskIOPSReadWrite   *int64
+	DiskMBpsReadWrite   *int64
+	DiskEncryptionSetID string
+	ClusterName         string
+	AdditionalTags      infrav1.Tags
+	// Retain is true if the disk is kept once the last machine that uses it is deleted.
+	Retain bool
+}
+
+// ResourceName returns the name of the shared disk.
+func (s *SharedDiskSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the shared disk.
+func (s *SharedDiskSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for shared disks.
+func (s *SharedDiskSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the shared disk.
+func (s *SharedDiskSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		if _, ok := existing.(compute.Disk); !ok {
+			return nil, errors.Errorf("%T is not a compute.Disk", existing)
+		}
+		// The shared disk was already created by another machine, and its options can't be changed.
+		return nil, nil
+	}
+
+	disk := compute.Disk{
+		Location: ptr.To(s.Location),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        ptr.To(s.Name),
+			Additional:  s.AdditionalTags,
+		})),
+		Sku: &compute.DiskSku{
+			Name: compute.DiskStorageAccountTypes(s.StorageAccountType),
+		},
+		DiskProperties: &compute.DiskProperties{
+			CreationData: &compute.CreationData{
+				CreateOption: compute.DiskCreateOptionEmpty,
+			},
+			DiskSizeGB:        ptr.To(s.DiskSizeGB),
+			MaxShares:         ptr.To(s.MaxShares),
+			DiskIOPSReadWrite: s.DiskIOPSReadWrite,
+			DiskMBpsReadWrite: s.DiskMBpsReadWrite,
+		},
+	}
+
+	if s.Zone != "" {
+		disk.Zones = &[]string{s.Zone}
+	}
+
+	if s.DiskEncryptionSetID != "" {
+		disk.Encryption = &compute.Encryption{
+			DiskEncryptionSetID: ptr.To(s.DiskEncryptionSetID),
+			Type:                compute.EncryptionTypeEncryptionAtRestWithCustomerKey,
+		}
+	}
+
+	return disk, nil
+}
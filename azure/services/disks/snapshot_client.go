@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package disks
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// azureSnapshotsClient contains the Azure go-sdk Client for disk snapshots.
+type azureSnapshotsClient struct {
+	snapshots compute.SnapshotsClient
+}
+
+// newSnapshotsClient creates a new snapshots client from subscription ID.
+func newSnapshotsClient(auth azure.Authorizer) *azureSnapshotsClient {
+	snapshotsClient := compute.NewSnapshotsClientWithBaseURI(auth.BaseURI(), auth.SubscriptionID())
+	azure.SetAutoRestClientDefaults(&snapshotsClient.Client, auth.Authorizer())
+	return &azureSnapshotsClient{
+		snapshots: snapshotsClient,
+	}
+}
+
+// Get gets the specified snapshot.
+func (ac *azureSnapshotsClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.azureSnapshotsClient.Get")
+	defer done()
+
+	return ac.snapshots.Get(ctx, spec.ResourceGroupName(), spec.ResourceName())
+}
+
+// CreateOrUpdateAsync creates or updates a snapshot asynchronously.
+// It sends a PUT request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureSnapshotsClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (result interface{}, future azureautorest.FutureAPI, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.azureSnapshotsClient.CreateOrUpdateAsync")
+	defer done()
+
+	snapshot, ok := parameters.(compute.Snapshot)
+	if !ok {
+		return nil, nil, errors.Errorf("%T is not a compute.Snapshot", parameters)
+	}
+
+	createFuture, err := ac.snapshots.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), snapshot)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	err = createFuture.WaitForCompletionRef(ctx, ac.snapshots.Client)
+	if err != nil {
+		// if an error occurs, return the future.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, &createFuture, err
+	}
+	result, err = createFuture.Result(ac.snapshots)
+	// if the operation completed, return a nil future.
+	return result, nil, err
+}
+
+// IsDone returns true if the long-running operation has completed.
+func (ac *azureSnapshotsClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (isDone bool, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.azureSnapshotsClient.IsDone")
+	defer done()
+
+	return future.DoneWithContext(ctx, ac.snapshots)
+}
+
+// Result fetches the result of a long-running operation future.
+func (ac *azureSnapshotsClient) Result(ctx context.Context, future azureautorest.FutureAPI, futureType string) (result interface{}, err error) {
+	_, _, done := tele.StartSpanWithLogger(ctx, "disks.azureSnapshotsClient.Result")
+	defer done()
+
+	if future == nil {
+		return nil, errors.Errorf("cannot get result from nil future")
+	}
+
+	switch futureType {
+	case infrav1.PutFuture:
+		// Marshal and Unmarshal the future to put it into the correct future type so we can access the Result function.
+		// Unfortunately the FutureAPI can't be casted directly to SnapshotsCreateOrUpdateFuture because it is a azureautorest.Future, which doesn't implement the Result function. See PR #1686 for discussion on alternatives.
+		// It was converted back to a generic azureautorest.Future from the CAPZ infrav1.Future type stored in Status: https://github.com/kubernetes-sigs/cluster-api-provider-azure/blob/main/azure/converters/futures.go#L49.
+		var createFuture *compute.SnapshotsCreateOrUpdateFuture
+		jsonData, err := future.MarshalJSON()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal future")
+		}
+		if err := json.Unmarshal(jsonData, &createFuture); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal future data")
+		}
+		return createFuture.Result(ac.snapshots)
+
+	default:
+		return nil, errors.Errorf("unknown future type %q", futureType)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package disks
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// SnapshotSpec defines the specification for a snapshot of a disk.
+type SnapshotSpec struct {
+	Name            string
+	ResourceGroup   string
+	Location        string
+	SourceDiskID    string
+	ClusterName     string
+	RetentionPeriod *metav1.Duration
+	AdditionalTags  infrav1.Tags
+}
+
+// ResourceName returns the name of the snapshot.
+func (s *SnapshotSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the snapshot.
+func (s *SnapshotSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for snapshots.
+func (s *SnapshotSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the snapshot.
+func (s *SnapshotSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		if _, ok := existing.(compute.Snapshot); !ok {
+			return nil, errors.Errorf("%T is not a compute.Snapshot", existing)
+		}
+		// The snapshot was already taken, it is never updated.
+		return nil, nil
+	}
+
+	// The snapshot outlives the machine and the cluster, so it is shared with the cluster rather than owned by it.
+	tags := infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.ClusterName,
+		Lifecycle:   infrav1.ResourceLifecycleShared,
+		Name:        ptr.To(s.Name),
+		Additional:  s.AdditionalTags,
+	})
+	if s.RetentionPeriod != nil {
+		tags[infrav1.SnapshotExpiryTag] = time.Now().UTC().Add(s.RetentionPeriod.Duration).Format(time.RFC3339)
+	}
+
+	return compute.Snapshot{
+		Location: ptr.To(s.Location),
+		Tags:     converters.TagsToMap(tags),
+		SnapshotProperties: &compute.SnapshotProperties{
+			CreationData: &compute.CreationData{
+				CreateOption:     compute.DiskCreateOptionCopy,
+				SourceResourceID: ptr.To(s.SourceDiskID),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package disks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestSnapshotSpec_Parameters(t *testing.T) {
+	testcases := []struct {
+		name          string
+		spec          SnapshotSpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name: "new snapshot",
+			spec: snapshotSpec1,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(compute.Snapshot{
+					Location: ptr.To("westus"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": ptr.To("shared"),
+						"Name": ptr.To("my-disk-1_snapshot"),
+					},
+					SnapshotProperties: &compute.SnapshotProperties{
+						CreationData: &compute.CreationData{
+							CreateOption:     compute.DiskCreateOptionCopy,
+							SourceResourceID: ptr.To("/subscriptions/123/resourceGroups/my-group/providers/Microsoft.Compute/disks/my-disk-1"),
+						},
+					},
+				}))
+			},
+		},
+		{
+			name: "new snapshot with retention period",
+			spec: SnapshotSpec{
+				Name:            "my-disk-1_snapshot",
+				ResourceGroup:   "my-group",
+				Location:        "westus",
+				SourceDiskID:    "/subscriptions/123/resourceGroups/my-group/providers/Microsoft.Compute/disks/my-disk-1",
+				ClusterName:     "my-cluster",
+				RetentionPeriod: &metav1.Duration{Duration: 24 * time.Hour},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.Snapshot{}))
+				tags := result.(compute.Snapshot).Tags
+				g.Expect(tags).To(HaveKey(infrav1.SnapshotExpiryTag))
+				expiry, err := time.Parse(time.RFC3339, *tags[infrav1.SnapshotExpiryTag])
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(expiry).To(BeTemporally("~", time.Now().Add(24*time.Hour), time.Minute))
+			},
+		},
+		{
+			name:     "existing snapshot",
+			spec:     snapshotSpec1,
+			existing: compute.Snapshot{},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:          "type cast error",
+			spec:          snapshotSpec1,
+			existing:      "I'm not compute.Snapshot",
+			expectedError: "string is not a compute.Snapshot",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				tc.expect(g, result)
+			}
+		})
+	}
+}
@@ -18,13 +18,17 @@ package groups
 
 import (
 	"context"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
+	deployments "github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2021-04-01/resources"
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -37,6 +41,7 @@ type Service struct {
 	Scope GroupScope
 	async.Reconciler
 	client
+	whatIf whatIfClient
 }
 
 // GroupScope defines the scope interface for a group service.
@@ -47,6 +52,18 @@ type GroupScope interface {
 	ClusterName() string
 }
 
+// WhatIfRecorder records the result of ARM what-if operations run before the resource group is created or updated.
+type WhatIfRecorder interface {
+	UpdateAnnotationJSON(string, map[string]interface{}) error
+}
+
+// whatIfChange is a change reported by an ARM what-if operation.
+type whatIfChange struct {
+	ResourceID string                              `json:"resourceId"`
+	ChangeType deployments.ChangeType              `json:"changeType"`
+	Delta      *[]deployments.WhatIfPropertyChange `json:"delta,omitempty"`
+}
+
 // New creates a new service.
 func New(scope GroupScope) *Service {
 	client := newClient(scope)
@@ -54,6 +71,7 @@ func New(scope GroupScope) *Service {
 		Scope:      scope,
 		client:     client,
 		Reconciler: async.New(scope, client, client),
+		whatIf:     newWhatIfClient(scope),
 	}
 }
 
@@ -75,6 +93,10 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		return nil
 	}
 
+	if feature.Gates.Enabled(feature.ResourceGroupWhatIf) {
+		s.recordWhatIf(ctx, groupSpec)
+	}
+
 	_, err := s.CreateOrUpdateResource(ctx, groupSpec, ServiceName)
 	s.Scope.UpdatePutStatus(infrav1.ResourceGroupReadyCondition, ServiceName, err)
 	return err
@@ -114,6 +136,60 @@ func (s *Service) Delete(ctx context.Context) error {
 	return err
 }
 
+// recordWhatIf runs an ARM what-if operation for the changes about to be made to the resource group, if any, and
+// records its result in an annotation of the scope's object. Failures are logged but don't prevent the resource group
+// from being reconciled.
+func (s *Service) recordWhatIf(ctx context.Context, groupSpec azure.ResourceSpecGetter) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "groups.Service.recordWhatIf")
+	defer done()
+
+	recorder, ok := s.Scope.(WhatIfRecorder)
+	if !ok {
+		return
+	}
+
+	existing, err := s.client.Get(ctx, groupSpec)
+	if err != nil {
+		if !azure.ResourceNotFound(err) {
+			log.V(2).Info("failed to get resource group for what-if operation", "resourceGroup", groupSpec.ResourceName(), "err", err.Error())
+			return
+		}
+		existing = nil
+	}
+	params, err := groupSpec.Parameters(ctx, existing)
+	if err != nil || params == nil {
+		// Nothing is about to change.
+		return
+	}
+	group, ok := params.(resources.Group)
+	if !ok {
+		return
+	}
+
+	sdkChanges, err := s.whatIf.WhatIf(ctx, groupSpec, group)
+	if err != nil {
+		log.V(2).Info("failed to run what-if operation for resource group", "resourceGroup", groupSpec.ResourceName(), "err", err.Error())
+		return
+	}
+
+	changes := make([]whatIfChange, 0, len(sdkChanges))
+	for _, change := range sdkChanges {
+		changes = append(changes, whatIfChange{
+			ResourceID: ptr.Deref(change.ResourceID, ""),
+			ChangeType: change.ChangeType,
+			Delta:      change.Delta,
+		})
+	}
+	log.V(2).Info("ran what-if operation for resource group", "resourceGroup", groupSpec.ResourceName(), "changes", len(changes))
+
+	if err := recorder.UpdateAnnotationJSON(azure.ResourceGroupWhatIfAnnotation, map[string]interface{}{
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+		"changes":   changes,
+	}); err != nil {
+		log.V(2).Info("failed to record what-if operation result", "resourceGroup", groupSpec.ResourceName(), "err", err.Error())
+	}
+}
+
 // IsManaged returns true if the resource group has an owned tag with the cluster name as value,
 // meaning that the resource group's lifecycle is managed.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
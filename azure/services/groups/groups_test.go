@@ -22,13 +22,17 @@ import (
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
+	deployments "github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2021-04-01/resources"
 	"github.com/Azure/go-autorest/autorest"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups/mock_groups"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 )
 
@@ -119,6 +123,87 @@ func TestReconcileGroups(t *testing.T) {
 	}
 }
 
+type fakeWhatIfScope struct {
+	*mock_groups.MockGroupScope
+	annotations map[string]map[string]interface{}
+}
+
+func (f *fakeWhatIfScope) UpdateAnnotationJSON(annotation string, content map[string]interface{}) error {
+	f.annotations[annotation] = content
+	return nil
+}
+
+type fakeWhatIfClient struct {
+	changes []deployments.WhatIfChange
+	called  bool
+}
+
+func (f *fakeWhatIfClient) WhatIf(_ context.Context, _ azure.ResourceSpecGetter, _ resources.Group) ([]deployments.WhatIfChange, error) {
+	f.called = true
+	return f.changes, nil
+}
+
+func TestReconcileGroupsWhatIf(t *testing.T) {
+	testcases := []struct {
+		name            string
+		existing        resources.Group
+		getErr          error
+		expectedChanges []whatIfChange
+	}{
+		{
+			name:   "records the what-if result before creating the resource group",
+			getErr: notFoundError,
+			expectedChanges: []whatIfChange{
+				{ResourceID: "/subscriptions/123/resourceGroups/test-group", ChangeType: deployments.Create},
+			},
+		},
+		{
+			name:     "skips the what-if operation when the resource group isn't about to change",
+			existing: sampleManagedGroup,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_groups.NewMockGroupScope(mockCtrl)
+			clientMock := mock_groups.NewMockclient(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			scopeMock.EXPECT().GroupSpec().Return(&fakeGroupSpec)
+			clientMock.EXPECT().Get(gomockinternal.AContext(), &fakeGroupSpec).Return(tc.existing, tc.getErr)
+			asyncMock.EXPECT().CreateOrUpdateResource(gomockinternal.AContext(), &fakeGroupSpec, ServiceName).Return(nil, nil)
+			scopeMock.EXPECT().UpdatePutStatus(infrav1.ResourceGroupReadyCondition, ServiceName, nil)
+
+			scope := &fakeWhatIfScope{MockGroupScope: scopeMock, annotations: map[string]map[string]interface{}{}}
+			whatIf := &fakeWhatIfClient{changes: []deployments.WhatIfChange{
+				{ResourceID: ptr.To("/subscriptions/123/resourceGroups/test-group"), ChangeType: deployments.Create},
+			}}
+			s := &Service{
+				Scope:      scope,
+				client:     clientMock,
+				Reconciler: asyncMock,
+				whatIf:     whatIf,
+			}
+
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ResourceGroupWhatIf, true)()
+
+			g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+			if tc.expectedChanges == nil {
+				g.Expect(whatIf.called).To(BeFalse())
+				g.Expect(scope.annotations).To(BeEmpty())
+				return
+			}
+			g.Expect(scope.annotations).To(HaveKey(azure.ResourceGroupWhatIfAnnotation))
+			g.Expect(scope.annotations[azure.ResourceGroupWhatIfAnnotation]).To(HaveKeyWithValue("changes", tc.expectedChanges))
+		})
+	}
+}
+
 func TestDeleteGroups(t *testing.T) {
 	testcases := []struct {
 		name          string
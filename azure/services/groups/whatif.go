@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groups
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
+	deployments "github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2021-04-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// subscriptionDeploymentTemplateSchema is the schema of ARM templates deployed at the subscription scope.
+const subscriptionDeploymentTemplateSchema = "https://schema.management.azure.com/schemas/2018-05-01/subscriptionDeploymentTemplate.json#"
+
+// whatIfClient previews the changes a resource group update would make.
+type whatIfClient interface {
+	WhatIf(ctx context.Context, spec azure.ResourceSpecGetter, group resources.Group) ([]deployments.WhatIfChange, error)
+}
+
+// azureWhatIfClient contains the Azure go-sdk deployments client.
+type azureWhatIfClient struct {
+	deployments deployments.DeploymentsClient
+}
+
+var _ whatIfClient = (*azureWhatIfClient)(nil)
+
+// newWhatIfClient creates a new what-if client from subscription ID.
+func newWhatIfClient(auth azure.Authorizer) *azureWhatIfClient {
+	return &azureWhatIfClient{
+		deployments: newDeploymentsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newDeploymentsClient creates a new deployments client from subscription ID.
+func newDeploymentsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) deployments.DeploymentsClient {
+	deploymentsClient := deployments.NewDeploymentsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&deploymentsClient.Client, authorizer)
+	return deploymentsClient
+}
+
+// WhatIf runs an ARM what-if operation at the subscription scope for a template declaring the resource group, and
+// returns the changes that creating or updating the resource group would make.
+func (ac *azureWhatIfClient) WhatIf(ctx context.Context, spec azure.ResourceSpecGetter, group resources.Group) ([]deployments.WhatIfChange, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "groups.AzureWhatIfClient.WhatIf")
+	defer done()
+
+	template := map[string]interface{}{
+		"$schema":        subscriptionDeploymentTemplateSchema,
+		"contentVersion": "1.0.0.0",
+		"resources": []interface{}{
+			map[string]interface{}{
+				"type":       "Microsoft.Resources/resourceGroups",
+				"apiVersion": "2019-05-01",
+				"name":       spec.ResourceName(),
+				"location":   ptr.Deref(group.Location, ""),
+				"tags":       group.Tags,
+			},
+		},
+	}
+
+	future, err := ac.deployments.WhatIfAtSubscriptionScope(ctx, whatIfDeploymentName(spec.ResourceName()), deployments.DeploymentWhatIf{
+		Location: group.Location,
+		Properties: &deployments.DeploymentWhatIfProperties{
+			Template: template,
+			Mode:     deployments.Incremental,
+			WhatIfSettings: &deployments.DeploymentWhatIfSettings{
+				ResultFormat: deployments.FullResourcePayloads,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	if err := future.WaitForCompletionRef(ctx, ac.deployments.Client); err != nil {
+		return nil, errors.Wrap(err, "failed waiting for what-if operation to complete")
+	}
+	result, err := future.Result(ac.deployments)
+	if err != nil {
+		return nil, err
+	}
+	if result.WhatIfOperationProperties == nil || result.Changes == nil {
+		return nil, nil
+	}
+	return *result.Changes, nil
+}
+
+// whatIfDeploymentName returns the name of the what-if deployment for a resource group, which is limited to 64
+// characters.
+func whatIfDeploymentName(groupName string) string {
+	name := fmt.Sprintf("capz-whatif-%s", groupName)
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
@@ -80,6 +80,42 @@ var (
 		APIServerPort: 6443,
 	}
 
+	fakeInternalAPILBSpecWithSecondaryFrontend = LBSpec{
+		Name:                 "my-private-lb",
+		ResourceGroup:        "my-rg",
+		SubscriptionID:       "123",
+		ClusterName:          "my-cluster",
+		Location:             "my-location",
+		Role:                 infrav1.APIServerRole,
+		Type:                 infrav1.Internal,
+		SKU:                  infrav1.SKUStandard,
+		VNetName:             "my-vnet",
+		VNetResourceGroup:    "my-rg",
+		SubnetName:           "my-cp-subnet",
+		BackendPoolName:      "my-private-lb-backendPool",
+		IdleTimeoutInMinutes: ptr.To[int32](4),
+		FrontendIPConfigs: []infrav1.FrontendIP{
+			{
+				Name: "my-private-lb-frontEnd",
+				FrontendIPClass: infrav1.FrontendIPClass{
+					PrivateIPAddress: "10.0.0.10",
+				},
+			},
+			{
+				Name: "my-private-lb-frontEnd-hub",
+				Subnet: &infrav1.FrontendIPSubnet{
+					Name:              "hub-subnet",
+					VNetName:          "hub-vnet",
+					VNetResourceGroup: "hub-rg",
+				},
+				FrontendIPClass: infrav1.FrontendIPClass{
+					PrivateIPAddress: "10.100.0.10",
+				},
+			},
+		},
+		APIServerPort: 6443,
+	}
+
 	fakeNodeOutboundLBSpec = LBSpec{
 		Name:                 "my-cluster",
 		ResourceGroup:        "my-rg",
@@ -101,6 +137,67 @@ var (
 		},
 	}
 
+	fakeNodeOutboundLBSpecWithTwoFrontends = LBSpec{
+		Name:                 "my-cluster",
+		ResourceGroup:        "my-rg",
+		SubscriptionID:       "123",
+		ClusterName:          "my-cluster",
+		Location:             "my-location",
+		Role:                 infrav1.NodeOutboundRole,
+		Type:                 infrav1.Public,
+		SKU:                  infrav1.SKUStandard,
+		BackendPoolName:      "my-cluster-outboundBackendPool",
+		IdleTimeoutInMinutes: ptr.To[int32](30),
+		FrontendIPConfigs: []infrav1.FrontendIP{
+			{
+				Name: "my-cluster-frontEnd-1",
+				PublicIP: &infrav1.PublicIPSpec{
+					Name: "outbound-publicip-1",
+				},
+			},
+			{
+				Name: "my-cluster-frontEnd-2",
+				PublicIP: &infrav1.PublicIPSpec{
+					Name: "outbound-publicip-2",
+				},
+			},
+		},
+	}
+
+	fakeNodeOutboundLBSpecWithAdditionalOutboundRule = LBSpec{
+		Name:                 "my-cluster",
+		ResourceGroup:        "my-rg",
+		SubscriptionID:       "123",
+		ClusterName:          "my-cluster",
+		Location:             "my-location",
+		Role:                 infrav1.NodeOutboundRole,
+		Type:                 infrav1.Public,
+		SKU:                  infrav1.SKUStandard,
+		BackendPoolName:      "my-cluster-outboundBackendPool",
+		IdleTimeoutInMinutes: ptr.To[int32](30),
+		FrontendIPConfigs: []infrav1.FrontendIP{
+			{
+				Name: "my-cluster-frontEnd",
+				PublicIP: &infrav1.PublicIPSpec{
+					Name: "outbound-publicip",
+				},
+			},
+		},
+		AdditionalOutboundRules: []infrav1.OutboundRule{
+			{
+				Name: "egress",
+				FrontendIPs: []infrav1.FrontendIP{
+					{
+						Name: "my-cluster-egress-frontEnd-1",
+						PublicIP: &infrav1.PublicIPSpec{
+							Name: "egress-publicip",
+						},
+					},
+				},
+			},
+		},
+	}
+
 	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
 )
 
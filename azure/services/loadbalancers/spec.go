@@ -18,6 +18,8 @@ package loadbalancers
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
 	"github.com/pkg/errors"
@@ -46,6 +48,9 @@ type LBSpec struct {
 	APIServerPort        int32
 	IdleTimeoutInMinutes *int32
 	AdditionalTags       map[string]string
+	// AdditionalOutboundRules are outbound rules with their own frontends and backend pool,
+	// in addition to the default outbound rule.
+	AdditionalOutboundRules []infrav1.OutboundRule
 }
 
 // ResourceName returns the name of the load balancer.
@@ -111,13 +116,22 @@ func (s *LBSpec) Parameters(ctx context.Context, existing interface{}) (paramete
 			}
 		}
 
-		outboundRules = *existingLB.OutboundRules
+		outboundRules = append(outboundRules, *existingLB.OutboundRules...)
+		var unusedFrontends []string
 		for _, rule := range getOutboundRules(*s, wantedFrontendIDs) {
-			if !outboundRuleExists(outboundRules, rule) {
+			i := outboundRuleIndex(outboundRules, rule)
+			if i < 0 {
 				update = true
 				outboundRules = append(outboundRules, rule)
+				continue
+			}
+			// Changes to the frontend IPs count and the idle timeout are applied to the existing rule.
+			if updated, removed := updateOutboundRule(&outboundRules[i], rule); updated {
+				update = true
+				unusedFrontends = append(unusedFrontends, removed...)
 			}
 		}
+		frontendIPConfigs = removeUnusedFrontends(frontendIPConfigs, unusedFrontends, wantedIPs, outboundRules)
 
 		probes = *existingLB.Probes
 		for _, probe := range getProbes(*s) {
@@ -162,16 +176,36 @@ func (s *LBSpec) Parameters(ctx context.Context, existing interface{}) (paramete
 	return lb, nil
 }
 
+// WithSpecHash returns the load balancer parameters tagged with the given spec hash.
+func (s *LBSpec) WithSpecHash(parameters interface{}, hash string) (interface{}, error) {
+	lb, ok := parameters.(network.LoadBalancer)
+	if !ok {
+		return nil, errors.Errorf("%T is not a network.LoadBalancer", parameters)
+	}
+	tags := infrav1.Tags{}
+	tags.Merge(converters.MapToTags(lb.Tags))
+	lb.Tags = converters.TagsToMap(tags.AddSpecVersionHashTag(hash))
+	return lb, nil
+}
+
 func getFrontendIPConfigs(lbSpec LBSpec) ([]network.FrontendIPConfiguration, []network.SubResource) {
 	frontendIPConfigurations := make([]network.FrontendIPConfiguration, 0)
 	frontendIDs := make([]network.SubResource, 0)
 	for _, ipConfig := range lbSpec.FrontendIPConfigs {
 		var properties network.FrontendIPConfigurationPropertiesFormat
 		if lbSpec.Type == infrav1.Internal {
+			subnetID := azure.SubnetID(lbSpec.SubscriptionID, lbSpec.VNetResourceGroup, lbSpec.VNetName, lbSpec.SubnetName)
+			if ipConfig.Subnet != nil {
+				vnetResourceGroup := ipConfig.Subnet.VNetResourceGroup
+				if vnetResourceGroup == "" {
+					vnetResourceGroup = lbSpec.VNetResourceGroup
+				}
+				subnetID = azure.SubnetID(lbSpec.SubscriptionID, vnetResourceGroup, ipConfig.Subnet.VNetName, ipConfig.Subnet.Name)
+			}
 			properties = network.FrontendIPConfigurationPropertiesFormat{
 				PrivateIPAllocationMethod: network.IPAllocationMethodStatic,
 				Subnet: &network.Subnet{
-					ID: ptr.To(azure.SubnetID(lbSpec.SubscriptionID, lbSpec.VNetResourceGroup, lbSpec.VNetName, lbSpec.SubnetName)),
+					ID: ptr.To(subnetID),
 				},
 				PrivateIPAddress: ptr.To(ipConfig.PrivateIPAddress),
 			}
@@ -190,6 +224,22 @@ func getFrontendIPConfigs(lbSpec LBSpec) ([]network.FrontendIPConfiguration, []n
 			ID: ptr.To(azure.FrontendIPConfigID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, ipConfig.Name)),
 		})
 	}
+	// The frontends of additional outbound rules are only used by their own rule,
+	// so they are not part of the frontend IDs of the default rules.
+	if lbSpec.Type != infrav1.Internal {
+		for _, rule := range lbSpec.AdditionalOutboundRules {
+			for _, ipConfig := range rule.FrontendIPs {
+				frontendIPConfigurations = append(frontendIPConfigurations, network.FrontendIPConfiguration{
+					FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+						PublicIPAddress: &network.PublicIPAddress{
+							ID: ptr.To(azure.PublicIPID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, ipConfig.PublicIP.Name)),
+						},
+					},
+					Name: ptr.To(ipConfig.Name),
+				})
+			}
+		}
+	}
 	return frontendIPConfigurations, frontendIDs
 }
 
@@ -197,16 +247,28 @@ func getOutboundRules(lbSpec LBSpec, frontendIDs []network.SubResource) []networ
 	if lbSpec.Type == infrav1.Internal {
 		return []network.OutboundRule{}
 	}
-	return []network.OutboundRule{
-		{
-			Name: ptr.To(outboundNAT),
-			OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
-				Protocol:                 network.LoadBalancerOutboundRuleProtocolAll,
-				IdleTimeoutInMinutes:     lbSpec.IdleTimeoutInMinutes,
-				FrontendIPConfigurations: &frontendIDs,
-				BackendAddressPool: &network.SubResource{
-					ID: ptr.To(azure.AddressPoolID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, lbSpec.BackendPoolName)),
-				},
+	rules := []network.OutboundRule{outboundRule(lbSpec, outboundNAT, frontendIDs, lbSpec.BackendPoolName)}
+	for _, rule := range lbSpec.AdditionalOutboundRules {
+		ruleFrontendIDs := make([]network.SubResource, 0, len(rule.FrontendIPs))
+		for _, ipConfig := range rule.FrontendIPs {
+			ruleFrontendIDs = append(ruleFrontendIDs, network.SubResource{
+				ID: ptr.To(azure.FrontendIPConfigID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, ipConfig.Name)),
+			})
+		}
+		rules = append(rules, outboundRule(lbSpec, rule.Name, ruleFrontendIDs, azure.GenerateOutboundRuleBackendPoolName(lbSpec.Name, rule.Name)))
+	}
+	return rules
+}
+
+func outboundRule(lbSpec LBSpec, name string, frontendIDs []network.SubResource, backendPoolName string) network.OutboundRule {
+	return network.OutboundRule{
+		Name: ptr.To(name),
+		OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
+			Protocol:                 network.LoadBalancerOutboundRuleProtocolAll,
+			IdleTimeoutInMinutes:     lbSpec.IdleTimeoutInMinutes,
+			FrontendIPConfigurations: &frontendIDs,
+			BackendAddressPool: &network.SubResource{
+				ID: ptr.To(azure.AddressPoolID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, backendPoolName)),
 			},
 		},
 	}
@@ -220,37 +282,55 @@ func getLoadBalancingRules(lbSpec LBSpec, frontendIDs []network.SubResource) []n
 		if len(frontendIDs) != 0 {
 			frontendIPConfig = frontendIDs[0]
 		}
-		return []network.LoadBalancingRule{
-			{
-				Name: ptr.To(lbRuleHTTPS),
-				LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
-					DisableOutboundSnat:     ptr.To(true),
-					Protocol:                network.TransportProtocolTCP,
-					FrontendPort:            ptr.To[int32](lbSpec.APIServerPort),
-					BackendPort:             ptr.To[int32](lbSpec.APIServerPort),
-					IdleTimeoutInMinutes:    lbSpec.IdleTimeoutInMinutes,
-					EnableFloatingIP:        ptr.To(false),
-					LoadDistribution:        network.LoadDistributionDefault,
-					FrontendIPConfiguration: &frontendIPConfig,
-					BackendAddressPool: &network.SubResource{
-						ID: ptr.To(azure.AddressPoolID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, lbSpec.BackendPoolName)),
-					},
-					Probe: &network.SubResource{
-						ID: ptr.To(azure.ProbeID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, httpsProbe)),
-					},
-				},
-			},
+		rules := []network.LoadBalancingRule{apiServerLoadBalancingRule(lbSpec, lbRuleHTTPS, frontendIPConfig)}
+
+		// Secondary frontends of an internal load balancer expose the API server into additional subnets,
+		// so each of them needs its own rule.
+		if lbSpec.Type == infrav1.Internal {
+			for i := 1; i < len(frontendIDs) && i < len(lbSpec.FrontendIPConfigs); i++ {
+				name := fmt.Sprintf("%s-%s", lbRuleHTTPS, lbSpec.FrontendIPConfigs[i].Name)
+				rules = append(rules, apiServerLoadBalancingRule(lbSpec, name, frontendIDs[i]))
+			}
 		}
+		return rules
 	}
 	return []network.LoadBalancingRule{}
 }
 
+func apiServerLoadBalancingRule(lbSpec LBSpec, name string, frontendIPConfig network.SubResource) network.LoadBalancingRule {
+	return network.LoadBalancingRule{
+		Name: ptr.To(name),
+		LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
+			DisableOutboundSnat:     ptr.To(true),
+			Protocol:                network.TransportProtocolTCP,
+			FrontendPort:            ptr.To[int32](lbSpec.APIServerPort),
+			BackendPort:             ptr.To[int32](lbSpec.APIServerPort),
+			IdleTimeoutInMinutes:    lbSpec.IdleTimeoutInMinutes,
+			EnableFloatingIP:        ptr.To(false),
+			LoadDistribution:        network.LoadDistributionDefault,
+			FrontendIPConfiguration: &frontendIPConfig,
+			BackendAddressPool: &network.SubResource{
+				ID: ptr.To(azure.AddressPoolID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, lbSpec.BackendPoolName)),
+			},
+			Probe: &network.SubResource{
+				ID: ptr.To(azure.ProbeID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, httpsProbe)),
+			},
+		},
+	}
+}
+
 func getBackendAddressPools(lbSpec LBSpec) []network.BackendAddressPool {
-	return []network.BackendAddressPool{
+	pools := []network.BackendAddressPool{
 		{
 			Name: ptr.To(lbSpec.BackendPoolName),
 		},
 	}
+	for _, rule := range lbSpec.AdditionalOutboundRules {
+		pools = append(pools, network.BackendAddressPool{
+			Name: ptr.To(azure.GenerateOutboundRuleBackendPoolName(lbSpec.Name, rule.Name)),
+		})
+	}
+	return pools
 }
 
 func getProbes(lbSpec LBSpec) []network.Probe {
@@ -280,9 +360,95 @@ func probeExists(probes []network.Probe, probe network.Probe) bool {
 	return false
 }
 
-func outboundRuleExists(rules []network.OutboundRule, rule network.OutboundRule) bool {
-	for _, r := range rules {
+func outboundRuleIndex(rules []network.OutboundRule, rule network.OutboundRule) int {
+	for i, r := range rules {
 		if ptr.Deref(r.Name, "") == ptr.Deref(rule.Name, "") {
+			return i
+		}
+	}
+	return -1
+}
+
+// updateOutboundRule sets the frontends and idle timeout of the wanted rule on the existing rule, leaving its other
+// properties as they are. It returns whether the rule was changed and the names of the frontends it no longer uses.
+func updateOutboundRule(existing *network.OutboundRule, wanted network.OutboundRule) (bool, []string) {
+	if existing.OutboundRulePropertiesFormat == nil || wanted.OutboundRulePropertiesFormat == nil {
+		return false, nil
+	}
+	props := *existing.OutboundRulePropertiesFormat
+	existingFrontends := frontendNames(props.FrontendIPConfigurations)
+	wantedFrontends := frontendNames(wanted.FrontendIPConfigurations)
+
+	var removed []string
+	for _, name := range existingFrontends {
+		if !containsName(wantedFrontends, name) {
+			removed = append(removed, name)
+		}
+	}
+	frontendsChanged := len(removed) > 0 || len(existingFrontends) != len(wantedFrontends)
+	// Azure fills in the default idle timeout when none is set, so an unset wanted value is not a change.
+	idleTimeoutChanged := wanted.IdleTimeoutInMinutes != nil && !ptr.Equal(props.IdleTimeoutInMinutes, wanted.IdleTimeoutInMinutes)
+	if !frontendsChanged && !idleTimeoutChanged {
+		return false, nil
+	}
+
+	if frontendsChanged {
+		props.FrontendIPConfigurations = wanted.FrontendIPConfigurations
+	}
+	if idleTimeoutChanged {
+		props.IdleTimeoutInMinutes = wanted.IdleTimeoutInMinutes
+	}
+	existing.OutboundRulePropertiesFormat = &props
+	return true, removed
+}
+
+// removeUnusedFrontends removes the frontends that an outbound rule no longer uses from the load balancer, unless
+// they are still wanted or used by another rule, such as the load balancing rules of Kubernetes services.
+func removeUnusedFrontends(configs []network.FrontendIPConfiguration, unused []string, wanted []network.FrontendIPConfiguration, outboundRules []network.OutboundRule) []network.FrontendIPConfiguration {
+	if len(unused) == 0 {
+		return configs
+	}
+	var inUse []string
+	for _, rule := range outboundRules {
+		if rule.OutboundRulePropertiesFormat != nil {
+			inUse = append(inUse, frontendNames(rule.FrontendIPConfigurations)...)
+		}
+	}
+
+	result := make([]network.FrontendIPConfiguration, 0, len(configs))
+	for _, config := range configs {
+		name := ptr.Deref(config.Name, "")
+		if !containsName(unused, name) || containsName(inUse, name) || ipExists(wanted, config) || frontendHasRules(config) {
+			result = append(result, config)
+		}
+	}
+	return result
+}
+
+// frontendHasRules returns true if load balancing or inbound NAT rules use the frontend.
+func frontendHasRules(config network.FrontendIPConfiguration) bool {
+	props := config.FrontendIPConfigurationPropertiesFormat
+	if props == nil {
+		return false
+	}
+	return len(ptr.Deref(props.LoadBalancingRules, nil)) > 0 ||
+		len(ptr.Deref(props.InboundNatRules, nil)) > 0 ||
+		len(ptr.Deref(props.InboundNatPools, nil)) > 0
+}
+
+// frontendNames returns the names of the frontends referenced by their IDs.
+func frontendNames(ids *[]network.SubResource) []string {
+	names := make([]string, 0, len(ptr.Deref(ids, nil)))
+	for _, id := range ptr.Deref(ids, nil) {
+		resourceID := ptr.Deref(id.ID, "")
+		names = append(names, resourceID[strings.LastIndex(resourceID, "/")+1:])
+	}
+	return names
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
 			return true
 		}
 	}
@@ -18,12 +18,14 @@ package loadbalancers
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
 	. "github.com/onsi/gomega"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 )
 
 func getExistingLBWithMissingFrontendIPConfigs() network.LoadBalancer {
@@ -87,6 +89,26 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:     "internal API load balancer exists with missing secondary frontend IP",
+			spec:     &fakeInternalAPILBSpecWithSecondaryFrontend,
+			existing: newDefaultInternalAPIServerLB(),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(network.LoadBalancer{}))
+				lb := result.(network.LoadBalancer)
+				g.Expect(*lb.FrontendIPConfigurations).To(HaveLen(2))
+				frontend := (*lb.FrontendIPConfigurations)[1]
+				g.Expect(frontend.Name).To(Equal(ptr.To("my-private-lb-frontEnd-hub")))
+				g.Expect(frontend.Subnet.ID).To(Equal(ptr.To("/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/virtualNetworks/hub-vnet/subnets/hub-subnet")))
+				g.Expect(frontend.PrivateIPAddress).To(Equal(ptr.To("10.100.0.10")))
+				g.Expect(*lb.LoadBalancingRules).To(HaveLen(2))
+				rule := (*lb.LoadBalancingRules)[1]
+				g.Expect(rule.Name).To(Equal(ptr.To(lbRuleHTTPS + "-my-private-lb-frontEnd-hub")))
+				g.Expect(rule.FrontendIPConfiguration.ID).To(Equal(ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-private-lb/frontendIPConfigurations/my-private-lb-frontEnd-hub")))
+				g.Expect(rule.BackendPort).To(Equal(ptr.To[int32](6443)))
+			},
+			expectedError: "",
+		},
 		{
 			name:     "node outbound load balancer exists with all expected values",
 			spec:     &fakeNodeOutboundLBSpec,
@@ -96,6 +118,79 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:     "node outbound load balancer exists with missing additional outbound rule",
+			spec:     &fakeNodeOutboundLBSpecWithAdditionalOutboundRule,
+			existing: newDefaultNodeOutboundLB(),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(network.LoadBalancer{}))
+				lb := result.(network.LoadBalancer)
+				g.Expect(*lb.FrontendIPConfigurations).To(HaveLen(2))
+				frontend := (*lb.FrontendIPConfigurations)[1]
+				g.Expect(frontend.Name).To(Equal(ptr.To("my-cluster-egress-frontEnd-1")))
+				g.Expect(frontend.PublicIPAddress.ID).To(Equal(ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/egress-publicip")))
+				g.Expect(*lb.BackendAddressPools).To(HaveLen(2))
+				g.Expect((*lb.BackendAddressPools)[1].Name).To(Equal(ptr.To("my-cluster-egress-outboundBackendPool")))
+				g.Expect(*lb.OutboundRules).To(HaveLen(2))
+				defaultRule := (*lb.OutboundRules)[0]
+				g.Expect(*defaultRule.FrontendIPConfigurations).To(HaveLen(1))
+				rule := (*lb.OutboundRules)[1]
+				g.Expect(rule.Name).To(Equal(ptr.To("egress")))
+				g.Expect(*rule.FrontendIPConfigurations).To(Equal([]network.SubResource{
+					{ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster/frontendIPConfigurations/my-cluster-egress-frontEnd-1")},
+				}))
+				g.Expect(rule.BackendAddressPool.ID).To(Equal(ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster/backendAddressPools/my-cluster-egress-outboundBackendPool")))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "node outbound load balancer exists with a different frontend IPs count",
+			spec:     &fakeNodeOutboundLBSpecWithTwoFrontends,
+			existing: newDefaultNodeOutboundLB(),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(network.LoadBalancer{}))
+				lb := result.(network.LoadBalancer)
+				g.Expect(*lb.FrontendIPConfigurations).To(HaveLen(2))
+				g.Expect((*lb.FrontendIPConfigurations)[0].Name).To(Equal(ptr.To("my-cluster-frontEnd-1")))
+				g.Expect((*lb.FrontendIPConfigurations)[1].Name).To(Equal(ptr.To("my-cluster-frontEnd-2")))
+				g.Expect(*lb.OutboundRules).To(HaveLen(1))
+				g.Expect(*(*lb.OutboundRules)[0].FrontendIPConfigurations).To(Equal([]network.SubResource{
+					{ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster/frontendIPConfigurations/my-cluster-frontEnd-1")},
+					{ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster/frontendIPConfigurations/my-cluster-frontEnd-2")},
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "node outbound load balancer keeps a removed frontend that is used by a load balancing rule",
+			spec:     &fakeNodeOutboundLBSpecWithTwoFrontends,
+			existing: newNodeOutboundLBWithServiceRule(),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(network.LoadBalancer{}))
+				lb := result.(network.LoadBalancer)
+				g.Expect(*lb.FrontendIPConfigurations).To(HaveLen(3))
+				g.Expect((*lb.FrontendIPConfigurations)[0].Name).To(Equal(ptr.To("my-cluster-frontEnd")))
+				g.Expect(*(*lb.OutboundRules)[0].FrontendIPConfigurations).To(HaveLen(2))
+			},
+			expectedError: "",
+		},
+		{
+			name: "node outbound load balancer exists with a different idle timeout",
+			spec: func() *LBSpec {
+				spec := fakeNodeOutboundLBSpec
+				spec.IdleTimeoutInMinutes = ptr.To[int32](15)
+				return &spec
+			}(),
+			existing: newDefaultNodeOutboundLB(),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(network.LoadBalancer{}))
+				lb := result.(network.LoadBalancer)
+				g.Expect(*lb.FrontendIPConfigurations).To(HaveLen(1))
+				g.Expect(*lb.OutboundRules).To(HaveLen(1))
+				g.Expect((*lb.OutboundRules)[0].IdleTimeoutInMinutes).To(Equal(ptr.To[int32](15)))
+			},
+			expectedError: "",
+		},
 		{
 			name:     "load balancer exists with missing frontend IP configs",
 			spec:     &fakePublicAPILBSpec,
@@ -208,12 +303,20 @@ func newDefaultNodeOutboundLB() network.LoadBalancer {
 	}
 }
 
+func newNodeOutboundLBWithServiceRule() network.LoadBalancer {
+	lb := newDefaultNodeOutboundLB()
+	(*lb.FrontendIPConfigurations)[0].LoadBalancingRules = &[]network.SubResource{
+		{ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster/loadBalancingRules/my-service-rule")},
+	}
+	return lb
+}
+
 func newSamplePublicAPIServerLB(verifyFrontendIP bool, verifyBackendAddressPools bool, verifyLBRules bool, verifyProbes bool, verifyOutboundRules bool) network.LoadBalancer {
 	var subnet *network.Subnet
 	var backendAddressPoolProps *network.BackendAddressPoolPropertiesFormat
 	enableFloatingIP := ptr.To(false)
 	numProbes := ptr.To[int32](4)
-	idleTimeout := ptr.To[int32](4)
+	var allocatedOutboundPorts *int32
 
 	if verifyFrontendIP {
 		subnet = &network.Subnet{
@@ -232,7 +335,7 @@ func newSamplePublicAPIServerLB(verifyFrontendIP bool, verifyBackendAddressPools
 		numProbes = ptr.To[int32](999)
 	}
 	if verifyOutboundRules {
-		idleTimeout = ptr.To[int32](1000)
+		allocatedOutboundPorts = ptr.To[int32](1000)
 	}
 
 	return network.LoadBalancer{
@@ -303,8 +406,9 @@ func newSamplePublicAPIServerLB(verifyFrontendIP bool, verifyBackendAddressPools
 						BackendAddressPool: &network.SubResource{
 							ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/backendAddressPools/my-publiclb-backendPool"),
 						},
-						Protocol:             network.LoadBalancerOutboundRuleProtocolAll,
-						IdleTimeoutInMinutes: idleTimeout, // Add to verify that OutboundRules aren't overwritten on update
+						Protocol:               network.LoadBalancerOutboundRuleProtocolAll,
+						IdleTimeoutInMinutes:   ptr.To[int32](4),
+						AllocatedOutboundPorts: allocatedOutboundPorts, // Add to verify that OutboundRules aren't overwritten on update
 					},
 				},
 			},
@@ -377,3 +481,52 @@ func newDefaultInternalAPIServerLB() network.LoadBalancer {
 		},
 	}
 }
+
+func FuzzParametersRoundTrip(f *testing.F) {
+	f.Add(false, "my-publiclb-frontEnd", "my-publicip", "", "my-publiclb-backendPool", true, int32(4))
+	f.Add(true, "my-private-lb-frontEnd", "", "10.0.0.100", "my-private-lb-backendPool", false, int32(0))
+	f.Fuzz(func(t *testing.T, internal bool, frontendName, publicIPName, privateIP, backendPoolName string, hasIdleTimeout bool, idleTimeout int32) {
+		spec := fakePublicAPILBSpec
+		frontendIP := infrav1.FrontendIP{Name: frontendName}
+		if internal {
+			spec.Type = infrav1.Internal
+			frontendIP.PrivateIPAddress = privateIP
+		} else {
+			// The public IP name is the last segment of its resource ID.
+			if publicIPName == "" || strings.Contains(publicIPName, "/") {
+				t.Skip("public IP name is not a valid resource name")
+			}
+			frontendIP.PublicIP = &infrav1.PublicIPSpec{Name: publicIPName}
+		}
+		spec.FrontendIPConfigs = []infrav1.FrontendIP{frontendIP}
+		spec.BackendPoolName = backendPoolName
+		spec.IdleTimeoutInMinutes = nil
+		if hasIdleTimeout {
+			spec.IdleTimeoutInMinutes = ptr.To(idleTimeout)
+		}
+		g := NewWithT(t)
+
+		result, err := spec.Parameters(context.TODO(), nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		lb, ok := result.(network.LoadBalancer)
+		g.Expect(ok).To(BeTrue())
+		lb.Name = ptr.To(spec.Name)
+
+		got := converters.SDKToLoadBalancer(lb)
+		g.Expect(got.Name).To(Equal(spec.Name))
+		g.Expect(got.FrontendIPs).To(Equal(spec.FrontendIPConfigs))
+		g.Expect(got.BackendPool.Name).To(Equal(spec.BackendPoolName))
+		g.Expect(got.SKU).To(Equal(spec.SKU))
+		g.Expect(got.Type).To(Equal(spec.Type))
+		g.Expect(got.IdleTimeoutInMinutes).To(Equal(spec.IdleTimeoutInMinutes))
+	})
+}
+
+func TestWithSpecHash(t *testing.T) {
+	g := NewWithT(t)
+	spec := &LBSpec{Name: "test-lb"}
+
+	tagged, err := spec.WithSpecHash(network.LoadBalancer{Tags: map[string]*string{"foo": ptr.To("bar")}}, "hash")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tagged).To(Equal(network.LoadBalancer{Tags: map[string]*string{"foo": ptr.To("bar"), infrav1.SpecVersionHashTagKey(): ptr.To("hash")}}))
+}
@@ -20,7 +20,7 @@ import (
 	"context"
 	"encoding/json"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2022-03-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/preview/containerservice/mgmt/2022-03-02-preview/containerservice"
 	"github.com/Azure/go-autorest/autorest"
 	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/pkg/errors"
@@ -62,6 +62,14 @@ func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (
 	return ac.managedclusters.Get(ctx, spec.ResourceGroupName(), spec.ResourceName())
 }
 
+// GetUpgradeProfile gets the upgrade profile of a managed cluster.
+func (ac *azureClient) GetUpgradeProfile(ctx context.Context, resourceGroupName, name string) (containerservice.ManagedClusterUpgradeProfile, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.azureClient.GetUpgradeProfile")
+	defer done()
+
+	return ac.managedclusters.GetUpgradeProfile(ctx, resourceGroupName, name)
+}
+
 // GetCredentials fetches the admin kubeconfig for a managed cluster.
 func (ac *azureClient) GetCredentials(ctx context.Context, resourceGroupName, name string) ([]byte, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.azureClient.GetCredentials")
@@ -132,7 +140,7 @@ func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecG
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.azureClient.DeleteAsync")
 	defer done()
 
-	deleteFuture, err := ac.managedclusters.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	deleteFuture, err := ac.managedclusters.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -19,7 +19,7 @@ package managedclusters
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2022-03-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/preview/containerservice/mgmt/2022-03-02-preview/containerservice"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
@@ -42,9 +42,13 @@ type ManagedClusterScope interface {
 	ManagedClusterSpec() azure.ResourceSpecGetter
 	SetControlPlaneEndpoint(clusterv1.APIEndpoint)
 	SetKubeletIdentity(string)
+	SetControlPlaneIdentityStatus(*infrav1.ManagedClusterIdentityStatus)
+	SetKubeletIdentityStatus(*infrav1.ManagedClusterIdentityStatus)
+	SetOIDCIssuerProfileStatus(*infrav1.OIDCIssuerProfileStatus)
 	MakeEmptyKubeConfigSecret() corev1.Secret
 	GetKubeConfigData() []byte
 	SetKubeConfigData([]byte)
+	SetWindowsAdminPasswordApplied()
 }
 
 // Service provides operations on azure resources.
@@ -108,11 +112,71 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		if id := managedCluster.ManagedClusterProperties.IdentityProfile[kubeletIdentityKey]; id != nil && id.ResourceID != nil {
 			s.Scope.SetKubeletIdentity(*id.ResourceID)
 		}
+
+		// Surface the identities of the cluster so they can be consumed without calling Azure, e.g. to assign roles.
+		s.Scope.SetControlPlaneIdentityStatus(controlPlaneIdentityStatus(managedCluster.Identity))
+		s.Scope.SetKubeletIdentityStatus(kubeletIdentityStatus(managedCluster.ManagedClusterProperties.IdentityProfile[kubeletIdentityKey]))
+		s.Scope.SetOIDCIssuerProfileStatus(oidcIssuerProfileStatus(managedCluster.ManagedClusterProperties.OidcIssuerProfile))
+
+		// Record the Windows admin password applied to AKS so that changing it triggers an update.
+		s.Scope.SetWindowsAdminPasswordApplied()
 	}
 	s.Scope.UpdatePutStatus(infrav1.ManagedClusterRunningCondition, serviceName, resultErr)
 	return resultErr
 }
 
+// controlPlaneIdentityStatus returns the status of the identity of the control plane of a managed cluster, or nil if
+// AKS doesn't report one.
+func controlPlaneIdentityStatus(identity *containerservice.ManagedClusterIdentity) *infrav1.ManagedClusterIdentityStatus {
+	if identity == nil {
+		return nil
+	}
+	switch identity.Type {
+	case containerservice.ResourceIdentityTypeSystemAssigned:
+		if identity.PrincipalID == nil {
+			return nil
+		}
+		return &infrav1.ManagedClusterIdentityStatus{
+			ObjectID: *identity.PrincipalID,
+		}
+	case containerservice.ResourceIdentityTypeUserAssigned:
+		// AKS control planes only support a single user-assigned identity.
+		for resourceID, userAssignedIdentity := range identity.UserAssignedIdentities {
+			if userAssignedIdentity == nil {
+				continue
+			}
+			return &infrav1.ManagedClusterIdentityStatus{
+				ResourceID: resourceID,
+				ClientID:   ptr.Deref(userAssignedIdentity.ClientID, ""),
+				ObjectID:   ptr.Deref(userAssignedIdentity.PrincipalID, ""),
+			}
+		}
+	}
+	return nil
+}
+
+// kubeletIdentityStatus returns the status of the kubelet identity of a managed cluster, or nil if AKS doesn't report one.
+func kubeletIdentityStatus(identity *containerservice.UserAssignedIdentity) *infrav1.ManagedClusterIdentityStatus {
+	if identity == nil {
+		return nil
+	}
+	return &infrav1.ManagedClusterIdentityStatus{
+		ResourceID: ptr.Deref(identity.ResourceID, ""),
+		ClientID:   ptr.Deref(identity.ClientID, ""),
+		ObjectID:   ptr.Deref(identity.ObjectID, ""),
+	}
+}
+
+// oidcIssuerProfileStatus returns the OIDC issuer of the cluster, or nil if the OIDC issuer isn't enabled.
+func oidcIssuerProfileStatus(profile *containerservice.ManagedClusterOIDCIssuerProfile) *infrav1.OIDCIssuerProfileStatus {
+	if profile == nil || profile.IssuerURL == nil {
+		return nil
+	}
+	return &infrav1.OIDCIssuerProfileStatus{
+		IssuerURL: profile.IssuerURL,
+	}
+}
+
 // Delete deletes the managed cluster.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.Service.Delete")
@@ -21,7 +21,7 @@ import (
 	"errors"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2022-03-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/preview/containerservice/mgmt/2022-03-02-preview/containerservice"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 	"k8s.io/utils/ptr"
@@ -62,14 +62,24 @@ func TestReconcile(t *testing.T) {
 			expect: func(m *mock_managedclusters.MockCredentialGetterMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.ManagedClusterSpec().Return(fakeManagedClusterSpec)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), fakeManagedClusterSpec, serviceName).Return(containerservice.ManagedCluster{
+					Identity: &containerservice.ManagedClusterIdentity{
+						Type:        containerservice.ResourceIdentityTypeSystemAssigned,
+						PrincipalID: ptr.To("control-plane-object-id"),
+					},
 					ManagedClusterProperties: &containerservice.ManagedClusterProperties{
 						Fqdn:              ptr.To("my-managedcluster-fqdn"),
 						ProvisioningState: ptr.To("Succeeded"),
 						IdentityProfile: map[string]*containerservice.UserAssignedIdentity{
 							kubeletIdentityKey: {
 								ResourceID: ptr.To("kubelet-id"),
+								ClientID:   ptr.To("kubelet-client-id"),
+								ObjectID:   ptr.To("kubelet-object-id"),
 							},
 						},
+						OidcIssuerProfile: &containerservice.ManagedClusterOIDCIssuerProfile{
+							Enabled:   ptr.To(true),
+							IssuerURL: ptr.To("https://oidc.example.com/issuer"),
+						},
 					},
 				}, nil)
 				s.SetControlPlaneEndpoint(clusterv1.APIEndpoint{
@@ -79,6 +89,18 @@ func TestReconcile(t *testing.T) {
 				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return([]byte("credentials"), nil)
 				s.SetKubeConfigData([]byte("credentials"))
 				s.SetKubeletIdentity("kubelet-id")
+				s.SetControlPlaneIdentityStatus(&infrav1.ManagedClusterIdentityStatus{
+					ObjectID: "control-plane-object-id",
+				})
+				s.SetKubeletIdentityStatus(&infrav1.ManagedClusterIdentityStatus{
+					ResourceID: "kubelet-id",
+					ClientID:   "kubelet-client-id",
+					ObjectID:   "kubelet-object-id",
+				})
+				s.SetOIDCIssuerProfileStatus(&infrav1.OIDCIssuerProfileStatus{
+					IssuerURL: ptr.To("https://oidc.example.com/issuer"),
+				})
+				s.SetWindowsAdminPasswordApplied()
 				s.UpdatePutStatus(infrav1.ManagedClusterRunningCondition, serviceName, nil)
 			},
 		},
@@ -132,6 +154,70 @@ func TestReconcile(t *testing.T) {
 	}
 }
 
+func TestControlPlaneIdentityStatus(t *testing.T) {
+	testcases := []struct {
+		name     string
+		identity *containerservice.ManagedClusterIdentity
+		expected *infrav1.ManagedClusterIdentityStatus
+	}{
+		{
+			name:     "no identity",
+			identity: nil,
+			expected: nil,
+		},
+		{
+			name: "system-assigned identity",
+			identity: &containerservice.ManagedClusterIdentity{
+				Type:        containerservice.ResourceIdentityTypeSystemAssigned,
+				PrincipalID: ptr.To("object-id"),
+				TenantID:    ptr.To("tenant-id"),
+			},
+			expected: &infrav1.ManagedClusterIdentityStatus{
+				ObjectID: "object-id",
+			},
+		},
+		{
+			name: "system-assigned identity not reported yet",
+			identity: &containerservice.ManagedClusterIdentity{
+				Type: containerservice.ResourceIdentityTypeSystemAssigned,
+			},
+			expected: nil,
+		},
+		{
+			name: "user-assigned identity",
+			identity: &containerservice.ManagedClusterIdentity{
+				Type: containerservice.ResourceIdentityTypeUserAssigned,
+				UserAssignedIdentities: map[string]*containerservice.ManagedClusterIdentityUserAssignedIdentitiesValue{
+					"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity": {
+						ClientID:    ptr.To("client-id"),
+						PrincipalID: ptr.To("object-id"),
+					},
+				},
+			},
+			expected: &infrav1.ManagedClusterIdentityStatus{
+				ResourceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity",
+				ClientID:   "client-id",
+				ObjectID:   "object-id",
+			},
+		},
+		{
+			name: "no identity type",
+			identity: &containerservice.ManagedClusterIdentity{
+				Type: containerservice.ResourceIdentityTypeNone,
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(controlPlaneIdentityStatus(tc.identity)).To(Equal(tc.expected))
+		})
+	}
+}
+
 func TestDelete(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -219,6 +219,18 @@ func (mr *MockManagedClusterScopeMockRecorder) SetControlPlaneEndpoint(arg0 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetControlPlaneEndpoint", reflect.TypeOf((*MockManagedClusterScope)(nil).SetControlPlaneEndpoint), arg0)
 }
 
+// SetControlPlaneIdentityStatus mocks base method.
+func (m *MockManagedClusterScope) SetControlPlaneIdentityStatus(arg0 *v1beta1.ManagedClusterIdentityStatus) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetControlPlaneIdentityStatus", arg0)
+}
+
+// SetControlPlaneIdentityStatus indicates an expected call of SetControlPlaneIdentityStatus.
+func (mr *MockManagedClusterScopeMockRecorder) SetControlPlaneIdentityStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetControlPlaneIdentityStatus", reflect.TypeOf((*MockManagedClusterScope)(nil).SetControlPlaneIdentityStatus), arg0)
+}
+
 // SetKubeConfigData mocks base method.
 func (m *MockManagedClusterScope) SetKubeConfigData(arg0 []byte) {
 	m.ctrl.T.Helper()
@@ -243,6 +255,18 @@ func (mr *MockManagedClusterScopeMockRecorder) SetKubeletIdentity(arg0 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKubeletIdentity", reflect.TypeOf((*MockManagedClusterScope)(nil).SetKubeletIdentity), arg0)
 }
 
+// SetKubeletIdentityStatus mocks base method.
+func (m *MockManagedClusterScope) SetKubeletIdentityStatus(arg0 *v1beta1.ManagedClusterIdentityStatus) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetKubeletIdentityStatus", arg0)
+}
+
+// SetKubeletIdentityStatus indicates an expected call of SetKubeletIdentityStatus.
+func (mr *MockManagedClusterScopeMockRecorder) SetKubeletIdentityStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKubeletIdentityStatus", reflect.TypeOf((*MockManagedClusterScope)(nil).SetKubeletIdentityStatus), arg0)
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockManagedClusterScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
@@ -255,6 +279,30 @@ func (mr *MockManagedClusterScopeMockRecorder) SetLongRunningOperationState(arg0
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLongRunningOperationState", reflect.TypeOf((*MockManagedClusterScope)(nil).SetLongRunningOperationState), arg0)
 }
 
+// SetOIDCIssuerProfileStatus mocks base method.
+func (m *MockManagedClusterScope) SetOIDCIssuerProfileStatus(arg0 *v1beta1.OIDCIssuerProfileStatus) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetOIDCIssuerProfileStatus", arg0)
+}
+
+// SetOIDCIssuerProfileStatus indicates an expected call of SetOIDCIssuerProfileStatus.
+func (mr *MockManagedClusterScopeMockRecorder) SetOIDCIssuerProfileStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOIDCIssuerProfileStatus", reflect.TypeOf((*MockManagedClusterScope)(nil).SetOIDCIssuerProfileStatus), arg0)
+}
+
+// SetWindowsAdminPasswordApplied mocks base method.
+func (m *MockManagedClusterScope) SetWindowsAdminPasswordApplied() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetWindowsAdminPasswordApplied")
+}
+
+// SetWindowsAdminPasswordApplied indicates an expected call of SetWindowsAdminPasswordApplied.
+func (mr *MockManagedClusterScopeMockRecorder) SetWindowsAdminPasswordApplied() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWindowsAdminPasswordApplied", reflect.TypeOf((*MockManagedClusterScope)(nil).SetWindowsAdminPasswordApplied))
+}
+
 // SubscriptionID mocks base method.
 func (m *MockManagedClusterScope) SubscriptionID() string {
 	m.ctrl.T.Helper()
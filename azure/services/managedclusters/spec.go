@@ -24,7 +24,7 @@ import (
 	"reflect"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2022-03-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/preview/containerservice/mgmt/2022-03-02-preview/containerservice"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	"k8s.io/utils/ptr"
@@ -116,6 +116,48 @@ type ManagedClusterSpec struct {
 
 	// HTTPProxyConfig is the HTTP proxy configuration for the cluster.
 	HTTPProxyConfig *HTTPProxyConfig
+
+	// WindowsProfile is the profile of the Windows nodes of the cluster.
+	WindowsProfile *WindowsProfile
+
+	// OIDCIssuerProfile is the OIDC issuer profile of the cluster.
+	OIDCIssuerProfile *OIDCIssuerProfile
+}
+
+// OIDCIssuerProfile is the OIDC issuer profile of the cluster.
+type OIDCIssuerProfile struct {
+	// Enabled defines whether to enable the OIDC issuer.
+	Enabled *bool
+}
+
+// WindowsProfile is the profile of the Windows nodes of the cluster.
+type WindowsProfile struct {
+	// AdminUsername is the name of the administrator account of the Windows nodes.
+	AdminUsername string
+
+	// AdminPassword is the password of the administrator account of the Windows nodes.
+	AdminPassword string
+
+	// RotateAdminPassword is true when AdminPassword differs from the password last applied to the cluster.
+	RotateAdminPassword bool
+
+	// LicenseType is the license type of the Windows nodes. Possible values include: 'None', 'Windows_Server'.
+	LicenseType *string
+
+	// GmsaProfile is the Group Managed Service Account profile of the Windows nodes.
+	GmsaProfile *GmsaProfile
+}
+
+// GmsaProfile is the Group Managed Service Account profile of the Windows nodes.
+type GmsaProfile struct {
+	// Enabled defines whether to enable Group Managed Service Accounts.
+	Enabled bool
+
+	// DNSServer is the DNS server of the Active Directory domain.
+	DNSServer string
+
+	// RootDomainName is the root domain name of the Active Directory domain.
+	RootDomainName string
 }
 
 // HTTPProxyConfig is the HTTP proxy configuration for the cluster.
@@ -444,6 +486,31 @@ func (s *ManagedClusterSpec) Parameters(ctx context.Context, existing interface{
 		}
 	}
 
+	if s.WindowsProfile != nil {
+		managedCluster.WindowsProfile = &containerservice.ManagedClusterWindowsProfile{
+			AdminUsername: ptr.To(s.WindowsProfile.AdminUsername),
+			AdminPassword: ptr.To(s.WindowsProfile.AdminPassword),
+		}
+		if s.WindowsProfile.LicenseType != nil {
+			managedCluster.WindowsProfile.LicenseType = containerservice.LicenseType(*s.WindowsProfile.LicenseType)
+		}
+		if gmsa := s.WindowsProfile.GmsaProfile; gmsa != nil {
+			managedCluster.WindowsProfile.GmsaProfile = &containerservice.WindowsGmsaProfile{
+				Enabled: ptr.To(gmsa.Enabled),
+			}
+			if gmsa.DNSServer != "" {
+				managedCluster.WindowsProfile.GmsaProfile.DNSServer = ptr.To(gmsa.DNSServer)
+				managedCluster.WindowsProfile.GmsaProfile.RootDomainName = ptr.To(gmsa.RootDomainName)
+			}
+		}
+	}
+
+	if s.OIDCIssuerProfile != nil {
+		managedCluster.OidcIssuerProfile = &containerservice.ManagedClusterOIDCIssuerProfile{
+			Enabled: s.OIDCIssuerProfile.Enabled,
+		}
+	}
+
 	if existing != nil {
 		existingMC, ok := existing.(containerservice.ManagedCluster)
 		if !ok {
@@ -479,11 +546,16 @@ func (s *ManagedClusterSpec) Parameters(ctx context.Context, existing interface{
 		}
 
 		diff := computeDiffOfNormalizedClusters(managedCluster, existingMC)
-		if diff == "" {
+		switch {
+		case diff != "":
+			log.V(4).Info("found a diff between the desired spec and the existing managed cluster", "difference", diff)
+		case s.WindowsProfile != nil && s.WindowsProfile.RotateAdminPassword:
+			// AKS never returns the password, so a rotation can't show up in the diff.
+			log.V(4).Info("rotating the Windows admin password of the managed cluster")
+		default:
 			log.V(4).Info("no changes found between user-updated spec and existing spec")
 			return nil, nil
 		}
-		log.V(4).Info("found a diff between the desired spec and the existing managed cluster", "difference", diff)
 	} else {
 		// Add all agent pools to cluster spec that will be submitted to the API
 		agentPoolSpecs, err := s.GetAllAgentPools()
@@ -655,6 +727,43 @@ func computeDiffOfNormalizedClusters(managedCluster containerservice.ManagedClus
 		}
 	}
 
+	if managedCluster.WindowsProfile != nil {
+		propertiesNormalized.WindowsProfile = &containerservice.ManagedClusterWindowsProfile{
+			AdminUsername: managedCluster.WindowsProfile.AdminUsername,
+			LicenseType:   managedCluster.WindowsProfile.LicenseType,
+			GmsaProfile:   managedCluster.WindowsProfile.GmsaProfile,
+		}
+	}
+
+	// AKS creates a Windows profile for clusters with Windows agent pools, so only compare it when CAPZ sets it.
+	if existingMC.WindowsProfile != nil && propertiesNormalized.WindowsProfile != nil {
+		existingMCPropertiesNormalized.WindowsProfile = &containerservice.ManagedClusterWindowsProfile{
+			AdminUsername: existingMC.WindowsProfile.AdminUsername,
+			LicenseType:   existingMC.WindowsProfile.LicenseType,
+			GmsaProfile:   existingMC.WindowsProfile.GmsaProfile,
+		}
+
+		// AKS defaults the license type and the GMSA profile, so only compare them when CAPZ sets them.
+		if propertiesNormalized.WindowsProfile.LicenseType == "" {
+			existingMCPropertiesNormalized.WindowsProfile.LicenseType = ""
+		}
+		if propertiesNormalized.WindowsProfile.GmsaProfile == nil {
+			existingMCPropertiesNormalized.WindowsProfile.GmsaProfile = nil
+		}
+	}
+
+	// AKS reports the OIDC issuer profile of every cluster, so only compare it when CAPZ sets it.
+	if managedCluster.OidcIssuerProfile != nil {
+		propertiesNormalized.OidcIssuerProfile = &containerservice.ManagedClusterOIDCIssuerProfile{
+			Enabled: managedCluster.OidcIssuerProfile.Enabled,
+		}
+		if existingMC.OidcIssuerProfile != nil {
+			existingMCPropertiesNormalized.OidcIssuerProfile = &containerservice.ManagedClusterOIDCIssuerProfile{
+				Enabled: existingMC.OidcIssuerProfile.Enabled,
+			}
+		}
+	}
+
 	// Once the AKS autoscaler has been updated it will always return values so we need to
 	// respect those values even though the settings are now not being explicitly set by CAPZ.
 	if existingMC.AutoScalerProfile != nil && managedCluster.AutoScalerProfile == nil {
@@ -21,7 +21,7 @@ import (
 	"encoding/base64"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2022-03-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/preview/containerservice/mgmt/2022-03-02-preview/containerservice"
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/format"
@@ -357,6 +357,163 @@ func TestParameters(t *testing.T) {
 				g.Expect(result).To(BeNil())
 			},
 		},
+		{
+			name:     "no update needed when the Windows profile and admin password are unchanged",
+			existing: getExistingClusterWithWindowsProfile(),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:         "v1.22.0",
+				LoadBalancerSKU: "Standard",
+				WindowsProfile: &WindowsProfile{
+					AdminUsername: "azureuser",
+					AdminPassword: "P@ssw0rd1",
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:     "no update needed when the spec doesn't set the Windows profile created by AKS",
+			existing: getExistingClusterWithWindowsProfile(),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:         "v1.22.0",
+				LoadBalancerSKU: "Standard",
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:     "managedcluster exists and the Windows admin password is rotated",
+			existing: getExistingClusterWithWindowsProfile(),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:         "v1.22.0",
+				LoadBalancerSKU: "Standard",
+				WindowsProfile: &WindowsProfile{
+					AdminUsername:       "azureuser",
+					AdminPassword:       "P@ssw0rd2",
+					RotateAdminPassword: true,
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(containerservice.ManagedCluster{}))
+				g.Expect(result.(containerservice.ManagedCluster).WindowsProfile).To(Equal(&containerservice.ManagedClusterWindowsProfile{
+					AdminUsername: ptr.To("azureuser"),
+					AdminPassword: ptr.To("P@ssw0rd2"),
+				}))
+			},
+		},
+		{
+			name:     "managedcluster exists and the Windows license type is updated",
+			existing: getExistingClusterWithWindowsProfile(),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:         "v1.22.0",
+				LoadBalancerSKU: "Standard",
+				WindowsProfile: &WindowsProfile{
+					AdminUsername: "azureuser",
+					AdminPassword: "P@ssw0rd1",
+					LicenseType:   ptr.To("Windows_Server"),
+					GmsaProfile: &GmsaProfile{
+						Enabled: true,
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(containerservice.ManagedCluster{}))
+				g.Expect(result.(containerservice.ManagedCluster).WindowsProfile).To(Equal(&containerservice.ManagedClusterWindowsProfile{
+					AdminUsername: ptr.To("azureuser"),
+					AdminPassword: ptr.To("P@ssw0rd1"),
+					LicenseType:   containerservice.LicenseTypeWindowsServer,
+					GmsaProfile: &containerservice.WindowsGmsaProfile{
+						Enabled: ptr.To(true),
+					},
+				}))
+			},
+		},
+		{
+			name:     "managedcluster exists and the OIDC issuer is enabled",
+			existing: getExistingClusterWithOIDCIssuerProfile(false),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:         "v1.22.0",
+				LoadBalancerSKU: "Standard",
+				OIDCIssuerProfile: &OIDCIssuerProfile{
+					Enabled: ptr.To(true),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(containerservice.ManagedCluster{}))
+				g.Expect(result.(containerservice.ManagedCluster).OidcIssuerProfile).To(Equal(&containerservice.ManagedClusterOIDCIssuerProfile{
+					Enabled: ptr.To(true),
+				}))
+			},
+		},
+		{
+			name:     "no update needed when the OIDC issuer is enabled",
+			existing: getExistingClusterWithOIDCIssuerProfile(true),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:         "v1.22.0",
+				LoadBalancerSKU: "Standard",
+				OIDCIssuerProfile: &OIDCIssuerProfile{
+					Enabled: ptr.To(true),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:     "no update needed when the spec doesn't set the OIDC issuer profile reported by AKS",
+			existing: getExistingClusterWithOIDCIssuerProfile(false),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:         "v1.22.0",
+				LoadBalancerSKU: "Standard",
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
 	}
 	for _, tc := range testcases {
 		tc := tc
@@ -441,6 +598,27 @@ func getExistingCluster() containerservice.ManagedCluster {
 	return mc
 }
 
+func getExistingClusterWithWindowsProfile() containerservice.ManagedCluster {
+	mc := getExistingCluster()
+	mc.WindowsProfile = &containerservice.ManagedClusterWindowsProfile{
+		AdminUsername:  ptr.To("azureuser"),
+		LicenseType:    containerservice.LicenseTypeNone,
+		EnableCSIProxy: ptr.To(true),
+	}
+	return mc
+}
+
+func getExistingClusterWithOIDCIssuerProfile(enabled bool) containerservice.ManagedCluster {
+	mc := getExistingCluster()
+	mc.OidcIssuerProfile = &containerservice.ManagedClusterOIDCIssuerProfile{
+		Enabled: ptr.To(enabled),
+	}
+	if enabled {
+		mc.OidcIssuerProfile.IssuerURL = ptr.To("https://oidc.example.com/issuer")
+	}
+	return mc
+}
+
 func getSampleManagedCluster() containerservice.ManagedCluster {
 	return containerservice.ManagedCluster{
 		ManagedClusterProperties: &containerservice.ManagedClusterProperties{
@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managedclusters
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/preview/containerservice/mgmt/2022-03-02-preview/containerservice"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// upgradesCacheTTL is how long the available upgrades of a managed cluster are cached. Upgrades are cached per version
+// of the cluster, so the TTL only bounds how long newly released AKS versions go unnoticed.
+const upgradesCacheTTL = 10 * time.Minute
+
+var (
+	upgradesOnce  sync.Once
+	upgradesCache ttllru.PeekingCacher
+)
+
+// upgradesKey identifies the available upgrades of a managed cluster running a given Kubernetes version.
+type upgradesKey struct {
+	subscriptionID    string
+	resourceGroupName string
+	name              string
+	version           string
+}
+
+// upgrades are the Kubernetes version of a managed cluster and the versions it can be upgraded to.
+type upgrades struct {
+	current  string
+	upgrades []string
+}
+
+// GetUpgrades returns the Kubernetes version of a managed cluster and the versions it can be upgraded to, according to
+// its upgrade profile. Results are cached by the Kubernetes version the caller expects the cluster to run, so they are
+// looked up again once the cluster has been upgraded.
+func GetUpgrades(ctx context.Context, auth azure.Authorizer, resourceGroupName, name, version string) (string, []string, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "managedclusters.GetUpgrades")
+	defer done()
+
+	var err error
+	upgradesOnce.Do(func() {
+		upgradesCache, err = ttllru.New(1024, upgradesCacheTTL)
+	})
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed creating LRU cache for managed cluster upgrades")
+	}
+
+	key := upgradesKey{
+		subscriptionID:    auth.SubscriptionID(),
+		resourceGroupName: resourceGroupName,
+		name:              name,
+		version:           version,
+	}
+	if cached, ok := upgradesCache.Get(key); ok {
+		log.V(4).Info("managed cluster upgrades cache hit", "resourceGroup", resourceGroupName, "name", name)
+		u := cached.(upgrades)
+		return u.current, u.upgrades, nil
+	}
+
+	log.V(4).Info("managed cluster upgrades cache miss", "resourceGroup", resourceGroupName, "name", name)
+	profile, err := newClient(auth).GetUpgradeProfile(ctx, resourceGroupName, name)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to get managed cluster upgrade profile")
+	}
+
+	u := upgradesFromProfile(profile)
+	_ = upgradesCache.Add(key, u)
+	return u.current, u.upgrades, nil
+}
+
+// upgradesFromProfile returns the Kubernetes version of the control plane of a managed cluster and the versions it
+// can be upgraded to, including preview versions.
+func upgradesFromProfile(profile containerservice.ManagedClusterUpgradeProfile) upgrades {
+	var u upgrades
+	if profile.ManagedClusterUpgradeProfileProperties == nil || profile.ControlPlaneProfile == nil {
+		return u
+	}
+
+	u.current = ptr.Deref(profile.ControlPlaneProfile.KubernetesVersion, "")
+	if profile.ControlPlaneProfile.Upgrades == nil {
+		return u
+	}
+	for _, upgrade := range *profile.ControlPlaneProfile.Upgrades {
+		if upgrade.KubernetesVersion == nil {
+			continue
+		}
+		u.upgrades = append(u.upgrades, *upgrade.KubernetesVersion)
+	}
+	return u
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managedclusters
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/preview/containerservice/mgmt/2022-03-02-preview/containerservice"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+func TestUpgradesFromProfile(t *testing.T) {
+	testcases := []struct {
+		name     string
+		profile  containerservice.ManagedClusterUpgradeProfile
+		expected upgrades
+	}{
+		{
+			name:     "no properties",
+			profile:  containerservice.ManagedClusterUpgradeProfile{},
+			expected: upgrades{},
+		},
+		{
+			name: "no upgrades",
+			profile: containerservice.ManagedClusterUpgradeProfile{
+				ManagedClusterUpgradeProfileProperties: &containerservice.ManagedClusterUpgradeProfileProperties{
+					ControlPlaneProfile: &containerservice.ManagedClusterPoolUpgradeProfile{
+						KubernetesVersion: ptr.To("1.27.3"),
+					},
+				},
+			},
+			expected: upgrades{current: "1.27.3"},
+		},
+		{
+			name: "upgrades including a preview version",
+			profile: containerservice.ManagedClusterUpgradeProfile{
+				ManagedClusterUpgradeProfileProperties: &containerservice.ManagedClusterUpgradeProfileProperties{
+					ControlPlaneProfile: &containerservice.ManagedClusterPoolUpgradeProfile{
+						KubernetesVersion: ptr.To("1.26.6"),
+						Upgrades: &[]containerservice.ManagedClusterPoolUpgradeProfileUpgradesItem{
+							{KubernetesVersion: ptr.To("1.27.3")},
+							{KubernetesVersion: ptr.To("1.27.7"), IsPreview: ptr.To(true)},
+							{},
+						},
+					},
+				},
+			},
+			expected: upgrades{current: "1.26.6", upgrades: []string{"1.27.3", "1.27.7"}},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(upgradesFromProfile(tc.profile)).To(Equal(tc.expected))
+		})
+	}
+}
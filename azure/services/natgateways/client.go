@@ -35,7 +35,7 @@ type azureClient struct {
 
 // newClient creates a new nat gateways client from an authorizer.
 func newClient(auth azure.Authorizer) (*azureClient, error) {
-	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment(), serviceName)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create natgateways client options")
 	}
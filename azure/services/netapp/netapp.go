@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netapp
+
+import (
+	"context"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// ServiceName is the name of this service.
+	ServiceName = "netapp"
+	// SubnetDelegation is the service a subnet must be delegated to in order to host Azure NetApp Files volumes.
+	SubnetDelegation = "Microsoft.NetApp/volumes"
+)
+
+// Scope defines the scope interface for an Azure NetApp Files service.
+type Scope interface {
+	azure.ClusterDescriber
+	azure.AsyncStatusUpdater
+	NetAppAccountSpec() azure.ResourceSpecGetter
+	NetAppCapacityPoolSpecs() []azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope             Scope
+	accountReconciler async.Reconciler
+	poolReconciler    async.Reconciler
+}
+
+// New creates a new Azure NetApp Files service.
+func New(scope Scope) *Service {
+	accountsClient := newAccountsClient(scope)
+	poolsClient := newPoolsClient(scope)
+	return &Service{
+		Scope:             scope,
+		accountReconciler: async.New(scope, accountsClient, accountsClient),
+		poolReconciler:    async.New(scope, poolsClient, poolsClient),
+	}
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return ServiceName
+}
+
+// Reconcile idempotently creates or updates the NetApp account and its capacity pools.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "netapp.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	accountSpec := s.Scope.NetAppAccountSpec()
+	if accountSpec == nil {
+		return nil
+	}
+
+	_, result := s.accountReconciler.CreateOrUpdateResource(ctx, accountSpec, ServiceName)
+	if result == nil {
+		// We go through the list of capacity pools to reconcile each one, independently of the result of the previous one.
+		// If multiple errors occur, we return the most pressing one.
+		//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
+		for _, poolSpec := range s.Scope.NetAppCapacityPoolSpecs() {
+			if _, err := s.poolReconciler.CreateOrUpdateResource(ctx, poolSpec, ServiceName); err != nil {
+				if !azure.IsOperationNotDoneError(err) || result == nil {
+					result = err
+				}
+			}
+		}
+	}
+
+	s.Scope.UpdatePutStatus(infrav1.NetAppReadyCondition, ServiceName, result)
+	return result
+}
+
+// Delete deletes the capacity pools and then the NetApp account.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "netapp.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	accountSpec := s.Scope.NetAppAccountSpec()
+	if accountSpec == nil {
+		return nil
+	}
+
+	// An account can only be deleted once all of its capacity pools are gone.
+	var result error
+	for _, poolSpec := range s.Scope.NetAppCapacityPoolSpecs() {
+		if err := s.poolReconciler.DeleteResource(ctx, poolSpec, ServiceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+	if result == nil {
+		result = s.accountReconciler.DeleteResource(ctx, accountSpec, ServiceName)
+	}
+
+	s.Scope.UpdateDeleteStatus(infrav1.NetAppReadyCondition, ServiceName, result)
+	return result
+}
+
+// IsManaged returns always returns true as the NetApp account is always created by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
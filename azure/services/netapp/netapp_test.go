@@ -0,0 +1,232 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netapp
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/netapp/mock_netapp"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+const (
+	accountName   = "my-cluster-netapp"
+	resourceGroup = "my-rg"
+	clusterName   = "my-cluster"
+	location      = "eastus"
+)
+
+var (
+	fakeAccount = &AccountSpec{
+		Name:          accountName,
+		ResourceGroup: resourceGroup,
+		Location:      location,
+		ClusterName:   clusterName,
+	}
+
+	fakePool1 = &CapacityPoolSpec{
+		Name:          "pool1",
+		AccountName:   accountName,
+		ResourceGroup: resourceGroup,
+		Location:      location,
+		ServiceLevel:  infrav1.NetAppServiceLevelPremium,
+		SizeTiB:       4,
+		ClusterName:   clusterName,
+	}
+
+	fakePool2 = &CapacityPoolSpec{
+		Name:          "pool2",
+		AccountName:   accountName,
+		ResourceGroup: resourceGroup,
+		Location:      location,
+		ServiceLevel:  infrav1.NetAppServiceLevelUltra,
+		SizeTiB:       2,
+		ClusterName:   clusterName,
+	}
+
+	fakePoolSpecs = []azure.ResourceSpecGetter{fakePool1, fakePool2}
+
+	notDoneError = azure.NewOperationNotDoneError(&infrav1.Future{Type: "resourceType", ResourceGroup: resourceGroup, Name: "resourceName"})
+	errFake      = errors.New("this is an error")
+)
+
+func TestReconcileNetApp(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_netapp.MockScopeMockRecorder, a, p *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "no netapp account",
+			expectedError: "",
+			expect: func(s *mock_netapp.MockScopeMockRecorder, a, p *mock_async.MockReconcilerMockRecorder) {
+				s.NetAppAccountSpec().Return(nil)
+			},
+		},
+		{
+			name:          "create account and capacity pools successfully",
+			expectedError: "",
+			expect: func(s *mock_netapp.MockScopeMockRecorder, a, p *mock_async.MockReconcilerMockRecorder) {
+				s.NetAppAccountSpec().Return(fakeAccount)
+				a.CreateOrUpdateResource(gomockinternal.AContext(), fakeAccount, ServiceName).Return(nil, nil)
+				s.NetAppCapacityPoolSpecs().Return(fakePoolSpecs)
+				p.CreateOrUpdateResource(gomockinternal.AContext(), fakePool1, ServiceName).Return(nil, nil)
+				p.CreateOrUpdateResource(gomockinternal.AContext(), fakePool2, ServiceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.NetAppReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "account creation fails",
+			expectedError: "this is an error",
+			expect: func(s *mock_netapp.MockScopeMockRecorder, a, p *mock_async.MockReconcilerMockRecorder) {
+				s.NetAppAccountSpec().Return(fakeAccount)
+				a.CreateOrUpdateResource(gomockinternal.AContext(), fakeAccount, ServiceName).Return(nil, errFake)
+				s.UpdatePutStatus(infrav1.NetAppReadyCondition, ServiceName, errFake)
+			},
+		},
+		{
+			name:          "capacity pool creation fails while another is in progress",
+			expectedError: "this is an error",
+			expect: func(s *mock_netapp.MockScopeMockRecorder, a, p *mock_async.MockReconcilerMockRecorder) {
+				s.NetAppAccountSpec().Return(fakeAccount)
+				a.CreateOrUpdateResource(gomockinternal.AContext(), fakeAccount, ServiceName).Return(nil, nil)
+				s.NetAppCapacityPoolSpecs().Return(fakePoolSpecs)
+				p.CreateOrUpdateResource(gomockinternal.AContext(), fakePool1, ServiceName).Return(nil, notDoneError)
+				p.CreateOrUpdateResource(gomockinternal.AContext(), fakePool2, ServiceName).Return(nil, errFake)
+				s.UpdatePutStatus(infrav1.NetAppReadyCondition, ServiceName, errFake)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_netapp.NewMockScope(mockCtrl)
+			accountReconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+			poolReconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), accountReconcilerMock.EXPECT(), poolReconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:             scopeMock,
+				accountReconciler: accountReconcilerMock,
+				poolReconciler:    poolReconcilerMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteNetApp(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_netapp.MockScopeMockRecorder, a, p *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "no netapp account",
+			expectedError: "",
+			expect: func(s *mock_netapp.MockScopeMockRecorder, a, p *mock_async.MockReconcilerMockRecorder) {
+				s.NetAppAccountSpec().Return(nil)
+			},
+		},
+		{
+			name:          "delete capacity pools and then the account",
+			expectedError: "",
+			expect: func(s *mock_netapp.MockScopeMockRecorder, a, p *mock_async.MockReconcilerMockRecorder) {
+				s.NetAppAccountSpec().Return(fakeAccount)
+				s.NetAppCapacityPoolSpecs().Return(fakePoolSpecs)
+				gomock.InOrder(
+					p.DeleteResource(gomockinternal.AContext(), fakePool1, ServiceName).Return(nil),
+					p.DeleteResource(gomockinternal.AContext(), fakePool2, ServiceName).Return(nil),
+					a.DeleteResource(gomockinternal.AContext(), fakeAccount, ServiceName).Return(nil),
+				)
+				s.UpdateDeleteStatus(infrav1.NetAppReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "account is kept while a capacity pool is being deleted",
+			expectedError: "operation type resourceType on Azure resource my-rg/resourceName is not done",
+			expect: func(s *mock_netapp.MockScopeMockRecorder, a, p *mock_async.MockReconcilerMockRecorder) {
+				s.NetAppAccountSpec().Return(fakeAccount)
+				s.NetAppCapacityPoolSpecs().Return(fakePoolSpecs)
+				p.DeleteResource(gomockinternal.AContext(), fakePool1, ServiceName).Return(notDoneError)
+				p.DeleteResource(gomockinternal.AContext(), fakePool2, ServiceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.NetAppReadyCondition, ServiceName, notDoneError)
+			},
+		},
+		{
+			name:          "account deletion fails",
+			expectedError: "this is an error",
+			expect: func(s *mock_netapp.MockScopeMockRecorder, a, p *mock_async.MockReconcilerMockRecorder) {
+				s.NetAppAccountSpec().Return(fakeAccount)
+				s.NetAppCapacityPoolSpecs().Return(nil)
+				a.DeleteResource(gomockinternal.AContext(), fakeAccount, ServiceName).Return(errFake)
+				s.UpdateDeleteStatus(infrav1.NetAppReadyCondition, ServiceName, errFake)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_netapp.NewMockScope(mockCtrl)
+			accountReconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+			poolReconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), accountReconcilerMock.EXPECT(), poolReconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:             scopeMock,
+				accountReconciler: accountReconcilerMock,
+				poolReconciler:    poolReconcilerMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
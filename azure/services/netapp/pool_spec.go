@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netapp
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/netapp/mgmt/2021-10-01/netapp"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// bytesPerTiB is the number of bytes in a TiB, the unit capacity pools are sized in.
+const bytesPerTiB int64 = 1 << 40
+
+// CapacityPoolSpec defines the specification for a NetApp capacity pool.
+type CapacityPoolSpec struct {
+	Name           string
+	AccountName    string
+	ResourceGroup  string
+	Location       string
+	ServiceLevel   infrav1.NetAppServiceLevel
+	SizeTiB        int64
+	ClusterName    string
+	AdditionalTags infrav1.Tags
+}
+
+// ResourceName returns the name of the capacity pool.
+func (s *CapacityPoolSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the capacity pool.
+func (s *CapacityPoolSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName returns the name of the NetApp account of the capacity pool.
+func (s *CapacityPoolSpec) OwnerResourceName() string {
+	return s.AccountName
+}
+
+// Parameters returns the parameters for the capacity pool.
+// An existing capacity pool is only updated when its size changed.
+func (s *CapacityPoolSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
+	size := s.SizeTiB * bytesPerTiB
+	if existing != nil {
+		existingPool, ok := existing.(netapp.CapacityPool)
+		if !ok {
+			return nil, errors.Errorf("%T is not a netapp.CapacityPool", existing)
+		}
+		if existingPool.PoolProperties != nil && ptr.Deref(existingPool.Size, 0) == size {
+			return nil, nil
+		}
+	}
+
+	return netapp.CapacityPool{
+		Location: ptr.To(s.Location),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        ptr.To(s.Name),
+			Additional:  s.AdditionalTags,
+		})),
+		PoolProperties: &netapp.PoolProperties{
+			Size:         ptr.To(size),
+			ServiceLevel: netapp.ServiceLevel(s.ServiceLevel),
+		},
+	}, nil
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netapp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/netapp/mgmt/2021-10-01/netapp"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+func TestAccountSpec_Parameters(t *testing.T) {
+	testcases := []struct {
+		name          string
+		spec          *AccountSpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name: "new account",
+			spec: fakeAccount,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(netapp.Account{
+					Location: ptr.To(location),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": ptr.To("owned"),
+						"Name": ptr.To(accountName),
+					},
+					AccountProperties: &netapp.AccountProperties{},
+				}))
+			},
+		},
+		{
+			name:     "existing account is not updated",
+			spec:     fakeAccount,
+			existing: netapp.Account{Name: ptr.To(accountName)},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:          "existing is not an account",
+			spec:          fakeAccount,
+			existing:      "not an account",
+			expectedError: "string is not a netapp.Account",
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			tc.expect(g, result)
+		})
+	}
+}
+
+func TestCapacityPoolSpec_Parameters(t *testing.T) {
+	fourTiB := int64(4) << 40
+	testcases := []struct {
+		name          string
+		spec          *CapacityPoolSpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name: "new capacity pool",
+			spec: fakePool1,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(netapp.CapacityPool{
+					Location: ptr.To(location),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": ptr.To("owned"),
+						"Name": ptr.To("pool1"),
+					},
+					PoolProperties: &netapp.PoolProperties{
+						Size:         ptr.To(fourTiB),
+						ServiceLevel: netapp.ServiceLevelPremium,
+					},
+				}))
+			},
+		},
+		{
+			name: "existing capacity pool with the same size",
+			spec: fakePool1,
+			existing: netapp.CapacityPool{
+				PoolProperties: &netapp.PoolProperties{
+					Size:         ptr.To(fourTiB),
+					ServiceLevel: netapp.ServiceLevelPremium,
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "existing capacity pool with a different size",
+			spec: fakePool1,
+			existing: netapp.CapacityPool{
+				PoolProperties: &netapp.PoolProperties{
+					Size:         ptr.To(int64(2) << 40),
+					ServiceLevel: netapp.ServiceLevelPremium,
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(netapp.CapacityPool{}))
+				g.Expect(result.(netapp.CapacityPool).Size).To(Equal(ptr.To(fourTiB)))
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			tc.expect(g, result)
+		})
+	}
+}
@@ -30,11 +30,18 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+// client wraps go-sdk.
+type client interface {
+	List(context.Context, string) ([]network.PublicIPAddress, error)
+}
+
 // AzureClient contains the Azure go-sdk Client.
 type AzureClient struct {
 	publicips network.PublicIPAddressesClient
 }
 
+var _ client = (*AzureClient)(nil)
+
 // NewClient creates a new public IP client from subscription ID.
 func NewClient(auth azure.Authorizer) *AzureClient {
 	c := newPublicIPAddressesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
@@ -56,6 +63,27 @@ func (ac *AzureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (
 	return ac.publicips.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), "")
 }
 
+// List returns all public IP addresses in a resource group.
+func (ac *AzureClient) List(ctx context.Context, resourceGroupName string) ([]network.PublicIPAddress, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "publicips.AzureClient.List")
+	defer done()
+
+	iter, err := ac.publicips.ListComplete(ctx, resourceGroupName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not list public IPs in resource group %s", resourceGroupName)
+	}
+
+	var publicIPs []network.PublicIPAddress
+	for iter.NotDone() {
+		publicIPs = append(publicIPs, iter.Value())
+		if err := iter.NextWithContext(ctx); err != nil {
+			return publicIPs, errors.Wrap(err, "could not iterate public IPs")
+		}
+	}
+
+	return publicIPs, nil
+}
+
 // CreateOrUpdateAsync creates or updates a static or dynamic public IP address.
 // It sends a PUT request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
 // progress of the operation.
@@ -290,6 +290,20 @@ func (mr *MockPublicIPScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockPublicIPScope)(nil).Location))
 }
 
+// OutboundPublicIPNamePrefixes mocks base method.
+func (m *MockPublicIPScope) OutboundPublicIPNamePrefixes() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OutboundPublicIPNamePrefixes")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// OutboundPublicIPNamePrefixes indicates an expected call of OutboundPublicIPNamePrefixes.
+func (mr *MockPublicIPScopeMockRecorder) OutboundPublicIPNamePrefixes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutboundPublicIPNamePrefixes", reflect.TypeOf((*MockPublicIPScope)(nil).OutboundPublicIPNamePrefixes))
+}
+
 // PublicIPSpecs mocks base method.
 func (m *MockPublicIPScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 	m.ctrl.T.Helper()
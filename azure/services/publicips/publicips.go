@@ -18,8 +18,10 @@ package publicips
 
 import (
 	"context"
+	"strings"
 
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
@@ -36,6 +38,7 @@ type PublicIPScope interface {
 	azure.AsyncStatusUpdater
 	azure.ClusterDescriber
 	PublicIPSpecs() []azure.ResourceSpecGetter
+	OutboundPublicIPNamePrefixes() []string
 }
 
 // Service provides operations on Azure resources.
@@ -44,6 +47,7 @@ type Service struct {
 	async.Reconciler
 	async.Getter
 	async.TagsGetter
+	client client
 }
 
 // New creates a new service.
@@ -55,6 +59,7 @@ func New(scope PublicIPScope) *Service {
 		Getter:     client,
 		TagsGetter: tagsClient,
 		Reconciler: async.New(scope, client, client),
+		client:     client,
 	}
 }
 
@@ -85,10 +90,69 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		}
 	}
 
+	if err := s.deleteUnusedOutboundIPs(ctx, specs); err != nil {
+		if !azure.IsOperationNotDoneError(err) || result == nil {
+			result = err
+		}
+	}
+
 	s.Scope.UpdatePutStatus(infrav1.PublicIPsReadyCondition, serviceName, result)
 	return result
 }
 
+// deleteUnusedOutboundIPs deletes the public IPs generated for the frontend IPs of the outbound load balancers that
+// are no longer wanted, e.g. after the frontend IPs count of a load balancer was decreased. A public IP is only
+// deleted once the load balancer no longer uses it.
+func (s *Service) deleteUnusedOutboundIPs(ctx context.Context, specs []azure.ResourceSpecGetter) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "publicips.Service.deleteUnusedOutboundIPs")
+	defer done()
+
+	prefixes := s.Scope.OutboundPublicIPNamePrefixes()
+	if len(prefixes) == 0 {
+		return nil
+	}
+
+	wanted := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		wanted[spec.ResourceName()] = true
+	}
+
+	ips, err := s.client.List(ctx, s.Scope.ResourceGroup())
+	if err != nil {
+		return errors.Wrap(err, "failed to list public IPs")
+	}
+
+	var result error
+	for _, ip := range ips {
+		name := ptr.Deref(ip.Name, "")
+		if wanted[name] || !hasAnyPrefix(name, prefixes) || !converters.MapToTags(ip.Tags).HasOwned(s.Scope.ClusterName()) {
+			continue
+		}
+		if props := ip.PublicIPAddressPropertiesFormat; props != nil && (props.IPConfiguration != nil || props.NatGateway != nil) {
+			log.V(4).Info("skipping deletion of unused outbound public IP still attached to a load balancer", "public ip", name)
+			continue
+		}
+
+		log.V(2).Info("deleting unused outbound public IP", "public ip", name)
+		spec := &PublicIPSpec{Name: name, ResourceGroup: s.Scope.ResourceGroup()}
+		if err := s.DeleteResource(ctx, spec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+	return result
+}
+
+func hasAnyPrefix(name string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // Delete deletes the public IP with the provided scope.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "publicips.Service.Delete")
@@ -116,6 +180,11 @@ func (s *Service) Delete(ctx context.Context) error {
 			continue
 		}
 
+		if spec, ok := publicIPSpec.(*PublicIPSpec); ok && spec.DeletePolicy == infrav1.DeletePolicyRetain {
+			log.V(2).Info("Skipping IP deletion for public IP with Retain delete policy", "public ip", publicIPSpec.ResourceName())
+			continue
+		}
+
 		log.V(2).Info("deleting public IP", "public ip", publicIPSpec.ResourceName())
 		hasManagedPublicIPs = true
 		if err := s.DeleteResource(ctx, publicIPSpec, serviceName); err != nil {
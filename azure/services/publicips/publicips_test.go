@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-10-01/resources"
 	"github.com/Azure/go-autorest/autorest"
 	. "github.com/onsi/gomega"
@@ -79,6 +80,15 @@ var (
 			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
 		},
 	}
+	fakePublicIPSpecRetained = PublicIPSpec{
+		Name:           "my-publicip-retained",
+		ResourceGroup:  "my-rg",
+		IsIPv6:         false,
+		ClusterName:    "my-cluster",
+		Location:       "centralIndia",
+		FailureDomains: []string{"failure-domain-id-1", "failure-domain-id-2", "failure-domain-id-3"},
+		DeletePolicy:   infrav1.DeletePolicyRetain,
+	}
 	fakePublicIPSpecIpv6 = PublicIPSpec{
 		Name:           "my-publicip-ipv6",
 		ResourceGroup:  "my-rg",
@@ -112,46 +122,103 @@ var (
 		},
 	}
 
+	fakeOutboundIPSpec = PublicIPSpec{
+		Name:          "pip-my-cluster-node-outbound",
+		ResourceGroup: "my-rg",
+		ClusterName:   "my-cluster",
+		Location:      "centralIndia",
+	}
+
+	ownedTags = map[string]*string{
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": ptr.To("owned"),
+	}
+
 	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
 )
 
+func fakeOutboundIP(name string, tags map[string]*string, attached bool) network.PublicIPAddress {
+	ip := network.PublicIPAddress{
+		Name:                            ptr.To(name),
+		Tags:                            tags,
+		PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{},
+	}
+	if attached {
+		ip.IPConfiguration = &network.IPConfiguration{ID: ptr.To("frontend-ip-id")}
+	}
+	return ip
+}
+
 func TestReconcilePublicIP(t *testing.T) {
 	testcases := []struct {
 		name          string
 		expectedError string
-		expect        func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+		expect        func(s *mock_publicips.MockPublicIPScopeMockRecorder, c *mock_publicips.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder)
 	}{
 		{
 			name:          "noop if no public IPs",
 			expectedError: "",
-			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, c *mock_publicips.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.PublicIPSpecs().Return([]azure.ResourceSpecGetter{})
 			},
 		},
 		{
 			name:          "successfully create public IPs",
 			expectedError: "",
-			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, c *mock_publicips.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.PublicIPSpecs().Return([]azure.ResourceSpecGetter{&fakePublicIPSpec1, &fakePublicIPSpec2, &fakePublicIPSpec3, &fakePublicIPSpecIpv6})
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec1, serviceName).Return(nil, nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec2, serviceName).Return(nil, nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec3, serviceName).Return(nil, nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpecIpv6, serviceName).Return(nil, nil)
+				s.OutboundPublicIPNamePrefixes().Return(nil)
 				s.UpdatePutStatus(infrav1.PublicIPsReadyCondition, serviceName, nil)
 			},
 		},
 		{
 			name:          "fail to create a public IP",
 			expectedError: internalError.Error(),
-			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, c *mock_publicips.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.PublicIPSpecs().Return([]azure.ResourceSpecGetter{&fakePublicIPSpec1, &fakePublicIPSpec2, &fakePublicIPSpec3, &fakePublicIPSpecIpv6})
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec1, serviceName).Return(nil, nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec2, serviceName).Return(nil, nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec3, serviceName).Return(nil, internalError)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpecIpv6, serviceName).Return(nil, nil)
+				s.OutboundPublicIPNamePrefixes().Return(nil)
 				s.UpdatePutStatus(infrav1.PublicIPsReadyCondition, serviceName, internalError)
 			},
 		},
+		{
+			name:          "delete unused outbound public IPs",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, c *mock_publicips.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.PublicIPSpecs().Return([]azure.ResourceSpecGetter{&fakeOutboundIPSpec})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeOutboundIPSpec, serviceName).Return(nil, nil)
+				s.OutboundPublicIPNamePrefixes().Return([]string{"pip-my-cluster-node-outbound"})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				c.List(gomockinternal.AContext(), "my-rg").Return([]network.PublicIPAddress{
+					fakeOutboundIP("pip-my-cluster-node-outbound-1", ownedTags, true),
+					fakeOutboundIP("pip-my-cluster-node-outbound-2", ownedTags, false),
+					fakeOutboundIP("pip-my-cluster-node-outbound-3", ownedTags, true),
+					fakeOutboundIP("pip-my-cluster-node-outbound-4", map[string]*string{}, false),
+					fakeOutboundIP("pip-my-vm", ownedTags, false),
+				}, nil)
+				r.DeleteResource(gomockinternal.AContext(), &PublicIPSpec{Name: "pip-my-cluster-node-outbound-2", ResourceGroup: "my-rg"}, serviceName).Return(nil)
+				s.UpdatePutStatus(infrav1.PublicIPsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to list public IPs",
+			expectedError: "failed to list public IPs: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, c *mock_publicips.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.PublicIPSpecs().Return([]azure.ResourceSpecGetter{&fakeOutboundIPSpec})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeOutboundIPSpec, serviceName).Return(nil, nil)
+				s.OutboundPublicIPNamePrefixes().Return([]string{"pip-my-cluster-node-outbound"})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				c.List(gomockinternal.AContext(), "my-rg").Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.PublicIPsReadyCondition, serviceName, gomockinternal.ErrStrEq("failed to list public IPs: #: Internal Server Error: StatusCode=500"))
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -164,15 +231,15 @@ func TestReconcilePublicIP(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			scopeMock := mock_publicips.NewMockPublicIPScope(mockCtrl)
-			tagsGetterMock := mock_async.NewMockTagsGetter(mockCtrl)
+			clientMock := mock_publicips.NewMockclient(mockCtrl)
 			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
 
-			tc.expect(scopeMock.EXPECT(), tagsGetterMock.EXPECT(), reconcilerMock.EXPECT())
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), reconcilerMock.EXPECT())
 
 			s := &Service{
 				Scope:      scopeMock,
-				TagsGetter: tagsGetterMock,
 				Reconciler: reconcilerMock,
+				client:     clientMock,
 			}
 
 			err := s.Reconcile(context.TODO())
@@ -227,6 +294,24 @@ func TestDeletePublicIP(t *testing.T) {
 				s.UpdateDeleteStatus(infrav1.PublicIPsReadyCondition, serviceName, nil)
 			},
 		},
+		{
+			name:          "successfully delete managed public IPs and skip public IPs with Retain delete policy",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.PublicIPSpecs().Return([]azure.ResourceSpecGetter{&fakePublicIPSpec1, &fakePublicIPSpecRetained})
+
+				s.SubscriptionID().Return("123")
+				m.GetAtScope(gomockinternal.AContext(), azure.PublicIPID("123", fakePublicIPSpec1.ResourceGroupName(), fakePublicIPSpec1.ResourceName())).Return(managedTags, nil)
+				s.ClusterName().Return("my-cluster")
+				r.DeleteResource(gomockinternal.AContext(), &fakePublicIPSpec1, serviceName).Return(nil)
+
+				s.SubscriptionID().Return("123")
+				m.GetAtScope(gomockinternal.AContext(), azure.PublicIPID("123", fakePublicIPSpecRetained.ResourceGroupName(), fakePublicIPSpecRetained.ResourceName())).Return(managedTags, nil)
+				s.ClusterName().Return("my-cluster")
+
+				s.UpdateDeleteStatus(infrav1.PublicIPsReadyCondition, serviceName, nil)
+			},
+		},
 		{
 			name:          "noop if no managed public IPs",
 			expectedError: "",
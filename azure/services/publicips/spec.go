@@ -39,6 +39,7 @@ type PublicIPSpec struct {
 	FailureDomains   []string
 	AdditionalTags   infrav1.Tags
 	IPTags           []infrav1.IPTag
+	DeletePolicy     infrav1.DeletePolicy
 }
 
 // ResourceName returns the name of the public IP.
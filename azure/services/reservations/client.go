@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservations
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/reservations/mgmt/2022-03-01/reservations"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	ListAll(context.Context) ([]reservations.Response, error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	reservations reservations.Client
+}
+
+var _ client = &azureClient{}
+
+// newClient creates a new reservations client from an authorizer.
+func newClient(auth azure.Authorizer) *azureClient {
+	return &azureClient{
+		reservations: newReservationsClient(auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newReservationsClient creates a new reservations client. Reservations aren't scoped to a subscription.
+func newReservationsClient(baseURI string, authorizer autorest.Authorizer) reservations.Client {
+	c := reservations.NewClientWithBaseURI(baseURI)
+	c.Authorizer = authorizer
+	_ = c.AddToUserAgent(azure.UserAgent()) // intentionally ignore error as it doesn't matter
+	return c
+}
+
+// ListAll returns all the reservations the identity has access to.
+func (ac *azureClient) ListAll(ctx context.Context) ([]reservations.Response, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "reservations.azureClient.ListAll")
+	defer done()
+
+	iter, err := ac.reservations.ListAllComplete(ctx, "", "", "", nil, "", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed listing reservations")
+	}
+
+	var result []reservations.Response
+	for iter.NotDone() {
+		result = append(result, iter.Value())
+		if err := iter.NextWithContext(ctx); err != nil {
+			return nil, errors.Wrap(err, "failed to iterate reservations")
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservations
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/reservations/mgmt/2022-03-01/reservations"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "reservations"
+
+// CoverageSpec describes the instances whose reservation coverage is reported.
+type CoverageSpec struct {
+	Location  string
+	VMSize    string
+	Instances int32
+	// Spot is true when the instances are Spot VMs, which reservations don't apply to.
+	Spot bool
+}
+
+// Coverage compares a number of instances with the reserved VM instances of their size in their location.
+type Coverage struct {
+	VMSize            string
+	Instances         int32
+	ReservedInstances int32
+}
+
+// ReservationsScope defines the scope interface for a reservations service.
+type ReservationsScope interface {
+	azure.Authorizer
+	ReservationCoverageSpec() CoverageSpec
+	// SetReservationCoverage sets the reservation coverage of the instances, or clears it when coverage is nil.
+	SetReservationCoverage(coverage *Coverage)
+}
+
+// Cacher describes the ability to get and to add items to cache.
+type Cacher interface {
+	Get(key interface{}) (value interface{}, ok bool)
+	Add(key interface{}, value interface{}) bool
+}
+
+var (
+	reservationCacheOnce sync.Once
+	reservationCache     Cacher
+	reservationCacheErr  error
+)
+
+// getReservationCache returns the reservations cache shared by all the services, or an error if it couldn't be created.
+func getReservationCache() (Cacher, error) {
+	reservationCacheOnce.Do(func() {
+		reservationCache, reservationCacheErr = ttllru.New(128, time.Hour)
+	})
+	if reservationCacheErr != nil {
+		return nil, errors.Wrap(reservationCacheErr, "failed creating LRU cache for reservations")
+	}
+	return reservationCache, nil
+}
+
+// Service reports whether instances are covered by the reserved VM instances of their subscription according to the
+// Azure Reservations API.
+type Service struct {
+	Scope ReservationsScope
+	client
+	cache Cacher
+}
+
+// New creates a new service.
+func New(scope ReservationsScope) (*Service, error) {
+	cache, err := getReservationCache()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+		cache:  cache,
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile reports the reservation coverage of the instances. Failing to list the reservations doesn't fail the
+// reconciliation since the report is informational.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "reservations.Service.Reconcile")
+	defer done()
+
+	spec := s.Scope.ReservationCoverageSpec()
+	if !feature.Gates.Enabled(feature.ReservationCoverage) || spec.Spot {
+		s.Scope.SetReservationCoverage(nil)
+		return nil
+	}
+
+	all, err := s.list(ctx)
+	if err != nil {
+		log.V(2).Info("failed to list reservations", "err", err.Error())
+		return nil
+	}
+
+	s.Scope.SetReservationCoverage(&Coverage{
+		VMSize:            spec.VMSize,
+		Instances:         spec.Instances,
+		ReservedInstances: reservedInstances(all, s.Scope.SubscriptionID(), spec.Location, spec.VMSize),
+	})
+	return nil
+}
+
+// Delete is a no-op.
+func (s *Service) Delete(ctx context.Context) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "reservations.Service.Delete")
+	defer done()
+
+	return nil
+}
+
+// IsManaged always returns true.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+// list returns the reservations visible to the identity of the subscription, from the cache if they were already
+// fetched.
+func (s *Service) list(ctx context.Context) ([]reservations.Response, error) {
+	key := s.Scope.HashKey()
+	if all, ok := s.cache.Get(key); ok {
+		return all.([]reservations.Response), nil
+	}
+
+	all, err := s.client.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.cache.Add(key, all)
+	return all, nil
+}
+
+// reservedInstances returns the total quantity of the active VM reservations for the size in the location that
+// apply to the subscription. Reservations are only matched by their exact size, regardless of instance size
+// flexibility.
+func reservedInstances(all []reservations.Response, subscriptionID, location, vmSize string) int32 {
+	var quantity int32
+	for _, r := range all {
+		p := r.Properties
+		if p == nil || r.Sku == nil {
+			continue
+		}
+		if p.ReservedResourceType != reservations.ReservedResourceTypeVirtualMachines ||
+			p.ProvisioningState != reservations.ProvisioningStateSucceeded ||
+			ptr.Deref(p.Archived, false) {
+			continue
+		}
+		if !strings.EqualFold(ptr.Deref(r.Sku.Name, ""), vmSize) || !strings.EqualFold(ptr.Deref(r.Location, ""), location) {
+			continue
+		}
+		if !appliesToSubscription(p, subscriptionID) {
+			continue
+		}
+		quantity += ptr.Deref(p.Quantity, 0)
+	}
+	return quantity
+}
+
+// appliesToSubscription returns true if the reservation is shared or scoped to the subscription or to one of its
+// resource groups. Reservations scoped to management groups are ignored since the management group of the
+// subscription isn't known.
+func appliesToSubscription(p *reservations.Properties, subscriptionID string) bool {
+	switch p.AppliedScopeType {
+	case reservations.AppliedScopeTypeShared:
+		return true
+	case reservations.AppliedScopeTypeSingle:
+		prefix := "/subscriptions/" + subscriptionID
+		for _, scope := range ptr.Deref(p.AppliedScopes, nil) {
+			if strings.EqualFold(scope, prefix) || strings.HasPrefix(strings.ToLower(scope), strings.ToLower(prefix)+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservations
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/reservations/mgmt/2022-03-01/reservations"
+	. "github.com/onsi/gomega"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+)
+
+type fakeScope struct {
+	azure.Authorizer
+	spec     CoverageSpec
+	coverage *Coverage
+}
+
+func (f *fakeScope) SubscriptionID() string {
+	return "123"
+}
+
+func (f *fakeScope) HashKey() string {
+	return "hash"
+}
+
+func (f *fakeScope) ReservationCoverageSpec() CoverageSpec {
+	return f.spec
+}
+
+func (f *fakeScope) SetReservationCoverage(coverage *Coverage) {
+	f.coverage = coverage
+}
+
+type fakeClient struct {
+	reservations []reservations.Response
+	err          error
+}
+
+func (f *fakeClient) ListAll(context.Context) ([]reservations.Response, error) {
+	return f.reservations, f.err
+}
+
+func vmReservation(vmSize, location string, quantity int32, modify ...func(*reservations.Properties)) reservations.Response {
+	r := reservations.Response{
+		Location: ptr.To(location),
+		Sku:      &reservations.SkuName{Name: ptr.To(vmSize)},
+		Properties: &reservations.Properties{
+			ReservedResourceType: reservations.ReservedResourceTypeVirtualMachines,
+			ProvisioningState:    reservations.ProvisioningStateSucceeded,
+			AppliedScopeType:     reservations.AppliedScopeTypeShared,
+			Quantity:             ptr.To(quantity),
+		},
+	}
+	for _, m := range modify {
+		m(r.Properties)
+	}
+	return r
+}
+
+func TestReconcileReservations(t *testing.T) {
+	spec := CoverageSpec{Location: "eastus", VMSize: "Standard_D2s_v3", Instances: 5}
+
+	testcases := []struct {
+		name             string
+		featureDisabled  bool
+		spec             CoverageSpec
+		reservations     []reservations.Response
+		listErr          error
+		existingCoverage *Coverage
+		expectedCoverage *Coverage
+	}{
+		{
+			name: "shared and single scoped reservations are added up",
+			spec: spec,
+			reservations: []reservations.Response{
+				vmReservation("Standard_D2s_v3", "eastus", 2),
+				vmReservation("standard_d2s_v3", "eastus", 1, func(p *reservations.Properties) {
+					p.AppliedScopeType = reservations.AppliedScopeTypeSingle
+					p.AppliedScopes = &[]string{"/subscriptions/123"}
+				}),
+				vmReservation("Standard_D2s_v3", "eastus", 1, func(p *reservations.Properties) {
+					p.AppliedScopeType = reservations.AppliedScopeTypeSingle
+					p.AppliedScopes = &[]string{"/subscriptions/123/resourceGroups/my-rg"}
+				}),
+			},
+			expectedCoverage: &Coverage{VMSize: "Standard_D2s_v3", Instances: 5, ReservedInstances: 4},
+		},
+		{
+			name: "non matching reservations are ignored",
+			spec: spec,
+			reservations: []reservations.Response{
+				vmReservation("Standard_D4s_v3", "eastus", 2),
+				vmReservation("Standard_D2s_v3", "westus", 2),
+				vmReservation("Standard_D2s_v3", "eastus", 2, func(p *reservations.Properties) {
+					p.ProvisioningState = reservations.ProvisioningStateExpired
+				}),
+				vmReservation("Standard_D2s_v3", "eastus", 2, func(p *reservations.Properties) {
+					p.ReservedResourceType = reservations.ReservedResourceTypeDedicatedHost
+				}),
+				vmReservation("Standard_D2s_v3", "eastus", 2, func(p *reservations.Properties) {
+					p.AppliedScopeType = reservations.AppliedScopeTypeSingle
+					p.AppliedScopes = &[]string{"/subscriptions/1234"}
+				}),
+				vmReservation("Standard_D2s_v3", "eastus", 6),
+			},
+			expectedCoverage: &Coverage{VMSize: "Standard_D2s_v3", Instances: 5, ReservedInstances: 6},
+		},
+		{
+			name:             "failing to list reservations leaves the coverage as is",
+			spec:             spec,
+			listErr:          errors.New("forbidden"),
+			existingCoverage: &Coverage{VMSize: "Standard_D2s_v3", Instances: 5, ReservedInstances: 1},
+			expectedCoverage: &Coverage{VMSize: "Standard_D2s_v3", Instances: 5, ReservedInstances: 1},
+		},
+		{
+			name:             "Spot instances clear the coverage",
+			spec:             CoverageSpec{Location: "eastus", VMSize: "Standard_D2s_v3", Instances: 5, Spot: true},
+			reservations:     []reservations.Response{vmReservation("Standard_D2s_v3", "eastus", 2)},
+			existingCoverage: &Coverage{VMSize: "Standard_D2s_v3", Instances: 5, ReservedInstances: 1},
+		},
+		{
+			name:             "feature disabled clears the coverage",
+			featureDisabled:  true,
+			spec:             spec,
+			reservations:     []reservations.Response{vmReservation("Standard_D2s_v3", "eastus", 2)},
+			existingCoverage: &Coverage{VMSize: "Standard_D2s_v3", Instances: 5, ReservedInstances: 1},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cache, err := ttllru.New(10, time.Hour)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			scope := &fakeScope{spec: tc.spec, coverage: tc.existingCoverage}
+			s := &Service{
+				Scope:  scope,
+				client: &fakeClient{reservations: tc.reservations, err: tc.listErr},
+				cache:  cache,
+			}
+
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ReservationCoverage, !tc.featureDisabled)()
+
+			g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+			g.Expect(scope.coverage).To(Equal(tc.expectedCoverage))
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/component-base/featuregate"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
@@ -41,7 +42,8 @@ type ResourceHealthScope interface {
 
 // AvailabilityStatusFilterer transforms the condition derived from the
 // availability status to allow the condition to be overridden in specific
-// circumstances.
+// circumstances. Returning nil removes the condition, e.g. when the availability
+// status isn't meaningful yet.
 type AvailabilityStatusFilterer interface {
 	AvailabilityStatusFilter(cond *clusterv1.Condition) *clusterv1.Condition
 }
@@ -50,13 +52,15 @@ type AvailabilityStatusFilterer interface {
 type Service struct {
 	Scope ResourceHealthScope
 	client
+	featureGate featuregate.Feature
 }
 
-// New creates a new service.
-func New(scope ResourceHealthScope) *Service {
+// New creates a new service reporting the availability status when the given feature gate is enabled.
+func New(scope ResourceHealthScope, featureGate featuregate.Feature) *Service {
 	return &Service{
-		Scope:  scope,
-		client: newClient(scope),
+		Scope:       scope,
+		client:      newClient(scope),
+		featureGate: featureGate,
 	}
 }
 
@@ -70,7 +74,7 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "resourcehealth.Service.Reconcile")
 	defer done()
 
-	if !feature.Gates.Enabled(feature.AKSResourceHealth) {
+	if !feature.Gates.Enabled(s.featureGate) {
 		conditions.Delete(s.Scope.AvailabilityStatusResource(), infrav1.AzureResourceAvailableCondition)
 		return nil
 	}
@@ -87,6 +91,11 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		cond = filterer.AvailabilityStatusFilter(cond)
 	}
 
+	if cond == nil {
+		conditions.Delete(s.Scope.AvailabilityStatusResource(), infrav1.AzureResourceAvailableCondition)
+		return nil
+	}
+
 	conditions.Set(s.Scope.AvailabilityStatusResource(), cond)
 
 	if cond.Status == corev1.ConditionFalse {
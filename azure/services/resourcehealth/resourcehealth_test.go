@@ -93,6 +93,22 @@ func TestReconcileResourceHealth(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:          "filter removes the condition",
+			filterEnabled: true,
+			expect: func(s *mock_resourcehealth.MockResourceHealthScopeMockRecorder, m *mock_resourcehealth.MockclientMockRecorder, f *mock_resourcehealth.MockAvailabilityStatusFiltererMockRecorder) {
+				s.AvailabilityStatusResource().Times(1)
+				s.AvailabilityStatusResourceURI().Times(1)
+				m.GetByResource(gomockinternal.AContext(), gomock.Any()).Times(1).Return(resourcehealth.AvailabilityStatus{
+					Properties: &resourcehealth.AvailabilityStatusProperties{
+						AvailabilityState: resourcehealth.AvailabilityStateValuesUnavailable,
+						Summary:           ptr.To("summary"),
+					},
+				}, nil)
+				f.AvailabilityStatusFilter(gomock.Any()).Return(nil)
+			},
+			expectedError: "",
+		},
 		{
 			name:            "feature disabled",
 			featureDisabled: true,
@@ -115,8 +131,9 @@ func TestReconcileResourceHealth(t *testing.T) {
 			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), filtererMock.EXPECT())
 
 			s := &Service{
-				Scope:  scopeMock,
-				client: clientMock,
+				Scope:       scopeMock,
+				client:      clientMock,
+				featureGate: feature.AKSResourceHealth,
 			}
 			if tc.filterEnabled {
 				s.Scope = struct {
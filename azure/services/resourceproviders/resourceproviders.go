@@ -0,0 +1,169 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceproviders
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	serviceName = "resourceproviders"
+
+	registeredState  = "Registered"
+	registeringState = "Registering"
+
+	// registeringRequeueAfter is how long to wait before checking again on resource providers being registered.
+	registeringRequeueAfter = 30 * time.Second
+	// notRegisteredRequeueAfter is how long to wait before checking again on resource providers that couldn't be
+	// registered, giving some time to register them out of band.
+	notRegisteredRequeueAfter = 5 * time.Minute
+)
+
+// ResourceProvidersScope defines the scope interface for a resource providers service.
+type ResourceProvidersScope interface {
+	azure.Authorizer
+	RequiredResourceProviders() []string
+	ResourceProvidersObject() conditions.Setter
+}
+
+// Cacher describes the ability to get and to add items to cache.
+type Cacher interface {
+	Get(key interface{}) (value interface{}, ok bool)
+	Add(key interface{}, value interface{}) bool
+}
+
+var (
+	doOnce             sync.Once
+	registrationsCache Cacher
+)
+
+// Service checks that the subscription is registered with the resource providers required by the cluster, and
+// registers it with the missing ones.
+type Service struct {
+	Scope ResourceProvidersScope
+	client
+	cache Cacher
+}
+
+// New creates a new service.
+func New(scope ResourceProvidersScope) (*Service, error) {
+	var err error
+	doOnce.Do(func() {
+		registrationsCache, err = ttllru.New(128, 24*time.Hour)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating LRU cache for resource provider registrations")
+	}
+
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+		cache:  registrationsCache,
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile ensures the subscription is registered with the required resource providers. Registrations are cached
+// per subscription, so the resource providers are only checked the first time a subscription is reconciled.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "resourceproviders.Service.Reconcile")
+	defer done()
+
+	obj := s.Scope.ResourceProvidersObject()
+	if !feature.Gates.Enabled(feature.ResourceProviderRegistration) {
+		conditions.Delete(obj, infrav1.ResourceProvidersRegisteredCondition)
+		return nil
+	}
+
+	subscriptionID := s.Scope.SubscriptionID()
+	var registering, notRegistered []string
+	var registerErrs []error
+	for _, namespace := range s.Scope.RequiredResourceProviders() {
+		key := subscriptionID + "/" + strings.ToLower(namespace)
+		if _, ok := s.cache.Get(key); ok {
+			continue
+		}
+
+		provider, err := s.Get(ctx, namespace)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get resource provider %s", namespace)
+		}
+
+		switch ptr.Deref(provider.RegistrationState, "") {
+		case registeredState:
+			_ = s.cache.Add(key, true)
+		case registeringState:
+			registering = append(registering, namespace)
+		default:
+			log.V(2).Info("registering resource provider", "namespace", namespace, "subscriptionID", subscriptionID)
+			if _, err := s.Register(ctx, namespace); err != nil {
+				notRegistered = append(notRegistered, namespace)
+				registerErrs = append(registerErrs, errors.Wrapf(err, "failed to register resource provider %s", namespace))
+				continue
+			}
+			registering = append(registering, namespace)
+		}
+	}
+
+	if len(notRegistered) > 0 {
+		msg := fmt.Sprintf("subscription %s is not registered with resource providers %s, register it with `az provider register --namespace <namespace>`: %s",
+			subscriptionID, strings.Join(notRegistered, ", "), kerrors.NewAggregate(registerErrs).Error())
+		conditions.MarkFalse(obj, infrav1.ResourceProvidersRegisteredCondition, infrav1.ResourceProvidersNotRegisteredReason, clusterv1.ConditionSeverityError, "%s", msg)
+		return azure.WithTransientError(errors.New(msg), notRegisteredRequeueAfter)
+	}
+
+	if len(registering) > 0 {
+		msg := fmt.Sprintf("registering subscription %s with resource providers %s", subscriptionID, strings.Join(registering, ", "))
+		conditions.MarkFalse(obj, infrav1.ResourceProvidersRegisteredCondition, infrav1.ResourceProvidersRegisteringReason, clusterv1.ConditionSeverityInfo, "%s", msg)
+		return azure.WithTransientError(errors.New(msg), registeringRequeueAfter)
+	}
+
+	conditions.MarkTrue(obj, infrav1.ResourceProvidersRegisteredCondition)
+	return nil
+}
+
+// Delete is a no-op as resource provider registrations are left in place.
+func (s *Service) Delete(ctx context.Context) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "resourceproviders.Service.Delete")
+	defer done()
+
+	return nil
+}
+
+// IsManaged always returns true.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
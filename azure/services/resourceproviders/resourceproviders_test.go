@@ -0,0 +1,184 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceproviders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+type fakeScope struct {
+	azure.Authorizer
+	azureCluster *infrav1.AzureCluster
+}
+
+func (f *fakeScope) SubscriptionID() string {
+	return "123"
+}
+
+func (f *fakeScope) RequiredResourceProviders() []string {
+	return []string{"Microsoft.Compute", "Microsoft.Network"}
+}
+
+func (f *fakeScope) ResourceProvidersObject() conditions.Setter {
+	return f.azureCluster
+}
+
+type fakeClient struct {
+	states      map[string]string
+	registerErr error
+	registered  []string
+}
+
+func (f *fakeClient) Get(_ context.Context, namespace string) (resources.Provider, error) {
+	state, ok := f.states[namespace]
+	if !ok {
+		return resources.Provider{}, errors.New("not found")
+	}
+	return resources.Provider{Namespace: ptr.To(namespace), RegistrationState: ptr.To(state)}, nil
+}
+
+func (f *fakeClient) Register(_ context.Context, namespace string) (resources.Provider, error) {
+	if f.registerErr != nil {
+		return resources.Provider{}, f.registerErr
+	}
+	f.registered = append(f.registered, namespace)
+	return resources.Provider{Namespace: ptr.To(namespace), RegistrationState: ptr.To(registeringState)}, nil
+}
+
+func TestReconcileResourceProviders(t *testing.T) {
+	testcases := []struct {
+		name               string
+		featureDisabled    bool
+		states             map[string]string
+		registerErr        error
+		expectedRegistered []string
+		expectedStatus     corev1.ConditionStatus
+		expectedReason     string
+		expectedError      string
+	}{
+		{
+			name:           "all resource providers registered",
+			states:         map[string]string{"Microsoft.Compute": "Registered", "Microsoft.Network": "Registered"},
+			expectedStatus: corev1.ConditionTrue,
+		},
+		{
+			name:               "registers missing resource providers",
+			states:             map[string]string{"Microsoft.Compute": "Registered", "Microsoft.Network": "NotRegistered"},
+			expectedRegistered: []string{"Microsoft.Network"},
+			expectedStatus:     corev1.ConditionFalse,
+			expectedReason:     infrav1.ResourceProvidersRegisteringReason,
+			expectedError:      "registering subscription 123 with resource providers Microsoft.Network",
+		},
+		{
+			name:           "waits for resource providers being registered",
+			states:         map[string]string{"Microsoft.Compute": "Registering", "Microsoft.Network": "Registered"},
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: infrav1.ResourceProvidersRegisteringReason,
+			expectedError:  "registering subscription 123 with resource providers Microsoft.Compute",
+		},
+		{
+			name:           "reports resource providers that can't be registered",
+			states:         map[string]string{"Microsoft.Compute": "Unregistered", "Microsoft.Network": "Registered"},
+			registerErr:    errors.New("AuthorizationFailed"),
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: infrav1.ResourceProvidersNotRegisteredReason,
+			expectedError:  "subscription 123 is not registered with resource providers Microsoft.Compute, register it with `az provider register --namespace <namespace>`: failed to register resource provider Microsoft.Compute: AuthorizationFailed",
+		},
+		{
+			name:          "API error",
+			states:        map[string]string{},
+			expectedError: "failed to get resource provider Microsoft.Compute: not found",
+		},
+		{
+			name:            "feature disabled",
+			featureDisabled: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cache, err := ttllru.New(10, time.Hour)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			azureCluster := &infrav1.AzureCluster{}
+			client := &fakeClient{states: tc.states, registerErr: tc.registerErr}
+			s := &Service{
+				Scope:  &fakeScope{azureCluster: azureCluster},
+				client: client,
+				cache:  cache,
+			}
+
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ResourceProviderRegistration, !tc.featureDisabled)()
+
+			err = s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedError)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(client.registered).To(Equal(tc.expectedRegistered))
+
+			cond := conditions.Get(azureCluster, infrav1.ResourceProvidersRegisteredCondition)
+			if tc.expectedStatus == "" {
+				g.Expect(cond).To(BeNil())
+				return
+			}
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Status).To(Equal(tc.expectedStatus))
+			g.Expect(cond.Reason).To(Equal(tc.expectedReason))
+		})
+	}
+}
+
+func TestReconcileResourceProvidersCachesRegistrations(t *testing.T) {
+	g := NewWithT(t)
+
+	cache, err := ttllru.New(10, time.Hour)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	client := &fakeClient{states: map[string]string{"Microsoft.Compute": "Registered", "Microsoft.Network": "Registered"}}
+	s := &Service{
+		Scope:  &fakeScope{azureCluster: &infrav1.AzureCluster{}},
+		client: client,
+		cache:  cache,
+	}
+
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ResourceProviderRegistration, true)()
+
+	g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+
+	// The registrations of the subscription are cached, so the resource providers aren't checked again.
+	client.states = map[string]string{}
+	g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+}
@@ -17,11 +17,14 @@ limitations under the License.
 package resourceskus
 
 import (
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
 // SKU is a thin layer over the Azure resource SKU API to better introspect capabilities.
@@ -52,6 +55,12 @@ const (
 const (
 	// EphemeralOSDisk identifies the capability for ephemeral os support.
 	EphemeralOSDisk = "EphemeralOSDiskSupported"
+	// CachedDiskBytes identifies the capability for the size of the cache disk, which can hold an ephemeral os disk.
+	CachedDiskBytes = "CachedDiskBytes"
+	// MaxResourceVolumeMB identifies the capability for the size of the resource (temp) disk.
+	MaxResourceVolumeMB = "MaxResourceVolumeMB"
+	// SupportedEphemeralOSDiskPlacements identifies the capability listing the disks that can hold an ephemeral os disk.
+	SupportedEphemeralOSDiskPlacements = "SupportedEphemeralOSDiskPlacements"
 	// AcceleratedNetworking identifies the capability for accelerated networking support.
 	AcceleratedNetworking = "AcceleratedNetworkingEnabled"
 	// VCPUs identifies the capability for the number of vCPUS.
@@ -74,6 +83,12 @@ const (
 	ConfidentialComputingType = "ConfidentialComputingType"
 	// CPUArchitectureType identifies the capability for cpu architecture.
 	CPUArchitectureType = "CpuArchitectureType"
+	// PremiumIO identifies the capability for premium storage support.
+	PremiumIO = "PremiumIO"
+	// MaxNetworkInterfaces identifies the capability for the maximum number of network interfaces.
+	MaxNetworkInterfaces = "MaxNetworkInterfaces"
+	// GPUs identifies the capability for the number of GPUs.
+	GPUs = "GPUs"
 )
 
 // HasCapability return true for a capability which can be either
@@ -124,6 +139,43 @@ func (s SKU) HasCapabilityWithCapacity(name string, value int64) (bool, error) {
 	return false, nil
 }
 
+// SupportsEphemeralOSDiskPlacement returns true if the provided resource can place an ephemeral os disk on the given
+// disk, either "CacheDisk" or "ResourceDisk". When the resource doesn't list its supported placements, the disk is
+// considered supported if the resource has one.
+func (s SKU) SupportsEphemeralOSDiskPlacement(placement string) bool {
+	if placements, ok := s.GetCapability(SupportedEphemeralOSDiskPlacements); ok {
+		for _, supported := range strings.Split(placements, ",") {
+			if strings.EqualFold(strings.TrimSpace(supported), placement) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var diskCapability string
+	switch placement {
+	case string(compute.DiffDiskPlacementCacheDisk):
+		diskCapability = CachedDiskBytes
+	case string(compute.DiffDiskPlacementResourceDisk):
+		diskCapability = MaxResourceVolumeMB
+	default:
+		return false
+	}
+	hasDisk, err := s.HasCapabilityWithCapacity(diskCapability, 1)
+	return err == nil && hasDisk
+}
+
+// MinimumResources returns the minimum number of vCPUs and GB of memory a VM size must have, using MinimumVCPUS and
+// MinimumMemory for the thresholds that aren't overridden.
+func MinimumResources(overrides *infrav1.MinimumVMResources) (vCPUs, memoryGB int64) {
+	vCPUs, memoryGB = MinimumVCPUS, MinimumMemory
+	if overrides != nil {
+		vCPUs = ptr.Deref(overrides.VCPUs, vCPUs)
+		memoryGB = ptr.Deref(overrides.MemoryGiB, memoryGB)
+	}
+	return vCPUs, memoryGB
+}
+
 // GetCapability gets the value assigned to the given capability.
 // Eg. MaximumPlatformFaultDomainCount -> "3" will return "3" for the capability "MaximumPlatformFaultDomainCount".
 func (s SKU) GetCapability(name string) (string, bool) {
@@ -137,6 +189,35 @@ func (s SKU) GetCapability(name string) (string, bool) {
 	return "", false
 }
 
+// GetZonesWithLocationCapability returns the sorted zones of the given location in which the provided resource supports the location capability.
+func (s SKU) GetZonesWithLocationCapability(capabilityName, location string) []string {
+	if s.LocationInfo == nil {
+		return nil
+	}
+
+	var zones []string
+	for _, info := range *s.LocationInfo {
+		if info.Location == nil || *info.Location != location || info.ZoneDetails == nil {
+			continue
+		}
+
+		for _, zoneDetail := range *info.ZoneDetails {
+			if zoneDetail.Capabilities == nil || zoneDetail.Name == nil {
+				continue
+			}
+
+			for _, capability := range *zoneDetail.Capabilities {
+				if capability.Name != nil && *capability.Name == capabilityName {
+					zones = append(zones, *zoneDetail.Name...)
+					break
+				}
+			}
+		}
+	}
+	sort.Strings(zones)
+	return zones
+}
+
 // HasLocationCapability returns true if the provided resource supports the location capability.
 func (s SKU) HasLocationCapability(capabilityName, location, zone string) bool {
 	if s.LocationInfo == nil {
@@ -171,3 +252,38 @@ func (s SKU) HasLocationCapability(capabilityName, location, zone string) bool {
 	}
 	return false
 }
+
+// IsAvailableInZone returns true if the resource is offered in the zone of the given location
+// and isn't restricted there for the subscription.
+func (s SKU) IsAvailableInZone(location, zone string) bool {
+	if s.LocationInfo == nil {
+		return false
+	}
+
+	if s.Restrictions != nil {
+		for _, restriction := range *s.Restrictions {
+			if restriction.Type == compute.ResourceSkuRestrictionsTypeLocation {
+				return false
+			}
+			if restriction.RestrictionInfo != nil && restriction.RestrictionInfo.Zones != nil {
+				for _, restrictedZone := range *restriction.RestrictionInfo.Zones {
+					if restrictedZone == zone {
+						return false
+					}
+				}
+			}
+		}
+	}
+
+	for _, info := range *s.LocationInfo {
+		if info.Location == nil || !strings.EqualFold(*info.Location, location) || info.Zones == nil {
+			continue
+		}
+		for _, name := range *info.Zones {
+			if name == zone {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceskus
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestSKUGetZonesWithLocationCapability(t *testing.T) {
+	ultraSSDZone := func(zones ...string) compute.ResourceSkuZoneDetails {
+		return compute.ResourceSkuZoneDetails{
+			Name: &zones,
+			Capabilities: &[]compute.ResourceSkuCapabilities{
+				{
+					Name:  ptr.To(UltraSSDAvailable),
+					Value: ptr.To("True"),
+				},
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		have SKU
+		want []string
+	}{
+		"should find sorted zones across zone details": {
+			have: SKU{
+				LocationInfo: &[]compute.ResourceSkuLocationInfo{
+					{
+						Location:    ptr.To("baz"),
+						ZoneDetails: &[]compute.ResourceSkuZoneDetails{ultraSSDZone("3"), ultraSSDZone("2", "1")},
+					},
+				},
+			},
+			want: []string{"1", "2", "3"},
+		},
+		"should not find due to location mismatch": {
+			have: SKU{
+				LocationInfo: &[]compute.ResourceSkuLocationInfo{
+					{
+						Location:    ptr.To("foobar"),
+						ZoneDetails: &[]compute.ResourceSkuZoneDetails{ultraSSDZone("1")},
+					},
+				},
+			},
+			want: nil,
+		},
+		"should not find due to missing capability": {
+			have: SKU{
+				LocationInfo: &[]compute.ResourceSkuLocationInfo{
+					{
+						Location: ptr.To("baz"),
+						ZoneDetails: &[]compute.ResourceSkuZoneDetails{
+							{
+								Name: &[]string{"1"},
+								Capabilities: &[]compute.ResourceSkuCapabilities{
+									{
+										Name:  ptr.To("OtherCapability"),
+										Value: ptr.To("True"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: nil,
+		},
+		"should not find without location info": {
+			have: SKU{},
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			zones := tc.have.GetZonesWithLocationCapability(UltraSSDAvailable, "baz")
+			if diff := cmp.Diff(zones, tc.want, []cmp.Option{cmpopts.EquateEmpty()}...); diff != "" {
+				t.Fatalf(diff)
+			}
+		})
+	}
+}
+
+func TestSKUIsAvailableInZone(t *testing.T) {
+	locationInfo := &[]compute.ResourceSkuLocationInfo{
+		{
+			Location: ptr.To("baz"),
+			Zones:    &[]string{"1", "2"},
+		},
+	}
+
+	cases := map[string]struct {
+		have SKU
+		zone string
+		want bool
+	}{
+		"should be available in an offered zone": {
+			have: SKU{LocationInfo: locationInfo},
+			zone: "2",
+			want: true,
+		},
+		"should not be available in another zone": {
+			have: SKU{LocationInfo: locationInfo},
+			zone: "3",
+			want: false,
+		},
+		"should not be available in a restricted zone": {
+			have: SKU{
+				LocationInfo: locationInfo,
+				Restrictions: &[]compute.ResourceSkuRestrictions{
+					{
+						Type:            compute.ResourceSkuRestrictionsTypeZone,
+						RestrictionInfo: &compute.ResourceSkuRestrictionInfo{Zones: &[]string{"2"}},
+					},
+				},
+			},
+			zone: "2",
+			want: false,
+		},
+		"should not be available in a restricted location": {
+			have: SKU{
+				LocationInfo: locationInfo,
+				Restrictions: &[]compute.ResourceSkuRestrictions{
+					{
+						Type: compute.ResourceSkuRestrictionsTypeLocation,
+					},
+				},
+			},
+			zone: "1",
+			want: false,
+		},
+		"should not be available without location info": {
+			have: SKU{},
+			zone: "1",
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.have.IsAvailableInZone("baz", tc.zone); got != tc.want {
+				t.Fatalf("expected %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSKUSupportsEphemeralOSDiskPlacement(t *testing.T) {
+	cases := map[string]struct {
+		have      SKU
+		placement string
+		want      bool
+	}{
+		"should support a listed placement": {
+			have: SKU{Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: ptr.To(SupportedEphemeralOSDiskPlacements), Value: ptr.To("ResourceDisk,CacheDisk")},
+			}},
+			placement: "CacheDisk",
+			want:      true,
+		},
+		"should not support an unlisted placement": {
+			have: SKU{Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: ptr.To(SupportedEphemeralOSDiskPlacements), Value: ptr.To("ResourceDisk")},
+				{Name: ptr.To(CachedDiskBytes), Value: ptr.To("53687091200")},
+			}},
+			placement: "CacheDisk",
+			want:      false,
+		},
+		"should support the resource disk of a size without a cache disk": {
+			have: SKU{Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: ptr.To(CachedDiskBytes), Value: ptr.To("0")},
+				{Name: ptr.To(MaxResourceVolumeMB), Value: ptr.To("153600")},
+			}},
+			placement: "ResourceDisk",
+			want:      true,
+		},
+		"should not support the cache disk of a size without a cache disk": {
+			have: SKU{Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: ptr.To(CachedDiskBytes), Value: ptr.To("0")},
+				{Name: ptr.To(MaxResourceVolumeMB), Value: ptr.To("153600")},
+			}},
+			placement: "CacheDisk",
+			want:      false,
+		},
+		"should not support an unknown placement": {
+			have: SKU{Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: ptr.To(MaxResourceVolumeMB), Value: ptr.To("153600")},
+			}},
+			placement: "NvmeDisk",
+			want:      false,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.have.SupportsEphemeralOSDiskPlacement(tc.placement); got != tc.want {
+				t.Fatalf("expected %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestMinimumResources(t *testing.T) {
+	cases := map[string]struct {
+		overrides  *infrav1.MinimumVMResources
+		wantVCPUs  int64
+		wantMemory int64
+	}{
+		"should default without overrides": {
+			overrides:  nil,
+			wantVCPUs:  MinimumVCPUS,
+			wantMemory: MinimumMemory,
+		},
+		"should override the vCPUs only": {
+			overrides:  &infrav1.MinimumVMResources{VCPUs: ptr.To[int64](1)},
+			wantVCPUs:  1,
+			wantMemory: MinimumMemory,
+		},
+		"should override both thresholds": {
+			overrides:  &infrav1.MinimumVMResources{VCPUs: ptr.To[int64](4), MemoryGiB: ptr.To[int64](0)},
+			wantVCPUs:  4,
+			wantMemory: 0,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			vCPUs, memory := MinimumResources(tc.overrides)
+			if vCPUs != tc.wantVCPUs || memory != tc.wantMemory {
+				t.Fatalf("expected %d vCPUs and %dGB, got %d vCPUs and %dGB", tc.wantVCPUs, tc.wantMemory, vCPUs, memory)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retailprices
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// DefaultRetailPricesURL is the endpoint of the Azure Retail Prices API.
+const DefaultRetailPricesURL = "https://prices.azure.com/api/retail/prices"
+
+// Price is an item returned by the Azure Retail Prices API.
+type Price struct {
+	CurrencyCode  string  `json:"currencyCode"`
+	RetailPrice   float64 `json:"retailPrice"`
+	UnitOfMeasure string  `json:"unitOfMeasure"`
+	ArmRegionName string  `json:"armRegionName"`
+	ArmSkuName    string  `json:"armSkuName"`
+	MeterName     string  `json:"meterName"`
+	ProductName   string  `json:"productName"`
+	SkuName       string  `json:"skuName"`
+	ServiceName   string  `json:"serviceName"`
+	Type          string  `json:"type"`
+}
+
+// priceList is a page of prices returned by the Azure Retail Prices API.
+type priceList struct {
+	Items        []Price `json:"Items"`
+	NextPageLink string  `json:"NextPageLink"`
+}
+
+// client wraps the Azure Retail Prices API.
+type client interface {
+	List(context.Context, string) ([]Price, error)
+}
+
+// azureClient queries the Azure Retail Prices API, which doesn't require authentication.
+type azureClient struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+var _ client = &azureClient{}
+
+// newClient creates a new Retail Prices API client.
+func newClient() *azureClient {
+	return &azureClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		baseURL:    DefaultRetailPricesURL,
+	}
+}
+
+// List returns all the prices matching the OData filter, following the pages of the response.
+func (ac *azureClient) List(ctx context.Context, filter string) ([]Price, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "retailprices.azureClient.List")
+	defer done()
+
+	next := ac.baseURL + "?" + url.Values{"$filter": []string{filter}}.Encode()
+	var prices []Price
+	for next != "" {
+		page, err := ac.getPage(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, page.Items...)
+		next = page.NextPageLink
+	}
+
+	return prices, nil
+}
+
+func (ac *azureClient) getPage(ctx context.Context, pageURL string) (*priceList, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, http.NoBody)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create retail prices request")
+	}
+	req.Header.Set("User-Agent", azure.UserAgent())
+
+	resp, err := ac.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get retail prices")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to get retail prices: unexpected status %s", resp.Status)
+	}
+
+	var page priceList
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, errors.Wrap(err, "failed to decode retail prices")
+	}
+
+	return &page, nil
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retailprices
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	serviceName = "retailprices"
+
+	// hoursPerMonth is the number of hours Azure uses to convert monthly prices to hourly ones.
+	hoursPerMonth = 730
+)
+
+// CostSpec describes the resources whose cost is estimated.
+type CostSpec struct {
+	Location string
+	VMSize   string
+	OSType   string
+	Spot     bool
+	// OSDiskStorageAccountType and OSDiskSizeGB describe the managed OS disk. They are empty for ephemeral OS disks.
+	OSDiskStorageAccountType string
+	OSDiskSizeGB             int32
+}
+
+// RetailPricesScope defines the scope interface for a retail prices service.
+type RetailPricesScope interface {
+	EstimatedCostSpec() CostSpec
+	EstimatedCostObject() metav1.Object
+}
+
+// Cacher describes the ability to get and to add items to cache.
+type Cacher interface {
+	Get(key interface{}) (value interface{}, ok bool)
+	Add(key interface{}, value interface{}) bool
+}
+
+var (
+	priceCacheOnce sync.Once
+	priceCache     Cacher
+	priceCacheErr  error
+)
+
+// getPriceCache returns the retail prices cache shared by all the services, or an error if it couldn't be created.
+func getPriceCache() (Cacher, error) {
+	priceCacheOnce.Do(func() {
+		priceCache, priceCacheErr = ttllru.New(1024, 24*time.Hour)
+	})
+	if priceCacheErr != nil {
+		return nil, errors.Wrap(priceCacheErr, "failed creating LRU cache for retail prices")
+	}
+	return priceCache, nil
+}
+
+// Service annotates objects with the estimated hourly cost of their resources according to the Azure Retail Prices API.
+type Service struct {
+	Scope RetailPricesScope
+	client
+	cache Cacher
+}
+
+// New creates a new service.
+func New(scope RetailPricesScope) (*Service, error) {
+	cache, err := getPriceCache()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		Scope:  scope,
+		client: newClient(),
+		cache:  cache,
+	}, nil
+}
+
+// EstimateHourlyCost returns the estimated hourly cost in USD of the VM and of its managed OS disk, using the prices
+// cached by the services.
+func EstimateHourlyCost(ctx context.Context, spec CostSpec) (float64, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "retailprices.EstimateHourlyCost")
+	defer done()
+
+	cache, err := getPriceCache()
+	if err != nil {
+		return 0, err
+	}
+
+	s := &Service{
+		client: newClient(),
+		cache:  cache,
+	}
+	return s.estimateHourlyCost(ctx, spec)
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile annotates the object with the estimated hourly cost of its resources. Failing to estimate the cost
+// doesn't fail the reconciliation since the estimate is informational.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "retailprices.Service.Reconcile")
+	defer done()
+
+	obj := s.Scope.EstimatedCostObject()
+	if !feature.Gates.Enabled(feature.CostEstimation) {
+		removeAnnotation(obj)
+		return nil
+	}
+
+	spec := s.Scope.EstimatedCostSpec()
+	cost, err := s.estimateHourlyCost(ctx, spec)
+	if err != nil {
+		log.V(2).Info("failed to estimate the hourly cost", "vmSize", spec.VMSize, "location", spec.Location, "err", err.Error())
+		return nil
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[azure.EstimatedHourlyCostAnnotation] = fmt.Sprintf("%.4f", cost)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// Delete is a no-op.
+func (s *Service) Delete(ctx context.Context) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "retailprices.Service.Delete")
+	defer done()
+
+	return nil
+}
+
+// IsManaged always returns true.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+// estimateHourlyCost returns the estimated hourly cost in USD of the VM and of its managed OS disk.
+func (s *Service) estimateHourlyCost(ctx context.Context, spec CostSpec) (float64, error) {
+	vmPrices, err := s.list(ctx, fmt.Sprintf("serviceName eq 'Virtual Machines' and armRegionName eq '%s' and armSkuName eq '%s' and priceType eq 'Consumption'", spec.Location, spec.VMSize))
+	if err != nil {
+		return 0, err
+	}
+
+	vmPrice, ok := selectVMPrice(vmPrices, strings.EqualFold(spec.OSType, azure.WindowsOS), spec.Spot)
+	if !ok {
+		return 0, errors.Errorf("no price found for VM size %s in location %s", spec.VMSize, spec.Location)
+	}
+	cost := vmPrice.RetailPrice
+
+	meterName, ok := diskMeterName(spec.OSDiskStorageAccountType, spec.OSDiskSizeGB)
+	if !ok {
+		return cost, nil
+	}
+
+	diskPrices, err := s.list(ctx, fmt.Sprintf("serviceName eq 'Storage' and armRegionName eq '%s' and meterName eq '%s' and priceType eq 'Consumption'", spec.Location, meterName))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, price := range diskPrices {
+		if price.UnitOfMeasure == "1/Month" {
+			cost += price.RetailPrice / hoursPerMonth
+			break
+		}
+	}
+
+	return cost, nil
+}
+
+// list returns the prices matching the filter, from the cache if they were already fetched.
+func (s *Service) list(ctx context.Context, filter string) ([]Price, error) {
+	if prices, ok := s.cache.Get(filter); ok {
+		return prices.([]Price), nil
+	}
+
+	prices, err := s.client.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.cache.Add(filter, prices)
+	return prices, nil
+}
+
+// selectVMPrice selects the hourly pay-as-you-go price of the VM for the OS type and priority.
+func selectVMPrice(prices []Price, windows bool, spot bool) (Price, bool) {
+	for _, price := range prices {
+		if price.UnitOfMeasure != "1 Hour" || strings.HasSuffix(price.SkuName, " Low Priority") {
+			continue
+		}
+		if strings.Contains(price.ProductName, "Windows") != windows {
+			continue
+		}
+		if strings.HasSuffix(price.SkuName, " Spot") != spot {
+			continue
+		}
+		return price, true
+	}
+
+	return Price{}, false
+}
+
+// diskTiers are the managed disk tiers by maximum size in GB.
+var diskTiers = []struct {
+	maxSizeGB int32
+	tier      int
+}{
+	{4, 1}, {8, 2}, {16, 3}, {32, 4}, {64, 6}, {128, 10}, {256, 15}, {512, 20},
+	{1024, 30}, {2048, 40}, {4096, 50}, {8192, 60}, {16384, 70}, {32767, 80},
+}
+
+// diskMeterName returns the meter name of the managed disk tier matching the storage account type and size, such as
+// "P10 LRS Disk". Storage account types whose price doesn't depend only on the size, such as UltraSSD_LRS, aren't
+// supported.
+func diskMeterName(storageAccountType string, sizeGB int32) (string, bool) {
+	if sizeGB <= 0 {
+		return "", false
+	}
+
+	var prefix, redundancy string
+	switch storageAccountType {
+	case string(compute.StorageAccountTypesPremiumLRS):
+		prefix, redundancy = "P", "LRS"
+	case string(compute.StorageAccountTypesPremiumZRS):
+		prefix, redundancy = "P", "ZRS"
+	case string(compute.StorageAccountTypesStandardSSDLRS):
+		prefix, redundancy = "E", "LRS"
+	case string(compute.StorageAccountTypesStandardSSDZRS):
+		prefix, redundancy = "E", "ZRS"
+	case string(compute.StorageAccountTypesStandardLRS):
+		prefix, redundancy = "S", "LRS"
+	default:
+		return "", false
+	}
+
+	for _, t := range diskTiers {
+		if sizeGB > t.maxSizeGB {
+			continue
+		}
+		tier := t.tier
+		// Standard HDD disks start with the S4 tier.
+		if prefix == "S" && tier < 4 {
+			tier = 4
+		}
+		return fmt.Sprintf("%s%d %s Disk", prefix, tier, redundancy), true
+	}
+
+	return "", false
+}
+
+// removeAnnotation removes the estimated hourly cost annotation from the object.
+func removeAnnotation(obj metav1.Object) {
+	annotations := obj.GetAnnotations()
+	if _, ok := annotations[azure.EstimatedHourlyCostAnnotation]; ok {
+		delete(annotations, azure.EstimatedHourlyCostAnnotation)
+		obj.SetAnnotations(annotations)
+	}
+}
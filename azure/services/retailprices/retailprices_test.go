@@ -0,0 +1,208 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retailprices
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+)
+
+type fakeScope struct {
+	spec CostSpec
+	obj  metav1.Object
+}
+
+func (f *fakeScope) EstimatedCostSpec() CostSpec {
+	return f.spec
+}
+
+func (f *fakeScope) EstimatedCostObject() metav1.Object {
+	return f.obj
+}
+
+var testPrices = map[string][]Price{
+	"serviceName eq 'Virtual Machines' and armRegionName eq 'eastus' and armSkuName eq 'Standard_D2s_v3' and priceType eq 'Consumption'": {
+		{RetailPrice: 0.0096, UnitOfMeasure: "1 Hour", SkuName: "D2s v3 Low Priority", ProductName: "Virtual Machines DSv3 Series"},
+		{RetailPrice: 0.188, UnitOfMeasure: "1 Hour", SkuName: "D2s v3", ProductName: "Virtual Machines DSv3 Series Windows"},
+		{RetailPrice: 0.096, UnitOfMeasure: "1 Hour", SkuName: "D2s v3", ProductName: "Virtual Machines DSv3 Series"},
+		{RetailPrice: 0.0192, UnitOfMeasure: "1 Hour", SkuName: "D2s v3 Spot", ProductName: "Virtual Machines DSv3 Series"},
+	},
+	"serviceName eq 'Storage' and armRegionName eq 'eastus' and meterName eq 'P10 LRS Disk' and priceType eq 'Consumption'": {
+		{RetailPrice: 19.71, UnitOfMeasure: "1/Month", MeterName: "P10 LRS Disk"},
+	},
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prices, ok := testPrices[r.URL.Query().Get("$filter")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(priceList{Items: prices})
+	}))
+}
+
+func TestReconcileRetailPrices(t *testing.T) {
+	testcases := []struct {
+		name               string
+		featureDisabled    bool
+		spec               CostSpec
+		annotations        map[string]string
+		expectedAnnotation string
+	}{
+		{
+			name: "Linux VM with a managed OS disk",
+			spec: CostSpec{
+				Location:                 "eastus",
+				VMSize:                   "Standard_D2s_v3",
+				OSType:                   azure.LinuxOS,
+				OSDiskStorageAccountType: "Premium_LRS",
+				OSDiskSizeGB:             128,
+			},
+			expectedAnnotation: "0.1230",
+		},
+		{
+			name: "Windows VM with an ephemeral OS disk",
+			spec: CostSpec{
+				Location: "eastus",
+				VMSize:   "Standard_D2s_v3",
+				OSType:   azure.WindowsOS,
+			},
+			expectedAnnotation: "0.1880",
+		},
+		{
+			name: "Spot VM",
+			spec: CostSpec{
+				Location: "eastus",
+				VMSize:   "Standard_D2s_v3",
+				OSType:   azure.LinuxOS,
+				Spot:     true,
+			},
+			expectedAnnotation: "0.0192",
+		},
+		{
+			name: "unknown VM size leaves the annotation as is",
+			spec: CostSpec{
+				Location: "eastus",
+				VMSize:   "Standard_Unknown",
+				OSType:   azure.LinuxOS,
+			},
+			annotations:        map[string]string{azure.EstimatedHourlyCostAnnotation: "1.0000"},
+			expectedAnnotation: "1.0000",
+		},
+		{
+			name:            "feature disabled removes the annotation",
+			featureDisabled: true,
+			spec: CostSpec{
+				Location: "eastus",
+				VMSize:   "Standard_D2s_v3",
+				OSType:   azure.LinuxOS,
+			},
+			annotations: map[string]string{azure.EstimatedHourlyCostAnnotation: "1.0000"},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			server := newTestServer(t)
+			defer server.Close()
+
+			cache, err := ttllru.New(10, time.Hour)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			obj := &infrav1.AzureMachine{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			s := &Service{
+				Scope:  &fakeScope{spec: tc.spec, obj: obj},
+				client: &azureClient{httpClient: server.Client(), baseURL: server.URL},
+				cache:  cache,
+			}
+
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.CostEstimation, !tc.featureDisabled)()
+
+			g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+			if tc.expectedAnnotation == "" {
+				g.Expect(obj.Annotations).NotTo(HaveKey(azure.EstimatedHourlyCostAnnotation))
+			} else {
+				g.Expect(obj.Annotations).To(HaveKeyWithValue(azure.EstimatedHourlyCostAnnotation, tc.expectedAnnotation))
+			}
+		})
+	}
+}
+
+func TestAzureClientList(t *testing.T) {
+	g := NewWithT(t)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := priceList{Items: []Price{{MeterName: "second"}}}
+		if r.URL.Query().Get("page") == "" {
+			page = priceList{Items: []Price{{MeterName: "first"}}, NextPageLink: server.URL + "?page=2"}
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	ac := &azureClient{httpClient: server.Client(), baseURL: server.URL}
+	prices, err := ac.List(context.TODO(), "filter")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(prices).To(Equal([]Price{{MeterName: "first"}, {MeterName: "second"}}))
+}
+
+func TestDiskMeterName(t *testing.T) {
+	testcases := []struct {
+		storageAccountType string
+		sizeGB             int32
+		expected           string
+		expectedOK         bool
+	}{
+		{storageAccountType: "Premium_LRS", sizeGB: 128, expected: "P10 LRS Disk", expectedOK: true},
+		{storageAccountType: "Premium_ZRS", sizeGB: 30, expected: "P4 ZRS Disk", expectedOK: true},
+		{storageAccountType: "StandardSSD_LRS", sizeGB: 129, expected: "E15 LRS Disk", expectedOK: true},
+		{storageAccountType: "Standard_LRS", sizeGB: 4, expected: "S4 LRS Disk", expectedOK: true},
+		{storageAccountType: "Standard_LRS", sizeGB: 1024, expected: "S30 LRS Disk", expectedOK: true},
+		{storageAccountType: "UltraSSD_LRS", sizeGB: 128},
+		{storageAccountType: "Premium_LRS", sizeGB: 0},
+		{storageAccountType: "Premium_LRS", sizeGB: 40000},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.storageAccountType, func(t *testing.T) {
+			g := NewWithT(t)
+			meterName, ok := diskMeterName(tc.storageAccountType, tc.sizeGB)
+			g.Expect(ok).To(Equal(tc.expectedOK))
+			g.Expect(meterName).To(Equal(tc.expected))
+		})
+	}
+}
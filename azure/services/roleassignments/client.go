@@ -27,15 +27,15 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
-// azureClient contains the Azure go-sdk Client.
-type azureClient struct {
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
 	roleassignments authorization.RoleAssignmentsClient
 }
 
-// newClient creates a new role assignment client from subscription ID.
-func newClient(auth azure.Authorizer) *azureClient {
+// NewClient creates a new role assignment client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
 	c := newRoleAssignmentClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
-	return &azureClient{c}
+	return &AzureClient{c}
 }
 
 // newRoleAssignmentClient creates a role assignments client from subscription ID.
@@ -46,7 +46,7 @@ func newRoleAssignmentClient(subscriptionID string, baseURI string, authorizer a
 }
 
 // Get gets the specified role assignment by the role assignment name.
-func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (interface{}, error) {
+func (ac *AzureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (interface{}, error) {
 	ctx, span := tele.Tracer().Start(ctx, "roleassignments.AzureClient.Get")
 	defer span.End()
 	return ac.roleassignments.Get(ctx, spec.OwnerResourceName(), spec.ResourceName())
@@ -54,7 +54,7 @@ func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (
 
 // CreateOrUpdateAsync creates a roleassignment.
 // Creating a roleassignment is not a long running operation, so we don't ever return a future.
-func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (interface{}, azureautorest.FutureAPI, error) {
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (interface{}, azureautorest.FutureAPI, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "groups.AzureClient.CreateOrUpdate")
 	defer done()
 	createParams, ok := parameters.(authorization.RoleAssignmentCreateParameters)
@@ -66,7 +66,7 @@ func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.Resou
 }
 
 // IsDone returns true if the long-running operation has completed.
-func (ac *azureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (bool, error) {
+func (ac *AzureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (bool, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.AzureClient.IsDone")
 	defer done()
 
@@ -74,12 +74,17 @@ func (ac *azureClient) IsDone(ctx context.Context, future azureautorest.FutureAP
 }
 
 // Result fetches the result of a long-running operation future.
-func (ac *azureClient) Result(ctx context.Context, futureData azureautorest.FutureAPI, futureType string) (interface{}, error) {
+func (ac *AzureClient) Result(ctx context.Context, futureData azureautorest.FutureAPI, futureType string) (interface{}, error) {
 	// Result is a no-op for role assignment as only Delete operations return a future.
 	return nil, nil
 }
 
-// DeleteAsync is no-op for role assignments. It gets deleted as part of the VM deletion.
-func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (azureautorest.FutureAPI, error) {
-	return nil, nil
+// DeleteAsync deletes a role assignment. Deleting a role assignment is not a long running operation, so we don't ever
+// return a future. The role assignments of VMs are not deleted explicitly, as they get deleted as part of the VM deletion.
+func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (azureautorest.FutureAPI, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.AzureClient.DeleteAsync")
+	defer done()
+
+	_, err := ac.roleassignments.Delete(ctx, spec.OwnerResourceName(), spec.ResourceName())
+	return nil, err
 }
@@ -52,7 +52,7 @@ type Service struct {
 
 // New creates a new service.
 func New(scope RoleAssignmentScope) *Service {
-	client := newClient(scope)
+	client := NewClient(scope)
 	return &Service{
 		Scope:                        scope,
 		virtualMachinesGetter:        virtualmachines.NewClient(scope),
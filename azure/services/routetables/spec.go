@@ -18,6 +18,7 @@ package routetables
 
 import (
 	"context"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
 	"github.com/pkg/errors"
@@ -33,6 +34,14 @@ type RouteTableSpec struct {
 	Location       string
 	ClusterName    string
 	AdditionalTags infrav1.Tags
+	Routes         []RouteSpec
+}
+
+// RouteSpec defines a route of a route table to a virtual appliance.
+type RouteSpec struct {
+	Name             string
+	AddressPrefix    string
+	NextHopIPAddress string
 }
 
 // ResourceName returns the name of the route table.
@@ -53,16 +62,19 @@ func (s *RouteTableSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the route table.
 func (s *RouteTableSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
 	if existing != nil {
-		if _, ok := existing.(network.RouteTable); !ok {
+		existingRouteTable, ok := existing.(network.RouteTable)
+		if !ok {
 			return nil, errors.Errorf("%T is not a network.RouteTable", existing)
 		}
 		// route table already exists
-		// currently don't support specifying your own routes via spec.
-		return nil, nil
+		// only update it if one of the routes of the spec is missing or different.
+		return s.mergeRoutes(existingRouteTable), nil
 	}
 	return network.RouteTable{
-		Location:                   ptr.To(s.Location),
-		RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{},
+		Location: ptr.To(s.Location),
+		RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{
+			Routes: s.routes(),
+		},
 		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
 			ClusterName: s.ClusterName,
 			Lifecycle:   infrav1.ResourceLifecycleOwned,
@@ -71,3 +83,90 @@ func (s *RouteTableSpec) Parameters(ctx context.Context, existing interface{}) (
 		})),
 	}, nil
 }
+
+// WithSpecHash returns the route table parameters tagged with the given spec hash.
+func (s *RouteTableSpec) WithSpecHash(parameters interface{}, hash string) (interface{}, error) {
+	routeTable, ok := parameters.(network.RouteTable)
+	if !ok {
+		return nil, errors.Errorf("%T is not a network.RouteTable", parameters)
+	}
+	tags := infrav1.Tags{}
+	tags.Merge(converters.MapToTags(routeTable.Tags))
+	routeTable.Tags = converters.TagsToMap(tags.AddSpecVersionHashTag(hash))
+	return routeTable, nil
+}
+
+// routes returns the routes of the spec, or nil if it has none.
+func (s *RouteTableSpec) routes() *[]network.Route {
+	if len(s.Routes) == 0 {
+		return nil
+	}
+	routes := make([]network.Route, 0, len(s.Routes))
+	for _, route := range s.Routes {
+		routes = append(routes, route.route())
+	}
+	return &routes
+}
+
+// mergeRoutes returns the existing route table with the routes of the spec added or updated, or nil if it already
+// has all of them. Routes not in the spec, such as the pod routes added by the cloud provider, are preserved.
+func (s *RouteTableSpec) mergeRoutes(existing network.RouteTable) interface{} {
+	var existingRoutes []network.Route
+	if existing.RouteTablePropertiesFormat != nil && existing.Routes != nil {
+		existingRoutes = *existing.Routes
+	}
+
+	updated := false
+	for _, route := range s.Routes {
+		i := indexOfRoute(existingRoutes, route.Name)
+		if i < 0 {
+			existingRoutes = append(existingRoutes, route.route())
+			updated = true
+			continue
+		}
+		if !route.matches(existingRoutes[i]) {
+			existingRoutes[i] = route.route()
+			updated = true
+		}
+	}
+	if !updated {
+		return nil
+	}
+
+	if existing.RouteTablePropertiesFormat == nil {
+		existing.RouteTablePropertiesFormat = &network.RouteTablePropertiesFormat{}
+	}
+	existing.Routes = &existingRoutes
+	return existing
+}
+
+// indexOfRoute returns the index of the route with the given name, or -1 if there is none.
+func indexOfRoute(routes []network.Route, name string) int {
+	for i := range routes {
+		if strings.EqualFold(ptr.Deref(routes[i].Name, ""), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r RouteSpec) route() network.Route {
+	return network.Route{
+		Name: ptr.To(r.Name),
+		RoutePropertiesFormat: &network.RoutePropertiesFormat{
+			AddressPrefix:    ptr.To(r.AddressPrefix),
+			NextHopType:      network.RouteNextHopTypeVirtualAppliance,
+			NextHopIPAddress: ptr.To(r.NextHopIPAddress),
+		},
+	}
+}
+
+// matches returns true if an existing route sends the traffic of the same address prefix to the same next hop.
+func (r RouteSpec) matches(existing network.Route) bool {
+	if existing.RoutePropertiesFormat == nil {
+		return false
+	}
+	return ptr.Deref(existing.AddressPrefix, "") == r.AddressPrefix &&
+		existing.NextHopType == network.RouteNextHopTypeVirtualAppliance &&
+		ptr.Deref(existing.NextHopIPAddress, "") == r.NextHopIPAddress
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routetables
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+var (
+	fakeFirewallRoute = RouteSpec{
+		Name:             "default-via-firewall",
+		AddressPrefix:    "0.0.0.0/0",
+		NextHopIPAddress: "10.255.255.4",
+	}
+
+	fakeRouteTableSpecWithRoute = RouteTableSpec{
+		Name:          "my-cluster-node-routetable",
+		ResourceGroup: "my-rg",
+		Location:      "westus",
+		ClusterName:   "my-cluster",
+		Routes:        []RouteSpec{fakeFirewallRoute},
+	}
+
+	podRoute = network.Route{
+		Name: ptr.To("k8s-node-1____10244010024"),
+		RoutePropertiesFormat: &network.RoutePropertiesFormat{
+			AddressPrefix:    ptr.To("10.244.1.0/24"),
+			NextHopType:      network.RouteNextHopTypeVirtualAppliance,
+			NextHopIPAddress: ptr.To("10.1.0.4"),
+		},
+	}
+
+	firewallRoute = network.Route{
+		Name: ptr.To("default-via-firewall"),
+		RoutePropertiesFormat: &network.RoutePropertiesFormat{
+			AddressPrefix:    ptr.To("0.0.0.0/0"),
+			NextHopType:      network.RouteNextHopTypeVirtualAppliance,
+			NextHopIPAddress: ptr.To("10.255.255.4"),
+		},
+	}
+)
+
+func TestParameters(t *testing.T) {
+	testcases := []struct {
+		name     string
+		spec     RouteTableSpec
+		existing interface{}
+		expect   func(g *WithT, result interface{})
+	}{
+		{
+			name:     "new route table without routes",
+			spec:     RouteTableSpec{Name: "my-cluster-node-routetable", Location: "westus", ClusterName: "my-cluster"},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(network.RouteTable{}))
+				g.Expect(result.(network.RouteTable).Routes).To(BeNil())
+			},
+		},
+		{
+			name:     "new route table with a route to the firewall",
+			spec:     fakeRouteTableSpecWithRoute,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(network.RouteTable{}))
+				g.Expect(*result.(network.RouteTable).Routes).To(Equal([]network.Route{firewallRoute}))
+			},
+		},
+		{
+			name: "existing route table without routes in the spec",
+			spec: RouteTableSpec{Name: "my-cluster-node-routetable", Location: "westus", ClusterName: "my-cluster"},
+			existing: network.RouteTable{
+				RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{Routes: &[]network.Route{podRoute}},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "existing route table missing the route to the firewall keeps its other routes",
+			spec: fakeRouteTableSpecWithRoute,
+			existing: network.RouteTable{
+				ID:                         ptr.To("my-route-table-id"),
+				RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{Routes: &[]network.Route{podRoute}},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(network.RouteTable{}))
+				g.Expect(result.(network.RouteTable).ID).To(Equal(ptr.To("my-route-table-id")))
+				g.Expect(*result.(network.RouteTable).Routes).To(Equal([]network.Route{podRoute, firewallRoute}))
+			},
+		},
+		{
+			name: "existing route table with an outdated route to the firewall",
+			spec: fakeRouteTableSpecWithRoute,
+			existing: network.RouteTable{
+				RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{Routes: &[]network.Route{
+					{
+						Name: ptr.To("default-via-firewall"),
+						RoutePropertiesFormat: &network.RoutePropertiesFormat{
+							AddressPrefix:    ptr.To("0.0.0.0/0"),
+							NextHopType:      network.RouteNextHopTypeVirtualAppliance,
+							NextHopIPAddress: ptr.To("10.255.255.5"),
+						},
+					},
+				}},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(network.RouteTable{}))
+				g.Expect(*result.(network.RouteTable).Routes).To(Equal([]network.Route{firewallRoute}))
+			},
+		},
+		{
+			name: "existing route table with the route to the firewall",
+			spec: fakeRouteTableSpecWithRoute,
+			existing: network.RouteTable{
+				RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{Routes: &[]network.Route{podRoute, firewallRoute}},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.expect(g, result)
+		})
+	}
+}
+
+func TestWithSpecHash(t *testing.T) {
+	g := NewWithT(t)
+	spec := &RouteTableSpec{Name: "test-rt"}
+
+	tagged, err := spec.WithSpecHash(network.RouteTable{Tags: map[string]*string{"foo": ptr.To("bar")}}, "hash")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tagged).To(Equal(network.RouteTable{Tags: map[string]*string{"foo": ptr.To("bar"), infrav1.SpecVersionHashTagKey(): ptr.To("hash")}}))
+}
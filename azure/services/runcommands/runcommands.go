@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runcommands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const serviceName = "runcommands"
+
+// runCommandRequeueAfter is how long to wait before checking again on the scripts of run commands that are still running.
+const runCommandRequeueAfter = 30 * time.Second
+
+// maxErrorOutputLength is the maximum length of the error stream of a failed script reported in the condition.
+const maxErrorOutputLength = 512
+
+// RunCommandScope defines the scope interface for a run command service.
+type RunCommandScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	RunCommandSpecs() []azure.ResourceSpecGetter
+	RunCommandsStatusResource() conditions.Setter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope RunCommandScope
+	async.Reconciler
+}
+
+// New creates a new run command service.
+func New(scope RunCommandScope) *Service {
+	client := newClient(scope)
+	return &Service{
+		Scope:      scope,
+		Reconciler: async.New(scope, client, client),
+	}
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile idempotently creates or updates the run commands of a VM, and reports the result of their scripts in the
+// RunCommandsSucceeded condition.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "runcommands.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.RunCommandSpecs()
+	if len(specs) == 0 {
+		conditions.Delete(s.Scope.RunCommandsStatusResource(), infrav1.RunCommandsSucceededCondition)
+		return nil
+	}
+
+	// We go through the list of run commands to reconcile each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one.
+	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
+	var resultErr error
+	var running, failed []string
+	for _, spec := range specs {
+		result, err := s.CreateOrUpdateResource(ctx, spec, serviceName)
+		if err != nil {
+			if !azure.IsOperationNotDoneError(err) || resultErr == nil {
+				resultErr = err
+			}
+			continue
+		}
+
+		runCommand, _ := result.(compute.VirtualMachineRunCommand)
+		state, message := executionResult(runCommand)
+		log.V(4).Info("got run command execution state", "runCommand", spec.ResourceName(), "state", state)
+		switch state {
+		case compute.ExecutionStateSucceeded:
+		case compute.ExecutionStateFailed, compute.ExecutionStateTimedOut, compute.ExecutionStateCanceled:
+			failed = append(failed, fmt.Sprintf("run command %s %s: %s", spec.ResourceName(), strings.ToLower(string(state)), message))
+		default:
+			running = append(running, spec.ResourceName())
+		}
+	}
+
+	if resultErr != nil {
+		s.Scope.UpdatePutStatus(infrav1.RunCommandsSucceededCondition, serviceName, resultErr)
+		return resultErr
+	}
+
+	switch {
+	case len(failed) > 0:
+		// A failed script isn't retried until the run command is changed, so it doesn't fail the reconciliation.
+		conditions.MarkFalse(s.Scope.RunCommandsStatusResource(), infrav1.RunCommandsSucceededCondition, infrav1.RunCommandFailedReason, clusterv1.ConditionSeverityWarning, "%s", strings.Join(failed, "; "))
+	case len(running) > 0:
+		conditions.MarkFalse(s.Scope.RunCommandsStatusResource(), infrav1.RunCommandsSucceededCondition, infrav1.RunCommandInProgressReason, clusterv1.ConditionSeverityInfo, "run commands %s are still running", strings.Join(running, ", "))
+	default:
+		conditions.MarkTrue(s.Scope.RunCommandsStatusResource(), infrav1.RunCommandsSucceededCondition)
+	}
+
+	if len(running) > 0 {
+		return azure.WithTransientError(errors.Errorf("run commands %s are still running", strings.Join(running, ", ")), runCommandRequeueAfter)
+	}
+
+	return nil
+}
+
+// executionResult returns the execution state of the script of a run command, and a message describing why it failed.
+// The state is unknown when the run command doesn't have an instance view yet.
+func executionResult(runCommand compute.VirtualMachineRunCommand) (compute.ExecutionState, string) {
+	if runCommand.VirtualMachineRunCommandProperties == nil || runCommand.InstanceView == nil {
+		return compute.ExecutionStateUnknown, ""
+	}
+
+	instanceView := runCommand.InstanceView
+	state := instanceView.ExecutionState
+	if state == compute.ExecutionStateSucceeded && ptr.Deref(instanceView.ExitCode, 0) != 0 {
+		// Azure reports scripts that ran to completion as succeeded regardless of their exit code.
+		state = compute.ExecutionStateFailed
+	}
+	switch state {
+	case compute.ExecutionStateFailed, compute.ExecutionStateTimedOut, compute.ExecutionStateCanceled:
+	default:
+		return state, ""
+	}
+
+	message := ptr.Deref(instanceView.Error, "")
+	if message == "" {
+		message = ptr.Deref(instanceView.ExecutionMessage, "")
+	}
+	if len(message) > maxErrorOutputLength {
+		message = message[len(message)-maxErrorOutputLength:]
+	}
+	result := fmt.Sprintf("exit code %d", ptr.Deref(instanceView.ExitCode, 0))
+	if message = strings.TrimSpace(message); message != "" {
+		result += ", " + message
+	}
+	return state, result
+}
+
+// Delete is a no-op. Run commands will be deleted as part of VM deletion.
+func (s *Service) Delete(_ context.Context) error {
+	return nil
+}
+
+// IsManaged returns always returns true as CAPZ does not support BYO run commands.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runcommands
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/runcommands/mock_runcommands"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+var (
+	runCommandSpec1 = RunCommandSpec{
+		Name:          "rotate-certs",
+		VMName:        "my-vm",
+		ResourceGroup: "my-rg",
+		Location:      "test-location",
+		Script:        "kubeadm certs renew all",
+	}
+
+	runCommandSpec2 = RunCommandSpec{
+		Name:          "collect-logs",
+		VMName:        "my-vm",
+		ResourceGroup: "my-rg",
+		Location:      "test-location",
+		ScriptURI:     "https://mystorage.blob.core.windows.net/scripts/collect-logs.sh",
+	}
+
+	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+	notDoneError  = azure.NewOperationNotDoneError(&infrav1.Future{})
+)
+
+func runCommandWithInstanceView(state compute.ExecutionState, exitCode int32, stderr string) compute.VirtualMachineRunCommand {
+	return compute.VirtualMachineRunCommand{
+		VirtualMachineRunCommandProperties: &compute.VirtualMachineRunCommandProperties{
+			InstanceView: &compute.VirtualMachineRunCommandInstanceView{
+				ExecutionState: state,
+				ExitCode:       ptr.To(exitCode),
+				Error:          ptr.To(stderr),
+			},
+		},
+	}
+}
+
+func TestReconcileRunCommands(t *testing.T) {
+	testcases := []struct {
+		name            string
+		expectedError   string
+		expectedStatus  corev1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+		expect          func(s *mock_runcommands.MockRunCommandScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name: "no run commands",
+			expect: func(s *mock_runcommands.MockRunCommandScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.RunCommandSpecs().Return(nil)
+			},
+		},
+		{
+			name:           "run commands succeeded",
+			expectedStatus: corev1.ConditionTrue,
+			expect: func(s *mock_runcommands.MockRunCommandScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.RunCommandSpecs().Return([]azure.ResourceSpecGetter{&runCommandSpec1, &runCommandSpec2})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &runCommandSpec1, serviceName).Return(runCommandWithInstanceView(compute.ExecutionStateSucceeded, 0, ""), nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &runCommandSpec2, serviceName).Return(runCommandWithInstanceView(compute.ExecutionStateSucceeded, 0, ""), nil)
+			},
+		},
+		{
+			name:            "script exited with a non-zero exit code",
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  infrav1.RunCommandFailedReason,
+			expectedMessage: "run command rotate-certs failed: exit code 1, permission denied",
+			expect: func(s *mock_runcommands.MockRunCommandScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.RunCommandSpecs().Return([]azure.ResourceSpecGetter{&runCommandSpec1, &runCommandSpec2})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &runCommandSpec1, serviceName).Return(runCommandWithInstanceView(compute.ExecutionStateSucceeded, 1, "permission denied\n"), nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &runCommandSpec2, serviceName).Return(runCommandWithInstanceView(compute.ExecutionStateSucceeded, 0, ""), nil)
+			},
+		},
+		{
+			name:            "script timed out",
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  infrav1.RunCommandFailedReason,
+			expectedMessage: "run command rotate-certs timedout: exit code 0",
+			expect: func(s *mock_runcommands.MockRunCommandScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.RunCommandSpecs().Return([]azure.ResourceSpecGetter{&runCommandSpec1})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &runCommandSpec1, serviceName).Return(runCommandWithInstanceView(compute.ExecutionStateTimedOut, 0, ""), nil)
+			},
+		},
+		{
+			name:            "script is still running",
+			expectedError:   "run commands collect-logs are still running. Object will be requeued after 30s",
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  infrav1.RunCommandInProgressReason,
+			expectedMessage: "run commands collect-logs are still running",
+			expect: func(s *mock_runcommands.MockRunCommandScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.RunCommandSpecs().Return([]azure.ResourceSpecGetter{&runCommandSpec1, &runCommandSpec2})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &runCommandSpec1, serviceName).Return(runCommandWithInstanceView(compute.ExecutionStateSucceeded, 0, ""), nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &runCommandSpec2, serviceName).Return(runCommandWithInstanceView(compute.ExecutionStateRunning, 0, ""), nil)
+			},
+		},
+		{
+			name:           "run command without an instance view yet",
+			expectedError:  "run commands rotate-certs are still running. Object will be requeued after 30s",
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: infrav1.RunCommandInProgressReason,
+			expect: func(s *mock_runcommands.MockRunCommandScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.RunCommandSpecs().Return([]azure.ResourceSpecGetter{&runCommandSpec1})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &runCommandSpec1, serviceName).Return(compute.VirtualMachineRunCommand{}, nil)
+			},
+		},
+		{
+			name:          "error creating a run command",
+			expectedError: internalError.Error(),
+			expect: func(s *mock_runcommands.MockRunCommandScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.RunCommandSpecs().Return([]azure.ResourceSpecGetter{&runCommandSpec1, &runCommandSpec2})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &runCommandSpec1, serviceName).Return(nil, internalError)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &runCommandSpec2, serviceName).Return(nil, notDoneError)
+				s.UpdatePutStatus(infrav1.RunCommandsSucceededCondition, serviceName, internalError)
+			},
+		},
+		{
+			name:          "run command is still being created",
+			expectedError: notDoneError.Error(),
+			expect: func(s *mock_runcommands.MockRunCommandScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.RunCommandSpecs().Return([]azure.ResourceSpecGetter{&runCommandSpec1})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &runCommandSpec1, serviceName).Return(nil, notDoneError)
+				s.UpdatePutStatus(infrav1.RunCommandsSucceededCondition, serviceName, notDoneError)
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_runcommands.NewMockRunCommandScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			azureMachine := &infrav1.AzureMachine{}
+			scopeMock.EXPECT().RunCommandsStatusResource().Return(azureMachine).AnyTimes()
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+
+			cond := conditions.Get(azureMachine, infrav1.RunCommandsSucceededCondition)
+			if tc.expectedStatus == "" {
+				g.Expect(cond).To(BeNil())
+				return
+			}
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Status).To(Equal(tc.expectedStatus))
+			g.Expect(cond.Reason).To(Equal(tc.expectedReason))
+			if tc.expectedMessage != "" {
+				g.Expect(cond.Message).To(Equal(tc.expectedMessage))
+			}
+		})
+	}
+}
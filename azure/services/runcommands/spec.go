@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runcommands
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// RunCommandSpec defines the specification for a run command of a virtual machine.
+type RunCommandSpec struct {
+	Name           string
+	VMName         string
+	ResourceGroup  string
+	Location       string
+	Script         string
+	ScriptURI      string
+	CommandID      string
+	Parameters     []infrav1.RunCommandParameter
+	TimeoutSeconds *int32
+}
+
+// ResourceName returns the name of the run command.
+func (s *RunCommandSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *RunCommandSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName returns the name of the VM that owns this run command.
+func (s *RunCommandSpec) OwnerResourceName() string {
+	return s.VMName
+}
+
+// Parameters returns the parameters for the run command.
+func (s *RunCommandSpec) Parameters(ctx context.Context, existing interface{}) (interface{}, error) {
+	runCommand := compute.VirtualMachineRunCommand{
+		VirtualMachineRunCommandProperties: &compute.VirtualMachineRunCommandProperties{
+			Source:           s.source(),
+			Parameters:       s.parameters(),
+			TimeoutInSeconds: s.TimeoutSeconds,
+			AsyncExecution:   ptr.To(true),
+		},
+		Location: ptr.To(s.Location),
+	}
+
+	if existing != nil {
+		existingRunCommand, ok := existing.(compute.VirtualMachineRunCommand)
+		if !ok {
+			return nil, errors.Errorf("%T is not a compute.VirtualMachineRunCommand", existing)
+		}
+
+		// Every PUT of a run command runs its script again, so only send one when the run command has changed.
+		if s.isUpToDate(existingRunCommand) {
+			return nil, nil
+		}
+	}
+
+	return runCommand, nil
+}
+
+// isUpToDate returns true if the existing run command runs the same script with the same parameters as the spec.
+func (s *RunCommandSpec) isUpToDate(existing compute.VirtualMachineRunCommand) bool {
+	properties := existing.VirtualMachineRunCommandProperties
+	if properties == nil || properties.Source == nil {
+		return false
+	}
+
+	if ptr.Deref(properties.Source.Script, "") != s.Script ||
+		ptr.Deref(properties.Source.ScriptURI, "") != s.ScriptURI ||
+		ptr.Deref(properties.Source.CommandID, "") != s.CommandID {
+		return false
+	}
+
+	// Azure applies a default timeout when none is set, so only compare it when the spec sets one.
+	if s.TimeoutSeconds != nil && ptr.Deref(properties.TimeoutInSeconds, 0) != *s.TimeoutSeconds {
+		return false
+	}
+
+	return cmp.Equal(ptr.Deref(properties.Parameters, nil), ptr.Deref(s.parameters(), nil))
+}
+
+func (s *RunCommandSpec) source() *compute.VirtualMachineRunCommandScriptSource {
+	source := &compute.VirtualMachineRunCommandScriptSource{}
+	if s.Script != "" {
+		source.Script = ptr.To(s.Script)
+	}
+	if s.ScriptURI != "" {
+		source.ScriptURI = ptr.To(s.ScriptURI)
+	}
+	if s.CommandID != "" {
+		source.CommandID = ptr.To(s.CommandID)
+	}
+	return source
+}
+
+func (s *RunCommandSpec) parameters() *[]compute.RunCommandInputParameter {
+	if len(s.Parameters) == 0 {
+		return nil
+	}
+
+	parameters := make([]compute.RunCommandInputParameter, 0, len(s.Parameters))
+	for _, parameter := range s.Parameters {
+		parameters = append(parameters, compute.RunCommandInputParameter{
+			Name:  ptr.To(parameter.Name),
+			Value: ptr.To(parameter.Value),
+		})
+	}
+	return &parameters
+}
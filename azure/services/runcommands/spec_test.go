@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runcommands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+var (
+	fakeRunCommandSpec = RunCommandSpec{
+		Name:           "rotate-certs",
+		VMName:         "my-vm",
+		ResourceGroup:  "my-rg",
+		Location:       "my-location",
+		Script:         "kubeadm certs renew all",
+		Parameters:     []infrav1.RunCommandParameter{{Name: "my-param", Value: "my-value"}},
+		TimeoutSeconds: ptr.To[int32](600),
+	}
+
+	fakeRunCommandParams = compute.VirtualMachineRunCommand{
+		VirtualMachineRunCommandProperties: &compute.VirtualMachineRunCommandProperties{
+			Source: &compute.VirtualMachineRunCommandScriptSource{
+				Script: ptr.To("kubeadm certs renew all"),
+			},
+			Parameters:       &[]compute.RunCommandInputParameter{{Name: ptr.To("my-param"), Value: ptr.To("my-value")}},
+			TimeoutInSeconds: ptr.To[int32](600),
+			AsyncExecution:   ptr.To(true),
+		},
+		Location: ptr.To("my-location"),
+	}
+)
+
+func TestParameters(t *testing.T) {
+	testcases := []struct {
+		name          string
+		spec          *RunCommandSpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name:     "get parameters for run command",
+			spec:     &fakeRunCommandSpec,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(fakeRunCommandParams))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "run command that already ran",
+			spec:     &fakeRunCommandSpec,
+			existing: fakeRunCommandParams,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "",
+		},
+		{
+			name: "run command without a timeout that already ran with the default timeout",
+			spec: &RunCommandSpec{
+				Name:      "collect-logs",
+				Location:  "my-location",
+				CommandID: "RunShellScript",
+			},
+			existing: compute.VirtualMachineRunCommand{
+				VirtualMachineRunCommandProperties: &compute.VirtualMachineRunCommandProperties{
+					Source:           &compute.VirtualMachineRunCommandScriptSource{CommandID: ptr.To("RunShellScript")},
+					TimeoutInSeconds: ptr.To[int32](5400),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "",
+		},
+		{
+			name: "run command with a changed script runs again",
+			spec: &fakeRunCommandSpec,
+			existing: compute.VirtualMachineRunCommand{
+				VirtualMachineRunCommandProperties: &compute.VirtualMachineRunCommandProperties{
+					Source:           &compute.VirtualMachineRunCommandScriptSource{Script: ptr.To("kubeadm certs check-expiration")},
+					Parameters:       &[]compute.RunCommandInputParameter{{Name: ptr.To("my-param"), Value: ptr.To("my-value")}},
+					TimeoutInSeconds: ptr.To[int32](600),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(fakeRunCommandParams))
+			},
+			expectedError: "",
+		},
+		{
+			name: "run command with changed parameters runs again",
+			spec: &fakeRunCommandSpec,
+			existing: compute.VirtualMachineRunCommand{
+				VirtualMachineRunCommandProperties: &compute.VirtualMachineRunCommandProperties{
+					Source:           &compute.VirtualMachineRunCommandScriptSource{Script: ptr.To("kubeadm certs renew all")},
+					Parameters:       &[]compute.RunCommandInputParameter{{Name: ptr.To("my-param"), Value: ptr.To("other-value")}},
+					TimeoutInSeconds: ptr.To[int32](600),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(fakeRunCommandParams))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "existing is not a run command",
+			spec:     &fakeRunCommandSpec,
+			existing: "not a run command",
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "string is not a compute.VirtualMachineRunCommand",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			tc.expect(g, result)
+		})
+	}
+}
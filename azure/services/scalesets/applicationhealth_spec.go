@@ -0,0 +1,93 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scalesets
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// ApplicationHealthExtensionSpec defines the specification for the Application Health extension of a scale set, which
+// reports the health of the instances to Azure for automatic instance repairs and rolling upgrades.
+type ApplicationHealthExtensionSpec struct {
+	VMSSName      string
+	ResourceGroup string
+	OSType        string
+	Protocol      string
+	Port          int32
+	RequestPath   string
+	GracePeriod   *time.Duration
+}
+
+// ResourceName returns the name of the Application Health extension, which depends on the OS of the instances.
+func (s *ApplicationHealthExtensionSpec) ResourceName() string {
+	if s.OSType == azure.WindowsOS {
+		return azure.ApplicationHealthExtensionWindows
+	}
+	return azure.ApplicationHealthExtensionLinux
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *ApplicationHealthExtensionSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName returns the name of the VMSS that owns the Application Health extension.
+func (s *ApplicationHealthExtensionSpec) OwnerResourceName() string {
+	return s.VMSSName
+}
+
+// Parameters returns the parameters for the Application Health extension.
+func (s *ApplicationHealthExtensionSpec) Parameters(ctx context.Context, existing interface{}) (interface{}, error) {
+	if existing != nil {
+		if _, ok := existing.(compute.VirtualMachineScaleSetExtension); !ok {
+			return nil, errors.Errorf("%T is not a compute.VirtualMachineScaleSetExtension", existing)
+		}
+
+		// VMSS extension already exists, nothing to update.
+		return nil, nil
+	}
+
+	// The port has to be a number in the settings of the extension, so they can't be an azure.ExtensionSpec.
+	settings := map[string]interface{}{
+		"protocol": s.Protocol,
+		"port":     s.Port,
+	}
+	if s.RequestPath != "" {
+		settings["requestPath"] = s.RequestPath
+	}
+	// The grace period is only supported by version 2.0 of the extension.
+	if s.GracePeriod != nil {
+		settings["gracePeriod"] = int64(s.GracePeriod.Seconds())
+	}
+
+	return compute.VirtualMachineScaleSetExtension{
+		Name: ptr.To(s.ResourceName()),
+		VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+			Publisher:               ptr.To("Microsoft.ManagedServices"),
+			Type:                    ptr.To(s.ResourceName()),
+			TypeHandlerVersion:      ptr.To("2.0"),
+			AutoUpgradeMinorVersion: ptr.To(true),
+			Settings:                settings,
+		},
+	}, nil
+}
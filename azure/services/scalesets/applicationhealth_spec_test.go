@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scalesets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+func TestApplicationHealthExtensionParameters(t *testing.T) {
+	testcases := []struct {
+		name     string
+		spec     *ApplicationHealthExtensionSpec
+		existing interface{}
+		expected interface{}
+	}{
+		{
+			name: "linux http health probe",
+			spec: &ApplicationHealthExtensionSpec{VMSSName: "my-vmss", ResourceGroup: "my-rg", OSType: azure.LinuxOS, Protocol: "http", Port: 10248, RequestPath: "/healthz"},
+			expected: compute.VirtualMachineScaleSetExtension{
+				Name: ptr.To("ApplicationHealthLinux"),
+				VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+					Publisher:               ptr.To("Microsoft.ManagedServices"),
+					Type:                    ptr.To("ApplicationHealthLinux"),
+					TypeHandlerVersion:      ptr.To("2.0"),
+					AutoUpgradeMinorVersion: ptr.To(true),
+					Settings:                map[string]interface{}{"protocol": "http", "port": int32(10248), "requestPath": "/healthz"},
+				},
+			},
+		},
+		{
+			name: "windows tcp health probe",
+			spec: &ApplicationHealthExtensionSpec{VMSSName: "my-vmss", ResourceGroup: "my-rg", OSType: azure.WindowsOS, Protocol: "tcp", Port: 10250},
+			expected: compute.VirtualMachineScaleSetExtension{
+				Name: ptr.To("ApplicationHealthWindows"),
+				VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+					Publisher:               ptr.To("Microsoft.ManagedServices"),
+					Type:                    ptr.To("ApplicationHealthWindows"),
+					TypeHandlerVersion:      ptr.To("2.0"),
+					AutoUpgradeMinorVersion: ptr.To(true),
+					Settings:                map[string]interface{}{"protocol": "tcp", "port": int32(10250)},
+				},
+			},
+		},
+		{
+			name: "health probe with a grace period",
+			spec: &ApplicationHealthExtensionSpec{VMSSName: "my-vmss", ResourceGroup: "my-rg", OSType: azure.LinuxOS, Protocol: "https", Port: 443, RequestPath: "/", GracePeriod: ptr.To(15 * time.Minute)},
+			expected: compute.VirtualMachineScaleSetExtension{
+				Name: ptr.To("ApplicationHealthLinux"),
+				VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+					Publisher:               ptr.To("Microsoft.ManagedServices"),
+					Type:                    ptr.To("ApplicationHealthLinux"),
+					TypeHandlerVersion:      ptr.To("2.0"),
+					AutoUpgradeMinorVersion: ptr.To(true),
+					Settings:                map[string]interface{}{"protocol": "https", "port": int32(443), "requestPath": "/", "gracePeriod": int64(900)},
+				},
+			},
+		},
+		{
+			name:     "extension that already exists",
+			spec:     &ApplicationHealthExtensionSpec{VMSSName: "my-vmss", ResourceGroup: "my-rg", OSType: azure.LinuxOS, Protocol: "http", Port: 10248},
+			existing: compute.VirtualMachineScaleSetExtension{Name: ptr.To("ApplicationHealthLinux")},
+			expected: nil,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.expected == nil {
+				g.Expect(result).To(BeNil())
+			} else {
+				g.Expect(result).To(Equal(tc.expected))
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	computev20220801 "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2022-08-01/compute"
 	"github.com/Azure/go-autorest/autorest"
 	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/pkg/errors"
@@ -49,6 +50,8 @@ type Client interface {
 type AzureClient struct {
 	scalesetvms compute.VirtualMachineScaleSetVMsClient
 	scalesets   compute.VirtualMachineScaleSetsClient
+	// priorityMixScalesets creates the scale sets with a priority mix policy, which requires API version 2022-08-01.
+	priorityMixScalesets computev20220801.VirtualMachineScaleSetsClient
 }
 
 var _ Client = &AzureClient{}
@@ -58,6 +61,8 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 	return &AzureClient{
 		scalesetvms: newVirtualMachineScaleSetVMsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
 		scalesets:   newVirtualMachineScaleSetsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+
+		priorityMixScalesets: newPriorityMixVirtualMachineScaleSetsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
 	}
 }
 
@@ -75,6 +80,13 @@ func newVirtualMachineScaleSetsClient(subscriptionID string, baseURI string, aut
 	return c
 }
 
+// newPriorityMixVirtualMachineScaleSetsClient creates a new vmss client supporting priority mix policies from subscription ID.
+func newPriorityMixVirtualMachineScaleSetsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) computev20220801.VirtualMachineScaleSetsClient {
+	c := computev20220801.NewVirtualMachineScaleSetsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	return c
+}
+
 // ListInstances retrieves information about the model views of a virtual machine scale set.
 func (ac *AzureClient) ListInstances(ctx context.Context, resourceGroupName string, resourceName string) ([]compute.VirtualMachineScaleSetVM, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.ListInstances")
@@ -137,6 +149,10 @@ func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.Resou
 		return nil, nil, errors.Errorf("%T is not a compute.VirtualMachineScaleSet", parameters)
 	}
 
+	if scaleSetSpec, ok := spec.(*ScaleSetSpec); ok && scaleSetSpec.PriorityMixPolicy != nil {
+		return ac.createOrUpdateWithPriorityMixPolicy(ctx, spec, scaleset, scaleSetSpec.PriorityMixPolicy)
+	}
+
 	createFuture, err := ac.scalesets.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), scaleset)
 	if err != nil {
 		return nil, nil, err
@@ -157,6 +173,52 @@ func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.Resou
 	return result, nil, err
 }
 
+// createOrUpdateWithPriorityMixPolicy creates or updates a virtual machine scale set with a priority mix policy
+// asynchronously. The scale set is converted to API version 2022-08-01, which is the first one supporting priority mix
+// policies, and the result is fetched again with the API version used for all the other operations.
+func (ac *AzureClient) createOrUpdateWithPriorityMixPolicy(ctx context.Context, spec azure.ResourceSpecGetter, scaleset compute.VirtualMachineScaleSet, policy *PriorityMixPolicy) (result interface{}, future azureautorest.FutureAPI, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.createOrUpdateWithPriorityMixPolicy")
+	defer done()
+
+	data, err := json.Marshal(scaleset)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal scale set")
+	}
+	var priorityMixScaleset computev20220801.VirtualMachineScaleSet
+	if err := json.Unmarshal(data, &priorityMixScaleset); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unmarshal scale set")
+	}
+	if priorityMixScaleset.VirtualMachineScaleSetProperties == nil {
+		priorityMixScaleset.VirtualMachineScaleSetProperties = &computev20220801.VirtualMachineScaleSetProperties{}
+	}
+	priorityMixScaleset.PriorityMixPolicy = &computev20220801.PriorityMixPolicy{
+		BaseRegularPriorityCount:           policy.BaseRegularPriorityCount,
+		RegularPriorityPercentageAboveBase: policy.RegularPriorityPercentageAboveBase,
+	}
+
+	createFuture, err := ac.priorityMixScalesets.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), priorityMixScaleset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	err = createFuture.WaitForCompletionRef(ctx, ac.priorityMixScalesets.Client)
+	if err != nil {
+		// if an error occurs, return the future.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, &createFuture, err
+	}
+
+	if _, err := createFuture.Result(ac.priorityMixScalesets); err != nil {
+		return nil, nil, err
+	}
+	// if the operation completed, return a nil future
+	result, err = ac.scalesets.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), "")
+	return result, nil, err
+}
+
 // UpdateInstances update instances of a VM scale set.
 func (ac *AzureClient) UpdateInstances(ctx context.Context, resourceGroupName, vmssName string, instanceIDs []string) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.UpdateInstances")
@@ -0,0 +1,202 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scalesets
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// PinnedVMSpec defines the specification for a VM of a Flexible orchestration mode scale set that is created
+// individually in a zone and a fault domain.
+type PinnedVMSpec struct {
+	Name                string
+	ResourceGroup       string
+	ScaleSetName        string
+	Zone                string
+	PlatformFaultDomain *int32
+	// ScaleSet is the model of the scale set the VM is created from. Its ID has to be set.
+	ScaleSet compute.VirtualMachineScaleSet
+}
+
+// ResourceName returns the name of the VM.
+func (s *PinnedVMSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the VM.
+func (s *PinnedVMSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for pinned VMs.
+func (s *PinnedVMSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the VM.
+func (s *PinnedVMSpec) Parameters(_ context.Context, existing interface{}) (interface{}, error) {
+	if existing != nil {
+		if _, ok := existing.(compute.VirtualMachine); !ok {
+			return nil, errors.Errorf("%T is not a compute.VirtualMachine", existing)
+		}
+		// Like the other instances of a Flexible scale set, pinned VMs are replaced rather than updated.
+		return nil, nil
+	}
+
+	if s.ScaleSet.VirtualMachineScaleSetProperties == nil || s.ScaleSet.VirtualMachineProfile == nil {
+		return nil, errors.Errorf("scale set %s has no VM profile", s.ScaleSetName)
+	}
+
+	// The VM profile of a scale set and the properties of a VM share their JSON representation, apart from the
+	// per-VM properties set below.
+	var properties compute.VirtualMachineProperties
+	if err := convertJSON(s.ScaleSet.VirtualMachineProfile, &properties); err != nil {
+		return nil, errors.Wrapf(err, "failed to convert the VM profile of scale set %s", s.ScaleSetName)
+	}
+
+	if properties.HardwareProfile == nil {
+		properties.HardwareProfile = &compute.HardwareProfile{}
+	}
+	if s.ScaleSet.Sku != nil {
+		properties.HardwareProfile.VMSize = compute.VirtualMachineSizeTypes(ptr.Deref(s.ScaleSet.Sku.Name, ""))
+	}
+	if properties.OsProfile != nil {
+		properties.OsProfile.ComputerName = ptr.To(s.Name)
+	}
+	if storageProfile := properties.StorageProfile; storageProfile != nil {
+		if storageProfile.OsDisk != nil {
+			storageProfile.OsDisk.Name = ptr.To(azure.GenerateOSDiskName(s.Name))
+		}
+		if storageProfile.DataDisks != nil {
+			for i, disk := range *storageProfile.DataDisks {
+				suffix := strings.TrimPrefix(ptr.Deref(disk.Name, ""), s.ScaleSetName+"_")
+				(*storageProfile.DataDisks)[i].Name = ptr.To(azure.GenerateDataDiskName(s.Name, suffix))
+			}
+		}
+	}
+	if networkProfile := properties.NetworkProfile; networkProfile != nil && networkProfile.NetworkInterfaceConfigurations != nil {
+		// Network interfaces and public IP addresses are named after their configuration, so they need to be
+		// unique to the VM.
+		for i, nic := range *networkProfile.NetworkInterfaceConfigurations {
+			(*networkProfile.NetworkInterfaceConfigurations)[i].Name = ptr.To(s.vmScopedName(ptr.Deref(nic.Name, "")))
+			if nic.VirtualMachineNetworkInterfaceConfigurationProperties == nil || nic.IPConfigurations == nil {
+				continue
+			}
+			for _, ipConfig := range *nic.IPConfigurations {
+				if ipConfig.VirtualMachineNetworkInterfaceIPConfigurationProperties != nil && ipConfig.PublicIPAddressConfiguration != nil {
+					ipConfig.PublicIPAddressConfiguration.Name = ptr.To(s.vmScopedName(ptr.Deref(ipConfig.PublicIPAddressConfiguration.Name, "")))
+				}
+			}
+		}
+	}
+	properties.VirtualMachineScaleSet = &compute.SubResource{ID: s.ScaleSet.ID}
+	properties.PlatformFaultDomain = s.PlatformFaultDomain
+
+	vm := compute.VirtualMachine{
+		Location:                 s.ScaleSet.Location,
+		Plan:                     s.ScaleSet.Plan,
+		Tags:                     s.ScaleSet.Tags,
+		VirtualMachineProperties: &properties,
+	}
+	if s.Zone != "" {
+		vm.Zones = &[]string{s.Zone}
+	}
+	if s.ScaleSet.Identity != nil {
+		vm.Identity = &compute.VirtualMachineIdentity{}
+		if err := convertJSON(s.ScaleSet.Identity, vm.Identity); err != nil {
+			return nil, errors.Wrapf(err, "failed to convert the identity of scale set %s", s.ScaleSetName)
+		}
+	}
+
+	return vm, nil
+}
+
+// PinnedVMExtensionSpec defines the specification for an extension of the scale set installed on a pinned VM, which
+// doesn't get the extensions of the scale set model.
+type PinnedVMExtensionSpec struct {
+	VMName   string
+	Location string
+	// Extension is the spec of the extension of the scale set.
+	Extension azure.ResourceSpecGetter
+}
+
+// ResourceName returns the name of the extension.
+func (s *PinnedVMExtensionSpec) ResourceName() string {
+	return s.Extension.ResourceName()
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *PinnedVMExtensionSpec) ResourceGroupName() string {
+	return s.Extension.ResourceGroupName()
+}
+
+// OwnerResourceName returns the name of the pinned VM that owns the extension.
+func (s *PinnedVMExtensionSpec) OwnerResourceName() string {
+	return s.VMName
+}
+
+// Parameters returns the parameters for the extension.
+func (s *PinnedVMExtensionSpec) Parameters(ctx context.Context, existing interface{}) (interface{}, error) {
+	if existing != nil {
+		if _, ok := existing.(compute.VirtualMachineExtension); !ok {
+			return nil, errors.Errorf("%T is not a compute.VirtualMachineExtension", existing)
+		}
+		// VM extension already exists, nothing to update.
+		return nil, nil
+	}
+
+	params, err := s.Extension.Parameters(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	vmssExtension, ok := params.(compute.VirtualMachineScaleSetExtension)
+	if !ok {
+		return nil, errors.Errorf("%T is not a compute.VirtualMachineScaleSetExtension", params)
+	}
+
+	// Scale set and VM extensions share their properties, apart from the ones only meaningful in a scale set model.
+	extension := compute.VirtualMachineExtension{
+		Location:                          ptr.To(s.Location),
+		VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{},
+	}
+	if err := convertJSON(vmssExtension.VirtualMachineScaleSetExtensionProperties, extension.VirtualMachineExtensionProperties); err != nil {
+		return nil, errors.Wrapf(err, "failed to convert extension %s", s.ResourceName())
+	}
+	return extension, nil
+}
+
+// vmScopedName replaces the scale set name prefix of the name of a resource created for each instance with the name
+// of the VM.
+func (s *PinnedVMSpec) vmScopedName(name string) string {
+	return s.Name + strings.TrimPrefix(name, s.ScaleSetName)
+}
+
+// convertJSON converts between SDK types sharing their JSON representation.
+func convertJSON(from, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
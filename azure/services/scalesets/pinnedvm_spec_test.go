@@ -0,0 +1,221 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scalesets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+var fakePinnedVMScaleSet = compute.VirtualMachineScaleSet{
+	ID:       ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/my-vmss"),
+	Location: ptr.To("westus"),
+	Sku:      &compute.Sku{Name: ptr.To("Standard_D2s_v3"), Capacity: ptr.To[int64](2)},
+	Tags:     map[string]*string{"foo": ptr.To("bar")},
+	Identity: &compute.VirtualMachineScaleSetIdentity{Type: compute.ResourceIdentityTypeSystemAssigned},
+	VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
+		VirtualMachineProfile: &compute.VirtualMachineScaleSetVMProfile{
+			OsProfile: &compute.VirtualMachineScaleSetOSProfile{
+				ComputerNamePrefix: ptr.To("my-vmss"),
+				AdminUsername:      ptr.To("azureuser"),
+				CustomData:         ptr.To("Y3VzdG9tIGRhdGE="),
+			},
+			StorageProfile: &compute.VirtualMachineScaleSetStorageProfile{
+				OsDisk: &compute.VirtualMachineScaleSetOSDisk{CreateOption: compute.DiskCreateOptionTypesFromImage},
+				DataDisks: &[]compute.VirtualMachineScaleSetDataDisk{
+					{Name: ptr.To("my-vmss_etcddisk"), Lun: ptr.To[int32](0), CreateOption: compute.DiskCreateOptionTypesEmpty},
+				},
+			},
+			NetworkProfile: &compute.VirtualMachineScaleSetNetworkProfile{
+				NetworkInterfaceConfigurations: &[]compute.VirtualMachineScaleSetNetworkConfiguration{
+					{
+						Name: ptr.To("my-vmss-nic-0"),
+						VirtualMachineScaleSetNetworkConfigurationProperties: &compute.VirtualMachineScaleSetNetworkConfigurationProperties{
+							Primary: ptr.To(true),
+							IPConfigurations: &[]compute.VirtualMachineScaleSetIPConfiguration{
+								{
+									Name: ptr.To("ipConfig0"),
+									VirtualMachineScaleSetIPConfigurationProperties: &compute.VirtualMachineScaleSetIPConfigurationProperties{
+										Primary: ptr.To(true),
+										PublicIPAddressConfiguration: &compute.VirtualMachineScaleSetPublicIPAddressConfiguration{
+											Name: ptr.To("my-vmss-nic-0-pip"),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				NetworkAPIVersion: compute.NetworkAPIVersionTwoZeroTwoZeroHyphenMinusOneOneHyphenMinusZeroOne,
+			},
+		},
+	},
+}
+
+func TestPinnedVMParameters(t *testing.T) {
+	testcases := []struct {
+		name          string
+		spec          *PinnedVMSpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name: "pinned VM with a zone and a fault domain",
+			spec: &PinnedVMSpec{
+				Name:                "my-vmss-a",
+				ResourceGroup:       "my-rg",
+				ScaleSetName:        "my-vmss",
+				Zone:                "2",
+				PlatformFaultDomain: ptr.To[int32](1),
+				ScaleSet:            fakePinnedVMScaleSet,
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				vm := result.(compute.VirtualMachine)
+				g.Expect(vm.Location).To(Equal(ptr.To("westus")))
+				g.Expect(vm.Zones).To(Equal(&[]string{"2"}))
+				g.Expect(vm.Tags).To(Equal(map[string]*string{"foo": ptr.To("bar")}))
+				g.Expect(vm.Identity.Type).To(Equal(compute.ResourceIdentityTypeSystemAssigned))
+				g.Expect(vm.PlatformFaultDomain).To(Equal(ptr.To[int32](1)))
+				g.Expect(vm.VirtualMachineScaleSet.ID).To(Equal(fakePinnedVMScaleSet.ID))
+				g.Expect(vm.HardwareProfile.VMSize).To(Equal(compute.VirtualMachineSizeTypes("Standard_D2s_v3")))
+				g.Expect(vm.OsProfile.ComputerName).To(Equal(ptr.To("my-vmss-a")))
+				g.Expect(vm.OsProfile.AdminUsername).To(Equal(ptr.To("azureuser")))
+				g.Expect(vm.OsProfile.CustomData).To(Equal(ptr.To("Y3VzdG9tIGRhdGE=")))
+				g.Expect(vm.StorageProfile.OsDisk.Name).To(Equal(ptr.To("my-vmss-a_OSDisk")))
+				g.Expect((*vm.StorageProfile.DataDisks)[0].Name).To(Equal(ptr.To("my-vmss-a_etcddisk")))
+				nic := (*vm.NetworkProfile.NetworkInterfaceConfigurations)[0]
+				g.Expect(nic.Name).To(Equal(ptr.To("my-vmss-a-nic-0")))
+				g.Expect((*nic.IPConfigurations)[0].PublicIPAddressConfiguration.Name).To(Equal(ptr.To("my-vmss-a-nic-0-pip")))
+				g.Expect(vm.NetworkProfile.NetworkAPIVersion).To(Equal(compute.NetworkAPIVersionTwoZeroTwoZeroHyphenMinusOneOneHyphenMinusZeroOne))
+			},
+		},
+		{
+			name: "pinned VM without a zone",
+			spec: &PinnedVMSpec{
+				Name:          "my-vmss-a",
+				ResourceGroup: "my-rg",
+				ScaleSetName:  "my-vmss",
+				ScaleSet:      fakePinnedVMScaleSet,
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				vm := result.(compute.VirtualMachine)
+				g.Expect(vm.Zones).To(BeNil())
+				g.Expect(vm.PlatformFaultDomain).To(BeNil())
+			},
+		},
+		{
+			name: "pinned VM that already exists",
+			spec: &PinnedVMSpec{
+				Name:          "my-vmss-a",
+				ResourceGroup: "my-rg",
+				ScaleSetName:  "my-vmss",
+				ScaleSet:      fakePinnedVMScaleSet,
+			},
+			existing: compute.VirtualMachine{Name: ptr.To("my-vmss-a")},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "scale set without a VM profile",
+			spec: &PinnedVMSpec{
+				Name:          "my-vmss-a",
+				ResourceGroup: "my-rg",
+				ScaleSetName:  "my-vmss",
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "scale set my-vmss has no VM profile",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			tc.expect(g, result)
+		})
+	}
+}
+
+func TestPinnedVMExtensionParameters(t *testing.T) {
+	testcases := []struct {
+		name     string
+		spec     *PinnedVMExtensionSpec
+		existing interface{}
+		expect   func(g *WithT, result interface{})
+	}{
+		{
+			name: "extension of the scale set on a pinned VM",
+			spec: &PinnedVMExtensionSpec{
+				VMName:    "my-vmss-a",
+				Location:  "westus",
+				Extension: &fakeVMSSExtensionSpec,
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachineExtension{}))
+				extension := result.(compute.VirtualMachineExtension)
+				g.Expect(extension.Location).To(Equal(ptr.To("westus")))
+				g.Expect(extension.Publisher).To(Equal(ptr.To("my-publisher")))
+				g.Expect(extension.Type).To(Equal(ptr.To("my-vm-extension")))
+				g.Expect(extension.TypeHandlerVersion).To(Equal(ptr.To("1.0")))
+				g.Expect(extension.Settings).To(Equal(map[string]interface{}{"my-setting": "my-value"}))
+				g.Expect(extension.ProtectedSettings).To(Equal(map[string]interface{}{"my-protected-setting": "my-protected-value"}))
+			},
+		},
+		{
+			name: "extension that already exists",
+			spec: &PinnedVMExtensionSpec{
+				VMName:    "my-vmss-a",
+				Location:  "westus",
+				Extension: &fakeVMSSExtensionSpec,
+			},
+			existing: compute.VirtualMachineExtension{Name: ptr.To("my-vm-extension")},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(tc.spec.OwnerResourceName()).To(Equal("my-vmss-a"))
+			tc.expect(g, result)
+		})
+	}
+}
@@ -28,6 +28,9 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensionimages"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensions"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/slice"
@@ -54,18 +57,26 @@ type (
 		Scope ScaleSetScope
 		Client
 		resourceSKUCache *resourceskus.Cache
+		extensionImages  *vmextensionimages.Cache
 		async.Reconciler
+		pinnedVMReconciler          async.Reconciler
+		pinnedVMExtensionReconciler async.Reconciler
 	}
 )
 
 // New creates a new service.
-func New(scope ScaleSetScope, skuCache *resourceskus.Cache) *Service {
+func New(scope ScaleSetScope, skuCache *resourceskus.Cache, extensionImages *vmextensionimages.Cache) *Service {
 	client := NewClient(scope)
+	vmClient := virtualmachines.NewClient(scope)
+	vmExtensionClient := vmextensions.NewClient(scope)
 	return &Service{
-		Reconciler:       async.New(scope, client, client),
-		Client:           client,
-		Scope:            scope,
-		resourceSKUCache: skuCache,
+		Reconciler:                  async.New(scope, client, client),
+		Client:                      client,
+		Scope:                       scope,
+		resourceSKUCache:            skuCache,
+		extensionImages:             extensionImages,
+		pinnedVMReconciler:          async.New(scope, vmClient, vmClient),
+		pinnedVMExtensionReconciler: async.New(scope, vmExtensionClient, vmExtensionClient),
 	}
 }
 
@@ -127,11 +138,43 @@ func (s *Service) Reconcile(ctx context.Context) (retErr error) {
 		}
 		s.Scope.SetProviderID(providerID)
 		s.Scope.SetVMSSState(&fetchedVMSS)
+
+		if err := s.reconcilePinnedInstances(ctx, scaleSetSpec, fetchedVMSS.ID); err != nil {
+			return err
+		}
 	}
 
 	return err
 }
 
+// reconcilePinnedInstances creates the VMs of the pinned instances of a Flexible scale set in their zone and fault
+// domain, along with the extensions of the scale set, which VMs created outside of the scale set model don't get.
+func (s *Service) reconcilePinnedInstances(ctx context.Context, scaleSetSpec *ScaleSetSpec, scaleSetID string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.reconcilePinnedInstances")
+	defer done()
+
+	vmSpecs, err := scaleSetSpec.PinnedVMSpecs(ctx, scaleSetID)
+	if err != nil {
+		return err
+	}
+	for _, vmSpec := range vmSpecs {
+		if _, err := s.pinnedVMReconciler.CreateOrUpdateResource(ctx, vmSpec, serviceName); err != nil {
+			return errors.Wrapf(err, "failed to create pinned VM %s", vmSpec.ResourceName())
+		}
+		for _, extensionSpec := range scaleSetSpec.VMSSExtensionSpecs {
+			spec := &PinnedVMExtensionSpec{
+				VMName:    vmSpec.ResourceName(),
+				Location:  scaleSetSpec.Location,
+				Extension: extensionSpec,
+			}
+			if _, err := s.pinnedVMExtensionReconciler.CreateOrUpdateResource(ctx, spec, serviceName); err != nil {
+				return errors.Wrapf(err, "failed to create extension %s of pinned VM %s", spec.ResourceName(), vmSpec.ResourceName())
+			}
+		}
+	}
+	return nil
+}
+
 // Delete deletes a scale set asynchronously. Delete sends a DELETE request to Azure and if accepted without error,
 // the VMSS will be considered deleted. The actual delete in Azure may take longer, but should eventually complete.
 func (s *Service) Delete(ctx context.Context) error {
@@ -176,30 +219,49 @@ func (s *Service) validateSpec(ctx context.Context) error {
 		return errors.Wrapf(err, "failed to get SKU %s in compute api", scaleSetSpec.Size)
 	}
 
-	// Checking if the requested VM size has at least 2 vCPUS
-	vCPUCapability, err := sku.HasCapabilityWithCapacity(resourceskus.VCPUs, resourceskus.MinimumVCPUS)
+	minVCPUs, minMemory := resourceskus.MinimumResources(scaleSetSpec.MinimumVMResources)
+
+	// Checking if the requested VM size has at least the minimum vCPUS
+	vCPUCapability, err := sku.HasCapabilityWithCapacity(resourceskus.VCPUs, minVCPUs)
 	if err != nil {
 		return azure.WithTerminalError(errors.Wrap(err, "failed to validate the vCPU capability"))
 	}
 
 	if !vCPUCapability {
-		return azure.WithTerminalError(errors.New("vm size should be bigger or equal to at least 2 vCPUs"))
+		return azure.WithTerminalError(errors.Errorf("vm size should be bigger or equal to at least %d vCPUs", minVCPUs))
 	}
 
-	// Checking if the requested VM size has at least 2 Gi of memory
-	MemoryCapability, err := sku.HasCapabilityWithCapacity(resourceskus.MemoryGB, resourceskus.MinimumMemory)
-	if err != nil {
-		return azure.WithTerminalError(errors.Wrap(err, "failed to validate the memory capability"))
-	}
+	// Checking if the requested VM size has at least the minimum memory, VM sizes with less than 1 Gi of memory
+	// can only be used when the check is disabled.
+	if minMemory > 0 {
+		MemoryCapability, err := sku.HasCapabilityWithCapacity(resourceskus.MemoryGB, minMemory)
+		if err != nil {
+			return azure.WithTerminalError(errors.Wrap(err, "failed to validate the memory capability"))
+		}
 
-	if !MemoryCapability {
-		return azure.WithTerminalError(errors.New("vm memory should be bigger or equal to at least 2Gi"))
+		if !MemoryCapability {
+			return azure.WithTerminalError(errors.Errorf("vm memory should be bigger or equal to at least %dGi", minMemory))
+		}
 	}
 
 	// enable ephemeral OS
 	if scaleSetSpec.OSDisk.DiffDiskSettings != nil && !sku.HasCapability(resourceskus.EphemeralOSDisk) {
 		return azure.WithTerminalError(fmt.Errorf("vm size %s does not support ephemeral os. select a different vm size or disable ephemeral os", scaleSetSpec.Size))
 	}
+	if diffDiskSettings := scaleSetSpec.OSDisk.DiffDiskSettings; diffDiskSettings != nil && diffDiskSettings.Placement != "" &&
+		!sku.SupportsEphemeralOSDiskPlacement(string(diffDiskSettings.Placement)) {
+		return azure.WithTerminalError(fmt.Errorf("vm size %s does not support placing the ephemeral os disk on the %s. select a different vm size or ephemeral os disk placement", scaleSetSpec.Size, diffDiskSettings.Placement))
+	}
+
+	for _, spec := range scaleSetSpec.VMSSExtensionSpecs {
+		extensionSpec, ok := spec.(*VMSSExtensionSpec)
+		if !ok || !extensionSpec.Custom {
+			continue
+		}
+		if err := s.extensionImages.Validate(ctx, extensionSpec.Publisher, extensionSpec.Name, extensionSpec.Version); err != nil {
+			return errors.Wrapf(err, "invalid VM extension %s", extensionSpec.Name)
+		}
+	}
 
 	if scaleSetSpec.SecurityProfile != nil && !sku.HasCapability(resourceskus.EncryptionAtHost) {
 		return azure.WithTerminalError(errors.Errorf("encryption at host is not supported for VM type %s", scaleSetSpec.Size))
@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets/mock_scalesets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensionimages"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -206,6 +207,26 @@ func TestReconcileVMSS(t *testing.T) {
 				s.ScaleSetSpec(gomockinternal.AContext()).Return(&spec).AnyTimes()
 			},
 		},
+		{
+			name:          "validate spec failure: custom VM extension version is not offered",
+			expectedError: "invalid VM extension CustomScript: reconcile error that cannot be recovered occurred: version 3.0 of VM extension type CustomScript of publisher Microsoft.Azure.Extensions is not offered in location test-location, offered versions are 2.1. Object will not be requeued",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.VMSSExtensionSpecs = []azure.ResourceSpecGetter{
+					&VMSSExtensionSpec{
+						ExtensionSpec: azure.ExtensionSpec{
+							Name:      "CustomScript",
+							VMName:    "my-vmss",
+							Publisher: "Microsoft.Azure.Extensions",
+							Version:   "3.0",
+							Custom:    true,
+						},
+						ResourceGroup: "my-rg",
+					},
+				}
+				s.ScaleSetSpec(gomockinternal.AContext()).Return(&spec).AnyTimes()
+			},
+		},
 		{
 			name:          "validate spec failure: failed to get SKU",
 			expectedError: "failed to get SKU INVALID_VM_SIZE in compute api: reconcile error that cannot be recovered occurred: resource sku with name 'INVALID_VM_SIZE' and category 'virtualMachines' not found in location 'test-location'. Object will not be requeued",
@@ -304,6 +325,9 @@ func TestReconcileVMSS(t *testing.T) {
 				Reconciler:       asyncMock,
 				Client:           clientMock,
 				resourceSKUCache: resourceskus.NewStaticCache(getFakeSkus(), "test-location"),
+				extensionImages: vmextensionimages.NewStaticCache(map[string][]string{
+					"Microsoft.Azure.Extensions/CustomScript": {"2.1.3"},
+				}, "test-location"),
 			}
 
 			err := s.Reconcile(context.TODO())
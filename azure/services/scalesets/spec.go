@@ -21,6 +21,8 @@ import (
 	"encoding/base64"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
 	"github.com/pkg/errors"
@@ -36,10 +38,13 @@ import (
 // ScaleSetSpec defines the specification for a Scale Set.
 type ScaleSetSpec struct {
 	Name                         string
+	ComputerNamePrefix           string
 	ResourceGroup                string
 	Size                         string
 	Capacity                     int64
 	SSHKeyData                   string
+	AdditionalSSHKeyData         []string
+	AdminUsername                string
 	OSDisk                       infrav1.OSDisk
 	DataDisks                    []infrav1.DataDisk
 	SubnetName                   string
@@ -53,9 +58,16 @@ type ScaleSetSpec struct {
 	UserAssignedIdentities       []infrav1.UserAssignedIdentity
 	SecurityProfile              *infrav1.SecurityProfile
 	SpotVMOptions                *infrav1.SpotVMOptions
+	VaultSecrets                 []infrav1.VaultSecretGroup
+	VMGalleryApplications        []infrav1.VMGalleryApplication
 	AdditionalCapabilities       *infrav1.AdditionalCapabilities
 	DiagnosticsProfile           *infrav1.Diagnostics
 	FailureDomains               []string
+	ZoneBalance                  *bool
+	PlatformFaultDomainCount     *int32
+	SinglePlacementGroup         *bool
+	CapacityReservationGroupID   string
+	PinnedInstances              []PinnedInstance
 	VMExtensions                 []infrav1.VMExtension
 	NetworkInterfaces            []infrav1.NetworkInterface
 	IPv6Enabled                  bool
@@ -63,6 +75,7 @@ type ScaleSetSpec struct {
 	Location                     string
 	SubscriptionID               string
 	SKU                          resourceskus.SKU
+	MinimumVMResources           *infrav1.MinimumVMResources
 	VMSSExtensionSpecs           []azure.ResourceSpecGetter
 	VMImage                      *infrav1.Image
 	BootstrapData                string
@@ -72,6 +85,36 @@ type ScaleSetSpec struct {
 	ShouldPatchCustomData        bool
 	HasReplicasExternallyManaged bool
 	AdditionalTags               infrav1.Tags
+	UpgradeMode                  string
+	MaxBatchInstancePercent      *int32
+	PauseTimeBetweenBatches      *time.Duration
+	EnableAutomaticOSUpgrade     bool
+	AutomaticRepairsEnabled      bool
+	AutomaticRepairsGracePeriod  *time.Duration
+	PriorityMixPolicy            *PriorityMixPolicy
+	ScaleInPolicy                *ScaleInPolicy
+	SpotRestoreEnabled           bool
+	SpotRestoreTimeout           *time.Duration
+}
+
+// PriorityMixPolicy defines the mix of regular and Spot priority instances of a Flexible orchestration mode scale set.
+type PriorityMixPolicy struct {
+	BaseRegularPriorityCount           *int32
+	RegularPriorityPercentageAboveBase *int32
+}
+
+// PinnedInstance defines an instance of a Flexible orchestration mode scale set that is created individually in a zone
+// and a fault domain.
+type PinnedInstance struct {
+	Name                string
+	Zone                string
+	PlatformFaultDomain *int32
+}
+
+// ScaleInPolicy defines the instances Azure removes first when the capacity of a scale set is lowered.
+type ScaleInPolicy struct {
+	Rule          string
+	ForceDeletion bool
 }
 
 // ResourceName returns the name of the Scale Set.
@@ -111,6 +154,25 @@ func (s *ScaleSetSpec) existingParameters(ctx context.Context, existing interfac
 	vmss.ID = existingVMSS.ID
 
 	hasModelChanges := hasModelModifyingDifferences(&existingInfraVMSS, vmss)
+	hasUpgradePolicyChanges := hasUpgradePolicyDifferences(existingVMSS.UpgradePolicy, vmss.UpgradePolicy)
+	if !s.AutomaticRepairsEnabled && automaticRepairsEnabled(existingVMSS.AutomaticRepairsPolicy) {
+		// automatic repairs have to be disabled explicitly
+		vmss.AutomaticRepairsPolicy = &compute.AutomaticRepairsPolicy{Enabled: ptr.To(false)}
+	}
+	hasAutomaticRepairsChanges := hasAutomaticRepairsPolicyDifferences(existingVMSS.AutomaticRepairsPolicy, vmss.AutomaticRepairsPolicy)
+	if s.ScaleInPolicy == nil && hasScaleInPolicyDifferences(existingVMSS.ScaleInPolicy, defaultScaleInPolicy()) {
+		// a scale-in policy removed from the spec has to be reset explicitly
+		vmss.ScaleInPolicy = defaultScaleInPolicy()
+	}
+	hasScaleInPolicyChanges := hasScaleInPolicyDifferences(existingVMSS.ScaleInPolicy, vmss.ScaleInPolicy)
+	if !s.SpotRestoreEnabled && spotRestoreEnabled(existingVMSS.SpotRestorePolicy) {
+		// the Spot restore policy has to be disabled explicitly
+		vmss.SpotRestorePolicy = &compute.SpotRestorePolicy{Enabled: ptr.To(false)}
+	}
+	hasSpotRestorePolicyChanges := hasSpotRestorePolicyDifferences(existingVMSS.SpotRestorePolicy, vmss.SpotRestorePolicy)
+	// Boot diagnostics are updated in the model without rolling out the instances, which pick the change up without a
+	// reimage.
+	hasDiagnosticsChanges := hasDiagnosticsProfileDifferences(existingDiagnosticsProfile(existingVMSS), vmss.VirtualMachineProfile.DiagnosticsProfile)
 	isFlex := s.OrchestrationMode == infrav1.FlexibleOrchestrationMode
 	updated := true
 	if !isFlex {
@@ -118,13 +180,13 @@ func (s *ScaleSetSpec) existingParameters(ctx context.Context, existing interfac
 	}
 	if s.MaxSurge > 0 && (hasModelChanges || !updated) && !s.HasReplicasExternallyManaged {
 		// surge capacity with the intention of lowering during instance reconciliation
-		surge := s.Capacity + int64(s.MaxSurge)
+		surge := s.capacity() + int64(s.MaxSurge)
 		vmss.Sku.Capacity = ptr.To[int64](surge)
 	}
 
-	// If there are no model changes and no increase in the replica count, do not update the VMSS.
+	// If there are no model or policy changes and no increase in the replica count, do not update the VMSS.
 	// Decreases in replica count is handled by deleting AzureMachinePoolMachine instances in the MachinePoolScope
-	if *vmss.Sku.Capacity <= existingInfraVMSS.Capacity && !hasModelChanges && !s.ShouldPatchCustomData {
+	if *vmss.Sku.Capacity <= existingInfraVMSS.Capacity && !hasModelChanges && !hasUpgradePolicyChanges && !hasAutomaticRepairsChanges && !hasScaleInPolicyChanges && !hasSpotRestorePolicyChanges && !hasDiagnosticsChanges && !s.ShouldPatchCustomData {
 		// up to date, nothing to do
 		return nil, nil
 	}
@@ -132,6 +194,71 @@ func (s *ScaleSetSpec) existingParameters(ctx context.Context, existing interfac
 	return vmss, nil
 }
 
+// capacity returns the number of instances Azure creates from the scale set model. Pinned instances count toward the
+// capacity of the scale set once their VM exists, so the ones that don't exist yet are left out until CAPZ creates
+// them.
+func (s *ScaleSetSpec) capacity() int64 {
+	capacity := s.Capacity - int64(len(s.missingPinnedInstances()))
+	if capacity < 0 {
+		return 0
+	}
+	return capacity
+}
+
+// missingPinnedInstances returns the pinned instances whose VM isn't an instance of the scale set.
+func (s *ScaleSetSpec) missingPinnedInstances() []PinnedInstance {
+	var missing []PinnedInstance
+	for _, instance := range s.PinnedInstances {
+		vmName := s.pinnedVMName(instance)
+		exists := false
+		for _, vm := range s.VMSSInstances {
+			if strings.EqualFold(ptr.Deref(vm.Name, ""), vmName) {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			missing = append(missing, instance)
+		}
+	}
+	return missing
+}
+
+// pinnedVMName returns the name of the VM of a pinned instance.
+func (s *ScaleSetSpec) pinnedVMName(instance PinnedInstance) string {
+	return fmt.Sprintf("%s-%s", s.Name, instance.Name)
+}
+
+// PinnedVMSpecs returns the specs of the VMs of the pinned instances, created from the model of the scale set with
+// the given ID.
+func (s *ScaleSetSpec) PinnedVMSpecs(ctx context.Context, scaleSetID string) ([]azure.ResourceSpecGetter, error) {
+	if len(s.PinnedInstances) == 0 {
+		return nil, nil
+	}
+	params, err := s.Parameters(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to generate the model of scale set %s", s.Name)
+	}
+	model, ok := params.(compute.VirtualMachineScaleSet)
+	if !ok {
+		return nil, errors.Errorf("%T is not a compute.VirtualMachineScaleSet", params)
+	}
+	model.ID = ptr.To(scaleSetID)
+
+	specs := make([]azure.ResourceSpecGetter, 0, len(s.PinnedInstances))
+	for _, instance := range s.PinnedInstances {
+		specs = append(specs, &PinnedVMSpec{
+			Name:                s.pinnedVMName(instance),
+			ResourceGroup:       s.ResourceGroup,
+			ScaleSetName:        s.Name,
+			Zone:                instance.Zone,
+			PlatformFaultDomain: instance.PlatformFaultDomain,
+			ScaleSet:            model,
+		})
+	}
+	return specs, nil
+}
+
 // Parameters returns the parameters for the Scale Set.
 func (s *ScaleSetSpec) Parameters(ctx context.Context, existing interface{}) (parameters interface{}, err error) {
 	if existing != nil {
@@ -178,18 +305,19 @@ func (s *ScaleSetSpec) Parameters(ctx context.Context, existing interface{}) (pa
 		Sku: &compute.Sku{
 			Name:     ptr.To(s.Size),
 			Tier:     ptr.To("Standard"),
-			Capacity: ptr.To[int64](s.Capacity),
+			Capacity: ptr.To[int64](s.capacity()),
 		},
 		Zones: &s.FailureDomains,
 		Plan:  s.generateImagePlan(ctx),
 		VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
 			OrchestrationMode:    orchestrationMode,
-			SinglePlacementGroup: ptr.To(false),
+			SinglePlacementGroup: ptr.To(ptr.Deref(s.SinglePlacementGroup, false)),
 			VirtualMachineProfile: &compute.VirtualMachineScaleSetVMProfile{
 				OsProfile:          osProfile,
 				StorageProfile:     storageProfile,
 				SecurityProfile:    securityProfile,
 				DiagnosticsProfile: diagnosticsProfile,
+				ApplicationProfile: converters.VMGalleryApplicationsToSDK(s.VMGalleryApplications),
 				NetworkProfile: &compute.VirtualMachineScaleSetNetworkProfile{
 					NetworkInterfaceConfigurations: s.getVirtualMachineScaleSetNetworkConfiguration(),
 				},
@@ -208,7 +336,10 @@ func (s *ScaleSetSpec) Parameters(ctx context.Context, existing interface{}) (pa
 	switch orchestrationMode {
 	case compute.OrchestrationModeUniform: // Uniform VMSS
 		vmss.VirtualMachineScaleSetProperties.Overprovision = ptr.To(false)
-		vmss.VirtualMachineScaleSetProperties.UpgradePolicy = &compute.UpgradePolicy{Mode: compute.UpgradeModeManual}
+		vmss.VirtualMachineScaleSetProperties.UpgradePolicy = s.getUpgradePolicy()
+		// Azure picks the number of fault domains of a Uniform scale set unless it is set
+		vmss.VirtualMachineScaleSetProperties.PlatformFaultDomainCount = s.PlatformFaultDomainCount
+		vmss.VirtualMachineScaleSetProperties.ZoneBalance = s.ZoneBalance
 	case compute.OrchestrationModeFlexible: // VMSS Flex, VMs are treated as individual virtual machines
 		vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.NetworkProfile.NetworkAPIVersion =
 			compute.NetworkAPIVersionTwoZeroTwoZeroHyphenMinusOneOneHyphenMinusZeroOne
@@ -216,6 +347,45 @@ func (s *ScaleSetSpec) Parameters(ctx context.Context, existing interface{}) (pa
 		if len(s.FailureDomains) > 1 {
 			vmss.VirtualMachineScaleSetProperties.PlatformFaultDomainCount = ptr.To[int32](int32(len(s.FailureDomains)))
 		}
+		if s.PlatformFaultDomainCount != nil {
+			vmss.VirtualMachineScaleSetProperties.PlatformFaultDomainCount = s.PlatformFaultDomainCount
+		}
+		vmss.VirtualMachineScaleSetProperties.ZoneBalance = s.ZoneBalance
+	}
+
+	if s.CapacityReservationGroupID != "" {
+		vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.CapacityReservation = &compute.CapacityReservationProfile{
+			CapacityReservationGroup: &compute.SubResource{ID: ptr.To(s.CapacityReservationGroupID)},
+		}
+	}
+
+	if s.AutomaticRepairsEnabled {
+		vmss.VirtualMachineScaleSetProperties.AutomaticRepairsPolicy = &compute.AutomaticRepairsPolicy{
+			Enabled: ptr.To(true),
+		}
+		if s.AutomaticRepairsGracePeriod != nil {
+			vmss.VirtualMachineScaleSetProperties.AutomaticRepairsPolicy.GracePeriod = ptr.To(iso8601Duration(*s.AutomaticRepairsGracePeriod))
+		}
+	}
+
+	if s.SpotRestoreEnabled {
+		vmss.VirtualMachineScaleSetProperties.SpotRestorePolicy = &compute.SpotRestorePolicy{
+			Enabled: ptr.To(true),
+		}
+		if s.SpotRestoreTimeout != nil {
+			vmss.VirtualMachineScaleSetProperties.SpotRestorePolicy.RestoreTimeout = ptr.To(iso8601Duration(*s.SpotRestoreTimeout))
+		}
+	}
+
+	if s.ScaleInPolicy != nil {
+		rule := compute.VirtualMachineScaleSetScaleInRulesDefault
+		if s.ScaleInPolicy.Rule != "" {
+			rule = compute.VirtualMachineScaleSetScaleInRules(s.ScaleInPolicy.Rule)
+		}
+		vmss.VirtualMachineScaleSetProperties.ScaleInPolicy = &compute.ScaleInPolicy{
+			Rules:         &[]compute.VirtualMachineScaleSetScaleInRules{rule},
+			ForceDeletion: ptr.To(s.ScaleInPolicy.ForceDeletion),
+		}
 	}
 
 	// Assign Identity to VMSS
@@ -278,6 +448,161 @@ func hasModelModifyingDifferences(infraVMSS *azure.VMSS, vmss compute.VirtualMac
 	return infraVMSS.HasModelChanges(other)
 }
 
+// hasUpgradePolicyDifferences returns true if the desired upgrade policy differs from the existing one in the settings
+// managed by CAPZ. Settings defaulted by Azure are ignored when they aren't set in the desired upgrade policy.
+func hasUpgradePolicyDifferences(existing, desired *compute.UpgradePolicy) bool {
+	if desired == nil {
+		return false
+	}
+	if existing == nil {
+		return true
+	}
+	if existing.Mode != desired.Mode {
+		return true
+	}
+	if automaticOSUpgradeEnabled(existing) != automaticOSUpgradeEnabled(desired) {
+		return true
+	}
+	if desired.RollingUpgradePolicy == nil {
+		return false
+	}
+	if existing.RollingUpgradePolicy == nil {
+		return true
+	}
+	if desired.RollingUpgradePolicy.MaxBatchInstancePercent != nil &&
+		ptr.Deref(existing.RollingUpgradePolicy.MaxBatchInstancePercent, 0) != *desired.RollingUpgradePolicy.MaxBatchInstancePercent {
+		return true
+	}
+	return desired.RollingUpgradePolicy.PauseTimeBetweenBatches != nil &&
+		!strings.EqualFold(ptr.Deref(existing.RollingUpgradePolicy.PauseTimeBetweenBatches, ""), *desired.RollingUpgradePolicy.PauseTimeBetweenBatches)
+}
+
+func automaticOSUpgradeEnabled(policy *compute.UpgradePolicy) bool {
+	return policy.AutomaticOSUpgradePolicy != nil && ptr.Deref(policy.AutomaticOSUpgradePolicy.EnableAutomaticOSUpgrade, false)
+}
+
+// hasAutomaticRepairsPolicyDifferences returns true if the desired automatic repairs policy differs from the existing
+// one. The grace period defaulted by Azure is ignored when it isn't set in the desired policy.
+func hasAutomaticRepairsPolicyDifferences(existing, desired *compute.AutomaticRepairsPolicy) bool {
+	if automaticRepairsEnabled(existing) != automaticRepairsEnabled(desired) {
+		return true
+	}
+	return automaticRepairsEnabled(desired) && desired.GracePeriod != nil &&
+		!strings.EqualFold(ptr.Deref(existing.GracePeriod, ""), *desired.GracePeriod)
+}
+
+func automaticRepairsEnabled(policy *compute.AutomaticRepairsPolicy) bool {
+	return policy != nil && ptr.Deref(policy.Enabled, false)
+}
+
+// existingDiagnosticsProfile returns the diagnostics profile of the model of an existing scale set, if any.
+func existingDiagnosticsProfile(vmss compute.VirtualMachineScaleSet) *compute.DiagnosticsProfile {
+	if vmss.VirtualMachineScaleSetProperties == nil || vmss.VirtualMachineProfile == nil {
+		return nil
+	}
+	return vmss.VirtualMachineProfile.DiagnosticsProfile
+}
+
+// hasDiagnosticsProfileDifferences returns true if the desired boot diagnostics differ from the existing ones. Boot
+// diagnostics left to Azure when they aren't set in the desired profile are ignored.
+func hasDiagnosticsProfileDifferences(existing, desired *compute.DiagnosticsProfile) bool {
+	if desired == nil || desired.BootDiagnostics == nil {
+		return false
+	}
+	if existing == nil || existing.BootDiagnostics == nil {
+		return true
+	}
+	if ptr.Deref(existing.BootDiagnostics.Enabled, false) != ptr.Deref(desired.BootDiagnostics.Enabled, false) {
+		return true
+	}
+	return !strings.EqualFold(ptr.Deref(existing.BootDiagnostics.StorageURI, ""), ptr.Deref(desired.BootDiagnostics.StorageURI, ""))
+}
+
+// hasSpotRestorePolicyDifferences returns true if the desired Spot restore policy differs from the existing one. The
+// restore timeout defaulted by Azure is ignored when it isn't set in the desired policy.
+func hasSpotRestorePolicyDifferences(existing, desired *compute.SpotRestorePolicy) bool {
+	if spotRestoreEnabled(existing) != spotRestoreEnabled(desired) {
+		return true
+	}
+	return spotRestoreEnabled(desired) && desired.RestoreTimeout != nil &&
+		!strings.EqualFold(ptr.Deref(existing.RestoreTimeout, ""), *desired.RestoreTimeout)
+}
+
+func spotRestoreEnabled(policy *compute.SpotRestorePolicy) bool {
+	return policy != nil && ptr.Deref(policy.Enabled, false)
+}
+
+// hasScaleInPolicyDifferences returns true if the desired scale-in policy differs from the existing one. A scale set
+// without a scale-in policy follows the Default rule without force deletion.
+func hasScaleInPolicyDifferences(existing, desired *compute.ScaleInPolicy) bool {
+	if desired == nil {
+		return false
+	}
+	return scaleInRule(existing) != scaleInRule(desired) || scaleInForceDeletion(existing) != scaleInForceDeletion(desired)
+}
+
+func scaleInRule(policy *compute.ScaleInPolicy) compute.VirtualMachineScaleSetScaleInRules {
+	if policy == nil || policy.Rules == nil || len(*policy.Rules) == 0 {
+		return compute.VirtualMachineScaleSetScaleInRulesDefault
+	}
+	return (*policy.Rules)[0]
+}
+
+func scaleInForceDeletion(policy *compute.ScaleInPolicy) bool {
+	return policy != nil && ptr.Deref(policy.ForceDeletion, false)
+}
+
+// defaultScaleInPolicy returns the scale-in policy Azure uses when none is set.
+func defaultScaleInPolicy() *compute.ScaleInPolicy {
+	return &compute.ScaleInPolicy{
+		Rules:         &[]compute.VirtualMachineScaleSetScaleInRules{compute.VirtualMachineScaleSetScaleInRulesDefault},
+		ForceDeletion: ptr.To(false),
+	}
+}
+
+// getUpgradePolicy returns the upgrade policy of a Uniform orchestration mode scale set. Instances are upgraded
+// manually by CAPZ unless another upgrade mode is set.
+func (s *ScaleSetSpec) getUpgradePolicy() *compute.UpgradePolicy {
+	policy := &compute.UpgradePolicy{Mode: compute.UpgradeModeManual}
+	if s.UpgradeMode != "" {
+		policy.Mode = compute.UpgradeMode(s.UpgradeMode)
+	}
+	if s.EnableAutomaticOSUpgrade {
+		policy.AutomaticOSUpgradePolicy = &compute.AutomaticOSUpgradePolicy{
+			EnableAutomaticOSUpgrade: ptr.To(true),
+		}
+	}
+	if policy.Mode == compute.UpgradeModeRolling && (s.MaxBatchInstancePercent != nil || s.PauseTimeBetweenBatches != nil) {
+		policy.RollingUpgradePolicy = &compute.RollingUpgradePolicy{
+			MaxBatchInstancePercent: s.MaxBatchInstancePercent,
+		}
+		if s.PauseTimeBetweenBatches != nil {
+			policy.RollingUpgradePolicy.PauseTimeBetweenBatches = ptr.To(iso8601Duration(*s.PauseTimeBetweenBatches))
+		}
+	}
+	return policy
+}
+
+// iso8601Duration formats a duration as an ISO 8601 duration, e.g. PT1H30M, the way Azure returns it.
+func iso8601Duration(d time.Duration) string {
+	seconds := int64(d.Round(time.Second) / time.Second)
+	if seconds <= 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	b.WriteString("PT")
+	if h := seconds / 3600; h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+	}
+	if m := seconds % 3600 / 60; m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+	}
+	if s := seconds % 60; s > 0 {
+		fmt.Fprintf(&b, "%dS", s)
+	}
+	return b.String()
+}
+
 func (s *ScaleSetSpec) generateExtensions(ctx context.Context) ([]compute.VirtualMachineScaleSetExtension, error) {
 	extensions := make([]compute.VirtualMachineScaleSetExtension, len(s.VMSSExtensionSpecs))
 	for i, extensionSpec := range s.VMSSExtensionSpecs {
@@ -336,6 +661,9 @@ func (s *ScaleSetSpec) getVirtualMachineScaleSetNetworkConfiguration() *[]comput
 			if j == 0 {
 				// Always use the first IPConfig as the Primary
 				ipconfig.Primary = ptr.To(true)
+				if n.PublicIPPerInstance {
+					ipconfig.PublicIPAddressConfiguration = s.getPublicIPAddressConfiguration(*nicConfig.Name, n)
+				}
 			}
 			ipconfigs = append(ipconfigs, ipconfig)
 		}
@@ -362,6 +690,30 @@ func (s *ScaleSetSpec) getVirtualMachineScaleSetNetworkConfiguration() *[]comput
 	return &nicConfigs
 }
 
+// getPublicIPAddressConfiguration returns the configuration of the public IP addresses assigned to each instance of the
+// scale set on the primary IP configuration of a network interface.
+func (s *ScaleSetSpec) getPublicIPAddressConfiguration(nicConfigName string, n infrav1.NetworkInterface) *compute.VirtualMachineScaleSetPublicIPAddressConfiguration {
+	config := &compute.VirtualMachineScaleSetPublicIPAddressConfiguration{
+		Name: ptr.To(nicConfigName + "-pip"),
+		Sku: &compute.PublicIPAddressSku{
+			Name: compute.PublicIPAddressSkuNameStandard,
+			Tier: compute.PublicIPAddressSkuTierRegional,
+		},
+		VirtualMachineScaleSetPublicIPAddressConfigurationProperties: &compute.VirtualMachineScaleSetPublicIPAddressConfigurationProperties{
+			PublicIPAddressVersion: compute.IPVersionIPv4,
+		},
+	}
+	if n.PublicIPPrefixID != "" {
+		config.PublicIPPrefix = &compute.SubResource{ID: ptr.To(n.PublicIPPrefixID)}
+	}
+	if s.OrchestrationMode == infrav1.FlexibleOrchestrationMode {
+		// Public IP addresses of Flexible scale set instances are standalone resources, which are only detached from
+		// their instance by default.
+		config.DeleteOption = compute.DeleteOptionsDelete
+	}
+	return config
+}
+
 // generateStorageProfile generates a pointer to a compute.VirtualMachineScaleSetStorageProfile which can utilized for VM creation.
 func (s *ScaleSetSpec) generateStorageProfile(ctx context.Context) (*compute.VirtualMachineScaleSetStorageProfile, error) {
 	_, _, done := tele.StartSpanWithLogger(ctx, "scalesets.ScaleSetSpec.generateStorageProfile")
@@ -384,6 +736,13 @@ func (s *ScaleSetSpec) generateStorageProfile(ctx context.Context) (*compute.Vir
 		storageProfile.OsDisk.DiffDiskSettings = &compute.DiffDiskSettings{
 			Option: compute.DiffDiskOptions(s.OSDisk.DiffDiskSettings.Option),
 		}
+
+		if placement := s.OSDisk.DiffDiskSettings.Placement; placement != "" {
+			if !s.SKU.SupportsEphemeralOSDiskPlacement(string(placement)) {
+				return nil, fmt.Errorf("vm size %s does not support placing the ephemeral os disk on the %s. select a different vm size or ephemeral os disk placement", s.Size, placement)
+			}
+			storageProfile.OsDisk.DiffDiskSettings.Placement = compute.DiffDiskPlacement(placement)
+		}
 	}
 
 	if s.OSDisk.ManagedDisk != nil {
@@ -417,6 +776,9 @@ func (s *ScaleSetSpec) generateStorageProfile(ctx context.Context) (*compute.Vir
 			if disk.ManagedDisk.DiskEncryptionSet != nil {
 				dataDisks[i].ManagedDisk.DiskEncryptionSet = &compute.DiskEncryptionSetParameters{ID: ptr.To(disk.ManagedDisk.DiskEncryptionSet.ID)}
 			}
+
+			dataDisks[i].DiskIOPSReadWrite = disk.ManagedDisk.DiskIOPSReadWrite
+			dataDisks[i].DiskMBpsReadWrite = disk.ManagedDisk.DiskMBpsReadWrite
 		}
 	}
 	storageProfile.DataDisks = &dataDisks
@@ -440,10 +802,21 @@ func (s *ScaleSetSpec) generateOSProfile(_ context.Context) (*compute.VirtualMac
 		return nil, errors.Wrap(err, "failed to decode ssh public key")
 	}
 
+	computerNamePrefix := s.ComputerNamePrefix
+	if computerNamePrefix == "" {
+		computerNamePrefix = s.Name
+	}
+
+	adminUsername := s.AdminUsername
+	if adminUsername == "" {
+		adminUsername = azure.DefaultUserName
+	}
+
 	osProfile := &compute.VirtualMachineScaleSetOSProfile{
-		ComputerNamePrefix: ptr.To(s.Name),
-		AdminUsername:      ptr.To(azure.DefaultUserName),
+		ComputerNamePrefix: ptr.To(computerNamePrefix),
+		AdminUsername:      ptr.To(adminUsername),
 		CustomData:         ptr.To(s.BootstrapData),
+		Secrets:            converters.VaultSecretsToSDK(s.VaultSecrets),
 	}
 
 	switch s.OSDisk.OSType {
@@ -460,15 +833,28 @@ func (s *ScaleSetSpec) generateOSProfile(_ context.Context) (*compute.VirtualMac
 			EnableAutomaticUpdates: ptr.To(false),
 		}
 	default:
+		authorizedKeysPath := fmt.Sprintf("/home/%s/.ssh/authorized_keys", adminUsername)
+		publicKeys := []compute.SSHPublicKey{
+			{
+				Path:    ptr.To(authorizedKeysPath),
+				KeyData: ptr.To(string(sshKey)),
+			},
+		}
+		for _, keyData := range s.AdditionalSSHKeyData {
+			additionalKey, err := base64.StdEncoding.DecodeString(keyData)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to decode additional ssh public key")
+			}
+			publicKeys = append(publicKeys, compute.SSHPublicKey{
+				Path:    ptr.To(authorizedKeysPath),
+				KeyData: ptr.To(string(additionalKey)),
+			})
+		}
+
 		osProfile.LinuxConfiguration = &compute.LinuxConfiguration{
 			DisablePasswordAuthentication: ptr.To(true),
 			SSH: &compute.SSHConfiguration{
-				PublicKeys: &[]compute.SSHPublicKey{
-					{
-						Path:    ptr.To(fmt.Sprintf("/home/%s/.ssh/authorized_keys", azure.DefaultUserName)),
-						KeyData: ptr.To(string(sshKey)),
-					},
-				},
+				PublicKeys: &publicKeys,
 			},
 		}
 	}
@@ -513,11 +899,11 @@ func (s *ScaleSetSpec) getSecurityProfile() (*compute.SecurityProfile, error) {
 		return nil, nil
 	}
 
-	if !s.SKU.HasCapability(resourceskus.EncryptionAtHost) {
+	if ptr.Deref(s.SecurityProfile.EncryptionAtHost, false) && !s.SKU.HasCapability(resourceskus.EncryptionAtHost) {
 		return nil, azure.WithTerminalError(errors.Errorf("encryption at host is not supported for VM type %s", s.Size))
 	}
 
 	return &compute.SecurityProfile{
-		EncryptionAtHost: ptr.To(*s.SecurityProfile.EncryptionAtHost),
+		EncryptionAtHost: s.SecurityProfile.EncryptionAtHost,
 	}, nil
 }
@@ -20,6 +20,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
 	"github.com/google/go-cmp/cmp"
@@ -50,6 +51,17 @@ var (
 	managedDiagnosticsSpec, managedDiagnoisticsVMSS                                    = getManagedDiagnosticsVMSS()
 	disabledDiagnosticsSpec, disabledDiagnosticsVMSS                                   = getDisabledDiagnosticsVMSS()
 	nilDiagnosticsProfileSpec, nilDiagnosticsProfileVMSS                               = getNilDiagnosticsProfileVMSS()
+	flexPlacementSpec, flexPlacementVMSS                                               = getFlexPlacementVMSS()
+	rollingUpgradeSpec, rollingUpgradeVMSS                                             = getRollingUpgradeVMSS()
+	automaticRepairsSpec, automaticRepairsVMSS                                         = getAutomaticRepairsVMSS()
+	scaleInPolicySpec, scaleInPolicyVMSS                                               = getScaleInPolicyVMSS()
+	capacityReservationSpec, capacityReservationVMSS                                   = getCapacityReservationVMSS()
+	uniformPlacementSpec, uniformPlacementVMSS                                         = getUniformPlacementVMSS()
+	spotRestoreSpec, spotRestoreVMSS                                                   = getSpotRestoreVMSS()
+	adminUsernameSpec, adminUsernameVMSS                                               = getAdminUsernameVMSS()
+	ultraDiskPerformanceSpec, ultraDiskPerformanceVMSS                                 = getUltraDiskPerformanceVMSS()
+	publicIPPerInstanceSpec, publicIPPerInstanceVMSS                                   = getPublicIPPerInstanceVMSS()
+	flexPublicIPPerInstanceSpec, flexPublicIPPerInstanceVMSS                           = getFlexPublicIPPerInstanceVMSS()
 )
 
 func getDefaultVMSS() (ScaleSetSpec, compute.VirtualMachineScaleSet) {
@@ -520,6 +532,196 @@ func getNilDiagnosticsProfileVMSS() (ScaleSetSpec, compute.VirtualMachineScaleSe
 	return spec, vmss
 }
 
+func getFlexPlacementVMSS() (ScaleSetSpec, compute.VirtualMachineScaleSet) {
+	spec := newDefaultVMSSSpec()
+	spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
+		NameSuffix: "my_disk_with_ultra_disks",
+		DiskSizeGB: 128,
+		Lun:        ptr.To[int32](3),
+		ManagedDisk: &infrav1.ManagedDiskParameters{
+			StorageAccountType: "UltraSSD_LRS",
+		},
+	})
+	spec.OrchestrationMode = infrav1.FlexibleOrchestrationMode
+	spec.ZoneBalance = ptr.To(true)
+	spec.PlatformFaultDomainCount = ptr.To[int32](1)
+	spec.SinglePlacementGroup = ptr.To(true)
+	spec.CapacityReservationGroupID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/capacityReservationGroups/my-crg"
+
+	vmss := newDefaultVMSS("VM_SIZE")
+	vmss.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: ptr.To(true)}
+	vmss.VirtualMachineScaleSetProperties.OrchestrationMode = compute.OrchestrationModeFlexible
+	vmss.VirtualMachineScaleSetProperties.UpgradePolicy = nil
+	vmss.VirtualMachineScaleSetProperties.Overprovision = nil
+	vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.NetworkProfile.NetworkAPIVersion =
+		compute.NetworkAPIVersionTwoZeroTwoZeroHyphenMinusOneOneHyphenMinusZeroOne
+	vmss.VirtualMachineScaleSetProperties.ZoneBalance = ptr.To(true)
+	vmss.VirtualMachineScaleSetProperties.PlatformFaultDomainCount = ptr.To[int32](1)
+	vmss.VirtualMachineScaleSetProperties.SinglePlacementGroup = ptr.To(true)
+	vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.CapacityReservation = &compute.CapacityReservationProfile{
+		CapacityReservationGroup: &compute.SubResource{
+			ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/capacityReservationGroups/my-crg"),
+		},
+	}
+
+	return spec, vmss
+}
+
+func getRollingUpgradeVMSS() (ScaleSetSpec, compute.VirtualMachineScaleSet) {
+	spec, vmss := getDefaultVMSS()
+	spec.UpgradeMode = "Rolling"
+	spec.MaxBatchInstancePercent = ptr.To[int32](50)
+	spec.PauseTimeBetweenBatches = ptr.To(90 * time.Second)
+	spec.EnableAutomaticOSUpgrade = true
+
+	vmss.VirtualMachineScaleSetProperties.UpgradePolicy = &compute.UpgradePolicy{
+		Mode: compute.UpgradeModeRolling,
+		RollingUpgradePolicy: &compute.RollingUpgradePolicy{
+			MaxBatchInstancePercent: ptr.To[int32](50),
+			PauseTimeBetweenBatches: ptr.To("PT1M30S"),
+		},
+		AutomaticOSUpgradePolicy: &compute.AutomaticOSUpgradePolicy{
+			EnableAutomaticOSUpgrade: ptr.To(true),
+		},
+	}
+
+	return spec, vmss
+}
+
+func getAutomaticRepairsVMSS() (ScaleSetSpec, compute.VirtualMachineScaleSet) {
+	spec, vmss := getDefaultVMSS()
+	spec.AutomaticRepairsEnabled = true
+	spec.AutomaticRepairsGracePeriod = ptr.To(30 * time.Minute)
+
+	vmss.VirtualMachineScaleSetProperties.AutomaticRepairsPolicy = &compute.AutomaticRepairsPolicy{
+		Enabled:     ptr.To(true),
+		GracePeriod: ptr.To("PT30M"),
+	}
+
+	return spec, vmss
+}
+
+func getScaleInPolicyVMSS() (ScaleSetSpec, compute.VirtualMachineScaleSet) {
+	spec, vmss := getDefaultVMSS()
+	spec.ScaleInPolicy = &ScaleInPolicy{Rule: "OldestVM", ForceDeletion: true}
+
+	vmss.VirtualMachineScaleSetProperties.ScaleInPolicy = &compute.ScaleInPolicy{
+		Rules:         &[]compute.VirtualMachineScaleSetScaleInRules{compute.VirtualMachineScaleSetScaleInRulesOldestVM},
+		ForceDeletion: ptr.To(true),
+	}
+
+	return spec, vmss
+}
+
+func getSpotRestoreVMSS() (ScaleSetSpec, compute.VirtualMachineScaleSet) {
+	spec, vmss := getDefaultVMSS()
+	spec.SpotRestoreEnabled = true
+	spec.SpotRestoreTimeout = ptr.To(2 * time.Hour)
+
+	vmss.VirtualMachineScaleSetProperties.SpotRestorePolicy = &compute.SpotRestorePolicy{
+		Enabled:        ptr.To(true),
+		RestoreTimeout: ptr.To("PT2H"),
+	}
+
+	return spec, vmss
+}
+
+func getAdminUsernameVMSS() (ScaleSetSpec, compute.VirtualMachineScaleSet) {
+	spec, vmss := getDefaultVMSS()
+	spec.AdminUsername = "ops_admin"
+
+	osProfile := vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.OsProfile
+	osProfile.AdminUsername = ptr.To("ops_admin")
+	(*osProfile.LinuxConfiguration.SSH.PublicKeys)[0].Path = ptr.To("/home/ops_admin/.ssh/authorized_keys")
+
+	return spec, vmss
+}
+
+func getUltraDiskPerformanceVMSS() (ScaleSetSpec, compute.VirtualMachineScaleSet) {
+	spec, vmss := getDefaultVMSS()
+	ultraDisk := &spec.DataDisks[len(spec.DataDisks)-1]
+	ultraDisk.ManagedDisk = &infrav1.ManagedDiskParameters{
+		StorageAccountType: "UltraSSD_LRS",
+		DiskIOPSReadWrite:  ptr.To[int64](20000),
+		DiskMBpsReadWrite:  ptr.To[int64](500),
+	}
+
+	dataDisks := *vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.StorageProfile.DataDisks
+	dataDisks[len(dataDisks)-1].DiskIOPSReadWrite = ptr.To[int64](20000)
+	dataDisks[len(dataDisks)-1].DiskMBpsReadWrite = ptr.To[int64](500)
+
+	return spec, vmss
+}
+
+func getPublicIPPerInstanceVMSS() (ScaleSetSpec, compute.VirtualMachineScaleSet) {
+	spec, vmss := getDefaultVMSS()
+	spec.NetworkInterfaces[0].PublicIPPerInstance = true
+	spec.NetworkInterfaces[0].PublicIPPrefixID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPPrefixes/my-prefix"
+
+	nicConfigs := *vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations
+	(*nicConfigs[0].IPConfigurations)[0].PublicIPAddressConfiguration = &compute.VirtualMachineScaleSetPublicIPAddressConfiguration{
+		Name: ptr.To("my-vmss-nic-0-pip"),
+		Sku: &compute.PublicIPAddressSku{
+			Name: compute.PublicIPAddressSkuNameStandard,
+			Tier: compute.PublicIPAddressSkuTierRegional,
+		},
+		VirtualMachineScaleSetPublicIPAddressConfigurationProperties: &compute.VirtualMachineScaleSetPublicIPAddressConfigurationProperties{
+			PublicIPAddressVersion: compute.IPVersionIPv4,
+			PublicIPPrefix: &compute.SubResource{
+				ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPPrefixes/my-prefix"),
+			},
+		},
+	}
+
+	return spec, vmss
+}
+
+func getFlexPublicIPPerInstanceVMSS() (ScaleSetSpec, compute.VirtualMachineScaleSet) {
+	spec, vmss := getFlexPlacementVMSS()
+	spec.NetworkInterfaces[0].PublicIPPerInstance = true
+
+	nicConfigs := *vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations
+	(*nicConfigs[0].IPConfigurations)[0].PublicIPAddressConfiguration = &compute.VirtualMachineScaleSetPublicIPAddressConfiguration{
+		Name: ptr.To("my-vmss-nic-0-pip"),
+		Sku: &compute.PublicIPAddressSku{
+			Name: compute.PublicIPAddressSkuNameStandard,
+			Tier: compute.PublicIPAddressSkuTierRegional,
+		},
+		VirtualMachineScaleSetPublicIPAddressConfigurationProperties: &compute.VirtualMachineScaleSetPublicIPAddressConfigurationProperties{
+			PublicIPAddressVersion: compute.IPVersionIPv4,
+			DeleteOption:           compute.DeleteOptionsDelete,
+		},
+	}
+
+	return spec, vmss
+}
+
+func getCapacityReservationVMSS() (ScaleSetSpec, compute.VirtualMachineScaleSet) {
+	spec, vmss := getDefaultVMSS()
+	spec.CapacityReservationGroupID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/capacityReservationGroups/my-crg"
+
+	vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.CapacityReservation = &compute.CapacityReservationProfile{
+		CapacityReservationGroup: &compute.SubResource{
+			ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/capacityReservationGroups/my-crg"),
+		},
+	}
+
+	return spec, vmss
+}
+
+func getUniformPlacementVMSS() (ScaleSetSpec, compute.VirtualMachineScaleSet) {
+	spec, vmss := getDefaultVMSS()
+	spec.ZoneBalance = ptr.To(true)
+	spec.PlatformFaultDomainCount = ptr.To[int32](5)
+	spec.SinglePlacementGroup = ptr.To(true)
+
+	vmss.VirtualMachineScaleSetProperties.ZoneBalance = ptr.To(true)
+	vmss.VirtualMachineScaleSetProperties.PlatformFaultDomainCount = ptr.To[int32](5)
+	vmss.VirtualMachineScaleSetProperties.SinglePlacementGroup = ptr.To(true)
+
+	return spec, vmss
+}
+
 func TestScaleSetParameters(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -549,6 +751,39 @@ func TestScaleSetParameters(t *testing.T) {
 			expected:      nil,
 			expectedError: "",
 		},
+		{
+			name: "windows vmss with upgrade policy settings defaulted by Azure is not updated",
+			spec: windowsSpec,
+			existing: func() compute.VirtualMachineScaleSet {
+				vmss := windowsVMSS
+				properties := *vmss.VirtualMachineScaleSetProperties
+				vmss.VirtualMachineScaleSetProperties = &properties
+				vmss.UpgradePolicy = &compute.UpgradePolicy{
+					Mode: compute.UpgradeModeManual,
+					RollingUpgradePolicy: &compute.RollingUpgradePolicy{
+						MaxBatchInstancePercent: ptr.To[int32](20),
+						PauseTimeBetweenBatches: ptr.To("PT0S"),
+					},
+					AutomaticOSUpgradePolicy: &compute.AutomaticOSUpgradePolicy{
+						EnableAutomaticOSUpgrade: ptr.To(false),
+					},
+				}
+				return vmss
+			}(),
+			expected:      nil,
+			expectedError: "",
+		},
+		{
+			name: "windows vmss with different tags is not updated",
+			spec: windowsSpec,
+			existing: func() compute.VirtualMachineScaleSet {
+				vmss := windowsVMSS
+				vmss.Tags = map[string]*string{"cost-center": ptr.To("1234")}
+				return vmss
+			}(),
+			expected:      nil,
+			expectedError: "",
+		},
 		{
 			name:          "accelerated networking vmss",
 			spec:          acceleratedNetworkingSpec,
@@ -675,6 +910,83 @@ func TestScaleSetParameters(t *testing.T) {
 			expected:      nilDiagnosticsProfileVMSS,
 			expectedError: "",
 		},
+		{
+			name:          "flex vmss with placement",
+			spec:          flexPlacementSpec,
+			existing:      nil,
+			expected:      flexPlacementVMSS,
+			expectedError: "",
+		},
+		{
+			name:          "vmss with rolling upgrade policy",
+			spec:          rollingUpgradeSpec,
+			existing:      nil,
+			expected:      rollingUpgradeVMSS,
+			expectedError: "",
+		},
+		{
+			name:          "vmss with automatic repairs",
+			spec:          automaticRepairsSpec,
+			existing:      nil,
+			expected:      automaticRepairsVMSS,
+			expectedError: "",
+		},
+		{
+			name:          "vmss with scale-in policy",
+			spec:          scaleInPolicySpec,
+			existing:      nil,
+			expected:      scaleInPolicyVMSS,
+			expectedError: "",
+		},
+		{
+			name:          "uniform vmss with a capacity reservation group",
+			spec:          capacityReservationSpec,
+			existing:      nil,
+			expected:      capacityReservationVMSS,
+			expectedError: "",
+		},
+		{
+			name:          "uniform vmss with placement",
+			spec:          uniformPlacementSpec,
+			existing:      nil,
+			expected:      uniformPlacementVMSS,
+			expectedError: "",
+		},
+		{
+			name:          "vmss with a Spot restore policy",
+			spec:          spotRestoreSpec,
+			existing:      nil,
+			expected:      spotRestoreVMSS,
+			expectedError: "",
+		},
+		{
+			name:          "vmss with a custom admin username",
+			spec:          adminUsernameSpec,
+			existing:      nil,
+			expected:      adminUsernameVMSS,
+			expectedError: "",
+		},
+		{
+			name:          "vmss with an ultra disk with provisioned IOPS and throughput",
+			spec:          ultraDiskPerformanceSpec,
+			existing:      nil,
+			expected:      ultraDiskPerformanceVMSS,
+			expectedError: "",
+		},
+		{
+			name:          "uniform vmss with a public IP per instance",
+			spec:          publicIPPerInstanceSpec,
+			existing:      nil,
+			expected:      publicIPPerInstanceVMSS,
+			expectedError: "",
+		},
+		{
+			name:          "flex vmss with a public IP per instance",
+			spec:          flexPublicIPPerInstanceSpec,
+			existing:      nil,
+			expected:      flexPublicIPPerInstanceVMSS,
+			expectedError: "",
+		},
 	}
 	for _, tc := range testcases {
 		tc := tc
@@ -705,3 +1017,343 @@ func TestScaleSetParameters(t *testing.T) {
 		})
 	}
 }
+
+func TestHasUpgradePolicyDifferences(t *testing.T) {
+	testcases := []struct {
+		name     string
+		existing *compute.UpgradePolicy
+		desired  *compute.UpgradePolicy
+		expected bool
+	}{
+		{
+			name:     "flex vmss without upgrade policy",
+			existing: nil,
+			desired:  nil,
+			expected: false,
+		},
+		{
+			name:     "same mode",
+			existing: &compute.UpgradePolicy{Mode: compute.UpgradeModeManual},
+			desired:  &compute.UpgradePolicy{Mode: compute.UpgradeModeManual},
+			expected: false,
+		},
+		{
+			name:     "different mode",
+			existing: &compute.UpgradePolicy{Mode: compute.UpgradeModeManual},
+			desired:  &compute.UpgradePolicy{Mode: compute.UpgradeModeAutomatic},
+			expected: true,
+		},
+		{
+			name:     "automatic OS upgrade enabled",
+			existing: &compute.UpgradePolicy{Mode: compute.UpgradeModeRolling, AutomaticOSUpgradePolicy: &compute.AutomaticOSUpgradePolicy{EnableAutomaticOSUpgrade: ptr.To(false)}},
+			desired:  &compute.UpgradePolicy{Mode: compute.UpgradeModeRolling, AutomaticOSUpgradePolicy: &compute.AutomaticOSUpgradePolicy{EnableAutomaticOSUpgrade: ptr.To(true)}},
+			expected: true,
+		},
+		{
+			name:     "rolling upgrade policy defaulted by Azure",
+			existing: &compute.UpgradePolicy{Mode: compute.UpgradeModeRolling, RollingUpgradePolicy: &compute.RollingUpgradePolicy{MaxBatchInstancePercent: ptr.To[int32](20), PauseTimeBetweenBatches: ptr.To("PT0S")}},
+			desired:  &compute.UpgradePolicy{Mode: compute.UpgradeModeRolling},
+			expected: false,
+		},
+		{
+			name:     "same pause time",
+			existing: &compute.UpgradePolicy{Mode: compute.UpgradeModeRolling, RollingUpgradePolicy: &compute.RollingUpgradePolicy{MaxBatchInstancePercent: ptr.To[int32](20), PauseTimeBetweenBatches: ptr.To("PT1M30S")}},
+			desired:  &compute.UpgradePolicy{Mode: compute.UpgradeModeRolling, RollingUpgradePolicy: &compute.RollingUpgradePolicy{PauseTimeBetweenBatches: ptr.To("PT1M30S")}},
+			expected: false,
+		},
+		{
+			name:     "different max batch instance percent",
+			existing: &compute.UpgradePolicy{Mode: compute.UpgradeModeRolling, RollingUpgradePolicy: &compute.RollingUpgradePolicy{MaxBatchInstancePercent: ptr.To[int32](20)}},
+			desired:  &compute.UpgradePolicy{Mode: compute.UpgradeModeRolling, RollingUpgradePolicy: &compute.RollingUpgradePolicy{MaxBatchInstancePercent: ptr.To[int32](50)}},
+			expected: true,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(hasUpgradePolicyDifferences(tc.existing, tc.desired)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestHasAutomaticRepairsPolicyDifferences(t *testing.T) {
+	testcases := []struct {
+		name     string
+		existing *compute.AutomaticRepairsPolicy
+		desired  *compute.AutomaticRepairsPolicy
+		expected bool
+	}{
+		{
+			name:     "automatic repairs disabled",
+			existing: &compute.AutomaticRepairsPolicy{Enabled: ptr.To(false), GracePeriod: ptr.To("PT10M")},
+			desired:  nil,
+			expected: false,
+		},
+		{
+			name:     "automatic repairs enabled",
+			existing: nil,
+			desired:  &compute.AutomaticRepairsPolicy{Enabled: ptr.To(true)},
+			expected: true,
+		},
+		{
+			name:     "grace period defaulted by Azure",
+			existing: &compute.AutomaticRepairsPolicy{Enabled: ptr.To(true), GracePeriod: ptr.To("PT10M")},
+			desired:  &compute.AutomaticRepairsPolicy{Enabled: ptr.To(true)},
+			expected: false,
+		},
+		{
+			name:     "different grace period",
+			existing: &compute.AutomaticRepairsPolicy{Enabled: ptr.To(true), GracePeriod: ptr.To("PT10M")},
+			desired:  &compute.AutomaticRepairsPolicy{Enabled: ptr.To(true), GracePeriod: ptr.To("PT30M")},
+			expected: true,
+		},
+		{
+			name:     "automatic repairs disabled explicitly",
+			existing: &compute.AutomaticRepairsPolicy{Enabled: ptr.To(true), GracePeriod: ptr.To("PT10M")},
+			desired:  &compute.AutomaticRepairsPolicy{Enabled: ptr.To(false)},
+			expected: true,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(hasAutomaticRepairsPolicyDifferences(tc.existing, tc.desired)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestHasSpotRestorePolicyDifferences(t *testing.T) {
+	testcases := []struct {
+		name     string
+		existing *compute.SpotRestorePolicy
+		desired  *compute.SpotRestorePolicy
+		expected bool
+	}{
+		{
+			name:     "Spot restore disabled",
+			existing: &compute.SpotRestorePolicy{Enabled: ptr.To(false), RestoreTimeout: ptr.To("PT1H")},
+			desired:  nil,
+			expected: false,
+		},
+		{
+			name:     "Spot restore enabled",
+			existing: nil,
+			desired:  &compute.SpotRestorePolicy{Enabled: ptr.To(true)},
+			expected: true,
+		},
+		{
+			name:     "restore timeout defaulted by Azure",
+			existing: &compute.SpotRestorePolicy{Enabled: ptr.To(true), RestoreTimeout: ptr.To("PT1H")},
+			desired:  &compute.SpotRestorePolicy{Enabled: ptr.To(true)},
+			expected: false,
+		},
+		{
+			name:     "different restore timeout",
+			existing: &compute.SpotRestorePolicy{Enabled: ptr.To(true), RestoreTimeout: ptr.To("PT1H")},
+			desired:  &compute.SpotRestorePolicy{Enabled: ptr.To(true), RestoreTimeout: ptr.To("PT2H")},
+			expected: true,
+		},
+		{
+			name:     "Spot restore disabled explicitly",
+			existing: &compute.SpotRestorePolicy{Enabled: ptr.To(true), RestoreTimeout: ptr.To("PT1H")},
+			desired:  &compute.SpotRestorePolicy{Enabled: ptr.To(false)},
+			expected: true,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(hasSpotRestorePolicyDifferences(tc.existing, tc.desired)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestHasDiagnosticsProfileDifferences(t *testing.T) {
+	managed := &compute.DiagnosticsProfile{BootDiagnostics: &compute.BootDiagnostics{Enabled: ptr.To(true)}}
+	userManaged := &compute.DiagnosticsProfile{BootDiagnostics: &compute.BootDiagnostics{
+		Enabled:    ptr.To(true),
+		StorageURI: ptr.To("https://teamadiag.blob.core.windows.net/"),
+	}}
+	testcases := []struct {
+		name     string
+		existing *compute.DiagnosticsProfile
+		desired  *compute.DiagnosticsProfile
+		expected bool
+	}{
+		{
+			name:     "diagnostics left to Azure",
+			existing: managed,
+			desired:  nil,
+			expected: false,
+		},
+		{
+			name:     "same diagnostics storage",
+			existing: userManaged,
+			desired: &compute.DiagnosticsProfile{BootDiagnostics: &compute.BootDiagnostics{
+				Enabled:    ptr.To(true),
+				StorageURI: ptr.To("https://TeamADiag.blob.core.windows.net/"),
+			}},
+			expected: false,
+		},
+		{
+			name:     "diagnostics moved to a user managed storage account",
+			existing: managed,
+			desired:  userManaged,
+			expected: true,
+		},
+		{
+			name:     "diagnostics disabled",
+			existing: managed,
+			desired:  &compute.DiagnosticsProfile{BootDiagnostics: &compute.BootDiagnostics{Enabled: ptr.To(false)}},
+			expected: true,
+		},
+		{
+			name:     "diagnostics enabled on a scale set without diagnostics",
+			existing: nil,
+			desired:  managed,
+			expected: true,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(hasDiagnosticsProfileDifferences(tc.existing, tc.desired)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestHasScaleInPolicyDifferences(t *testing.T) {
+	testcases := []struct {
+		name     string
+		existing *compute.ScaleInPolicy
+		desired  *compute.ScaleInPolicy
+		expected bool
+	}{
+		{
+			name:     "no desired scale-in policy",
+			existing: &compute.ScaleInPolicy{Rules: &[]compute.VirtualMachineScaleSetScaleInRules{compute.VirtualMachineScaleSetScaleInRulesNewestVM}},
+			desired:  nil,
+			expected: false,
+		},
+		{
+			name:     "default scale-in policy without an existing one",
+			existing: nil,
+			desired:  defaultScaleInPolicy(),
+			expected: false,
+		},
+		{
+			name:     "same rule",
+			existing: &compute.ScaleInPolicy{Rules: &[]compute.VirtualMachineScaleSetScaleInRules{compute.VirtualMachineScaleSetScaleInRulesOldestVM}, ForceDeletion: ptr.To(false)},
+			desired:  &compute.ScaleInPolicy{Rules: &[]compute.VirtualMachineScaleSetScaleInRules{compute.VirtualMachineScaleSetScaleInRulesOldestVM}, ForceDeletion: ptr.To(false)},
+			expected: false,
+		},
+		{
+			name:     "different rule",
+			existing: &compute.ScaleInPolicy{Rules: &[]compute.VirtualMachineScaleSetScaleInRules{compute.VirtualMachineScaleSetScaleInRulesDefault}},
+			desired:  &compute.ScaleInPolicy{Rules: &[]compute.VirtualMachineScaleSetScaleInRules{compute.VirtualMachineScaleSetScaleInRulesOldestVM}, ForceDeletion: ptr.To(false)},
+			expected: true,
+		},
+		{
+			name:     "force deletion enabled",
+			existing: defaultScaleInPolicy(),
+			desired:  &compute.ScaleInPolicy{Rules: &[]compute.VirtualMachineScaleSetScaleInRules{compute.VirtualMachineScaleSetScaleInRulesDefault}, ForceDeletion: ptr.To(true)},
+			expected: true,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(hasScaleInPolicyDifferences(tc.existing, tc.desired)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestISO8601Duration(t *testing.T) {
+	testcases := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{duration: 0, expected: "PT0S"},
+		{duration: 30 * time.Second, expected: "PT30S"},
+		{duration: 5 * time.Minute, expected: "PT5M"},
+		{duration: 90 * time.Minute, expected: "PT1H30M"},
+		{duration: time.Hour + 2*time.Second, expected: "PT1H2S"},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.expected, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(iso8601Duration(tc.duration)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestScaleSetCapacityWithPinnedInstances(t *testing.T) {
+	pinnedInstances := []PinnedInstance{{Name: "a", Zone: "1"}, {Name: "b", Zone: "2"}}
+	testcases := []struct {
+		name             string
+		capacity         int64
+		pinnedInstances  []PinnedInstance
+		vmssInstances    []compute.VirtualMachineScaleSetVM
+		expectedCapacity int64
+		expectedPinnedVM []string
+	}{
+		{
+			name:             "no pinned instances",
+			capacity:         3,
+			expectedCapacity: 3,
+		},
+		{
+			name:             "pinned instances not created yet",
+			capacity:         3,
+			pinnedInstances:  pinnedInstances,
+			expectedCapacity: 1,
+			expectedPinnedVM: []string{"my-vmss-a", "my-vmss-b"},
+		},
+		{
+			name:            "one pinned instance created",
+			capacity:        3,
+			pinnedInstances: pinnedInstances,
+			vmssInstances: []compute.VirtualMachineScaleSetVM{
+				{Name: ptr.To("my-vmss-a")},
+				{Name: ptr.To("my-vmss_0123abcd")},
+			},
+			expectedCapacity: 2,
+			expectedPinnedVM: []string{"my-vmss-a", "my-vmss-b"},
+		},
+		{
+			name:            "all pinned instances created",
+			capacity:        2,
+			pinnedInstances: pinnedInstances,
+			vmssInstances: []compute.VirtualMachineScaleSetVM{
+				{Name: ptr.To("my-vmss-a")},
+				{Name: ptr.To("my-vmss-b")},
+			},
+			expectedCapacity: 2,
+			expectedPinnedVM: []string{"my-vmss-a", "my-vmss-b"},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			spec := &ScaleSetSpec{
+				Name:            "my-vmss",
+				Capacity:        tc.capacity,
+				PinnedInstances: tc.pinnedInstances,
+				VMSSInstances:   tc.vmssInstances,
+			}
+			g.Expect(spec.capacity()).To(Equal(tc.expectedCapacity))
+			var names []string
+			for _, instance := range spec.PinnedInstances {
+				names = append(names, spec.pinnedVMName(instance))
+			}
+			g.Expect(names).To(Equal(tc.expectedPinnedVM))
+		})
+	}
+}
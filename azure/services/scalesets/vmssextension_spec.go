@@ -61,11 +61,13 @@ func (s *VMSSExtensionSpec) Parameters(ctx context.Context, existing interface{}
 	return compute.VirtualMachineScaleSetExtension{
 		Name: ptr.To(s.Name),
 		VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
-			Publisher:          ptr.To(s.Publisher),
-			Type:               ptr.To(s.Name),
-			TypeHandlerVersion: ptr.To(s.Version),
-			Settings:           s.Settings,
-			ProtectedSettings:  s.ProtectedSettings,
+			Publisher:               ptr.To(s.Publisher),
+			Type:                    ptr.To(s.Name),
+			TypeHandlerVersion:      ptr.To(s.Version),
+			AutoUpgradeMinorVersion: s.AutoUpgradeMinorVersion,
+			EnableAutomaticUpgrade:  s.EnableAutomaticUpgrade,
+			Settings:                s.Settings,
+			ProtectedSettings:       s.ProtectedSettings,
 		},
 	}, nil
 }
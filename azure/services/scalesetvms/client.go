@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/pkg/errors"
 	"k8s.io/utils/ptr"
@@ -37,12 +38,19 @@ type client interface {
 	Get(context.Context, string, string, string) (compute.VirtualMachineScaleSetVM, error)
 	GetResultIfDone(ctx context.Context, future *infrav1.Future) (compute.VirtualMachineScaleSetVM, error)
 	DeleteAsync(context.Context, string, string, string) (*infrav1.Future, error)
+	UpdateInstancesAsync(context.Context, string, string, string) (*infrav1.Future, error)
+	UpdateProtectionPolicyAsync(context.Context, string, string, string, compute.VirtualMachineScaleSetVMProtectionPolicy) (*infrav1.Future, error)
+	ListNetworkInterfaces(context.Context, string, string, string) ([]network.Interface, error)
+	ListPublicIPAddresses(context.Context, string, string, string, string, string) ([]network.PublicIPAddress, error)
 }
 
 type (
 	// azureClient contains the Azure go-sdk Client.
 	azureClient struct {
 		scalesetvms compute.VirtualMachineScaleSetVMsClient
+		scalesets   compute.VirtualMachineScaleSetsClient
+		interfaces  network.InterfacesClient
+		publicIPs   network.PublicIPAddressesClient
 	}
 
 	genericScaleSetVMFuture interface {
@@ -53,6 +61,15 @@ type (
 	deleteFutureAdapter struct {
 		compute.VirtualMachineScaleSetVMsDeleteFuture
 	}
+
+	updateInstancesFutureAdapter struct {
+		compute.VirtualMachineScaleSetsUpdateInstancesFuture
+		scalesets compute.VirtualMachineScaleSetsClient
+	}
+
+	updateFutureAdapter struct {
+		compute.VirtualMachineScaleSetVMsUpdateFuture
+	}
 )
 
 var _ client = &azureClient{}
@@ -61,6 +78,9 @@ var _ client = &azureClient{}
 func newClient(auth azure.Authorizer) *azureClient {
 	return &azureClient{
 		scalesetvms: newVirtualMachineScaleSetVMsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		scalesets:   newVirtualMachineScaleSetsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		interfaces:  newInterfacesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		publicIPs:   newPublicIPAddressesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
 	}
 }
 
@@ -73,12 +93,37 @@ func newVirtualMachineScaleSetVMsClient(subscriptionID string, baseURI string, a
 	return c
 }
 
-// Get retrieves the Virtual Machine Scale Set Virtual Machine.
+// newVirtualMachineScaleSetsClient creates a new vmss client from subscription ID.
+func newVirtualMachineScaleSetsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachineScaleSetsClient {
+	c := compute.NewVirtualMachineScaleSetsClientWithBaseURI(baseURI, subscriptionID)
+	c.Authorizer = authorizer
+	c.RetryAttempts = 1
+	_ = c.AddToUserAgent(azure.UserAgent()) // intentionally ignore error as it doesn't matter
+	return c
+}
+
+// newInterfacesClient creates a new network interfaces client from subscription ID.
+func newInterfacesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.InterfacesClient {
+	c := network.NewInterfacesClientWithBaseURI(baseURI, subscriptionID)
+	c.Authorizer = authorizer
+	_ = c.AddToUserAgent(azure.UserAgent()) // intentionally ignore error as it doesn't matter
+	return c
+}
+
+// newPublicIPAddressesClient creates a new public IP addresses client from subscription ID.
+func newPublicIPAddressesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.PublicIPAddressesClient {
+	c := network.NewPublicIPAddressesClientWithBaseURI(baseURI, subscriptionID)
+	c.Authorizer = authorizer
+	_ = c.AddToUserAgent(azure.UserAgent()) // intentionally ignore error as it doesn't matter
+	return c
+}
+
+// Get retrieves the Virtual Machine Scale Set Virtual Machine along with its instance view.
 func (ac *azureClient) Get(ctx context.Context, resourceGroupName, vmssName, instanceID string) (compute.VirtualMachineScaleSetVM, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.Get")
 	defer done()
 
-	return ac.scalesetvms.Get(ctx, resourceGroupName, vmssName, instanceID, "")
+	return ac.scalesetvms.Get(ctx, resourceGroupName, vmssName, instanceID, compute.InstanceViewTypesInstanceView)
 }
 
 // GetResultIfDone fetches the result of a long-running operation future if it is done.
@@ -102,6 +147,25 @@ func (ac *azureClient) GetResultIfDone(ctx context.Context, future *infrav1.Futu
 		genericFuture = &deleteFutureAdapter{
 			VirtualMachineScaleSetVMsDeleteFuture: future,
 		}
+	case infrav1.PutFuture:
+		var future compute.VirtualMachineScaleSetsUpdateInstancesFuture
+		if err := json.Unmarshal(futureData, &future); err != nil {
+			return compute.VirtualMachineScaleSetVM{}, errors.Wrap(err, "failed to unmarshal future data")
+		}
+
+		genericFuture = &updateInstancesFutureAdapter{
+			VirtualMachineScaleSetsUpdateInstancesFuture: future,
+			scalesets: ac.scalesets,
+		}
+	case infrav1.PatchFuture:
+		var future compute.VirtualMachineScaleSetVMsUpdateFuture
+		if err := json.Unmarshal(futureData, &future); err != nil {
+			return compute.VirtualMachineScaleSetVM{}, errors.Wrap(err, "failed to unmarshal future data")
+		}
+
+		genericFuture = &updateFutureAdapter{
+			VirtualMachineScaleSetVMsUpdateFuture: future,
+		}
 	default:
 		return compute.VirtualMachineScaleSetVM{}, errors.Errorf("unknown future type %q", future.Type)
 	}
@@ -123,6 +187,47 @@ func (ac *azureClient) GetResultIfDone(ctx context.Context, future *infrav1.Futu
 	return vm, nil
 }
 
+// ListNetworkInterfaces returns the network interfaces of a virtual machine scale set instance.
+func (ac *azureClient) ListNetworkInterfaces(ctx context.Context, resourceGroupName, vmssName, instanceID string) ([]network.Interface, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.ListNetworkInterfaces")
+	defer done()
+
+	iter, err := ac.interfaces.ListVirtualMachineScaleSetVMNetworkInterfacesComplete(ctx, resourceGroupName, vmssName, instanceID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed listing the network interfaces of instance %q of vmss named %q", instanceID, vmssName)
+	}
+
+	var nics []network.Interface
+	for iter.NotDone() {
+		nics = append(nics, iter.Value())
+		if err := iter.NextWithContext(ctx); err != nil {
+			return nil, errors.Wrap(err, "failed to iterate network interfaces")
+		}
+	}
+	return nics, nil
+}
+
+// ListPublicIPAddresses returns the public IP addresses of an IP configuration of a network interface of a virtual
+// machine scale set instance.
+func (ac *azureClient) ListPublicIPAddresses(ctx context.Context, resourceGroupName, vmssName, instanceID, nicName, ipConfigName string) ([]network.PublicIPAddress, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.ListPublicIPAddresses")
+	defer done()
+
+	iter, err := ac.publicIPs.ListVirtualMachineScaleSetVMPublicIPAddressesComplete(ctx, resourceGroupName, vmssName, instanceID, nicName, ipConfigName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed listing the public IP addresses of instance %q of vmss named %q", instanceID, vmssName)
+	}
+
+	var publicIPs []network.PublicIPAddress
+	for iter.NotDone() {
+		publicIPs = append(publicIPs, iter.Value())
+		if err := iter.NextWithContext(ctx); err != nil {
+			return nil, errors.Wrap(err, "failed to iterate public IP addresses")
+		}
+	}
+	return publicIPs, nil
+}
+
 // DeleteAsync is the operation to delete a virtual machine scale set instance asynchronously. DeleteAsync sends a DELETE
 // request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
 // progress of the operation.
@@ -150,3 +255,66 @@ func (da *deleteFutureAdapter) Result(client compute.VirtualMachineScaleSetVMsCl
 	_, err := da.VirtualMachineScaleSetVMsDeleteFuture.Result(client)
 	return compute.VirtualMachineScaleSetVM{}, err
 }
+
+// UpdateInstancesAsync is the operation to update a virtual machine scale set instance to the latest model of the
+// scale set asynchronously. If the image of the model changed, the OS disk of the instance is reimaged in place,
+// keeping its name and network interfaces. UpdateInstancesAsync sends a POST request to Azure and if accepted without
+// error, the func will return a Future which can be used to track the ongoing progress of the operation.
+//
+// Parameters:
+//
+//	resourceGroupName - the name of the resource group.
+//	vmssName - the name of the VM scale set.
+//	instanceID - the ID of the VM scale set VM.
+func (ac *azureClient) UpdateInstancesAsync(ctx context.Context, resourceGroupName, vmssName, instanceID string) (*infrav1.Future, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.UpdateInstancesAsync")
+	defer done()
+
+	future, err := ac.scalesets.UpdateInstances(ctx, resourceGroupName, vmssName, compute.VirtualMachineScaleSetVMInstanceRequiredIDs{
+		InstanceIds: &[]string{instanceID},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed updating instance %q of vmss named %q", instanceID, vmssName)
+	}
+
+	return converters.SDKToFuture(&future, infrav1.PutFuture, serviceName, instanceID, resourceGroupName)
+}
+
+// Result wraps the update instances result so that we can treat it generically. The only thing we care about is if
+// the update was successful. If it wasn't, an error will be returned.
+func (ua *updateInstancesFutureAdapter) Result(client compute.VirtualMachineScaleSetVMsClient) (compute.VirtualMachineScaleSetVM, error) {
+	_, err := ua.VirtualMachineScaleSetsUpdateInstancesFuture.Result(ua.scalesets)
+	return compute.VirtualMachineScaleSetVM{}, err
+}
+
+// UpdateProtectionPolicyAsync is the operation to set the protection policy of a virtual machine scale set instance
+// asynchronously. Only the protection policy is sent, so the rest of the instance is left as it is.
+// UpdateProtectionPolicyAsync sends a PUT request to Azure and if accepted without error, the func will return a
+// Future which can be used to track the ongoing progress of the operation.
+//
+// Parameters:
+//
+//	resourceGroupName - the name of the resource group.
+//	vmssName - the name of the VM scale set.
+//	instanceID - the ID of the VM scale set VM.
+//	policy - the protection policy of the VM scale set VM.
+func (ac *azureClient) UpdateProtectionPolicyAsync(ctx context.Context, resourceGroupName, vmssName, instanceID string, policy compute.VirtualMachineScaleSetVMProtectionPolicy) (*infrav1.Future, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.UpdateProtectionPolicyAsync")
+	defer done()
+
+	future, err := ac.scalesetvms.Update(ctx, resourceGroupName, vmssName, instanceID, compute.VirtualMachineScaleSetVM{
+		VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+			ProtectionPolicy: &policy,
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed updating the protection policy of instance %q of vmss named %q", instanceID, vmssName)
+	}
+
+	return converters.SDKToFuture(&future, infrav1.PatchFuture, serviceName, instanceID, resourceGroupName)
+}
+
+// Result wraps the update result so that we can treat it generically.
+func (ua *updateFutureAdapter) Result(client compute.VirtualMachineScaleSetVMsClient) (compute.VirtualMachineScaleSetVM, error) {
+	return ua.VirtualMachineScaleSetVMsUpdateFuture.Result(client)
+}
@@ -25,6 +25,7 @@ import (
 	reflect "reflect"
 
 	compute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	network "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
 	autorest "github.com/Azure/go-autorest/autorest"
 	gomock "go.uber.org/mock/gomock"
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -98,6 +99,36 @@ func (mr *MockclientMockRecorder) GetResultIfDone(ctx, future interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResultIfDone", reflect.TypeOf((*Mockclient)(nil).GetResultIfDone), ctx, future)
 }
 
+// ListNetworkInterfaces mocks base method.
+func (m *Mockclient) ListNetworkInterfaces(arg0 context.Context, arg1, arg2, arg3 string) ([]network.Interface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNetworkInterfaces", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]network.Interface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNetworkInterfaces indicates an expected call of ListNetworkInterfaces.
+func (mr *MockclientMockRecorder) ListNetworkInterfaces(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNetworkInterfaces", reflect.TypeOf((*Mockclient)(nil).ListNetworkInterfaces), arg0, arg1, arg2, arg3)
+}
+
+// ListPublicIPAddresses mocks base method.
+func (m *Mockclient) ListPublicIPAddresses(arg0 context.Context, arg1, arg2, arg3, arg4, arg5 string) ([]network.PublicIPAddress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPublicIPAddresses", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].([]network.PublicIPAddress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPublicIPAddresses indicates an expected call of ListPublicIPAddresses.
+func (mr *MockclientMockRecorder) ListPublicIPAddresses(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPublicIPAddresses", reflect.TypeOf((*Mockclient)(nil).ListPublicIPAddresses), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
 // MockgenericScaleSetVMFuture is a mock of genericScaleSetVMFuture interface.
 type MockgenericScaleSetVMFuture struct {
 	ctrl     *gomock.Controller
@@ -150,3 +181,33 @@ func (mr *MockgenericScaleSetVMFutureMockRecorder) Result(client interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Result", reflect.TypeOf((*MockgenericScaleSetVMFuture)(nil).Result), client)
 }
+
+// UpdateInstancesAsync mocks base method.
+func (m *Mockclient) UpdateInstancesAsync(arg0 context.Context, arg1, arg2, arg3 string) (*v1beta1.Future, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInstancesAsync", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*v1beta1.Future)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateInstancesAsync indicates an expected call of UpdateInstancesAsync.
+func (mr *MockclientMockRecorder) UpdateInstancesAsync(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInstancesAsync", reflect.TypeOf((*Mockclient)(nil).UpdateInstancesAsync), arg0, arg1, arg2, arg3)
+}
+
+// UpdateProtectionPolicyAsync mocks base method.
+func (m *Mockclient) UpdateProtectionPolicyAsync(arg0 context.Context, arg1, arg2, arg3 string, arg4 compute.VirtualMachineScaleSetVMProtectionPolicy) (*v1beta1.Future, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProtectionPolicyAsync", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*v1beta1.Future)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProtectionPolicyAsync indicates an expected call of UpdateProtectionPolicyAsync.
+func (mr *MockclientMockRecorder) UpdateProtectionPolicyAsync(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProtectionPolicyAsync", reflect.TypeOf((*Mockclient)(nil).UpdateProtectionPolicyAsync), arg0, arg1, arg2, arg3, arg4)
+}
@@ -318,6 +318,34 @@ func (mr *MockScaleSetVMScopeMockRecorder) OrchestrationMode() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrchestrationMode", reflect.TypeOf((*MockScaleSetVMScope)(nil).OrchestrationMode))
 }
 
+// ProtectFromScaleIn mocks base method.
+func (m *MockScaleSetVMScope) ProtectFromScaleIn() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProtectFromScaleIn")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ProtectFromScaleIn indicates an expected call of ProtectFromScaleIn.
+func (mr *MockScaleSetVMScopeMockRecorder) ProtectFromScaleIn() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProtectFromScaleIn", reflect.TypeOf((*MockScaleSetVMScope)(nil).ProtectFromScaleIn))
+}
+
+// ProtectFromScaleSetActions mocks base method.
+func (m *MockScaleSetVMScope) ProtectFromScaleSetActions() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProtectFromScaleSetActions")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ProtectFromScaleSetActions indicates an expected call of ProtectFromScaleSetActions.
+func (mr *MockScaleSetVMScopeMockRecorder) ProtectFromScaleSetActions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProtectFromScaleSetActions", reflect.TypeOf((*MockScaleSetVMScope)(nil).ProtectFromScaleSetActions))
+}
+
 // ProviderID mocks base method.
 func (m *MockScaleSetVMScope) ProviderID() string {
 	m.ctrl.T.Helper()
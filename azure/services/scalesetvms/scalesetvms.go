@@ -20,20 +20,45 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 const serviceName = "scalesetvms"
 
+// addressesCacheTTL is how long the addresses of an instance are cached. Addresses are cached per set of network
+// interfaces of the instance, so the TTL only bounds how long a changed IP address goes unnoticed.
+const addressesCacheTTL = 5 * time.Minute
+
+var (
+	addressesCacheOnce sync.Once
+	addressesCache     ttllru.Cacher
+	addressesCacheErr  error
+)
+
+// addressesKey identifies the addresses of an instance with a given set of network interfaces.
+type addressesKey struct {
+	instance            string
+	networkInterfaceIDs string
+}
+
 type (
 	// ScaleSetVMScope defines the scope interface for a scale sets service.
 	ScaleSetVMScope interface {
@@ -43,24 +68,44 @@ type (
 		ProviderID() string
 		ScaleSetName() string
 		OrchestrationMode() infrav1.OrchestrationModeType
+		ProtectFromScaleIn() bool
+		ProtectFromScaleSetActions() bool
 		SetVMSSVM(vmssvm *azure.VMSSVM)
 	}
 
 	// Service provides operations on Azure resources.
 	Service struct {
-		Client   client
-		VMClient virtualmachines.Client
-		Scope    ScaleSetVMScope
+		Client           client
+		VMClient         virtualmachines.Client
+		InterfacesGetter async.Getter
+		PublicIPsGetter  async.Getter
+		Scope            ScaleSetVMScope
+		// AddressesCache caches the addresses of instances across reconciles. Addresses aren't cached when it is nil.
+		AddressesCache ttllru.Cacher
 	}
 )
 
 // NewService creates a new service.
 func NewService(scope ScaleSetVMScope) *Service {
 	return &Service{
-		Client:   newClient(scope),
-		VMClient: virtualmachines.NewClient(scope),
-		Scope:    scope,
+		Client:           newClient(scope),
+		VMClient:         virtualmachines.NewClient(scope),
+		InterfacesGetter: networkinterfaces.NewClient(scope),
+		PublicIPsGetter:  publicips.NewClient(scope),
+		Scope:            scope,
+		AddressesCache:   getAddressesCache(),
+	}
+}
+
+// getAddressesCache returns the cache of instance addresses shared by the services, or nil if it couldn't be created.
+func getAddressesCache() ttllru.Cacher {
+	addressesCacheOnce.Do(func() {
+		addressesCache, addressesCacheErr = ttllru.New(4096, addressesCacheTTL)
+	})
+	if addressesCacheErr != nil {
+		return nil
 	}
+	return addressesCache
 }
 
 // Name returns the service name.
@@ -93,7 +138,17 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			}
 			return errors.Wrap(err, "failed getting vm")
 		}
-		s.Scope.SetVMSSVM(converters.SDKVMToVMSSVM(vm, infrav1.FlexibleOrchestrationMode))
+		vmssvm := converters.SDKVMToVMSSVM(vm, infrav1.FlexibleOrchestrationMode)
+		if vm.VirtualMachineProperties != nil {
+			vmssvm.NetworkInterfaceIDs = networkInterfaceIDs(vm.NetworkProfile)
+		}
+		vmssvm.Addresses = s.cachedAddresses(ctx, resourceID, vmssvm.NetworkInterfaceIDs, func(ctx context.Context) ([]corev1.NodeAddress, error) {
+			return s.getVMAddresses(ctx, vmssvm.NetworkInterfaceIDs)
+		})
+		s.Scope.SetVMSSVM(vmssvm)
+		if s.Scope.ProtectFromScaleIn() || s.Scope.ProtectFromScaleSetActions() {
+			log.Info("ignoring the protection policy since instance protection is not supported for Flexible orchestration mode")
+		}
 		return nil
 	}
 
@@ -107,10 +162,214 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		return errors.Wrap(err, "failed getting instance")
 	}
 
-	s.Scope.SetVMSSVM(converters.SDKToVMSSVM(instance))
+	vmssvm := converters.SDKToVMSSVM(instance)
+	if instance.VirtualMachineScaleSetVMProperties != nil {
+		vmssvm.NetworkInterfaceIDs = networkInterfaceIDs(instance.NetworkProfile)
+	}
+	vmssvm.Addresses = s.cachedAddresses(ctx, strings.Join([]string{resourceGroup, vmssName, instanceID}, "/"), vmssvm.NetworkInterfaceIDs,
+		func(ctx context.Context) ([]corev1.NodeAddress, error) {
+			return s.getInstanceAddresses(ctx, resourceGroup, vmssName, instanceID)
+		})
+	s.Scope.SetVMSSVM(vmssvm)
+	return s.reconcileProtectionPolicy(ctx, resourceGroup, vmssName, instanceID, instance)
+}
+
+// networkInterfaceIDs returns the IDs of the network interfaces in the network profile of an instance or VM.
+func networkInterfaceIDs(profile *compute.NetworkProfile) []string {
+	if profile == nil || profile.NetworkInterfaces == nil {
+		return nil
+	}
+	var nicIDs []string
+	for _, nicRef := range *profile.NetworkInterfaces {
+		if nicRef.ID != nil {
+			nicIDs = append(nicIDs, *nicRef.ID)
+		}
+	}
+	return nicIDs
+}
+
+// cachedAddresses returns the IP addresses of an instance with the given network interfaces, looking them up if they
+// aren't cached. Failing to look them up doesn't fail the reconcile: it is logged, and nil is returned so that the last
+// known addresses are kept.
+func (s *Service) cachedAddresses(ctx context.Context, instance string, nicIDs []string, lookup func(context.Context) ([]corev1.NodeAddress, error)) []corev1.NodeAddress {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scalesetvms.Service.cachedAddresses")
+	defer done()
+
+	if len(nicIDs) == 0 {
+		return nil
+	}
+
+	key := addressesKey{instance: instance, networkInterfaceIDs: strings.Join(nicIDs, ",")}
+	if s.AddressesCache != nil {
+		if cached, ok := s.AddressesCache.Get(key); ok {
+			return cached.([]corev1.NodeAddress)
+		}
+	}
+
+	addresses, err := lookup(ctx)
+	if err != nil {
+		log.Error(err, "failed to get the addresses of the instance, keeping the last known ones", "instance", instance)
+		return nil
+	}
+	if s.AddressesCache != nil {
+		_ = s.AddressesCache.Add(key, addresses)
+	}
+	return addresses
+}
+
+// getInstanceAddresses returns the IP addresses of a Uniform scale set instance. The network interfaces and public IP
+// addresses of Uniform instances are only reachable through the scale set.
+func (s *Service) getInstanceAddresses(ctx context.Context, resourceGroup, vmssName, instanceID string) ([]corev1.NodeAddress, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.Service.getInstanceAddresses")
+	defer done()
+
+	nics, err := s.Client.ListNetworkInterfaces(ctx, resourceGroup, vmssName, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := []corev1.NodeAddress{}
+	for _, nic := range nics {
+		for _, ipConfig := range ipConfigurations(nic) {
+			if ipConfig.PrivateIPAddress != nil {
+				addresses = append(addresses, corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: *ipConfig.PrivateIPAddress})
+			}
+			if ipConfig.PublicIPAddress == nil {
+				continue
+			}
+			publicIPs, err := s.Client.ListPublicIPAddresses(ctx, resourceGroup, vmssName, instanceID, ptr.Deref(nic.Name, ""), ptr.Deref(ipConfig.Name, ""))
+			if err != nil {
+				return nil, err
+			}
+			for _, publicIP := range publicIPs {
+				if publicIP.PublicIPAddressPropertiesFormat != nil && publicIP.IPAddress != nil {
+					addresses = append(addresses, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: *publicIP.IPAddress})
+				}
+			}
+		}
+	}
+	return addresses, nil
+}
+
+// getVMAddresses returns the IP addresses of a Flexible scale set VM with the given network interfaces, whose network
+// interfaces and public IP addresses are regular resources.
+func (s *Service) getVMAddresses(ctx context.Context, nicIDs []string) ([]corev1.NodeAddress, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.Service.getVMAddresses")
+	defer done()
+
+	addresses := []corev1.NodeAddress{}
+	for _, nicID := range nicIDs {
+		parsed, err := azureutil.ParseResourceID(nicID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse network interface ID %q", nicID)
+		}
+
+		existingNic, err := s.InterfacesGetter.Get(ctx, &networkinterfaces.NICSpec{
+			Name:          parsed.Name,
+			ResourceGroup: parsed.ResourceGroupName,
+		})
+		if err != nil {
+			return nil, err
+		}
+		nic, ok := existingNic.(network.Interface)
+		if !ok {
+			return nil, errors.Errorf("%T is not a network.Interface", existingNic)
+		}
+
+		for _, ipConfig := range ipConfigurations(nic) {
+			if ipConfig.PrivateIPAddress != nil {
+				addresses = append(addresses, corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: *ipConfig.PrivateIPAddress})
+			}
+			if ipConfig.PublicIPAddress == nil || ipConfig.PublicIPAddress.ID == nil {
+				continue
+			}
+			parsed, err := azureutil.ParseResourceID(*ipConfig.PublicIPAddress.ID)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse public IP address ID %q", *ipConfig.PublicIPAddress.ID)
+			}
+			existingPublicIP, err := s.PublicIPsGetter.Get(ctx, &publicips.PublicIPSpec{
+				Name:          parsed.Name,
+				ResourceGroup: parsed.ResourceGroupName,
+			})
+			if err != nil {
+				return nil, err
+			}
+			publicIP, ok := existingPublicIP.(network.PublicIPAddress)
+			if !ok {
+				return nil, errors.Errorf("%T is not a network.PublicIPAddress", existingPublicIP)
+			}
+			if publicIP.PublicIPAddressPropertiesFormat != nil && publicIP.IPAddress != nil {
+				addresses = append(addresses, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: *publicIP.IPAddress})
+			}
+		}
+	}
+	return addresses, nicIDs, nil
+}
+
+// ipConfigurations returns the IP configurations of a network interface that have properties.
+func ipConfigurations(nic network.Interface) []network.InterfaceIPConfiguration {
+	if nic.InterfacePropertiesFormat == nil || nic.IPConfigurations == nil {
+		return nil
+	}
+	var ipConfigs []network.InterfaceIPConfiguration
+	for _, ipConfig := range *nic.IPConfigurations {
+		if ipConfig.InterfaceIPConfigurationPropertiesFormat != nil {
+			ipConfigs = append(ipConfigs, ipConfig)
+		}
+	}
+	return ipConfigs
+}
+
+// reconcileProtectionPolicy updates the protection policy of a Uniform scale set instance when it differs from the
+// desired one.
+func (s *Service) reconcileProtectionPolicy(ctx context.Context, resourceGroup, vmssName, instanceID string, instance compute.VirtualMachineScaleSetVM) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scalesetvms.Service.reconcileProtectionPolicy")
+	defer done()
+
+	policy := compute.VirtualMachineScaleSetVMProtectionPolicy{
+		ProtectFromScaleIn:         ptr.To(s.Scope.ProtectFromScaleIn()),
+		ProtectFromScaleSetActions: ptr.To(s.Scope.ProtectFromScaleSetActions()),
+	}
+
+	future := s.Scope.GetLongRunningOperationState(instanceID, serviceName, infrav1.PatchFuture)
+	if future == nil {
+		if !protectionPolicyChanged(instance, policy) {
+			return nil
+		}
+
+		// since the future was nil, there is no ongoing activity; start updating the protection policy
+		log.V(2).Info("updating the protection policy of the instance", "protectFromScaleIn", *policy.ProtectFromScaleIn, "protectFromScaleSetActions", *policy.ProtectFromScaleSetActions)
+		var err error
+		future, err = s.Client.UpdateProtectionPolicyAsync(ctx, resourceGroup, vmssName, instanceID, policy)
+		if err != nil {
+			return errors.Wrapf(err, "failed to update the protection policy of instance %s/%s", vmssName, instanceID)
+		}
+
+		s.Scope.SetLongRunningOperationState(future)
+	}
+
+	log.V(4).Info("checking if the protection policy of the instance is done updating")
+	if _, err := s.Client.GetResultIfDone(ctx, future); err != nil {
+		return errors.Wrap(err, "failed to get result of long running operation")
+	}
+
+	// there was no error in fetching the result, the future has been completed
+	log.V(4).Info("successfully updated the protection policy of the instance")
+	s.Scope.DeleteLongRunningOperationState(instanceID, serviceName, infrav1.PatchFuture)
 	return nil
 }
 
+// protectionPolicyChanged returns true if the protection policy of the instance differs from the desired one.
+// An instance without a protection policy is not protected.
+func protectionPolicyChanged(instance compute.VirtualMachineScaleSetVM, desired compute.VirtualMachineScaleSetVMProtectionPolicy) bool {
+	var current compute.VirtualMachineScaleSetVMProtectionPolicy
+	if instance.VirtualMachineScaleSetVMProperties != nil && instance.ProtectionPolicy != nil {
+		current = *instance.ProtectionPolicy
+	}
+	return ptr.Deref(current.ProtectFromScaleIn, false) != ptr.Deref(desired.ProtectFromScaleIn, false) ||
+		ptr.Deref(current.ProtectFromScaleSetActions, false) != ptr.Deref(desired.ProtectFromScaleSetActions, false)
+}
+
 // Delete deletes a scaleset instance asynchronously returning a future which encapsulates the long-running operation.
 func (s *Service) Delete(ctx context.Context) error {
 	var (
@@ -253,6 +512,59 @@ func (s *Service) deleteVMSSUniformInstance(ctx context.Context, resourceGroup s
 	return nil
 }
 
+// Reimage updates a scaleset instance to the latest model of the scale set asynchronously, reimaging it in place
+// instead of replacing it. Only Uniform scale sets are supported.
+func (s *Service) Reimage(ctx context.Context) error {
+	var (
+		resourceGroup = s.Scope.ResourceGroup()
+		vmssName      = s.Scope.ScaleSetName()
+		instanceID    = s.Scope.InstanceID()
+		isFlex        = s.Scope.OrchestrationMode() == infrav1.FlexibleOrchestrationMode
+	)
+
+	ctx, log, done := tele.StartSpanWithLogger(
+		ctx,
+		"scalesetvms.Service.Reimage",
+		tele.KVP("resourceGroup", resourceGroup),
+		tele.KVP("scaleset", vmssName),
+		tele.KVP("instanceID", instanceID),
+	)
+	defer done()
+
+	if isFlex {
+		return azure.WithTerminalError(errors.New("reimaging instances is not supported for Flexible orchestration mode"))
+	}
+
+	defer func() {
+		if instance, err := s.Client.Get(ctx, resourceGroup, vmssName, instanceID); err == nil && instance.VirtualMachineScaleSetVMProperties != nil {
+			log.V(4).Info("updating vmss vm state", "state", instance.ProvisioningState)
+			s.Scope.SetVMSSVM(converters.SDKToVMSSVM(instance))
+		}
+	}()
+
+	future := s.Scope.GetLongRunningOperationState(instanceID, serviceName, infrav1.PutFuture)
+	if future == nil {
+		// since the future was nil, there is no ongoing activity; start updating the instance
+		var err error
+		future, err = s.Client.UpdateInstancesAsync(ctx, resourceGroup, vmssName, instanceID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to reimage instance %s/%s", vmssName, instanceID)
+		}
+
+		s.Scope.SetLongRunningOperationState(future)
+	}
+
+	log.V(4).Info("checking if the instance is done reimaging")
+	if _, err := s.Client.GetResultIfDone(ctx, future); err != nil {
+		return errors.Wrap(err, "failed to get result of long running operation")
+	}
+
+	// there was no error in fetching the result, the future has been completed
+	log.V(4).Info("successfully reimaged the instance")
+	s.Scope.DeleteLongRunningOperationState(instanceID, serviceName, infrav1.PutFuture)
+	return nil
+}
+
 // VMSSFlexVMGetter gets the information required to create, update, or delete an Azure resource.
 type VMSSFlexVMGetter struct {
 	Name          string
@@ -24,10 +24,12 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
 	"github.com/Azure/go-autorest/autorest"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/ptr"
@@ -35,10 +37,14 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesetvms/mock_scalesetvms"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines/mock_virtualmachines"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	gomock2 "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -112,6 +118,174 @@ func TestService_Reconcile(t *testing.T) {
 				}
 				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(vm, nil)
 				s.SetVMSSVM(converters.SDKToVMSSVM(vm))
+				s.ProtectFromScaleIn().Return(false)
+				s.ProtectFromScaleSetActions().Return(false)
+				s.GetLongRunningOperationState("0", serviceName, infrav1.PatchFuture).Return(nil)
+			},
+		},
+		{
+			Name: "should not update the protection policy if it is unchanged",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ProviderID().Return("foo")
+				s.ScaleSetName().Return("scaleset")
+				vm := compute.VirtualMachineScaleSetVM{
+					InstanceID: ptr.To("0"),
+					VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+						ProtectionPolicy: &compute.VirtualMachineScaleSetVMProtectionPolicy{
+							ProtectFromScaleIn: ptr.To(true),
+						},
+					},
+				}
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(vm, nil)
+				s.SetVMSSVM(converters.SDKToVMSSVM(vm))
+				s.ProtectFromScaleIn().Return(true)
+				s.ProtectFromScaleSetActions().Return(false)
+				s.GetLongRunningOperationState("0", serviceName, infrav1.PatchFuture).Return(nil)
+			},
+		},
+		{
+			Name: "should start updating the protection policy if it changed",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ProviderID().Return("foo")
+				s.ScaleSetName().Return("scaleset")
+				vm := compute.VirtualMachineScaleSetVM{
+					InstanceID: ptr.To("0"),
+				}
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(vm, nil)
+				s.SetVMSSVM(converters.SDKToVMSSVM(vm))
+				s.ProtectFromScaleIn().Return(false)
+				s.ProtectFromScaleSetActions().Return(true)
+				s.GetLongRunningOperationState("0", serviceName, infrav1.PatchFuture).Return(nil)
+				future := &infrav1.Future{
+					Type: infrav1.PatchFuture,
+				}
+				m.UpdateProtectionPolicyAsync(gomock2.AContext(), "rg", "scaleset", "0", compute.VirtualMachineScaleSetVMProtectionPolicy{
+					ProtectFromScaleIn:         ptr.To(false),
+					ProtectFromScaleSetActions: ptr.To(true),
+				}).Return(future, nil)
+				s.SetLongRunningOperationState(future)
+				m.GetResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachineScaleSetVM{}, azure.WithTransientError(azure.NewOperationNotDoneError(future), 15*time.Second))
+			},
+			Err: errors.Wrap(azure.WithTransientError(azure.NewOperationNotDoneError(&infrav1.Future{
+				Type: infrav1.PatchFuture,
+			}), 15*time.Second), "failed to get result of long running operation"),
+		},
+		{
+			Name: "should finish updating the protection policy when the long running operation has completed",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ProviderID().Return("foo")
+				s.ScaleSetName().Return("scaleset")
+				vm := compute.VirtualMachineScaleSetVM{
+					InstanceID: ptr.To("0"),
+				}
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(vm, nil)
+				s.SetVMSSVM(converters.SDKToVMSSVM(vm))
+				s.ProtectFromScaleIn().Return(true)
+				s.ProtectFromScaleSetActions().Return(false)
+				future := &infrav1.Future{
+					Type: infrav1.PatchFuture,
+				}
+				s.GetLongRunningOperationState("0", serviceName, infrav1.PatchFuture).Return(future)
+				m.GetResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachineScaleSetVM{}, nil)
+				s.DeleteLongRunningOperationState("0", serviceName, infrav1.PatchFuture)
+			},
+		},
+		{
+			Name: "should error when the protection policy update fails",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ProviderID().Return("foo")
+				s.ScaleSetName().Return("scaleset")
+				vm := compute.VirtualMachineScaleSetVM{
+					InstanceID: ptr.To("0"),
+				}
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(vm, nil)
+				s.SetVMSSVM(converters.SDKToVMSSVM(vm))
+				s.ProtectFromScaleIn().Return(true)
+				s.ProtectFromScaleSetActions().Return(false)
+				s.GetLongRunningOperationState("0", serviceName, infrav1.PatchFuture).Return(nil)
+				m.UpdateProtectionPolicyAsync(gomock2.AContext(), "rg", "scaleset", "0", gomock.Any()).Return(nil, errors.New("boom"))
+			},
+			Err: errors.Wrap(errors.New("boom"), "failed to update the protection policy of instance scaleset/0"),
+		},
+		{
+			Name: "should set the addresses and network interfaces of the instance",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ProviderID().Return("foo")
+				s.ScaleSetName().Return("scaleset")
+				vm := compute.VirtualMachineScaleSetVM{
+					InstanceID: ptr.To("0"),
+					VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+						NetworkProfile: &compute.NetworkProfile{
+							NetworkInterfaces: &[]compute.NetworkInterfaceReference{{ID: ptr.To("nic-id")}},
+						},
+					},
+				}
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(vm, nil)
+				m.ListNetworkInterfaces(gomock2.AContext(), "rg", "scaleset", "0").Return([]network.Interface{
+					{
+						ID:   ptr.To("nic-id"),
+						Name: ptr.To("nic"),
+						InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+							IPConfigurations: &[]network.InterfaceIPConfiguration{
+								{
+									Name: ptr.To("ipconfig"),
+									InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
+										PrivateIPAddress: ptr.To("10.1.0.4"),
+										PublicIPAddress:  &network.PublicIPAddress{ID: ptr.To("public-ip-id")},
+									},
+								},
+							},
+						},
+					},
+				}, nil)
+				m.ListPublicIPAddresses(gomock2.AContext(), "rg", "scaleset", "0", "nic", "ipconfig").Return([]network.PublicIPAddress{
+					{PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{IPAddress: ptr.To("20.1.2.3")}},
+				}, nil)
+				vmssvm := converters.SDKToVMSSVM(vm)
+				vmssvm.Addresses = []corev1.NodeAddress{
+					{Type: corev1.NodeInternalIP, Address: "10.1.0.4"},
+					{Type: corev1.NodeExternalIP, Address: "20.1.2.3"},
+				}
+				vmssvm.NetworkInterfaceIDs = []string{"nic-id"}
+				s.SetVMSSVM(vmssvm)
+				s.ProtectFromScaleIn().Return(false)
+				s.ProtectFromScaleSetActions().Return(false)
+				s.GetLongRunningOperationState("0", serviceName, infrav1.PatchFuture).Return(nil)
+			},
+		},
+		{
+			Name: "should keep the last known addresses when the network interfaces of the instance can't be listed",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ProviderID().Return("foo")
+				s.ScaleSetName().Return("scaleset")
+				vm := compute.VirtualMachineScaleSetVM{
+					InstanceID: ptr.To("0"),
+					VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+						NetworkProfile: &compute.NetworkProfile{
+							NetworkInterfaces: &[]compute.NetworkInterfaceReference{{ID: ptr.To("nic-id")}},
+						},
+					},
+				}
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(vm, nil)
+				m.ListNetworkInterfaces(gomock2.AContext(), "rg", "scaleset", "0").Return(nil, errors.New("boom"))
+				vmssvm := converters.SDKToVMSSVM(vm)
+				vmssvm.NetworkInterfaceIDs = []string{"nic-id"}
+				s.SetVMSSVM(vmssvm)
+				s.ProtectFromScaleIn().Return(false)
+				s.ProtectFromScaleSetActions().Return(false)
+				s.GetLongRunningOperationState("0", serviceName, infrav1.PatchFuture).Return(nil)
 			},
 		},
 		{
@@ -156,6 +330,7 @@ func TestService_Reconcile(t *testing.T) {
 
 			service := NewService(scopeMock)
 			service.Client = clientMock
+			service.AddressesCache = nil
 			c.Setup(scopeMock.EXPECT(), clientMock.EXPECT())
 
 			if err := service.Reconcile(context.TODO()); c.Err == nil {
@@ -171,6 +346,83 @@ func TestService_Reconcile(t *testing.T) {
 	}
 }
 
+func TestService_GetVMAddresses(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	interfacesMock := mock_async.NewMockGetter(mockCtrl)
+	publicIPsMock := mock_async.NewMockGetter(mockCtrl)
+	service := &Service{
+		InterfacesGetter: interfacesMock,
+		PublicIPsGetter:  publicIPsMock,
+	}
+
+	nicID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/my-nic"
+	interfacesMock.EXPECT().Get(gomock2.AContext(), &networkinterfaces.NICSpec{Name: "my-nic", ResourceGroup: "my-rg"}).Return(network.Interface{
+		ID: ptr.To(nicID),
+		InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+			IPConfigurations: &[]network.InterfaceIPConfiguration{
+				{
+					InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
+						PrivateIPAddress: ptr.To("10.1.0.4"),
+						PublicIPAddress: &network.PublicIPAddress{
+							ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-public-ip"),
+						},
+					},
+				},
+				{
+					InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
+						PrivateIPAddress: ptr.To("fd00::4"),
+					},
+				},
+			},
+		},
+	}, nil)
+	publicIPsMock.EXPECT().Get(gomock2.AContext(), &publicips.PublicIPSpec{Name: "my-public-ip", ResourceGroup: "my-rg"}).Return(network.PublicIPAddress{
+		PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{IPAddress: ptr.To("20.1.2.3")},
+	}, nil)
+
+	addresses, err := service.getVMAddresses(context.TODO(), []string{nicID})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(addresses).To(Equal([]corev1.NodeAddress{
+		{Type: corev1.NodeInternalIP, Address: "10.1.0.4"},
+		{Type: corev1.NodeExternalIP, Address: "20.1.2.3"},
+		{Type: corev1.NodeInternalIP, Address: "fd00::4"},
+	}))
+}
+
+func TestService_CachedAddresses(t *testing.T) {
+	g := NewWithT(t)
+	cache, err := ttllru.New(10, time.Minute)
+	g.Expect(err).NotTo(HaveOccurred())
+	service := &Service{AddressesCache: cache}
+
+	lookups := 0
+	lookup := func(context.Context) ([]corev1.NodeAddress, error) {
+		lookups++
+		return []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "10.1.0.4"}}, nil
+	}
+	failingLookup := func(context.Context) ([]corev1.NodeAddress, error) {
+		lookups++
+		return nil, errors.New("boom")
+	}
+
+	// Instances without network interfaces have no addresses to look up.
+	g.Expect(service.cachedAddresses(context.TODO(), "rg/scaleset/0", nil, lookup)).To(BeNil())
+	g.Expect(lookups).To(Equal(0))
+
+	// The addresses are looked up once per set of network interfaces.
+	g.Expect(service.cachedAddresses(context.TODO(), "rg/scaleset/0", []string{"nic-1"}, lookup)).To(HaveLen(1))
+	g.Expect(service.cachedAddresses(context.TODO(), "rg/scaleset/0", []string{"nic-1"}, failingLookup)).To(HaveLen(1))
+	g.Expect(lookups).To(Equal(1))
+
+	// Failed lookups return nil and aren't cached.
+	g.Expect(service.cachedAddresses(context.TODO(), "rg/scaleset/0", []string{"nic-1", "nic-2"}, failingLookup)).To(BeNil())
+	g.Expect(service.cachedAddresses(context.TODO(), "rg/scaleset/0", []string{"nic-1", "nic-2"}, lookup)).To(HaveLen(1))
+	g.Expect(lookups).To(Equal(3))
+}
+
 func TestService_Delete(t *testing.T) {
 	cases := []struct {
 		Name       string
@@ -329,3 +581,98 @@ func TestService_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Reimage(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Setup func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder)
+		Err   error
+	}{
+		{
+			Name: "should start reimaging if no long running operation is active",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.UniformOrchestrationMode)
+				s.GetLongRunningOperationState("0", serviceName, infrav1.PutFuture).Return(nil)
+				future := &infrav1.Future{
+					Type: infrav1.PutFuture,
+				}
+				m.UpdateInstancesAsync(gomock2.AContext(), "rg", "scaleset", "0").Return(future, nil)
+				s.SetLongRunningOperationState(future)
+				m.GetResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachineScaleSetVM{}, azure.WithTransientError(azure.NewOperationNotDoneError(future), 15*time.Second))
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, nil)
+			},
+			Err: errors.Wrap(azure.WithTransientError(azure.NewOperationNotDoneError(&infrav1.Future{
+				Type: infrav1.PutFuture,
+			}), 15*time.Second), "failed to get result of long running operation"),
+		},
+		{
+			Name: "should finish reimaging when there's a long running operation that has completed",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.UniformOrchestrationMode)
+				future := &infrav1.Future{
+					Type: infrav1.PutFuture,
+				}
+				s.GetLongRunningOperationState("0", serviceName, infrav1.PutFuture).Return(future)
+				m.GetResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachineScaleSetVM{}, nil)
+				s.DeleteLongRunningOperationState("0", serviceName, infrav1.PutFuture)
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, nil)
+			},
+		},
+		{
+			Name: "should error when the update instances call fails",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.UniformOrchestrationMode)
+				s.GetLongRunningOperationState("0", serviceName, infrav1.PutFuture).Return(nil)
+				m.UpdateInstancesAsync(gomock2.AContext(), "rg", "scaleset", "0").Return(nil, errors.New("boom"))
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, nil)
+			},
+			Err: errors.Wrap(errors.New("boom"), "failed to reimage instance scaleset/0"),
+		},
+		{
+			Name: "(flex) should return a terminal error",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.FlexibleOrchestrationMode)
+			},
+			Err: errors.New("reimaging instances is not supported for Flexible orchestration mode"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var (
+				g          = NewWithT(t)
+				mockCtrl   = gomock.NewController(t)
+				scopeMock  = mock_scalesetvms.NewMockScaleSetVMScope(mockCtrl)
+				clientMock = mock_scalesetvms.NewMockclient(mockCtrl)
+			)
+			defer mockCtrl.Finish()
+
+			scopeMock.EXPECT().SubscriptionID().Return("subID").AnyTimes()
+			scopeMock.EXPECT().BaseURI().Return("https://localhost/").AnyTimes()
+			scopeMock.EXPECT().Authorizer().Return(nil).AnyTimes()
+
+			service := NewService(scopeMock)
+			service.Client = clientMock
+			c.Setup(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			if err := service.Reimage(context.TODO()); c.Err == nil {
+				g.Expect(err).To(Succeed())
+			} else {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(c.Err.Error()))
+			}
+		})
+	}
+}
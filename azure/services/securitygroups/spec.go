@@ -118,6 +118,18 @@ func (s *NSGSpec) Parameters(ctx context.Context, existing interface{}) (interfa
 	}, nil
 }
 
+// WithSpecHash returns the security group parameters tagged with the given spec hash.
+func (s *NSGSpec) WithSpecHash(parameters interface{}, hash string) (interface{}, error) {
+	nsg, ok := parameters.(network.SecurityGroup)
+	if !ok {
+		return nil, errors.Errorf("%T is not a network.SecurityGroup", parameters)
+	}
+	tags := infrav1.Tags{}
+	tags.Merge(converters.MapToTags(nsg.Tags))
+	nsg.Tags = converters.TagsToMap(tags.AddSpecVersionHashTag(hash))
+	return nsg, nil
+}
+
 // TODO: review this logic and make sure it is what we want. It seems incorrect to skip rules that don't have a certain protocol, etc.
 func ruleExists(rules []network.SecurityRule, rule network.SecurityRule) bool {
 	for _, existingRule := range rules {
@@ -303,3 +303,12 @@ func TestRuleExists(t *testing.T) {
 		})
 	}
 }
+
+func TestWithSpecHash(t *testing.T) {
+	g := NewWithT(t)
+	spec := &NSGSpec{Name: "test-nsg"}
+
+	tagged, err := spec.WithSpecHash(network.SecurityGroup{Tags: map[string]*string{"foo": ptr.To("bar")}}, "hash")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tagged).To(Equal(network.SecurityGroup{Tags: map[string]*string{"foo": ptr.To("bar"), infrav1.SpecVersionHashTagKey(): ptr.To("hash")}}))
+}
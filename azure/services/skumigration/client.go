@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skumigration
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	GetLoadBalancer(ctx context.Context, resourceGroup, name string) (network.LoadBalancer, error)
+	GetNetworkInterface(ctx context.Context, resourceGroup, name string) (network.Interface, error)
+	ListNetworkInterfaces(ctx context.Context, resourceGroup string) ([]network.Interface, error)
+	GetPublicIP(ctx context.Context, resourceGroup, name string) (network.PublicIPAddress, error)
+}
+
+// azureClient contains the Azure go-sdk Clients.
+type azureClient struct {
+	loadbalancers network.LoadBalancersClient
+	interfaces    network.InterfacesClient
+	publicips     network.PublicIPAddressesClient
+}
+
+// newClient creates a new SKU migration client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	loadbalancers := network.NewLoadBalancersClientWithBaseURI(auth.BaseURI(), auth.SubscriptionID())
+	azure.SetAutoRestClientDefaults(&loadbalancers.Client, auth.Authorizer())
+	interfaces := network.NewInterfacesClientWithBaseURI(auth.BaseURI(), auth.SubscriptionID())
+	azure.SetAutoRestClientDefaults(&interfaces.Client, auth.Authorizer())
+	publicips := network.NewPublicIPAddressesClientWithBaseURI(auth.BaseURI(), auth.SubscriptionID())
+	azure.SetAutoRestClientDefaults(&publicips.Client, auth.Authorizer())
+	return &azureClient{
+		loadbalancers: loadbalancers,
+		interfaces:    interfaces,
+		publicips:     publicips,
+	}
+}
+
+// GetLoadBalancer gets the specified load balancer, including the network interfaces in its backend pools.
+func (ac *azureClient) GetLoadBalancer(ctx context.Context, resourceGroup, name string) (network.LoadBalancer, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "skumigration.azureClient.GetLoadBalancer")
+	defer done()
+
+	return ac.loadbalancers.Get(ctx, resourceGroup, name, "")
+}
+
+// GetNetworkInterface gets the specified network interface.
+func (ac *azureClient) GetNetworkInterface(ctx context.Context, resourceGroup, name string) (network.Interface, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "skumigration.azureClient.GetNetworkInterface")
+	defer done()
+
+	return ac.interfaces.Get(ctx, resourceGroup, name, "")
+}
+
+// ListNetworkInterfaces lists the network interfaces of the specified resource group.
+func (ac *azureClient) ListNetworkInterfaces(ctx context.Context, resourceGroup string) ([]network.Interface, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "skumigration.azureClient.ListNetworkInterfaces")
+	defer done()
+
+	var nics []network.Interface
+	iter, err := ac.interfaces.ListComplete(ctx, resourceGroup)
+	if err != nil {
+		return nil, err
+	}
+	for iter.NotDone() {
+		nics = append(nics, iter.Value())
+		if err := iter.NextWithContext(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return nics, nil
+}
+
+// GetPublicIP gets the specified public IP.
+func (ac *azureClient) GetPublicIP(ctx context.Context, resourceGroup, name string) (network.PublicIPAddress, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "skumigration.azureClient.GetPublicIP")
+	defer done()
+
+	return ac.publicips.Get(ctx, resourceGroup, name, "")
+}
@@ -0,0 +1,383 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skumigration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "skumigration"
+
+// SKUMigrationScope defines the scope interface for a SKU migration service.
+type SKUMigrationScope interface {
+	azure.ClusterScoper
+	azure.AsyncStatusUpdater
+	LBSpecs() []azure.ResourceSpecGetter
+	BasicSKUMigrationRequested() bool
+}
+
+// Service migrates the Basic load balancers of a cluster and their public IPs to the Standard SKU.
+type Service struct {
+	Scope SKUMigrationScope
+	client
+	publicIPs         async.Reconciler
+	loadBalancers     async.Reconciler
+	networkInterfaces async.Reconciler
+}
+
+// New creates a new service.
+func New(scope SKUMigrationScope) *Service {
+	publicIPsClient := publicips.NewClient(scope)
+	networkInterfacesClient := networkinterfaces.NewClient(scope)
+	return &Service{
+		Scope:             scope,
+		client:            newClient(scope),
+		publicIPs:         async.New(scope, publicIPsClient, publicIPsClient),
+		loadBalancers:     loadbalancers.New(scope),
+		networkInterfaces: async.New(scope, networkInterfacesClient, networkInterfacesClient),
+	}
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile migrates the Basic load balancers of the cluster to the Standard SKU when the migration is requested.
+// Basic load balancers can't be upgraded in place, so each of them is migrated by:
+//  1. making its Basic public IPs static so that they keep their addresses,
+//  2. removing the references of the network interfaces to its backend pools and inbound NAT rules,
+//  3. deleting it,
+//  4. upgrading its public IPs to the Standard SKU,
+//  5. creating it again with the Standard SKU,
+//  6. restoring the references of the network interfaces.
+//
+// The removed references are recorded in tags of the network interfaces, so the migration resumes where it stopped
+// when one of the steps fails or is still in progress.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "skumigration.Service.Reconcile")
+	defer done()
+
+	if !s.Scope.BasicSKUMigrationRequested() {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	for _, spec := range s.Scope.LBSpecs() {
+		lbSpec, ok := spec.(*loadbalancers.LBSpec)
+		if !ok {
+			return errors.Errorf("%T is not a loadbalancers.LBSpec", spec)
+		}
+		if err := s.migrateLoadBalancer(ctx, lbSpec); err != nil {
+			return errors.Wrapf(err, "failed to migrate load balancer %s to the Standard SKU", lbSpec.Name)
+		}
+	}
+
+	return s.reassociateNetworkInterfaces(ctx)
+}
+
+// Delete is a no-op as the migrated resources are deleted by their own services.
+func (s *Service) Delete(ctx context.Context) error {
+	return nil
+}
+
+// IsManaged returns always returns true as the migration only applies to resources managed by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+// migrateLoadBalancer migrates the load balancer of the given spec to the Standard SKU if it is a Basic one.
+func (s *Service) migrateLoadBalancer(ctx context.Context, lbSpec *loadbalancers.LBSpec) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "skumigration.Service.migrateLoadBalancer")
+	defer done()
+
+	existing, err := s.client.GetLoadBalancer(ctx, lbSpec.ResourceGroupName(), lbSpec.ResourceName())
+	if azure.ResourceNotFound(err) {
+		// The load balancer may have been deleted before its public IPs were upgraded.
+		return s.upgradePublicIPs(ctx, specPublicIPs(lbSpec))
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to get load balancer")
+	}
+	if existing.Sku != nil && existing.Sku.Name != network.LoadBalancerSkuNameBasic {
+		return nil
+	}
+	if err := validateBasicLoadBalancer(existing, lbSpec); err != nil {
+		return err
+	}
+	if err := s.validateInstancePublicIPs(ctx, existing); err != nil {
+		return err
+	}
+
+	log.Info("migrating Basic load balancer to the Standard SKU", "load balancer", lbSpec.Name)
+	publicIPs := frontendPublicIPs(existing)
+	for _, publicIP := range publicIPs {
+		if _, err := s.publicIPs.CreateOrUpdateResource(ctx, &PublicIPSKUSpec{Name: publicIP.name, ResourceGroup: publicIP.resourceGroup}, serviceName); err != nil {
+			return errors.Wrapf(err, "failed to make public IP %s static", publicIP.name)
+		}
+	}
+
+	for _, nic := range backendNetworkInterfaces(existing) {
+		spec := &NICDisassociateSpec{Name: nic.name, ResourceGroup: nic.resourceGroup, LoadBalancerName: lbSpec.Name}
+		if _, err := s.networkInterfaces.CreateOrUpdateResource(ctx, spec, serviceName); err != nil {
+			return errors.Wrapf(err, "failed to disassociate network interface %s", nic.name)
+		}
+	}
+
+	if err := s.loadBalancers.DeleteResource(ctx, lbSpec, serviceName); err != nil {
+		return errors.Wrap(err, "failed to delete Basic load balancer")
+	}
+
+	if err := s.upgradePublicIPs(ctx, publicIPs); err != nil {
+		return err
+	}
+
+	if _, err := s.loadBalancers.CreateOrUpdateResource(ctx, lbSpec, serviceName); err != nil {
+		return errors.Wrap(err, "failed to create Standard load balancer")
+	}
+	log.Info("migrated load balancer to the Standard SKU", "load balancer", lbSpec.Name)
+	return nil
+}
+
+// upgradePublicIPs upgrades the given public IPs to the Standard SKU if they are Basic ones.
+func (s *Service) upgradePublicIPs(ctx context.Context, publicIPs []resourceReference) error {
+	for _, publicIP := range publicIPs {
+		spec := &PublicIPSKUSpec{Name: publicIP.name, ResourceGroup: publicIP.resourceGroup, UpgradeSKU: true}
+		if _, err := s.publicIPs.CreateOrUpdateResource(ctx, spec, serviceName); err != nil {
+			return errors.Wrapf(err, "failed to upgrade public IP %s to the Standard SKU", publicIP.name)
+		}
+	}
+	return nil
+}
+
+// reassociateNetworkInterfaces restores the load balancer references that were removed from the network interfaces of
+// the cluster during the migration, once the backend pools and inbound NAT rules exist on the migrated load balancers.
+func (s *Service) reassociateNetworkInterfaces(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "skumigration.Service.reassociateNetworkInterfaces")
+	defer done()
+
+	nics, err := s.client.ListNetworkInterfaces(ctx, s.Scope.ResourceGroup())
+	if err != nil {
+		return errors.Wrap(err, "failed to list network interfaces")
+	}
+
+	available := map[string]bool{}
+	checked := map[string]bool{}
+	for _, nic := range nics {
+		migrated := false
+		for tagKey := range nic.Tags {
+			if !strings.HasPrefix(tagKey, migrationTagPrefix) {
+				continue
+			}
+			migrated = true
+			lbName := strings.TrimPrefix(tagKey, migrationTagPrefix)
+			if checked[strings.ToLower(lbName)] {
+				continue
+			}
+			checked[strings.ToLower(lbName)] = true
+
+			lb, err := s.client.GetLoadBalancer(ctx, s.Scope.ResourceGroup(), lbName)
+			if azure.ResourceNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return errors.Wrapf(err, "failed to get load balancer %s", lbName)
+			}
+			if lb.Sku != nil && lb.Sku.Name == network.LoadBalancerSkuNameBasic {
+				continue
+			}
+			for _, pool := range ptr.Deref(lb.BackendAddressPools, nil) {
+				available[loadBalancerChildKey(lbName, backendAddressPools, ptr.Deref(pool.Name, ""))] = true
+			}
+			for _, natRule := range ptr.Deref(lb.InboundNatRules, nil) {
+				available[loadBalancerChildKey(lbName, inboundNatRules, ptr.Deref(natRule.Name, ""))] = true
+			}
+		}
+		if !migrated {
+			continue
+		}
+
+		log.V(2).Info("restoring load balancer references of network interface", "network interface", ptr.Deref(nic.Name, ""))
+		spec := &NICReassociateSpec{
+			Name:           ptr.Deref(nic.Name, ""),
+			ResourceGroup:  s.Scope.ResourceGroup(),
+			SubscriptionID: s.Scope.SubscriptionID(),
+			Available:      available,
+		}
+		if _, err := s.networkInterfaces.CreateOrUpdateResource(ctx, spec, serviceName); err != nil {
+			return errors.Wrapf(err, "failed to reassociate network interface %s", spec.Name)
+		}
+	}
+	return nil
+}
+
+// resourceReference references an Azure resource by resource group and name.
+type resourceReference struct {
+	resourceGroup string
+	name          string
+}
+
+// validateBasicLoadBalancer returns an error if the Basic load balancer can't be migrated by CAPZ.
+func validateBasicLoadBalancer(lb network.LoadBalancer, lbSpec *loadbalancers.LBSpec) error {
+	managedFrontends := map[string]bool{}
+	for _, frontend := range lbSpec.FrontendIPConfigs {
+		managedFrontends[strings.ToLower(frontend.Name)] = true
+	}
+	for _, rule := range lbSpec.AdditionalOutboundRules {
+		for _, frontend := range rule.FrontendIPs {
+			managedFrontends[strings.ToLower(frontend.Name)] = true
+		}
+	}
+	for _, frontend := range ptr.Deref(lb.FrontendIPConfigurations, nil) {
+		if !managedFrontends[strings.ToLower(ptr.Deref(frontend.Name, ""))] {
+			return errors.Errorf("frontend %s is not managed by CAPZ, e.g. it belongs to a Kubernetes service of type LoadBalancer, and must be removed before the migration", ptr.Deref(frontend.Name, ""))
+		}
+	}
+
+	for _, id := range backendIPConfigurationIDs(lb) {
+		if strings.Contains(strings.ToLower(id), "/virtualmachinescalesets/") {
+			return errors.Errorf("load balancer is used by virtual machine scale set IP configuration %s, which can't be migrated", id)
+		}
+	}
+	return nil
+}
+
+// validateInstancePublicIPs returns an error if a network interface in the backend of the Basic load balancer has a
+// Basic public IP, such as the public IP of an AzureMachine with allocatePublicIP. The network interfaces in the backend
+// of a Standard load balancer can't have Basic public IPs, so they couldn't be added back once it is migrated.
+func (s *Service) validateInstancePublicIPs(ctx context.Context, lb network.LoadBalancer) error {
+	var basicPublicIPs []string
+	for _, nicRef := range backendNetworkInterfaces(lb) {
+		nic, err := s.client.GetNetworkInterface(ctx, nicRef.resourceGroup, nicRef.name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get network interface %s", nicRef.name)
+		}
+		for _, ipConfig := range ptr.Deref(nic.IPConfigurations, nil) {
+			if ipConfig.InterfaceIPConfigurationPropertiesFormat == nil || ipConfig.PublicIPAddress == nil {
+				continue
+			}
+			ref, ok := parseResourceID(ptr.Deref(ipConfig.PublicIPAddress.ID, ""), "publicIPAddresses")
+			if !ok {
+				continue
+			}
+			publicIP, err := s.client.GetPublicIP(ctx, ref.resourceGroup, ref.name)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get public IP %s", ref.name)
+			}
+			// Azure defaults to the Basic SKU when none is set.
+			if publicIP.Sku == nil || publicIP.Sku.Name == network.PublicIPAddressSkuNameBasic {
+				basicPublicIPs = append(basicPublicIPs, fmt.Sprintf("%s of network interface %s", ref.name, nicRef.name))
+			}
+		}
+	}
+	if len(basicPublicIPs) > 0 {
+		return errors.Errorf("Basic public IPs %s must be upgraded to the Standard SKU before the migration", strings.Join(basicPublicIPs, ", "))
+	}
+	return nil
+}
+
+// frontendPublicIPs returns the public IPs of the frontends of the load balancer.
+func frontendPublicIPs(lb network.LoadBalancer) []resourceReference {
+	var publicIPs []resourceReference
+	for _, frontend := range ptr.Deref(lb.FrontendIPConfigurations, nil) {
+		if frontend.FrontendIPConfigurationPropertiesFormat == nil || frontend.PublicIPAddress == nil {
+			continue
+		}
+		if ref, ok := parseResourceID(ptr.Deref(frontend.PublicIPAddress.ID, ""), "publicIPAddresses"); ok {
+			publicIPs = append(publicIPs, ref)
+		}
+	}
+	return publicIPs
+}
+
+// specPublicIPs returns the public IPs of the frontends of the load balancer spec.
+func specPublicIPs(lbSpec *loadbalancers.LBSpec) []resourceReference {
+	var publicIPs []resourceReference
+	for _, frontend := range lbSpec.FrontendIPConfigs {
+		if frontend.PublicIP != nil {
+			publicIPs = append(publicIPs, resourceReference{resourceGroup: lbSpec.ResourceGroup, name: frontend.PublicIP.Name})
+		}
+	}
+	return publicIPs
+}
+
+// backendNetworkInterfaces returns the network interfaces in the backend pools and inbound NAT rules of the load balancer.
+func backendNetworkInterfaces(lb network.LoadBalancer) []resourceReference {
+	var nics []resourceReference
+	seen := map[resourceReference]bool{}
+	for _, id := range backendIPConfigurationIDs(lb) {
+		nic, ok := parseResourceID(id, "networkInterfaces")
+		if !ok || seen[nic] {
+			continue
+		}
+		seen[nic] = true
+		nics = append(nics, nic)
+	}
+	return nics
+}
+
+// backendIPConfigurationIDs returns the IDs of the IP configurations in the backend pools and inbound NAT rules of the
+// load balancer.
+func backendIPConfigurationIDs(lb network.LoadBalancer) []string {
+	var ids []string
+	for _, pool := range ptr.Deref(lb.BackendAddressPools, nil) {
+		if pool.BackendAddressPoolPropertiesFormat == nil {
+			continue
+		}
+		for _, ipConfig := range ptr.Deref(pool.BackendIPConfigurations, nil) {
+			ids = append(ids, ptr.Deref(ipConfig.ID, ""))
+		}
+	}
+	for _, natRule := range ptr.Deref(lb.InboundNatRules, nil) {
+		if natRule.InboundNatRulePropertiesFormat == nil || natRule.BackendIPConfiguration == nil {
+			continue
+		}
+		ids = append(ids, ptr.Deref(natRule.BackendIPConfiguration.ID, ""))
+	}
+	return ids
+}
+
+// parseResourceID returns the resource group and the name of the resource of the given type in the resource ID.
+func parseResourceID(id, resourceType string) (resourceReference, bool) {
+	var ref resourceReference
+	parts := strings.Split(id, "/")
+	for i := 0; i+1 < len(parts); i++ {
+		switch {
+		case strings.EqualFold(parts[i], "resourceGroups"):
+			ref.resourceGroup = parts[i+1]
+		case strings.EqualFold(parts[i], resourceType):
+			ref.name = parts[i+1]
+			return ref, ref.resourceGroup != ""
+		}
+	}
+	return ref, false
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skumigration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/skumigration/mock_skumigration"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+const (
+	testPoolName         = "my-cluster-internal-lb-backendPool"
+	testNICName          = "my-vm-nic"
+	testLBPublicIP       = "my-cluster-internal-lb-ip"
+	testNodePublicIP     = "pip-my-vm"
+	testIPConfigName     = "pipConfig"
+	testPublicIPIDPrefix = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/"
+)
+
+var (
+	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+	notFoundError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusNotFound}, "Not Found")
+
+	fakeLBSpec = &loadbalancers.LBSpec{
+		Name:          testLBName,
+		ResourceGroup: testResourceGroup,
+		FrontendIPConfigs: []infrav1.FrontendIP{
+			{Name: "my-cluster-internal-lb-frontEnd", PublicIP: &infrav1.PublicIPSpec{Name: testLBPublicIP}},
+		},
+	}
+
+	fakeIPConfigID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/" + testNICName + "/ipConfigurations/" + testIPConfigName
+
+	fakeStaticPublicIPSpec  = &PublicIPSKUSpec{Name: testLBPublicIP, ResourceGroup: testResourceGroup}
+	fakeUpgradePublicIPSpec = &PublicIPSKUSpec{Name: testLBPublicIP, ResourceGroup: testResourceGroup, UpgradeSKU: true}
+	fakeNICDisassociateSpec = &NICDisassociateSpec{Name: testNICName, ResourceGroup: testResourceGroup, LoadBalancerName: testLBName}
+	fakeNICReassociatedSpec = newNICReassociateSpec(map[string]bool{loadBalancerChildKey(testLBName, backendAddressPools, testPoolName): true})
+	fakeNICNotAvailableSpec = newNICReassociateSpec(map[string]bool{})
+	fakeMigratedNIC         = network.Interface{
+		Name: ptr.To(testNICName),
+		Tags: map[string]*string{
+			testTagKey: ptr.To(testIPConfigName + "/" + backendAddressPools + "/" + testPoolName),
+		},
+	}
+)
+
+func newNICReassociateSpec(available map[string]bool) *NICReassociateSpec {
+	return &NICReassociateSpec{
+		Name:           testNICName,
+		ResourceGroup:  testResourceGroup,
+		SubscriptionID: testSubscriptionID,
+		Available:      available,
+	}
+}
+
+// fakeLoadBalancer returns a load balancer of the given SKU with a public frontend and, if withBackend is true, a
+// network interface in its backend pool.
+func fakeLoadBalancer(sku network.LoadBalancerSkuName, withBackend bool) network.LoadBalancer {
+	pool := network.BackendAddressPool{
+		Name:                               ptr.To(testPoolName),
+		BackendAddressPoolPropertiesFormat: &network.BackendAddressPoolPropertiesFormat{},
+	}
+	if withBackend {
+		pool.BackendIPConfigurations = &[]network.InterfaceIPConfiguration{{ID: ptr.To(fakeIPConfigID)}}
+	}
+	return network.LoadBalancer{
+		Name: ptr.To(testLBName),
+		Sku:  &network.LoadBalancerSku{Name: sku},
+		LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+			FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+				{
+					Name: ptr.To("my-cluster-internal-lb-frontEnd"),
+					FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+						PublicIPAddress: &network.PublicIPAddress{ID: ptr.To(testPublicIPIDPrefix + testLBPublicIP)},
+					},
+				},
+			},
+			BackendAddressPools: &[]network.BackendAddressPool{pool},
+		},
+	}
+}
+
+// fakeNetworkInterface returns a network interface, with an instance-level public IP if withPublicIP is true.
+func fakeNetworkInterface(withPublicIP bool) network.Interface {
+	props := &network.InterfaceIPConfigurationPropertiesFormat{}
+	if withPublicIP {
+		props.PublicIPAddress = &network.PublicIPAddress{ID: ptr.To(testPublicIPIDPrefix + testNodePublicIP)}
+	}
+	return network.Interface{
+		Name: ptr.To(testNICName),
+		InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+			IPConfigurations: &[]network.InterfaceIPConfiguration{
+				{Name: ptr.To(testIPConfigName), InterfaceIPConfigurationPropertiesFormat: props},
+			},
+		},
+	}
+}
+
+func fakePublicIP(sku network.PublicIPAddressSkuName) network.PublicIPAddress {
+	return network.PublicIPAddress{
+		Name: ptr.To(testNodePublicIP),
+		Sku:  &network.PublicIPAddressSku{Name: sku},
+	}
+}
+
+type recorders struct {
+	scope             *mock_skumigration.MockSKUMigrationScopeMockRecorder
+	client            *mock_skumigration.MockclientMockRecorder
+	publicIPs         *mock_async.MockReconcilerMockRecorder
+	loadBalancers     *mock_async.MockReconcilerMockRecorder
+	networkInterfaces *mock_async.MockReconcilerMockRecorder
+}
+
+func TestReconcileSKUMigration(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(r recorders)
+	}{
+		{
+			name:          "noop if the migration isn't requested",
+			expectedError: "",
+			expect: func(r recorders) {
+				r.scope.BasicSKUMigrationRequested().Return(false)
+			},
+		},
+		{
+			name:          "migrate a Basic load balancer and restore the references of its network interfaces",
+			expectedError: "",
+			expect: func(r recorders) {
+				r.scope.BasicSKUMigrationRequested().Return(true)
+				r.scope.LBSpecs().Return([]azure.ResourceSpecGetter{fakeLBSpec})
+				gomock.InOrder(
+					r.client.GetLoadBalancer(gomockinternal.AContext(), testResourceGroup, testLBName).Return(fakeLoadBalancer(network.LoadBalancerSkuNameBasic, true), nil),
+					r.client.GetNetworkInterface(gomockinternal.AContext(), testResourceGroup, testNICName).Return(fakeNetworkInterface(true), nil),
+					r.client.GetPublicIP(gomockinternal.AContext(), testResourceGroup, testNodePublicIP).Return(fakePublicIP(network.PublicIPAddressSkuNameStandard), nil),
+					r.publicIPs.CreateOrUpdateResource(gomockinternal.AContext(), fakeStaticPublicIPSpec, serviceName).Return(nil, nil),
+					r.networkInterfaces.CreateOrUpdateResource(gomockinternal.AContext(), fakeNICDisassociateSpec, serviceName).Return(nil, nil),
+					r.loadBalancers.DeleteResource(gomockinternal.AContext(), fakeLBSpec, serviceName).Return(nil),
+					r.publicIPs.CreateOrUpdateResource(gomockinternal.AContext(), fakeUpgradePublicIPSpec, serviceName).Return(nil, nil),
+					r.loadBalancers.CreateOrUpdateResource(gomockinternal.AContext(), fakeLBSpec, serviceName).Return(nil, nil),
+					r.client.ListNetworkInterfaces(gomockinternal.AContext(), testResourceGroup).Return([]network.Interface{fakeMigratedNIC}, nil),
+					r.client.GetLoadBalancer(gomockinternal.AContext(), testResourceGroup, testLBName).Return(fakeLoadBalancer(network.LoadBalancerSkuNameStandard, false), nil),
+					r.networkInterfaces.CreateOrUpdateResource(gomockinternal.AContext(), fakeNICReassociatedSpec, serviceName).Return(nil, nil),
+				)
+				r.scope.ResourceGroup().AnyTimes().Return(testResourceGroup)
+				r.scope.SubscriptionID().AnyTimes().Return(testSubscriptionID)
+			},
+		},
+		{
+			name:          "resume after the network interfaces were disassociated",
+			expectedError: "",
+			expect: func(r recorders) {
+				r.scope.BasicSKUMigrationRequested().Return(true)
+				r.scope.LBSpecs().Return([]azure.ResourceSpecGetter{fakeLBSpec})
+				gomock.InOrder(
+					r.client.GetLoadBalancer(gomockinternal.AContext(), testResourceGroup, testLBName).Return(fakeLoadBalancer(network.LoadBalancerSkuNameBasic, false), nil),
+					r.publicIPs.CreateOrUpdateResource(gomockinternal.AContext(), fakeStaticPublicIPSpec, serviceName).Return(nil, nil),
+					r.loadBalancers.DeleteResource(gomockinternal.AContext(), fakeLBSpec, serviceName).Return(nil),
+					r.publicIPs.CreateOrUpdateResource(gomockinternal.AContext(), fakeUpgradePublicIPSpec, serviceName).Return(nil, nil),
+					r.loadBalancers.CreateOrUpdateResource(gomockinternal.AContext(), fakeLBSpec, serviceName).Return(nil, nil),
+					r.client.ListNetworkInterfaces(gomockinternal.AContext(), testResourceGroup).Return([]network.Interface{fakeMigratedNIC}, nil),
+					r.client.GetLoadBalancer(gomockinternal.AContext(), testResourceGroup, testLBName).Return(fakeLoadBalancer(network.LoadBalancerSkuNameStandard, false), nil),
+					r.networkInterfaces.CreateOrUpdateResource(gomockinternal.AContext(), fakeNICReassociatedSpec, serviceName).Return(nil, nil),
+				)
+				r.scope.ResourceGroup().AnyTimes().Return(testResourceGroup)
+				r.scope.SubscriptionID().AnyTimes().Return(testSubscriptionID)
+			},
+		},
+		{
+			name:          "resume after the Basic load balancer was deleted",
+			expectedError: "",
+			expect: func(r recorders) {
+				r.scope.BasicSKUMigrationRequested().Return(true)
+				r.scope.LBSpecs().Return([]azure.ResourceSpecGetter{fakeLBSpec})
+				gomock.InOrder(
+					r.client.GetLoadBalancer(gomockinternal.AContext(), testResourceGroup, testLBName).Return(network.LoadBalancer{}, notFoundError),
+					r.publicIPs.CreateOrUpdateResource(gomockinternal.AContext(), fakeUpgradePublicIPSpec, serviceName).Return(nil, nil),
+					r.client.ListNetworkInterfaces(gomockinternal.AContext(), testResourceGroup).Return([]network.Interface{fakeMigratedNIC}, nil),
+					r.client.GetLoadBalancer(gomockinternal.AContext(), testResourceGroup, testLBName).Return(network.LoadBalancer{}, notFoundError),
+					r.networkInterfaces.CreateOrUpdateResource(gomockinternal.AContext(), fakeNICNotAvailableSpec, serviceName).Return(nil, nil),
+				)
+				r.scope.ResourceGroup().AnyTimes().Return(testResourceGroup)
+				r.scope.SubscriptionID().AnyTimes().Return(testSubscriptionID)
+			},
+		},
+		{
+			name:          "resume after the load balancer was created with the Standard SKU",
+			expectedError: "",
+			expect: func(r recorders) {
+				r.scope.BasicSKUMigrationRequested().Return(true)
+				r.scope.LBSpecs().Return([]azure.ResourceSpecGetter{fakeLBSpec})
+				gomock.InOrder(
+					r.client.GetLoadBalancer(gomockinternal.AContext(), testResourceGroup, testLBName).Return(fakeLoadBalancer(network.LoadBalancerSkuNameStandard, false), nil),
+					r.client.ListNetworkInterfaces(gomockinternal.AContext(), testResourceGroup).Return([]network.Interface{fakeMigratedNIC}, nil),
+					r.client.GetLoadBalancer(gomockinternal.AContext(), testResourceGroup, testLBName).Return(fakeLoadBalancer(network.LoadBalancerSkuNameStandard, false), nil),
+					r.networkInterfaces.CreateOrUpdateResource(gomockinternal.AContext(), fakeNICReassociatedSpec, serviceName).Return(nil, nil),
+				)
+				r.scope.ResourceGroup().AnyTimes().Return(testResourceGroup)
+				r.scope.SubscriptionID().AnyTimes().Return(testSubscriptionID)
+			},
+		},
+		{
+			name:          "keep the references of network interfaces to load balancers that are still Basic",
+			expectedError: "",
+			expect: func(r recorders) {
+				r.scope.BasicSKUMigrationRequested().Return(true)
+				r.scope.LBSpecs().Return(nil)
+				gomock.InOrder(
+					r.client.ListNetworkInterfaces(gomockinternal.AContext(), testResourceGroup).Return([]network.Interface{fakeMigratedNIC, {Name: ptr.To("other-nic")}}, nil),
+					r.client.GetLoadBalancer(gomockinternal.AContext(), testResourceGroup, testLBName).Return(fakeLoadBalancer(network.LoadBalancerSkuNameBasic, false), nil),
+					r.networkInterfaces.CreateOrUpdateResource(gomockinternal.AContext(), fakeNICNotAvailableSpec, serviceName).Return(nil, nil),
+				)
+				r.scope.ResourceGroup().AnyTimes().Return(testResourceGroup)
+				r.scope.SubscriptionID().AnyTimes().Return(testSubscriptionID)
+			},
+		},
+		{
+			name:          "stop when the deletion of the Basic load balancer fails",
+			expectedError: "failed to migrate load balancer my-cluster-internal-lb to the Standard SKU: failed to delete Basic load balancer: #: Internal Server Error: StatusCode=500",
+			expect: func(r recorders) {
+				r.scope.BasicSKUMigrationRequested().Return(true)
+				r.scope.LBSpecs().Return([]azure.ResourceSpecGetter{fakeLBSpec})
+				gomock.InOrder(
+					r.client.GetLoadBalancer(gomockinternal.AContext(), testResourceGroup, testLBName).Return(fakeLoadBalancer(network.LoadBalancerSkuNameBasic, true), nil),
+					r.client.GetNetworkInterface(gomockinternal.AContext(), testResourceGroup, testNICName).Return(fakeNetworkInterface(false), nil),
+					r.publicIPs.CreateOrUpdateResource(gomockinternal.AContext(), fakeStaticPublicIPSpec, serviceName).Return(nil, nil),
+					r.networkInterfaces.CreateOrUpdateResource(gomockinternal.AContext(), fakeNICDisassociateSpec, serviceName).Return(nil, nil),
+					r.loadBalancers.DeleteResource(gomockinternal.AContext(), fakeLBSpec, serviceName).Return(internalError),
+				)
+			},
+		},
+		{
+			name:          "refuse to migrate a load balancer whose network interfaces have Basic public IPs",
+			expectedError: "failed to migrate load balancer my-cluster-internal-lb to the Standard SKU: Basic public IPs pip-my-vm of network interface my-vm-nic must be upgraded to the Standard SKU before the migration",
+			expect: func(r recorders) {
+				r.scope.BasicSKUMigrationRequested().Return(true)
+				r.scope.LBSpecs().Return([]azure.ResourceSpecGetter{fakeLBSpec})
+				gomock.InOrder(
+					r.client.GetLoadBalancer(gomockinternal.AContext(), testResourceGroup, testLBName).Return(fakeLoadBalancer(network.LoadBalancerSkuNameBasic, true), nil),
+					r.client.GetNetworkInterface(gomockinternal.AContext(), testResourceGroup, testNICName).Return(fakeNetworkInterface(true), nil),
+					r.client.GetPublicIP(gomockinternal.AContext(), testResourceGroup, testNodePublicIP).Return(fakePublicIP(network.PublicIPAddressSkuNameBasic), nil),
+				)
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_skumigration.NewMockSKUMigrationScope(mockCtrl)
+			clientMock := mock_skumigration.NewMockclient(mockCtrl)
+			publicIPsMock := mock_async.NewMockReconciler(mockCtrl)
+			loadBalancersMock := mock_async.NewMockReconciler(mockCtrl)
+			networkInterfacesMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(recorders{
+				scope:             scopeMock.EXPECT(),
+				client:            clientMock.EXPECT(),
+				publicIPs:         publicIPsMock.EXPECT(),
+				loadBalancers:     loadBalancersMock.EXPECT(),
+				networkInterfaces: networkInterfacesMock.EXPECT(),
+			})
+
+			s := &Service{
+				Scope:             scopeMock,
+				client:            clientMock,
+				publicIPs:         publicIPsMock,
+				loadBalancers:     loadBalancersMock,
+				networkInterfaces: networkInterfacesMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skumigration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+const (
+	// migrationTagPrefix is the prefix of the network interface tags recording the load balancer references removed
+	// during the migration of a load balancer. The name of the load balancer follows the prefix.
+	migrationTagPrefix = "sigs.k8s.io_cluster-api-provider-azure_basic-sku-migration_"
+
+	backendAddressPools = "backendAddressPools"
+	inboundNatRules     = "inboundNatRules"
+)
+
+// PublicIPSKUSpec defines the specification for the migration of a Basic public IP.
+type PublicIPSKUSpec struct {
+	Name          string
+	ResourceGroup string
+	// UpgradeSKU upgrades the public IP to the Standard SKU, which requires it to be disassociated. Otherwise, only its
+	// allocation method is made static so that it keeps its address once it is disassociated.
+	UpgradeSKU bool
+}
+
+// ResourceName returns the name of the public IP.
+func (s *PublicIPSKUSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *PublicIPSKUSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for public IPs.
+func (s *PublicIPSKUSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the public IP.
+func (s *PublicIPSKUSpec) Parameters(ctx context.Context, existing interface{}) (interface{}, error) {
+	if existing == nil {
+		// Public IPs that don't exist are created with the Standard SKU.
+		return nil, nil
+	}
+	publicIP, ok := existing.(network.PublicIPAddress)
+	if !ok {
+		return nil, errors.Errorf("%T is not a network.PublicIPAddress", existing)
+	}
+	// Azure defaults to the Basic SKU when none is set.
+	if publicIP.Sku != nil && publicIP.Sku.Name != network.PublicIPAddressSkuNameBasic {
+		return nil, nil
+	}
+	if publicIP.PublicIPAddressPropertiesFormat == nil {
+		publicIP.PublicIPAddressPropertiesFormat = &network.PublicIPAddressPropertiesFormat{}
+	}
+
+	if !s.UpgradeSKU {
+		if publicIP.PublicIPAllocationMethod == network.IPAllocationMethodStatic {
+			return nil, nil
+		}
+		publicIP.PublicIPAllocationMethod = network.IPAllocationMethodStatic
+		return publicIP, nil
+	}
+
+	publicIP.Sku = &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard}
+	publicIP.PublicIPAllocationMethod = network.IPAllocationMethodStatic
+	return publicIP, nil
+}
+
+// NICDisassociateSpec defines the specification for the removal of the references of a network interface to the
+// backend pools and inbound NAT rules of a load balancer. The removed references are recorded in a tag of the network
+// interface so that they can be restored once the load balancer is migrated.
+type NICDisassociateSpec struct {
+	Name             string
+	ResourceGroup    string
+	LoadBalancerName string
+}
+
+// ResourceName returns the name of the network interface.
+func (s *NICDisassociateSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *NICDisassociateSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for network interfaces.
+func (s *NICDisassociateSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the network interface.
+func (s *NICDisassociateSpec) Parameters(ctx context.Context, existing interface{}) (interface{}, error) {
+	if existing == nil {
+		return nil, nil
+	}
+	nic, ok := existing.(network.Interface)
+	if !ok {
+		return nil, errors.Errorf("%T is not a network.Interface", existing)
+	}
+
+	var references []string
+	for _, ipConfig := range ptr.Deref(nic.IPConfigurations, nil) {
+		props := ipConfig.InterfaceIPConfigurationPropertiesFormat
+		if props == nil {
+			continue
+		}
+		ipConfigName := ptr.Deref(ipConfig.Name, "")
+
+		pools := make([]network.BackendAddressPool, 0)
+		for _, pool := range ptr.Deref(props.LoadBalancerBackendAddressPools, nil) {
+			if lbName, childType, name := parseLoadBalancerChildID(ptr.Deref(pool.ID, "")); strings.EqualFold(lbName, s.LoadBalancerName) {
+				references = append(references, strings.Join([]string{ipConfigName, childType, name}, "/"))
+				continue
+			}
+			pools = append(pools, pool)
+		}
+		props.LoadBalancerBackendAddressPools = &pools
+
+		natRules := make([]network.InboundNatRule, 0)
+		for _, natRule := range ptr.Deref(props.LoadBalancerInboundNatRules, nil) {
+			if lbName, childType, name := parseLoadBalancerChildID(ptr.Deref(natRule.ID, "")); strings.EqualFold(lbName, s.LoadBalancerName) {
+				references = append(references, strings.Join([]string{ipConfigName, childType, name}, "/"))
+				continue
+			}
+			natRules = append(natRules, natRule)
+		}
+		props.LoadBalancerInboundNatRules = &natRules
+	}
+	if len(references) == 0 {
+		return nil, nil
+	}
+
+	if nic.Tags == nil {
+		nic.Tags = map[string]*string{}
+	}
+	tagKey := migrationTagPrefix + s.LoadBalancerName
+	// Keep the references recorded by an earlier attempt.
+	if recorded := ptr.Deref(nic.Tags[tagKey], ""); recorded != "" {
+		references = append(strings.Split(recorded, ";"), references...)
+	}
+	nic.Tags[tagKey] = ptr.To(strings.Join(references, ";"))
+	return nic, nil
+}
+
+// NICReassociateSpec defines the specification for the restoration of the load balancer references of a network
+// interface that were removed during the migration of the load balancers.
+type NICReassociateSpec struct {
+	Name           string
+	ResourceGroup  string
+	SubscriptionID string
+	// Available are the backend pools and inbound NAT rules of the migrated load balancers that exist, as returned by
+	// loadBalancerChildKey.
+	Available map[string]bool
+}
+
+// ResourceName returns the name of the network interface.
+func (s *NICReassociateSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *NICReassociateSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for network interfaces.
+func (s *NICReassociateSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the network interface.
+func (s *NICReassociateSpec) Parameters(ctx context.Context, existing interface{}) (interface{}, error) {
+	if existing == nil {
+		return nil, nil
+	}
+	nic, ok := existing.(network.Interface)
+	if !ok {
+		return nil, errors.Errorf("%T is not a network.Interface", existing)
+	}
+
+	update := false
+	for tagKey, value := range nic.Tags {
+		if !strings.HasPrefix(tagKey, migrationTagPrefix) {
+			continue
+		}
+		lbName := strings.TrimPrefix(tagKey, migrationTagPrefix)
+
+		var remaining []string
+		for _, reference := range strings.Split(ptr.Deref(value, ""), ";") {
+			parts := strings.Split(reference, "/")
+			if len(parts) != 3 {
+				// Drop references that can't be restored.
+				continue
+			}
+			ipConfigName, childType, name := parts[0], parts[1], parts[2]
+			if !s.Available[loadBalancerChildKey(lbName, childType, name)] {
+				remaining = append(remaining, reference)
+				continue
+			}
+			if err := s.addReference(&nic, ipConfigName, lbName, childType, name); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(remaining) == 0 {
+			delete(nic.Tags, tagKey)
+		} else {
+			nic.Tags[tagKey] = ptr.To(strings.Join(remaining, ";"))
+		}
+		update = true
+	}
+	if !update {
+		return nil, nil
+	}
+	return nic, nil
+}
+
+// addReference adds the reference to the backend pool or inbound NAT rule of a load balancer to the IP configuration
+// of the network interface.
+func (s *NICReassociateSpec) addReference(nic *network.Interface, ipConfigName, lbName, childType, name string) error {
+	for _, ipConfig := range ptr.Deref(nic.IPConfigurations, nil) {
+		props := ipConfig.InterfaceIPConfigurationPropertiesFormat
+		if props == nil || !strings.EqualFold(ptr.Deref(ipConfig.Name, ""), ipConfigName) {
+			continue
+		}
+
+		switch {
+		case strings.EqualFold(childType, backendAddressPools):
+			id := azure.AddressPoolID(s.SubscriptionID, s.ResourceGroup, lbName, name)
+			pools := ptr.Deref(props.LoadBalancerBackendAddressPools, nil)
+			for _, pool := range pools {
+				if strings.EqualFold(ptr.Deref(pool.ID, ""), id) {
+					return nil
+				}
+			}
+			pools = append(pools, network.BackendAddressPool{ID: ptr.To(id)})
+			props.LoadBalancerBackendAddressPools = &pools
+		case strings.EqualFold(childType, inboundNatRules):
+			id := azure.NATRuleID(s.SubscriptionID, s.ResourceGroup, lbName, name)
+			natRules := ptr.Deref(props.LoadBalancerInboundNatRules, nil)
+			for _, natRule := range natRules {
+				if strings.EqualFold(ptr.Deref(natRule.ID, ""), id) {
+					return nil
+				}
+			}
+			natRules = append(natRules, network.InboundNatRule{ID: ptr.To(id)})
+			props.LoadBalancerInboundNatRules = &natRules
+		default:
+			return errors.Errorf("unknown load balancer child resource type %q", childType)
+		}
+		return nil
+	}
+	return errors.Errorf("network interface %s has no IP configuration %s", ptr.Deref(nic.Name, ""), ipConfigName)
+}
+
+// parseLoadBalancerChildID returns the load balancer name and the type and name of the load balancer child resource
+// with the given ID, such as a backend pool or an inbound NAT rule.
+func parseLoadBalancerChildID(id string) (lbName, childType, name string) {
+	parts := strings.Split(id, "/")
+	for i := 0; i+3 < len(parts); i++ {
+		if strings.EqualFold(parts[i], "loadBalancers") {
+			return parts[i+1], parts[i+2], parts[i+3]
+		}
+	}
+	return "", "", ""
+}
+
+// loadBalancerChildKey returns the key of a load balancer child resource in NICReassociateSpec.Available.
+func loadBalancerChildKey(lbName, childType, name string) string {
+	return strings.ToLower(fmt.Sprintf("%s/%s/%s", lbName, childType, name))
+}
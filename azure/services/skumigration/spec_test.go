@@ -0,0 +1,277 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skumigration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+const (
+	testSubscriptionID = "123"
+	testResourceGroup  = "my-rg"
+	testLBName         = "my-cluster-internal-lb"
+	testTagKey         = migrationTagPrefix + testLBName
+)
+
+var (
+	testPoolID      = azure.AddressPoolID(testSubscriptionID, testResourceGroup, testLBName, "my-cluster-internal-lb-backendPool")
+	testNATRuleID   = azure.NATRuleID(testSubscriptionID, testResourceGroup, testLBName, "my-vm")
+	testOtherPoolID = azure.AddressPoolID(testSubscriptionID, testResourceGroup, "my-cluster", "my-cluster-outboundBackendPool")
+)
+
+func TestPublicIPSKUSpecParameters(t *testing.T) {
+	basicDynamic := network.PublicIPAddress{
+		Name: ptr.To("my-publicip"),
+		Sku:  &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameBasic},
+		PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+			PublicIPAllocationMethod: network.IPAllocationMethodDynamic,
+		},
+	}
+	basicStatic := network.PublicIPAddress{
+		Name: ptr.To("my-publicip"),
+		Sku:  &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameBasic},
+		PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+			PublicIPAllocationMethod: network.IPAllocationMethodStatic,
+		},
+	}
+	standard := network.PublicIPAddress{
+		Name: ptr.To("my-publicip"),
+		Sku:  &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
+		PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+			PublicIPAllocationMethod: network.IPAllocationMethodStatic,
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		upgradeSKU    bool
+		existing      interface{}
+		expected      interface{}
+		expectedError string
+	}{
+		{
+			name:     "noop if public IP doesn't exist",
+			existing: nil,
+			expected: nil,
+		},
+		{
+			name:     "Basic dynamic public IP is made static",
+			existing: basicDynamic,
+			expected: basicStatic,
+		},
+		{
+			name:     "noop if Basic public IP is already static",
+			existing: basicStatic,
+			expected: nil,
+		},
+		{
+			name:       "Basic public IP is upgraded to the Standard SKU",
+			upgradeSKU: true,
+			existing:   basicDynamic,
+			expected:   standard,
+		},
+		{
+			name:       "noop if public IP is already Standard",
+			upgradeSKU: true,
+			existing:   standard,
+			expected:   nil,
+		},
+		{
+			name:          "error if existing is not a public IP",
+			existing:      network.Interface{},
+			expectedError: "network.Interface is not a network.PublicIPAddress",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			spec := &PublicIPSKUSpec{Name: "my-publicip", ResourceGroup: testResourceGroup, UpgradeSKU: tc.upgradeSKU}
+			result, err := spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestNICDisassociateSpecParameters(t *testing.T) {
+	testCases := []struct {
+		name     string
+		existing interface{}
+		expected interface{}
+	}{
+		{
+			name:     "noop if network interface doesn't exist",
+			existing: nil,
+			expected: nil,
+		},
+		{
+			name:     "noop if network interface doesn't reference the load balancer",
+			existing: newTestNIC(nil, testOtherPoolID),
+			expected: nil,
+		},
+		{
+			name:     "references to the load balancer are removed and recorded",
+			existing: newTestNIC(nil, testOtherPoolID, testPoolID, testNATRuleID),
+			expected: newTestNIC(map[string]*string{
+				testTagKey: ptr.To("ipconfig1/backendAddressPools/my-cluster-internal-lb-backendPool;ipconfig1/inboundNatRules/my-vm"),
+			}, testOtherPoolID),
+		},
+		{
+			name: "references recorded by an earlier attempt are kept",
+			existing: newTestNIC(map[string]*string{
+				testTagKey: ptr.To("ipconfig1/backendAddressPools/my-cluster-internal-lb-backendPool"),
+			}, testNATRuleID),
+			expected: newTestNIC(map[string]*string{
+				testTagKey: ptr.To("ipconfig1/backendAddressPools/my-cluster-internal-lb-backendPool;ipconfig1/inboundNatRules/my-vm"),
+			}),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			spec := &NICDisassociateSpec{Name: "my-vm-nic", ResourceGroup: testResourceGroup, LoadBalancerName: testLBName}
+			result, err := spec.Parameters(context.TODO(), tc.existing)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestNICReassociateSpecParameters(t *testing.T) {
+	recorded := func() map[string]*string {
+		return map[string]*string{
+			testTagKey: ptr.To("ipconfig1/backendAddressPools/my-cluster-internal-lb-backendPool;ipconfig1/inboundNatRules/my-vm"),
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		existing      interface{}
+		available     map[string]bool
+		expected      interface{}
+		expectedError string
+	}{
+		{
+			name:     "noop if network interface doesn't exist",
+			existing: nil,
+			expected: nil,
+		},
+		{
+			name:     "noop if network interface wasn't migrated",
+			existing: newTestNIC(nil, testOtherPoolID),
+			expected: nil,
+		},
+		{
+			name:     "available references are restored and the tag is removed",
+			existing: newTestNIC(recorded(), testOtherPoolID),
+			available: map[string]bool{
+				loadBalancerChildKey(testLBName, backendAddressPools, "my-cluster-internal-lb-backendPool"): true,
+				loadBalancerChildKey(testLBName, inboundNatRules, "my-vm"):                                  true,
+			},
+			expected: newTestNIC(map[string]*string{}, testOtherPoolID, testPoolID, testNATRuleID),
+		},
+		{
+			name:     "unavailable references are kept in the tag",
+			existing: newTestNIC(recorded()),
+			available: map[string]bool{
+				loadBalancerChildKey(testLBName, backendAddressPools, "my-cluster-internal-lb-backendPool"): true,
+			},
+			expected: newTestNIC(map[string]*string{
+				testTagKey: ptr.To("ipconfig1/inboundNatRules/my-vm"),
+			}, testPoolID),
+		},
+		{
+			name: "error if the IP configuration doesn't exist",
+			existing: newTestNIC(map[string]*string{
+				testTagKey: ptr.To("ipconfig2/backendAddressPools/my-cluster-internal-lb-backendPool"),
+			}),
+			available: map[string]bool{
+				loadBalancerChildKey(testLBName, backendAddressPools, "my-cluster-internal-lb-backendPool"): true,
+			},
+			expectedError: "network interface my-vm-nic has no IP configuration ipconfig2",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			spec := &NICReassociateSpec{
+				Name:           "my-vm-nic",
+				ResourceGroup:  testResourceGroup,
+				SubscriptionID: testSubscriptionID,
+				Available:      tc.available,
+			}
+			result, err := spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result).To(Equal(tc.expected))
+		})
+	}
+}
+
+// newTestNIC returns a network interface with the given tags whose IP configuration references the given load
+// balancer backend pools and inbound NAT rules.
+func newTestNIC(tags map[string]*string, ids ...string) network.Interface {
+	pools := make([]network.BackendAddressPool, 0)
+	natRules := make([]network.InboundNatRule, 0)
+	for _, id := range ids {
+		if _, childType, _ := parseLoadBalancerChildID(id); childType == inboundNatRules {
+			natRules = append(natRules, network.InboundNatRule{ID: ptr.To(id)})
+		} else {
+			pools = append(pools, network.BackendAddressPool{ID: ptr.To(id)})
+		}
+	}
+	return network.Interface{
+		Name: ptr.To("my-vm-nic"),
+		Tags: tags,
+		InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+			IPConfigurations: &[]network.InterfaceIPConfiguration{
+				{
+					Name: ptr.To("ipconfig1"),
+					InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
+						LoadBalancerBackendAddressPools: &pools,
+						LoadBalancerInboundNatRules:     &natRules,
+					},
+				},
+			},
+		},
+	}
+}
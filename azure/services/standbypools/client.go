@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standbypools
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/asyncpoller"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// apiVersion is the Microsoft.StandbyPool API version used for standby virtual machine pools.
+// There is no dedicated SDK client for this resource provider, so standby pools are managed as generic resources.
+const apiVersion = "2024-03-01"
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	resources      *armresources.Client
+	subscriptionID string
+}
+
+// newClient creates a new standby pools client from an authorizer.
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment(), serviceName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create standbypools client options")
+	}
+	client, err := armresources.NewClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create armresources client")
+	}
+	return &azureClient{resources: client, subscriptionID: auth.SubscriptionID()}, nil
+}
+
+// resourceID returns the ID of the standby pool described by a spec.
+func (ac *azureClient) resourceID(spec azure.ResourceSpecGetter) string {
+	return azure.StandbyPoolID(ac.subscriptionID, spec.ResourceGroupName(), spec.ResourceName())
+}
+
+// Get gets the specified standby pool.
+func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "standbypools.azureClient.Get")
+	defer done()
+
+	resp, err := ac.resources.GetByID(ctx, ac.resourceID(spec), apiVersion, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GenericResource, nil
+}
+
+// CreateOrUpdateAsync creates or updates a standby pool asynchronously.
+// It sends a PUT request to Azure and if accepted without error, the func will return a Poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller *runtime.Poller[armresources.ClientCreateOrUpdateByIDResponse], err error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "standbypools.azureClient.CreateOrUpdateAsync")
+	defer done()
+
+	standbyPool, ok := parameters.(armresources.GenericResource)
+	if !ok {
+		return nil, nil, errors.Errorf("%T is not an armresources.GenericResource", parameters)
+	}
+
+	opts := &armresources.ClientBeginCreateOrUpdateByIDOptions{ResumeToken: resumeToken}
+	log.V(4).Info("sending request", "resumeToken", resumeToken)
+	poller, err = ac.resources.BeginCreateOrUpdateByID(ctx, ac.resourceID(spec), apiVersion, standbyPool, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	pollOpts := &runtime.PollUntilDoneOptions{Frequency: asyncpoller.DefaultPollerFrequency}
+	resp, err := poller.PollUntilDone(ctx, pollOpts)
+	if err != nil {
+		// if an error occurs, return the poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, poller, err
+	}
+
+	// if the operation completed, return a nil poller
+	return resp.GenericResource, nil, err
+}
+
+// DeleteAsync deletes a standby pool asynchronously. DeleteAsync sends a DELETE
+// request to Azure and if accepted without error, the func will return a Poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller *runtime.Poller[armresources.ClientDeleteByIDResponse], err error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "standbypools.azureClient.DeleteAsync")
+	defer done()
+
+	opts := &armresources.ClientBeginDeleteByIDOptions{ResumeToken: resumeToken}
+	log.V(4).Info("sending request", "resumeToken", resumeToken)
+	poller, err = ac.resources.BeginDeleteByID(ctx, ac.resourceID(spec), apiVersion, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	pollOpts := &runtime.PollUntilDoneOptions{Frequency: asyncpoller.DefaultPollerFrequency}
+	_, err = poller.PollUntilDone(ctx, pollOpts)
+	if err != nil {
+		// if an error occurs, return the Poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return poller, err
+	}
+
+	// if the operation completed, return a nil poller.
+	return nil, err
+}
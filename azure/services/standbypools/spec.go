@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standbypools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// StandbyPoolSpec defines the specification for the standby virtual machine pool of a scale set.
+type StandbyPoolSpec struct {
+	Name                string
+	ResourceGroup       string
+	SubscriptionID      string
+	Location            string
+	ScaleSetName        string
+	MaxReadyCapacity    int64
+	MinReadyCapacity    *int64
+	VirtualMachineState string
+	ClusterName         string
+	AdditionalTags      infrav1.Tags
+}
+
+// standbyPoolProperties are the properties of a standby virtual machine pool.
+type standbyPoolProperties struct {
+	AttachedVirtualMachineScaleSetID string                       `json:"attachedVirtualMachineScaleSetId"`
+	ElasticityProfile                standbyPoolElasticityProfile `json:"elasticityProfile"`
+	VirtualMachineState              string                       `json:"virtualMachineState"`
+}
+
+// standbyPoolElasticityProfile is the capacity of a standby virtual machine pool.
+type standbyPoolElasticityProfile struct {
+	MaxReadyCapacity int64  `json:"maxReadyCapacity"`
+	MinReadyCapacity *int64 `json:"minReadyCapacity,omitempty"`
+}
+
+// ResourceName returns the name of the standby pool.
+func (s *StandbyPoolSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *StandbyPoolSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for standby pools.
+func (s *StandbyPoolSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the standby pool.
+func (s *StandbyPoolSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
+	properties := standbyPoolProperties{
+		AttachedVirtualMachineScaleSetID: azure.VMSSID(s.SubscriptionID, s.ResourceGroup, s.ScaleSetName),
+		ElasticityProfile: standbyPoolElasticityProfile{
+			MaxReadyCapacity: s.MaxReadyCapacity,
+			MinReadyCapacity: s.MinReadyCapacity,
+		},
+		VirtualMachineState: s.VirtualMachineState,
+	}
+
+	if existing != nil {
+		existingStandbyPool, ok := existing.(armresources.GenericResource)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armresources.GenericResource", existing)
+		}
+		existingProperties, err := toStandbyPoolProperties(existingStandbyPool.Properties)
+		if err != nil {
+			return nil, err
+		}
+		if propertiesMatch(existingProperties, properties) {
+			// Skip update for the standby pool as it exists with expected values
+			return nil, nil
+		}
+	}
+
+	return armresources.GenericResource{
+		Location:   ptr.To(s.Location),
+		Properties: properties,
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        ptr.To(s.Name),
+			Additional:  s.AdditionalTags,
+		})),
+	}, nil
+}
+
+// toStandbyPoolProperties converts the untyped properties of a generic resource to the properties of a standby pool.
+func toStandbyPoolProperties(properties interface{}) (standbyPoolProperties, error) {
+	var result standbyPoolProperties
+	data, err := json.Marshal(properties)
+	if err != nil {
+		return result, errors.Wrap(err, "failed to marshal standby pool properties")
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, errors.Wrap(err, "failed to unmarshal standby pool properties")
+	}
+	return result, nil
+}
+
+// propertiesMatch returns true if the properties of an existing standby pool match the desired ones.
+// Azure may return the ID of the attached scale set with a different casing.
+func propertiesMatch(existing, desired standbyPoolProperties) bool {
+	return strings.EqualFold(existing.AttachedVirtualMachineScaleSetID, desired.AttachedVirtualMachineScaleSetID) &&
+		existing.ElasticityProfile.MaxReadyCapacity == desired.ElasticityProfile.MaxReadyCapacity &&
+		ptr.Equal(existing.ElasticityProfile.MinReadyCapacity, desired.ElasticityProfile.MinReadyCapacity) &&
+		existing.VirtualMachineState == desired.VirtualMachineState
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standbypools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+func TestParameters(t *testing.T) {
+	testcases := []struct {
+		name          string
+		spec          *StandbyPoolSpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name:     "new standby pool",
+			spec:     &fakeStandbyPoolSpec,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armresources.GenericResource{}))
+				standbyPool := result.(armresources.GenericResource)
+				g.Expect(standbyPool.Location).To(Equal(ptr.To("westus")))
+				g.Expect(standbyPool.Properties).To(Equal(standbyPoolProperties{
+					AttachedVirtualMachineScaleSetID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/my-vmss",
+					ElasticityProfile:                standbyPoolElasticityProfile{MaxReadyCapacity: 10},
+					VirtualMachineState:              "Deallocated",
+				}))
+				g.Expect(standbyPool.Tags).To(HaveKeyWithValue("sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster", ptr.To("owned")))
+			},
+		},
+		{
+			name: "existing standby pool with the expected properties",
+			spec: &fakeStandbyPoolSpec,
+			existing: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"attachedVirtualMachineScaleSetId": "/subscriptions/123/resourceGroups/MY-RG/providers/Microsoft.Compute/virtualMachineScaleSets/my-vmss",
+					"elasticityProfile":                map[string]interface{}{"maxReadyCapacity": 10},
+					"virtualMachineState":              "Deallocated",
+					"provisioningState":                "Succeeded",
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "existing standby pool with a different capacity",
+			spec: &fakeStandbyPoolSpec,
+			existing: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"attachedVirtualMachineScaleSetId": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/my-vmss",
+					"elasticityProfile":                map[string]interface{}{"maxReadyCapacity": 5},
+					"virtualMachineState":              "Deallocated",
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armresources.GenericResource{}))
+				standbyPool := result.(armresources.GenericResource)
+				g.Expect(standbyPool.Properties.(standbyPoolProperties).ElasticityProfile.MaxReadyCapacity).To(Equal(int64(10)))
+			},
+		},
+		{
+			name:          "existing resource is not a generic resource",
+			spec:          &fakeStandbyPoolSpec,
+			existing:      "not a standby pool",
+			expect:        func(g *WithT, result interface{}) {},
+			expectedError: "string is not an armresources.GenericResource",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			tc.expect(g, result)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standbypools
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/asyncpoller"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const serviceName = "standbypools"
+
+// StandbyPoolScope defines the scope interface for a standby pool service.
+type StandbyPoolScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	StandbyPoolSpec() azure.ResourceSpecGetter
+	HasStandbyPool() bool
+	StandbyPoolStatusResource() conditions.Setter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope StandbyPoolScope
+	asyncpoller.Reconciler
+}
+
+// New creates a new standby pool service.
+func New(scope StandbyPoolScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope: scope,
+		Reconciler: asyncpoller.New[armresources.ClientCreateOrUpdateByIDResponse,
+			armresources.ClientDeleteByIDResponse](scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile idempotently creates or updates the standby pool of a scale set, and deletes it once it is removed from
+// the spec.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "standbypools.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	if !s.Scope.HasStandbyPool() {
+		return s.deleteRemoved(ctx)
+	}
+
+	_, err := s.CreateOrUpdateResource(ctx, s.Scope.StandbyPoolSpec(), serviceName)
+	s.Scope.UpdatePutStatus(infrav1.StandbyPoolReadyCondition, serviceName, err)
+	return err
+}
+
+// Delete deletes the standby pool of a scale set.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "standbypools.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	if !s.Scope.HasStandbyPool() && !conditions.Has(s.Scope.StandbyPoolStatusResource(), infrav1.StandbyPoolReadyCondition) {
+		return nil
+	}
+
+	err := s.DeleteResource(ctx, s.Scope.StandbyPoolSpec(), serviceName)
+	s.Scope.UpdateDeleteStatus(infrav1.StandbyPoolReadyCondition, serviceName, err)
+	return err
+}
+
+// deleteRemoved deletes a standby pool that was removed from the spec. The StandbyPoolReady condition tells whether
+// a standby pool was created, which avoids a call to Azure for every scale set without one.
+func (s *Service) deleteRemoved(ctx context.Context) error {
+	if !conditions.Has(s.Scope.StandbyPoolStatusResource(), infrav1.StandbyPoolReadyCondition) {
+		return nil
+	}
+
+	if err := s.DeleteResource(ctx, s.Scope.StandbyPoolSpec(), serviceName); err != nil {
+		s.Scope.UpdateDeleteStatus(infrav1.StandbyPoolReadyCondition, serviceName, err)
+		return err
+	}
+	conditions.Delete(s.Scope.StandbyPoolStatusResource(), infrav1.StandbyPoolReadyCondition)
+	return nil
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standbypools
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/standbypools/mock_standbypools"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+var (
+	fakeStandbyPoolSpec = StandbyPoolSpec{
+		Name:                "my-vmss",
+		ResourceGroup:       "my-rg",
+		SubscriptionID:      "123",
+		Location:            "westus",
+		ScaleSetName:        "my-vmss",
+		MaxReadyCapacity:    10,
+		VirtualMachineState: "Deallocated",
+		ClusterName:         "my-cluster",
+	}
+	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+)
+
+func TestReconcileStandbyPool(t *testing.T) {
+	testcases := []struct {
+		name              string
+		hadStandbyPool    bool
+		expectedError     string
+		expectedCondition bool
+		expect            func(s *mock_standbypools.MockStandbyPoolScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:              "noop if no standby pool is set",
+			expectedError:     "",
+			expectedCondition: false,
+			expect: func(s *mock_standbypools.MockStandbyPoolScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.HasStandbyPool().Return(false)
+			},
+		},
+		{
+			name:              "create a standby pool",
+			expectedError:     "",
+			expectedCondition: false,
+			expect: func(s *mock_standbypools.MockStandbyPoolScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.HasStandbyPool().Return(true)
+				s.StandbyPoolSpec().Return(&fakeStandbyPoolSpec)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeStandbyPoolSpec, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.StandbyPoolReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:              "fail to create a standby pool",
+			expectedError:     internalError.Error(),
+			expectedCondition: false,
+			expect: func(s *mock_standbypools.MockStandbyPoolScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.HasStandbyPool().Return(true)
+				s.StandbyPoolSpec().Return(&fakeStandbyPoolSpec)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeStandbyPoolSpec, serviceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.StandbyPoolReadyCondition, serviceName, internalError)
+			},
+		},
+		{
+			name:              "delete a standby pool removed from the spec",
+			hadStandbyPool:    true,
+			expectedError:     "",
+			expectedCondition: false,
+			expect: func(s *mock_standbypools.MockStandbyPoolScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.HasStandbyPool().Return(false)
+				s.StandbyPoolSpec().Return(&fakeStandbyPoolSpec)
+				r.DeleteResource(gomockinternal.AContext(), &fakeStandbyPoolSpec, serviceName).Return(nil)
+			},
+		},
+		{
+			name:              "fail to delete a standby pool removed from the spec",
+			hadStandbyPool:    true,
+			expectedError:     internalError.Error(),
+			expectedCondition: true,
+			expect: func(s *mock_standbypools.MockStandbyPoolScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.HasStandbyPool().Return(false)
+				s.StandbyPoolSpec().Return(&fakeStandbyPoolSpec)
+				r.DeleteResource(gomockinternal.AContext(), &fakeStandbyPoolSpec, serviceName).Return(internalError)
+				s.UpdateDeleteStatus(infrav1.StandbyPoolReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_standbypools.NewMockStandbyPoolScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			amp := &infrav1exp.AzureMachinePool{}
+			if tc.hadStandbyPool {
+				conditions.MarkTrue(amp, infrav1.StandbyPoolReadyCondition)
+			}
+			scopeMock.EXPECT().StandbyPoolStatusResource().Return(amp).AnyTimes()
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tc.hadStandbyPool {
+				g.Expect(conditions.Has(amp, infrav1.StandbyPoolReadyCondition)).To(Equal(tc.expectedCondition))
+			}
+		})
+	}
+}
+
+func TestDeleteStandbyPool(t *testing.T) {
+	testcases := []struct {
+		name           string
+		hadStandbyPool bool
+		expectedError  string
+		expect         func(s *mock_standbypools.MockStandbyPoolScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if no standby pool was created",
+			expectedError: "",
+			expect: func(s *mock_standbypools.MockStandbyPoolScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.HasStandbyPool().Return(false)
+			},
+		},
+		{
+			name:          "delete a standby pool",
+			expectedError: "",
+			expect: func(s *mock_standbypools.MockStandbyPoolScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.HasStandbyPool().Return(true)
+				s.StandbyPoolSpec().Return(&fakeStandbyPoolSpec)
+				r.DeleteResource(gomockinternal.AContext(), &fakeStandbyPoolSpec, serviceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.StandbyPoolReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:           "delete a standby pool removed from the spec",
+			hadStandbyPool: true,
+			expectedError:  "",
+			expect: func(s *mock_standbypools.MockStandbyPoolScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.HasStandbyPool().Return(false)
+				s.StandbyPoolSpec().Return(&fakeStandbyPoolSpec)
+				r.DeleteResource(gomockinternal.AContext(), &fakeStandbyPoolSpec, serviceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.StandbyPoolReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to delete a standby pool",
+			expectedError: internalError.Error(),
+			expect: func(s *mock_standbypools.MockStandbyPoolScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.HasStandbyPool().Return(true)
+				s.StandbyPoolSpec().Return(&fakeStandbyPoolSpec)
+				r.DeleteResource(gomockinternal.AContext(), &fakeStandbyPoolSpec, serviceName).Return(internalError)
+				s.UpdateDeleteStatus(infrav1.StandbyPoolReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_standbypools.NewMockStandbyPoolScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			amp := &infrav1exp.AzureMachinePool{}
+			if tc.hadStandbyPool {
+				conditions.MarkTrue(amp, infrav1.StandbyPoolReadyCondition)
+			}
+			scopeMock.EXPECT().StandbyPoolStatusResource().Return(amp).AnyTimes()
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storageaccounts
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-09-01/storage"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// StorageAccountSpec defines the specification for a storage account.
+type StorageAccountSpec struct {
+	Name           string
+	ResourceGroup  string
+	Location       string
+	SKU            string
+	ClusterName    string
+	AdditionalTags infrav1.Tags
+}
+
+// ResourceName returns the name of the storage account.
+func (s *StorageAccountSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the storage account.
+func (s *StorageAccountSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for storage accounts.
+func (s *StorageAccountSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the storage account.
+// An existing account is never updated.
+func (s *StorageAccountSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		if _, ok := existing.(storage.Account); !ok {
+			return nil, errors.Errorf("%T is not a storage.Account", existing)
+		}
+		return nil, nil
+	}
+
+	return storage.AccountCreateParameters{
+		Sku:      &storage.Sku{Name: storage.SkuName(s.SKU)},
+		Kind:     storage.KindStorageV2,
+		Location: ptr.To(s.Location),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        ptr.To(s.Name),
+			Additional:  s.AdditionalTags,
+		})),
+		AccountPropertiesCreateParameters: &storage.AccountPropertiesCreateParameters{
+			EnableHTTPSTrafficOnly: ptr.To(true),
+			AllowBlobPublicAccess:  ptr.To(false),
+			MinimumTLSVersion:      storage.MinimumTLSVersionTLS12,
+		},
+	}, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storageaccounts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-09-01/storage"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+func TestStorageAccountSpec_Parameters(t *testing.T) {
+	testcases := []struct {
+		name          string
+		spec          *StorageAccountSpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name: "new storage account",
+			spec: fakeStorageAccount,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(storage.AccountCreateParameters{
+					Sku:      &storage.Sku{Name: storage.SkuNameStandardLRS},
+					Kind:     storage.KindStorageV2,
+					Location: ptr.To("eastus"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": ptr.To("owned"),
+						"Name": ptr.To("diagmypool"),
+					},
+					AccountPropertiesCreateParameters: &storage.AccountPropertiesCreateParameters{
+						EnableHTTPSTrafficOnly: ptr.To(true),
+						AllowBlobPublicAccess:  ptr.To(false),
+						MinimumTLSVersion:      storage.MinimumTLSVersionTLS12,
+					},
+				}))
+			},
+		},
+		{
+			name:     "existing storage account is not updated",
+			spec:     fakeStorageAccount,
+			existing: storage.Account{Name: ptr.To("diagmypool")},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:          "existing is not a storage account",
+			spec:          fakeStorageAccount,
+			existing:      "not a storage account",
+			expectedError: "string is not a storage.Account",
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			tc.expect(g, result)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storageaccounts
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/storageaccounts/mock_storageaccounts"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeStorageAccount = &StorageAccountSpec{
+		Name:          "diagmypool",
+		ResourceGroup: "my-rg",
+		Location:      "eastus",
+		SKU:           "Standard_LRS",
+		ClusterName:   "my-cluster",
+	}
+
+	errFake = errors.New("this is an error")
+)
+
+func TestReconcileStorageAccount(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "no diagnostics storage account",
+			expectedError: "",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiagnosticsStorageAccountSpec().Return(nil)
+			},
+		},
+		{
+			name:          "create diagnostics storage account successfully",
+			expectedError: "",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiagnosticsStorageAccountSpec().Return(fakeStorageAccount)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), fakeStorageAccount, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.DiagnosticsStorageAccountReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "diagnostics storage account creation fails",
+			expectedError: "this is an error",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiagnosticsStorageAccountSpec().Return(fakeStorageAccount)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), fakeStorageAccount, serviceName).Return(nil, errFake)
+				s.UpdatePutStatus(infrav1.DiagnosticsStorageAccountReadyCondition, serviceName, errFake)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_storageaccounts.NewMockStorageAccountScope(mockCtrl)
+			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), reconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: reconcilerMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteStorageAccount(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "no diagnostics storage account",
+			expectedError: "",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiagnosticsStorageAccountSpec().Return(nil)
+			},
+		},
+		{
+			name:          "delete diagnostics storage account successfully",
+			expectedError: "",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiagnosticsStorageAccountSpec().Return(fakeStorageAccount)
+				r.DeleteResource(gomockinternal.AContext(), fakeStorageAccount, serviceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.DiagnosticsStorageAccountReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "diagnostics storage account deletion fails",
+			expectedError: "this is an error",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiagnosticsStorageAccountSpec().Return(fakeStorageAccount)
+				r.DeleteResource(gomockinternal.AContext(), fakeStorageAccount, serviceName).Return(errFake)
+				s.UpdateDeleteStatus(infrav1.DiagnosticsStorageAccountReadyCondition, serviceName, errFake)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_storageaccounts.NewMockStorageAccountScope(mockCtrl)
+			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), reconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: reconcilerMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
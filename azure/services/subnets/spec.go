@@ -18,6 +18,7 @@ package subnets
 
 import (
 	"context"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
 	"github.com/google/go-cmp/cmp"
@@ -29,18 +30,20 @@ import (
 
 // SubnetSpec defines the specification for a Subnet.
 type SubnetSpec struct {
-	Name              string
-	ResourceGroup     string
-	SubscriptionID    string
-	CIDRs             []string
-	VNetName          string
-	VNetResourceGroup string
-	IsVNetManaged     bool
-	RouteTableName    string
-	SecurityGroupName string
-	Role              infrav1.SubnetRole
-	NatGatewayName    string
-	ServiceEndpoints  infrav1.ServiceEndpoints
+	Name                    string
+	ResourceGroup           string
+	SubscriptionID          string
+	CIDRs                   []string
+	VNetName                string
+	VNetResourceGroup       string
+	IsVNetManaged           bool
+	RouteTableName          string
+	SecurityGroupName       string
+	Role                    infrav1.SubnetRole
+	NatGatewayName          string
+	ServiceEndpoints        infrav1.ServiceEndpoints
+	ServiceEndpointPolicies []string
+	Delegations             []string
 }
 
 // ResourceName returns the name of the subnet.
@@ -111,6 +114,27 @@ func (s *SubnetSpec) Parameters(ctx context.Context, existing interface{}) (para
 	}
 	subnetProperties.ServiceEndpoints = &serviceEndpoints
 
+	if len(s.ServiceEndpointPolicies) > 0 {
+		policies := make([]network.ServiceEndpointPolicy, 0, len(s.ServiceEndpointPolicies))
+		for _, policyID := range s.ServiceEndpointPolicies {
+			policies = append(policies, network.ServiceEndpointPolicy{ID: ptr.To(policyID)})
+		}
+		subnetProperties.ServiceEndpointPolicies = &policies
+	}
+
+	if len(s.Delegations) > 0 {
+		delegations := make([]network.Delegation, 0, len(s.Delegations))
+		for _, service := range s.Delegations {
+			delegations = append(delegations, network.Delegation{
+				Name: ptr.To(strings.ReplaceAll(service, "/", ".")),
+				ServiceDelegationPropertiesFormat: &network.ServiceDelegationPropertiesFormat{
+					ServiceName: ptr.To(service),
+				},
+			})
+		}
+		subnetProperties.Delegations = &delegations
+	}
+
 	return network.Subnet{
 		SubnetPropertiesFormat: &subnetProperties,
 	}, nil
@@ -128,6 +152,16 @@ func (s *SubnetSpec) shouldUpdate(existingSubnet network.Subnet) bool {
 		return true
 	}
 
+	// Update the subnet if a delegation is missing.
+	if s.hasMissingDelegation(existingSubnet) {
+		return true
+	}
+
+	// Update the subnet if the service endpoint policies changed.
+	if s.serviceEndpointPoliciesChanged(existingSubnet) {
+		return true
+	}
+
 	// Update the subnet if the service endpoints changed.
 	if existingSubnet.ServiceEndpoints != nil || len(s.ServiceEndpoints) > 0 {
 		var existingServiceEndpoints []network.ServiceEndpointPropertiesFormat
@@ -147,3 +181,40 @@ func (s *SubnetSpec) shouldUpdate(existingSubnet network.Subnet) bool {
 	}
 	return false
 }
+
+// hasMissingDelegation returns true if one of the delegations of the spec is not set on an existing subnet.
+func (s *SubnetSpec) hasMissingDelegation(existingSubnet network.Subnet) bool {
+	existing := make(map[string]bool)
+	if existingSubnet.SubnetPropertiesFormat != nil && existingSubnet.Delegations != nil {
+		for _, delegation := range *existingSubnet.Delegations {
+			if delegation.ServiceDelegationPropertiesFormat != nil {
+				existing[ptr.Deref(delegation.ServiceName, "")] = true
+			}
+		}
+	}
+	for _, service := range s.Delegations {
+		if !existing[service] {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceEndpointPoliciesChanged returns true if the Service Endpoint Policies of an existing subnet differ from the spec.
+func (s *SubnetSpec) serviceEndpointPoliciesChanged(existingSubnet network.Subnet) bool {
+	existing := make(map[string]bool)
+	if existingSubnet.SubnetPropertiesFormat != nil && existingSubnet.ServiceEndpointPolicies != nil {
+		for _, policy := range *existingSubnet.ServiceEndpointPolicies {
+			existing[strings.ToLower(ptr.Deref(policy.ID, ""))] = true
+		}
+	}
+	if len(existing) != len(s.ServiceEndpointPolicies) {
+		return true
+	}
+	for _, policyID := range s.ServiceEndpointPolicies {
+		if !existing[strings.ToLower(policyID)] {
+			return true
+		}
+	}
+	return false
+}
@@ -51,6 +51,60 @@ var (
 		},
 	}
 
+	fakeDelegatedSubnetSpec = SubnetSpec{
+		Name:              "my-netapp-subnet",
+		ResourceGroup:     "my-rg",
+		SubscriptionID:    "123",
+		CIDRs:             []string{"10.255.254.0/24"},
+		IsVNetManaged:     true,
+		VNetName:          "my-vnet",
+		VNetResourceGroup: "my-rg",
+		Role:              infrav1.SubnetNetApp,
+		Delegations:       []string{"Microsoft.NetApp/volumes"},
+	}
+
+	fakeDelegatedSubnetParams = network.Subnet{
+		SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+			AddressPrefix:    ptr.To("10.255.254.0/24"),
+			ServiceEndpoints: &[]network.ServiceEndpointPropertiesFormat{},
+			Delegations: &[]network.Delegation{
+				{
+					Name: ptr.To("Microsoft.NetApp.volumes"),
+					ServiceDelegationPropertiesFormat: &network.ServiceDelegationPropertiesFormat{
+						ServiceName: ptr.To("Microsoft.NetApp/volumes"),
+					},
+				},
+			},
+		},
+	}
+
+	fakeSubnetWithPoliciesSpec = SubnetSpec{
+		Name:              "my-subnet-1",
+		ResourceGroup:     "my-rg",
+		SubscriptionID:    "123",
+		CIDRs:             []string{"10.0.0.0/16"},
+		IsVNetManaged:     true,
+		VNetName:          "my-vnet",
+		VNetResourceGroup: "my-rg",
+		Role:              infrav1.SubnetNode,
+		ServiceEndpoints: infrav1.ServiceEndpoints{
+			{Service: "Microsoft.Storage", Locations: []string{"*"}},
+		},
+		ServiceEndpointPolicies: []string{"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/serviceEndpointPolicies/my-policy"},
+	}
+
+	fakeSubnetWithPoliciesParams = network.Subnet{
+		SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+			AddressPrefix: ptr.To("10.0.0.0/16"),
+			ServiceEndpoints: &[]network.ServiceEndpointPropertiesFormat{
+				{Service: ptr.To("Microsoft.Storage"), Locations: &[]string{"*"}},
+			},
+			ServiceEndpointPolicies: &[]network.ServiceEndpointPolicy{
+				{ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/serviceEndpointPolicies/my-policy")},
+			},
+		},
+	}
+
 	fakeSubnetMultipleCidrSpec = SubnetSpec{
 		Name:              "my-subnet-1",
 		ResourceGroup:     "my-rg",
@@ -132,6 +186,24 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:     "get parameters for subnet delegated to a service",
+			spec:     &fakeDelegatedSubnetSpec,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(fakeDelegatedSubnetParams))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "get parameters for subnet with service endpoint policies",
+			spec:     &fakeSubnetWithPoliciesSpec,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(fakeSubnetWithPoliciesParams))
+			},
+			expectedError: "",
+		},
 		{
 			name:     "error vnet is not managed but subnet is missing",
 			spec:     &fakeSubnetSpecNotManaged,
@@ -189,18 +261,20 @@ func TestParameters(t *testing.T) {
 
 func TestSubnetSpec_shouldUpdate(t *testing.T) {
 	type fields struct {
-		Name              string
-		ResourceGroup     string
-		SubscriptionID    string
-		CIDRs             []string
-		VNetName          string
-		VNetResourceGroup string
-		IsVNetManaged     bool
-		RouteTableName    string
-		SecurityGroupName string
-		Role              infrav1.SubnetRole
-		NatGatewayName    string
-		ServiceEndpoints  infrav1.ServiceEndpoints
+		Name                    string
+		ResourceGroup           string
+		SubscriptionID          string
+		CIDRs                   []string
+		VNetName                string
+		VNetResourceGroup       string
+		IsVNetManaged           bool
+		RouteTableName          string
+		SecurityGroupName       string
+		Role                    infrav1.SubnetRole
+		NatGatewayName          string
+		ServiceEndpoints        infrav1.ServiceEndpoints
+		Delegations             []string
+		ServiceEndpointPolicies []string
 	}
 	type args struct {
 		existingSubnet network.Subnet
@@ -268,6 +342,107 @@ func TestSubnetSpec_shouldUpdate(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "subnet should be updated if a delegation is missing",
+			fields: fields{
+				Name:           "my-subnet",
+				ResourceGroup:  "my-rg",
+				SubscriptionID: "123",
+				IsVNetManaged:  true,
+				Delegations:    []string{"Microsoft.NetApp/volumes"},
+			},
+			args: args{
+				existingSubnet: network.Subnet{
+					Name:                   ptr.To("my-subnet"),
+					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "subnet should not be updated if delegations are set",
+			fields: fields{
+				Name:           "my-subnet",
+				ResourceGroup:  "my-rg",
+				SubscriptionID: "123",
+				IsVNetManaged:  true,
+				Delegations:    []string{"Microsoft.NetApp/volumes"},
+			},
+			args: args{
+				existingSubnet: network.Subnet{
+					Name: ptr.To("my-subnet"),
+					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+						Delegations: &[]network.Delegation{
+							{
+								Name: ptr.To("Microsoft.NetApp.volumes"),
+								ServiceDelegationPropertiesFormat: &network.ServiceDelegationPropertiesFormat{
+									ServiceName: ptr.To("Microsoft.NetApp/volumes"),
+								},
+							},
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "subnet should be updated if a service endpoint policy is added",
+			fields: fields{
+				Name:                    "my-subnet",
+				ResourceGroup:           "my-rg",
+				SubscriptionID:          "123",
+				IsVNetManaged:           true,
+				ServiceEndpointPolicies: []string{"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/serviceEndpointPolicies/my-policy"},
+			},
+			args: args{
+				existingSubnet: network.Subnet{
+					Name:                   ptr.To("my-subnet"),
+					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "subnet should be updated if a service endpoint policy is removed",
+			fields: fields{
+				Name:           "my-subnet",
+				ResourceGroup:  "my-rg",
+				SubscriptionID: "123",
+				IsVNetManaged:  true,
+			},
+			args: args{
+				existingSubnet: network.Subnet{
+					Name: ptr.To("my-subnet"),
+					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+						ServiceEndpointPolicies: &[]network.ServiceEndpointPolicy{
+							{ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/serviceEndpointPolicies/my-policy")},
+						},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "subnet should not be updated if service endpoint policies only differ in casing",
+			fields: fields{
+				Name:                    "my-subnet",
+				ResourceGroup:           "my-rg",
+				SubscriptionID:          "123",
+				IsVNetManaged:           true,
+				ServiceEndpointPolicies: []string{"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/serviceEndpointPolicies/my-policy"},
+			},
+			args: args{
+				existingSubnet: network.Subnet{
+					Name: ptr.To("my-subnet"),
+					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+						ServiceEndpointPolicies: &[]network.ServiceEndpointPolicy{
+							{ID: ptr.To("/subscriptions/123/resourceGroups/MY-RG/providers/Microsoft.Network/serviceEndpointPolicies/my-policy")},
+						},
+					},
+				},
+			},
+			want: false,
+		},
 		{
 			name: "subnet should not be updated if other properties change",
 			fields: fields{
@@ -291,18 +466,20 @@ func TestSubnetSpec_shouldUpdate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := &SubnetSpec{
-				Name:              tt.fields.Name,
-				ResourceGroup:     tt.fields.ResourceGroup,
-				SubscriptionID:    tt.fields.SubscriptionID,
-				CIDRs:             tt.fields.CIDRs,
-				VNetName:          tt.fields.VNetName,
-				VNetResourceGroup: tt.fields.VNetResourceGroup,
-				IsVNetManaged:     tt.fields.IsVNetManaged,
-				RouteTableName:    tt.fields.RouteTableName,
-				SecurityGroupName: tt.fields.SecurityGroupName,
-				Role:              tt.fields.Role,
-				NatGatewayName:    tt.fields.NatGatewayName,
-				ServiceEndpoints:  tt.fields.ServiceEndpoints,
+				Name:                    tt.fields.Name,
+				ResourceGroup:           tt.fields.ResourceGroup,
+				SubscriptionID:          tt.fields.SubscriptionID,
+				CIDRs:                   tt.fields.CIDRs,
+				VNetName:                tt.fields.VNetName,
+				VNetResourceGroup:       tt.fields.VNetResourceGroup,
+				IsVNetManaged:           tt.fields.IsVNetManaged,
+				RouteTableName:          tt.fields.RouteTableName,
+				SecurityGroupName:       tt.fields.SecurityGroupName,
+				Role:                    tt.fields.Role,
+				NatGatewayName:          tt.fields.NatGatewayName,
+				ServiceEndpoints:        tt.fields.ServiceEndpoints,
+				Delegations:             tt.fields.Delegations,
+				ServiceEndpointPolicies: tt.fields.ServiceEndpointPolicies,
 			}
 			if got := s.shouldUpdate(tt.args.existingSubnet); got != tt.want {
 				t.Errorf("SubnetSpec.shouldUpdate() = %v, want %v", got, tt.want)
@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trafficmanager
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/trafficmanager/mgmt/2018-04-01/trafficmanager"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// externalEndpointType is the Traffic Manager endpoint type used for API server endpoints.
+const externalEndpointType = "ExternalEndpoints"
+
+// azureEndpointsClient contains the Azure go-sdk Client for Traffic Manager endpoints.
+type azureEndpointsClient struct {
+	endpoints trafficmanager.EndpointsClient
+}
+
+// newEndpointsClient creates a new Traffic Manager endpoints client from subscription ID.
+func newEndpointsClient(auth azure.Authorizer) *azureEndpointsClient {
+	c := trafficmanager.NewEndpointsClientWithBaseURI(auth.BaseURI(), auth.SubscriptionID())
+	azure.SetAutoRestClientDefaults(&c.Client, auth.Authorizer())
+	return &azureEndpointsClient{
+		endpoints: c,
+	}
+}
+
+// Get gets the specified Traffic Manager endpoint.
+func (aec *azureEndpointsClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "trafficmanager.azureEndpointsClient.Get")
+	defer done()
+
+	return aec.endpoints.Get(ctx, spec.ResourceGroupName(), spec.OwnerResourceName(), externalEndpointType, spec.ResourceName())
+}
+
+// CreateOrUpdateAsync creates or updates a Traffic Manager endpoint.
+// Creating an endpoint is not a long running operation, so we don't ever return a future.
+func (aec *azureEndpointsClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (result interface{}, future azureautorest.FutureAPI, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "trafficmanager.azureEndpointsClient.CreateOrUpdateAsync")
+	defer done()
+
+	endpoint, ok := parameters.(trafficmanager.Endpoint)
+	if !ok {
+		return nil, nil, errors.Errorf("%T is not a trafficmanager.Endpoint", parameters)
+	}
+
+	result, err = aec.endpoints.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.OwnerResourceName(), externalEndpointType, spec.ResourceName(), endpoint)
+	return result, nil, err
+}
+
+// DeleteAsync deletes a Traffic Manager endpoint.
+// Deleting an endpoint is not a long running operation, so we don't ever return a future.
+func (aec *azureEndpointsClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (future azureautorest.FutureAPI, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "trafficmanager.azureEndpointsClient.DeleteAsync")
+	defer done()
+
+	_, err = aec.endpoints.Delete(ctx, spec.ResourceGroupName(), spec.OwnerResourceName(), externalEndpointType, spec.ResourceName())
+	return nil, err
+}
+
+// IsDone returns true if the long-running operation has completed. Noop for Traffic Manager endpoints.
+func (aec *azureEndpointsClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (isDone bool, err error) {
+	return true, nil
+}
+
+// Result fetches the result of a long-running operation future. Noop for Traffic Manager endpoints.
+func (aec *azureEndpointsClient) Result(ctx context.Context, future azureautorest.FutureAPI, futureType string) (result interface{}, err error) {
+	return nil, nil
+}
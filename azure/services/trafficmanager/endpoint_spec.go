@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trafficmanager
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/trafficmanager/mgmt/2018-04-01/trafficmanager"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+)
+
+// EndpointSpec defines the specification for a Traffic Manager endpoint pointing to a cluster's API server.
+type EndpointSpec struct {
+	Name          string
+	ProfileName   string
+	ResourceGroup string
+	Target        string
+	Priority      *int64
+	Weight        *int64
+}
+
+// ResourceName returns the name of the Traffic Manager endpoint.
+func (s *EndpointSpec) ResourceName() string {
+	return s.Name
+}
+
+// OwnerResourceName returns the name of the Traffic Manager profile that owns the endpoint.
+func (s *EndpointSpec) OwnerResourceName() string {
+	return s.ProfileName
+}
+
+// ResourceGroupName returns the name of the resource group of the Traffic Manager profile.
+func (s *EndpointSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// Parameters returns the parameters for the Traffic Manager endpoint.
+func (s *EndpointSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		existingEndpoint, ok := existing.(trafficmanager.Endpoint)
+		if !ok {
+			return nil, errors.Errorf("%T is not a trafficmanager.Endpoint", existing)
+		}
+		if props := existingEndpoint.EndpointProperties; props != nil &&
+			ptr.Deref(props.Target, "") == s.Target &&
+			props.EndpointStatus == trafficmanager.EndpointStatusEnabled &&
+			(s.Priority == nil || ptr.Equal(props.Priority, s.Priority)) &&
+			(s.Weight == nil || ptr.Equal(props.Weight, s.Weight)) {
+			// Endpoint is up to date, nothing to do.
+			return nil, nil
+		}
+	}
+
+	return trafficmanager.Endpoint{
+		EndpointProperties: &trafficmanager.EndpointProperties{
+			Target:         ptr.To(s.Target),
+			EndpointStatus: trafficmanager.EndpointStatusEnabled,
+			Priority:       s.Priority,
+			Weight:         s.Weight,
+		},
+	}, nil
+}
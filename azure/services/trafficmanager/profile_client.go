@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trafficmanager
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/trafficmanager/mgmt/2018-04-01/trafficmanager"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// azureProfilesClient contains the Azure go-sdk Client for Traffic Manager profiles.
+type azureProfilesClient struct {
+	profiles trafficmanager.ProfilesClient
+}
+
+// newProfilesClient creates a new Traffic Manager profiles client from subscription ID.
+func newProfilesClient(auth azure.Authorizer) *azureProfilesClient {
+	c := trafficmanager.NewProfilesClientWithBaseURI(auth.BaseURI(), auth.SubscriptionID())
+	azure.SetAutoRestClientDefaults(&c.Client, auth.Authorizer())
+	return &azureProfilesClient{
+		profiles: c,
+	}
+}
+
+// Get gets the specified Traffic Manager profile.
+func (apc *azureProfilesClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "trafficmanager.azureProfilesClient.Get")
+	defer done()
+
+	return apc.profiles.Get(ctx, spec.ResourceGroupName(), spec.ResourceName())
+}
+
+// CreateOrUpdateAsync creates or updates a Traffic Manager profile.
+// Creating a profile is not a long running operation, so we don't ever return a future.
+func (apc *azureProfilesClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (result interface{}, future azureautorest.FutureAPI, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "trafficmanager.azureProfilesClient.CreateOrUpdateAsync")
+	defer done()
+
+	profile, ok := parameters.(trafficmanager.Profile)
+	if !ok {
+		return nil, nil, errors.Errorf("%T is not a trafficmanager.Profile", parameters)
+	}
+
+	result, err = apc.profiles.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), profile)
+	return result, nil, err
+}
+
+// DeleteAsync deletes a Traffic Manager profile.
+// Deleting a profile is not a long running operation, so we don't ever return a future.
+func (apc *azureProfilesClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (future azureautorest.FutureAPI, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "trafficmanager.azureProfilesClient.DeleteAsync")
+	defer done()
+
+	_, err = apc.profiles.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	return nil, err
+}
+
+// IsDone returns true if the long-running operation has completed. Noop for Traffic Manager profiles.
+func (apc *azureProfilesClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (isDone bool, err error) {
+	return true, nil
+}
+
+// Result fetches the result of a long-running operation future. Noop for Traffic Manager profiles.
+func (apc *azureProfilesClient) Result(ctx context.Context, future azureautorest.FutureAPI, futureType string) (result interface{}, err error) {
+	return nil, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trafficmanager
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/trafficmanager/mgmt/2018-04-01/trafficmanager"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+const (
+	// defaultDNSTTLInSeconds is the DNS TTL of the profile. A low value allows clients to fail over quickly.
+	defaultDNSTTLInSeconds = 30
+	// defaultMonitorIntervalInSeconds is the interval at which Traffic Manager probes the endpoints.
+	defaultMonitorIntervalInSeconds = 30
+	// defaultMonitorTimeoutInSeconds is the time Traffic Manager waits for an endpoint to respond to a probe.
+	defaultMonitorTimeoutInSeconds = 10
+	// defaultToleratedNumberOfFailures is the number of failed probes tolerated before an endpoint is degraded.
+	defaultToleratedNumberOfFailures = 3
+)
+
+// ProfileSpec defines the specification for a Traffic Manager profile.
+type ProfileSpec struct {
+	Name           string
+	ResourceGroup  string
+	RoutingMethod  infrav1.TrafficRoutingMethod
+	MonitorPort    int32
+	ClusterName    string
+	AdditionalTags infrav1.Tags
+}
+
+// ResourceName returns the name of the Traffic Manager profile.
+func (s *ProfileSpec) ResourceName() string {
+	return s.Name
+}
+
+// OwnerResourceName is a no-op for Traffic Manager profiles.
+func (s *ProfileSpec) OwnerResourceName() string {
+	return ""
+}
+
+// ResourceGroupName returns the name of the resource group of the Traffic Manager profile.
+func (s *ProfileSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// Parameters returns the parameters for the Traffic Manager profile.
+// Profiles are shared between clusters, so an existing profile is never updated.
+func (s *ProfileSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		if _, ok := existing.(trafficmanager.Profile); !ok {
+			return nil, errors.Errorf("%T is not a trafficmanager.Profile", existing)
+		}
+		return nil, nil
+	}
+
+	return trafficmanager.Profile{
+		Location: ptr.To(azure.Global),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Additional:  s.AdditionalTags,
+		})),
+		ProfileProperties: &trafficmanager.ProfileProperties{
+			ProfileStatus:        trafficmanager.ProfileStatusEnabled,
+			TrafficRoutingMethod: trafficmanager.TrafficRoutingMethod(s.RoutingMethod),
+			DNSConfig: &trafficmanager.DNSConfig{
+				RelativeName: ptr.To(s.Name),
+				TTL:          ptr.To[int64](defaultDNSTTLInSeconds),
+			},
+			MonitorConfig: &trafficmanager.MonitorConfig{
+				Protocol:                  trafficmanager.TCP,
+				Port:                      ptr.To(int64(s.MonitorPort)),
+				IntervalInSeconds:         ptr.To[int64](defaultMonitorIntervalInSeconds),
+				TimeoutInSeconds:          ptr.To[int64](defaultMonitorTimeoutInSeconds),
+				ToleratedNumberOfFailures: ptr.To[int64](defaultToleratedNumberOfFailures),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trafficmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/trafficmanager/mgmt/2018-04-01/trafficmanager"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+func TestEndpointSpec_Parameters(t *testing.T) {
+	testcases := []struct {
+		name          string
+		spec          *EndpointSpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name: "new endpoint",
+			spec: fakeEndpoint,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(trafficmanager.Endpoint{
+					EndpointProperties: &trafficmanager.EndpointProperties{
+						Target:         ptr.To("my-cluster.eastus.cloudapp.azure.com"),
+						EndpointStatus: trafficmanager.EndpointStatusEnabled,
+						Priority:       ptr.To[int64](1),
+					},
+				}))
+			},
+		},
+		{
+			name: "existing endpoint is up to date",
+			spec: fakeEndpoint,
+			existing: trafficmanager.Endpoint{
+				EndpointProperties: &trafficmanager.EndpointProperties{
+					Target:         ptr.To("my-cluster.eastus.cloudapp.azure.com"),
+					EndpointStatus: trafficmanager.EndpointStatusEnabled,
+					Priority:       ptr.To[int64](1),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "existing endpoint with different priority",
+			spec: fakeEndpoint,
+			existing: trafficmanager.Endpoint{
+				EndpointProperties: &trafficmanager.EndpointProperties{
+					Target:         ptr.To("my-cluster.eastus.cloudapp.azure.com"),
+					EndpointStatus: trafficmanager.EndpointStatusEnabled,
+					Priority:       ptr.To[int64](2),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(trafficmanager.Endpoint{
+					EndpointProperties: &trafficmanager.EndpointProperties{
+						Target:         ptr.To("my-cluster.eastus.cloudapp.azure.com"),
+						EndpointStatus: trafficmanager.EndpointStatusEnabled,
+						Priority:       ptr.To[int64](1),
+					},
+				}))
+			},
+		},
+		{
+			name:          "existing is not an endpoint",
+			spec:          fakeEndpoint,
+			existing:      "wrong type",
+			expectedError: "string is not a trafficmanager.Endpoint",
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			tc.expect(g, result)
+		})
+	}
+}
+
+func TestProfileSpec_Parameters(t *testing.T) {
+	g := NewWithT(t)
+
+	result, err := fakeProfile.Parameters(context.TODO(), nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	profile, ok := result.(trafficmanager.Profile)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(profile.Tags).To(Equal(ownedTags))
+	g.Expect(profile.TrafficRoutingMethod).To(Equal(trafficmanager.Priority))
+	g.Expect(profile.DNSConfig.RelativeName).To(Equal(ptr.To(profileName)))
+	g.Expect(profile.MonitorConfig.Protocol).To(Equal(trafficmanager.TCP))
+	g.Expect(profile.MonitorConfig.Port).To(Equal(ptr.To[int64](6443)))
+
+	result, err = fakeProfile.Parameters(context.TODO(), trafficmanager.Profile{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(BeNil())
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trafficmanager
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/trafficmanager/mgmt/2018-04-01/trafficmanager"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// ServiceName is the name of this service.
+const ServiceName = "trafficmanager"
+
+// Scope defines the scope interface for a Traffic Manager service.
+type Scope interface {
+	azure.ClusterDescriber
+	azure.AsyncStatusUpdater
+	TrafficManagerSpecs() (profileSpec azure.ResourceSpecGetter, endpointSpec azure.ResourceSpecGetter)
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope              Scope
+	profileGetter      async.Getter
+	profileReconciler  async.Reconciler
+	endpointReconciler async.Reconciler
+}
+
+// New creates a new Traffic Manager service.
+func New(scope Scope) *Service {
+	profilesClient := newProfilesClient(scope)
+	endpointsClient := newEndpointsClient(scope)
+	return &Service{
+		Scope:              scope,
+		profileGetter:      profilesClient,
+		profileReconciler:  async.New(scope, profilesClient, profilesClient),
+		endpointReconciler: async.New(scope, endpointsClient, endpointsClient),
+	}
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return ServiceName
+}
+
+// Reconcile creates the Traffic Manager profile if it does not exist and registers the API server endpoint with it.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "trafficmanager.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	profileSpec, endpointSpec := s.Scope.TrafficManagerSpecs()
+	if profileSpec == nil || endpointSpec == nil {
+		return nil
+	}
+
+	_, err := s.profileReconciler.CreateOrUpdateResource(ctx, profileSpec, ServiceName)
+	if err == nil {
+		_, err = s.endpointReconciler.CreateOrUpdateResource(ctx, endpointSpec, ServiceName)
+	}
+
+	s.Scope.UpdatePutStatus(infrav1.TrafficManagerReadyCondition, ServiceName, err)
+	return err
+}
+
+// Delete deregisters the API server endpoint from the Traffic Manager profile.
+// The profile itself is only deleted when it is owned by this cluster and no other endpoints remain in it.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "trafficmanager.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	profileSpec, endpointSpec := s.Scope.TrafficManagerSpecs()
+	if profileSpec == nil || endpointSpec == nil {
+		return nil
+	}
+
+	err := s.endpointReconciler.DeleteResource(ctx, endpointSpec, ServiceName)
+	if err == nil {
+		err = s.deleteProfileIfUnused(ctx, profileSpec)
+	}
+	if err != nil && azure.ResourceNotFound(err) {
+		log.V(2).Info("Traffic Manager profile already deleted", "profile", profileSpec.ResourceName())
+		err = nil
+	}
+
+	s.Scope.UpdateDeleteStatus(infrav1.TrafficManagerReadyCondition, ServiceName, err)
+	return err
+}
+
+// deleteProfileIfUnused deletes the Traffic Manager profile when it is owned by this cluster and has no endpoints left.
+func (s *Service) deleteProfileIfUnused(ctx context.Context, profileSpec azure.ResourceSpecGetter) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "trafficmanager.Service.deleteProfileIfUnused")
+	defer done()
+
+	existing, err := s.profileGetter.Get(ctx, profileSpec)
+	if err != nil {
+		return err
+	}
+	profile, ok := existing.(trafficmanager.Profile)
+	if !ok {
+		return errors.Errorf("%T is not a trafficmanager.Profile", existing)
+	}
+
+	if !converters.MapToTags(profile.Tags).HasOwned(s.Scope.ClusterName()) {
+		log.V(2).Info("Skipping deletion of Traffic Manager profile not owned by this cluster", "profile", profileSpec.ResourceName())
+		return nil
+	}
+	if profile.ProfileProperties != nil && profile.Endpoints != nil && len(*profile.Endpoints) > 0 {
+		log.V(2).Info("Skipping deletion of Traffic Manager profile that still has endpoints", "profile", profileSpec.ResourceName())
+		return nil
+	}
+
+	return s.profileReconciler.DeleteResource(ctx, profileSpec, ServiceName)
+}
+
+// IsManaged returns always returns true as the endpoint of this cluster is always managed by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
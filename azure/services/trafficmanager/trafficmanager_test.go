@@ -0,0 +1,250 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trafficmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/trafficmanager/mgmt/2018-04-01/trafficmanager"
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/trafficmanager/mock_trafficmanager"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+const (
+	profileName   = "my-profile"
+	resourceGroup = "my-rg"
+	clusterName   = "my-cluster"
+)
+
+var (
+	fakeProfile = &ProfileSpec{
+		Name:          profileName,
+		ResourceGroup: resourceGroup,
+		RoutingMethod: infrav1.TrafficRoutingMethodPriority,
+		MonitorPort:   6443,
+		ClusterName:   clusterName,
+	}
+
+	fakeEndpoint = &EndpointSpec{
+		Name:          clusterName,
+		ProfileName:   profileName,
+		ResourceGroup: resourceGroup,
+		Target:        "my-cluster.eastus.cloudapp.azure.com",
+		Priority:      ptr.To[int64](1),
+	}
+
+	ownedTags = map[string]*string{
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_" + clusterName: ptr.To("owned"),
+	}
+
+	notDoneError  = azure.NewOperationNotDoneError(&infrav1.Future{Type: "resourceType", ResourceGroup: resourceGroup, Name: "resourceName"})
+	errFake       = errors.New("this is an error")
+	notFoundError = autorest.DetailedError{StatusCode: 404}
+)
+
+func TestReconcileTrafficManager(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_trafficmanager.MockScopeMockRecorder, p, e *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "no traffic manager",
+			expectedError: "",
+			expect: func(s *mock_trafficmanager.MockScopeMockRecorder, p, e *mock_async.MockReconcilerMockRecorder) {
+				s.TrafficManagerSpecs().Return(nil, nil)
+			},
+		},
+		{
+			name:          "create profile and endpoint successfully",
+			expectedError: "",
+			expect: func(s *mock_trafficmanager.MockScopeMockRecorder, p, e *mock_async.MockReconcilerMockRecorder) {
+				s.TrafficManagerSpecs().Return(fakeProfile, fakeEndpoint)
+				p.CreateOrUpdateResource(gomockinternal.AContext(), fakeProfile, ServiceName).Return(nil, nil)
+				e.CreateOrUpdateResource(gomockinternal.AContext(), fakeEndpoint, ServiceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.TrafficManagerReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "profile creation fails",
+			expectedError: "this is an error",
+			expect: func(s *mock_trafficmanager.MockScopeMockRecorder, p, e *mock_async.MockReconcilerMockRecorder) {
+				s.TrafficManagerSpecs().Return(fakeProfile, fakeEndpoint)
+				p.CreateOrUpdateResource(gomockinternal.AContext(), fakeProfile, ServiceName).Return(nil, errFake)
+				s.UpdatePutStatus(infrav1.TrafficManagerReadyCondition, ServiceName, errFake)
+			},
+		},
+		{
+			name:          "endpoint creation in progress",
+			expectedError: "operation type resourceType on Azure resource my-rg/resourceName is not done",
+			expect: func(s *mock_trafficmanager.MockScopeMockRecorder, p, e *mock_async.MockReconcilerMockRecorder) {
+				s.TrafficManagerSpecs().Return(fakeProfile, fakeEndpoint)
+				p.CreateOrUpdateResource(gomockinternal.AContext(), fakeProfile, ServiceName).Return(nil, nil)
+				e.CreateOrUpdateResource(gomockinternal.AContext(), fakeEndpoint, ServiceName).Return(nil, notDoneError)
+				s.UpdatePutStatus(infrav1.TrafficManagerReadyCondition, ServiceName, notDoneError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_trafficmanager.NewMockScope(mockCtrl)
+			profileReconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+			endpointReconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), profileReconcilerMock.EXPECT(), endpointReconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:              scopeMock,
+				profileReconciler:  profileReconcilerMock,
+				endpointReconciler: endpointReconcilerMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteTrafficManager(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_trafficmanager.MockScopeMockRecorder, g *mock_async.MockGetterMockRecorder, p, e *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "no traffic manager",
+			expectedError: "",
+			expect: func(s *mock_trafficmanager.MockScopeMockRecorder, g *mock_async.MockGetterMockRecorder, p, e *mock_async.MockReconcilerMockRecorder) {
+				s.TrafficManagerSpecs().Return(nil, nil)
+			},
+		},
+		{
+			name:          "delete endpoint and owned profile without other endpoints",
+			expectedError: "",
+			expect: func(s *mock_trafficmanager.MockScopeMockRecorder, g *mock_async.MockGetterMockRecorder, p, e *mock_async.MockReconcilerMockRecorder) {
+				s.TrafficManagerSpecs().Return(fakeProfile, fakeEndpoint)
+				e.DeleteResource(gomockinternal.AContext(), fakeEndpoint, ServiceName).Return(nil)
+				g.Get(gomockinternal.AContext(), fakeProfile).Return(trafficmanager.Profile{
+					Tags:              ownedTags,
+					ProfileProperties: &trafficmanager.ProfileProperties{Endpoints: &[]trafficmanager.Endpoint{}},
+				}, nil)
+				s.ClusterName().Return(clusterName)
+				p.DeleteResource(gomockinternal.AContext(), fakeProfile, ServiceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.TrafficManagerReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "keep owned profile that still has endpoints of other clusters",
+			expectedError: "",
+			expect: func(s *mock_trafficmanager.MockScopeMockRecorder, g *mock_async.MockGetterMockRecorder, p, e *mock_async.MockReconcilerMockRecorder) {
+				s.TrafficManagerSpecs().Return(fakeProfile, fakeEndpoint)
+				e.DeleteResource(gomockinternal.AContext(), fakeEndpoint, ServiceName).Return(nil)
+				g.Get(gomockinternal.AContext(), fakeProfile).Return(trafficmanager.Profile{
+					Tags: ownedTags,
+					ProfileProperties: &trafficmanager.ProfileProperties{Endpoints: &[]trafficmanager.Endpoint{
+						{Name: ptr.To("other-cluster")},
+					}},
+				}, nil)
+				s.ClusterName().Return(clusterName)
+				s.UpdateDeleteStatus(infrav1.TrafficManagerReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "keep profile not owned by this cluster",
+			expectedError: "",
+			expect: func(s *mock_trafficmanager.MockScopeMockRecorder, g *mock_async.MockGetterMockRecorder, p, e *mock_async.MockReconcilerMockRecorder) {
+				s.TrafficManagerSpecs().Return(fakeProfile, fakeEndpoint)
+				e.DeleteResource(gomockinternal.AContext(), fakeEndpoint, ServiceName).Return(nil)
+				g.Get(gomockinternal.AContext(), fakeProfile).Return(trafficmanager.Profile{}, nil)
+				s.ClusterName().Return(clusterName)
+				s.UpdateDeleteStatus(infrav1.TrafficManagerReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "profile already deleted",
+			expectedError: "",
+			expect: func(s *mock_trafficmanager.MockScopeMockRecorder, g *mock_async.MockGetterMockRecorder, p, e *mock_async.MockReconcilerMockRecorder) {
+				s.TrafficManagerSpecs().Return(fakeProfile, fakeEndpoint)
+				e.DeleteResource(gomockinternal.AContext(), fakeEndpoint, ServiceName).Return(nil)
+				g.Get(gomockinternal.AContext(), fakeProfile).Return(nil, notFoundError)
+				s.UpdateDeleteStatus(infrav1.TrafficManagerReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "endpoint deletion fails",
+			expectedError: "this is an error",
+			expect: func(s *mock_trafficmanager.MockScopeMockRecorder, g *mock_async.MockGetterMockRecorder, p, e *mock_async.MockReconcilerMockRecorder) {
+				s.TrafficManagerSpecs().Return(fakeProfile, fakeEndpoint)
+				e.DeleteResource(gomockinternal.AContext(), fakeEndpoint, ServiceName).Return(errFake)
+				s.UpdateDeleteStatus(infrav1.TrafficManagerReadyCondition, ServiceName, errFake)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_trafficmanager.NewMockScope(mockCtrl)
+			profileGetterMock := mock_async.NewMockGetter(mockCtrl)
+			profileReconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+			endpointReconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), profileGetterMock.EXPECT(), profileReconcilerMock.EXPECT(), endpointReconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:              scopeMock,
+				profileGetter:      profileGetterMock,
+				profileReconciler:  profileReconcilerMock,
+				endpointReconciler: endpointReconcilerMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -25,6 +25,8 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+const serviceName = "virtualmachineimages"
+
 // Client is an interface for listing VM images.
 type Client interface {
 	List(ctx context.Context, location, publisher, offer, sku string) (armcompute.VirtualMachineImagesClientListResponse, error)
@@ -39,7 +41,7 @@ var _ Client = (*AzureClient)(nil)
 
 // NewClient creates an AzureClient from an Authorizer.
 func NewClient(auth azure.Authorizer) (*AzureClient, error) {
-	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment(), serviceName)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create virtualmachineimages client options")
 	}
@@ -48,17 +48,30 @@ func New(auth azure.Authorizer) (*Service, error) {
 }
 
 // GetDefaultUbuntuImage returns the default image spec for Ubuntu.
-func (s *Service) GetDefaultUbuntuImage(ctx context.Context, location, k8sVersion string) (*infrav1.Image, error) {
+// A variant selects the hardened variant of the image, whose SKU would be named like "ubuntu-2204-fips-gen1". Variants
+// aren't guaranteed to be published, so their SKU is only used once it is found in the marketplace.
+func (s *Service) GetDefaultUbuntuImage(ctx context.Context, location, k8sVersion string, variant infrav1.ImageVariant) (*infrav1.Image, error) {
 	v, err := semver.ParseTolerant(k8sVersion)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to parse Kubernetes version \"%s\"", k8sVersion)
 	}
 
-	osVersion := getUbuntuOSVersion(v.Major, v.Minor, v.Patch)
+	osAndVersion := fmt.Sprintf("ubuntu-%s", getUbuntuOSVersion(v.Major, v.Minor, v.Patch))
+	if variant != "" {
+		// Variants were never published with the old SKU naming.
+		if k8sVersionInSKUName(v.Major, v.Minor, v.Patch) {
+			return nil, errors.Errorf("no %s reference image is published for Kubernetes version \"%s\", set the image explicitly", variant, k8sVersion)
+		}
+		osAndVersion = fmt.Sprintf("%s-%s", osAndVersion, strings.ToLower(string(variant)))
+	}
+
 	publisher, offer := azure.DefaultImagePublisherID, azure.DefaultImageOfferID
 	skuID, version, err := s.getSKUAndVersion(
-		ctx, location, publisher, offer, k8sVersion, fmt.Sprintf("ubuntu-%s", osVersion))
+		ctx, location, publisher, offer, k8sVersion, osAndVersion)
 	if err != nil {
+		if variant != "" {
+			return nil, errors.Wrapf(err, "no %s reference image is published for Kubernetes version \"%s\" in location \"%s\", set the image explicitly", variant, k8sVersion, location)
+		}
 		return nil, errors.Wrap(err, "failed to get default image")
 	}
 
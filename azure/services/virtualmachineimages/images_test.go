@@ -26,6 +26,7 @@ import (
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachineimages/mock_virtualmachineimages"
@@ -238,7 +239,7 @@ func TestGetDefaultUbuntuImage(t *testing.T) {
 					List(gomock.Any(), location, azure.DefaultImagePublisherID, azure.DefaultImageOfferID, gomock.Any()).
 					Return(test.versions, nil)
 			}
-			image, err := svc.GetDefaultUbuntuImage(context.TODO(), location, test.k8sVersion)
+			image, err := svc.GetDefaultUbuntuImage(context.TODO(), location, test.k8sVersion, "")
 
 			g := NewWithT(t)
 			g.Expect(err).NotTo(HaveOccurred())
@@ -248,6 +249,88 @@ func TestGetDefaultUbuntuImage(t *testing.T) {
 	}
 }
 
+func TestGetDefaultUbuntuImageVariant(t *testing.T) {
+	tests := []struct {
+		name          string
+		k8sVersion    string
+		variant       infrav1.ImageVariant
+		version       string
+		listedSKU     string
+		expectedSKU   string
+		expectedError string
+	}{
+		{
+			name:        "FIPS variant",
+			k8sVersion:  "v1.25.3",
+			variant:     infrav1.ImageVariantFIPS,
+			version:     "125.3.20221014",
+			listedSKU:   "ubuntu-2204-fips-gen1",
+			expectedSKU: "ubuntu-2204-fips-gen1",
+		},
+		{
+			name:        "CIS variant",
+			k8sVersion:  "v1.24.7",
+			variant:     infrav1.ImageVariantCIS,
+			version:     "124.7.20221014",
+			listedSKU:   "ubuntu-2004-cis-gen1",
+			expectedSKU: "ubuntu-2004-cis-gen1",
+		},
+		{
+			name:       "variant that isn't published",
+			k8sVersion: "v1.26.0",
+			variant:    infrav1.ImageVariantCIS,
+			listedSKU:  "ubuntu-2204-cis-gen1",
+			expectedError: "no CIS reference image is published for Kubernetes version \"v1.26.0\" in location \"westus3\", set the image explicitly: " +
+				"no VM images found for publisher \"cncf-upstream\" offer \"capi\" sku \"ubuntu-2204-cis-gen1\"",
+		},
+		{
+			name:          "variant of an image with the Kubernetes version in its SKU name",
+			k8sVersion:    "v1.21.2",
+			variant:       infrav1.ImageVariantFIPS,
+			expectedError: "no FIPS reference image is published for Kubernetes version \"v1.21.2\", set the image explicitly",
+		},
+	}
+
+	location := "westus3"
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockAuth := mock_azure.NewMockAuthorizer(mockCtrl)
+			mockAuth.EXPECT().HashKey().Return(t.Name()).AnyTimes()
+			mockAuth.EXPECT().Authorizer().AnyTimes()
+			mockAuth.EXPECT().SubscriptionID().AnyTimes()
+			mockAuth.EXPECT().CloudEnvironment().AnyTimes()
+			mockAuth.EXPECT().Token().Return(&azidentity.DefaultAzureCredential{}).AnyTimes()
+			mockClient := mock_virtualmachineimages.NewMockClient(mockCtrl)
+			svc := Service{Client: mockClient, Authorizer: mockAuth}
+
+			if test.listedSKU != "" {
+				listed := []*armcompute.VirtualMachineImageResource{}
+				if test.version != "" {
+					listed = append(listed, &armcompute.VirtualMachineImageResource{Name: ptr.To(test.version)})
+				}
+				mockClient.EXPECT().
+					List(gomock.Any(), location, azure.DefaultImagePublisherID, azure.DefaultImageOfferID, test.listedSKU).
+					Return(armcompute.VirtualMachineImagesClientListResponse{VirtualMachineImageResourceArray: listed}, nil)
+			}
+			image, err := svc.GetDefaultUbuntuImage(context.TODO(), location, test.k8sVersion, test.variant)
+
+			g := NewWithT(t)
+			if test.expectedError != "" {
+				g.Expect(err).To(MatchError(test.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(image.Marketplace.SKU).To(Equal(test.expectedSKU))
+			g.Expect(image.Marketplace.Version).To(Equal(test.version))
+		})
+	}
+}
+
 func TestGetDefaultWindowsImage(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
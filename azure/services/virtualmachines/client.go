@@ -21,6 +21,8 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
@@ -30,6 +32,7 @@ import (
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/disks"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -38,18 +41,31 @@ import (
 type (
 	// AzureClient contains the Azure go-sdk Client.
 	AzureClient struct {
-		virtualmachines compute.VirtualMachinesClient
+		virtualmachines     compute.VirtualMachinesClient
+		dedicatedHostGroups compute.DedicatedHostGroupsClient
+		disks               compute.DisksClient
+		httpClient          *http.Client
 	}
 
 	// Client provides operations on Azure virtual machine resources.
 	Client interface {
 		Get(context.Context, azure.ResourceSpecGetter) (interface{}, error)
 		GetByID(context.Context, string) (compute.VirtualMachine, error)
+		GetDedicatedHostGroup(ctx context.Context, hostGroupID string) (compute.DedicatedHostGroup, error)
 		CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (result interface{}, future azureautorest.FutureAPI, err error)
 		DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (future azureautorest.FutureAPI, err error)
 		IsDone(ctx context.Context, future azureautorest.FutureAPI) (isDone bool, err error)
 		Result(ctx context.Context, future azureautorest.FutureAPI, futureType string) (result interface{}, err error)
 		GetResultIfDone(ctx context.Context, future *infrav1.Future) (compute.VirtualMachine, error)
+		UpdateAdditionalCapabilities(ctx context.Context, spec azure.ResourceSpecGetter, capabilities *compute.AdditionalCapabilities) (isDone bool, err error)
+		Deallocate(ctx context.Context, spec azure.ResourceSpecGetter) (isDone bool, err error)
+		Start(ctx context.Context, spec azure.ResourceSpecGetter) (isDone bool, err error)
+		ResizeDisk(ctx context.Context, resourceGroup, diskName string, diskSizeGB int32) (isDone bool, err error)
+		DeleteDisk(ctx context.Context, resourceGroup, diskName string) (isDone bool, err error)
+		ListDisks(ctx context.Context, resourceGroup string) ([]compute.Disk, error)
+		UpdateDataDisks(ctx context.Context, spec azure.ResourceSpecGetter, dataDisks []compute.DataDisk) (isDone bool, err error)
+		Resize(ctx context.Context, spec azure.ResourceSpecGetter, vmSize string) (isDone bool, err error)
+		GetSerialConsoleLog(ctx context.Context, spec azure.ResourceSpecGetter) (string, error)
 	}
 )
 
@@ -67,7 +83,14 @@ var _ Client = &AzureClient{}
 // NewClient creates a new VM client from subscription ID.
 func NewClient(auth azure.Authorizer) *AzureClient {
 	c := newVirtualMachinesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
-	return &AzureClient{c}
+	hostGroupsClient := compute.NewDedicatedHostGroupsClientWithBaseURI(auth.BaseURI(), auth.SubscriptionID())
+	azure.SetAutoRestClientDefaults(&hostGroupsClient.Client, auth.Authorizer())
+	return &AzureClient{
+		virtualmachines:     c,
+		dedicatedHostGroups: hostGroupsClient,
+		disks:               disks.NewDisksClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		httpClient:          &http.Client{Timeout: reconciler.DefaultAzureCallTimeout},
+	}
 }
 
 // newVirtualMachinesClient creates a new VM client from subscription ID.
@@ -97,7 +120,20 @@ func (ac *AzureClient) GetByID(ctx context.Context, resourceID string) (compute.
 
 	log.V(4).Info("parsed VM resourceID", "parsed", parsed)
 
-	return ac.virtualmachines.Get(ctx, parsed.ResourceGroupName, parsed.Name, "")
+	return ac.virtualmachines.Get(ctx, parsed.ResourceGroupName, parsed.Name, compute.InstanceViewTypesInstanceView)
+}
+
+// GetDedicatedHostGroup retrieves the dedicated host group with the given resource ID.
+func (ac *AzureClient) GetDedicatedHostGroup(ctx context.Context, hostGroupID string) (compute.DedicatedHostGroup, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.GetDedicatedHostGroup")
+	defer done()
+
+	parsed, err := azureutil.ParseResourceID(hostGroupID)
+	if err != nil {
+		return compute.DedicatedHostGroup{}, errors.Wrap(err, fmt.Sprintf("failed parsing the dedicated host group resource id %q", hostGroupID))
+	}
+
+	return ac.dedicatedHostGroups.Get(ctx, parsed.ResourceGroupName, parsed.Name, "")
 }
 
 // CreateOrUpdateAsync creates or updates a virtual machine asynchronously.
@@ -131,6 +167,195 @@ func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.Resou
 	return result, nil, err
 }
 
+// UpdateAdditionalCapabilities patches the additional capabilities of a virtual machine.
+// It returns false if the operation was accepted but didn't complete in the call timeout.
+func (ac *AzureClient) UpdateAdditionalCapabilities(ctx context.Context, spec azure.ResourceSpecGetter, capabilities *compute.AdditionalCapabilities) (isDone bool, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.UpdateAdditionalCapabilities")
+	defer done()
+
+	update := compute.VirtualMachineUpdate{
+		VirtualMachineProperties: &compute.VirtualMachineProperties{
+			AdditionalCapabilities: capabilities,
+		},
+	}
+	updateFuture, err := ac.virtualmachines.Update(ctx, spec.ResourceGroupName(), spec.ResourceName(), update)
+	if err != nil {
+		return false, err
+	}
+	return ac.waitForCompletion(ctx, ac.virtualmachines.Client, updateFuture.FutureAPI)
+}
+
+// Deallocate deallocates a virtual machine.
+// It returns false if the operation was accepted but didn't complete in the call timeout.
+func (ac *AzureClient) Deallocate(ctx context.Context, spec azure.ResourceSpecGetter) (isDone bool, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Deallocate")
+	defer done()
+
+	deallocateFuture, err := ac.virtualmachines.Deallocate(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	if err != nil {
+		return false, err
+	}
+	return ac.waitForCompletion(ctx, ac.virtualmachines.Client, deallocateFuture.FutureAPI)
+}
+
+// Start starts a virtual machine.
+// It returns false if the operation was accepted but didn't complete in the call timeout.
+func (ac *AzureClient) Start(ctx context.Context, spec azure.ResourceSpecGetter) (isDone bool, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Start")
+	defer done()
+
+	startFuture, err := ac.virtualmachines.Start(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	if err != nil {
+		return false, err
+	}
+	return ac.waitForCompletion(ctx, ac.virtualmachines.Client, startFuture.FutureAPI)
+}
+
+// Resize patches the hardware profile of a virtual machine to change its size. Azure restarts a running
+// virtual machine to resize it.
+// It returns false if the operation was accepted but didn't complete in the call timeout.
+func (ac *AzureClient) Resize(ctx context.Context, spec azure.ResourceSpecGetter, vmSize string) (isDone bool, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Resize")
+	defer done()
+
+	update := compute.VirtualMachineUpdate{
+		VirtualMachineProperties: &compute.VirtualMachineProperties{
+			HardwareProfile: &compute.HardwareProfile{
+				VMSize: compute.VirtualMachineSizeTypes(vmSize),
+			},
+		},
+	}
+	updateFuture, err := ac.virtualmachines.Update(ctx, spec.ResourceGroupName(), spec.ResourceName(), update)
+	if err != nil {
+		return false, err
+	}
+	return ac.waitForCompletion(ctx, ac.virtualmachines.Client, updateFuture.FutureAPI)
+}
+
+// ResizeDisk patches the size of a managed disk, e.g. the OS disk of a deallocated virtual machine.
+// It returns false if the operation was accepted but didn't complete in the call timeout.
+func (ac *AzureClient) ResizeDisk(ctx context.Context, resourceGroup, diskName string, diskSizeGB int32) (isDone bool, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.ResizeDisk")
+	defer done()
+
+	update := compute.DiskUpdate{
+		DiskUpdateProperties: &compute.DiskUpdateProperties{
+			DiskSizeGB: ptr.To(diskSizeGB),
+		},
+	}
+	updateFuture, err := ac.disks.Update(ctx, resourceGroup, diskName, update)
+	if err != nil {
+		return false, err
+	}
+	return ac.waitForCompletion(ctx, ac.disks.Client, updateFuture.FutureAPI)
+}
+
+// DeleteDisk deletes a managed disk, e.g. a data disk that was detached from a virtual machine.
+// It returns false if the operation was accepted but didn't complete in the call timeout.
+func (ac *AzureClient) DeleteDisk(ctx context.Context, resourceGroup, diskName string) (isDone bool, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.DeleteDisk")
+	defer done()
+
+	deleteFuture, err := ac.disks.Delete(ctx, resourceGroup, diskName)
+	if err != nil {
+		return false, err
+	}
+	return ac.waitForCompletion(ctx, ac.disks.Client, deleteFuture.FutureAPI)
+}
+
+// ListDisks returns the managed disks of a resource group.
+func (ac *AzureClient) ListDisks(ctx context.Context, resourceGroup string) ([]compute.Disk, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.ListDisks")
+	defer done()
+
+	itr, err := ac.disks.ListByResourceGroupComplete(ctx, resourceGroup)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list disks in the resource group")
+	}
+
+	var disks []compute.Disk
+	for ; itr.NotDone(); err = itr.NextWithContext(ctx) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate disks [%w]", err)
+		}
+		disks = append(disks, itr.Value())
+	}
+	return disks, nil
+}
+
+// UpdateDataDisks patches the data disks of a virtual machine, which attaches the disks that are new to the list and
+// detaches the disks that are missing from it.
+// It returns false if the operation was accepted but didn't complete in the call timeout.
+func (ac *AzureClient) UpdateDataDisks(ctx context.Context, spec azure.ResourceSpecGetter, dataDisks []compute.DataDisk) (isDone bool, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.UpdateDataDisks")
+	defer done()
+
+	update := compute.VirtualMachineUpdate{
+		VirtualMachineProperties: &compute.VirtualMachineProperties{
+			StorageProfile: &compute.StorageProfile{
+				DataDisks: &dataDisks,
+			},
+		},
+	}
+	updateFuture, err := ac.virtualmachines.Update(ctx, spec.ResourceGroupName(), spec.ResourceName(), update)
+	if err != nil {
+		return false, err
+	}
+	return ac.waitForCompletion(ctx, ac.virtualmachines.Client, updateFuture.FutureAPI)
+}
+
+// GetSerialConsoleLog downloads the serial console log of a virtual machine from its boot diagnostics.
+func (ac *AzureClient) GetSerialConsoleLog(ctx context.Context, spec azure.ResourceSpecGetter) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.GetSerialConsoleLog")
+	defer done()
+
+	data, err := ac.virtualmachines.RetrieveBootDiagnosticsData(ctx, spec.ResourceGroupName(), spec.ResourceName(), ptr.To[int32](bootDiagnosticsSASExpirationMinutes))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to retrieve boot diagnostics data")
+	}
+	logURI := ptr.Deref(data.SerialConsoleLogBlobURI, "")
+	if logURI == "" {
+		return "", errors.New("boot diagnostics have no serial console log")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, logURI, http.NoBody)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create serial console log request")
+	}
+	req.Header.Set("User-Agent", azure.UserAgent())
+
+	resp, err := ac.httpClient.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to download serial console log")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("failed to download serial console log: unexpected status %s", resp.Status)
+	}
+
+	serialLog, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read serial console log")
+	}
+	return string(serialLog), nil
+}
+
+// waitForCompletion waits for a long-running operation for at most the call timeout.
+func (ac *AzureClient) waitForCompletion(ctx context.Context, client autorest.Client, future azureautorest.FutureAPI) (isDone bool, err error) {
+	waitCtx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	if err := future.WaitForCompletionRef(waitCtx, client); err != nil {
+		if waitCtx.Err() != nil && ctx.Err() == nil {
+			// the operation didn't finish in the call timeout, it keeps running in Azure.
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 // DeleteAsync deletes a virtual machine asynchronously. DeleteAsync sends a DELETE
 // request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
 // progress of the operation.
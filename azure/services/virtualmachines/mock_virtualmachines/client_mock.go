@@ -71,6 +71,21 @@ func (mr *MockClientMockRecorder) CreateOrUpdateAsync(ctx, spec, parameters inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateAsync", reflect.TypeOf((*MockClient)(nil).CreateOrUpdateAsync), ctx, spec, parameters)
 }
 
+// Deallocate mocks base method.
+func (m *MockClient) Deallocate(ctx context.Context, spec azure0.ResourceSpecGetter) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deallocate", ctx, spec)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Deallocate indicates an expected call of Deallocate.
+func (mr *MockClientMockRecorder) Deallocate(ctx, spec interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deallocate", reflect.TypeOf((*MockClient)(nil).Deallocate), ctx, spec)
+}
+
 // DeleteAsync mocks base method.
 func (m *MockClient) DeleteAsync(ctx context.Context, spec azure0.ResourceSpecGetter) (azure.FutureAPI, error) {
 	m.ctrl.T.Helper()
@@ -86,6 +101,21 @@ func (mr *MockClientMockRecorder) DeleteAsync(ctx, spec interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAsync", reflect.TypeOf((*MockClient)(nil).DeleteAsync), ctx, spec)
 }
 
+// DeleteDisk mocks base method.
+func (m *MockClient) DeleteDisk(ctx context.Context, resourceGroup, diskName string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDisk", ctx, resourceGroup, diskName)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDisk indicates an expected call of DeleteDisk.
+func (mr *MockClientMockRecorder) DeleteDisk(ctx, resourceGroup, diskName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDisk", reflect.TypeOf((*MockClient)(nil).DeleteDisk), ctx, resourceGroup, diskName)
+}
+
 // Get mocks base method.
 func (m *MockClient) Get(arg0 context.Context, arg1 azure0.ResourceSpecGetter) (interface{}, error) {
 	m.ctrl.T.Helper()
@@ -116,6 +146,21 @@ func (mr *MockClientMockRecorder) GetByID(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockClient)(nil).GetByID), arg0, arg1)
 }
 
+// GetDedicatedHostGroup mocks base method.
+func (m *MockClient) GetDedicatedHostGroup(ctx context.Context, hostGroupID string) (compute.DedicatedHostGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDedicatedHostGroup", ctx, hostGroupID)
+	ret0, _ := ret[0].(compute.DedicatedHostGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDedicatedHostGroup indicates an expected call of GetDedicatedHostGroup.
+func (mr *MockClientMockRecorder) GetDedicatedHostGroup(ctx, hostGroupID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDedicatedHostGroup", reflect.TypeOf((*MockClient)(nil).GetDedicatedHostGroup), ctx, hostGroupID)
+}
+
 // GetResultIfDone mocks base method.
 func (m *MockClient) GetResultIfDone(ctx context.Context, future *v1beta1.Future) (compute.VirtualMachine, error) {
 	m.ctrl.T.Helper()
@@ -131,6 +176,21 @@ func (mr *MockClientMockRecorder) GetResultIfDone(ctx, future interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResultIfDone", reflect.TypeOf((*MockClient)(nil).GetResultIfDone), ctx, future)
 }
 
+// GetSerialConsoleLog mocks base method.
+func (m *MockClient) GetSerialConsoleLog(ctx context.Context, spec azure0.ResourceSpecGetter) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSerialConsoleLog", ctx, spec)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSerialConsoleLog indicates an expected call of GetSerialConsoleLog.
+func (mr *MockClientMockRecorder) GetSerialConsoleLog(ctx, spec interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSerialConsoleLog", reflect.TypeOf((*MockClient)(nil).GetSerialConsoleLog), ctx, spec)
+}
+
 // IsDone mocks base method.
 func (m *MockClient) IsDone(ctx context.Context, future azure.FutureAPI) (bool, error) {
 	m.ctrl.T.Helper()
@@ -146,6 +206,51 @@ func (mr *MockClientMockRecorder) IsDone(ctx, future interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDone", reflect.TypeOf((*MockClient)(nil).IsDone), ctx, future)
 }
 
+// ListDisks mocks base method.
+func (m *MockClient) ListDisks(ctx context.Context, resourceGroup string) ([]compute.Disk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDisks", ctx, resourceGroup)
+	ret0, _ := ret[0].([]compute.Disk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDisks indicates an expected call of ListDisks.
+func (mr *MockClientMockRecorder) ListDisks(ctx, resourceGroup interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDisks", reflect.TypeOf((*MockClient)(nil).ListDisks), ctx, resourceGroup)
+}
+
+// Resize mocks base method.
+func (m *MockClient) Resize(ctx context.Context, spec azure0.ResourceSpecGetter, vmSize string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resize", ctx, spec, vmSize)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resize indicates an expected call of Resize.
+func (mr *MockClientMockRecorder) Resize(ctx, spec, vmSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resize", reflect.TypeOf((*MockClient)(nil).Resize), ctx, spec, vmSize)
+}
+
+// ResizeDisk mocks base method.
+func (m *MockClient) ResizeDisk(ctx context.Context, resourceGroup, diskName string, diskSizeGB int32) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResizeDisk", ctx, resourceGroup, diskName, diskSizeGB)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResizeDisk indicates an expected call of ResizeDisk.
+func (mr *MockClientMockRecorder) ResizeDisk(ctx, resourceGroup, diskName, diskSizeGB interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResizeDisk", reflect.TypeOf((*MockClient)(nil).ResizeDisk), ctx, resourceGroup, diskName, diskSizeGB)
+}
+
 // Result mocks base method.
 func (m *MockClient) Result(ctx context.Context, future azure.FutureAPI, futureType string) (interface{}, error) {
 	m.ctrl.T.Helper()
@@ -161,6 +266,51 @@ func (mr *MockClientMockRecorder) Result(ctx, future, futureType interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Result", reflect.TypeOf((*MockClient)(nil).Result), ctx, future, futureType)
 }
 
+// Start mocks base method.
+func (m *MockClient) Start(ctx context.Context, spec azure0.ResourceSpecGetter) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx, spec)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockClientMockRecorder) Start(ctx, spec interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockClient)(nil).Start), ctx, spec)
+}
+
+// UpdateAdditionalCapabilities mocks base method.
+func (m *MockClient) UpdateAdditionalCapabilities(ctx context.Context, spec azure0.ResourceSpecGetter, capabilities *compute.AdditionalCapabilities) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAdditionalCapabilities", ctx, spec, capabilities)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAdditionalCapabilities indicates an expected call of UpdateAdditionalCapabilities.
+func (mr *MockClientMockRecorder) UpdateAdditionalCapabilities(ctx, spec, capabilities interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAdditionalCapabilities", reflect.TypeOf((*MockClient)(nil).UpdateAdditionalCapabilities), ctx, spec, capabilities)
+}
+
+// UpdateDataDisks mocks base method.
+func (m *MockClient) UpdateDataDisks(ctx context.Context, spec azure0.ResourceSpecGetter, dataDisks []compute.DataDisk) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDataDisks", ctx, spec, dataDisks)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDataDisks indicates an expected call of UpdateDataDisks.
+func (mr *MockClientMockRecorder) UpdateDataDisks(ctx, spec, dataDisks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDataDisks", reflect.TypeOf((*MockClient)(nil).UpdateDataDisks), ctx, spec, dataDisks)
+}
+
 // MockgenericVMFuture is a mock of genericVMFuture interface.
 type MockgenericVMFuture struct {
 	ctrl     *gomock.Controller
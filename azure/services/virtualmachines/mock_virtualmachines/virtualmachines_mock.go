@@ -55,6 +55,20 @@ func (m *MockVMScope) EXPECT() *MockVMScopeMockRecorder {
 	return m.recorder
 }
 
+// Annotation mocks base method.
+func (m *MockVMScope) Annotation(arg0 string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Annotation", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Annotation indicates an expected call of Annotation.
+func (mr *MockVMScopeMockRecorder) Annotation(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Annotation", reflect.TypeOf((*MockVMScope)(nil).Annotation), arg0)
+}
+
 // Authorizer mocks base method.
 func (m *MockVMScope) Authorizer() autorest.Authorizer {
 	m.ctrl.T.Helper()
@@ -165,6 +179,18 @@ func (mr *MockVMScopeMockRecorder) HashKey() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockVMScope)(nil).HashKey))
 }
 
+// RemoveAnnotation mocks base method.
+func (m *MockVMScope) RemoveAnnotation(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemoveAnnotation", arg0)
+}
+
+// RemoveAnnotation indicates an expected call of RemoveAnnotation.
+func (mr *MockVMScopeMockRecorder) RemoveAnnotation(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAnnotation", reflect.TypeOf((*MockVMScope)(nil).RemoveAnnotation), arg0)
+}
+
 // SetAddresses mocks base method.
 func (m *MockVMScope) SetAddresses(arg0 []v1.NodeAddress) {
 	m.ctrl.T.Helper()
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
 	"github.com/pkg/errors"
@@ -28,35 +29,49 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/generators"
 )
 
 // VMSpec defines the specification for a Virtual Machine.
 type VMSpec struct {
-	Name                   string
-	ResourceGroup          string
-	Location               string
-	ExtendedLocation       *infrav1.ExtendedLocationSpec
-	ClusterName            string
-	Role                   string
-	NICIDs                 []string
-	SSHKeyData             string
-	Size                   string
-	AvailabilitySetID      string
-	Zone                   string
-	Identity               infrav1.VMIdentity
-	OSDisk                 infrav1.OSDisk
-	DataDisks              []infrav1.DataDisk
-	UserAssignedIdentities []infrav1.UserAssignedIdentity
-	SpotVMOptions          *infrav1.SpotVMOptions
-	SecurityProfile        *infrav1.SecurityProfile
-	AdditionalTags         infrav1.Tags
-	AdditionalCapabilities *infrav1.AdditionalCapabilities
-	DiagnosticsProfile     *infrav1.Diagnostics
-	SKU                    resourceskus.SKU
-	Image                  *infrav1.Image
-	BootstrapData          string
-	ProviderID             string
+	Name                         string
+	ResourceGroup                string
+	SubscriptionID               string
+	Location                     string
+	ExtendedLocation             *infrav1.ExtendedLocationSpec
+	ClusterName                  string
+	Role                         string
+	NICIDs                       []string
+	SSHKeyData                   string
+	AdditionalSSHKeyData         []string
+	AdminUsername                string
+	Size                         string
+	ResizeVM                     bool
+	AvailabilitySetID            string
+	Zone                         string
+	Identity                     infrav1.VMIdentity
+	OSDisk                       infrav1.OSDisk
+	ResizeOSDisk                 bool
+	DataDisks                    []infrav1.DataDisk
+	SharedDiskOwnerName          string
+	UpdateDataDisks              bool
+	UserAssignedIdentities       []infrav1.UserAssignedIdentity
+	SpotVMOptions                *infrav1.SpotVMOptions
+	TerminateNotificationTimeout *int
+	DedicatedHost                *infrav1.DedicatedHost
+	SecurityProfile              *infrav1.SecurityProfile
+	VaultSecrets                 []infrav1.VaultSecretGroup
+	VMGalleryApplications        []infrav1.VMGalleryApplication
+	AdditionalTags               infrav1.Tags
+	AdditionalCapabilities       *infrav1.AdditionalCapabilities
+	DiagnosticsProfile           *infrav1.Diagnostics
+	SKU                          resourceskus.SKU
+	Image                        *infrav1.Image
+	BootstrapData                string
+	ProviderID                   string
+	RetrieveBootLog              bool
+	MinimumVMResources           *infrav1.MinimumVMResources
 }
 
 // ResourceName returns the name of the virtual machine.
@@ -127,7 +142,10 @@ func (s *VMSpec) Parameters(ctx context.Context, existing interface{}) (params i
 		})),
 		VirtualMachineProperties: &compute.VirtualMachineProperties{
 			AdditionalCapabilities: s.generateAdditionalCapabilities(),
+			ApplicationProfile:     converters.VMGalleryApplicationsToSDK(s.VMGalleryApplications),
 			AvailabilitySet:        s.getAvailabilitySet(),
+			Host:                   s.getHost(),
+			HostGroup:              s.getHostGroup(),
 			HardwareProfile: &compute.HardwareProfile{
 				VMSize: compute.VirtualMachineSizeTypes(s.Size),
 			},
@@ -137,10 +155,11 @@ func (s *VMSpec) Parameters(ctx context.Context, existing interface{}) (params i
 			NetworkProfile: &compute.NetworkProfile{
 				NetworkInterfaces: s.generateNICRefs(),
 			},
-			Priority:           priority,
-			EvictionPolicy:     evictionPolicy,
-			BillingProfile:     billingProfile,
-			DiagnosticsProfile: converters.GetDiagnosticsProfile(s.DiagnosticsProfile),
+			Priority:               priority,
+			EvictionPolicy:         evictionPolicy,
+			BillingProfile:         billingProfile,
+			DiagnosticsProfile:     converters.GetDiagnosticsProfile(s.DiagnosticsProfile),
+			ScheduledEventsProfile: s.getScheduledEventsProfile(),
 		},
 		Identity: identity,
 		Zones:    s.getZones(),
@@ -159,23 +178,28 @@ func (s *VMSpec) generateStorageProfile() (*compute.StorageProfile, error) {
 		},
 	}
 
-	// Checking if the requested VM size has at least 2 vCPUS
-	vCPUCapability, err := s.SKU.HasCapabilityWithCapacity(resourceskus.VCPUs, resourceskus.MinimumVCPUS)
+	minVCPUs, minMemory := resourceskus.MinimumResources(s.MinimumVMResources)
+
+	// Checking if the requested VM size has at least the minimum vCPUS
+	vCPUCapability, err := s.SKU.HasCapabilityWithCapacity(resourceskus.VCPUs, minVCPUs)
 	if err != nil {
 		return nil, azure.WithTerminalError(errors.Wrap(err, "failed to validate the vCPU capability"))
 	}
 	if !vCPUCapability {
-		return nil, azure.WithTerminalError(errors.New("VM size should be bigger or equal to at least 2 vCPUs"))
+		return nil, azure.WithTerminalError(errors.Errorf("VM size should be bigger or equal to at least %d vCPUs", minVCPUs))
 	}
 
-	// Checking if the requested VM size has at least 2 Gi of memory
-	MemoryCapability, err := s.SKU.HasCapabilityWithCapacity(resourceskus.MemoryGB, resourceskus.MinimumMemory)
-	if err != nil {
-		return nil, azure.WithTerminalError(errors.Wrap(err, "failed to validate the memory capability"))
-	}
+	// Checking if the requested VM size has at least the minimum memory, VM sizes with less than 1 Gi of memory
+	// can only be used when the check is disabled.
+	if minMemory > 0 {
+		MemoryCapability, err := s.SKU.HasCapabilityWithCapacity(resourceskus.MemoryGB, minMemory)
+		if err != nil {
+			return nil, azure.WithTerminalError(errors.Wrap(err, "failed to validate the memory capability"))
+		}
 
-	if !MemoryCapability {
-		return nil, azure.WithTerminalError(errors.New("VM memory should be bigger or equal to at least 2Gi"))
+		if !MemoryCapability {
+			return nil, azure.WithTerminalError(errors.Errorf("VM memory should be bigger or equal to at least %dGi", minMemory))
+		}
 	}
 	// enable ephemeral OS
 	if s.OSDisk.DiffDiskSettings != nil {
@@ -186,6 +210,13 @@ func (s *VMSpec) generateStorageProfile() (*compute.StorageProfile, error) {
 		storageProfile.OsDisk.DiffDiskSettings = &compute.DiffDiskSettings{
 			Option: compute.DiffDiskOptions(s.OSDisk.DiffDiskSettings.Option),
 		}
+
+		if placement := s.OSDisk.DiffDiskSettings.Placement; placement != "" {
+			if !s.SKU.SupportsEphemeralOSDiskPlacement(string(placement)) {
+				return nil, azure.WithTerminalError(fmt.Errorf("VM size %s does not support placing the ephemeral os disk on the %s. Select a different VM size or ephemeral os disk placement", s.Size, placement))
+			}
+			storageProfile.OsDisk.DiffDiskSettings.Placement = compute.DiffDiskPlacement(placement)
+		}
 	}
 
 	if s.OSDisk.ManagedDisk != nil {
@@ -212,6 +243,23 @@ func (s *VMSpec) generateStorageProfile() (*compute.StorageProfile, error) {
 		}
 	}
 
+	dataDisks, err := s.generateDataDisks()
+	if err != nil {
+		return nil, err
+	}
+	storageProfile.DataDisks = &dataDisks
+
+	imageRef, err := converters.ImageToSDK(s.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	storageProfile.ImageReference = imageRef
+
+	return storageProfile, nil
+}
+
+func (s *VMSpec) generateDataDisks() ([]compute.DataDisk, error) {
 	dataDisks := make([]compute.DataDisk, len(s.DataDisks))
 	for i, disk := range s.DataDisks {
 		dataDisks[i] = compute.DataDisk{
@@ -236,17 +284,39 @@ func (s *VMSpec) generateStorageProfile() (*compute.StorageProfile, error) {
 				return nil, azure.WithTerminalError(fmt.Errorf("VM size %s does not support ultra disks in location %s. Select a different VM size or disable ultra disks", s.Size, s.Location))
 			}
 		}
-	}
-	storageProfile.DataDisks = &dataDisks
 
-	imageRef, err := converters.ImageToSDK(s.Image)
-	if err != nil {
-		return nil, err
-	}
-
-	storageProfile.ImageReference = imageRef
+		// Shared disks are created by the disks service with their size and encryption, and attached to every VM
+		// that uses them.
+		if disk.IsShared() {
+			sharedDiskName := azure.GenerateSharedDataDiskName(s.ClusterName, s.SharedDiskOwnerName, disk.NameSuffix)
+			dataDisks[i].CreateOption = compute.DiskCreateOptionTypesAttach
+			dataDisks[i].DiskSizeGB = nil
+			dataDisks[i].Name = ptr.To(sharedDiskName)
+			dataDisks[i].ManagedDisk.ID = ptr.To(azure.DiskID(s.SubscriptionID, s.ResourceGroup, sharedDiskName))
+			dataDisks[i].ManagedDisk.DiskEncryptionSet = nil
+		} else if disk.HasProvisionedPerformance() && !disk.IsExisting() {
+			// The VM API can't set the IOPS and throughput of the disks it creates, so disks with provisioned
+			// performance are created by the disks service and attached to the VM.
+			dataDiskName := azure.GenerateDataDiskName(s.Name, disk.NameSuffix)
+			dataDisks[i].CreateOption = compute.DiskCreateOptionTypesAttach
+			dataDisks[i].DiskSizeGB = nil
+			dataDisks[i].ManagedDisk.ID = ptr.To(azure.DiskID(s.SubscriptionID, s.ResourceGroup, dataDiskName))
+			dataDisks[i].ManagedDisk.DiskEncryptionSet = nil
+		}
 
-	return storageProfile, nil
+		// Existing disks are attached as they are.
+		if disk.IsExisting() {
+			diskID, err := azureutil.ParseResourceID(disk.ManagedDiskID)
+			if err != nil {
+				return nil, azure.WithTerminalError(errors.Wrapf(err, "failed to parse the managed disk ID of data disk %s", disk.NameSuffix))
+			}
+			dataDisks[i].CreateOption = compute.DiskCreateOptionTypesAttach
+			dataDisks[i].DiskSizeGB = nil
+			dataDisks[i].Name = ptr.To(diskID.Name)
+			dataDisks[i].ManagedDisk = &compute.ManagedDiskParameters{ID: ptr.To(disk.ManagedDiskID)}
+		}
+	}
+	return dataDisks, nil
 }
 
 func (s *VMSpec) generateOSProfile() (*compute.OSProfile, error) {
@@ -255,10 +325,16 @@ func (s *VMSpec) generateOSProfile() (*compute.OSProfile, error) {
 		return nil, errors.Wrap(err, "failed to decode ssh public key")
 	}
 
+	adminUsername := s.AdminUsername
+	if adminUsername == "" {
+		adminUsername = azure.DefaultUserName
+	}
+
 	osProfile := &compute.OSProfile{
 		ComputerName:  ptr.To(s.Name),
-		AdminUsername: ptr.To(azure.DefaultUserName),
+		AdminUsername: ptr.To(adminUsername),
 		CustomData:    ptr.To(s.BootstrapData),
+		Secrets:       converters.VaultSecretsToSDK(s.VaultSecrets),
 	}
 
 	switch s.OSDisk.OSType {
@@ -275,15 +351,28 @@ func (s *VMSpec) generateOSProfile() (*compute.OSProfile, error) {
 			EnableAutomaticUpdates: ptr.To(false),
 		}
 	default:
+		authorizedKeysPath := fmt.Sprintf("/home/%s/.ssh/authorized_keys", adminUsername)
+		publicKeys := []compute.SSHPublicKey{
+			{
+				Path:    ptr.To(authorizedKeysPath),
+				KeyData: ptr.To(string(sshKey)),
+			},
+		}
+		for _, keyData := range s.AdditionalSSHKeyData {
+			additionalKey, err := base64.StdEncoding.DecodeString(keyData)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to decode additional ssh public key")
+			}
+			publicKeys = append(publicKeys, compute.SSHPublicKey{
+				Path:    ptr.To(authorizedKeysPath),
+				KeyData: ptr.To(string(additionalKey)),
+			})
+		}
+
 		osProfile.LinuxConfiguration = &compute.LinuxConfiguration{
 			DisablePasswordAuthentication: ptr.To(true),
 			SSH: &compute.SSHConfiguration{
-				PublicKeys: &[]compute.SSHPublicKey{
-					{
-						Path:    ptr.To(fmt.Sprintf("/home/%s/.ssh/authorized_keys", azure.DefaultUserName)),
-						KeyData: ptr.To(string(sshKey)),
-					},
-				},
+				PublicKeys: &publicKeys,
 			},
 		}
 	}
@@ -296,78 +385,52 @@ func (s *VMSpec) generateSecurityProfile(storageProfile *compute.StorageProfile)
 		return nil, nil
 	}
 
+	// The combinations of security type, disk encryption, host encryption, secure boot and vTPM are validated by the
+	// AzureMachine webhook, only the capabilities of the VM size are checked here.
 	securityProfile := &compute.SecurityProfile{}
 
-	if storageProfile.OsDisk.ManagedDisk != nil &&
-		storageProfile.OsDisk.ManagedDisk.SecurityProfile != nil &&
-		storageProfile.OsDisk.ManagedDisk.SecurityProfile.SecurityEncryptionType != "" {
-		if s.SecurityProfile.EncryptionAtHost != nil && *s.SecurityProfile.EncryptionAtHost &&
-			storageProfile.OsDisk.ManagedDisk.SecurityProfile.SecurityEncryptionType == compute.SecurityEncryptionTypesDiskWithVMGuestState {
-			return nil, azure.WithTerminalError(errors.Errorf("encryption at host is not supported when securityEncryptionType is set to %s", compute.SecurityEncryptionTypesDiskWithVMGuestState))
-		}
-
-		if s.SecurityProfile.SecurityType != infrav1.SecurityTypesConfidentialVM {
-			return nil, azure.WithTerminalError(errors.Errorf("securityType should be set to %s when securityEncryptionType is set", infrav1.SecurityTypesConfidentialVM))
-		}
-
-		if s.SecurityProfile.UefiSettings == nil {
-			return nil, azure.WithTerminalError(errors.New("vTpmEnabled should be true when securityEncryptionType is set"))
-		}
-
-		if storageProfile.OsDisk.ManagedDisk.SecurityProfile.SecurityEncryptionType == compute.SecurityEncryptionTypesDiskWithVMGuestState &&
-			!*s.SecurityProfile.UefiSettings.SecureBootEnabled {
-			return nil, azure.WithTerminalError(errors.Errorf("secureBootEnabled should be true when securityEncryptionType is set to %s", compute.SecurityEncryptionTypesDiskWithVMGuestState))
+	if s.SecurityProfile.EncryptionAtHost != nil {
+		if !s.SKU.HasCapability(resourceskus.EncryptionAtHost) && *s.SecurityProfile.EncryptionAtHost {
+			return nil, azure.WithTerminalError(errors.Errorf("encryption at host is not supported for VM type %s", s.Size))
 		}
 
-		if s.SecurityProfile.UefiSettings.VTpmEnabled != nil && !*s.SecurityProfile.UefiSettings.VTpmEnabled {
-			return nil, azure.WithTerminalError(errors.New("vTpmEnabled should be true when securityEncryptionType is set"))
-		}
+		securityProfile.EncryptionAtHost = s.SecurityProfile.EncryptionAtHost
+	}
 
+	if storageProfile.OsDisk.ManagedDisk != nil &&
+		storageProfile.OsDisk.ManagedDisk.SecurityProfile != nil &&
+		storageProfile.OsDisk.ManagedDisk.SecurityProfile.SecurityEncryptionType != "" {
 		securityProfile.SecurityType = compute.SecurityTypesConfidentialVM
 
-		securityProfile.UefiSettings = &compute.UefiSettings{
-			SecureBootEnabled: s.SecurityProfile.UefiSettings.SecureBootEnabled,
-			VTpmEnabled:       s.SecurityProfile.UefiSettings.VTpmEnabled,
+		if s.SecurityProfile.UefiSettings != nil {
+			securityProfile.UefiSettings = &compute.UefiSettings{
+				SecureBootEnabled: s.SecurityProfile.UefiSettings.SecureBootEnabled,
+				VTpmEnabled:       s.SecurityProfile.UefiSettings.VTpmEnabled,
+			}
 		}
 
 		return securityProfile, nil
 	}
 
-	if s.SecurityProfile.EncryptionAtHost != nil {
-		if !s.SKU.HasCapability(resourceskus.EncryptionAtHost) && *s.SecurityProfile.EncryptionAtHost {
-			return nil, azure.WithTerminalError(errors.Errorf("encryption at host is not supported for VM type %s", s.Size))
-		}
-
-		securityProfile.EncryptionAtHost = s.SecurityProfile.EncryptionAtHost
-	}
-
 	hasTrustedLaunchDisabled := s.SKU.HasCapability(resourceskus.TrustedLaunchDisabled)
 
 	if s.SecurityProfile.UefiSettings != nil {
 		securityProfile.UefiSettings = &compute.UefiSettings{}
 
-		if s.SecurityProfile.UefiSettings.SecureBootEnabled != nil && *s.SecurityProfile.UefiSettings.SecureBootEnabled {
+		if ptr.Deref(s.SecurityProfile.UefiSettings.SecureBootEnabled, false) {
 			if hasTrustedLaunchDisabled {
 				return nil, azure.WithTerminalError(errors.Errorf("secure boot is not supported for VM type %s", s.Size))
 			}
 
-			if s.SecurityProfile.SecurityType != infrav1.SecurityTypesTrustedLaunch {
-				return nil, azure.WithTerminalError(errors.Errorf("securityType should be set to %s when secureBootEnabled is true", infrav1.SecurityTypesTrustedLaunch))
-			}
-
 			securityProfile.SecurityType = compute.SecurityTypesTrustedLaunch
 			securityProfile.UefiSettings.SecureBootEnabled = ptr.To(true)
 		}
 
-		if s.SecurityProfile.UefiSettings.VTpmEnabled != nil && *s.SecurityProfile.UefiSettings.VTpmEnabled {
+		if ptr.Deref(s.SecurityProfile.UefiSettings.VTpmEnabled, false) {
 			if hasTrustedLaunchDisabled {
 				return nil, azure.WithTerminalError(errors.Errorf("vTPM is not supported for VM type %s", s.Size))
 			}
 
-			if s.SecurityProfile.SecurityType != infrav1.SecurityTypesTrustedLaunch {
-				return nil, azure.WithTerminalError(errors.Errorf("securityType should be set to %s when vTpmEnabled is true", infrav1.SecurityTypesTrustedLaunch))
-			}
-
 			securityProfile.SecurityType = compute.SecurityTypesTrustedLaunch
 			securityProfile.UefiSettings.VTpmEnabled = ptr.To(true)
 		}
@@ -413,11 +476,187 @@ func (s *VMSpec) generateAdditionalCapabilities() *compute.AdditionalCapabilitie
 		if s.AdditionalCapabilities.UltraSSDEnabled != nil {
 			capabilities.UltraSSDEnabled = s.AdditionalCapabilities.UltraSSDEnabled
 		}
+		if s.AdditionalCapabilities.HibernationEnabled != nil {
+			capabilities.HibernationEnabled = s.AdditionalCapabilities.HibernationEnabled
+		}
 	}
 
 	return capabilities
 }
 
+// deallocateOnChange returns true if the VM may be deallocated to change its additional capabilities.
+func (s *VMSpec) deallocateOnChange() bool {
+	return s.AdditionalCapabilities != nil && s.AdditionalCapabilities.DeallocateOnChange
+}
+
+// osDiskGrown returns true if the OS disk of the existing VM is smaller than the spec and may be resized in place.
+func (s *VMSpec) osDiskGrown(vm compute.VirtualMachine) bool {
+	if !s.ResizeOSDisk || s.OSDisk.DiskSizeGB == nil || s.OSDisk.DiffDiskSettings != nil {
+		return false
+	}
+	if vm.VirtualMachineProperties == nil || vm.StorageProfile == nil || vm.StorageProfile.OsDisk == nil || vm.StorageProfile.OsDisk.DiskSizeGB == nil {
+		return false
+	}
+	return *s.OSDisk.DiskSizeGB > *vm.StorageProfile.OsDisk.DiskSizeGB
+}
+
+// osDiskName returns the name of the OS disk of an existing VM.
+func (s *VMSpec) osDiskName(vm compute.VirtualMachine) string {
+	if vm.VirtualMachineProperties != nil && vm.StorageProfile != nil && vm.StorageProfile.OsDisk != nil && vm.StorageProfile.OsDisk.Name != nil {
+		return *vm.StorageProfile.OsDisk.Name
+	}
+	return azure.GenerateOSDiskName(s.Name)
+}
+
+// dataDisksChanged returns the data disks an existing VM should have and the data disks to detach from it, if the
+// machine opted in to attaching and detaching data disks in place and the data disks of the VM differ from the spec.
+// Data disks are matched by name, the disks the VM keeps are left as they are.
+func (s *VMSpec) dataDisksChanged(vm compute.VirtualMachine) (dataDisks, detached []compute.DataDisk, changed bool, err error) {
+	if !s.UpdateDataDisks || vm.VirtualMachineProperties == nil || vm.StorageProfile == nil {
+		return nil, nil, false, nil
+	}
+	desired, err := s.generateDataDisks()
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	desiredNames := make(map[string]struct{}, len(desired))
+	for _, disk := range desired {
+		desiredNames[strings.ToLower(ptr.Deref(disk.Name, ""))] = struct{}{}
+	}
+	existingNames := make(map[string]struct{})
+	dataDisks = []compute.DataDisk{}
+	if vm.StorageProfile.DataDisks != nil {
+		for _, disk := range *vm.StorageProfile.DataDisks {
+			name := strings.ToLower(ptr.Deref(disk.Name, ""))
+			existingNames[name] = struct{}{}
+			if _, ok := desiredNames[name]; ok {
+				dataDisks = append(dataDisks, disk)
+			} else {
+				detached = append(detached, disk)
+			}
+		}
+	}
+	attached := false
+	for _, disk := range desired {
+		if _, ok := existingNames[strings.ToLower(ptr.Deref(disk.Name, ""))]; !ok {
+			dataDisks = append(dataDisks, disk)
+			attached = true
+		}
+	}
+	if !attached && len(detached) == 0 {
+		return nil, nil, false, nil
+	}
+	return dataDisks, detached, true, nil
+}
+
+// createdForVM returns true if a data disk of the VM was created by CAPZ for it, as opposed to a shared disk or an
+// existing disk that was attached to it.
+func (s *VMSpec) createdForVM(disk compute.DataDisk) bool {
+	return strings.HasPrefix(ptr.Deref(disk.Name, ""), azure.GenerateDataDiskName(s.Name, ""))
+}
+
+// detachedDataDisks returns the names of the disks created by CAPZ for the VM that are no longer data disks of its spec
+// and are not attached to any VM, i.e. the disks that were detached from the VM and are left to delete.
+func (s *VMSpec) detachedDataDisks(disks []compute.Disk) ([]string, error) {
+	desired, err := s.generateDataDisks()
+	if err != nil {
+		return nil, err
+	}
+	desiredNames := map[string]struct{}{strings.ToLower(azure.GenerateOSDiskName(s.Name)): {}}
+	for _, disk := range desired {
+		desiredNames[strings.ToLower(ptr.Deref(disk.Name, ""))] = struct{}{}
+	}
+
+	var detached []string
+	for _, disk := range disks {
+		name := ptr.Deref(disk.Name, "")
+		if _, ok := desiredNames[strings.ToLower(name)]; ok || !s.createdForVM(compute.DataDisk{Name: disk.Name}) {
+			continue
+		}
+		if ptr.Deref(disk.ManagedBy, "") != "" {
+			// the disk is still attached, or being detached
+			continue
+		}
+		detached = append(detached, name)
+	}
+	return detached, nil
+}
+
+// vmSizeChanged returns true if the existing VM has a different size than the spec and may be resized in place.
+func (s *VMSpec) vmSizeChanged(vm compute.VirtualMachine) bool {
+	if !s.ResizeVM || s.Size == "" || vm.VirtualMachineProperties == nil || vm.HardwareProfile == nil {
+		return false
+	}
+	return !strings.EqualFold(string(vm.HardwareProfile.VMSize), s.Size)
+}
+
+// validateResize checks that the VM size of the spec can host the existing VM: it must be offered in the zone of the VM
+// and support its disks and network interfaces.
+func (s *VMSpec) validateResize(acceleratedNetworking bool) error {
+	if s.Zone != "" && !s.SKU.IsAvailableInZone(s.Location, s.Zone) {
+		return errors.Errorf("VM size %s is not available in zone %s of location %s", s.Size, s.Zone, s.Location)
+	}
+
+	if s.OSDisk.DiffDiskSettings != nil && !s.SKU.HasCapability(resourceskus.EphemeralOSDisk) {
+		return errors.Errorf("VM size %s does not support ephemeral os", s.Size)
+	}
+
+	premiumStorage := s.OSDisk.ManagedDisk != nil && strings.HasPrefix(s.OSDisk.ManagedDisk.StorageAccountType, "Premium")
+	for _, disk := range s.DataDisks {
+		if disk.ManagedDisk == nil {
+			continue
+		}
+		if strings.HasPrefix(disk.ManagedDisk.StorageAccountType, "Premium") {
+			premiumStorage = true
+		}
+		if disk.ManagedDisk.StorageAccountType == string(compute.StorageAccountTypesUltraSSDLRS) && !s.SKU.HasLocationCapability(resourceskus.UltraSSDAvailable, s.Location, s.Zone) {
+			return errors.Errorf("VM size %s does not support ultra disks in location %s", s.Size, s.Location)
+		}
+	}
+	if premiumStorage && !s.SKU.HasCapability(resourceskus.PremiumIO) {
+		return errors.Errorf("VM size %s does not support premium storage", s.Size)
+	}
+
+	if s.SecurityProfile != nil && ptr.Deref(s.SecurityProfile.EncryptionAtHost, false) && !s.SKU.HasCapability(resourceskus.EncryptionAtHost) {
+		return errors.Errorf("encryption at host is not supported for VM type %s", s.Size)
+	}
+
+	if acceleratedNetworking && !s.SKU.HasCapability(resourceskus.AcceleratedNetworking) {
+		return errors.Errorf("VM size %s does not support accelerated networking", s.Size)
+	}
+
+	if len(s.NICIDs) > 1 {
+		supported, err := s.SKU.HasCapabilityWithCapacity(resourceskus.MaxNetworkInterfaces, int64(len(s.NICIDs)))
+		if err != nil {
+			return errors.Wrap(err, "failed to validate the network interfaces capability")
+		}
+		if !supported {
+			return errors.Errorf("VM size %s supports fewer than %d network interfaces", s.Size, len(s.NICIDs))
+		}
+	}
+
+	return nil
+}
+
+// additionalCapabilitiesChanged returns true if any capability set in the desired capabilities differs
+// from the existing ones. Capabilities that are not set are left as they are.
+func additionalCapabilitiesChanged(existing, desired *compute.AdditionalCapabilities) bool {
+	if desired == nil {
+		return false
+	}
+	if existing == nil {
+		existing = &compute.AdditionalCapabilities{}
+	}
+	if desired.UltraSSDEnabled != nil && *desired.UltraSSDEnabled != ptr.Deref(existing.UltraSSDEnabled, false) {
+		return true
+	}
+	if desired.HibernationEnabled != nil && *desired.HibernationEnabled != ptr.Deref(existing.HibernationEnabled, false) {
+		return true
+	}
+	return false
+}
+
 func (s *VMSpec) getAvailabilitySet() *compute.SubResource {
 	var as *compute.SubResource
 	if s.AvailabilitySetID != "" {
@@ -426,6 +665,32 @@ func (s *VMSpec) getAvailabilitySet() *compute.SubResource {
 	return as
 }
 
+func (s *VMSpec) getHost() *compute.SubResource {
+	if s.DedicatedHost == nil || s.DedicatedHost.HostID == "" {
+		return nil
+	}
+	return &compute.SubResource{ID: ptr.To(s.DedicatedHost.HostID)}
+}
+
+func (s *VMSpec) getScheduledEventsProfile() *compute.ScheduledEventsProfile {
+	if s.TerminateNotificationTimeout == nil {
+		return nil
+	}
+	return &compute.ScheduledEventsProfile{
+		TerminateNotificationProfile: &compute.TerminateNotificationProfile{
+			NotBeforeTimeout: ptr.To(fmt.Sprintf("PT%dM", *s.TerminateNotificationTimeout)),
+			Enable:           ptr.To(true),
+		},
+	}
+}
+
+func (s *VMSpec) getHostGroup() *compute.SubResource {
+	if s.DedicatedHost == nil || s.DedicatedHost.HostGroupID == "" {
+		return nil
+	}
+	return &compute.SubResource{ID: ptr.To(s.DedicatedHost.HostGroupID)}
+}
+
 func (s *VMSpec) getZones() *[]string {
 	var zones *[]string
 	if s.Zone != "" {
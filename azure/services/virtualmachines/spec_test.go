@@ -18,6 +18,7 @@ package virtualmachines
 
 import (
 	"context"
+	"encoding/base64"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
@@ -27,6 +28,7 @@ import (
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 )
@@ -135,6 +137,10 @@ var (
 				Name:  ptr.To(resourceskus.EphemeralOSDisk),
 				Value: ptr.To("True"),
 			},
+			{
+				Name:  ptr.To(resourceskus.SupportedEphemeralOSDiskPlacements),
+				Value: ptr.To("ResourceDisk"),
+			},
 		},
 	}
 
@@ -313,6 +319,52 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a vm on a dedicated host",
+			spec: &VMSpec{
+				Name:          "my-vm",
+				Role:          infrav1.Node,
+				NICIDs:        []string{"my-nic"},
+				SSHKeyData:    "fakesshpublickey",
+				Size:          "Standard_D2v3",
+				Zone:          "1",
+				Image:         &infrav1.Image{ID: ptr.To("fake-image-id")},
+				DedicatedHost: &infrav1.DedicatedHost{HostID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/hostGroups/my-host-group/hosts/my-host"},
+				SKU:           validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				g.Expect(result.(compute.VirtualMachine).Host).To(Equal(&compute.SubResource{ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/hostGroups/my-host-group/hosts/my-host")}))
+				g.Expect(result.(compute.VirtualMachine).HostGroup).To(BeNil())
+			},
+			expectedError: "",
+		},
+		{
+			name: "can create a vm with a terminate notification",
+			spec: &VMSpec{
+				Name:                         "my-vm",
+				Role:                         infrav1.Node,
+				NICIDs:                       []string{"my-nic"},
+				SSHKeyData:                   "fakesshpublickey",
+				Size:                         "Standard_D2v3",
+				Zone:                         "1",
+				Image:                        &infrav1.Image{ID: ptr.To("fake-image-id")},
+				TerminateNotificationTimeout: ptr.To(7),
+				SKU:                          validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				g.Expect(result.(compute.VirtualMachine).ScheduledEventsProfile).To(Equal(&compute.ScheduledEventsProfile{
+					TerminateNotificationProfile: &compute.TerminateNotificationProfile{
+						NotBeforeTimeout: ptr.To("PT7M"),
+						Enable:           ptr.To(true),
+					},
+				}))
+			},
+			expectedError: "",
+		},
 
 		{
 			name: "can create a spot vm with evictionPolicy delete",
@@ -365,6 +417,43 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a vm with an existing data disk",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Zone:       "1",
+				Image:      &infrav1.Image{ID: ptr.To("fake-image-id")},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix:    "etcddisk",
+						ManagedDiskID: "/subscriptions/123/resourceGroups/restore-rg/providers/Microsoft.Compute/disks/restored-etcddisk",
+						Lun:           ptr.To[int32](0),
+						CachingType:   "None",
+					},
+				},
+				SKU: validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				g.Expect(*result.(compute.VirtualMachine).StorageProfile.DataDisks).To(Equal([]compute.DataDisk{
+					{
+						CreateOption: compute.DiskCreateOptionTypesAttach,
+						Lun:          ptr.To[int32](0),
+						Name:         ptr.To("restored-etcddisk"),
+						Caching:      compute.CachingTypesNone,
+						ManagedDisk: &compute.ManagedDiskParameters{
+							ID: ptr.To("/subscriptions/123/resourceGroups/restore-rg/providers/Microsoft.Compute/disks/restored-etcddisk"),
+						},
+					},
+				}))
+			},
+			expectedError: "",
+		},
 		{
 			name: "can create a vm with encryption",
 			spec: &VMSpec{
@@ -412,6 +501,62 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a vm with Key Vault certificates",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Zone:       "1",
+				Image:      &infrav1.Image{ID: ptr.To("fake-image-id")},
+				VaultSecrets: []infrav1.VaultSecretGroup{
+					{
+						SourceVault: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/my-vault",
+						VaultCertificates: []infrav1.VaultCertificate{
+							{CertificateURL: "https://my-vault.vault.azure.net/secrets/my-cert/1"},
+						},
+					},
+				},
+				SKU: validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				secrets := *result.(compute.VirtualMachine).OsProfile.Secrets
+				g.Expect(secrets).To(HaveLen(1))
+				g.Expect(*secrets[0].SourceVault.ID).To(Equal("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/my-vault"))
+				g.Expect(*(*secrets[0].VaultCertificates)[0].CertificateURL).To(Equal("https://my-vault.vault.azure.net/secrets/my-cert/1"))
+				g.Expect((*secrets[0].VaultCertificates)[0].CertificateStore).To(BeNil())
+			},
+			expectedError: "",
+		},
+		{
+			name: "can create a vm with gallery applications",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Zone:       "1",
+				Image:      &infrav1.Image{ID: ptr.To("fake-image-id")},
+				VMGalleryApplications: []infrav1.VMGalleryApplication{
+					{Version: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/galleries/my-gallery/applications/my-agent/versions/1.0.0", Order: ptr.To[int32](1)},
+				},
+				SKU: validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				apps := *result.(compute.VirtualMachine).ApplicationProfile.GalleryApplications
+				g.Expect(apps).To(HaveLen(1))
+				g.Expect(*apps[0].PackageReferenceID).To(Equal("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/galleries/my-gallery/applications/my-agent/versions/1.0.0"))
+				g.Expect(*apps[0].Order).To(Equal(int32(1)))
+			},
+			expectedError: "",
+		},
 		{
 			name: "can create a vm and assign it to an availability set",
 			spec: &VMSpec{
@@ -462,35 +607,58 @@ func TestParameters(t *testing.T) {
 			expectedError: "",
 		},
 		{
-			name: "can create a trusted launch vm",
+			name: "can create a vm with EphemeralOSDisk on the resource disk",
 			spec: &VMSpec{
-				Name:              "my-vm",
-				Role:              infrav1.Node,
-				NICIDs:            []string{"my-nic"},
-				SSHKeyData:        "fakesshpublickey",
-				Size:              "Standard_D2v3",
-				AvailabilitySetID: "fake-availability-set-id",
-				Zone:              "",
-				Image:             &infrav1.Image{ID: ptr.To("fake-image-id")},
-				SecurityProfile: &infrav1.SecurityProfile{
-					SecurityType: infrav1.SecurityTypesTrustedLaunch,
-					UefiSettings: &infrav1.UefiSettings{
-						SecureBootEnabled: ptr.To(true),
-						VTpmEnabled:       ptr.To(true),
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				OSDisk: infrav1.OSDisk{
+					OSType:     "Linux",
+					DiskSizeGB: ptr.To[int32](128),
+					DiffDiskSettings: &infrav1.DiffDiskSettings{
+						Option:    string(compute.DiffDiskOptionsLocal),
+						Placement: infrav1.DiffDiskPlacementResourceDisk,
 					},
 				},
-				SKU: validSKU,
+				Image: &infrav1.Image{ID: ptr.To("fake-image-id")},
+				SKU:   validSKUWithEphemeralOS,
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
 				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
-				g.Expect(*result.(compute.VirtualMachine).SecurityProfile.UefiSettings.SecureBootEnabled).To(BeTrue())
-				g.Expect(*result.(compute.VirtualMachine).SecurityProfile.UefiSettings.VTpmEnabled).To(BeTrue())
+				g.Expect(result.(compute.VirtualMachine).StorageProfile.OsDisk.DiffDiskSettings.Placement).To(Equal(compute.DiffDiskPlacementResourceDisk))
 			},
 			expectedError: "",
 		},
 		{
-			name: "can create a confidential vm",
+			name: "creating a vm with EphemeralOSDisk on an unsupported placement fails",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				OSDisk: infrav1.OSDisk{
+					OSType:     "Linux",
+					DiskSizeGB: ptr.To[int32](128),
+					DiffDiskSettings: &infrav1.DiffDiskSettings{
+						Option:    string(compute.DiffDiskOptionsLocal),
+						Placement: infrav1.DiffDiskPlacementCacheDisk,
+					},
+				},
+				Image: &infrav1.Image{ID: ptr.To("fake-image-id")},
+				SKU:   validSKUWithEphemeralOS,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "reconcile error that cannot be recovered occurred: VM size Standard_D2v3 does not support placing the ephemeral os disk on the CacheDisk. Select a different VM size or ephemeral os disk placement. Object will not be requeued",
+		},
+		{
+			name: "can create a trusted launch vm",
 			spec: &VMSpec{
 				Name:              "my-vm",
 				Role:              infrav1.Node,
@@ -500,35 +668,25 @@ func TestParameters(t *testing.T) {
 				AvailabilitySetID: "fake-availability-set-id",
 				Zone:              "",
 				Image:             &infrav1.Image{ID: ptr.To("fake-image-id")},
-				OSDisk: infrav1.OSDisk{
-					OSType:     "Linux",
-					DiskSizeGB: ptr.To[int32](128),
-					ManagedDisk: &infrav1.ManagedDiskParameters{
-						StorageAccountType: "Premium_LRS",
-						SecurityProfile: &infrav1.VMDiskSecurityProfile{
-							SecurityEncryptionType: infrav1.SecurityEncryptionTypeVMGuestStateOnly,
-						},
-					},
-				},
 				SecurityProfile: &infrav1.SecurityProfile{
-					SecurityType: infrav1.SecurityTypesConfidentialVM,
+					SecurityType: infrav1.SecurityTypesTrustedLaunch,
 					UefiSettings: &infrav1.UefiSettings{
-						SecureBootEnabled: ptr.To(false),
+						SecureBootEnabled: ptr.To(true),
 						VTpmEnabled:       ptr.To(true),
 					},
 				},
-				SKU: validSKUWithConfidentialComputingType,
+				SKU: validSKU,
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
 				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
-				g.Expect(result.(compute.VirtualMachine).StorageProfile.OsDisk.ManagedDisk.SecurityProfile.SecurityEncryptionType).To(Equal(compute.SecurityEncryptionTypesVMGuestStateOnly))
+				g.Expect(*result.(compute.VirtualMachine).SecurityProfile.UefiSettings.SecureBootEnabled).To(BeTrue())
 				g.Expect(*result.(compute.VirtualMachine).SecurityProfile.UefiSettings.VTpmEnabled).To(BeTrue())
 			},
 			expectedError: "",
 		},
 		{
-			name: "creating a confidential vm without the SecurityType set to ConfidentialVM fails",
+			name: "can create a confidential vm",
 			spec: &VMSpec{
 				Name:              "my-vm",
 				Role:              infrav1.Node,
@@ -549,7 +707,7 @@ func TestParameters(t *testing.T) {
 					},
 				},
 				SecurityProfile: &infrav1.SecurityProfile{
-					SecurityType: "",
+					SecurityType: infrav1.SecurityTypesConfidentialVM,
 					UefiSettings: &infrav1.UefiSettings{
 						SecureBootEnabled: ptr.To(false),
 						VTpmEnabled:       ptr.To(true),
@@ -559,9 +717,11 @@ func TestParameters(t *testing.T) {
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeNil())
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				g.Expect(result.(compute.VirtualMachine).StorageProfile.OsDisk.ManagedDisk.SecurityProfile.SecurityEncryptionType).To(Equal(compute.SecurityEncryptionTypesVMGuestStateOnly))
+				g.Expect(*result.(compute.VirtualMachine).SecurityProfile.UefiSettings.VTpmEnabled).To(BeTrue())
 			},
-			expectedError: "reconcile error that cannot be recovered occurred: securityType should be set to ConfidentialVM when securityEncryptionType is set. Object will not be requeued",
+			expectedError: "",
 		},
 		{
 			name: "creating a vm with encryption at host enabled for unsupported VM type fails",
@@ -583,39 +743,6 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "reconcile error that cannot be recovered occurred: encryption at host is not supported for VM type Standard_D2v3. Object will not be requeued",
 		},
-		{
-			name: "creating a trusted launch vm without the SecurityType set to TrustedLaunch fails",
-			spec: &VMSpec{
-				Name:              "my-vm",
-				Role:              infrav1.Node,
-				NICIDs:            []string{"my-nic"},
-				SSHKeyData:        "fakesshpublickey",
-				Size:              "Standard_D2v3",
-				AvailabilitySetID: "fake-availability-set-id",
-				Zone:              "",
-				Image:             &infrav1.Image{ID: ptr.To("fake-image-id")},
-				OSDisk: infrav1.OSDisk{
-					OSType:     "Linux",
-					DiskSizeGB: ptr.To[int32](128),
-					ManagedDisk: &infrav1.ManagedDiskParameters{
-						StorageAccountType: "Premium_LRS",
-					},
-				},
-				SecurityProfile: &infrav1.SecurityProfile{
-					SecurityType: "",
-					UefiSettings: &infrav1.UefiSettings{
-						SecureBootEnabled: ptr.To(false),
-						VTpmEnabled:       ptr.To(true),
-					},
-				},
-				SKU: validSKUWithConfidentialComputingType,
-			},
-			existing: nil,
-			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeNil())
-			},
-			expectedError: "reconcile error that cannot be recovered occurred: securityType should be set to TrustedLaunch when vTpmEnabled is true. Object will not be requeued",
-		},
 		{
 			name: "creating a trusted launch vm with secure boot enabled on unsupported VM type fails",
 			spec: &VMSpec{
@@ -666,113 +793,6 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "reconcile error that cannot be recovered occurred: vTPM is not supported for VM type Standard_D2v3. Object will not be requeued",
 		},
-		{
-			name: "creating a confidential vm with securityTypeEncryption DiskWithVMGuestState and encryption at host enabled fails",
-			spec: &VMSpec{
-				Name:              "my-vm",
-				Role:              infrav1.Node,
-				NICIDs:            []string{"my-nic"},
-				SSHKeyData:        "fakesshpublickey",
-				Size:              "Standard_D2v3",
-				AvailabilitySetID: "fake-availability-set-id",
-				Zone:              "",
-				Image:             &infrav1.Image{ID: ptr.To("fake-image-id")},
-				OSDisk: infrav1.OSDisk{
-					OSType:     "Linux",
-					DiskSizeGB: ptr.To[int32](128),
-					ManagedDisk: &infrav1.ManagedDiskParameters{
-						StorageAccountType: "Premium_LRS",
-						SecurityProfile: &infrav1.VMDiskSecurityProfile{
-							SecurityEncryptionType: infrav1.SecurityEncryptionTypeDiskWithVMGuestState,
-						},
-					},
-				},
-				SecurityProfile: &infrav1.SecurityProfile{
-					EncryptionAtHost: ptr.To(true),
-					SecurityType:     infrav1.SecurityTypesConfidentialVM,
-					UefiSettings: &infrav1.UefiSettings{
-						VTpmEnabled: ptr.To(true),
-					},
-				},
-				SKU: validSKUWithConfidentialComputingType,
-			},
-			existing: nil,
-			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeNil())
-			},
-			expectedError: "reconcile error that cannot be recovered occurred: encryption at host is not supported when securityEncryptionType is set to DiskWithVMGuestState. Object will not be requeued",
-		},
-		{
-			name: "creating a confidential vm with DiskWithVMGuestState encryption type and secure boot disabled fails",
-			spec: &VMSpec{
-				Name:              "my-vm",
-				Role:              infrav1.Node,
-				NICIDs:            []string{"my-nic"},
-				SSHKeyData:        "fakesshpublickey",
-				Size:              "Standard_D2v3",
-				AvailabilitySetID: "fake-availability-set-id",
-				Zone:              "",
-				Image:             &infrav1.Image{ID: ptr.To("fake-image-id")},
-				OSDisk: infrav1.OSDisk{
-					OSType:     "Linux",
-					DiskSizeGB: ptr.To[int32](128),
-					ManagedDisk: &infrav1.ManagedDiskParameters{
-						StorageAccountType: "Premium_LRS",
-						SecurityProfile: &infrav1.VMDiskSecurityProfile{
-							SecurityEncryptionType: infrav1.SecurityEncryptionTypeDiskWithVMGuestState,
-						},
-					},
-				},
-				SecurityProfile: &infrav1.SecurityProfile{
-					SecurityType: infrav1.SecurityTypesConfidentialVM,
-					UefiSettings: &infrav1.UefiSettings{
-						SecureBootEnabled: ptr.To(false),
-						VTpmEnabled:       ptr.To(true),
-					},
-				},
-				SKU: validSKUWithConfidentialComputingType,
-			},
-			existing: nil,
-			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeNil())
-			},
-			expectedError: "reconcile error that cannot be recovered occurred: secureBootEnabled should be true when securityEncryptionType is set to DiskWithVMGuestState. Object will not be requeued",
-		},
-		{
-			name: "creating a confidential vm with vTPM disabled fails",
-			spec: &VMSpec{
-				Name:              "my-vm",
-				Role:              infrav1.Node,
-				NICIDs:            []string{"my-nic"},
-				SSHKeyData:        "fakesshpublickey",
-				Size:              "Standard_D2v3",
-				AvailabilitySetID: "fake-availability-set-id",
-				Zone:              "",
-				Image:             &infrav1.Image{ID: ptr.To("fake-image-id")},
-				OSDisk: infrav1.OSDisk{
-					OSType:     "Linux",
-					DiskSizeGB: ptr.To[int32](128),
-					ManagedDisk: &infrav1.ManagedDiskParameters{
-						StorageAccountType: "Premium_LRS",
-						SecurityProfile: &infrav1.VMDiskSecurityProfile{
-							SecurityEncryptionType: infrav1.SecurityEncryptionTypeVMGuestStateOnly,
-						},
-					},
-				},
-				SecurityProfile: &infrav1.SecurityProfile{
-					SecurityType: infrav1.SecurityTypesConfidentialVM,
-					UefiSettings: &infrav1.UefiSettings{
-						VTpmEnabled: ptr.To(false),
-					},
-				},
-				SKU: validSKUWithConfidentialComputingType,
-			},
-			existing: nil,
-			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeNil())
-			},
-			expectedError: "reconcile error that cannot be recovered occurred: vTpmEnabled should be true when securityEncryptionType is set. Object will not be requeued",
-		},
 		{
 			name: "creating a confidential vm with unsupported VM type fails",
 			spec: &VMSpec{
@@ -869,6 +889,47 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "reconcile error that cannot be recovered occurred: VM memory should be bigger or equal to at least 2Gi. Object will not be requeued",
 		},
+		{
+			name: "can create vm with less than 2 vCPUs and 2Gi of memory if the minimums are lowered",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Image:      &infrav1.Image{ID: ptr.To("fake-image-id")},
+				SKU:        invalidMemSKU,
+				MinimumVMResources: &infrav1.MinimumVMResources{
+					VCPUs:     ptr.To[int64](1),
+					MemoryGiB: ptr.To[int64](0),
+				},
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+			},
+			expectedError: "",
+		},
+		{
+			name: "cannot create vm if vCPU is less than the raised minimum",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Image:      &infrav1.Image{ID: ptr.To("fake-image-id")},
+				SKU:        validSKU,
+				MinimumVMResources: &infrav1.MinimumVMResources{
+					VCPUs: ptr.To[int64](64),
+				},
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "reconcile error that cannot be recovered occurred: VM size should be bigger or equal to at least 64 vCPUs. Object will not be requeued",
+		},
 		{
 			name: "can create a vm with a marketplace image using a plan",
 			spec: &VMSpec{
@@ -1028,6 +1089,108 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a vm with a shared data disk",
+			spec: &VMSpec{
+				Name:                "my-vm",
+				ResourceGroup:       "my-rg",
+				SubscriptionID:      "123",
+				ClusterName:         "my-cluster",
+				SharedDiskOwnerName: "md-0",
+				Role:                infrav1.Node,
+				NICIDs:              []string{"my-nic"},
+				SSHKeyData:          "fakesshpublickey",
+				Size:                "Standard_D2v3",
+				Location:            "test-location",
+				Image:               &infrav1.Image{ID: ptr.To("fake-image-id")},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix:  "shared",
+						DiskSizeGB:  256,
+						Lun:         ptr.To[int32](0),
+						CachingType: "None",
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Premium_ZRS",
+							MaxShares:          ptr.To[int32](2),
+							DiskEncryptionSet: &infrav1.DiskEncryptionSetParameters{
+								ID: "my_id",
+							},
+						},
+					},
+				},
+				SKU: validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				expectedDataDisks := &[]compute.DataDisk{
+					{
+						Lun:          ptr.To[int32](0),
+						Name:         ptr.To("my-cluster_md-0_shared"),
+						CreateOption: "Attach",
+						Caching:      "None",
+						ManagedDisk: &compute.ManagedDiskParameters{
+							ID:                 ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-cluster_md-0_shared"),
+							StorageAccountType: "Premium_ZRS",
+						},
+					},
+				}
+				g.Expect(gomockinternal.DiffEq(expectedDataDisks).Matches(result.(compute.VirtualMachine).StorageProfile.DataDisks)).To(BeTrue(), cmp.Diff(expectedDataDisks, result.(compute.VirtualMachine).StorageProfile.DataDisks))
+			},
+			expectedError: "",
+		},
+		{
+			name: "can create a vm with an ultra data disk with provisioned IOPS and throughput",
+			spec: &VMSpec{
+				Name:           "my-vm",
+				ResourceGroup:  "my-rg",
+				SubscriptionID: "123",
+				ClusterName:    "my-cluster",
+				Role:           infrav1.Node,
+				NICIDs:         []string{"my-nic"},
+				SSHKeyData:     "fakesshpublickey",
+				Size:           "Standard_D2v3",
+				Location:       "test-location",
+				Zone:           "1",
+				Image:          &infrav1.Image{ID: ptr.To("fake-image-id")},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix:  "etcddisk",
+						DiskSizeGB:  256,
+						Lun:         ptr.To[int32](0),
+						CachingType: "None",
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "UltraSSD_LRS",
+							DiskIOPSReadWrite:  ptr.To[int64](20000),
+							DiskMBpsReadWrite:  ptr.To[int64](500),
+							DiskEncryptionSet: &infrav1.DiskEncryptionSetParameters{
+								ID: "my_id",
+							},
+						},
+					},
+				},
+				SKU: validSKUWithUltraSSD,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				expectedDataDisks := &[]compute.DataDisk{
+					{
+						Lun:          ptr.To[int32](0),
+						Name:         ptr.To("my-vm_etcddisk"),
+						CreateOption: "Attach",
+						Caching:      "None",
+						ManagedDisk: &compute.ManagedDiskParameters{
+							ID:                 ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-vm_etcddisk"),
+							StorageAccountType: "UltraSSD_LRS",
+						},
+					},
+				}
+				g.Expect(gomockinternal.DiffEq(expectedDataDisks).Matches(result.(compute.VirtualMachine).StorageProfile.DataDisks)).To(BeTrue(), cmp.Diff(expectedDataDisks, result.(compute.VirtualMachine).StorageProfile.DataDisks))
+				g.Expect(result.(compute.VirtualMachine).AdditionalCapabilities.UltraSSDEnabled).To(Equal(ptr.To(true)))
+			},
+			expectedError: "",
+		},
 		{
 			name: "creating vm with ultra disk enabled in unsupported location fails",
 			spec: &VMSpec{
@@ -1235,6 +1398,74 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "creates a vm with a custom admin username",
+			spec: &VMSpec{
+				Name:          "my-vm",
+				Role:          infrav1.Node,
+				NICIDs:        []string{"my-nic"},
+				SSHKeyData:    "fakesshpublickey",
+				AdminUsername: "ops_admin",
+				Size:          "Standard_D2v3",
+				Location:      "test-location",
+				Zone:          "1",
+				Image:         &infrav1.Image{ID: ptr.To("fake-image-id")},
+				SKU:           validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				osProfile := result.(compute.VirtualMachine).OsProfile
+				g.Expect(osProfile.AdminUsername).To(Equal(ptr.To("ops_admin")))
+				g.Expect(*osProfile.LinuxConfiguration.SSH.PublicKeys).To(HaveLen(1))
+				g.Expect((*osProfile.LinuxConfiguration.SSH.PublicKeys)[0].Path).To(Equal(ptr.To("/home/ops_admin/.ssh/authorized_keys")))
+			},
+			expectedError: "",
+		},
+		{
+			name: "creates a vm with additional ssh public keys",
+			spec: &VMSpec{
+				Name:                 "my-vm",
+				Role:                 infrav1.Node,
+				NICIDs:               []string{"my-nic"},
+				SSHKeyData:           "fakesshpublickey",
+				AdditionalSSHKeyData: []string{"dGVhbWtleQ=="},
+				Size:                 "Standard_D2v3",
+				Location:             "test-location",
+				Zone:                 "1",
+				Image:                &infrav1.Image{ID: ptr.To("fake-image-id")},
+				SKU:                  validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				publicKeys := *result.(compute.VirtualMachine).OsProfile.LinuxConfiguration.SSH.PublicKeys
+				g.Expect(publicKeys).To(HaveLen(2))
+				g.Expect(publicKeys[1].Path).To(Equal(ptr.To("/home/capi/.ssh/authorized_keys")))
+				g.Expect(publicKeys[1].KeyData).To(Equal(ptr.To("teamkey")))
+			},
+			expectedError: "",
+		},
+		{
+			name: "fails to create a vm with an additional ssh public key that is not base64 encoded",
+			spec: &VMSpec{
+				Name:                 "my-vm",
+				Role:                 infrav1.Node,
+				NICIDs:               []string{"my-nic"},
+				SSHKeyData:           "fakesshpublickey",
+				AdditionalSSHKeyData: []string{"not base64"},
+				Size:                 "Standard_D2v3",
+				Location:             "test-location",
+				Zone:                 "1",
+				Image:                &infrav1.Image{ID: ptr.To("fake-image-id")},
+				SKU:                  validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "failed to generate OS Profile: failed to decode additional ssh public key: illegal base64 data at input byte 3",
+		},
 		{
 			name: "creates a vm with Diagnostics disabled",
 			spec: &VMSpec{
@@ -1363,3 +1594,217 @@ func TestParameters(t *testing.T) {
 		})
 	}
 }
+
+func TestAdditionalCapabilitiesChanged(t *testing.T) {
+	testcases := []struct {
+		name     string
+		existing *compute.AdditionalCapabilities
+		desired  *compute.AdditionalCapabilities
+		want     bool
+	}{
+		{
+			name: "no desired capabilities",
+			existing: &compute.AdditionalCapabilities{
+				UltraSSDEnabled: ptr.To(true),
+			},
+			want: false,
+		},
+		{
+			name:    "capability enabled on a VM without capabilities",
+			desired: &compute.AdditionalCapabilities{UltraSSDEnabled: ptr.To(true)},
+			want:    true,
+		},
+		{
+			name:     "capability disabled on a VM without capabilities",
+			desired:  &compute.AdditionalCapabilities{HibernationEnabled: ptr.To(false)},
+			existing: nil,
+			want:     false,
+		},
+		{
+			name:     "unchanged capabilities",
+			existing: &compute.AdditionalCapabilities{UltraSSDEnabled: ptr.To(true), HibernationEnabled: ptr.To(true)},
+			desired:  &compute.AdditionalCapabilities{UltraSSDEnabled: ptr.To(true)},
+			want:     false,
+		},
+		{
+			name:     "hibernation changed",
+			existing: &compute.AdditionalCapabilities{UltraSSDEnabled: ptr.To(true), HibernationEnabled: ptr.To(false)},
+			desired:  &compute.AdditionalCapabilities{HibernationEnabled: ptr.To(true)},
+			want:     true,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			g.Expect(additionalCapabilitiesChanged(tc.existing, tc.desired)).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestValidateResize(t *testing.T) {
+	sku := func(capabilities ...string) resourceskus.SKU {
+		skuCapabilities := []compute.ResourceSkuCapabilities{
+			{
+				Name:  ptr.To(resourceskus.MaxNetworkInterfaces),
+				Value: ptr.To("2"),
+			},
+		}
+		for _, capability := range capabilities {
+			skuCapabilities = append(skuCapabilities, compute.ResourceSkuCapabilities{
+				Name:  ptr.To(capability),
+				Value: ptr.To(string(resourceskus.CapabilitySupported)),
+			})
+		}
+		return resourceskus.SKU{
+			Name: ptr.To("Standard_D4s_v3"),
+			LocationInfo: &[]compute.ResourceSkuLocationInfo{
+				{
+					Location: ptr.To("test-location"),
+					Zones:    &[]string{"1", "2"},
+				},
+			},
+			Capabilities: &skuCapabilities,
+		}
+	}
+	premiumOSDisk := infrav1.OSDisk{
+		ManagedDisk: &infrav1.ManagedDiskParameters{StorageAccountType: "Premium_LRS"},
+	}
+
+	testcases := []struct {
+		name                  string
+		spec                  VMSpec
+		acceleratedNetworking bool
+		expectedError         string
+	}{
+		{
+			name: "VM size supports the VM",
+			spec: VMSpec{
+				Size:     "Standard_D4s_v3",
+				Location: "test-location",
+				Zone:     "1",
+				OSDisk:   premiumOSDisk,
+				NICIDs:   []string{"nic-1", "nic-2"},
+				SKU:      sku(resourceskus.PremiumIO, resourceskus.AcceleratedNetworking),
+			},
+			acceleratedNetworking: true,
+		},
+		{
+			name: "VM size not offered in the zone of the VM",
+			spec: VMSpec{
+				Size:     "Standard_D4s_v3",
+				Location: "test-location",
+				Zone:     "3",
+				SKU:      sku(),
+			},
+			expectedError: "VM size Standard_D4s_v3 is not available in zone 3 of location test-location",
+		},
+		{
+			name: "VM size without premium storage",
+			spec: VMSpec{
+				Size:     "Standard_D4s_v3",
+				Location: "test-location",
+				OSDisk:   premiumOSDisk,
+				SKU:      sku(),
+			},
+			expectedError: "VM size Standard_D4s_v3 does not support premium storage",
+		},
+		{
+			name: "VM size without ephemeral OS",
+			spec: VMSpec{
+				Size:     "Standard_D4s_v3",
+				Location: "test-location",
+				OSDisk:   infrav1.OSDisk{DiffDiskSettings: &infrav1.DiffDiskSettings{Option: string(compute.DiffDiskOptionsLocal)}},
+				SKU:      sku(),
+			},
+			expectedError: "VM size Standard_D4s_v3 does not support ephemeral os",
+		},
+		{
+			name: "VM size without accelerated networking",
+			spec: VMSpec{
+				Size:     "Standard_D4s_v3",
+				Location: "test-location",
+				SKU:      sku(),
+			},
+			acceleratedNetworking: true,
+			expectedError:         "VM size Standard_D4s_v3 does not support accelerated networking",
+		},
+		{
+			name: "VM size with too few network interfaces",
+			spec: VMSpec{
+				Size:     "Standard_D4s_v3",
+				Location: "test-location",
+				NICIDs:   []string{"nic-1", "nic-2", "nic-3"},
+				SKU:      sku(),
+			},
+			expectedError: "VM size Standard_D4s_v3 supports fewer than 3 network interfaces",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			err := tc.spec.validateResize(tc.acceleratedNetworking)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func FuzzParametersRoundTrip(f *testing.F) {
+	f.Add("my-vm", "ssh-rsa AAAA", int32(128), "etcddisk", int32(256), int32(0), "Premium_LRS")
+	f.Add("vm", "", int32(30), "", int32(1), int32(63), "")
+	f.Fuzz(func(t *testing.T, name, sshKey string, osDiskSizeGB int32, dataDiskNameSuffix string, dataDiskSizeGB, lun int32, storageAccountType string) {
+		if storageAccountType == string(compute.StorageAccountTypesUltraSSDLRS) {
+			t.Skip("ultra disks depend on the location capabilities of the SKU")
+		}
+		g := NewWithT(t)
+		spec := &VMSpec{
+			Name:       name,
+			Role:       infrav1.Node,
+			NICIDs:     []string{"my-nic"},
+			SSHKeyData: base64.StdEncoding.EncodeToString([]byte(sshKey)),
+			Size:       "Standard_D2v3",
+			Image:      &infrav1.Image{ID: ptr.To("fake-image-id")},
+			Identity:   infrav1.VMIdentitySystemAssigned,
+			OSDisk: infrav1.OSDisk{
+				OSType:     "Linux",
+				DiskSizeGB: ptr.To(osDiskSizeGB),
+				ManagedDisk: &infrav1.ManagedDiskParameters{
+					StorageAccountType: storageAccountType,
+				},
+			},
+			DataDisks: []infrav1.DataDisk{
+				{
+					NameSuffix: dataDiskNameSuffix,
+					DiskSizeGB: dataDiskSizeGB,
+					Lun:        ptr.To(lun),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: storageAccountType,
+					},
+				},
+			},
+			SKU: validSKU,
+		}
+
+		result, err := spec.Parameters(context.TODO(), nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		vm, ok := result.(compute.VirtualMachine)
+		g.Expect(ok).To(BeTrue())
+		vm.Name = ptr.To(name)
+
+		got, err := converters.SDKToAzureMachineSpec(vm)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(got.VMSize).To(Equal(spec.Size))
+		g.Expect(got.Image).To(Equal(spec.Image))
+		g.Expect(got.Identity).To(Equal(spec.Identity))
+		g.Expect(got.OSDisk).To(Equal(spec.OSDisk))
+		g.Expect(got.DataDisks).To(Equal(spec.DataDisks))
+		g.Expect(got.SSHPublicKey).To(Equal(spec.SSHKeyData))
+	})
+}
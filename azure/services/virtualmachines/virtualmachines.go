@@ -18,7 +18,9 @@ package virtualmachines
 
 import (
 	"context"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
@@ -39,7 +41,21 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
-const serviceName = "virtualmachine"
+const (
+	serviceName = "virtualmachine"
+
+	powerStatePrefix       = "PowerState/"
+	powerStateDeallocated  = "deallocated"
+	powerStateDeallocating = "deallocating"
+	powerStateStarting     = "starting"
+	provisioningSucceeded  = "Succeeded"
+	capabilitiesRetryAfter = 15 * time.Second
+
+	// bootDiagnosticsSASExpirationMinutes is the lifetime of the SAS URI used to download the serial console log.
+	bootDiagnosticsSASExpirationMinutes = 5
+	// bootLogTailBytes is the maximum size of the tail of the serial console log reported on the AzureMachine.
+	bootLogTailBytes = 1000
+)
 
 // VMScope defines the scope interface for a virtual machines service.
 type VMScope interface {
@@ -51,6 +67,8 @@ type VMScope interface {
 	SetAddresses([]corev1.NodeAddress)
 	SetVMState(infrav1.ProvisioningState)
 	SetConditionFalse(clusterv1.ConditionType, string, clusterv1.ConditionSeverity, string)
+	Annotation(string) string
+	RemoveAnnotation(string)
 }
 
 // Service provides operations on Azure resources.
@@ -60,6 +78,7 @@ type Service struct {
 	interfacesGetter async.Getter
 	publicIPsGetter  async.Getter
 	identitiesGetter identities.Client
+	client           Client
 }
 
 // New creates a new service.
@@ -70,6 +89,7 @@ func New(scope VMScope) *Service {
 		interfacesGetter: networkinterfaces.NewClient(scope),
 		publicIPsGetter:  publicips.NewClient(scope),
 		identitiesGetter: identities.NewClient(scope),
+		client:           Client,
 		Reconciler:       async.New(scope, Client, Client),
 	}
 }
@@ -123,14 +143,292 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			return errors.Errorf("%T is not a valid VM spec", vmSpec)
 		}
 
+		s.reconcileBootLog(ctx, spec)
+
 		err = s.checkUserAssignedIdentities(ctx, spec.UserAssignedIdentities, infraVM.UserAssignedIdentities)
 		if err != nil {
 			return errors.Wrap(err, "failed to check user assigned identities")
 		}
+
+		if err := s.reconcileDeallocatedUpdates(ctx, spec, vm); err != nil {
+			return errors.Wrap(err, "failed to reconcile updates of deallocated VM")
+		}
+
+		if err := s.reconcileVMSize(ctx, spec, vm); err != nil {
+			return errors.Wrap(err, "failed to reconcile VM size")
+		}
+
+		if err := s.reconcileDataDisks(ctx, spec, vm); err != nil {
+			return errors.Wrap(err, "failed to reconcile VM data disks")
+		}
 	}
 	return err
 }
 
+// reconcileDeallocatedUpdates applies changes to the additional capabilities of an existing VM, and grows its OS disk.
+// Azure only accepts such changes on deallocated VMs, so a running VM is deallocated first if the spec allows it,
+// and started again once the changes are applied.
+func (s *Service) reconcileDeallocatedUpdates(ctx context.Context, spec *VMSpec, vm compute.VirtualMachine) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.reconcileDeallocatedUpdates")
+	defer done()
+
+	desired := spec.generateAdditionalCapabilities()
+	var existing *compute.AdditionalCapabilities
+	if vm.VirtualMachineProperties != nil {
+		existing = vm.AdditionalCapabilities
+	}
+	capabilitiesChanged := additionalCapabilitiesChanged(existing, desired)
+	osDiskGrown := spec.osDiskGrown(vm)
+	changed := capabilitiesChanged || osDiskGrown
+	deallocatedForUpdate := s.Scope.Annotation(azure.VMDeallocatedForUpdateAnnotation) != ""
+	if !changed && !deallocatedForUpdate {
+		return nil
+	}
+	osDiskName := spec.osDiskName(vm)
+
+	// The instance view is needed to know the power state of the VM.
+	vm, err := s.client.GetByID(ctx, azure.VMID(s.Scope.SubscriptionID(), spec.ResourceGroupName(), spec.ResourceName()))
+	if err != nil {
+		return errors.Wrap(err, "failed to get VM instance view")
+	}
+	if state := ptr.Deref(vm.ProvisioningState, ""); state != provisioningSucceeded {
+		return azure.WithTransientError(errors.Errorf("VM is in provisioning state %s", state), capabilitiesRetryAfter)
+	}
+	powerState := getPowerState(vm)
+	if powerState == powerStateDeallocating || powerState == powerStateStarting {
+		return azure.WithTransientError(errors.Errorf("VM is in power state %s", powerState), capabilitiesRetryAfter)
+	}
+
+	switch {
+	case changed && powerState == powerStateDeallocated:
+		if capabilitiesChanged {
+			log.V(2).Info("updating VM additional capabilities")
+			isDone, err := s.client.UpdateAdditionalCapabilities(ctx, spec, desired)
+			if err != nil {
+				return errors.Wrap(err, "failed to update VM additional capabilities")
+			}
+			if !isDone {
+				return azure.WithTransientError(errors.New("VM additional capabilities update in progress"), capabilitiesRetryAfter)
+			}
+		}
+		if osDiskGrown {
+			log.V(2).Info("resizing VM OS disk", "disk", osDiskName, "diskSizeGB", *spec.OSDisk.DiskSizeGB)
+			isDone, err := s.client.ResizeDisk(ctx, spec.ResourceGroupName(), osDiskName, *spec.OSDisk.DiskSizeGB)
+			if err != nil {
+				return errors.Wrap(err, "failed to resize VM OS disk")
+			}
+			if !isDone {
+				return azure.WithTransientError(errors.New("VM OS disk resize in progress"), capabilitiesRetryAfter)
+			}
+		}
+		if deallocatedForUpdate {
+			return s.startDeallocatedVM(ctx, spec)
+		}
+		return nil
+	case changed && !osDiskGrown && !spec.deallocateOnChange():
+		log.Info("additional capabilities of the VM will be updated the next time it is deallocated", "powerState", powerState)
+		return nil
+	case changed:
+		// OS disks are only grown on machines that opted in to being deallocated for it.
+		reason := "update its additional capabilities"
+		if osDiskGrown {
+			reason = "resize its OS disk"
+		}
+		log.V(2).Info("deallocating VM to "+reason, "powerState", powerState)
+		s.Scope.SetAnnotation(azure.VMDeallocatedForUpdateAnnotation, "true")
+		isDone, err := s.client.Deallocate(ctx, spec)
+		if err != nil {
+			return errors.Wrap(err, "failed to deallocate VM")
+		}
+		if !isDone {
+			return azure.WithTransientError(errors.New("VM deallocation in progress"), capabilitiesRetryAfter)
+		}
+		// the changes are applied on the next reconciliation.
+		return azure.WithTransientError(errors.Errorf("VM deallocated to %s", reason), capabilitiesRetryAfter)
+	case powerState == powerStateDeallocated:
+		return s.startDeallocatedVM(ctx, spec)
+	default:
+		s.Scope.RemoveAnnotation(azure.VMDeallocatedForUpdateAnnotation)
+		return nil
+	}
+}
+
+// reconcileBootLog reports the tail of the serial console log of a VM whose bootstrap failed, so that the failure can be
+// triaged without access to the VM. The log is only retrieved once, and failing to retrieve it doesn't fail the reconciliation.
+func (s *Service) reconcileBootLog(ctx context.Context, spec *VMSpec) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.reconcileBootLog")
+	defer done()
+
+	if !spec.RetrieveBootLog {
+		return
+	}
+
+	serialLog, err := s.client.GetSerialConsoleLog(ctx, spec)
+	if err != nil {
+		log.Error(err, "failed to get the serial console log of the VM")
+		s.Scope.SetConditionFalse(infrav1.BootLogCondition, infrav1.BootLogUnavailableReason, clusterv1.ConditionSeverityWarning,
+			fmt.Sprintf("failed to get the serial console log of the VM, check that boot diagnostics are enabled: %s", err))
+		return
+	}
+	s.Scope.SetConditionFalse(infrav1.BootLogCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityWarning,
+		"VM bootstrapping failed, tail of the serial console log:\n"+logTail(serialLog, bootLogTailBytes))
+}
+
+// logTail returns the last lines of a log that fit in maxBytes.
+func logTail(log string, maxBytes int) string {
+	log = strings.TrimRight(log, "\r\n ")
+	if len(log) > maxBytes {
+		log = log[len(log)-maxBytes:]
+		if i := strings.IndexByte(log, '\n'); i >= 0 {
+			log = log[i+1:]
+		}
+	}
+	return strings.ToValidUTF8(log, "")
+}
+
+// reconcileVMSize resizes an existing VM to the size of its spec if the machine opted in to it.
+// A size that can't host the VM isn't a terminal error, as the VM keeps running with its current size until the spec is fixed.
+func (s *Service) reconcileVMSize(ctx context.Context, spec *VMSpec, vm compute.VirtualMachine) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.reconcileVMSize")
+	defer done()
+
+	if !spec.vmSizeChanged(vm) {
+		return nil
+	}
+
+	acceleratedNetworking, err := s.hasAcceleratedNetworking(ctx, vm, spec.ResourceGroupName())
+	if err != nil {
+		return errors.Wrap(err, "failed to get VM network interfaces")
+	}
+	if err := spec.validateResize(acceleratedNetworking); err != nil {
+		return errors.Wrapf(err, "failed to resize VM from %s to %s", vm.HardwareProfile.VMSize, spec.Size)
+	}
+
+	log.V(2).Info("resizing VM", "from", vm.HardwareProfile.VMSize, "to", spec.Size)
+	isDone, err := s.client.Resize(ctx, spec, spec.Size)
+	if err != nil {
+		return errors.Wrap(err, "failed to resize VM")
+	}
+	if !isDone {
+		return azure.WithTransientError(errors.New("VM resize in progress"), capabilitiesRetryAfter)
+	}
+	return nil
+}
+
+// reconcileDataDisks attaches the data disks added to the spec to an existing VM and detaches the removed ones if the
+// machine opted in to it. Detached disks that were created for the machine are deleted, the others are kept.
+func (s *Service) reconcileDataDisks(ctx context.Context, spec *VMSpec, vm compute.VirtualMachine) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.reconcileDataDisks")
+	defer done()
+
+	if !spec.UpdateDataDisks {
+		return nil
+	}
+
+	dataDisks, detached, changed, err := spec.dataDisksChanged(vm)
+	if err != nil {
+		return err
+	}
+	if changed {
+		log.V(2).Info("updating VM data disks", "dataDisks", len(dataDisks), "detached", len(detached))
+		isDone, err := s.client.UpdateDataDisks(ctx, spec, dataDisks)
+		if err != nil {
+			return errors.Wrap(err, "failed to update VM data disks")
+		}
+		if !isDone {
+			return azure.WithTransientError(errors.New("VM data disks update in progress"), capabilitiesRetryAfter)
+		}
+	}
+
+	return s.deleteDetachedDataDisks(ctx, spec)
+}
+
+// deleteDetachedDataDisks deletes the disks created for the VM that were detached from it. The disks are looked up in
+// the resource group on every reconciliation rather than taken from the VM, so that a deletion that failed or a detach
+// that was still in progress is retried until the disks are gone.
+func (s *Service) deleteDetachedDataDisks(ctx context.Context, spec *VMSpec) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.deleteDetachedDataDisks")
+	defer done()
+
+	disks, err := s.client.ListDisks(ctx, spec.ResourceGroupName())
+	if err != nil {
+		return errors.Wrap(err, "failed to list disks")
+	}
+	detached, err := spec.detachedDataDisks(disks)
+	if err != nil {
+		return err
+	}
+
+	inProgress := false
+	for _, diskName := range detached {
+		log.V(2).Info("deleting detached data disk", "disk", diskName)
+		isDone, err := s.client.DeleteDisk(ctx, spec.ResourceGroupName(), diskName)
+		if err != nil && !azure.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to delete detached data disk %s", diskName)
+		}
+		if err == nil && !isDone {
+			inProgress = true
+		}
+	}
+	if inProgress {
+		return azure.WithTransientError(errors.New("detached data disks deletion in progress"), capabilitiesRetryAfter)
+	}
+	return nil
+}
+
+// hasAcceleratedNetworking returns true if any network interface of the VM has accelerated networking enabled.
+func (s *Service) hasAcceleratedNetworking(ctx context.Context, vm compute.VirtualMachine, rgName string) (bool, error) {
+	if vm.NetworkProfile == nil || vm.NetworkProfile.NetworkInterfaces == nil {
+		return false, nil
+	}
+	for _, nicRef := range *vm.NetworkProfile.NetworkInterfaces {
+		if nicRef.ID == nil {
+			continue
+		}
+		existingNic, err := s.interfacesGetter.Get(ctx, &networkinterfaces.NICSpec{
+			Name:          getResourceNameByID(*nicRef.ID),
+			ResourceGroup: getResourceGroupByID(*nicRef.ID, rgName),
+		})
+		if err != nil {
+			return false, err
+		}
+		nic, ok := existingNic.(network.Interface)
+		if !ok {
+			return false, errors.Errorf("%T is not a network.Interface", existingNic)
+		}
+		if nic.InterfacePropertiesFormat != nil && ptr.Deref(nic.EnableAcceleratedNetworking, false) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// startDeallocatedVM starts a VM that was deallocated to update its additional capabilities.
+func (s *Service) startDeallocatedVM(ctx context.Context, spec *VMSpec) error {
+	isDone, err := s.client.Start(ctx, spec)
+	if err != nil {
+		return errors.Wrap(err, "failed to start VM")
+	}
+	if !isDone {
+		return azure.WithTransientError(errors.New("VM start in progress"), capabilitiesRetryAfter)
+	}
+	s.Scope.RemoveAnnotation(azure.VMDeallocatedForUpdateAnnotation)
+	return nil
+}
+
+// getPowerState returns the power state of a VM from its instance view, e.g. running or deallocated.
+func getPowerState(vm compute.VirtualMachine) string {
+	if vm.VirtualMachineProperties == nil || vm.InstanceView == nil || vm.InstanceView.Statuses == nil {
+		return ""
+	}
+	for _, status := range *vm.InstanceView.Statuses {
+		if code := ptr.Deref(status.Code, ""); strings.HasPrefix(code, powerStatePrefix) {
+			return strings.TrimPrefix(code, powerStatePrefix)
+		}
+	}
+	return ""
+}
+
 // Delete deletes the virtual machine with the provided name.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.Delete")
@@ -205,11 +503,13 @@ func (s *Service) getAddresses(ctx context.Context, vm compute.VirtualMachine, r
 			continue
 		}
 		nicName := getResourceNameByID(ptr.Deref(nicRef.ID, ""))
+		// Existing network interfaces attached to the VM may be in another resource group.
+		nicRGName := getResourceGroupByID(ptr.Deref(nicRef.ID, ""), rgName)
 
 		// Fetch nic and append its addresses
 		existingNic, err := s.interfacesGetter.Get(ctx, &networkinterfaces.NICSpec{
 			Name:          nicName,
-			ResourceGroup: rgName,
+			ResourceGroup: nicRGName,
 		})
 		if err != nil {
 			return addresses, err
@@ -239,7 +539,8 @@ func (s *Service) getAddresses(ctx context.Context, vm compute.VirtualMachine, r
 			// ID is the only field populated in PublicIPAddress sub-resource.
 			// Thus, we have to go fetch the publicIP with the name.
 			publicIPName := getResourceNameByID(ptr.Deref(ipConfig.PublicIPAddress.ID, ""))
-			publicNodeAddress, err := s.getPublicIPAddress(ctx, publicIPName, rgName)
+			publicIPRGName := getResourceGroupByID(ptr.Deref(ipConfig.PublicIPAddress.ID, ""), rgName)
+			publicNodeAddress, err := s.getPublicIPAddress(ctx, publicIPName, publicIPRGName)
 			if err != nil {
 				return addresses, err
 			}
@@ -284,6 +585,16 @@ func getResourceNameByID(resourceID string) string {
 	return resourceName
 }
 
+// getResourceGroupByID returns the resource group of a resource ID, or the given default resource group if the
+// resource ID can't be parsed.
+func getResourceGroupByID(resourceID string, defaultResourceGroup string) string {
+	parsed, err := azureutil.ParseResourceID(resourceID)
+	if err != nil || parsed.ResourceGroupName == "" {
+		return defaultResourceGroup
+	}
+	return parsed.ResourceGroupName
+}
+
 // IsManaged returns always returns true as CAPZ does not support BYO VM.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	return true, nil
@@ -30,10 +30,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/identities/mock_identities"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines/mock_virtualmachines"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -63,7 +65,7 @@ var (
 			NetworkProfile: &compute.NetworkProfile{
 				NetworkInterfaces: &[]compute.NetworkInterfaceReference{
 					{
-						ID: ptr.To("/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.Network/networkInterfaces/nic-1"),
+						ID: ptr.To("/subscriptions/123/resourceGroups/test-group/providers/Microsoft.Network/networkInterfaces/nic-1"),
 					},
 				},
 			},
@@ -80,7 +82,7 @@ var (
 					InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
 						PrivateIPAddress: ptr.To("10.0.0.5"),
 						PublicIPAddress: &network.PublicIPAddress{
-							ID: ptr.To("/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.Network/publicIPAddresses/pip-1"),
+							ID: ptr.To("/subscriptions/123/resourceGroups/test-group/providers/Microsoft.Network/publicIPAddresses/pip-1"),
 						},
 					},
 				},
@@ -146,6 +148,7 @@ func TestReconcileVM(t *testing.T) {
 				mpip.Get(gomockinternal.AContext(), &fakePublicIPSpec).Return(fakePublicIPs, nil)
 				s.SetAddresses(fakeNodeAddresses)
 				s.SetVMState(infrav1.Succeeded)
+				s.Annotation(azure.VMDeallocatedForUpdateAnnotation).Return("")
 			},
 		},
 		{
@@ -220,6 +223,581 @@ func TestReconcileVM(t *testing.T) {
 	}
 }
 
+func TestReconcileDeallocatedUpdates(t *testing.T) {
+	ultraSSDSpec := func(deallocateOnChange bool) *VMSpec {
+		spec := fakeVMSpec
+		spec.AdditionalCapabilities = &infrav1.AdditionalCapabilities{
+			UltraSSDEnabled:    ptr.To(true),
+			DeallocateOnChange: deallocateOnChange,
+		}
+		return &spec
+	}
+	vmWithPowerState := func(powerState string, ultraSSDEnabled bool) compute.VirtualMachine {
+		return compute.VirtualMachine{
+			VirtualMachineProperties: &compute.VirtualMachineProperties{
+				ProvisioningState:      ptr.To("Succeeded"),
+				AdditionalCapabilities: &compute.AdditionalCapabilities{UltraSSDEnabled: ptr.To(ultraSSDEnabled)},
+				InstanceView: &compute.VirtualMachineInstanceView{
+					Statuses: &[]compute.InstanceViewStatus{
+						{Code: ptr.To("ProvisioningState/succeeded")},
+						{Code: ptr.To("PowerState/" + powerState)},
+					},
+				},
+			},
+		}
+	}
+	osDiskSpec := func(resizeOSDisk bool) *VMSpec {
+		spec := fakeVMSpec
+		spec.OSDisk.DiskSizeGB = ptr.To[int32](128)
+		spec.ResizeOSDisk = resizeOSDisk
+		return &spec
+	}
+	vmWithOSDisk := func(powerState string, diskSizeGB int32) compute.VirtualMachine {
+		vm := vmWithPowerState(powerState, false)
+		vm.StorageProfile = &compute.StorageProfile{
+			OsDisk: &compute.OSDisk{Name: ptr.To("test-vm_OSDisk"), DiskSizeGB: ptr.To(diskSizeGB)},
+		}
+		return vm
+	}
+	vmID := azure.VMID("123", "test-group", "test-vm")
+
+	testcases := []struct {
+		name          string
+		spec          *VMSpec
+		vm            compute.VirtualMachine
+		expectedError string
+		expect        func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder)
+	}{
+		{
+			name: "noop if the capabilities are up to date",
+			spec: ultraSSDSpec(false),
+			vm:   vmWithPowerState("running", true),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.Annotation(azure.VMDeallocatedForUpdateAnnotation).Return("")
+			},
+		},
+		{
+			name: "running VM is left as is if it may not be deallocated",
+			spec: ultraSSDSpec(false),
+			vm:   vmWithPowerState("running", false),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.Annotation(azure.VMDeallocatedForUpdateAnnotation).Return("")
+				s.SubscriptionID().Return("123")
+				m.GetByID(gomockinternal.AContext(), vmID).Return(vmWithPowerState("running", false), nil)
+			},
+		},
+		{
+			name:          "running VM is deallocated if allowed",
+			spec:          ultraSSDSpec(true),
+			vm:            vmWithPowerState("running", false),
+			expectedError: "VM deallocated to update its additional capabilities. Object will be requeued after 15s",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.Annotation(azure.VMDeallocatedForUpdateAnnotation).Return("")
+				s.SubscriptionID().Return("123")
+				m.GetByID(gomockinternal.AContext(), vmID).Return(vmWithPowerState("running", false), nil)
+				s.SetAnnotation(azure.VMDeallocatedForUpdateAnnotation, "true")
+				m.Deallocate(gomockinternal.AContext(), ultraSSDSpec(true)).Return(true, nil)
+			},
+		},
+		{
+			name:          "waits for the VM to be deallocated",
+			spec:          ultraSSDSpec(true),
+			vm:            vmWithPowerState("running", false),
+			expectedError: "VM is in power state deallocating. Object will be requeued after 15s",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.Annotation(azure.VMDeallocatedForUpdateAnnotation).Return("true")
+				s.SubscriptionID().Return("123")
+				m.GetByID(gomockinternal.AContext(), vmID).Return(vmWithPowerState("deallocating", false), nil)
+			},
+		},
+		{
+			name: "deallocated VM is updated and started again",
+			spec: ultraSSDSpec(true),
+			vm:   vmWithPowerState("deallocated", false),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.Annotation(azure.VMDeallocatedForUpdateAnnotation).Return("true")
+				s.SubscriptionID().Return("123")
+				m.GetByID(gomockinternal.AContext(), vmID).Return(vmWithPowerState("deallocated", false), nil)
+				m.UpdateAdditionalCapabilities(gomockinternal.AContext(), ultraSSDSpec(true), &compute.AdditionalCapabilities{UltraSSDEnabled: ptr.To(true)}).Return(true, nil)
+				m.Start(gomockinternal.AContext(), ultraSSDSpec(true)).Return(true, nil)
+				s.RemoveAnnotation(azure.VMDeallocatedForUpdateAnnotation)
+			},
+		},
+		{
+			name: "VM deallocated by the user is updated but not started",
+			spec: ultraSSDSpec(false),
+			vm:   vmWithPowerState("deallocated", false),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.Annotation(azure.VMDeallocatedForUpdateAnnotation).Return("")
+				s.SubscriptionID().Return("123")
+				m.GetByID(gomockinternal.AContext(), vmID).Return(vmWithPowerState("deallocated", false), nil)
+				m.UpdateAdditionalCapabilities(gomockinternal.AContext(), ultraSSDSpec(false), &compute.AdditionalCapabilities{UltraSSDEnabled: ptr.To(true)}).Return(true, nil)
+			},
+		},
+		{
+			name:          "update in progress",
+			spec:          ultraSSDSpec(false),
+			vm:            vmWithPowerState("deallocated", false),
+			expectedError: "VM additional capabilities update in progress. Object will be requeued after 15s",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.Annotation(azure.VMDeallocatedForUpdateAnnotation).Return("")
+				s.SubscriptionID().Return("123")
+				m.GetByID(gomockinternal.AContext(), vmID).Return(vmWithPowerState("deallocated", false), nil)
+				m.UpdateAdditionalCapabilities(gomockinternal.AContext(), ultraSSDSpec(false), &compute.AdditionalCapabilities{UltraSSDEnabled: ptr.To(true)}).Return(false, nil)
+			},
+		},
+		{
+			name: "VM deallocated for an update is started once up to date",
+			spec: ultraSSDSpec(true),
+			vm:   vmWithPowerState("deallocated", true),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.Annotation(azure.VMDeallocatedForUpdateAnnotation).Return("true")
+				s.SubscriptionID().Return("123")
+				m.GetByID(gomockinternal.AContext(), vmID).Return(vmWithPowerState("deallocated", true), nil)
+				m.Start(gomockinternal.AContext(), ultraSSDSpec(true)).Return(true, nil)
+				s.RemoveAnnotation(azure.VMDeallocatedForUpdateAnnotation)
+			},
+		},
+		{
+			name: "larger OS disk is ignored without the resize annotation",
+			spec: osDiskSpec(false),
+			vm:   vmWithOSDisk("running", 30),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.Annotation(azure.VMDeallocatedForUpdateAnnotation).Return("")
+			},
+		},
+		{
+			name:          "running VM is deallocated to resize its OS disk",
+			spec:          osDiskSpec(true),
+			vm:            vmWithOSDisk("running", 30),
+			expectedError: "VM deallocated to resize its OS disk. Object will be requeued after 15s",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.Annotation(azure.VMDeallocatedForUpdateAnnotation).Return("")
+				s.SubscriptionID().Return("123")
+				m.GetByID(gomockinternal.AContext(), vmID).Return(vmWithOSDisk("running", 30), nil)
+				s.SetAnnotation(azure.VMDeallocatedForUpdateAnnotation, "true")
+				m.Deallocate(gomockinternal.AContext(), osDiskSpec(true)).Return(true, nil)
+			},
+		},
+		{
+			name: "deallocated VM has its OS disk resized and is started again",
+			spec: osDiskSpec(true),
+			vm:   vmWithOSDisk("deallocated", 30),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.Annotation(azure.VMDeallocatedForUpdateAnnotation).Return("true")
+				s.SubscriptionID().Return("123")
+				m.GetByID(gomockinternal.AContext(), vmID).Return(vmWithOSDisk("deallocated", 30), nil)
+				m.ResizeDisk(gomockinternal.AContext(), "test-group", "test-vm_OSDisk", int32(128)).Return(true, nil)
+				m.Start(gomockinternal.AContext(), osDiskSpec(true)).Return(true, nil)
+				s.RemoveAnnotation(azure.VMDeallocatedForUpdateAnnotation)
+			},
+		},
+		{
+			name:          "OS disk resize in progress",
+			spec:          osDiskSpec(true),
+			vm:            vmWithOSDisk("deallocated", 30),
+			expectedError: "VM OS disk resize in progress. Object will be requeued after 15s",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.Annotation(azure.VMDeallocatedForUpdateAnnotation).Return("true")
+				s.SubscriptionID().Return("123")
+				m.GetByID(gomockinternal.AContext(), vmID).Return(vmWithOSDisk("deallocated", 30), nil)
+				m.ResizeDisk(gomockinternal.AContext(), "test-group", "test-vm_OSDisk", int32(128)).Return(false, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_virtualmachines.NewMockVMScope(mockCtrl)
+			clientMock := mock_virtualmachines.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.reconcileDeallocatedUpdates(context.TODO(), tc.spec, tc.vm)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestReconcileVMSize(t *testing.T) {
+	resizeSpec := func(resizeVM bool, capabilities ...string) *VMSpec {
+		skuCapabilities := []compute.ResourceSkuCapabilities{}
+		for _, capability := range capabilities {
+			skuCapabilities = append(skuCapabilities, compute.ResourceSkuCapabilities{
+				Name:  ptr.To(capability),
+				Value: ptr.To(string(resourceskus.CapabilitySupported)),
+			})
+		}
+		spec := fakeVMSpec
+		spec.Size = "Standard_D4s_v3"
+		spec.NICIDs = []string{"nic-id-1"}
+		spec.ResizeVM = resizeVM
+		spec.SKU = resourceskus.SKU{Name: ptr.To("Standard_D4s_v3"), Capabilities: &skuCapabilities}
+		return &spec
+	}
+	vmWithSize := func(size string) compute.VirtualMachine {
+		vm := fakeExistingVM
+		properties := *vm.VirtualMachineProperties
+		properties.HardwareProfile = &compute.HardwareProfile{VMSize: compute.VirtualMachineSizeTypes(size)}
+		vm.VirtualMachineProperties = &properties
+		return vm
+	}
+	nicWithAcceleratedNetworking := network.Interface{
+		InterfacePropertiesFormat: &network.InterfacePropertiesFormat{EnableAcceleratedNetworking: ptr.To(true)},
+	}
+
+	testcases := []struct {
+		name          string
+		spec          *VMSpec
+		vm            compute.VirtualMachine
+		expectedError string
+		expect        func(mnic *mock_async.MockGetterMockRecorder, m *mock_virtualmachines.MockClientMockRecorder)
+	}{
+		{
+			name:   "noop without the resize annotation",
+			spec:   resizeSpec(false),
+			vm:     vmWithSize("Standard_D2s_v3"),
+			expect: func(mnic *mock_async.MockGetterMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {},
+		},
+		{
+			name:   "noop if the VM size is up to date",
+			spec:   resizeSpec(true),
+			vm:     vmWithSize("standard_d4s_v3"),
+			expect: func(mnic *mock_async.MockGetterMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {},
+		},
+		{
+			name: "VM is resized",
+			spec: resizeSpec(true, resourceskus.AcceleratedNetworking),
+			vm:   vmWithSize("Standard_D2s_v3"),
+			expect: func(mnic *mock_async.MockGetterMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				mnic.Get(gomockinternal.AContext(), &fakeNetworkInterfaceGetterSpec).Return(nicWithAcceleratedNetworking, nil)
+				m.Resize(gomockinternal.AContext(), resizeSpec(true, resourceskus.AcceleratedNetworking), "Standard_D4s_v3").Return(true, nil)
+			},
+		},
+		{
+			name:          "VM resize in progress",
+			spec:          resizeSpec(true, resourceskus.AcceleratedNetworking),
+			vm:            vmWithSize("Standard_D2s_v3"),
+			expectedError: "VM resize in progress. Object will be requeued after 15s",
+			expect: func(mnic *mock_async.MockGetterMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				mnic.Get(gomockinternal.AContext(), &fakeNetworkInterfaceGetterSpec).Return(nicWithAcceleratedNetworking, nil)
+				m.Resize(gomockinternal.AContext(), resizeSpec(true, resourceskus.AcceleratedNetworking), "Standard_D4s_v3").Return(false, nil)
+			},
+		},
+		{
+			name:          "VM isn't resized to a size that doesn't support its network interfaces",
+			spec:          resizeSpec(true),
+			vm:            vmWithSize("Standard_D2s_v3"),
+			expectedError: "failed to resize VM from Standard_D2s_v3 to Standard_D4s_v3: VM size Standard_D4s_v3 does not support accelerated networking",
+			expect: func(mnic *mock_async.MockGetterMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				mnic.Get(gomockinternal.AContext(), &fakeNetworkInterfaceGetterSpec).Return(nicWithAcceleratedNetworking, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			interfaceMock := mock_async.NewMockGetter(mockCtrl)
+			clientMock := mock_virtualmachines.NewMockClient(mockCtrl)
+
+			tc.expect(interfaceMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				interfacesGetter: interfaceMock,
+				client:           clientMock,
+			}
+
+			err := s.reconcileVMSize(context.TODO(), tc.spec, tc.vm)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestReconcileDataDisks(t *testing.T) {
+	dataDisksSpec := func(updateDataDisks bool, dataDisks ...infrav1.DataDisk) *VMSpec {
+		spec := fakeVMSpec
+		spec.DataDisks = dataDisks
+		spec.UpdateDataDisks = updateDataDisks
+		return &spec
+	}
+	vmWithDataDisks := func(names ...string) compute.VirtualMachine {
+		vm := fakeExistingVM
+		properties := *vm.VirtualMachineProperties
+		dataDisks := make([]compute.DataDisk, len(names))
+		for i, name := range names {
+			dataDisks[i] = compute.DataDisk{Name: ptr.To(name), Lun: ptr.To(int32(i))}
+		}
+		properties.StorageProfile = &compute.StorageProfile{DataDisks: &dataDisks}
+		vm.VirtualMachineProperties = &properties
+		return vm
+	}
+	disk1 := infrav1.DataDisk{NameSuffix: "disk1", DiskSizeGB: 64, Lun: ptr.To[int32](0)}
+	disk2 := infrav1.DataDisk{NameSuffix: "disk2", DiskSizeGB: 128, Lun: ptr.To[int32](1)}
+	vmID := "/subscriptions/123/resourceGroups/test-group/providers/Microsoft.Compute/virtualMachines/test-vm"
+	attachedDisk := func(name string) compute.Disk {
+		return compute.Disk{Name: ptr.To(name), ManagedBy: ptr.To(vmID)}
+	}
+	unattachedDisk := func(name string) compute.Disk {
+		return compute.Disk{Name: ptr.To(name)}
+	}
+
+	testcases := []struct {
+		name          string
+		spec          *VMSpec
+		vm            compute.VirtualMachine
+		expectedError string
+		expect        func(m *mock_virtualmachines.MockClientMockRecorder)
+	}{
+		{
+			name:   "noop without the update-data-disks annotation",
+			spec:   dataDisksSpec(false, disk1, disk2),
+			vm:     vmWithDataDisks("test-vm_disk1"),
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {},
+		},
+		{
+			name: "noop if the data disks are up to date",
+			spec: dataDisksSpec(true, disk1, disk2),
+			vm:   vmWithDataDisks("test-vm_disk1", "test-vm_disk2"),
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.ListDisks(gomockinternal.AContext(), "test-group").Return([]compute.Disk{
+					attachedDisk("test-vm_OSDisk"), attachedDisk("test-vm_disk1"), attachedDisk("test-vm_disk2"), unattachedDisk("other-vm_disk1"),
+				}, nil)
+			},
+		},
+		{
+			name: "data disk is attached",
+			spec: dataDisksSpec(true, disk1, disk2),
+			vm:   vmWithDataDisks("test-vm_disk1"),
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.UpdateDataDisks(gomockinternal.AContext(), dataDisksSpec(true, disk1, disk2), []compute.DataDisk{
+					{Name: ptr.To("test-vm_disk1"), Lun: ptr.To[int32](0)},
+					{
+						Name:         ptr.To("test-vm_disk2"),
+						Lun:          ptr.To[int32](1),
+						CreateOption: compute.DiskCreateOptionTypesEmpty,
+						DiskSizeGB:   ptr.To[int32](128),
+					},
+				}).Return(true, nil)
+				m.ListDisks(gomockinternal.AContext(), "test-group").Return([]compute.Disk{attachedDisk("test-vm_disk1"), attachedDisk("test-vm_disk2")}, nil)
+			},
+		},
+		{
+			name:          "data disks update in progress",
+			spec:          dataDisksSpec(true, disk1, disk2),
+			vm:            vmWithDataDisks("test-vm_disk1"),
+			expectedError: "VM data disks update in progress. Object will be requeued after 15s",
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.UpdateDataDisks(gomockinternal.AContext(), dataDisksSpec(true, disk1, disk2), gomock.Any()).Return(false, nil)
+			},
+		},
+		{
+			name: "data disk created for the VM is detached and deleted",
+			spec: dataDisksSpec(true, disk1),
+			vm:   vmWithDataDisks("test-vm_disk1", "test-vm_disk2"),
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.UpdateDataDisks(gomockinternal.AContext(), dataDisksSpec(true, disk1), []compute.DataDisk{
+					{Name: ptr.To("test-vm_disk1"), Lun: ptr.To[int32](0)},
+				}).Return(true, nil)
+				m.ListDisks(gomockinternal.AContext(), "test-group").Return([]compute.Disk{attachedDisk("test-vm_disk1"), unattachedDisk("test-vm_disk2")}, nil)
+				m.DeleteDisk(gomockinternal.AContext(), "test-group", "test-vm_disk2").Return(true, nil)
+			},
+		},
+		{
+			name: "data disk still being detached isn't deleted",
+			spec: dataDisksSpec(true, disk1),
+			vm:   vmWithDataDisks("test-vm_disk1"),
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.ListDisks(gomockinternal.AContext(), "test-group").Return([]compute.Disk{attachedDisk("test-vm_disk1"), attachedDisk("test-vm_disk2")}, nil)
+			},
+		},
+		{
+			name: "data disk detached in a previous reconciliation is deleted",
+			spec: dataDisksSpec(true, disk1),
+			vm:   vmWithDataDisks("test-vm_disk1"),
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.ListDisks(gomockinternal.AContext(), "test-group").Return([]compute.Disk{attachedDisk("test-vm_disk1"), unattachedDisk("test-vm_disk2")}, nil)
+				m.DeleteDisk(gomockinternal.AContext(), "test-group", "test-vm_disk2").Return(true, nil)
+			},
+		},
+		{
+			name:          "failed deletion of a detached data disk is returned",
+			spec:          dataDisksSpec(true, disk1),
+			vm:            vmWithDataDisks("test-vm_disk1"),
+			expectedError: "failed to delete detached data disk test-vm_disk2: conflict",
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.ListDisks(gomockinternal.AContext(), "test-group").Return([]compute.Disk{unattachedDisk("test-vm_disk2")}, nil)
+				m.DeleteDisk(gomockinternal.AContext(), "test-group", "test-vm_disk2").Return(false, errors.New("conflict"))
+			},
+		},
+		{
+			name:          "deletion of a detached data disk in progress",
+			spec:          dataDisksSpec(true, disk1),
+			vm:            vmWithDataDisks("test-vm_disk1"),
+			expectedError: "detached data disks deletion in progress. Object will be requeued after 15s",
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.ListDisks(gomockinternal.AContext(), "test-group").Return([]compute.Disk{unattachedDisk("test-vm_disk2")}, nil)
+				m.DeleteDisk(gomockinternal.AContext(), "test-group", "test-vm_disk2").Return(false, nil)
+			},
+		},
+		{
+			name: "existing data disk is only detached",
+			spec: dataDisksSpec(true),
+			vm:   vmWithDataDisks("restored-disk"),
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.UpdateDataDisks(gomockinternal.AContext(), dataDisksSpec(true), []compute.DataDisk{}).Return(true, nil)
+				m.ListDisks(gomockinternal.AContext(), "test-group").Return([]compute.Disk{unattachedDisk("restored-disk")}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			clientMock := mock_virtualmachines.NewMockClient(mockCtrl)
+
+			tc.expect(clientMock.EXPECT())
+
+			s := &Service{
+				client: clientMock,
+			}
+
+			err := s.reconcileDataDisks(context.TODO(), tc.spec, tc.vm)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestReconcileBootLog(t *testing.T) {
+	bootLogSpec := func(retrieveBootLog bool) *VMSpec {
+		spec := fakeVMSpec
+		spec.RetrieveBootLog = retrieveBootLog
+		return &spec
+	}
+
+	testcases := []struct {
+		name   string
+		spec   *VMSpec
+		expect func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder)
+	}{
+		{
+			name: "noop if the bootstrap didn't fail",
+			spec: bootLogSpec(false),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+			},
+		},
+		{
+			name: "tail of the serial console log is reported",
+			spec: bootLogSpec(true),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				m.GetSerialConsoleLog(gomockinternal.AContext(), bootLogSpec(true)).Return("[  OK  ] Started cloud-init.\nkubeadm join failed\n", nil)
+				s.SetConditionFalse(infrav1.BootLogCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityWarning,
+					"VM bootstrapping failed, tail of the serial console log:\n[  OK  ] Started cloud-init.\nkubeadm join failed")
+			},
+		},
+		{
+			name: "failure to get the serial console log is reported",
+			spec: bootLogSpec(true),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				m.GetSerialConsoleLog(gomockinternal.AContext(), bootLogSpec(true)).Return("", errors.New("boot diagnostics have no serial console log"))
+				s.SetConditionFalse(infrav1.BootLogCondition, infrav1.BootLogUnavailableReason, clusterv1.ConditionSeverityWarning,
+					"failed to get the serial console log of the VM, check that boot diagnostics are enabled: boot diagnostics have no serial console log")
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_virtualmachines.NewMockVMScope(mockCtrl)
+			clientMock := mock_virtualmachines.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			s.reconcileBootLog(context.TODO(), tc.spec)
+		})
+	}
+}
+
+func TestLogTail(t *testing.T) {
+	testcases := []struct {
+		name     string
+		log      string
+		maxBytes int
+		expected string
+	}{
+		{
+			name:     "short log is kept as is",
+			log:      "line 1\nline 2\n",
+			maxBytes: 100,
+			expected: "line 1\nline 2",
+		},
+		{
+			name:     "long log is cut at a line boundary",
+			log:      "line 1\nline 2\nline 3\n",
+			maxBytes: 10,
+			expected: "line 3",
+		},
+		{
+			name:     "invalid UTF-8 is dropped",
+			log:      "line 1\n\xffline 2",
+			maxBytes: 100,
+			expected: "line 1\nline 2",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(logTail(tc.log, tc.maxBytes)).To(Equal(tc.expected))
+		})
+	}
+}
+
 func TestDeleteVM(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -34,6 +34,7 @@ type VNetSpec struct {
 	ExtendedLocation *infrav1.ExtendedLocationSpec
 	ClusterName      string
 	AdditionalTags   infrav1.Tags
+	DeletePolicy     infrav1.DeletePolicy
 }
 
 // ResourceName returns the name of the vnet.
@@ -144,6 +144,11 @@ func (s *Service) Delete(ctx context.Context) error {
 		return nil
 	}
 
+	if spec, ok := vnetSpec.(*VNetSpec); ok && spec.DeletePolicy == infrav1.DeletePolicyRetain {
+		log.Info("Skipping VNet deletion, delete policy is Retain")
+		return nil
+	}
+
 	err = s.DeleteResource(ctx, vnetSpec, serviceName)
 	s.Scope.UpdateDeleteStatus(infrav1.VNetReadyCondition, serviceName, err)
 	return err
@@ -44,6 +44,16 @@ var (
 		AdditionalTags: map[string]string{"foo": "bar"},
 	}
 
+	fakeRetainedVNetSpec = VNetSpec{
+		ResourceGroup:  "test-group",
+		Name:           "test-vnet",
+		CIDRs:          []string{"10.0.0.0/8"},
+		Location:       "test-location",
+		ClusterName:    "test-cluster",
+		AdditionalTags: map[string]string{"foo": "bar"},
+		DeletePolicy:   infrav1.DeletePolicyRetain,
+	}
+
 	managedTags = resources.TagsResource{
 		Properties: &resources.Tags{
 			Tags: map[string]*string{
@@ -207,6 +217,16 @@ func TestDeleteVnet(t *testing.T) {
 				s.UpdateDeleteStatus(infrav1.VNetReadyCondition, serviceName, nil)
 			},
 		},
+		{
+			name:          "vnet has Retain delete policy, do nothing",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VNetSpec().Times(2).Return(&fakeRetainedVNetSpec)
+				s.SubscriptionID().Return("123")
+				m.GetAtScope(gomockinternal.AContext(), azure.VNetID("123", fakeRetainedVNetSpec.ResourceGroupName(), fakeRetainedVNetSpec.Name)).Return(managedTags, nil)
+				s.ClusterName().Return("test-cluster")
+			},
+		},
 		{
 			name:          "delete vnet fails, should return an error",
 			expectedError: internalError.Error(),
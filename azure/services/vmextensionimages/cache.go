@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmextensionimages
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Cache lazily loads the versions of the VM extension images offered in a location, to validate the VM extensions of
+// machines before they are created. The cached versions are refreshed when the cache expires.
+type Cache struct {
+	client Client
+
+	// location is the Azure location for which this cache stores VM extension image versions.
+	location string
+
+	// mu synchronizes access to data, as the cache is shared across reconciles.
+	mu sync.Mutex
+	// data maps the key of a VM extension image, as returned by imageKey, to its versions.
+	data map[string][]string
+}
+
+// Cacher describes the ability to get and to add items to cache.
+type Cacher interface {
+	Get(key interface{}) (value interface{}, ok bool)
+	Add(key interface{}, value interface{}) bool
+}
+
+var (
+	doOnce      sync.Once
+	clientCache Cacher
+)
+
+// newCache instantiates a cache.
+func newCache(auth azure.Authorizer, location string) *Cache {
+	return &Cache{
+		client:   NewClient(auth),
+		location: location,
+		data:     map[string][]string{},
+	}
+}
+
+// GetCache either creates a new VM extension images cache or returns an existing one based on the location +
+// Authorizer HashKey().
+func GetCache(auth azure.Authorizer, location string) (*Cache, error) {
+	var err error
+	doOnce.Do(func() {
+		clientCache, err = ttllru.New(128, 24*time.Hour)
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating LRU cache for VM extension images cache")
+	}
+
+	key := location + "_" + auth.HashKey()
+	c, ok := clientCache.Get(key)
+	if ok {
+		return c.(*Cache), nil
+	}
+
+	c = newCache(auth, location)
+	_ = clientCache.Add(key, c)
+	return c.(*Cache), nil
+}
+
+// NewStaticCache initializes a cache with the versions of VM extension images, keyed by "<publisher>/<type>", and no
+// ability to refresh. Used for testing.
+func NewStaticCache(versions map[string][]string, location string) *Cache {
+	data := map[string][]string{}
+	for key, v := range versions {
+		data[strings.ToLower(key)] = v
+	}
+	return &Cache{
+		location: location,
+		data:     data,
+	}
+}
+
+// Validate returns a terminal error if the publisher doesn't offer the VM extension type in the version, which is a
+// major.minor type handler version, in the location of the cache.
+func (c *Cache) Validate(ctx context.Context, publisher, extensionType, version string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "vmextensionimages.Cache.Validate")
+	defer done()
+
+	versions, err := c.versions(ctx, publisher, extensionType)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		return azure.WithTerminalError(fmt.Errorf("VM extension type %s of publisher %s is not offered in location %s", extensionType, publisher, c.location))
+	}
+
+	for _, v := range versions {
+		if v == version || strings.HasPrefix(v, version+".") {
+			return nil
+		}
+	}
+	return azure.WithTerminalError(fmt.Errorf("version %s of VM extension type %s of publisher %s is not offered in location %s, offered versions are %s",
+		version, extensionType, publisher, c.location, strings.Join(handlerVersions(versions), ", ")))
+}
+
+// Versions returns the versions of the VM extension type that the publisher offers in the location of the cache.
+func (c *Cache) Versions(ctx context.Context, publisher, extensionType string) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "vmextensionimages.Cache.Versions")
+	defer done()
+
+	return c.versions(ctx, publisher, extensionType)
+}
+
+// versions returns the versions of the VM extension image, listing them if they aren't cached yet.
+func (c *Cache) versions(ctx context.Context, publisher, extensionType string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := imageKey(publisher, extensionType)
+	if versions, ok := c.data[key]; ok || c.client == nil {
+		return versions, nil
+	}
+
+	versions, err := c.client.ListVersions(ctx, c.location, publisher, extensionType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list versions of VM extension type %s of publisher %s", extensionType, publisher)
+	}
+	c.data[key] = versions
+	return versions, nil
+}
+
+// imageKey returns the key of a VM extension image in the cache.
+func imageKey(publisher, extensionType string) string {
+	return strings.ToLower(publisher + "/" + extensionType)
+}
+
+// handlerVersions returns the sorted major.minor type handler versions of the VM extension image versions.
+func handlerVersions(versions []string) []string {
+	seen := map[string]bool{}
+	var handlerVersions []string
+	for _, v := range versions {
+		parts := strings.SplitN(v, ".", 3)
+		if len(parts) > 2 {
+			v = parts[0] + "." + parts[1]
+		}
+		if !seen[v] {
+			seen[v] = true
+			handlerVersions = append(handlerVersions, v)
+		}
+	}
+	sort.Strings(handlerVersions)
+	return handlerVersions
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmextensionimages
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+type fakeClient struct {
+	versions map[string][]string
+	calls    int
+}
+
+func (f *fakeClient) ListVersions(_ context.Context, _, publisher, extensionType string) ([]string, error) {
+	f.calls++
+	return f.versions[imageKey(publisher, extensionType)], nil
+}
+
+func TestCacheValidate(t *testing.T) {
+	testcases := []struct {
+		name          string
+		publisher     string
+		extensionType string
+		version       string
+		expectedError string
+	}{
+		{
+			name:          "offered version",
+			publisher:     "Microsoft.Azure.Extensions",
+			extensionType: "CustomScript",
+			version:       "2.1",
+		},
+		{
+			name:          "offered version with different casing of the publisher and type",
+			publisher:     "microsoft.azure.extensions",
+			extensionType: "customscript",
+			version:       "2.0",
+		},
+		{
+			name:          "version that isn't offered",
+			publisher:     "Microsoft.Azure.Extensions",
+			extensionType: "CustomScript",
+			version:       "3.0",
+			expectedError: "reconcile error that cannot be recovered occurred: version 3.0 of VM extension type CustomScript of publisher Microsoft.Azure.Extensions is not offered in location test-location, offered versions are 2.0, 2.1. Object will not be requeued",
+		},
+		{
+			name:          "version that is a prefix of an offered version",
+			publisher:     "Microsoft.Azure.Extensions",
+			extensionType: "CustomScript",
+			version:       "2.",
+			expectedError: "reconcile error that cannot be recovered occurred: version 2. of VM extension type CustomScript of publisher Microsoft.Azure.Extensions is not offered in location test-location, offered versions are 2.0, 2.1. Object will not be requeued",
+		},
+		{
+			name:          "type that isn't offered",
+			publisher:     "Microsoft.Azure.Extensions",
+			extensionType: "DoesNotExist",
+			version:       "1.0",
+			expectedError: "reconcile error that cannot be recovered occurred: VM extension type DoesNotExist of publisher Microsoft.Azure.Extensions is not offered in location test-location. Object will not be requeued",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			c := NewStaticCache(map[string][]string{
+				"Microsoft.Azure.Extensions/CustomScript": {"2.0.7", "2.1.3", "2.1.10"},
+			}, "test-location")
+
+			err := c.Validate(context.TODO(), tc.publisher, tc.extensionType, tc.version)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestCacheVersions(t *testing.T) {
+	g := NewWithT(t)
+
+	c := NewStaticCache(map[string][]string{
+		"Microsoft.Azure.Extensions/CustomScript": {"2.0.7", "2.1.3"},
+	}, "test-location")
+
+	versions, err := c.Versions(context.TODO(), "microsoft.azure.extensions", "customscript")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(versions).To(Equal([]string{"2.0.7", "2.1.3"}))
+
+	versions, err = c.Versions(context.TODO(), "Microsoft.Azure.Extensions", "DoesNotExist")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(versions).To(BeEmpty())
+}
+
+func TestCacheListsVersionsOnce(t *testing.T) {
+	g := NewWithT(t)
+
+	client := &fakeClient{versions: map[string][]string{
+		imageKey("Microsoft.Azure.Extensions", "CustomScript"): {"2.1.3"},
+	}}
+	c := &Cache{client: client, location: "test-location", data: map[string][]string{}}
+
+	g.Expect(c.Validate(context.TODO(), "Microsoft.Azure.Extensions", "CustomScript", "2.1")).To(Succeed())
+	g.Expect(c.Validate(context.TODO(), "Microsoft.Azure.Extensions", "CustomScript", "2.0")).NotTo(Succeed())
+	g.Expect(c.Validate(context.TODO(), "Microsoft.Azure.Extensions", "Other", "1.0")).NotTo(Succeed())
+	g.Expect(c.Validate(context.TODO(), "Microsoft.Azure.Extensions", "Other", "1.0")).NotTo(Succeed())
+	g.Expect(client.calls).To(Equal(2))
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmextensionimages
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Client wraps go-sdk.
+type Client interface {
+	ListVersions(ctx context.Context, location, publisher, extensionType string) ([]string, error)
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	images compute.VirtualMachineExtensionImagesClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new VM extension images client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		images: newVirtualMachineExtensionImagesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newVirtualMachineExtensionImagesClient creates a new VM extension images client from subscription ID.
+func newVirtualMachineExtensionImagesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachineExtensionImagesClient {
+	c := compute.NewVirtualMachineExtensionImagesClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	return c
+}
+
+// ListVersions returns the versions of the VM extension image offered in the location.
+// It returns no versions if the publisher doesn't offer the extension type in the location.
+func (ac *AzureClient) ListVersions(ctx context.Context, location, publisher, extensionType string) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "vmextensionimages.AzureClient.ListVersions")
+	defer done()
+
+	images, err := ac.images.ListVersions(ctx, location, publisher, extensionType, "", nil, "")
+	if azure.ResourceNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list VM extension image versions")
+	}
+
+	var versions []string
+	for _, image := range ptr.Deref(images.Value, nil) {
+		versions = append(versions, ptr.Deref(image.Name, ""))
+	}
+	return versions, nil
+}
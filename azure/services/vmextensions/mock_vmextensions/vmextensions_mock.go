@@ -29,6 +29,7 @@ import (
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
 	v1beta10 "sigs.k8s.io/cluster-api/api/v1beta1"
+	conditions "sigs.k8s.io/cluster-api/util/conditions"
 )
 
 // MockVMExtensionScope is a mock of VMExtensionScope interface.
@@ -267,3 +268,17 @@ func (mr *MockVMExtensionScopeMockRecorder) VMExtensionSpecs() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMExtensionSpecs", reflect.TypeOf((*MockVMExtensionScope)(nil).VMExtensionSpecs))
 }
+
+// VMExtensionsStatusResource mocks base method.
+func (m *MockVMExtensionScope) VMExtensionsStatusResource() conditions.Setter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VMExtensionsStatusResource")
+	ret0, _ := ret[0].(conditions.Setter)
+	return ret0
+}
+
+// VMExtensionsStatusResource indicates an expected call of VMExtensionsStatusResource.
+func (mr *MockVMExtensionScopeMockRecorder) VMExtensionsStatusResource() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMExtensionsStatusResource", reflect.TypeOf((*MockVMExtensionScope)(nil).VMExtensionsStatusResource))
+}
@@ -50,23 +50,43 @@ func (s *VMExtensionSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the VM extension.
 func (s *VMExtensionSpec) Parameters(ctx context.Context, existing interface{}) (interface{}, error) {
 	if existing != nil {
-		_, ok := existing.(compute.VirtualMachineExtension)
+		extension, ok := existing.(compute.VirtualMachineExtension)
 		if !ok {
 			return nil, errors.Errorf("%T is not a compute.VirtualMachineExtension", existing)
 		}
 
-		// VM extension already exists, nothing to update.
-		return nil, nil
+		// Only the upgrade behavior of an existing VM extension is updated, which redeploys it.
+		if !s.upgradeBehaviorChanged(extension) {
+			return nil, nil
+		}
 	}
 
 	return compute.VirtualMachineExtension{
 		VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
-			Publisher:          ptr.To(s.Publisher),
-			Type:               ptr.To(s.Name),
-			TypeHandlerVersion: ptr.To(s.Version),
-			Settings:           s.Settings,
-			ProtectedSettings:  s.ProtectedSettings,
+			Publisher:               ptr.To(s.Publisher),
+			Type:                    ptr.To(s.Name),
+			TypeHandlerVersion:      ptr.To(s.Version),
+			AutoUpgradeMinorVersion: s.AutoUpgradeMinorVersion,
+			EnableAutomaticUpgrade:  s.EnableAutomaticUpgrade,
+			Settings:                s.Settings,
+			ProtectedSettings:       s.ProtectedSettings,
 		},
 		Location: ptr.To(s.Location),
 	}, nil
 }
+
+// upgradeBehaviorChanged returns true if the upgrade behavior set in the spec differs from the one of the existing
+// VM extension.
+func (s *VMExtensionSpec) upgradeBehaviorChanged(existing compute.VirtualMachineExtension) bool {
+	props := existing.VirtualMachineExtensionProperties
+	if props == nil {
+		props = &compute.VirtualMachineExtensionProperties{}
+	}
+	if s.AutoUpgradeMinorVersion != nil && *s.AutoUpgradeMinorVersion != ptr.Deref(props.AutoUpgradeMinorVersion, false) {
+		return true
+	}
+	if s.EnableAutomaticUpgrade != nil && *s.EnableAutomaticUpgrade != ptr.Deref(props.EnableAutomaticUpgrade, false) {
+		return true
+	}
+	return false
+}
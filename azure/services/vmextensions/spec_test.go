@@ -50,6 +50,30 @@ var (
 		},
 		Location: ptr.To("my-location"),
 	}
+
+	fakeVMExtensionSpecWithUpgradeBehavior = VMExtensionSpec{
+		azure.ExtensionSpec{
+			Name:                    "my-vm-extension",
+			VMName:                  "my-vm",
+			Publisher:               "my-publisher",
+			Version:                 "1.0",
+			AutoUpgradeMinorVersion: ptr.To(false),
+			EnableAutomaticUpgrade:  ptr.To(true),
+		},
+		"my-rg",
+		"my-location",
+	}
+
+	fakeVMExtensionParamsWithUpgradeBehavior = compute.VirtualMachineExtension{
+		VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+			Publisher:               ptr.To("my-publisher"),
+			Type:                    ptr.To("my-vm-extension"),
+			TypeHandlerVersion:      ptr.To("1.0"),
+			AutoUpgradeMinorVersion: ptr.To(false),
+			EnableAutomaticUpgrade:  ptr.To(true),
+		},
+		Location: ptr.To("my-location"),
+	}
 )
 
 func TestParameters(t *testing.T) {
@@ -78,6 +102,33 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:     "get parameters for vmextension with upgrade behavior",
+			spec:     &fakeVMExtensionSpecWithUpgradeBehavior,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(fakeVMExtensionParamsWithUpgradeBehavior))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "vmextension that already exists with the same upgrade behavior",
+			spec:     &fakeVMExtensionSpecWithUpgradeBehavior,
+			existing: fakeVMExtensionParamsWithUpgradeBehavior,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "",
+		},
+		{
+			name:     "vmextension that already exists with a different upgrade behavior",
+			spec:     &fakeVMExtensionSpecWithUpgradeBehavior,
+			existing: fakeVMExtensionParams,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(fakeVMExtensionParamsWithUpgradeBehavior))
+			},
+			expectedError: "",
+		},
 	}
 	for _, tc := range testcases {
 		tc := tc
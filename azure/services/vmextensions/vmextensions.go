@@ -18,13 +18,19 @@ package vmextensions
 
 import (
 	"context"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensionimages"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 const serviceName = "vmextensions"
@@ -34,20 +40,25 @@ type VMExtensionScope interface {
 	azure.Authorizer
 	azure.AsyncStatusUpdater
 	VMExtensionSpecs() []azure.ResourceSpecGetter
+	VMExtensionsStatusResource() conditions.Setter
 }
 
 // Service provides operations on Azure resources.
 type Service struct {
 	Scope VMExtensionScope
 	async.Reconciler
+	extensionGetter async.Getter
+	extensionImages *vmextensionimages.Cache
 }
 
 // New creates a new vm extension service.
-func New(scope VMExtensionScope) *Service {
-	client := newClient(scope)
+func New(scope VMExtensionScope, extensionImages *vmextensionimages.Cache) *Service {
+	client := NewClient(scope)
 	return &Service{
-		Scope:      scope,
-		Reconciler: async.New(scope, client, client),
+		Scope:           scope,
+		Reconciler:      async.New(scope, client, client),
+		extensionGetter: client,
+		extensionImages: extensionImages,
 	}
 }
 
@@ -69,17 +80,28 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		return nil
 	}
 
+	for _, extensionSpec := range specs {
+		if err := s.validate(ctx, extensionSpec); err != nil {
+			err = errors.Wrapf(err, "invalid VM extension %s", extensionSpec.ResourceName())
+			s.Scope.UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, err)
+			return err
+		}
+	}
+
 	// We go through the list of ExtensionSpecs to reconcile each one, independently of the result of the previous one.
 	// If multiple errors occur, we return the most pressing one.
 	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
 	var resultErr error
 	for _, extensionSpec := range specs {
-		_, err := s.CreateOrUpdateResource(ctx, extensionSpec, serviceName)
+		result, err := s.CreateOrUpdateResource(ctx, extensionSpec, serviceName)
 		if err != nil {
 			if !azure.IsOperationNotDoneError(err) || resultErr == nil {
 				resultErr = err
 			}
 		}
+		if extensionSpec.ResourceName() == azure.BootstrappingExtensionLinux {
+			s.updateNodeJoinedCondition(ctx, extensionSpec, result, err)
+		}
 	}
 
 	if azure.IsOperationNotDoneError(resultErr) {
@@ -92,6 +114,98 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	return resultErr
 }
 
+// updateNodeJoinedCondition reports the last stage reached by the bootstrap of the machine, given the result of
+// reconciling the Linux bootstrap extension.
+func (s *Service) updateNodeJoinedCondition(ctx context.Context, spec azure.ResourceSpecGetter, result interface{}, err error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "vmextensions.Service.updateNodeJoinedCondition")
+	defer done()
+
+	statusResource := s.Scope.VMExtensionsStatusResource()
+	extension, _ := result.(compute.VirtualMachineExtension)
+	var provisioningState string
+	if extension.VirtualMachineExtensionProperties != nil {
+		provisioningState = ptr.Deref(extension.ProvisioningState, "")
+	}
+	stage := bootstrapStage(extension)
+
+	switch {
+	case azure.IsOperationNotDoneError(err):
+		// While the extension is running, its instance view reports the output its command has written so far.
+		running, getErr := s.extensionGetter.Get(ctx, spec)
+		if getErr != nil {
+			log.V(4).Info("failed to get the instance view of the bootstrap extension", "err", getErr.Error())
+		} else if runningExtension, ok := running.(compute.VirtualMachineExtension); ok {
+			stage = bootstrapStage(runningExtension)
+		}
+		reason, ok := bootstrapStageReasons[stage]
+		if !ok {
+			reason = infrav1.BootstrapInProgressReason
+		}
+		conditions.MarkFalse(statusResource, infrav1.NodeJoinedCondition, reason, clusterv1.ConditionSeverityInfo, "")
+	case err != nil:
+		conditions.MarkFalse(statusResource, infrav1.NodeJoinedCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
+	case provisioningState == string(compute.ProvisioningStateFailed):
+		reason, ok := bootstrapStageReasons[stage]
+		if !ok {
+			reason = infrav1.BootstrapFailedReason
+		}
+		conditions.MarkFalse(statusResource, infrav1.NodeJoinedCondition, reason, clusterv1.ConditionSeverityWarning, "bootstrap extension failed before the node joined the cluster")
+	case provisioningState == string(compute.ProvisioningStateSucceeded) || stage == azure.BootstrapStageJoined:
+		conditions.MarkTrue(statusResource, infrav1.NodeJoinedCondition)
+	case stage != "":
+		conditions.MarkFalse(statusResource, infrav1.NodeJoinedCondition, bootstrapStageReasons[stage], clusterv1.ConditionSeverityInfo, "")
+	default:
+		conditions.MarkFalse(statusResource, infrav1.NodeJoinedCondition, infrav1.BootstrapInProgressReason, clusterv1.ConditionSeverityInfo, "")
+	}
+}
+
+// bootstrapStageReasons maps the stages reported by the Linux bootstrap extension before the node joined the cluster
+// to the reasons of the NodeJoined condition.
+var bootstrapStageReasons = map[string]string{
+	azure.BootstrapStageDownloading:    infrav1.BootstrapDownloadingReason,
+	azure.BootstrapStageRunningKubeadm: infrav1.BootstrapRunningKubeadmReason,
+}
+
+// bootstrapStage returns the last known stage reported in the output of the Linux bootstrap extension, or an empty
+// string if it didn't report any. The output is reported in the substatuses of the instance view of the extension,
+// while its command is running and once it has exited.
+func bootstrapStage(extension compute.VirtualMachineExtension) string {
+	if extension.VirtualMachineExtensionProperties == nil || extension.InstanceView == nil {
+		return ""
+	}
+	var statuses []compute.InstanceViewStatus
+	if extension.InstanceView.Substatuses != nil {
+		statuses = append(statuses, *extension.InstanceView.Substatuses...)
+	}
+	if extension.InstanceView.Statuses != nil {
+		statuses = append(statuses, *extension.InstanceView.Statuses...)
+	}
+
+	var stage string
+	for _, status := range statuses {
+		for _, line := range strings.Split(ptr.Deref(status.Message, ""), "\n") {
+			line = strings.TrimSpace(line)
+			if !strings.HasPrefix(line, azure.BootstrapStageMarker) {
+				continue
+			}
+			lineStage := strings.TrimPrefix(line, azure.BootstrapStageMarker)
+			if _, ok := bootstrapStageReasons[lineStage]; ok || lineStage == azure.BootstrapStageJoined {
+				stage = lineStage
+			}
+		}
+	}
+	return stage
+}
+
+// validate returns an error if the publisher doesn't offer a custom VM extension in its version.
+func (s *Service) validate(ctx context.Context, spec azure.ResourceSpecGetter) error {
+	extensionSpec, ok := spec.(*VMExtensionSpec)
+	if !ok || !extensionSpec.Custom {
+		return nil
+	}
+	return s.extensionImages.Validate(ctx, extensionSpec.Publisher, extensionSpec.Name, extensionSpec.Version)
+}
+
 // Delete is a no-op. VM Extensions will be deleted as part of VM deletion.
 func (s *Service) Delete(_ context.Context) error {
 	return nil
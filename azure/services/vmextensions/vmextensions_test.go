@@ -21,15 +21,21 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
 	"github.com/Azure/go-autorest/autorest"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensionimages"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensions/mock_vmextensions"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 var (
@@ -55,6 +61,32 @@ var (
 		Location:      "test-location",
 	}
 
+	customExtensionSpec = VMExtensionSpec{
+		ExtensionSpec: azure.ExtensionSpec{
+			Name:      "CustomScript",
+			VMName:    "my-vm",
+			Publisher: "Microsoft.Azure.Extensions",
+			Version:   "2.1",
+			Custom:    true,
+		},
+		ResourceGroup: "my-rg",
+		Location:      "test-location",
+	}
+
+	unofferedExtensionSpec = VMExtensionSpec{
+		ExtensionSpec: azure.ExtensionSpec{
+			Name:      "CustomScript",
+			VMName:    "my-vm",
+			Publisher: "Microsoft.Azure.Extensions",
+			Version:   "3.0",
+			Custom:    true,
+		},
+		ResourceGroup: "my-rg",
+		Location:      "test-location",
+	}
+
+	unofferedExtensionError = "invalid VM extension CustomScript: reconcile error that cannot be recovered occurred: version 3.0 of VM extension type CustomScript of publisher Microsoft.Azure.Extensions is not offered in location test-location, offered versions are 2.1. Object will not be requeued"
+
 	internalError        = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
 	extensionFailedError = errors.Wrapf(internalError, "extension state failed. This likely means the Kubernetes node bootstrapping process failed or timed out. Check VM boot diagnostics logs to learn more")
 
@@ -105,6 +137,24 @@ func TestReconcileVMExtension(t *testing.T) {
 				s.UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, nil)
 			},
 		},
+		{
+			name:          "custom extension offered in the location",
+			expectedError: "",
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VMExtensionSpecs().Return([]azure.ResourceSpecGetter{&customExtensionSpec, &extensionSpec1})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &customExtensionSpec, serviceName).Return(nil, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &extensionSpec1, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "custom extension version not offered in the location",
+			expectedError: unofferedExtensionError,
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VMExtensionSpecs().Return([]azure.ResourceSpecGetter{&unofferedExtensionSpec, &extensionSpec1})
+				s.UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, gomockinternal.ErrStrEq(unofferedExtensionError))
+			},
+		},
 		{
 			name:          "error creating the first extension",
 			expectedError: extensionFailedError.Error(),
@@ -132,6 +182,9 @@ func TestReconcileVMExtension(t *testing.T) {
 			s := &Service{
 				Scope:      scopeMock,
 				Reconciler: asyncMock,
+				extensionImages: vmextensionimages.NewStaticCache(map[string][]string{
+					"Microsoft.Azure.Extensions/CustomScript": {"2.1.3"},
+				}, "test-location"),
 			}
 
 			err := s.Reconcile(context.TODO())
@@ -144,3 +197,175 @@ func TestReconcileVMExtension(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileVMExtensionNodeJoined(t *testing.T) {
+	bootstrapExtensionSpec := VMExtensionSpec{
+		ExtensionSpec: azure.ExtensionSpec{
+			Name:      azure.BootstrappingExtensionLinux,
+			VMName:    "my-vm",
+			Publisher: "Microsoft.Azure.Extensions",
+			Version:   "2.1",
+		},
+		ResourceGroup: "my-rg",
+		Location:      "test-location",
+	}
+
+	testcases := []struct {
+		name             string
+		result           interface{}
+		err              error
+		running          interface{}
+		getErr           error
+		expectedStatus   corev1.ConditionStatus
+		expectedReason   string
+		expectedSeverity clusterv1.ConditionSeverity
+	}{
+		{
+			name:             "extension is still running",
+			err:              notDoneError,
+			running:          bootstrapExtension("Creating", ""),
+			expectedStatus:   corev1.ConditionFalse,
+			expectedReason:   infrav1.BootstrapInProgressReason,
+			expectedSeverity: clusterv1.ConditionSeverityInfo,
+		},
+		{
+			name:             "extension is still running kubeadm",
+			err:              notDoneError,
+			running:          bootstrapExtension("Creating", "Enable in progress: \n[stdout]\nbootstrap stage: Downloading\nbootstrap stage: RunningKubeadm\n\n[stderr]\n"),
+			expectedStatus:   corev1.ConditionFalse,
+			expectedReason:   infrav1.BootstrapRunningKubeadmReason,
+			expectedSeverity: clusterv1.ConditionSeverityInfo,
+		},
+		{
+			name:             "extension is still running and its instance view can't be read",
+			err:              notDoneError,
+			getErr:           internalError,
+			expectedStatus:   corev1.ConditionFalse,
+			expectedReason:   infrav1.BootstrapInProgressReason,
+			expectedSeverity: clusterv1.ConditionSeverityInfo,
+		},
+		{
+			name:           "extension succeeded",
+			result:         bootstrapExtension("Succeeded", "Enable succeeded: \n[stdout]\nbootstrap stage: Downloading\nbootstrap stage: RunningKubeadm\nbootstrap stage: Joined\n\n[stderr]\n"),
+			expectedStatus: corev1.ConditionTrue,
+		},
+		{
+			name:           "extension succeeded without reporting stages",
+			result:         bootstrapExtension("Succeeded", ""),
+			expectedStatus: corev1.ConditionTrue,
+		},
+		{
+			name:             "extension failed while kubeadm was running",
+			result:           bootstrapExtension("Failed", "Enable failed: \n[stdout]\nbootstrap stage: Downloading\nbootstrap stage: RunningKubeadm\n\n[stderr]\n"),
+			expectedStatus:   corev1.ConditionFalse,
+			expectedReason:   infrav1.BootstrapRunningKubeadmReason,
+			expectedSeverity: clusterv1.ConditionSeverityWarning,
+		},
+		{
+			name:             "extension failed while downloading the bootstrap data",
+			result:           bootstrapExtension("Failed", "Enable failed: \n[stdout]\nbootstrap stage: Downloading\n\n[stderr]\n"),
+			expectedStatus:   corev1.ConditionFalse,
+			expectedReason:   infrav1.BootstrapDownloadingReason,
+			expectedSeverity: clusterv1.ConditionSeverityWarning,
+		},
+		{
+			name:             "extension failed without reporting stages",
+			result:           bootstrapExtension("Failed", ""),
+			expectedStatus:   corev1.ConditionFalse,
+			expectedReason:   infrav1.BootstrapFailedReason,
+			expectedSeverity: clusterv1.ConditionSeverityWarning,
+		},
+		{
+			name:             "error creating the extension",
+			err:              internalError,
+			expectedStatus:   corev1.ConditionFalse,
+			expectedReason:   infrav1.BootstrapFailedReason,
+			expectedSeverity: clusterv1.ConditionSeverityWarning,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_vmextensions.NewMockVMExtensionScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+			getterMock := mock_async.NewMockGetter(mockCtrl)
+			azureMachine := &infrav1.AzureMachine{}
+
+			scopeMock.EXPECT().VMExtensionSpecs().Return([]azure.ResourceSpecGetter{&bootstrapExtensionSpec})
+			asyncMock.EXPECT().CreateOrUpdateResource(gomockinternal.AContext(), &bootstrapExtensionSpec, serviceName).Return(tc.result, tc.err)
+			if azure.IsOperationNotDoneError(tc.err) {
+				getterMock.EXPECT().Get(gomockinternal.AContext(), &bootstrapExtensionSpec).Return(tc.running, tc.getErr)
+			}
+			scopeMock.EXPECT().VMExtensionsStatusResource().Return(azureMachine)
+			scopeMock.EXPECT().UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, gomock.Any())
+
+			s := &Service{
+				Scope:           scopeMock,
+				Reconciler:      asyncMock,
+				extensionGetter: getterMock,
+			}
+
+			_ = s.Reconcile(context.TODO())
+			condition := conditions.Get(azureMachine, infrav1.NodeJoinedCondition)
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(tc.expectedStatus))
+			g.Expect(condition.Reason).To(Equal(tc.expectedReason))
+			g.Expect(condition.Severity).To(Equal(tc.expectedSeverity))
+		})
+	}
+}
+
+func TestBootstrapStage(t *testing.T) {
+	testcases := []struct {
+		name      string
+		extension compute.VirtualMachineExtension
+		expected  string
+	}{
+		{
+			name:      "no instance view",
+			extension: compute.VirtualMachineExtension{VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{}},
+			expected:  "",
+		},
+		{
+			name:      "last stage in the output of the extension",
+			extension: bootstrapExtension("Failed", "Enable failed: \n[stdout]\nbootstrap stage: Downloading\nbootstrap stage: RunningKubeadm\n\n[stderr]\n"),
+			expected:  azure.BootstrapStageRunningKubeadm,
+		},
+		{
+			name:      "unknown stages are ignored",
+			extension: bootstrapExtension("Failed", "Enable failed: \n[stdout]\nbootstrap stage: Downloading\nbootstrap stage: Unknown\n\n[stderr]\n"),
+			expected:  azure.BootstrapStageDownloading,
+		},
+		{
+			name:      "joined",
+			extension: bootstrapExtension("Succeeded", "Enable succeeded: \n[stdout]\nbootstrap stage: Joined\n\n[stderr]\n"),
+			expected:  azure.BootstrapStageJoined,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(bootstrapStage(tc.extension)).To(Equal(tc.expected))
+		})
+	}
+}
+
+// bootstrapExtension returns a bootstrap VM extension in the given provisioning state, whose output is the given message.
+func bootstrapExtension(provisioningState, message string) compute.VirtualMachineExtension {
+	return compute.VirtualMachineExtension{
+		VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+			ProvisioningState: ptr.To(provisioningState),
+			InstanceView: &compute.VirtualMachineExtensionInstanceView{
+				Substatuses: &[]compute.InstanceViewStatus{
+					{Code: ptr.To("ComponentStatus/StdOut/succeeded"), Message: ptr.To(message)},
+				},
+			},
+		},
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 )
@@ -91,19 +92,30 @@ type ExtensionSpec struct {
 	Version           string
 	Settings          map[string]string
 	ProtectedSettings map[string]string
+	// AutoUpgradeMinorVersion and EnableAutomaticUpgrade are left to the Azure defaults when nil.
+	AutoUpgradeMinorVersion *bool
+	EnableAutomaticUpgrade  *bool
+	// Custom indicates the extension is one of the custom VM extensions of the machine spec. Custom extensions are
+	// validated against the VM extension images offered in the location before they are created.
+	Custom bool
 }
 
 type (
 	// VMSSVM defines a VM in a virtual machine scale set.
 	VMSSVM struct {
-		ID                 string                        `json:"id,omitempty"`
-		InstanceID         string                        `json:"instanceID,omitempty"`
-		Image              infrav1.Image                 `json:"image,omitempty"`
-		Name               string                        `json:"name,omitempty"`
-		AvailabilityZone   string                        `json:"availabilityZone,omitempty"`
-		State              infrav1.ProvisioningState     `json:"vmState,omitempty"`
-		BootstrappingState infrav1.ProvisioningState     `json:"bootstrappingState,omitempty"`
-		OrchestrationMode  infrav1.OrchestrationModeType `json:"orchestrationMode,omitempty"`
+		ID                  string                        `json:"id,omitempty"`
+		InstanceID          string                        `json:"instanceID,omitempty"`
+		Image               infrav1.Image                 `json:"image,omitempty"`
+		Name                string                        `json:"name,omitempty"`
+		AvailabilityZone    string                        `json:"availabilityZone,omitempty"`
+		State               infrav1.ProvisioningState     `json:"vmState,omitempty"`
+		PowerState          string                        `json:"powerState,omitempty"`
+		BootstrappingState  infrav1.ProvisioningState     `json:"bootstrappingState,omitempty"`
+		OrchestrationMode   infrav1.OrchestrationModeType `json:"orchestrationMode,omitempty"`
+		PatchStatus         *infrav1.VMPatchStatus        `json:"patchStatus,omitempty"`
+		PlatformFaultDomain *int32                        `json:"platformFaultDomain,omitempty"`
+		Addresses           []corev1.NodeAddress          `json:"addresses,omitempty"`
+		NetworkInterfaceIDs []string                      `json:"networkInterfaceIDs,omitempty"`
 	}
 
 	// VMSS defines a virtual machine scale set.
@@ -122,11 +134,11 @@ type (
 )
 
 // HasModelChanges returns true if the spec fields which will mutate the Azure VMSS model are different.
+// Tags aren't part of the model, they are updated through the Tags API without rolling out the instances.
 func (vmss VMSS) HasModelChanges(other VMSS) bool {
 	equal := cmp.Equal(vmss.Image, other.Image) &&
 		cmp.Equal(vmss.Identity, other.Identity) &&
 		cmp.Equal(vmss.Zones, other.Zones) &&
-		cmp.Equal(vmss.Tags, other.Tags) &&
 		cmp.Equal(vmss.Sku, other.Sku)
 	return !equal
 }
@@ -298,8 +298,8 @@ func (asos *ASOSecretReconciler) createSecretFromClusterIdentity(ctx context.Con
 	case infrav1.ServicePrincipal, infrav1.ManualServicePrincipal:
 		newASOSecret.Data["AZURE_CLIENT_SECRET"] = identitySecret.Data[scope.AzureSecretKey]
 	case infrav1.ServicePrincipalCertificate:
-		newASOSecret.Data["AZURE_CLIENT_CERTIFICATE"] = identitySecret.Data["certificate"]
-		newASOSecret.Data["AZURE_CLIENT_CERTIFICATE_PASSWORD"] = identitySecret.Data["password"]
+		newASOSecret.Data["AZURE_CLIENT_CERTIFICATE"] = identitySecret.Data[scope.AzureCertificateKey]
+		newASOSecret.Data["AZURE_CLIENT_CERTIFICATE_PASSWORD"] = identitySecret.Data[scope.AzureCertificatePasswordKey]
 	}
 	return newASOSecret, nil
 }
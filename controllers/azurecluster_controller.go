@@ -102,9 +102,51 @@ func (acr *AzureClusterReconciler) SetupWithManager(ctx context.Context, mgr ctr
 		return errors.Wrap(err, "failed adding a watch for ready clusters")
 	}
 
+	// Add a watch on AzureMachines with retained disks, so that the disks are recorded in the AzureCluster status
+	// before the machines are deleted.
+	if err = c.Watch(
+		source.Kind(mgr.GetCache(), &infrav1.AzureMachine{}),
+		handler.EnqueueRequestsFromMapFunc(acr.azureMachineWithRetainedDisksToAzureCluster),
+		predicates.ResourceHasFilterLabel(log, acr.WatchFilterValue),
+	); err != nil {
+		return errors.Wrap(err, "failed adding a watch for AzureMachines with retained disks")
+	}
+
 	return nil
 }
 
+// azureMachineWithRetainedDisksToAzureCluster maps an AzureMachine with disks whose delete policy is Retain to the
+// AzureCluster of its cluster.
+func (acr *AzureClusterReconciler) azureMachineWithRetainedDisksToAzureCluster(ctx context.Context, o client.Object) []reconcile.Request {
+	azureMachine, ok := o.(*infrav1.AzureMachine)
+	if !ok || !hasRetainedDisks(azureMachine) {
+		return nil
+	}
+
+	cluster, err := util.GetClusterFromMetadata(ctx, acr.Client, azureMachine.ObjectMeta)
+	if err != nil || cluster.Spec.InfrastructureRef == nil || cluster.Spec.InfrastructureRef.Kind != "AzureCluster" {
+		return nil
+	}
+
+	return []reconcile.Request{{
+		NamespacedName: client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.InfrastructureRef.Name},
+	}}
+}
+
+// hasRetainedDisks returns true if the OS disk or one of the data disks created for the AzureMachine are retained
+// when the machine is deleted.
+func hasRetainedDisks(azureMachine *infrav1.AzureMachine) bool {
+	if azureMachine.Spec.OSDisk.DeletePolicy == infrav1.DeletePolicyRetain {
+		return true
+	}
+	for _, dd := range azureMachine.Spec.DataDisks {
+		if dd.IsRetained() && !dd.IsShared() {
+			return true
+		}
+	}
+	return false
+}
+
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
@@ -152,6 +194,7 @@ func (acr *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	}
 
 	log = log.WithValues("cluster", cluster.Name)
+	ctx = azure.WithUserAgentSuffix(ctx, cluster)
 
 	// Create the scope.
 	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
@@ -285,6 +328,13 @@ func (acr *AzureClusterReconciler) reconcileDelete(ctx context.Context, clusterS
 
 	azureCluster := clusterScope.AzureCluster
 
+	if azureCluster.Spec.DeletionProtection {
+		log.Info("Skipping AzureCluster deletion since deletion protection is enabled")
+		acr.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, infrav1.DeletionProtectedReason, "AzureCluster %s/%s has deletion protection enabled, set spec.deletionProtection to false to delete it", azureCluster.Namespace, azureCluster.Name)
+		conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, infrav1.DeletionProtectedReason, clusterv1.ConditionSeverityWarning, "deletion protection is enabled")
+		return reconcile.Result{}, nil
+	}
+
 	acs, err := acr.createAzureClusterService(clusterScope)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to create a new AzureClusterReconciler")
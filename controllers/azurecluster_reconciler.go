@@ -20,23 +20,32 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/applicationgateways"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/asogroups"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/azurefirewalls"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bastionhosts"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/natgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/netapp"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privatedns"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privateendpoints"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourcehealth"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceproviders"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/routetables"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/securitygroups"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/skumigration"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/trafficmanager"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vnetpeerings"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
@@ -64,22 +73,33 @@ func newAzureClusterService(scope *scope.ClusterScope) (*azureClusterService, er
 	if err != nil {
 		return nil, err
 	}
+	resourceProvidersSvc, err := resourceproviders.New(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &azureClusterService{
 		scope: scope,
 		services: []azure.ServiceReconciler{
+			resourceProvidersSvc,
 			groupsSvc,
 			virtualnetworks.New(scope),
 			securitygroups.New(scope),
 			routetables.New(scope),
+			skumigration.New(scope),
 			publicips.New(scope),
 			natGatewaysSvc,
 			subnets.New(scope),
+			netapp.New(scope),
+			azurefirewalls.New(scope),
+			applicationgateways.New(scope),
 			vnetpeerings.New(scope),
 			loadbalancers.New(scope),
 			privatedns.New(scope),
 			bastionhosts.New(scope),
 			privateendpoints.New(scope),
+			trafficmanager.New(scope),
 			tags.New(scope),
+			resourcehealth.New(scope, feature.ResourceHealth),
 		},
 		skuCache: skuCache,
 	}, nil
@@ -94,9 +114,29 @@ func (s *azureClusterService) Reconcile(ctx context.Context) error {
 		return errors.Wrap(err, "failed to get availability zones")
 	}
 
+	if err := s.setVMSizeZones(ctx); err != nil {
+		return errors.Wrap(err, "failed to get availability zones of VM sizes")
+	}
+
+	if err := s.scope.ValidateAPIServerPort(); err != nil {
+		return err
+	}
+
+	if err := s.scope.ValidateSharedVnetSubnets(ctx); err != nil {
+		return err
+	}
+
+	// The retained disks of the machines are recorded while they exist, so that they are still known, and the
+	// resource group kept, once the machines are deleted along with the cluster.
+	if err := s.scope.LoadRetainedDisks(ctx); err != nil {
+		return err
+	}
+	s.scope.SetRetainedResources()
+
 	s.scope.AzureCluster.SetBackendPoolNameDefault()
 	s.scope.SetDNSName()
 	s.scope.SetControlPlaneSecurityRules()
+	s.scope.SetNodeSecurityRules()
 
 	for _, service := range s.services {
 		if err := service.Reconcile(ctx); err != nil {
@@ -127,9 +167,14 @@ func (s *azureClusterService) Pause(ctx context.Context) error {
 
 // Delete reconciles all the services in a predetermined order.
 func (s *azureClusterService) Delete(ctx context.Context) error {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.Delete")
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.Delete")
 	defer done()
 
+	if err := s.scope.LoadRetainedDisks(ctx); err != nil {
+		return err
+	}
+	s.scope.SetRetainedResources()
+
 	if !ShouldDeleteIndividualResources(ctx, s.scope) {
 		// If the resource group is managed, delete it.
 		// We need to explicitly delete vnet peerings, as it is not part of the resource group.
@@ -141,6 +186,18 @@ func (s *azureClusterService) Delete(ctx context.Context) error {
 			return errors.Wrap(err, "failed to delete peerings")
 		}
 
+		// The Traffic Manager profile may be shared with other clusters and live outside of the resource group,
+		// so the API server endpoint needs to be deregistered explicitly.
+		if s.scope.AzureCluster.Spec.TrafficManager != nil {
+			trafficManagerSvc, err := s.getService(trafficmanager.ServiceName)
+			if err != nil {
+				return errors.Wrap(err, "failed to get traffic manager service")
+			}
+			if err := trafficManagerSvc.Delete(ctx); err != nil {
+				return errors.Wrap(err, "failed to delete traffic manager endpoint")
+			}
+		}
+
 		groupsServiceName := asogroups.ServiceName
 		if s.scope.UseLegacyGroups {
 			groupsServiceName = groups.ServiceName
@@ -157,6 +214,13 @@ func (s *azureClusterService) Delete(ctx context.Context) error {
 		// If the resource group is not managed we need to delete resources inside the group one by one.
 		// services are deleted in reverse order from the order in which they are reconciled.
 		for i := len(s.services) - 1; i >= 0; i-- {
+			if s.scope.HasRetainedResourcesInResourceGroup() && s.isGroupsService(s.services[i]) {
+				log.Info("Skipping resource group deletion as it contains resources with a Retain delete policy")
+				if err := s.releaseResourceGroup(ctx, s.services[i]); err != nil {
+					return errors.Wrap(err, "failed to release resource group")
+				}
+				continue
+			}
 			if err := s.services[i].Delete(ctx); err != nil {
 				return errors.Wrapf(err, "failed to delete AzureCluster service %s", s.services[i].Name())
 			}
@@ -166,6 +230,25 @@ func (s *azureClusterService) Delete(ctx context.Context) error {
 	return nil
 }
 
+// isGroupsService returns true if the given service reconciles the cluster resource group.
+func (s *azureClusterService) isGroupsService(service azure.ServiceReconciler) bool {
+	return service.Name() == asogroups.ServiceName || service.Name() == groups.ServiceName
+}
+
+// releaseResourceGroup stops managing the cluster resource group without deleting it in Azure.
+// ASO deletes a resource group in Azure along with its ASO resource unless its reconcile-policy is skip,
+// so the resource is paused before it is deleted.
+func (s *azureClusterService) releaseResourceGroup(ctx context.Context, groupsSvc azure.ServiceReconciler) error {
+	pauser, ok := groupsSvc.(azure.Pauser)
+	if !ok {
+		return nil
+	}
+	if err := pauser.Pause(ctx); err != nil {
+		return err
+	}
+	return groupsSvc.Delete(ctx)
+}
+
 func (s *azureClusterService) getService(name string) (azure.ServiceReconciler, error) {
 	for _, service := range s.services {
 		if service.Name() == name {
@@ -195,3 +278,31 @@ func (s *azureClusterService) setFailureDomainsForLocation(ctx context.Context)
 
 	return nil
 }
+
+// setVMSizeZones reports the availability zones in which each of the VM sizes of the cluster is available.
+func (s *azureClusterService) setVMSizeZones(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.setVMSizeZones")
+	defer done()
+
+	if s.scope.ExtendedLocation() != nil || len(s.scope.VMSizes()) == 0 {
+		s.scope.SetVMSizeZones(nil)
+		return nil
+	}
+
+	var vmSizeZones []infrav1.VMSizeZones
+	for _, size := range s.scope.VMSizes() {
+		if _, err := s.skuCache.Get(ctx, size, resourceskus.VirtualMachines); err != nil {
+			log.V(4).Info("VM size is not available in location", "vmSize", size, "location", s.scope.Location())
+			continue
+		}
+
+		zones, err := s.skuCache.GetZonesWithVMSize(ctx, size, s.scope.Location())
+		if err != nil {
+			return errors.Wrapf(err, "failed to get zones for VM size %s", size)
+		}
+		vmSizeZones = append(vmSizeZones, infrav1.VMSizeZones{VMSize: size, Zones: zones})
+	}
+
+	s.scope.SetVMSizeZones(vmSizeZones)
+	return nil
+}
@@ -28,6 +28,7 @@ import (
 	"go.uber.org/mock/gomock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
@@ -81,8 +82,12 @@ func TestAzureClusterServiceReconcile(t *testing.T) {
 
 			tc.expect(svcOneMock.EXPECT(), svcTwoMock.EXPECT(), svcThreeMock.EXPECT())
 
+			scheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+
 			s := &azureClusterService{
 				scope: &scope.ClusterScope{
+					Client:       fakeclient.NewClientBuilder().WithScheme(scheme).Build(),
 					Cluster:      &clusterv1.Cluster{},
 					AzureCluster: &infrav1.AzureCluster{},
 				},
@@ -105,6 +110,94 @@ func TestAzureClusterServiceReconcile(t *testing.T) {
 	}
 }
 
+func TestAzureClusterServiceSetVMSizeZones(t *testing.T) {
+	skus := []compute.ResourceSku{
+		{
+			Name:         ptr.To("Standard_D2s_v3"),
+			ResourceType: ptr.To(string(resourceskus.VirtualMachines)),
+			LocationInfo: &[]compute.ResourceSkuLocationInfo{
+				{
+					Location: ptr.To("eastus"),
+					Zones:    &[]string{"3", "1", "2"},
+				},
+			},
+		},
+		{
+			Name:         ptr.To("Standard_NC6"),
+			ResourceType: ptr.To(string(resourceskus.VirtualMachines)),
+			LocationInfo: &[]compute.ResourceSkuLocationInfo{
+				{
+					Location: ptr.To("eastus"),
+					Zones:    &[]string{"1", "2", "3"},
+				},
+			},
+			Restrictions: &[]compute.ResourceSkuRestrictions{
+				{
+					Type: compute.ResourceSkuRestrictionsTypeZone,
+					RestrictionInfo: &compute.ResourceSkuRestrictionInfo{
+						Zones: &[]string{"2"},
+					},
+				},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		vmSizes          []string
+		extendedLocation *infrav1.ExtendedLocationSpec
+		expected         []infrav1.VMSizeZones
+	}{
+		"no VM sizes": {},
+		"zones of each VM size are reported": {
+			vmSizes: []string{"Standard_D2s_v3", "Standard_NC6"},
+			expected: []infrav1.VMSizeZones{
+				{VMSize: "Standard_D2s_v3", Zones: []string{"1", "2", "3"}},
+				{VMSize: "Standard_NC6", Zones: []string{"1", "3"}},
+			},
+		},
+		"VM sizes not offered in the location are left out": {
+			vmSizes: []string{"Standard_Unknown", "Standard_D2s_v3"},
+			expected: []infrav1.VMSizeZones{
+				{VMSize: "Standard_D2s_v3", Zones: []string{"1", "2", "3"}},
+			},
+		},
+		"extended location": {
+			vmSizes:          []string{"Standard_D2s_v3"},
+			extendedLocation: &infrav1.ExtendedLocationSpec{Name: "losangeles", Type: "EdgeZone"},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			azureCluster := &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						Location:         "eastus",
+						ExtendedLocation: tc.extendedLocation,
+						VMSizes:          tc.vmSizes,
+					},
+				},
+				Status: infrav1.AzureClusterStatus{
+					VMSizeZones: []infrav1.VMSizeZones{{VMSize: "Standard_Stale"}},
+				},
+			}
+			s := &azureClusterService{
+				scope: &scope.ClusterScope{
+					Cluster:      &clusterv1.Cluster{},
+					AzureCluster: azureCluster,
+				},
+				skuCache: resourceskus.NewStaticCache(skus, "eastus"),
+			}
+
+			g.Expect(s.setVMSizeZones(context.TODO())).To(Succeed())
+			g.Expect(azureCluster.Status.VMSizeZones).To(Equal(tc.expected))
+		})
+	}
+}
+
 func TestAzureClusterServicePause(t *testing.T) {
 	type pausingServiceReconciler struct {
 		*mock_azure.MockServiceReconciler
@@ -190,6 +283,7 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 			clientBuilder: func(g Gomega) client.Client {
 				scheme := runtime.NewScheme()
 				g.Expect(asoresourcesv1.AddToScheme(scheme)).To(Succeed())
+				g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
 
 				rg := &asoresourcesv1.ResourceGroup{
 					ObjectMeta: metav1.ObjectMeta{
@@ -225,6 +319,7 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 			clientBuilder: func(g Gomega) client.Client {
 				scheme := runtime.NewScheme()
 				g.Expect(asoresourcesv1.AddToScheme(scheme)).To(Succeed())
+				g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
 
 				rg := &asoresourcesv1.ResourceGroup{
 					ObjectMeta: metav1.ObjectMeta{
@@ -260,6 +355,7 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 			clientBuilder: func(g Gomega) client.Client {
 				scheme := runtime.NewScheme()
 				g.Expect(asoresourcesv1.AddToScheme(scheme)).To(Succeed())
+				g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
 
 				rg := &asoresourcesv1.ResourceGroup{
 					ObjectMeta: metav1.ObjectMeta{
@@ -292,6 +388,7 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 			clientBuilder: func(g Gomega) client.Client {
 				scheme := runtime.NewScheme()
 				g.Expect(asoresourcesv1.AddToScheme(scheme)).To(Succeed())
+				g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
 
 				rg := &asoresourcesv1.ResourceGroup{
 					ObjectMeta: metav1.ObjectMeta{
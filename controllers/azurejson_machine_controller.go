@@ -90,6 +90,21 @@ func (r *AzureJSONMachineReconciler) SetupWithManager(ctx context.Context, mgr c
 		return errors.Wrap(err, "failed adding a watch for Clusters")
 	}
 
+	azureClusterMapper, err := AzureClusterToTypedObjectsMapper(r.Client, &infrav1.AzureMachineList{}, mgr.GetScheme(), log)
+	if err != nil {
+		return errors.Wrap(err, "failed to create mapper for AzureCluster to AzureMachines")
+	}
+
+	// Add a watch on AzureClusters to regenerate the cloud provider config when its overrides change.
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &infrav1.AzureCluster{}),
+		handler.EnqueueRequestsFromMapFunc(azureClusterMapper),
+		AzureClusterCloudProviderConfigOverridesChange(log),
+		predicates.ResourceNotPausedAndHasFilterLabel(log, r.WatchFilterValue),
+	); err != nil {
+		return errors.Wrap(err, "failed adding a watch for AzureClusters")
+	}
+
 	return nil
 }
 
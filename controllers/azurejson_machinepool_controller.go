@@ -86,6 +86,21 @@ func (r *AzureJSONMachinePoolReconciler) SetupWithManager(ctx context.Context, m
 		return errors.Wrap(err, "failed adding a watch for Clusters")
 	}
 
+	azureClusterMapper, err := AzureClusterToTypedObjectsMapper(r.Client, &infrav1exp.AzureMachinePoolList{}, mgr.GetScheme(), log)
+	if err != nil {
+		return errors.Wrap(err, "failed to create mapper for AzureCluster to AzureMachinePools")
+	}
+
+	// Add a watch on AzureClusters to regenerate the cloud provider config when its overrides change.
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &infrav1.AzureCluster{}),
+		handler.EnqueueRequestsFromMapFunc(azureClusterMapper),
+		AzureClusterCloudProviderConfigOverridesChange(log),
+		predicates.ResourceNotPausedAndHasFilterLabel(log, r.WatchFilterValue),
+	); err != nil {
+		return errors.Wrap(err, "failed adding a watch for AzureClusters")
+	}
+
 	return nil
 }
 
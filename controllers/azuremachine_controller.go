@@ -128,6 +128,7 @@ func (amr *AzureMachineReconciler) SetupWithManager(ctx context.Context, mgr ctr
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachines/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azurevmsizecatalogs,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
@@ -178,6 +179,7 @@ func (amr *AzureMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	}
 
 	log = log.WithValues("cluster", cluster.Name)
+	ctx = azure.WithUserAgentSuffix(ctx, cluster)
 
 	log = log.WithValues("AzureCluster", cluster.Spec.InfrastructureRef.Name)
 	azureClusterName := client.ObjectKey{
@@ -299,7 +301,13 @@ func (amr *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineS
 		return reconcile.Result{}, errors.Wrap(err, "failed to create azure machine service")
 	}
 
-	if err := ams.Reconcile(ctx); err != nil {
+	hadBootLog := conditions.Has(machineScope.AzureMachine, infrav1.BootLogCondition)
+	err = ams.Reconcile(ctx)
+	// Publish the serial console log of a VM whose bootstrap failed as soon as it is retrieved.
+	if !hadBootLog && conditions.Has(machineScope.AzureMachine, infrav1.BootLogCondition) {
+		amr.Recorder.Event(machineScope.AzureMachine, corev1.EventTypeWarning, infrav1.BootstrapFailedReason, conditions.GetMessage(machineScope.AzureMachine, infrav1.BootLogCondition))
+	}
+	if err != nil {
 		// This means that a VM was created and managed by this controller, but is not present anymore.
 		// In this case, we mark it as failed and leave it to MHC for remediation
 		if errors.As(err, &azure.VMDeletedError{}) {
@@ -364,6 +372,12 @@ func (amr *AzureMachineReconciler) reconcileDelete(ctx context.Context, machineS
 	defer done()
 
 	log.Info("Handling deleted AzureMachine")
+	if IsDeletionProtected(clusterScope) {
+		log.Info("Skipping AzureMachine deletion since the AzureCluster has deletion protection enabled")
+		conditions.MarkFalse(machineScope.AzureMachine, infrav1.VMRunningCondition, infrav1.DeletionProtectedReason, clusterv1.ConditionSeverityWarning, "AzureCluster has deletion protection enabled")
+		return reconcile.Result{}, nil
+	}
+
 	conditions.MarkFalse(machineScope.AzureMachine, infrav1.VMRunningCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
 	if err := machineScope.PatchObject(ctx); err != nil {
 		return reconcile.Result{}, err
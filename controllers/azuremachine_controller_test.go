@@ -37,6 +37,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -328,6 +329,24 @@ func TestAzureMachineReconcileDelete(t *testing.T) {
 	}
 }
 
+func TestAzureMachineReconcileDeleteWithDeletionProtection(t *testing.T) {
+	g := NewWithT(t)
+
+	reconciler, machineScope, clusterScope, err := getReconcileInputs(TestReconcileInput{
+		createAzureMachineService: getFakeAzureMachineServiceWithGeneralError,
+		cache:                     &scope.MachineCache{},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	clusterScope.AzureCluster.Spec.DeletionProtection = true
+	clusterScope.Cluster.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	result, err := reconciler.reconcileDelete(context.Background(), machineScope, clusterScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(reconcile.Result{}))
+	g.Expect(conditions.GetReason(machineScope.AzureMachine, infrav1.VMRunningCondition)).To(Equal(infrav1.DeletionProtectedReason))
+}
+
 func getReconcileInputs(tc TestReconcileInput) (*AzureMachineReconciler, *scope.MachineScope, *scope.ClusterScope, error) {
 	scheme, err := newScheme()
 	if err != nil {
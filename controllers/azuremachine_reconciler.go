@@ -27,11 +27,16 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inboundnatrules"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourcehealth"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/retailprices"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/runcommands"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensionimages"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensions"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -53,6 +58,14 @@ func newAzureMachineService(machineScope *scope.MachineScope) (*azureMachineServ
 	if err != nil {
 		return nil, errors.Wrap(err, "failed creating a NewCache")
 	}
+	extensionImagesCache, err := vmextensionimages.GetCache(machineScope, machineScope.Location())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating a VM extension images cache")
+	}
+	retailPricesSvc, err := retailprices.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating retail prices service")
+	}
 	ams := &azureMachineService{
 		scope: machineScope,
 		services: []azure.ServiceReconciler{
@@ -63,8 +76,11 @@ func newAzureMachineService(machineScope *scope.MachineScope) (*azureMachineServ
 			disks.New(machineScope),
 			virtualmachines.New(machineScope),
 			roleassignments.New(machineScope),
-			vmextensions.New(machineScope),
+			vmextensions.New(machineScope, extensionImagesCache),
 			tags.New(machineScope),
+			resourcehealth.New(machineScope, feature.ResourceHealth),
+			retailPricesSvc,
+			runcommands.New(machineScope),
 		},
 		skuCache: cache,
 	}
@@ -119,6 +135,8 @@ func (s *azureMachineService) delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureMachineService.delete")
 	defer done()
 
+	s.scope.SetRetainedResources()
+
 	// Delete services in reverse order of creation.
 	for i := len(s.services) - 1; i >= 0; i-- {
 		if err := s.services[i].Delete(ctx); err != nil {
@@ -147,6 +147,7 @@ func (amcpr *AzureManagedControlPlaneReconciler) Reconcile(ctx context.Context,
 	}
 
 	log = log.WithValues("cluster", cluster.Name)
+	ctx = azure.WithUserAgentSuffix(ctx, cluster)
 
 	// Fetch all the ManagedMachinePools owned by this Cluster.
 	opt1 := client.InNamespace(azureControlPlane.Namespace)
@@ -235,7 +236,15 @@ func (amcpr *AzureManagedControlPlaneReconciler) reconcileNormal(ctx context.Con
 		}
 	}
 
-	if err := newAzureManagedControlPlaneReconciler(scope).Reconcile(ctx); err != nil {
+	if err := scope.InitWindowsAdminPassword(ctx); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	svc, err := newAzureManagedControlPlaneReconciler(scope)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to create azureManagedControlPlane service")
+	}
+	if err := svc.Reconcile(ctx); err != nil {
 		// Handle transient and terminal errors
 		log := log.WithValues("name", scope.ControlPlane.Name, "namespace", scope.ControlPlane.Namespace)
 		var reconcileError azure.ReconcileError
@@ -271,7 +280,11 @@ func (amcpr *AzureManagedControlPlaneReconciler) reconcilePause(ctx context.Cont
 
 	log.Info("Reconciling AzureManagedControlPlane pause")
 
-	if err := newAzureManagedControlPlaneReconciler(scope).Pause(ctx); err != nil {
+	svc, err := newAzureManagedControlPlaneReconciler(scope)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to create azureManagedControlPlane service")
+	}
+	if err := svc.Pause(ctx); err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to pause control plane services")
 	}
 
@@ -284,7 +297,11 @@ func (amcpr *AzureManagedControlPlaneReconciler) reconcileDelete(ctx context.Con
 
 	log.Info("Reconciling AzureManagedControlPlane delete")
 
-	if err := newAzureManagedControlPlaneReconciler(scope).Delete(ctx); err != nil {
+	svc, err := newAzureManagedControlPlaneReconciler(scope)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to create azureManagedControlPlane service")
+	}
+	if err := svc.Delete(ctx); err != nil {
 		// Handle transient errors
 		var reconcileError azure.ReconcileError
 		if errors.As(err, &reconcileError) && reconcileError.IsTransient() {
@@ -27,9 +27,11 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privateendpoints"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourcehealth"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceproviders"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	"sigs.k8s.io/cluster-api/util/secret"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -44,24 +46,29 @@ type azureManagedControlPlaneService struct {
 }
 
 // newAzureManagedControlPlaneReconciler populates all the services based on input scope.
-func newAzureManagedControlPlaneReconciler(scope *scope.ManagedControlPlaneScope) *azureManagedControlPlaneService {
+func newAzureManagedControlPlaneReconciler(scope *scope.ManagedControlPlaneScope) (*azureManagedControlPlaneService, error) {
 	var groupsService azure.ServiceReconciler = asogroups.New(scope)
 	if scope.UseLegacyGroups {
 		groupsService = groups.New(scope)
 	}
+	resourceProvidersService, err := resourceproviders.New(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &azureManagedControlPlaneService{
 		kubeclient: scope.Client,
 		scope:      scope,
 		services: []azure.ServiceReconciler{
+			resourceProvidersService,
 			groupsService,
 			virtualnetworks.New(scope),
 			subnets.New(scope),
 			managedclusters.New(scope),
 			privateendpoints.New(scope),
 			tags.New(scope),
-			resourcehealth.New(scope),
+			resourcehealth.New(scope, feature.AKSResourceHealth),
 		},
-	}
+	}, nil
 }
 
 // Reconcile reconciles all the services in a predetermined order.
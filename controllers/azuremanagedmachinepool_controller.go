@@ -173,6 +173,7 @@ func (ammpr *AzureManagedMachinePoolReconciler) Reconcile(ctx context.Context, r
 	}
 
 	log = log.WithValues("ownerCluster", ownerCluster.Name)
+	ctx = azure.WithUserAgentSuffix(ctx, ownerCluster)
 
 	// Fetch the corresponding control plane which has all the interesting data.
 	controlPlane := &infrav1.AzureManagedControlPlane{}
@@ -131,6 +131,36 @@ func AzureClusterToAzureMachinesMapper(ctx context.Context, c client.Client, obj
 	}, nil
 }
 
+// AzureClusterToTypedObjectsMapper creates a mapping handler to transform AzureClusters into objects of the given list
+// type belonging to the owning Cluster.
+func AzureClusterToTypedObjectsMapper(c client.Client, ro client.ObjectList, scheme *runtime.Scheme, log logr.Logger) (handler.MapFunc, error) {
+	clusterMapper, err := util.ClusterToTypedObjectsMapper(c, ro, scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, o client.Object) []ctrl.Request {
+		azCluster, ok := o.(*infrav1.AzureCluster)
+		if !ok {
+			log.Error(errors.Errorf("expected an AzureCluster, got %T instead", o), "failed to map AzureCluster")
+			return nil
+		}
+
+		clusterName, ok := GetOwnerClusterName(azCluster.ObjectMeta)
+		if !ok {
+			log.V(4).Info("unable to get the owner cluster", "AzureCluster", klog.KObj(azCluster))
+			return nil
+		}
+
+		return clusterMapper(ctx, &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: azCluster.Namespace,
+				Name:      clusterName,
+			},
+		})
+	}, nil
+}
+
 // GetOwnerClusterName returns the name of the owning Cluster by finding a clusterv1.Cluster in the ownership references.
 func GetOwnerClusterName(obj metav1.ObjectMeta) (string, bool) {
 	for _, ref := range obj.OwnerReferences {
@@ -342,6 +372,7 @@ type CloudProviderConfig struct {
 	MaximumLoadBalancerRuleCount int    `json:"maximumLoadBalancerRuleCount"`
 	UseManagedIdentityExtension  bool   `json:"useManagedIdentityExtension"`
 	UseInstanceMetadata          bool   `json:"useInstanceMetadata"`
+	ExcludeMasterFromStandardLB  *bool  `json:"excludeMasterFromStandardLB,omitempty"`
 	EnableVmssFlexNodes          bool   `json:"enableVmssFlexNodes,omitempty"`
 	UserAssignedIdentityID       string `json:"userAssignedIdentityID,omitempty"`
 	CloudProviderRateLimitConfig
@@ -390,6 +421,19 @@ func (cpc *CloudProviderConfig) overrideFromSpec(d azure.ClusterScoper) *CloudPr
 	}
 
 	cpc.BackOffConfig = toCloudProviderBackOffConfig(d.CloudProviderConfigOverrides().BackOffs)
+
+	if sku := d.CloudProviderConfigOverrides().LoadBalancerSKU; sku != "" {
+		cpc.LoadBalancerSku = sku
+	}
+	if useInstanceMetadata := d.CloudProviderConfigOverrides().UseInstanceMetadata; useInstanceMetadata != nil {
+		cpc.UseInstanceMetadata = *useInstanceMetadata
+	}
+	if excludeMaster := d.CloudProviderConfigOverrides().ExcludeMasterFromStandardLB; excludeMaster != nil {
+		cpc.ExcludeMasterFromStandardLB = excludeMaster
+	}
+	if ruleCount := d.CloudProviderConfigOverrides().MaximumLoadBalancerRuleCount; ruleCount != nil {
+		cpc.MaximumLoadBalancerRuleCount = *ruleCount
+	}
 	return cpc
 }
 
@@ -607,6 +651,11 @@ func ShouldDeleteIndividualResources(ctx context.Context, clusterScope *scope.Cl
 	if clusterScope.Cluster.DeletionTimestamp.IsZero() {
 		return true
 	}
+	// Deleting the resource group would delete the resources that need to be retained with it, including the
+	// disks of the machines. If they can't be listed, take the long way as well.
+	if err := clusterScope.LoadRetainedDisks(ctx); err != nil || clusterScope.HasRetainedResourcesInResourceGroup() {
+		return true
+	}
 	if clusterScope.UseLegacyGroups {
 		grpSvc := groups.New(clusterScope)
 		managed, err := grpSvc.IsManaged(ctx)
@@ -617,6 +666,12 @@ func ShouldDeleteIndividualResources(ctx context.Context, clusterScope *scope.Cl
 	return asogroups.New(clusterScope).ShouldDeleteIndividualResources(ctx)
 }
 
+// IsDeletionProtected returns true if the Cluster is being deleted while its AzureCluster has deletion protection
+// enabled, meaning that the Azure resources of the cluster must be left in place.
+func IsDeletionProtected(clusterScope *scope.ClusterScope) bool {
+	return clusterScope.AzureCluster.Spec.DeletionProtection && !clusterScope.Cluster.DeletionTimestamp.IsZero()
+}
+
 // GetClusterIdentityFromRef returns the AzureClusterIdentity referenced by the AzureCluster.
 func GetClusterIdentityFromRef(ctx context.Context, c client.Client, azureClusterNamespace string, ref *corev1.ObjectReference) (*infrav1.AzureClusterIdentity, error) {
 	identity := &infrav1.AzureClusterIdentity{}
@@ -1062,6 +1117,36 @@ func ClusterUpdatePauseChange(logger logr.Logger) predicate.Funcs {
 	}
 }
 
+// AzureClusterCloudProviderConfigOverridesChange returns a predicate that returns true for an update event when an
+// AzureCluster's Spec.CloudProviderConfigOverrides changes.
+func AzureClusterCloudProviderConfigOverridesChange(logger logr.Logger) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			log := logger.WithValues("predicate", "AzureClusterCloudProviderConfigOverridesChange", "eventType", "update")
+
+			oldAzureCluster, ok := e.ObjectOld.(*infrav1.AzureCluster)
+			if !ok {
+				log.V(4).Info("Expected AzureCluster", "type", fmt.Sprintf("%T", e.ObjectOld))
+				return false
+			}
+			log = log.WithValues("AzureCluster", klog.KObj(oldAzureCluster))
+
+			newAzureCluster := e.ObjectNew.(*infrav1.AzureCluster)
+
+			if !equality.Semantic.DeepEqual(oldAzureCluster.Spec.CloudProviderConfigOverrides, newAzureCluster.Spec.CloudProviderConfigOverrides) {
+				log.V(4).Info("AzureCluster cloud provider config overrides changed, allowing further processing")
+				return true
+			}
+
+			log.V(6).Info("AzureCluster cloud provider config overrides remained the same, blocking further processing")
+			return false
+		},
+		CreateFunc:  func(e event.CreateEvent) bool { return false },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+}
+
 // ClusterPauseChangeAndInfrastructureReady is based on ClusterUnpausedAndInfrastructureReady, but
 // additionally accepts Cluster pause events.
 func ClusterPauseChangeAndInfrastructureReady(log logr.Logger) predicate.Funcs {
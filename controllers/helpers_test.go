@@ -156,6 +156,13 @@ func TestGetCloudProviderConfig(t *testing.T) {
 			expectedControlPlaneConfig: backOffCloudConfig,
 			expectedWorkerNodeConfig:   backOffCloudConfig,
 		},
+		"with load balancer overrides": {
+			cluster:                    cluster,
+			azureCluster:               withLoadBalancerOverrides(*azureCluster),
+			identityType:               infrav1.VMIdentityNone,
+			expectedControlPlaneConfig: loadBalancerOverridesCloudConfig,
+			expectedWorkerNodeConfig:   loadBalancerOverridesCloudConfig,
+		},
 		"with machinepools": {
 			cluster:                    cluster,
 			azureCluster:               azureCluster,
@@ -432,6 +439,16 @@ func withbackOffConfig(ac infrav1.AzureCluster) *infrav1.AzureCluster {
 	return &ac
 }
 
+func withLoadBalancerOverrides(ac infrav1.AzureCluster) *infrav1.AzureCluster {
+	ac.Spec.CloudProviderConfigOverrides = &infrav1.CloudProviderConfigOverrides{
+		LoadBalancerSKU:              "Basic",
+		UseInstanceMetadata:          ptr.To(false),
+		ExcludeMasterFromStandardLB:  ptr.To(false),
+		MaximumLoadBalancerRuleCount: ptr.To(100),
+	}
+	return &ac
+}
+
 func newAzureClusterWithCustomVnet(location string) *infrav1.AzureCluster {
 	return &infrav1.AzureCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -711,6 +728,28 @@ const (
     "cloudProviderBackoffExponent": 1.2000000000000002,
     "cloudProviderBackoffDuration": 60,
     "cloudProviderBackoffJitter": 1.2000000000000002
+}`
+	loadBalancerOverridesCloudConfig = `{
+    "cloud": "AzurePublicCloud",
+    "tenantId": "fooTenant",
+    "subscriptionId": "baz",
+    "aadClientId": "fooClient",
+    "aadClientSecret": "fooSecret",
+    "resourceGroup": "bar",
+    "securityGroupName": "foo-node-nsg",
+    "securityGroupResourceGroup": "bar",
+    "location": "bar",
+    "vmType": "vmss",
+    "vnetName": "foo-vnet",
+    "vnetResourceGroup": "bar",
+    "subnetName": "foo-node-subnet",
+    "routeTableName": "foo-node-routetable",
+    "loadBalancerSku": "Basic",
+    "loadBalancerName": "",
+    "maximumLoadBalancerRuleCount": 100,
+    "useManagedIdentityExtension": false,
+    "useInstanceMetadata": false,
+    "excludeMasterFromStandardLB": false
 }`
 	vmssCloudConfig = `{
     "cloud": "AzurePublicCloud",
@@ -1522,3 +1561,92 @@ func TestClusterPauseChangeAndInfrastructureReady(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureClusterCloudProviderConfigOverridesChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		event  any // an event.(Create|Update)Event
+		expect bool
+	}{
+		{
+			name: "create azure cluster",
+			event: event.CreateEvent{
+				Object: &infrav1.AzureCluster{},
+			},
+			expect: false,
+		},
+		{
+			name: "update azure cluster overrides unchanged",
+			event: event.UpdateEvent{
+				ObjectOld: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+							CloudProviderConfigOverrides: &infrav1.CloudProviderConfigOverrides{LoadBalancerSKU: "Standard"},
+						},
+					},
+				},
+				ObjectNew: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+							CloudProviderConfigOverrides: &infrav1.CloudProviderConfigOverrides{LoadBalancerSKU: "Standard"},
+						},
+						ResourceGroup: "changed",
+					},
+				},
+			},
+			expect: false,
+		},
+		{
+			name: "update azure cluster overrides added",
+			event: event.UpdateEvent{
+				ObjectOld: &infrav1.AzureCluster{},
+				ObjectNew: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+							CloudProviderConfigOverrides: &infrav1.CloudProviderConfigOverrides{UseInstanceMetadata: ptr.To(false)},
+						},
+					},
+				},
+			},
+			expect: true,
+		},
+		{
+			name: "update azure cluster overrides changed",
+			event: event.UpdateEvent{
+				ObjectOld: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+							CloudProviderConfigOverrides: &infrav1.CloudProviderConfigOverrides{MaximumLoadBalancerRuleCount: ptr.To(250)},
+						},
+					},
+				},
+				ObjectNew: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+							CloudProviderConfigOverrides: &infrav1.CloudProviderConfigOverrides{MaximumLoadBalancerRuleCount: ptr.To(100)},
+						},
+					},
+				},
+			},
+			expect: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			p := AzureClusterCloudProviderConfigOverridesChange(logr.New(nil))
+			var actual bool
+			switch e := test.event.(type) {
+			case event.CreateEvent:
+				actual = p.Create(e)
+			case event.UpdateEvent:
+				actual = p.Update(e)
+			default:
+				panic("unimplemented event type")
+			}
+			NewGomegaWithT(t).Expect(actual).To(Equal(test.expect))
+		})
+	}
+}
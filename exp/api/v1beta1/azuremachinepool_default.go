@@ -112,8 +112,13 @@ func (amp *AzureMachinePool) SetSpotEvictionPolicyDefaults() {
 	}
 }
 
-// SetDiagnosticsDefaults sets the defaults for Diagnostic settings for an AzureMachinePool.
+// SetDiagnosticsDefaults sets the defaults for Diagnostic settings for an AzureMachinePool. The boot diagnostics of a
+// pool with its own diagnostics storage account are set by CAPZ, so they aren't defaulted.
 func (amp *AzureMachinePool) SetDiagnosticsDefaults() {
+	if amp.Spec.DiagnosticsStorageAccount != nil {
+		return
+	}
+
 	bootDefault := &infrav1.BootDiagnostics{
 		StorageAccountType: infrav1.ManagedDiagnosticsStorage,
 	}
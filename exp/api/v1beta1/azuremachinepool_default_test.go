@@ -200,6 +200,14 @@ func TestAzureMachinePool_SetDiagnosticsDefaults(t *testing.T) {
 
 	nilDiagnostics.machinePool.SetDiagnosticsDefaults()
 	g.Expect(nilDiagnostics.machinePool.Spec.Template.Diagnostics.Boot.StorageAccountType).To(Equal(infrav1.ManagedDiagnosticsStorage))
+
+	diagnosticsStorageAccount := test{machinePool: &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			DiagnosticsStorageAccount: &AzureMachinePoolDiagnosticsStorageAccount{SKU: "Standard_LRS"},
+		},
+	}}
+	diagnosticsStorageAccount.machinePool.SetDiagnosticsDefaults()
+	g.Expect(diagnosticsStorageAccount.machinePool.Spec.Template.Diagnostics).To(BeNil())
 }
 
 func TestAzureMachinePool_SetSpotEvictionPolicyDefaults(t *testing.T) {
@@ -39,6 +39,50 @@ const (
 	NewestDeletePolicyType AzureMachinePoolDeletePolicyType = "Newest"
 	// RandomDeletePolicyType will delete machines in random order.
 	RandomDeletePolicyType AzureMachinePoolDeletePolicyType = "Random"
+
+	// RecreateReplacePolicyType will delete machines without the latest model and let the scale set create new ones.
+	RecreateReplacePolicyType AzureMachinePoolReplacePolicyType = "Recreate"
+	// ReimageReplacePolicyType will update machines without the latest model in place.
+	ReimageReplacePolicyType AzureMachinePoolReplacePolicyType = "Reimage"
+
+	// LoadBalancerOutboundType makes the instances egress through the cluster's node outbound load balancer.
+	LoadBalancerOutboundType AzureMachinePoolOutboundType = "LoadBalancer"
+	// NATGatewayOutboundType makes the instances egress through the NAT gateway of their subnet.
+	NATGatewayOutboundType AzureMachinePoolOutboundType = "NATGateway"
+	// NoneOutboundType leaves the instances without outbound connectivity set up by CAPZ.
+	NoneOutboundType AzureMachinePoolOutboundType = "None"
+
+	// ManualUpgradeMode leaves the instances without the latest model to CAPZ, which replaces them according to the
+	// deployment strategy.
+	ManualUpgradeMode AzureMachinePoolUpgradeMode = "Manual"
+	// AutomaticUpgradeMode makes Azure update all the instances to the latest model at once.
+	AutomaticUpgradeMode AzureMachinePoolUpgradeMode = "Automatic"
+	// RollingUpgradeMode makes Azure update the instances to the latest model in batches.
+	RollingUpgradeMode AzureMachinePoolUpgradeMode = "Rolling"
+
+	// HTTPHealthProbeProtocol probes the health of the instances with an HTTP request.
+	HTTPHealthProbeProtocol AzureMachinePoolHealthProbeProtocol = "http"
+	// HTTPSHealthProbeProtocol probes the health of the instances with an HTTPS request.
+	HTTPSHealthProbeProtocol AzureMachinePoolHealthProbeProtocol = "https"
+	// TCPHealthProbeProtocol probes the health of the instances by opening a TCP connection.
+	TCPHealthProbeProtocol AzureMachinePoolHealthProbeProtocol = "tcp"
+
+	// DefaultScaleInRule balances the scale set across zones and fault domains, then removes the newest instances.
+	DefaultScaleInRule AzureMachinePoolScaleInRule = "Default"
+	// OldestVMScaleInRule removes the oldest instances, after balancing the scale set across zones.
+	OldestVMScaleInRule AzureMachinePoolScaleInRule = "OldestVM"
+	// NewestVMScaleInRule removes the newest instances, after balancing the scale set across zones.
+	NewestVMScaleInRule AzureMachinePoolScaleInRule = "NewestVM"
+
+	// RunningStandbyPoolVMState keeps the instances of a standby pool running, so they join the scale set the fastest.
+	RunningStandbyPoolVMState AzureMachinePoolStandbyPoolVMState = "Running"
+	// DeallocatedStandbyPoolVMState keeps the instances of a standby pool deallocated, so they don't incur compute costs.
+	DeallocatedStandbyPoolVMState AzureMachinePoolStandbyPoolVMState = "Deallocated"
+
+	// NoneZoneRebalanceStrategy selects the instances deleted when scaling in by the delete policy only.
+	NoneZoneRebalanceStrategy AzureMachinePoolZoneRebalanceStrategy = "None"
+	// ScaleInZoneRebalanceStrategy deletes the instances from the zones with the most instances first when scaling in.
+	ScaleInZoneRebalanceStrategy AzureMachinePoolZoneRebalanceStrategy = "ScaleIn"
 )
 
 type (
@@ -48,6 +92,11 @@ type (
 		// See https://learn.microsoft.com/rest/api/compute/virtualmachines/createorupdate#virtualmachinesizetypes
 		VMSize string `json:"vmSize"`
 
+		// MinimumVMResources overrides the minimum vCPUs and memory the VM size must have, which default to 2 vCPUs
+		// and 2 GiB of memory. Lower them to use small burstable VM sizes, for instance in development clusters.
+		// +optional
+		MinimumVMResources *infrav1.MinimumVMResources `json:"minimumVMResources,omitempty"`
+
 		// Image is used to provide details of an image to use during VM creation.
 		// If image details are omitted the image will default the Azure Marketplace "capi" offer,
 		// which is based on Ubuntu.
@@ -67,6 +116,29 @@ type (
 		// +optional
 		SSHPublicKey string `json:"sshPublicKey"`
 
+		// AdditionalSSHPublicKeys is a list of SSH public key strings, base64-encoded, authorized in addition to the
+		// SSHPublicKey, such as break-glass or team keys. Linux only.
+		// +optional
+		AdditionalSSHPublicKeys []string `json:"additionalSSHPublicKeys,omitempty"`
+
+		// ComputerNamePrefix is the prefix of the OS computer names (hostnames) of the scale set instances, to which
+		// Azure appends 6 characters. When set, the name of the scale set resource is no longer shortened for Windows
+		// machine pools. Defaults to using the name of the scale set resource as prefix.
+		// The prefix can be up to 9 characters long for Windows machine pools and 58 characters long for Linux machine pools.
+		// Immutable.
+		// +kubebuilder:validation:Pattern=`^[a-z][-a-z0-9]*$`
+		// +optional
+		ComputerNamePrefix string `json:"computerNamePrefix,omitempty"`
+
+		// AdminUsername is the name of the administrator account of the scale set instances, to which the
+		// SSHPublicKey is authorized. Linux only. It must not be a name reserved by Azure, such as root or admin.
+		// Defaults to capi.
+		// Immutable.
+		// +kubebuilder:validation:Pattern=`^[a-z_][-a-z0-9_]*$`
+		// +kubebuilder:validation:MaxLength=32
+		// +optional
+		AdminUsername string `json:"adminUsername,omitempty"`
+
 		// Deprecated: AcceleratedNetworking should be set in the networkInterfaces field.
 		// +optional
 		AcceleratedNetworking *bool `json:"acceleratedNetworking,omitempty"`
@@ -89,6 +161,21 @@ type (
 		// +optional
 		SpotVMOptions *infrav1.SpotVMOptions `json:"spotVMOptions,omitempty"`
 
+		// VaultSecrets are the certificates from Key Vault to install on the instances of the scale set when they are
+		// provisioned, for instance to serve kubelet with a certificate or to trust a private certificate authority.
+		// +optional
+		VaultSecrets []infrav1.VaultSecretGroup `json:"vaultSecrets,omitempty"`
+
+		// VMGalleryApplications are the Azure Compute Gallery applications to install on the instances of the scale set
+		// when they are provisioned, such as monitoring agents, without having to use a custom script extension.
+		// +optional
+		VMGalleryApplications []infrav1.VMGalleryApplication `json:"vmGalleryApplications,omitempty"`
+
+		// BootstrapEncryption enables envelope encryption of the bootstrap data of the scale set with a Key Vault key.
+		// It is only supported for Linux machines bootstrapped with cloud-init.
+		// +optional
+		BootstrapEncryption *infrav1.BootstrapEncryption `json:"bootstrapEncryption,omitempty"`
+
 		// Deprecated: SubnetName should be set in the networkInterfaces field.
 		// +optional
 		SubnetName string `json:"subnetName,omitempty"`
@@ -97,12 +184,59 @@ type (
 		// +optional
 		VMExtensions []infrav1.VMExtension `json:"vmExtensions,omitempty"`
 
+		// EnableGPUDriverExtension installs the GPU driver VM extension on VM sizes with GPUs. It isn't needed when the
+		// image already contains the GPU driver, or when the driver is installed by other means, such as the NVIDIA GPU
+		// Operator.
+		// +optional
+		EnableGPUDriverExtension bool `json:"enableGPUDriverExtension,omitempty"`
+
+		// BootstrapExtension configures the CAPZ bootstrap VM extension, which reports whether the instances bootstrap
+		// successfully. If not specified, the extension waits up to 5 minutes for bootstrapping to complete.
+		// +optional
+		BootstrapExtension *AzureMachinePoolBootstrapExtension `json:"bootstrapExtension,omitempty"`
+
+		// EnableAutomaticExtensionUpgrade enables the automatic upgrade of the VM extensions installed by CAPZ, such as the
+		// bootstrap extension and the GPU driver extension, so that new versions of them, including security fixes, are
+		// rolled out by Azure without replacing the machines. Custom VM extensions use their own enableAutomaticUpgrade.
+		// +optional
+		EnableAutomaticExtensionUpgrade bool `json:"enableAutomaticExtensionUpgrade,omitempty"`
+
 		// NetworkInterfaces specifies a list of network interface configurations.
 		// If left unspecified, the VM will get a single network interface with a
 		// single IPConfig in the subnet specified in the cluster's node subnet field.
 		// The primary interface will be the first networkInterface specified (index 0) in the list.
 		// +optional
 		NetworkInterfaces []infrav1.NetworkInterface `json:"networkInterfaces,omitempty"`
+
+		// NodeLabels are the labels CAPZ adds to the nodes of the instances once they joined the cluster, for Linux and
+		// Windows machine pools alike. Labels removed from the list are not removed from existing nodes.
+		// +optional
+		NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+
+		// NodeTaints are the taints CAPZ adds to the nodes of the instances once they joined the cluster, for Linux and
+		// Windows machine pools alike. Taints that must be present before any pod is scheduled on the node should be set
+		// in the bootstrap config instead, e.g. in nodeRegistration.taints of a KubeadmConfig.
+		// Taints removed from the list are not removed from existing nodes.
+		// +optional
+		NodeTaints infrav1.Taints `json:"nodeTaints,omitempty"`
+	}
+
+	// AzureMachinePoolBootstrapExtension configures the CAPZ bootstrap VM extension of an AzureMachinePool.
+	AzureMachinePoolBootstrapExtension struct {
+		// Disabled disables the bootstrap extension, e.g. for images that report bootstrapping by their own means.
+		// The bootstrap status of the instances is then not reported.
+		// +optional
+		Disabled bool `json:"disabled,omitempty"`
+
+		// Timeout is how long the bootstrap extension waits for bootstrapping to complete before failing, which fails
+		// the provisioning of the instance. It must be at most 90 minutes. Defaults to 5 minutes.
+		// +optional
+		Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+		// RetryInterval is how often the bootstrap extension checks whether bootstrapping completed. It must be at least
+		// 1 second and at most Timeout. Defaults to 5 seconds.
+		// +optional
+		RetryInterval *metav1.Duration `json:"retryInterval,omitempty"`
 	}
 
 	// AzureMachinePoolSpec defines the desired state of AzureMachinePool.
@@ -166,8 +300,284 @@ type (
 		// OrchestrationMode specifies the orchestration mode for the Virtual Machine Scale Set
 		// +kubebuilder:default=Uniform
 		OrchestrationMode infrav1.OrchestrationModeType `json:"orchestrationMode,omitempty"`
+
+		// NodeOutboundRule is the name of an additional outbound rule of the cluster's node outbound load balancer.
+		// If set, the instances of the scale set egress through the frontend IPs of that rule instead of the default ones.
+		// Immutable.
+		// +optional
+		NodeOutboundRule string `json:"nodeOutboundRule,omitempty"`
+
+		// OutboundType is the egress path of the instances of the scale set. LoadBalancer joins the cluster's node
+		// outbound load balancer, NATGateway relies on the NAT gateway of the subnet of the scale set and None sets up
+		// no outbound connectivity. Defaults to the node outbound load balancer when the cluster has one.
+		// Immutable.
+		// +kubebuilder:validation:Enum=LoadBalancer;NATGateway;None
+		// +optional
+		OutboundType AzureMachinePoolOutboundType `json:"outboundType,omitempty"`
+
+		// Placement constrains how the instances of the scale set are spread across zones and fault domains, and
+		// the capacity they are allocated from. zoneRebalanceStrategy and instances can only be set for Flexible
+		// orchestration mode. Immutable, except for zoneRebalanceStrategy and instances.
+		// +optional
+		Placement *AzureMachinePoolPlacement `json:"placement,omitempty"`
+
+		// UpgradePolicy controls whether Azure updates the instances of a Uniform orchestration mode scale set to the
+		// latest model, instead of CAPZ replacing them according to the deployment strategy.
+		// +optional
+		UpgradePolicy *AzureMachinePoolUpgradePolicy `json:"upgradePolicy,omitempty"`
+
+		// AutomaticRepairs makes Azure replace the instances of the scale set that report being unhealthy through the
+		// Application Health extension, without waiting for MachineHealthCheck remediation.
+		// +optional
+		AutomaticRepairs *AzureMachinePoolAutomaticRepairs `json:"automaticRepairs,omitempty"`
+
+		// PriorityMixPolicy blends regular and Spot priority instances in a single Flexible orchestration mode scale set.
+		// It requires the template to set spotVMOptions, which apply to the Spot priority instances. Immutable.
+		// +optional
+		PriorityMixPolicy *AzureMachinePoolPriorityMixPolicy `json:"priorityMixPolicy,omitempty"`
+
+		// ScaleInPolicy selects the instances Azure removes when the capacity of the scale set is lowered without
+		// deleting specific instances, e.g. by an external autoscaler.
+		// +optional
+		ScaleInPolicy *AzureMachinePoolScaleInPolicy `json:"scaleInPolicy,omitempty"`
+
+		// StandbyPool attaches a standby pool of pre-provisioned instances to the scale set, from which Azure takes the
+		// instances added when the scale set scales out. It requires the Flexible orchestration mode.
+		// +optional
+		StandbyPool *AzureMachinePoolStandbyPool `json:"standbyPool,omitempty"`
+
+		// DiagnosticsStorageAccount makes CAPZ create a storage account dedicated to the boot diagnostics of the
+		// instances of the scale set, and delete it with the AzureMachinePool. It can't be set together with
+		// template.diagnostics.boot. Immutable.
+		// +optional
+		DiagnosticsStorageAccount *AzureMachinePoolDiagnosticsStorageAccount `json:"diagnosticsStorageAccount,omitempty"`
+	}
+
+	// AzureMachinePoolDiagnosticsStorageAccount defines the storage account CAPZ creates for the boot diagnostics of an
+	// AzureMachinePool.
+	AzureMachinePoolDiagnosticsStorageAccount struct {
+		// Name is the name of the storage account, which must be globally unique. Defaults to a name derived from the
+		// resource group of the cluster and the name of the AzureMachinePool.
+		// +kubebuilder:validation:Pattern=`^[a-z0-9]{3,24}$`
+		// +optional
+		Name string `json:"name,omitempty"`
+
+		// SKU is the redundancy of the storage account. Defaults to Standard_LRS.
+		// +kubebuilder:validation:Enum=Standard_LRS;Standard_ZRS;Standard_GRS
+		// +kubebuilder:default=Standard_LRS
+		// +optional
+		SKU string `json:"sku,omitempty"`
+	}
+
+	// AzureMachinePoolStandbyPoolVMState is the state the instances of a standby pool are kept in.
+	AzureMachinePoolStandbyPoolVMState string
+
+	// AzureMachinePoolStandbyPool defines the standby pool attached to the scale set of an AzureMachinePool.
+	AzureMachinePoolStandbyPool struct {
+		// MaxReadyCapacity is the maximum number of instances in the scale set and its standby pool combined.
+		// +kubebuilder:validation:Minimum=1
+		MaxReadyCapacity int64 `json:"maxReadyCapacity"`
+
+		// MinReadyCapacity is the minimum number of instances kept in the standby pool. It must not exceed
+		// maxReadyCapacity.
+		// +kubebuilder:validation:Minimum=0
+		// +optional
+		MinReadyCapacity *int64 `json:"minReadyCapacity,omitempty"`
+
+		// VirtualMachineState is the state the instances of the standby pool are kept in. Defaults to Deallocated.
+		// +kubebuilder:validation:Enum=Running;Deallocated
+		// +kubebuilder:default=Deallocated
+		// +optional
+		VirtualMachineState AzureMachinePoolStandbyPoolVMState `json:"virtualMachineState,omitempty"`
+	}
+
+	// AzureMachinePoolScaleInRule is the rule Azure follows to select the instances removed from a scale set.
+	AzureMachinePoolScaleInRule string
+
+	// AzureMachinePoolScaleInPolicy defines the scale-in policy of the scale set of an AzureMachinePool.
+	AzureMachinePoolScaleInPolicy struct {
+		// Rule is the rule used to select the instances to remove. Defaults to Default.
+		// +kubebuilder:validation:Enum=Default;OldestVM;NewestVM
+		// +kubebuilder:default=Default
+		// +optional
+		Rule AzureMachinePoolScaleInRule `json:"rule,omitempty"`
+
+		// ForceDeletion force deletes the instances selected for removal, which is faster but skips their graceful
+		// shutdown.
+		// +optional
+		ForceDeletion bool `json:"forceDeletion,omitempty"`
+	}
+
+	// AzureMachinePoolPriorityMixPolicy defines the mix of regular and Spot priority instances of the scale set of an
+	// AzureMachinePool.
+	AzureMachinePoolPriorityMixPolicy struct {
+		// BaseRegularPriorityCount is the number of regular priority instances created before any Spot priority
+		// instance when the scale set scales out. Defaults to 0.
+		// +kubebuilder:validation:Minimum=0
+		// +optional
+		BaseRegularPriorityCount *int32 `json:"baseRegularPriorityCount,omitempty"`
+
+		// RegularPriorityPercentageAboveBase is the percentage of regular priority instances among the instances
+		// created once the base regular priority count is reached. Defaults to 0.
+		// +kubebuilder:validation:Minimum=0
+		// +kubebuilder:validation:Maximum=100
+		// +optional
+		RegularPriorityPercentageAboveBase *int32 `json:"regularPriorityPercentageAboveBase,omitempty"`
 	}
 
+	// AzureMachinePoolAutomaticRepairs configures the automatic instance repairs of the scale set of an AzureMachinePool.
+	AzureMachinePoolAutomaticRepairs struct {
+		// Enabled enables automatic instance repairs and installs the Application Health extension on the instances.
+		Enabled bool `json:"enabled"`
+
+		// GracePeriod is how long Azure waits after a change of the state of an instance before repairing it.
+		// It must be between 10 and 90 minutes. Defaults to 10 minutes.
+		// +optional
+		GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+
+		// HealthProbe is the endpoint the Application Health extension probes on each instance.
+		// Defaults to the health endpoint of the kubelet, http://localhost:10248/healthz. Setting it installs the
+		// Application Health extension even when automatic repairs aren't enabled, e.g. for the Rolling upgrade mode.
+		// +optional
+		HealthProbe *AzureMachinePoolHealthProbe `json:"healthProbe,omitempty"`
+	}
+
+	// AzureMachinePoolHealthProbeProtocol is the protocol of the Application Health extension probe.
+	AzureMachinePoolHealthProbeProtocol string
+
+	// AzureMachinePoolHealthProbe is the endpoint the Application Health extension probes on the instances of the
+	// scale set of an AzureMachinePool.
+	AzureMachinePoolHealthProbe struct {
+		// Protocol is the protocol of the probe.
+		// +kubebuilder:validation:Enum=http;https;tcp
+		// +kubebuilder:default=http
+		// +optional
+		Protocol AzureMachinePoolHealthProbeProtocol `json:"protocol,omitempty"`
+
+		// Port is the port probed on the instance.
+		// +kubebuilder:validation:Minimum=1
+		// +kubebuilder:validation:Maximum=65535
+		Port int32 `json:"port"`
+
+		// RequestPath is the path of the HTTP or HTTPS request of the probe. It can't be set when protocol is tcp.
+		// +optional
+		RequestPath string `json:"requestPath,omitempty"`
+
+		// GracePeriod is how long the extension reports an instance as initializing, instead of unhealthy, after its
+		// state changes, e.g. when it is created. It must be at most 4 hours.
+		// +optional
+		GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+	}
+
+	// AzureMachinePoolUpgradeMode is the mode of the upgrade policy of the scale set of an AzureMachinePool.
+	AzureMachinePoolUpgradeMode string
+
+	// AzureMachinePoolUpgradePolicy defines how the instances of the scale set of an AzureMachinePool are updated to
+	// the latest model.
+	AzureMachinePoolUpgradePolicy struct {
+		// Mode is the upgrade mode of the scale set. Manual leaves the instances without the latest model to CAPZ,
+		// Automatic makes Azure update all of them at once and Rolling makes Azure update them in batches.
+		// Rolling requires the instances to report their health through the Application Health extension, so
+		// automaticRepairs must be enabled or automaticRepairs.healthProbe set. Defaults to Manual.
+		// +kubebuilder:validation:Enum=Manual;Automatic;Rolling
+		// +kubebuilder:default=Manual
+		// +optional
+		Mode AzureMachinePoolUpgradeMode `json:"mode,omitempty"`
+
+		// RollingUpgrade configures the batches of a Rolling upgrade. It can only be set when mode is Rolling.
+		// +optional
+		RollingUpgrade *AzureMachinePoolRollingUpgradePolicy `json:"rollingUpgrade,omitempty"`
+
+		// EnableAutomaticOSUpgrade makes Azure upgrade the instances in rolling batches when a new version of their
+		// OS image is published. The image must reference the latest version of a marketplace or compute gallery
+		// image. It can't be enabled when mode is Manual, and requires automaticRepairs to be enabled or
+		// automaticRepairs.healthProbe to be set.
+		// +optional
+		EnableAutomaticOSUpgrade bool `json:"enableAutomaticOSUpgrade,omitempty"`
+	}
+
+	// AzureMachinePoolRollingUpgradePolicy configures the batches of a Rolling upgrade of the scale set of an
+	// AzureMachinePool.
+	AzureMachinePoolRollingUpgradePolicy struct {
+		// MaxBatchInstancePercent is the maximum percentage of the instances upgraded at once. Defaults to 20.
+		// +kubebuilder:validation:Minimum=5
+		// +kubebuilder:validation:Maximum=100
+		// +optional
+		MaxBatchInstancePercent *int32 `json:"maxBatchInstancePercent,omitempty"`
+
+		// PauseTime is how long Azure waits between batches. Defaults to 0.
+		// +optional
+		PauseTime *metav1.Duration `json:"pauseTime,omitempty"`
+	}
+
+	// AzureMachinePoolPlacement constrains the placement of the instances of the scale set of an AzureMachinePool.
+	AzureMachinePoolPlacement struct {
+		// ZoneBalance forces a strictly even distribution of the instances across the failure domains of the
+		// MachinePool. It can only be set when the MachinePool has more than one failure domain.
+		// +optional
+		ZoneBalance *bool `json:"zoneBalance,omitempty"`
+
+		// PlatformFaultDomainCount is the number of fault domains the instances are assigned to in a round-robin
+		// fashion. 1 lets Azure spread the instances across as many fault domains as possible. At most 3 for Flexible
+		// orchestration mode. Defaults to the number of failure domains of the MachinePool for Flexible orchestration
+		// mode, and to the Azure default for Uniform orchestration mode.
+		// +kubebuilder:validation:Minimum=1
+		// +kubebuilder:validation:Maximum=5
+		// +optional
+		PlatformFaultDomainCount *int32 `json:"platformFaultDomainCount,omitempty"`
+
+		// SinglePlacementGroup limits the scale set to a single placement group of at most 100 instances.
+		// Some VM sizes and regions require it to be enabled. It can only be enabled for Uniform orchestration mode.
+		// Defaults to false, which lets a scale set grow up to 1000 instances.
+		// +optional
+		SinglePlacementGroup *bool `json:"singlePlacementGroup,omitempty"`
+
+		// CapacityReservationGroupID is the resource ID of the capacity reservation group the instances of the scale
+		// set are allocated from. It can't be set for Spot VMs.
+		// +optional
+		CapacityReservationGroupID string `json:"capacityReservationGroupID,omitempty"`
+
+		// ZoneRebalanceStrategy is how CAPZ keeps the instances evenly distributed across the failure domains of the
+		// MachinePool after scale events. ScaleIn deletes the instances from the zones with the most instances first
+		// when scaling in. Defaults to None, which selects them by the delete policy of the strategy only.
+		// +kubebuilder:validation:Enum=None;ScaleIn
+		// +optional
+		ZoneRebalanceStrategy AzureMachinePoolZoneRebalanceStrategy `json:"zoneRebalanceStrategy,omitempty"`
+
+		// Instances are instances of a Flexible orchestration mode scale set that CAPZ creates individually, each
+		// pinned to a zone and a fault domain. They count toward the replicas of the MachinePool, and CAPZ recreates
+		// them from the latest model of the scale set when they are deleted.
+		// +listType=map
+		// +listMapKey=name
+		// +optional
+		Instances []AzureMachinePoolInstancePlacement `json:"instances,omitempty"`
+	}
+
+	// AzureMachinePoolInstancePlacement pins an instance of a Flexible orchestration mode scale set to a zone and a
+	// fault domain.
+	AzureMachinePoolInstancePlacement struct {
+		// Name identifies the instance. Its VM is named after the scale set and this name.
+		// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+		// +kubebuilder:validation:MaxLength=15
+		Name string `json:"name"`
+
+		// Zone is the availability zone of the instance. It must be one of the failure domains of the MachinePool.
+		// +optional
+		Zone string `json:"zone,omitempty"`
+
+		// PlatformFaultDomain is the fault domain of the instance. It must be lower than platformFaultDomainCount.
+		// +kubebuilder:validation:Minimum=0
+		// +optional
+		PlatformFaultDomain *int32 `json:"platformFaultDomain,omitempty"`
+	}
+
+	// AzureMachinePoolZoneRebalanceStrategy is how CAPZ keeps the instances of an AzureMachinePool balanced across
+	// zones.
+	AzureMachinePoolZoneRebalanceStrategy string
+
+	// AzureMachinePoolOutboundType is the egress path of the instances of an AzureMachinePool.
+	AzureMachinePoolOutboundType string
+
 	// AzureMachinePoolDeploymentStrategyType is the type of deployment strategy employed to rollout a new version of
 	// the AzureMachinePool.
 	AzureMachinePoolDeploymentStrategyType string
@@ -191,6 +601,10 @@ type (
 	// upgrade.
 	AzureMachinePoolDeletePolicyType string
 
+	// AzureMachinePoolReplacePolicyType is the type of ReplacePolicy employed to replace machines without the latest
+	// model during an upgrade.
+	AzureMachinePoolReplacePolicyType string
+
 	// MachineRollingUpdateDeployment is used to control the desired behavior of rolling update.
 	MachineRollingUpdateDeployment struct {
 		// The maximum number of machines that can be unavailable during the update.
@@ -233,6 +647,17 @@ type (
 		// +kubebuilder:validation:Enum=Random;Newest;Oldest
 		// +kubebuilder:default:=Oldest
 		DeletePolicy AzureMachinePoolDeletePolicyType `json:"deletePolicy,omitempty"`
+
+		// ReplacePolicy defines how machines without the latest model are replaced during an upgrade.
+		// "Recreate" deletes them so that the scale set creates new ones. "Reimage" updates them in place to the latest
+		// model, which preserves their names and private IPs and doesn't need surge capacity. When MaxUnavailable is 0,
+		// one machine at a time is reimaged. "Reimage" is only supported for Uniform orchestration mode.
+		// Valid values are "Recreate" and "Reimage"
+		// When no value is supplied, the default is Recreate
+		// +optional
+		// +kubebuilder:validation:Enum=Recreate;Reimage
+		// +kubebuilder:default:=Recreate
+		ReplacePolicy AzureMachinePoolReplacePolicyType `json:"replacePolicy,omitempty"`
 	}
 
 	// AzureMachinePoolStatus defines the observed state of AzureMachinePool.
@@ -308,6 +733,67 @@ type (
 		// next reconciliation loop.
 		// +optional
 		LongRunningOperationStates infrav1.Futures `json:"longRunningOperationStates,omitempty"`
+
+		// PatchStatus summarizes the OS patch status of the instances of the AzureMachinePool.
+		// It is only set when instances report a patch status, i.e. for Flexible orchestration mode.
+		// +optional
+		PatchStatus *AzureMachinePoolPatchStatus `json:"patchStatus,omitempty"`
+
+		// Rollout reports the progress of rolling out the latest VMSS model to the instances of the AzureMachinePool.
+		// +optional
+		Rollout *AzureMachinePoolRolloutStatus `json:"rollout,omitempty"`
+
+		// ReservationCoverage compares the instances of the AzureMachinePool with the reserved VM instances that apply
+		// to its subscription. It is only set when the ReservationCoverage feature gate is enabled.
+		// +optional
+		ReservationCoverage *AzureMachinePoolReservationCoverage `json:"reservationCoverage,omitempty"`
+	}
+
+	// AzureMachinePoolPatchStatus counts the instances of an AzureMachinePool by OS patch status.
+	AzureMachinePoolPatchStatus struct {
+		// Patched is the number of instances without available critical or security patches.
+		Patched int32 `json:"patched"`
+
+		// RebootPending is the number of instances that need to be rebooted to complete the installation of patches.
+		RebootPending int32 `json:"rebootPending"`
+
+		// Failed is the number of instances whose latest patch assessment or installation failed.
+		Failed int32 `json:"failed"`
+	}
+
+	// AzureMachinePoolRolloutStatus reports the progress of a VMSS model rollout, so that clients can wait for an
+	// upgrade of the AzureMachinePool to complete without querying Azure.
+	AzureMachinePoolRolloutStatus struct {
+		// ObservedModelGeneration is the generation of the AzureMachinePool the VMSS model was last reconciled for.
+		// The rollout of a change is complete once it matches metadata.generation and RemainingReplicas is 0.
+		ObservedModelGeneration int64 `json:"observedModelGeneration"`
+
+		// UpdatedReplicas is the number of instances running the latest VMSS model.
+		UpdatedReplicas int32 `json:"updatedReplicas"`
+
+		// ReadyUpdatedReplicas is the number of ready instances running the latest VMSS model.
+		ReadyUpdatedReplicas int32 `json:"readyUpdatedReplicas"`
+
+		// RemainingReplicas is the estimated number of instances that still have to be updated to the latest VMSS model.
+		RemainingReplicas int32 `json:"remainingReplicas"`
+	}
+
+	// AzureMachinePoolReservationCoverage compares the instances of an AzureMachinePool with the active reserved VM
+	// instances of the same size in the same location, so that platform teams can right-size their reservations.
+	AzureMachinePoolReservationCoverage struct {
+		// VMSize is the size of the instances of the AzureMachinePool.
+		VMSize string `json:"vmSize"`
+
+		// Instances is the number of instances of the AzureMachinePool.
+		Instances int32 `json:"instances"`
+
+		// ReservedInstances is the total quantity of the active reservations for VMSize in the location of the
+		// AzureMachinePool that apply to its subscription. Reservations are shared by all the matching VMs in their
+		// scope, including the ones outside of the AzureMachinePool.
+		ReservedInstances int32 `json:"reservedInstances"`
+
+		// Covered is true when ReservedInstances is at least Instances.
+		Covered bool `json:"covered"`
 	}
 
 	// AzureMachinePoolInstanceStatus provides status information for each instance in the VMSS.
@@ -349,6 +835,9 @@ type (
 	// +kubebuilder:printcolumn:name="MachinePool",type="string",priority=1,JSONPath=".metadata.ownerReferences[?(@.kind==\"MachinePool\")].name",description="MachinePool object to which this AzureMachinePool belongs"
 	// +kubebuilder:printcolumn:name="VMSS ID",type="string",priority=1,JSONPath=".spec.providerID",description="Azure VMSS ID"
 	// +kubebuilder:printcolumn:name="VM Size",type="string",priority=1,JSONPath=".spec.template.vmSize",description="Azure VM Size"
+	// +kubebuilder:printcolumn:name="Patched",type="integer",priority=1,JSONPath=".status.patchStatus.patched",description="Number of instances without available critical or security patches"
+	// +kubebuilder:printcolumn:name="Reboot Pending",type="integer",priority=1,JSONPath=".status.patchStatus.rebootPending",description="Number of instances pending a reboot to complete patch installation"
+	// +kubebuilder:printcolumn:name="Patch Failed",type="integer",priority=1,JSONPath=".status.patchStatus.failed",description="Number of instances whose latest patch assessment or installation failed"
 	// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of this AzureMachinePool"
 
 	// AzureMachinePool is the Schema for the azuremachinepools API.
@@ -20,15 +20,19 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
@@ -39,8 +43,10 @@ import (
 )
 
 // SetupAzureMachinePoolWebhookWithManager sets up and registers the webhook with the manager.
-func SetupAzureMachinePoolWebhookWithManager(mgr ctrl.Manager) error {
-	ampw := &azureMachinePoolWebhook{Client: mgr.GetClient()}
+// extensionVersions is optional; when it is nil, custom VM extensions are only checked before they are deployed.
+// costEstimator is optional; when it is nil, the estimated hourly cost is only annotated by the controller.
+func SetupAzureMachinePoolWebhookWithManager(mgr ctrl.Manager, extensionVersions infrav1.VMExtensionVersionsGetter, costEstimator infrav1.HourlyCostEstimator) error {
+	ampw := &azureMachinePoolWebhook{Client: mgr.GetClient(), ExtensionVersions: extensionVersions, CostEstimator: costEstimator}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&AzureMachinePool{}).
 		WithDefaulter(ampw).
@@ -52,7 +58,9 @@ func SetupAzureMachinePoolWebhookWithManager(mgr ctrl.Manager) error {
 
 // azureMachinePoolWebhook implements a validating and defaulting webhook for AzureMachinePool.
 type azureMachinePoolWebhook struct {
-	Client client.Client
+	Client            client.Client
+	ExtensionVersions infrav1.VMExtensionVersionsGetter
+	CostEstimator     infrav1.HourlyCostEstimator
 }
 
 // Default implements webhook.Defaulter so a webhook will be registered for the type.
@@ -61,7 +69,12 @@ func (ampw *azureMachinePoolWebhook) Default(ctx context.Context, obj runtime.Ob
 	if !ok {
 		return apierrors.NewBadRequest("expected an AzureMachinePool")
 	}
-	return amp.SetDefaults(ampw.Client)
+	if err := amp.SetDefaults(ampw.Client); err != nil {
+		return err
+	}
+	template := amp.Spec.Template
+	infrav1.SetEstimatedHourlyCost(ctx, ampw.CostEstimator, &amp.ObjectMeta, template.VMSize, template.OSDisk, template.SpotVMOptions != nil)
+	return nil
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachinepool,mutating=false,failurePolicy=fail,groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepools,versions=v1beta1,name=validation.azuremachinepool.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
@@ -80,7 +93,10 @@ func (ampw *azureMachinePoolWebhook) ValidateCreate(ctx context.Context, obj run
 			"can be set only if the MachinePool feature flag is enabled",
 		)
 	}
-	return nil, amp.Validate(nil, ampw.Client)
+	if err := amp.Validate(nil, ampw.Client); err != nil {
+		return nil, err
+	}
+	return ampw.validateVMExtensionVersions(ctx, amp)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -89,7 +105,24 @@ func (ampw *azureMachinePoolWebhook) ValidateUpdate(ctx context.Context, oldObj,
 	if !ok {
 		return nil, apierrors.NewBadRequest("expected an AzureMachinePool")
 	}
-	return nil, amp.Validate(oldObj, ampw.Client)
+	if err := amp.Validate(oldObj, ampw.Client); err != nil {
+		return nil, err
+	}
+	if old, ok := oldObj.(*AzureMachinePool); ok && reflect.DeepEqual(old.Spec.Template.VMExtensions, amp.Spec.Template.VMExtensions) {
+		return nil, nil
+	}
+	return ampw.validateVMExtensionVersions(ctx, amp)
+}
+
+// validateVMExtensionVersions checks that the publishers of the custom VM extensions of the AzureMachinePool offer them
+// in their versions.
+func (ampw *azureMachinePoolWebhook) validateVMExtensionVersions(ctx context.Context, amp *AzureMachinePool) (admission.Warnings, error) {
+	warnings, errs := infrav1.ValidateVMExtensionVersions(ctx, ampw.ExtensionVersions, amp.ObjectMeta, amp.Spec.Template.VMExtensions,
+		field.NewPath("spec", "template", "vmExtensions"))
+	if len(errs) > 0 {
+		return warnings, apierrors.NewInvalid(GroupVersion.WithKind("AzureMachinePool").GroupKind(), amp.Name, errs)
+	}
+	return warnings, nil
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
@@ -103,13 +136,34 @@ func (amp *AzureMachinePool) Validate(old runtime.Object, client client.Client)
 		amp.ValidateImage,
 		amp.ValidateTerminateNotificationTimeout,
 		amp.ValidateSSHKey,
+		amp.ValidateAdditionalSSHKeys,
+		amp.ValidateSecurityProfile,
 		amp.ValidateUserAssignedIdentity,
 		amp.ValidateDiagnostics,
+		amp.ValidateDiagnosticsStorageAccount(old),
 		amp.ValidateOrchestrationMode(client),
 		amp.ValidateStrategy(),
 		amp.ValidateSystemAssignedIdentity(old),
 		amp.ValidateSystemAssignedIdentityRole,
 		amp.ValidateNetwork,
+		amp.ValidateDiskDeletePolicy,
+		amp.ValidateSharedDisks,
+		amp.ValidateNodeOutboundRule(old),
+		amp.ValidateOutboundType(old),
+		amp.ValidatePlacement(old),
+		amp.ValidateUpgradePolicy,
+		amp.ValidateAutomaticRepairs,
+		amp.ValidatePriorityMixPolicy(old),
+		amp.ValidateStandbyPool,
+		amp.ValidateSpotRestorePolicy,
+		amp.ValidateComputerNamePrefix(old),
+		amp.ValidateAdminUsername(old),
+		amp.ValidateVaultSecrets,
+		amp.ValidateVMGalleryApplications,
+		amp.ValidateVMExtensions,
+		amp.ValidateBootstrapEncryption,
+		amp.ValidateBootstrapExtension,
+		amp.ValidateNodeLabelsAndTaints,
 	}
 
 	var errs []error
@@ -127,9 +181,354 @@ func (amp *AzureMachinePool) ValidateNetwork() error {
 	if (amp.Spec.Template.NetworkInterfaces != nil) && len(amp.Spec.Template.NetworkInterfaces) > 0 && amp.Spec.Template.SubnetName != "" {
 		return errors.New("cannot set both NetworkInterfaces and machine SubnetName")
 	}
+	for _, nic := range amp.Spec.Template.NetworkInterfaces {
+		if nic.ID != "" {
+			return errors.New("existing network interfaces are not supported for machine pools")
+		}
+		if nic.PublicIPPrefixID != "" {
+			if !nic.PublicIPPerInstance {
+				return errors.New("publicIPPrefixID requires publicIPPerInstance")
+			}
+			if _, err := azureutil.ParseResourceID(nic.PublicIPPrefixID); err != nil {
+				return errors.Wrap(err, "publicIPPrefixID must be the resource ID of a public IP prefix")
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateDiskDeletePolicy of an AzureMachinePool.
+// Disks of scale set instances are always deleted along with their instance, so they cannot be retained.
+func (amp *AzureMachinePool) ValidateDiskDeletePolicy() error {
+	if amp.Spec.Template.OSDisk.DeletePolicy == infrav1.DeletePolicyRetain {
+		return errors.New("osDisk deletePolicy Retain is not supported for machine pools")
+	}
+	for _, disk := range amp.Spec.Template.DataDisks {
+		if disk.DeletePolicy == infrav1.DeletePolicyRetain {
+			return errors.Errorf("dataDisks deletePolicy Retain is not supported for machine pools, found on disk %s", disk.NameSuffix)
+		}
+	}
+	return nil
+}
+
+// ValidateSharedDisks of an AzureMachinePool.
+// Scale set instances can only attach the data disks created along with them, so they can neither share a disk nor
+// attach an existing one.
+func (amp *AzureMachinePool) ValidateSharedDisks() error {
+	for _, disk := range amp.Spec.Template.DataDisks {
+		if disk.ManagedDisk != nil && disk.ManagedDisk.MaxShares != nil {
+			return errors.Errorf("dataDisks maxShares is not supported for machine pools, found on disk %s", disk.NameSuffix)
+		}
+		if disk.IsExisting() {
+			return errors.Errorf("dataDisks managedDiskID is not supported for machine pools, found on disk %s", disk.NameSuffix)
+		}
+	}
 	return nil
 }
 
+// ValidateNodeOutboundRule validates that the node outbound rule of an AzureMachinePool is not changed.
+func (amp *AzureMachinePool) ValidateNodeOutboundRule(old runtime.Object) func() error {
+	return func() error {
+		if old == nil {
+			return nil
+		}
+		oldMachinePool, ok := old.(*AzureMachinePool)
+		if !ok {
+			return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+				"AzureMachinePool", reflect.TypeOf(old))
+		}
+		if oldMachinePool.Spec.NodeOutboundRule != amp.Spec.NodeOutboundRule {
+			return errors.New("nodeOutboundRule is immutable")
+		}
+		return nil
+	}
+}
+
+// ValidateOutboundType validates that the outbound type of an AzureMachinePool is compatible with its node outbound
+// rule and is not changed.
+func (amp *AzureMachinePool) ValidateOutboundType(old runtime.Object) func() error {
+	return func() error {
+		if amp.Spec.NodeOutboundRule != "" && amp.Spec.OutboundType != "" && amp.Spec.OutboundType != LoadBalancerOutboundType {
+			return errors.Errorf("nodeOutboundRule can only be set with outboundType %s", LoadBalancerOutboundType)
+		}
+		if old == nil {
+			return nil
+		}
+		oldMachinePool, ok := old.(*AzureMachinePool)
+		if !ok {
+			return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+				"AzureMachinePool", reflect.TypeOf(old))
+		}
+		if oldMachinePool.Spec.OutboundType != amp.Spec.OutboundType {
+			return errors.New("outboundType is immutable")
+		}
+		return nil
+	}
+}
+
+// maxFlexiblePlatformFaultDomainCount is the maximum number of fault domains of a Flexible orchestration mode scale
+// set, while Uniform orchestration mode scale sets support up to 5.
+const maxFlexiblePlatformFaultDomainCount = 3
+
+// ValidatePlacement validates that the placement of an AzureMachinePool is supported by its orchestration mode, and
+// is not changed except for its zone rebalance strategy.
+func (amp *AzureMachinePool) ValidatePlacement(old runtime.Object) func() error {
+	return func() error {
+		placement := amp.Spec.Placement
+		if placement != nil && amp.Spec.OrchestrationMode == infrav1.FlexibleOrchestrationMode {
+			if ptr.Deref(placement.SinglePlacementGroup, false) {
+				return errors.New("placement.singlePlacementGroup can only be enabled for Uniform orchestration mode")
+			}
+			if ptr.Deref(placement.PlatformFaultDomainCount, 0) > maxFlexiblePlatformFaultDomainCount {
+				return errors.Errorf("placement.platformFaultDomainCount must be at most %d for Flexible orchestration mode, got %d",
+					maxFlexiblePlatformFaultDomainCount, *placement.PlatformFaultDomainCount)
+			}
+		}
+		if placement != nil && amp.Spec.OrchestrationMode != infrav1.FlexibleOrchestrationMode && placement.ZoneRebalanceStrategy != "" {
+			return errors.New("placement.zoneRebalanceStrategy is only supported for Flexible orchestration mode")
+		}
+		if placement != nil && len(placement.Instances) > 0 {
+			if err := amp.validatePinnedInstances(); err != nil {
+				return err
+			}
+		}
+		if placement != nil && placement.CapacityReservationGroupID != "" {
+			if _, err := azureutil.ParseResourceID(placement.CapacityReservationGroupID); err != nil {
+				return errors.Wrap(err, "placement.capacityReservationGroupID must be a valid resource ID")
+			}
+			if amp.Spec.Template.SpotVMOptions != nil {
+				return errors.New("placement.capacityReservationGroupID can't be set with spotVMOptions")
+			}
+		}
+		if old == nil {
+			return nil
+		}
+		oldMachinePool, ok := old.(*AzureMachinePool)
+		if !ok {
+			return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+				"AzureMachinePool", reflect.TypeOf(old))
+		}
+		if !reflect.DeepEqual(immutablePlacement(oldMachinePool.Spec.Placement), immutablePlacement(amp.Spec.Placement)) {
+			return errors.New("placement is immutable, except for zoneRebalanceStrategy and instances")
+		}
+		return nil
+	}
+}
+
+// validatePinnedInstances validates that pinned instances are only set for Flexible orchestration mode, that their
+// names are unique, and that their fault domains exist in the scale set.
+func (amp *AzureMachinePool) validatePinnedInstances() error {
+	placement := amp.Spec.Placement
+	if amp.Spec.OrchestrationMode != infrav1.FlexibleOrchestrationMode {
+		return errors.New("placement.instances is only supported for Flexible orchestration mode")
+	}
+	names := make(map[string]struct{}, len(placement.Instances))
+	for i, instance := range placement.Instances {
+		if _, ok := names[instance.Name]; ok {
+			return errors.Errorf("placement.instances[%d].name %q is not unique", i, instance.Name)
+		}
+		names[instance.Name] = struct{}{}
+		if instance.PlatformFaultDomain == nil {
+			continue
+		}
+		// Azure only lets an instance pick its fault domain when the scale set has a fixed number of them.
+		count := ptr.Deref(placement.PlatformFaultDomainCount, 0)
+		if count < 2 {
+			return errors.Errorf("placement.instances[%d].platformFaultDomain requires placement.platformFaultDomainCount to be greater than 1", i)
+		}
+		if *instance.PlatformFaultDomain < 0 || *instance.PlatformFaultDomain >= count {
+			return errors.Errorf("placement.instances[%d].platformFaultDomain must be lower than placement.platformFaultDomainCount %d, got %d",
+				i, count, *instance.PlatformFaultDomain)
+		}
+	}
+	return nil
+}
+
+// immutablePlacement returns the part of a placement that can't be changed, or nil if it is empty. The zone
+// rebalance strategy only affects how CAPZ scales in, and pinned instances are created and released individually,
+// so both can be changed at any time.
+func immutablePlacement(placement *AzureMachinePoolPlacement) *AzureMachinePoolPlacement {
+	if placement == nil {
+		return nil
+	}
+	immutable := placement.DeepCopy()
+	immutable.ZoneRebalanceStrategy = ""
+	immutable.Instances = nil
+	if reflect.DeepEqual(*immutable, AzureMachinePoolPlacement{}) {
+		return nil
+	}
+	return immutable
+}
+
+// ValidateUpgradePolicy validates that the upgrade policy of an AzureMachinePool is only set for Uniform orchestration
+// mode and that its settings match its mode.
+func (amp *AzureMachinePool) ValidateUpgradePolicy() error {
+	upgradePolicy := amp.Spec.UpgradePolicy
+	if upgradePolicy == nil {
+		return nil
+	}
+	if amp.Spec.OrchestrationMode == infrav1.FlexibleOrchestrationMode {
+		return errors.New("upgradePolicy is only supported for Uniform orchestration mode")
+	}
+	if upgradePolicy.RollingUpgrade != nil && upgradePolicy.Mode != RollingUpgradeMode {
+		return errors.Errorf("upgradePolicy.rollingUpgrade can only be set with mode %s", RollingUpgradeMode)
+	}
+	if upgradePolicy.EnableAutomaticOSUpgrade && (upgradePolicy.Mode == "" || upgradePolicy.Mode == ManualUpgradeMode) {
+		return errors.Errorf("upgradePolicy.enableAutomaticOSUpgrade can't be enabled with mode %s", ManualUpgradeMode)
+	}
+	if upgradePolicy.RollingUpgrade != nil && upgradePolicy.RollingUpgrade.PauseTime != nil && upgradePolicy.RollingUpgrade.PauseTime.Duration < 0 {
+		return errors.New("upgradePolicy.rollingUpgrade.pauseTime must not be negative")
+	}
+	// The instances of the scale set aren't behind a load balancer with a health probe, so Azure can only tell whether
+	// an upgraded batch is healthy through the Application Health extension.
+	if !amp.hasApplicationHealthExtension() {
+		if upgradePolicy.Mode == RollingUpgradeMode {
+			return errors.Errorf("upgradePolicy.mode %s requires automaticRepairs to be enabled or automaticRepairs.healthProbe to be set", RollingUpgradeMode)
+		}
+		if upgradePolicy.EnableAutomaticOSUpgrade {
+			return errors.New("upgradePolicy.enableAutomaticOSUpgrade requires automaticRepairs to be enabled or automaticRepairs.healthProbe to be set")
+		}
+	}
+	return nil
+}
+
+// hasApplicationHealthExtension returns true if the Application Health extension is installed on the instances of the
+// scale set of the AzureMachinePool.
+func (amp *AzureMachinePool) hasApplicationHealthExtension() bool {
+	automaticRepairs := amp.Spec.AutomaticRepairs
+	return automaticRepairs != nil && (automaticRepairs.Enabled || automaticRepairs.HealthProbe != nil)
+}
+
+// ValidateAutomaticRepairs validates the grace period and the health probe of the automatic repairs of an
+// AzureMachinePool.
+func (amp *AzureMachinePool) ValidateAutomaticRepairs() error {
+	automaticRepairs := amp.Spec.AutomaticRepairs
+	if automaticRepairs == nil {
+		return nil
+	}
+	if gracePeriod := automaticRepairs.GracePeriod; gracePeriod != nil && (gracePeriod.Duration < 10*time.Minute || gracePeriod.Duration > 90*time.Minute) {
+		return errors.Errorf("automaticRepairs.gracePeriod must be between 10m and 90m, got %s", gracePeriod.Duration)
+	}
+	healthProbe := automaticRepairs.HealthProbe
+	if healthProbe == nil {
+		return nil
+	}
+	if healthProbe.Protocol == TCPHealthProbeProtocol && healthProbe.RequestPath != "" {
+		return errors.Errorf("automaticRepairs.healthProbe.requestPath can't be set with protocol %s", TCPHealthProbeProtocol)
+	}
+	if gracePeriod := healthProbe.GracePeriod; gracePeriod != nil && (gracePeriod.Duration < 0 || gracePeriod.Duration > 4*time.Hour) {
+		return errors.Errorf("automaticRepairs.healthProbe.gracePeriod must be between 0 and 4h, got %s", gracePeriod.Duration)
+	}
+	return nil
+}
+
+// ValidatePriorityMixPolicy validates that the priority mix policy of an AzureMachinePool is only set for Flexible
+// orchestration mode with Spot VM options, and that it is not changed.
+func (amp *AzureMachinePool) ValidatePriorityMixPolicy(old runtime.Object) func() error {
+	return func() error {
+		if amp.Spec.PriorityMixPolicy != nil {
+			if amp.Spec.OrchestrationMode != infrav1.FlexibleOrchestrationMode {
+				return errors.New("priorityMixPolicy is only supported for Flexible orchestration mode")
+			}
+			if amp.Spec.Template.SpotVMOptions == nil {
+				return errors.New("priorityMixPolicy requires template.spotVMOptions to be set")
+			}
+		}
+		if old == nil {
+			return nil
+		}
+		oldMachinePool, ok := old.(*AzureMachinePool)
+		if !ok {
+			return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+				"AzureMachinePool", reflect.TypeOf(old))
+		}
+		if !reflect.DeepEqual(oldMachinePool.Spec.PriorityMixPolicy, amp.Spec.PriorityMixPolicy) {
+			return errors.New("priorityMixPolicy is immutable")
+		}
+		return nil
+	}
+}
+
+// ValidateStandbyPool validates that the standby pool of an AzureMachinePool is only set for Flexible orchestration
+// mode without Spot VM options, and that its minimum ready capacity doesn't exceed its maximum ready capacity.
+func (amp *AzureMachinePool) ValidateStandbyPool() error {
+	standbyPool := amp.Spec.StandbyPool
+	if standbyPool == nil {
+		return nil
+	}
+	if amp.Spec.OrchestrationMode != infrav1.FlexibleOrchestrationMode {
+		return errors.New("standbyPool is only supported for Flexible orchestration mode")
+	}
+	if amp.Spec.Template.SpotVMOptions != nil {
+		return errors.New("standbyPool can't be set together with template.spotVMOptions")
+	}
+	if standbyPool.MinReadyCapacity != nil && *standbyPool.MinReadyCapacity > standbyPool.MaxReadyCapacity {
+		return errors.Errorf("standbyPool.minReadyCapacity %d can't exceed standbyPool.maxReadyCapacity %d",
+			*standbyPool.MinReadyCapacity, standbyPool.MaxReadyCapacity)
+	}
+	return nil
+}
+
+// ValidateSpotRestorePolicy validates that the Spot restore policy of an AzureMachinePool isn't set for a scale set
+// limited to a single placement group and that its restore timeout is positive.
+func (amp *AzureMachinePool) ValidateSpotRestorePolicy() error {
+	spotVMOptions := amp.Spec.Template.SpotVMOptions
+	if spotVMOptions == nil || spotVMOptions.RestorePolicy == nil {
+		return nil
+	}
+	if placement := amp.Spec.Placement; placement != nil && ptr.Deref(placement.SinglePlacementGroup, false) {
+		return errors.New("template.spotVMOptions.restorePolicy can't be set with placement.singlePlacementGroup")
+	}
+	if restoreTimeout := spotVMOptions.RestorePolicy.RestoreTimeout; restoreTimeout != nil && restoreTimeout.Duration <= 0 {
+		return errors.Errorf("template.spotVMOptions.restorePolicy.restoreTimeout must be positive, got %s", restoreTimeout.Duration)
+	}
+	return nil
+}
+
+// ValidateComputerNamePrefix validates the computer name prefix of an AzureMachinePool and that it is not changed.
+func (amp *AzureMachinePool) ValidateComputerNamePrefix(old runtime.Object) func() error {
+	return func() error {
+		template := amp.Spec.Template
+		if errs := infrav1.ValidateComputerNamePrefix(template.ComputerNamePrefix, template.OSDisk.OSType, infrav1.ScaleSetComputerNameSuffixLength, field.NewPath("template", "computerNamePrefix")); len(errs) > 0 {
+			return errs.ToAggregate()
+		}
+		if old == nil {
+			return nil
+		}
+		oldMachinePool, ok := old.(*AzureMachinePool)
+		if !ok {
+			return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+				"AzureMachinePool", reflect.TypeOf(old))
+		}
+		if oldMachinePool.Spec.Template.ComputerNamePrefix != template.ComputerNamePrefix {
+			return errors.New("template.computerNamePrefix is immutable")
+		}
+		return nil
+	}
+}
+
+// ValidateAdminUsername validates the administrator user name of an AzureMachinePool and that it is not changed.
+func (amp *AzureMachinePool) ValidateAdminUsername(old runtime.Object) func() error {
+	return func() error {
+		template := amp.Spec.Template
+		if errs := infrav1.ValidateAdminUsername(template.AdminUsername, template.OSDisk.OSType, field.NewPath("template", "adminUsername")); len(errs) > 0 {
+			return errs.ToAggregate()
+		}
+		if old == nil {
+			return nil
+		}
+		oldMachinePool, ok := old.(*AzureMachinePool)
+		if !ok {
+			return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+				"AzureMachinePool", reflect.TypeOf(old))
+		}
+		if oldMachinePool.Spec.Template.AdminUsername != template.AdminUsername {
+			return errors.New("template.adminUsername is immutable")
+		}
+		return nil
+	}
+}
+
 // ValidateImage of an AzureMachinePool.
 func (amp *AzureMachinePool) ValidateImage() error {
 	if amp.Spec.Template.Image != nil {
@@ -138,6 +537,10 @@ func (amp *AzureMachinePool) ValidateImage() error {
 			agg := kerrors.NewAggregate(errs.ToAggregate().Errors())
 			return agg
 		}
+		if errs := infrav1.ValidateImageVariant(image, amp.Spec.Template.OSDisk.OSType, field.NewPath("image", "variant")); len(errs) > 0 {
+			agg := kerrors.NewAggregate(errs.ToAggregate().Errors())
+			return agg
+		}
 	}
 
 	return nil
@@ -159,6 +562,26 @@ func (amp *AzureMachinePool) ValidateTerminateNotificationTimeout() error {
 	return nil
 }
 
+// ValidateSecurityProfile validates the security profile of an AzureMachinePool.
+func (amp *AzureMachinePool) ValidateSecurityProfile() error {
+	template := amp.Spec.Template
+	if errs := infrav1.ValidateSecurityProfile(template.OSDisk.ManagedDisk, template.SecurityProfile, field.NewPath("template", "securityProfile")); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+
+	return nil
+}
+
+// ValidateAdditionalSSHKeys validates the SSH keys authorized in addition to the SSHPublicKey.
+func (amp *AzureMachinePool) ValidateAdditionalSSHKeys() error {
+	template := amp.Spec.Template
+	if errs := infrav1.ValidateAdditionalSSHKeys(template.SSHPublicKey, template.AdditionalSSHPublicKeys, field.NewPath("template", "additionalSSHPublicKeys")); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+
+	return nil
+}
+
 // ValidateSSHKey validates an SSHKey.
 func (amp *AzureMachinePool) ValidateSSHKey() error {
 	if amp.Spec.Template.SSHPublicKey != "" {
@@ -182,23 +605,140 @@ func (amp *AzureMachinePool) ValidateUserAssignedIdentity() error {
 	return nil
 }
 
+// ValidateVaultSecrets validates the Key Vault certificates to install on the scale set instances.
+func (amp *AzureMachinePool) ValidateVaultSecrets() error {
+	fldPath := field.NewPath("vaultSecrets")
+	if errs := infrav1.ValidateVaultSecrets(amp.Spec.Template.OSDisk.OSType, amp.Spec.Template.VaultSecrets, fldPath); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateVMGalleryApplications validates the gallery applications to install on the scale set instances.
+func (amp *AzureMachinePool) ValidateVMGalleryApplications() error {
+	fldPath := field.NewPath("vmGalleryApplications")
+	if errs := infrav1.ValidateVMGalleryApplications(amp.Spec.Template.VMGalleryApplications, fldPath); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateVMExtensions validates the custom VM extensions of the scale set.
+func (amp *AzureMachinePool) ValidateVMExtensions() error {
+	fldPath := field.NewPath("vmExtensions")
+	if errs := infrav1.ValidateVMExtensions(amp.Spec.Template.VMExtensions, fldPath); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateBootstrapEncryption validates the envelope encryption of the bootstrap data of the scale set.
+func (amp *AzureMachinePool) ValidateBootstrapEncryption() error {
+	fldPath := field.NewPath("bootstrapEncryption")
+	if errs := infrav1.ValidateBootstrapEncryption(amp.Spec.Template.OSDisk.OSType, amp.Spec.Identity, amp.Spec.Template.BootstrapEncryption, fldPath); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// maxBootstrapExtensionTimeout is the maximum time the bootstrap extension can run, which is the limit of the custom
+// script extension it is based on.
+const maxBootstrapExtensionTimeout = 90 * time.Minute
+
+// ValidateBootstrapExtension validates the timeout and retry interval of the bootstrap extension.
+func (amp *AzureMachinePool) ValidateBootstrapExtension() error {
+	bootstrapExtension := amp.Spec.Template.BootstrapExtension
+	if bootstrapExtension == nil {
+		return nil
+	}
+	timeout, retryInterval := bootstrapExtension.Timeout, bootstrapExtension.RetryInterval
+	if bootstrapExtension.Disabled && (timeout != nil || retryInterval != nil) {
+		return errors.New("template.bootstrapExtension.timeout and retryInterval can't be set when the bootstrap extension is disabled")
+	}
+	if timeout != nil && (timeout.Duration <= 0 || timeout.Duration > maxBootstrapExtensionTimeout) {
+		return errors.Errorf("template.bootstrapExtension.timeout must be positive and at most %s, got %s", maxBootstrapExtensionTimeout, timeout.Duration)
+	}
+	if retryInterval != nil && retryInterval.Duration < time.Second {
+		return errors.Errorf("template.bootstrapExtension.retryInterval must be at least 1s, got %s", retryInterval.Duration)
+	}
+	if timeout != nil && retryInterval != nil && retryInterval.Duration > timeout.Duration {
+		return errors.Errorf("template.bootstrapExtension.retryInterval must be at most the timeout %s, got %s", timeout.Duration, retryInterval.Duration)
+	}
+	return nil
+}
+
+// ValidateNodeLabelsAndTaints validates the labels and taints added to the nodes of an AzureMachinePool.
+func (amp *AzureMachinePool) ValidateNodeLabelsAndTaints() error {
+	template := amp.Spec.Template
+	fldPath := field.NewPath("template")
+	allErrs := metav1validation.ValidateLabels(template.NodeLabels, fldPath.Child("nodeLabels"))
+	for i, taint := range template.NodeTaints {
+		taintPath := fldPath.Child("nodeTaints").Index(i)
+		for _, msg := range validation.IsQualifiedName(taint.Key) {
+			allErrs = append(allErrs, field.Invalid(taintPath.Child("key"), taint.Key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(taint.Value) {
+			allErrs = append(allErrs, field.Invalid(taintPath.Child("value"), taint.Value, msg))
+		}
+		for _, other := range template.NodeTaints[:i] {
+			if other.Key == taint.Key && other.Effect == taint.Effect {
+				allErrs = append(allErrs, field.Duplicate(taintPath, taint.Key))
+			}
+		}
+	}
+	return allErrs.ToAggregate()
+}
+
 // ValidateStrategy validates the strategy.
 func (amp *AzureMachinePool) ValidateStrategy() func() error {
 	return func() error {
 		if amp.Spec.Strategy.Type == RollingUpdateAzureMachinePoolDeploymentStrategyType && amp.Spec.Strategy.RollingUpdate != nil {
 			rollingUpdateStrategy := amp.Spec.Strategy.RollingUpdate
-			maxSurge := rollingUpdateStrategy.MaxSurge
-			maxUnavailable := rollingUpdateStrategy.MaxUnavailable
-			if maxSurge.Type == intstr.Int && maxSurge.IntVal == 0 &&
-				maxUnavailable.Type == intstr.Int && maxUnavailable.IntVal == 0 {
+			maxSurge, err := intOrPercentValue(rollingUpdateStrategy.MaxSurge, 1, "MaxSurge")
+			if err != nil {
+				return err
+			}
+			maxUnavailable, err := intOrPercentValue(rollingUpdateStrategy.MaxUnavailable, 0, "MaxUnavailable")
+			if err != nil {
+				return err
+			}
+			if rollingUpdateStrategy.MaxUnavailable != nil && rollingUpdateStrategy.MaxUnavailable.Type == intstr.String && maxUnavailable > 100 {
+				return errors.Errorf("rolling update strategy MaxUnavailable must not be greater than 100%%, got %s", rollingUpdateStrategy.MaxUnavailable.StrVal)
+			}
+			if maxSurge == 0 && maxUnavailable == 0 {
 				return errors.New("rolling update strategy MaxUnavailable must not be 0 if MaxSurge is 0")
 			}
+			if rollingUpdateStrategy.ReplacePolicy == ReimageReplacePolicyType &&
+				amp.Spec.OrchestrationMode == infrav1.FlexibleOrchestrationMode {
+				return errors.New("rolling update strategy ReplacePolicy Reimage is not supported for Flexible orchestration mode")
+			}
 		}
 
 		return nil
 	}
 }
 
+// intOrPercentValue returns the value of a MaxSurge or MaxUnavailable of a rolling update strategy, as a number or a
+// percentage, or its default value if it is unset, and validates that it is not negative.
+func intOrPercentValue(value *intstr.IntOrString, defaultValue int, name string) (int, error) {
+	if value == nil {
+		return defaultValue, nil
+	}
+	// percentages are scaled against 100 replicas so that their value is the percentage itself
+	v, err := intstr.GetScaledValueFromIntOrPercent(value, 100, true)
+	if err != nil {
+		return 0, errors.Wrapf(err, "rolling update strategy %s must be a number or a percentage", name)
+	}
+	if v < 0 {
+		return 0, errors.Errorf("rolling update strategy %s must not be negative, got %s", name, value.String())
+	}
+	return v, nil
+}
+
 // ValidateSystemAssignedIdentity validates system-assigned identity role.
 func (amp *AzureMachinePool) ValidateSystemAssignedIdentity(old runtime.Object) func() error {
 	return func() error {
@@ -294,6 +834,28 @@ func (amp *AzureMachinePool) ValidateDiagnostics() error {
 	return nil
 }
 
+// ValidateDiagnosticsStorageAccount validates that the diagnostics storage account of an AzureMachinePool doesn't
+// conflict with the boot diagnostics of its template and is not changed.
+func (amp *AzureMachinePool) ValidateDiagnosticsStorageAccount(old runtime.Object) func() error {
+	return func() error {
+		if amp.Spec.DiagnosticsStorageAccount != nil && amp.Spec.Template.Diagnostics != nil && amp.Spec.Template.Diagnostics.Boot != nil {
+			return errors.New("diagnosticsStorageAccount can't be set together with template.diagnostics.boot")
+		}
+		if old == nil {
+			return nil
+		}
+		oldMachinePool, ok := old.(*AzureMachinePool)
+		if !ok {
+			return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+				"AzureMachinePool", reflect.TypeOf(old))
+		}
+		if !reflect.DeepEqual(oldMachinePool.Spec.DiagnosticsStorageAccount, amp.Spec.DiagnosticsStorageAccount) {
+			return errors.New("diagnosticsStorageAccount is immutable")
+		}
+		return nil
+	}
+}
+
 // ValidateOrchestrationMode validates requirements for the VMSS orchestration mode.
 func (amp *AzureMachinePool) ValidateOrchestrationMode(c client.Client) func() error {
 	return func() error {
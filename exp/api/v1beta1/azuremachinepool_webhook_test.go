@@ -23,6 +23,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
 	guuid "github.com/google/uuid"
@@ -31,6 +32,7 @@ import (
 	"golang.org/x/crypto/ssh"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	utilfeature "k8s.io/component-base/featuregate/testing"
@@ -205,49 +207,1130 @@ func TestAzureMachinePool_ValidateCreate(t *testing.T) {
 			}),
 			wantErr: false,
 		},
+		{
+			name: "azuremachinepool with Reimage replace policy",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxSurge:       &zero,
+					MaxUnavailable: &one,
+					ReplacePolicy:  ReimageReplacePolicyType,
+				},
+			}),
+			wantErr: false,
+		},
 		{
 			name:    "azuremachinepool with valid legacy network configuration",
 			amp:     createMachinePoolWithNetworkConfig("testSubnet", []infrav1.NetworkInterface{}),
 			wantErr: false,
 		},
 		{
-			name:    "azuremachinepool with invalid legacy network configuration",
-			amp:     createMachinePoolWithNetworkConfig("testSubnet", []infrav1.NetworkInterface{{SubnetName: "testSubnet"}}),
-			wantErr: true,
+			name:    "azuremachinepool with invalid legacy network configuration",
+			amp:     createMachinePoolWithNetworkConfig("testSubnet", []infrav1.NetworkInterface{{SubnetName: "testSubnet"}}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with valid networkinterface configuration",
+			amp:     createMachinePoolWithNetworkConfig("", []infrav1.NetworkInterface{{SubnetName: "testSubnet"}}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with public IP per instance",
+			amp:     createMachinePoolWithNetworkConfig("", []infrav1.NetworkInterface{{SubnetName: "testSubnet", PublicIPPerInstance: true, PublicIPPrefixID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPPrefixes/my-prefix"}}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with public IP prefix without public IP per instance",
+			amp:     createMachinePoolWithNetworkConfig("", []infrav1.NetworkInterface{{SubnetName: "testSubnet", PublicIPPrefixID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPPrefixes/my-prefix"}}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with invalid public IP prefix ID",
+			amp:     createMachinePoolWithNetworkConfig("", []infrav1.NetworkInterface{{SubnetName: "testSubnet", PublicIPPerInstance: true, PublicIPPrefixID: "my-prefix"}}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with Delete disk delete policy",
+			amp:     createMachinePoolWithDiskDeletePolicy(infrav1.DeletePolicyDelete, infrav1.DeletePolicyDelete),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with Retain os disk delete policy",
+			amp:     createMachinePoolWithDiskDeletePolicy(infrav1.DeletePolicyRetain, ""),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with Retain data disk delete policy",
+			amp:     createMachinePoolWithDiskDeletePolicy("", infrav1.DeletePolicyRetain),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with shared data disk",
+			amp:     createMachinePoolWithSharedDataDisk(),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with Flexible orchestration mode",
+			amp:     createMachinePoolWithOrchestrationMode(compute.OrchestrationModeFlexible),
+			version: "v1.26.0",
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with Flexible orchestration mode and invalid Kubernetes version",
+			amp:     createMachinePoolWithOrchestrationMode(compute.OrchestrationModeFlexible),
+			version: "v1.25.6",
+			wantErr: true,
+		},
+		{
+			name:          "azuremachinepool with Flexible orchestration mode and invalid Kubernetes version, no owner",
+			amp:           createMachinePoolWithOrchestrationMode(compute.OrchestrationModeFlexible),
+			version:       "v1.25.6",
+			ownerNotFound: true,
+			wantErr:       true,
+		},
+	}
+
+	for _, tc := range tests {
+		client := mockClient{Version: tc.version, ReturnError: tc.ownerNotFound}
+		t.Run(tc.name, func(t *testing.T) {
+			ampw := &azureMachinePoolWebhook{
+				Client: client,
+			}
+			_, err := ampw.ValidateCreate(context.Background(), tc.amp)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+type fakeVMExtensionVersionsGetter struct {
+	versions []string
+	err      error
+}
+
+func (f fakeVMExtensionVersionsGetter) VMExtensionVersions(_ context.Context, _ metav1.ObjectMeta, _, _ string) (string, []string, error) {
+	return "eastus", f.versions, f.err
+}
+
+func TestAzureMachinePool_ValidateVMExtensionVersions(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, true)()
+
+	withExtension := func(version string) *AzureMachinePool {
+		amp := getKnownValidAzureMachinePool()
+		amp.Spec.Template.VMExtensions = []infrav1.VMExtension{
+			{Name: "CustomScript", Publisher: "Microsoft.Azure.Extensions", Version: version},
+		}
+		return amp
+	}
+
+	tests := []struct {
+		name              string
+		oldAMP            *AzureMachinePool
+		amp               *AzureMachinePool
+		extensionVersions infrav1.VMExtensionVersionsGetter
+		wantErr           bool
+		wantWarnings      bool
+	}{
+		{
+			name:              "create with an offered version",
+			amp:               withExtension("2.1"),
+			extensionVersions: fakeVMExtensionVersionsGetter{versions: []string{"2.1.3"}},
+		},
+		{
+			name:              "create with a version that isn't offered",
+			amp:               withExtension("3.0"),
+			extensionVersions: fakeVMExtensionVersionsGetter{versions: []string{"2.1.3"}},
+			wantErr:           true,
+		},
+		{
+			name:              "create when the lookup fails",
+			amp:               withExtension("2.1"),
+			extensionVersions: fakeVMExtensionVersionsGetter{err: errors.New("no credentials")},
+			wantWarnings:      true,
+		},
+		{
+			name:              "update without extension changes",
+			oldAMP:            withExtension("3.0"),
+			amp:               withExtension("3.0"),
+			extensionVersions: fakeVMExtensionVersionsGetter{versions: []string{"2.1.3"}},
+		},
+		{
+			name:              "update to a version that isn't offered",
+			oldAMP:            withExtension("2.1"),
+			amp:               withExtension("3.0"),
+			extensionVersions: fakeVMExtensionVersionsGetter{versions: []string{"2.1.3"}},
+			wantErr:           true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ampw := &azureMachinePoolWebhook{Client: mockClient{}, ExtensionVersions: tc.extensionVersions}
+			var warnings []string
+			var err error
+			if tc.oldAMP == nil {
+				warnings, err = ampw.ValidateCreate(context.Background(), tc.amp)
+			} else {
+				warnings, err = ampw.ValidateUpdate(context.Background(), tc.oldAMP, tc.amp)
+			}
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tc.wantWarnings {
+				g.Expect(warnings).NotTo(BeEmpty())
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
+type mockDefaultClient struct {
+	client.Client
+	Name           string
+	ClusterName    string
+	SubscriptionID string
+	Version        string
+	ReturnError    bool
+}
+
+func (m mockDefaultClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	switch obj := obj.(type) {
+	case *infrav1.AzureCluster:
+		obj.Spec.SubscriptionID = m.SubscriptionID
+	case *clusterv1.Cluster:
+		obj.Spec.InfrastructureRef = &corev1.ObjectReference{
+			Kind: "AzureCluster",
+			Name: "test-cluster",
+		}
+	default:
+		return errors.New("invalid object type")
+	}
+	return nil
+}
+
+func (m mockDefaultClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	list.(*expv1.MachinePoolList).Items = []expv1.MachinePool{
+		{
+			Spec: expv1.MachinePoolSpec{
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{
+						InfrastructureRef: corev1.ObjectReference{
+							Name: m.Name,
+						},
+					},
+				},
+				ClusterName: m.ClusterName,
+			},
+		},
+	}
+
+	return nil
+}
+
+func TestAzureMachinePool_ValidateUpdate(t *testing.T) {
+	// NOTE: AzureMachinePool is behind MachinePool feature gate flag; the webhook
+	// must prevent creating new objects in case the feature flag is disabled.
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, true)()
+
+	g := NewWithT(t)
+
+	var (
+		zero = intstr.FromInt(0)
+		one  = intstr.FromInt(1)
+	)
+
+	tests := []struct {
+		name    string
+		oldAMP  *AzureMachinePool
+		amp     *AzureMachinePool
+		wantErr bool
+	}{
+		{
+			name:    "azuremachinepool with valid SSHPublicKey",
+			oldAMP:  createMachinePoolWithSSHPublicKey(""),
+			amp:     createMachinePoolWithSSHPublicKey(validSSHPublicKey),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with invalid SSHPublicKey",
+			oldAMP:  createMachinePoolWithSSHPublicKey(""),
+			amp:     createMachinePoolWithSSHPublicKey("invalid ssh key"),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with system-assigned identity, and role unchanged",
+			oldAMP:  createMachinePoolWithSystemAssignedIdentity("30a757d8-fcf0-4c8b-acf0-9253a7e093ea"),
+			amp:     createMachinePoolWithSystemAssignedIdentity("30a757d8-fcf0-4c8b-acf0-9253a7e093ea"),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with system-assigned identity, and role changed",
+			oldAMP:  createMachinePoolWithSystemAssignedIdentity(string(uuid.NewUUID())),
+			amp:     createMachinePoolWithSystemAssignedIdentity(string(uuid.NewUUID())),
+			wantErr: true,
+		},
+		{
+			name:   "azuremachinepool with invalid MaxSurge and MaxUnavailable rolling upgrade configuration",
+			oldAMP: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{}),
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxSurge:       &zero,
+					MaxUnavailable: &zero,
+				},
+			}),
+			wantErr: true,
+		},
+		{
+			name:   "azuremachinepool with valid MaxSurge and MaxUnavailable rolling upgrade configuration",
+			oldAMP: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{}),
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxSurge:       &zero,
+					MaxUnavailable: &one,
+				},
+			}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with valid network interface config",
+			oldAMP:  createMachinePoolWithNetworkConfig("", []infrav1.NetworkInterface{{SubnetName: "testSubnet"}}),
+			amp:     createMachinePoolWithNetworkConfig("", []infrav1.NetworkInterface{{SubnetName: "testSubnet2"}}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with valid network interface config",
+			oldAMP:  createMachinePoolWithNetworkConfig("", []infrav1.NetworkInterface{{SubnetName: "testSubnet"}}),
+			amp:     createMachinePoolWithNetworkConfig("subnet", []infrav1.NetworkInterface{{SubnetName: "testSubnet2"}}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with valid network interface config",
+			oldAMP:  createMachinePoolWithNetworkConfig("subnet", []infrav1.NetworkInterface{}),
+			amp:     createMachinePoolWithNetworkConfig("subnet", []infrav1.NetworkInterface{{SubnetName: "testSubnet2"}}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with unchanged node outbound rule",
+			oldAMP:  createMachinePoolWithNodeOutboundRule("egress"),
+			amp:     createMachinePoolWithNodeOutboundRule("egress"),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with node outbound rule added",
+			oldAMP:  createMachinePoolWithNodeOutboundRule(""),
+			amp:     createMachinePoolWithNodeOutboundRule("egress"),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with node outbound rule changed",
+			oldAMP:  createMachinePoolWithNodeOutboundRule("egress"),
+			amp:     createMachinePoolWithNodeOutboundRule("other"),
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ampw := &azureMachinePoolWebhook{}
+			_, err := ampw.ValidateUpdate(context.Background(), tc.oldAMP, tc.amp)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachinePool_ValidateStrategyReplacePolicy(t *testing.T) {
+	g := NewWithT(t)
+
+	amp := createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+		Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+		RollingUpdate: &MachineRollingUpdateDeployment{
+			MaxSurge:       &zero,
+			MaxUnavailable: &one,
+			ReplacePolicy:  ReimageReplacePolicyType,
+		},
+	})
+	amp.Spec.OrchestrationMode = infrav1.UniformOrchestrationMode
+	g.Expect(amp.ValidateStrategy()()).To(Succeed())
+
+	amp.Spec.OrchestrationMode = infrav1.FlexibleOrchestrationMode
+	g.Expect(amp.ValidateStrategy()()).NotTo(Succeed())
+}
+
+func TestAzureMachinePool_ValidateStrategyPercentages(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxSurge       *intstr.IntOrString
+		maxUnavailable *intstr.IntOrString
+		wantErr        bool
+	}{
+		{
+			name:           "percentages",
+			maxSurge:       ptr.To(intstr.FromString("25%")),
+			maxUnavailable: ptr.To(intstr.FromString("0%")),
+			wantErr:        false,
+		},
+		{
+			name:           "zero percentages",
+			maxSurge:       ptr.To(intstr.FromString("0%")),
+			maxUnavailable: ptr.To(intstr.FromString("0%")),
+			wantErr:        true,
+		},
+		{
+			name:           "zero percentage and zero number",
+			maxSurge:       ptr.To(intstr.FromString("0%")),
+			maxUnavailable: &zero,
+			wantErr:        true,
+		},
+		{
+			name:           "unset MaxSurge defaults to 1",
+			maxUnavailable: &zero,
+			wantErr:        false,
+		},
+		{
+			name:           "invalid percentage",
+			maxSurge:       ptr.To(intstr.FromString("25")),
+			maxUnavailable: &zero,
+			wantErr:        true,
+		},
+		{
+			name:           "negative percentage",
+			maxSurge:       ptr.To(intstr.FromString("-10%")),
+			maxUnavailable: &one,
+			wantErr:        true,
+		},
+		{
+			name:           "MaxUnavailable above 100%",
+			maxSurge:       &one,
+			maxUnavailable: ptr.To(intstr.FromString("150%")),
+			wantErr:        true,
+		},
+		{
+			name:           "MaxSurge above 100%",
+			maxSurge:       ptr.To(intstr.FromString("200%")),
+			maxUnavailable: &zero,
+			wantErr:        false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxSurge:       tc.maxSurge,
+					MaxUnavailable: tc.maxUnavailable,
+				},
+			})
+			err := amp.ValidateStrategy()()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachinePool_ValidatePlacement(t *testing.T) {
+	placement := &AzureMachinePoolPlacement{
+		ZoneBalance:              ptr.To(true),
+		PlatformFaultDomainCount: ptr.To[int32](1),
+	}
+	tests := []struct {
+		name    string
+		oldAMP  *AzureMachinePool
+		amp     *AzureMachinePool
+		wantErr bool
+	}{
+		{
+			name:    "placement for Flexible orchestration mode",
+			amp:     createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, placement),
+			wantErr: false,
+		},
+		{
+			name:    "placement for Uniform orchestration mode",
+			amp:     createMachinePoolWithPlacement(infrav1.UniformOrchestrationMode, placement),
+			wantErr: false,
+		},
+		{
+			name:    "unchanged placement",
+			oldAMP:  createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, placement),
+			amp:     createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, placement.DeepCopy()),
+			wantErr: false,
+		},
+		{
+			name:    "placement added",
+			oldAMP:  createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, nil),
+			amp:     createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, placement),
+			wantErr: true,
+		},
+		{
+			name:    "placement changed",
+			oldAMP:  createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, placement),
+			amp:     createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, &AzureMachinePoolPlacement{PlatformFaultDomainCount: ptr.To[int32](2)}),
+			wantErr: true,
+		},
+		{
+			name: "placement with a capacity reservation group",
+			amp: createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, &AzureMachinePoolPlacement{
+				SinglePlacementGroup:       ptr.To(false),
+				CapacityReservationGroupID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/capacityReservationGroups/my-crg",
+			}),
+			wantErr: false,
+		},
+		{
+			name: "capacity reservation group for Uniform orchestration mode",
+			amp: createMachinePoolWithPlacement(infrav1.UniformOrchestrationMode, &AzureMachinePoolPlacement{
+				CapacityReservationGroupID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/capacityReservationGroups/my-crg",
+			}),
+			wantErr: false,
+		},
+		{
+			name: "capacity reservation group with Spot VMs",
+			amp: func() *AzureMachinePool {
+				amp := createMachinePoolWithPlacement(infrav1.UniformOrchestrationMode, &AzureMachinePoolPlacement{
+					CapacityReservationGroupID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/capacityReservationGroups/my-crg",
+				})
+				amp.Spec.Template.SpotVMOptions = &infrav1.SpotVMOptions{}
+				return amp
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "spreading options for Uniform orchestration mode",
+			amp: createMachinePoolWithPlacement(infrav1.UniformOrchestrationMode, &AzureMachinePoolPlacement{
+				ZoneBalance:              ptr.To(true),
+				PlatformFaultDomainCount: ptr.To[int32](5),
+				SinglePlacementGroup:     ptr.To(true),
+			}),
+			wantErr: false,
+		},
+		{
+			name:    "single placement group for Flexible orchestration mode",
+			amp:     createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, &AzureMachinePoolPlacement{SinglePlacementGroup: ptr.To(true)}),
+			wantErr: true,
+		},
+		{
+			name:    "more than 3 fault domains for Flexible orchestration mode",
+			amp:     createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, &AzureMachinePoolPlacement{PlatformFaultDomainCount: ptr.To[int32](5)}),
+			wantErr: true,
+		},
+		{
+			name:    "zone rebalance strategy for Uniform orchestration mode",
+			amp:     createMachinePoolWithPlacement(infrav1.UniformOrchestrationMode, &AzureMachinePoolPlacement{ZoneRebalanceStrategy: ScaleInZoneRebalanceStrategy}),
+			wantErr: true,
+		},
+		{
+			name:   "zone rebalance strategy changed",
+			oldAMP: createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, placement),
+			amp: createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, &AzureMachinePoolPlacement{
+				ZoneBalance:              ptr.To(true),
+				PlatformFaultDomainCount: ptr.To[int32](1),
+				ZoneRebalanceStrategy:    ScaleInZoneRebalanceStrategy,
+			}),
+			wantErr: false,
+		},
+		{
+			name:    "zone rebalance strategy added without placement",
+			oldAMP:  createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, nil),
+			amp:     createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, &AzureMachinePoolPlacement{ZoneRebalanceStrategy: ScaleInZoneRebalanceStrategy}),
+			wantErr: false,
+		},
+		{
+			name:    "placement with an invalid capacity reservation group ID",
+			amp:     createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, &AzureMachinePoolPlacement{CapacityReservationGroupID: "my-crg"}),
+			wantErr: true,
+		},
+		{
+			name: "pinned instances for Flexible orchestration mode",
+			amp: createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, &AzureMachinePoolPlacement{
+				PlatformFaultDomainCount: ptr.To[int32](3),
+				Instances: []AzureMachinePoolInstancePlacement{
+					{Name: "a", Zone: "1", PlatformFaultDomain: ptr.To[int32](0)},
+					{Name: "b", Zone: "2", PlatformFaultDomain: ptr.To[int32](2)},
+					{Name: "c", Zone: "3"},
+				},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "pinned instances for Uniform orchestration mode",
+			amp: createMachinePoolWithPlacement(infrav1.UniformOrchestrationMode, &AzureMachinePoolPlacement{
+				Instances: []AzureMachinePoolInstancePlacement{{Name: "a", Zone: "1"}},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "pinned instances with duplicate names",
+			amp: createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, &AzureMachinePoolPlacement{
+				Instances: []AzureMachinePoolInstancePlacement{{Name: "a", Zone: "1"}, {Name: "a", Zone: "2"}},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "pinned instance fault domain without a fault domain count",
+			amp: createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, &AzureMachinePoolPlacement{
+				Instances: []AzureMachinePoolInstancePlacement{{Name: "a", PlatformFaultDomain: ptr.To[int32](0)}},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "pinned instance fault domain out of range",
+			amp: createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, &AzureMachinePoolPlacement{
+				PlatformFaultDomainCount: ptr.To[int32](2),
+				Instances:                []AzureMachinePoolInstancePlacement{{Name: "a", PlatformFaultDomain: ptr.To[int32](2)}},
+			}),
+			wantErr: true,
+		},
+		{
+			name:   "pinned instances changed",
+			oldAMP: createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, placement),
+			amp: createMachinePoolWithPlacement(infrav1.FlexibleOrchestrationMode, &AzureMachinePoolPlacement{
+				ZoneBalance:              ptr.To(true),
+				PlatformFaultDomainCount: ptr.To[int32](1),
+				Instances:                []AzureMachinePoolInstancePlacement{{Name: "a", Zone: "1"}},
+			}),
+			wantErr: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var old runtime.Object
+			if tc.oldAMP != nil {
+				old = tc.oldAMP
+			}
+			err := tc.amp.ValidatePlacement(old)()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachinePool_ValidatePriorityMixPolicy(t *testing.T) {
+	policy := &AzureMachinePoolPriorityMixPolicy{
+		BaseRegularPriorityCount:           ptr.To[int32](2),
+		RegularPriorityPercentageAboveBase: ptr.To[int32](50),
+	}
+	tests := []struct {
+		name    string
+		amp     *AzureMachinePool
+		oldAMP  *AzureMachinePool
+		wantErr bool
+	}{
+		{
+			name:    "no priority mix policy",
+			amp:     createMachinePoolWithPriorityMixPolicy(infrav1.UniformOrchestrationMode, nil, nil),
+			wantErr: false,
+		},
+		{
+			name:    "priority mix policy with Flexible mode and spot VM options",
+			amp:     createMachinePoolWithPriorityMixPolicy(infrav1.FlexibleOrchestrationMode, &infrav1.SpotVMOptions{}, policy),
+			wantErr: false,
+		},
+		{
+			name:    "priority mix policy with Uniform mode",
+			amp:     createMachinePoolWithPriorityMixPolicy(infrav1.UniformOrchestrationMode, &infrav1.SpotVMOptions{}, policy),
+			wantErr: true,
+		},
+		{
+			name:    "priority mix policy without spot VM options",
+			amp:     createMachinePoolWithPriorityMixPolicy(infrav1.FlexibleOrchestrationMode, nil, policy),
+			wantErr: true,
+		},
+		{
+			name:    "unchanged priority mix policy",
+			amp:     createMachinePoolWithPriorityMixPolicy(infrav1.FlexibleOrchestrationMode, &infrav1.SpotVMOptions{}, policy),
+			oldAMP:  createMachinePoolWithPriorityMixPolicy(infrav1.FlexibleOrchestrationMode, &infrav1.SpotVMOptions{}, policy.DeepCopy()),
+			wantErr: false,
+		},
+		{
+			name: "changed priority mix policy",
+			amp:  createMachinePoolWithPriorityMixPolicy(infrav1.FlexibleOrchestrationMode, &infrav1.SpotVMOptions{}, policy),
+			oldAMP: createMachinePoolWithPriorityMixPolicy(infrav1.FlexibleOrchestrationMode, &infrav1.SpotVMOptions{}, &AzureMachinePoolPriorityMixPolicy{
+				BaseRegularPriorityCount: ptr.To[int32](1),
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var old runtime.Object
+			if tc.oldAMP != nil {
+				old = tc.oldAMP
+			}
+			err := tc.amp.ValidatePriorityMixPolicy(old)()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachinePool_ValidateUpgradePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		amp     *AzureMachinePool
+		wantErr bool
+	}{
+		{
+			name:    "no upgrade policy",
+			amp:     createMachinePoolWithUpgradePolicy(infrav1.FlexibleOrchestrationMode, nil),
+			wantErr: false,
+		},
+		{
+			name: "rolling upgrade policy for Uniform orchestration mode",
+			amp: func() *AzureMachinePool {
+				amp := createMachinePoolWithUpgradePolicy(infrav1.UniformOrchestrationMode, &AzureMachinePoolUpgradePolicy{
+					Mode: RollingUpgradeMode,
+					RollingUpgrade: &AzureMachinePoolRollingUpgradePolicy{
+						MaxBatchInstancePercent: ptr.To[int32](50),
+						PauseTime:               &metav1.Duration{Duration: 5 * time.Minute},
+					},
+					EnableAutomaticOSUpgrade: true,
+				})
+				amp.Spec.AutomaticRepairs = &AzureMachinePoolAutomaticRepairs{
+					HealthProbe: &AzureMachinePoolHealthProbe{Port: 10248, RequestPath: "/healthz"},
+				}
+				return amp
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "automatic OS upgrade with automatic repairs",
+			amp: func() *AzureMachinePool {
+				amp := createMachinePoolWithUpgradePolicy(infrav1.UniformOrchestrationMode, &AzureMachinePoolUpgradePolicy{
+					Mode:                     AutomaticUpgradeMode,
+					EnableAutomaticOSUpgrade: true,
+				})
+				amp.Spec.AutomaticRepairs = &AzureMachinePoolAutomaticRepairs{Enabled: true}
+				return amp
+			}(),
+			wantErr: false,
+		},
+		{
+			name:    "rolling upgrade mode without the Application Health extension",
+			amp:     createMachinePoolWithUpgradePolicy(infrav1.UniformOrchestrationMode, &AzureMachinePoolUpgradePolicy{Mode: RollingUpgradeMode}),
+			wantErr: true,
+		},
+		{
+			name: "automatic OS upgrade without the Application Health extension",
+			amp: createMachinePoolWithUpgradePolicy(infrav1.UniformOrchestrationMode, &AzureMachinePoolUpgradePolicy{
+				Mode:                     AutomaticUpgradeMode,
+				EnableAutomaticOSUpgrade: true,
+			}),
+			wantErr: true,
+		},
+		{
+			name:    "upgrade policy for Flexible orchestration mode",
+			amp:     createMachinePoolWithUpgradePolicy(infrav1.FlexibleOrchestrationMode, &AzureMachinePoolUpgradePolicy{Mode: AutomaticUpgradeMode}),
+			wantErr: true,
+		},
+		{
+			name: "rolling upgrade settings without Rolling mode",
+			amp: createMachinePoolWithUpgradePolicy(infrav1.UniformOrchestrationMode, &AzureMachinePoolUpgradePolicy{
+				Mode:           AutomaticUpgradeMode,
+				RollingUpgrade: &AzureMachinePoolRollingUpgradePolicy{MaxBatchInstancePercent: ptr.To[int32](50)},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "automatic OS upgrade with Manual mode",
+			amp: createMachinePoolWithUpgradePolicy(infrav1.UniformOrchestrationMode, &AzureMachinePoolUpgradePolicy{
+				Mode:                     ManualUpgradeMode,
+				EnableAutomaticOSUpgrade: true,
+			}),
+			wantErr: true,
+		},
+		{
+			name: "negative pause time",
+			amp: createMachinePoolWithUpgradePolicy(infrav1.UniformOrchestrationMode, &AzureMachinePoolUpgradePolicy{
+				Mode:           RollingUpgradeMode,
+				RollingUpgrade: &AzureMachinePoolRollingUpgradePolicy{PauseTime: &metav1.Duration{Duration: -time.Minute}},
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := tc.amp.ValidateUpgradePolicy()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachinePool_ValidateAutomaticRepairs(t *testing.T) {
+	tests := []struct {
+		name             string
+		automaticRepairs *AzureMachinePoolAutomaticRepairs
+		wantErr          bool
+	}{
+		{
+			name:             "no automatic repairs",
+			automaticRepairs: nil,
+			wantErr:          false,
+		},
+		{
+			name: "automatic repairs with a grace period and an HTTP health probe",
+			automaticRepairs: &AzureMachinePoolAutomaticRepairs{
+				Enabled:     true,
+				GracePeriod: &metav1.Duration{Duration: 30 * time.Minute},
+				HealthProbe: &AzureMachinePoolHealthProbe{Protocol: HTTPHealthProbeProtocol, Port: 10256, RequestPath: "/healthz"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "grace period shorter than 10 minutes",
+			automaticRepairs: &AzureMachinePoolAutomaticRepairs{
+				Enabled:     true,
+				GracePeriod: &metav1.Duration{Duration: 5 * time.Minute},
+			},
+			wantErr: true,
+		},
+		{
+			name: "grace period longer than 90 minutes",
+			automaticRepairs: &AzureMachinePoolAutomaticRepairs{
+				Enabled:     true,
+				GracePeriod: &metav1.Duration{Duration: 2 * time.Hour},
+			},
+			wantErr: true,
+		},
+		{
+			name: "request path with a TCP health probe",
+			automaticRepairs: &AzureMachinePoolAutomaticRepairs{
+				Enabled:     true,
+				HealthProbe: &AzureMachinePoolHealthProbe{Protocol: TCPHealthProbeProtocol, Port: 22, RequestPath: "/healthz"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "health probe with a grace period without automatic repairs",
+			automaticRepairs: &AzureMachinePoolAutomaticRepairs{
+				HealthProbe: &AzureMachinePoolHealthProbe{
+					Protocol:    HTTPHealthProbeProtocol,
+					Port:        10256,
+					RequestPath: "/healthz",
+					GracePeriod: &metav1.Duration{Duration: 10 * time.Minute},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "health probe grace period longer than 4 hours",
+			automaticRepairs: &AzureMachinePoolAutomaticRepairs{
+				HealthProbe: &AzureMachinePoolHealthProbe{Port: 10256, GracePeriod: &metav1.Duration{Duration: 5 * time.Hour}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := &AzureMachinePool{Spec: AzureMachinePoolSpec{AutomaticRepairs: tc.automaticRepairs}}
+			err := amp.ValidateAutomaticRepairs()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachinePool_ValidateStandbyPool(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    AzureMachinePoolSpec
+		wantErr bool
+	}{
+		{
+			name:    "no standby pool",
+			spec:    AzureMachinePoolSpec{},
+			wantErr: false,
+		},
+		{
+			name: "standby pool for Flexible orchestration mode",
+			spec: AzureMachinePoolSpec{
+				OrchestrationMode: infrav1.FlexibleOrchestrationMode,
+				StandbyPool:       &AzureMachinePoolStandbyPool{MaxReadyCapacity: 10, MinReadyCapacity: ptr.To[int64](2)},
+			},
+			wantErr: false,
+		},
+		{
+			name: "standby pool for Uniform orchestration mode",
+			spec: AzureMachinePoolSpec{
+				OrchestrationMode: infrav1.UniformOrchestrationMode,
+				StandbyPool:       &AzureMachinePoolStandbyPool{MaxReadyCapacity: 10},
+			},
+			wantErr: true,
+		},
+		{
+			name: "standby pool with Spot VM options",
+			spec: AzureMachinePoolSpec{
+				OrchestrationMode: infrav1.FlexibleOrchestrationMode,
+				Template:          AzureMachinePoolMachineTemplate{SpotVMOptions: &infrav1.SpotVMOptions{}},
+				StandbyPool:       &AzureMachinePoolStandbyPool{MaxReadyCapacity: 10},
+			},
+			wantErr: true,
+		},
+		{
+			name: "minimum ready capacity exceeding the maximum ready capacity",
+			spec: AzureMachinePoolSpec{
+				OrchestrationMode: infrav1.FlexibleOrchestrationMode,
+				StandbyPool:       &AzureMachinePoolStandbyPool{MaxReadyCapacity: 2, MinReadyCapacity: ptr.To[int64](3)},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := &AzureMachinePool{Spec: tc.spec}
+			err := amp.ValidateStandbyPool()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachinePool_ValidateSpotRestorePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    AzureMachinePoolSpec
+		wantErr bool
+	}{
+		{
+			name:    "no Spot VM options",
+			spec:    AzureMachinePoolSpec{},
+			wantErr: false,
+		},
+		{
+			name: "restore policy with a restore timeout",
+			spec: AzureMachinePoolSpec{
+				Template: AzureMachinePoolMachineTemplate{SpotVMOptions: &infrav1.SpotVMOptions{
+					RestorePolicy: &infrav1.SpotRestorePolicy{Enabled: true, RestoreTimeout: &metav1.Duration{Duration: 2 * time.Hour}},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "restore policy with a single placement group",
+			spec: AzureMachinePoolSpec{
+				OrchestrationMode: infrav1.FlexibleOrchestrationMode,
+				Placement:         &AzureMachinePoolPlacement{SinglePlacementGroup: ptr.To(true)},
+				Template: AzureMachinePoolMachineTemplate{SpotVMOptions: &infrav1.SpotVMOptions{
+					RestorePolicy: &infrav1.SpotRestorePolicy{Enabled: true},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "restore policy with a zero restore timeout",
+			spec: AzureMachinePoolSpec{
+				Template: AzureMachinePoolMachineTemplate{SpotVMOptions: &infrav1.SpotVMOptions{
+					RestorePolicy: &infrav1.SpotRestorePolicy{Enabled: true, RestoreTimeout: &metav1.Duration{}},
+				}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := &AzureMachinePool{Spec: tc.spec}
+			err := amp.ValidateSpotRestorePolicy()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachinePool_ValidateBootstrapExtension(t *testing.T) {
+	tests := []struct {
+		name               string
+		bootstrapExtension *AzureMachinePoolBootstrapExtension
+		wantErr            bool
+	}{
+		{
+			name:    "no bootstrap extension settings",
+			wantErr: false,
+		},
+		{
+			name:               "disabled bootstrap extension",
+			bootstrapExtension: &AzureMachinePoolBootstrapExtension{Disabled: true},
+			wantErr:            false,
+		},
+		{
+			name:               "custom timeout and retry interval",
+			bootstrapExtension: &AzureMachinePoolBootstrapExtension{Timeout: &metav1.Duration{Duration: 20 * time.Minute}, RetryInterval: &metav1.Duration{Duration: 10 * time.Second}},
+			wantErr:            false,
+		},
+		{
+			name:               "timeout set on a disabled bootstrap extension",
+			bootstrapExtension: &AzureMachinePoolBootstrapExtension{Disabled: true, Timeout: &metav1.Duration{Duration: 20 * time.Minute}},
+			wantErr:            true,
+		},
+		{
+			name:               "zero timeout",
+			bootstrapExtension: &AzureMachinePoolBootstrapExtension{Timeout: &metav1.Duration{}},
+			wantErr:            true,
+		},
+		{
+			name:               "timeout above 90 minutes",
+			bootstrapExtension: &AzureMachinePoolBootstrapExtension{Timeout: &metav1.Duration{Duration: 2 * time.Hour}},
+			wantErr:            true,
+		},
+		{
+			name:               "retry interval below a second",
+			bootstrapExtension: &AzureMachinePoolBootstrapExtension{RetryInterval: &metav1.Duration{Duration: 500 * time.Millisecond}},
+			wantErr:            true,
+		},
+		{
+			name:               "retry interval above the timeout",
+			bootstrapExtension: &AzureMachinePoolBootstrapExtension{Timeout: &metav1.Duration{Duration: time.Minute}, RetryInterval: &metav1.Duration{Duration: 2 * time.Minute}},
+			wantErr:            true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := &AzureMachinePool{Spec: AzureMachinePoolSpec{Template: AzureMachinePoolMachineTemplate{BootstrapExtension: tc.bootstrapExtension}}}
+			err := amp.ValidateBootstrapExtension()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachinePool_ValidateNodeLabelsAndTaints(t *testing.T) {
+	tests := []struct {
+		name       string
+		nodeLabels map[string]string
+		nodeTaints infrav1.Taints
+		wantErr    bool
+	}{
+		{
+			name:    "no node labels and taints",
+			wantErr: false,
+		},
+		{
+			name:       "valid node labels and taints",
+			nodeLabels: map[string]string{"example.com/role": "gpu", "pool": "win"},
+			nodeTaints: infrav1.Taints{
+				{Key: "example.com/gpu", Value: "true", Effect: "NoSchedule"},
+				{Key: "example.com/gpu", Value: "true", Effect: "NoExecute"},
+			},
+			wantErr: false,
+		},
+		{
+			name:       "invalid node label key",
+			nodeLabels: map[string]string{"example.com/role/gpu": "true"},
+			wantErr:    true,
+		},
+		{
+			name:       "invalid node label value",
+			nodeLabels: map[string]string{"role": "gpu nodes"},
+			wantErr:    true,
+		},
+		{
+			name:       "invalid taint key",
+			nodeTaints: infrav1.Taints{{Key: "-gpu", Value: "true", Effect: "NoSchedule"}},
+			wantErr:    true,
+		},
+		{
+			name:       "duplicate taint",
+			nodeTaints: infrav1.Taints{{Key: "gpu", Value: "true", Effect: "NoSchedule"}, {Key: "gpu", Value: "false", Effect: "NoSchedule"}},
+			wantErr:    true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := &AzureMachinePool{Spec: AzureMachinePoolSpec{Template: AzureMachinePoolMachineTemplate{NodeLabels: tc.nodeLabels, NodeTaints: tc.nodeTaints}}}
+			err := amp.ValidateNodeLabelsAndTaints()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachinePool_ValidateAdminUsername(t *testing.T) {
+	tests := []struct {
+		name    string
+		amp     *AzureMachinePool
+		oldAMP  *AzureMachinePool
+		wantErr bool
+	}{
+		{
+			name:    "no admin username",
+			amp:     createMachinePoolWithAdminUsername(""),
+			wantErr: false,
+		},
+		{
+			name:    "valid admin username",
+			amp:     createMachinePoolWithAdminUsername("ops_admin"),
+			wantErr: false,
 		},
 		{
-			name:    "azuremachinepool with valid networkinterface configuration",
-			amp:     createMachinePoolWithNetworkConfig("", []infrav1.NetworkInterface{{SubnetName: "testSubnet"}}),
-			wantErr: false,
+			name:    "reserved admin username",
+			amp:     createMachinePoolWithAdminUsername("admin"),
+			wantErr: true,
 		},
 		{
-			name:    "azuremachinepool with Flexible orchestration mode",
-			amp:     createMachinePoolWithOrchestrationMode(compute.OrchestrationModeFlexible),
-			version: "v1.26.0",
+			name:    "unchanged admin username",
+			amp:     createMachinePoolWithAdminUsername("ops_admin"),
+			oldAMP:  createMachinePoolWithAdminUsername("ops_admin"),
 			wantErr: false,
 		},
 		{
-			name:    "azuremachinepool with Flexible orchestration mode and invalid Kubernetes version",
-			amp:     createMachinePoolWithOrchestrationMode(compute.OrchestrationModeFlexible),
-			version: "v1.25.6",
+			name:    "changed admin username",
+			amp:     createMachinePoolWithAdminUsername("ops_admin"),
+			oldAMP:  createMachinePoolWithAdminUsername(""),
 			wantErr: true,
 		},
-		{
-			name:          "azuremachinepool with Flexible orchestration mode and invalid Kubernetes version, no owner",
-			amp:           createMachinePoolWithOrchestrationMode(compute.OrchestrationModeFlexible),
-			version:       "v1.25.6",
-			ownerNotFound: true,
-			wantErr:       true,
-		},
 	}
-
 	for _, tc := range tests {
-		client := mockClient{Version: tc.version, ReturnError: tc.ownerNotFound}
 		t.Run(tc.name, func(t *testing.T) {
-			ampw := &azureMachinePoolWebhook{
-				Client: client,
+			g := NewWithT(t)
+			var old runtime.Object
+			if tc.oldAMP != nil {
+				old = tc.oldAMP
 			}
-			_, err := ampw.ValidateCreate(context.Background(), tc.amp)
+			err := tc.amp.ValidateAdminUsername(old)()
 			if tc.wantErr {
 				g.Expect(err).To(HaveOccurred())
 			} else {
@@ -257,61 +1340,67 @@ func TestAzureMachinePool_ValidateCreate(t *testing.T) {
 	}
 }
 
-type mockDefaultClient struct {
-	client.Client
-	Name           string
-	ClusterName    string
-	SubscriptionID string
-	Version        string
-	ReturnError    bool
-}
-
-func (m mockDefaultClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
-	switch obj := obj.(type) {
-	case *infrav1.AzureCluster:
-		obj.Spec.SubscriptionID = m.SubscriptionID
-	case *clusterv1.Cluster:
-		obj.Spec.InfrastructureRef = &corev1.ObjectReference{
-			Kind: "AzureCluster",
-			Name: "test-cluster",
-		}
-	default:
-		return errors.New("invalid object type")
-	}
-	return nil
-}
-
-func (m mockDefaultClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
-	list.(*expv1.MachinePoolList).Items = []expv1.MachinePool{
+func TestAzureMachinePool_ValidateSecurityProfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		template AzureMachinePoolMachineTemplate
+		wantErr  bool
+	}{
 		{
-			Spec: expv1.MachinePoolSpec{
-				Template: clusterv1.MachineTemplateSpec{
-					Spec: clusterv1.MachineSpec{
-						InfrastructureRef: corev1.ObjectReference{
-							Name: m.Name,
+			name:     "no security profile",
+			template: AzureMachinePoolMachineTemplate{},
+			wantErr:  false,
+		},
+		{
+			name: "encryption at host",
+			template: AzureMachinePoolMachineTemplate{
+				SecurityProfile: &infrav1.SecurityProfile{EncryptionAtHost: ptr.To(true)},
+			},
+			wantErr: false,
+		},
+		{
+			name: "DiskWithVMGuestState encryption and encryption at host",
+			template: AzureMachinePoolMachineTemplate{
+				OSDisk: infrav1.OSDisk{
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						SecurityProfile: &infrav1.VMDiskSecurityProfile{
+							SecurityEncryptionType: infrav1.SecurityEncryptionTypeDiskWithVMGuestState,
 						},
 					},
 				},
-				ClusterName: m.ClusterName,
+				SecurityProfile: &infrav1.SecurityProfile{
+					EncryptionAtHost: ptr.To(true),
+					SecurityType:     infrav1.SecurityTypesConfidentialVM,
+					UefiSettings:     &infrav1.UefiSettings{SecureBootEnabled: ptr.To(true), VTpmEnabled: ptr.To(true)},
+				},
 			},
+			wantErr: true,
+		},
+		{
+			name: "vTPM without security type",
+			template: AzureMachinePoolMachineTemplate{
+				SecurityProfile: &infrav1.SecurityProfile{
+					UefiSettings: &infrav1.UefiSettings{VTpmEnabled: ptr.To(true)},
+				},
+			},
+			wantErr: true,
 		},
 	}
-
-	return nil
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := &AzureMachinePool{Spec: AzureMachinePoolSpec{Template: tc.template}}
+			err := amp.ValidateSecurityProfile()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
 }
 
-func TestAzureMachinePool_ValidateUpdate(t *testing.T) {
-	// NOTE: AzureMachinePool is behind MachinePool feature gate flag; the webhook
-	// must prevent creating new objects in case the feature flag is disabled.
-	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, true)()
-
-	g := NewWithT(t)
-
-	var (
-		zero = intstr.FromInt(0)
-		one  = intstr.FromInt(1)
-	)
-
+func TestAzureMachinePool_ValidateOutboundType(t *testing.T) {
 	tests := []struct {
 		name    string
 		oldAMP  *AzureMachinePool
@@ -319,76 +1408,100 @@ func TestAzureMachinePool_ValidateUpdate(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name:    "azuremachinepool with valid SSHPublicKey",
-			oldAMP:  createMachinePoolWithSSHPublicKey(""),
-			amp:     createMachinePoolWithSSHPublicKey(validSSHPublicKey),
+			name:    "no outbound type",
+			amp:     &AzureMachinePool{Spec: AzureMachinePoolSpec{NodeOutboundRule: "egress"}},
 			wantErr: false,
 		},
 		{
-			name:    "azuremachinepool with invalid SSHPublicKey",
-			oldAMP:  createMachinePoolWithSSHPublicKey(""),
-			amp:     createMachinePoolWithSSHPublicKey("invalid ssh key"),
+			name:    "node outbound rule with LoadBalancer outbound type",
+			amp:     &AzureMachinePool{Spec: AzureMachinePoolSpec{OutboundType: LoadBalancerOutboundType, NodeOutboundRule: "egress"}},
+			wantErr: false,
+		},
+		{
+			name:    "node outbound rule with NATGateway outbound type",
+			amp:     &AzureMachinePool{Spec: AzureMachinePoolSpec{OutboundType: NATGatewayOutboundType, NodeOutboundRule: "egress"}},
 			wantErr: true,
 		},
 		{
-			name:    "azuremachinepool with system-assigned identity, and role unchanged",
-			oldAMP:  createMachinePoolWithSystemAssignedIdentity("30a757d8-fcf0-4c8b-acf0-9253a7e093ea"),
-			amp:     createMachinePoolWithSystemAssignedIdentity("30a757d8-fcf0-4c8b-acf0-9253a7e093ea"),
+			name:    "unchanged outbound type",
+			oldAMP:  &AzureMachinePool{Spec: AzureMachinePoolSpec{OutboundType: NoneOutboundType}},
+			amp:     &AzureMachinePool{Spec: AzureMachinePoolSpec{OutboundType: NoneOutboundType}},
 			wantErr: false,
 		},
 		{
-			name:    "azuremachinepool with system-assigned identity, and role changed",
-			oldAMP:  createMachinePoolWithSystemAssignedIdentity(string(uuid.NewUUID())),
-			amp:     createMachinePoolWithSystemAssignedIdentity(string(uuid.NewUUID())),
+			name:    "outbound type changed",
+			oldAMP:  &AzureMachinePool{Spec: AzureMachinePoolSpec{OutboundType: NATGatewayOutboundType}},
+			amp:     &AzureMachinePool{Spec: AzureMachinePoolSpec{OutboundType: LoadBalancerOutboundType}},
 			wantErr: true,
 		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var old runtime.Object
+			if tc.oldAMP != nil {
+				old = tc.oldAMP
+			}
+			err := tc.amp.ValidateOutboundType(old)()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachinePool_ValidateDiagnosticsStorageAccount(t *testing.T) {
+	account := &AzureMachinePoolDiagnosticsStorageAccount{Name: "teamadiag", SKU: "Standard_LRS"}
+	tests := []struct {
+		name    string
+		oldAMP  *AzureMachinePool
+		amp     *AzureMachinePool
+		wantErr bool
+	}{
 		{
-			name:   "azuremachinepool with invalid MaxSurge and MaxUnavailable rolling upgrade configuration",
-			oldAMP: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{}),
-			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
-				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
-				RollingUpdate: &MachineRollingUpdateDeployment{
-					MaxSurge:       &zero,
-					MaxUnavailable: &zero,
-				},
-			}),
-			wantErr: true,
+			name:    "diagnostics storage account",
+			amp:     &AzureMachinePool{Spec: AzureMachinePoolSpec{DiagnosticsStorageAccount: account}},
+			wantErr: false,
 		},
 		{
-			name:   "azuremachinepool with valid MaxSurge and MaxUnavailable rolling upgrade configuration",
-			oldAMP: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{}),
-			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
-				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
-				RollingUpdate: &MachineRollingUpdateDeployment{
-					MaxSurge:       &zero,
-					MaxUnavailable: &one,
+			name: "diagnostics storage account with boot diagnostics",
+			amp: &AzureMachinePool{Spec: AzureMachinePoolSpec{
+				DiagnosticsStorageAccount: account,
+				Template: AzureMachinePoolMachineTemplate{
+					Diagnostics: &infrav1.Diagnostics{Boot: &infrav1.BootDiagnostics{StorageAccountType: infrav1.ManagedDiagnosticsStorage}},
 				},
-			}),
-			wantErr: false,
+			}},
+			wantErr: true,
 		},
 		{
-			name:    "azuremachinepool with valid network interface config",
-			oldAMP:  createMachinePoolWithNetworkConfig("", []infrav1.NetworkInterface{{SubnetName: "testSubnet"}}),
-			amp:     createMachinePoolWithNetworkConfig("", []infrav1.NetworkInterface{{SubnetName: "testSubnet2"}}),
+			name:    "unchanged diagnostics storage account",
+			oldAMP:  &AzureMachinePool{Spec: AzureMachinePoolSpec{DiagnosticsStorageAccount: account}},
+			amp:     &AzureMachinePool{Spec: AzureMachinePoolSpec{DiagnosticsStorageAccount: account.DeepCopy()}},
 			wantErr: false,
 		},
 		{
-			name:    "azuremachinepool with valid network interface config",
-			oldAMP:  createMachinePoolWithNetworkConfig("", []infrav1.NetworkInterface{{SubnetName: "testSubnet"}}),
-			amp:     createMachinePoolWithNetworkConfig("subnet", []infrav1.NetworkInterface{{SubnetName: "testSubnet2"}}),
+			name:    "diagnostics storage account added",
+			oldAMP:  &AzureMachinePool{},
+			amp:     &AzureMachinePool{Spec: AzureMachinePoolSpec{DiagnosticsStorageAccount: account}},
 			wantErr: true,
 		},
 		{
-			name:    "azuremachinepool with valid network interface config",
-			oldAMP:  createMachinePoolWithNetworkConfig("subnet", []infrav1.NetworkInterface{}),
-			amp:     createMachinePoolWithNetworkConfig("subnet", []infrav1.NetworkInterface{{SubnetName: "testSubnet2"}}),
+			name:    "diagnostics storage account changed",
+			oldAMP:  &AzureMachinePool{Spec: AzureMachinePoolSpec{DiagnosticsStorageAccount: account}},
+			amp:     &AzureMachinePool{Spec: AzureMachinePoolSpec{DiagnosticsStorageAccount: &AzureMachinePoolDiagnosticsStorageAccount{SKU: "Standard_ZRS"}}},
 			wantErr: true,
 		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			ampw := &azureMachinePoolWebhook{}
-			_, err := ampw.ValidateUpdate(context.Background(), tc.oldAMP, tc.amp)
+			g := NewWithT(t)
+			var old runtime.Object
+			if tc.oldAMP != nil {
+				old = tc.oldAMP
+			}
+			err := tc.amp.ValidateDiagnosticsStorageAccount(old)()
 			if tc.wantErr {
 				g.Expect(err).To(HaveOccurred())
 			} else {
@@ -488,6 +1601,61 @@ func TestAzureMachinePool_Default(t *testing.T) {
 	g.Expect(emptyTest.amp.Spec.SystemAssignedIdentityRole.DefinitionID).To(Equal(fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", fakeSubscriptionID, infrav1.ContributorRoleID)))
 }
 
+type fakeHourlyCostEstimator struct {
+	cost float64
+	err  error
+}
+
+func (f fakeHourlyCostEstimator) EstimateHourlyCost(_ context.Context, _ metav1.ObjectMeta, _ string, _ infrav1.OSDisk, _ bool) (float64, error) {
+	return f.cost, f.err
+}
+
+func TestAzureMachinePool_DefaultEstimatedHourlyCost(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, true)()
+
+	tests := []struct {
+		name           string
+		featureEnabled bool
+		costEstimator  infrav1.HourlyCostEstimator
+		want           string
+	}{
+		{
+			name:           "cost estimation is disabled",
+			featureEnabled: false,
+			costEstimator:  fakeHourlyCostEstimator{cost: 0.096},
+		},
+		{
+			name:           "cost is estimated",
+			featureEnabled: true,
+			costEstimator:  fakeHourlyCostEstimator{cost: 0.096},
+			want:           "0.0960",
+		},
+		{
+			name:           "cost estimation fails",
+			featureEnabled: true,
+			costEstimator:  fakeHourlyCostEstimator{err: errors.New("timeout")},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.CostEstimation, tc.featureEnabled)()
+
+			amp := createMachinePoolWithSSHPublicKey(validSSHPublicKey)
+			ampw := &azureMachinePoolWebhook{
+				Client:        mockDefaultClient{Name: "testmachinepool", ClusterName: "testcluster", SubscriptionID: guuid.New().String()},
+				CostEstimator: tc.costEstimator,
+			}
+			g.Expect(ampw.Default(context.Background(), amp)).To(Succeed())
+			if tc.want != "" {
+				g.Expect(amp.Annotations).To(HaveKeyWithValue(infrav1.EstimatedHourlyCostAnnotation, tc.want))
+			} else {
+				g.Expect(amp.Annotations).NotTo(HaveKey(infrav1.EstimatedHourlyCostAnnotation))
+			}
+		})
+	}
+}
+
 func createMachinePoolWithMarketPlaceImage(publisher, offer, sku, version string, terminateNotificationTimeout *int) *AzureMachinePool {
 	image := infrav1.Image{
 		Marketplace: &infrav1.AzureMarketplaceImage{
@@ -544,6 +1712,88 @@ func createMachinePoolWithNetworkConfig(subnetName string, interfaces []infrav1.
 	}
 }
 
+func createMachinePoolWithDiskDeletePolicy(osDiskDeletePolicy, dataDiskDeletePolicy infrav1.DeletePolicy) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{
+				OSDisk: infrav1.OSDisk{
+					DeletePolicy: osDiskDeletePolicy,
+				},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix:   "etcddisk",
+						DeletePolicy: dataDiskDeletePolicy,
+					},
+				},
+			},
+		},
+	}
+}
+
+func createMachinePoolWithSharedDataDisk() *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "shareddisk",
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Premium_LRS",
+							MaxShares:          ptr.To[int32](2),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func createMachinePoolWithNodeOutboundRule(rule string) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			NodeOutboundRule: rule,
+		},
+	}
+}
+
+func createMachinePoolWithPlacement(mode infrav1.OrchestrationModeType, placement *AzureMachinePoolPlacement) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			OrchestrationMode: mode,
+			Placement:         placement,
+		},
+	}
+}
+
+func createMachinePoolWithPriorityMixPolicy(mode infrav1.OrchestrationModeType, spotVMOptions *infrav1.SpotVMOptions, policy *AzureMachinePoolPriorityMixPolicy) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			OrchestrationMode: mode,
+			Template:          AzureMachinePoolMachineTemplate{SpotVMOptions: spotVMOptions},
+			PriorityMixPolicy: policy,
+		},
+	}
+}
+
+func createMachinePoolWithAdminUsername(adminUsername string) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{
+				AdminUsername: adminUsername,
+			},
+		},
+	}
+}
+
+func createMachinePoolWithUpgradePolicy(mode infrav1.OrchestrationModeType, upgradePolicy *AzureMachinePoolUpgradePolicy) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			OrchestrationMode: mode,
+			UpgradePolicy:     upgradePolicy,
+		},
+	}
+}
+
 func createMachinePoolWithImageByID(imageID string, terminateNotificationTimeout *int) *AzureMachinePool {
 	image := infrav1.Image{
 		ID: &imageID,
@@ -27,6 +27,15 @@ import (
 const (
 	// AzureMachinePoolMachineFinalizer is used to ensure deletion of dependencies (nodes, infra).
 	AzureMachinePoolMachineFinalizer = "azuremachinepoolmachine.infrastructure.cluster.x-k8s.io"
+
+	// ReimageAnnotation is set on an AzureMachinePoolMachine to have its instance drained and updated in place to the
+	// latest model of the scale set. It is removed once the node of the instance is ready again.
+	ReimageAnnotation = "azuremachinepoolmachine.infrastructure.cluster.x-k8s.io/reimage"
+
+	// ReimagedAtAnnotation records when the update of the instance of an AzureMachinePoolMachine to the latest model
+	// completed, in RFC 3339 format. The node of the instance is only uncordoned once it reported it is ready after
+	// that time.
+	ReimagedAtAnnotation = "azuremachinepoolmachine.infrastructure.cluster.x-k8s.io/reimaged-at"
 )
 
 type (
@@ -39,6 +48,24 @@ type (
 		// InstanceID is the identification of the Machine Instance within the VMSS
 		// +optional
 		InstanceID string `json:"instanceID,omitempty"`
+
+		// ProtectionPolicy protects the instance from being removed or changed by the scale set. Protected instances
+		// are also skipped when CAPZ selects the instances to remove or reimage during scale-in and rolling upgrades.
+		// Instance protection is only supported for Uniform orchestration mode scale sets.
+		// +optional
+		ProtectionPolicy *InstanceProtectionPolicy `json:"protectionPolicy,omitempty"`
+	}
+
+	// InstanceProtectionPolicy defines the protection of a scale set instance.
+	InstanceProtectionPolicy struct {
+		// ProtectFromScaleIn protects the instance from being removed when the scale set scales in.
+		// +optional
+		ProtectFromScaleIn bool `json:"protectFromScaleIn,omitempty"`
+
+		// ProtectFromScaleSetActions protects the instance from scale-in and from any action on the scale set, such
+		// as upgrades or reimages, being applied to it.
+		// +optional
+		ProtectFromScaleSetActions bool `json:"protectFromScaleSetActions,omitempty"`
 	}
 
 	// AzureMachinePoolMachineStatus defines the observed state of AzureMachinePoolMachine.
@@ -94,6 +121,28 @@ type (
 		// +optional
 		LatestModelApplied bool `json:"latestModelApplied,omitempty"`
 
+		// PatchStatus is the OS patch status of the instance. It is only reported for instances of Flexible
+		// orchestration mode scale sets.
+		// +optional
+		PatchStatus *infrav1.VMPatchStatus `json:"patchStatus,omitempty"`
+
+		// Zone is the availability zone the instance is placed in.
+		// +optional
+		Zone string `json:"zone,omitempty"`
+
+		// PlatformFaultDomain is the fault domain the instance is placed in. It is only reported for instances of
+		// Flexible orchestration mode scale sets.
+		// +optional
+		PlatformFaultDomain *int32 `json:"platformFaultDomain,omitempty"`
+
+		// Addresses contains the private and public IP addresses of the instance.
+		// +optional
+		Addresses []corev1.NodeAddress `json:"addresses,omitempty"`
+
+		// NetworkInterfaceIDs are the resource IDs of the network interfaces of the instance.
+		// +optional
+		NetworkInterfaceIDs []string `json:"networkInterfaceIDs,omitempty"`
+
 		// Ready is true when the provider resource is ready.
 		// +optional
 		Ready bool `json:"ready"`
@@ -129,6 +178,17 @@ type (
 	}
 )
 
+// IsProtectedFromScaleIn returns true if the instance must not be removed when the scale set scales in.
+func (ampm *AzureMachinePoolMachine) IsProtectedFromScaleIn() bool {
+	policy := ampm.Spec.ProtectionPolicy
+	return policy != nil && (policy.ProtectFromScaleIn || policy.ProtectFromScaleSetActions)
+}
+
+// IsProtectedFromScaleSetActions returns true if the instance must not be upgraded, reimaged or removed by the scale set.
+func (ampm *AzureMachinePoolMachine) IsProtectedFromScaleSetActions() bool {
+	return ampm.Spec.ProtectionPolicy != nil && ampm.Spec.ProtectionPolicy.ProtectFromScaleSetActions
+}
+
 // GetConditions returns the list of conditions for an AzureMachinePool API object.
 func (ampm *AzureMachinePoolMachine) GetConditions() clusterv1.Conditions {
 	return ampm.Status.Conditions
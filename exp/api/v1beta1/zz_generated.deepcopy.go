@@ -58,6 +58,56 @@ func (in *AzureMachinePool) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolAutomaticRepairs) DeepCopyInto(out *AzureMachinePoolAutomaticRepairs) {
+	*out = *in
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.HealthProbe != nil {
+		in, out := &in.HealthProbe, &out.HealthProbe
+		*out = new(AzureMachinePoolHealthProbe)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolAutomaticRepairs.
+func (in *AzureMachinePoolAutomaticRepairs) DeepCopy() *AzureMachinePoolAutomaticRepairs {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolAutomaticRepairs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolBootstrapExtension) DeepCopyInto(out *AzureMachinePoolBootstrapExtension) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RetryInterval != nil {
+		in, out := &in.RetryInterval, &out.RetryInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolBootstrapExtension.
+func (in *AzureMachinePoolBootstrapExtension) DeepCopy() *AzureMachinePoolBootstrapExtension {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolBootstrapExtension)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachinePoolDeploymentStrategy) DeepCopyInto(out *AzureMachinePoolDeploymentStrategy) {
 	*out = *in
@@ -78,6 +128,41 @@ func (in *AzureMachinePoolDeploymentStrategy) DeepCopy() *AzureMachinePoolDeploy
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolDiagnosticsStorageAccount) DeepCopyInto(out *AzureMachinePoolDiagnosticsStorageAccount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolDiagnosticsStorageAccount.
+func (in *AzureMachinePoolDiagnosticsStorageAccount) DeepCopy() *AzureMachinePoolDiagnosticsStorageAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolDiagnosticsStorageAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolInstancePlacement) DeepCopyInto(out *AzureMachinePoolInstancePlacement) {
+	*out = *in
+	if in.PlatformFaultDomain != nil {
+		in, out := &in.PlatformFaultDomain, &out.PlatformFaultDomain
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolInstancePlacement.
+func (in *AzureMachinePoolInstancePlacement) DeepCopy() *AzureMachinePoolInstancePlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolInstancePlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachinePoolInstanceStatus) DeepCopyInto(out *AzureMachinePoolInstanceStatus) {
 	*out = *in
@@ -98,6 +183,26 @@ func (in *AzureMachinePoolInstanceStatus) DeepCopy() *AzureMachinePoolInstanceSt
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolHealthProbe) DeepCopyInto(out *AzureMachinePoolHealthProbe) {
+	*out = *in
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolHealthProbe.
+func (in *AzureMachinePoolHealthProbe) DeepCopy() *AzureMachinePoolHealthProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolHealthProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachinePoolList) DeepCopyInto(out *AzureMachinePoolList) {
 	*out = *in
@@ -135,7 +240,7 @@ func (in *AzureMachinePoolMachine) DeepCopyInto(out *AzureMachinePoolMachine) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -192,6 +297,11 @@ func (in *AzureMachinePoolMachineList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachinePoolMachineSpec) DeepCopyInto(out *AzureMachinePoolMachineSpec) {
 	*out = *in
+	if in.ProtectionPolicy != nil {
+		in, out := &in.ProtectionPolicy, &out.ProtectionPolicy
+		*out = new(InstanceProtectionPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolMachineSpec.
@@ -239,6 +349,26 @@ func (in *AzureMachinePoolMachineStatus) DeepCopyInto(out *AzureMachinePoolMachi
 		*out = make(apiv1beta1.Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.PatchStatus != nil {
+		in, out := &in.PatchStatus, &out.PatchStatus
+		*out = new(apiv1beta1.VMPatchStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PlatformFaultDomain != nil {
+		in, out := &in.PlatformFaultDomain, &out.PlatformFaultDomain
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]corev1.NodeAddress, len(*in))
+		copy(*out, *in)
+	}
+	if in.NetworkInterfaceIDs != nil {
+		in, out := &in.NetworkInterfaceIDs, &out.NetworkInterfaceIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolMachineStatus.
@@ -254,6 +384,11 @@ func (in *AzureMachinePoolMachineStatus) DeepCopy() *AzureMachinePoolMachineStat
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachinePoolMachineTemplate) DeepCopyInto(out *AzureMachinePoolMachineTemplate) {
 	*out = *in
+	if in.MinimumVMResources != nil {
+		in, out := &in.MinimumVMResources, &out.MinimumVMResources
+		*out = new(apiv1beta1.MinimumVMResources)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Image != nil {
 		in, out := &in.Image, &out.Image
 		*out = new(apiv1beta1.Image)
@@ -267,6 +402,11 @@ func (in *AzureMachinePoolMachineTemplate) DeepCopyInto(out *AzureMachinePoolMac
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AdditionalSSHPublicKeys != nil {
+		in, out := &in.AdditionalSSHPublicKeys, &out.AdditionalSSHPublicKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.AcceleratedNetworking != nil {
 		in, out := &in.AcceleratedNetworking, &out.AcceleratedNetworking
 		*out = new(bool)
@@ -292,6 +432,25 @@ func (in *AzureMachinePoolMachineTemplate) DeepCopyInto(out *AzureMachinePoolMac
 		*out = new(apiv1beta1.SpotVMOptions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.VaultSecrets != nil {
+		in, out := &in.VaultSecrets, &out.VaultSecrets
+		*out = make([]apiv1beta1.VaultSecretGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VMGalleryApplications != nil {
+		in, out := &in.VMGalleryApplications, &out.VMGalleryApplications
+		*out = make([]apiv1beta1.VMGalleryApplication, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BootstrapEncryption != nil {
+		in, out := &in.BootstrapEncryption, &out.BootstrapEncryption
+		*out = new(apiv1beta1.BootstrapEncryption)
+		**out = **in
+	}
 	if in.VMExtensions != nil {
 		in, out := &in.VMExtensions, &out.VMExtensions
 		*out = make([]apiv1beta1.VMExtension, len(*in))
@@ -299,6 +458,11 @@ func (in *AzureMachinePoolMachineTemplate) DeepCopyInto(out *AzureMachinePoolMac
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.BootstrapExtension != nil {
+		in, out := &in.BootstrapExtension, &out.BootstrapExtension
+		*out = new(AzureMachinePoolBootstrapExtension)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.NetworkInterfaces != nil {
 		in, out := &in.NetworkInterfaces, &out.NetworkInterfaces
 		*out = make([]apiv1beta1.NetworkInterface, len(*in))
@@ -306,6 +470,18 @@ func (in *AzureMachinePoolMachineTemplate) DeepCopyInto(out *AzureMachinePoolMac
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.NodeLabels != nil {
+		in, out := &in.NodeLabels, &out.NodeLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeTaints != nil {
+		in, out := &in.NodeTaints, &out.NodeTaints
+		*out = make(apiv1beta1.Taints, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolMachineTemplate.
@@ -318,6 +494,153 @@ func (in *AzureMachinePoolMachineTemplate) DeepCopy() *AzureMachinePoolMachineTe
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolPatchStatus) DeepCopyInto(out *AzureMachinePoolPatchStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolPatchStatus.
+func (in *AzureMachinePoolPatchStatus) DeepCopy() *AzureMachinePoolPatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolPatchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolPlacement) DeepCopyInto(out *AzureMachinePoolPlacement) {
+	*out = *in
+	if in.ZoneBalance != nil {
+		in, out := &in.ZoneBalance, &out.ZoneBalance
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PlatformFaultDomainCount != nil {
+		in, out := &in.PlatformFaultDomainCount, &out.PlatformFaultDomainCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SinglePlacementGroup != nil {
+		in, out := &in.SinglePlacementGroup, &out.SinglePlacementGroup
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]AzureMachinePoolInstancePlacement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolPlacement.
+func (in *AzureMachinePoolPlacement) DeepCopy() *AzureMachinePoolPlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolPlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolPriorityMixPolicy) DeepCopyInto(out *AzureMachinePoolPriorityMixPolicy) {
+	*out = *in
+	if in.BaseRegularPriorityCount != nil {
+		in, out := &in.BaseRegularPriorityCount, &out.BaseRegularPriorityCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RegularPriorityPercentageAboveBase != nil {
+		in, out := &in.RegularPriorityPercentageAboveBase, &out.RegularPriorityPercentageAboveBase
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolPriorityMixPolicy.
+func (in *AzureMachinePoolPriorityMixPolicy) DeepCopy() *AzureMachinePoolPriorityMixPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolPriorityMixPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolRollingUpgradePolicy) DeepCopyInto(out *AzureMachinePoolRollingUpgradePolicy) {
+	*out = *in
+	if in.MaxBatchInstancePercent != nil {
+		in, out := &in.MaxBatchInstancePercent, &out.MaxBatchInstancePercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PauseTime != nil {
+		in, out := &in.PauseTime, &out.PauseTime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolRollingUpgradePolicy.
+func (in *AzureMachinePoolRollingUpgradePolicy) DeepCopy() *AzureMachinePoolRollingUpgradePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolRollingUpgradePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolReservationCoverage) DeepCopyInto(out *AzureMachinePoolReservationCoverage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolReservationCoverage.
+func (in *AzureMachinePoolReservationCoverage) DeepCopy() *AzureMachinePoolReservationCoverage {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolReservationCoverage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolRolloutStatus) DeepCopyInto(out *AzureMachinePoolRolloutStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolRolloutStatus.
+func (in *AzureMachinePoolRolloutStatus) DeepCopy() *AzureMachinePoolRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolScaleInPolicy) DeepCopyInto(out *AzureMachinePoolScaleInPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolScaleInPolicy.
+func (in *AzureMachinePoolScaleInPolicy) DeepCopy() *AzureMachinePoolScaleInPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolScaleInPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachinePoolSpec) DeepCopyInto(out *AzureMachinePoolSpec) {
 	*out = *in
@@ -350,6 +673,41 @@ func (in *AzureMachinePoolSpec) DeepCopyInto(out *AzureMachinePoolSpec) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(AzureMachinePoolPlacement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradePolicy != nil {
+		in, out := &in.UpgradePolicy, &out.UpgradePolicy
+		*out = new(AzureMachinePoolUpgradePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutomaticRepairs != nil {
+		in, out := &in.AutomaticRepairs, &out.AutomaticRepairs
+		*out = new(AzureMachinePoolAutomaticRepairs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PriorityMixPolicy != nil {
+		in, out := &in.PriorityMixPolicy, &out.PriorityMixPolicy
+		*out = new(AzureMachinePoolPriorityMixPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleInPolicy != nil {
+		in, out := &in.ScaleInPolicy, &out.ScaleInPolicy
+		*out = new(AzureMachinePoolScaleInPolicy)
+		**out = **in
+	}
+	if in.StandbyPool != nil {
+		in, out := &in.StandbyPool, &out.StandbyPool
+		*out = new(AzureMachinePoolStandbyPool)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiagnosticsStorageAccount != nil {
+		in, out := &in.DiagnosticsStorageAccount, &out.DiagnosticsStorageAccount
+		*out = new(AzureMachinePoolDiagnosticsStorageAccount)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolSpec.
@@ -362,6 +720,26 @@ func (in *AzureMachinePoolSpec) DeepCopy() *AzureMachinePoolSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolStandbyPool) DeepCopyInto(out *AzureMachinePoolStandbyPool) {
+	*out = *in
+	if in.MinReadyCapacity != nil {
+		in, out := &in.MinReadyCapacity, &out.MinReadyCapacity
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolStandbyPool.
+func (in *AzureMachinePoolStandbyPool) DeepCopy() *AzureMachinePoolStandbyPool {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolStandbyPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachinePoolStatus) DeepCopyInto(out *AzureMachinePoolStatus) {
 	*out = *in
@@ -408,6 +786,21 @@ func (in *AzureMachinePoolStatus) DeepCopyInto(out *AzureMachinePoolStatus) {
 		*out = make(apiv1beta1.Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.PatchStatus != nil {
+		in, out := &in.PatchStatus, &out.PatchStatus
+		*out = new(AzureMachinePoolPatchStatus)
+		**out = **in
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(AzureMachinePoolRolloutStatus)
+		**out = **in
+	}
+	if in.ReservationCoverage != nil {
+		in, out := &in.ReservationCoverage, &out.ReservationCoverage
+		*out = new(AzureMachinePoolReservationCoverage)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolStatus.
@@ -420,6 +813,41 @@ func (in *AzureMachinePoolStatus) DeepCopy() *AzureMachinePoolStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolUpgradePolicy) DeepCopyInto(out *AzureMachinePoolUpgradePolicy) {
+	*out = *in
+	if in.RollingUpgrade != nil {
+		in, out := &in.RollingUpgrade, &out.RollingUpgrade
+		*out = new(AzureMachinePoolRollingUpgradePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolUpgradePolicy.
+func (in *AzureMachinePoolUpgradePolicy) DeepCopy() *AzureMachinePoolUpgradePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolUpgradePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceProtectionPolicy) DeepCopyInto(out *InstanceProtectionPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceProtectionPolicy.
+func (in *InstanceProtectionPolicy) DeepCopy() *InstanceProtectionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceProtectionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineRollingUpdateDeployment) DeepCopyInto(out *MachineRollingUpdateDeployment) {
 	*out = *in
@@ -39,6 +39,7 @@ import (
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -209,6 +210,7 @@ func (ampr *AzureMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.
 	}
 
 	logger = logger.WithValues("cluster", cluster.Name)
+	ctx = azure.WithUserAgentSuffix(ctx, cluster)
 
 	logger = logger.WithValues("AzureCluster", cluster.Spec.InfrastructureRef.Name)
 	azureClusterName := client.ObjectKey{
@@ -264,6 +266,24 @@ func (ampr *AzureMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.
 	return ampr.reconcileNormal(ctx, machinePoolScope, clusterScope)
 }
 
+// validateNodeOutboundRule checks that the additional outbound rule referenced by the AzureMachinePool exists on the
+// node outbound load balancer of the cluster.
+func validateNodeOutboundRule(amp *infrav1exp.AzureMachinePool, nodeOutboundLB *infrav1.LoadBalancerSpec) error {
+	rule := amp.Spec.NodeOutboundRule
+	if rule == "" || amp.Spec.OutboundType == infrav1exp.NATGatewayOutboundType || amp.Spec.OutboundType == infrav1exp.NoneOutboundType {
+		return nil
+	}
+	if nodeOutboundLB == nil {
+		return errors.Errorf("node outbound rule %s not found: the cluster has no node outbound load balancer", rule)
+	}
+	for _, outboundRule := range nodeOutboundLB.AdditionalOutboundRules {
+		if outboundRule.Name == rule {
+			return nil
+		}
+	}
+	return errors.Errorf("node outbound rule %s not found in the additional outbound rules of node outbound load balancer %s", rule, nodeOutboundLB.Name)
+}
+
 func (ampr *AzureMachinePoolReconciler) reconcileNormal(ctx context.Context, machinePoolScope *scope.MachinePoolScope, clusterScope *scope.ClusterScope) (_ reconcile.Result, reterr error) {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureMachinePoolReconciler.reconcileNormal")
 	defer done()
@@ -288,6 +308,15 @@ func (ampr *AzureMachinePoolReconciler) reconcileNormal(ctx context.Context, mac
 		return reconcile.Result{}, nil
 	}
 
+	// The scale set would join a backend pool that doesn't exist. The AzureMachinePool is reconciled again when the
+	// AzureCluster changes.
+	if err := validateNodeOutboundRule(machinePoolScope.AzureMachinePool, clusterScope.NodeOutboundLB()); err != nil {
+		log.Info("Node outbound rule not found", "reason", err.Error())
+		ampr.Recorder.Eventf(machinePoolScope.AzureMachinePool, corev1.EventTypeWarning, infrav1.NodeOutboundRuleNotFoundReason, err.Error())
+		conditions.MarkFalse(machinePoolScope.AzureMachinePool, infrav1.ScaleSetRunningCondition, infrav1.NodeOutboundRuleNotFoundReason, clusterv1.ConditionSeverityError, err.Error())
+		return reconcile.Result{}, nil
+	}
+
 	// Make sure bootstrap data is available and populated.
 	if machinePoolScope.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName == nil {
 		log.Info("Bootstrap data secret reference is not yet available")
@@ -380,6 +409,12 @@ func (ampr *AzureMachinePoolReconciler) reconcileDelete(ctx context.Context, mac
 
 	log.V(2).Info("handling deleted AzureMachinePool")
 
+	if infracontroller.IsDeletionProtected(clusterScope) {
+		log.V(2).Info("skipping AzureMachinePool deletion since the AzureCluster has deletion protection enabled")
+		conditions.MarkFalse(machinePoolScope.AzureMachinePool, infrav1.ScaleSetRunningCondition, infrav1.DeletionProtectedReason, clusterv1.ConditionSeverityWarning, "AzureCluster has deletion protection enabled")
+		return reconcile.Result{}, nil
+	}
+
 	if infracontroller.ShouldDeleteIndividualResources(ctx, clusterScope) {
 		amps, err := ampr.createAzureMachinePoolService(machinePoolScope)
 		if err != nil {
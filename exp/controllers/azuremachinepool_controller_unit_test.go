@@ -50,8 +50,10 @@ func Test_newAzureMachinePoolService(t *testing.T) {
 	clusterMock.EXPECT().SubscriptionID().AnyTimes()
 	clusterMock.EXPECT().BaseURI().AnyTimes()
 	clusterMock.EXPECT().Authorizer().AnyTimes()
-	clusterMock.EXPECT().Location().Return(cluster.Spec.Location)
-	clusterMock.EXPECT().HashKey().Return("fakeCluster")
+	clusterMock.EXPECT().CloudEnvironment().AnyTimes()
+	clusterMock.EXPECT().Token().AnyTimes()
+	clusterMock.EXPECT().Location().Return(cluster.Spec.Location).Times(2)
+	clusterMock.EXPECT().HashKey().Return("fakeCluster").Times(2)
 
 	mps := &scope.MachinePoolScope{
 		ClusterScoper: clusterMock,
@@ -146,3 +148,55 @@ func newCluster(name string) *clusterv1.Cluster {
 		},
 	}
 }
+
+func Test_validateNodeOutboundRule(t *testing.T) {
+	nodeOutboundLB := &infrav1.LoadBalancerSpec{
+		Name: "my-cluster",
+		AdditionalOutboundRules: []infrav1.OutboundRule{
+			{Name: "egress"},
+		},
+	}
+	tests := []struct {
+		name           string
+		spec           infrav1exp.AzureMachinePoolSpec
+		nodeOutboundLB *infrav1.LoadBalancerSpec
+		wantErr        string
+	}{
+		{
+			name:           "no node outbound rule",
+			nodeOutboundLB: nodeOutboundLB,
+		},
+		{
+			name:           "existing node outbound rule",
+			spec:           infrav1exp.AzureMachinePoolSpec{NodeOutboundRule: "egress"},
+			nodeOutboundLB: nodeOutboundLB,
+		},
+		{
+			name:           "missing node outbound rule",
+			spec:           infrav1exp.AzureMachinePoolSpec{NodeOutboundRule: "other"},
+			nodeOutboundLB: nodeOutboundLB,
+			wantErr:        "node outbound rule other not found in the additional outbound rules of node outbound load balancer my-cluster",
+		},
+		{
+			name:    "cluster without node outbound load balancer",
+			spec:    infrav1exp.AzureMachinePoolSpec{NodeOutboundRule: "egress"},
+			wantErr: "node outbound rule egress not found: the cluster has no node outbound load balancer",
+		},
+		{
+			name: "node outbound rule ignored with a NAT gateway",
+			spec: infrav1exp.AzureMachinePoolSpec{NodeOutboundRule: "other", OutboundType: infrav1exp.NATGatewayOutboundType},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := validateNodeOutboundRule(&infrav1exp.AzureMachinePool{Spec: tc.spec}, tc.nodeOutboundLB)
+			if tc.wantErr != "" {
+				g.Expect(err).To(MatchError(tc.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -22,9 +22,15 @@ import (
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/reservations"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/retailprices"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/standbypools"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/storageaccounts"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensionimages"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -41,12 +47,33 @@ func newAzureMachinePoolService(machinePoolScope *scope.MachinePoolScope) (*azur
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create a NewCache")
 	}
+	extensionImagesCache, err := vmextensionimages.GetCache(machinePoolScope, machinePoolScope.Location())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create a VM extension images cache")
+	}
+	retailPricesSvc, err := retailprices.New(machinePoolScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create retail prices service")
+	}
+	reservationsSvc, err := reservations.New(machinePoolScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create reservations service")
+	}
+	standbyPoolsSvc, err := standbypools.New(machinePoolScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create standby pools service")
+	}
 
 	return &azureMachinePoolService{
 		scope: machinePoolScope,
 		services: []azure.ServiceReconciler{
-			scalesets.New(machinePoolScope, cache),
+			storageaccounts.New(machinePoolScope),
+			scalesets.New(machinePoolScope, cache, extensionImagesCache),
+			standbyPoolsSvc,
 			roleassignments.New(machinePoolScope),
+			tags.New(machinePoolScope),
+			retailPricesSvc,
+			reservationsSvc,
 		},
 		skuCache: cache,
 	}, nil
@@ -64,6 +91,10 @@ func (s *azureMachinePoolService) Reconcile(ctx context.Context) error {
 		return errors.Wrap(err, "failed defaulting subnet name")
 	}
 
+	if err := s.scope.ValidateOutboundType(); err != nil {
+		return errors.Wrap(err, "invalid outbound type")
+	}
+
 	for _, service := range s.services {
 		if err := service.Reconcile(ctx); err != nil {
 			return errors.Wrapf(err, "failed to reconcile AzureMachinePool service %s", service.Name())
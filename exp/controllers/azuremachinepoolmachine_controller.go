@@ -179,6 +179,7 @@ func (ampmr *AzureMachinePoolMachineController) Reconcile(ctx context.Context, r
 	}
 
 	logger = logger.WithValues("cluster", cluster.Name)
+	ctx = azure.WithUserAgentSuffix(ctx, cluster)
 
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, machine) {
@@ -369,6 +370,37 @@ func (r *azureMachinePoolMachineReconciler) Reconcile(ctx context.Context) error
 		return errors.Wrap(err, "failed to update VMSS VM instance status")
 	}
 
+	if r.Scope.IsReimageRequested() {
+		return r.reimage(ctx)
+	}
+
+	return nil
+}
+
+// reimage will attempt to drain the node and update the Azure VMSS VM to the latest model in place. Once the update
+// completed and the node is ready again, the node is uncordoned and the reimage request is cleared.
+func (r *azureMachinePoolMachineReconciler) reimage(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureMachinePoolMachineReconciler.reimage")
+	defer done()
+
+	if r.Scope.IsReimaged() {
+		if err := r.Scope.FinishReimage(ctx); err != nil {
+			return errors.Wrap(err, "failed to finish reimaging the scalesetVM")
+		}
+
+		return nil
+	}
+
+	if err := r.Scope.CordonAndDrain(ctx); err != nil {
+		return errors.Wrap(err, "failed to cordon and drain the scalesetVM")
+	}
+
+	// Reimage only returns without error once the update of the instance completed.
+	if err := r.scalesetVMsService.Reimage(ctx); err != nil {
+		return errors.Wrap(err, "failed to reimage the scalesetVM")
+	}
+
+	r.Scope.MarkReimaged()
 	return nil
 }
 
@@ -43,6 +43,36 @@ const (
 	// alpha: v1.7
 	AKSResourceHealth featuregate.Feature = "AKSResourceHealth"
 
+	// ResourceHealth is the feature gate for reporting Azure Resource Health
+	// on AzureMachines and AzureClusters.
+	// alpha: v1.11
+	ResourceHealth featuregate.Feature = "ResourceHealth"
+
+	// CostEstimation is the feature gate for annotating AzureMachines and AzureMachinePools
+	// with the estimated hourly cost of their VMs from the Azure Retail Prices API.
+	// alpha: v1.11
+	CostEstimation featuregate.Feature = "CostEstimation"
+
+	// ReservationCoverage is the feature gate for reporting in the status of AzureMachinePools whether their instances
+	// are covered by the reserved VM instances of their subscription, according to the Azure Reservations API.
+	// alpha: v1.11
+	ReservationCoverage featuregate.Feature = "ReservationCoverage"
+
+	// ResourceProviderRegistration is the feature gate for checking that the subscription of AzureClusters and
+	// AzureManagedControlPlanes is registered with the required resource providers, and registering it with them.
+	// alpha: v1.11
+	ResourceProviderRegistration featuregate.Feature = "ResourceProviderRegistration"
+
+	// ResourceGroupWhatIf is the feature gate for running ARM what-if operations before CAPZ creates or updates
+	// resource groups through the Azure SDK, and recording their result on the cluster object.
+	// alpha: v1.11
+	ResourceGroupWhatIf featuregate.Feature = "ResourceGroupWhatIf"
+
+	// APIVersionOverrides is the feature gate for overriding the ARM API version requested by the SDK v2 clients of
+	// specific services, e.g. when the API version of their SDK is retired or unavailable in a cloud.
+	// alpha: v1.11
+	APIVersionOverrides featuregate.Feature = "APIVersionOverrides"
+
 	// EdgeZone is the feature gate for creating clusters on public MEC.
 	// owner: @upxinxin
 	// alpha: v1.8
@@ -57,7 +87,13 @@ func init() {
 // To add a new feature, define a key for it above and add it here.
 var defaultCAPZFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	// Every feature should be initiated here:
-	AKS:               {Default: true, PreRelease: featuregate.GA, LockToDefault: true}, // Remove in 1.12
-	AKSResourceHealth: {Default: false, PreRelease: featuregate.Alpha},
-	EdgeZone:          {Default: false, PreRelease: featuregate.Alpha},
+	AKS:                          {Default: true, PreRelease: featuregate.GA, LockToDefault: true}, // Remove in 1.12
+	AKSResourceHealth:            {Default: false, PreRelease: featuregate.Alpha},
+	ResourceHealth:               {Default: false, PreRelease: featuregate.Alpha},
+	CostEstimation:               {Default: false, PreRelease: featuregate.Alpha},
+	ReservationCoverage:          {Default: false, PreRelease: featuregate.Alpha},
+	ResourceProviderRegistration: {Default: false, PreRelease: featuregate.Alpha},
+	ResourceGroupWhatIf:          {Default: false, PreRelease: featuregate.Alpha},
+	APIVersionOverrides:          {Default: false, PreRelease: featuregate.Alpha},
+	EdgeZone:                     {Default: false, PreRelease: featuregate.Alpha},
 }
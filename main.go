@@ -18,6 +18,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	_ "net/http/pprof"
@@ -38,6 +39,8 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/controllers"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	infrav1controllersexp "sigs.k8s.io/cluster-api-provider-azure/exp/controllers"
@@ -109,6 +112,7 @@ var (
 	webhookPort                        int
 	reconcileTimeout                   time.Duration
 	enableTracing                      bool
+	apiVersionOverrides                map[string]string
 )
 
 // InitFlags initializes all command-line flags.
@@ -235,6 +239,12 @@ func InitFlags(fs *pflag.FlagSet) {
 		"Enable tracing to the opentelemetry-collector service in the same namespace.",
 	)
 
+	fs.StringToStringVar(&apiVersionOverrides,
+		"api-version-overrides",
+		nil,
+		"ARM API versions used by the SDK v2 clients of services instead of the API version of their SDK, keyed by service name (e.g. natgateways=2023-06-01). Only the api-version query parameter changes, the request and response bodies are still those of the SDK. Requires the APIVersionOverrides feature gate.",
+	)
+
 	feature.MutableGates.AddFlag(fs)
 }
 
@@ -245,6 +255,18 @@ func main() {
 
 	ctrl.SetLogger(klogr.New())
 
+	if len(apiVersionOverrides) > 0 {
+		if !feature.Gates.Enabled(feature.APIVersionOverrides) {
+			setupLog.Error(errors.New("the APIVersionOverrides feature gate is disabled"), "unable to use API version overrides")
+			os.Exit(1)
+		}
+		if err := azure.SetAPIVersionOverrides(apiVersionOverrides); err != nil {
+			setupLog.Error(err, "unable to use API version overrides")
+			os.Exit(1)
+		}
+		setupLog.Info("Overriding ARM API versions", "overrides", apiVersionOverrides)
+	}
+
 	if watchNamespace != "" {
 		setupLog.Info("Watching cluster-api objects only in namespace for reconciliation", "namespace", watchNamespace)
 	}
@@ -517,22 +539,24 @@ func registerWebhooks(mgr manager.Manager) {
 		os.Exit(1)
 	}
 
-	if err := infrav1exp.SetupAzureMachinePoolWebhookWithManager(mgr); err != nil {
+	if err := infrav1exp.SetupAzureMachinePoolWebhookWithManager(mgr, scope.NewVMExtensionVersionsGetter(mgr.GetClient()),
+		scope.NewHourlyCostEstimator(mgr.GetClient())); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AzureMachinePool")
 		os.Exit(1)
 	}
 
-	if err := infrav1.SetupAzureMachineWebhookWithManager(mgr); err != nil {
+	if err := infrav1.SetupAzureMachineWebhookWithManager(mgr, scope.NewUltraSSDZonesGetter(mgr.GetClient()), scope.NewDedicatedHostGroupZonesGetter(mgr.GetClient()),
+		scope.NewVMExtensionVersionsGetter(mgr.GetClient()), scope.NewHourlyCostEstimator(mgr.GetClient())); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AzureMachine")
 		os.Exit(1)
 	}
 
-	if err := infrav1.SetupAzureManagedMachinePoolWebhookWithManager(mgr); err != nil {
+	if err := infrav1.SetupAzureManagedMachinePoolWebhookWithManager(mgr, scope.NewEphemeralOSDiskGetter(mgr.GetClient())); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AzureManagedMachinePool")
 		os.Exit(1)
 	}
 
-	if err := infrav1.SetupAzureManagedControlPlaneWebhookWithManager(mgr); err != nil {
+	if err := infrav1.SetupAzureManagedControlPlaneWebhookWithManager(mgr, scope.NewAKSUpgradesGetter(mgr.GetClient())); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AzureManagedControlPlane")
 		os.Exit(1)
 	}
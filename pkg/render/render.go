@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package render builds the payloads CAPZ sends to Azure for a set of Cluster API objects, without a management
+// cluster or Azure credentials. It is meant for tools that preview or lint what CAPZ would create.
+//
+// The objects must be defaulted as by the CAPZ webhooks, e.g. by reading them back from a cluster running CAPZ, and
+// the payloads are those of resources created from scratch, as the existing resources aren't looked up.
+package render
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
+
+// Cluster holds the objects of the cluster the resources are rendered for.
+type Cluster struct {
+	Cluster      *clusterv1.Cluster
+	AzureCluster *infrav1.AzureCluster
+}
+
+// MachineInputs holds the data CAPZ looks up in the management cluster or in Azure while reconciling a machine or a
+// machine pool.
+type MachineInputs struct {
+	// BootstrapData is the content of the bootstrap data secret. It is rendered unencrypted even if bootstrap
+	// encryption is enabled, as encrypting it requires access to Key Vault.
+	BootstrapData []byte
+
+	// Image is the image of the instances. Defaults to the image of the AzureMachine or AzureMachinePool template for
+	// the location of the cluster, and must be set if they don't have one, as the default image is looked up in Azure.
+	Image *infrav1.Image
+
+	// SKU describes the capabilities of the VM size, which decide e.g. whether accelerated networking is enabled.
+	// A zero SKU has no capabilities.
+	SKU resourceskus.SKU
+}
+
+// NetworkSecurityGroups returns the network security groups CAPZ creates for the subnets of the cluster.
+// The parameters are of the SDK type used by the securitygroups service.
+func NetworkSecurityGroups(ctx context.Context, cluster Cluster) ([]interface{}, error) {
+	clusterScope := scope.NewDetachedClusterScope(cluster.Cluster, cluster.AzureCluster)
+	specs := clusterScope.NSGSpecs()
+	nsgs := make([]interface{}, 0, len(specs))
+	for _, spec := range specs {
+		parameters, err := spec.Parameters(ctx, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render network security group %s", spec.ResourceName())
+		}
+		nsgs = append(nsgs, parameters)
+	}
+	return nsgs, nil
+}
+
+// VirtualMachine returns the virtual machine CAPZ creates for an AzureMachine.
+// The parameters are of the SDK type used by the virtualmachines service.
+func VirtualMachine(ctx context.Context, cluster Cluster, machine *clusterv1.Machine, azureMachine *infrav1.AzureMachine, inputs MachineInputs) (interface{}, error) {
+	clusterScope := scope.NewDetachedClusterScope(cluster.Cluster, cluster.AzureCluster)
+	image := inputs.Image
+	if image == nil {
+		image = azureMachine.Spec.Image.ForLocation(clusterScope.Location())
+	}
+	if image == nil {
+		return nil, errors.Errorf("an image is required to render AzureMachine %s", azureMachine.Name)
+	}
+
+	machineScope := scope.NewDetachedMachineScope(clusterScope, machine, azureMachine, &scope.MachineCache{
+		BootstrapData: base64.StdEncoding.EncodeToString(inputs.BootstrapData),
+		VMImage:       image,
+		VMSKU:         inputs.SKU,
+	})
+	parameters, err := machineScope.VMSpec().Parameters(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render virtual machine for AzureMachine %s", azureMachine.Name)
+	}
+	return parameters, nil
+}
+
+// ScaleSet returns the scale set CAPZ creates for an AzureMachinePool.
+// The parameters are of the SDK type used by the scalesets service.
+func ScaleSet(ctx context.Context, cluster Cluster, machinePool *expv1.MachinePool, azureMachinePool *infrav1exp.AzureMachinePool, inputs MachineInputs) (interface{}, error) {
+	clusterScope := scope.NewDetachedClusterScope(cluster.Cluster, cluster.AzureCluster)
+	image := inputs.Image
+	if image == nil {
+		image = azureMachinePool.Spec.Template.Image.ForLocation(clusterScope.Location())
+	}
+	if image == nil {
+		return nil, errors.Errorf("an image is required to render AzureMachinePool %s", azureMachinePool.Name)
+	}
+	if len(azureMachinePool.Spec.Template.NetworkInterfaces) == 0 {
+		return nil, errors.Errorf("AzureMachinePool %s has no network interfaces, it must be defaulted before being rendered", azureMachinePool.Name)
+	}
+
+	bootstrapData := base64.StdEncoding.EncodeToString(inputs.BootstrapData)
+	machinePoolScope := scope.NewDetachedMachinePoolScope(clusterScope, machinePool, azureMachinePool, &scope.MachinePoolCache{
+		BootstrapData: bootstrapData,
+		CustomData:    bootstrapData,
+		VMImage:       image,
+		VMSKU:         inputs.SKU,
+	})
+	parameters, err := machinePoolScope.ScaleSetSpec(ctx).Parameters(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render scale set for AzureMachinePool %s", azureMachinePool.Name)
+	}
+	return parameters, nil
+}
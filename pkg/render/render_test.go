@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func testCluster() Cluster {
+	return Cluster{
+		Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"}},
+		AzureCluster: &infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+				AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+					SubscriptionID: "123",
+					Location:       "westus2",
+				},
+				NetworkSpec: infrav1.NetworkSpec{
+					Vnet: infrav1.VnetSpec{Name: "my-vnet"},
+					Subnets: infrav1.Subnets{
+						{SubnetClassSpec: infrav1.SubnetClassSpec{Name: "control-plane-subnet", Role: infrav1.SubnetControlPlane}, SecurityGroup: infrav1.SecurityGroup{Name: "control-plane-nsg"}},
+						{SubnetClassSpec: infrav1.SubnetClassSpec{Name: "node-subnet", Role: infrav1.SubnetNode}, SecurityGroup: infrav1.SecurityGroup{Name: "node-nsg"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestNetworkSecurityGroups(t *testing.T) {
+	g := NewWithT(t)
+
+	nsgs, err := NetworkSecurityGroups(context.TODO(), testCluster())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(nsgs).To(HaveLen(2))
+	for _, nsg := range nsgs {
+		g.Expect(nsg).To(BeAssignableToTypeOf(network.SecurityGroup{}))
+		g.Expect(nsg.(network.SecurityGroup).Location).To(Equal(ptr.To("westus2")))
+	}
+}
+
+func TestVirtualMachine(t *testing.T) {
+	azureMachine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-machine", Namespace: "default"},
+		Spec: infrav1.AzureMachineSpec{
+			VMSize: "Standard_D2s_v3",
+			OSDisk: infrav1.OSDisk{
+				OSType:      azure.LinuxOS,
+				DiskSizeGB:  ptr.To[int32](128),
+				CachingType: "None",
+				ManagedDisk: &infrav1.ManagedDiskParameters{StorageAccountType: "Premium_LRS"},
+			},
+			SSHPublicKey: base64.StdEncoding.EncodeToString([]byte("ssh-rsa AAAA")),
+		},
+	}
+	machine := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "my-machine", Namespace: "default"}}
+	image := &infrav1.Image{ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/images/my-image")}
+
+	t.Run("without an image", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := VirtualMachine(context.TODO(), testCluster(), machine, azureMachine, MachineInputs{})
+		g.Expect(err).To(MatchError(ContainSubstring("an image is required")))
+	})
+
+	t.Run("with an image", func(t *testing.T) {
+		g := NewWithT(t)
+		vm, err := VirtualMachine(context.TODO(), testCluster(), machine, azureMachine, MachineInputs{
+			BootstrapData: []byte("#cloud-config"),
+			Image:         image,
+		})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(vm).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+		g.Expect(vm.(compute.VirtualMachine).OsProfile.CustomData).To(Equal(ptr.To(base64.StdEncoding.EncodeToString([]byte("#cloud-config")))))
+	})
+
+	t.Run("with an image override for the location of the cluster", func(t *testing.T) {
+		g := NewWithT(t)
+		azureMachine := azureMachine.DeepCopy()
+		azureMachine.Spec.Image = &infrav1.Image{
+			LocationOverrides: []infrav1.ImageLocationOverride{
+				{Location: "eastus", ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/images/eastus-image")},
+				{Location: "westus2", ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/images/westus2-image")},
+			},
+		}
+		vm, err := VirtualMachine(context.TODO(), testCluster(), machine, azureMachine, MachineInputs{BootstrapData: []byte("#cloud-config")})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(vm.(compute.VirtualMachine).StorageProfile.ImageReference.ID).To(Equal(ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/images/westus2-image")))
+	})
+
+	t.Run("without an image for the location of the cluster", func(t *testing.T) {
+		g := NewWithT(t)
+		azureMachine := azureMachine.DeepCopy()
+		azureMachine.Spec.Image = &infrav1.Image{
+			LocationOverrides: []infrav1.ImageLocationOverride{
+				{Location: "eastus", ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/images/eastus-image")},
+			},
+		}
+		_, err := VirtualMachine(context.TODO(), testCluster(), machine, azureMachine, MachineInputs{})
+		g.Expect(err).To(MatchError(ContainSubstring("an image is required")))
+	})
+}
@@ -0,0 +1,380 @@
+//go:build e2e
+// +build e2e
+
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-08-01/network"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8snet "k8s.io/utils/net"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	deploymentBuilder "sigs.k8s.io/cluster-api-provider-azure/test/e2e/kubernetes/deployment"
+	"sigs.k8s.io/cluster-api-provider-azure/test/e2e/kubernetes/job"
+	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/framework"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	AzureDualStackSpecName = "azure-dual-stack"
+
+	// dualStackEgressEndpoint is an external endpoint reachable over both IPv4 and IPv6.
+	dualStackEgressEndpoint = "https://www.google.com"
+)
+
+// AzureDualStackSpecInput is the input for AzureDualStackSpec.
+type AzureDualStackSpecInput struct {
+	BootstrapClusterProxy framework.ClusterProxy
+	Namespace             *corev1.Namespace
+	ClusterName           string
+	Cluster               *clusterv1.Cluster
+	SkipCleanup           bool
+}
+
+// AzureDualStackSpec implements a test that verifies the Azure resources of a dual-stack cluster have both IPv4 and
+// IPv6 addresses (virtual network, subnets, security groups and the IP configurations of VMs and scale sets), that
+// every node reports an internal address of each family, that pods can reach a dual-stack load balancer service
+// over both, that the load balancers of the cluster have frontends of both families, and that pods can reach an
+// external endpoint over both.
+func AzureDualStackSpec(ctx context.Context, inputGetter func() AzureDualStackSpecInput) {
+	input := inputGetter()
+	Expect(input.BootstrapClusterProxy).NotTo(BeNil(), "Invalid argument. input.BootstrapClusterProxy can't be nil when calling %s spec", AzureDualStackSpecName)
+	Expect(input.Namespace).NotTo(BeNil(), "Invalid argument. input.Namespace can't be nil when calling %s spec", AzureDualStackSpecName)
+	Expect(input.ClusterName).NotTo(BeEmpty(), "Invalid argument. input.ClusterName can't be empty when calling %s spec", AzureDualStackSpecName)
+	Expect(input.Cluster).NotTo(BeNil(), "Invalid argument. input.Cluster can't be nil when calling %s spec", AzureDualStackSpecName)
+
+	mgmtClient := input.BootstrapClusterProxy.GetClient()
+	Expect(mgmtClient).NotTo(BeNil())
+	workloadClusterProxy := input.BootstrapClusterProxy.GetWorkloadCluster(ctx, input.Namespace.Name, input.ClusterName)
+	Expect(workloadClusterProxy).NotTo(BeNil())
+	clientset := workloadClusterProxy.GetClientSet()
+	Expect(clientset).NotTo(BeNil())
+
+	settings, err := auth.GetSettingsFromEnvironment()
+	Expect(err).NotTo(HaveOccurred())
+	subscriptionID := settings.GetSubscriptionID()
+	authorizer, err := azureutil.GetAuthorizer(settings)
+	Expect(err).NotTo(HaveOccurred())
+
+	vnetsClient := network.NewVirtualNetworksClient(subscriptionID)
+	vnetsClient.Authorizer = authorizer
+	subnetsClient := network.NewSubnetsClient(subscriptionID)
+	subnetsClient.Authorizer = authorizer
+	securityGroupsClient := network.NewSecurityGroupsClient(subscriptionID)
+	securityGroupsClient.Authorizer = authorizer
+	interfacesClient := network.NewInterfacesClient(subscriptionID)
+	interfacesClient.Authorizer = authorizer
+	vmssClient := compute.NewVirtualMachineScaleSetsClient(subscriptionID)
+	vmssClient.Authorizer = authorizer
+	loadBalancersClient := network.NewLoadBalancersClient(subscriptionID)
+	loadBalancersClient.Authorizer = authorizer
+	publicIPsClient := network.NewPublicIPAddressesClient(subscriptionID)
+	publicIPsClient.Authorizer = authorizer
+
+	azureCluster := &infrav1.AzureCluster{}
+	Expect(mgmtClient.Get(ctx, client.ObjectKey{
+		Namespace: input.Cluster.Spec.InfrastructureRef.Namespace,
+		Name:      input.Cluster.Spec.InfrastructureRef.Name,
+	}, azureCluster)).To(Succeed())
+	vnetSpec := azureCluster.Spec.NetworkSpec.Vnet
+
+	Byf("verifying the virtual network %s has IPv4 and IPv6 address spaces", vnetSpec.Name)
+	vnet, err := vnetsClient.Get(ctx, vnetSpec.ResourceGroup, vnetSpec.Name, "")
+	Expect(err).NotTo(HaveOccurred())
+	Expect(vnet.VirtualNetworkPropertiesFormat).NotTo(BeNil())
+	Expect(vnet.AddressSpace).NotTo(BeNil())
+	expectDualStackCIDRs(ptr.Deref(vnet.AddressSpace.AddressPrefixes, nil))
+
+	for _, subnetSpec := range azureCluster.Spec.NetworkSpec.Subnets {
+		Byf("verifying the subnet %s has IPv4 and IPv6 address prefixes", subnetSpec.Name)
+		subnet, err := subnetsClient.Get(ctx, vnetSpec.ResourceGroup, vnetSpec.Name, subnetSpec.Name, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subnet.SubnetPropertiesFormat).NotTo(BeNil())
+		expectDualStackCIDRs(ptr.Deref(subnet.AddressPrefixes, nil))
+
+		if subnetSpec.Role != infrav1.SubnetControlPlane || subnetSpec.SecurityGroup.Name == "" {
+			continue
+		}
+		Byf("verifying the security group %s allows the control plane traffic over IPv6", subnetSpec.SecurityGroup.Name)
+		securityGroup, err := securityGroupsClient.Get(ctx, azureCluster.Spec.ResourceGroup, subnetSpec.SecurityGroup.Name, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(securityGroup.SecurityGroupPropertiesFormat).NotTo(BeNil())
+		var ipv6Rules []string
+		for _, rule := range ptr.Deref(securityGroup.SecurityRules, nil) {
+			if rule.SecurityRulePropertiesFormat != nil && k8snet.IsIPv6CIDRString(ptr.Deref(rule.SourceAddressPrefix, "")) {
+				ipv6Rules = append(ipv6Rules, ptr.Deref(rule.Name, ""))
+			}
+		}
+		Expect(ipv6Rules).NotTo(BeEmpty(), "security group %s has no IPv6 security rule", subnetSpec.SecurityGroup.Name)
+	}
+
+	clusterLabels := client.MatchingLabels{clusterv1.ClusterNameLabel: input.ClusterName}
+
+	machineList := &infrav1.AzureMachineList{}
+	Expect(mgmtClient.List(ctx, machineList, client.InNamespace(input.Namespace.Name), clusterLabels)).To(Succeed())
+	for _, machine := range machineList.Items {
+		nicName := azure.GenerateNICName(machine.Name, len(machine.Spec.NetworkInterfaces) > 1, 0)
+		Byf("verifying the network interface %s has an IPv6 IP configuration", nicName)
+		nic, err := interfacesClient.Get(ctx, azureCluster.Spec.ResourceGroup, nicName, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nic.InterfacePropertiesFormat).NotTo(BeNil())
+		var versions []network.IPVersion
+		for _, ipConfig := range ptr.Deref(nic.IPConfigurations, nil) {
+			if ipConfig.InterfaceIPConfigurationPropertiesFormat != nil {
+				versions = append(versions, ipConfig.PrivateIPAddressVersion)
+			}
+		}
+		Expect(versions).To(ContainElements(network.IPVersionIPv4, network.IPVersionIPv6))
+	}
+
+	ampList := &infrav1exp.AzureMachinePoolList{}
+	Expect(mgmtClient.List(ctx, ampList, client.InNamespace(input.Namespace.Name), clusterLabels)).To(Succeed())
+	for _, amp := range ampList.Items {
+		Byf("verifying the scale set of AzureMachinePool %s has IPv6 IP configurations", amp.Name)
+		resourceID, err := azureutil.ParseResourceID(amp.Spec.ProviderID)
+		Expect(err).NotTo(HaveOccurred())
+		vmss, err := vmssClient.Get(ctx, resourceID.ResourceGroupName, resourceID.Name, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vmss.VirtualMachineScaleSetProperties).NotTo(BeNil())
+		Expect(vmss.VirtualMachineProfile).NotTo(BeNil())
+		Expect(vmss.VirtualMachineProfile.NetworkProfile).NotTo(BeNil())
+		for _, nicConfig := range ptr.Deref(vmss.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations, nil) {
+			Expect(nicConfig.VirtualMachineScaleSetNetworkConfigurationProperties).NotTo(BeNil())
+			var versions []compute.IPVersion
+			for _, ipConfig := range ptr.Deref(nicConfig.IPConfigurations, nil) {
+				if ipConfig.VirtualMachineScaleSetIPConfigurationProperties != nil {
+					versions = append(versions, ipConfig.PrivateIPAddressVersion)
+				}
+			}
+			Expect(versions).To(ContainElements(compute.IPVersionIPv4, compute.IPVersionIPv6))
+		}
+	}
+
+	By("verifying every node has an IPv4 and an IPv6 internal address")
+	nodeList, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(nodeList.Items).NotTo(BeEmpty())
+	for _, node := range nodeList.Items {
+		var hasIPv4, hasIPv6 bool
+		for _, address := range node.Status.Addresses {
+			if address.Type != corev1.NodeInternalIP {
+				continue
+			}
+			hasIPv4 = hasIPv4 || k8snet.IsIPv4String(address.Address)
+			hasIPv6 = hasIPv6 || k8snet.IsIPv6String(address.Address)
+		}
+		Expect(hasIPv4).To(BeTrue(), "node %s has no IPv4 internal address", node.Name)
+		Expect(hasIPv6).To(BeTrue(), "node %s has no IPv6 internal address", node.Name)
+	}
+
+	By("creating an HTTP deployment behind a dual-stack load balancer service")
+	deploymentName := "web-dual-stack" + util.RandomString(6)
+	webDeployment := deploymentBuilder.Create("httpd", deploymentName, corev1.NamespaceDefault)
+	webDeployment.AddContainerPort("http", "http", 80, corev1.ProtocolTCP)
+	deployment, err := webDeployment.Deploy(ctx, clientset)
+	Expect(err).NotTo(HaveOccurred())
+	WaitForDeploymentsAvailable(ctx, WaitForDeploymentsAvailableInput{
+		Getter:     deploymentsClientAdapter{client: webDeployment.Client(clientset)},
+		Deployment: deployment,
+		Clientset:  clientset,
+	}, e2eConfig.GetIntervals(AzureDualStackSpecName, "wait-deployment")...)
+
+	servicesClient := clientset.CoreV1().Services(corev1.NamespaceDefault)
+	jobsClient := clientset.BatchV1().Jobs(corev1.NamespaceDefault)
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deploymentName,
+			Namespace: corev1.NamespaceDefault,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:           corev1.ServiceTypeLoadBalancer,
+			IPFamilyPolicy: ptr.To(corev1.IPFamilyPolicyRequireDualStack),
+			IPFamilies:     []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+			Ports:          []corev1.ServicePort{{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP}},
+			Selector:       map[string]string{"app": deploymentName},
+		},
+	}
+	Eventually(func(g Gomega) {
+		_, err := servicesClient.Create(ctx, service, metav1.CreateOptions{})
+		if err != nil {
+			LogWarningf("failed creating service (%s):%s\n", service.Name, err.Error())
+		}
+		g.Expect(err).NotTo(HaveOccurred())
+	}, retryableOperationTimeout, retryableOperationSleepBetweenRetries).Should(Succeed())
+
+	var clusterIPs, ingressIPs []string
+	Eventually(func(g Gomega) {
+		svc, err := servicesClient.Get(ctx, service.Name, metav1.GetOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(svc.Spec.ClusterIPs).To(HaveLen(2))
+		g.Expect(svc.Status.LoadBalancer.Ingress).To(HaveLen(2))
+		clusterIPs = svc.Spec.ClusterIPs
+		ingressIPs = nil
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			ingressIPs = append(ingressIPs, ingress.IP)
+		}
+	}, e2eConfig.GetIntervals(AzureDualStackSpecName, "wait-service")...).Should(Succeed())
+
+	var curlJobs []string
+	runCurlJob := func(name string, command ...string) {
+		curlJob := job.CreateCurlJobResourceSpec(name, "")
+		curlJob.Spec.Template.Spec.Containers[0].Command = command
+		Eventually(func(g Gomega) {
+			_, err := jobsClient.Create(ctx, curlJob, metav1.CreateOptions{})
+			if err != nil {
+				LogWarningf("failed creating job (%s):%s\n", curlJob.Name, err.Error())
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		}, retryableOperationTimeout, retryableOperationSleepBetweenRetries).Should(Succeed())
+		WaitForJobComplete(ctx, WaitForJobCompleteInput{
+			Getter:    jobsClientAdapter{client: jobsClient},
+			Job:       curlJob,
+			Clientset: clientset,
+		}, e2eConfig.GetIntervals(AzureDualStackSpecName, "wait-job")...)
+		curlJobs = append(curlJobs, curlJob.Name)
+	}
+
+	for _, clusterIP := range clusterIPs {
+		family := ipFamilyName(clusterIP)
+		Byf("connecting to the cluster IP of the dual-stack service over %s from a curl pod", family)
+		runCurlJob("curl-dual-stack-"+family+"-job", "curl", "--fail", urlHost(clusterIP))
+	}
+	for _, ingressIP := range ingressIPs {
+		family := ipFamilyName(ingressIP)
+		Byf("connecting to the load balancer IP of the dual-stack service over %s from a curl pod", family)
+		runCurlJob("curl-dual-stack-lb-"+family+"-job", "curl", "--fail", urlHost(ingressIP))
+	}
+
+	nodeLBName := azureCluster.Name
+	if azureCluster.Spec.NetworkSpec.NodeOutboundLB != nil {
+		nodeLBName = azureCluster.Spec.NetworkSpec.NodeOutboundLB.Name
+	}
+	Byf("verifying the node load balancer %s has IPv4 and IPv6 frontends with load balancing rules", nodeLBName)
+	nodeLB, err := loadBalancersClient.Get(ctx, azureCluster.Spec.ResourceGroup, nodeLBName, "")
+	Expect(err).NotTo(HaveOccurred())
+	Expect(nodeLB.LoadBalancerPropertiesFormat).NotTo(BeNil())
+	Expect(ptr.Deref(nodeLB.BackendAddressPools, nil)).NotTo(BeEmpty(), "load balancer %s has no backend pool", nodeLBName)
+	frontendVersions := map[network.IPVersion]bool{}
+	for _, frontend := range ptr.Deref(nodeLB.FrontendIPConfigurations, nil) {
+		if frontend.FrontendIPConfigurationPropertiesFormat == nil || frontend.PublicIPAddress == nil ||
+			ptr.Deref(frontend.LoadBalancingRules, nil) == nil {
+			continue
+		}
+		resourceID, err := azureutil.ParseResourceID(ptr.Deref(frontend.PublicIPAddress.ID, ""))
+		Expect(err).NotTo(HaveOccurred())
+		publicIP, err := publicIPsClient.Get(ctx, resourceID.ResourceGroupName, resourceID.Name, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(publicIP.PublicIPAddressPropertiesFormat).NotTo(BeNil())
+		frontendVersions[publicIP.PublicIPAddressVersion] = true
+	}
+	Expect(frontendVersions).To(HaveKey(network.IPVersionIPv4), "load balancer %s has no IPv4 frontend with load balancing rules", nodeLBName)
+	Expect(frontendVersions).To(HaveKey(network.IPVersionIPv6), "load balancer %s has no IPv6 frontend with load balancing rules", nodeLBName)
+
+	if apiServerLB := azureCluster.Spec.NetworkSpec.APIServerLB; apiServerLB != nil && apiServerLB.Name != "" {
+		Byf("verifying the API server load balancer %s has a frontend, a populated backend pool and a probe", apiServerLB.Name)
+		lb, err := loadBalancersClient.Get(ctx, azureCluster.Spec.ResourceGroup, apiServerLB.Name, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lb.LoadBalancerPropertiesFormat).NotTo(BeNil())
+		Expect(ptr.Deref(lb.FrontendIPConfigurations, nil)).NotTo(BeEmpty(), "load balancer %s has no frontend", apiServerLB.Name)
+		Expect(ptr.Deref(lb.Probes, nil)).NotTo(BeEmpty(), "load balancer %s has no probe", apiServerLB.Name)
+		var backendIPConfigurations int
+		for _, pool := range ptr.Deref(lb.BackendAddressPools, nil) {
+			if pool.BackendAddressPoolPropertiesFormat != nil {
+				backendIPConfigurations += len(ptr.Deref(pool.BackendIPConfigurations, nil))
+			}
+		}
+		Expect(backendIPConfigurations).To(BeNumerically(">", 0), "load balancer %s has an empty backend pool", apiServerLB.Name)
+	}
+
+	for _, flag := range []string{"-4", "-6"} {
+		family := "ipv4"
+		if flag == "-6" {
+			family = "ipv6"
+		}
+		Byf("connecting to %s over %s from a curl pod", dualStackEgressEndpoint, family)
+		runCurlJob("curl-dual-stack-egress-"+family+"-job", "curl", "--fail", flag, dualStackEgressEndpoint)
+	}
+
+	if input.SkipCleanup {
+		return
+	}
+	By("deleting the dual-stack test resources")
+	for _, jobName := range curlJobs {
+		Logf("starting to delete job %s", jobName)
+		Eventually(func(g Gomega) {
+			err := jobsClient.Delete(ctx, jobName, metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				LogWarningf("failed deleting job (%s):%s\n", jobName, err.Error())
+			}
+			g.Expect(client.IgnoreNotFound(err)).NotTo(HaveOccurred())
+		}, deleteOperationTimeout, retryableOperationSleepBetweenRetries).Should(Succeed())
+	}
+	Logf("starting to delete service %s", service.Name)
+	Eventually(func(g Gomega) {
+		err := servicesClient.Delete(ctx, service.Name, metav1.DeleteOptions{})
+		g.Expect(client.IgnoreNotFound(err)).NotTo(HaveOccurred())
+	}, retryableDeleteOperationTimeout, retryableOperationSleepBetweenRetries).Should(Succeed())
+	Logf("starting to delete deployment %s", deployment.Name)
+	Eventually(func(g Gomega) {
+		err := webDeployment.Client(clientset).Delete(ctx, deployment.Name, metav1.DeleteOptions{})
+		g.Expect(client.IgnoreNotFound(err)).NotTo(HaveOccurred())
+	}, deleteOperationTimeout, retryableOperationSleepBetweenRetries).Should(Succeed())
+}
+
+// ipFamilyName returns "ipv6" for an IPv6 address and "ipv4" otherwise.
+func ipFamilyName(ip string) string {
+	if k8snet.IsIPv6String(ip) {
+		return "ipv6"
+	}
+	return "ipv4"
+}
+
+// urlHost returns an IP address in the form it takes as the host of a URL.
+func urlHost(ip string) string {
+	if k8snet.IsIPv6String(ip) {
+		return fmt.Sprintf("[%s]", ip)
+	}
+	return ip
+}
+
+// expectDualStackCIDRs expects a list of address prefixes to contain both an IPv4 and an IPv6 CIDR.
+func expectDualStackCIDRs(cidrs []string) {
+	GinkgoHelper()
+	var hasIPv4, hasIPv6 bool
+	for _, cidr := range cidrs {
+		hasIPv4 = hasIPv4 || k8snet.IsIPv4CIDRString(cidr)
+		hasIPv6 = hasIPv6 || k8snet.IsIPv6CIDRString(cidr)
+	}
+	Expect(hasIPv4).To(BeTrue(), "address prefixes %v have no IPv4 CIDR", cidrs)
+	Expect(hasIPv6).To(BeTrue(), "address prefixes %v have no IPv6 CIDR", cidrs)
+}
@@ -0,0 +1,123 @@
+//go:build e2e
+// +build e2e
+
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/framework"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	AzureMachinePoolSpotEvictionSpecName = "azure-mp-spot-eviction"
+)
+
+// AzureMachinePoolSpotEvictionSpecInput is the input for AzureMachinePoolSpotEvictionSpec.
+type AzureMachinePoolSpotEvictionSpecInput struct {
+	Cluster               *clusterv1.Cluster
+	BootstrapClusterProxy framework.ClusterProxy
+	Namespace             *corev1.Namespace
+	ClusterName           string
+	WaitIntervals         []interface{}
+}
+
+// AzureMachinePoolSpotEvictionSpec implements a test that evicts one Spot instance of each Spot AzureMachinePool
+// using the Simulate Eviction API, then verifies the evicted instance is removed from the pool and the pool
+// recovers to its desired replica count.
+func AzureMachinePoolSpotEvictionSpec(ctx context.Context, inputGetter func() AzureMachinePoolSpotEvictionSpecInput) {
+	input := inputGetter()
+	Expect(input.Cluster).NotTo(BeNil(), "Invalid argument. input.Cluster can't be nil when calling %s spec", AzureMachinePoolSpotEvictionSpecName)
+	Expect(input.BootstrapClusterProxy).NotTo(BeNil(), "Invalid argument. input.BootstrapClusterProxy can't be nil when calling %s spec", AzureMachinePoolSpotEvictionSpecName)
+	Expect(input.Namespace).NotTo(BeNil(), "Invalid argument. input.Namespace can't be nil when calling %s spec", AzureMachinePoolSpotEvictionSpecName)
+	Expect(input.ClusterName).NotTo(BeEmpty(), "Invalid argument. input.ClusterName can't be empty when calling %s spec", AzureMachinePoolSpotEvictionSpecName)
+	Expect(input.WaitIntervals).NotTo(BeEmpty(), "Invalid argument. input.WaitIntervals can't be empty when calling %s spec", AzureMachinePoolSpotEvictionSpecName)
+
+	settings, err := auth.GetSettingsFromEnvironment()
+	Expect(err).NotTo(HaveOccurred())
+	subscriptionID := settings.GetSubscriptionID()
+	authorizer, err := azureutil.GetAuthorizer(settings)
+	Expect(err).NotTo(HaveOccurred())
+
+	vmssVMsClient := compute.NewVirtualMachineScaleSetVMsClient(subscriptionID)
+	vmssVMsClient.Authorizer = authorizer
+	vmsClient := compute.NewVirtualMachinesClient(subscriptionID)
+	vmsClient.Authorizer = authorizer
+
+	mgmtClient := input.BootstrapClusterProxy.GetClient()
+	Expect(mgmtClient).NotTo(BeNil())
+
+	Byf("listing AzureMachinePools for cluster %s in namespace %s", input.ClusterName, input.Namespace.Name)
+	ampList := &infrav1exp.AzureMachinePoolList{}
+	Expect(mgmtClient.List(ctx, ampList, client.InNamespace(input.Namespace.Name), client.MatchingLabels{clusterv1.ClusterNameLabel: input.ClusterName})).To(Succeed())
+
+	spotPools := []infrav1exp.AzureMachinePool{}
+	for _, amp := range ampList.Items {
+		if amp.Spec.Template.SpotVMOptions != nil {
+			spotPools = append(spotPools, amp)
+		}
+	}
+	Expect(spotPools).NotTo(BeEmpty(), "expected at least one AzureMachinePool with spotVMOptions")
+
+	for _, amp := range spotPools {
+		Expect(amp.Spec.ProviderIDList).NotTo(BeEmpty(), "AzureMachinePool %s has no instances to evict", amp.Name)
+		providerID := amp.Spec.ProviderIDList[0]
+		resourceID, err := azureutil.ParseResourceID(providerID)
+		Expect(err).NotTo(HaveOccurred())
+
+		Byf("simulating eviction of Spot instance %s in AzureMachinePool %s", providerID, amp.Name)
+		switch amp.Spec.OrchestrationMode {
+		case infrav1.OrchestrationModeType(compute.OrchestrationModeFlexible):
+			_, err = vmsClient.SimulateEviction(ctx, resourceID.ResourceGroupName, resourceID.Name)
+		default:
+			Expect(resourceID.Parent).NotTo(BeNil())
+			_, err = vmssVMsClient.SimulateEviction(ctx, resourceID.ResourceGroupName, resourceID.Parent.Name, resourceID.Name)
+		}
+		Expect(err).NotTo(HaveOccurred())
+
+		mp, err := azureutil.FindParentMachinePool(amp.Name, mgmtClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mp).NotTo(BeNil())
+
+		Byf("waiting for AzureMachinePool %s to recover to %d replicas", amp.Name, ptr.Deref(mp.Spec.Replicas, 0))
+		Eventually(func(g Gomega) {
+			current := &infrav1exp.AzureMachinePool{}
+			g.Expect(mgmtClient.Get(ctx, client.ObjectKeyFromObject(&amp), current)).To(Succeed())
+			g.Expect(current.Spec.ProviderIDList).NotTo(ContainElement(providerID))
+
+			currentMP := &expv1.MachinePool{}
+			g.Expect(mgmtClient.Get(ctx, client.ObjectKeyFromObject(mp), currentMP)).To(Succeed())
+			replicas := ptr.Deref(currentMP.Spec.Replicas, 0)
+			g.Expect(current.Spec.ProviderIDList).To(HaveLen(int(replicas)))
+			g.Expect(currentMP.Status.ReadyReplicas).To(Equal(replicas))
+		}, input.WaitIntervals...).Should(Succeed())
+	}
+}
@@ -658,6 +658,41 @@ var _ = Describe("Workload cluster creation", func() {
 		})
 	})
 
+	// ci-e2e.sh and Prow CI skip this test by default. To include this test, set `GINKGO_SKIP=""`.
+	Context("Creating a cluster with spot machinepools [OPTIONAL]", func() {
+		It("with 1 control plane node and 1 machinepool", func() {
+			clusterName = getClusterName(clusterNamePrefix, "spot")
+			clusterctl.ApplyClusterTemplateAndWait(ctx, createApplyClusterTemplateInput(
+				specName,
+				withFlavor("machine-pool-spot"),
+				withNamespace(namespace.Name),
+				withClusterName(clusterName),
+				withControlPlaneMachineCount(1),
+				withWorkerMachineCount(1),
+				withMachineDeploymentInterval(specName, ""),
+				withControlPlaneWaiters(clusterctl.ControlPlaneWaiters{
+					WaitForControlPlaneInitialized: EnsureControlPlaneInitialized,
+				}),
+				withMachinePoolInterval(specName, "wait-machine-pool-nodes"),
+				withControlPlaneInterval(specName, "wait-control-plane"),
+			), result)
+
+			By("Verifying machinepool recovers from a Spot eviction", func() {
+				AzureMachinePoolSpotEvictionSpec(ctx, func() AzureMachinePoolSpotEvictionSpecInput {
+					return AzureMachinePoolSpotEvictionSpecInput{
+						Cluster:               result.Cluster,
+						BootstrapClusterProxy: bootstrapClusterProxy,
+						Namespace:             namespace,
+						ClusterName:           clusterName,
+						WaitIntervals:         e2eConfig.GetIntervals(specName, "wait-worker-nodes"),
+					}
+				})
+			})
+
+			By("PASSED!")
+		})
+	})
+
 	// ci-e2e.sh and Prow CI skip this test by default. To include this test, set `GINKGO_SKIP=""`.
 	Context("Creating a cluster that uses the intree cloud provider [OPTIONAL]", func() {
 		It("with a 1 control plane nodes and 2 worker nodes", func() {
@@ -882,6 +917,18 @@ var _ = Describe("Workload cluster creation", func() {
 				})
 			})
 
+			By("Verifying the cluster is dual-stack", func() {
+				AzureDualStackSpec(ctx, func() AzureDualStackSpecInput {
+					return AzureDualStackSpecInput{
+						BootstrapClusterProxy: bootstrapClusterProxy,
+						Namespace:             namespace,
+						ClusterName:           clusterName,
+						Cluster:               result.Cluster,
+						SkipCleanup:           skipCleanup,
+					}
+				})
+			})
+
 			// dual-stack external IP for dual-stack clusters is not yet supported
 			// first ip family in ipFamilies is used for the primary clusterIP and cloud-provider
 			// determines the elb/ilb ip family based on the primary clusterIP